@@ -2,34 +2,67 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/capture"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/discovery"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/logbackend"
 	mcpserver "github.com/isitobservable/k8s-networking-mcp/pkg/mcp"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/privacy"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/provider"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/telemetry"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/traces"
 )
 
 func main() {
-	cfg, err := config.Load()
+	transport := flag.String("transport", "", "MCP transport: http or stdio (overrides MCP_TRANSPORT)")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file, reloaded when it changes (overrides CONFIG_FILE)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "fatal: %v\n", err)
 		os.Exit(1)
 	}
+	if *transport != "" {
+		if err := config.ValidateTransport(*transport); err != nil {
+			fmt.Fprintf(os.Stderr, "fatal: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Transport = *transport
+	}
 
-	config.SetupLogging(cfg.LogLevel)
+	// stdout carries the protocol stream in stdio mode, so logs go to stderr.
+	logOutput := os.Stdout
+	if cfg.Transport == config.TransportStdio {
+		logOutput = os.Stderr
+	}
+	config.SetupLogging(cfg.LogLevel, logOutput)
+
+	slog.Info("starting mcp-k8s-networking server", "cluster", cfg.ClusterName, "transport", cfg.Transport, "port", cfg.Port, "config_file", cfg.File)
 
-	slog.Info("starting mcp-k8s-networking server", "cluster", cfg.ClusterName, "port", cfg.Port)
+	// The OTLP exporters read their endpoint from the environment
+	if cfg.OTLPEndpoint != "" {
+		_ = os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", cfg.OTLPEndpoint)
+	}
 
 	// Initialize OpenTelemetry (traces + metrics + logs)
 	otelResult, err := telemetry.Init(context.Background(), cfg.ClusterName)
@@ -48,33 +81,312 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Additional clusters from kubeconfig contexts, selectable per tool call
+	clusters := k8s.NewClusterRegistry(&k8s.Cluster{Name: cfg.ClusterName, Clients: clients})
+	for _, cc := range cfg.Clusters {
+		ccClients, err := k8s.NewClientsForContext("", cc.Context)
+		if err != nil {
+			slog.Error("failed to create K8s clients for cluster", "cluster", cc.Name, "error", err)
+			os.Exit(1)
+		}
+		if err := clusters.Add(&k8s.Cluster{Name: cc.Name, Context: cc.Context, Clients: ccClients}); err != nil {
+			slog.Error("invalid cluster configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Per-cluster tool registries; the MCP server is created afterwards, so
+	// discovery callbacks reach it through this variable once started.
+	var srv *mcpserver.Server
+	runtimes := make(map[string]*clusterRuntime, len(clusters.Names()))
+	activeProviders := func(cluster string) []string {
+		if rt, ok := runtimes[cluster]; ok {
+			return rt.providers.Active()
+		}
+		return nil
+	}
+	for _, c := range clusters.List() {
+		runtimes[c.Name] = newClusterRuntime(cfg, c, clusters, activeProviders, func() { srv.SyncTools() })
+	}
+	primary := runtimes[cfg.ClusterName]
+
+	// Create MCP server
+	srv = mcpserver.NewServer(cfg.ClusterName, primary.registry)
+	for _, name := range clusters.Names()[1:] {
+		srv.AddCluster(name, runtimes[name].registry)
+	}
+	if len(cfg.Clusters) > 0 {
+		slog.Info("multi-cluster mode", "clusters", clusters.Names(), "default", cfg.ClusterName)
+	}
+
+	// Authentication applies to the HTTP transport only; stdio is a local, single-user session
+	if len(cfg.AuthModes) > 0 && cfg.Transport == config.TransportHTTP {
+		verifier, err := buildVerifier(cfg, clients)
+		if err != nil {
+			slog.Error("failed to configure authentication", "error", err)
+			os.Exit(1)
+		}
+		srv.EnableAuth(verifier)
+		slog.Info("authentication enabled for /mcp", "modes", cfg.AuthModes)
+	}
+
+	if cfg.ImpersonateCaller || cfg.ImpersonateUser != "" {
+		if cfg.ImpersonateCaller && cfg.Transport != config.TransportHTTP {
+			slog.Warn("IMPERSONATE_CALLER has no effect without HTTP authentication", "transport", cfg.Transport)
+		}
+		srv.EnableImpersonation(k8s.Impersonation{User: cfg.ImpersonateUser, Groups: cfg.ImpersonateGroups}, cfg.ImpersonateCaller)
+		slog.Info("impersonation enabled for tool calls", "caller", cfg.ImpersonateCaller, "fallback_user", cfg.ImpersonateUser)
+	}
+
+	if cfg.DataMinimization {
+		minimizer := privacy.NewMinimizer(cfg.DataMinimizationSalt)
+		for _, c := range clusters.List() {
+			minimizer.AddSource(privacy.KubernetesTerms(c.Clients))
+		}
+		srv.EnableMinimization(minimizer)
+		slog.Info("data minimization enabled", "stable_pseudonyms", cfg.DataMinimizationSalt != "")
+	}
+
+	if cfg.RedactSecrets {
+		srv.EnableRedaction()
+	}
+
+	srv.EnableToolTimeouts(timeoutPolicy(cfg))
+
+	if cfg.ResponseMaxBytes > 0 {
+		srv.EnableResponseBudget(cfg.ResponseMaxBytes)
+	}
+
+	if cfg.DeduplicateCalls {
+		srv.EnableDeduplication(cfg.ResultCacheTTL)
+	}
+
+	for name, rt := range runtimes {
+		if rt.findings != nil {
+			srv.EnableFindingHistory(name, rt.findings)
+		}
+		if rt.suppressions != nil {
+			srv.EnableSuppressions(name, rt.suppressions)
+		}
+	}
+
+	// Graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, name := range clusters.Names() {
+		runtimes[name].disc.Start(ctx)
+		if rec := runtimes[name].recorder; rec != nil {
+			rec.Start(ctx)
+		}
+		if loader := runtimes[name].skillLoader; loader != nil {
+			loader.Start(ctx)
+		}
+		if sched := runtimes[name].scheduler; sched != nil {
+			sched.Start(ctx)
+		}
+	}
+
+	// Changes to the configuration file apply without a restart where they
+	// can; reloadMu keeps a reload from racing with shutdown
+	var reloadMu sync.Mutex
+	if cfg.File != "" {
+		err := config.Watch(ctx, cfg, []string{cfg.AuthPolicyFile}, func(next *config.Config) {
+			if *transport != "" {
+				next.Transport = *transport
+			}
+			reloadMu.Lock()
+			defer reloadMu.Unlock()
+			reloadConfig(ctx, cfg, next, srv, runtimes, clients)
+		})
+		if err != nil {
+			slog.Warn("configuration file changes need a restart", "file", cfg.File, "error", err)
+		}
+	}
+
+	if cfg.Transport == config.TransportStdio {
+		// The client owns the process lifecycle: exit once it closes stdin.
+		go func() {
+			if err := srv.RunStdio(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("MCP stdio session error", "error", err)
+			}
+			stop()
+		}()
+	} else {
+		startHTTP(cfg, srv, primary.disc)
+	}
+
+	slog.Info("server ready", "transport", cfg.Transport, "port", cfg.Port)
+
+	<-ctx.Done()
+	slog.Info("shutting down")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown error", "error", err)
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	for _, rt := range runtimes {
+		rt.probeMgr.Stop()
+		if rt.recorder != nil {
+			rt.recorder.Stop()
+		}
+		if rt.skillLoader != nil {
+			rt.skillLoader.Stop()
+		}
+		if rt.scheduler != nil {
+			rt.scheduler.Stop()
+		}
+		if rt.findings != nil {
+			if err := rt.findings.Close(); err != nil {
+				slog.Warn("failed to close finding history", "cluster", rt.cluster.Name, "error", err)
+			}
+		}
+	}
+
+	// Flush pending OTel data (traces + metrics + logs) before exit
+	if err := otelResult.Shutdown(shutdownCtx); err != nil {
+		slog.Error("telemetry shutdown error", "error", err)
+	}
+
+	slog.Info("server stopped")
+}
+
+// clusterRuntime holds the tool registry, CRD discovery, probe manager,
+// configuration history recorder and finding history of one cluster.
+type clusterRuntime struct {
+	// cfg holds the cluster's settings, with the live settings of the
+	// latest configuration file reload
+	cfg       *config.Config
+	cluster   *k8s.Cluster
+	registry  *tools.Registry
+	disc      *discovery.Discovery
+	providers *provider.Manager
+	probeMgr  *probes.Manager
+	recorder  *history.Recorder     // nil when CONFIG_HISTORY_INTERVAL is 0
+	findings  *history.FindingStore // nil when FINDING_HISTORY_RETENTION is 0
+	// suppressions is nil when SUPPRESSIONS_CONFIGMAP is not set
+	suppressions *tools.SuppressionStore
+	// skillLoader is nil when neither SKILLS_DIR nor SKILLS_CONFIGMAP_NAMESPACE is set
+	skillLoader    *skills.Loader
+	skillsRegistry *skills.Registry
+	// scheduler is nil when SKILL_SCHEDULE_FILE has no schedule for the cluster
+	scheduler *skills.Scheduler
+}
+
+// newClusterRuntime registers every tool for one cluster. Responses carry the
+// cluster's name and CRD-dependent tools follow that cluster's discovery.
+func newClusterRuntime(baseCfg *config.Config, cluster *k8s.Cluster, clusters *k8s.ClusterRegistry, activeProviders func(string) []string, onToolsChanged func()) *clusterRuntime {
+	clusterCfg := *baseCfg
+	clusterCfg.ClusterName = cluster.Name
+	cfg := &clusterCfg
+	clients := cluster.Clients
+
 	// Create tool registry
 	registry := tools.NewRegistry()
 
-	base := tools.BaseTool{Cfg: cfg, Clients: clients}
+	// Shared list snapshot so scans issued together reuse one API call per resource type
+	snapshot := tools.NewClusterSnapshot(clients.Dynamic, cfg.CacheTTL)
+	probeMgr := probes.NewManager(context.Background(), cfg, clients)
+	base := tools.BaseTool{Cfg: cfg, Clients: clients, Snapshot: snapshot, Probes: probeMgr}
+
+	// Cluster inventory (always available)
+	registry.Register(&tools.ListClustersTool{BaseTool: base, Clusters: clusters, Providers: activeProviders})
 
 	// Register core K8s tools (always available)
 	registry.Register(&tools.ListServicesTool{BaseTool: base})
 	registry.Register(&tools.GetServiceTool{BaseTool: base})
+	registry.Register(&tools.GetResourcesTool{BaseTool: base})
 	registry.Register(&tools.ListEndpointsTool{BaseTool: base})
+	registry.Register(&tools.CheckTrafficPolicyTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeExternalExposureTool{BaseTool: base})
+	registry.Register(&tools.AuditEgressTool{BaseTool: base})
+	registry.Register(&tools.ExportServiceCatalogTool{BaseTool: base})
+	registry.Register(&tools.EstimateBlastRadiusTool{BaseTool: base})
+	registry.Register(&tools.DiffNetworkConfigTool{BaseTool: base, Clusters: clusters})
+	registry.Register(&tools.AuditTLSPolicyTool{BaseTool: base})
+	registry.Register(&tools.CheckCertificateSNITool{BaseTool: base})
+	registry.Register(&tools.QuickScanTool{BaseTool: base})
+	registry.Register(&tools.ValidateManifestsTool{BaseTool: base})
+	recorder := newHistoryRecorder(cfg, cluster, registry, base)
+	findings := newFindingHistory(cfg, cluster, registry, base)
+	var suppressions *tools.SuppressionStore
+	if cfg.SuppressionsNamespace != "" {
+		suppressions = tools.NewSuppressionStore(clients.Dynamic, cfg.SuppressionsNamespace, cfg.SuppressionsName, cfg.CacheTTL)
+		registry.Register(&tools.SuppressFindingTool{BaseTool: base, Suppressions: suppressions})
+		registry.Register(&tools.ListSuppressionsTool{BaseTool: base, Suppressions: suppressions})
+	}
+
+	// Register traffic metrics tools (when PROMETHEUS_URL is set)
+	var prom *prometheus.Client
+	if cfg.PrometheusURL != "" {
+		var err error
+		prom, err = prometheus.NewClient(cfg.PrometheusURL, cfg.PrometheusTokenFile, cfg.PrometheusClusterLabel, cluster.Name)
+		if err != nil {
+			slog.Error("invalid Prometheus configuration", "error", err)
+			os.Exit(1)
+		}
+		registry.Register(&tools.QueryServiceTrafficTool{BaseTool: base, Prometheus: prom})
+		registry.Register(&tools.CheckErrorRateTool{BaseTool: base, Prometheus: prom})
+	}
+	registry.Register(&tools.BuildServiceGraphTool{BaseTool: base, Prometheus: prom})
+	registry.Register(&tools.AnalyzeChangeImpactTool{BaseTool: base, Prometheus: prom})
+	// Register trace lookup (when TRACES_URL is set)
+	if cfg.TracesURL != "" {
+		tc, err := traces.NewClient(cfg.TracesBackend, cfg.TracesURL, cfg.TracesTokenFile)
+		if err != nil {
+			slog.Error("invalid trace backend configuration", "error", err)
+			os.Exit(1)
+		}
+		registry.Register(&tools.FindFailingTracesTool{BaseTool: base, Traces: tc})
+	}
 	registry.Register(&tools.ListNetworkPoliciesTool{BaseTool: base})
 	registry.Register(&tools.GetNetworkPolicyTool{BaseTool: base})
+	registry.Register(&tools.CheckNetworkPolicyPortsTool{BaseTool: base})
+	registry.Register(&tools.AnalyzePolicyCoverageTool{BaseTool: base})
 	registry.Register(&tools.CheckDNSTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeCoreDNSConfigTool{BaseTool: base})
 	registry.Register(&tools.CheckKubeProxyHealthTool{BaseTool: base})
+	registry.Register(&tools.RecommendScalingTool{BaseTool: base})
 	registry.Register(&tools.ListIngressesTool{BaseTool: base})
 	registry.Register(&tools.GetIngressTool{BaseTool: base})
 
-	// Register log tools (always available)
-	registry.Register(&tools.GetProxyLogsTool{BaseTool: base})
+	// Register log tools (always available), reading the log backend as
+	// well when LOG_BACKEND_URL is set
+	var logs *logbackend.Client
+	if cfg.LogBackendURL != "" {
+		var err error
+		logs, err = logbackend.NewClient(cfg.LogBackend, cfg.LogBackendURL, cfg.LogBackendTokenFile, cfg.LogBackendIndex, cfg.LogBackendClusterLabel, cluster.Name)
+		if err != nil {
+			slog.Error("invalid log backend configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+	registry.Register(&tools.GetProxyLogsTool{BaseTool: base, LogBackend: logs})
 	registry.Register(&tools.GetGatewayLogsTool{BaseTool: base})
 	registry.Register(&tools.GetInfraLogsTool{BaseTool: base})
-	registry.Register(&tools.AnalyzeLogErrorsTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeLogErrorsTool{BaseTool: base, LogBackend: logs})
+	registry.Register(&tools.CheckProxyResourcesTool{BaseTool: base, Prometheus: prom})
 
-	// Initialize probe manager and register probe tools (always available)
-	probeMgr := probes.NewManager(context.Background(), cfg, clients)
+	// Register probe tools (always available)
 	registry.Register(&tools.ProbeConnectivityTool{BaseTool: base, ProbeManager: probeMgr})
 	registry.Register(&tools.ProbeDNSTool{BaseTool: base, ProbeManager: probeMgr})
 	registry.Register(&tools.ProbeHTTPTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.ProbeLatencyTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.CheckMTUTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.VerifyTenantIsolationTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.VerifyTrafficPoliciesTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.VerifyRouteProgrammingTool{BaseTool: base, ProbeManager: probeMgr})
+	registry.Register(&tools.CheckProbeHygieneTool{BaseTool: base, ProbeManager: probeMgr})
+	// Probes that run privileged on a node's host network are opt-in
+	if cfg.PrivilegedProbes {
+		registry.Register(&tools.CaptureTrafficTool{BaseTool: base, ProbeManager: probeMgr, Store: &capture.Store{Dir: cfg.PacketCaptureDir}})
+		registry.Register(&tools.InspectConnectionsTool{BaseTool: base, ProbeManager: probeMgr})
+	}
 
 	// Register data plane health tool (always available — gracefully returns info findings when no sidecars found)
 	registry.Register(&tools.CheckDataplaneHealthTool{BaseTool: base})
@@ -86,135 +398,244 @@ func main() {
 	registry.Register(&tools.ListSkillsTool{BaseTool: base, Registry: skillsRegistry})
 	registry.Register(&tools.RunSkillTool{BaseTool: base, Registry: skillsRegistry})
 
-	// Create MCP server
-	srv := mcpserver.NewServer(registry)
+	// Custom skills from a mounted directory and/or labelled ConfigMaps
+	skillLoader := newSkillLoader(cfg, clients, skillsRegistry, registry)
+	scheduler := newSkillScheduler(baseCfg, cluster, skillsRegistry, registry, base)
 
 	// Register remediation and rate limit tools (always available — graceful CRD handling)
 	registry.Register(&tools.SuggestRemediationTool{BaseTool: base})
+	registry.Register(&tools.ListFindingCodesTool{BaseTool: base})
+	registry.Register(&tools.CheckPermissionsTool{BaseTool: base, Registry: registry})
+	registry.Register(&tools.GetServerStatsTool{BaseTool: base, Registry: registry, Stats: telemetry.DefaultStats})
 	registry.Register(&tools.CheckRateLimitPoliciesTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeRateLimitsTool{BaseTool: base})
+	registry.Register(&tools.DetectHostnameConflictsTool{BaseTool: base})
+	registry.Register(&tools.CheckOpenAPICoverageTool{BaseTool: base})
+	registry.Register(&tools.CheckMTUConsistencyTool{BaseTool: base})
+	registry.Register(&tools.CheckPodEncryptionTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeIPAMTool{BaseTool: base})
+	registry.Register(&tools.AnalyzeDualStackTool{BaseTool: base})
+	registry.Register(&tools.CheckAdmissionWebhooksTool{BaseTool: base, ProbeManager: probeMgr})
+
+	// Gateway, mesh and CNI providers (built-in and extensions) are enabled by CRD discovery
+	providers := provider.NewManager(base, registry, skillsRegistry)
+	registry.Register(&provider.CheckProviderHealthTool{BaseTool: base, Manager: providers})
+	registry.Register(&provider.GenerateGrafanaDashboardTool{BaseTool: base, Manager: providers})
 
-	// Gateway API tool names for conditional registration
-	gatewayToolNames := []string{"list_gateways", "get_gateway", "list_httproutes", "get_httproute", "list_grpcroutes", "get_grpcroute", "list_referencegrants", "get_referencegrant", "scan_gateway_misconfigs", "check_gateway_conformance", "design_gateway_api"}
-	istioToolNames := []string{"list_istio_resources", "get_istio_resource", "check_sidecar_injection", "check_istio_mtls", "validate_istio_config", "analyze_istio_authpolicy", "analyze_istio_routing", "design_istio"}
+	// CRD discovery with onChange callback
+	var disc *discovery.Discovery
+	disc = discovery.New(clients.Discovery, clients.Dynamic, func(features discovery.Features) {
+		// CRDs changed: cached lists may be for resources that no longer exist (or now do)
+		snapshot.Reset()
 
-	kgatewayToolNames := []string{"list_kgateway_resources", "validate_kgateway_resource", "check_kgateway_health", "design_kgateway"}
-	kumaToolNames := []string{"check_kuma_status"}
-	linkerdToolNames := []string{"check_linkerd_status"}
-	ciliumToolNames := []string{"list_cilium_policies", "check_cilium_status", "get_cilium_policy"}
-	calicoToolNames := []string{"list_calico_policies", "check_calico_status"}
-	flannelToolNames := []string{"check_flannel_status"}
+		providers.Sync(provider.Detection{Features: features, Groups: disc.APIGroups()})
 
-	// CRD discovery with onChange callback
-	disc := discovery.New(clients.Discovery, clients.Dynamic, func(features discovery.Features) {
-
-		// Gateway API tools
-		if features.HasGatewayAPI {
-			registry.Register(&tools.ListGatewaysTool{BaseTool: base})
-			registry.Register(&tools.GetGatewayTool{BaseTool: base})
-			registry.Register(&tools.ListHTTPRoutesTool{BaseTool: base})
-			registry.Register(&tools.GetHTTPRouteTool{BaseTool: base})
-			registry.Register(&tools.ListGRPCRoutesTool{BaseTool: base})
-			registry.Register(&tools.GetGRPCRouteTool{BaseTool: base})
-			registry.Register(&tools.ListReferenceGrantsTool{BaseTool: base})
-			registry.Register(&tools.GetReferenceGrantTool{BaseTool: base})
-			registry.Register(&tools.ScanGatewayMisconfigsTool{BaseTool: base})
-			registry.Register(&tools.CheckGatewayConformanceTool{BaseTool: base})
-			registry.Register(&tools.DesignGatewayAPITool{BaseTool: base})
-		} else {
-			for _, name := range gatewayToolNames {
-				registry.Unregister(name)
-			}
-		}
+		// Sync skills registry with discovered features
+		skillsRegistry.SyncWithFeatures(features, cfg, clients)
 
-		// Istio tools
-		if features.HasIstio {
-			registry.Register(&tools.ListIstioResourcesTool{BaseTool: base})
-			registry.Register(&tools.GetIstioResourceTool{BaseTool: base})
-			registry.Register(&tools.CheckSidecarInjectionTool{BaseTool: base})
-			registry.Register(&tools.CheckIstioMTLSTool{BaseTool: base})
-			registry.Register(&tools.ValidateIstioConfigTool{BaseTool: base})
-			registry.Register(&tools.AnalyzeIstioAuthPolicyTool{BaseTool: base})
-			registry.Register(&tools.AnalyzeIstioRoutingTool{BaseTool: base})
-			registry.Register(&tools.DesignIstioTool{BaseTool: base})
-		} else {
-			for _, name := range istioToolNames {
-				registry.Unregister(name)
-			}
-		}
+		// Re-sync tools with MCP server
+		onToolsChanged()
+
+		// Check the RBAC permissions of the current tool set, then re-sync so
+		// degraded tools say so in their descriptions
+		go func() {
+			tools.AuditPermissions(context.Background(), registry, clients, cfg.ProbeNamespace)
+			onToolsChanged()
+		}()
+	})
 
-		// kgateway tools
-		if features.HasKgateway {
-			registry.Register(&tools.ListKgatewayResourcesTool{BaseTool: base})
-			registry.Register(&tools.ValidateKgatewayResourceTool{BaseTool: base})
-			registry.Register(&tools.CheckKgatewayHealthTool{BaseTool: base})
-			registry.Register(&tools.DesignKgatewayTool{BaseTool: base})
-		} else {
-			for _, name := range kgatewayToolNames {
-				registry.Unregister(name)
-			}
-		}
+	registry.Register(&provider.GetCapabilitiesTool{BaseTool: base, Manager: providers, Discovery: disc})
+	registry.Register(&provider.RefreshCapabilitiesTool{BaseTool: base, Manager: providers, Discovery: disc})
 
-		// Kuma tools
-		if features.HasKuma {
-			registry.Register(&tools.CheckKumaStatusTool{BaseTool: base})
-		} else {
-			for _, name := range kumaToolNames {
-				registry.Unregister(name)
-			}
-		}
+	return &clusterRuntime{cfg: cfg, cluster: cluster, registry: registry, disc: disc, providers: providers, probeMgr: probeMgr, recorder: recorder, findings: findings, suppressions: suppressions, skillLoader: skillLoader, skillsRegistry: skillsRegistry, scheduler: scheduler}
+}
 
-		// Linkerd tools
-		if features.HasLinkerd {
-			registry.Register(&tools.CheckLinkerdStatusTool{BaseTool: base})
-		} else {
-			for _, name := range linkerdToolNames {
-				registry.Unregister(name)
-			}
-		}
+// newSkillLoader returns the loader of the custom skills in SKILLS_DIR and
+// SKILLS_CONFIGMAP_NAMESPACE, or nil when neither is set.
+func newSkillLoader(cfg *config.Config, clients *k8s.Clients, skillsRegistry *skills.Registry, registry *tools.Registry) *skills.Loader {
+	if cfg.SkillsDir == "" && cfg.SkillsNamespace == "" {
+		return nil
+	}
+	return skills.NewLoader(skillsRegistry, tools.SkillToolRunner{Registry: registry}, cfg.SkillsDir, clients.Dynamic, cfg.SkillsNamespace, cfg.SkillsReloadInterval)
+}
 
-		// Cilium tools
-		if features.HasCilium {
-			registry.Register(&tools.ListCiliumPoliciesTool{BaseTool: base})
-			registry.Register(&tools.CheckCiliumStatusTool{BaseTool: base})
-			registry.Register(&tools.GetCiliumPolicyTool{BaseTool: base})
-		} else {
-			for _, name := range ciliumToolNames {
-				registry.Unregister(name)
-			}
+// reload applies the live settings of a reloaded configuration to the
+// cluster: probes use the new image and limits, and custom skills are
+// reloaded, from new sources if they changed.
+func (rt *clusterRuntime) reload(ctx context.Context, next *config.Config) {
+	live := *rt.cfg
+	live.ProbeImage = next.ProbeImage
+	live.MaxConcurrentProbes = next.MaxConcurrentProbes
+	live.ProbeQueueSize = next.ProbeQueueSize
+	live.ProbeRateLimit = next.ProbeRateLimit
+	live.SkillsDir = next.SkillsDir
+	live.SkillsNamespace = next.SkillsNamespace
+	live.SkillsReloadInterval = next.SkillsReloadInterval
+	rt.probeMgr.Reconfigure(&live)
+
+	if live.SkillsDir == rt.cfg.SkillsDir && live.SkillsNamespace == rt.cfg.SkillsNamespace && live.SkillsReloadInterval == rt.cfg.SkillsReloadInterval {
+		if rt.skillLoader != nil {
+			rt.skillLoader.Reload(ctx)
 		}
+	} else {
+		if rt.skillLoader != nil {
+			rt.skillLoader.Stop()
+			rt.skillLoader.Unload()
+		}
+		rt.skillLoader = newSkillLoader(&live, rt.cluster.Clients, rt.skillsRegistry, rt.registry)
+		if rt.skillLoader != nil {
+			rt.skillLoader.Start(ctx)
+		}
+		slog.Info("custom skill sources changed", "cluster", rt.cluster.Name, "dir", live.SkillsDir, "namespace", live.SkillsNamespace)
+	}
+	rt.cfg = &live
+}
 
-		// Calico tools
-		if features.HasCalico {
-			registry.Register(&tools.ListCalicoPoliciesTool{BaseTool: base})
-			registry.Register(&tools.CheckCalicoStatusTool{BaseTool: base})
+// reloadConfig applies a changed configuration file to the running server.
+// The log level, tool timeouts, probe settings, custom skills and
+// authentication follow it; other changed settings are logged, as they
+// apply after a restart.
+func reloadConfig(ctx context.Context, cfg, next *config.Config, srv *mcpserver.Server, runtimes map[string]*clusterRuntime, clients *k8s.Clients) {
+	if changed := cfg.RestartRequired(next); len(changed) > 0 {
+		slog.Warn("changed settings apply after a restart", "settings", changed)
+	}
+	config.SetLogLevel(next.LogLevel)
+	srv.EnableToolTimeouts(timeoutPolicy(next))
+	for _, rt := range runtimes {
+		rt.reload(ctx, next)
+	}
+	if len(cfg.AuthModes) > 0 && len(next.AuthModes) > 0 && cfg.Transport == config.TransportHTTP {
+		verifier, err := buildVerifier(next, clients)
+		if err != nil {
+			slog.Error("invalid authentication configuration, keeping the running one", "error", err)
 		} else {
-			for _, name := range calicoToolNames {
-				registry.Unregister(name)
-			}
+			srv.ReloadAuth(verifier)
 		}
+	}
+	slog.Info("configuration reloaded", "file", next.File)
+}
 
-		// Flannel tools
-		if features.HasFlannel {
-			registry.Register(&tools.CheckFlannelStatusTool{BaseTool: base})
-		} else {
-			for _, name := range flannelToolNames {
-				registry.Unregister(name)
-			}
+// timeoutPolicy returns the tool call timeouts of cfg.
+func timeoutPolicy(cfg *config.Config) tools.TimeoutPolicy {
+	return tools.TimeoutPolicy{
+		Read:      cfg.ToolTimeout,
+		Scan:      cfg.ScanToolTimeout,
+		Probe:     cfg.ProbeToolTimeout,
+		Overrides: cfg.ToolTimeouts,
+	}
+}
+
+// newSkillScheduler registers the scheduled skill tools and returns the
+// scheduler of the cluster's schedules, or nil when it has none.
+func newSkillScheduler(baseCfg *config.Config, cluster *k8s.Cluster, skillsRegistry *skills.Registry, registry *tools.Registry, base tools.BaseTool) *skills.Scheduler {
+	if baseCfg.SkillScheduleFile == "" {
+		return nil
+	}
+	schedCfg, err := skills.LoadScheduleConfig(baseCfg.SkillScheduleFile)
+	if err != nil {
+		slog.Error("invalid skill schedule", "error", err)
+		os.Exit(1)
+	}
+	var schedules []skills.Schedule
+	for _, s := range schedCfg.Schedules {
+		// Schedules without a cluster run on the server's own cluster.
+		if s.Cluster == cluster.Name || (s.Cluster == "" && cluster.Name == baseCfg.ClusterName) {
+			schedules = append(schedules, s)
 		}
+	}
+	if len(schedules) == 0 {
+		return nil
+	}
+	var notifier *skills.Notifier
+	if schedCfg.Webhook != nil {
+		if notifier, err = skills.NewNotifier(*schedCfg.Webhook, cluster.Name); err != nil {
+			slog.Error("invalid skill schedule webhook", "error", err)
+			os.Exit(1)
+		}
+	}
+	dir := ""
+	if baseCfg.SkillResultsDir != "" {
+		dir = filepath.Join(baseCfg.SkillResultsDir, cluster.Name)
+	}
+	scheduler, err := skills.NewScheduler(skillsRegistry, schedules, notifier, dir)
+	if err != nil {
+		slog.Warn("scheduled skill results fall back to memory", "cluster", cluster.Name, "error", err)
+		scheduler, _ = skills.NewScheduler(skillsRegistry, schedules, notifier, "")
+	}
+	registry.Register(&tools.ListScheduledSkillsTool{BaseTool: base, Scheduler: scheduler})
+	registry.Register(&tools.GetLastRunResultsTool{BaseTool: base, Scheduler: scheduler})
+	return scheduler
+}
 
-		// Sync skills registry with discovered features
-		skillsRegistry.SyncWithFeatures(features, cfg, clients)
+// newHistoryRecorder registers the configuration history tools and returns the
+// recorder feeding them, or nil when history is disabled.
+func newHistoryRecorder(cfg *config.Config, cluster *k8s.Cluster, registry *tools.Registry, base tools.BaseTool) *history.Recorder {
+	if cfg.HistoryInterval <= 0 {
+		return nil
+	}
+	dir := ""
+	if cfg.HistoryDir != "" {
+		dir = filepath.Join(cfg.HistoryDir, cluster.Name)
+	}
+	store, err := history.NewStore(cfg.HistorySize, dir)
+	if err != nil {
+		slog.Warn("configuration history falls back to memory", "cluster", cluster.Name, "error", err)
+		store, _ = history.NewStore(cfg.HistorySize, "")
+	}
+	registry.Register(&tools.GetConfigTimelineTool{BaseTool: base, History: store})
+	registry.Register(&tools.DiffSnapshotsTool{BaseTool: base, History: store})
+	return history.NewRecorder(cluster.Clients.Dynamic, store, cfg.HistoryInterval)
+}
 
-		// Re-sync tools with MCP server
-		srv.SyncTools()
-	})
+// newFindingHistory registers the finding history tools and returns the store
+// recording tool call findings, or nil when finding history is disabled.
+func newFindingHistory(cfg *config.Config, cluster *k8s.Cluster, registry *tools.Registry, base tools.BaseTool) *history.FindingStore {
+	if cfg.FindingHistoryRetention <= 0 {
+		return nil
+	}
+	dir := ""
+	if cfg.FindingHistoryDir != "" {
+		dir = filepath.Join(cfg.FindingHistoryDir, cluster.Name)
+	}
+	store, err := history.NewFindingStore(cfg.FindingHistoryRetention, dir)
+	if err != nil {
+		slog.Warn("finding history falls back to memory", "cluster", cluster.Name, "error", err)
+		store, _ = history.NewFindingStore(cfg.FindingHistoryRetention, "")
+	}
+	registry.Register(&tools.GetFindingHistoryTool{BaseTool: base, Findings: store})
+	registry.Register(&tools.GetFindingTrendsTool{BaseTool: base, Findings: store})
+	return store
+}
 
-	// Graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+// buildVerifier creates the bearer token verifier for the configured AUTH_MODE values.
+func buildVerifier(cfg *config.Config, clients *k8s.Clients) (*auth.Verifier, error) {
+	policy, err := auth.LoadPolicy(cfg.AuthPolicyFile)
+	if err != nil {
+		return nil, err
+	}
 
-	disc.Start(ctx)
+	var authenticators []auth.Authenticator
+	for _, mode := range cfg.AuthModes {
+		switch mode {
+		case config.AuthModeBearer:
+			if len(policy.Tokens) == 0 {
+				return nil, fmt.Errorf("AUTH_MODE bearer requires tokens in AUTH_POLICY_FILE")
+			}
+			// Static tokens are checked by the verifier itself
+		case config.AuthModeTokenReview:
+			authenticators = append(authenticators, auth.NewTokenReviewAuthenticator(clients.Clientset, cfg.TokenReviewAudiences))
+		case config.AuthModeOIDC:
+			authenticators = append(authenticators, auth.NewOIDCAuthenticator(cfg.OIDCIssuer, cfg.OIDCAudience, cfg.OIDCUsernameClaim, cfg.OIDCGroupsClaim))
+		}
+	}
+	if !slices.Contains(cfg.AuthModes, config.AuthModeBearer) {
+		policy.Tokens = nil
+	}
+	return auth.NewVerifier(policy, authenticators...), nil
+}
 
+// startHTTP starts the health check server and the MCP Streamable HTTP server.
+func startHTTP(cfg *config.Config, srv *mcpserver.Server, disc *discovery.Discovery) {
 	// Health check endpoints
 	healthMux := http.NewServeMux()
 	healthMux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -248,25 +669,4 @@ func main() {
 			os.Exit(1)
 		}
 	}()
-
-	slog.Info("server ready", "port", cfg.Port)
-
-	<-ctx.Done()
-	slog.Info("shutting down")
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		slog.Error("shutdown error", "error", err)
-	}
-
-	probeMgr.Stop()
-
-	// Flush pending OTel data (traces + metrics + logs) before exit
-	if err := otelResult.Shutdown(shutdownCtx); err != nil {
-		slog.Error("telemetry shutdown error", "error", err)
-	}
-
-	slog.Info("server stopped")
 }
@@ -0,0 +1,271 @@
+// Package auth authenticates callers of the MCP HTTP endpoint and decides
+// which tools each caller may invoke.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// staticTokenLifetime is the expiration reported for static bearer tokens,
+// which never expire on their own; the SDK middleware requires one.
+const staticTokenLifetime = time.Hour
+
+// TokenInfo.Extra keys holding the caller's *ToolAccess and the *Identity
+// tool calls may impersonate.
+const (
+	accessKey   = "toolAccess"
+	identityKey = "kubernetesIdentity"
+)
+
+// Identity is an authenticated caller.
+type Identity struct {
+	Username string
+	Groups   []string
+	// Expiry is when the credential stops being valid (zero = unknown).
+	Expiry time.Time
+}
+
+// Authenticator validates a bearer token. It returns (nil, nil) when the
+// token is not one it recognizes, so the next authenticator can try.
+type Authenticator interface {
+	Name() string
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// ToolAccess restricts which tools a caller may use. Patterns use path.Match
+// syntax, e.g. "probe_*". An empty Allow list allows every tool not denied.
+type ToolAccess struct {
+	Allow []string `json:"tools,omitempty"`
+	Deny  []string `json:"denyTools,omitempty"`
+}
+
+// Allows reports whether the tool may be invoked.
+func (a *ToolAccess) Allows(tool string) bool {
+	if a == nil {
+		return true
+	}
+	if matchAny(a.Deny, tool) {
+		return false
+	}
+	return len(a.Allow) == 0 || matchAny(a.Allow, tool)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// StaticToken is a pre-shared bearer token with its own tool access.
+// User and Groups are the Kubernetes identity its tool calls impersonate
+// when IMPERSONATE_CALLER is set.
+type StaticToken struct {
+	Name   string   `json:"name"`
+	Token  string   `json:"token"`
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	ToolAccess
+}
+
+// Rule grants tool access to TokenReview or OIDC identities by user or group.
+type Rule struct {
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	ToolAccess
+}
+
+func (r *Rule) matches(id *Identity) bool {
+	for _, u := range r.Users {
+		if u == id.Username || u == "*" {
+			return true
+		}
+	}
+	for _, g := range r.Groups {
+		for _, ig := range id.Groups {
+			if g == ig {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Policy is the contents of AUTH_POLICY_FILE.
+type Policy struct {
+	Tokens []StaticToken `json:"tokens,omitempty"`
+	// Rules are evaluated in order; the first match decides tool access.
+	Rules []Rule `json:"rules,omitempty"`
+	// Default applies to identities that match no rule. When nil, such
+	// identities are rejected.
+	Default *ToolAccess `json:"default,omitempty"`
+}
+
+// LoadPolicy reads a YAML or JSON policy file. An empty path yields an empty policy.
+func LoadPolicy(file string) (*Policy, error) {
+	p := &Policy{}
+	if file == "" {
+		return p, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open auth policy: %w", err)
+	}
+	defer f.Close()
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(p); err != nil {
+		return nil, fmt.Errorf("failed to parse auth policy %s: %w", file, err)
+	}
+	for i, t := range p.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("auth policy token %d (%q) has an empty token", i, t.Name)
+		}
+	}
+	return p, nil
+}
+
+// accessFor returns the tool access for an identity, or nil if no rule
+// matches and there is no default.
+func (p *Policy) accessFor(id *Identity) *ToolAccess {
+	for i := range p.Rules {
+		if p.Rules[i].matches(id) {
+			return &p.Rules[i].ToolAccess
+		}
+	}
+	return p.Default
+}
+
+// staticAuthenticator checks tokens listed in the policy file.
+type staticAuthenticator struct {
+	tokens []StaticToken
+}
+
+func (a *staticAuthenticator) Name() string { return "bearer" }
+
+func (a *staticAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return &Identity{Username: "token:" + t.Name}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Verifier resolves bearer tokens to identities and tool access.
+type Verifier struct {
+	policy         *Policy
+	authenticators []Authenticator
+	static         map[string]*StaticToken // by static token identity
+}
+
+// NewVerifier builds a verifier trying each authenticator in order. A
+// staticAuthenticator for the policy's tokens is prepended when present.
+func NewVerifier(policy *Policy, authenticators ...Authenticator) *Verifier {
+	v := &Verifier{policy: policy, static: make(map[string]*StaticToken)}
+	if len(policy.Tokens) > 0 {
+		v.authenticators = append(v.authenticators, &staticAuthenticator{tokens: policy.Tokens})
+		for i := range policy.Tokens {
+			v.static["token:"+policy.Tokens[i].Name] = &policy.Tokens[i]
+		}
+	}
+	v.authenticators = append(v.authenticators, authenticators...)
+	return v
+}
+
+// Verify implements the go-sdk TokenVerifier signature.
+func (v *Verifier) Verify(ctx context.Context, token string, _ *http.Request) (*sdkauth.TokenInfo, error) {
+	for _, a := range v.authenticators {
+		id, err := a.Authenticate(ctx, token)
+		if err != nil {
+			slog.Debug("auth: authenticator rejected token", "authenticator", a.Name(), "error", err)
+			continue
+		}
+		if id == nil {
+			continue
+		}
+
+		var access *ToolAccess
+		kubeID := id
+		if st, ok := v.static[id.Username]; ok {
+			access = &st.ToolAccess
+			kubeID = nil
+			if st.User != "" {
+				kubeID = &Identity{Username: st.User, Groups: st.Groups}
+			}
+		} else {
+			access = v.policy.accessFor(id)
+			if access == nil {
+				slog.Warn("auth: authenticated identity has no matching policy rule", "user", id.Username, "authenticator", a.Name())
+				return nil, fmt.Errorf("%w: no access policy for %s", sdkauth.ErrInvalidToken, id.Username)
+			}
+		}
+
+		expiry := id.Expiry
+		if expiry.IsZero() {
+			expiry = time.Now().Add(staticTokenLifetime)
+		}
+		return &sdkauth.TokenInfo{
+			UserID:     id.Username,
+			Expiration: expiry,
+			Extra:      map[string]any{accessKey: access, identityKey: kubeID},
+		}, nil
+	}
+	return nil, sdkauth.ErrInvalidToken
+}
+
+// Middleware wraps an HTTP handler so that requests without a valid bearer
+// token are rejected with 401.
+func (v *Verifier) Middleware() func(http.Handler) http.Handler {
+	return sdkauth.RequireBearerToken(v.Verify, nil)
+}
+
+// AccessFromTokenInfo returns the tool access attached by Verify. It returns
+// nil (unrestricted) when the request was not authenticated, e.g. stdio.
+func AccessFromTokenInfo(ti *sdkauth.TokenInfo) *ToolAccess {
+	if ti == nil {
+		return nil
+	}
+	access, _ := ti.Extra[accessKey].(*ToolAccess)
+	return access
+}
+
+// KubernetesIdentityFromTokenInfo returns the Kubernetes identity a caller's
+// tool calls may impersonate: the TokenReview or OIDC identity, or the user
+// configured for a static token. It returns nil when there is none.
+func KubernetesIdentityFromTokenInfo(ti *sdkauth.TokenInfo) *Identity {
+	if ti == nil {
+		return nil
+	}
+	id, _ := ti.Extra[identityKey].(*Identity)
+	return id
+}
+
+type accessContextKey struct{}
+
+// WithToolAccess returns a context carrying the caller's tool access, so
+// tools that run other tools (custom skills) apply the same allowlist.
+func WithToolAccess(ctx context.Context, access *ToolAccess) context.Context {
+	return context.WithValue(ctx, accessContextKey{}, access)
+}
+
+// ToolAccessFromContext returns the tool access set by WithToolAccess, or nil
+// (unrestricted).
+func ToolAccessFromContext(ctx context.Context) *ToolAccess {
+	access, _ := ctx.Value(accessContextKey{}).(*ToolAccess)
+	return access
+}
+
+// ErrToolNotAllowed is returned when a caller invokes a tool outside its allowlist.
+var ErrToolNotAllowed = errors.New("tool not allowed for this token")
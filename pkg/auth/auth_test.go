@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+func TestToolAccess_Allows(t *testing.T) {
+	readOnly := &ToolAccess{Allow: []string{"list_*", "get_*", "check_*"}, Deny: []string{"check_mtu_consistency"}}
+	tests := []struct {
+		access *ToolAccess
+		tool   string
+		want   bool
+	}{
+		{nil, "probe_http", true},
+		{&ToolAccess{}, "probe_http", true},
+		{&ToolAccess{Deny: []string{"probe_*"}}, "probe_http", false},
+		{readOnly, "list_services", true},
+		{readOnly, "probe_connectivity", false},
+		{readOnly, "check_mtu_consistency", false},
+	}
+	for _, tc := range tests {
+		if got := tc.access.Allows(tc.tool); got != tc.want {
+			t.Errorf("%+v.Allows(%q) = %v, want %v", tc.access, tc.tool, got, tc.want)
+		}
+	}
+}
+
+type fakeAuthenticator struct{ id *Identity }
+
+func (f *fakeAuthenticator) Name() string { return "fake" }
+func (f *fakeAuthenticator) Authenticate(context.Context, string) (*Identity, error) {
+	return f.id, nil
+}
+
+func TestVerifier_StaticTokens(t *testing.T) {
+	policy := &Policy{Tokens: []StaticToken{
+		{Name: "ci", Token: "s3cret", ToolAccess: ToolAccess{Deny: []string{"probe_*"}}},
+		{Name: "team-a", Token: "t34m", User: "team-a-viewer", Groups: []string{"team-a"}},
+	}}
+	v := NewVerifier(policy)
+
+	ti, err := v.Verify(context.Background(), "s3cret", nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if ti.UserID != "token:ci" || ti.Expiration.IsZero() {
+		t.Errorf("unexpected token info: %+v", ti)
+	}
+	if AccessFromTokenInfo(ti).Allows("probe_dns") {
+		t.Error("expected probe_dns to be denied for the ci token")
+	}
+	if id := KubernetesIdentityFromTokenInfo(ti); id != nil {
+		t.Errorf("tokens without a user should not impersonate, got %+v", id)
+	}
+
+	ti, err = v.Verify(context.Background(), "t34m", nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if id := KubernetesIdentityFromTokenInfo(ti); id == nil || id.Username != "team-a-viewer" || len(id.Groups) != 1 {
+		t.Errorf("expected team-a-viewer identity, got %+v", id)
+	}
+
+	if _, err := v.Verify(context.Background(), "wrong", nil); !errors.Is(err, sdkauth.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for unknown token, got %v", err)
+	}
+}
+
+func TestVerifier_RulesAndDefault(t *testing.T) {
+	id := &Identity{Username: "system:serviceaccount:ops:agent", Groups: []string{"sre"}}
+	policy := &Policy{Rules: []Rule{
+		{Groups: []string{"sre"}, ToolAccess: ToolAccess{Allow: []string{"*"}}},
+	}}
+	v := NewVerifier(policy, &fakeAuthenticator{id: id})
+	ti, err := v.Verify(context.Background(), "tok", nil)
+	if err != nil {
+		t.Fatalf("expected group rule to match: %v", err)
+	}
+	if got := KubernetesIdentityFromTokenInfo(ti); got != id {
+		t.Errorf("expected the authenticated identity to be impersonable, got %+v", got)
+	}
+
+	// No matching rule and no default: rejected.
+	v = NewVerifier(&Policy{}, &fakeAuthenticator{id: id})
+	if _, err := v.Verify(context.Background(), "tok", nil); !errors.Is(err, sdkauth.ErrInvalidToken) {
+		t.Errorf("expected rejection without a matching rule, got %v", err)
+	}
+
+	v = NewVerifier(&Policy{Default: &ToolAccess{Allow: []string{"list_*"}}}, &fakeAuthenticator{id: id})
+	ti, err = v.Verify(context.Background(), "tok", nil)
+	if err != nil {
+		t.Fatalf("expected default access: %v", err)
+	}
+	if AccessFromTokenInfo(ti).Allows("probe_http") {
+		t.Error("default access should only allow list_* tools")
+	}
+}
+
+func TestOIDCAuthenticator_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issuer string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	issuer = srv.URL
+
+	sign := func(claims map[string]interface{}) string {
+		h, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+		c, _ := json.Marshal(claims)
+		signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+		digest := sha256.Sum256([]byte(signed))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+
+	a := NewOIDCAuthenticator(issuer, "mcp", "email", "groups")
+	exp := float64(time.Now().Add(time.Hour).Unix())
+
+	id, err := a.Authenticate(context.Background(), sign(map[string]interface{}{
+		"iss": issuer, "aud": []string{"mcp"}, "exp": exp, "email": "dev@example.com", "groups": []string{"sre"},
+	}))
+	if err != nil || id == nil {
+		t.Fatalf("Authenticate() = %v, %v", id, err)
+	}
+	if id.Username != "dev@example.com" || len(id.Groups) != 1 || id.Groups[0] != "sre" {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+
+	if _, err := a.Authenticate(context.Background(), sign(map[string]interface{}{
+		"iss": issuer, "aud": "other", "exp": exp, "email": "dev@example.com",
+	})); err == nil {
+		t.Error("expected wrong audience to be rejected")
+	}
+
+	if id, err := a.Authenticate(context.Background(), sign(map[string]interface{}{
+		"iss": "https://elsewhere", "aud": "mcp", "exp": exp,
+	})); id != nil || err != nil {
+		t.Errorf("tokens from other issuers should be skipped, got %v, %v", id, err)
+	}
+}
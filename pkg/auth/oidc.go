@@ -0,0 +1,311 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oidcClockSkew tolerates small clock differences with the issuer.
+	oidcClockSkew = time.Minute
+	// jwksMinRefresh limits how often an unknown key ID triggers a JWKS fetch.
+	jwksMinRefresh = time.Minute
+)
+
+// OIDCAuthenticator validates JWTs issued by an OpenID Connect provider.
+// Keys are discovered from the issuer's /.well-known/openid-configuration.
+type OIDCAuthenticator struct {
+	issuer        string
+	audience      string
+	usernameClaim string
+	groupsClaim   string
+	httpClient    *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+// NewOIDCAuthenticator creates an authenticator for tokens from issuer whose
+// aud claim contains audience.
+func NewOIDCAuthenticator(issuer, audience, usernameClaim, groupsClaim string) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		issuer:        strings.TrimSuffix(issuer, "/"),
+		audience:      audience,
+		usernameClaim: usernameClaim,
+		groupsClaim:   groupsClaim,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		keys:          make(map[string]crypto.PublicKey),
+	}
+}
+
+func (a *OIDCAuthenticator) Name() string { return "oidc" }
+
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil // not a JWT
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, nil
+	}
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, nil
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != a.issuer {
+		return nil, nil // issued by someone else
+	}
+
+	key, err := a.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exp, ok := numericClaim(claims, "exp")
+	if !ok || now.After(exp.Add(oidcClockSkew)) {
+		return nil, fmt.Errorf("token expired or missing exp")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(oidcClockSkew).Before(nbf) {
+		return nil, fmt.Errorf("token not yet valid")
+	}
+	if !audienceContains(claims["aud"], a.audience) {
+		return nil, fmt.Errorf("token audience does not include %q", a.audience)
+	}
+
+	username, _ := claims[a.usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("token has no %q claim", a.usernameClaim)
+	}
+	return &Identity{
+		Username: username,
+		Groups:   stringsClaim(claims[a.groupsClaim]),
+		Expiry:   exp,
+	}, nil
+}
+
+// key returns the signing key for kid, refreshing the JWKS when it is unknown.
+func (a *OIDCAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if k, ok := a.keys[kid]; ok {
+		return k, nil
+	}
+	if time.Since(a.lastRefresh) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	a.lastRefresh = time.Now()
+
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.keys = keys
+	if k, ok := a.keys[kid]; ok {
+		return k, nil
+	}
+	// Single-key providers sometimes omit kid from tokens.
+	if kid == "" && len(a.keys) == 1 {
+		for _, k := range a.keys {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (a *OIDCAuthenticator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := a.getJSON(ctx, a.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document has no jwks_uri")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := a.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	return keys, nil
+}
+
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// verifySignature checks a JWS signature for the RS* and ES* algorithms.
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") || len(sig)%2 != 0 {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		r := new(big.Int).SetBytes(sig[:len(sig)/2])
+		s := new(big.Int).SetBytes(sig[len(sig)/2:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func numericClaim(claims map[string]interface{}, name string) (time.Time, bool) {
+	f, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+func audienceContains(aud interface{}, want string) bool {
+	for _, a := range stringsClaim(aud) {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+// stringsClaim normalizes a claim that may be a string or a list of strings.
+func stringsClaim(v interface{}) []string {
+	switch c := v.(type) {
+	case string:
+		return []string{c}
+	case []interface{}:
+		out := make([]string, 0, len(c))
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
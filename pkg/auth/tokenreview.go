@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// tokenReviewCacheTTL bounds how long a TokenReview verdict is reused, so a
+// chatty client does not cost one API call per MCP request.
+const tokenReviewCacheTTL = time.Minute
+
+// TokenReviewAuthenticator validates Kubernetes service account (or any
+// API-server-accepted) tokens via the TokenReview API.
+type TokenReviewAuthenticator struct {
+	client    kubernetes.Interface
+	audiences []string
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]tokenReviewResult
+}
+
+type tokenReviewResult struct {
+	id      *Identity
+	expires time.Time
+}
+
+// NewTokenReviewAuthenticator creates an authenticator. audiences may be empty
+// to accept the API server's default audience.
+func NewTokenReviewAuthenticator(client kubernetes.Interface, audiences []string) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		client:    client,
+		audiences: audiences,
+		cache:     make(map[[sha256.Size]byte]tokenReviewResult),
+	}
+}
+
+func (a *TokenReviewAuthenticator) Name() string { return "tokenreview" }
+
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	a.mu.Lock()
+	if r, ok := a.cache[key]; ok && now.Before(r.expires) {
+		a.mu.Unlock()
+		return r.id, nil
+	}
+	a.mu.Unlock()
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(ctx, &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{Token: token, Audiences: a.audiences},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+
+	var id *Identity
+	if review.Status.Authenticated {
+		id = &Identity{
+			Username: review.Status.User.Username,
+			Groups:   review.Status.User.Groups,
+			Expiry:   now.Add(tokenReviewCacheTTL),
+		}
+	}
+
+	a.mu.Lock()
+	for k, r := range a.cache {
+		if now.After(r.expires) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = tokenReviewResult{id: id, expires: now.Add(tokenReviewCacheTTL)}
+	a.mu.Unlock()
+	return id, nil
+}
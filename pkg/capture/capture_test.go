@@ -0,0 +1,167 @@
+package capture
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPcap builds a little-endian pcap with microsecond timestamps.
+type testPcap struct {
+	data []byte
+	t    time.Time
+}
+
+func newTestPcap(linkType uint32) *testPcap {
+	hdr := make([]byte, pcapHeaderLen)
+	binary.LittleEndian.PutUint32(hdr, 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(hdr[4:], 2)
+	binary.LittleEndian.PutUint16(hdr[6:], 4)
+	binary.LittleEndian.PutUint32(hdr[16:], 128)
+	binary.LittleEndian.PutUint32(hdr[20:], linkType)
+	return &testPcap{data: hdr, t: time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)}
+}
+
+func (p *testPcap) record(frame []byte) {
+	p.t = p.t.Add(10 * time.Millisecond)
+	rec := make([]byte, recordHeaderLen)
+	binary.LittleEndian.PutUint32(rec, uint32(p.t.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:], uint32(p.t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(rec[12:], uint32(len(frame)))
+	p.data = append(append(p.data, rec...), frame...)
+}
+
+// tcp appends an SLL2 frame carrying an IPv4 TCP segment from src to dst.
+func (p *testPcap) tcp(src, dst string, seq uint32, flags byte, payload int) {
+	s, d := netip.MustParseAddrPort(src), netip.MustParseAddrPort(dst)
+	ip := make([]byte, 40)
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], uint16(40+payload))
+	ip[9] = protoTCP
+	copy(ip[12:], s.Addr().AsSlice())
+	copy(ip[16:], d.Addr().AsSlice())
+	binary.BigEndian.PutUint16(ip[20:], s.Port())
+	binary.BigEndian.PutUint16(ip[22:], d.Port())
+	binary.BigEndian.PutUint32(ip[24:], seq)
+	ip[32] = 5 << 4
+	ip[33] = flags
+	sll := make([]byte, 20)
+	binary.BigEndian.PutUint16(sll, etherTypeIPv4)
+	p.record(append(sll, ip...))
+}
+
+func TestParsePcap(t *testing.T) {
+	p := newTestPcap(LinkTypeLinuxSLL2)
+	p.tcp("10.0.0.1:40000", "10.0.0.2:80", 1, tcpSYN, 0)
+	p.tcp("10.0.0.2:80", "10.0.0.1:40000", 9, tcpSYN|tcpACK, 0)
+
+	got, err := ParsePcap(p.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LinkType != LinkTypeLinuxSLL2 || len(got.Packets) != 2 || got.Truncated {
+		t.Fatalf("got link type %d, %d packets, truncated %v", got.LinkType, len(got.Packets), got.Truncated)
+	}
+	if want := time.Date(2026, 10, 15, 10, 0, 0, 20*int(time.Millisecond), time.UTC); !got.Packets[1].Time.Equal(want) {
+		t.Errorf("time = %s, want %s", got.Packets[1].Time, want)
+	}
+
+	got, err = ParsePcap(p.data[:len(p.data)-5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Packets) != 1 || !got.Truncated {
+		t.Errorf("cut file: %d packets, truncated %v", len(got.Packets), got.Truncated)
+	}
+
+	if _, err := ParsePcap([]byte{0x0a, 0x0d, 0x0d, 0x0a, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}); err == nil || !strings.Contains(err.Error(), "pcapng") {
+		t.Errorf("pcapng: err = %v", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	p := newTestPcap(LinkTypeLinuxSLL2)
+	// Unanswered connection attempt, retried once.
+	p.tcp("10.0.0.1:40001", "10.0.0.3:5432", 100, tcpSYN, 0)
+	p.tcp("10.0.0.1:40001", "10.0.0.3:5432", 100, tcpSYN, 0)
+	// Handshake, a request sent twice, then a reset by the server.
+	p.tcp("10.0.0.1:40000", "10.0.0.2:80", 1, tcpSYN, 0)
+	p.tcp("10.0.0.2:80", "10.0.0.1:40000", 9, tcpSYN|tcpACK, 0)
+	p.tcp("10.0.0.1:40000", "10.0.0.2:80", 2, tcpACK, 0)
+	p.tcp("10.0.0.1:40000", "10.0.0.2:80", 2, tcpACK, 100)
+	p.tcp("10.0.0.1:40000", "10.0.0.2:80", 2, tcpACK, 100)
+	p.tcp("10.0.0.2:80", "10.0.0.1:40000", 10, tcpRST|tcpACK, 0)
+	// A connection already open when the capture started, answered by the
+	// pod on port 8080.
+	p.tcp("10.0.0.2:8080", "10.0.0.5:51000", 500, tcpACK, 20)
+	p.tcp("10.0.0.5:51000", "10.0.0.2:8080", 700, tcpACK|tcpFIN, 0)
+	p.record([]byte{0, 0}) // too short for SLL2
+
+	pc, err := ParsePcap(p.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Summarize(pc)
+	if s.Packets != 10 || s.Skipped != 1 || len(s.Flows) != 3 {
+		t.Fatalf("packets %d, skipped %d, flows %d", s.Packets, s.Skipped, len(s.Flows))
+	}
+
+	unanswered, reset, closed := s.Flows[0], s.Flows[1], s.Flows[2]
+	if unanswered.State() != StateSYNUnanswered || unanswered.SYN != 2 || unanswered.Retransmits != 1 {
+		t.Errorf("unanswered flow: %+v state %s", unanswered, unanswered.State())
+	}
+	if reset.State() != StateReset || reset.Client.String() != "10.0.0.1:40000" || reset.ResetBy.String() != "10.0.0.2:80" ||
+		reset.Retransmits != 1 || reset.Bytes != 200 || reset.SYNACK != 1 {
+		t.Errorf("reset flow: %+v state %s", reset, reset.State())
+	}
+	if closed.State() != StateClosed || closed.Client.String() != "10.0.0.5:51000" {
+		t.Errorf("midstream flow: %+v state %s", closed, closed.State())
+	}
+
+	if s.Count(StateSYNUnanswered) != 1 || s.Count(StateReset) != 1 {
+		t.Errorf("counts: unanswered %d, reset %d", s.Count(StateSYNUnanswered), s.Count(StateReset))
+	}
+	if segments, flows := s.Retransmits(); segments != 2 || flows != 2 {
+		t.Errorf("retransmits: %d segments in %d flows", segments, flows)
+	}
+
+	table := strings.Split(s.Table(2), "\n")
+	if len(table) != 4 || !strings.HasPrefix(table[0], "CLIENT") || !strings.HasSuffix(table[1], StateSYNUnanswered) || table[3] != "... 1 more flows" {
+		t.Errorf("table:\n%s", strings.Join(table, "\n"))
+	}
+}
+
+func TestStorePrunes(t *testing.T) {
+	dir := t.TempDir()
+	s := &Store{Dir: dir, Keep: 2}
+	at := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := s.Save([]byte("pcap"), at.Add(time.Duration(i)*time.Second), "prod", "shop", "web/0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		mod := at.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	if got, want := filepath.Base(paths[2]), "prod_shop_web-0_20261015T100002Z.pcap"; got != want {
+		t.Errorf("name = %q, want %q", got, want)
+	}
+	s.prune()
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("oldest pcap not pruned: %v", err)
+	}
+	for _, p := range paths[1:] {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("recent pcap pruned: %v", err)
+		}
+	}
+}
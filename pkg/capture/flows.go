@@ -0,0 +1,363 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+
+	protoTCP = 6
+	protoUDP = 17
+
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpRST = 0x04
+	tcpACK = 0x10
+)
+
+// Flow states of TCP connections.
+const (
+	StateSYNUnanswered = "syn-unanswered"
+	StateReset         = "reset"
+	StateClosed        = "closed"
+	StateEstablished   = "established"
+	// StateMidstream is a connection whose handshake predates the capture.
+	StateMidstream = "midstream"
+)
+
+// Flow is one TCP connection or UDP conversation seen in a capture.
+type Flow struct {
+	Protocol string // tcp or udp
+	// Client opened the connection: the sender of the SYN or, when the
+	// handshake was not captured, the endpoint with the higher port.
+	Client netip.AddrPort
+	Server netip.AddrPort
+
+	Packets     int
+	Bytes       int // transport payload bytes, as sent on the wire
+	SYN         int // SYNs from the client, including retries
+	SYNACK      int
+	RST         int
+	FIN         int
+	Retransmits int // data segments, SYNs and FINs sent again
+	// ResetBy is the sender of the last RST.
+	ResetBy netip.AddrPort
+
+	First, Last time.Time
+
+	seen [2]map[uint64]bool // segments sent by the client and the server
+}
+
+// State summarizes how a TCP connection fared; it is empty for UDP.
+func (f *Flow) State() string {
+	switch {
+	case f.Protocol != "tcp":
+		return ""
+	case f.SYN > 0 && f.SYNACK == 0:
+		return StateSYNUnanswered
+	case f.RST > 0:
+		return StateReset
+	case f.FIN > 0:
+		return StateClosed
+	case f.SYNACK > 0:
+		return StateEstablished
+	}
+	return StateMidstream
+}
+
+// problems ranks flows for sorting: failed handshakes, then resets, then
+// retransmissions.
+func (f *Flow) problems() int {
+	n := 0
+	switch f.State() {
+	case StateSYNUnanswered:
+		n += 4
+	case StateReset:
+		n += 2
+	}
+	if f.Retransmits > 0 {
+		n++
+	}
+	return n
+}
+
+// Summary is the flow table of a capture.
+type Summary struct {
+	Packets int // packets decoded into flows
+	Skipped int // non-IP, fragmented or non-TCP/UDP packets
+	// Truncated is set when the capture file ended mid-record.
+	Truncated  bool
+	Start, End time.Time
+	// Flows are sorted with the most problematic first, then by packets.
+	Flows []*Flow
+}
+
+// Count returns the number of TCP flows in state.
+func (s *Summary) Count(state string) int {
+	n := 0
+	for _, f := range s.Flows {
+		if f.State() == state {
+			n++
+		}
+	}
+	return n
+}
+
+// Retransmits returns the retransmitted segments and the flows that had any.
+func (s *Summary) Retransmits() (segments, flows int) {
+	for _, f := range s.Flows {
+		if f.Retransmits > 0 {
+			segments += f.Retransmits
+			flows++
+		}
+	}
+	return segments, flows
+}
+
+// Table renders up to limit flows (all when limit <= 0) as an aligned
+// text table.
+func (s *Summary) Table(limit int) string {
+	flows := s.Flows
+	if limit > 0 && len(flows) > limit {
+		flows = flows[:limit]
+	}
+	rows := [][]string{{"CLIENT", "SERVER", "PROTO", "PKTS", "BYTES", "SYN", "SYN-ACK", "RST", "FIN", "RETRANS", "STATE"}}
+	for _, f := range flows {
+		rows = append(rows, []string{
+			f.Client.String(), f.Server.String(), f.Protocol,
+			fmt.Sprint(f.Packets), fmt.Sprint(f.Bytes), fmt.Sprint(f.SYN), fmt.Sprint(f.SYNACK),
+			fmt.Sprint(f.RST), fmt.Sprint(f.FIN), fmt.Sprint(f.Retransmits), f.State(),
+		})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(row)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], cell)
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		b.WriteString("\n")
+	}
+	if len(s.Flows) > len(flows) {
+		fmt.Fprintf(&b, "... %d more flows\n", len(s.Flows)-len(flows))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// segment is the transport header of one packet.
+type segment struct {
+	protocol   string
+	src, dst   netip.AddrPort
+	flags      byte
+	seq        uint32
+	payloadLen int
+}
+
+// Summarize groups the packets of p into flows.
+func Summarize(p *Pcap) *Summary {
+	s := &Summary{Truncated: p.Truncated}
+	flows := make(map[[2]netip.AddrPort]*Flow)
+	for _, pkt := range p.Packets {
+		seg, ok := decode(p.LinkType, pkt.Data)
+		if !ok {
+			s.Skipped++
+			continue
+		}
+		s.Packets++
+		if s.Start.IsZero() {
+			s.Start = pkt.Time
+		}
+		s.End = pkt.Time
+
+		key := [2]netip.AddrPort{seg.src, seg.dst}
+		if seg.dst.Compare(seg.src) < 0 {
+			key = [2]netip.AddrPort{seg.dst, seg.src}
+		}
+		f := flows[key]
+		if f == nil {
+			f = newFlow(seg, pkt.Time)
+			flows[key] = f
+			s.Flows = append(s.Flows, f)
+		}
+		f.add(seg, pkt.Time)
+	}
+	sort.SliceStable(s.Flows, func(i, j int) bool {
+		a, b := s.Flows[i], s.Flows[j]
+		if a.problems() != b.problems() {
+			return a.problems() > b.problems()
+		}
+		return a.Packets > b.Packets
+	})
+	return s
+}
+
+func newFlow(seg segment, t time.Time) *Flow {
+	client, server := seg.src, seg.dst
+	switch {
+	case seg.protocol == "tcp" && seg.flags&(tcpSYN|tcpACK) == tcpSYN:
+	case seg.protocol == "tcp" && seg.flags&(tcpSYN|tcpACK) == tcpSYN|tcpACK:
+		client, server = seg.dst, seg.src
+	case seg.src.Port() < seg.dst.Port():
+		client, server = seg.dst, seg.src
+	}
+	return &Flow{
+		Protocol: seg.protocol,
+		Client:   client,
+		Server:   server,
+		First:    t,
+		seen:     [2]map[uint64]bool{{}, {}},
+	}
+}
+
+func (f *Flow) add(seg segment, t time.Time) {
+	f.Packets++
+	f.Bytes += seg.payloadLen
+	f.Last = t
+	if seg.protocol != "tcp" {
+		return
+	}
+	dir := 0
+	if seg.src != f.Client {
+		dir = 1
+	}
+	switch {
+	case seg.flags&tcpRST != 0:
+		f.RST++
+		f.ResetBy = seg.src
+		return
+	case seg.flags&(tcpSYN|tcpACK) == tcpSYN && dir == 0:
+		f.SYN++
+	case seg.flags&(tcpSYN|tcpACK) == tcpSYN|tcpACK:
+		f.SYNACK++
+	}
+	if seg.flags&tcpFIN != 0 {
+		f.FIN++
+	}
+	if seg.payloadLen == 0 && seg.flags&(tcpSYN|tcpFIN) == 0 {
+		return
+	}
+	id := uint64(seg.seq)<<32 | uint64(seg.payloadLen)<<8 | uint64(seg.flags&(tcpSYN|tcpFIN))
+	if f.seen[dir][id] {
+		f.Retransmits++
+	}
+	f.seen[dir][id] = true
+}
+
+// decode extracts the TCP or UDP header of a frame.
+func decode(linkType uint32, data []byte) (segment, bool) {
+	etherType, data, ok := networkLayer(linkType, data)
+	if !ok {
+		return segment{}, false
+	}
+
+	var src, dst netip.Addr
+	var proto byte
+	var l4 []byte
+	l4Len := 0
+	switch etherType {
+	case etherTypeIPv4:
+		if len(data) < 20 {
+			return segment{}, false
+		}
+		ihl := int(data[0]&0x0f) * 4
+		if ihl < 20 || len(data) < ihl || binary.BigEndian.Uint16(data[6:])&0x1fff != 0 {
+			return segment{}, false
+		}
+		src = netip.AddrFrom4([4]byte(data[12:16]))
+		dst = netip.AddrFrom4([4]byte(data[16:20]))
+		proto = data[9]
+		l4, l4Len = data[ihl:], int(binary.BigEndian.Uint16(data[2:]))-ihl
+	case etherTypeIPv6:
+		if len(data) < 40 {
+			return segment{}, false
+		}
+		src = netip.AddrFrom16([16]byte(data[8:24]))
+		dst = netip.AddrFrom16([16]byte(data[24:40]))
+		proto = data[6]
+		l4, l4Len = data[40:], int(binary.BigEndian.Uint16(data[4:]))
+	default:
+		return segment{}, false
+	}
+
+	switch proto {
+	case protoTCP:
+		if len(l4) < 14 {
+			return segment{}, false
+		}
+		return segment{
+			protocol:   "tcp",
+			src:        netip.AddrPortFrom(src, binary.BigEndian.Uint16(l4)),
+			dst:        netip.AddrPortFrom(dst, binary.BigEndian.Uint16(l4[2:])),
+			seq:        binary.BigEndian.Uint32(l4[4:]),
+			flags:      l4[13],
+			payloadLen: max(0, l4Len-int(l4[12]>>4)*4),
+		}, true
+	case protoUDP:
+		if len(l4) < 8 {
+			return segment{}, false
+		}
+		return segment{
+			protocol:   "udp",
+			src:        netip.AddrPortFrom(src, binary.BigEndian.Uint16(l4)),
+			dst:        netip.AddrPortFrom(dst, binary.BigEndian.Uint16(l4[2:])),
+			payloadLen: max(0, int(binary.BigEndian.Uint16(l4[4:]))-8),
+		}, true
+	}
+	return segment{}, false
+}
+
+// networkLayer strips the link-layer header of a frame.
+func networkLayer(linkType uint32, data []byte) (uint16, []byte, bool) {
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(data) < 14 {
+			return 0, nil, false
+		}
+		etherType, off := binary.BigEndian.Uint16(data[12:]), 14
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(data) < off+4 {
+				return 0, nil, false
+			}
+			etherType, off = binary.BigEndian.Uint16(data[off+2:]), off+4
+		}
+		return etherType, data[off:], true
+	case LinkTypeLinuxSLL:
+		if len(data) < 16 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(data[14:]), data[16:], true
+	case LinkTypeLinuxSLL2:
+		if len(data) < 20 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(data), data[20:], true
+	case LinkTypeRaw:
+		if len(data) == 0 {
+			return 0, nil, false
+		}
+		switch data[0] >> 4 {
+		case 4:
+			return etherTypeIPv4, data, true
+		case 6:
+			return etherTypeIPv6, data, true
+		}
+	}
+	return 0, nil, false
+}
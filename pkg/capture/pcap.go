@@ -0,0 +1,96 @@
+// Package capture decodes packet captures taken by capture probes and
+// summarizes them into per-connection flows.
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Link-layer header types of the pcap format.
+const (
+	LinkTypeEthernet  = 1
+	LinkTypeRaw       = 101
+	LinkTypeLinuxSLL  = 113
+	LinkTypeLinuxSLL2 = 276
+)
+
+const (
+	pcapHeaderLen   = 24
+	recordHeaderLen = 16
+	maxRecordLen    = 256 * 1024
+)
+
+// Packet is one captured frame.
+type Packet struct {
+	Time time.Time
+	Data []byte
+	// OrigLen is the length of the frame on the wire; Data holds at most
+	// the capture's snap length of it.
+	OrigLen int
+}
+
+// Pcap is a decoded capture file.
+type Pcap struct {
+	LinkType uint32
+	Packets  []Packet
+	// Truncated is set when the file ends in the middle of a record, as
+	// when tcpdump is stopped while writing.
+	Truncated bool
+}
+
+// ParsePcap decodes a classic pcap file with microsecond or nanosecond
+// timestamps in either byte order. pcapng files are not supported.
+func ParsePcap(data []byte) (*Pcap, error) {
+	if len(data) < pcapHeaderLen {
+		return nil, errors.New("pcap: file shorter than its header")
+	}
+	var order binary.ByteOrder
+	nanos := false
+	switch magic := binary.LittleEndian.Uint32(data); magic {
+	case 0xa1b2c3d4:
+		order = binary.LittleEndian
+	case 0xa1b23c4d:
+		order, nanos = binary.LittleEndian, true
+	case 0xd4c3b2a1:
+		order = binary.BigEndian
+	case 0x4d3cb2a1:
+		order, nanos = binary.BigEndian, true
+	case 0x0a0d0d0a:
+		return nil, errors.New("pcap: pcapng files are not supported")
+	default:
+		return nil, fmt.Errorf("pcap: unknown magic number %#x", magic)
+	}
+
+	p := &Pcap{LinkType: order.Uint32(data[20:]) & 0x0fffffff}
+	rest := data[pcapHeaderLen:]
+	for len(rest) > 0 {
+		if len(rest) < recordHeaderLen {
+			p.Truncated = true
+			break
+		}
+		sec, frac := order.Uint32(rest), order.Uint32(rest[4:])
+		inclLen, origLen := order.Uint32(rest[8:]), order.Uint32(rest[12:])
+		if inclLen > maxRecordLen {
+			return nil, fmt.Errorf("pcap: record of %d bytes exceeds %d", inclLen, maxRecordLen)
+		}
+		rest = rest[recordHeaderLen:]
+		if int(inclLen) > len(rest) {
+			p.Truncated = true
+			break
+		}
+		ns := int64(frac) * 1000
+		if nanos {
+			ns = int64(frac)
+		}
+		p.Packets = append(p.Packets, Packet{
+			Time:    time.Unix(int64(sec), ns).UTC(),
+			Data:    rest[:inclLen],
+			OrigLen: int(origLen),
+		})
+		rest = rest[inclLen:]
+	}
+	return p, nil
+}
@@ -0,0 +1,71 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultKeep is the number of pcaps a Store keeps.
+const DefaultKeep = 20
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Store keeps the most recent pcaps in a directory.
+type Store struct {
+	Dir  string
+	Keep int // pcaps kept; older ones are deleted on Save (DefaultKeep when 0)
+}
+
+// Save writes data as a pcap named after parts and the capture time, then
+// prunes the oldest pcaps, and returns the file's path.
+func (s *Store) Save(data []byte, at time.Time, parts ...string) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return "", fmt.Errorf("create capture directory: %w", err)
+	}
+	name := unsafeFileChars.ReplaceAllString(strings.Join(parts, "_"), "-")
+	path := filepath.Join(s.Dir, fmt.Sprintf("%s_%s.pcap", name, at.UTC().Format("20060102T150405Z")))
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return "", fmt.Errorf("write pcap: %w", err)
+	}
+	s.prune()
+	return path, nil
+}
+
+// prune deletes the oldest pcaps beyond Keep.
+func (s *Store) prune() {
+	keep := s.Keep
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return
+	}
+	type pcapFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []pcapFile
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".pcap" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, pcapFile{filepath.Join(s.Dir, e.Name()), info.ModTime()})
+	}
+	if len(files) <= keep {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	for _, f := range files[keep:] {
+		_ = os.Remove(f.path)
+	}
+}
@@ -2,71 +2,276 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// Supported MCP transports.
+const (
+	TransportHTTP  = "http"
+	TransportStdio = "stdio"
+)
+
+// Supported authentication modes for the HTTP transport.
+const (
+	AuthModeBearer      = "bearer"
+	AuthModeTokenReview = "tokenreview"
+	AuthModeOIDC        = "oidc"
+)
+
+// TLS policy profiles for the TLS configuration audit.
+const (
+	TLSProfileIntermediate = "intermediate"
+	TLSProfileModern       = "modern"
+	TLSProfileFIPS         = "fips"
+)
+
+// minResponseBytes is the smallest response budget, leaving room for a
+// summary and the truncation note.
+const minResponseBytes = 1024
+
+// ClusterContext is an additional cluster reached through a kubeconfig context.
+type ClusterContext struct {
+	Name    string
+	Context string
+}
+
 type Config struct {
-	ClusterName         string
-	Port                int
-	LogLevel            string
-	Namespace           string
-	CacheTTL            time.Duration
+	// File is the YAML configuration file the settings were read from, if
+	// any; changes to it are applied without a restart where possible.
+	File string
+
+	ClusterName string
+	// Clusters are diagnosed alongside ClusterName and selected per request
+	// with the "cluster" tool argument.
+	Clusters  []ClusterContext
+	Transport string
+	Port      int
+	LogLevel  string
+	Namespace string
+	CacheTTL  time.Duration
+	// Tool call timeouts per class: ToolTimeout for read tools, then scans
+	// and probes, with per-tool overrides by name.
 	ToolTimeout         time.Duration
+	ScanToolTimeout     time.Duration
+	ProbeToolTimeout    time.Duration
+	ToolTimeouts        map[string]time.Duration
 	ProbeNamespace      string
 	ProbeImage          string
 	MaxConcurrentProbes int
+	ProbeQueueSize      int
+	ProbeRateLimit      int
+	// PrivilegedProbes registers the tools whose probe pods run as root on
+	// a node's host network: capture_traffic and inspect_connections.
+	PrivilegedProbes bool
+	// PacketCaptureDir keeps the pcaps of capture_traffic.
+	PacketCaptureDir string
+
+	// Configuration history: a snapshot of networking resources every
+	// HistoryInterval (0 disables), keeping HistorySize snapshots in memory
+	// and, when HistoryDir is set, on disk.
+	HistoryInterval time.Duration
+	HistorySize     int
+	HistoryDir      string
+
+	// Finding history: the findings of every tool call, kept for
+	// FindingHistoryRetention (0 disables) in memory and, when
+	// FindingHistoryDir is set, on disk.
+	FindingHistoryRetention time.Duration
+	FindingHistoryDir       string
+
+	// Finding suppressions, kept in ConfigMap SuppressionsNamespace/
+	// SuppressionsName of each cluster; empty SuppressionsNamespace disables
+	// them.
+	SuppressionsNamespace string
+	SuppressionsName      string
+
+	// Custom skills: definitions in SkillsDir and in the ConfigMaps of
+	// SkillsNamespace labelled mcp-k8s-networking/skill, reloaded every
+	// SkillsReloadInterval (0 loads them once).
+	SkillsDir            string
+	SkillsNamespace      string
+	SkillsReloadInterval time.Duration
+	// Scheduled skills: cron schedules and webhook in SkillScheduleFile, with
+	// the latest run of each schedule kept in SkillResultsDir when set.
+	SkillScheduleFile string
+	SkillResultsDir   string
+
+	// Prometheus-compatible API for traffic metrics; the metrics tools are
+	// only registered when PrometheusURL is set. PrometheusClusterLabel
+	// scopes queries to ClusterName when several clusters share the backend.
+	PrometheusURL          string
+	PrometheusTokenFile    string
+	PrometheusClusterLabel string
+
+	// Trace backend (tempo or jaeger) for find_failing_traces; the tool is
+	// only registered when TracesURL is set.
+	TracesBackend   string
+	TracesURL       string
+	TracesTokenFile string
+
+	// Log backend (loki or elasticsearch) that get_proxy_logs and
+	// analyze_log_errors read with source=backend, to look back further than
+	// the logs kept on the nodes; empty LogBackendURL disables it.
+	// LogBackendIndex is the Elasticsearch index pattern searched, and
+	// LogBackendClusterLabel scopes queries to ClusterName like
+	// PrometheusClusterLabel.
+	LogBackend             string
+	LogBackendURL          string
+	LogBackendTokenFile    string
+	LogBackendIndex        string
+	LogBackendClusterLabel string
+
+	// OTLPEndpoint receives traces, metrics and logs; empty disables
+	// telemetry export.
+	OTLPEndpoint string
+
+	// TLSPolicyProfile is the default profile audit_tls_policy checks
+	// listener TLS versions and cipher suites against.
+	TLSPolicyProfile string
+
+	// Authentication for /mcp; empty AuthModes leaves the endpoint open.
+	AuthModes            []string
+	AuthPolicyFile       string
+	TokenReviewAudiences []string
+	OIDCIssuer           string
+	OIDCAudience         string
+	OIDCUsernameClaim    string
+	OIDCGroupsClaim      string
+
+	// Impersonation: tool calls run as the authenticated caller when
+	// ImpersonateCaller is set, otherwise as ImpersonateUser when set.
+	ImpersonateCaller bool
+	ImpersonateUser   string
+	ImpersonateGroups []string
+
+	// Namespace scoping: tool calls only reach AllowedNamespaces (when set)
+	// and never DeniedNamespaces.
+	AllowedNamespaces []string
+	DeniedNamespaces  []string
+
+	// DataMinimization replaces IPs, node names and external hostnames in
+	// responses with pseudonyms derived from DataMinimizationSalt.
+	DataMinimization     bool
+	DataMinimizationSalt string
+	// RedactSecrets removes secret material, such as tokens, passwords and
+	// certificates, from finding details and raw tool output.
+	RedactSecrets bool
+
+	// ResponseMaxBytes caps text tool results; larger results drop details,
+	// then suggestions, then the least severe findings. 0 disables the cap.
+	ResponseMaxBytes int
+
+	// DeduplicateCalls runs concurrent identical tool calls once and shares
+	// the result; ResultCacheTTL also serves identical calls that finished
+	// less than that long ago. 0 disables the result cache.
+	DeduplicateCalls bool
+	ResultCacheTTL   time.Duration
 }
 
-func Load() (*Config, error) {
-	clusterName := os.Getenv("CLUSTER_NAME")
+// Load reads the configuration from the environment and, when file is not
+// empty, from that YAML file. Precedence is file > environment > default: a
+// setting in the file overrides its environment variable, which overrides the
+// default. The file wins because it is the part that is reloaded at runtime,
+// while the Helm chart sets most environment variables from its values.
+func Load(file string) (*Config, error) {
+	getenv := os.Getenv
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		getenv = func(key string) string {
+			if v, ok := values[key]; ok {
+				return v
+			}
+			return os.Getenv(key)
+		}
+	}
+
+	clusterName := getenv("CLUSTER_NAME")
 	if clusterName == "" {
-		return nil, fmt.Errorf("CLUSTER_NAME environment variable is required")
+		return nil, fmt.Errorf("CLUSTER_NAME environment variable or clusterName setting is required")
+	}
+
+	clusters, err := parseClusters(getenv("CLUSTERS"), clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := getenv("MCP_TRANSPORT")
+	if transport == "" {
+		transport = TransportHTTP
+	}
+	if err := ValidateTransport(transport); err != nil {
+		return nil, err
 	}
 
 	port := 8080
-	if p := os.Getenv("PORT"); p != "" {
+	if p := getenv("PORT"); p != "" {
 		if v, err := strconv.Atoi(p); err == nil {
 			port = v
 		}
 	}
 
-	logLevel := os.Getenv("LOG_LEVEL")
+	logLevel := getenv("LOG_LEVEL")
 	if logLevel == "" {
 		logLevel = "info"
 	}
 
-	namespace := os.Getenv("NAMESPACE")
+	namespace := getenv("NAMESPACE")
 
 	cacheTTL := 30 * time.Second
-	if v := os.Getenv("CACHE_TTL"); v != "" {
+	if v := getenv("CACHE_TTL"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			cacheTTL = d
 		}
 	}
 
 	toolTimeout := 10 * time.Second
-	if v := os.Getenv("TOOL_TIMEOUT"); v != "" {
+	if v := getenv("TOOL_TIMEOUT"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			toolTimeout = d
 		}
 	}
 
-	probeNamespace := os.Getenv("PROBE_NAMESPACE")
+	scanToolTimeout := 60 * time.Second
+	if v := getenv("TOOL_TIMEOUT_SCAN"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			scanToolTimeout = d
+		}
+	}
+
+	probeToolTimeout := 120 * time.Second
+	if v := getenv("TOOL_TIMEOUT_PROBE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			probeToolTimeout = d
+		}
+	}
+
+	toolTimeouts, err := parseToolTimeouts(getenv("TOOL_TIMEOUTS"))
+	if err != nil {
+		return nil, err
+	}
+
+	probeNamespace := getenv("PROBE_NAMESPACE")
 	if probeNamespace == "" {
 		probeNamespace = "mcp-diagnostics"
 	}
 
-	probeImage := os.Getenv("PROBE_IMAGE")
+	probeImage := getenv("PROBE_IMAGE")
 	if probeImage == "" {
 		probeImage = "nicolaka/netshoot:latest"
 	}
 
 	maxProbes := 5
-	if v := os.Getenv("MAX_CONCURRENT_PROBES"); v != "" {
+	if v := getenv("MAX_CONCURRENT_PROBES"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			if n < 1 {
 				n = 1
@@ -77,32 +282,379 @@ func Load() (*Config, error) {
 		}
 	}
 
+	probeQueueSize := 10
+	if v := getenv("PROBE_QUEUE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			if n > 100 {
+				n = 100
+			}
+			probeQueueSize = n
+		}
+	}
+
+	// Probes per namespace per minute; 0 disables the limit.
+	probeRateLimit := 30
+	if v := getenv("PROBE_RATE_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			probeRateLimit = n
+		}
+	}
+
+	historyInterval := 5 * time.Minute
+	if v := getenv("CONFIG_HISTORY_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			historyInterval = d
+		}
+	}
+
+	historySize := 48
+	if v := getenv("CONFIG_HISTORY_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			historySize = n
+		}
+	}
+
+	findingHistoryRetention := 30 * 24 * time.Hour
+	if v := getenv("FINDING_HISTORY_RETENTION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			findingHistoryRetention = d
+		}
+	}
+
+	skillsReloadInterval := 30 * time.Second
+	if v := getenv("SKILLS_RELOAD_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			skillsReloadInterval = d
+		}
+	}
+
+	authModes, err := parseAuthModes(getenv("AUTH_MODE"))
+	if err != nil {
+		return nil, err
+	}
+	oidcIssuer := getenv("AUTH_OIDC_ISSUER")
+	oidcAudience := getenv("AUTH_OIDC_AUDIENCE")
+	for _, m := range authModes {
+		if m == AuthModeOIDC && (oidcIssuer == "" || oidcAudience == "") {
+			return nil, fmt.Errorf("AUTH_MODE oidc requires AUTH_OIDC_ISSUER and AUTH_OIDC_AUDIENCE")
+		}
+	}
+	oidcUsernameClaim := getenv("AUTH_OIDC_USERNAME_CLAIM")
+	if oidcUsernameClaim == "" {
+		oidcUsernameClaim = "sub"
+	}
+	oidcGroupsClaim := getenv("AUTH_OIDC_GROUPS_CLAIM")
+	if oidcGroupsClaim == "" {
+		oidcGroupsClaim = "groups"
+	}
+
+	impersonateCaller := false
+	if v := getenv("IMPERSONATE_CALLER"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMPERSONATE_CALLER %q: %w", v, err)
+		}
+		impersonateCaller = b
+	}
+	if impersonateCaller && len(authModes) == 0 {
+		return nil, fmt.Errorf("IMPERSONATE_CALLER requires AUTH_MODE to identify callers")
+	}
+	impersonateUser := getenv("IMPERSONATE_USER")
+	impersonateGroups := splitList(getenv("IMPERSONATE_GROUPS"))
+	if impersonateUser == "" && len(impersonateGroups) > 0 {
+		return nil, fmt.Errorf("IMPERSONATE_GROUPS requires IMPERSONATE_USER")
+	}
+
+	allowedNamespaces := splitList(getenv("ALLOWED_NAMESPACES"))
+	deniedNamespaces := splitList(getenv("DENIED_NAMESPACES"))
+	for _, ns := range allowedNamespaces {
+		if slices.Contains(deniedNamespaces, ns) {
+			return nil, fmt.Errorf("namespace %q is both in ALLOWED_NAMESPACES and DENIED_NAMESPACES", ns)
+		}
+	}
+
+	privilegedProbes := false
+	if v := getenv("PRIVILEGED_PROBES"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PRIVILEGED_PROBES %q: %w", v, err)
+		}
+		privilegedProbes = b
+	}
+	packetCaptureDir := getenv("PACKET_CAPTURE_DIR")
+	if packetCaptureDir == "" {
+		packetCaptureDir = filepath.Join(os.TempDir(), "mcp-k8s-networking-captures")
+	}
+
+	redactSecrets := true
+	if v := getenv("REDACT_SECRETS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDACT_SECRETS %q: %w", v, err)
+		}
+		redactSecrets = b
+	}
+
+	dataMinimization := false
+	if v := getenv("DATA_MINIMIZATION"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DATA_MINIMIZATION %q: %w", v, err)
+		}
+		dataMinimization = b
+	}
+
+	// RESPONSE_MAX_TOKENS is converted at ~4 bytes per token; the smaller
+	// of the two caps applies.
+	responseMaxBytes := 65536
+	if v := getenv("RESPONSE_MAX_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || (n > 0 && n < minResponseBytes) {
+			return nil, fmt.Errorf("invalid RESPONSE_MAX_BYTES %q: expected at least %d bytes, or 0 to disable", v, minResponseBytes)
+		}
+		responseMaxBytes = n
+	}
+	if v := getenv("RESPONSE_MAX_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n*4 < minResponseBytes {
+			return nil, fmt.Errorf("invalid RESPONSE_MAX_TOKENS %q: expected at least %d tokens", v, minResponseBytes/4)
+		}
+		if responseMaxBytes == 0 || n*4 < responseMaxBytes {
+			responseMaxBytes = n * 4
+		}
+	}
+
+	deduplicateCalls := true
+	if v := getenv("DEDUPLICATE_CALLS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEDUPLICATE_CALLS %q: %w", v, err)
+		}
+		deduplicateCalls = b
+	}
+	var resultCacheTTL time.Duration
+	if v := getenv("RESULT_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid RESULT_CACHE_TTL %q: expected a duration such as 5s, or 0 to disable", v)
+		}
+		resultCacheTTL = d
+	}
+
+	var suppressionsNamespace, suppressionsName string
+	if v := getenv("SUPPRESSIONS_CONFIGMAP"); v != "" {
+		var ok bool
+		suppressionsNamespace, suppressionsName, ok = strings.Cut(v, "/")
+		if !ok || suppressionsNamespace == "" || suppressionsName == "" {
+			return nil, fmt.Errorf("invalid SUPPRESSIONS_CONFIGMAP %q (expected namespace/name)", v)
+		}
+	}
+
+	tracesBackend := strings.ToLower(getenv("TRACES_BACKEND"))
+	if tracesBackend == "" {
+		tracesBackend = "tempo"
+	}
+
+	logBackend := strings.ToLower(getenv("LOG_BACKEND"))
+	if logBackend == "" {
+		logBackend = "loki"
+	}
+	logBackendIndex := getenv("LOG_BACKEND_INDEX")
+	if logBackendIndex == "" {
+		logBackendIndex = "logs-*"
+	}
+
+	tlsProfile := strings.ToLower(getenv("TLS_POLICY_PROFILE"))
+	if tlsProfile == "" {
+		tlsProfile = TLSProfileIntermediate
+	}
+	if err := ValidateTLSProfile(tlsProfile); err != nil {
+		return nil, err
+	}
+
 	return &Config{
+		File:                file,
 		ClusterName:         clusterName,
+		Clusters:            clusters,
+		Transport:           transport,
 		Port:                port,
 		LogLevel:            logLevel,
 		Namespace:           namespace,
 		CacheTTL:            cacheTTL,
 		ToolTimeout:         toolTimeout,
+		ScanToolTimeout:     scanToolTimeout,
+		ProbeToolTimeout:    probeToolTimeout,
+		ToolTimeouts:        toolTimeouts,
 		ProbeNamespace:      probeNamespace,
 		ProbeImage:          probeImage,
 		MaxConcurrentProbes: maxProbes,
+		ProbeQueueSize:      probeQueueSize,
+		ProbeRateLimit:      probeRateLimit,
+		PrivilegedProbes:    privilegedProbes,
+		PacketCaptureDir:    packetCaptureDir,
+		HistoryInterval:     historyInterval,
+		HistorySize:         historySize,
+		HistoryDir:          getenv("CONFIG_HISTORY_DIR"),
+
+		FindingHistoryRetention: findingHistoryRetention,
+		FindingHistoryDir:       getenv("FINDING_HISTORY_DIR"),
+
+		SuppressionsNamespace: suppressionsNamespace,
+		SuppressionsName:      suppressionsName,
+
+		SkillsDir:            getenv("SKILLS_DIR"),
+		SkillsNamespace:      getenv("SKILLS_CONFIGMAP_NAMESPACE"),
+		SkillsReloadInterval: skillsReloadInterval,
+		SkillScheduleFile:    getenv("SKILL_SCHEDULE_FILE"),
+		SkillResultsDir:      getenv("SKILL_RESULTS_DIR"),
+
+		PrometheusURL:          getenv("PROMETHEUS_URL"),
+		PrometheusTokenFile:    getenv("PROMETHEUS_TOKEN_FILE"),
+		PrometheusClusterLabel: getenv("PROMETHEUS_CLUSTER_LABEL"),
+
+		TracesBackend:   tracesBackend,
+		TracesURL:       getenv("TRACES_URL"),
+		TracesTokenFile: getenv("TRACES_TOKEN_FILE"),
+
+		LogBackend:             logBackend,
+		LogBackendURL:          getenv("LOG_BACKEND_URL"),
+		LogBackendTokenFile:    getenv("LOG_BACKEND_TOKEN_FILE"),
+		LogBackendIndex:        logBackendIndex,
+		LogBackendClusterLabel: getenv("LOG_BACKEND_CLUSTER_LABEL"),
+
+		OTLPEndpoint: getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+
+		AuthModes:            authModes,
+		AuthPolicyFile:       getenv("AUTH_POLICY_FILE"),
+		TokenReviewAudiences: splitList(getenv("AUTH_TOKENREVIEW_AUDIENCES")),
+		OIDCIssuer:           oidcIssuer,
+		OIDCAudience:         oidcAudience,
+		OIDCUsernameClaim:    oidcUsernameClaim,
+		OIDCGroupsClaim:      oidcGroupsClaim,
+
+		ImpersonateCaller: impersonateCaller,
+		ImpersonateUser:   impersonateUser,
+		ImpersonateGroups: impersonateGroups,
+
+		AllowedNamespaces: allowedNamespaces,
+		DeniedNamespaces:  deniedNamespaces,
+
+		DataMinimization:     dataMinimization,
+		RedactSecrets:        redactSecrets,
+		DataMinimizationSalt: getenv("DATA_MINIMIZATION_SALT"),
+		TLSPolicyProfile:     tlsProfile,
+		ResponseMaxBytes:     responseMaxBytes,
+
+		DeduplicateCalls: deduplicateCalls,
+		ResultCacheTTL:   resultCacheTTL,
 	}, nil
 }
 
+// ValidateTLSProfile returns an error if profile is not a supported TLS policy profile.
+func ValidateTLSProfile(profile string) error {
+	switch profile {
+	case TLSProfileIntermediate, TLSProfileModern, TLSProfileFIPS:
+		return nil
+	default:
+		return fmt.Errorf("unsupported TLS policy profile %q (expected %q, %q or %q)", profile, TLSProfileIntermediate, TLSProfileModern, TLSProfileFIPS)
+	}
+}
+
+// ValidateTransport returns an error if transport is not a supported MCP transport.
+func ValidateTransport(transport string) error {
+	switch transport {
+	case TransportHTTP, TransportStdio:
+		return nil
+	default:
+		return fmt.Errorf("unsupported transport %q (expected %q or %q)", transport, TransportHTTP, TransportStdio)
+	}
+}
+
+// parseClusters parses CLUSTERS, a comma-separated list of kubeconfig contexts
+// written as "name=context" or just "context" (name = context).
+func parseClusters(v, primary string) ([]ClusterContext, error) {
+	var clusters []ClusterContext
+	seen := map[string]bool{primary: true}
+	for _, entry := range splitList(v) {
+		name, kubeContext, found := strings.Cut(entry, "=")
+		if !found {
+			kubeContext = name
+		}
+		name, kubeContext = strings.TrimSpace(name), strings.TrimSpace(kubeContext)
+		if name == "" || kubeContext == "" {
+			return nil, fmt.Errorf("invalid CLUSTERS entry %q (expected name=context)", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate cluster name %q in CLUSTERS", name)
+		}
+		seen[name] = true
+		clusters = append(clusters, ClusterContext{Name: name, Context: kubeContext})
+	}
+	return clusters, nil
+}
+
+// parseToolTimeouts parses a comma-separated TOOL_TIMEOUTS value of
+// tool=duration entries.
+func parseToolTimeouts(v string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range splitList(v) {
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !found || name == "" || err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid TOOL_TIMEOUTS entry %q (expected tool=duration, e.g. quick_scan=30s)", entry)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
+}
+
+// parseAuthModes parses a comma-separated AUTH_MODE value. "none" or empty disables auth.
+func parseAuthModes(v string) ([]string, error) {
+	var modes []string
+	for _, m := range splitList(strings.ToLower(v)) {
+		switch m {
+		case "none":
+		case AuthModeBearer, AuthModeTokenReview, AuthModeOIDC:
+			modes = append(modes, m)
+		default:
+			return nil, fmt.Errorf("unsupported AUTH_MODE %q (expected %s, %s, %s or none)", m, AuthModeBearer, AuthModeTokenReview, AuthModeOIDC)
+		}
+	}
+	return modes, nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// logLevel is the level of the logger set up by SetupLogging.
+var logLevel slog.LevelVar
+
 // SetupLogging initializes the global slog logger with JSON output at the specified level.
-func SetupLogging(level string) {
-	var slogLevel slog.Level
+// In stdio mode w must not be os.Stdout, which carries the MCP protocol stream.
+func SetupLogging(level string, w io.Writer) {
+	SetLogLevel(level)
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: &logLevel})
+	slog.SetDefault(slog.New(handler))
+}
+
+// SetLogLevel changes the level of the logger set up by SetupLogging.
+func SetLogLevel(level string) {
 	switch strings.ToLower(level) {
 	case "debug":
-		slogLevel = slog.LevelDebug
+		logLevel.Set(slog.LevelDebug)
 	case "warn", "warning":
-		slogLevel = slog.LevelWarn
+		logLevel.Set(slog.LevelWarn)
 	case "error":
-		slogLevel = slog.LevelError
+		logLevel.Set(slog.LevelError)
 	default:
-		slogLevel = slog.LevelInfo
+		logLevel.Set(slog.LevelInfo)
 	}
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slogLevel})
-	slog.SetDefault(slog.New(handler))
 }
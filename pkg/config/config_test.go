@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("clusterName: file\nlogLevel: debug\ntimeouts: {scan: 90s}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLUSTER_NAME", "env")
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("PORT", "9090")
+
+	cfg, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// file > environment > default
+	if cfg.ClusterName != "file" || cfg.LogLevel != "debug" || cfg.ScanToolTimeout != 90*time.Second {
+		t.Errorf("file settings not applied: cluster %q, log level %q, scan timeout %s", cfg.ClusterName, cfg.LogLevel, cfg.ScanToolTimeout)
+	}
+	if cfg.Port != 9090 {
+		t.Errorf("port %d, want 9090 from the environment", cfg.Port)
+	}
+	if cfg.ProbeToolTimeout != 120*time.Second {
+		t.Errorf("probe timeout %s, want the 2m default", cfg.ProbeToolTimeout)
+	}
+
+	// Without a file, the environment applies.
+	if cfg, err = Load(""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClusterName != "env" || cfg.LogLevel != "warn" || cfg.ScanToolTimeout != 60*time.Second {
+		t.Errorf("environment not applied: cluster %q, log level %q, scan timeout %s", cfg.ClusterName, cfg.LogLevel, cfg.ScanToolTimeout)
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// fileConfig is the YAML configuration file. Each setting stands for the
+// environment variable in its env tag, and overrides it when present; see Load
+// for the precedence.
+type fileConfig struct {
+	ClusterName string   `json:"clusterName,omitempty" env:"CLUSTER_NAME"`
+	Clusters    []string `json:"clusters,omitempty" env:"CLUSTERS"`
+	Transport   string   `json:"transport,omitempty" env:"MCP_TRANSPORT"`
+	Port        *int     `json:"port,omitempty" env:"PORT"`
+	LogLevel    string   `json:"logLevel,omitempty" env:"LOG_LEVEL"`
+	Namespace   string   `json:"namespace,omitempty" env:"NAMESPACE"`
+	CacheTTL    string   `json:"cacheTTL,omitempty" env:"CACHE_TTL"`
+
+	Probe struct {
+		Namespace     string `json:"namespace,omitempty" env:"PROBE_NAMESPACE"`
+		Image         string `json:"image,omitempty" env:"PROBE_IMAGE"`
+		MaxConcurrent *int   `json:"maxConcurrent,omitempty" env:"MAX_CONCURRENT_PROBES"`
+		QueueSize     *int   `json:"queueSize,omitempty" env:"PROBE_QUEUE_SIZE"`
+		RateLimit     *int   `json:"rateLimit,omitempty" env:"PROBE_RATE_LIMIT"`
+		CaptureDir    string `json:"captureDir,omitempty" env:"PACKET_CAPTURE_DIR"`
+	} `json:"probe,omitempty"`
+
+	Telemetry struct {
+		OTLPEndpoint string `json:"otlpEndpoint,omitempty" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+		Prometheus   struct {
+			URL          string `json:"url,omitempty" env:"PROMETHEUS_URL"`
+			TokenFile    string `json:"tokenFile,omitempty" env:"PROMETHEUS_TOKEN_FILE"`
+			ClusterLabel string `json:"clusterLabel,omitempty" env:"PROMETHEUS_CLUSTER_LABEL"`
+		} `json:"prometheus,omitempty"`
+		Traces struct {
+			Backend   string `json:"backend,omitempty" env:"TRACES_BACKEND"`
+			URL       string `json:"url,omitempty" env:"TRACES_URL"`
+			TokenFile string `json:"tokenFile,omitempty" env:"TRACES_TOKEN_FILE"`
+		} `json:"traces,omitempty"`
+		Logs struct {
+			Backend      string `json:"backend,omitempty" env:"LOG_BACKEND"`
+			URL          string `json:"url,omitempty" env:"LOG_BACKEND_URL"`
+			TokenFile    string `json:"tokenFile,omitempty" env:"LOG_BACKEND_TOKEN_FILE"`
+			Index        string `json:"index,omitempty" env:"LOG_BACKEND_INDEX"`
+			ClusterLabel string `json:"clusterLabel,omitempty" env:"LOG_BACKEND_CLUSTER_LABEL"`
+		} `json:"logs,omitempty"`
+	} `json:"telemetry,omitempty"`
+
+	Features struct {
+		PrivilegedProbes  *bool `json:"privilegedProbes,omitempty" env:"PRIVILEGED_PROBES"`
+		RedactSecrets     *bool `json:"redactSecrets,omitempty" env:"REDACT_SECRETS"`
+		DataMinimization  *bool `json:"dataMinimization,omitempty" env:"DATA_MINIMIZATION"`
+		ImpersonateCaller *bool `json:"impersonateCaller,omitempty" env:"IMPERSONATE_CALLER"`
+	} `json:"features,omitempty"`
+
+	Calls struct {
+		Deduplicate *bool  `json:"deduplicate,omitempty" env:"DEDUPLICATE_CALLS"`
+		CacheTTL    string `json:"cacheTTL,omitempty" env:"RESULT_CACHE_TTL"`
+	} `json:"calls,omitempty"`
+
+	Namespaces struct {
+		Allowed []string `json:"allowed,omitempty" env:"ALLOWED_NAMESPACES"`
+		Denied  []string `json:"denied,omitempty" env:"DENIED_NAMESPACES"`
+	} `json:"namespaces,omitempty"`
+
+	Timeouts struct {
+		Read  string            `json:"read,omitempty" env:"TOOL_TIMEOUT"`
+		Scan  string            `json:"scan,omitempty" env:"TOOL_TIMEOUT_SCAN"`
+		Probe string            `json:"probe,omitempty" env:"TOOL_TIMEOUT_PROBE"`
+		Tools map[string]string `json:"tools,omitempty" env:"TOOL_TIMEOUTS"`
+	} `json:"timeouts,omitempty"`
+
+	Skills struct {
+		Dir                string `json:"dir,omitempty" env:"SKILLS_DIR"`
+		ConfigMapNamespace string `json:"configMapNamespace,omitempty" env:"SKILLS_CONFIGMAP_NAMESPACE"`
+		ReloadInterval     string `json:"reloadInterval,omitempty" env:"SKILLS_RELOAD_INTERVAL"`
+	} `json:"skills,omitempty"`
+
+	Auth struct {
+		Modes                []string `json:"modes,omitempty" env:"AUTH_MODE"`
+		PolicyFile           string   `json:"policyFile,omitempty" env:"AUTH_POLICY_FILE"`
+		TokenReviewAudiences []string `json:"tokenReviewAudiences,omitempty" env:"AUTH_TOKENREVIEW_AUDIENCES"`
+		OIDC                 struct {
+			Issuer        string `json:"issuer,omitempty" env:"AUTH_OIDC_ISSUER"`
+			Audience      string `json:"audience,omitempty" env:"AUTH_OIDC_AUDIENCE"`
+			UsernameClaim string `json:"usernameClaim,omitempty" env:"AUTH_OIDC_USERNAME_CLAIM"`
+			GroupsClaim   string `json:"groupsClaim,omitempty" env:"AUTH_OIDC_GROUPS_CLAIM"`
+		} `json:"oidc,omitempty"`
+	} `json:"auth,omitempty"`
+}
+
+// readFile reads the configuration file at path into the environment
+// variables its settings stand for. Unknown keys are an error, so that a
+// typo does not silently leave a setting at its default.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	var f fileConfig
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	fileValues(reflect.ValueOf(f), values)
+	return values, nil
+}
+
+// fileValues adds the settings set in v, a fileConfig or one of its
+// sections, to values in their environment variable form: lists are
+// comma-separated and maps become comma-separated key=value entries.
+func fileValues(v reflect.Value, values map[string]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		env := field.Tag.Get("env")
+		if env == "" {
+			fileValues(value, values)
+			continue
+		}
+		switch x := value.Interface().(type) {
+		case string:
+			if x != "" {
+				values[env] = x
+			}
+		case *int:
+			if x != nil {
+				values[env] = strconv.Itoa(*x)
+			}
+		case *bool:
+			if x != nil {
+				values[env] = strconv.FormatBool(*x)
+			}
+		case []string:
+			if len(x) > 0 {
+				values[env] = strings.Join(x, ",")
+			}
+		case map[string]string:
+			if len(x) > 0 {
+				entries := make([]string, 0, len(x))
+				for k, d := range x {
+					entries = append(entries, k+"="+d)
+				}
+				sort.Strings(entries)
+				values[env] = strings.Join(entries, ",")
+			}
+		}
+	}
+}
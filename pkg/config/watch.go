@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay lets a burst of file events, such as a ConfigMap update
+// swapping its ..data symlink, settle into one reload.
+const reloadDelay = 500 * time.Millisecond
+
+// liveSettings are the Config fields a running server applies on reload:
+// the log level, tool timeouts, probe limits, custom skills and the
+// authentication of an HTTP server started with authentication.
+var liveSettings = map[string]bool{
+	"File":                 true,
+	"LogLevel":             true,
+	"ToolTimeout":          true,
+	"ScanToolTimeout":      true,
+	"ProbeToolTimeout":     true,
+	"ToolTimeouts":         true,
+	"ProbeImage":           true,
+	"MaxConcurrentProbes":  true,
+	"ProbeQueueSize":       true,
+	"ProbeRateLimit":       true,
+	"SkillsDir":            true,
+	"SkillsNamespace":      true,
+	"SkillsReloadInterval": true,
+	"AuthModes":            true,
+	"AuthPolicyFile":       true,
+	"TokenReviewAudiences": true,
+	"OIDCIssuer":           true,
+	"OIDCAudience":         true,
+	"OIDCUsernameClaim":    true,
+	"OIDCGroupsClaim":      true,
+}
+
+// RestartRequired returns the names of the settings that differ between c
+// and next and only apply after a restart. Turning authentication on or off
+// is one of them; changing how callers authenticate is not.
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	a, b := reflect.ValueOf(*c), reflect.ValueOf(*next)
+	for i := 0; i < a.NumField(); i++ {
+		name := a.Type().Field(i).Name
+		if !liveSettings[name] && !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	if (len(c.AuthModes) > 0) != (len(next.AuthModes) > 0) {
+		changed = append(changed, "AuthModes")
+	}
+	return changed
+}
+
+// Watch reloads the configuration whenever cfg.File, or one of the extra
+// files it refers to, changes, and passes the new configuration to apply. A
+// file that no longer loads is logged and leaves the running configuration
+// in place. Watching stops when ctx ends.
+func Watch(ctx context.Context, cfg *Config, extra []string, apply func(*Config)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watching config file: %w", err)
+	}
+	// Directories are watched rather than files: editors and ConfigMap
+	// updates replace files, which ends a watch on the file itself.
+	files := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, f := range append([]string{cfg.File}, extra...) {
+		if f == "" {
+			continue
+		}
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			_ = w.Close()
+			return fmt.Errorf("watching config file: %w", err)
+		}
+		files[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+	for dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			_ = w.Close()
+			return fmt.Errorf("watching config file: %w", err)
+		}
+	}
+
+	go func() {
+		defer func() { _ = w.Close() }()
+		var timer *time.Timer
+		var fire <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if !files[ev.Name] && filepath.Base(ev.Name) != "..data" {
+					continue
+				}
+				if timer == nil {
+					timer = time.NewTimer(reloadDelay)
+				} else {
+					timer.Reset(reloadDelay)
+				}
+				fire = timer.C
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("config: file watch error", "error", err)
+			case <-fire:
+				fire = nil
+				next, err := Load(cfg.File)
+				if err != nil {
+					slog.Warn("config: invalid configuration, keeping the running one", "file", cfg.File, "error", err)
+					continue
+				}
+				apply(next)
+			}
+		}
+	}()
+	return nil
+}
@@ -24,6 +24,29 @@ type Features struct {
 	HasKuma       bool
 	HasFlannel    bool
 	HasKgateway   bool
+	// Experimental channel Gateway API routes and BackendTLSPolicy, detected
+	// by CRD kind.
+	HasTCPRoute         bool
+	HasTLSRoute         bool
+	HasUDPRoute         bool
+	HasBackendTLSPolicy bool
+	// Managed offerings: GKE Gateway policies, AWS VPC Lattice and AWS App Mesh.
+	HasGKEGateway bool
+	HasVPCLattice bool
+	HasAppMesh    bool
+	// GitOps controllers that apply networking resources from Git.
+	HasArgoCD bool
+	HasFlux   bool
+	// HasMetalLB is set when the MetalLB load balancer CRDs are installed.
+	HasMetalLB bool
+	// HasNodeLocalDNS is detected from the node-local-dns DaemonSet, not a CRD.
+	HasNodeLocalDNS bool
+	// HasIngressNginx is detected from an IngressClass of the ingress-nginx
+	// controller, which installs no CRDs.
+	HasIngressNginx bool
+	// HasExternalDNS is detected from the external-dns Deployment; its
+	// DNSEndpoint CRD is optional.
+	HasExternalDNS bool
 }
 
 type ProviderInfo struct {
@@ -43,8 +66,12 @@ type Discovery struct {
 	mu              sync.RWMutex
 	cancel          context.CancelFunc
 	ready           bool
+	scannedAt       time.Time // end of the last scan that succeeded
 
 	providerVersions map[string]string
+	// apiGroups holds every served API group (group -> preferred version),
+	// so extension providers can detect groups not covered by Features.
+	apiGroups map[string]string
 }
 
 func New(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, onChange OnChangeFunc) *Discovery {
@@ -53,6 +80,7 @@ func New(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Int
 		dynamicClient:    dynamicClient,
 		onChange:         onChange,
 		providerVersions: make(map[string]string),
+		apiGroups:        make(map[string]string),
 	}
 }
 
@@ -62,6 +90,25 @@ func (d *Discovery) GetFeatures() Features {
 	return d.features
 }
 
+// APIGroups returns a copy of the served API groups and their preferred versions.
+func (d *Discovery) APIGroups() map[string]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	groups := make(map[string]string, len(d.apiGroups))
+	for g, v := range d.apiGroups {
+		groups[g] = v
+	}
+	return groups
+}
+
+// ScannedAt returns when features were last detected, or the zero time
+// before the first successful scan.
+func (d *Discovery) ScannedAt() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.scannedAt
+}
+
 // IsReady returns true after the initial CRD scan has completed.
 func (d *Discovery) IsReady() bool {
 	d.mu.RLock()
@@ -83,6 +130,15 @@ func (d *Discovery) GetProviders() []ProviderInfo {
 		{Name: "Kuma", APIGroup: "kuma.io", Detected: d.features.HasKuma},
 		{Name: "Flannel", APIGroup: "", Detected: d.features.HasFlannel},
 		{Name: "kgateway", APIGroup: "kgateway.dev", Detected: d.features.HasKgateway},
+		{Name: "GKE Gateway", APIGroup: "networking.gke.io", Detected: d.features.HasGKEGateway},
+		{Name: "AWS VPC Lattice", APIGroup: "application-networking.k8s.aws", Detected: d.features.HasVPCLattice},
+		{Name: "AWS App Mesh", APIGroup: "appmesh.k8s.aws", Detected: d.features.HasAppMesh},
+		{Name: "Argo CD", APIGroup: "argoproj.io", Detected: d.features.HasArgoCD},
+		{Name: "Flux", APIGroup: "toolkit.fluxcd.io", Detected: d.features.HasFlux},
+		{Name: "MetalLB", APIGroup: "metallb.io", Detected: d.features.HasMetalLB},
+		{Name: "NodeLocal DNSCache", APIGroup: "", Detected: d.features.HasNodeLocalDNS},
+		{Name: "ingress-nginx", APIGroup: "", Detected: d.features.HasIngressNginx},
+		{Name: "external-dns", APIGroup: "", Detected: d.features.HasExternalDNS},
 	}
 
 	for i := range providers {
@@ -99,7 +155,7 @@ func (d *Discovery) Start(ctx context.Context) {
 	ctx, d.cancel = context.WithCancel(ctx)
 
 	// Initial scan via ServerGroups (fast)
-	d.initialScan()
+	d.initialScan(ctx)
 
 	d.mu.Lock()
 	d.ready = true
@@ -108,6 +164,7 @@ func (d *Discovery) Start(ctx context.Context) {
 
 	// Start CRD watch in background
 	go d.watchLoop(ctx)
+	go d.workloadLoop(ctx)
 }
 
 func (d *Discovery) Stop() {
@@ -117,7 +174,7 @@ func (d *Discovery) Stop() {
 }
 
 // initialScan uses the discovery client for fast initial detection.
-func (d *Discovery) initialScan() {
+func (d *Discovery) initialScan(ctx context.Context) {
 	groups, err := d.discoveryClient.ServerGroups()
 	if err != nil {
 		slog.Error("discovery: failed to fetch server groups", "error", err)
@@ -126,15 +183,20 @@ func (d *Discovery) initialScan() {
 
 	newFeatures := Features{}
 	versions := make(map[string]string)
+	apiGroups := make(map[string]string)
 
 	for _, group := range groups.Groups {
 		d.detectGroup(group.Name, group.PreferredVersion.Version, &newFeatures, versions)
+		apiGroups[group.Name] = group.PreferredVersion.Version
 	}
+	d.detectWorkloads(ctx, &newFeatures)
 
 	d.mu.Lock()
-	changed := newFeatures != d.features
+	changed := newFeatures != d.features || !sameGroups(apiGroups, d.apiGroups)
 	d.features = newFeatures
 	d.providerVersions = versions
+	d.apiGroups = apiGroups
+	d.scannedAt = time.Now()
 	d.mu.Unlock()
 
 	if changed && d.onChange != nil {
@@ -209,39 +271,60 @@ func (d *Discovery) processEvents(ctx context.Context, watcher watch.Interface)
 			slog.Debug("discovery: CRD event", "type", event.Type, "group", group)
 
 			// Rescan all CRDs to recompute features
-			d.rescanCRDs(ctx)
+			_, _ = d.rescanCRDs(ctx)
 		}
 	}
 }
 
-// rescanCRDs lists all CRDs and recomputes the features set.
-func (d *Discovery) rescanCRDs(ctx context.Context) {
+// Refresh rescans CRDs and workloads now rather than on the next CRD event
+// or workload rescan, and reports whether the features changed. Changes
+// reach the onChange callback before Refresh returns.
+func (d *Discovery) Refresh(ctx context.Context) (bool, error) {
+	return d.rescanCRDs(ctx)
+}
+
+// rescanCRDs lists all CRDs and recomputes the features set, reporting
+// whether it changed.
+func (d *Discovery) rescanCRDs(ctx context.Context) (bool, error) {
 	crdList, err := d.dynamicClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		slog.Error("discovery: failed to list CRDs", "error", err)
-		return
+		return false, err
 	}
 
 	newFeatures := Features{}
 	versions := make(map[string]string)
+	apiGroups := make(map[string]string)
 
 	for _, item := range crdList.Items {
 		group, _, _ := unstructured.NestedString(item.Object, "spec", "group")
 		version := extractPreferredVersion(&item)
 		if group != "" {
 			d.detectGroup(group, version, &newFeatures, versions)
+			apiGroups[group] = version
+		}
+		if group == "gateway.networking.k8s.io" {
+			kind, _, _ := unstructured.NestedString(item.Object, "spec", "names", "kind")
+			detectGatewayKind(kind, &newFeatures)
 		}
 	}
+	d.detectWorkloads(ctx, &newFeatures)
 
 	d.mu.Lock()
-	changed := newFeatures != d.features
+	changed := newFeatures != d.features || !sameGroups(apiGroups, d.apiGroups)
 	d.features = newFeatures
 	d.providerVersions = versions
+	d.apiGroups = apiGroups
+	d.scannedAt = time.Now()
 	d.mu.Unlock()
 
 	if changed && d.onChange != nil {
 		slog.Info("discovery: features changed",
 			"gatewayAPI", newFeatures.HasGatewayAPI,
+			"tcpRoute", newFeatures.HasTCPRoute,
+			"tlsRoute", newFeatures.HasTLSRoute,
+			"udpRoute", newFeatures.HasUDPRoute,
+			"backendTLSPolicy", newFeatures.HasBackendTLSPolicy,
 			"istio", newFeatures.HasIstio,
 			"cilium", newFeatures.HasCilium,
 			"calico", newFeatures.HasCalico,
@@ -249,9 +332,116 @@ func (d *Discovery) rescanCRDs(ctx context.Context) {
 			"kuma", newFeatures.HasKuma,
 			"flannel", newFeatures.HasFlannel,
 			"kgateway", newFeatures.HasKgateway,
+			"gkeGateway", newFeatures.HasGKEGateway,
+			"vpcLattice", newFeatures.HasVPCLattice,
+			"appMesh", newFeatures.HasAppMesh,
+			"argoCD", newFeatures.HasArgoCD,
+			"flux", newFeatures.HasFlux,
+			"metalLB", newFeatures.HasMetalLB,
+			"nodeLocalDNS", newFeatures.HasNodeLocalDNS,
+			"ingressNginx", newFeatures.HasIngressNginx,
+			"externalDNS", newFeatures.HasExternalDNS,
 		)
 		d.onChange(newFeatures)
 	}
+	return changed, nil
+}
+
+var (
+	daemonsetsGVR     = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	deploymentsGVR    = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	ingressClassesGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingressclasses"}
+)
+
+// nodeLocalDNSSelector matches the DaemonSet of the upstream NodeLocal DNSCache addon.
+const nodeLocalDNSSelector = "k8s-app=node-local-dns"
+
+// flannelSelector matches the kube-flannel DaemonSet of the upstream
+// manifest and helm chart.
+const flannelSelector = "app=flannel"
+
+// externalDNSSelector matches the Deployment of the external-dns helm chart.
+const externalDNSSelector = "app.kubernetes.io/name=external-dns"
+
+// ingressNginxController is the spec.controller of ingress-nginx IngressClasses.
+const ingressNginxController = "k8s.io/ingress-nginx"
+
+// workloadRescanInterval is how often features detected from workloads are
+// re-checked, since installing a DaemonSet produces no CRD event.
+const workloadRescanInterval = 5 * time.Minute
+
+// detectWorkloads sets the features detected from running workloads rather
+// than CRDs. On error the previously detected values are kept.
+func (d *Discovery) detectWorkloads(ctx context.Context, features *Features) {
+	d.mu.RLock()
+	previous := d.features
+	d.mu.RUnlock()
+
+	list, err := d.dynamicClient.Resource(daemonsetsGVR).List(ctx, metav1.ListOptions{LabelSelector: nodeLocalDNSSelector, Limit: 1})
+	if err != nil {
+		slog.Debug("discovery: failed to list node-local-dns DaemonSets", "error", err)
+		features.HasNodeLocalDNS = previous.HasNodeLocalDNS
+	} else {
+		features.HasNodeLocalDNS = len(list.Items) > 0
+	}
+
+	flannel, err := d.dynamicClient.Resource(daemonsetsGVR).List(ctx, metav1.ListOptions{LabelSelector: flannelSelector, Limit: 1})
+	if err != nil {
+		slog.Debug("discovery: failed to list flannel DaemonSets", "error", err)
+		features.HasFlannel = previous.HasFlannel
+	} else {
+		features.HasFlannel = len(flannel.Items) > 0
+	}
+
+	deployments, err := d.dynamicClient.Resource(deploymentsGVR).List(ctx, metav1.ListOptions{LabelSelector: externalDNSSelector, Limit: 1})
+	if err != nil {
+		slog.Debug("discovery: failed to list external-dns Deployments", "error", err)
+		features.HasExternalDNS = previous.HasExternalDNS
+	} else {
+		features.HasExternalDNS = len(deployments.Items) > 0
+	}
+
+	classes, err := d.dynamicClient.Resource(ingressClassesGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Debug("discovery: failed to list IngressClasses", "error", err)
+		features.HasIngressNginx = previous.HasIngressNginx
+		return
+	}
+	features.HasIngressNginx = false
+	for _, c := range classes.Items {
+		if controller, _, _ := unstructured.NestedString(c.Object, "spec", "controller"); controller == ingressNginxController {
+			features.HasIngressNginx = true
+			break
+		}
+	}
+}
+
+// workloadLoop periodically re-runs workload detection.
+func (d *Discovery) workloadLoop(ctx context.Context) {
+	ticker := time.NewTicker(workloadRescanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.mu.RLock()
+			newFeatures := d.features
+			d.mu.RUnlock()
+
+			d.detectWorkloads(ctx, &newFeatures)
+
+			d.mu.Lock()
+			changed := newFeatures != d.features
+			d.features = newFeatures
+			d.mu.Unlock()
+
+			if changed && d.onChange != nil {
+				slog.Info("discovery: workload features changed", "flannel", newFeatures.HasFlannel, "nodeLocalDNS", newFeatures.HasNodeLocalDNS, "ingressNginx", newFeatures.HasIngressNginx, "externalDNS", newFeatures.HasExternalDNS)
+				d.onChange(newFeatures)
+			}
+		}
+	}
 }
 
 // detectGroup maps a CRD API group to the corresponding feature flag.
@@ -278,7 +468,53 @@ func (d *Discovery) detectGroup(group, version string, features *Features, versi
 	case group == "kgateway.dev" || strings.HasSuffix(group, ".kgateway.dev"):
 		features.HasKgateway = true
 		versions["kgateway.dev"] = version
+	case group == "networking.gke.io":
+		features.HasGKEGateway = true
+		versions[group] = version
+	case group == "application-networking.k8s.aws":
+		features.HasVPCLattice = true
+		versions[group] = version
+	case group == "appmesh.k8s.aws":
+		features.HasAppMesh = true
+		versions[group] = version
+	case group == "argoproj.io":
+		features.HasArgoCD = true
+		versions[group] = version
+	case strings.HasSuffix(group, ".toolkit.fluxcd.io"):
+		features.HasFlux = true
+		versions["toolkit.fluxcd.io"] = version
+	case group == "metallb.io":
+		features.HasMetalLB = true
+		versions[group] = version
+	}
+}
+
+// detectGatewayKind sets the flags of the Gateway API kinds that are
+// installed separately from the standard channel routes.
+func detectGatewayKind(kind string, features *Features) {
+	switch kind {
+	case "TCPRoute":
+		features.HasTCPRoute = true
+	case "TLSRoute":
+		features.HasTLSRoute = true
+	case "UDPRoute":
+		features.HasUDPRoute = true
+	case "BackendTLSPolicy":
+		features.HasBackendTLSPolicy = true
+	}
+}
+
+// sameGroups reports whether two group sets contain the same group names.
+func sameGroups(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for g := range a {
+		if _, ok := b[g]; !ok {
+			return false
+		}
 	}
+	return true
 }
 
 // extractPreferredVersion gets the preferred served version from a CRD object.
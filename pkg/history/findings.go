@@ -0,0 +1,403 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxFindingRuns bounds the tool calls kept whatever the retention.
+const maxFindingRuns = 100000
+
+// findingRunsBucket holds the runs of a finding database, keyed by time.
+var findingRunsBucket = []byte("runs")
+
+// FindingRecord is one finding emitted by a tool call.
+type FindingRecord struct {
+	Code      string `json:"code,omitempty"`
+	Severity  string `json:"severity"`
+	Category  string `json:"category,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Summary   string `json:"summary"`
+}
+
+// Key identifies the same finding across tool calls: its code and resource,
+// or its summary when it has no code.
+func (r FindingRecord) Key() string {
+	id := r.Code
+	if id == "" {
+		id = r.Summary
+	}
+	return id + "|" + r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// FindingRun is one tool call and the findings it emitted. Runs without
+// findings are kept too: they tell that earlier findings were resolved.
+type FindingRun struct {
+	Time     time.Time       `json:"time"`
+	Tool     string          `json:"tool"`
+	Scope    FindingScope    `json:"scope,omitzero"`
+	Findings []FindingRecord `json:"findings,omitempty"`
+}
+
+// FindingScope is what a tool call inspected: its namespace argument, empty
+// for all namespaces, and its other arguments that narrow the call, as
+// sorted key=value pairs.
+type FindingScope struct {
+	Namespace string `json:"namespace,omitempty"`
+	Args      string `json:"args,omitempty"`
+}
+
+// Covers reports whether a call of scope s inspected rec again, when rec was
+// last reported by a call of scope seen: the other arguments must be the
+// same, and s must span all namespaces or the namespace of seen or rec.
+func (s FindingScope) Covers(seen FindingScope, rec FindingRecord) bool {
+	if s.Args != seen.Args {
+		return false
+	}
+	return s.Namespace == "" || s.Namespace == seen.Namespace || s.Namespace == rec.Namespace
+}
+
+// FindingStore keeps the tool calls of the retention period, in memory or,
+// when it has a directory, in an embedded bbolt database there, so that the
+// history survives restarts and is read back one run at a time.
+type FindingStore struct {
+	retention time.Duration
+	db        *bolt.DB // nil = runs are kept in memory
+
+	mu    sync.RWMutex
+	runs  []*FindingRun // oldest first; without a database only
+	count int           // runs in the database
+}
+
+// NewFindingStore creates a store keeping runs for retention. When dir is
+// set, runs are kept in dir/findings.db, and those already there are kept.
+func NewFindingStore(retention time.Duration, dir string) (*FindingStore, error) {
+	s := &FindingStore{retention: retention}
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create finding history directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, "findings.db")
+	db, err := bolt.Open(path, 0o640, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open finding history %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(findingRunsBucket)
+		if err != nil {
+			return err
+		}
+		s.count = b.Stats().KeyN
+		return s.pruneBucket(b, time.Now())
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open finding history %s: %w", path, err)
+	}
+	s.db = db
+	return s, nil
+}
+
+// Close closes the database of the store, if any.
+func (s *FindingStore) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Add stores run and drops the runs that left the retention period.
+func (s *FindingStore) Add(run *FindingRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		i := sort.Search(len(s.runs), func(i int) bool { return run.Time.Before(s.runs[i].Time) })
+		s.runs = append(s.runs, nil)
+		copy(s.runs[i+1:], s.runs[i:])
+		s.runs[i] = run
+		s.prune(run.Time)
+		return nil
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(findingRunsBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := b.Put(findingRunKey(run.Time, seq), data); err != nil {
+			return err
+		}
+		s.count++
+		return s.pruneBucket(b, run.Time)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write finding history: %w", err)
+	}
+	return nil
+}
+
+// prune drops the runs older than the retention, and the oldest runs past
+// maxFindingRuns. Callers must hold s.mu.
+func (s *FindingStore) prune(now time.Time) {
+	cutoff := now.Add(-s.retention)
+	drop := 0
+	for drop < len(s.runs) && (s.runs[drop].Time.Before(cutoff) || len(s.runs)-drop > maxFindingRuns) {
+		drop++
+	}
+	s.runs = s.runs[drop:]
+}
+
+// pruneBucket is prune for the runs of a database. Callers must hold s.mu,
+// or own s.
+func (s *FindingStore) pruneBucket(b *bolt.Bucket, now time.Time) error {
+	cutoff := findingRunKey(now.Add(-s.retention), 0)
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && (bytes.Compare(k, cutoff) < 0 || s.count > maxFindingRuns); k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		s.count--
+	}
+	return nil
+}
+
+// findingRunKey orders runs by time; seq tells apart runs of the same time.
+func findingRunKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
+
+// Scan calls fn with each run at or after since, oldest first, until fn
+// returns false. Runs are read from the database one at a time; fn must
+// not add runs.
+func (s *FindingStore) Scan(since time.Time, fn func(*FindingRun) bool) error {
+	if s.db == nil {
+		s.mu.RLock()
+		i := sort.Search(len(s.runs), func(i int) bool { return !s.runs[i].Time.Before(since) })
+		runs := append([]*FindingRun(nil), s.runs[i:]...)
+		s.mu.RUnlock()
+		for _, run := range runs {
+			if !fn(run) {
+				break
+			}
+		}
+		return nil
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(findingRunsBucket).Cursor()
+		var start []byte
+		if !since.IsZero() {
+			start = findingRunKey(since, 0)
+		}
+		skipped := 0
+		for k, v := c.Seek(start); k != nil; k, v = c.Next() {
+			var run FindingRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				skipped++
+				continue
+			}
+			if !fn(&run) {
+				break
+			}
+		}
+		if skipped > 0 {
+			slog.Warn("history: skipping unreadable finding runs", "runs", skipped)
+		}
+		return nil
+	})
+}
+
+// FindingSummary is the history of one finding.
+type FindingSummary struct {
+	// FindingRecord is the latest observation.
+	FindingRecord
+	Tools        []string
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	Observations int
+	// Open is false when a run of one of Tools after LastSeen, covering the
+	// finding's scope, no longer emitted the finding.
+	Open bool
+	// ResolvedAt is the first such run.
+	ResolvedAt time.Time
+
+	// scopes holds, per tool, the scope of the last run that reported it.
+	scopes map[string]FindingScope
+}
+
+// Summarize returns the history of every finding in runs, sorted by first
+// sighting, oldest first.
+func Summarize(runs []*FindingRun) []*FindingSummary {
+	z := NewSummarizer()
+	for _, run := range runs {
+		z.Add(run)
+	}
+	return z.Summaries()
+}
+
+// Summarizer builds the history of every finding from runs added oldest
+// first, keeping one summary per finding rather than the runs.
+type Summarizer struct {
+	byKey  map[string]*FindingSummary
+	byTool map[string][]*FindingSummary
+	out    []*FindingSummary
+}
+
+// NewSummarizer returns an empty Summarizer.
+func NewSummarizer() *Summarizer {
+	return &Summarizer{byKey: make(map[string]*FindingSummary), byTool: make(map[string][]*FindingSummary)}
+}
+
+// Add records run, which must not be older than the runs added before.
+func (z *Summarizer) Add(run *FindingRun) {
+	for _, rec := range run.Findings {
+		fs, ok := z.byKey[rec.Key()]
+		if !ok {
+			fs = &FindingSummary{FirstSeen: run.Time, scopes: make(map[string]FindingScope)}
+			z.byKey[rec.Key()] = fs
+			z.out = append(z.out, fs)
+		}
+		fs.FindingRecord = rec
+		fs.LastSeen = run.Time
+		fs.Observations++
+		fs.Open, fs.ResolvedAt = true, time.Time{}
+		fs.scopes[run.Tool] = run.Scope
+		if !containsTool(fs.Tools, run.Tool) {
+			fs.Tools = append(fs.Tools, run.Tool)
+			z.byTool[run.Tool] = append(z.byTool[run.Tool], fs)
+		}
+	}
+	// A later run of a tool that reported a finding resolves it, when the
+	// run inspected the finding's scope again
+	for _, fs := range z.byTool[run.Tool] {
+		if fs.Open && run.Time.After(fs.LastSeen) && run.Scope.Covers(fs.scopes[run.Tool], fs.FindingRecord) {
+			fs.Open, fs.ResolvedAt = false, run.Time
+		}
+	}
+}
+
+// Summaries returns the history of every finding added, sorted by first
+// sighting, oldest first.
+func (z *Summarizer) Summaries() []*FindingSummary {
+	return z.out
+}
+
+func containsTool(tools []string, tool string) bool {
+	for _, t := range tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// TrendBucket counts the distinct findings seen in one interval.
+type TrendBucket struct {
+	Start      time.Time
+	BySeverity map[string]int
+	// New counts the findings first seen in this interval.
+	New int
+}
+
+// Total returns the number of distinct findings in the bucket.
+func (b TrendBucket) Total() int {
+	n := 0
+	for _, c := range b.BySeverity {
+		n += c
+	}
+	return n
+}
+
+// Trends buckets runs by interval from since to now. A finding seen several
+// times in one interval counts once, with its latest severity there; New is
+// relative to the whole of runs, so runs before since tell which findings
+// already existed.
+func Trends(runs []*FindingRun, since, now time.Time, interval time.Duration) []TrendBucket {
+	tc := NewTrendCounter(since, now, interval)
+	for _, run := range runs {
+		tc.Add(run)
+	}
+	return tc.Buckets()
+}
+
+// TrendCounter buckets runs added one at a time; see Trends.
+type TrendCounter struct {
+	since    time.Time
+	interval time.Duration
+	buckets  []TrendBucket
+	seen     map[string]bool
+	latest   []map[string]string
+}
+
+// NewTrendCounter returns a TrendCounter of the intervals from since to now.
+func NewTrendCounter(since, now time.Time, interval time.Duration) *TrendCounter {
+	tc := &TrendCounter{since: since, interval: interval, seen: make(map[string]bool)}
+	for t := since; t.Before(now); t = t.Add(interval) {
+		tc.buckets = append(tc.buckets, TrendBucket{Start: t, BySeverity: make(map[string]int)})
+	}
+	tc.latest = make([]map[string]string, len(tc.buckets))
+	return tc
+}
+
+// Add counts run, which must not be older than the runs added before.
+func (tc *TrendCounter) Add(run *FindingRun) {
+	i := -1
+	if !run.Time.Before(tc.since) {
+		i = int(run.Time.Sub(tc.since) / tc.interval)
+	}
+	for _, rec := range run.Findings {
+		key := rec.Key()
+		if i >= 0 && i < len(tc.buckets) {
+			if tc.latest[i] == nil {
+				tc.latest[i] = make(map[string]string)
+			}
+			tc.latest[i][key] = rec.Severity
+			if !tc.seen[key] {
+				tc.buckets[i].New++
+			}
+		}
+		tc.seen[key] = true
+	}
+}
+
+// Buckets returns the counts of the runs added.
+func (tc *TrendCounter) Buckets() []TrendBucket {
+	for i, m := range tc.latest {
+		for k := range tc.buckets[i].BySeverity {
+			delete(tc.buckets[i].BySeverity, k)
+		}
+		for _, sev := range m {
+			tc.buckets[i].BySeverity[sev]++
+		}
+	}
+	return tc.buckets
+}
+
+// MatchesResource reports whether the record is about a resource matching
+// kind, namespace and name; empty values match anything.
+func (r FindingRecord) MatchesResource(kind, namespace, name string) bool {
+	return (kind == "" || strings.EqualFold(r.Kind, kind)) &&
+		(namespace == "" || r.Namespace == namespace) &&
+		(name == "" || r.Name == name)
+}
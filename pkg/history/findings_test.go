@@ -0,0 +1,184 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func findingRun(at time.Time, tool string, recs ...FindingRecord) *FindingRun {
+	return &FindingRun{Time: at, Tool: tool, Findings: recs}
+}
+
+// scanRuns returns the runs of store at or after since.
+func scanRuns(t *testing.T, store *FindingStore, since time.Time) []*FindingRun {
+	t.Helper()
+	var runs []*FindingRun
+	if err := store.Scan(since, func(run *FindingRun) bool {
+		runs = append(runs, run)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return runs
+}
+
+func TestFindingStorePersistence(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	store, err := NewFindingStore(24*time.Hour, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := FindingRecord{Code: "GW003", Severity: "warning", Kind: "HTTPRoute", Namespace: "shop", Name: "web", Summary: "backend missing"}
+	for _, run := range []*FindingRun{
+		findingRun(now.Add(-48*time.Hour), "scan_gateway_misconfigs", rec),
+		findingRun(now.Add(-time.Hour), "scan_gateway_misconfigs", rec),
+		findingRun(now, "scan_gateway_misconfigs"),
+	} {
+		if err := store.Add(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewFindingStore(24*time.Hour, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reloaded.Close() }()
+	runs := scanRuns(t, reloaded, time.Time{})
+	if len(runs) != 2 || len(runs[0].Findings) != 1 || runs[0].Findings[0] != rec || len(runs[1].Findings) != 0 {
+		t.Fatalf("reloaded runs = %+v, want the 2 runs within the retention", runs)
+	}
+	if got := scanRuns(t, reloaded, now.Add(-time.Minute)); len(got) != 1 {
+		t.Errorf("Scan(since) = %d runs, want 1", len(got))
+	}
+
+	// Scan stops when fn returns false.
+	n := 0
+	_ = reloaded.Scan(time.Time{}, func(*FindingRun) bool { n++; return false })
+	if n != 1 {
+		t.Errorf("Scan() went on for %d runs after fn returned false", n)
+	}
+}
+
+func TestFindingStoreOrder(t *testing.T) {
+	now := time.Now()
+	for _, dir := range []string{"", t.TempDir()} {
+		store, err := NewFindingStore(time.Hour, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Runs of the same time are all kept, and late runs are put in order.
+		for _, at := range []time.Duration{-time.Minute, -time.Minute, -3 * time.Minute, 0} {
+			if err := store.Add(findingRun(now.Add(at), "quick_scan")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		runs := scanRuns(t, store, time.Time{})
+		if len(runs) != 4 || !runs[0].Time.Equal(now.Add(-3*time.Minute)) || !runs[3].Time.Equal(now) {
+			t.Errorf("dir %q: runs = %+v, want 4 runs oldest first", dir, runs)
+		}
+		_ = store.Close()
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	fixed := FindingRecord{Code: "DNS001", Severity: "critical", Kind: "Service", Namespace: "kube-system", Name: "kube-dns", Summary: "no endpoints"}
+	open := FindingRecord{Code: "NP002", Severity: "warning", Kind: "Namespace", Name: "shop", Summary: "no policy"}
+	runs := []*FindingRun{
+		findingRun(now.Add(-3*time.Hour), "check_dns_resolution", fixed),
+		findingRun(now.Add(-2*time.Hour), "check_dns_resolution", fixed),
+		findingRun(now.Add(-2*time.Hour), "analyze_policy_coverage", open),
+		findingRun(now.Add(-time.Hour), "check_dns_resolution"),
+		findingRun(now, "quick_scan"),
+	}
+	summaries := Summarize(runs)
+	if len(summaries) != 2 {
+		t.Fatalf("Summarize() = %+v, want 2 findings", summaries)
+	}
+	dns, np := summaries[0], summaries[1]
+	if dns.Code != "DNS001" || dns.Open || dns.Observations != 2 || !dns.ResolvedAt.Equal(runs[3].Time) || !dns.FirstSeen.Equal(runs[0].Time) {
+		t.Errorf("DNS001 summary = %+v, want resolved after 2 observations", dns)
+	}
+	// quick_scan never reported NP002, so its empty run does not resolve it
+	if np.Code != "NP002" || !np.Open {
+		t.Errorf("NP002 summary = %+v, want open", np)
+	}
+}
+
+func TestSummarizeReopened(t *testing.T) {
+	now := time.Now()
+	rec := FindingRecord{Code: "GW003", Severity: "warning", Name: "web"}
+	summaries := Summarize([]*FindingRun{
+		findingRun(now.Add(-3*time.Hour), "quick_scan", rec),
+		findingRun(now.Add(-2*time.Hour), "quick_scan"),
+		findingRun(now.Add(-time.Hour), "quick_scan", rec),
+	})
+	if len(summaries) != 1 || !summaries[0].Open || !summaries[0].ResolvedAt.IsZero() || summaries[0].Observations != 2 {
+		t.Errorf("Summarize() = %+v, want GW003 open again", summaries[0])
+	}
+}
+
+func TestSummarizeScopedRuns(t *testing.T) {
+	now := time.Now()
+	scoped := func(at time.Time, ns, args string, recs ...FindingRecord) *FindingRun {
+		run := findingRun(at, "scan_gateway_misconfigs", recs...)
+		run.Scope = FindingScope{Namespace: ns, Args: args}
+		return run
+	}
+	inA := FindingRecord{Code: "GW003", Severity: "warning", Kind: "HTTPRoute", Namespace: "a", Name: "web"}
+	inB := FindingRecord{Code: "GW003", Severity: "warning", Kind: "HTTPRoute", Namespace: "b", Name: "web"}
+	summaries := Summarize([]*FindingRun{
+		scoped(now.Add(-4*time.Hour), "a", "", inA),
+		scoped(now.Add(-4*time.Hour), "b", "", inB),
+		// A run in namespace a, or with other arguments, leaves b alone
+		scoped(now.Add(-3*time.Hour), "a", ""),
+		scoped(now.Add(-2*time.Hour), "", "route_name=api"),
+	})
+	a, b := summaries[0], summaries[1]
+	if a.Open || !a.ResolvedAt.Equal(now.Add(-3*time.Hour)) {
+		t.Errorf("namespace a finding = %+v, want resolved by the run in a", a)
+	}
+	if !b.Open {
+		t.Errorf("namespace b finding = %+v, want open", b)
+	}
+
+	// A run over all namespaces covers both
+	summaries = Summarize([]*FindingRun{
+		scoped(now.Add(-2*time.Hour), "b", "", inB),
+		scoped(now.Add(-time.Hour), "", ""),
+	})
+	if summaries[0].Open {
+		t.Errorf("namespace b finding = %+v, want resolved by the cluster-wide run", summaries[0])
+	}
+}
+
+func TestTrends(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	a := FindingRecord{Code: "GW003", Severity: "warning", Name: "a"}
+	b := FindingRecord{Code: "GW001", Severity: "critical", Name: "b"}
+	runs := []*FindingRun{
+		findingRun(since.Add(-time.Hour), "quick_scan", a),
+		findingRun(since.Add(time.Hour), "quick_scan", a),
+		findingRun(since.Add(2*time.Hour), "quick_scan", a),
+		findingRun(since.Add(25*time.Hour), "quick_scan", a, b),
+	}
+	buckets := Trends(runs, since, since.Add(72*time.Hour), 24*time.Hour)
+	if len(buckets) != 3 {
+		t.Fatalf("Trends() = %d buckets, want 3", len(buckets))
+	}
+	if buckets[0].BySeverity["warning"] != 1 || buckets[0].New != 0 {
+		t.Errorf("bucket 0 = %+v, want 1 warning already seen before since", buckets[0])
+	}
+	if buckets[1].Total() != 2 || buckets[1].BySeverity["critical"] != 1 || buckets[1].New != 1 {
+		t.Errorf("bucket 1 = %+v, want 1 critical and 1 warning, 1 new", buckets[1])
+	}
+	if buckets[2].Total() != 0 {
+		t.Errorf("bucket 2 = %+v, want empty", buckets[2])
+	}
+}
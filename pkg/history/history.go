@@ -0,0 +1,246 @@
+// Package history keeps periodic snapshots of the cluster's networking
+// configuration so that changes can be reviewed after the fact, e.g. what
+// changed in the hour before an outage.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Object is the recorded state of one resource.
+type Object struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Hash identifies the recorded content; equal hashes mean no change.
+	Hash    string      `json:"hash"`
+	Content interface{} `json:"content,omitempty"`
+}
+
+// Key identifies an object within a snapshot.
+func (o *Object) Key() string { return o.Kind + "/" + o.Namespace + "/" + o.Name }
+
+// Snapshot is the networking configuration at one point in time.
+type Snapshot struct {
+	ID   int       `json:"id"`
+	Time time.Time `json:"time"`
+	// Since is the last capture that still matched the previous snapshot:
+	// the changes in this snapshot happened between Since and Time.
+	Since   time.Time          `json:"since"`
+	Objects map[string]*Object `json:"objects"`
+}
+
+// Change types reported by Diff.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// Change is one object that differs between two snapshots. Old is nil for
+// added objects and New for removed ones.
+type Change struct {
+	Type string
+	Old  *Object
+	New  *Object
+}
+
+// Object returns the most recent known state of the changed object.
+func (c Change) Object() *Object {
+	if c.New != nil {
+		return c.New
+	}
+	return c.Old
+}
+
+// Diff returns the objects added, removed or modified between from and to,
+// sorted by kind, namespace and name.
+func Diff(from, to *Snapshot) []Change {
+	var changes []Change
+	for key, o := range to.Objects {
+		prev, ok := from.Objects[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Type: Added, New: o})
+		case prev.Hash != o.Hash:
+			changes = append(changes, Change{Type: Modified, Old: prev, New: o})
+		}
+	}
+	for key, o := range from.Objects {
+		if _, ok := to.Objects[key]; !ok {
+			changes = append(changes, Change{Type: Removed, Old: o})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Object().Key() < changes[j].Object().Key()
+	})
+	return changes
+}
+
+// Store holds the most recent snapshots, in memory and optionally in a
+// directory (e.g. a PersistentVolume) so that history survives restarts.
+// Captures identical to the latest snapshot are not stored.
+type Store struct {
+	size int
+	dir  string
+
+	mu        sync.RWMutex
+	snapshots []*Snapshot // oldest first
+	nextID    int
+	lastCheck time.Time
+}
+
+// NewStore creates a store keeping size snapshots. When dir is set, snapshots
+// already in it are loaded and new ones are written to it.
+func NewStore(size int, dir string) (*Store, error) {
+	s := &Store{size: size, dir: dir, nextID: 1}
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create history directory %s: %w", dir, err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "snapshot-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", f, err)
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			slog.Warn("history: skipping unreadable snapshot", "file", f, "error", err)
+			continue
+		}
+		s.snapshots = append(s.snapshots, &snap)
+		if snap.ID >= s.nextID {
+			s.nextID = snap.ID + 1
+		}
+	}
+	s.prune()
+	if n := len(s.snapshots); n > 0 {
+		s.lastCheck = s.snapshots[n-1].Time
+	}
+	return s, nil
+}
+
+// Add stores snap unless it is identical to the latest snapshot, and reports
+// whether it was stored.
+func (s *Store) Add(snap *Snapshot) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.snapshots); n > 0 {
+		prev := s.snapshots[n-1]
+		if len(Diff(prev, snap)) == 0 {
+			s.lastCheck = snap.Time
+			return false, nil
+		}
+		// Share unchanged objects with the previous snapshot.
+		for key, o := range snap.Objects {
+			if p, ok := prev.Objects[key]; ok && p.Hash == o.Hash {
+				snap.Objects[key] = p
+			}
+		}
+	}
+	snap.ID = s.nextID
+	snap.Since = s.lastCheck
+	s.nextID++
+	s.lastCheck = snap.Time
+	s.snapshots = append(s.snapshots, snap)
+	s.prune()
+
+	if s.dir == "" {
+		return true, nil
+	}
+	return true, s.write(snap)
+}
+
+// prune drops the oldest snapshots beyond the store size, and their files.
+// Callers must hold s.mu.
+func (s *Store) prune() {
+	for len(s.snapshots) > s.size {
+		if s.dir != "" {
+			_ = os.Remove(s.path(s.snapshots[0].ID))
+		}
+		s.snapshots = s.snapshots[1:]
+	}
+}
+
+func (s *Store) path(id int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("snapshot-%08d.json", id))
+}
+
+// write persists snap atomically. Callers must hold s.mu.
+func (s *Store) write(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(snap.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("failed to write snapshot %d: %w", snap.ID, err)
+	}
+	return os.Rename(tmp, s.path(snap.ID))
+}
+
+// Snapshots returns the stored snapshots, oldest first.
+func (s *Store) Snapshots() []*Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Snapshot(nil), s.snapshots...)
+}
+
+// Get returns the snapshot with the given ID.
+func (s *Store) Get(id int) (*Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, snap := range s.snapshots {
+		if snap.ID == id {
+			return snap, true
+		}
+	}
+	return nil, false
+}
+
+// At returns the configuration in effect at t: the latest snapshot taken at
+// or before t, or nil when t predates the history.
+func (s *Store) At(t time.Time) *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found *Snapshot
+	for _, snap := range s.snapshots {
+		if snap.Time.After(t) {
+			break
+		}
+		found = snap
+	}
+	return found
+}
+
+// Latest returns the most recent snapshot, or nil when none was recorded.
+func (s *Store) Latest() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.snapshots) == 0 {
+		return nil
+	}
+	return s.snapshots[len(s.snapshots)-1]
+}
+
+// LastCheck returns the time of the latest capture, stored or not.
+func (s *Store) LastCheck() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastCheck
+}
@@ -0,0 +1,131 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func snapshotOf(t time.Time, objects ...*Object) *Snapshot {
+	snap := &Snapshot{Time: t, Objects: make(map[string]*Object)}
+	for _, o := range objects {
+		snap.Objects[o.Key()] = o
+	}
+	return snap
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	from := snapshotOf(now,
+		NewObject("Service", "shop", "web", map[string]interface{}{"type": "ClusterIP"}),
+		NewObject("NetworkPolicy", "shop", "deny-all", map[string]interface{}{}),
+		NewObject("HTTPRoute", "shop", "web", map[string]interface{}{"hostnames": []interface{}{"a.example.com"}}),
+	)
+	to := snapshotOf(now.Add(time.Minute),
+		NewObject("Service", "shop", "web", map[string]interface{}{"type": "ClusterIP"}),
+		NewObject("HTTPRoute", "shop", "web", map[string]interface{}{"hostnames": []interface{}{"b.example.com"}}),
+		NewObject("Gateway", "infra", "public", map[string]interface{}{}),
+	)
+
+	changes := Diff(from, to)
+	want := []struct{ typ, key string }{
+		{Added, "Gateway/infra/public"},
+		{Modified, "HTTPRoute/shop/web"},
+		{Removed, "NetworkPolicy/shop/deny-all"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, w := range want {
+		if changes[i].Type != w.typ || changes[i].Object().Key() != w.key {
+			t.Errorf("change %d = %s %s, want %s %s", i, changes[i].Type, changes[i].Object().Key(), w.typ, w.key)
+		}
+	}
+}
+
+func TestStoreAddSkipsUnchanged(t *testing.T) {
+	store, err := NewStore(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	svc := NewObject("Service", "shop", "web", map[string]interface{}{"type": "ClusterIP"})
+
+	if stored, _ := store.Add(snapshotOf(t0, svc)); !stored {
+		t.Fatal("expected the first snapshot to be stored")
+	}
+	same := NewObject("Service", "shop", "web", map[string]interface{}{"type": "ClusterIP"})
+	if stored, _ := store.Add(snapshotOf(t0.Add(5*time.Minute), same)); stored {
+		t.Error("expected an unchanged capture not to be stored")
+	}
+	if got := store.LastCheck(); !got.Equal(t0.Add(5 * time.Minute)) {
+		t.Errorf("LastCheck() = %v, want the unchanged capture time", got)
+	}
+
+	changed := snapshotOf(t0.Add(10*time.Minute), svc,
+		NewObject("NetworkPolicy", "shop", "deny-all", map[string]interface{}{}))
+	if stored, _ := store.Add(changed); !stored {
+		t.Fatal("expected a changed capture to be stored")
+	}
+	if changed.ID != 2 {
+		t.Errorf("ID = %d, want 2", changed.ID)
+	}
+	if !changed.Since.Equal(t0.Add(5 * time.Minute)) {
+		t.Errorf("Since = %v, want the last unchanged capture", changed.Since)
+	}
+}
+
+func TestStorePrunesAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(2, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		o := NewObject("Service", "shop", "web", map[string]interface{}{"port": i})
+		if _, err := store.Add(snapshotOf(t0.Add(time.Duration(i)*time.Hour), o)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := store.Get(1); ok {
+		t.Error("expected snapshot 1 to be pruned")
+	}
+
+	reloaded, err := NewStore(2, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snaps := reloaded.Snapshots()
+	if len(snaps) != 2 || snaps[0].ID != 2 || snaps[1].ID != 3 {
+		t.Fatalf("expected snapshots 2 and 3 after reload, got %d", len(snaps))
+	}
+	if len(Diff(store.Latest(), reloaded.Latest())) != 0 {
+		t.Error("expected the reloaded snapshot to match the stored one")
+	}
+
+	// New snapshots continue the ID sequence.
+	next := snapshotOf(t0.Add(4*time.Hour), NewObject("Service", "shop", "web", map[string]interface{}{"port": 9}))
+	if _, err := reloaded.Add(next); err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != 4 {
+		t.Errorf("ID = %d, want 4", next.ID)
+	}
+}
+
+func TestStoreAt(t *testing.T) {
+	store, _ := NewStore(10, "")
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.Add(snapshotOf(t0, NewObject("Service", "shop", "web", "a")))
+	store.Add(snapshotOf(t0.Add(time.Hour), NewObject("Service", "shop", "web", "b")))
+
+	if got := store.At(t0.Add(-time.Minute)); got != nil {
+		t.Errorf("expected no snapshot before the history, got %d", got.ID)
+	}
+	if got := store.At(t0.Add(30 * time.Minute)); got == nil || got.ID != 1 {
+		t.Errorf("expected snapshot 1 to be in effect after 30m")
+	}
+	if got := store.At(t0.Add(2 * time.Hour)); got == nil || got.ID != 2 {
+		t.Errorf("expected snapshot 2 to be in effect after 2h")
+	}
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Source is one resource type recorded in snapshots. Field is the top-level
+// field holding the configuration ("spec", or "data" for ConfigMaps); status
+// and metadata are left out so that only configuration changes show up.
+type Source struct {
+	Kind string
+	// GVRs are tried in order; the first served version is recorded.
+	GVRs  []schema.GroupVersionResource
+	Field string
+	// Namespace and Name restrict the source to one object, e.g. the
+	// CoreDNS ConfigMap.
+	Namespace string
+	Name      string
+}
+
+func gvr(group, version, resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+}
+
+// DefaultSources are the networking resources recorded by default. Sources
+// whose CRDs are not installed are skipped.
+var DefaultSources = []Source{
+	{Kind: "Service", GVRs: []schema.GroupVersionResource{gvr("", "v1", "services")}, Field: "spec"},
+	{Kind: "Ingress", GVRs: []schema.GroupVersionResource{gvr("networking.k8s.io", "v1", "ingresses")}, Field: "spec"},
+	{Kind: "NetworkPolicy", GVRs: []schema.GroupVersionResource{gvr("networking.k8s.io", "v1", "networkpolicies")}, Field: "spec"},
+	{Kind: "ConfigMap", GVRs: []schema.GroupVersionResource{gvr("", "v1", "configmaps")}, Field: "data", Namespace: "kube-system", Name: "coredns"},
+	{Kind: "GatewayClass", GVRs: []schema.GroupVersionResource{gvr("gateway.networking.k8s.io", "v1", "gatewayclasses")}, Field: "spec"},
+	{Kind: "Gateway", GVRs: []schema.GroupVersionResource{gvr("gateway.networking.k8s.io", "v1", "gateways"), gvr("gateway.networking.k8s.io", "v1beta1", "gateways")}, Field: "spec"},
+	{Kind: "HTTPRoute", GVRs: []schema.GroupVersionResource{gvr("gateway.networking.k8s.io", "v1", "httproutes"), gvr("gateway.networking.k8s.io", "v1beta1", "httproutes")}, Field: "spec"},
+	{Kind: "GRPCRoute", GVRs: []schema.GroupVersionResource{gvr("gateway.networking.k8s.io", "v1", "grpcroutes"), gvr("gateway.networking.k8s.io", "v1alpha2", "grpcroutes")}, Field: "spec"},
+	{Kind: "ReferenceGrant", GVRs: []schema.GroupVersionResource{gvr("gateway.networking.k8s.io", "v1beta1", "referencegrants")}, Field: "spec"},
+	{Kind: "VirtualService", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1", "virtualservices"), gvr("networking.istio.io", "v1beta1", "virtualservices")}, Field: "spec"},
+	{Kind: "DestinationRule", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1", "destinationrules"), gvr("networking.istio.io", "v1beta1", "destinationrules")}, Field: "spec"},
+	{Kind: "IstioGateway", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1", "gateways"), gvr("networking.istio.io", "v1beta1", "gateways")}, Field: "spec"},
+	{Kind: "ServiceEntry", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1", "serviceentries"), gvr("networking.istio.io", "v1beta1", "serviceentries")}, Field: "spec"},
+	{Kind: "Sidecar", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1", "sidecars"), gvr("networking.istio.io", "v1beta1", "sidecars")}, Field: "spec"},
+	{Kind: "EnvoyFilter", GVRs: []schema.GroupVersionResource{gvr("networking.istio.io", "v1alpha3", "envoyfilters")}, Field: "spec"},
+	{Kind: "AuthorizationPolicy", GVRs: []schema.GroupVersionResource{gvr("security.istio.io", "v1", "authorizationpolicies"), gvr("security.istio.io", "v1beta1", "authorizationpolicies")}, Field: "spec"},
+	{Kind: "PeerAuthentication", GVRs: []schema.GroupVersionResource{gvr("security.istio.io", "v1", "peerauthentications"), gvr("security.istio.io", "v1beta1", "peerauthentications")}, Field: "spec"},
+	{Kind: "ClientTrafficPolicy", GVRs: []schema.GroupVersionResource{gvr("gateway.envoyproxy.io", "v1alpha1", "clienttrafficpolicies")}, Field: "spec"},
+	{Kind: "BackendTrafficPolicy", GVRs: []schema.GroupVersionResource{gvr("gateway.envoyproxy.io", "v1alpha1", "backendtrafficpolicies")}, Field: "spec"},
+	{Kind: "SecurityPolicy", GVRs: []schema.GroupVersionResource{gvr("gateway.envoyproxy.io", "v1alpha1", "securitypolicies")}, Field: "spec"},
+	{Kind: "CiliumNetworkPolicy", GVRs: []schema.GroupVersionResource{gvr("cilium.io", "v2", "ciliumnetworkpolicies")}, Field: "spec"},
+	{Kind: "CiliumClusterwideNetworkPolicy", GVRs: []schema.GroupVersionResource{gvr("cilium.io", "v2", "ciliumclusterwidenetworkpolicies")}, Field: "spec"},
+	{Kind: "CalicoNetworkPolicy", GVRs: []schema.GroupVersionResource{gvr("crd.projectcalico.org", "v1", "networkpolicies")}, Field: "spec"},
+	{Kind: "CalicoGlobalNetworkPolicy", GVRs: []schema.GroupVersionResource{gvr("crd.projectcalico.org", "v1", "globalnetworkpolicies")}, Field: "spec"},
+}
+
+// Recorder captures a snapshot of its sources every interval into a store.
+type Recorder struct {
+	client   dynamic.Interface
+	store    *Store
+	interval time.Duration
+	sources  []Source
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewRecorder creates a recorder of DefaultSources.
+func NewRecorder(client dynamic.Interface, store *Store, interval time.Duration) *Recorder {
+	return &Recorder{
+		client:   client,
+		store:    store,
+		interval: interval,
+		sources:  DefaultSources,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start captures a first snapshot and then one every interval until ctx ends
+// or Stop is called. A zero interval disables recording.
+func (r *Recorder) Start(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			r.record(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends recording.
+func (r *Recorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *Recorder) record(ctx context.Context) {
+	captureCtx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+	snap, failed := r.Capture(captureCtx)
+	// Keep the last known state of sources that could not be listed this
+	// time rather than report their objects as removed.
+	if prev := r.store.Latest(); prev != nil && len(failed) > 0 {
+		for key, o := range prev.Objects {
+			if failed[o.Kind] {
+				snap.Objects[key] = o
+			}
+		}
+	}
+	stored, err := r.store.Add(snap)
+	if err != nil {
+		slog.Warn("history: failed to persist snapshot", "error", err)
+	}
+	if stored {
+		slog.Debug("history: recorded configuration snapshot", "id", snap.ID, "objects", len(snap.Objects))
+	}
+}
+
+// Capture reads the current state of every source. Sources whose API is not
+// served are skipped; failed reports the kinds that could not be listed for
+// any other reason (e.g. a timeout).
+func (r *Recorder) Capture(ctx context.Context) (snap *Snapshot, failed map[string]bool) {
+	snap = &Snapshot{Time: time.Now().UTC(), Objects: make(map[string]*Object)}
+	failed = make(map[string]bool)
+	for _, src := range r.sources {
+		for _, gvr := range src.GVRs {
+			list, err := r.client.Resource(gvr).Namespace(src.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					slog.Debug("history: failed to list source", "kind", src.Kind, "error", err)
+					failed[src.Kind] = true
+				}
+				continue
+			}
+			delete(failed, src.Kind)
+			for _, item := range list.Items {
+				if src.Name != "" && item.GetName() != src.Name {
+					continue
+				}
+				o := NewObject(src.Kind, item.GetNamespace(), item.GetName(), item.Object[src.Field])
+				snap.Objects[o.Key()] = o
+			}
+			break
+		}
+	}
+	return snap, failed
+}
+
+// NewObject records content under a hash of its JSON encoding. Content is
+// stored as decoded JSON so that snapshots compare equally before and after
+// a round trip through the store's directory.
+func NewObject(kind, namespace, name string, content interface{}) *Object {
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	var decoded interface{}
+	_ = json.Unmarshal(data, &decoded)
+	return &Object{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Hash:      hex.EncodeToString(sum[:8]),
+		Content:   decoded,
+	}
+}
@@ -16,6 +16,8 @@ type Clients struct {
 	Dynamic   dynamic.Interface
 	Discovery discovery.DiscoveryInterface
 	Clientset kubernetes.Interface
+	// Host is the API server URL the clients talk to.
+	Host string
 }
 
 func NewClients() (*Clients, error) {
@@ -31,8 +33,31 @@ func NewClients() (*Clients, error) {
 			return nil, fmt.Errorf("failed to build k8s config: %w", err)
 		}
 	}
+	return newClientsForConfig(config)
+}
+
+// NewClientsForContext creates clients for a named context in a kubeconfig
+// file. An empty kubeconfig uses the standard loading rules ($KUBECONFIG or
+// ~/.kube/config).
+func NewClientsForContext(kubeconfig, kubeContext string) (*Clients, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build k8s config for context %q: %w", kubeContext, err)
+	}
+	return newClientsForConfig(config)
+}
 
-	// Wrap transport with OTel tracing for K8s API call spans.
+func newClientsForConfig(config *rest.Config) (*Clients, error) {
+	// Impersonate the caller carried in the request context, if any, keep
+	// calls within its namespace scope, then wrap with OTel tracing for K8s
+	// API call spans.
+	config.Wrap(newImpersonatingTransport)
+	config.Wrap(newNamespaceScopeTransport)
 	config.Wrap(newTracingTransport)
 
 	dynClient, err := dynamic.NewForConfig(config)
@@ -54,5 +79,6 @@ func NewClients() (*Clients, error) {
 		Dynamic:   dynClient,
 		Discovery: discoClient,
 		Clientset: clientset,
+		Host:      config.Host,
 	}, nil
 }
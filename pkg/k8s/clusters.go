@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Cluster is one Kubernetes cluster the server can diagnose.
+type Cluster struct {
+	Name string
+	// Context is the kubeconfig context; empty for the server's own cluster.
+	Context string
+	Clients *Clients
+}
+
+// ClusterRegistry holds every configured cluster. The default cluster serves
+// tool calls that do not name one.
+type ClusterRegistry struct {
+	mu          sync.RWMutex
+	clusters    map[string]*Cluster
+	order       []string
+	defaultName string
+}
+
+// NewClusterRegistry creates a registry whose default cluster is c.
+func NewClusterRegistry(c *Cluster) *ClusterRegistry {
+	return &ClusterRegistry{
+		clusters:    map[string]*Cluster{c.Name: c},
+		order:       []string{c.Name},
+		defaultName: c.Name,
+	}
+}
+
+// Add registers an additional cluster. Names must be unique.
+func (r *ClusterRegistry) Add(c *Cluster) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.clusters[c.Name]; dup {
+		return fmt.Errorf("cluster %q is configured more than once", c.Name)
+	}
+	r.clusters[c.Name] = c
+	r.order = append(r.order, c.Name)
+	return nil
+}
+
+// Get returns the named cluster.
+func (r *ClusterRegistry) Get(name string) (*Cluster, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clusters[name]
+	return c, ok
+}
+
+// Default returns the cluster used when a request does not select one.
+func (r *ClusterRegistry) Default() *Cluster {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clusters[r.defaultName]
+}
+
+// List returns clusters in configuration order, default first.
+func (r *ClusterRegistry) List() []*Cluster {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*Cluster, 0, len(r.order))
+	for _, name := range r.order {
+		out = append(out, r.clusters[name])
+	}
+	return out
+}
+
+// Names returns cluster names in configuration order.
+func (r *ClusterRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
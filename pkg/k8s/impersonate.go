@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/transport"
+)
+
+// Impersonation is the Kubernetes identity an API call is made as.
+type Impersonation struct {
+	User   string
+	Groups []string
+}
+
+// Key identifies the impersonated identity, e.g. for per-identity caches.
+// It is empty when no impersonation applies.
+func (i Impersonation) Key() string {
+	if i.User == "" {
+		return ""
+	}
+	return i.User + "|" + strings.Join(i.Groups, ",")
+}
+
+type impersonationKey struct{}
+
+// WithImpersonation returns a context whose API calls, through any Clients,
+// are made as the given identity. An empty user leaves ctx unchanged.
+func WithImpersonation(ctx context.Context, imp Impersonation) context.Context {
+	if imp.User == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, impersonationKey{}, imp)
+}
+
+// ImpersonationFrom returns the identity set by WithImpersonation.
+func ImpersonationFrom(ctx context.Context) (Impersonation, bool) {
+	imp, ok := ctx.Value(impersonationKey{}).(Impersonation)
+	return imp, ok
+}
+
+// impersonatingRoundTripper adds Impersonate-* headers for the identity
+// carried in the request context, so one set of clients can serve every caller.
+type impersonatingRoundTripper struct {
+	base http.RoundTripper
+}
+
+func newImpersonatingTransport(base http.RoundTripper) http.RoundTripper {
+	return &impersonatingRoundTripper{base: base}
+}
+
+func (t *impersonatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	imp, ok := ImpersonationFrom(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(transport.ImpersonateUserHeader, imp.User)
+	req.Header.Del(transport.ImpersonateGroupHeader)
+	for _, g := range imp.Groups {
+		req.Header.Add(transport.ImpersonateGroupHeader, g)
+	}
+	trace.SpanFromContext(req.Context()).SetAttributes(attribute.String("k8s.impersonate.user", imp.User))
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceScope limits the namespaces API calls may read or write. With
+// Allowed set, only those namespaces are reachable; Denied namespaces never
+// are.
+type NamespaceScope struct {
+	Allowed []string
+	Denied  []string
+}
+
+// NewNamespaceScope returns the scope of allowed and denied, or nil when both
+// are empty and every namespace is reachable.
+func NewNamespaceScope(allowed, denied []string) *NamespaceScope {
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil
+	}
+	return &NamespaceScope{Allowed: allowed, Denied: denied}
+}
+
+// Allows reports whether ns is reachable; a nil scope allows every namespace.
+func (s *NamespaceScope) Allows(ns string) bool {
+	if s == nil {
+		return true
+	}
+	for _, d := range s.Denied {
+		if d == ns {
+			return false
+		}
+	}
+	if len(s.Allowed) == 0 {
+		return true
+	}
+	for _, a := range s.Allowed {
+		if a == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the scope for error messages.
+func (s *NamespaceScope) String() string {
+	var parts []string
+	if len(s.Allowed) > 0 {
+		parts = append(parts, "allowed: "+strings.Join(s.Allowed, ", "))
+	}
+	if len(s.Denied) > 0 {
+		parts = append(parts, "denied: "+strings.Join(s.Denied, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+type namespaceScopeKey struct{}
+
+// WithNamespaceScope returns a context whose API calls, through any Clients,
+// are limited to the namespaces of scope. A nil scope lifts any limit, e.g.
+// for calls the server makes in its own namespaces on behalf of a tool.
+func WithNamespaceScope(ctx context.Context, scope *NamespaceScope) context.Context {
+	return context.WithValue(ctx, namespaceScopeKey{}, scope)
+}
+
+// NamespaceScopeFrom returns the scope set by WithNamespaceScope, or nil.
+func NamespaceScopeFrom(ctx context.Context) *NamespaceScope {
+	scope, _ := ctx.Value(namespaceScopeKey{}).(*NamespaceScope)
+	return scope
+}
+
+// namespaceScopeRoundTripper enforces the namespace scope carried in the
+// request context: calls in other namespaces are rejected as Forbidden,
+// cluster-wide lists only return the objects of reachable namespaces, and
+// cluster-wide watches, which cannot be narrowed, are rejected.
+type namespaceScopeRoundTripper struct {
+	base http.RoundTripper
+}
+
+func newNamespaceScopeTransport(base http.RoundTripper) http.RoundTripper {
+	return &namespaceScopeRoundTripper{base: base}
+}
+
+func (t *namespaceScopeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	scope := NamespaceScopeFrom(req.Context())
+	if scope == nil {
+		return t.base.RoundTrip(req)
+	}
+	verb, resource, namespace, name := parseK8sURL(req.Method, req.URL.Path)
+	switch {
+	case namespace != "" && !scope.Allows(namespace):
+		return forbiddenResponse(req, scope, namespace), nil
+	case resource == "namespaces" && name != "" && !scope.Allows(name):
+		return forbiddenResponse(req, scope, name), nil
+	case namespace != "" || verb != "list" || resource == "unknown":
+		return t.base.RoundTrip(req)
+	case req.URL.Query().Get("watch") == "true" || req.URL.Query().Get("watch") == "1":
+		return forbiddenResponse(req, scope, ""), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		_ = resp.Body.Close()
+		return forbiddenResponse(req, scope, ""), nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	filtered, dropped, err := filterListItems(body, resource, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to narrow %s list to the allowed namespaces: %w", resource, err)
+	}
+	if dropped > 0 {
+		trace.SpanFromContext(req.Context()).SetAttributes(attribute.Int("k8s.namespace_scope.dropped", dropped))
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(filtered))
+	resp.ContentLength = int64(len(filtered))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(filtered)))
+	return resp, nil
+}
+
+// filterListItems drops the items of a JSON list that live in namespaces
+// outside scope, and the Namespace objects of such namespaces.
+func filterListItems(body []byte, resource string, scope *NamespaceScope) ([]byte, int, error) {
+	var list map[string]interface{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, 0, err
+	}
+	items, ok := list["items"].([]interface{})
+	if !ok {
+		return body, 0, nil
+	}
+	kept := items[:0]
+	for _, item := range items {
+		meta, _ := item.(map[string]interface{})["metadata"].(map[string]interface{})
+		ns, _ := meta["namespace"].(string)
+		if resource == "namespaces" {
+			ns, _ = meta["name"].(string)
+		}
+		if ns == "" || scope.Allows(ns) {
+			kept = append(kept, item)
+		}
+	}
+	dropped := len(items) - len(kept)
+	if dropped == 0 {
+		return body, 0, nil
+	}
+	list["items"] = kept
+	out, err := json.Marshal(list)
+	return out, dropped, err
+}
+
+// forbiddenResponse answers req as the API server does for a call RBAC
+// denies, so tools report it like a missing permission.
+func forbiddenResponse(req *http.Request, scope *NamespaceScope, namespace string) *http.Response {
+	msg := "cluster-wide watches are not allowed when the server is limited to some namespaces"
+	if namespace != "" {
+		msg = fmt.Sprintf("namespace %q is outside the namespaces this server may inspect", namespace)
+	}
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  fmt.Sprintf("%s (%s)", msg, scope),
+		Reason:   metav1.StatusReasonForbidden,
+		Code:     http.StatusForbidden,
+	}
+	body, _ := json.Marshal(status)
+	return &http.Response{
+		Status:        "403 Forbidden",
+		StatusCode:    http.StatusForbidden,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
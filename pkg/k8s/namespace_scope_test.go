@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNamespaceScopeAllows(t *testing.T) {
+	scope := NewNamespaceScope([]string{"team-a", "team-b"}, []string{"team-b"})
+	if !scope.Allows("team-a") || scope.Allows("team-b") || scope.Allows("kube-system") {
+		t.Errorf("Allows() mismatch for %s", scope)
+	}
+	if deny := NewNamespaceScope(nil, []string{"kube-system"}); !deny.Allows("team-a") || deny.Allows("kube-system") {
+		t.Error("a deny list alone should allow every other namespace")
+	}
+	if NewNamespaceScope(nil, nil) != nil {
+		t.Error("an empty scope should be nil")
+	}
+}
+
+func TestNamespaceScopeTransport(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		items := `[{"metadata":{"namespace":"team-a","name":"web"}},{"metadata":{"namespace":"team-b","name":"api"}},{"metadata":{"name":"node-1"}}]`
+		if strings.HasSuffix(req.URL.Path, "/namespaces") {
+			items = `[{"metadata":{"name":"team-a"}},{"metadata":{"name":"team-b"}}]`
+		}
+		_, _ = rec.WriteString(`{"kind":"List","items":` + items + `}`)
+		return rec.Result(), nil
+	})
+	rt := newNamespaceScopeTransport(base)
+	ctx := WithNamespaceScope(context.Background(), NewNamespaceScope([]string{"team-a"}, nil))
+
+	do := func(ctx context.Context, path string) (*http.Response, []string) {
+		req := httptest.NewRequest(http.MethodGet, "https://api"+path, nil).WithContext(ctx)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		var list struct {
+			Items []struct {
+				Metadata struct{ Name string } `json:"metadata"`
+			} `json:"items"`
+		}
+		_ = json.Unmarshal(body, &list)
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.Metadata.Name)
+		}
+		return resp, names
+	}
+
+	if resp, _ := do(ctx, "/api/v1/namespaces/team-b/pods"); resp.StatusCode != http.StatusForbidden || calls != 0 {
+		t.Errorf("list in team-b: status %d after %d calls, want 403 before any call", resp.StatusCode, calls)
+	}
+	if resp, _ := do(ctx, "/api/v1/namespaces/team-b"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("get namespace team-b: status %d, want 403", resp.StatusCode)
+	}
+	if resp, _ := do(ctx, "/api/v1/pods?watch=true"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cluster-wide watch: status %d, want 403", resp.StatusCode)
+	}
+	if _, names := do(ctx, "/api/v1/pods"); strings.Join(names, ",") != "web,node-1" {
+		t.Errorf("cluster-wide list = %v, want the team-a and cluster-scoped items", names)
+	}
+	if _, names := do(ctx, "/api/v1/namespaces"); strings.Join(names, ",") != "team-a" {
+		t.Errorf("namespace list = %v, want team-a", names)
+	}
+	if _, names := do(WithNamespaceScope(ctx, nil), "/api/v1/pods"); len(names) != 3 {
+		t.Errorf("unscoped list = %v, want every item", names)
+	}
+}
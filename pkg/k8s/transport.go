@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/telemetry"
 )
 
 var k8sTracer = otel.Tracer("mcp-k8s-networking.k8s")
@@ -46,13 +49,22 @@ func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	)
 	defer span.End()
 
+	call := telemetry.APICall{
+		APIOperation: telemetry.APIOperation{Verb: verb, Resource: resource},
+		Namespace:    namespace,
+		At:           time.Now(),
+	}
 	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	call.Duration = time.Since(call.At)
 	if err != nil {
+		telemetry.DefaultStats.RecordAPICall(call)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return resp, err
 	}
 
+	call.Status = resp.StatusCode
+	telemetry.DefaultStats.RecordAPICall(call)
 	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 	if resp.StatusCode >= 400 {
 		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
@@ -139,3 +139,37 @@ func TestHttpMethodToVerb(t *testing.T) {
 		})
 	}
 }
+
+type headerRecorder struct{ header http.Header }
+
+func (r *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.header = req.Header
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestImpersonatingRoundTripper(t *testing.T) {
+	rec := &headerRecorder{}
+	rt := newImpersonatingTransport(rec)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://k8s/api/v1/pods", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.header.Get("Impersonate-User"); got != "" {
+		t.Errorf("unexpected Impersonate-User %q without an identity in context", got)
+	}
+
+	ctx := WithImpersonation(req.Context(), Impersonation{User: "alice", Groups: []string{"dev", "sre"}})
+	if _, err := rt.RoundTrip(req.WithContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.header.Get("Impersonate-User"); got != "alice" {
+		t.Errorf("Impersonate-User = %q, want alice", got)
+	}
+	if got := rec.header.Values("Impersonate-Group"); len(got) != 2 || got[0] != "dev" || got[1] != "sre" {
+		t.Errorf("Impersonate-Group = %v, want [dev sre]", got)
+	}
+	if req.Header.Get("Impersonate-User") != "" {
+		t.Error("original request headers must not be modified")
+	}
+}
@@ -0,0 +1,346 @@
+// Package logbackend reads container logs from a log backend (Grafana Loki
+// or Elasticsearch) so that tools can look back further than the logs still
+// on the nodes, including logs of pods that no longer exist.
+package logbackend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSize bounds the size of a query response.
+const maxResponseSize = 20 << 20
+
+// MaxLimit caps the lines one query returns.
+const MaxLimit = 5000
+
+// Supported backends.
+const (
+	BackendLoki          = "loki"
+	BackendElasticsearch = "elasticsearch"
+)
+
+// Line is one log line of a container.
+type Line struct {
+	Time      time.Time
+	Pod       string
+	Container string
+	Text      string
+}
+
+// Query selects the log lines of containers in a namespace.
+type Query struct {
+	Namespace string
+	// Pods are exact pod names; PodPrefix matches pods by name prefix, e.g.
+	// the replicas of a Deployment including deleted ones. Both empty
+	// matches every pod of the namespace.
+	Pods      []string
+	PodPrefix string
+	// Containers empty matches every container.
+	Containers []string
+	Since      time.Duration
+	// Limit is the number of newest lines returned, at most MaxLimit.
+	Limit int
+}
+
+// Client queries a Loki or Elasticsearch API.
+type Client struct {
+	backend    string
+	baseURL    string
+	tokenFile  string
+	index      string
+	httpClient *http.Client
+
+	// clusterLabel and cluster scope queries to one cluster when several
+	// clusters ship logs to the same backend.
+	clusterLabel string
+	cluster      string
+}
+
+// NewClient creates a client for the backend API at baseURL. index is the
+// Elasticsearch index pattern searched. When tokenFile is set, its content is
+// sent as a bearer token, or as is when it starts with an authorization
+// scheme such as "ApiKey " or "Basic "; it is read on every request so that
+// rotated tokens are picked up. When clusterLabel is set, queries only match
+// lines whose clusterLabel label (Loki) or field (Elasticsearch) is cluster.
+func NewClient(backend, baseURL, tokenFile, index, clusterLabel, cluster string) (*Client, error) {
+	backend = strings.ToLower(backend)
+	if backend != BackendLoki && backend != BackendElasticsearch {
+		return nil, fmt.Errorf("invalid log backend %q: expected loki or elasticsearch", backend)
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid log backend URL %q: expected http(s)://host[:port][/path]", baseURL)
+	}
+	if backend == BackendElasticsearch && index == "" {
+		return nil, fmt.Errorf("an Elasticsearch log backend needs an index pattern")
+	}
+	return &Client{
+		backend:      backend,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		tokenFile:    tokenFile,
+		index:        index,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		clusterLabel: clusterLabel,
+		cluster:      cluster,
+	}, nil
+}
+
+// Backend returns the backend type, loki or elasticsearch.
+func (c *Client) Backend() string { return c.backend }
+
+// Logs returns the newest q.Limit lines matching q, oldest first.
+func (c *Client) Logs(ctx context.Context, q Query) ([]Line, error) {
+	if q.Limit <= 0 || q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	end := time.Now()
+	start := end.Add(-q.Since)
+	var (
+		lines []Line
+		err   error
+	)
+	if c.backend == BackendElasticsearch {
+		lines, err = c.elasticsearchLogs(ctx, q, start)
+	} else {
+		lines, err = c.lokiLogs(ctx, q, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	if len(lines) > q.Limit {
+		lines = lines[len(lines)-q.Limit:]
+	}
+	return lines, nil
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read log backend token: %w", err)
+		}
+		auth := strings.TrimSpace(string(token))
+		if !strings.HasPrefix(auth, "ApiKey ") && !strings.HasPrefix(auth, "Basic ") && !strings.HasPrefix(auth, "Bearer ") {
+			auth = "Bearer " + auth
+		}
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.backend, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d: %s", c.backend, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s returned an unreadable body: %w", c.backend, err)
+	}
+	return nil
+}
+
+// --- Loki ---
+
+// logQL builds the stream selector of q, with the labels set by Promtail,
+// Grafana Alloy and the OpenTelemetry Collector's Loki exporter defaults.
+func (c *Client) logQL(q Query) string {
+	matchers := []string{"namespace=" + strconv.Quote(q.Namespace)}
+	switch {
+	case len(q.Pods) > 0:
+		matchers = append(matchers, "pod=~"+strconv.Quote(alternation(q.Pods)))
+	case q.PodPrefix != "":
+		matchers = append(matchers, "pod=~"+strconv.Quote(regexp.QuoteMeta(q.PodPrefix)+".*"))
+	}
+	if len(q.Containers) > 0 {
+		matchers = append(matchers, "container=~"+strconv.Quote(alternation(q.Containers)))
+	}
+	if c.clusterLabel != "" {
+		matchers = append(matchers, c.clusterLabel+"="+strconv.Quote(c.cluster))
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+func alternation(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = regexp.QuoteMeta(v)
+	}
+	return strings.Join(quoted, "|")
+}
+
+func (c *Client) lokiLogs(ctx context.Context, q Query, start, end time.Time) ([]Line, error) {
+	var result struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	query := url.Values{
+		"query":     {c.logQL(q)},
+		"start":     {strconv.FormatInt(start.UnixNano(), 10)},
+		"end":       {strconv.FormatInt(end.UnixNano(), 10)},
+		"limit":     {strconv.Itoa(q.Limit)},
+		"direction": {"backward"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/loki/api/v1/query_range?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	var lines []Line
+	for _, s := range result.Data.Result {
+		for _, v := range s.Values {
+			ns, _ := strconv.ParseInt(v[0], 10, 64)
+			lines = append(lines, Line{
+				Time:      time.Unix(0, ns),
+				Pod:       s.Stream["pod"],
+				Container: s.Stream["container"],
+				Text:      strings.TrimRight(v[1], "\n"),
+			})
+		}
+	}
+	return lines, nil
+}
+
+// --- Elasticsearch ---
+
+// Kubernetes metadata fields: Fluent Bit and Fluentd with dynamic mappings
+// first, then Filebeat and Elastic Agent.
+var (
+	namespaceFields = []string{"kubernetes.namespace_name.keyword", "kubernetes.namespace"}
+	podFields       = []string{"kubernetes.pod_name.keyword", "kubernetes.pod.name"}
+	containerFields = []string{"kubernetes.container_name.keyword", "kubernetes.container.name"}
+)
+
+// anyField matches documents where one of fields matches clause, a function
+// of the field name.
+func anyField(fields []string, clause func(field string) map[string]interface{}) map[string]interface{} {
+	should := make([]interface{}, len(fields))
+	for i, f := range fields {
+		should[i] = clause(f)
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{"should": should, "minimum_should_match": 1}}
+}
+
+func terms(values []string) func(string) map[string]interface{} {
+	return func(field string) map[string]interface{} {
+		return map[string]interface{}{"terms": map[string]interface{}{field: values}}
+	}
+}
+
+// searchBody builds the Elasticsearch search request of q for lines newer
+// than start.
+func (c *Client) searchBody(q Query, start time.Time) map[string]interface{} {
+	filter := []interface{}{
+		map[string]interface{}{"range": map[string]interface{}{"@timestamp": map[string]interface{}{"gte": start.UTC().Format(time.RFC3339Nano)}}},
+		anyField(namespaceFields, terms([]string{q.Namespace})),
+	}
+	switch {
+	case len(q.Pods) > 0:
+		filter = append(filter, anyField(podFields, terms(q.Pods)))
+	case q.PodPrefix != "":
+		filter = append(filter, anyField(podFields, func(field string) map[string]interface{} {
+			return map[string]interface{}{"prefix": map[string]interface{}{field: q.PodPrefix}}
+		}))
+	}
+	if len(q.Containers) > 0 {
+		filter = append(filter, anyField(containerFields, terms(q.Containers)))
+	}
+	if c.clusterLabel != "" {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{c.clusterLabel: c.cluster}})
+	}
+	return map[string]interface{}{
+		"size":  q.Limit,
+		"sort":  []interface{}{map[string]interface{}{"@timestamp": map[string]interface{}{"order": "desc"}}},
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filter}},
+	}
+}
+
+func (c *Client) elasticsearchLogs(ctx context.Context, q Query, start time.Time) ([]Line, error) {
+	body, err := json.Marshal(c.searchBody(q, start))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+url.PathEscape(c.index)+"/_search", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	lines := make([]Line, 0, len(result.Hits.Hits))
+	for _, h := range result.Hits.Hits {
+		ts, _ := time.Parse(time.RFC3339Nano, sourceField(h.Source, "@timestamp"))
+		text := sourceField(h.Source, "message")
+		if text == "" {
+			text = sourceField(h.Source, "log")
+		}
+		lines = append(lines, Line{
+			Time:      ts,
+			Pod:       firstSourceField(h.Source, "kubernetes.pod_name", "kubernetes.pod.name"),
+			Container: firstSourceField(h.Source, "kubernetes.container_name", "kubernetes.container.name"),
+			Text:      strings.TrimRight(text, "\n"),
+		})
+	}
+	return lines, nil
+}
+
+// sourceField returns the string at a dotted path of a document, whether
+// its objects are nested or its keys dotted.
+func sourceField(doc map[string]interface{}, path string) string {
+	if v, ok := doc[path]; ok {
+		s, _ := v.(string)
+		return s
+	}
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return ""
+	}
+	nested, _ := doc[head].(map[string]interface{})
+	if nested == nil {
+		return ""
+	}
+	return sourceField(nested, rest)
+}
+
+func firstSourceField(doc map[string]interface{}, paths ...string) string {
+	for _, p := range paths {
+		if v := sourceField(doc, p); v != "" {
+			return v
+		}
+	}
+	return ""
+}
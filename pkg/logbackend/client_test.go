@@ -0,0 +1,125 @@
+package logbackend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Validates(t *testing.T) {
+	if _, err := NewClient("splunk", "http://loki:3100", "", "", "", ""); err == nil {
+		t.Error("expected an error for an unsupported backend")
+	}
+	if _, err := NewClient("loki", "loki:3100", "", "", "", ""); err == nil {
+		t.Error("expected an error for a URL without scheme")
+	}
+	if _, err := NewClient("elasticsearch", "http://es:9200", "", "", "", ""); err == nil {
+		t.Error("expected an error for Elasticsearch without an index pattern")
+	}
+	if c, err := NewClient("Loki", "http://loki:3100/", "", "", "", ""); err != nil || c.Backend() != BackendLoki {
+		t.Errorf("NewClient(Loki) = %v, %v", c, err)
+	}
+}
+
+func TestLogQL(t *testing.T) {
+	c, _ := NewClient("loki", "http://loki:3100", "", "", "cluster", "prod")
+	tests := []struct {
+		q    Query
+		want string
+	}{
+		{Query{Namespace: "shop"}, `{namespace="shop", cluster="prod"}`},
+		{Query{Namespace: "shop", Pods: []string{"web-1", "web-2"}, Containers: []string{"istio-proxy"}}, `{namespace="shop", pod=~"web-1|web-2", container=~"istio-proxy", cluster="prod"}`},
+		{Query{Namespace: "shop", PodPrefix: "web."}, `{namespace="shop", pod=~"web\\..*", cluster="prod"}`},
+	}
+	for _, tt := range tests {
+		if got := c.logQL(tt.q); got != tt.want {
+			t.Errorf("logQL(%+v) = %s, want %s", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestLogs_Loki(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var gotQuery, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery, gotAuth = r.URL.Query().Get("query"), r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[
+			{"stream":{"pod":"web-1","container":"istio-proxy"},"values":[["3000000000","third\n"],["1000000000","first"]]},
+			{"stream":{"pod":"web-2","container":"istio-proxy"},"values":[["2000000000","second"]]}]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("loki", srv.URL, tokenFile, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := c.Logs(context.Background(), Query{Namespace: "shop", Pods: []string{"web-1", "web-2"}, Since: time.Hour, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != `{namespace="shop", pod=~"web-1|web-2"}` || gotAuth != "Bearer secret" {
+		t.Errorf("query = %s, auth = %q", gotQuery, gotAuth)
+	}
+	if len(lines) != 2 || lines[0].Text != "second" || lines[0].Pod != "web-2" || lines[1].Text != "third" {
+		t.Errorf("lines = %+v", lines)
+	}
+}
+
+func TestLogs_Elasticsearch(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("ApiKey abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var gotPath, gotAuth string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		_, _ = w.Write([]byte(`{"hits":{"hits":[
+			{"_source":{"@timestamp":"2024-05-01T10:00:02Z","log":"second\n","kubernetes":{"pod_name":"web-1","container_name":"istio-proxy"}}},
+			{"_source":{"@timestamp":"2024-05-01T10:00:01Z","message":"first","kubernetes.pod.name":"web-2","kubernetes":{"container":{"name":"istio-proxy"}}}}]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("elasticsearch", srv.URL, tokenFile, "logs-*", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := c.Logs(context.Background(), Query{Namespace: "shop", PodPrefix: "web-", Since: time.Hour, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/logs-*/_search" || gotAuth != "ApiKey abc" || gotBody["size"] != float64(10) {
+		t.Errorf("path = %s, auth = %q, body = %v", gotPath, gotAuth, gotBody)
+	}
+	if !strings.Contains(mustJSON(t, gotBody), `"prefix":{"kubernetes.pod_name.keyword":"web-"}`) {
+		t.Errorf("pod prefix filter missing: %s", mustJSON(t, gotBody))
+	}
+	if len(lines) != 2 || lines[0].Text != "first" || lines[0].Pod != "web-2" || lines[0].Container != "istio-proxy" || lines[1].Text != "second" {
+		t.Errorf("lines = %+v", lines)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
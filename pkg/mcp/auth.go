@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
+)
+
+// EnableAuth requires a valid bearer token on /mcp and enforces each token's
+// tool allowlist. Must be called before Start.
+func (s *Server) EnableAuth(v *auth.Verifier) {
+	s.verifier.Store(v)
+	s.mcpServer.AddReceivingMiddleware(toolAccessMiddleware)
+}
+
+// ReloadAuth replaces the verifier of a server that has authentication
+// enabled, e.g. after its policy file changed. Requests already verified
+// keep the tool access they were granted.
+func (s *Server) ReloadAuth(v *auth.Verifier) {
+	if s.verifier.Load() != nil {
+		s.verifier.Store(v)
+	}
+}
+
+// verify checks a bearer token with the current verifier.
+func (s *Server) verify(ctx context.Context, token string, r *http.Request) (*sdkauth.TokenInfo, error) {
+	return s.verifier.Load().Verify(ctx, token, r)
+}
+
+// toolAccessMiddleware hides tools a caller may not use from tools/list and
+// rejects tools/call for them.
+func toolAccessMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		var access *auth.ToolAccess
+		if extra := req.GetExtra(); extra != nil {
+			access = auth.AccessFromTokenInfo(extra.TokenInfo)
+		}
+		if access == nil {
+			return next(ctx, method, req)
+		}
+
+		switch method {
+		case "tools/call":
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && !access.Allows(params.Name) {
+				slog.Warn("mcp: tool call denied", "tool", params.Name, "user", req.GetExtra().TokenInfo.UserID)
+				return nil, fmt.Errorf("%w: %s", auth.ErrToolNotAllowed, params.Name)
+			}
+		case "tools/list":
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+				allowed := list.Tools[:0:0]
+				for _, t := range list.Tools {
+					if access.Allows(t.Name) {
+						allowed = append(allowed, t)
+					}
+				}
+				list.Tools = allowed
+			}
+			return result, err
+		}
+		return next(ctx, method, req)
+	}
+}
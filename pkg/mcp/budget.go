@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Summarization tiers applied, in order, to text responses over budget.
+const (
+	tierFull     = "full"
+	tierNoDetail = "no_detail"
+	tierCompact  = "compact"
+	tierTrimmed  = "trimmed"
+)
+
+// EnableResponseBudget caps text tool results at maxBytes. Must be called
+// before Start.
+func (s *Server) EnableResponseBudget(maxBytes int) {
+	s.maxResponseBytes = maxBytes
+}
+
+// fitBudget renders result as text within the response budget, dropping
+// finding details, then suggestions, then the least severe findings until it
+// fits. It returns the text and the tier used.
+func (s *Server) fitBudget(result *tools.StandardResponse) (string, string) {
+	text := result.ToText()
+	if s.maxResponseBytes <= 0 || len(text) <= s.maxResponseBytes {
+		return text, tierFull
+	}
+	tr, ok := result.Data.(*types.ToolResult)
+	if !ok {
+		return truncateText(text, s.maxResponseBytes), tierTrimmed
+	}
+
+	withFindings := func(findings []types.DiagnosticFinding) string {
+		trimmed := *result
+		data := *tr
+		data.Findings = findings
+		trimmed.Data = &data
+		return trimmed.ToText()
+	}
+
+	noDetail := make([]types.DiagnosticFinding, len(tr.Findings))
+	for i, f := range tr.Findings {
+		f.Detail = ""
+		noDetail[i] = f
+	}
+	note := fmt.Sprintf("\nRESPONSE BUDGET: finding details were dropped to stay under %d bytes. Call again with a namespace or selector to see them.", s.maxResponseBytes)
+	if text := withFindings(noDetail) + note; len(text) <= s.maxResponseBytes {
+		return text, tierNoDetail
+	}
+
+	compact := types.FilterFindings(tr.Findings, false)
+	note = fmt.Sprintf("\nRESPONSE BUDGET: details and suggestions were dropped to stay under %d bytes. Call again with a namespace or selector to see them.", s.maxResponseBytes)
+	if text := withFindings(compact) + note; len(text) <= s.maxResponseBytes {
+		return text, tierCompact
+	}
+
+	// Keep the most severe findings that fit.
+	sort.SliceStable(compact, func(i, j int) bool {
+		return severityRank(compact[i].Severity) > severityRank(compact[j].Severity)
+	})
+	keep := sort.Search(len(compact)+1, func(n int) bool {
+		return len(withFindings(compact[:n])+omittedNote(compact[n:], s.maxResponseBytes)) > s.maxResponseBytes
+	}) - 1
+	if keep < 0 {
+		keep = 0
+	}
+	return truncateText(withFindings(compact[:keep])+omittedNote(compact[keep:], s.maxResponseBytes), s.maxResponseBytes), tierTrimmed
+}
+
+// omittedNote tells the client which findings were left out and how to get
+// them.
+func omittedNote(omitted []types.DiagnosticFinding, maxBytes int) string {
+	counts := make(map[string]int)
+	for _, f := range omitted {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, sev := range []string{types.SeverityCritical, types.SeverityWarning, types.SeverityInfo, types.SeverityOK} {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	return fmt.Sprintf("\nRESPONSE BUDGET: %d more findings (%s) and all details were omitted to stay under %d bytes. Call again with a namespace, selector or limit to see the rest.",
+		len(omitted), strings.Join(parts, ", "), maxBytes)
+}
+
+// truncateText cuts text at the last line break that keeps it, with a
+// truncation note, within maxBytes. A budget too small for the note gets
+// as much of the text as fits.
+func truncateText(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	note := fmt.Sprintf("\nRESPONSE BUDGET: output truncated at %d of %d bytes. Call again with a namespace, selector or limit to see the rest.", maxBytes, len(text))
+	cut := maxBytes - len(note)
+	if cut < 0 {
+		note, cut = "", max(maxBytes, 0)
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if i := strings.LastIndexByte(text[:cut], '\n'); i > 0 && note != "" {
+		cut = i
+	}
+	return text[:cut] + note
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case types.SeverityCritical:
+		return 3
+	case types.SeverityWarning:
+		return 2
+	case types.SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
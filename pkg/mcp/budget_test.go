@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// budgetResponse has findings of every severity, with details and
+// suggestions, rendering to a few thousand bytes.
+func budgetResponse() *tools.StandardResponse {
+	severities := []string{types.SeverityOK, types.SeverityInfo, types.SeverityWarning, types.SeverityCritical}
+	var findings []types.DiagnosticFinding
+	for i := 0; i < 20; i++ {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severities[i%len(severities)],
+			Category:   types.CategoryRouting,
+			Summary:    fmt.Sprintf("Service shop/web-%02d has no ready endpoints", i),
+			Detail:     strings.Repeat("selector app=web matches no running pod; ", 4),
+			Suggestion: "Check the Deployment rollout and the readiness probe of its pods.",
+		})
+	}
+	return &tools.StandardResponse{Cluster: "test", Tool: "list_services", Data: &types.ToolResult{Findings: findings}}
+}
+
+func fit(budget int, result *tools.StandardResponse) (string, string) {
+	s := &Server{}
+	s.EnableResponseBudget(budget)
+	return s.fitBudget(result)
+}
+
+func TestFitBudgetTiers(t *testing.T) {
+	result := budgetResponse()
+	full := result.ToText()
+
+	if text, tier := fit(0, result); tier != tierFull || text != full {
+		t.Errorf("no budget: tier %s", tier)
+	}
+	if text, tier := fit(len(full), result); tier != tierFull || text != full {
+		t.Errorf("budget of the full text: tier %s", tier)
+	}
+
+	// Each tier's output has the same length for budgets of the same number
+	// of digits, so the budget equal to it is the tier's boundary.
+	budget := len(full) - 1
+	for _, c := range []struct{ tier, next, note string }{
+		{tierNoDetail, tierCompact, "finding details were dropped"},
+		{tierCompact, tierTrimmed, "details and suggestions were dropped"},
+	} {
+		text, tier := fit(budget, result)
+		if tier != c.tier {
+			t.Fatalf("budget %d: tier %s, want %s", budget, tier, c.tier)
+		}
+		if text, tier = fit(len(text), result); tier != c.tier || !strings.Contains(text, c.note) {
+			t.Errorf("budget %d: tier %s, want %s with %q", len(text), tier, c.tier, c.note)
+		}
+		budget = len(text) - 1
+		if _, tier := fit(budget, result); tier != c.next {
+			t.Errorf("budget %d: tier %s, want %s", budget, tier, c.next)
+		}
+	}
+}
+
+func TestFitBudgetTrimsLeastSevere(t *testing.T) {
+	result := budgetResponse()
+	text, tier := fit(1000, result)
+	if tier != tierTrimmed {
+		t.Fatalf("tier %s, want %s", tier, tierTrimmed)
+	}
+	if !strings.Contains(text, "more findings (") || !strings.Contains(text, "5 ok") {
+		t.Errorf("no omitted note for the ok findings:\n%s", text)
+	}
+	if !strings.Contains(text, "web-03") || strings.Contains(text, "web-00 ") {
+		t.Errorf("kept a less severe finding before a critical one:\n%s", text)
+	}
+}
+
+func TestFitBudgetNeverExceedsBudget(t *testing.T) {
+	result := budgetResponse()
+	full := result.ToText()
+	plain := &tools.StandardResponse{Cluster: "test", Tool: "get_raw", Data: strings.Repeat("line of raw output\n", 200)}
+	for _, r := range []*tools.StandardResponse{result, plain} {
+		for budget := 1; budget < len(full)+10; budget += 37 {
+			text, tier := fit(budget, r)
+			if len(text) > budget {
+				t.Errorf("%s, budget %d: %d bytes (tier %s)", r.Tool, budget, len(text), tier)
+			}
+			if tier != tierFull && budget > 200 && !strings.Contains(text, "RESPONSE BUDGET:") {
+				t.Errorf("%s, budget %d: tier %s without a note", r.Tool, budget, tier)
+			}
+		}
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	text := strings.Repeat("é€😀", 300)
+	for budget := 0; budget < len(text)+5; budget++ {
+		got := truncateText(text, budget)
+		if len(got) > budget && len(text) > budget {
+			t.Fatalf("budget %d: %d bytes", budget, len(got))
+		}
+		if !utf8.ValidString(got) {
+			t.Fatalf("budget %d: split a rune", budget)
+		}
+		if budget >= len(text) && got != text {
+			t.Fatalf("budget %d: changed text that fits", budget)
+		}
+		if budget > 200 && budget < len(text) && !strings.HasSuffix(got, "to see the rest.") {
+			t.Fatalf("budget %d: no truncation note", budget)
+		}
+	}
+
+	// Text is cut at a line break when there is one.
+	lines := strings.Repeat("0123456789\n", 50)
+	body, _, _ := strings.Cut(truncateText(lines, 300), "\nRESPONSE BUDGET")
+	if body == "" || !strings.HasSuffix(body, "0123456789") {
+		t.Errorf("not cut at a line break:\n%s", body)
+	}
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// How a call was served; see callGroup.do.
+const (
+	callRan       = "ran"
+	callCoalesced = "coalesced"
+	callCached    = "cached"
+)
+
+// unkeyedArgs are left out of call keys: output_format and detail only
+// change how the result is rendered, and the cluster is keyed once resolved.
+var unkeyedArgs = map[string]bool{"output_format": true, "detail": true, "cluster": true}
+
+// EnableDeduplication runs concurrent identical calls of read and scan
+// tools once and hands each caller a copy of the result. Tools that change
+// state or deploy probe pods always run. With ttl > 0, identical calls that
+// finished less than ttl ago are served from their result too. Must be
+// called before Start.
+func (s *Server) EnableDeduplication(ttl time.Duration) {
+	s.dedup = newCallGroup(ttl)
+}
+
+// runTool runs t, or shares the outcome of an identical call when
+// deduplication is enabled and t may share results, and reports how the
+// call was served.
+func (s *Server) runTool(callCtx context.Context, cluster string, t tools.Tool, args map[string]interface{}) (toolRun, string) {
+	runOnce := func() (toolRun, bool) {
+		ctx, listErrs := tools.WithListErrors(callCtx)
+		result, err := t.Run(ctx, args)
+		return toolRun{result: result, err: err, listErrs: listErrs.Errors()}, callCtx.Err() != nil
+	}
+	if s.dedup == nil || !tools.SharesResults(t) {
+		run, _ := runOnce()
+		return run, callRan
+	}
+	key, ok := callKey(callCtx, cluster, t.Name(), args)
+	if !ok {
+		run, _ := runOnce()
+		return run, callRan
+	}
+	return s.dedup.do(callCtx, key, runOnce)
+}
+
+// toolRun is the outcome of running a tool once.
+type toolRun struct {
+	result *tools.StandardResponse
+	err    error
+	// listErrs are the resource types the run could not list.
+	listErrs []tools.ResourceError
+}
+
+// call is a run in flight or, once done is closed, finished.
+type call struct {
+	done     chan struct{}
+	run      toolRun
+	finished time.Time
+	// abandoned is set when the run ended with its caller's context, so
+	// its outcome says nothing about the other callers' calls.
+	abandoned bool
+}
+
+// callGroup coalesces identical tool calls by key.
+type callGroup struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+func newCallGroup(ttl time.Duration) *callGroup {
+	return &callGroup{ttl: ttl, calls: make(map[string]*call)}
+}
+
+// do returns the outcome of the call with key: a run in flight or, within
+// the TTL, a successful finished one; otherwise it runs fn. Waiting callers
+// give up when ctx ends, and run fn themselves when the run they waited for
+// was abandoned. The result is a copy the caller may change.
+func (g *callGroup) do(ctx context.Context, key string, fn func() (toolRun, bool)) (toolRun, string) {
+	for {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		if ok {
+			select {
+			case <-c.done:
+				if c.abandoned || c.run.err != nil || time.Since(c.finished) >= g.ttl {
+					ok = false
+				}
+			default:
+			}
+		}
+		if !ok {
+			c = &call{done: make(chan struct{})}
+			g.calls[key] = c
+			g.mu.Unlock()
+			return g.run(key, c, fn), callRan
+		}
+		g.mu.Unlock()
+
+		how := callCached
+		select {
+		case <-c.done:
+		default:
+			how = callCoalesced
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return toolRun{err: ctx.Err()}, how
+			}
+		}
+		if !c.abandoned {
+			return c.run.copy(), how
+		}
+	}
+}
+
+// run runs fn as the call c and publishes its outcome. A panicking fn
+// abandons the call, so waiting callers do not wait forever.
+func (g *callGroup) run(key string, c *call, fn func() (toolRun, bool)) (run toolRun) {
+	abandoned := true
+	defer func() {
+		g.mu.Lock()
+		c.run = run.copy()
+		c.abandoned = abandoned
+		c.finished = time.Now()
+		if abandoned || run.err != nil || g.ttl <= 0 {
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.sweep()
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	run, abandoned = fn()
+	return run
+}
+
+// sweep drops finished calls past the TTL. g.mu must be held.
+func (g *callGroup) sweep() {
+	for key, c := range g.calls {
+		if !c.finished.IsZero() && time.Since(c.finished) >= g.ttl {
+			delete(g.calls, key)
+		}
+	}
+}
+
+// copy returns r with its own response and findings, since each caller
+// filters, suppresses and redacts findings in place.
+func (r toolRun) copy() toolRun {
+	if r.result == nil {
+		return r
+	}
+	res := *r.result
+	if tr, ok := res.Data.(*types.ToolResult); ok {
+		data := *tr
+		data.Findings = append([]types.DiagnosticFinding(nil), tr.Findings...)
+		res.Data = &data
+	}
+	r.result = &res
+	r.listErrs = append([]tools.ResourceError(nil), r.listErrs...)
+	return r
+}
+
+// callKey identifies a tool call: the cluster, the tool, the arguments that
+// change its result, and the identity it runs as, so that callers with
+// different permissions never share results.
+func callKey(ctx context.Context, cluster, tool string, args map[string]interface{}) (string, bool) {
+	keyArgs := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if !unkeyedArgs[k] {
+			keyArgs[k] = v
+		}
+	}
+	// Map keys are marshalled in sorted order.
+	argsJSON, err := json.Marshal(keyArgs)
+	if err != nil {
+		return "", false
+	}
+	parts := []string{cluster, tool, string(argsJSON)}
+	if imp, ok := k8s.ImpersonationFrom(ctx); ok {
+		parts = append(parts, imp.Key())
+	}
+	if access := auth.ToolAccessFromContext(ctx); access != nil {
+		accessJSON, _ := json.Marshal(access)
+		parts = append(parts, string(accessJSON))
+	}
+	return strings.Join(parts, "\x00"), true
+}
@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func findingsRun(summary string) toolRun {
+	return toolRun{result: &tools.StandardResponse{Data: &types.ToolResult{
+		Findings: []types.DiagnosticFinding{{Severity: types.SeverityInfo, Summary: summary}},
+	}}}
+}
+
+func summaryOf(run toolRun) string {
+	return run.result.Data.(*types.ToolResult).Findings[0].Summary
+}
+
+func TestCallGroupCoalesces(t *testing.T) {
+	// The TTL serves callers that arrive after the run finished.
+	g := newCallGroup(time.Minute)
+	var runs atomic.Int32
+	release := make(chan struct{})
+	fn := func() (toolRun, bool) {
+		runs.Add(1)
+		<-release
+		return findingsRun("ok"), false
+	}
+
+	const callers = 5
+	served := make([]string, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			run, how := g.do(context.Background(), "k", fn)
+			if run.err != nil || summaryOf(run) != "ok" {
+				t.Errorf("caller %d: %v", i, run.err)
+			}
+			served[i] = how
+		}(i)
+	}
+	waitFor(t, func() bool { return runs.Load() == 1 && inFlight(g, "k") })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("ran %d times, want 1", runs.Load())
+	}
+	ran := 0
+	for _, how := range served {
+		if how == callRan {
+			ran++
+		} else if how != callCoalesced && how != callCached {
+			t.Errorf("served %q", how)
+		}
+	}
+	if ran != 1 {
+		t.Errorf("%d callers ran the tool: %v", ran, served)
+	}
+}
+
+func TestCallGroupTTL(t *testing.T) {
+	noTTL := newCallGroup(0)
+	ok := func() (toolRun, bool) { return findingsRun("ok"), false }
+	noTTL.do(context.Background(), "k", ok)
+	if _, how := noTTL.do(context.Background(), "k", ok); how != callRan {
+		t.Errorf("call after a run without TTL: %s, want %s", how, callRan)
+	}
+
+	g := newCallGroup(50 * time.Millisecond)
+	var runs atomic.Int32
+	fn := func() (toolRun, bool) {
+		runs.Add(1)
+		return findingsRun("ok"), false
+	}
+
+	for i, want := range []string{callRan, callCached} {
+		if _, how := g.do(context.Background(), "k", fn); how != want {
+			t.Errorf("call %d: %s, want %s", i, how, want)
+		}
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, how := g.do(context.Background(), "k", fn); how != callRan {
+		t.Errorf("call after the TTL: %s, want %s", how, callRan)
+	}
+	if runs.Load() != 2 {
+		t.Errorf("ran %d times, want 2", runs.Load())
+	}
+
+	// Errors are not cached.
+	failing := func() (toolRun, bool) { return toolRun{err: errors.New("boom")}, false }
+	g.do(context.Background(), "err", failing)
+	if _, how := g.do(context.Background(), "err", failing); how != callRan {
+		t.Errorf("call after an error: %s, want %s", how, callRan)
+	}
+}
+
+func TestCallGroupAbandonedLeader(t *testing.T) {
+	g := newCallGroup(time.Minute)
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	leader := func() (toolRun, bool) {
+		close(started)
+		<-leaderCtx.Done()
+		return toolRun{err: leaderCtx.Err()}, true
+	}
+
+	done := make(chan toolRun)
+	go func() {
+		run, _ := g.do(leaderCtx, "k", leader)
+		done <- run
+	}()
+	<-started
+
+	waiter := make(chan string)
+	go func() {
+		run, how := g.do(context.Background(), "k", func() (toolRun, bool) { return findingsRun("waiter"), false })
+		if run.err != nil || summaryOf(run) != "waiter" {
+			t.Errorf("waiter got %+v", run)
+		}
+		waiter <- how
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if run := <-done; !errors.Is(run.err, context.Canceled) {
+		t.Errorf("leader got %v, want context.Canceled", run.err)
+	}
+	if how := <-waiter; how != callRan {
+		t.Errorf("waiter was %s, want %s after the leader was cancelled", how, callRan)
+	}
+}
+
+func TestCallGroupWaiterContext(t *testing.T) {
+	g := newCallGroup(0)
+	release := make(chan struct{})
+	defer close(release)
+	go g.do(context.Background(), "k", func() (toolRun, bool) {
+		<-release
+		return findingsRun("ok"), false
+	})
+	waitFor(t, func() bool { return inFlight(g, "k") })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	run, how := g.do(ctx, "k", func() (toolRun, bool) { t.Error("waiter ran the tool"); return toolRun{}, false })
+	if how != callCoalesced || !errors.Is(run.err, context.DeadlineExceeded) {
+		t.Errorf("got %s, %v; want %s, context.DeadlineExceeded", how, run.err, callCoalesced)
+	}
+}
+
+func TestToolRunCopy(t *testing.T) {
+	g := newCallGroup(time.Minute)
+	fn := func() (toolRun, bool) { return findingsRun("original"), false }
+
+	first, _ := g.do(context.Background(), "k", fn)
+	first.result.Data.(*types.ToolResult).Findings[0].Summary = "redacted"
+	first.result.Data.(*types.ToolResult).Findings = nil
+	first.result.Cluster = "changed"
+
+	second, how := g.do(context.Background(), "k", fn)
+	if how != callCached {
+		t.Fatalf("second call was %s, want %s", how, callCached)
+	}
+	if got := summaryOf(second); got != "original" {
+		t.Errorf("second caller sees %q", got)
+	}
+	if second.result.Cluster != "" {
+		t.Errorf("second caller sees cluster %q", second.result.Cluster)
+	}
+}
+
+type countingTool struct {
+	tools.ListServicesTool
+	name string
+	runs atomic.Int32
+}
+
+func (c *countingTool) Name() string { return c.name }
+func (c *countingTool) Run(context.Context, map[string]interface{}) (*tools.StandardResponse, error) {
+	c.runs.Add(1)
+	return findingsRun(c.name).result, nil
+}
+
+func TestRunToolSkipsToolsWithSideEffects(t *testing.T) {
+	s := &Server{}
+	s.EnableDeduplication(time.Minute)
+	args := map[string]interface{}{"namespace": "shop"}
+
+	for name, want := range map[string]int32{
+		"list_services":        1,
+		"quick_scan":           1,
+		"suppress_finding":     2,
+		"refresh_capabilities": 2,
+		"probe_latency":        2,
+		"check_probe_hygiene":  2,
+	} {
+		tool := &countingTool{name: name}
+		s.runTool(context.Background(), "default", tool, args)
+		s.runTool(context.Background(), "default", tool, args)
+		if got := tool.runs.Load(); got != want {
+			t.Errorf("%s ran %d times, want %d", name, got, want)
+		}
+	}
+}
+
+func inFlight(g *callGroup, key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.calls[key]
+	return ok
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
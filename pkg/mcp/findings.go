@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// EnableFindingHistory records the findings of every successful tool call
+// against cluster in store. Must be called before Start.
+func (s *Server) EnableFindingHistory(cluster string, store *history.FindingStore) {
+	if s.findingHistory == nil {
+		s.findingHistory = make(map[string]*history.FindingStore)
+	}
+	s.findingHistory[cluster] = store
+}
+
+// EnableSuppressions leaves the findings silenced by the suppressions of
+// cluster out of its tool results. Must be called before Start.
+func (s *Server) EnableSuppressions(cluster string, store *tools.SuppressionStore) {
+	if s.suppressions == nil {
+		s.suppressions = make(map[string]*tools.SuppressionStore)
+	}
+	s.suppressions[cluster] = store
+}
+
+// applySuppressions filters the findings of one call of t, if the cluster
+// has suppressions.
+func (s *Server) applySuppressions(ctx context.Context, cluster string, t tools.Tool, findings []types.DiagnosticFinding) []types.DiagnosticFinding {
+	store := s.suppressions[cluster]
+	if store == nil || !tools.AppliesSuppressions(t) {
+		return findings
+	}
+	return store.Apply(ctx, findings)
+}
+
+// recordFindingHistory stores the findings of one call of t with args, if
+// the cluster keeps a finding history.
+func (s *Server) recordFindingHistory(cluster string, t tools.Tool, args map[string]interface{}, at time.Time, findings []types.DiagnosticFinding) {
+	store := s.findingHistory[cluster]
+	if store == nil || !tools.RecordsFindingHistory(t) {
+		return
+	}
+	if err := store.Add(tools.FindingRun(t.Name(), args, at, findings)); err != nil {
+		slog.Warn("failed to record finding history", "cluster", cluster, "tool", t.Name(), "error", err)
+	}
+}
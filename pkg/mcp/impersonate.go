@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+// EnableImpersonation makes tool calls reach the Kubernetes API as another
+// identity, so the caller's RBAC decides which namespaces they can see. When
+// caller is set, authenticated callers with a Kubernetes identity are
+// impersonated; everyone else runs as fallback (empty = the server's own
+// service account). Must be called before Start.
+func (s *Server) EnableImpersonation(fallback k8s.Impersonation, caller bool) {
+	s.impersonateCaller = caller
+	s.impersonateFallback = fallback
+}
+
+// impersonationFor returns the identity a tool call runs as.
+func (s *Server) impersonationFor(req *mcp.CallToolRequest) k8s.Impersonation {
+	if s.impersonateCaller && req.Extra != nil {
+		if id := auth.KubernetesIdentityFromTokenInfo(req.Extra.TokenInfo); id != nil {
+			return k8s.Impersonation{User: id.Username, Groups: id.Groups}
+		}
+	}
+	return s.impersonateFallback
+}
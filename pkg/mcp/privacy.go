@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"github.com/isitobservable/k8s-networking-mcp/pkg/privacy"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// EnableMinimization pseudonymizes IP addresses, node names and external
+// hostnames in every tool result and tool error. Must be called before Start.
+func (s *Server) EnableMinimization(m *privacy.Minimizer) {
+	s.minimizer = m
+}
+
+// minimize applies data minimization to text sent to the client.
+func (s *Server) minimize(text string) string {
+	if s.minimizer == nil {
+		return text
+	}
+	return s.minimizer.Apply(text)
+}
+
+// EnableRedaction removes secret material, such as tokens, passwords and
+// certificates, from finding details and from tool results without
+// findings. Must be called before Start.
+func (s *Server) EnableRedaction() {
+	s.redactSecrets = true
+}
+
+// redactFindings redacts the details of findings in place and returns the
+// number of redactions.
+func (s *Server) redactFindings(findings []types.DiagnosticFinding) int {
+	if !s.redactSecrets {
+		return 0
+	}
+	total := 0
+	for i := range findings {
+		var n int
+		findings[i].Detail, n = privacy.Redact(findings[i].Detail)
+		total += n
+	}
+	return total
+}
+
+// redact redacts text sent to the client and returns the number of
+// redactions.
+func (s *Server) redact(text string) (string, int) {
+	if !s.redactSecrets {
+		return text, 0
+	}
+	return privacy.Redact(text)
+}
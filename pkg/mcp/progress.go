@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
+)
+
+// progressNotifier sends the progress reports of a tool call to the client
+// as notifications/progress for token. Reports that would not move progress
+// forward are dropped, as the protocol requires progress to increase.
+func (s *Server) progressNotifier(ctx context.Context, session *mcp.ServerSession, token any) progress.Func {
+	var mu sync.Mutex
+	last := -1
+	return func(done, total int, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done <= last {
+			return
+		}
+		last = done
+		params := &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(done),
+			Message:       s.minimize(message),
+		}
+		if total > 0 {
+			params.Total = float64(total)
+		}
+		if err := session.NotifyProgress(ctx, params); err != nil {
+			slog.Debug("mcp: failed to send progress", "error", err)
+		}
+	}
+}
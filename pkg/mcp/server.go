@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -18,6 +20,11 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/privacy"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/telemetry"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
@@ -34,14 +41,37 @@ var sensitiveKeys = []string{"secret", "token", "key", "password", "credential"}
 type Server struct {
 	mcpServer  *mcp.Server
 	httpServer *http.Server
-	registry   *tools.Registry
 	meters     *telemetry.Meters
+	verifier   atomic.Pointer[auth.Verifier] // nil = /mcp is unauthenticated
+
+	// Identity tool calls impersonate; see EnableImpersonation.
+	impersonateCaller   bool
+	impersonateFallback k8s.Impersonation
+
+	minimizer     *privacy.Minimizer // nil = responses are returned verbatim
+	redactSecrets bool               // see EnableRedaction
+
+	maxResponseBytes int // 0 = text responses are not budgeted
+
+	timeouts atomic.Pointer[tools.TimeoutPolicy] // nil = tool calls run until they return
+	dedup    *callGroup                          // nil = every call runs its tool
+
+	findingHistory map[string]*history.FindingStore   // per cluster; see EnableFindingHistory
+	suppressions   map[string]*tools.SuppressionStore // per cluster; see EnableSuppressions
+
+	// Per-cluster tool registries; tool calls pick one with the "cluster"
+	// argument. clusters and clusterOrder are guarded by mu.
+	defaultCluster string
+	clusters       map[string]*tools.Registry
+	clusterOrder   []string
 
 	mu              sync.Mutex
-	registeredTools map[string]struct{} // tracks tools currently registered in mcpServer
+	registeredTools map[string]string // description of each tool currently registered in mcpServer
 }
 
-func NewServer(registry *tools.Registry) *Server {
+// NewServer creates a server whose tool calls go to registry unless the
+// caller selects another cluster added with AddCluster.
+func NewServer(defaultCluster string, registry *tools.Registry) *Server {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "mcp-k8s-networking",
 		Version: "1.0.0",
@@ -54,10 +84,99 @@ func NewServer(registry *tools.Registry) *Server {
 
 	return &Server{
 		mcpServer:       mcpServer,
-		registry:        registry,
 		meters:          meters,
-		registeredTools: make(map[string]struct{}),
+		defaultCluster:  defaultCluster,
+		clusters:        map[string]*tools.Registry{defaultCluster: registry},
+		clusterOrder:    []string{defaultCluster},
+		registeredTools: make(map[string]string),
+	}
+}
+
+// AddCluster makes another cluster's tools selectable per request. Must be
+// called before the first SyncTools.
+func (s *Server) AddCluster(name string, registry *tools.Registry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clusters[name] = registry
+	s.clusterOrder = append(s.clusterOrder, name)
+}
+
+// multiCluster reports whether tools should accept a "cluster" argument.
+func (s *Server) multiCluster() bool {
+	return len(s.clusterOrder) > 1
+}
+
+// allTools returns the union of tools across clusters, preferring the
+// default cluster's instance for schema and description.
+func (s *Server) allTools() []tools.Tool {
+	seen := make(map[string]struct{})
+	var out []tools.Tool
+	for _, name := range s.clusterOrder {
+		for _, t := range s.clusters[name].List() {
+			if _, ok := seen[t.Name()]; ok {
+				continue
+			}
+			seen[t.Name()] = struct{}{}
+			out = append(out, t)
+		}
 	}
+	return out
+}
+
+// allAliases returns the deprecated aliases across clusters that no tool is
+// named, mapped to the tool each calls, preferring the default cluster's.
+func (s *Server) allAliases(names map[string]struct{}) map[string]tools.Tool {
+	out := make(map[string]tools.Tool)
+	for _, cluster := range s.clusterOrder {
+		registry := s.clusters[cluster]
+		for alias, target := range registry.Aliases() {
+			if _, ok := names[alias]; ok {
+				continue
+			}
+			if _, ok := out[alias]; ok {
+				continue
+			}
+			if t, ok := registry.Get(target); ok {
+				out[alias] = t
+			}
+		}
+	}
+	return out
+}
+
+// resolveTool finds the tool instance for the cluster selected in args. It
+// reports whether name is a deprecated alias of the tool.
+func (s *Server) resolveTool(name string, args map[string]interface{}) (tools.Tool, bool, string, error) {
+	cluster := s.defaultCluster
+	if c, ok := args["cluster"].(string); ok && c != "" {
+		cluster = c
+	}
+
+	s.mu.Lock()
+	registry, ok := s.clusters[cluster]
+	var configured string
+	if !ok {
+		configured = strings.Join(s.clusterOrder, ", ")
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, false, cluster, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    name,
+			Message: fmt.Sprintf("unknown cluster %q", cluster),
+			Detail:  fmt.Sprintf("configured clusters: %s", configured),
+		}
+	}
+	t, alias, ok := registry.Resolve(name)
+	if !ok {
+		return nil, false, cluster, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    name,
+			Message: fmt.Sprintf("tool %s is not available on cluster %s", name, cluster),
+			Detail:  "The CRDs this tool needs were not detected on that cluster. Use list_clusters to see each cluster's providers.",
+		}
+	}
+	return t, alias, cluster, nil
 }
 
 // SyncTools diffs the registry against what is currently registered in the MCP server,
@@ -66,18 +185,21 @@ func (s *Server) SyncTools() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	registryTools := s.registry.List()
+	registryTools := s.allTools()
 
 	// Build a set of tool names currently in the registry
 	wanted := make(map[string]struct{}, len(registryTools))
 	for _, t := range registryTools {
 		wanted[t.Name()] = struct{}{}
 	}
+	aliases := s.allAliases(wanted)
 
-	// Remove tools that are registered but no longer in the registry
+	// Remove tools and aliases that are registered but no longer in the registry
 	var toRemove []string
 	for name := range s.registeredTools {
-		if _, ok := wanted[name]; !ok {
+		_, isTool := wanted[name]
+		_, isAlias := aliases[name]
+		if !isTool && !isAlias {
 			toRemove = append(toRemove, name)
 		}
 	}
@@ -89,20 +211,62 @@ func (s *Server) SyncTools() {
 		slog.Info("mcp: removed tools", "tools", toRemove)
 	}
 
-	// Add tools that are in the registry but not yet registered
-	added := 0
+	// Add tools that are in the registry but not yet registered, and re-add
+	// those whose description changed (e.g. marked degraded by the RBAC audit)
+	added, updated := 0, 0
 	for _, t := range registryTools {
-		if _, ok := s.registeredTools[t.Name()]; ok {
+		mcpTool := buildMCPTool(t, s.clusterArgSchema())
+		if note := s.degradedNote(s.defaultCluster, t.Name()); note != "" {
+			mcpTool.Description += "\n\n" + note
+		}
+		desc, ok := s.registeredTools[t.Name()]
+		if ok && desc == mcpTool.Description {
 			continue
 		}
-		mcpTool := buildMCPTool(t)
-		handler := s.buildInstrumentedHandler(t)
+		handler := s.buildInstrumentedHandler(t.Name())
 		s.mcpServer.AddTool(mcpTool, handler)
-		s.registeredTools[t.Name()] = struct{}{}
-		added++
+		s.registeredTools[t.Name()] = mcpTool.Description
+		if ok {
+			updated++
+		} else {
+			added++
+		}
 	}
 
-	slog.Info("mcp: synced tools", "total", len(s.registeredTools), "added", added, "removed", len(toRemove))
+	// Deprecated aliases are listed as tools of their own, so clients with
+	// the former name in their prompts can still call it
+	for alias, t := range aliases {
+		mcpTool := buildMCPTool(t, s.clusterArgSchema())
+		mcpTool.Name = alias
+		mcpTool.Description = fmt.Sprintf("Deprecated: renamed to %s, call that instead. %s", t.Name(), mcpTool.Description)
+		desc, ok := s.registeredTools[alias]
+		if ok && desc == mcpTool.Description {
+			continue
+		}
+		s.mcpServer.AddTool(mcpTool, s.buildInstrumentedHandler(alias))
+		s.registeredTools[alias] = mcpTool.Description
+		if ok {
+			updated++
+		} else {
+			added++
+		}
+	}
+
+	slog.Info("mcp: synced tools", "total", len(s.registeredTools), "added", added, "updated", updated, "removed", len(toRemove))
+}
+
+// degradedNote describes the RBAC permissions a tool is missing on a cluster,
+// or returns "" when it has them all.
+func (s *Server) degradedNote(cluster, name string) string {
+	registry, ok := s.clusters[cluster]
+	if !ok {
+		return ""
+	}
+	missing := registry.Degraded(name)
+	if len(missing) == 0 {
+		return ""
+	}
+	return tools.DegradedNote(missing)
 }
 
 func (s *Server) Start(addr string) error {
@@ -112,8 +276,13 @@ func (s *Server) Start(addr string) error {
 		return s.mcpServer
 	}, nil)
 
+	var mcpHandler http.Handler = handler
+	if s.verifier.Load() != nil {
+		mcpHandler = sdkauth.RequireBearerToken(s.verify, nil)(mcpHandler)
+	}
+
 	mux := http.NewServeMux()
-	mux.Handle("/mcp", otelhttp.NewHandler(handler, "MCP",
+	mux.Handle("/mcp", otelhttp.NewHandler(mcpHandler, "MCP",
 		otelhttp.WithTracerProvider(otel.GetTracerProvider()),
 		otelhttp.WithPropagators(otel.GetTextMapPropagator()),
 	))
@@ -127,6 +296,15 @@ func (s *Server) Start(addr string) error {
 	return s.httpServer.ListenAndServe()
 }
 
+// RunStdio serves MCP over stdin/stdout until the client disconnects or ctx is cancelled.
+// Used by desktop MCP clients that launch the binary directly.
+func (s *Server) RunStdio(ctx context.Context) error {
+	s.SyncTools()
+
+	slog.Info("mcp: serving over stdio")
+	return s.mcpServer.Run(ctx, &mcp.StdioTransport{})
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	if s.httpServer != nil {
 		return s.httpServer.Shutdown(ctx)
@@ -134,8 +312,38 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-func buildMCPTool(t tools.Tool) *mcp.Tool {
+// clusterArgSchema returns the JSON schema for the "cluster" argument, or nil
+// when only one cluster is configured.
+func (s *Server) clusterArgSchema() map[string]interface{} {
+	if !s.multiCluster() {
+		return nil
+	}
+	return map[string]interface{}{
+		"type":        "string",
+		"description": fmt.Sprintf("Cluster to run against (default %s). Use list_clusters to see available clusters.", s.defaultCluster),
+		"enum":        append([]string(nil), s.clusterOrder...),
+	}
+}
+
+// outputFormatArg is the JSON schema of the output_format argument every tool
+// accepts.
+var outputFormatArg = map[string]interface{}{
+	"type":        "string",
+	"description": "Response format: text (default), sarif for code scanning or junit for CI pipelines; sarif and junit need a tool that returns findings",
+	"enum":        types.OutputFormats,
+}
+
+func buildMCPTool(t tools.Tool, clusterArg map[string]interface{}) *mcp.Tool {
 	schema := t.InputSchema()
+	props, _ := schema["properties"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+		schema["properties"] = props
+	}
+	if clusterArg != nil {
+		props["cluster"] = clusterArg
+	}
+	props["output_format"] = outputFormatArg
 	schemaJSON, _ := json.Marshal(schema)
 
 	tool := &mcp.Tool{
@@ -153,7 +361,7 @@ func buildMCPTool(t tools.Tool) *mcp.Tool {
 
 // buildInstrumentedHandler creates a ToolHandler that wraps tool execution
 // with OTel spans, metrics, and context propagation per GenAI + MCP semantic conventions.
-func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
+func (s *Server) buildInstrumentedHandler(name string) mcp.ToolHandler {
 	tracer := otel.Tracer("mcp-k8s-networking")
 
 	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -176,7 +384,7 @@ func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
 		}
 
 		// --- Start span following GenAI + MCP semantic conventions ---
-		spanName := fmt.Sprintf("execute_tool %s", t.Name())
+		spanName := fmt.Sprintf("execute_tool %s", name)
 		ctx, span := tracer.Start(ctx, spanName,
 			trace.WithSpanKind(trace.SpanKindServer),
 		)
@@ -185,7 +393,7 @@ func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
 		// Set GenAI + MCP span attributes
 		span.SetAttributes(
 			attribute.String("gen_ai.operation.name", "execute_tool"),
-			attribute.String("gen_ai.tool.name", t.Name()),
+			attribute.String("gen_ai.tool.name", name),
 			attribute.String("mcp.method.name", "tools/call"),
 			attribute.String("mcp.protocol.version", mcpProtocolVersion),
 			attribute.String("mcp.session.id", sessionID),
@@ -195,10 +403,11 @@ func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
 		var args map[string]interface{}
 		if request.Params.Arguments != nil {
 			if err := json.Unmarshal(request.Params.Arguments, &args); err != nil {
-				errType := "INVALID_INPUT"
-				s.recordError(ctx, span, t.Name(), errType, err)
+				mcpErr := &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: name, Message: fmt.Sprintf("failed to parse arguments: %v", err)}
+				s.recordError(ctx, span, name, mcpErr.Code, err)
+				errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
 				return &mcp.CallToolResult{
-					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("failed to parse arguments: %v", err)}},
+					Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
 					IsError: true,
 				}, nil
 			}
@@ -210,56 +419,197 @@ func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
 		// Set sanitized arguments as span attribute
 		span.SetAttributes(attribute.String("gen_ai.tool.call.arguments", sanitizeArgs(args)))
 
-		// --- Execute tool with timing ---
+		format, err := outputFormat(args)
+		if err != nil {
+			mcpErr := err.(*types.MCPError)
+			mcpErr.Tool = name
+			s.recordError(ctx, span, name, mcpErr.Code, err)
+			errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
+				IsError: true,
+			}, nil
+		}
+
+		// --- Resolve the tool instance for the selected cluster ---
+		t, alias, cluster, err := s.resolveTool(name, args)
+		span.SetAttributes(attribute.String("k8s.cluster.name", cluster))
+		if err != nil {
+			mcpErr := err.(*types.MCPError)
+			s.recordError(ctx, span, name, mcpErr.Code, err)
+			errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
+				IsError: true,
+			}, nil
+		}
+
+		// --- A deprecated alias runs its tool under the tool's name, so
+		// metrics and timeouts stay with the tool, and says so in the result ---
+		deprecation := ""
+		if alias {
+			deprecation = tools.DeprecationNote(name, t.Name())
+			s.recordAliasCall(ctx, name, t.Name())
+			span.SetAttributes(
+				attribute.String("mcp.tool.alias", name),
+				attribute.String("gen_ai.tool.name", t.Name()),
+			)
+			name = t.Name()
+		}
+
+		// --- Keep the call within ALLOWED_NAMESPACES and DENIED_NAMESPACES ---
+		ctx, err = tools.ApplyNamespaceScope(ctx, t, args)
+		if err != nil {
+			mcpErr := err.(*types.MCPError)
+			s.recordError(ctx, span, name, mcpErr.Code, err)
+			errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
+				IsError: true,
+			}, nil
+		}
+
+		// --- Tools run by custom skills follow the caller's allowlist ---
+		if request.Extra != nil {
+			if access := auth.AccessFromTokenInfo(request.Extra.TokenInfo); access != nil {
+				ctx = auth.WithToolAccess(ctx, access)
+			}
+		}
+
+		// --- Run as the impersonated identity, if configured ---
+		imp := s.impersonationFor(request)
+		if imp.User != "" {
+			span.SetAttributes(attribute.String("k8s.impersonate.user", imp.User))
+			ctx = k8s.WithImpersonation(ctx, imp)
+		}
+
+		// --- Stream progress of long-running tools to clients that asked for it ---
+		if token := request.Params.GetProgressToken(); token != nil && request.Session != nil {
+			ctx = progress.With(ctx, s.progressNotifier(ctx, request.Session, token))
+		}
+
+		// --- Bound the call by its tool class timeout; cancelling the context
+		// aborts in-flight API calls and probes delete their pods ---
+		callCtx := ctx
+		var timeout time.Duration
+		if policy := s.timeouts.Load(); policy != nil {
+			var class string
+			timeout, class = policy.For(t)
+			span.SetAttributes(attribute.String("mcp.tool.class", class))
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		// --- Execute tool with timing, sharing the outcome of identical calls ---
 		start := time.Now()
-		result, err := t.Run(ctx, args)
+		run, served := s.runTool(callCtx, cluster, t, args)
+		result, err := run.result, run.err
 		duration := time.Since(start).Seconds()
-
-		// --- Record metrics ---
+		if served != callRan {
+			span.SetAttributes(attribute.String("mcp.tool.dedup", served))
+		}
 		if err != nil {
-			errType := "tool_error"
-			if mcpErr, ok := err.(*types.MCPError); ok {
-				errType = mcpErr.Code
+			err = cancellationError(name, err, ctx, callCtx, timeout)
+		}
+
+		// Resource types that failed to list make the results partial
+		if result != nil {
+			result.Deprecation = deprecation
+			if errs := run.listErrs; len(errs) > 0 {
+				result.Errors = errs
+				span.SetAttributes(attribute.Int("mcp.tool.partial_errors", len(errs)))
 			}
-			s.recordMetrics(ctx, t.Name(), errType, duration)
-			s.recordError(ctx, span, t.Name(), errType, err)
+		}
 
-			// Format MCPError consistently if available
-			if mcpErr, ok := err.(*types.MCPError); ok {
-				errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
-				return &mcp.CallToolResult{
-					Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
-					IsError: true,
-				}, nil
+		// --- Record metrics ---
+		if err != nil {
+			// Plain errors are classified so agents know whether to retry
+			mcpErr := tools.ClassifyError(name, err)
+			s.recordMetrics(ctx, name, mcpErr.Code, duration)
+			s.recordError(ctx, span, name, mcpErr.Code, err)
+
+			// A degraded tool likely failed for want of RBAC permissions
+			s.mu.Lock()
+			note := s.degradedNote(cluster, name)
+			s.mu.Unlock()
+
+			if note != "" || deprecation != "" {
+				withNote := *mcpErr
+				for _, n := range []string{note, deprecation} {
+					if n != "" {
+						withNote.Detail = strings.TrimSpace(withNote.Detail + "\n" + n)
+					}
+				}
+				mcpErr = &withNote
 			}
+			errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
 			return &mcp.CallToolResult{
-				Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+				Content: []mcp.Content{&mcp.TextContent{Text: s.minimize(string(errJSON))}},
 				IsError: true,
 			}, nil
 		}
 
 		// Success metrics
-		s.recordMetrics(ctx, t.Name(), "", duration)
+		s.recordMetrics(ctx, name, "", duration)
 		span.SetStatus(codes.Ok, "")
 
-		// Apply compact/detail filtering if the response contains a ToolResult
+		// Apply compact/detail filtering if the response contains a ToolResult,
+		// and redact secret material from finding details
+		redactions := 0
 		if result != nil {
 			if tr, ok := result.Data.(*types.ToolResult); ok {
-				detail := false
+				// Exports keep details and suggestions unless asked not to
+				detail := format != types.OutputFormatText
 				if d, ok := args["detail"]; ok {
 					if b, ok := d.(bool); ok {
 						detail = b
 					}
 				}
+				// Shared results were recorded by the call that ran the tool
+				if served == callRan {
+					s.recordFindingHistory(cluster, t, args, start, tr.Findings)
+				}
+				tr.Findings = s.applySuppressions(ctx, cluster, t, tr.Findings)
 				tr.Findings = types.FilterFindings(tr.Findings, detail)
+				redactions = s.redactFindings(tr.Findings)
 
 				// Record findings metrics
-				s.recordFindings(ctx, t.Name(), tr.Findings)
+				s.recordFindings(ctx, name, tr.Findings)
 			}
 		}
 
-		// Render as compact text for LLM token efficiency
-		resultText := result.ToText()
+		// Render as compact text for LLM token efficiency, within the response
+		// budget, or as a report for CI
+		var rendered string
+		if format == types.OutputFormatText {
+			var tier string
+			rendered, tier = s.fitBudget(result)
+			if tier != tierFull {
+				span.SetAttributes(attribute.String("mcp.tool.response_tier", tier))
+			}
+		} else if rendered, err = renderResult(result, format); err != nil {
+			mcpErr := err.(*types.MCPError)
+			mcpErr.Tool = name
+			s.recordError(ctx, span, name, mcpErr.Code, err)
+			errJSON, _ := json.MarshalIndent(mcpErr, "", "  ")
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(errJSON)}},
+				IsError: true,
+			}, nil
+		}
+		// Raw results, e.g. run_skill output, are redacted as a whole
+		if result != nil {
+			if _, ok := result.Data.(*types.ToolResult); !ok {
+				var n int
+				rendered, n = s.redact(rendered)
+				redactions += n
+			}
+		}
+		if redactions > 0 {
+			span.SetAttributes(attribute.Int("mcp.tool.redactions", redactions))
+		}
+		resultText := s.minimize(rendered)
 
 		// Set truncated result as span attribute
 		resultAttr := resultText
@@ -274,6 +624,54 @@ func (s *Server) buildInstrumentedHandler(t tools.Tool) mcp.ToolHandler {
 	}
 }
 
+// outputFormat returns the output_format argument, defaulting to text.
+func outputFormat(args map[string]interface{}) (string, error) {
+	format, _ := args["output_format"].(string)
+	if format == "" {
+		return types.OutputFormatText, nil
+	}
+	format = strings.ToLower(format)
+	for _, f := range types.OutputFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", &types.MCPError{
+		Code:    types.ErrCodeInvalidInput,
+		Message: fmt.Sprintf("unknown output_format %q", format),
+		Detail:  "expected one of " + strings.Join(types.OutputFormats, ", "),
+	}
+}
+
+// renderResult renders a tool response in format. SARIF and JUnit need a
+// response with findings. Errors are *types.MCPError: INVALID_INPUT for a
+// tool without findings, INTERNAL_ERROR when rendering fails.
+func renderResult(result *tools.StandardResponse, format string) (string, error) {
+	if format == types.OutputFormatText {
+		return result.ToText(), nil
+	}
+	tr, ok := result.Data.(*types.ToolResult)
+	if !ok {
+		return "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Message: fmt.Sprintf("output_format %s needs a tool that returns findings; %s does not", format, result.Tool),
+		}
+	}
+	render := tr.ToJUnit
+	if format == types.OutputFormatSARIF {
+		render = tr.ToSARIF
+	}
+	out, err := render(result.Tool)
+	if err != nil {
+		return "", &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Message: fmt.Sprintf("rendering the result as %s failed", format),
+			Detail:  err.Error(),
+		}
+	}
+	return out, nil
+}
+
 // recordMetrics records GenAI request duration and count metrics.
 func (s *Server) recordMetrics(ctx context.Context, toolName, errType string, duration float64) {
 	if s.meters == nil {
@@ -287,6 +685,20 @@ func (s *Server) recordMetrics(ctx context.Context, toolName, errType string, du
 	}
 	s.meters.RequestDuration.Record(ctx, duration, telemetry.WithAttrs(attrs...))
 	s.meters.RequestCount.Add(ctx, 1, telemetry.WithAttrs(attrs...))
+	s.meters.Stats.RecordToolCall(toolName, errType, duration)
+}
+
+// recordAliasCall counts a call made by a deprecated alias, so operators
+// can tell when an alias is no longer used and can be removed.
+func (s *Server) recordAliasCall(ctx context.Context, alias, toolName string) {
+	slog.Debug("mcp: tool called by deprecated alias", "alias", alias, "tool", toolName)
+	if s.meters == nil {
+		return
+	}
+	s.meters.AliasCalls.Add(ctx, 1, telemetry.WithAttrs(
+		attribute.String("mcp.tool.alias", alias),
+		attribute.String("gen_ai.tool.name", toolName),
+	))
 }
 
 // recordError records error metrics and sets span error status.
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// twoClusterServer serves list_services on "prod", the default cluster, and
+// "staging", where only staging also has quick_scan.
+func twoClusterServer() (*Server, *countingTool, *countingTool) {
+	prod, staging := tools.NewRegistry(), tools.NewRegistry()
+	prodTool, stagingTool := &countingTool{name: "list_services"}, &countingTool{name: "list_services"}
+	prod.Register(prodTool)
+	staging.Register(stagingTool)
+	staging.Register(&countingTool{name: "quick_scan"})
+
+	s := NewServer("prod", prod)
+	s.AddCluster("staging", staging)
+	return s, prodTool, stagingTool
+}
+
+func TestResolveToolCluster(t *testing.T) {
+	s, prodTool, stagingTool := twoClusterServer()
+
+	for _, c := range []struct {
+		args    map[string]interface{}
+		cluster string
+		tool    tools.Tool
+	}{
+		{map[string]interface{}{}, "prod", prodTool},
+		{map[string]interface{}{"cluster": ""}, "prod", prodTool},
+		{map[string]interface{}{"cluster": "prod"}, "prod", prodTool},
+		{map[string]interface{}{"cluster": "staging"}, "staging", stagingTool},
+	} {
+		got, _, cluster, err := s.resolveTool("list_services", c.args)
+		if err != nil || cluster != c.cluster || got != c.tool {
+			t.Errorf("%v: got %s, %v", c.args, cluster, err)
+		}
+	}
+
+	_, _, cluster, err := s.resolveTool("list_services", map[string]interface{}{"cluster": "dev"})
+	var mcpErr *types.MCPError
+	if !errors.As(err, &mcpErr) || cluster != "dev" || mcpErr.Code != types.ErrCodeInvalidInput ||
+		mcpErr.Detail != "configured clusters: prod, staging" {
+		t.Errorf("unknown cluster: %s, %+v", cluster, err)
+	}
+
+	_, _, _, err = s.resolveTool("quick_scan", map[string]interface{}{})
+	if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeCRDNotAvailable {
+		t.Errorf("tool missing on the default cluster: %+v", err)
+	}
+	if _, _, _, err := s.resolveTool("quick_scan", map[string]interface{}{"cluster": "staging"}); err != nil {
+		t.Errorf("quick_scan on staging: %v", err)
+	}
+}
+
+func TestClusterArgSchema(t *testing.T) {
+	single := NewServer("prod", tools.NewRegistry())
+	if schema := single.clusterArgSchema(); schema != nil {
+		t.Errorf("single cluster has a cluster argument: %v", schema)
+	}
+	s, _, _ := twoClusterServer()
+	schema := s.clusterArgSchema()
+	if enum := fmt.Sprint(schema["enum"]); enum != "[prod staging]" {
+		t.Errorf("cluster enum %s", enum)
+	}
+}
+
+func TestResolveToolWhileAddingClusters(t *testing.T) {
+	s, _, _ := twoClusterServer()
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 200; i++ {
+			s.AddCluster(fmt.Sprintf("c%d", i), tools.NewRegistry())
+			runtime.Gosched()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 200; i++ {
+			_, _, _, _ = s.resolveTool("list_services", map[string]interface{}{"cluster": "dev"})
+			runtime.Gosched()
+		}
+	}()
+	close(start)
+	wg.Wait()
+}
+
+type rawTool struct{ countingTool }
+
+func (r *rawTool) Run(context.Context, map[string]interface{}) (*tools.StandardResponse, error) {
+	return &tools.StandardResponse{Tool: r.name, Data: "raw output"}, nil
+}
+
+func TestExportErrorsAreMCPErrors(t *testing.T) {
+	reg := tools.NewRegistry()
+	reg.Register(&rawTool{countingTool{name: "get_raw"}})
+	s := NewServer("prod", reg)
+
+	res, err := s.buildInstrumentedHandler("get_raw")(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Name: "get_raw", Arguments: json.RawMessage(`{"output_format":"sarif"}`)},
+	})
+	if err != nil || !res.IsError {
+		t.Fatalf("got %+v, %v; want an error result", res, err)
+	}
+	var mcpErr types.MCPError
+	if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &mcpErr); err != nil {
+		t.Fatalf("error result is not an MCPError: %v", err)
+	}
+	if mcpErr.Code != types.ErrCodeInvalidInput || mcpErr.Tool != "get_raw" {
+		t.Errorf("got %+v", mcpErr)
+	}
+
+	result := &tools.StandardResponse{Tool: "list_services", Data: &types.ToolResult{}}
+	for _, format := range []string{types.OutputFormatSARIF, types.OutputFormatJUnit} {
+		if _, err := renderResult(result, format); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// EnableToolTimeouts cancels tool calls that run past the timeout of their
+// class. Calling it again replaces the policy for the calls that start
+// afterwards.
+func (s *Server) EnableToolTimeouts(p tools.TimeoutPolicy) {
+	s.timeouts.Store(&p)
+}
+
+// cancellationError reports a call that failed after its context ended as
+// TOOL_TIMEOUT (callCtx hit the class timeout) or CANCELLED (the client
+// cancelled ctx), whatever error the tool returned; other errors are
+// returned unchanged.
+func cancellationError(tool string, err error, ctx, callCtx context.Context, timeout time.Duration) error {
+	switch {
+	case ctx.Err() != nil:
+		return &types.MCPError{
+			Code:    types.ErrCodeCancelled,
+			Tool:    tool,
+			Message: "the call was cancelled before it finished",
+			Detail:  fmt.Sprintf("In-flight API calls were aborted and probe pods deleted (%v)", err),
+		}
+	case errors.Is(callCtx.Err(), context.DeadlineExceeded):
+		return &types.MCPError{
+			Code:    types.ErrCodeToolTimeout,
+			Tool:    tool,
+			Message: fmt.Sprintf("the call did not finish within %s", timeout),
+			Detail:  fmt.Sprintf("Narrow the call (e.g. with namespace), or raise the timeout with TOOL_TIMEOUT, TOOL_TIMEOUT_SCAN, TOOL_TIMEOUT_PROBE or TOOL_TIMEOUTS=%s=<duration> (%v)", tool, err),
+		}
+	}
+	return err
+}
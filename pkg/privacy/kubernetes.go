@@ -0,0 +1,55 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+// KubernetesTerms returns a source listing node names and hostnames plus the
+// external addresses of Services (load balancer hostnames and ExternalName
+// targets). IP addresses are handled separately and need no source.
+func KubernetesTerms(clients *k8s.Clients) TermSource {
+	return func(ctx context.Context) ([]Term, error) {
+		var terms []Term
+		nodes, err := clients.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// Without RBAC on nodes, learn node names from where pods run.
+			pods, perr := clients.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if perr != nil {
+				return nil, fmt.Errorf("failed to list nodes (%v) or pods: %w", err, perr)
+			}
+			for _, p := range pods.Items {
+				terms = append(terms, Term{Value: p.Spec.NodeName, Kind: "node"})
+			}
+			nodes = &corev1.NodeList{}
+		}
+		for _, n := range nodes.Items {
+			terms = append(terms, Term{Value: n.Name, Kind: "node"})
+			for _, a := range n.Status.Addresses {
+				switch a.Type {
+				case corev1.NodeHostName, corev1.NodeInternalDNS, corev1.NodeExternalDNS:
+					terms = append(terms, Term{Value: a.Address, Kind: "host"})
+				}
+			}
+		}
+
+		services, err := clients.Clientset.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		for _, svc := range services.Items {
+			if svc.Spec.Type == corev1.ServiceTypeExternalName {
+				terms = append(terms, Term{Value: svc.Spec.ExternalName, Kind: "host"})
+			}
+			for _, ing := range svc.Status.LoadBalancer.Ingress {
+				terms = append(terms, Term{Value: ing.Hostname, Kind: "host"})
+			}
+		}
+		return terms, nil
+	}
+}
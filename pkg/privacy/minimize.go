@@ -0,0 +1,165 @@
+// Package privacy replaces infrastructure identifiers in tool output with
+// stable pseudonyms, for deployments that send MCP responses to third-party
+// LLM providers (DATA_MINIMIZATION).
+package privacy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// termRefreshInterval is how often term sources are re-read.
+const termRefreshInterval = time.Minute
+
+// Term is an identifier to pseudonymize wherever it appears in output.
+type Term struct {
+	Value string
+	// Kind prefixes the pseudonym, e.g. "node" gives "node-1a2b3c4d".
+	Kind string
+}
+
+// TermSource lists identifiers known to the cluster, such as node names.
+type TermSource func(ctx context.Context) ([]Term, error)
+
+// Minimizer rewrites text so that IP addresses and known identifiers are
+// replaced by pseudonyms. The same input always maps to the same pseudonym
+// for a given salt, so relationships between findings survive.
+type Minimizer struct {
+	key     []byte
+	sources []TermSource
+
+	mu          sync.Mutex
+	terms       []Term // longest first
+	refreshedAt time.Time
+}
+
+// NewMinimizer creates a minimizer. Pseudonyms are derived from salt; an empty
+// salt uses a random one, so pseudonyms are only stable until restart.
+func NewMinimizer(salt string) *Minimizer {
+	key := []byte(salt)
+	if salt == "" {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Minimizer{key: key}
+}
+
+// AddSource registers identifiers to pseudonymize. Must be called before Apply.
+func (m *Minimizer) AddSource(src TermSource) {
+	m.sources = append(m.sources, src)
+}
+
+// ipCandidate matches text that may be an IPv4 or IPv6 address; each match is
+// validated with netip before being replaced.
+var ipCandidate = regexp.MustCompile(`[0-9A-Fa-f:.]*[:.][0-9A-Fa-f:.]*[0-9A-Fa-f]`)
+
+// Apply returns text with IP addresses and known terms pseudonymized.
+func (m *Minimizer) Apply(text string) string {
+	text = ipCandidate.ReplaceAllStringFunc(text, m.replaceIP)
+	for _, t := range m.currentTerms() {
+		text = replaceWord(text, t.Value, m.pseudonym(t.Kind, t.Value))
+	}
+	return text
+}
+
+func (m *Minimizer) replaceIP(s string) string {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		// host:port, e.g. an endpoint address
+		ap, perr := netip.ParseAddrPort(s)
+		if perr != nil || !ap.Addr().Is4() {
+			return s
+		}
+		return m.replaceIP(ap.Addr().String()) + s[strings.LastIndex(s, ":"):]
+	}
+	// Well-known addresses carry no identifying information and are often
+	// what the diagnosis is about (e.g. 169.254.20.10 for NodeLocal DNSCache).
+	if addr.IsLoopback() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return s
+	}
+	kind := "ip-public"
+	if addr.IsPrivate() {
+		kind = "ip-private"
+	}
+	if addr.Is6() && !addr.Is4In6() {
+		kind = strings.Replace(kind, "ip-", "ipv6-", 1)
+	}
+	return m.pseudonym(kind, addr.String())
+}
+
+func (m *Minimizer) pseudonym(kind, value string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(kind + ":" + value))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// currentTerms returns the cached terms, re-reading sources when stale. A
+// failed source keeps the previous terms rather than leaking identifiers.
+func (m *Minimizer) currentTerms() []Term {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sources) == 0 || time.Since(m.refreshedAt) < termRefreshInterval {
+		return m.terms
+	}
+
+	// Sources run with the server's identity and their own deadline, not the
+	// caller's context, which may be impersonated or about to expire.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	seen := make(map[string]bool)
+	var terms []Term
+	for _, src := range m.sources {
+		ts, err := src(ctx)
+		if err != nil {
+			slog.Warn("privacy: failed to refresh identifiers, keeping previous list", "error", err)
+			m.refreshedAt = time.Now()
+			return m.terms
+		}
+		for _, t := range ts {
+			if t.Value != "" && !seen[t.Value] {
+				seen[t.Value] = true
+				terms = append(terms, t)
+			}
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i].Value) > len(terms[j].Value) })
+	m.terms, m.refreshedAt = terms, time.Now()
+	return m.terms
+}
+
+// replaceWord replaces whole-word occurrences of term. A word boundary is any
+// character other than a letter, digit, '-' or '_', so "worker-1" does not
+// match inside "worker-10".
+func replaceWord(text, term, repl string) string {
+	var sb strings.Builder
+	for {
+		i := strings.Index(text, term)
+		if i < 0 {
+			sb.WriteString(text)
+			return sb.String()
+		}
+		end := i + len(term)
+		if (i > 0 && isWordChar(text[i-1])) || (end < len(text) && isWordChar(text[end])) {
+			sb.WriteString(text[:end])
+		} else {
+			sb.WriteString(text[:i])
+			sb.WriteString(repl)
+		}
+		text = text[end:]
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,34 @@
+package privacy
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMinimizer_Apply(t *testing.T) {
+	m := NewMinimizer("salt")
+	m.AddSource(func(context.Context) ([]Term, error) {
+		return []Term{{Value: "worker-1", Kind: "node"}, {Value: "lb.example.com", Kind: "host"}}, nil
+	})
+
+	in := "pod 10.244.1.7 on worker-1 (not worker-10) reached 203.0.113.9:443 via lb.example.com; dns 169.254.20.10, v1.28.3"
+	out := m.Apply(in)
+
+	for _, leaked := range []string{"10.244.1.7", "203.0.113.9", "worker-1 ", "lb.example.com"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("output still contains %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"worker-10", "169.254.20.10", "v1.28.3", ":443", "ip-private-", "ip-public-", "node-", "host-"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("output should contain %q: %s", kept, out)
+		}
+	}
+	if again := m.Apply(in); again != out {
+		t.Errorf("pseudonyms are not stable:\n%s\n%s", out, again)
+	}
+	if other := NewMinimizer("other").Apply("10.244.1.7"); strings.Contains(out, other) {
+		t.Errorf("pseudonyms should depend on the salt")
+	}
+}
@@ -0,0 +1,73 @@
+package privacy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret material removed by Redact.
+const Redacted = "[REDACTED]"
+
+var (
+	// secretField matches a key naming secret material, in JSON, YAML,
+	// header or key=value form, and its value. An auth scheme before the
+	// value (Authorization: Bearer ...) is part of the value.
+	secretField = regexp.MustCompile(`(?i)("?[\w-]*(?:password|passwd|secret|token|api[_-]?key|private[_-]?key|access[_-]?key|credential|authorization|cookie)[\w-]*"?\s*[:=]\s*)((?:(?:bearer|basic)\s+)?(?:"(?:[^"\\]|\\.)*"|'[^']*'|[^\s,;&"'{}\[\]]+))`)
+	// authScheme matches credentials after an auth scheme anywhere, e.g. in a
+	// header value whose name is a separate field.
+	authScheme = regexp.MustCompile(`(?i)\b(bearer|basic)\s+([A-Za-z0-9._~+/-]{8,}=*)`)
+	// jwt matches JSON Web Tokens.
+	jwt = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`)
+	// pemBlock matches PEM certificates and keys, also when newlines are
+	// escaped, and base64-encoded PEM as found in caBundle fields.
+	pemBlock    = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]+)-----[^-]*-----END [A-Z0-9 ]+-----`)
+	pemEncoded  = regexp.MustCompile(`LS0tLS1CRUdJTi[A-Za-z0-9+/]*=*`)
+	quotedValue = regexp.MustCompile(`^(["'])`)
+	// plainValue matches values that carry no secret, e.g. tokenTTL: 300 or
+	// automountServiceAccountToken: false.
+	plainValue = regexp.MustCompile(`(?i)^["']?(?:true|false|null|\d{1,7})["']?$`)
+	// plainWord matches words after "basic" or "bearer" that are prose, as in
+	// "basic authentication", rather than credentials.
+	plainWord = regexp.MustCompile(`^[A-Za-z][a-z]*$`)
+)
+
+// Redact returns text with secret material replaced by [REDACTED]: values of
+// fields naming passwords, tokens, keys, credentials, Secret references,
+// Authorization headers and cookies; bearer and basic credentials; JWTs; and
+// PEM certificates and keys. It also returns the number of redactions.
+func Redact(text string) (string, int) {
+	n := 0
+	text = secretField.ReplaceAllStringFunc(text, func(s string) string {
+		m := secretField.FindStringSubmatch(s)
+		key, value := m[1], m[2]
+		if strings.Contains(value, Redacted) || plainValue.MatchString(value) {
+			return s
+		}
+		n++
+		if q := quotedValue.FindString(value); q != "" {
+			return key + q + Redacted + q
+		}
+		return key + Redacted
+	})
+	text = authScheme.ReplaceAllStringFunc(text, func(s string) string {
+		m := authScheme.FindStringSubmatch(s)
+		if plainWord.MatchString(m[2]) {
+			return s
+		}
+		n++
+		return m[1] + " " + Redacted
+	})
+	text = jwt.ReplaceAllStringFunc(text, func(string) string {
+		n++
+		return Redacted
+	})
+	text = pemBlock.ReplaceAllStringFunc(text, func(s string) string {
+		n++
+		return "[REDACTED " + pemBlock.FindStringSubmatch(s)[1] + "]"
+	})
+	text = pemEncoded.ReplaceAllStringFunc(text, func(string) string {
+		n++
+		return Redacted
+	})
+	return text, n
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -15,6 +16,27 @@ const (
 	cleanupInterval = 60 * time.Second
 )
 
+// LeakedPod is a probe pod the reconciler found past its TTL.
+type LeakedPod struct {
+	Namespace string
+	Name      string
+	ProbeType string
+	Phase     string
+	Age       time.Duration
+	Deleted   bool
+	Error     string
+}
+
+// HygieneReport is the outcome of one reconciliation pass over probe pods.
+type HygieneReport struct {
+	RanAt time.Time
+	// Active is the number of probe pods still within their TTL.
+	Active int
+	Leaked []LeakedPod
+	// Error is set when the pass could not list probe pods.
+	Error string
+}
+
 // cleanupLoop periodically removes orphaned probe pods.
 func (m *Manager) cleanupLoop(ctx context.Context) {
 	ticker := time.NewTicker(cleanupInterval)
@@ -32,41 +54,84 @@ func (m *Manager) cleanupLoop(ctx context.Context) {
 	}
 }
 
-// cleanupOrphans deletes probe pods that have exceeded their TTL.
-func (m *Manager) cleanupOrphans(ctx context.Context) {
-	ns := m.cfg.ProbeNamespace
+// cleanupOrphans deletes probe pods that have exceeded their TTL in every
+// namespace, since probes may run outside PROBE_NAMESPACE.
+func (m *Manager) cleanupOrphans(ctx context.Context) HygieneReport {
+	report := HygieneReport{RanAt: time.Now()}
 
-	pods, err := m.clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+	pods, err := m.clients.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
 		LabelSelector: LabelManagedBy + "=" + LabelManagedByValue,
 	})
 	if err != nil {
-		slog.Warn("probe: cleanup failed to list pods", "namespace", ns, "error", err)
-		return
+		slog.Warn("probe: cleanup failed to list pods", "error", err)
+		report.Error = err.Error()
+		m.recordHygiene(report)
+		return report
 	}
 
-	now := time.Now()
-	cleaned := 0
-
 	for _, pod := range pods.Items {
-		createdAtStr, ok := pod.Annotations[AnnotationCreatedAt]
-		if !ok {
+		age := report.RanAt.Sub(probeCreatedAt(&pod))
+		if age <= probeTTL {
+			report.Active++
 			continue
 		}
-		createdAt, err := time.Parse(time.RFC3339, createdAtStr)
-		if err != nil {
-			slog.Warn("probe: cleanup skipped pod with invalid timestamp", "pod", pod.Name, "annotation", createdAtStr, "error", err)
-			continue
+
+		leak := LeakedPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			ProbeType: pod.Labels[LabelProbeType],
+			Phase:     string(pod.Status.Phase),
+			Age:       age,
 		}
-		if now.Sub(createdAt) > probeTTL {
-			if err := m.clients.Clientset.CoreV1().Pods(ns).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
-				slog.Warn("probe: cleanup failed to delete pod", "pod", pod.Name, "error", err)
-				continue
-			}
-			cleaned++
+		if err := m.clients.Clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+			slog.Warn("probe: cleanup failed to delete pod", "pod", pod.Name, "namespace", pod.Namespace, "error", err)
+			leak.Error = err.Error()
+		} else {
+			leak.Deleted = true
 		}
+		report.Leaked = append(report.Leaked, leak)
 	}
 
-	if cleaned > 0 {
-		slog.Info("probe: cleaned up orphaned pods", "count", cleaned, "namespace", ns)
+	if len(report.Leaked) > 0 {
+		slog.Info("probe: cleaned up orphaned pods", "count", len(report.Leaked))
 	}
+	m.recordHygiene(report)
+	return report
+}
+
+// probeCreatedAt returns when a probe pod was created, preferring the
+// annotation written at creation and falling back to the object timestamp.
+func probeCreatedAt(pod *corev1.Pod) time.Time {
+	if s, ok := pod.Annotations[AnnotationCreatedAt]; ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+		slog.Warn("probe: pod has invalid created-at annotation", "pod", pod.Name, "annotation", s)
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// recordHygiene stores the latest report and counts leaked pods removed.
+func (m *Manager) recordHygiene(report HygieneReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastHygiene = &report
+	for _, leak := range report.Leaked {
+		if leak.Deleted {
+			m.leaksRemoved++
+		}
+	}
+}
+
+// Reconcile runs a cleanup pass immediately and returns its report.
+func (m *Manager) Reconcile(ctx context.Context) HygieneReport {
+	return m.cleanupOrphans(ctx)
+}
+
+// LastHygiene returns the most recent reconciliation report (nil before the
+// first pass) and the number of leaked pods removed since startup.
+func (m *Manager) LastHygiene() (*HygieneReport, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastHygiene, m.leaksRemoved
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
@@ -21,20 +22,29 @@ var probeTracer = otel.Tracer("mcp-k8s-networking.probes")
 
 // Manager handles the lifecycle of ephemeral diagnostic pods.
 type Manager struct {
-	cfg     *config.Config
 	clients *k8s.Clients
 
 	mu       sync.Mutex
+	cfg      *config.Config // replaced by Reconfigure
 	running  int
+	waiters  []chan struct{}        // FIFO queue of callers waiting for a slot
+	recent   map[string][]time.Time // probe start times per namespace within rateWindow
 	stopOnce sync.Once
 	stopCh   chan struct{}
+
+	lastHygiene  *HygieneReport // most recent cleanup pass
+	leaksRemoved int            // leaked probe pods deleted since startup
 }
 
+// rateWindow is the sliding window used for per-namespace probe rate limiting.
+const rateWindow = time.Minute
+
 // NewManager creates a probe manager and starts the orphan cleanup goroutine.
 func NewManager(ctx context.Context, cfg *config.Config, clients *k8s.Clients) *Manager {
 	m := &Manager{
 		cfg:     cfg,
 		clients: clients,
+		recent:  make(map[string][]time.Time),
 		stopCh:  make(chan struct{}),
 	}
 
@@ -47,23 +57,41 @@ func NewManager(ctx context.Context, cfg *config.Config, clients *k8s.Clients) *
 	return m
 }
 
+// probePhases are the progress steps of a probe: deploy, wait, finished.
+const probePhases = 3
+
 // Execute runs a probe by creating an ephemeral pod, waiting for completion, and returning the result.
 func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult, error) {
-	if err := m.acquireSlot(); err != nil {
-		return nil, err
-	}
-	defer m.releaseSlot()
-
 	// Default timeout
 	if req.Timeout == 0 {
 		req.Timeout = 30 * time.Second
 	}
 
+	cfg := m.config()
 	ns := req.Namespace
 	if ns == "" {
-		ns = m.cfg.ProbeNamespace
+		ns = cfg.ProbeNamespace
+	}
+	if ns == cfg.ProbeNamespace {
+		// The probe namespace belongs to the server, whatever the namespace
+		// scope of the call
+		ctx = k8s.WithNamespaceScope(ctx, nil)
 	}
 
+	if err := m.checkRateLimit(ns); err != nil {
+		return nil, err
+	}
+
+	queuedAt := time.Now()
+	position, err := m.acquireSlot(ctx)
+	if err != nil {
+		// A probe that never ran does not count against the rate limit
+		m.unrecordProbe(ns)
+		return nil, err
+	}
+	defer m.releaseSlot()
+	queueWait := time.Since(queuedAt)
+
 	// Start parent span for the entire probe lifecycle
 	ctx, parentSpan := probeTracer.Start(ctx, fmt.Sprintf("probe/%s", req.Type),
 		trace.WithSpanKind(trace.SpanKindInternal),
@@ -71,6 +99,8 @@ func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult,
 			attribute.String("probe.type", string(req.Type)),
 			attribute.String("k8s.namespace", ns),
 			attribute.String("probe.timeout", req.Timeout.String()),
+			attribute.Int("probe.queue_position", position),
+			attribute.String("probe.queue_wait", queueWait.String()),
 		),
 	)
 	defer parentSpan.End()
@@ -82,6 +112,7 @@ func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult,
 	start := time.Now()
 
 	// Deploy: create the pod
+	progress.Report(ctx, 1, probePhases, fmt.Sprintf("deploying %s probe pod in %s", req.Type, ns))
 	podName, err := m.deployProbe(probeCtx, ns, req)
 	if err != nil {
 		parentSpan.RecordError(err)
@@ -97,7 +128,8 @@ func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult,
 	}()
 
 	// Wait + execute: wait for the pod to complete and collect output
-	result, err := m.waitProbe(probeCtx, ns, podName)
+	progress.Report(ctx, 2, probePhases, fmt.Sprintf("probe pod %s/%s created, waiting for its results (timeout %s)", ns, podName, req.Timeout))
+	result, err := m.waitProbe(probeCtx, ns, podName, req.MaxOutputBytes)
 	if err != nil {
 		if probeCtx.Err() != nil {
 			parentSpan.AddEvent("probe.timeout", trace.WithAttributes(
@@ -105,9 +137,11 @@ func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult,
 			))
 			parentSpan.SetStatus(codes.Error, "probe timed out")
 			return &ProbeResult{
-				Success:  false,
-				Error:    "probe timed out",
-				Duration: time.Since(start),
+				Success:       false,
+				Error:         "probe timed out",
+				Duration:      time.Since(start),
+				QueuePosition: position,
+				QueueWait:     queueWait,
 			}, &types.MCPError{
 				Code:    types.ErrCodeProbeTimeout,
 				Message: fmt.Sprintf("probe timed out after %s", req.Timeout),
@@ -119,6 +153,9 @@ func (m *Manager) Execute(ctx context.Context, req ProbeRequest) (*ProbeResult,
 	}
 
 	result.Duration = time.Since(start)
+	progress.Report(ctx, probePhases, probePhases, fmt.Sprintf("probe finished in %s", result.Duration.Round(time.Millisecond)))
+	result.QueuePosition = position
+	result.QueueWait = queueWait
 	parentSpan.SetAttributes(
 		attribute.Bool("probe.success", result.Success),
 		attribute.Int("probe.exit_code", result.ExitCode),
@@ -137,7 +174,7 @@ func (m *Manager) deployProbe(ctx context.Context, ns string, req ProbeRequest)
 	)
 	defer span.End()
 
-	podName, err := createProbePod(ctx, m.clients, m.cfg, ns, req)
+	podName, err := createProbePod(ctx, m.clients, m.config(), ns, req)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -149,13 +186,13 @@ func (m *Manager) deployProbe(ctx context.Context, ns string, req ProbeRequest)
 }
 
 // waitProbe waits for the probe pod to complete with a child span.
-func (m *Manager) waitProbe(ctx context.Context, ns, podName string) (*ProbeResult, error) {
+func (m *Manager) waitProbe(ctx context.Context, ns, podName string, maxOutput int) (*ProbeResult, error) {
 	ctx, span := probeTracer.Start(ctx, "probe/wait",
 		trace.WithSpanKind(trace.SpanKindInternal),
 	)
 	defer span.End()
 
-	result, err := waitForPod(ctx, m.clients, ns, podName)
+	result, err := waitForPod(ctx, m.clients, ns, podName, maxOutput)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -191,32 +228,143 @@ func (m *Manager) cleanupProbe(ctx context.Context, ns, podName string) {
 	}
 }
 
-// acquireSlot checks and reserves a concurrency slot.
-func (m *Manager) acquireSlot() error {
+// acquireSlot reserves a concurrency slot, waiting in a FIFO queue when all slots are busy.
+// It returns the caller's initial queue position (0 when a slot was free immediately).
+// When the queue is full the call fails fast with PROBE_LIMIT_REACHED.
+func (m *Manager) acquireSlot(ctx context.Context) (int, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.running >= m.cfg.MaxConcurrentProbes {
-		return &types.MCPError{
+	if m.running < m.cfg.MaxConcurrentProbes && len(m.waiters) == 0 {
+		m.running++
+		slog.Debug("probe: acquired slot", "running", m.running, "max", m.cfg.MaxConcurrentProbes)
+		m.mu.Unlock()
+		return 0, nil
+	}
+	if len(m.waiters) >= m.cfg.ProbeQueueSize {
+		running, queued, cfg := m.running, len(m.waiters), m.cfg
+		m.mu.Unlock()
+		return 0, &types.MCPError{
 			Code:    types.ErrCodeProbeLimitReached,
-			Message: fmt.Sprintf("concurrent probe limit reached (%d/%d)", m.running, m.cfg.MaxConcurrentProbes),
+			Message: fmt.Sprintf("concurrent probe limit reached (%d/%d) and queue is full (%d/%d)", running, cfg.MaxConcurrentProbes, queued, cfg.ProbeQueueSize),
 		}
 	}
-	m.running++
-	slog.Debug("probe: acquired slot", "running", m.running, "max", m.cfg.MaxConcurrentProbes)
-	return nil
+	ch := make(chan struct{})
+	m.waiters = append(m.waiters, ch)
+	position, maxProbes := len(m.waiters), m.cfg.MaxConcurrentProbes
+	m.mu.Unlock()
+
+	slog.Debug("probe: queued for slot", "position", position)
+	progress.Report(ctx, 0, probePhases, fmt.Sprintf("queued for a probe slot at position %d (all %d slots busy)", position, maxProbes))
+
+	select {
+	case <-ch:
+		// releaseSlot handed its slot over to us; running is unchanged.
+		return position, nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, w := range m.waiters {
+			if w == ch {
+				m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+				return position, ctx.Err()
+			}
+		}
+		// The slot was handed over concurrently with cancellation; give it back.
+		m.releaseSlotLocked()
+		return position, ctx.Err()
+	}
 }
 
-// releaseSlot frees a concurrency slot.
+// releaseSlot frees a concurrency slot, handing it to the next queued caller if any.
 func (m *Manager) releaseSlot() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.releaseSlotLocked()
+}
+
+func (m *Manager) releaseSlotLocked() {
+	if len(m.waiters) > 0 && m.running <= m.cfg.MaxConcurrentProbes {
+		next := m.waiters[0]
+		m.waiters = m.waiters[1:]
+		close(next)
+		slog.Debug("probe: handed slot to queued probe", "running", m.running, "queued", len(m.waiters))
+		return
+	}
 	if m.running > 0 {
 		m.running--
 	}
 	slog.Debug("probe: released slot", "running", m.running)
 }
 
+// checkRateLimit enforces the per-namespace probe rate limit over a sliding window
+// and records the attempt when allowed.
+func (m *Manager) checkRateLimit(ns string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg.ProbeRateLimit <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-rateWindow)
+	kept := m.recent[ns][:0]
+	for _, t := range m.recent[ns] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+
+	if len(kept) >= m.cfg.ProbeRateLimit {
+		m.recent[ns] = kept
+		retryAfter := kept[0].Add(rateWindow).Sub(now).Round(time.Second)
+		return &types.MCPError{
+			Code:    types.ErrCodeProbeRateLimited,
+			Message: fmt.Sprintf("probe rate limit reached for namespace %s (%d per %s)", ns, m.cfg.ProbeRateLimit, rateWindow),
+			Detail:  fmt.Sprintf("retry after %s", retryAfter),
+		}
+	}
+	m.recent[ns] = append(kept, now)
+	return nil
+}
+
+// unrecordProbe takes back the latest probe checkRateLimit recorded in ns.
+func (m *Manager) unrecordProbe(ns string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n := len(m.recent[ns]); n > 0 {
+		m.recent[ns] = m.recent[ns][:n-1]
+	}
+}
+
+// config returns the current probe settings.
+func (m *Manager) config() *config.Config {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg
+}
+
+// Reconfigure applies new probe settings to the probes started from now on:
+// the image and the concurrency, queue and rate limits. Queued probes start
+// when the concurrency limit grows; running probes are left alone when it
+// shrinks.
+func (m *Manager) Reconfigure(cfg *config.Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = cfg
+	for m.running < cfg.MaxConcurrentProbes && len(m.waiters) > 0 {
+		next := m.waiters[0]
+		m.waiters = m.waiters[1:]
+		m.running++
+		close(next)
+	}
+}
+
+// QueueStatus returns the number of running probes and queued callers.
+func (m *Manager) QueueStatus() (running, queued int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running, len(m.waiters)
+}
+
 // Stop signals the cleanup goroutine to exit.
 func (m *Manager) Stop() {
 	m.stopOnce.Do(func() {
@@ -0,0 +1,251 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func newTestManager(maxProbes, queueSize, rateLimit int) *Manager {
+	return &Manager{
+		cfg: &config.Config{
+			MaxConcurrentProbes: maxProbes,
+			ProbeQueueSize:      queueSize,
+			ProbeRateLimit:      rateLimit,
+		},
+		recent: make(map[string][]time.Time),
+		stopCh: make(chan struct{}),
+	}
+}
+
+func TestAcquireSlot_ImmediateWhenFree(t *testing.T) {
+	m := newTestManager(1, 1, 0)
+	pos, err := m.acquireSlot(context.Background())
+	if err != nil || pos != 0 {
+		t.Fatalf("acquireSlot() = %d, %v; want 0, nil", pos, err)
+	}
+	if running, queued := m.QueueStatus(); running != 1 || queued != 0 {
+		t.Errorf("QueueStatus() = %d, %d; want 1, 0", running, queued)
+	}
+}
+
+func TestAcquireSlot_QueuesAndHandsOver(t *testing.T) {
+	m := newTestManager(1, 2, 0)
+	if _, err := m.acquireSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan int, 1)
+	go func() {
+		pos, err := m.acquireSlot(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		done <- pos
+	}()
+
+	// Wait until the second caller is queued
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, queued := m.QueueStatus(); queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("caller was never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	m.releaseSlot()
+	select {
+	case pos := <-done:
+		if pos != 1 {
+			t.Errorf("queue position = %d, want 1", pos)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued caller did not receive the slot")
+	}
+	if running, queued := m.QueueStatus(); running != 1 || queued != 0 {
+		t.Errorf("QueueStatus() = %d, %d; want 1, 0", running, queued)
+	}
+}
+
+func TestAcquireSlot_QueueFull(t *testing.T) {
+	m := newTestManager(1, 0, 0)
+	if _, err := m.acquireSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	_, err := m.acquireSlot(context.Background())
+	var mcpErr *types.MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeProbeLimitReached {
+		t.Fatalf("expected PROBE_LIMIT_REACHED, got %v", err)
+	}
+}
+
+func TestAcquireSlot_CancelledWhileQueued(t *testing.T) {
+	m := newTestManager(1, 1, 0)
+	if _, err := m.acquireSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.acquireSlot(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if _, queued := m.QueueStatus(); queued != 0 {
+		t.Errorf("cancelled caller should be removed from queue, queued=%d", queued)
+	}
+}
+
+func TestReconfigure_ResizesSlots(t *testing.T) {
+	m := newTestManager(1, 1, 0)
+	if _, err := m.acquireSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.acquireSlot(context.Background())
+		done <- err
+	}()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, queued := m.QueueStatus(); queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("caller was never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A larger limit starts the queued caller at once.
+	m.Reconfigure(&config.Config{MaxConcurrentProbes: 2, ProbeQueueSize: 1})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued caller did not get the new slot")
+	}
+	if running, queued := m.QueueStatus(); running != 2 || queued != 0 {
+		t.Errorf("QueueStatus() = %d, %d; want 2, 0", running, queued)
+	}
+
+	// A smaller limit lets running probes finish without handing over
+	// their slots.
+	m.Reconfigure(&config.Config{MaxConcurrentProbes: 1, ProbeQueueSize: 1})
+	m.releaseSlot()
+	if running, _ := m.QueueStatus(); running != 1 {
+		t.Errorf("running = %d after release, want 1", running)
+	}
+}
+
+func TestCheckRateLimit_PerNamespace(t *testing.T) {
+	m := newTestManager(5, 5, 2)
+	for i := 0; i < 2; i++ {
+		if err := m.checkRateLimit("a"); err != nil {
+			t.Fatalf("probe %d unexpectedly limited: %v", i, err)
+		}
+	}
+	err := m.checkRateLimit("a")
+	var mcpErr *types.MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeProbeRateLimited {
+		t.Fatalf("expected PROBE_RATE_LIMITED, got %v", err)
+	}
+	if err := m.checkRateLimit("b"); err != nil {
+		t.Errorf("namespace b should have its own budget: %v", err)
+	}
+}
+
+func TestExecute_RejectedProbesDoNotCountAgainstRateLimit(t *testing.T) {
+	m := newTestManager(1, 1, 2)
+	if _, err := m.acquireSlot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// A caller waits in the only queue slot, so later probes are rejected.
+	queued := make(chan error)
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	go func() {
+		_, err := m.Execute(queuedCtx, ProbeRequest{Namespace: "a"})
+		queued <- err
+	}()
+	for {
+		if _, n := m.QueueStatus(); n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := m.Execute(context.Background(), ProbeRequest{Namespace: "a"})
+		var mcpErr *types.MCPError
+		if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeProbeLimitReached {
+			t.Fatalf("probe %d: expected PROBE_LIMIT_REACHED, got %v", i, err)
+		}
+	}
+	cancelQueued()
+	if err := <-queued; !errors.Is(err, context.Canceled) {
+		t.Fatalf("queued probe: expected context.Canceled, got %v", err)
+	}
+
+	// Neither the rejected nor the cancelled probes used up the budget.
+	for i := 0; i < 2; i++ {
+		if err := m.checkRateLimit("a"); err != nil {
+			t.Fatalf("probe %d limited after rejected probes: %v", i, err)
+		}
+	}
+}
+
+func TestCheckRateLimit_Disabled(t *testing.T) {
+	m := newTestManager(5, 5, 0)
+	for i := 0; i < 100; i++ {
+		if err := m.checkRateLimit("a"); err != nil {
+			t.Fatalf("rate limit disabled but got %v", err)
+		}
+	}
+}
+
+func probePod(ns, name string, age time.Duration) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: ns,
+		Name:      name,
+		Labels:    map[string]string{LabelManagedBy: LabelManagedByValue, LabelProbeType: "dns"},
+		Annotations: map[string]string{
+			AnnotationCreatedAt: time.Now().Add(-age).UTC().Format(time.RFC3339),
+		},
+	}}
+}
+
+func TestCleanupOrphans_AllNamespaces(t *testing.T) {
+	cs := fake.NewSimpleClientset(
+		probePod("mcp-diagnostics", "fresh", time.Minute),
+		probePod("team-a", "leaked", time.Hour),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app"}},
+	)
+	m := newTestManager(1, 1, 0)
+	m.clients = &k8s.Clients{Clientset: cs}
+
+	report := m.Reconcile(context.Background())
+	if report.Active != 1 || len(report.Leaked) != 1 {
+		t.Fatalf("report = %+v; want 1 active, 1 leaked", report)
+	}
+	if leak := report.Leaked[0]; leak.Namespace != "team-a" || leak.Name != "leaked" || !leak.Deleted {
+		t.Errorf("unexpected leak: %+v", leak)
+	}
+	if _, err := cs.CoreV1().Pods("team-a").Get(context.Background(), "app", metav1.GetOptions{}); err != nil {
+		t.Errorf("non-probe pod should be untouched: %v", err)
+	}
+	if _, removed := m.LastHygiene(); removed != 1 {
+		t.Errorf("LastHygiene() removed = %d, want 1", removed)
+	}
+}
@@ -60,6 +60,7 @@ func createProbePod(ctx context.Context, clients *k8s.Clients, cfg *config.Confi
 		},
 		Spec: corev1.PodSpec{
 			RestartPolicy: corev1.RestartPolicyNever,
+			NodeName:      req.NodeName,
 			Containers: []corev1.Container{
 				{
 					Name:    "probe",
@@ -93,6 +94,17 @@ func createProbePod(ctx context.Context, clients *k8s.Clients, cfg *config.Confi
 		},
 	}
 
+	if req.Privileged {
+		var rootUser int64
+		pod.Spec.HostNetwork = true
+		pod.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		sc := pod.Spec.Containers[0].SecurityContext
+		sc.RunAsNonRoot = &falseVal
+		sc.RunAsUser = &rootUser
+		sc.Capabilities.Add = []corev1.Capability{"NET_RAW", "NET_ADMIN"}
+		pod.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("128Mi")
+	}
+
 	created, err := clients.Clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return "", err
@@ -108,7 +120,7 @@ func deleteProbePod(ctx context.Context, clients *k8s.Clients, namespace, podNam
 }
 
 // waitForPod watches the pod until it reaches a terminal state and collects logs.
-func waitForPod(ctx context.Context, clients *k8s.Clients, namespace, podName string) (*ProbeResult, error) {
+func waitForPod(ctx context.Context, clients *k8s.Clients, namespace, podName string, maxOutput int) (*ProbeResult, error) {
 	watcher, err := clients.Clientset.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("metadata.name=%s", podName),
 	})
@@ -136,14 +148,14 @@ func waitForPod(ctx context.Context, clients *k8s.Clients, namespace, podName st
 
 			switch pod.Status.Phase {
 			case corev1.PodSucceeded:
-				output := collectLogs(ctx, clients, namespace, podName)
+				output := collectLogs(ctx, clients, namespace, podName, maxOutput)
 				return &ProbeResult{
 					Success:  true,
 					Output:   output,
 					ExitCode: 0,
 				}, nil
 			case corev1.PodFailed:
-				output := collectLogs(ctx, clients, namespace, podName)
+				output := collectLogs(ctx, clients, namespace, podName, maxOutput)
 				exitCode := 1
 				if len(pod.Status.ContainerStatuses) > 0 {
 					if terminated := pod.Status.ContainerStatuses[0].State.Terminated; terminated != nil {
@@ -161,8 +173,9 @@ func waitForPod(ctx context.Context, clients *k8s.Clients, namespace, podName st
 	}
 }
 
-// collectLogs retrieves the logs from the probe container.
-func collectLogs(ctx context.Context, clients *k8s.Clients, namespace, podName string) string {
+// collectLogs retrieves up to maxOutput bytes (64KB when 0) of logs from the
+// probe container.
+func collectLogs(ctx context.Context, clients *k8s.Clients, namespace, podName string, maxOutput int) string {
 	logReq := clients.Clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
 		Container: "probe",
 	})
@@ -173,9 +186,11 @@ func collectLogs(ctx context.Context, clients *k8s.Clients, namespace, podName s
 	}
 	defer func() { _ = stream.Close() }()
 
+	if maxOutput <= 0 {
+		maxOutput = 64 * 1024
+	}
 	var buf bytes.Buffer
-	// Limit log output to 64KB
-	if _, err := io.Copy(&buf, io.LimitReader(stream, 64*1024)); err != nil {
+	if _, err := io.Copy(&buf, io.LimitReader(stream, int64(maxOutput))); err != nil {
 		slog.Warn("probe: error reading logs", "pod", podName, "error", err)
 	}
 	return buf.String()
@@ -6,17 +6,33 @@ import "time"
 type ProbeType string
 
 const (
-	ProbeTypeConnectivity ProbeType = "connectivity"
-	ProbeTypeDNS          ProbeType = "dns"
-	ProbeTypeHTTP         ProbeType = "http"
+	ProbeTypeConnectivity     ProbeType = "connectivity"
+	ProbeTypeDNS              ProbeType = "dns"
+	ProbeTypeHTTP             ProbeType = "http"
+	ProbeTypeLatency          ProbeType = "latency"
+	ProbeTypeMTU              ProbeType = "mtu"
+	ProbeTypeIsolation        ProbeType = "isolation"
+	ProbeTypeTraffic          ProbeType = "traffic-policy"
+	ProbeTypeCapture          ProbeType = "capture"
+	ProbeTypeConnections      ProbeType = "connections"
+	ProbeTypeRouteProgramming ProbeType = "route-programming"
+	ProbeTypeRoutes           ProbeType = "routes"
 )
 
 // ProbeRequest defines the parameters for launching an ephemeral probe pod.
 type ProbeRequest struct {
 	Type      ProbeType
 	Namespace string // source namespace where the probe pod runs
+	NodeName  string // node to run the probe pod on; empty lets the scheduler pick
 	Command   []string
 	Timeout   time.Duration
+
+	// Privileged runs the probe as root on the node's host network with
+	// NET_RAW and NET_ADMIN, as packet captures need; the probe namespace
+	// must then admit privileged pods.
+	Privileged bool
+	// MaxOutputBytes caps the logs collected from the probe; 0 means 64KB.
+	MaxOutputBytes int
 }
 
 // ProbeResult holds the outcome of a probe execution.
@@ -26,6 +42,11 @@ type ProbeResult struct {
 	ExitCode int
 	Duration time.Duration
 	Error    string
+
+	// QueuePosition is the position the probe held in the wait queue (0 = ran immediately).
+	QueuePosition int
+	// QueueWait is how long the probe waited for a concurrency slot.
+	QueueWait time.Duration
 }
 
 const (
@@ -0,0 +1,82 @@
+// Package progress carries progress reports of long-running tool calls, such
+// as probes and cluster-wide scans, from the tools to the MCP client.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Func receives a progress report: done of total units (total is 0 when
+// unknown) and a message for the user.
+type Func func(done, total int, message string)
+
+type contextKey struct{}
+
+// With returns a context whose progress reports go to fn.
+func With(ctx context.Context, fn Func) context.Context {
+	return context.WithValue(ctx, contextKey{}, fn)
+}
+
+// Without returns a context that drops progress reports, for nested work
+// whose progress the caller reports itself.
+func Without(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, Func(nil))
+}
+
+// Report sends a progress report if ctx carries a Func.
+func Report(ctx context.Context, done, total int, message string) {
+	if fn, ok := ctx.Value(contextKey{}).(Func); ok && fn != nil {
+		fn(done, total, message)
+	}
+}
+
+// Enabled reports whether ctx carries a Func, so callers can skip work only
+// needed for progress.
+func Enabled(ctx context.Context) bool {
+	fn, ok := ctx.Value(contextKey{}).(Func)
+	return ok && fn != nil
+}
+
+// Interval is the shortest time between two reports of a Counter.
+const Interval = time.Second
+
+// Counter reports progress through a loop over total items, e.g.
+// "validated 120/500 HTTPRoutes", at most once per Interval and once at the
+// end. It is safe for concurrent use; a nil Counter does nothing.
+type Counter struct {
+	ctx         context.Context
+	verb, unit  string
+	total, done int
+	last        time.Time
+	mu          sync.Mutex
+}
+
+// NewCounter returns a counter of total units, or nil when ctx does not
+// want progress.
+func NewCounter(ctx context.Context, verb string, total int, unit string) *Counter {
+	if !Enabled(ctx) || total <= 0 {
+		return nil
+	}
+	return &Counter{ctx: ctx, verb: verb, unit: unit, total: total, last: time.Now()}
+}
+
+// Add counts n more units done.
+func (c *Counter) Add(n int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.done += n
+	done, now := c.done, time.Now()
+	report := done >= c.total || now.Sub(c.last) >= Interval
+	if report {
+		c.last = now
+	}
+	c.mu.Unlock()
+	if report {
+		Report(c.ctx, done, c.total, fmt.Sprintf("%s %d/%d %s", c.verb, done, c.total, c.unit))
+	}
+}
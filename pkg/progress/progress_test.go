@@ -0,0 +1,49 @@
+package progress
+
+import (
+	"context"
+	"testing"
+)
+
+type report struct {
+	done, total int
+	message     string
+}
+
+func recorder() (context.Context, *[]report) {
+	var reports []report
+	ctx := With(context.Background(), func(done, total int, message string) {
+		reports = append(reports, report{done, total, message})
+	})
+	return ctx, &reports
+}
+
+func TestCounter(t *testing.T) {
+	ctx, reports := recorder()
+	c := NewCounter(ctx, "validated", 3, "HTTPRoutes")
+	c.Add(1)
+	c.Add(1)
+	c.Add(1)
+	// Reports within the interval are throttled, but the last one is sent.
+	if len(*reports) != 1 || (*reports)[0] != (report{3, 3, "validated 3/3 HTTPRoutes"}) {
+		t.Errorf("unexpected reports %+v", *reports)
+	}
+
+	if NewCounter(context.Background(), "validated", 3, "HTTPRoutes") != nil {
+		t.Error("expected no counter without a progress func")
+	}
+	var nilCounter *Counter
+	nilCounter.Add(1)
+}
+
+func TestWithout(t *testing.T) {
+	ctx, reports := recorder()
+	Report(Without(ctx), 1, 2, "nested")
+	if Enabled(Without(ctx)) || len(*reports) != 0 {
+		t.Errorf("expected nested reports to be dropped, got %+v", *reports)
+	}
+	Report(ctx, 1, 2, "step")
+	if len(*reports) != 1 {
+		t.Errorf("expected one report, got %+v", *reports)
+	}
+}
@@ -0,0 +1,148 @@
+// Package prometheus queries a Prometheus-compatible HTTP API (Prometheus,
+// Thanos Query, Mimir) so that tools can back configuration findings with
+// traffic metrics.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSize bounds the size of a query response.
+const maxResponseSize = 10 << 20
+
+// Client runs instant queries against the /api/v1/query endpoint.
+type Client struct {
+	baseURL    string
+	tokenFile  string
+	httpClient *http.Client
+
+	// clusterLabel and cluster scope queries to one cluster when several
+	// clusters share a metrics backend, e.g. Thanos.
+	clusterLabel string
+	cluster      string
+}
+
+// NewClient creates a client for the API at baseURL. When tokenFile is set,
+// its content is sent as a bearer token; it is read on every query so that
+// rotated service account tokens are picked up. When clusterLabel is set,
+// Selector adds clusterLabel="cluster" to every selector.
+func NewClient(baseURL, tokenFile, clusterLabel, cluster string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid Prometheus URL %q: expected http(s)://host[:port][/path]", baseURL)
+	}
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		tokenFile:    tokenFile,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		clusterLabel: clusterLabel,
+		cluster:      cluster,
+	}, nil
+}
+
+// Sample is one series of an instant vector.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Selector builds a series selector from label matchers such as
+// `namespace="shop"`, adding the cluster matcher when configured.
+func (c *Client) Selector(matchers ...string) string {
+	if c.clusterLabel != "" {
+		matchers = append(matchers, fmt.Sprintf("%s=%s", c.clusterLabel, strconv.Quote(c.cluster)))
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+// Equal returns a label matcher for an exact value.
+func Equal(label, value string) string {
+	return label + "=" + strconv.Quote(value)
+}
+
+// Query evaluates expr at the current time and returns its samples, sorted
+// by labels. Only instant vector results are supported.
+func (c *Client) Query(ctx context.Context, expr string) ([]Sample, error) {
+	form := url.Values{"query": {expr}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/query", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Prometheus token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Status    string `json:"status"`
+		ErrorType string `json:"errorType"`
+		Error     string `json:"error"`
+		Data      struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Value  [2]interface{}    `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("prometheus returned HTTP %d with an unreadable body", resp.StatusCode)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", result.ErrorType, result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("prometheus returned a %s, expected a vector", result.Data.ResultType)
+	}
+
+	samples := make([]Sample, 0, len(result.Data.Result))
+	for _, r := range result.Data.Result {
+		s, _ := r.Value[1].(string)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, Sample{Labels: r.Metric, Value: v})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return labelsKey(samples[i].Labels) < labelsKey(samples[j].Labels)
+	})
+	return samples, nil
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + labels[k] + ",")
+	}
+	return b.String()
+}
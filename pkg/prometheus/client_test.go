@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var gotQuery, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prom/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.FormValue("query")
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"code":"503"},"value":[1700000000,"1.5"]},
+			{"metric":{"code":"200"},"value":[1700000000,"10"]}]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/prom/", tokenFile, "cluster", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr := "sum by (code) (rate(http_requests_total" + c.Selector(Equal("namespace", "shop")) + "[5m]))"
+	samples, err := c.Query(context.Background(), expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != `sum by (code) (rate(http_requests_total{namespace="shop", cluster="prod"}[5m]))` {
+		t.Errorf("query = %s", gotQuery)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if len(samples) != 2 || samples[0].Labels["code"] != "200" || samples[1].Value != 1.5 {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	defer srv.Close()
+
+	c, _ := NewClient(srv.URL, "", "", "")
+	if _, err := c.Query(context.Background(), "up{"); err == nil {
+		t.Error("expected an error for a failed query")
+	}
+}
+
+func TestNewClientInvalidURL(t *testing.T) {
+	for _, u := range []string{"", "prometheus:9090", "ftp://prometheus"} {
+		if _, err := NewClient(u, "", "", ""); err == nil {
+			t.Errorf("NewClient(%q): expected an error", u)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+)
+
+// builtin is a Provider assembled from the tools and skills in this repository.
+type builtin struct {
+	name   string
+	detect func(Detection) bool
+	// requires says what detect looks for, for get_capabilities.
+	requires string
+	tools    func(tools.BaseTool) []tools.Tool
+	skills   func(tools.BaseTool) []skills.Skill
+	// health lists tools (by name, from tools) whose findings double as health checks.
+	health []string
+}
+
+func (b *builtin) Name() string            { return b.name }
+func (b *builtin) Detect(d Detection) bool { return b.detect(d) }
+func (b *builtin) Requires() string        { return b.requires }
+
+func (b *builtin) Tools(base tools.BaseTool) []tools.Tool { return b.tools(base) }
+
+func (b *builtin) Skills(base tools.BaseTool) []skills.Skill {
+	if b.skills == nil {
+		return nil
+	}
+	return b.skills(base)
+}
+
+func (b *builtin) HealthChecks(base tools.BaseTool) []HealthCheck {
+	var checks []HealthCheck
+	for _, t := range b.tools(base) {
+		for _, name := range b.health {
+			if t.Name() == name {
+				checks = append(checks, ToolHealthCheck(t))
+			}
+		}
+	}
+	return checks
+}
+
+func init() {
+	Register(&builtin{
+		name:     "gateway-api",
+		requires: "Gateway API CRDs (gateway.networking.k8s.io)",
+		detect:   func(d Detection) bool { return d.Features.HasGatewayAPI },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListGatewaysTool{BaseTool: base},
+				&tools.GetGatewayTool{BaseTool: base},
+				&tools.ListHTTPRoutesTool{BaseTool: base},
+				&tools.GetHTTPRouteTool{BaseTool: base},
+				&tools.ListGRPCRoutesTool{BaseTool: base},
+				&tools.GetGRPCRouteTool{BaseTool: base},
+				&tools.ListReferenceGrantsTool{BaseTool: base},
+				&tools.GetReferenceGrantTool{BaseTool: base},
+				&tools.ScanGatewayMisconfigsTool{BaseTool: base},
+				&tools.CheckGatewayConformanceTool{BaseTool: base},
+				&tools.DetectGatewayImplementationTool{BaseTool: base},
+				&tools.AnalyzeRoutePrecedenceTool{BaseTool: base},
+				&tools.DesignGatewayAPITool{BaseTool: base},
+				&tools.GenerateRouteTelemetryTool{BaseTool: base},
+			}
+		},
+		skills: func(base tools.BaseTool) []skills.Skill {
+			return []skills.Skill{skills.NewExposeServiceSkill(base.Cfg, base.Clients)}
+		},
+	})
+
+	// The experimental channel route kinds are installed separately, so each
+	// registers its tools only when its own CRD is present.
+	Register(&builtin{
+		name:     "gateway-api-tcproute",
+		requires: "the experimental TCPRoute CRD",
+		detect:   func(d Detection) bool { return d.Features.HasTCPRoute },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListTCPRoutesTool{BaseTool: base},
+				&tools.GetTCPRouteTool{BaseTool: base},
+			}
+		},
+	})
+
+	Register(&builtin{
+		name:     "gateway-api-tlsroute",
+		requires: "the experimental TLSRoute CRD",
+		detect:   func(d Detection) bool { return d.Features.HasTLSRoute },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListTLSRoutesTool{BaseTool: base},
+				&tools.GetTLSRouteTool{BaseTool: base},
+			}
+		},
+	})
+
+	Register(&builtin{
+		name:     "gateway-api-udproute",
+		requires: "the experimental UDPRoute CRD",
+		detect:   func(d Detection) bool { return d.Features.HasUDPRoute },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListUDPRoutesTool{BaseTool: base},
+				&tools.GetUDPRouteTool{BaseTool: base},
+			}
+		},
+	})
+
+	Register(&builtin{
+		name:     "gateway-api-backendtlspolicy",
+		requires: "the BackendTLSPolicy CRD",
+		detect:   func(d Detection) bool { return d.Features.HasBackendTLSPolicy },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListBackendTLSPoliciesTool{BaseTool: base},
+			}
+		},
+	})
+
+	Register(&builtin{
+		name:     "istio",
+		requires: "Istio CRDs (networking.istio.io or security.istio.io)",
+		detect:   func(d Detection) bool { return d.Features.HasIstio },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListIstioResourcesTool{BaseTool: base},
+				&tools.GetIstioResourceTool{BaseTool: base},
+				&tools.CheckSidecarInjectionTool{BaseTool: base},
+				&tools.CheckIstioRevisionsTool{BaseTool: base},
+				&tools.CheckIstiodHealthTool{BaseTool: base},
+				&tools.CheckIstioMTLSTool{BaseTool: base},
+				&tools.ValidateIstioConfigTool{BaseTool: base},
+				&tools.AnalyzeEnvoyFiltersTool{BaseTool: base},
+				&tools.AnalyzeIstioAuthPolicyTool{BaseTool: base},
+				&tools.AnalyzeIstioRoutingTool{BaseTool: base},
+				&tools.CheckOutlierDetectionTool{BaseTool: base},
+				&tools.Triage404Tool{BaseTool: base},
+				&tools.DesignIstioTool{BaseTool: base},
+			}
+		},
+		skills: func(base tools.BaseTool) []skills.Skill {
+			return []skills.Skill{skills.NewConfigureMTLSSkill(base.Cfg, base.Clients)}
+		},
+	})
+
+	Register(&builtin{
+		name:     "kgateway",
+		requires: "kgateway CRDs (kgateway.dev)",
+		detect:   func(d Detection) bool { return d.Features.HasKgateway },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListKgatewayResourcesTool{BaseTool: base},
+				&tools.ValidateKgatewayResourceTool{BaseTool: base},
+				&tools.CheckKgatewayHealthTool{BaseTool: base},
+				&tools.DiffKgatewayXDSTool{BaseTool: base},
+				&tools.DesignKgatewayTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_kgateway_health"},
+	})
+
+	Register(&builtin{
+		name:     "gke-gateway",
+		requires: "GKE Gateway policy CRDs (networking.gke.io)",
+		detect:   func(d Detection) bool { return d.Features.HasGKEGateway },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListGKEGatewayPoliciesTool{BaseTool: base},
+				&tools.CheckGKEGatewayStatusTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_gke_gateway_status"},
+	})
+
+	Register(&builtin{
+		name:     "vpc-lattice",
+		requires: "AWS Gateway API Controller CRDs (application-networking.k8s.aws)",
+		detect:   func(d Detection) bool { return d.Features.HasVPCLattice },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListVPCLatticePoliciesTool{BaseTool: base},
+				&tools.CheckVPCLatticeStatusTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_vpc_lattice_status"},
+	})
+
+	Register(&builtin{
+		name:     "appmesh",
+		requires: "AWS App Mesh CRDs (appmesh.k8s.aws)",
+		detect:   func(d Detection) bool { return d.Features.HasAppMesh },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListAppMeshResourcesTool{BaseTool: base},
+				&tools.CheckAppMeshStatusTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_appmesh_status"},
+	})
+
+	Register(&builtin{
+		name:     "gitops",
+		requires: "Argo CD (argoproj.io) or Flux (toolkit.fluxcd.io) CRDs",
+		detect:   func(d Detection) bool { return d.Features.HasArgoCD || d.Features.HasFlux },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListGitopsResourcesTool{BaseTool: base},
+				&tools.CheckGitopsSyncTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_gitops_sync"},
+	})
+
+	Register(&builtin{
+		name:     "canary",
+		requires: "Istio or Gateway API CRDs",
+		detect:   func(d Detection) bool { return d.Features.HasIstio || d.Features.HasGatewayAPI },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.DesignCanaryTool{BaseTool: base},
+				&tools.CheckCanaryTool{BaseTool: base},
+			}
+		},
+	})
+
+	Register(&builtin{
+		name:     "metallb",
+		requires: "MetalLB CRDs (metallb.io)",
+		detect:   func(d Detection) bool { return d.Features.HasMetalLB },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListMetalLBResourcesTool{BaseTool: base},
+				&tools.CheckMetalLBStatusTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_metallb_status"},
+	})
+
+	Register(&builtin{
+		name:     "kuma",
+		requires: "Kuma CRDs (kuma.io)",
+		detect:   func(d Detection) bool { return d.Features.HasKuma },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{&tools.CheckKumaStatusTool{BaseTool: base}}
+		},
+		health: []string{"check_kuma_status"},
+	})
+
+	Register(&builtin{
+		name:     "linkerd",
+		requires: "Linkerd CRDs (linkerd.io)",
+		detect:   func(d Detection) bool { return d.Features.HasLinkerd },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{&tools.CheckLinkerdStatusTool{BaseTool: base}}
+		},
+		health: []string{"check_linkerd_status"},
+	})
+
+	Register(&builtin{
+		name:     "cilium",
+		requires: "Cilium CRDs (cilium.io)",
+		detect:   func(d Detection) bool { return d.Features.HasCilium },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListCiliumPoliciesTool{BaseTool: base},
+				&tools.CheckCiliumStatusTool{BaseTool: base},
+				&tools.GetCiliumPolicyTool{BaseTool: base},
+				&tools.ValidateCiliumPolicyTool{BaseTool: base},
+				&tools.CheckCiliumClusterMeshTool{BaseTool: base},
+				&tools.ValidateCiliumEgressGatewayTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_cilium_status"},
+	})
+
+	Register(&builtin{
+		name:     "calico",
+		requires: "Calico CRDs (crd.projectcalico.org)",
+		detect:   func(d Detection) bool { return d.Features.HasCalico },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.ListCalicoPoliciesTool{BaseTool: base},
+				&tools.CheckCalicoStatusTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_calico_status"},
+	})
+
+	Register(&builtin{
+		name:     "flannel",
+		requires: "a DaemonSet labelled app=flannel",
+		detect:   func(d Detection) bool { return d.Features.HasFlannel },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{&tools.CheckFlannelStatusTool{BaseTool: base}}
+		},
+		health: []string{"check_flannel_status"},
+	})
+
+	Register(&builtin{
+		name:     "nodelocal-dns",
+		requires: "a DaemonSet labelled k8s-app=node-local-dns",
+		detect:   func(d Detection) bool { return d.Features.HasNodeLocalDNS },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{&tools.CheckNodeLocalDNSTool{BaseTool: base}}
+		},
+		health: []string{"check_nodelocal_dns"},
+	})
+
+	Register(&builtin{
+		name:     "ingress-nginx",
+		requires: "an IngressClass with controller k8s.io/ingress-nginx",
+		detect:   func(d Detection) bool { return d.Features.HasIngressNginx },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{
+				&tools.GetIngressNginxConfigTool{BaseTool: base},
+				&tools.CheckIngressNginxTool{BaseTool: base},
+			}
+		},
+		health: []string{"check_ingress_nginx"},
+	})
+
+	Register(&builtin{
+		name:     "external-dns",
+		requires: "a Deployment labelled app.kubernetes.io/name=external-dns",
+		detect:   func(d Detection) bool { return d.Features.HasExternalDNS },
+		tools: func(base tools.BaseTool) []tools.Tool {
+			return []tools.Tool{&tools.CheckExternalDNSTool{BaseTool: base}}
+		},
+		health: []string{"check_external_dns"},
+	})
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/discovery"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// configuredTools are the tools the server registers only when configured
+// to, with the setting that enables them.
+var configuredTools = []struct {
+	tools    []string
+	requires string
+}{
+	{[]string{"query_service_traffic", "check_error_rate"}, "PROMETHEUS_URL"},
+	{[]string{"find_failing_traces"}, "TRACES_URL"},
+	{[]string{"capture_traffic", "inspect_connections"}, "PRIVILEGED_PROBES=true"},
+	{[]string{"suppress_finding", "list_suppressions"}, "SUPPRESSIONS_CONFIGMAP"},
+	{[]string{"get_config_timeline", "diff_snapshots"}, "a CONFIG_HISTORY_INTERVAL above 0"},
+	{[]string{"get_finding_history", "get_finding_trends"}, "a FINDING_HISTORY_RETENTION above 0"},
+	{[]string{"list_scheduled_skills", "get_last_run_results"}, "a SKILL_SCHEDULE_FILE with a schedule for this cluster"},
+}
+
+// featureNames lists the detected and undetected features, without their
+// Has prefix, e.g. "GatewayAPI".
+func featureNames(f discovery.Features) (detected, missing []string) {
+	v := reflect.ValueOf(f)
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.TrimPrefix(v.Type().Field(i).Name, "Has")
+		if v.Field(i).Bool() {
+			detected = append(detected, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return detected, missing
+}
+
+// registeredToolNames returns the names of the tools in registry.
+func registeredToolNames(registry *tools.Registry) map[string]bool {
+	names := make(map[string]bool)
+	for _, t := range registry.List() {
+		names[t.Name()] = true
+	}
+	return names
+}
+
+// capabilityFindings reports the detected features, each provider with the
+// tools it contributes or why it is inactive, the tools that need a setting,
+// and the active tools RBAC degrades.
+func capabilityFindings(m *Manager, d discovery.Features, scannedAt time.Time) []types.DiagnosticFinding {
+	registered := registeredToolNames(m.tools)
+	detected, missing := featureNames(d)
+	active := m.Active()
+	enabled := make(map[string]bool, len(active))
+	for _, name := range active {
+		enabled[name] = true
+	}
+
+	scan := "discovery has not completed a scan yet"
+	if !scannedAt.IsZero() {
+		scan = "discovery last scanned at " + scannedAt.UTC().Format(time.RFC3339)
+	}
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryMesh,
+		Summary:  fmt.Sprintf("%d tools registered, %d of %d providers active; %s", len(registered), len(active), len(Providers()), scan),
+		Detail:   fmt.Sprintf("Detected: %s\nNot detected: %s", orNone(detected), orNone(missing)),
+	}}
+
+	for _, p := range Providers() {
+		var names []string
+		for _, t := range p.Tools(m.base) {
+			names = append(names, t.Name())
+		}
+		detail := "Tools: " + orNone(names)
+		if enabled[p.Name()] {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryMesh,
+				Summary:  fmt.Sprintf("Provider %s is active with %d tools", p.Name(), len(names)),
+				Detail:   detail,
+			})
+			continue
+		}
+		reason := "it was not detected"
+		if r, ok := p.(Requirer); ok && r.Requires() != "" {
+			reason = "it needs " + r.Requires()
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryMesh,
+			Summary:    fmt.Sprintf("Provider %s is inactive: %s", p.Name(), reason),
+			Detail:     detail,
+			Suggestion: "Run refresh_capabilities after installing it, rather than waiting for discovery to notice.",
+		})
+	}
+
+	for _, c := range configuredTools {
+		var off []string
+		for _, name := range c.tools {
+			if !registered[name] {
+				off = append(off, name)
+			}
+		}
+		if len(off) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryMesh,
+				Summary:  fmt.Sprintf("%s inactive: the server needs %s", strings.Join(off, ", "), c.requires),
+			})
+		}
+	}
+
+	var degraded []string
+	for name := range registered {
+		if missing := m.tools.Degraded(name); len(missing) > 0 {
+			degraded = append(degraded, fmt.Sprintf("%s: %s", name, tools.DegradedNote(missing)))
+		}
+	}
+	if len(degraded) > 0 {
+		sort.Strings(degraded)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Summary:    fmt.Sprintf("%d active tools lack RBAC permissions and return partial results", len(degraded)),
+			Detail:     strings.Join(degraded, "\n"),
+			Suggestion: "Run check_permissions for the rules to grant.",
+		})
+	}
+	return findings
+}
+
+func orNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// --- get_capabilities ---
+
+// GetCapabilitiesTool reports what discovery detected and which tools it
+// enabled.
+type GetCapabilitiesTool struct {
+	tools.BaseTool
+	Manager   *Manager
+	Discovery *discovery.Discovery
+}
+
+func (t *GetCapabilitiesTool) Name() string { return "get_capabilities" }
+func (t *GetCapabilitiesTool) Description() string {
+	return "Report the networking features CRD discovery detected, which providers are active and the tools they contribute, why inactive providers and configuration-dependent tools are unavailable, and which active tools RBAC degrades"
+}
+func (t *GetCapabilitiesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *GetCapabilitiesTool) Run(ctx context.Context, args map[string]interface{}) (*tools.StandardResponse, error) {
+	findings := capabilityFindings(t.Manager, t.Discovery.GetFeatures(), t.Discovery.ScannedAt())
+	return tools.NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+// --- refresh_capabilities ---
+
+// RefreshCapabilitiesTool runs discovery immediately, so that tools for a
+// newly installed provider appear without waiting.
+type RefreshCapabilitiesTool struct {
+	tools.BaseTool
+	Manager   *Manager
+	Discovery *discovery.Discovery
+}
+
+func (t *RefreshCapabilitiesTool) Name() string { return "refresh_capabilities" }
+func (t *RefreshCapabilitiesTool) Description() string {
+	return "Rescan CRDs and networking workloads now instead of waiting for discovery, enabling the tools of newly installed providers (e.g. after installing Istio) and removing those of uninstalled ones; reports the tools added and removed"
+}
+func (t *RefreshCapabilitiesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *RefreshCapabilitiesTool) Run(ctx context.Context, args map[string]interface{}) (*tools.StandardResponse, error) {
+	before := registeredToolNames(t.Manager.tools)
+	changed, err := t.Discovery.Refresh(ctx)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to list CustomResourceDefinitions",
+			Detail:  err.Error(),
+		}
+	}
+	after := registeredToolNames(t.Manager.tools)
+
+	var added, removed []string
+	for name := range after {
+		if !before[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range before {
+		if !after[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryMesh,
+		Detail:   fmt.Sprintf("Active providers: %s", orNone(t.Manager.Active())),
+	}
+	switch {
+	case !changed:
+		f.Summary = "Discovery refreshed: the detected features did not change"
+	default:
+		f.Summary = fmt.Sprintf("Discovery refreshed: %d tools added, %d removed", len(added), len(removed))
+		f.Detail = fmt.Sprintf("Added: %s\nRemoved: %s\n%s", orNone(added), orNone(removed), f.Detail)
+	}
+	return tools.NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{f}, "", ""), nil
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestCapabilityFindings(t *testing.T) {
+	toolReg := tools.NewRegistry()
+	m := NewManager(tools.BaseTool{}, toolReg, skills.NewRegistry())
+	d := Detection{}
+	d.Features.HasIstio = true
+	m.Sync(d)
+
+	findings := capabilityFindings(m, d.Features, time.Time{})
+	summary := findings[0]
+	if !strings.Contains(summary.Summary, "has not completed a scan") {
+		t.Errorf("summary = %q", summary.Summary)
+	}
+	if !strings.Contains(summary.Detail, "Detected: Istio\n") || !strings.Contains(summary.Detail, "GatewayAPI") {
+		t.Errorf("summary detail = %q", summary.Detail)
+	}
+
+	byProvider := make(map[string]types.DiagnosticFinding)
+	var configured []string
+	for _, f := range findings[1:] {
+		switch {
+		case strings.HasPrefix(f.Summary, "Provider "):
+			byProvider[strings.Fields(f.Summary)[1]] = f
+		default:
+			configured = append(configured, f.Summary)
+		}
+	}
+	if f := byProvider["istio"]; f.Severity != types.SeverityOK || !strings.Contains(f.Detail, "validate_istio_config") {
+		t.Errorf("istio = %+v", f)
+	}
+	if f := byProvider["gateway-api"]; f.Severity != types.SeverityInfo || !strings.Contains(f.Summary, "it needs ") {
+		t.Errorf("gateway-api = %+v", f)
+	}
+	if len(configured) != len(configuredTools) {
+		t.Errorf("configuration findings = %v", configured)
+	}
+
+	toolReg.Register(&fakeTool{name: "find_failing_traces"})
+	for _, f := range capabilityFindings(m, d.Features, time.Now()) {
+		if strings.Contains(f.Summary, "find_failing_traces") {
+			t.Errorf("registered tool reported inactive: %s", f.Summary)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// enabledTools returns the tool names of enabled providers keyed by provider name.
+func (m *Manager) enabledTools() map[string][]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]string, len(m.enabled))
+	for name, a := range m.enabled {
+		out[name] = append([]string(nil), a.tools...)
+	}
+	return out
+}
+
+// panelQuery is one Prometheus query of a dashboard panel.
+type panelQuery struct {
+	Expr   string
+	Legend string
+}
+
+// dashboardPanel is a panel before layout.
+type dashboardPanel struct {
+	Title       string
+	Description string
+	Type        string // timeseries, stat or bargauge
+	Unit        string
+	Queries     []panelQuery
+}
+
+// providerPanels are the provider's own data plane metrics, added to the
+// findings panel of providers that export well-known Prometheus metrics.
+var providerPanels = map[string][]dashboardPanel{
+	"istio": {
+		{
+			Title:   "Mesh 5xx ratio by destination",
+			Type:    "timeseries",
+			Unit:    "percentunit",
+			Queries: []panelQuery{{Expr: `sum by (destination_service) (rate(istio_requests_total{response_code=~"5.."}[$__rate_interval])) / sum by (destination_service) (rate(istio_requests_total[$__rate_interval]))`, Legend: "{{destination_service}}"}},
+		},
+		{
+			Title:   "Mesh p99 request latency by destination",
+			Type:    "timeseries",
+			Unit:    "ms",
+			Queries: []panelQuery{{Expr: `histogram_quantile(0.99, sum by (le, destination_service) (rate(istio_request_duration_milliseconds_bucket[$__rate_interval])))`, Legend: "{{destination_service}}"}},
+		},
+	},
+	"linkerd": {
+		{
+			Title:   "Linkerd failed responses by deployment",
+			Type:    "timeseries",
+			Unit:    "reqps",
+			Queries: []panelQuery{{Expr: `sum by (deployment) (rate(response_total{classification="failure", direction="inbound"}[$__rate_interval]))`, Legend: "{{deployment}}"}},
+		},
+	},
+	"cilium": {
+		{
+			Title:   "Cilium dropped packets by reason",
+			Type:    "timeseries",
+			Unit:    "pps",
+			Queries: []panelQuery{{Expr: `sum by (reason) (rate(cilium_drop_count_total[$__rate_interval]))`, Legend: "{{reason}}"}},
+		},
+	},
+	"calico": {
+		{
+			Title:   "Calico denied packets by policy",
+			Type:    "timeseries",
+			Unit:    "pps",
+			Queries: []panelQuery{{Expr: `sum by (policy) (rate(calico_denied_packets[$__rate_interval]))`, Legend: "{{policy}}"}},
+		},
+	},
+}
+
+// dashboardSection is a dashboard row and its panels.
+type dashboardSection struct {
+	Title  string
+	Panels []dashboardPanel
+}
+
+// analyzerRegex matches the analyzer label of the given tools.
+func analyzerRegex(toolNames []string) string {
+	sorted := append([]string(nil), toolNames...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "|")
+}
+
+// dashboardSections lays out the server, DNS and per-provider sections.
+// providerTools maps each provider to include to its tool names.
+func dashboardSections(providerTools map[string][]string) []dashboardSection {
+	sections := []dashboardSection{
+		{
+			Title: "Diagnostic findings",
+			Panels: []dashboardPanel{
+				{
+					Title:   "Critical findings",
+					Type:    "stat",
+					Queries: []panelQuery{{Expr: `sum(increase(mcp_findings_total{severity="critical"}[$__range]))`}},
+				},
+				{
+					Title:   "Warning findings",
+					Type:    "stat",
+					Queries: []panelQuery{{Expr: `sum(increase(mcp_findings_total{severity="warning"}[$__range]))`}},
+				},
+				{
+					Title:   "Findings by severity",
+					Type:    "timeseries",
+					Queries: []panelQuery{{Expr: `sum by (severity) (increase(mcp_findings_total[$__rate_interval]))`, Legend: "{{severity}}"}},
+				},
+				{
+					Title:   "Critical and warning findings by tool",
+					Type:    "bargauge",
+					Queries: []panelQuery{{Expr: `topk(10, sum by (analyzer) (increase(mcp_findings_total{severity=~"critical|warning"}[$__range])))`, Legend: "{{analyzer}}"}},
+				},
+			},
+		},
+		{
+			Title: "Tool calls",
+			Panels: []dashboardPanel{
+				{
+					Title:   "Tool call p95 duration",
+					Type:    "timeseries",
+					Unit:    "s",
+					Queries: []panelQuery{{Expr: `histogram_quantile(0.95, sum by (le, gen_ai_tool_name) (rate(gen_ai_server_request_duration_bucket[$__rate_interval])))`, Legend: "{{gen_ai_tool_name}}"}},
+				},
+				{
+					Title:   "Tool errors by code",
+					Type:    "timeseries",
+					Unit:    "reqps",
+					Queries: []panelQuery{{Expr: `sum by (error_code) (rate(mcp_errors_total[$__rate_interval]))`, Legend: "{{error_code}}"}},
+				},
+			},
+		},
+		{
+			Title: "DNS",
+			Panels: []dashboardPanel{
+				{
+					Title:       "DNS p99 latency",
+					Description: "CoreDNS and NodeLocal DNSCache request latency, by scrape job.",
+					Type:        "timeseries",
+					Unit:        "s",
+					Queries:     []panelQuery{{Expr: `histogram_quantile(0.99, sum by (le, job) (rate(coredns_dns_request_duration_seconds_bucket[$__rate_interval])))`, Legend: "{{job}}"}},
+				},
+				{
+					Title:   "DNS error responses by rcode",
+					Type:    "timeseries",
+					Unit:    "reqps",
+					Queries: []panelQuery{{Expr: `sum by (rcode) (rate(coredns_dns_responses_total{rcode!="NOERROR"}[$__rate_interval]))`, Legend: "{{rcode}}"}},
+				},
+			},
+		},
+	}
+
+	names := make([]string, 0, len(providerTools))
+	for name := range providerTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		toolNames := providerTools[name]
+		if len(toolNames) == 0 && len(providerPanels[name]) == 0 {
+			continue
+		}
+		section := dashboardSection{Title: "Provider: " + name}
+		if len(toolNames) > 0 {
+			title := name + " findings by tool"
+			if name == "gateway-api" {
+				// Gateway and route condition checks report through the findings of these tools.
+				title = "Gateway and route condition findings by tool"
+			}
+			section.Panels = append(section.Panels, dashboardPanel{
+				Title:       title,
+				Description: "Findings from the tools this provider adds: " + strings.Join(toolNames, ", "),
+				Type:        "timeseries",
+				Queries: []panelQuery{{
+					Expr:   fmt.Sprintf(`sum by (analyzer, severity) (increase(mcp_findings_total{analyzer=~"%s", severity=~"critical|warning"}[$__rate_interval]))`, analyzerRegex(toolNames)),
+					Legend: "{{analyzer}} {{severity}}",
+				}},
+			})
+		}
+		section.Panels = append(section.Panels, providerPanels[name]...)
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// grafanaDashboard renders sections as a Grafana dashboard model. Panels use
+// a datasource template variable so the dashboard imports into any Grafana.
+func grafanaDashboard(title string, sections []dashboardSection) map[string]interface{} {
+	datasource := map[string]interface{}{"type": "prometheus", "uid": "${datasource}"}
+	var panels []interface{}
+	id, y := 1, 0
+	for _, s := range sections {
+		panels = append(panels, map[string]interface{}{
+			"id":        id,
+			"type":      "row",
+			"title":     s.Title,
+			"collapsed": false,
+			"gridPos":   map[string]interface{}{"h": 1, "w": 24, "x": 0, "y": y},
+			"panels":    []interface{}{},
+		})
+		id++
+		y++
+		x, rowH := 0, 0
+		for _, p := range s.Panels {
+			w, h := 12, 8
+			if p.Type == "stat" {
+				w, h = 6, 4
+			}
+			if x+w > 24 {
+				x = 0
+				y += rowH
+				rowH = 0
+			}
+			rowH = max(rowH, h)
+			var targets []interface{}
+			for i, q := range p.Queries {
+				targets = append(targets, map[string]interface{}{
+					"refId":        string(rune('A' + i)),
+					"datasource":   datasource,
+					"expr":         q.Expr,
+					"legendFormat": q.Legend,
+				})
+			}
+			panel := map[string]interface{}{
+				"id":         id,
+				"type":       p.Type,
+				"title":      p.Title,
+				"datasource": datasource,
+				"gridPos":    map[string]interface{}{"h": h, "w": w, "x": x, "y": y},
+				"targets":    targets,
+				"fieldConfig": map[string]interface{}{
+					"defaults":  map[string]interface{}{"unit": orDefault(p.Unit, "short")},
+					"overrides": []interface{}{},
+				},
+			}
+			if p.Description != "" {
+				panel["description"] = p.Description
+			}
+			panels = append(panels, panel)
+			id++
+			x += w
+		}
+		y += rowH
+	}
+
+	return map[string]interface{}{
+		"title":         title,
+		"tags":          []string{"kubernetes", "networking", "mcp-k8s-networking"},
+		"schemaVersion": 39,
+		"editable":      true,
+		"time":          map[string]interface{}{"from": "now-6h", "to": "now"},
+		"refresh":       "1m",
+		"templating": map[string]interface{}{
+			"list": []interface{}{map[string]interface{}{
+				"name":  "datasource",
+				"label": "Prometheus",
+				"type":  "datasource",
+				"query": "prometheus",
+			}},
+		},
+		"panels": panels,
+	}
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// --- generate_grafana_dashboard ---
+
+// GenerateGrafanaDashboardTool builds a Grafana dashboard for the server's
+// OTel metrics, DNS and the providers detected in the cluster.
+type GenerateGrafanaDashboardTool struct {
+	tools.BaseTool
+	Manager *Manager
+}
+
+func (t *GenerateGrafanaDashboardTool) Name() string { return "generate_grafana_dashboard" }
+func (t *GenerateGrafanaDashboardTool) Description() string {
+	return "Generate a ready-to-import Grafana dashboard JSON for the providers detected in the cluster: findings by severity and tool from this server's OTel metrics, gateway condition findings, tool latency and errors, DNS latency, and mesh/CNI data plane metrics"
+}
+func (t *GenerateGrafanaDashboardTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":        "string",
+				"description": "Dashboard title (default: Kubernetes networking - <cluster>)",
+			},
+			"providers": map[string]interface{}{
+				"type":        "string",
+				"description": "Comma-separated providers to include (default: all detected providers)",
+			},
+		},
+	}
+}
+
+func (t *GenerateGrafanaDashboardTool) Run(ctx context.Context, args map[string]interface{}) (*tools.StandardResponse, error) {
+	providerTools := t.Manager.enabledTools()
+
+	if only, _ := args["providers"].(string); only != "" {
+		selected := make(map[string][]string)
+		for _, name := range strings.Split(only, ",") {
+			name = strings.TrimSpace(name)
+			toolNames, ok := providerTools[name]
+			if !ok {
+				return nil, &types.MCPError{
+					Code:    types.ErrCodeProviderNotFound,
+					Tool:    t.Name(),
+					Message: fmt.Sprintf("provider %q is not detected in this cluster", name),
+					Detail:  fmt.Sprintf("detected providers: %v", t.Manager.Active()),
+				}
+			}
+			selected[name] = toolNames
+		}
+		providerTools = selected
+	}
+
+	title, _ := args["title"].(string)
+	if title == "" {
+		title = "Kubernetes networking - " + t.Cfg.ClusterName
+	}
+
+	b, err := json.MarshalIndent(grafanaDashboard(title, dashboardSections(providerTools)), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+	return tools.NewResponse(t.Cfg, t.Name(), string(b)), nil
+}
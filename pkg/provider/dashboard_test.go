@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestGenerateGrafanaDashboard(t *testing.T) {
+	base := tools.BaseTool{Cfg: &config.Config{ClusterName: "prod"}}
+	m := NewManager(base, tools.NewRegistry(), skills.NewRegistry())
+	d := Detection{}
+	d.Features.HasIstio = true
+	m.Sync(d)
+
+	tool := &GenerateGrafanaDashboardTool{BaseTool: base, Manager: m}
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dashboard struct {
+		Title  string `json:"title"`
+		Panels []struct {
+			Type    string `json:"type"`
+			Title   string `json:"title"`
+			GridPos struct {
+				Y int `json:"y"`
+			} `json:"gridPos"`
+			Targets []struct {
+				Expr string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	if err := json.Unmarshal([]byte(resp.Data.(string)), &dashboard); err != nil {
+		t.Fatalf("dashboard is not valid JSON: %v", err)
+	}
+	if dashboard.Title != "Kubernetes networking - prod" {
+		t.Errorf("title = %q", dashboard.Title)
+	}
+
+	var rows, exprs []string
+	lastY := 0
+	for _, p := range dashboard.Panels {
+		if p.GridPos.Y < lastY {
+			t.Errorf("panel %q placed above the previous panel", p.Title)
+		}
+		lastY = p.GridPos.Y
+		if p.Type == "row" {
+			rows = append(rows, p.Title)
+		}
+		for _, target := range p.Targets {
+			exprs = append(exprs, target.Expr)
+		}
+	}
+	if got := strings.Join(rows, ","); got != "Diagnostic findings,Tool calls,DNS,Provider: canary,Provider: istio" {
+		t.Errorf("rows = %s", got)
+	}
+	all := strings.Join(exprs, "\n")
+	for _, want := range []string{`analyzer=~"`, "validate_istio_config", "istio_requests_total", "coredns_dns_request_duration_seconds_bucket"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("expected a query containing %q", want)
+		}
+	}
+	if strings.Contains(all, "cilium") {
+		t.Error("undetected provider cilium has panels")
+	}
+}
+
+func TestGenerateGrafanaDashboard_UnknownProvider(t *testing.T) {
+	base := tools.BaseTool{Cfg: &config.Config{ClusterName: "prod"}}
+	m := NewManager(base, tools.NewRegistry(), skills.NewRegistry())
+	tool := &GenerateGrafanaDashboardTool{BaseTool: base, Manager: m}
+
+	_, err := tool.Run(context.Background(), map[string]interface{}{"providers": "cilium"})
+	mcpErr, ok := err.(*types.MCPError)
+	if !ok || mcpErr.Code != types.ErrCodeProviderNotFound {
+		t.Errorf("expected PROVIDER_NOT_FOUND, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// ToolHealthCheck adapts a status tool (e.g. check_cilium_status) into a
+// health check that returns the tool's findings.
+func ToolHealthCheck(t tools.Tool) HealthCheck {
+	return HealthCheck{
+		Name: t.Name(),
+		Run: func(ctx context.Context) ([]types.DiagnosticFinding, error) {
+			resp, err := t.Run(ctx, map[string]interface{}{})
+			if err != nil {
+				return nil, err
+			}
+			if result, ok := resp.Data.(*types.ToolResult); ok {
+				return result.Findings, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// --- check_provider_health ---
+
+// CheckProviderHealthTool runs the health checks of every enabled provider.
+type CheckProviderHealthTool struct {
+	tools.BaseTool
+	Manager *Manager
+}
+
+func (t *CheckProviderHealthTool) Name() string { return "check_provider_health" }
+func (t *CheckProviderHealthTool) Description() string {
+	return "Run the health checks of every detected gateway, mesh and CNI provider (built-in and extension providers) and report their findings together"
+}
+func (t *CheckProviderHealthTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"provider": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check this provider (e.g. cilium, kuma); empty for all detected providers",
+			},
+		},
+	}
+}
+
+func (t *CheckProviderHealthTool) Run(ctx context.Context, args map[string]interface{}) (*tools.StandardResponse, error) {
+	only, _ := args["provider"].(string)
+	checks := t.Manager.healthChecks()
+
+	if only != "" {
+		if _, ok := checks[only]; !ok {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeProviderNotFound,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("provider %q is not detected in this cluster", only),
+				Detail:  fmt.Sprintf("detected providers: %v", t.Manager.Active()),
+			}
+		}
+		checks = map[string][]HealthCheck{only: checks[only]}
+	}
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []types.DiagnosticFinding
+	for _, name := range names {
+		if len(checks[name]) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryMesh,
+				Summary:  fmt.Sprintf("Provider %s is detected but defines no health checks", name),
+			})
+			continue
+		}
+		for _, check := range checks[name] {
+			result, err := check.Run(ctx)
+			if err != nil {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryMesh,
+					Summary:    fmt.Sprintf("Health check %s for provider %s failed to run", check.Name, name),
+					Detail:     err.Error(),
+					Suggestion: "Verify the server's RBAC allows reading the provider's resources.",
+				})
+				continue
+			}
+			findings = append(findings, result...)
+		}
+	}
+
+	if len(checks) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Summary:  "No gateway, mesh or CNI providers detected",
+		})
+	}
+
+	return tools.NewToolResultResponse(t.Cfg, t.Name(), findings, "", only), nil
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+)
+
+// enabledProvider records what an enabled provider contributed so it can be removed.
+type enabledProvider struct {
+	provider Provider
+	tools    []string
+	skills   []string
+	checks   []HealthCheck
+}
+
+// Manager enables and disables registered providers as discovery results change.
+type Manager struct {
+	base   tools.BaseTool
+	tools  *tools.Registry
+	skills *skills.Registry
+
+	mu      sync.Mutex
+	enabled map[string]*enabledProvider
+}
+
+// NewManager creates a manager that registers provider tools and skills into
+// the given registries.
+func NewManager(base tools.BaseTool, toolRegistry *tools.Registry, skillRegistry *skills.Registry) *Manager {
+	return &Manager{
+		base:    base,
+		tools:   toolRegistry,
+		skills:  skillRegistry,
+		enabled: make(map[string]*enabledProvider),
+	}
+}
+
+// Sync enables newly detected providers and disables ones that disappeared.
+// Providers that stay detected keep their existing tool instances.
+func (m *Manager) Sync(d Detection) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range Providers() {
+		name := p.Name()
+		_, isEnabled := m.enabled[name]
+		detected := p.Detect(d)
+
+		switch {
+		case detected && !isEnabled:
+			a := &enabledProvider{provider: p, checks: p.HealthChecks(m.base)}
+			for _, t := range p.Tools(m.base) {
+				m.tools.Register(t)
+				a.tools = append(a.tools, t.Name())
+			}
+			for _, s := range p.Skills(m.base) {
+				m.skills.Register(s)
+				a.skills = append(a.skills, s.Definition().Name)
+			}
+			m.enabled[name] = a
+			slog.Info("provider: enabled", "provider", name, "tools", len(a.tools), "skills", len(a.skills))
+		case !detected && isEnabled:
+			a := m.enabled[name]
+			for _, t := range a.tools {
+				m.tools.Unregister(t)
+			}
+			for _, s := range a.skills {
+				m.skills.Unregister(s)
+			}
+			delete(m.enabled, name)
+			slog.Info("provider: disabled", "provider", name)
+		}
+	}
+}
+
+// Active returns the names of the currently enabled providers, sorted.
+func (m *Manager) Active() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var names []string
+	for _, p := range Providers() {
+		if _, ok := m.enabled[p.Name()]; ok {
+			names = append(names, p.Name())
+		}
+	}
+	return names
+}
+
+// healthChecks returns the health checks of enabled providers keyed by provider name.
+func (m *Manager) healthChecks() map[string][]HealthCheck {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]HealthCheck, len(m.enabled))
+	for name, a := range m.enabled {
+		out[name] = a.checks
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+)
+
+type fakeTool struct{ name string }
+
+func (f *fakeTool) Name() string                        { return f.name }
+func (f *fakeTool) Description() string                 { return "" }
+func (f *fakeTool) InputSchema() map[string]interface{} { return nil }
+func (f *fakeTool) Run(context.Context, map[string]interface{}) (*tools.StandardResponse, error) {
+	return nil, nil
+}
+
+type fakeProvider struct{ builds int }
+
+func (p *fakeProvider) Name() string            { return "zz-fake" }
+func (p *fakeProvider) Detect(d Detection) bool { return d.HasGroup("fake.example.com") }
+func (p *fakeProvider) Tools(tools.BaseTool) []tools.Tool {
+	p.builds++
+	return []tools.Tool{&fakeTool{name: "check_fake_status"}}
+}
+func (p *fakeProvider) Skills(tools.BaseTool) []skills.Skill      { return nil }
+func (p *fakeProvider) HealthChecks(tools.BaseTool) []HealthCheck { return nil }
+
+func TestManagerSync_ExtensionProvider(t *testing.T) {
+	fake := &fakeProvider{}
+	Register(fake)
+	defer func() {
+		providersMu.Lock()
+		delete(providers, fake.Name())
+		providersMu.Unlock()
+	}()
+
+	toolReg := tools.NewRegistry()
+	m := NewManager(tools.BaseTool{}, toolReg, skills.NewRegistry())
+
+	detected := Detection{Groups: map[string]string{"fake.example.com": "v1"}}
+	m.Sync(detected)
+	if _, ok := toolReg.Get("check_fake_status"); !ok {
+		t.Fatal("expected extension tool to be registered once its group is detected")
+	}
+	m.Sync(detected)
+	if fake.builds != 1 {
+		t.Errorf("tools rebuilt on unchanged detection: builds=%d", fake.builds)
+	}
+
+	m.Sync(Detection{})
+	if _, ok := toolReg.Get("check_fake_status"); ok {
+		t.Error("expected extension tool to be removed when its group disappears")
+	}
+	for _, name := range m.Active() {
+		if name == fake.Name() {
+			t.Error("provider should no longer be active")
+		}
+	}
+}
+
+func TestManagerSync_BuiltinFeatures(t *testing.T) {
+	toolReg := tools.NewRegistry()
+	skillReg := skills.NewRegistry()
+	m := NewManager(tools.BaseTool{}, toolReg, skillReg)
+
+	d := Detection{}
+	d.Features.HasIstio = true
+	m.Sync(d)
+	if _, ok := toolReg.Get("validate_istio_config"); !ok {
+		t.Error("expected istio tools when HasIstio is set")
+	}
+	if _, ok := toolReg.Get("scan_gateway_misconfigs"); ok {
+		t.Error("gateway-api tools registered without Gateway API")
+	}
+	if _, ok := skillReg.Get("configure_istio_mtls"); !ok {
+		t.Error("expected istio skill to be registered")
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate provider name")
+		}
+	}()
+	Register(&builtin{name: "istio"})
+}
@@ -0,0 +1,93 @@
+// Package provider is the extension point for gateway, mesh and CNI support.
+//
+// A provider bundles detection, tools, skills and health checks for one
+// technology. Built-in providers register themselves in this package; third
+// parties can ship a provider in their own Go module and register it from an
+// init function, then build the server with a blank import:
+//
+//	package contour
+//
+//	func init() { provider.Register(&Provider{}) }
+//
+//	// in a custom main package:
+//	import _ "example.com/mcp-contour"
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/discovery"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/tools"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Detection is what CRD discovery knows about the cluster.
+type Detection struct {
+	Features discovery.Features
+	// Groups maps every served API group to its preferred version.
+	Groups map[string]string
+}
+
+// HasGroup reports whether the API group is served by the cluster.
+func (d Detection) HasGroup(group string) bool {
+	_, ok := d.Groups[group]
+	return ok
+}
+
+// HealthCheck reports the health of a provider's control or data plane.
+type HealthCheck struct {
+	Name string
+	Run  func(ctx context.Context) ([]types.DiagnosticFinding, error)
+}
+
+// Provider adds support for a gateway, service mesh or CNI.
+type Provider interface {
+	// Name is a unique, lowercase identifier such as "istio".
+	Name() string
+	// Detect reports whether the provider is installed in the cluster.
+	Detect(d Detection) bool
+	// Tools returns the MCP tools to expose while the provider is detected.
+	Tools(base tools.BaseTool) []tools.Tool
+	// Skills returns the guided workflows to expose while the provider is detected.
+	Skills(base tools.BaseTool) []skills.Skill
+	// HealthChecks returns checks run by check_provider_health.
+	HealthChecks(base tools.BaseTool) []HealthCheck
+}
+
+// Requirer is implemented by providers that can say what Detect looks for,
+// so that get_capabilities can explain why they are inactive.
+type Requirer interface {
+	Requires() string
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
+// Register makes a provider available to the server. It panics if a provider
+// with the same name is already registered.
+func Register(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, dup := providers[p.Name()]; dup {
+		panic(fmt.Sprintf("provider: Register called twice for %q", p.Name()))
+	}
+	providers[p.Name()] = p
+}
+
+// Providers returns all registered providers sorted by name.
+func Providers() []Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	out := make([]Provider, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
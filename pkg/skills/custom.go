@@ -0,0 +1,201 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// ToolRunner runs a server tool on behalf of a custom skill step and returns
+// its findings, or its raw output when the tool does not report findings.
+type ToolRunner interface {
+	RunTool(ctx context.Context, name string, args map[string]interface{}) ([]types.DiagnosticFinding, string, error)
+}
+
+// CustomSkillSpec is a skill defined in YAML or JSON: a runbook whose steps
+// call server tools with arguments templated from the skill parameters.
+type CustomSkillSpec struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Parameters  []SkillParam `json:"parameters,omitempty"`
+	Steps       []CustomStep `json:"steps"`
+}
+
+// CustomStep calls one tool. String arguments are Go templates over the
+// skill parameters, e.g. "{{ .namespace }}"; an argument that is exactly one
+// parameter reference keeps the parameter's type. DependsOn, When and
+// Timeout are described on EngineStep; Timeout is a duration such as "20s".
+type CustomStep struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Tool        string                 `json:"tool"`
+	Arguments   map[string]interface{} `json:"arguments,omitempty"`
+	DependsOn   []string               `json:"dependsOn,omitempty"`
+	When        *Condition             `json:"when,omitempty"`
+	Timeout     string                 `json:"timeout,omitempty"`
+}
+
+var (
+	skillNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	singleParamRef   = regexp.MustCompile(`^\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}$`)
+)
+
+// ParseCustomSkill reads and validates one skill definition.
+func ParseCustomSkill(data []byte) (*CustomSkillSpec, error) {
+	var spec CustomSkillSpec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid skill definition: %w", err)
+	}
+	if !skillNamePattern.MatchString(spec.Name) {
+		return nil, fmt.Errorf("skill name %q must be lowercase letters, digits, '-' and '_'", spec.Name)
+	}
+	if len(spec.Steps) == 0 {
+		return nil, fmt.Errorf("skill %s has no steps", spec.Name)
+	}
+	params := make(map[string]bool, len(spec.Parameters))
+	for _, p := range spec.Parameters {
+		if p.Name == "" {
+			return nil, fmt.Errorf("skill %s has a parameter without a name", spec.Name)
+		}
+		params[p.Name] = true
+	}
+	for i, step := range spec.Steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("skill %s: step %d has no name", spec.Name, i+1)
+		}
+		if step.Tool == "" {
+			return nil, fmt.Errorf("skill %s: step %s has no tool", spec.Name, step.Name)
+		}
+		if step.Tool == "run_skill" {
+			return nil, fmt.Errorf("skill %s: step %s cannot run other skills", spec.Name, step.Name)
+		}
+		for key, v := range step.Arguments {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if _, err := template.New(key).Parse(s); err != nil {
+				return nil, fmt.Errorf("skill %s: step %s: argument %s: %w", spec.Name, step.Name, key, err)
+			}
+			if m := singleParamRef.FindStringSubmatch(s); m != nil && !params[m[1]] {
+				return nil, fmt.Errorf("skill %s: step %s: argument %s references undeclared parameter %q", spec.Name, step.Name, key, m[1])
+			}
+		}
+		if step.Timeout != "" {
+			if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+				return nil, fmt.Errorf("skill %s: step %s: invalid timeout %q", spec.Name, step.Name, step.Timeout)
+			}
+		}
+	}
+	if err := ValidateSteps(spec.engineSteps(nil, nil)); err != nil {
+		return nil, fmt.Errorf("skill %s: %w", spec.Name, err)
+	}
+	return &spec, nil
+}
+
+// engineSteps turns the steps into engine steps that render their arguments
+// from params and call runner.
+func (spec *CustomSkillSpec) engineSteps(runner ToolRunner, params map[string]interface{}) []EngineStep {
+	steps := make([]EngineStep, 0, len(spec.Steps))
+	for _, step := range spec.Steps {
+		timeout, _ := time.ParseDuration(step.Timeout)
+		steps = append(steps, EngineStep{
+			Name:      step.Name,
+			Tool:      step.Tool,
+			DependsOn: step.DependsOn,
+			When:      step.When,
+			Timeout:   timeout,
+			Run: func(ctx context.Context) ([]types.DiagnosticFinding, string, error) {
+				args, err := renderArguments(step, params)
+				if err != nil {
+					return nil, "", err
+				}
+				return runner.RunTool(ctx, step.Tool, args)
+			},
+		})
+	}
+	return steps
+}
+
+// CustomSkill runs a CustomSkillSpec through a ToolRunner.
+type CustomSkill struct {
+	spec   CustomSkillSpec
+	source string
+	runner ToolRunner
+}
+
+// NewCustomSkill creates a skill from a parsed definition; source tells
+// list_skills where it was loaded from.
+func NewCustomSkill(spec CustomSkillSpec, source string, runner ToolRunner) *CustomSkill {
+	return &CustomSkill{spec: spec, source: source, runner: runner}
+}
+
+func (s *CustomSkill) Definition() SkillDefinition {
+	return SkillDefinition{
+		Name:        s.spec.Name,
+		Description: s.spec.Description,
+		Parameters:  s.spec.Parameters,
+		Source:      s.source,
+	}
+}
+
+// renderArguments fills the argument templates of a step from params.
+func renderArguments(step CustomStep, params map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(step.Arguments))
+	for key, v := range step.Arguments {
+		s, ok := v.(string)
+		if !ok {
+			out[key] = v
+			continue
+		}
+		if m := singleParamRef.FindStringSubmatch(s); m != nil {
+			out[key] = params[m[1]]
+			continue
+		}
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", key, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, params); err != nil {
+			return nil, fmt.Errorf("argument %s: %w", key, err)
+		}
+		out[key] = buf.String()
+	}
+	return out, nil
+}
+
+func (s *CustomSkill) Execute(ctx context.Context, args map[string]interface{}) (*SkillResult, error) {
+	result := &SkillResult{SkillName: s.spec.Name}
+
+	params := make(map[string]interface{}, len(s.spec.Parameters))
+	var missing []string
+	for _, p := range s.spec.Parameters {
+		v, ok := args[p.Name]
+		switch {
+		case ok && v != "":
+			params[p.Name] = v
+		case p.Default != "":
+			params[p.Name] = p.Default
+		case p.Required:
+			missing = append(missing, p.Name)
+		default:
+			params[p.Name] = ""
+		}
+	}
+	if len(missing) > 0 {
+		result.Status = "failed"
+		result.Summary = fmt.Sprintf("Missing required parameters: %s", strings.Join(missing, ", "))
+		return result, nil
+	}
+
+	return Engine{}.Run(ctx, s.spec.Name, s.spec.engineSteps(s.runner, params)), nil
+}
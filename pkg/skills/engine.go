@@ -0,0 +1,245 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// DefaultStepTimeout bounds a step that does not set its own timeout.
+const DefaultStepTimeout = 30 * time.Second
+
+// Condition gates a step on the outcome of an earlier step. The step runs
+// when the earlier step reported a finding at least as severe as Severity,
+// or ended with Status; with both set, either one is enough.
+type Condition struct {
+	Step     string `json:"step"`
+	Severity string `json:"severity,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// severityRank orders severities from ok to critical.
+func severityRank(severity string) int {
+	switch severity {
+	case types.SeverityCritical:
+		return 3
+	case types.SeverityWarning:
+		return 2
+	case types.SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// validSeverity reports whether s names a finding severity.
+func validSeverity(s string) bool {
+	switch s {
+	case types.SeverityCritical, types.SeverityWarning, types.SeverityInfo, types.SeverityOK:
+		return true
+	}
+	return false
+}
+
+// holds reports whether the condition is met by the result of its step.
+func (c *Condition) holds(r StepResult) bool {
+	if r.Status == "skipped" {
+		return false
+	}
+	if c.Status != "" && r.Status == c.Status {
+		return true
+	}
+	if c.Severity != "" {
+		for _, f := range r.Findings {
+			if severityRank(f.Severity) >= severityRank(c.Severity) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Condition) String() string {
+	var parts []string
+	if c.Severity != "" {
+		parts = append(parts, fmt.Sprintf("reported a %s finding or worse", c.Severity))
+	}
+	if c.Status != "" {
+		parts = append(parts, "ended "+c.Status)
+	}
+	return fmt.Sprintf("step %s %s", c.Step, strings.Join(parts, " or "))
+}
+
+// EngineStep is one step run by the Engine.
+type EngineStep struct {
+	Name string
+	// Tool is reported in the step result; empty for steps that are not a
+	// single tool call.
+	Tool string
+	// DependsOn lists earlier steps that must run without failing first.
+	DependsOn []string
+	// When, if set, runs the step only if the condition holds.
+	When *Condition
+	// Timeout bounds the step; zero uses DefaultStepTimeout.
+	Timeout time.Duration
+	Run     func(ctx context.Context) ([]types.DiagnosticFinding, string, error)
+}
+
+// ValidateSteps checks that step names are unique and that dependencies and
+// conditions only refer to earlier steps, so the steps run in order.
+func ValidateSteps(steps []EngineStep) error {
+	seen := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		if seen[step.Name] {
+			return fmt.Errorf("duplicate step %s", step.Name)
+		}
+		for _, dep := range step.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("step %s depends on %s, which is not an earlier step", step.Name, dep)
+			}
+		}
+		if c := step.When; c != nil {
+			if !seen[c.Step] {
+				return fmt.Errorf("step %s is conditioned on %s, which is not an earlier step", step.Name, c.Step)
+			}
+			if c.Severity == "" && c.Status == "" {
+				return fmt.Errorf("step %s: condition needs a severity or a status", step.Name)
+			}
+			if c.Severity != "" && !validSeverity(c.Severity) {
+				return fmt.Errorf("step %s: unknown severity %q", step.Name, c.Severity)
+			}
+			switch c.Status {
+			case "", "passed", "warning", "failed":
+			default:
+				return fmt.Errorf("step %s: unknown status %q", step.Name, c.Status)
+			}
+		}
+		seen[step.Name] = true
+	}
+	return nil
+}
+
+// Engine runs skill steps in order. A step whose dependencies failed or were
+// skipped, or whose condition does not hold, is skipped with the reason
+// recorded. A failed step only skips the steps depending on it, so a skill
+// reports a partial result rather than stopping at the first failure.
+type Engine struct{}
+
+// Run executes the steps and returns the skill result with one step result,
+// including its duration, per step.
+func (Engine) Run(ctx context.Context, skillName string, steps []EngineStep) *SkillResult {
+	result := &SkillResult{SkillName: skillName, Steps: make([]StepResult, 0, len(steps))}
+	done := make(map[string]StepResult, len(steps))
+
+	for i, step := range steps {
+		var sr StepResult
+		if reason := skipReason(step, done); reason != "" {
+			sr = StepResult{StepName: step.Name, Tool: step.Tool, Status: "skipped", Reason: reason}
+		} else if err := ctx.Err(); err != nil {
+			sr = StepResult{StepName: step.Name, Tool: step.Tool, Status: "skipped", Reason: "skill cancelled: " + err.Error()}
+		} else {
+			progress.Report(ctx, i, len(steps), fmt.Sprintf("%s: running step %d/%d %s (%s)", skillName, i+1, len(steps), step.Name, step.Tool))
+			// Steps report as a whole; their tools' own progress would interleave.
+			sr = runStep(progress.Without(ctx), step)
+		}
+		done[step.Name] = sr
+		result.Steps = append(result.Steps, sr)
+	}
+	progress.Report(ctx, len(steps), len(steps), fmt.Sprintf("%s: finished %d steps", skillName, len(steps)))
+
+	counts := make(map[string]int)
+	for _, sr := range result.Steps {
+		counts[sr.Status]++
+	}
+	ran := len(steps) - counts["skipped"]
+	switch {
+	case counts["failed"] == 0:
+		result.Status = "completed"
+	case counts["failed"] == ran:
+		result.Status = "failed"
+	default:
+		result.Status = "partial"
+	}
+	result.Summary = fmt.Sprintf("Ran %d of %d steps: %d passed, %d warning, %d failed, %d skipped",
+		ran, len(steps), counts["passed"], counts["warning"], counts["failed"], counts["skipped"])
+	return result
+}
+
+// skipReason explains why a step cannot run, or returns "".
+func skipReason(step EngineStep, done map[string]StepResult) string {
+	for _, dep := range step.DependsOn {
+		switch r := done[dep]; r.Status {
+		case "failed":
+			return fmt.Sprintf("dependency %s failed", dep)
+		case "skipped":
+			return fmt.Sprintf("dependency %s was skipped", dep)
+		}
+	}
+	if c := step.When; c != nil && !c.holds(done[c.Step]) {
+		return "condition not met: " + c.String()
+	}
+	return ""
+}
+
+// runStep runs one step under its timeout. A step that ignores its context
+// is abandoned when the timeout expires.
+func runStep(ctx context.Context, step EngineStep) StepResult {
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = DefaultStepTimeout
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		findings []types.DiagnosticFinding
+		output   string
+		err      error
+	}
+	ch := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		findings, output, err := step.Run(stepCtx)
+		ch <- outcome{findings, output, err}
+	}()
+
+	sr := StepResult{StepName: step.Name, Tool: step.Tool}
+	select {
+	case o := <-ch:
+		sr.Findings, sr.Output = o.findings, o.output
+		switch {
+		case o.err != nil && stepCtx.Err() == context.DeadlineExceeded:
+			sr.Status, sr.Reason = "failed", fmt.Sprintf("timed out after %s", timeout)
+		case o.err != nil:
+			sr.Status, sr.Reason = "failed", o.err.Error()
+		default:
+			sr.Status = stepStatus(o.findings)
+		}
+	case <-stepCtx.Done():
+		sr.Status = "failed"
+		if ctx.Err() != nil {
+			sr.Reason = "skill cancelled: " + ctx.Err().Error()
+		} else {
+			sr.Reason = fmt.Sprintf("timed out after %s", timeout)
+		}
+	}
+	sr.DurationMs = time.Since(start).Milliseconds()
+	return sr
+}
+
+// stepStatus derives a step status from the worst finding severity.
+func stepStatus(findings []types.DiagnosticFinding) string {
+	status := "passed"
+	for _, f := range findings {
+		switch f.Severity {
+		case types.SeverityCritical:
+			return "failed"
+		case types.SeverityWarning:
+			status = "warning"
+		}
+	}
+	return status
+}
@@ -0,0 +1,83 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func reporting(severity string) func(context.Context) ([]types.DiagnosticFinding, string, error) {
+	return func(context.Context) ([]types.DiagnosticFinding, string, error) {
+		return []types.DiagnosticFinding{{Severity: severity}}, "", nil
+	}
+}
+
+func TestEngineRun(t *testing.T) {
+	ran := map[string]bool{}
+	track := func(name string, run func(context.Context) ([]types.DiagnosticFinding, string, error)) func(context.Context) ([]types.DiagnosticFinding, string, error) {
+		return func(ctx context.Context) ([]types.DiagnosticFinding, string, error) {
+			ran[name] = true
+			return run(ctx)
+		}
+	}
+	steps := []EngineStep{
+		{Name: "endpoints", Run: track("endpoints", reporting(types.SeverityWarning))},
+		{Name: "logs", When: &Condition{Step: "endpoints", Severity: types.SeverityWarning}, Run: track("logs", reporting(types.SeverityOK))},
+		{Name: "probe", When: &Condition{Step: "endpoints", Severity: types.SeverityCritical}, Run: track("probe", reporting(types.SeverityOK))},
+		{Name: "dns", Run: track("dns", func(context.Context) ([]types.DiagnosticFinding, string, error) {
+			return nil, "", errors.New("boom")
+		})},
+		{Name: "coredns", DependsOn: []string{"dns"}, Run: track("coredns", reporting(types.SeverityOK))},
+		{Name: "slow", Timeout: 10 * time.Millisecond, Run: track("slow", func(context.Context) ([]types.DiagnosticFinding, string, error) {
+			time.Sleep(time.Second)
+			return nil, "", nil
+		})},
+		{Name: "after_probe", DependsOn: []string{"probe"}, Run: track("after_probe", reporting(types.SeverityOK))},
+	}
+	if err := ValidateSteps(steps); err != nil {
+		t.Fatal(err)
+	}
+
+	result := Engine{}.Run(context.Background(), "runbook", steps)
+	got := map[string]StepResult{}
+	for _, sr := range result.Steps {
+		got[sr.StepName] = sr
+	}
+	want := map[string]string{
+		"endpoints": "warning", "logs": "passed", "probe": "skipped", "dns": "failed",
+		"coredns": "skipped", "slow": "failed", "after_probe": "skipped",
+	}
+	for name, status := range want {
+		if got[name].Status != status {
+			t.Errorf("%s: status %s (%s), want %s", name, got[name].Status, got[name].Reason, status)
+		}
+	}
+	if ran["probe"] || ran["coredns"] || ran["after_probe"] {
+		t.Errorf("skipped steps ran: %v", ran)
+	}
+	if got["coredns"].Reason != "dependency dns failed" || !strings.HasPrefix(got["slow"].Reason, "timed out") {
+		t.Errorf("unexpected reasons: %q, %q", got["coredns"].Reason, got["slow"].Reason)
+	}
+	if result.Status != "partial" || result.Summary != "Ran 4 of 7 steps: 1 passed, 1 warning, 2 failed, 3 skipped" {
+		t.Errorf("unexpected result %s: %s", result.Status, result.Summary)
+	}
+}
+
+func TestValidateSteps(t *testing.T) {
+	run := reporting(types.SeverityOK)
+	for name, steps := range map[string][]EngineStep{
+		"duplicate":        {{Name: "a", Run: run}, {Name: "a", Run: run}},
+		"later dependency": {{Name: "a", DependsOn: []string{"b"}, Run: run}, {Name: "b", Run: run}},
+		"unknown step":     {{Name: "a", When: &Condition{Step: "x", Severity: types.SeverityWarning}, Run: run}},
+		"empty condition":  {{Name: "a", Run: run}, {Name: "b", When: &Condition{Step: "a"}, Run: run}},
+		"bad severity":     {{Name: "a", Run: run}, {Name: "b", When: &Condition{Step: "a", Severity: "high"}, Run: run}},
+	} {
+		if err := ValidateSteps(steps); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package skills
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ConfigMapLabel marks the ConfigMaps holding custom skill definitions.
+const ConfigMapLabel = "mcp-k8s-networking/skill"
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// skillFile reports whether a file name or ConfigMap key holds a definition.
+func skillFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// customSource is one skill definition and where it comes from.
+type customSource struct {
+	key  string // file:<path> or configmap:<namespace>/<name>/<key>
+	data []byte
+}
+
+// loadedSkill is a custom skill registered from a source.
+type loadedSkill struct {
+	name string
+	hash string
+}
+
+// Loader registers custom skills from a directory and from labelled
+// ConfigMaps, and reloads them every interval so edits apply without a
+// restart. A definition that stops parsing keeps its last good version.
+type Loader struct {
+	registry  *Registry
+	runner    ToolRunner
+	dir       string
+	client    dynamic.Interface
+	namespace string
+	interval  time.Duration
+
+	mu     sync.Mutex
+	loaded map[string]loadedSkill // by source key
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewLoader creates a loader of the skill files in dir and of the ConfigMaps
+// labelled ConfigMapLabel in namespace; an empty dir or namespace disables
+// that source.
+func NewLoader(registry *Registry, runner ToolRunner, dir string, client dynamic.Interface, namespace string, interval time.Duration) *Loader {
+	return &Loader{
+		registry:  registry,
+		runner:    runner,
+		dir:       dir,
+		client:    client,
+		namespace: namespace,
+		interval:  interval,
+		loaded:    make(map[string]loadedSkill),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start loads the custom skills and then reloads them every interval until
+// ctx ends or Stop is called. A zero interval loads them once.
+func (l *Loader) Start(ctx context.Context) {
+	l.Reload(ctx)
+	if l.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-l.stopCh:
+				return
+			case <-ticker.C:
+				l.Reload(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends reloading.
+func (l *Loader) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.stopCh)
+	})
+}
+
+// Reload reads every source, registers new and changed skills and
+// unregisters those whose source is gone.
+func (l *Loader) Reload(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sources, failedPrefixes := l.read(ctx)
+	present := make(map[string]bool, len(sources))
+	owners := make(map[string]string) // skill name -> source key
+	for key, ls := range l.loaded {
+		owners[ls.name] = key
+	}
+
+	for _, src := range sources {
+		present[src.key] = true
+		sum := sha256.Sum256(src.data)
+		hash := hex.EncodeToString(sum[:])
+		prev, had := l.loaded[src.key]
+		if had && prev.hash == hash {
+			continue
+		}
+		spec, err := ParseCustomSkill(src.data)
+		if err != nil {
+			slog.Warn("skills: invalid custom skill, keeping the previous version if any", "source", src.key, "error", err)
+			continue
+		}
+		if owner, ok := owners[spec.Name]; ok && owner != src.key {
+			slog.Warn("skills: custom skill name already loaded from another source", "skill", spec.Name, "source", src.key, "loaded_from", owner)
+			continue
+		}
+		if existing, ok := l.registry.Get(spec.Name); ok {
+			if _, custom := existing.(*CustomSkill); !custom {
+				slog.Warn("skills: custom skill would replace a built-in skill", "skill", spec.Name, "source", src.key)
+				continue
+			}
+		}
+		if had && prev.name != spec.Name {
+			l.unregister(prev.name)
+			delete(owners, prev.name)
+		}
+		l.registry.Register(NewCustomSkill(*spec, src.key, l.runner))
+		l.loaded[src.key] = loadedSkill{name: spec.Name, hash: hash}
+		owners[spec.Name] = src.key
+		slog.Info("skills: loaded custom skill", "skill", spec.Name, "source", src.key)
+	}
+
+	for key, ls := range l.loaded {
+		if present[key] || hasAnyPrefix(key, failedPrefixes) {
+			continue
+		}
+		l.unregister(ls.name)
+		delete(l.loaded, key)
+		slog.Info("skills: removed custom skill", "skill", ls.name, "source", key)
+	}
+}
+
+// Unload unregisters every custom skill the loader registered, so that a
+// loader for other sources can replace it.
+func (l *Loader) Unload() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, ls := range l.loaded {
+		l.unregister(ls.name)
+		delete(l.loaded, key)
+	}
+}
+
+// unregister removes a custom skill, leaving a built-in skill that has since
+// been registered under the same name.
+func (l *Loader) unregister(name string) {
+	if s, ok := l.registry.Get(name); ok {
+		if _, custom := s.(*CustomSkill); custom {
+			l.registry.Unregister(name)
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// read returns the definitions of every source, in a stable order. Sources
+// that cannot be read are returned as key prefixes, so their skills are kept.
+func (l *Loader) read(ctx context.Context) ([]customSource, []string) {
+	var sources []customSource
+	var failed []string
+
+	if l.dir != "" {
+		entries, err := os.ReadDir(l.dir)
+		if err != nil {
+			slog.Warn("skills: failed to read custom skills directory", "dir", l.dir, "error", err)
+			failed = append(failed, "file:")
+		}
+		for _, e := range entries {
+			// Mounted ConfigMaps hold their files in ..data symlinks; the
+			// top-level names resolve to them.
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !skillFile(e.Name()) {
+				continue
+			}
+			path := filepath.Join(l.dir, e.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				slog.Warn("skills: failed to read custom skill", "file", path, "error", err)
+				failed = append(failed, "file:"+path)
+				continue
+			}
+			sources = append(sources, customSource{key: "file:" + path, data: data})
+		}
+	}
+
+	if l.namespace != "" && l.client != nil {
+		list, err := l.client.Resource(configMapsGVR).Namespace(l.namespace).List(ctx, metav1.ListOptions{LabelSelector: ConfigMapLabel})
+		if err != nil {
+			slog.Warn("skills: failed to list custom skill ConfigMaps", "namespace", l.namespace, "error", err)
+			failed = append(failed, "configmap:")
+		} else {
+			for _, cm := range list.Items {
+				data, _ := cm.Object["data"].(map[string]interface{})
+				keys := make([]string, 0, len(data))
+				for k := range data {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					v, ok := data[k].(string)
+					if !ok || !skillFile(k) {
+						continue
+					}
+					sources = append(sources, customSource{key: "configmap:" + cm.GetNamespace() + "/" + cm.GetName() + "/" + k, data: []byte(v)})
+				}
+			}
+		}
+	}
+	return sources, failed
+}
@@ -0,0 +1,189 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// fakeRunner records tool calls and answers with one finding per call.
+type fakeRunner struct {
+	calls    []string
+	severity map[string]string
+}
+
+func (r *fakeRunner) RunTool(_ context.Context, name string, args map[string]interface{}) ([]types.DiagnosticFinding, string, error) {
+	r.calls = append(r.calls, name+" "+formatArgs(args))
+	sev, ok := r.severity[name]
+	if !ok {
+		sev = types.SeverityOK
+	}
+	return []types.DiagnosticFinding{{Severity: sev, Summary: name}}, "", nil
+}
+
+func formatArgs(args map[string]interface{}) string {
+	var parts []string
+	for _, k := range []string{"namespace", "service", "tail"} {
+		if v, ok := args[k]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+const checkoutSkill = `
+name: checkout_runbook
+description: Checkout outage runbook
+parameters:
+  - name: namespace
+    type: string
+    required: true
+  - name: tail
+    type: integer
+    default: "50"
+steps:
+  - name: endpoints
+    tool: list_endpoints
+    arguments:
+      namespace: "{{ .namespace }}"
+  - name: errors
+    tool: analyze_log_errors
+    arguments:
+      namespace: "{{ .namespace }}"
+      service: "checkout.{{ .namespace }}"
+      tail: "{{ .tail }}"
+`
+
+func TestParseCustomSkill(t *testing.T) {
+	if _, err := ParseCustomSkill([]byte(checkoutSkill)); err != nil {
+		t.Fatal(err)
+	}
+	for name, def := range map[string]string{
+		"bad name":      "name: Checkout\nsteps: [{name: a, tool: list_services}]",
+		"no steps":      "name: checkout\n",
+		"no tool":       "name: checkout\nsteps: [{name: a}]",
+		"recursive":     "name: checkout\nsteps: [{name: a, tool: run_skill}]",
+		"unknown field": "name: checkout\nstep: []",
+		"undeclared":    "name: checkout\nsteps: [{name: a, tool: list_services, arguments: {namespace: '{{ .ns }}'}}]",
+		"invalid tmpl":  "name: checkout\nsteps: [{name: a, tool: list_services, arguments: {namespace: '{{ .ns '}}]",
+		"bad timeout":   "name: checkout\nsteps: [{name: a, tool: list_services, timeout: soon}]",
+		"bad depends":   "name: checkout\nsteps: [{name: a, tool: list_services, dependsOn: [b]}, {name: b, tool: list_pods}]",
+		"bad condition": "name: checkout\nsteps: [{name: a, tool: list_services}, {name: b, tool: list_pods, when: {step: a, severity: high}}]",
+	} {
+		if _, err := ParseCustomSkill([]byte(def)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCustomSkillExecute(t *testing.T) {
+	spec, err := ParseCustomSkill([]byte(checkoutSkill))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &fakeRunner{severity: map[string]string{"analyze_log_errors": types.SeverityCritical}}
+	skill := NewCustomSkill(*spec, "file:/skills/checkout.yaml", runner)
+
+	result, err := skill.Execute(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"list_endpoints namespace=shop", "analyze_log_errors namespace=shop,service=checkout.shop,tail=50"}
+	if strings.Join(runner.calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v, want %v", runner.calls, want)
+	}
+	if result.Status != "partial" || result.Steps[0].Status != "passed" || result.Steps[1].Status != "failed" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	result, _ = skill.Execute(context.Background(), map[string]interface{}{})
+	if result.Status != "failed" || !strings.Contains(result.Summary, "namespace") {
+		t.Errorf("expected the missing parameter to fail the skill, got %+v", result)
+	}
+}
+
+func TestLoaderReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkout.yaml")
+	if err := os.WriteFile(path, []byte(checkoutSkill), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "dns-runbooks",
+			"namespace": "mcp",
+			"labels":    map[string]interface{}{ConfigMapLabel: "true"},
+		},
+		"data": map[string]interface{}{
+			"dns.yaml":  "name: dns_runbook\nsteps: [{name: coredns, tool: analyze_coredns_config}]",
+			"notes.txt": "not a skill",
+			// Built-in names cannot be replaced.
+			"mtls.yaml": "name: configure_istio_mtls\nsteps: [{name: a, tool: list_services}]",
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapsGVR: "ConfigMapList"}, cm)
+
+	registry := NewRegistry()
+	registry.Register(NewConfigureMTLSSkill(nil, nil))
+	loader := NewLoader(registry, &fakeRunner{}, dir, client, "mcp", 0)
+	loader.Reload(context.Background())
+
+	names := func() string {
+		var out []string
+		for _, d := range registry.List() {
+			out = append(out, d.Name)
+		}
+		sort.Strings(out)
+		return strings.Join(out, ",")
+	}
+	if got := names(); got != "checkout_runbook,configure_istio_mtls,dns_runbook" {
+		t.Fatalf("skills = %s", got)
+	}
+	if s, _ := registry.Get("configure_istio_mtls"); s.Definition().Source != "" {
+		t.Error("the built-in skill was replaced")
+	}
+
+	// An invalid edit keeps the last good version; a rename replaces it.
+	if err := os.WriteFile(path, []byte("name: checkout_runbook\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loader.Reload(context.Background())
+	if got := names(); got != "checkout_runbook,configure_istio_mtls,dns_runbook" {
+		t.Fatalf("skills after invalid edit = %s", got)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(checkoutSkill, "checkout_runbook", "checkout_v2", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loader.Reload(context.Background())
+	if got := names(); got != "checkout_v2,configure_istio_mtls,dns_runbook" {
+		t.Fatalf("skills after rename = %s", got)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	loader.Reload(context.Background())
+	if got := names(); got != "configure_istio_mtls,dns_runbook" {
+		t.Fatalf("skills after removal = %s", got)
+	}
+
+	loader.Unload()
+	if got := names(); got != "configure_istio_mtls" {
+		t.Fatalf("skills after unload = %s", got)
+	}
+}
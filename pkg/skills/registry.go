@@ -61,24 +61,11 @@ func (r *Registry) List() []SkillDefinition {
 	return defs
 }
 
-// SyncWithFeatures registers/unregisters skills based on discovered features.
+// SyncWithFeatures registers/unregisters the skills that span several
+// providers. Single-provider skills are registered by their provider (see pkg/provider).
 func (r *Registry) SyncWithFeatures(features discovery.Features, cfg *config.Config, clients *k8s.Clients) {
 	base := skillBase{cfg: cfg, clients: clients}
 
-	// Gateway API skills
-	if features.HasGatewayAPI {
-		r.Register(&ExposeServiceSkill{base: base})
-	} else {
-		r.Unregister("expose_service_gateway_api")
-	}
-
-	// Istio skills
-	if features.HasIstio {
-		r.Register(&ConfigureMTLSSkill{base: base})
-	} else {
-		r.Unregister("configure_istio_mtls")
-	}
-
 	// Traffic split (needs Istio or Gateway API)
 	if features.HasIstio || features.HasGatewayAPI {
 		r.Register(&TrafficSplitSkill{base: base, hasIstio: features.HasIstio, hasGatewayAPI: features.HasGatewayAPI})
@@ -90,6 +77,16 @@ func (r *Registry) SyncWithFeatures(features discovery.Features, cfg *config.Con
 	r.Register(&NetworkPolicySkill{base: base, hasCilium: features.HasCilium, hasCalico: features.HasCalico})
 }
 
+// NewExposeServiceSkill creates the Gateway API expose skill.
+func NewExposeServiceSkill(cfg *config.Config, clients *k8s.Clients) Skill {
+	return &ExposeServiceSkill{base: skillBase{cfg: cfg, clients: clients}}
+}
+
+// NewConfigureMTLSSkill creates the Istio mTLS skill.
+func NewConfigureMTLSSkill(cfg *config.Config, clients *k8s.Clients) Skill {
+	return &ConfigureMTLSSkill{base: skillBase{cfg: cfg, clients: clients}}
+}
+
 // skillBase provides shared dependencies for skill implementations.
 type skillBase struct {
 	cfg     *config.Config
@@ -0,0 +1,294 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Schedule runs a skill on a cron.
+type Schedule struct {
+	Name      string                 `json:"name"`
+	Skill     string                 `json:"skill"`
+	Cron      string                 `json:"cron"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	// Cluster the skill runs on; empty is the server's own cluster.
+	Cluster string `json:"cluster,omitempty"`
+	// Severity overrides the webhook's lowest severity sent for this schedule.
+	Severity string `json:"severity,omitempty"`
+
+	cron *cronSchedule
+}
+
+// ScheduleConfig is the content of SKILL_SCHEDULE_FILE.
+type ScheduleConfig struct {
+	Schedules []Schedule     `json:"schedules"`
+	Webhook   *WebhookConfig `json:"webhook,omitempty"`
+}
+
+// LoadScheduleConfig reads and validates a YAML or JSON schedule file.
+func LoadScheduleConfig(file string) (*ScheduleConfig, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open skill schedule: %w", err)
+	}
+	defer f.Close()
+	cfg := &ScheduleConfig{}
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse skill schedule %s: %w", file, err)
+	}
+	seen := make(map[string]bool, len(cfg.Schedules))
+	for i := range cfg.Schedules {
+		s := &cfg.Schedules[i]
+		if !skillNamePattern.MatchString(s.Name) {
+			return nil, fmt.Errorf("schedule %d: name %q must be lowercase letters, digits, '-' and '_'", i+1, s.Name)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("duplicate schedule %s", s.Name)
+		}
+		seen[s.Name] = true
+		if s.Skill == "" {
+			return nil, fmt.Errorf("schedule %s has no skill", s.Name)
+		}
+		if s.Severity != "" && !validSeverity(s.Severity) {
+			return nil, fmt.Errorf("schedule %s: invalid severity %q", s.Name, s.Severity)
+		}
+		if s.cron, err = parseCron(s.Cron); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// ScheduledRun is the outcome of one scheduled skill run.
+type ScheduledRun struct {
+	Schedule   string       `json:"schedule"`
+	Skill      string       `json:"skill"`
+	StartedAt  time.Time    `json:"startedAt"`
+	DurationMs int64        `json:"durationMs"`
+	Result     *SkillResult `json:"result,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	// Notified counts the findings sent to the webhook.
+	Notified    int    `json:"notified,omitempty"`
+	NotifyError string `json:"notifyError,omitempty"`
+}
+
+// ScheduleStatus describes a schedule for list_scheduled_skills.
+type ScheduleStatus struct {
+	Name       string     `json:"name"`
+	Skill      string     `json:"skill"`
+	Cron       string     `json:"cron"`
+	NextRun    time.Time  `json:"nextRun"`
+	LastRun    *time.Time `json:"lastRun,omitempty"`
+	LastStatus string     `json:"lastStatus,omitempty"`
+}
+
+// Scheduler runs skills on their schedules, keeps the latest run of each
+// schedule (in memory and, when dir is set, on disk) and sends findings to
+// the webhook.
+type Scheduler struct {
+	registry  *Registry
+	schedules []Schedule
+	notifier  *Notifier // nil without a webhook
+	dir       string
+
+	mu   sync.Mutex
+	last map[string]*ScheduledRun
+	next map[string]time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewScheduler creates a scheduler of the skills in registry. Latest runs
+// kept in dir by a previous process are loaded.
+func NewScheduler(registry *Registry, schedules []Schedule, notifier *Notifier, dir string) (*Scheduler, error) {
+	s := &Scheduler{
+		registry:  registry,
+		schedules: schedules,
+		notifier:  notifier,
+		dir:       dir,
+		last:      make(map[string]*ScheduledRun),
+		next:      make(map[string]time.Time),
+		stopCh:    make(chan struct{}),
+	}
+	now := time.Now()
+	for _, sc := range schedules {
+		s.next[sc.Name] = sc.cron.next(now)
+	}
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create skill results directory %s: %w", dir, err)
+	}
+	for _, sc := range schedules {
+		data, err := os.ReadFile(s.resultFile(sc.Name))
+		if err != nil {
+			continue
+		}
+		var run ScheduledRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			slog.Warn("skills: skipping unreadable scheduled run", "schedule", sc.Name, "error", err)
+			continue
+		}
+		s.last[sc.Name] = &run
+	}
+	return s, nil
+}
+
+func (s *Scheduler) resultFile(name string) string {
+	return filepath.Join(s.dir, "run-"+name+".json")
+}
+
+// Start runs every schedule until ctx ends or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, sc := range s.schedules {
+		go s.loop(ctx, sc)
+	}
+}
+
+// Stop ends scheduling; runs in progress finish.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+func (s *Scheduler) loop(ctx context.Context, sc Schedule) {
+	for {
+		s.mu.Lock()
+		next := s.next[sc.Name]
+		s.mu.Unlock()
+		if next.IsZero() {
+			slog.Warn("skills: schedule never fires", "schedule", sc.Name, "cron", sc.Cron)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.mu.Lock()
+		s.next[sc.Name] = sc.cron.next(time.Now())
+		s.mu.Unlock()
+		s.Run(ctx, sc.Name)
+	}
+}
+
+// Run runs a schedule now, records the run and notifies the webhook.
+func (s *Scheduler) Run(ctx context.Context, name string) (*ScheduledRun, error) {
+	var sc *Schedule
+	for i := range s.schedules {
+		if s.schedules[i].Name == name {
+			sc = &s.schedules[i]
+		}
+	}
+	if sc == nil {
+		return nil, fmt.Errorf("schedule %q not found", name)
+	}
+
+	run := &ScheduledRun{Schedule: sc.Name, Skill: sc.Skill, StartedAt: time.Now().UTC()}
+	if skill, ok := s.registry.Get(sc.Skill); !ok {
+		run.Error = fmt.Sprintf("skill %q is not available", sc.Skill)
+	} else {
+		args := make(map[string]interface{}, len(sc.Arguments))
+		for k, v := range sc.Arguments {
+			args[k] = v
+		}
+		result, err := skill.Execute(ctx, args)
+		if err != nil {
+			run.Error = err.Error()
+		}
+		run.Result = result
+	}
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+
+	if s.notifier != nil {
+		severity := sc.Severity
+		if severity == "" {
+			severity = s.notifier.Severity()
+		}
+		n, err := s.notifier.Notify(ctx, run, severity)
+		run.Notified = n
+		if err != nil {
+			run.NotifyError = err.Error()
+			slog.Warn("skills: failed to send scheduled run findings", "schedule", sc.Name, "error", err)
+		}
+	}
+
+	status := "error"
+	if run.Result != nil {
+		status = run.Result.Status
+	}
+	slog.Info("skills: scheduled run finished", "schedule", sc.Name, "skill", sc.Skill, "status", status, "notified", run.Notified)
+
+	s.mu.Lock()
+	s.last[sc.Name] = run
+	s.mu.Unlock()
+	if s.dir != "" {
+		if data, err := json.Marshal(run); err == nil {
+			if err := os.WriteFile(s.resultFile(sc.Name), data, 0o640); err != nil {
+				slog.Warn("skills: failed to persist scheduled run", "schedule", sc.Name, "error", err)
+			}
+		}
+	}
+	return run, nil
+}
+
+// Schedules lists the schedules sorted by name.
+func (s *Scheduler) Schedules() []ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ScheduleStatus, 0, len(s.schedules))
+	for _, sc := range s.schedules {
+		st := ScheduleStatus{Name: sc.Name, Skill: sc.Skill, Cron: sc.Cron, NextRun: s.next[sc.Name]}
+		if run, ok := s.last[sc.Name]; ok {
+			started := run.StartedAt
+			st.LastRun = &started
+			st.LastStatus = "error"
+			if run.Result != nil {
+				st.LastStatus = run.Result.Status
+			}
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LastRun returns the latest run of a schedule.
+func (s *Scheduler) LastRun(name string) (*ScheduledRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.last[name]
+	return run, ok
+}
+
+// LastRuns returns the latest run of every schedule that has run, sorted by
+// schedule name.
+func (s *Scheduler) LastRuns() []*ScheduledRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*ScheduledRun, 0, len(s.last))
+	for _, sc := range s.schedules {
+		if run, ok := s.last[sc.Name]; ok {
+			out = append(out, run)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Schedule < out[j].Schedule })
+	return out
+}
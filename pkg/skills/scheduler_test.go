@@ -0,0 +1,149 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday.
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * SUN", time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", from.Add(90 * time.Minute)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		cron, err := parseCron(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		if got := cron.next(from); !got.Equal(c.want) {
+			t.Errorf("%s: next = %s, want %s", c.expr, got, c.want)
+		}
+	}
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * * foo *", "*/0 * * * *", "5-1 * * * *", "@every 10s"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("%s: expected an error", expr)
+		}
+	}
+}
+
+func writeSchedule(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadScheduleConfig(t *testing.T) {
+	for name, content := range map[string]string{
+		"bad cron":     "schedules: [{name: a, skill: s, cron: 'every day'}]",
+		"no skill":     "schedules: [{name: a, cron: '@daily'}]",
+		"duplicate":    "schedules: [{name: a, skill: s, cron: '@daily'}, {name: a, skill: s, cron: '@hourly'}]",
+		"bad severity": "schedules: [{name: a, skill: s, cron: '@daily', severity: high}]",
+	} {
+		if _, err := LoadScheduleConfig(writeSchedule(t, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	var alerts []map[string]interface{}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer hook.Close()
+
+	cfg, err := LoadScheduleConfig(writeSchedule(t, `
+schedules:
+  - name: checkout-nightly
+    skill: checkout_runbook
+    cron: "0 2 * * *"
+    arguments:
+      namespace: shop
+  - name: missing
+    skill: no_such_skill
+    cron: "@hourly"
+webhook:
+  url: `+hook.URL+`
+  format: alertmanager
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier, err := NewNotifier(*cfg.Webhook, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := ParseCustomSkill([]byte(checkoutSkill))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewRegistry()
+	registry.Register(NewCustomSkill(*spec, "test", &fakeRunner{severity: map[string]string{"analyze_log_errors": types.SeverityCritical}}))
+
+	dir := t.TempDir()
+	sched, err := NewScheduler(registry, cfg.Schedules, notifier, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := sched.Run(context.Background(), "checkout-nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Result.Status != "partial" || run.Notified != 1 || run.NotifyError != "" {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %v", alerts)
+	}
+	labels := alerts[0]["labels"].(map[string]interface{})
+	if labels["severity"] != "critical" || labels["schedule"] != "checkout-nightly" || labels["cluster"] != "prod" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	if run, _ := sched.Run(context.Background(), "missing"); !strings.Contains(run.Error, "no_such_skill") {
+		t.Errorf("expected a missing skill error, got %+v", run)
+	}
+	if _, err := sched.Run(context.Background(), "unknown"); err == nil {
+		t.Error("expected an error for an unknown schedule")
+	}
+
+	// A new scheduler reads the latest runs back from the directory.
+	reloaded, err := NewScheduler(registry, cfg.Schedules, nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := reloaded.Schedules()
+	if len(statuses) != 2 || statuses[0].Name != "checkout-nightly" || statuses[0].LastStatus != "partial" || statuses[1].LastStatus != "error" {
+		t.Errorf("unexpected schedules %+v", statuses)
+	}
+	if !statuses[0].NextRun.After(time.Now()) || statuses[0].NextRun.Hour() != 2 {
+		t.Errorf("unexpected next run %s", statuses[0].NextRun)
+	}
+}
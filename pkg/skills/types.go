@@ -23,10 +23,13 @@ type Step struct {
 
 // StepResult holds the outcome of executing a skill step.
 type StepResult struct {
-	StepName string                   `json:"stepName"`
-	Status   string                   `json:"status"` // "passed", "failed", "warning", "skipped"
-	Findings []types.DiagnosticFinding `json:"findings,omitempty"`
-	Output   string                   `json:"output,omitempty"`
+	StepName   string                    `json:"stepName"`
+	Tool       string                    `json:"tool,omitempty"`
+	Status     string                    `json:"status"`           // "passed", "failed", "warning", "skipped"
+	Reason     string                    `json:"reason,omitempty"` // why a step was skipped or failed
+	DurationMs int64                     `json:"durationMs,omitempty"`
+	Findings   []types.DiagnosticFinding `json:"findings,omitempty"`
+	Output     string                    `json:"output,omitempty"`
 }
 
 // SkillResult is the complete result of executing a skill.
@@ -44,6 +47,8 @@ type SkillDefinition struct {
 	Description  string   `json:"description"`
 	RequiredCRDs []string `json:"requiredCRDs,omitempty"`
 	Parameters   []SkillParam `json:"parameters"`
+	// Source is where a custom skill was loaded from; empty for built-in skills.
+	Source string `json:"source,omitempty"`
 }
 
 // SkillParam describes a skill input parameter.
@@ -52,4 +57,5 @@ type SkillParam struct {
 	Type        string `json:"type"`
 	Required    bool   `json:"required"`
 	Description string `json:"description"`
+	Default     string `json:"default,omitempty"`
 }
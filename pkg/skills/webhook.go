@@ -0,0 +1,198 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Webhook payload formats.
+const (
+	WebhookFormatSlack        = "slack"
+	WebhookFormatAlertmanager = "alertmanager"
+)
+
+// maxSlackFindings bounds the findings listed in one Slack message.
+const maxSlackFindings = 20
+
+// WebhookConfig is where scheduled runs send their findings.
+type WebhookConfig struct {
+	// URL, or URLFile holding it (read on every send, e.g. a mounted Secret).
+	URL     string `json:"url,omitempty"`
+	URLFile string `json:"urlFile,omitempty"`
+	// Format is slack (default) or alertmanager (POST to /api/v2/alerts).
+	Format string `json:"format,omitempty"`
+	// Severity is the lowest finding severity sent (default warning).
+	Severity string `json:"severity,omitempty"`
+}
+
+// Notifier posts the findings of scheduled runs to a webhook.
+type Notifier struct {
+	cfg        WebhookConfig
+	cluster    string
+	httpClient *http.Client
+}
+
+// NewNotifier validates cfg and creates a notifier labelling alerts with
+// cluster.
+func NewNotifier(cfg WebhookConfig, cluster string) (*Notifier, error) {
+	if cfg.Format == "" {
+		cfg.Format = WebhookFormatSlack
+	}
+	if cfg.Format != WebhookFormatSlack && cfg.Format != WebhookFormatAlertmanager {
+		return nil, fmt.Errorf("invalid webhook format %q: expected slack or alertmanager", cfg.Format)
+	}
+	if cfg.Severity == "" {
+		cfg.Severity = types.SeverityWarning
+	}
+	if !validSeverity(cfg.Severity) {
+		return nil, fmt.Errorf("invalid webhook severity %q", cfg.Severity)
+	}
+	if (cfg.URL == "") == (cfg.URLFile == "") {
+		return nil, fmt.Errorf("webhook needs exactly one of url and urlFile")
+	}
+	if cfg.URL != "" {
+		if err := checkWebhookURL(cfg.URL); err != nil {
+			return nil, err
+		}
+	}
+	return &Notifier{cfg: cfg, cluster: cluster, httpClient: &http.Client{Timeout: 15 * time.Second}}, nil
+}
+
+func checkWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL: expected http(s)://host[/path]")
+	}
+	return nil
+}
+
+// Severity is the default lowest severity sent.
+func (n *Notifier) Severity() string { return n.cfg.Severity }
+
+// alertFindings returns the findings of a result at least as severe as
+// severity.
+func alertFindings(result *SkillResult, severity string) []types.DiagnosticFinding {
+	var out []types.DiagnosticFinding
+	for _, step := range result.Steps {
+		for _, f := range step.Findings {
+			if severityRank(f.Severity) >= severityRank(severity) {
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
+
+// Notify posts the findings of run at least as severe as severity and
+// returns how many were sent. Nothing is sent when there are none.
+func (n *Notifier) Notify(ctx context.Context, run *ScheduledRun, severity string) (int, error) {
+	if run.Result == nil {
+		return 0, nil
+	}
+	findings := alertFindings(run.Result, severity)
+	if len(findings) == 0 {
+		return 0, nil
+	}
+
+	var payload interface{}
+	if n.cfg.Format == WebhookFormatAlertmanager {
+		payload = n.alertmanagerPayload(run, findings)
+	} else {
+		payload = n.slackPayload(run, findings)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	target := n.cfg.URL
+	if n.cfg.URLFile != "" {
+		data, err := os.ReadFile(n.cfg.URLFile)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read webhook URL: %w", err)
+		}
+		target = strings.TrimSpace(string(data))
+		if err := checkWebhookURL(target); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		// The URL may hold a secret token: report the host only.
+		return 0, fmt.Errorf("webhook request to %s failed", req.URL.Host)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("webhook %s returned HTTP %d", req.URL.Host, resp.StatusCode)
+	}
+	return len(findings), nil
+}
+
+func (n *Notifier) slackPayload(run *ScheduledRun, findings []types.DiagnosticFinding) map[string]interface{} {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s* (skill %s, cluster %s): %s\n", run.Schedule, run.Skill, n.cluster, run.Result.Summary)
+	for i, f := range findings {
+		if i == maxSlackFindings {
+			fmt.Fprintf(&b, "… and %d more\n", len(findings)-i)
+			break
+		}
+		fmt.Fprintf(&b, "%s %s", types.SeverityIcon(f.Severity), f.Summary)
+		if f.Code != "" {
+			fmt.Fprintf(&b, " [%s]", f.Code)
+		}
+		b.WriteString("\n")
+	}
+	return map[string]interface{}{"text": b.String()}
+}
+
+func (n *Notifier) alertmanagerPayload(run *ScheduledRun, findings []types.DiagnosticFinding) []map[string]interface{} {
+	alerts := make([]map[string]interface{}, 0, len(findings))
+	for _, f := range findings {
+		labels := map[string]string{
+			"alertname": "NetworkingSkillFinding",
+			"severity":  f.Severity,
+			"schedule":  run.Schedule,
+			"skill":     run.Skill,
+			"cluster":   n.cluster,
+		}
+		if f.Code != "" {
+			labels["code"] = string(f.Code)
+		}
+		if f.Resource != nil {
+			labels["kind"] = f.Resource.Kind
+			labels["name"] = f.Resource.Name
+			if f.Resource.Namespace != "" {
+				labels["namespace"] = f.Resource.Namespace
+			}
+		}
+		annotations := map[string]string{"summary": f.Summary}
+		if f.Detail != "" {
+			annotations["description"] = f.Detail
+		}
+		if f.Suggestion != "" {
+			annotations["suggestion"] = f.Suggestion
+		}
+		alerts = append(alerts, map[string]interface{}{
+			"labels":      labels,
+			"annotations": annotations,
+			"startsAt":    run.StartedAt.Format(time.RFC3339),
+		})
+	}
+	return alerts
+}
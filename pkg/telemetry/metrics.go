@@ -20,6 +20,10 @@ type Meters struct {
 	// Custom domain metrics
 	FindingsTotal metric.Int64Counter
 	ErrorsTotal   metric.Int64Counter
+	AliasCalls    metric.Int64Counter
+
+	// Stats keeps the tool calls recorded here in memory for get_server_stats.
+	Stats *Stats
 }
 
 // NewMeters creates all OTel metric instruments for MCP server instrumentation.
@@ -59,10 +63,20 @@ func NewMeters() (*Meters, error) {
 		return nil, err
 	}
 
+	aliasCalls, err := meter.Int64Counter(
+		"mcp.tool.alias.calls",
+		metric.WithDescription("Tool calls made by a deprecated tool name"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Meters{
 		RequestDuration: requestDuration,
 		RequestCount:    requestCount,
 		FindingsTotal:   findingsTotal,
 		ErrorsTotal:     errorsTotal,
+		AliasCalls:      aliasCalls,
+		Stats:           DefaultStats,
 	}, nil
 }
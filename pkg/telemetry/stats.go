@@ -0,0 +1,227 @@
+package telemetry
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindow is how many recent calls of each tool p95 latency is
+	// computed from.
+	latencyWindow = 1000
+	// apiCallWindow is how many recent Kubernetes API calls are kept for
+	// the slow-call report.
+	apiCallWindow = 500
+)
+
+// DefaultStats aggregates the tool and Kubernetes API calls of this process.
+// Like the OTel meter provider it is process-wide, so the Kubernetes client
+// transport, which has no Meters, records into the same place.
+var DefaultStats = NewStats()
+
+// Stats keeps in-memory aggregates of what Meters export, so the server can
+// report on itself without a metrics backend.
+type Stats struct {
+	mu      sync.Mutex
+	started time.Time
+	tools   map[string]*toolCalls
+
+	apiCalls    int
+	apiStatuses map[APIOperation]map[int]int // failed calls by operation and status
+	recentAPI   []APICall                    // ring of the last apiCallWindow calls
+	nextAPI     int
+}
+
+// toolCalls is what Stats knows about one tool.
+type toolCalls struct {
+	calls, errors int
+	errorTypes    map[string]int
+	maxSeconds    float64
+	recent        []float64 // ring of the last latencyWindow durations
+	next          int
+}
+
+// APIOperation is a Kubernetes API verb on a resource.
+type APIOperation struct {
+	Verb     string `json:"verb"`
+	Resource string `json:"resource"`
+}
+
+// APICall is one Kubernetes API request. Status is 0 when no response was
+// received.
+type APICall struct {
+	APIOperation
+	Namespace string        `json:"namespace,omitempty"`
+	Status    int           `json:"status"`
+	Duration  time.Duration `json:"duration"`
+	At        time.Time     `json:"at"`
+}
+
+// NewStats returns empty stats starting now.
+func NewStats() *Stats {
+	return &Stats{
+		started:     time.Now(),
+		tools:       make(map[string]*toolCalls),
+		apiStatuses: make(map[APIOperation]map[int]int),
+	}
+}
+
+// RecordToolCall records a tool call that took seconds; errType is empty
+// for a successful call.
+func (s *Stats) RecordToolCall(tool, errType string, seconds float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tc, ok := s.tools[tool]
+	if !ok {
+		tc = &toolCalls{errorTypes: make(map[string]int)}
+		s.tools[tool] = tc
+	}
+	tc.calls++
+	if errType != "" {
+		tc.errors++
+		tc.errorTypes[errType]++
+	}
+	tc.maxSeconds = math.Max(tc.maxSeconds, seconds)
+	if len(tc.recent) < latencyWindow {
+		tc.recent = append(tc.recent, seconds)
+	} else {
+		tc.recent[tc.next] = seconds
+		tc.next = (tc.next + 1) % latencyWindow
+	}
+}
+
+// RecordAPICall records a Kubernetes API request.
+func (s *Stats) RecordAPICall(c APICall) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiCalls++
+	if c.Status == 0 || c.Status >= 400 {
+		statuses, ok := s.apiStatuses[c.APIOperation]
+		if !ok {
+			statuses = make(map[int]int)
+			s.apiStatuses[c.APIOperation] = statuses
+		}
+		statuses[c.Status]++
+	}
+	if len(s.recentAPI) < apiCallWindow {
+		s.recentAPI = append(s.recentAPI, c)
+	} else {
+		s.recentAPI[s.nextAPI] = c
+		s.nextAPI = (s.nextAPI + 1) % apiCallWindow
+	}
+}
+
+// ToolStats summarizes the calls of one tool.
+type ToolStats struct {
+	Tool   string `json:"tool"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors"`
+	// ErrorTypes counts failed calls by MCP error code.
+	ErrorTypes map[string]int `json:"errorTypes,omitempty"`
+	// P95Seconds is over the last 1000 calls.
+	P95Seconds float64 `json:"p95Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+// ErrorRate is the share of calls that failed.
+func (t ToolStats) ErrorRate() float64 {
+	if t.Calls == 0 {
+		return 0
+	}
+	return float64(t.Errors) / float64(t.Calls)
+}
+
+// APIFailures counts failed requests of one operation by HTTP status.
+type APIFailures struct {
+	APIOperation
+	Statuses map[int]int `json:"statuses"`
+}
+
+// StatsSnapshot is a point-in-time copy of Stats.
+type StatsSnapshot struct {
+	Since time.Time `json:"since"`
+	// Tools is ordered by call count, most called first.
+	Tools    []ToolStats `json:"tools"`
+	APICalls int         `json:"apiCalls"`
+	// APIFailures is ordered by failure count, most failing first.
+	APIFailures []APIFailures `json:"apiFailures,omitempty"`
+	// SlowestAPICalls are the slowest of the recent API calls, slowest first.
+	SlowestAPICalls []APICall `json:"slowestApiCalls,omitempty"`
+}
+
+// Snapshot copies the stats, keeping the slowest recent API calls.
+func (s *Stats) Snapshot(slowest int) StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := StatsSnapshot{Since: s.started, APICalls: s.apiCalls}
+	for name, tc := range s.tools {
+		ts := ToolStats{
+			Tool:       name,
+			Calls:      tc.calls,
+			Errors:     tc.errors,
+			P95Seconds: percentile(tc.recent, 0.95),
+			MaxSeconds: tc.maxSeconds,
+		}
+		if len(tc.errorTypes) > 0 {
+			ts.ErrorTypes = make(map[string]int, len(tc.errorTypes))
+			for k, v := range tc.errorTypes {
+				ts.ErrorTypes[k] = v
+			}
+		}
+		snap.Tools = append(snap.Tools, ts)
+	}
+	sort.Slice(snap.Tools, func(i, j int) bool {
+		if snap.Tools[i].Calls != snap.Tools[j].Calls {
+			return snap.Tools[i].Calls > snap.Tools[j].Calls
+		}
+		return snap.Tools[i].Tool < snap.Tools[j].Tool
+	})
+
+	for op, statuses := range s.apiStatuses {
+		f := APIFailures{APIOperation: op, Statuses: make(map[int]int, len(statuses))}
+		for k, v := range statuses {
+			f.Statuses[k] = v
+		}
+		snap.APIFailures = append(snap.APIFailures, f)
+	}
+	sort.Slice(snap.APIFailures, func(i, j int) bool {
+		ni, nj := snap.APIFailures[i].Total(), snap.APIFailures[j].Total()
+		if ni != nj {
+			return ni > nj
+		}
+		a, b := snap.APIFailures[i].APIOperation, snap.APIFailures[j].APIOperation
+		return a.Resource+"/"+a.Verb < b.Resource+"/"+b.Verb
+	})
+
+	recent := append([]APICall(nil), s.recentAPI...)
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].Duration > recent[j].Duration })
+	if len(recent) > slowest {
+		recent = recent[:slowest]
+	}
+	snap.SlowestAPICalls = recent
+	return snap
+}
+
+// Total is the number of failed requests.
+func (f APIFailures) Total() int {
+	n := 0
+	for _, v := range f.Statuses {
+		n += v
+	}
+	return n
+}
+
+// percentile returns the p-th percentile of values by the nearest-rank
+// method, 0 when there are none.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
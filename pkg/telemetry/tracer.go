@@ -46,7 +46,7 @@ func Init(ctx context.Context, clusterName string) (*InitResult, error) {
 		slog.Info("telemetry: disabled (OTEL_EXPORTER_OTLP_ENDPOINT not set)")
 		return &InitResult{
 			Shutdown:    func(ctx context.Context) error { return nil },
-			SlogHandler: slog.NewJSONHandler(os.Stdout, nil),
+			SlogHandler: slog.Default().Handler(),
 			Providers:   &Providers{Enabled: false},
 		}, nil
 	}
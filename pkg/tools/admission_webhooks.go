@@ -0,0 +1,548 @@
+package tools
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var validatingWebhooksGVR = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}
+
+// networkingWebhookOwners recognizes the webhooks of networking controllers
+// from the configuration name, the webhook names and the Service namespace.
+var networkingWebhookOwners = []struct {
+	owner    string
+	patterns []string
+}{
+	{"Istio", []string{"istio"}},
+	{"Linkerd", []string{"linkerd"}},
+	{"Kuma", []string{"kuma"}},
+	{"Consul", []string{"consul"}},
+	{"AWS App Mesh", []string{"appmesh"}},
+	{"Cilium", []string{"cilium"}},
+	{"Calico", []string{"calico", "tigera"}},
+	{"Antrea", []string{"antrea"}},
+	{"MetalLB", []string{"metallb"}},
+	{"Gateway API", []string{"gateway.networking.k8s.io", "gateway-api"}},
+	{"Envoy Gateway", []string{"envoy-gateway"}},
+	{"kgateway", []string{"kgateway", "gloo"}},
+	{"Contour", []string{"contour"}},
+	{"Traefik", []string{"traefik"}},
+	{"Kong", []string{"kong"}},
+	{"NGINX Gateway Fabric", []string{"nginx-gateway"}},
+	{"ingress-nginx", []string{"ingress-nginx"}},
+	{"AWS Load Balancer Controller", []string{"aws-load-balancer", "elbv2.k8s.aws"}},
+}
+
+// webhookOwner returns the networking controller a webhook belongs to, or
+// "" for other webhooks.
+func webhookOwner(names ...string) string {
+	for _, o := range networkingWebhookOwners {
+		for _, p := range o.patterns {
+			for _, n := range names {
+				if strings.Contains(strings.ToLower(n), p) {
+					return o.owner
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// workloadResources are the resources whose admission, when blocked, stops
+// deployments and rollouts.
+var workloadResources = map[string]bool{
+	"*": true, "*/*": true, "pods": true, "deployments": true, "replicasets": true,
+	"statefulsets": true, "daemonsets": true, "jobs": true,
+}
+
+// admissionWebhook is one webhook of a Validating- or
+// MutatingWebhookConfiguration.
+type admissionWebhook struct {
+	kind, config  string
+	name          string
+	owner         string
+	failurePolicy string
+	timeout       int64
+	// service is "namespace/name"; url is set instead for external webhooks.
+	service     string
+	port        int64
+	path        string
+	url         string
+	caBundle    string
+	caInjected  bool
+	nsSelector  labels.Selector
+	objSelector bool
+	// workloads lists the workload resources the rules intercept on create.
+	workloads []string
+}
+
+func (w admissionWebhook) ref() *types.ResourceRef {
+	return &types.ResourceRef{Kind: w.kind, Name: w.config, APIVersion: "admissionregistration.k8s.io/v1"}
+}
+
+func (w admissionWebhook) label() string {
+	return fmt.Sprintf("%s %s webhook %s", w.kind, w.config, w.name)
+}
+
+func (w admissionWebhook) failClosed() bool { return w.failurePolicy == "Fail" }
+
+// parseAdmissionWebhooks reads the webhooks of a configuration, applying the
+// admissionregistration/v1 defaults.
+func parseAdmissionWebhooks(cfg unstructured.Unstructured) []admissionWebhook {
+	var out []admissionWebhook
+	ann := cfg.GetAnnotations()
+	list, _, _ := unstructured.NestedSlice(cfg.Object, "webhooks")
+	for _, item := range list {
+		wm, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		w := admissionWebhook{kind: cfg.GetKind(), config: cfg.GetName(), timeout: 10, port: 443}
+		w.name, _ = wm["name"].(string)
+		policy, _ := wm["failurePolicy"].(string)
+		w.failurePolicy = orDefault(policy, "Fail")
+		if v, ok, _ := unstructured.NestedInt64(wm, "timeoutSeconds"); ok {
+			w.timeout = v
+		}
+		svc, hasSvc, _ := unstructured.NestedMap(wm, "clientConfig", "service")
+		if hasSvc {
+			ns, _ := svc["namespace"].(string)
+			name, _ := svc["name"].(string)
+			w.service = ns + "/" + name
+			w.path, _ = svc["path"].(string)
+			if p, ok, _ := unstructured.NestedInt64(svc, "port"); ok {
+				w.port = p
+			}
+		}
+		w.url, _, _ = unstructured.NestedString(wm, "clientConfig", "url")
+		w.caBundle, _, _ = unstructured.NestedString(wm, "clientConfig", "caBundle")
+		w.caInjected = ann["cert-manager.io/inject-ca-from"] != "" || ann["cert-manager.io/inject-ca-from-secret"] != ""
+		nsSel, _, _ := unstructured.NestedMap(wm, "namespaceSelector")
+		if nsSel == nil {
+			nsSel = map[string]interface{}{}
+		}
+		if sel, err := parseLabelSelector(nsSel, true); err == nil {
+			w.nsSelector = sel
+		} else {
+			w.nsSelector = labels.Nothing()
+		}
+		objSel, _, _ := unstructured.NestedMap(wm, "objectSelector")
+		w.objSelector = len(objSel) > 0
+		w.workloads = webhookWorkloadRules(wm)
+		w.owner = webhookOwner(cfg.GetName(), w.name, w.service)
+		out = append(out, w)
+	}
+	return out
+}
+
+// webhookWorkloadRules returns the workload resources a webhook intercepts
+// on create.
+func webhookWorkloadRules(wm map[string]interface{}) []string {
+	var out []string
+	rules, _, _ := unstructured.NestedSlice(wm, "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ops, _, _ := unstructured.NestedStringSlice(rm, "operations")
+		if !containsString(ops, "CREATE") && !containsString(ops, "*") {
+			continue
+		}
+		resources, _, _ := unstructured.NestedStringSlice(rm, "resources")
+		for _, res := range resources {
+			if workloadResources[res] && !containsString(out, res) {
+				out = append(out, res)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// caBundleFindings checks that the API server can verify the webhook's
+// serving certificate: a Service webhook needs a CA bundle, and it must hold
+// an unexpired certificate.
+func caBundleFindings(w admissionWebhook, now time.Time) []types.DiagnosticFinding {
+	severity := types.SeverityWarning
+	if w.failClosed() {
+		severity = types.SeverityCritical
+	}
+	if w.caBundle == "" {
+		if w.service == "" {
+			return nil // an external URL may use a publicly trusted certificate
+		}
+		f := types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeWebhookCABundleInvalid,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s has no caBundle: the API server cannot verify its certificate", w.label()),
+			Suggestion: "The controller or cert-manager cainjector normally patches the caBundle in; check its logs.",
+		}
+		if w.caInjected {
+			f.Detail = "the cert-manager.io/inject-ca-from annotation is set, but the CA was not injected"
+		}
+		return []types.DiagnosticFinding{f}
+	}
+	data, err := base64.StdEncoding.DecodeString(w.caBundle)
+	if err != nil {
+		data = []byte(w.caBundle)
+	}
+	var valid, expired []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if now.After(cert.NotAfter) {
+			expired = append(expired, cert)
+		} else {
+			valid = append(valid, cert)
+		}
+	}
+	switch {
+	case len(valid) == 0 && len(expired) == 0:
+		return []types.DiagnosticFinding{{
+			Severity:   severity,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeWebhookCABundleInvalid,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s has a caBundle with no readable PEM certificate", w.label()),
+			Suggestion: "Every call to the webhook fails TLS verification; re-inject the CA.",
+		}}
+	case len(valid) == 0:
+		return []types.DiagnosticFinding{{
+			Severity:   severity,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeWebhookCABundleInvalid,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("The caBundle of %s expired on %s", w.label(), expired[0].NotAfter.Format("2006-01-02")),
+			Detail:     "subject: " + expired[0].Subject.String(),
+			Suggestion: "Every call to the webhook fails TLS verification; rotate the webhook CA and restart the controller.",
+		}}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].NotAfter.Before(valid[j].NotAfter) })
+	if left := valid[len(valid)-1].NotAfter.Sub(now); left < 30*24*time.Hour {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeWebhookCABundleInvalid,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("The caBundle of %s expires in %d day(s)", w.label(), int(left.Hours()/24)),
+			Detail:     "subject: " + valid[len(valid)-1].Subject.String(),
+			Suggestion: "Check that the controller or cert-manager rotates the CA before it expires.",
+		}}
+	}
+	return nil
+}
+
+// failurePolicyFindings flags fail-closed webhooks that can block
+// deployments: those intercepting workloads in kube-system, those
+// intercepting their own namespace, which cannot restart once they are down,
+// and those holding requests for long timeouts.
+func failurePolicyFindings(w admissionWebhook, nsLabels func(string) labels.Set) []types.DiagnosticFinding {
+	if !w.failClosed() {
+		return nil
+	}
+	var findings []types.DiagnosticFinding
+	// An objectSelector usually exempts the controller's own pods.
+	blocksWorkloads := len(w.workloads) > 0 && !w.objSelector
+	resources := strings.Join(w.workloads, ", ")
+	if blocksWorkloads && w.nsSelector.Matches(nsLabels("kube-system")) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeWebhookFailClosedBroad,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s fails closed on %s in every namespace, including kube-system", w.label(), resources),
+			Detail:     "failurePolicy: Fail; namespaceSelector: " + w.nsSelector.String(),
+			Suggestion: "While the webhook is unavailable no workload can be created anywhere, including the cluster components needed to recover. Exclude kube-system and the webhook's own namespace with a namespaceSelector, or use failurePolicy: Ignore.",
+		})
+	}
+	if ns, _, _ := strings.Cut(w.service, "/"); blocksWorkloads && ns != "" && ns != "kube-system" && w.nsSelector.Matches(nsLabels(ns)) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeWebhookSelfDeadlock,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s fails closed on %s in its own namespace %s", w.label(), resources, ns),
+			Suggestion: "If all webhook pods go down, their replacements are rejected by the webhook itself. Exclude the namespace with a namespaceSelector.",
+		})
+	}
+	if w.timeout > 10 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeWebhookTimeoutHigh,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s waits up to %ds before failing", w.label(), w.timeout),
+			Suggestion: "A hung webhook holds every matching API request for the whole timeout, and controllers retrying them pile up. Keep timeoutSeconds at 10 or below.",
+		})
+	}
+	return findings
+}
+
+// --- check_admission_webhooks ---
+
+type CheckAdmissionWebhooksTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *CheckAdmissionWebhooksTool) Name() string { return "check_admission_webhooks" }
+func (t *CheckAdmissionWebhooksTool) Description() string {
+	return "Audit the Validating and MutatingWebhookConfigurations of networking controllers (istiod, gateway controllers, CNI operators): webhook Service, port and ready endpoints, CA bundle validity and expiry, fail-closed webhooks that can block all deployments or their own restart, long timeouts, and optionally webhook latency measured from a probe pod"
+}
+func (t *CheckAdmissionWebhooksTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check the webhook configuration with this name",
+			},
+			"all": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Check every webhook configuration, not only those of networking controllers (default false)",
+			},
+			"measure_latency": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Deploy a probe pod that times requests to each webhook Service (default false)",
+			},
+			"source_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to deploy the latency probe pod in",
+			},
+		},
+	}
+}
+
+func (t *CheckAdmissionWebhooksTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "name", "")
+	all, _ := args["all"].(bool)
+	measure, _ := args["measure_latency"].(bool)
+	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
+
+	var webhooks []admissionWebhook
+	for _, gvr := range []schema.GroupVersionResource{validatingWebhooksGVR, mutatingWebhooksGVR} {
+		list, err := t.listResource(ctx, gvr, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		for _, cfg := range list.Items {
+			if name != "" && cfg.GetName() != name {
+				continue
+			}
+			if cfg.GetKind() == "" {
+				cfg.SetKind(map[string]string{"validatingwebhookconfigurations": "ValidatingWebhookConfiguration", "mutatingwebhookconfigurations": "MutatingWebhookConfiguration"}[gvr.Resource])
+			}
+			for _, w := range parseAdmissionWebhooks(cfg) {
+				if all || name != "" || w.owner != "" {
+					webhooks = append(webhooks, w)
+				}
+			}
+		}
+	}
+
+	nsLabels := make(map[string]labels.Set)
+	if list, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+		for _, ns := range list.Items {
+			nsLabels[ns.GetName()] = ns.GetLabels()
+		}
+	}
+	labelsOf := func(ns string) labels.Set {
+		if l, ok := nsLabels[ns]; ok {
+			return l
+		}
+		return labels.Set{"kubernetes.io/metadata.name": ns}
+	}
+
+	var findings []types.DiagnosticFinding
+	owners := make(map[string]bool)
+	services := make(map[string]admissionWebhook)
+	now := time.Now()
+	for _, w := range webhooks {
+		if w.owner != "" {
+			owners[w.owner] = true
+		}
+		findings = append(findings, t.webhookServiceFindings(ctx, w)...)
+		findings = append(findings, caBundleFindings(w, now)...)
+		findings = append(findings, failurePolicyFindings(w, labelsOf)...)
+		if w.service != "" {
+			key := fmt.Sprintf("%s:%d%s", w.service, w.port, w.path)
+			if prev, ok := services[key]; !ok || w.timeout < prev.timeout {
+				services[key] = w
+			}
+		}
+	}
+	ownerList := make([]string, 0, len(owners))
+	for o := range owners {
+		ownerList = append(ownerList, o)
+	}
+	sort.Strings(ownerList)
+	findings = append([]types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("%d admission webhook(s) checked", len(webhooks)),
+		Detail:   "networking controllers: " + orDefault(strings.Join(ownerList, ", "), "none"),
+	}}, findings...)
+
+	if measure && t.ProbeManager != nil {
+		keys := make([]string, 0, len(services))
+		for k := range services {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f, err := t.measureWebhookLatency(ctx, services[k], sourceNS)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, f...)
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+// webhookServiceFindings checks that the Service a webhook calls exists,
+// exposes the webhook port and has ready endpoints.
+func (t *CheckAdmissionWebhooksTool) webhookServiceFindings(ctx context.Context, w admissionWebhook) []types.DiagnosticFinding {
+	if w.service == "" {
+		return nil
+	}
+	severity, impact := types.SeverityWarning, "the API server skips it (failurePolicy: Ignore), so sidecars are not injected or invalid resources are admitted"
+	if w.failClosed() {
+		severity, impact = types.SeverityCritical, "every request it intercepts is rejected (failurePolicy: Fail)"
+	}
+	ns, svcName, _ := strings.Cut(w.service, "/")
+	svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		return []types.DiagnosticFinding{{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeWebhookServiceMissing,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s calls Service %s, which does not exist: %s", w.label(), w.service, impact),
+			Suggestion: "Reinstall the controller, or delete the webhook configuration left behind by an uninstall.",
+		}}
+	}
+	hasPort := false
+	ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+	for _, p := range ports {
+		if pm, ok := p.(map[string]interface{}); ok && int64(toInt(pm["port"])) == w.port {
+			hasPort = true
+		}
+	}
+	if !hasPort {
+		return []types.DiagnosticFinding{{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeWebhookServiceMissing,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("%s calls port %d of Service %s, which the Service does not expose: %s", w.label(), w.port, w.service, impact),
+			Suggestion: "Align clientConfig.service.port with the Service ports.",
+		}}
+	}
+	health := t.l4BackendHealth(ctx, ns, svcName)
+	if health.readyCount == 0 {
+		return []types.DiagnosticFinding{{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeWebhookNoReadyEndpoints,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("Service %s of %s has no ready endpoints: %s", w.service, w.label(), impact),
+			Suggestion: "Check the controller pods behind the Service (get_infra_logs, describe the pods).",
+		}}
+	}
+	return []types.DiagnosticFinding{{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Resource: w.ref(),
+		Summary:  fmt.Sprintf("%s: Service %s has %d ready endpoint(s)", w.label(), w.service, health.readyCount),
+		Detail:   fmt.Sprintf("owner: %s; failurePolicy: %s; timeoutSeconds: %d", orDefault(w.owner, "unknown"), w.failurePolicy, w.timeout),
+	}}
+}
+
+// measureWebhookLatency times POST requests to a webhook Service from a
+// probe pod. The webhook answers the empty body with an error status, which
+// still measures the network path, the TLS handshake and the server.
+func (t *CheckAdmissionWebhooksTool) measureWebhookLatency(ctx context.Context, w admissionWebhook, sourceNS string) ([]types.DiagnosticFinding, error) {
+	ns, svcName, _ := strings.Cut(w.service, "/")
+	targetURL := fmt.Sprintf("https://%s.%s.svc:%d%s", svcName, ns, w.port, w.path)
+	if containsShellMeta(targetURL) {
+		return nil, nil
+	}
+	const count = 5
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:      probes.ProbeTypeLatency,
+		Namespace: sourceNS,
+		Command:   []string{"sh", "-c", latencyProbeScript("POST", targetURL, "Content-Type: application/json", true, count, 200, int(w.timeout))},
+		Timeout:   latencyProbePodTimeout(int(w.timeout)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var findings []types.DiagnosticFinding
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+	var ok []latencySample
+	failures := make(map[string]int)
+	for _, s := range parseLatencySamples(result.Output) {
+		if s.failed() {
+			reason := curlExitReasons[s.exitCode]
+			if reason == "" {
+				reason = fmt.Sprintf("curl exit code %d", s.exitCode)
+			}
+			failures[reason]++
+			continue
+		}
+		ok = append(ok, s)
+	}
+	if len(ok) == 0 {
+		detail := formatCounts(failures)
+		if result.Error != "" {
+			detail = result.Error + "; " + detail
+		}
+		return append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeWebhookLatencyHigh,
+			Resource:   w.ref(),
+			Summary:    fmt.Sprintf("No request from a probe pod to %s (%s) got a response", targetURL, w.label()),
+			Detail:     detail,
+			Suggestion: "The API server calls webhooks from the control plane network, so also check that NetworkPolicies and firewalls allow it to reach the webhook pods on their target port.",
+		}), nil
+	}
+	total := phaseStats(ok, "total")
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Resource: w.ref(),
+		Summary:  fmt.Sprintf("%s answers in p50=%.0fms p95=%.0fms (timeout %ds)", targetURL, total.p50, total.p95, w.timeout),
+		Detail:   fmt.Sprintf("%d/%d requests answered; tls p95=%.0fms", len(ok), count, phaseStats(ok, "tls").p95),
+	}
+	if total.p95 > float64(w.timeout)*1000/2 {
+		f.Severity = types.SeverityWarning
+		f.Code = types.CodeWebhookLatencyHigh
+		f.Summary = fmt.Sprintf("%s answers in p95=%.0fms, more than half its %ds timeout", targetURL, total.p95, w.timeout)
+		f.Suggestion = "Slow webhooks delay every matching API request and time out under load; check the webhook pods' CPU limits and replicas."
+	}
+	return append(findings, f), nil
+}
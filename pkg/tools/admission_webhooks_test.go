@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+// testCABundle returns a base64 PEM CA valid until notAfter.
+func testCABundle(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook-ca"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func webhookConfig(kind, name string, webhooks ...interface{}) *unstructured.Unstructured {
+	return ipamObj("admissionregistration.k8s.io/v1", kind, "", name, map[string]interface{}{"webhooks": webhooks})
+}
+
+func webhookEntry(name, policy, service, caBundle string, nsSelector map[string]interface{}, resources ...interface{}) map[string]interface{} {
+	ns, svc, _ := strings.Cut(service, "/")
+	w := map[string]interface{}{
+		"name":          name,
+		"failurePolicy": policy,
+		"clientConfig": map[string]interface{}{
+			"service":  map[string]interface{}{"namespace": ns, "name": svc, "path": "/validate"},
+			"caBundle": caBundle,
+		},
+		"rules": []interface{}{map[string]interface{}{"operations": []interface{}{"CREATE", "UPDATE"}, "resources": resources}},
+	}
+	if nsSelector != nil {
+		w["namespaceSelector"] = nsSelector
+	}
+	return w
+}
+
+func TestCABundleFindings(t *testing.T) {
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		caBundle string
+		want     string
+	}{
+		{testCABundle(t, now.AddDate(1, 0, 0)), ""},
+		{testCABundle(t, now.AddDate(0, 0, 10)), "expires in 10 day(s)"},
+		{testCABundle(t, now.AddDate(0, 0, -1)), "expired on 2026-10-14"},
+		{base64.StdEncoding.EncodeToString([]byte("not a cert")), "no readable PEM certificate"},
+		{"", "has no caBundle"},
+	} {
+		w := admissionWebhook{kind: "ValidatingWebhookConfiguration", config: "istio-validator", name: "validation.istio.io", failurePolicy: "Fail", service: "istio-system/istiod", caBundle: tc.caBundle}
+		findings := caBundleFindings(w, now)
+		if tc.want == "" {
+			if len(findings) != 0 {
+				t.Errorf("caBundleFindings() = %+v, want none", findings)
+			}
+			continue
+		}
+		if len(findings) != 1 || !strings.Contains(findings[0].Summary, tc.want) {
+			t.Errorf("caBundleFindings() = %+v, want %q", findings, tc.want)
+		}
+	}
+}
+
+func TestFailurePolicyFindings(t *testing.T) {
+	nsLabels := func(ns string) labels.Set { return labels.Set{"kubernetes.io/metadata.name": ns} }
+	cfg := webhookConfig("MutatingWebhookConfiguration", "policy-injector",
+		webhookEntry("all.example.com", "Fail", "policy/injector", "", nil, "pods"),
+		webhookEntry("scoped.example.com", "Fail", "policy/injector", "", map[string]interface{}{
+			"matchExpressions": []interface{}{map[string]interface{}{"key": "kubernetes.io/metadata.name", "operator": "NotIn", "values": []interface{}{"kube-system", "policy"}}},
+		}, "pods"),
+		webhookEntry("ignore.example.com", "Ignore", "policy/injector", "", nil, "*"),
+	)
+	webhooks := parseAdmissionWebhooks(*cfg)
+	if len(webhooks) != 3 || webhooks[0].timeout != 10 || webhooks[0].port != 443 {
+		t.Fatalf("parseAdmissionWebhooks() = %+v", webhooks)
+	}
+	var codes []string
+	for _, w := range webhooks {
+		for _, f := range failurePolicyFindings(w, nsLabels) {
+			codes = append(codes, w.name+" "+string(f.Code))
+		}
+	}
+	want := "all.example.com WHK004_FAIL_CLOSED_BROAD,all.example.com WHK005_SELF_DEADLOCK"
+	if got := strings.Join(codes, ","); got != want {
+		t.Errorf("failurePolicyFindings() codes = %s, want %s", got, want)
+	}
+}
+
+func TestCheckAdmissionWebhooks(t *testing.T) {
+	ca := testCABundle(t, time.Now().AddDate(1, 0, 0))
+	istiod := ipamObj("v1", "Service", "istio-system", "istiod", map[string]interface{}{
+		"spec": map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(443)}}},
+	})
+	istiodEndpoints := ipamObj("v1", "Endpoints", "istio-system", "istiod", map[string]interface{}{
+		"subsets": []interface{}{map[string]interface{}{"addresses": []interface{}{map[string]interface{}{"ip": "10.244.0.5"}}}},
+	})
+	objs := []runtime.Object{
+		webhookConfig("ValidatingWebhookConfiguration", "istio-validator-istio-system",
+			webhookEntry("rev.validation.istio.io", "Ignore", "istio-system/istiod", ca, nil, "gateways", "virtualservices")),
+		webhookConfig("MutatingWebhookConfiguration", "cilium-operator",
+			webhookEntry("pods.cilium.io", "Fail", "kube-system/cilium-operator", ca, nil, "pods")),
+		webhookConfig("ValidatingWebhookConfiguration", "billing-validator",
+			webhookEntry("billing.example.com", "Fail", "billing/validator", "", nil, "pods")),
+		istiod, istiodEndpoints,
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		validatingWebhooksGVR: "ValidatingWebhookConfigurationList", mutatingWebhooksGVR: "MutatingWebhookConfigurationList",
+		namespacesGVR: "NamespaceList",
+	}, objs...)
+	tool := &CheckAdmissionWebhooksTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info 2 admission webhook(s) checked",
+		"ok ValidatingWebhookConfiguration istio-validator-istio-system webhook rev.validation.istio.io: Service istio-system/istiod has 1 ready endpoint(s)",
+		"critical MutatingWebhookConfiguration cilium-operator webhook pods.cilium.io calls Service kube-system/cilium-operator, which does not exist: every request it intercepts is rejected (failurePolicy: Fail) WHK001_SERVICE_MISSING",
+		"warning MutatingWebhookConfiguration cilium-operator webhook pods.cilium.io fails closed on pods in every namespace, including kube-system WHK004_FAIL_CLOSED_BROAD",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "billing") {
+		t.Errorf("non-networking webhook checked without all:\n%s", all)
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"all": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "billing-validator webhook billing.example.com has no caBundle") {
+		t.Errorf("expected the billing webhook to be checked with all:\n%s", all)
+	}
+}
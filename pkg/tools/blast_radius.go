@@ -0,0 +1,377 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// blastRadiusKinds are the resource kinds estimate_blast_radius understands,
+// with the API versions tried in order.
+var blastRadiusKinds = map[string][]schema.GroupVersionResource{
+	"Gateway":             {gatewaysV1GVR, gatewaysV1B1GVR},
+	"HTTPRoute":           {httpRoutesV1GVR, httpRoutesV1B1GVR},
+	"GRPCRoute":           {grpcRoutesV1GVR, grpcRoutesV1B1GVR},
+	"Service":             {servicesGVR},
+	"NetworkPolicy":       {networkPoliciesGVR},
+	"AuthorizationPolicy": {apV1GVR, apV1B1GVR},
+	"PeerAuthentication":  {paV1GVR, paV1B1GVR},
+	"DestinationRule":     {drV1GVR, drV1B1GVR},
+}
+
+// blastRadius is what depends on a resource: the routes and Services whose
+// traffic goes through it, and the pods it selects.
+type blastRadius struct {
+	Routes   []string
+	Services []string // "namespace/name"
+	Pods     int
+	// MeshWide is set for selector-less Istio policies and exported
+	// DestinationRules in the root namespace.
+	MeshWide bool
+	Notes    []string
+}
+
+func (br blastRadius) namespaces() []string {
+	seen := make(map[string]bool)
+	for _, s := range append(append([]string{}, br.Services...), br.Routes...) {
+		// Routes are labelled "Kind ns/name".
+		if i := strings.LastIndex(s, " "); i >= 0 {
+			s = s[i+1:]
+		}
+		if ns, _, ok := strings.Cut(s, "/"); ok {
+			seen[ns] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+// destinationRuleHostServices resolves a DestinationRule host to the
+// Services it applies to. Short names are relative to the rule's namespace;
+// "*" and "*.<ns>.svc.cluster.local" style wildcards match several Services.
+func destinationRuleHostServices(host, drNs string, services []unstructured.Unstructured) []string {
+	var out []string
+	for i := range services {
+		s := &services[i]
+		fqdn := fmt.Sprintf("%s.%s.svc.cluster.local", s.GetName(), s.GetNamespace())
+		match := false
+		switch {
+		case host == "*":
+			match = true
+		case strings.HasPrefix(host, "*."):
+			match = strings.HasSuffix(fqdn, host[1:])
+		case !strings.Contains(host, "."):
+			match = s.GetName() == host && s.GetNamespace() == drNs
+		default:
+			match = fqdn == host || strings.HasPrefix(fqdn, host+".")
+		}
+		if match {
+			out = append(out, s.GetNamespace()+"/"+s.GetName())
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// servicesSelectingPods returns the Services whose selector matches any of pods.
+func servicesSelectingPods(services []unstructured.Unstructured, pods []podPorts) []string {
+	var out []string
+	for i := range services {
+		s := &services[i]
+		sel, _, _ := unstructured.NestedStringMap(s.Object, "spec", "selector")
+		if len(sel) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(sel)
+		for _, p := range pods {
+			if p.Namespace == s.GetNamespace() && selector.Matches(labels.Set(p.Labels)) {
+				out = append(out, s.GetNamespace()+"/"+s.GetName())
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// routeLabels returns "Kind ns/name" for routes.
+func routeLabels(routes []routeInfo) []string {
+	out := make([]string, 0, len(routes))
+	for _, r := range routes {
+		out = append(out, fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// blastRadiusFindings turns a blast radius into findings. endpoints holds the
+// ready endpoint count per affected Service.
+func blastRadiusFindings(ref *types.ResourceRef, action string, br blastRadius, endpoints map[string]int) []types.DiagnosticFinding {
+	key := ref.Kind + " " + ref.Namespace + "/" + ref.Name
+	ready := 0
+	for _, s := range br.Services {
+		ready += endpoints[s]
+	}
+	nss := br.namespaces()
+
+	if len(br.Services) == 0 && len(br.Routes) == 0 && br.Pods == 0 && !br.MeshWide {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityOK, Category: types.CategoryRouting, Resource: ref,
+			Summary: fmt.Sprintf("No routes, Services or pods depend on %s", key),
+			Detail:  strings.Join(br.Notes, "; "),
+		}}
+	}
+
+	severity := types.SeverityWarning
+	switch {
+	case ready == 0 && !br.MeshWide:
+		severity = types.SeverityInfo
+	case action == "delete" && (ref.Kind == "Gateway" || ref.Kind == "Service" || strings.HasSuffix(ref.Kind, "Route")):
+		// Deleting the traffic path itself fails every request through it.
+		severity = types.SeverityCritical
+	case br.MeshWide || len(nss) > 1:
+		severity = types.SeverityCritical
+	}
+
+	scope := fmt.Sprintf("%d namespace(s)", len(nss))
+	if br.MeshWide {
+		scope = "the whole mesh"
+	}
+	detail := []string{
+		fmt.Sprintf("routes: %s", orDefault(truncateList(br.Routes, 10), "none")),
+		fmt.Sprintf("services: %s", orDefault(truncateList(br.Services, 10), "none")),
+		fmt.Sprintf("namespaces: %s", orDefault(truncateList(nss, 10), "none")),
+	}
+	if br.Pods > 0 {
+		detail = append(detail, fmt.Sprintf("selected pods: %d", br.Pods))
+	}
+	detail = append(detail, br.Notes...)
+
+	findings := []types.DiagnosticFinding{{
+		Severity: severity, Category: types.CategoryRouting, Resource: ref,
+		Summary: fmt.Sprintf("Blast radius of %s %s: %d route(s), %d Service(s), %d ready endpoint(s) across %s",
+			action, key, len(br.Routes), len(br.Services), ready, scope),
+		Detail:     strings.Join(detail, "; "),
+		Suggestion: "Roll the change out to a copy or a single namespace first, and watch the listed Services' error rates while it propagates.",
+	}}
+	for _, s := range br.Services {
+		ns, name, _ := strings.Cut(s, "/")
+		sev := types.SeverityInfo
+		if endpoints[s] == 0 {
+			sev = types.SeverityOK
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryRouting,
+			Resource: &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name, APIVersion: "v1"},
+			Summary:  fmt.Sprintf("Service %s: %d ready endpoint(s) affected", s, endpoints[s]),
+		})
+	}
+	return findings
+}
+
+// --- estimate_blast_radius ---
+
+type EstimateBlastRadiusTool struct{ BaseTool }
+
+func (t *EstimateBlastRadiusTool) Name() string { return "estimate_blast_radius" }
+func (t *EstimateBlastRadiusTool) Description() string {
+	return "Estimate the blast radius of changing or deleting a Gateway, route, Service, NetworkPolicy, AuthorizationPolicy, PeerAuthentication or DestinationRule: dependent routes, Services, namespaces and ready endpoints"
+}
+func (t *EstimateBlastRadiusTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "Resource kind: Gateway, HTTPRoute, GRPCRoute, Service, NetworkPolicy, AuthorizationPolicy, PeerAuthentication or DestinationRule",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Resource name",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace",
+			},
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "change or delete (default delete)",
+			},
+		},
+		"required": []string{"kind", "name", "namespace"},
+	}
+}
+
+func (t *EstimateBlastRadiusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	kind := getStringArg(args, "kind", "")
+	name := getStringArg(args, "name", "")
+	ns := getStringArg(args, "namespace", "")
+	action := strings.ToLower(getStringArg(args, "action", "delete"))
+
+	gvrs, ok := blastRadiusKinds[kind]
+	if !ok || name == "" || ns == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "kind, name and namespace are required; kind is one of Gateway, HTTPRoute, GRPCRoute, Service, NetworkPolicy, AuthorizationPolicy, PeerAuthentication, DestinationRule",
+		}
+	}
+	if action != "delete" && action != "change" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unsupported action %q: use change or delete", action),
+		}
+	}
+
+	var obj *unstructured.Unstructured
+	var err error
+	if len(gvrs) == 2 {
+		obj, err = getWithFallback(ctx, t.Clients.Dynamic, gvrs[0], gvrs[1], ns, name)
+	} else {
+		obj, err = t.Clients.Dynamic.Resource(gvrs[0]).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind, ns, name, err)
+	}
+	ref := &types.ResourceRef{Kind: kind, Namespace: ns, Name: name, APIVersion: obj.GetAPIVersion()}
+
+	br, err := t.estimate(ctx, kind, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make(map[string]int)
+	for _, s := range br.Services {
+		svcNs, svcName, _ := strings.Cut(s, "/")
+		if ep, err := t.Clients.Clientset.CoreV1().Endpoints(svcNs).Get(ctx, svcName, metav1.GetOptions{}); err == nil {
+			for _, c := range readyEndpointNodes(ep) {
+				endpoints[s] += c
+			}
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), blastRadiusFindings(ref, action, br, endpoints), ns, ""), nil
+}
+
+func (t *EstimateBlastRadiusTool) estimate(ctx context.Context, kind string, obj *unstructured.Unstructured) (blastRadius, error) {
+	var br blastRadius
+	ns, name := obj.GetNamespace(), obj.GetName()
+
+	switch kind {
+	case "Gateway":
+		var attached []routeInfo
+		for _, r := range t.listRoutes(ctx) {
+			if routeAttachedToGateway(r, ns, name) {
+				attached = append(attached, r)
+			}
+		}
+		br.Routes = routeLabels(attached)
+		br.Services = gatewayRouteBackends(attached, ns, name)
+		return br, nil
+
+	case "HTTPRoute", "GRPCRoute":
+		r := routeInfo{kind: kind, name: name, namespace: ns, obj: obj.Object}
+		br.Routes = routeLabels([]routeInfo{r})
+		br.Services = routeBackends(r)
+		return br, nil
+
+	case "Service":
+		key := ns + "/" + name
+		br.Services = []string{key}
+		br.Routes = routesByBackend(t.listRoutes(ctx))[key]
+		if ingList, err := t.listResource(ctx, ingressGVR, ns); err == nil {
+			for i := range ingList.Items {
+				if containsString(ingressBackends(&ingList.Items[i]), key) {
+					br.Routes = append(br.Routes, "Ingress "+ns+"/"+ingList.Items[i].GetName())
+				}
+			}
+		}
+		sort.Strings(br.Routes)
+		return br, nil
+
+	case "DestinationRule":
+		host, _, _ := unstructured.NestedString(obj.Object, "spec", "host")
+		svcList, err := t.listResource(ctx, servicesGVR, "")
+		if err != nil {
+			return br, fmt.Errorf("failed to list services: %w", err)
+		}
+		br.Services = destinationRuleHostServices(host, ns, svcList.Items)
+		exportTo, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "exportTo")
+		if len(exportTo) == 0 || containsString(exportTo, "*") {
+			br.MeshWide = ns == istioRootNamespace
+			br.Notes = append(br.Notes, "clients in every namespace use this rule (exportTo is unset or \"*\")")
+		} else {
+			br.Notes = append(br.Notes, fmt.Sprintf("clients in namespaces %s use this rule", strings.Join(exportTo, ", ")))
+		}
+		if len(br.Services) == 0 {
+			br.Notes = append(br.Notes, fmt.Sprintf("host %q matches no Service (it may be a ServiceEntry host)", host))
+		}
+		return br, nil
+	}
+
+	// Policies: find the pods they select, then the Services fronting them.
+	podNs := ns
+	meshWide := (kind == "AuthorizationPolicy" || kind == "PeerAuthentication") && ns == istioRootNamespace
+	if meshWide {
+		podNs = ""
+	}
+	podList, err := t.listResource(ctx, podsGVR, podNs)
+	if err != nil {
+		return br, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods := make([]podPorts, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, podPortsFrom(&podList.Items[i]))
+	}
+
+	var sel labels.Selector
+	if kind == "NetworkPolicy" {
+		selObj, present, _ := unstructured.NestedMap(obj.Object, "spec", "podSelector")
+		if !present {
+			selObj, present = map[string]interface{}{}, true
+		}
+		if sel, err = parseLabelSelector(selObj, present); err != nil {
+			return br, fmt.Errorf("invalid podSelector on %s/%s: %w", ns, name, err)
+		}
+	} else if matchLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels"); len(matchLabels) > 0 {
+		sel = labels.SelectorFromSet(matchLabels)
+	} else {
+		sel = labels.Everything()
+		if meshWide {
+			br.MeshWide = true
+			br.Notes = append(br.Notes, fmt.Sprintf("no selector in the root namespace %s: applies to every workload in the mesh", istioRootNamespace))
+		} else {
+			br.Notes = append(br.Notes, "no selector: applies to every workload in the namespace")
+		}
+	}
+	if kind == "AuthorizationPolicy" {
+		if refs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "targetRefs"); len(refs) > 0 {
+			br.Notes = append(br.Notes, "targetRefs policy: applied by the referenced Gateway or waypoint, pod selection is approximate")
+		}
+	}
+
+	selected := selectPods(pods, sel, func(string) bool { return true })
+	br.Pods = len(selected)
+	if len(selected) > 0 {
+		svcList, err := t.listResource(ctx, servicesGVR, podNs)
+		if err != nil {
+			return br, fmt.Errorf("failed to list services: %w", err)
+		}
+		br.Services = servicesSelectingPods(svcList.Items, selected)
+	}
+	switch kind {
+	case "NetworkPolicy":
+		br.Notes = append(br.Notes, "deleting it removes isolation from the selected pods unless another policy selects them; changing it can drop their allowed traffic")
+	case "PeerAuthentication":
+		br.Notes = append(br.Notes, "changing the mTLS mode affects every client of the selected pods, including un-meshed ones")
+	}
+	return br, nil
+}
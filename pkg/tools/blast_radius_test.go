@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testService(ns, name string, selector map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": ns},
+		"spec":     map[string]interface{}{"selector": selector},
+	}}
+}
+
+func TestDestinationRuleHostServices(t *testing.T) {
+	services := []unstructured.Unstructured{
+		testService("shop", "web", nil),
+		testService("shop", "api", nil),
+		testService("billing", "web", nil),
+	}
+	cases := map[string][]string{
+		"web":                        {"shop/web"},
+		"web.billing":                {"billing/web"},
+		"api.shop.svc.cluster.local": {"shop/api"},
+		"*.shop.svc.cluster.local":   {"shop/api", "shop/web"},
+		"*":                          {"billing/web", "shop/api", "shop/web"},
+		"payments.example.com":       nil,
+	}
+	for host, want := range cases {
+		if got := destinationRuleHostServices(host, "shop", services); !reflect.DeepEqual(got, want) {
+			t.Errorf("destinationRuleHostServices(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestServicesSelectingPods(t *testing.T) {
+	services := []unstructured.Unstructured{
+		testService("shop", "web", map[string]interface{}{"app": "web"}),
+		testService("shop", "api", map[string]interface{}{"app": "api"}),
+		testService("other", "web", map[string]interface{}{"app": "web"}),
+		testService("shop", "external", nil),
+	}
+	pods := []podPorts{{Namespace: "shop", Name: "web-1", Labels: map[string]string{"app": "web"}}}
+	if got := servicesSelectingPods(services, pods); !reflect.DeepEqual(got, []string{"shop/web"}) {
+		t.Errorf("servicesSelectingPods() = %v", got)
+	}
+}
+
+func TestBlastRadiusFindings(t *testing.T) {
+	gw := &types.ResourceRef{Kind: "Gateway", Namespace: "infra", Name: "public"}
+	br := blastRadius{
+		Routes:   []string{"HTTPRoute shop/web"},
+		Services: []string{"shop/web", "shop/api"},
+	}
+	findings := blastRadiusFindings(gw, "delete", br, map[string]int{"shop/web": 3})
+	if len(findings) != 3 || findings[0].Severity != types.SeverityCritical {
+		t.Fatalf("expected a critical summary and one finding per Service, got %+v", findings)
+	}
+	if findings[2].Severity != types.SeverityOK {
+		t.Errorf("a Service without ready endpoints should not be flagged, got %s", findings[2].Severity)
+	}
+	if got := br.namespaces(); !reflect.DeepEqual(got, []string{"shop"}) {
+		t.Errorf("namespaces() = %v", got)
+	}
+
+	if f := blastRadiusFindings(gw, "change", br, map[string]int{"shop/web": 3}); f[0].Severity != types.SeverityWarning {
+		t.Errorf("expected a warning for a change within one namespace, got %s", f[0].Severity)
+	}
+
+	np := &types.ResourceRef{Kind: "NetworkPolicy", Namespace: "shop", Name: "unused"}
+	if f := blastRadiusFindings(np, "delete", blastRadius{}, nil); len(f) != 1 || f[0].Severity != types.SeverityOK {
+		t.Errorf("expected a single OK finding for an unused policy, got %+v", f)
+	}
+}
@@ -0,0 +1,649 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// flaggerBuiltinMetrics are the Flagger metrics that need no MetricTemplate.
+var flaggerBuiltinMetrics = map[string]bool{"request-success-rate": true, "request-duration": true}
+
+// flaggerSecretProviders are the MetricTemplate providers that authenticate
+// with a secretRef.
+var flaggerSecretProviders = map[string]bool{"datadog": true, "newrelic": true, "dynatrace": true, "splunk": true}
+
+// argoProviderFields lists, per Argo Rollouts metric provider, the fields
+// a measurement cannot be taken without.
+var argoProviderFields = map[string][]string{
+	"prometheus": {"address", "query"},
+	"web":        {"url"},
+	"wavefront":  {"address", "query"},
+	"graphite":   {"address", "query"},
+	"kayenta":    {"address"},
+	"skywalking": {"address", "query"},
+	"newRelic":   {"query"},
+	"influxdb":   {"query"},
+}
+
+// canaryRouteWeight returns the percentage of traffic the first
+// VirtualService http route or HTTPRoute rule routing to the canary sends
+// it. isCanary tells the canary destination (VirtualService) or backendRef
+// (HTTPRoute) apart; found is false when no rule routes to the canary.
+func canaryRouteWeight(route *unstructured.Unstructured, isCanary func(map[string]interface{}) bool) (percent int, found bool) {
+	rulesField, destsField, defaultWeight := "rules", "backendRefs", 1
+	if route.GetKind() == "VirtualService" {
+		rulesField, destsField, defaultWeight = "http", "route", 0
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", rulesField)
+	for _, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		dests, _ := rule[destsField].([]interface{})
+		total, canary := 0, 0
+		for _, d := range dests {
+			dest, _ := d.(map[string]interface{})
+			weight := defaultWeight
+			if w, ok := dest["weight"]; ok {
+				weight = toInt(w)
+			} else if len(dests) == 1 {
+				weight = 100
+			}
+			target := dest
+			if destination, ok := dest["destination"].(map[string]interface{}); ok {
+				target = destination
+			}
+			total += weight
+			if isCanary(target) {
+				canary += weight
+				found = true
+			}
+		}
+		if found {
+			if total == 0 {
+				return 0, true
+			}
+			return canary * 100 / total, true
+		}
+	}
+	return 0, false
+}
+
+// hostIs reports whether a VirtualService destination host names service
+// in namespace ns, short or fully qualified.
+func hostIs(host, service, ns string) bool {
+	return host == service || strings.HasPrefix(host, service+"."+ns+".") || host == service+"."+ns
+}
+
+// --- check_canary ---
+
+type CheckCanaryTool struct{ BaseTool }
+
+func (t *CheckCanaryTool) Name() string { return "check_canary" }
+func (t *CheckCanaryTool) Description() string {
+	return "Validate in-progress canary rollouts of Flagger Canaries and Argo Rollouts: phase and failed checks, route weights against the controller's canary weight, analysis runs, and the metric providers of MetricTemplates and AnalysisTemplates"
+}
+func (t *CheckCanaryTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to check (default: all namespaces)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of one Canary or Rollout to check",
+			},
+		},
+	}
+}
+
+func (t *CheckCanaryTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+
+	canaryGVR, _, hasFlagger := t.servedResource("flagger.app", "Canary")
+	rolloutGVR, _, hasArgo := t.servedResource("argoproj.io", "Rollout")
+	if !hasFlagger && !hasArgo {
+		return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Summary:    "Neither Flagger nor Argo Rollouts is installed; there is no canary controller to check",
+			Suggestion: "Weighted VirtualServices and HTTPRoutes are checked by validate_istio_config and scan_gateway_misconfigs; use design_canary to set up a controller-driven canary.",
+		}}, ns, "canary"), nil
+	}
+
+	var findings []types.DiagnosticFinding
+	checked := 0
+	for _, source := range []struct {
+		served bool
+		gvr    schema.GroupVersionResource
+		check  func(context.Context, *unstructured.Unstructured) []types.DiagnosticFinding
+	}{
+		{hasFlagger, canaryGVR, t.flaggerCanaryFindings},
+		{hasArgo, rolloutGVR, t.rolloutFindings},
+	} {
+		if !source.served {
+			continue
+		}
+		list, err := t.listResource(ctx, source.gvr, ns)
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInternalError,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("failed to list %s", source.gvr.Resource),
+				Detail:  err.Error(),
+				Cause:   err,
+			}
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if name != "" && obj.GetName() != name {
+				continue
+			}
+			if out := source.check(ctx, obj); out != nil {
+				checked++
+				findings = append(findings, out...)
+			}
+		}
+	}
+	if checked == 0 {
+		target := "canary rollouts"
+		if name != "" {
+			target = fmt.Sprintf("Canary or canary Rollout named %s", name)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("No %s found", target),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "canary"), nil
+}
+
+// flaggerCanaryFindings checks a Flagger Canary: its phase and failed
+// checks, the weight of the route Flagger manages, and its metrics.
+func (t *CheckCanaryTool) flaggerCanaryFindings(ctx context.Context, c *unstructured.Unstructured) []types.DiagnosticFinding {
+	ns := c.GetNamespace()
+	ref := &types.ResourceRef{Kind: "Canary", Namespace: ns, Name: c.GetName(), APIVersion: c.GetAPIVersion()}
+	key := ns + "/" + c.GetName()
+	phase := orDefault(getNestedString(c.Object, "status", "phase"), "Unknown")
+	weight, _, _ := unstructured.NestedFieldNoCopy(c.Object, "status", "canaryWeight")
+	failed, _, _ := unstructured.NestedFieldNoCopy(c.Object, "status", "failedChecks")
+	threshold, _, _ := unstructured.NestedFieldNoCopy(c.Object, "spec", "analysis", "threshold")
+
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityOK,
+		Category: types.CategoryRouting,
+		Resource: ref,
+		Summary:  fmt.Sprintf("Canary %s (Flagger): phase %s, canary weight %d%%, %d/%d failed checks", key, phase, toInt(weight), toInt(failed), toInt(threshold)),
+	}}
+
+	switch {
+	case phase == "Failed":
+		message := ""
+		conditions, _, _ := unstructured.NestedSlice(c.Object, "status", "conditions")
+		for _, cond := range conditions {
+			if m, ok := cond.(map[string]interface{}); ok && m["type"] == "Promoted" {
+				message, _ = m["message"].(string)
+			}
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryFailed,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Canary %s failed and was rolled back", key),
+			Detail:     message,
+			Suggestion: "Check the metric checks below and the canary pods' logs, fix the new version, then roll out again by changing the Deployment.",
+		})
+	case toInt(failed) > 0:
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryAnalysisFailed,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Canary %s has %d failed checks; Flagger rolls back at %d", key, toInt(failed), toInt(threshold)),
+			Suggestion: "Compare the canary's success rate and latency with the primary's before the threshold is reached.",
+		})
+	}
+
+	metrics, _, _ := unstructured.NestedSlice(c.Object, "spec", "analysis", "metrics")
+	for _, m := range metrics {
+		metric, _ := m.(map[string]interface{})
+		findings = append(findings, t.flaggerMetricFindings(ctx, ref, metric)...)
+	}
+
+	// Flagger names the route it manages after the service.
+	svcName := orDefault(getNestedString(c.Object, "spec", "service", "name"), getNestedString(c.Object, "spec", "targetRef", "name"))
+	canarySvc := svcName + "-canary"
+	var route *unstructured.Unstructured
+	if vs, err := getWithFallback(ctx, t.Clients.Dynamic, vsV1GVR, vsV1B1GVR, ns, svcName); err == nil {
+		route = vs
+	} else if hr, err := getWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns, svcName); err == nil {
+		route = hr
+	}
+	switch {
+	case route == nil && phase != "Unknown" && phase != "Initializing":
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryRouteMissing,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Canary %s has no VirtualService or HTTPRoute %s/%s, so traffic is not shifted", key, ns, svcName),
+			Suggestion: "Check the Flagger logs: Flagger creates the route when the Canary is initialized, unless the mesh provider is wrong.",
+		})
+	case route != nil:
+		percent, found := canaryRouteWeight(route, func(dest map[string]interface{}) bool {
+			host, _ := dest["host"].(string)
+			name, _ := dest["name"].(string)
+			return hostIs(host, canarySvc, ns) || name == canarySvc
+		})
+		if found && percent != toInt(weight) {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeCanaryWeightMismatch,
+				Resource:   &types.ResourceRef{Kind: route.GetKind(), Namespace: ns, Name: route.GetName()},
+				Summary:    fmt.Sprintf("%s %s/%s sends %d%% to %s, but Canary %s is at %d%%", route.GetKind(), ns, route.GetName(), percent, canarySvc, key, toInt(weight)),
+				Suggestion: "Another controller or a GitOps sync may be overwriting the weights Flagger sets; exclude the route from it.",
+			})
+		}
+	}
+	return findings
+}
+
+// flaggerMetricFindings checks the MetricTemplate behind a Canary metric.
+func (t *CheckCanaryTool) flaggerMetricFindings(ctx context.Context, canary *types.ResourceRef, metric map[string]interface{}) []types.DiagnosticFinding {
+	name, _ := metric["name"].(string)
+	tplName := getNestedString(metric, "templateRef", "name")
+	if tplName == "" {
+		if flaggerBuiltinMetrics[name] {
+			return nil
+		}
+		if q, _ := metric["query"].(string); q != "" {
+			return nil
+		}
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryMetricProvider,
+			Resource:   canary,
+			Summary:    fmt.Sprintf("Canary %s/%s metric %q is not a builtin metric and has no templateRef", canary.Namespace, canary.Name, name),
+			Suggestion: "Reference a MetricTemplate with templateRef or use request-success-rate or request-duration.",
+		}}
+	}
+	tplNs := orDefault(getNestedString(metric, "templateRef", "namespace"), canary.Namespace)
+	tplRef := &types.ResourceRef{Kind: "MetricTemplate", Namespace: tplNs, Name: tplName}
+	gvr, _, served := t.servedResource("flagger.app", "MetricTemplate")
+	var tpl *unstructured.Unstructured
+	if served {
+		tpl, _ = t.Clients.Dynamic.Resource(gvr).Namespace(tplNs).Get(ctx, tplName, metav1.GetOptions{})
+	}
+	if tpl == nil {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryMetricProvider,
+			Resource:   canary,
+			Summary:    fmt.Sprintf("Canary %s/%s metric %q references MetricTemplate %s/%s, which does not exist", canary.Namespace, canary.Name, name, tplNs, tplName),
+			Suggestion: "Create the MetricTemplate or fix the templateRef; Flagger fails every check of a metric it cannot query.",
+		}}
+	}
+
+	provider := orDefault(getNestedString(tpl.Object, "spec", "provider", "type"), "prometheus")
+	var problems []string
+	if getNestedString(tpl.Object, "spec", "query") == "" {
+		problems = append(problems, "has no query")
+	}
+	if flaggerSecretProviders[provider] && getNestedString(tpl.Object, "spec", "provider", "secretRef", "name") == "" {
+		problems = append(problems, fmt.Sprintf("has no secretRef for the %s credentials", provider))
+	}
+	if provider != "prometheus" && !flaggerSecretProviders[provider] && provider != "cloudwatch" && getNestedString(tpl.Object, "spec", "provider", "address") == "" {
+		problems = append(problems, fmt.Sprintf("has no %s address", provider))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryRouting,
+		Code:       types.CodeCanaryMetricProvider,
+		Resource:   tplRef,
+		Summary:    fmt.Sprintf("MetricTemplate %s/%s (%s, used by Canary %s/%s) %s", tplNs, tplName, provider, canary.Namespace, canary.Name, strings.Join(problems, " and ")),
+		Suggestion: "Complete the provider of the MetricTemplate; checks of a metric that cannot be queried count as failed.",
+	}}
+}
+
+// rolloutWeight returns the canary weight a Rollout is at: the weight Argo
+// Rollouts reports, or else the last setWeight step reached. known is false
+// for a Rollout that is not between steps.
+func rolloutWeight(r *unstructured.Unstructured) (weight int, known bool) {
+	if w, ok, _ := unstructured.NestedFieldNoCopy(r.Object, "status", "canary", "weights", "canary", "weight"); ok {
+		return toInt(w), true
+	}
+	idx, ok, _ := unstructured.NestedFieldNoCopy(r.Object, "status", "currentStepIndex")
+	steps, _, _ := unstructured.NestedSlice(r.Object, "spec", "strategy", "canary", "steps")
+	if !ok || toInt(idx) >= len(steps) {
+		return 0, false
+	}
+	for i := 0; i <= toInt(idx); i++ {
+		if step, ok := steps[i].(map[string]interface{}); ok {
+			if w, ok := step["setWeight"]; ok {
+				weight = toInt(w)
+			}
+		}
+	}
+	return weight, true
+}
+
+// rolloutTemplates returns the analysis templates of a canary Rollout, as
+// "name" or "cluster/name" for ClusterAnalysisTemplates.
+func rolloutTemplates(canary map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	collect := func(analysis interface{}) {
+		templates, _, _ := unstructured.NestedSlice(map[string]interface{}{"a": analysis}, "a", "templates")
+		for _, t := range templates {
+			tpl, _ := t.(map[string]interface{})
+			name, _ := tpl["templateName"].(string)
+			if cluster, _ := tpl["clusterScope"].(bool); cluster {
+				name = "cluster/" + name
+			}
+			if name != "" {
+				seen[name] = true
+			}
+		}
+	}
+	collect(canary["analysis"])
+	steps, _ := canary["steps"].([]interface{})
+	for _, s := range steps {
+		if step, ok := s.(map[string]interface{}); ok {
+			collect(step["analysis"])
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// rolloutFindings checks a canary Rollout: its phase, the route it shifts
+// traffic with, its analysis runs and templates. Rollouts with another
+// strategy return nil.
+func (t *CheckCanaryTool) rolloutFindings(ctx context.Context, r *unstructured.Unstructured) []types.DiagnosticFinding {
+	canary, ok, _ := unstructured.NestedMap(r.Object, "spec", "strategy", "canary")
+	if !ok {
+		return nil
+	}
+	ns := r.GetNamespace()
+	ref := &types.ResourceRef{Kind: "Rollout", Namespace: ns, Name: r.GetName(), APIVersion: r.GetAPIVersion()}
+	key := ns + "/" + r.GetName()
+	phase := orDefault(getNestedString(r.Object, "status", "phase"), "Unknown")
+	steps, _ := canary["steps"].([]interface{})
+	idx, _, _ := unstructured.NestedFieldNoCopy(r.Object, "status", "currentStepIndex")
+	weight, known := rolloutWeight(r)
+
+	summary := fmt.Sprintf("Rollout %s (Argo Rollouts): phase %s, step %d/%d", key, phase, toInt(idx), len(steps))
+	if known {
+		summary += fmt.Sprintf(", canary weight %d%%", weight)
+	}
+	findings := []types.DiagnosticFinding{{Severity: types.SeverityOK, Category: types.CategoryRouting, Resource: ref, Summary: summary}}
+
+	if aborted, _, _ := unstructured.NestedBool(r.Object, "status", "abort"); aborted || phase == "Degraded" {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryFailed,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Rollout %s is %s", key, map[bool]string{true: "aborted", false: "degraded"}[aborted]),
+			Detail:     getNestedString(r.Object, "status", "message"),
+			Suggestion: "Fix the canary version and retry the rollout with `kubectl argo rollouts retry rollout`, or roll back with `kubectl argo rollouts undo`.",
+		})
+	}
+
+	findings = append(findings, t.rolloutRouteFindings(ctx, r, canary, weight, known)...)
+	findings = append(findings, t.analysisRunFindings(ctx, r)...)
+	for _, name := range rolloutTemplates(canary) {
+		findings = append(findings, t.analysisTemplateFindings(ctx, ref, name)...)
+	}
+	return findings
+}
+
+// rolloutRouteFindings checks that the Services, VirtualService,
+// DestinationRule subsets and HTTPRoute a Rollout shifts traffic with exist
+// and carry the canary weight.
+func (t *CheckCanaryTool) rolloutRouteFindings(ctx context.Context, r *unstructured.Unstructured, canary map[string]interface{}, weight int, known bool) []types.DiagnosticFinding {
+	ns := r.GetNamespace()
+	key := ns + "/" + r.GetName()
+	var findings []types.DiagnosticFinding
+	missing := func(kind, objNs, name, what string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryRouteMissing,
+			Resource:   &types.ResourceRef{Kind: kind, Namespace: objNs, Name: name},
+			Summary:    fmt.Sprintf("Rollout %s uses %s %s/%s%s, which does not exist", key, kind, objNs, name, what),
+			Suggestion: "Create it or fix the reference in spec.strategy.canary; Argo Rollouts cannot shift traffic without it.",
+		})
+	}
+
+	canarySvc, _ := canary["canaryService"].(string)
+	for _, svc := range []string{canarySvc, getNestedString(canary, "stableService")} {
+		if svc == "" {
+			continue
+		}
+		if _, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, svc, metav1.GetOptions{}); err != nil {
+			missing("Service", ns, svc, "")
+		}
+	}
+
+	var route *unstructured.Unstructured
+	var isCanary func(map[string]interface{}) bool
+	if istio, ok := getNestedMap(canary, "trafficRouting", "istio"); ok {
+		vsName := getNestedString(istio, "virtualService", "name")
+		if vsName == "" {
+			if list, _ := istio["virtualServices"].([]interface{}); len(list) > 0 {
+				first, _ := list[0].(map[string]interface{})
+				vsName, _ = first["name"].(string)
+			}
+		}
+		canarySubset := getNestedString(istio, "destinationRule", "canarySubsetName")
+		if drName := getNestedString(istio, "destinationRule", "name"); drName != "" {
+			dr, err := getWithFallback(ctx, t.Clients.Dynamic, drV1GVR, drV1B1GVR, ns, drName)
+			if err != nil {
+				missing("DestinationRule", ns, drName, "")
+			} else {
+				subsets := make(map[string]bool)
+				list, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+				for _, s := range list {
+					subset, _ := s.(map[string]interface{})
+					name, _ := subset["name"].(string)
+					subsets[name] = true
+				}
+				for _, subset := range []string{canarySubset, getNestedString(istio, "destinationRule", "stableSubsetName")} {
+					if subset != "" && !subsets[subset] {
+						missing("DestinationRule", ns, drName, fmt.Sprintf(" subset %s", subset))
+					}
+				}
+			}
+		}
+		if vsName != "" {
+			vs, err := getWithFallback(ctx, t.Clients.Dynamic, vsV1GVR, vsV1B1GVR, ns, vsName)
+			if err != nil {
+				missing("VirtualService", ns, vsName, "")
+			}
+			route = vs
+		}
+		isCanary = func(dest map[string]interface{}) bool {
+			if canarySubset != "" {
+				return dest["subset"] == canarySubset
+			}
+			host, _ := dest["host"].(string)
+			return hostIs(host, canarySvc, ns)
+		}
+	} else if gw, ok := getNestedMap(canary, "trafficRouting", "plugins", "argoproj-labs/gatewayAPI"); ok {
+		routeName := getNestedString(gw, "httpRoute")
+		routeNs := orDefault(getNestedString(gw, "namespace"), ns)
+		if routeName != "" {
+			hr, err := getWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, routeNs, routeName)
+			if err != nil {
+				missing("HTTPRoute", routeNs, routeName, "")
+			}
+			route = hr
+		}
+		isCanary = func(ref map[string]interface{}) bool { return ref["name"] == canarySvc }
+	}
+
+	if route != nil && known {
+		percent, found := canaryRouteWeight(route, isCanary)
+		if found && percent != weight {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeCanaryWeightMismatch,
+				Resource:   &types.ResourceRef{Kind: route.GetKind(), Namespace: route.GetNamespace(), Name: route.GetName()},
+				Summary:    fmt.Sprintf("%s %s/%s sends %d%% to the canary, but Rollout %s is at %d%%", route.GetKind(), route.GetNamespace(), route.GetName(), percent, key, weight),
+				Suggestion: "Another controller or a GitOps sync may be overwriting the weights Argo Rollouts sets; ignore the weight fields in it.",
+			})
+		}
+	}
+	return findings
+}
+
+// getNestedMap returns the map at fields of obj.
+func getNestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool) {
+	m, ok, _ := unstructured.NestedMap(obj, fields...)
+	return m, ok
+}
+
+// analysisRunFindings reports the AnalysisRuns of the current revision of a
+// Rollout that failed or were inconclusive.
+func (t *CheckCanaryTool) analysisRunFindings(ctx context.Context, r *unstructured.Unstructured) []types.DiagnosticFinding {
+	gvr, _, served := t.servedResource("argoproj.io", "AnalysisRun")
+	if !served {
+		return nil
+	}
+	runs, err := t.listResource(ctx, gvr, r.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	podHash := getNestedString(r.Object, "status", "currentPodHash")
+	var findings []types.DiagnosticFinding
+	for _, run := range runs.Items {
+		owned := false
+		for _, owner := range run.GetOwnerReferences() {
+			owned = owned || (owner.Kind == "Rollout" && owner.Name == r.GetName())
+		}
+		if hash, ok := run.GetLabels()["rollouts-pod-template-hash"]; !owned || (ok && podHash != "" && hash != podHash) {
+			continue
+		}
+		phase := getNestedString(run.Object, "status", "phase")
+		severity := types.SeverityCritical
+		switch phase {
+		case "Failed", "Error":
+		case "Inconclusive":
+			severity = types.SeverityWarning
+		default:
+			continue
+		}
+		var failedMetrics []string
+		results, _, _ := unstructured.NestedSlice(run.Object, "status", "metricResults")
+		for _, res := range results {
+			m, _ := res.(map[string]interface{})
+			if p, _ := m["phase"].(string); p != "Successful" && p != "Running" && p != "Pending" {
+				entry := fmt.Sprintf("%s: %s", m["name"], p)
+				if msg, _ := m["message"].(string); msg != "" {
+					entry += " (" + msg + ")"
+				}
+				failedMetrics = append(failedMetrics, entry)
+			}
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryAnalysisFailed,
+			Resource:   &types.ResourceRef{Kind: "AnalysisRun", Namespace: run.GetNamespace(), Name: run.GetName()},
+			Summary:    fmt.Sprintf("AnalysisRun %s/%s of Rollout %s/%s is %s", run.GetNamespace(), run.GetName(), r.GetNamespace(), r.GetName(), phase),
+			Detail:     strings.Join(append([]string{getNestedString(run.Object, "status", "message")}, failedMetrics...), "; "),
+			Suggestion: "An Error phase means the metric provider could not be queried; Failed means the canary did not meet the success condition.",
+		})
+	}
+	return findings
+}
+
+// analysisTemplateFindings checks that an analysis template of a Rollout
+// exists and that each metric has a complete provider.
+func (t *CheckCanaryTool) analysisTemplateFindings(ctx context.Context, rollout *types.ResourceRef, name string) []types.DiagnosticFinding {
+	kind, tplNs := "AnalysisTemplate", rollout.Namespace
+	if strings.HasPrefix(name, "cluster/") {
+		kind, tplNs, name = "ClusterAnalysisTemplate", "", strings.TrimPrefix(name, "cluster/")
+	}
+	gvr, _, served := t.servedResource("argoproj.io", kind)
+	var tpl *unstructured.Unstructured
+	if served {
+		if tplNs == "" {
+			tpl, _ = t.Clients.Dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		} else {
+			tpl, _ = t.Clients.Dynamic.Resource(gvr).Namespace(tplNs).Get(ctx, name, metav1.GetOptions{})
+		}
+	}
+	tplRef := &types.ResourceRef{Kind: kind, Namespace: tplNs, Name: name}
+	if tpl == nil {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCanaryMetricProvider,
+			Resource:   rollout,
+			Summary:    fmt.Sprintf("Rollout %s/%s references %s %s, which does not exist", rollout.Namespace, rollout.Name, kind, name),
+			Suggestion: "Create the template or fix templateName; the analysis run cannot start without it.",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	metrics, _, _ := unstructured.NestedSlice(tpl.Object, "spec", "metrics")
+	for _, m := range metrics {
+		metric, _ := m.(map[string]interface{})
+		metricName, _ := metric["name"].(string)
+		provider, _ := metric["provider"].(map[string]interface{})
+		if len(provider) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeCanaryMetricProvider,
+				Resource:   tplRef,
+				Summary:    fmt.Sprintf("%s %s metric %q has no provider", kind, name, metricName),
+				Suggestion: "Add a provider (prometheus, datadog, web, job, ...) to the metric.",
+			})
+			continue
+		}
+		for providerName, cfg := range provider {
+			settings, _ := cfg.(map[string]interface{})
+			var missingFields []string
+			for _, field := range argoProviderFields[providerName] {
+				if v, _ := settings[field].(string); v == "" {
+					missingFields = append(missingFields, field)
+				}
+			}
+			if len(missingFields) > 0 {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryRouting,
+					Code:       types.CodeCanaryMetricProvider,
+					Resource:   tplRef,
+					Summary:    fmt.Sprintf("%s %s metric %q: the %s provider has no %s", kind, name, metricName, providerName, strings.Join(missingFields, " or ")),
+					Suggestion: "Measurements of an incomplete provider end in the Error phase, which aborts the rollout once consecutiveErrorLimit is reached.",
+				})
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+var (
+	flaggerCanariesGVR     = schema.GroupVersionResource{Group: "flagger.app", Version: "v1beta1", Resource: "canaries"}
+	argoRolloutsGVR        = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	argoAnalysisRunsGVR    = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "analysisruns"}
+	canaryTestDiscoveryAPI = []*metav1.APIResourceList{
+		{GroupVersion: "networking.istio.io/v1", APIResources: []metav1.APIResource{{Name: "virtualservices", Kind: "VirtualService", Namespaced: true}}},
+		{GroupVersion: "flagger.app/v1beta1", APIResources: []metav1.APIResource{
+			{Name: "canaries", Kind: "Canary", Namespaced: true},
+			{Name: "metrictemplates", Kind: "MetricTemplate", Namespaced: true},
+		}},
+		{GroupVersion: "argoproj.io/v1alpha1", APIResources: []metav1.APIResource{
+			{Name: "rollouts", Kind: "Rollout", Namespaced: true},
+			{Name: "analysisruns", Kind: "AnalysisRun", Namespaced: true},
+			{Name: "analysistemplates", Kind: "AnalysisTemplate", Namespaced: true},
+		}},
+	}
+)
+
+func weightedVS(ns, name string, dests ...map[string]interface{}) *unstructured.Unstructured {
+	route := make([]interface{}, 0, len(dests))
+	for _, d := range dests {
+		route = append(route, d)
+	}
+	return managedObj("networking.istio.io/v1", "VirtualService", ns, name, map[string]interface{}{
+		"spec": map[string]interface{}{"http": []interface{}{map[string]interface{}{"name": "primary", "route": route}}},
+	})
+}
+
+func vsDest(host, subset string, weight int64) map[string]interface{} {
+	dest := map[string]interface{}{"host": host}
+	if subset != "" {
+		dest["subset"] = subset
+	}
+	return map[string]interface{}{"destination": dest, "weight": weight}
+}
+
+func TestCanaryRouteWeight(t *testing.T) {
+	vs := weightedVS("shop", "web", vsDest("web-primary", "", 70), vsDest("web-canary.shop.svc.cluster.local", "", 30))
+	if got, found := canaryRouteWeight(vs, func(d map[string]interface{}) bool { return hostIs(d["host"].(string), "web-canary", "shop") }); !found || got != 30 {
+		t.Errorf("VirtualService canary weight = %d (found %v), want 30", got, found)
+	}
+	// Gateway API weights default to 1 and are relative.
+	route := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{"rules": []interface{}{
+		map[string]interface{}{"backendRefs": []interface{}{
+			map[string]interface{}{"name": "web-stable"},
+			map[string]interface{}{"name": "web-canary", "weight": int64(3)},
+		}},
+	}}})
+	if got, found := canaryRouteWeight(route, func(d map[string]interface{}) bool { return d["name"] == "web-canary" }); !found || got != 75 {
+		t.Errorf("HTTPRoute canary weight = %d (found %v), want 75", got, found)
+	}
+}
+
+func TestCheckCanary(t *testing.T) {
+	objs := []runtime.Object{
+		managedObj("flagger.app/v1beta1", "Canary", "shop", "web", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{"kind": "Deployment", "name": "web"},
+				"analysis": map[string]interface{}{"threshold": int64(5), "metrics": []interface{}{
+					map[string]interface{}{"name": "request-success-rate"},
+					map[string]interface{}{"name": "p99", "templateRef": map[string]interface{}{"name": "latency"}},
+				}},
+			},
+			"status": map[string]interface{}{"phase": "Progressing", "canaryWeight": int64(20), "failedChecks": int64(2)},
+		}),
+		weightedVS("shop", "web", vsDest("web-primary", "", 90), vsDest("web-canary", "", 10)),
+		managedObj("argoproj.io/v1alpha1", "Rollout", "shop", "api", map[string]interface{}{
+			"spec": map[string]interface{}{"strategy": map[string]interface{}{"canary": map[string]interface{}{
+				"trafficRouting": map[string]interface{}{"istio": map[string]interface{}{
+					"virtualService":  map[string]interface{}{"name": "api"},
+					"destinationRule": map[string]interface{}{"name": "api", "canarySubsetName": "canary", "stableSubsetName": "stable"},
+				}},
+				"analysis": map[string]interface{}{"templates": []interface{}{map[string]interface{}{"templateName": "api-success"}}},
+				"steps": []interface{}{
+					map[string]interface{}{"setWeight": int64(20)},
+					map[string]interface{}{"pause": map[string]interface{}{}},
+					map[string]interface{}{"setWeight": int64(50)},
+				},
+			}}},
+			"status": map[string]interface{}{"phase": "Paused", "currentStepIndex": int64(1), "currentPodHash": "abc"},
+		}),
+		weightedVS("shop", "api", vsDest("api", "stable", 80), vsDest("api", "canary", 20)),
+		managedObj("networking.istio.io/v1", "DestinationRule", "shop", "api", map[string]interface{}{
+			"spec": map[string]interface{}{"host": "api", "subsets": []interface{}{map[string]interface{}{"name": "stable"}}},
+		}),
+		managedObj("argoproj.io/v1alpha1", "AnalysisTemplate", "shop", "api-success", map[string]interface{}{
+			"spec": map[string]interface{}{"metrics": []interface{}{map[string]interface{}{
+				"name":     "success-rate",
+				"provider": map[string]interface{}{"prometheus": map[string]interface{}{"query": "up"}},
+			}}},
+		}),
+	}
+	for hash, phase := range map[string]string{"abc": "Failed", "old": "Error"} {
+		run := managedObj("argoproj.io/v1alpha1", "AnalysisRun", "shop", "api-"+hash, map[string]interface{}{
+			"status": map[string]interface{}{"phase": phase, "message": "metric failed", "metricResults": []interface{}{
+				map[string]interface{}{"name": "success-rate", "phase": phase, "message": "0.91 < 0.99"},
+			}},
+		})
+		run.SetLabels(map[string]string{"rollouts-pod-template-hash": hash})
+		run.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Rollout", Name: "api"}})
+		objs = append(objs, run)
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		flaggerCanariesGVR: "CanaryList", argoRolloutsGVR: "RolloutList", argoAnalysisRunsGVR: "AnalysisRunList",
+	}, objs...)
+	disco := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: canaryTestDiscoveryAPI}}
+	tool := &CheckCanaryTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client, Discovery: disco}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok Canary shop/web (Flagger): phase Progressing, canary weight 20%, 2/5 failed checks",
+		"warning Canary shop/web has 2 failed checks; Flagger rolls back at 5 CNY002_ANALYSIS_FAILED",
+		`critical Canary shop/web metric "p99" references MetricTemplate shop/latency, which does not exist CNY003_METRIC_PROVIDER_INVALID`,
+		"warning VirtualService shop/web sends 10% to web-canary, but Canary shop/web is at 20% CNY004_WEIGHT_MISMATCH",
+		"ok Rollout shop/api (Argo Rollouts): phase Paused, step 1/3, canary weight 20%",
+		"critical Rollout shop/api uses DestinationRule shop/api subset canary, which does not exist CNY005_ROUTE_MISSING",
+		"critical AnalysisRun shop/api-abc of Rollout shop/api is Failed CNY002_ANALYSIS_FAILED",
+		`warning AnalysisTemplate api-success metric "success-rate": the prometheus provider has no address CNY003_METRIC_PROVIDER_INVALID`,
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	// The route of the Rollout matches its weight, and runs of older
+	// revisions are not reported.
+	if strings.Contains(all, "VirtualService shop/api sends") || strings.Contains(all, "api-old") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+}
+
+func TestDesignCanary(t *testing.T) {
+	svc := managedObj("v1", "Service", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+	})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{servicesGVR: "ServiceList"}, svc)
+	disco := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: canaryTestDiscoveryAPI[:1]}}
+	tool := &DesignCanaryTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client, Discovery: disco}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"service_name": "web", "namespace": "shop", "port": float64(8080), "canary_weight": float64(20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info Generated DestinationRule with stable (version=v1) and canary (version=v2) subsets",
+		"info Generated VirtualService sending 80% to stable and 20% to canary",
+		"info Complete canary configuration (istio, controller none): 2 resources to apply",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"service_name": "web", "namespace": "shop", "port": float64(80), "mode": "gateway-api", "controller": "argo-rollouts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all = managedFindingsOf(resp)
+	for _, want := range []string{
+		"warning argo-rollouts is not installed in the cluster; its resources are generated but cannot be applied yet DSN006_CANARY_CONTROLLER_MISSING",
+		"warning Deployment shop/web not found",
+		"info Generated Argo Rollout with canary steps 10%, 20%, 30%, 40%, 50%",
+		"info Complete canary configuration (gateway-api, controller argo-rollouts): 5 resources to apply",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"service_name": "web", "namespace": "shop", "port": float64(80), "canary_weight": float64(100)}); err == nil {
+		t.Error("expected a canary weight of 100 to be rejected")
+	}
+}
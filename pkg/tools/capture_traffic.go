@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/capture"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	captureMaxDuration   = 60
+	captureMaxPackets    = 20000
+	captureSnapLen       = 128
+	captureTableRows     = 50
+	captureMaxOutputSize = 8 << 20
+	// captureMaxPcapSize bounds the decompressed pcap.
+	captureMaxPcapSize = 32 << 20
+)
+
+// validCaptureFilter matches BPF filter expressions such as
+// "tcp port 5432 and not net 10.0.0.0/8" or "tcp[tcpflags] & tcp-rst != 0".
+var validCaptureFilter = regexp.MustCompile(`^[a-zA-Z0-9 ._:/()\[\]&|!<>=+-]*$`)
+
+// captureDroppedRe matches the drop count tcpdump prints when it stops.
+var captureDroppedRe = regexp.MustCompile(`(\d+) packets? dropped by kernel`)
+
+// capturePodTimeout is how long a probe pod capturing for seconds may run.
+func capturePodTimeout(seconds int) time.Duration {
+	return time.Duration(seconds+60) * time.Second
+}
+
+// captureScript runs tcpdump on all interfaces of the node for seconds, or
+// until packets are captured, and prints the gzipped pcap as one base64
+// line after "PCAP:". The BPF filter is the script's first argument, so it
+// never passes through the shell.
+func captureScript(seconds, packets int) string {
+	return fmt.Sprintf(`out=$(timeout %d tcpdump -i any -nn -Z root -U -s %d -c %d -w - "$1" | gzip -c | base64 | tr -d '\n'); echo "PCAP:$out"`,
+		seconds, captureSnapLen, packets)
+}
+
+// decodeCaptureOutput extracts the pcap from the output of captureScript
+// and returns it with the remaining lines, tcpdump's own messages.
+func decodeCaptureOutput(output string) ([]byte, string, error) {
+	var encoded string
+	var messages []string
+	for _, line := range strings.Split(output, "\n") {
+		if v, ok := strings.CutPrefix(line, "PCAP:"); ok {
+			encoded = strings.TrimSpace(v)
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			messages = append(messages, line)
+		}
+	}
+	msg := strings.Join(messages, "\n")
+	if encoded == "" {
+		return nil, msg, fmt.Errorf("the capture pod returned no pcap")
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, msg, fmt.Errorf("decode pcap: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, msg, fmt.Errorf("decompress pcap: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(zr, captureMaxPcapSize)); err != nil && buf.Len() == 0 {
+		return nil, msg, fmt.Errorf("decompress pcap: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, msg, fmt.Errorf("tcpdump wrote no pcap")
+	}
+	return buf.Bytes(), msg, nil
+}
+
+// captureFindings turns a flow summary into findings: an overview with the
+// flow table, then one finding per kind of problem.
+func captureFindings(target string, ref *types.ResourceRef, s *capture.Summary, seconds int, dropped string) []types.DiagnosticFinding {
+	overview := fmt.Sprintf("Captured %d packets in %d flows for %s over %ds", s.Packets, len(s.Flows), target, seconds)
+	var notes []string
+	if s.Skipped > 0 {
+		notes = append(notes, fmt.Sprintf("%d packets were not IPv4/IPv6 TCP or UDP and were skipped", s.Skipped))
+	}
+	if dropped != "" && dropped != "0" {
+		notes = append(notes, fmt.Sprintf("the kernel dropped %s packets, so counts are incomplete", dropped))
+	}
+	if s.Truncated {
+		notes = append(notes, "the pcap ended mid-packet")
+	}
+	detail := s.Table(captureTableRows)
+	if len(notes) > 0 {
+		detail += "\n" + strings.Join(notes, "; ")
+	}
+
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Resource: ref,
+		Summary:  overview,
+		Detail:   detail,
+	}}
+	if len(s.Flows) == 0 {
+		findings[0].Suggestion = "No traffic matched during the capture. Generate traffic while capturing, lengthen duration or loosen the filter."
+		return findings
+	}
+
+	var unanswered, reset []string
+	for _, f := range s.Flows {
+		switch f.State() {
+		case capture.StateSYNUnanswered:
+			unanswered = append(unanswered, fmt.Sprintf("%s -> %s (%d SYNs)", f.Client, f.Server, f.SYN))
+		case capture.StateReset:
+			by := "client"
+			if f.ResetBy == f.Server {
+				by = "server"
+			}
+			reset = append(reset, fmt.Sprintf("%s -> %s (reset by %s)", f.Client, f.Server, by))
+		}
+	}
+	if len(unanswered) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCaptureSYNUnanswered,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d TCP connection attempt(s) of %s got no SYN-ACK", len(unanswered), target),
+			Detail:     strings.Join(unanswered, "\n"),
+			Suggestion: "Packets to the server are dropped before it answers: check NetworkPolicies, security groups and firewalls on the path, that the server listens on the port, and that a Service has ready endpoints.",
+		})
+	}
+	if len(reset) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCaptureConnectionsReset,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d TCP connection(s) of %s were reset", len(reset), target),
+			Detail:     strings.Join(reset, "\n"),
+			Suggestion: "A reset right after the SYN means nothing listens on the port; a reset mid-connection points at an idle timeout, a proxy closing the connection, or conntrack entries that expired.",
+		})
+	}
+	if segments, flows := s.Retransmits(); segments > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCaptureRetransmissions,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d TCP segment(s) were retransmitted in %d flow(s) of %s", segments, flows, target),
+			Suggestion: "Retransmissions mean packet loss: compare the MTU along the path with check_mtu, and look for CPU throttling of the pod, saturated nodes or a lossy network.",
+		})
+	}
+	return findings
+}
+
+// --- capture_traffic ---
+
+type CaptureTrafficTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+	Store        *capture.Store
+}
+
+func (t *CaptureTrafficTool) Name() string { return "capture_traffic" }
+func (t *CaptureTrafficTool) Description() string {
+	return "Capture a pod's traffic with tcpdump for a few seconds from a short-lived privileged pod on its node, keep the pcap on the server, and return a flow table that flags connection attempts without SYN-ACK, resets and retransmissions instead of raw packets"
+}
+func (t *CaptureTrafficTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pod": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod whose traffic to capture",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the pod",
+			},
+			"filter": map[string]interface{}{
+				"type":        "string",
+				"description": "BPF filter narrowing the capture, e.g. 'tcp port 5432'; the pod's IPs are always matched",
+			},
+			"duration": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Seconds to capture (default: 10, max: %d)", captureMaxDuration),
+			},
+			"max_packets": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Stop after this many packets (default: 5000, max: %d)", captureMaxPackets),
+			},
+		},
+		"required": []string{"pod", "namespace"},
+	}
+}
+
+func (t *CaptureTrafficTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	podName := getStringArg(args, "pod", "")
+	ns := getStringArg(args, "namespace", "")
+	filter := strings.TrimSpace(getStringArg(args, "filter", ""))
+	seconds := getIntArg(args, "duration", 10)
+	packets := getIntArg(args, "max_packets", 5000)
+
+	if podName == "" || ns == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "pod and namespace are required",
+		}
+	}
+	if seconds < 1 || seconds > captureMaxDuration {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("duration %d out of range (1-%d)", seconds, captureMaxDuration),
+		}
+	}
+	if packets < 1 || packets > captureMaxPackets {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("max_packets %d out of range (1-%d)", packets, captureMaxPackets),
+		}
+	}
+	if len(filter) > 256 || !validCaptureFilter.MatchString(filter) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "filter must be a BPF expression of at most 256 characters without quotes or shell metacharacters",
+		}
+	}
+
+	pod, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get pod %s/%s", ns, podName),
+			Detail:  err.Error(),
+		}
+	}
+	var hosts []string
+	for _, ip := range pod.Status.PodIPs {
+		hosts = append(hosts, "host "+ip.IP)
+	}
+	if len(hosts) == 0 && pod.Status.PodIP != "" {
+		hosts = append(hosts, "host "+pod.Status.PodIP)
+	}
+	if len(hosts) == 0 || pod.Spec.NodeName == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("pod %s/%s is not running on a node with an IP yet", ns, podName),
+		}
+	}
+	bpf := "(" + strings.Join(hosts, " or ") + ")"
+	if filter != "" {
+		bpf += " and (" + filter + ")"
+	}
+
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:           probes.ProbeTypeCapture,
+		Namespace:      t.Cfg.ProbeNamespace,
+		NodeName:       pod.Spec.NodeName,
+		Command:        []string{"sh", "-c", captureScript(seconds, packets), "capture", bpf},
+		Timeout:        capturePodTimeout(seconds),
+		Privileged:     true,
+		MaxOutputBytes: captureMaxOutputSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target := fmt.Sprintf("pod %s/%s", ns, podName)
+	ref := &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: podName}
+	findings := make([]types.DiagnosticFinding, 0, 4)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+
+	data, messages, err := decodeCaptureOutput(result.Output)
+	var pcap *capture.Pcap
+	if err == nil {
+		pcap, err = capture.ParsePcap(data)
+	}
+	if err != nil {
+		detail := messages
+		if result.Error != "" {
+			detail = strings.TrimSpace(result.Error + "\n" + detail)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCaptureFailed,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Capturing traffic of %s on node %s failed: %s", target, pod.Spec.NodeName, err),
+			Detail:     detail,
+			Suggestion: fmt.Sprintf("The probe image %s needs tcpdump, gzip and base64, and namespace %s must admit privileged pods (pod-security.kubernetes.io/enforce: privileged).", t.Cfg.ProbeImage, t.Cfg.ProbeNamespace),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	var dropped string
+	if m := captureDroppedRe.FindStringSubmatch(messages); m != nil {
+		dropped = m[1]
+	}
+	summary := capture.Summarize(pcap)
+	captured := captureFindings(target, ref, summary, seconds, dropped)
+	if t.Store != nil {
+		path, err := t.Store.Save(data, time.Now(), t.Cfg.ClusterName, ns, podName)
+		if err != nil {
+			captured[0].Detail += "\npcap not kept: " + err.Error()
+		} else {
+			captured[0].Detail += fmt.Sprintf("\npcap (%d bytes, %d-byte snap length) kept on the server at %s", len(data), captureSnapLen, path)
+		}
+	}
+	findings = append(findings, captured...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
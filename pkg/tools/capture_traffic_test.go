@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/capture"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// rawSYNPcap returns a pcap of raw IPv4 frames holding n identical SYNs
+// from 10.0.0.1:40000 to 10.0.0.2:5432.
+func rawSYNPcap(n int) []byte {
+	data := make([]byte, 24)
+	binary.LittleEndian.PutUint32(data, 0xa1b2c3d4)
+	binary.LittleEndian.PutUint32(data[16:], 128)
+	binary.LittleEndian.PutUint32(data[20:], capture.LinkTypeRaw)
+	frame := make([]byte, 40)
+	frame[0] = 0x45
+	binary.BigEndian.PutUint16(frame[2:], 40)
+	frame[9] = 6
+	copy(frame[12:], []byte{10, 0, 0, 1, 10, 0, 0, 2})
+	binary.BigEndian.PutUint16(frame[20:], 40000)
+	binary.BigEndian.PutUint16(frame[22:], 5432)
+	frame[32] = 5 << 4
+	frame[33] = 0x02
+	for i := 0; i < n; i++ {
+		rec := make([]byte, 16)
+		binary.LittleEndian.PutUint32(rec, uint32(1760000000+i))
+		binary.LittleEndian.PutUint32(rec[8:], uint32(len(frame)))
+		binary.LittleEndian.PutUint32(rec[12:], uint32(len(frame)))
+		data = append(append(data, rec...), frame...)
+	}
+	return data
+}
+
+func TestDecodeCaptureOutput(t *testing.T) {
+	pcap := rawSYNPcap(2)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(pcap)
+	_ = zw.Close()
+	output := strings.Join([]string{
+		"tcpdump: data link type LINUX_SLL2",
+		"tcpdump: listening on any, link-type LINUX_SLL2 (Linux cooked v2), snapshot length 128 bytes",
+		"2 packets captured",
+		"0 packets dropped by kernel",
+		"PCAP:" + base64.StdEncoding.EncodeToString(gz.Bytes()),
+	}, "\n")
+
+	data, messages, err := decodeCaptureOutput(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pcap) {
+		t.Errorf("pcap differs: %d bytes, want %d", len(data), len(pcap))
+	}
+	if m := captureDroppedRe.FindStringSubmatch(messages); m == nil || m[1] != "0" {
+		t.Errorf("dropped count not found in %q", messages)
+	}
+
+	_, messages, err = decodeCaptureOutput("tcpdump: any: You don't have permission to capture on that device\nPCAP:")
+	if err == nil || !strings.Contains(messages, "permission") {
+		t.Errorf("failed capture: err %v, messages %q", err, messages)
+	}
+}
+
+func TestCaptureFindings(t *testing.T) {
+	p, err := capture.ParsePcap(rawSYNPcap(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := &types.ResourceRef{Kind: "Pod", Namespace: "shop", Name: "web-0"}
+	findings := captureFindings("pod shop/web-0", ref, capture.Summarize(p), 10, "4")
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Summary+" "+string(f.Code))
+	}
+	want := []string{
+		"info Captured 3 packets in 1 flows for pod shop/web-0 over 10s ",
+		"critical 1 TCP connection attempt(s) of pod shop/web-0 got no SYN-ACK PRB009_SYN_UNANSWERED",
+		"warning 2 TCP segment(s) were retransmitted in 1 flow(s) of pod shop/web-0 PRB011_RETRANSMISSIONS",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(findings[0].Detail, "the kernel dropped 4 packets") || !strings.Contains(findings[0].Detail, "syn-unanswered") {
+		t.Errorf("overview detail:\n%s", findings[0].Detail)
+	}
+	if findings[1].Detail != "10.0.0.1:40000 -> 10.0.0.2:5432 (3 SYNs)" {
+		t.Errorf("unanswered detail: %q", findings[1].Detail)
+	}
+}
@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// catalogAnnotationPrefix namespaces the networking annotations added to
+// exported catalog entities.
+const catalogAnnotationPrefix = "networking.isitobservable.io/"
+
+// ownerKeys are the labels and annotations read, in order, for an entity owner.
+var ownerKeys = []string{"backstage.io/owner", "owner", "team"}
+
+// catalogEntity is a Backstage catalog entity (backstage.io/v1alpha1).
+type catalogEntity struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   catalogMetadata `json:"metadata"`
+	Spec       catalogSpec     `json:"spec"`
+}
+
+type catalogMetadata struct {
+	Name        string            `json:"name"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Annotations map[string]string `json:"annotations"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type catalogSpec struct {
+	Type      string `json:"type"`
+	Lifecycle string `json:"lifecycle"`
+	Owner     string `json:"owner"`
+	System    string `json:"system,omitempty"`
+}
+
+// serviceInventory is the networking view of one Service that a catalog
+// entity is built from.
+type serviceInventory struct {
+	Namespace       string
+	Name            string
+	Type            string
+	Ports           []string
+	Selector        map[string]string
+	Owner           string
+	System          string
+	ExposedVia      []string
+	Routes          []string
+	NetworkPolicies []string
+	AuthzPolicies   []string
+	Mesh            string
+	Pods            int
+	MeshedPods      int
+}
+
+// catalogEntityName builds a Backstage entity name: unique per Kubernetes
+// namespace, at most 63 characters.
+func catalogEntityName(ns, name string) string {
+	n := ns + "-" + name
+	if len(n) > 63 {
+		n = strings.TrimRight(n[:63], "-.")
+	}
+	return n
+}
+
+func (inv serviceInventory) entity(lifecycle string) catalogEntity {
+	a := map[string]string{
+		"backstage.io/kubernetes-namespace":                inv.Namespace,
+		catalogAnnotationPrefix + "service-type":           inv.Type,
+		catalogAnnotationPrefix + "ports":                  strings.Join(inv.Ports, ","),
+		catalogAnnotationPrefix + "exposure":               "internal",
+		catalogAnnotationPrefix + "mesh":                   orDefault(inv.Mesh, "none"),
+		catalogAnnotationPrefix + "mesh-coverage":          fmt.Sprintf("%d/%d", inv.MeshedPods, inv.Pods),
+		catalogAnnotationPrefix + "network-policies":       strings.Join(inv.NetworkPolicies, ","),
+		catalogAnnotationPrefix + "authorization-policies": strings.Join(inv.AuthzPolicies, ","),
+		catalogAnnotationPrefix + "routes":                 strings.Join(inv.Routes, ","),
+	}
+	if len(inv.Selector) > 0 {
+		a["backstage.io/kubernetes-label-selector"] = labels.SelectorFromSet(inv.Selector).String()
+	}
+	tags := []string{}
+	if len(inv.ExposedVia) > 0 {
+		a[catalogAnnotationPrefix+"exposure"] = "external"
+		a[catalogAnnotationPrefix+"entry-points"] = strings.Join(inv.ExposedVia, ",")
+		tags = append(tags, "external")
+	}
+	if inv.Mesh != "" {
+		tags = append(tags, inv.Mesh)
+	}
+	if len(inv.NetworkPolicies) == 0 && len(inv.Selector) > 0 {
+		tags = append(tags, "no-network-policy")
+	}
+	for k, v := range a {
+		if v == "" {
+			delete(a, k)
+		}
+	}
+
+	return catalogEntity{
+		APIVersion: "backstage.io/v1alpha1",
+		Kind:       "Component",
+		Metadata: catalogMetadata{
+			Name:        catalogEntityName(inv.Namespace, inv.Name),
+			Title:       fmt.Sprintf("%s (%s)", inv.Name, inv.Namespace),
+			Description: fmt.Sprintf("Kubernetes Service %s/%s", inv.Namespace, inv.Name),
+			Annotations: a,
+			Tags:        tags,
+		},
+		Spec: catalogSpec{
+			Type:      "service",
+			Lifecycle: lifecycle,
+			Owner:     orDefault(inv.Owner, "unknown"),
+			System:    inv.System,
+		},
+	}
+}
+
+// lookupOwner returns the first owner label or annotation set on any of objs.
+func lookupOwner(objs ...metav1.Object) string {
+	for _, o := range objs {
+		if o == nil {
+			continue
+		}
+		for _, k := range ownerKeys {
+			if v := o.GetAnnotations()[k]; v != "" {
+				return v
+			}
+			if v := o.GetLabels()[k]; v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// podHasProxy reports whether a pod runs a mesh proxy, as a regular or
+// native sidecar container.
+func podHasProxy(pod *unstructured.Unstructured) bool {
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", field)
+		for _, c := range containers {
+			cm, _ := c.(map[string]interface{})
+			name, _ := cm["name"].(string)
+			if containsString(proxyContainerNames, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routesByBackend maps "namespace/name" of each backend Service to the
+// routes that send traffic to it.
+func routesByBackend(routes []routeInfo) map[string][]string {
+	out := make(map[string][]string)
+	for _, r := range routes {
+		label := fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name)
+		for _, b := range routeBackends(r) {
+			out[b] = append(out[b], label)
+		}
+	}
+	return out
+}
+
+// --- export_service_catalog ---
+
+type ExportServiceCatalogTool struct{ BaseTool }
+
+func (t *ExportServiceCatalogTool) Name() string { return "export_service_catalog" }
+func (t *ExportServiceCatalogTool) Description() string {
+	return "Export the networking inventory (services, owners, exposure, routes, policies, mesh coverage) as Backstage catalog entities in YAML or JSON"
+}
+func (t *ExportServiceCatalogTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "yaml (multi-document catalog-info) or json (array of entities); default yaml",
+			},
+			"lifecycle": map[string]interface{}{
+				"type":        "string",
+				"description": "spec.lifecycle of the exported components (default production)",
+			},
+		},
+	}
+}
+
+func (t *ExportServiceCatalogTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	format := strings.ToLower(getStringArg(args, "format", "yaml"))
+	lifecycle := getStringArg(args, "lifecycle", "production")
+	if format != "yaml" && format != "json" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unsupported format %q: use yaml or json", format),
+		}
+	}
+
+	inventory, err := t.inventory(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	entities := make([]catalogEntity, 0, len(inventory))
+	for _, inv := range inventory {
+		entities = append(entities, inv.entity(lifecycle))
+	}
+
+	if format == "json" {
+		return NewResponse(t.Cfg, t.Name(), entities), nil
+	}
+	docs := make([]string, 0, len(entities))
+	for _, e := range entities {
+		b, err := yaml.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render entity %s: %w", e.Metadata.Name, err)
+		}
+		docs = append(docs, strings.TrimRight(string(b), "\n"))
+	}
+	return NewResponse(t.Cfg, t.Name(), strings.Join(docs, "\n---\n")), nil
+}
+
+// inventory collects the networking view of every Service in ns, sorted by
+// namespace and name.
+func (t *ExportServiceCatalogTool) inventory(ctx context.Context, ns string) ([]serviceInventory, error) {
+	svcList, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	namespaces := make(map[string]*corev1.Namespace)
+	if nsList, err := t.Clients.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range nsList.Items {
+			namespaces[nsList.Items[i].Name] = &nsList.Items[i]
+		}
+	}
+
+	routes := t.listRoutes(ctx)
+	byBackend := routesByBackend(routes)
+	exposedVia := make(map[string][]string)
+	for _, e := range t.collectEntryPoints(ctx, ns, svcList.Items, routes) {
+		for _, b := range e.Backends {
+			exposedVia[b] = append(exposedVia[b], e.label())
+		}
+		if e.Kind == "Ingress" {
+			for _, b := range e.Backends {
+				byBackend[b] = append(byBackend[b], e.label())
+			}
+		}
+	}
+
+	type nsData struct {
+		pods    []unstructured.Unstructured
+		netpols []unstructured.Unstructured
+		authz   []unstructured.Unstructured
+	}
+	var rootAuthz []unstructured.Unstructured
+	if list, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, istioRootNamespace); err == nil {
+		rootAuthz = list.Items
+	}
+	cache := make(map[string]*nsData)
+	load := func(ns string) *nsData {
+		if d, ok := cache[ns]; ok {
+			return d
+		}
+		d := &nsData{}
+		if list, err := t.listResource(ctx, podsGVR, ns); err == nil {
+			d.pods = list.Items
+		}
+		if list, err := t.listResource(ctx, networkPoliciesGVR, ns); err == nil {
+			d.netpols = list.Items
+		}
+		if list, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ns); err == nil {
+			d.authz = list.Items
+		}
+		if ns != istioRootNamespace {
+			d.authz = append(d.authz, rootAuthz...)
+		}
+		cache[ns] = d
+		return d
+	}
+
+	out := make([]serviceInventory, 0, len(svcList.Items))
+	for i := range svcList.Items {
+		s := &svcList.Items[i]
+		key := s.GetNamespace() + "/" + s.GetName()
+		inv := serviceInventory{
+			Namespace:  s.GetNamespace(),
+			Name:       s.GetName(),
+			ExposedVia: exposedVia[key],
+			Routes:     byBackend[key],
+			System:     s.GetLabels()["app.kubernetes.io/part-of"],
+		}
+		inv.Type, _, _ = unstructured.NestedString(s.Object, "spec", "type")
+		inv.Type = orDefault(inv.Type, "ClusterIP")
+		for _, p := range serviceTargetPorts(s) {
+			inv.Ports = append(inv.Ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
+		}
+		inv.Selector, _, _ = unstructured.NestedStringMap(s.Object, "spec", "selector")
+
+		nsObj := namespaces[inv.Namespace]
+		if nsObj != nil {
+			inv.Owner = lookupOwner(s, nsObj)
+			inv.Mesh = namespaceMesh(*nsObj)
+		} else {
+			inv.Owner = lookupOwner(s)
+		}
+
+		if len(inv.Selector) > 0 {
+			d := load(inv.Namespace)
+			sel := labels.SelectorFromSet(inv.Selector)
+			var selected []podPorts
+			for j := range d.pods {
+				pod := &d.pods[j]
+				if !sel.Matches(labels.Set(pod.GetLabels())) {
+					continue
+				}
+				inv.Pods++
+				if podHasProxy(pod) || inv.Mesh == "istio-ambient" {
+					inv.MeshedPods++
+				}
+				selected = append(selected, podPortsFrom(pod))
+			}
+			if len(selected) > 0 {
+				inv.NetworkPolicies = selectingNetworkPolicies(selected, d.netpols)
+				inv.AuthzPolicies = applyingAuthorizationPolicies(selected, inv.Namespace, inv.Name, d.authz)
+			}
+		}
+		out = append(out, inv)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// selectingNetworkPolicies returns the NetworkPolicies whose podSelector
+// matches any of pods, regardless of what they allow.
+func selectingNetworkPolicies(pods []podPorts, policies []unstructured.Unstructured) []string {
+	var out []string
+	for i := range policies {
+		p := &policies[i]
+		selObj, ok, _ := unstructured.NestedMap(p.Object, "spec", "podSelector")
+		if !ok {
+			selObj = map[string]interface{}{}
+		}
+		sel, err := parseLabelSelector(selObj, true)
+		if err != nil {
+			continue
+		}
+		if len(selectPods(pods, sel, func(ns string) bool { return ns == p.GetNamespace() })) > 0 {
+			out = append(out, p.GetNamespace()+"/"+p.GetName())
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCatalogEntity(t *testing.T) {
+	inv := serviceInventory{
+		Namespace:  "shop",
+		Name:       "web",
+		Type:       "ClusterIP",
+		Ports:      []string{"80/TCP"},
+		Selector:   map[string]string{"app": "web"},
+		Owner:      "team-shop",
+		System:     "storefront",
+		ExposedVia: []string{"Gateway infra/public"},
+		Routes:     []string{"HTTPRoute shop/web"},
+		Mesh:       "istio",
+		Pods:       3,
+		MeshedPods: 2,
+	}
+	e := inv.entity("production")
+	if e.Metadata.Name != "shop-web" || e.Spec.Owner != "team-shop" || e.Spec.System != "storefront" {
+		t.Errorf("unexpected entity identity: %+v", e)
+	}
+	a := e.Metadata.Annotations
+	if a["backstage.io/kubernetes-label-selector"] != "app=web" {
+		t.Errorf("label selector = %q", a["backstage.io/kubernetes-label-selector"])
+	}
+	if a[catalogAnnotationPrefix+"exposure"] != "external" || a[catalogAnnotationPrefix+"mesh-coverage"] != "2/3" {
+		t.Errorf("unexpected networking annotations: %v", a)
+	}
+	if _, ok := a[catalogAnnotationPrefix+"network-policies"]; ok {
+		t.Error("expected empty annotations to be dropped")
+	}
+	if !reflect.DeepEqual(e.Metadata.Tags, []string{"external", "istio", "no-network-policy"}) {
+		t.Errorf("tags = %v", e.Metadata.Tags)
+	}
+
+	headless := serviceInventory{Namespace: "db", Name: "pg", Type: "ClusterIP"}.entity("experimental")
+	if headless.Spec.Owner != "unknown" || headless.Metadata.Annotations[catalogAnnotationPrefix+"exposure"] != "internal" {
+		t.Errorf("unexpected defaults: %+v", headless)
+	}
+}
+
+func TestCatalogEntityName(t *testing.T) {
+	long := catalogEntityName(strings.Repeat("n", 40), strings.Repeat("s", 40))
+	if len(long) > 63 {
+		t.Errorf("name too long: %d", len(long))
+	}
+	if got := catalogEntityName(strings.Repeat("n", 62), "svc"); strings.HasSuffix(got, "-") {
+		t.Errorf("name ends with a separator: %q", got)
+	}
+}
+
+func TestLookupOwner(t *testing.T) {
+	svc := &unstructured.Unstructured{}
+	svc.SetLabels(map[string]string{"team": "payments"})
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"backstage.io/owner": "group:platform"}}}
+	if got := lookupOwner(svc, ns); got != "payments" {
+		t.Errorf("service label should win, got %q", got)
+	}
+	if got := lookupOwner(&unstructured.Unstructured{}, ns); got != "group:platform" {
+		t.Errorf("expected namespace owner, got %q", got)
+	}
+}
+
+func TestRoutesByBackend(t *testing.T) {
+	route := routeInfo{kind: "HTTPRoute", name: "shop", namespace: "shop", obj: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "web"}}},
+				map[string]interface{}{"backendRefs": []interface{}{
+					map[string]interface{}{"name": "web"},
+					map[string]interface{}{"name": "api", "namespace": "backend"},
+				}},
+			},
+		},
+	}}
+	got := routesByBackend([]routeInfo{route})
+	want := map[string][]string{"shop/web": {"HTTPRoute shop/shop"}, "backend/api": {"HTTPRoute shop/shop"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("routesByBackend() = %v", got)
+	}
+}
+
+func TestPodHasProxy(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers":     []interface{}{map[string]interface{}{"name": "app"}},
+			"initContainers": []interface{}{map[string]interface{}{"name": "istio-proxy"}},
+		},
+	}}
+	if !podHasProxy(pod) {
+		t.Error("expected a native sidecar proxy to count")
+	}
+	if podHasProxy(&unstructured.Unstructured{Object: map[string]interface{}{}}) {
+		t.Error("expected no proxy on an empty pod")
+	}
+}
@@ -0,0 +1,533 @@
+package tools
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	secretsGVR      = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+	certificatesGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+)
+
+// sanCovers reports whether a certificate SAN is valid for host. A wildcard
+// SAN covers exactly one label: *.example.com covers a.example.com but not
+// example.com or a.b.example.com.
+func sanCovers(san, host string) bool {
+	san, host = strings.ToLower(san), strings.ToLower(host)
+	if san == host {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(san, "*")
+	if !ok || !strings.HasPrefix(suffix, ".") || !strings.HasSuffix(host, suffix) {
+		return false
+	}
+	label := strings.TrimSuffix(host, suffix)
+	return label != "" && label != "*" && !strings.Contains(label, ".")
+}
+
+// certCovers reports whether any SAN covers host.
+func certCovers(sans []string, host string) bool {
+	for _, san := range sans {
+		if sanCovers(san, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostnameMatches applies Gateway API hostname matching: an empty pattern
+// matches every host and *.example.com matches any subdomain, at any depth.
+func hostnameMatches(pattern, host string) bool {
+	pattern, host = strings.ToLower(pattern), strings.ToLower(host)
+	if pattern == "" || pattern == host {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(pattern, "*")
+	return ok && strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+}
+
+// listenerRouteHosts returns the hostnames a route serves through a listener:
+// the intersection of the listener hostname and the route's hostnames.
+func listenerRouteHosts(listenerHost string, routeHosts []string) []string {
+	if len(routeHosts) == 0 {
+		if listenerHost == "" {
+			return nil
+		}
+		return []string{listenerHost}
+	}
+	var out []string
+	for _, h := range routeHosts {
+		switch {
+		case hostnameMatches(listenerHost, h):
+			out = append(out, h)
+		case hostnameMatches(h, listenerHost):
+			out = append(out, listenerHost)
+		}
+	}
+	return out
+}
+
+// tlsListener is a Gateway API listener that terminates TLS.
+type tlsListener struct {
+	Name     string
+	Hostname string
+	Port     int64
+	// Certs are the "namespace/name" of the certificate Secrets, in order.
+	Certs []string
+}
+
+// sniListener returns the listener SNI selects for host: an exact hostname,
+// then the longest matching wildcard, then a listener without hostname.
+func sniListener(listeners []tlsListener, host string) *tlsListener {
+	var best *tlsListener
+	for i := range listeners {
+		l := &listeners[i]
+		if !hostnameMatches(l.Hostname, host) {
+			continue
+		}
+		if best == nil || len(l.Hostname) > len(best.Hostname) {
+			best = l
+		}
+	}
+	return best
+}
+
+// certInfo is what is known about the certificate in a Secret.
+type certInfo struct {
+	SANs   []string
+	Source string
+	Err    error
+}
+
+// certResolver reads certificate SANs from cert-manager Certificates or,
+// when RBAC allows, the Secret's tls.crt. Private keys are never read.
+type certResolver struct {
+	b     *BaseTool
+	cache map[string]certInfo
+}
+
+func (r *certResolver) resolve(ctx context.Context, ns, name string) certInfo {
+	key := ns + "/" + name
+	if info, ok := r.cache[key]; ok {
+		return info
+	}
+	info := r.lookup(ctx, ns, name)
+	r.cache[key] = info
+	return info
+}
+
+func (r *certResolver) lookup(ctx context.Context, ns, name string) certInfo {
+	if certs, err := r.b.listResource(ctx, certificatesGVR, ns); err == nil {
+		for _, c := range certs.Items {
+			if secretName, _, _ := unstructured.NestedString(c.Object, "spec", "secretName"); secretName != name {
+				continue
+			}
+			sans, _, _ := unstructured.NestedStringSlice(c.Object, "spec", "dnsNames")
+			if cn, _, _ := unstructured.NestedString(c.Object, "spec", "commonName"); cn != "" && len(sans) == 0 {
+				sans = []string{cn}
+			}
+			return certInfo{SANs: sans, Source: "Certificate " + ns + "/" + c.GetName()}
+		}
+	}
+
+	secret, err := r.b.Clients.Dynamic.Resource(secretsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return certInfo{Err: fmt.Errorf("no cert-manager Certificate for Secret %s/%s and reading Secrets is not allowed", ns, name)}
+		}
+		return certInfo{Err: fmt.Errorf("failed to get Secret %s/%s: %w", ns, name, err)}
+	}
+	encoded, _, _ := unstructured.NestedString(secret.Object, "data", "tls.crt")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return certInfo{Err: fmt.Errorf("secret %s/%s has an unreadable tls.crt", ns, name)}
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return certInfo{Err: fmt.Errorf("secret %s/%s has no PEM certificate in tls.crt", ns, name)}
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return certInfo{Err: fmt.Errorf("secret %s/%s: %w", ns, name, err)}
+	}
+	sans := cert.DNSNames
+	if len(sans) == 0 && cert.Subject.CommonName != "" {
+		sans = []string{cert.Subject.CommonName}
+	}
+	return certInfo{SANs: sans, Source: "Secret " + ns + "/" + name}
+}
+
+// resolveAll merges the SANs of several certificates and returns the reasons
+// the others could not be read.
+func (r *certResolver) resolveAll(ctx context.Context, refs []string) (sans []string, sources []string, errs []string) {
+	for _, ref := range refs {
+		ns, name, _ := strings.Cut(ref, "/")
+		info := r.resolve(ctx, ns, name)
+		if info.Err != nil {
+			errs = append(errs, info.Err.Error())
+			continue
+		}
+		sans = append(sans, info.SANs...)
+		sources = append(sources, info.Source)
+	}
+	return sans, sources, errs
+}
+
+// routeListenerNames returns the listener names a route attaches to on a
+// Gateway; all is true for parentRefs without sectionName.
+func routeListenerNames(route routeInfo, gwNs, gwName string) (names map[string]bool, all bool) {
+	names = map[string]bool{}
+	parentRefs, _, _ := unstructured.NestedSlice(route.obj, "spec", "parentRefs")
+	for _, pr := range parentRefs {
+		prm, ok := pr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := prm["kind"].(string)
+		name, _ := prm["name"].(string)
+		ns, _ := prm["namespace"].(string)
+		if (kind != "" && kind != "Gateway") || name != gwName || orDefault(ns, route.namespace) != gwNs {
+			continue
+		}
+		if section, _ := prm["sectionName"].(string); section != "" {
+			names[section] = true
+		} else {
+			all = true
+		}
+	}
+	return names, all
+}
+
+// gatewayTLSListeners returns the listeners of a Gateway that terminate TLS.
+func gatewayTLSListeners(gw *unstructured.Unstructured) []tlsListener {
+	listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	var out []tlsListener
+	for _, l := range listeners {
+		lm, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		protocol, _ := lm["protocol"].(string)
+		if protocol != "HTTPS" && protocol != "TLS" {
+			continue
+		}
+		if mode, _, _ := unstructured.NestedString(lm, "tls", "mode"); mode == "Passthrough" {
+			continue
+		}
+		tl := tlsListener{}
+		tl.Name, _ = lm["name"].(string)
+		tl.Hostname, _ = lm["hostname"].(string)
+		tl.Port, _, _ = unstructured.NestedInt64(lm, "port")
+		refs, _, _ := unstructured.NestedSlice(lm, "tls", "certificateRefs")
+		for _, ref := range refs {
+			rm, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := rm["kind"].(string); kind != "" && kind != "Secret" {
+				continue
+			}
+			name, _ := rm["name"].(string)
+			ns, _ := rm["namespace"].(string)
+			tl.Certs = append(tl.Certs, orDefault(ns, gw.GetNamespace())+"/"+name)
+		}
+		out = append(out, tl)
+	}
+	return out
+}
+
+// --- check_certificate_sni ---
+
+type CheckCertificateSNITool struct{ BaseTool }
+
+func (t *CheckCertificateSNITool) Name() string { return "check_certificate_sni" }
+func (t *CheckCertificateSNITool) Description() string {
+	return "Check that the TLS certificates on Gateway API listeners, Istio Gateways and Ingresses cover the hostnames routed behind them, including wildcard depth, and find hosts for which SNI selects another listener or the controller's default certificate"
+}
+func (t *CheckCertificateSNITool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Gateways and Ingresses (empty for all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *CheckCertificateSNITool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	r := &certResolver{b: &t.BaseTool, cache: map[string]certInfo{}}
+
+	var findings []types.DiagnosticFinding
+	checked := 0
+
+	if gws, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns); err == nil {
+		routes := t.listRoutes(ctx)
+		for i := range gws.Items {
+			f, n := t.checkGateway(ctx, r, &gws.Items[i], routes)
+			findings = append(findings, f...)
+			checked += n
+		}
+	}
+	if gws, err := t.listResourceWithFallback(ctx, istioGatewayV1GVR, istioGatewayV1B1GVR, ns); err == nil {
+		for i := range gws.Items {
+			f, n := t.checkIstioGateway(ctx, r, &gws.Items[i])
+			findings = append(findings, f...)
+			checked += n
+		}
+	}
+	if ings, err := t.listResource(ctx, ingressGVR, ns); err == nil {
+		for i := range ings.Items {
+			f, n := t.checkIngress(ctx, r, &ings.Items[i])
+			findings = append(findings, f...)
+			checked += n
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryTLS,
+			Summary:  fmt.Sprintf("All %d TLS-terminating listeners serve certificates covering their hostnames", checked),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), ""), nil
+}
+
+// uncoveredFinding reports a routed host missing from a certificate.
+func uncoveredFinding(ref *types.ResourceRef, where, host string, sans, sources []string) types.DiagnosticFinding {
+	detail := fmt.Sprintf("SANs=[%s] from %s", strings.Join(sans, ", "), strings.Join(sources, ", "))
+	suggestion := fmt.Sprintf("Add %s to the certificate's dnsNames, or use a certificate for it on a separate listener.", host)
+	for _, san := range sans {
+		if strings.HasPrefix(san, "*.") && strings.HasSuffix(host, san[1:]) {
+			suggestion = fmt.Sprintf("Wildcard %s covers a single label only; %s needs its own SAN (e.g. *.%s).", san, host, host[strings.Index(host, ".")+1:])
+			break
+		}
+	}
+	return types.DiagnosticFinding{
+		Severity:   types.SeverityCritical,
+		Category:   types.CategoryTLS,
+		Code:       types.CodeTLSCertHostNotCovered,
+		Resource:   ref,
+		Summary:    fmt.Sprintf("%s: certificate does not cover %s", where, host),
+		Detail:     detail,
+		Suggestion: suggestion,
+	}
+}
+
+func unreadableFinding(ref *types.ResourceRef, where string, errs []string) types.DiagnosticFinding {
+	return types.DiagnosticFinding{
+		Severity:   types.SeverityInfo,
+		Category:   types.CategoryTLS,
+		Resource:   ref,
+		Summary:    fmt.Sprintf("%s: certificate SANs could not be read", where),
+		Detail:     strings.Join(errs, "; "),
+		Suggestion: "Manage the certificate with cert-manager, or grant the server get on Secrets (Helm rbac.readTLSCertificates) to verify it.",
+	}
+}
+
+func (t *CheckCertificateSNITool) checkGateway(ctx context.Context, r *certResolver, gw *unstructured.Unstructured, routes []routeInfo) ([]types.DiagnosticFinding, int) {
+	ref := &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+	listeners := gatewayTLSListeners(gw)
+	byPort := map[int64][]tlsListener{}
+	for _, l := range listeners {
+		byPort[l.Port] = append(byPort[l.Port], l)
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, l := range listeners {
+		where := fmt.Sprintf("Listener %s (port %d)", l.Name, l.Port)
+		sans, sources, errs := r.resolveAll(ctx, l.Certs)
+
+		hosts := map[string]string{} // host -> route
+		for _, route := range routes {
+			names, all := routeListenerNames(route, gw.GetNamespace(), gw.GetName())
+			if !all && !names[l.Name] {
+				continue
+			}
+			routeHosts, _, _ := unstructured.NestedStringSlice(route.obj, "spec", "hostnames")
+			for _, h := range listenerRouteHosts(l.Hostname, routeHosts) {
+				hosts[h] = route.kind + " " + route.namespace + "/" + route.name
+			}
+		}
+		if l.Hostname != "" {
+			if _, ok := hosts[l.Hostname]; !ok {
+				hosts[l.Hostname] = ""
+			}
+		}
+
+		if len(errs) > 0 {
+			findings = append(findings, unreadableFinding(ref, where, errs))
+		}
+		for _, h := range sortedKeys(hosts) {
+			if len(errs) == 0 && len(l.Certs) > 0 && !certCovers(sans, h) {
+				f := uncoveredFinding(ref, where, h, sans, sources)
+				if route := hosts[h]; route != "" {
+					f.Detail += "; routed by " + route
+				}
+				findings = append(findings, f)
+			}
+			if len(byPort[l.Port]) < 2 || strings.HasPrefix(h, "*") {
+				continue
+			}
+			if sel := sniListener(byPort[l.Port], h); sel != nil && sel.Name != l.Name && strings.Join(sel.Certs, ",") != strings.Join(l.Certs, ",") {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryTLS,
+					Code:       types.CodeTLSSNIListenerMismatch,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("%s: SNI %s selects listener %s, which serves a different certificate", where, h, sel.Name),
+					Detail:     fmt.Sprintf("%s is the more specific match for %s (hostname %q vs %q); clients get %s", sel.Name, h, sel.Hostname, l.Hostname, strings.Join(sel.Certs, ", ")),
+					Suggestion: fmt.Sprintf("Attach the route to listener %s, or give listener %s a hostname that does not overlap.", sel.Name, l.Name),
+				})
+			}
+		}
+
+		if l.Hostname == "" && len(byPort[l.Port]) > 1 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryTLS,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s has no hostname: its certificate is the default for any SNI not matched by another listener on the port", where),
+				Detail:     fmt.Sprintf("certificates=%s", strings.Join(l.Certs, ", ")),
+				Suggestion: "Clients without SNI, or with an unexpected hostname, receive this certificate; make sure it is the intended default.",
+			})
+		}
+	}
+	return findings, len(listeners)
+}
+
+func (t *CheckCertificateSNITool) checkIstioGateway(ctx context.Context, r *certResolver, gw *unstructured.Unstructured) ([]types.DiagnosticFinding, int) {
+	ref := &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+	servers, _, _ := unstructured.NestedSlice(gw.Object, "spec", "servers")
+	var findings []types.DiagnosticFinding
+	checked := 0
+	for i, s := range servers {
+		sm, _ := s.(map[string]interface{})
+		mode, _, _ := unstructured.NestedString(sm, "tls", "mode")
+		credential, _, _ := unstructured.NestedString(sm, "tls", "credentialName")
+		if mode == "" || mode == "PASSTHROUGH" || mode == "AUTO_PASSTHROUGH" || mode == "ISTIO_MUTUAL" || credential == "" {
+			continue
+		}
+		checked++
+		where := fmt.Sprintf("server[%d]", i)
+		if port, _, _ := unstructured.NestedString(sm, "port", "name"); port != "" {
+			where = "server " + port
+		}
+		sans, sources, errs := r.resolveAll(ctx, []string{gw.GetNamespace() + "/" + credential})
+		if len(errs) > 0 {
+			findings = append(findings, unreadableFinding(ref, where, errs))
+			continue
+		}
+		hosts, _, _ := unstructured.NestedStringSlice(sm, "hosts")
+		for _, h := range hosts {
+			if _, host, ok := strings.Cut(h, "/"); ok {
+				h = host
+			}
+			if h == "*" {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryTLS,
+					Code:       types.CodeTLSCatchAllListener,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("%s accepts any host with a certificate for %s", where, strings.Join(sans, ", ")),
+					Suggestion: "List the served hostnames so VirtualServices for other hosts do not get this certificate.",
+				})
+				continue
+			}
+			if !certCovers(sans, h) {
+				findings = append(findings, uncoveredFinding(ref, where, h, sans, sources))
+			}
+		}
+	}
+	return findings, checked
+}
+
+func (t *CheckCertificateSNITool) checkIngress(ctx context.Context, r *certResolver, ing *unstructured.Unstructured) ([]types.DiagnosticFinding, int) {
+	tlsEntries, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
+	if len(tlsEntries) == 0 {
+		return nil, 0
+	}
+	ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+	var findings []types.DiagnosticFinding
+	var tlsHosts []string
+	for i, e := range tlsEntries {
+		em, _ := e.(map[string]interface{})
+		hosts, _, _ := unstructured.NestedStringSlice(em, "hosts")
+		tlsHosts = append(tlsHosts, hosts...)
+		secret, _ := em["secretName"].(string)
+		where := fmt.Sprintf("tls[%d]", i)
+		if secret == "" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryTLS,
+				Code:       types.CodeTLSIngressNoSecret,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s has no secretName: %s get the controller's default certificate", where, strings.Join(hosts, ", ")),
+				Suggestion: "Set secretName, unless the controller's default certificate is meant to cover these hosts.",
+			})
+			continue
+		}
+		sans, sources, errs := r.resolveAll(ctx, []string{ing.GetNamespace() + "/" + secret})
+		if len(errs) > 0 {
+			findings = append(findings, unreadableFinding(ref, where, errs))
+			continue
+		}
+		for _, h := range hosts {
+			if !certCovers(sans, h) {
+				findings = append(findings, uncoveredFinding(ref, where, h, sans, sources))
+			}
+		}
+	}
+
+	rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+	for _, rule := range rules {
+		rm, _ := rule.(map[string]interface{})
+		host, _ := rm["host"].(string)
+		if host == "" {
+			continue
+		}
+		listed := false
+		for _, th := range tlsHosts {
+			if hostnameMatches(th, host) {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryTLS,
+				Code:       types.CodeTLSHostMissingFromTLS,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Host %s is routed but missing from spec.tls: SNI %s gets the controller's default certificate", host, host),
+				Suggestion: fmt.Sprintf("Add %s to the hosts of a spec.tls entry whose certificate covers it.", host),
+			})
+		}
+	}
+	return findings, len(tlsEntries)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
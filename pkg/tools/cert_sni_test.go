@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestSANCovers(t *testing.T) {
+	cases := []struct {
+		san, host string
+		want      bool
+	}{
+		{"api.example.com", "API.example.com", true},
+		{"*.example.com", "api.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "v1.api.example.com", false},
+		{"*.example.com", "*.example.com", true},
+		{"*.example.com", "api.example.org", false},
+	}
+	for _, c := range cases {
+		if got := sanCovers(c.san, c.host); got != c.want {
+			t.Errorf("sanCovers(%q, %q) = %v, want %v", c.san, c.host, got, c.want)
+		}
+	}
+}
+
+func TestListenerRouteHosts(t *testing.T) {
+	cases := []struct {
+		listener string
+		route    []string
+		want     []string
+	}{
+		{"", []string{"a.example.com"}, []string{"a.example.com"}},
+		{"*.example.com", nil, []string{"*.example.com"}},
+		{"*.example.com", []string{"v1.api.example.com", "other.org"}, []string{"v1.api.example.com"}},
+		{"api.example.com", []string{"*.example.com"}, []string{"api.example.com"}},
+		{"", nil, nil},
+	}
+	for _, c := range cases {
+		if got := listenerRouteHosts(c.listener, c.route); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("listenerRouteHosts(%q, %v) = %v, want %v", c.listener, c.route, got, c.want)
+		}
+	}
+}
+
+func TestSNIListener(t *testing.T) {
+	listeners := []tlsListener{
+		{Name: "default"},
+		{Name: "wildcard", Hostname: "*.example.com"},
+		{Name: "api", Hostname: "api.example.com"},
+	}
+	for host, want := range map[string]string{
+		"api.example.com": "api",
+		"www.example.com": "wildcard",
+		"example.org":     "default",
+	} {
+		if got := sniListener(listeners, host); got == nil || got.Name != want {
+			t.Errorf("sniListener(%q) = %v, want %s", host, got, want)
+		}
+	}
+}
+
+func TestCheckCertificateSNI(t *testing.T) {
+	obj := func(apiVersion, kind, ns, name string, spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	listener := func(name, hostname, secret string) interface{} {
+		l := map[string]interface{}{
+			"name": name, "protocol": "HTTPS", "port": int64(443),
+			"tls": map[string]interface{}{"certificateRefs": []interface{}{map[string]interface{}{"name": secret}}},
+		}
+		if hostname != "" {
+			l["hostname"] = hostname
+		}
+		return l
+	}
+	gw := obj("gateway.networking.k8s.io/v1", "Gateway", "infra", "public", map[string]interface{}{
+		"listeners": []interface{}{
+			listener("wildcard", "*.example.com", "wildcard-cert"),
+			listener("api", "api.example.com", "api-cert"),
+		},
+	})
+	route := obj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra", "sectionName": "wildcard"}},
+		"hostnames":  []interface{}{"v1.shop.example.com", "api.example.com"},
+	})
+	wildcardCert := obj("cert-manager.io/v1", "Certificate", "infra", "wildcard", map[string]interface{}{
+		"secretName": "wildcard-cert", "dnsNames": []interface{}{"*.example.com"},
+	})
+	apiCert := obj("cert-manager.io/v1", "Certificate", "infra", "api", map[string]interface{}{
+		"secretName": "api-cert", "dnsNames": []interface{}{"api.example.com"},
+	})
+	ing := obj("networking.k8s.io/v1", "Ingress", "shop", "legacy", map[string]interface{}{
+		"tls":   []interface{}{map[string]interface{}{"hosts": []interface{}{"legacy.example.com"}}},
+		"rules": []interface{}{map[string]interface{}{"host": "legacy.example.com"}, map[string]interface{}{"host": "admin.example.com"}},
+	})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		gatewaysV1GVR: "GatewayList", gatewaysV1B1GVR: "GatewayList",
+		httpRoutesV1GVR: "HTTPRouteList", httpRoutesV1B1GVR: "HTTPRouteList",
+		grpcRoutesV1GVR: "GRPCRouteList", grpcRoutesV1B1GVR: "GRPCRouteList",
+		istioGatewayV1GVR: "GatewayList", istioGatewayV1B1GVR: "GatewayList",
+		ingressGVR: "IngressList", certificatesGVR: "CertificateList", secretsGVR: "SecretList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, route, wildcardCert, apiCert, ing)
+	// The fake tracker would guess the resource "gatewaies" from the kind.
+	if err := client.Tracker().Create(gatewaysV1GVR, gw, gw.GetNamespace()); err != nil {
+		t.Fatal(err)
+	}
+	tool := &CheckCertificateSNITool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		summaries = append(summaries, string(f.Severity)+" "+f.Summary)
+	}
+	all := strings.Join(summaries, "\n")
+	for _, want := range []string{
+		"critical Listener wildcard (port 443): certificate does not cover v1.shop.example.com",
+		"warning Listener wildcard (port 443): SNI api.example.com selects listener api",
+		"warning tls[0] has no secretName",
+		"warning Host admin.example.com is routed but missing from spec.tls",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if len(summaries) != 4 {
+		t.Errorf("expected 4 findings, got:\n%s", all)
+	}
+}
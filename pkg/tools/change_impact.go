@@ -0,0 +1,805 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// changeKinds are the kinds analyze_change_impact evaluates.
+var changeKinds = map[schema.GroupKind]bool{
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:       true,
+	{Group: "security.istio.io", Kind: "AuthorizationPolicy"}: true,
+	{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}:   true,
+	{Group: "gateway.networking.k8s.io", Kind: "GRPCRoute"}:   true,
+}
+
+// maxChangePairs bounds the source and destination pairs evaluated for
+// newly allowed traffic.
+const maxChangePairs = 20000
+
+// flowState is how policies treat a flow, from the least to the most open.
+type flowState int
+
+const (
+	flowDenied flowState = iota
+	// flowPartial allows some ports, or some requests depending on L7
+	// attributes of AuthorizationPolicy rules.
+	flowPartial
+	flowAllowed
+)
+
+func (s flowState) String() string {
+	switch s {
+	case flowAllowed:
+		return "allowed"
+	case flowPartial:
+		return "partly allowed"
+	}
+	return "denied"
+}
+
+// applyChange returns objs with changes replacing the objects of the same
+// namespace and name, or added, or removed when del is set.
+func applyChange(objs []unstructured.Unstructured, changes []*unstructured.Unstructured, del bool) []unstructured.Unstructured {
+	pending := make(map[string]*unstructured.Unstructured, len(changes))
+	for _, c := range changes {
+		pending[c.GetNamespace()+"/"+c.GetName()] = c
+	}
+	out := make([]unstructured.Unstructured, 0, len(objs)+len(changes))
+	for _, o := range objs {
+		key := o.GetNamespace() + "/" + o.GetName()
+		if c, ok := pending[key]; ok {
+			if !del {
+				out = append(out, *c)
+			}
+			delete(pending, key)
+			continue
+		}
+		out = append(out, o)
+	}
+	if !del {
+		for _, c := range changes {
+			if _, ok := pending[c.GetNamespace()+"/"+c.GetName()]; ok {
+				out = append(out, *c)
+			}
+		}
+	}
+	return out
+}
+
+// --- AuthorizationPolicy evaluation ---
+
+// authzMatch is how much of a flow's traffic an AuthorizationPolicy rule
+// matches.
+type authzMatch int
+
+const (
+	matchNone authzMatch = iota
+	// matchSome depends on request attributes a flow does not have, such
+	// as paths, methods, JWT principals or when conditions.
+	matchSome
+	matchAll
+)
+
+// authzSource is the identity Istio sees for the source of a request: none
+// for sources outside the mesh.
+type authzSource struct {
+	meshed    bool
+	namespace string
+	principal string
+}
+
+// istioValueMatches matches an AuthorizationPolicy value: exact, "*", a
+// "prefix*" or a "*suffix".
+func istioValueMatches(pattern, value string) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(value, pattern[1:])
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(value, pattern[:len(pattern)-1])
+	}
+	return pattern == value
+}
+
+func anyValueMatches(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if istioValueMatches(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// authzSourceMatches matches one from.source of a rule. Identity fields only
+// match meshed sources; IP blocks and request principals depend on the
+// request.
+func authzSourceMatches(source map[string]interface{}, src authzSource) authzMatch {
+	m := matchAll
+	for _, field := range []struct {
+		key   string
+		value string
+		not   bool
+	}{
+		{"namespaces", src.namespace, false},
+		{"principals", src.principal, false},
+		{"notNamespaces", src.namespace, true},
+		{"notPrincipals", src.principal, true},
+	} {
+		patterns, ok, _ := unstructured.NestedStringSlice(source, field.key)
+		if !ok {
+			continue
+		}
+		switch {
+		case !src.meshed && field.not:
+			m = min(m, matchSome)
+		case !src.meshed || anyValueMatches(patterns, field.value) == field.not:
+			return matchNone
+		}
+	}
+	for _, key := range []string{"ipBlocks", "notIpBlocks", "remoteIpBlocks", "notRemoteIpBlocks", "requestPrincipals", "notRequestPrincipals"} {
+		if _, ok := source[key]; ok {
+			m = min(m, matchSome)
+		}
+	}
+	return m
+}
+
+// authzRuleMatches matches an AuthorizationPolicy rule against the requests
+// of src to a destination port.
+func authzRuleMatches(rule map[string]interface{}, src authzSource, port int64) authzMatch {
+	m := matchAll
+	if from, ok, _ := unstructured.NestedSlice(rule, "from"); ok {
+		best := matchNone
+		for _, f := range from {
+			source, _, _ := unstructured.NestedMap(f.(map[string]interface{}), "source")
+			best = max(best, authzSourceMatches(source, src))
+		}
+		m = min(m, best)
+	}
+	if to, ok, _ := unstructured.NestedSlice(rule, "to"); ok {
+		best := matchNone
+		for _, t := range to {
+			op, _, _ := unstructured.NestedMap(t.(map[string]interface{}), "operation")
+			om := matchAll
+			p := strconv.FormatInt(port, 10)
+			if ports, ok, _ := unstructured.NestedStringSlice(op, "ports"); ok && !containsString(ports, p) {
+				om = matchNone
+			}
+			if ports, ok, _ := unstructured.NestedStringSlice(op, "notPorts"); ok && containsString(ports, p) {
+				om = matchNone
+			}
+			for key := range op {
+				if key != "ports" && key != "notPorts" {
+					om = min(om, matchSome)
+				}
+			}
+			best = max(best, om)
+		}
+		m = min(m, best)
+	}
+	if _, ok := rule["when"]; ok {
+		m = min(m, matchSome)
+	}
+	return m
+}
+
+// evaluateAuthorization applies the AuthorizationPolicies of a destination
+// to the requests of src on port: a DENY match denies, then, when ALLOW
+// policies apply, a request must match one of them. CUSTOM policies, whose
+// decision is external, can deny some requests.
+func evaluateAuthorization(policies []unstructured.Unstructured, src authzSource, port int64) (flowState, string) {
+	denied, allowed := matchNone, matchNone
+	var deniedBy, allowedBy string
+	allowPolicies := 0
+	for _, p := range policies {
+		action, _, _ := unstructured.NestedString(p.Object, "spec", "action")
+		rules, _, _ := unstructured.NestedSlice(p.Object, "spec", "rules")
+		best := matchNone
+		for _, r := range rules {
+			rm, _ := r.(map[string]interface{})
+			best = max(best, authzRuleMatches(rm, src, port))
+		}
+		name := p.GetNamespace() + "/" + p.GetName()
+		switch orDefault(action, "ALLOW") {
+		case "ALLOW":
+			allowPolicies++
+			if best > allowed {
+				allowed, allowedBy = best, name
+			}
+		case "DENY":
+			if best > denied {
+				denied, deniedBy = best, name
+			}
+		case "CUSTOM":
+			if best > matchNone && denied == matchNone {
+				denied, deniedBy = matchSome, name+" (CUSTOM)"
+			}
+		}
+	}
+
+	switch {
+	case denied == matchAll:
+		return flowDenied, "denied by AuthorizationPolicy " + deniedBy
+	case allowPolicies > 0 && allowed == matchNone:
+		return flowDenied, fmt.Sprintf("no rule of the %d ALLOW AuthorizationPolicies matches", allowPolicies)
+	case denied == matchSome:
+		return flowPartial, "some requests match AuthorizationPolicy " + deniedBy
+	case allowed == matchSome:
+		return flowPartial, "only some requests match AuthorizationPolicy " + allowedBy
+	case allowPolicies > 0:
+		return flowAllowed, "allowed by AuthorizationPolicy " + allowedBy
+	}
+	return flowAllowed, "no AuthorizationPolicy applies"
+}
+
+// --- flow evaluation ---
+
+// policySet is one side, before or after the change, of the policies.
+type policySet struct {
+	netpols []unstructured.Unstructured
+	authz   []unstructured.Unstructured
+}
+
+// flowEnv is the cluster state flows are evaluated in.
+type flowEnv struct {
+	nsLabels map[string]map[string]string
+	services map[string]*unstructured.Unstructured // "namespace/name"
+	// nodePods are the representative pods of each graph node.
+	nodePods map[string][]graphPod
+}
+
+// newFlowEnv keeps one pod per node, namespace, labels, ports, service
+// account and sidecar: policies cannot tell such pods apart. Gateway nodes
+// get the pods of their Gateway API deployment or Istio gateway selector.
+func newFlowEnv(services []unstructured.Unstructured, gpods []graphPod, nsLabels map[string]map[string]string, istioGateways []unstructured.Unstructured) *flowEnv {
+	env := &flowEnv{nsLabels: nsLabels, services: make(map[string]*unstructured.Unstructured), nodePods: make(map[string][]graphPod)}
+	for i := range services {
+		env.services[services[i].GetNamespace()+"/"+services[i].GetName()] = &services[i]
+	}
+	seen := make(map[string]bool)
+	add := func(id string, p graphPod) {
+		key := id + "|" + p.Namespace + "|" + labels.Set(p.Labels).String() + "|" + fmt.Sprint(p.Ports) + "|" + p.serviceAccount + "|" + strconv.FormatBool(p.proxy)
+		if !seen[key] {
+			seen[key] = true
+			env.nodePods[id] = append(env.nodePods[id], p)
+		}
+	}
+	for _, p := range gpods {
+		for _, n := range p.nodes {
+			add(n.ID, p)
+		}
+		if gw := p.Labels["gateway.networking.k8s.io/gateway-name"]; gw != "" {
+			add(newGraphNode("Gateway", p.Namespace, gw).ID, p)
+		}
+	}
+	for i := range istioGateways {
+		gw := &istioGateways[i]
+		sel, _, _ := unstructured.NestedStringMap(gw.Object, "spec", "selector")
+		if len(sel) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(sel)
+		for _, p := range gpods {
+			if selector.Matches(labels.Set(p.Labels)) {
+				add(newGraphNode("Gateway", gw.GetNamespace(), gw.GetName()).ID, p)
+			}
+		}
+	}
+	return env
+}
+
+func (env *flowEnv) ambient(p graphPod) bool {
+	return p.Labels["istio.io/dataplane-mode"] == "ambient" ||
+		env.nsLabels[p.Namespace]["istio.io/dataplane-mode"] == "ambient" && p.Labels["istio.io/dataplane-mode"] != "none"
+}
+
+func (env *flowEnv) authzSource(p graphPod) authzSource {
+	if !p.proxy && !env.ambient(p) {
+		return authzSource{}
+	}
+	return authzSource{
+		meshed:    true,
+		namespace: p.Namespace,
+		principal: fmt.Sprintf("cluster.local/ns/%s/sa/%s", p.Namespace, orDefault(p.serviceAccount, "default")),
+	}
+}
+
+// evaluateFlow returns how ps treats traffic from the pods of node from to
+// the pods of the Service to on its ports, with the evidence of the most
+// open pair. ok is false when either end has no known pods.
+func (env *flowEnv) evaluateFlow(ps policySet, from, to string) (state flowState, evidence string, ok bool) {
+	svcKey := strings.TrimPrefix(to, "Service ")
+	svc := env.services[svcKey]
+	srcs, dsts := env.nodePods[from], env.nodePods[to]
+	if svc == nil || len(srcs) == 0 || len(dsts) == 0 {
+		return flowDenied, "", false
+	}
+	svcNs, svcName, _ := strings.Cut(svcKey, "/")
+	ports := serviceTargetPorts(svc)
+
+	pairs, open := 0, 0
+	first := true
+	for _, d := range dsts {
+		var authz []unstructured.Unstructured
+		if d.proxy || env.ambient(d) {
+			names := applyingAuthorizationPolicies([]podPorts{d.podPorts}, svcNs, svcName, ps.authz)
+			for _, p := range ps.authz {
+				if containsString(names, p.GetNamespace()+"/"+p.GetName()) {
+					authz = append(authz, p)
+				}
+			}
+		}
+		var cps []containerPort
+		for _, sp := range ports {
+			if cp, ok := targetContainerPort(sp, d.podPorts); ok {
+				cps = append(cps, cp)
+			} else if sp.TargetPort != 0 {
+				cps = append(cps, containerPort{Port: sp.TargetPort, Protocol: sp.Protocol})
+			}
+		}
+		if len(cps) == 0 {
+			cps = []containerPort{{Protocol: "TCP"}}
+		}
+		for _, s := range srcs {
+			for _, cp := range cps {
+				egress, err := evaluatePolicies(ps.netpols, "Egress", s.podPorts, d.podPorts, d.podPorts, cp, env.nsLabels)
+				if err != nil {
+					continue
+				}
+				ingress, err := evaluatePolicies(ps.netpols, "Ingress", d.podPorts, s.podPorts, d.podPorts, cp, env.nsLabels)
+				if err != nil {
+					continue
+				}
+				st, ev := flowAllowed, ""
+				if !egress.allowed() || !ingress.allowed() {
+					st = flowDenied
+					ev = describeDecision("Egress", egress, s.podPorts, d.podPorts) + "; " + describeDecision("Ingress", ingress, d.podPorts, s.podPorts)
+				} else if d.proxy || env.ambient(d) {
+					st, ev = evaluateAuthorization(authz, env.authzSource(s), cp.Port)
+				}
+				pairs++
+				if st != flowDenied {
+					open++
+				}
+				if first || st > state {
+					state, evidence, first = st, fmt.Sprintf("port %d/%s: %s", cp.Port, cp.Protocol, orDefault(ev, "no policy denies it")), false
+				}
+			}
+		}
+	}
+	// Some ports or pods allowed and others denied is partial.
+	if state == flowAllowed && open < pairs {
+		state = flowPartial
+	}
+	return state, evidence, true
+}
+
+// flowChange is the effect of a change on traffic from one node to another.
+type flowChange struct {
+	edge          graphEdge
+	before, after flowState
+	evidence      string
+}
+
+// observed reports whether telemetry saw the flow.
+func (c flowChange) observed() bool {
+	return containsString(c.edge.Sources, "istio") || containsString(c.edge.Sources, "hubble")
+}
+
+// changeFindings reports the graph flows a change breaks or restricts, one
+// per flow, and the traffic it newly allows, grouped by destination.
+// Breaking a routed or observed flow is critical.
+func changeFindings(changes []flowChange, verb string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	opened := make(map[string][]string)
+	openedVia := make(map[string][]string)
+	for _, c := range changes {
+		// Pairs outside the graph are candidates for new traffic only.
+		if len(c.edge.Sources) == 0 && c.after < c.before {
+			continue
+		}
+		ns, name, _ := strings.Cut(strings.TrimPrefix(c.edge.To, "Service "), "/")
+		ref := &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name, APIVersion: "v1"}
+		detail := c.evidence
+		if len(c.edge.Via) > 0 {
+			detail = fmt.Sprintf("flow from %s; %s", strings.Join(c.edge.Via, ", "), detail)
+		}
+		if c.observed() {
+			detail = fmt.Sprintf("observed at %.2f/s; %s", c.edge.Rate, detail)
+		}
+		switch {
+		case c.after < c.before && c.after == flowDenied:
+			severity := types.SeverityWarning
+			if c.observed() || containsString(c.edge.Sources, "route") {
+				severity = types.SeverityCritical
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeChangeBreaksFlow,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s breaks %s -> %s (%s before)", verb, c.edge.From, c.edge.To, c.before),
+				Detail:     detail,
+				Suggestion: "Add a rule allowing this source to the manifest, or confirm the flow is meant to stop",
+			})
+		case c.after < c.before:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeChangeRestrictsFlow,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s restricts %s -> %s to some ports or requests", verb, c.edge.From, c.edge.To),
+				Detail:     detail,
+				Suggestion: "Check that the ports, paths or methods the source uses are still allowed",
+			})
+		case c.after > c.before && c.before == flowDenied:
+			opened[c.edge.To] = append(opened[c.edge.To], c.edge.From)
+			openedVia[c.edge.To] = append(openedVia[c.edge.To], c.edge.Via...)
+		}
+	}
+
+	targets := make([]string, 0, len(opened))
+	for to := range opened {
+		targets = append(targets, to)
+	}
+	sort.Strings(targets)
+	for _, to := range targets {
+		from := opened[to]
+		sort.Strings(from)
+		ns, name, _ := strings.Cut(strings.TrimPrefix(to, "Service "), "/")
+		severity := types.SeverityInfo
+		for _, f := range from {
+			if strings.HasPrefix(f, "Gateway ") || strings.HasPrefix(f, "Ingress ") {
+				severity = types.SeverityWarning
+			}
+		}
+		detail := "newly allowed from " + truncateList(from, 10)
+		if via := sortedSet(toSet(openedVia[to])); len(via) > 0 {
+			detail += "; via " + strings.Join(via, ", ")
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeChangeOpensFlow,
+			Resource:   &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name, APIVersion: "v1"},
+			Summary:    fmt.Sprintf("%s opens %s to %d sources that could not reach it before", verb, to, len(from)),
+			Detail:     detail,
+			Suggestion: "Check that every listed source should reach this Service; narrow the selectors or rules otherwise",
+		})
+	}
+	return findings
+}
+
+func toSet(items []string) map[string]bool {
+	out := make(map[string]bool, len(items))
+	for _, i := range items {
+		out[i] = true
+	}
+	return out
+}
+
+// routeChanges compares the route edges before and after a change: edges
+// gone break routed traffic, new ones route traffic that was not routed.
+func routeChanges(before, after *serviceGraph) []flowChange {
+	routeEdges := func(g *serviceGraph) map[[2]string]graphEdge {
+		out := make(map[[2]string]graphEdge)
+		for _, e := range g.Edges {
+			if containsString(e.Sources, "route") {
+				out[[2]string{e.From, e.To}] = e
+			}
+		}
+		return out
+	}
+	b, a := routeEdges(before), routeEdges(after)
+	var out []flowChange
+	for key, e := range b {
+		if _, ok := a[key]; !ok {
+			out = append(out, flowChange{edge: e, before: flowAllowed, after: flowDenied, evidence: "no longer routed"})
+		}
+	}
+	for key, e := range a {
+		if _, ok := b[key]; !ok {
+			out = append(out, flowChange{edge: e, before: flowDenied, after: flowAllowed, evidence: "now routed"})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].edge.From != out[j].edge.From {
+			return out[i].edge.From < out[j].edge.From
+		}
+		return out[i].edge.To < out[j].edge.To
+	})
+	return out
+}
+
+// --- analyze_change_impact ---
+
+type AnalyzeChangeImpactTool struct {
+	BaseTool
+	// Prometheus, when set, supplies the traffic observed by Istio and Hubble.
+	Prometheus *prometheus.Client
+}
+
+func (t *AnalyzeChangeImpactTool) Name() string { return "analyze_change_impact" }
+func (t *AnalyzeChangeImpactTool) Description() string {
+	return "Report the blast radius of a proposed NetworkPolicy, AuthorizationPolicy, HTTPRoute or GRPCRoute change before merging it: which configured or observed traffic flows of the service graph it breaks or restricts, and which it newly allows or routes"
+}
+func (t *AnalyzeChangeImpactTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"manifests": map[string]interface{}{
+				"type":        "string",
+				"description": "YAML or JSON manifests of the new or modified objects, multiple documents separated by ---",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace for objects that do not set one (default: default)",
+			},
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"apply", "delete"},
+				"description": "Evaluate applying the manifests (default) or deleting the objects they name",
+			},
+			"window": map[string]interface{}{
+				"type":        "string",
+				"description": "Window of observed traffic, e.g. 1h or 24h (default 1h)",
+			},
+		},
+		"required": []string{"manifests"},
+	}
+}
+
+func (t *AnalyzeChangeImpactTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	data := getStringArg(args, "manifests", "")
+	defaultNs := getStringArg(args, "namespace", "default")
+	action := getStringArg(args, "action", "apply")
+	if strings.TrimSpace(data) == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "manifests is required"}
+	}
+	if action != "apply" && action != "delete" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("invalid action %q: expected apply or delete", action)}
+	}
+	window, err := promWindow(getStringArg(args, "window", "1h"))
+	if err != nil {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: err.Error()}
+	}
+
+	objs, findings := parseManifests(data)
+	changed := make(map[string][]*unstructured.Unstructured)
+	var names, ignored []string
+	for _, obj := range objs {
+		gk := obj.GroupVersionKind().GroupKind()
+		if !changeKinds[gk] {
+			ignored = append(ignored, fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName()))
+			continue
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNs)
+		}
+		changed[gk.Kind] = append(changed[gk.Kind], obj)
+		names = append(names, fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
+	}
+	if len(names) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "the manifests contain no NetworkPolicy, AuthorizationPolicy, HTTPRoute or GRPCRoute",
+			Detail:  strings.Join(ignored, ", "),
+		}
+	}
+	if len(ignored) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("%d objects were not analyzed", len(ignored)),
+			Detail:   strings.Join(ignored, ", "),
+		})
+	}
+	del := action == "delete"
+	verb := "Applying " + strings.Join(names, ", ")
+	if del {
+		verb = "Deleting " + strings.Join(names, ", ")
+	}
+
+	// Cluster state
+	svcList, err := t.listResource(ctx, servicesGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	podList, err := t.listResource(ctx, podsGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	nsLabels := make(map[string]map[string]string)
+	if nsList, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+		for _, item := range nsList.Items {
+			nsLabels[item.GetName()] = item.GetLabels()
+		}
+	}
+	gpods := graphPods(podList.Items, svcList.Items)
+	routes := t.listRoutes(ctx)
+	var vss, ingresses, istioGateways []unstructured.Unstructured
+	if list, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ""); err == nil {
+		vss = list.Items
+	}
+	if list, err := t.listResource(ctx, ingressGVR, ""); err == nil {
+		ingresses = list.Items
+	}
+	if list, err := t.listResourceWithFallback(ctx, istioGatewayV1GVR, istioGatewayV1B1GVR, ""); err == nil {
+		istioGateways = list.Items
+	}
+
+	// Flows: the route edges of the graph and, with Prometheus, observed traffic
+	routeGraph := func(routes []routeInfo) *graphBuilder {
+		g := newGraphBuilder(svcList.Items)
+		g.addRouteEdges(routes)
+		g.addVirtualServiceEdges(vss)
+		g.addIngressEdges(ingresses)
+		return g
+	}
+	flows := routeGraph(routes)
+	if t.Prometheus != nil {
+		if _, err := flows.addTelemetryEdges(ctx, t.Prometheus, window, workloadNodes(podList.Items, gpods)); err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryPolicy,
+				Summary:  "Observed traffic not read: the Prometheus query failed",
+				Detail:   err.Error(),
+			})
+		}
+	}
+	flowGraph := flows.graph()
+
+	var changes []flowChange
+	evaluated, skipped := 0, 0
+
+	// Route changes
+	if len(changed["HTTPRoute"]) > 0 || len(changed["GRPCRoute"]) > 0 {
+		after := make([]routeInfo, 0, len(routes))
+		for _, kind := range []string{"HTTPRoute", "GRPCRoute"} {
+			var current []unstructured.Unstructured
+			for _, r := range routes {
+				if r.kind == kind {
+					u := unstructured.Unstructured{Object: r.obj}
+					u.SetNamespace(r.namespace)
+					u.SetName(r.name)
+					current = append(current, u)
+				}
+			}
+			for _, u := range applyChange(current, changed[kind], del) {
+				after = append(after, routeInfo{kind: kind, name: u.GetName(), namespace: u.GetNamespace(), obj: u.Object})
+			}
+		}
+		rc := routeChanges(routeGraph(routes).graph(), routeGraph(after).graph())
+		changes = append(changes, rc...)
+		evaluated += len(rc)
+	}
+
+	// Policy changes
+	if len(changed["NetworkPolicy"]) > 0 || len(changed["AuthorizationPolicy"]) > 0 {
+		var before policySet
+		if list, err := t.listResource(ctx, networkPoliciesGVR, ""); err == nil {
+			before.netpols = list.Items
+		}
+		if list, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ""); err == nil {
+			before.authz = list.Items
+		}
+		after := policySet{
+			netpols: applyChange(before.netpols, changed["NetworkPolicy"], del),
+			authz:   applyChange(before.authz, changed["AuthorizationPolicy"], del),
+		}
+		env := newFlowEnv(svcList.Items, gpods, nsLabels, istioGateways)
+
+		// Existing flows, then the traffic the change could newly allow:
+		// every source to the Services of the namespaces it touches and,
+		// for egress rules, the sources of those namespaces to every Service.
+		pairs := make(map[[2]string]graphEdge)
+		for _, e := range flowGraph.Edges {
+			pairs[[2]string{e.From, e.To}] = e
+		}
+		ingressNs, egressNs := make(map[string]bool), make(map[string]bool)
+		for _, c := range changed["AuthorizationPolicy"] {
+			ingressNs[c.GetNamespace()] = true
+		}
+		for _, set := range [][]unstructured.Unstructured{before.netpols, after.netpols} {
+			for _, np := range set {
+				for _, c := range changed["NetworkPolicy"] {
+					if np.GetNamespace() != c.GetNamespace() || np.GetName() != c.GetName() {
+						continue
+					}
+					ingressNs[c.GetNamespace()] = ingressNs[c.GetNamespace()] || policyHasType(np, "Ingress")
+					egressNs[c.GetNamespace()] = egressNs[c.GetNamespace()] || policyHasType(np, "Egress")
+				}
+			}
+		}
+		nodeNs := func(id string) string {
+			_, ref, _ := strings.Cut(id, " ")
+			ns, _, _ := strings.Cut(ref, "/")
+			return ns
+		}
+		nodes := make([]string, 0, len(env.nodePods))
+		for id := range env.nodePods {
+			nodes = append(nodes, id)
+		}
+		sort.Strings(nodes)
+		capped := false
+		for _, to := range nodes {
+			if !strings.HasPrefix(to, "Service ") {
+				continue
+			}
+			for _, from := range nodes {
+				key := [2]string{from, to}
+				if _, ok := pairs[key]; ok || from == to {
+					continue
+				}
+				if !ingressNs[nodeNs(to)] && !ingressNs[istioRootNamespace] && !egressNs[nodeNs(from)] {
+					continue
+				}
+				if len(pairs) >= maxChangePairs {
+					capped = true
+					break
+				}
+				pairs[key] = graphEdge{From: from, To: to}
+			}
+		}
+		keys := make([][2]string, 0, len(pairs))
+		for key := range pairs {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i][0] != keys[j][0] {
+				return keys[i][0] < keys[j][0]
+			}
+			return keys[i][1] < keys[j][1]
+		})
+		for _, key := range keys {
+			b, _, ok := env.evaluateFlow(before, key[0], key[1])
+			if !ok {
+				skipped++
+				continue
+			}
+			a, evidence, _ := env.evaluateFlow(after, key[0], key[1])
+			evaluated++
+			if a != b {
+				changes = append(changes, flowChange{edge: pairs[key], before: b, after: a, evidence: evidence})
+			}
+		}
+		if capped {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryPolicy,
+				Summary:  fmt.Sprintf("Only %d source and destination pairs were evaluated for newly allowed traffic", maxChangePairs),
+			})
+		}
+	}
+
+	findings = append(findings, changeFindings(changes, verb)...)
+	if skipped > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("%d flows were not evaluated: no running pods are known for their source or destination", skipped),
+			Detail:   "Ingresses, and Gateways whose proxy pods lack the gateway.networking.k8s.io/gateway-name label, have no known pods",
+		})
+	}
+	if len(changes) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("%s breaks no flow and opens none (%d flows evaluated)", verb, evaluated),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, defaultNs, ""), nil
+}
@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func authzPolicy(ns, name, action string, rules ...interface{}) unstructured.Unstructured {
+	spec := map[string]interface{}{"action": action}
+	if rules != nil {
+		spec["rules"] = rules
+	}
+	return *ipamObj("security.istio.io/v1", "AuthorizationPolicy", ns, name, map[string]interface{}{"spec": spec})
+}
+
+func TestApplyChange(t *testing.T) {
+	current := []unstructured.Unstructured{
+		*ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "a", nil),
+		*ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "b", nil),
+	}
+	modified := ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "b", map[string]interface{}{"spec": map[string]interface{}{}})
+	added := ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "c", nil)
+
+	got := applyChange(current, []*unstructured.Unstructured{modified, added}, false)
+	if len(got) != 3 || got[1].Object["spec"] == nil || got[2].GetName() != "c" {
+		t.Errorf("apply = %+v", got)
+	}
+	got = applyChange(current, []*unstructured.Unstructured{modified, added}, true)
+	if len(got) != 1 || got[0].GetName() != "a" {
+		t.Errorf("delete = %+v", got)
+	}
+}
+
+func TestEvaluateAuthorization(t *testing.T) {
+	frontend := authzSource{meshed: true, namespace: "shop", principal: "cluster.local/ns/shop/sa/frontend"}
+	fromFrontend := map[string]interface{}{"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+		"principals": []interface{}{"cluster.local/ns/shop/sa/front*"},
+	}}}}
+	onlyGet := map[string]interface{}{"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{
+		"methods": []interface{}{"GET"},
+	}}}}
+	onPort := map[string]interface{}{"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{
+		"ports": []interface{}{"9090"},
+	}}}}
+
+	tests := []struct {
+		name     string
+		policies []unstructured.Unstructured
+		src      authzSource
+		want     flowState
+	}{
+		{"no policy", nil, frontend, flowAllowed},
+		{"allow principal", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW", fromFrontend)}, frontend, flowAllowed},
+		{"unmeshed source has no principal", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW", fromFrontend)}, authzSource{}, flowDenied},
+		{"allow nothing", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW")}, frontend, flowDenied},
+		{"deny wins", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW", fromFrontend), authzPolicy("shop", "d", "DENY", fromFrontend)}, frontend, flowDenied},
+		{"allow some methods", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW", onlyGet)}, frontend, flowPartial},
+		{"other port", []unstructured.Unstructured{authzPolicy("shop", "p", "ALLOW", onPort)}, frontend, flowDenied},
+	}
+	for _, tt := range tests {
+		if got, _ := evaluateAuthorization(tt.policies, tt.src, 8080); got != tt.want {
+			t.Errorf("%s: state = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChangeImpactNetworkPolicy(t *testing.T) {
+	services := []unstructured.Unstructured{
+		graphService("shop", "web", map[string]interface{}{"app": "web"}),
+		graphService("shop", "db", map[string]interface{}{"app": "db"}),
+	}
+	pods := []unstructured.Unstructured{
+		*tenantPod("shop", "web-1", "10.0.0.1", map[string]string{"app": "web"}, 8080),
+		*tenantPod("shop", "db-1", "10.0.0.2", map[string]string{"app": "db"}, 5432),
+		*tenantPod("batch", "job-1", "10.0.1.1", map[string]string{"app": "job"}, 8080),
+	}
+	nsLabels := map[string]map[string]string{"shop": {"kubernetes.io/metadata.name": "shop"}, "batch": {"kubernetes.io/metadata.name": "batch"}}
+	env := newFlowEnv(services, graphPods(pods, services), nsLabels, nil)
+
+	allowAll := ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "db", map[string]interface{}{"spec": map[string]interface{}{
+		"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "db"}},
+		"ingress": []interface{}{map[string]interface{}{"from": []interface{}{map[string]interface{}{
+			"namespaceSelector": map[string]interface{}{},
+		}}}},
+	}})
+	before := policySet{}
+	after := policySet{netpols: applyChange(nil, []*unstructured.Unstructured{allowAll}, false)}
+
+	web, db, job := "Service shop/web", "Service shop/db", "Workload batch/job-1"
+	// Any namespace can still reach db: nothing changes.
+	for _, from := range []string{web, job} {
+		b, _, ok := env.evaluateFlow(before, from, db)
+		a, _, _ := env.evaluateFlow(after, from, db)
+		if !ok || b != flowAllowed || a != flowAllowed {
+			t.Errorf("%s -> db: before %s, after %s", from, b, a)
+		}
+	}
+
+	// Only the web pods: the job is cut off.
+	after.netpols = []unstructured.Unstructured{*ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "db", map[string]interface{}{"spec": map[string]interface{}{
+		"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "db"}},
+		"ingress": []interface{}{map[string]interface{}{"from": []interface{}{map[string]interface{}{
+			"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		}}}},
+	}})}
+	changes := []flowChange{}
+	for _, from := range []string{web, job} {
+		b, _, _ := env.evaluateFlow(before, from, db)
+		a, evidence, _ := env.evaluateFlow(after, from, db)
+		if a != b {
+			changes = append(changes, flowChange{edge: graphEdge{From: from, To: db, Sources: []string{"istio"}}, before: b, after: a, evidence: evidence})
+		}
+	}
+	findings := changeFindings(changes, "Applying NetworkPolicy shop/db")
+	if len(findings) != 1 || findings[0].Code != types.CodeChangeBreaksFlow || findings[0].Severity != types.SeverityCritical ||
+		findings[0].Resource.Name != "db" {
+		t.Fatalf("findings = %+v", findings)
+	}
+
+	// The reverse change opens the job's traffic, a candidate pair without
+	// graph sources.
+	opened := changeFindings([]flowChange{{edge: graphEdge{From: job, To: db}, before: flowDenied, after: flowAllowed}}, "Deleting NetworkPolicy shop/db")
+	if len(opened) != 1 || opened[0].Code != types.CodeChangeOpensFlow || opened[0].Severity != types.SeverityInfo {
+		t.Errorf("opened = %+v", opened)
+	}
+	// Pairs outside the graph are never reported as broken.
+	if got := changeFindings([]flowChange{{edge: graphEdge{From: job, To: db}, before: flowAllowed, after: flowDenied}}, "x"); len(got) != 0 {
+		t.Errorf("candidate break = %+v", got)
+	}
+}
+
+func TestRouteChanges(t *testing.T) {
+	services := []unstructured.Unstructured{graphService("shop", "web", nil), graphService("shop", "web-v2", nil)}
+	route := func(backend string) []routeInfo {
+		return []routeInfo{{kind: "HTTPRoute", namespace: "shop", name: "web", obj: map[string]interface{}{"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra"}},
+			"rules":      []interface{}{map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": backend}}}},
+		}}}}
+	}
+	graph := func(routes []routeInfo) *serviceGraph {
+		g := newGraphBuilder(services)
+		g.addRouteEdges(routes)
+		return g.graph()
+	}
+	changes := routeChanges(graph(route("web")), graph(route("web-v2")))
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	findings := changeFindings(changes, "Applying HTTPRoute shop/web")
+	if len(findings) != 2 || findings[0].Code != types.CodeChangeBreaksFlow || findings[0].Severity != types.SeverityCritical ||
+		findings[1].Code != types.CodeChangeOpensFlow || findings[1].Severity != types.SeverityWarning {
+		t.Errorf("findings = %+v", findings)
+	}
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// AuditPermissions checks the RBAC permissions of every registered tool as
+// the server's own identity, records the degraded tools in the registry and
+// logs the rules to add to the ClusterRole.
+func AuditPermissions(ctx context.Context, registry *Registry, clients *k8s.Clients, probeNamespace string) []ToolPermissions {
+	results := CheckPermissions(ctx, clients.Clientset, registry.List(), probeNamespace)
+	registry.SetDegraded(results)
+	var degraded []string
+	for _, tp := range results {
+		if tp.Degraded() {
+			degraded = append(degraded, tp.Tool)
+		}
+	}
+	if len(degraded) > 0 {
+		slog.Warn("rbac: tools are degraded by missing permissions; add these rules to the ClusterRole",
+			"tools", degraded, "rules", MissingRules(results))
+	} else {
+		slog.Info("rbac: every tool has the permissions it needs", "tools", len(results))
+	}
+	return results
+}
+
+// --- check_permissions ---
+
+// CheckPermissionsTool reports the tools missing RBAC permissions.
+type CheckPermissionsTool struct {
+	BaseTool
+	Registry *Registry
+}
+
+func (t *CheckPermissionsTool) Name() string { return "check_permissions" }
+func (t *CheckPermissionsTool) Description() string {
+	return "Check with SelfSubjectAccessReviews that the server (or the impersonated caller) has the RBAC permissions each tool needs, and report degraded tools with the exact ClusterRole rules to add"
+}
+func (t *CheckPermissionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"tool": map[string]interface{}{
+				"type":        "string",
+				"description": "Check one tool only (default: all tools)",
+			},
+		},
+	}
+}
+
+func (t *CheckPermissionsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "tool", "")
+	toolList := t.Registry.List()
+	if name != "" {
+		tool, ok := t.Registry.Get(name)
+		if !ok {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("tool %q is not registered", name),
+			}
+		}
+		toolList = []Tool{tool}
+	}
+
+	results := CheckPermissions(ctx, t.Clients.Clientset, toolList, t.Cfg.ProbeNamespace)
+	// The registry records the server's own permissions, not a caller's.
+	imp, impersonating := k8s.ImpersonationFrom(ctx)
+	if !impersonating && name == "" {
+		t.Registry.SetDegraded(results)
+	}
+	identity := "the server's service account"
+	if impersonating {
+		identity = "user " + imp.User
+	}
+
+	var findings []types.DiagnosticFinding
+	degraded := 0
+	for _, tp := range results {
+		if tp.Degraded() {
+			degraded++
+			missing := make([]string, 0, len(tp.Missing))
+			for _, p := range tp.Missing {
+				missing = append(missing, p.String())
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeRBACPermissionMissing,
+				Summary:    fmt.Sprintf("%s is degraded: %s cannot %s", tp.Tool, identity, strings.Join(missing, ", ")),
+				Detail:     fmt.Sprintf("The tool fails or returns partial results with Forbidden errors. Needs: %s", permissionList(tp.Required)),
+				Suggestion: "Add to the ClusterRole (or a Role in the namespace):\n" + MissingRules([]ToolPermissions{tp}),
+			})
+		}
+		if len(tp.Unchecked) > 0 {
+			unchecked := make([]string, 0, len(tp.Unchecked))
+			for _, p := range tp.Unchecked {
+				unchecked = append(unchecked, p.String())
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeRBACCheckFailed,
+				Summary:    fmt.Sprintf("%s: could not check %s", tp.Tool, strings.Join(unchecked, ", ")),
+				Suggestion: "Check that the API server accepts SelfSubjectAccessReviews, then run check_permissions again.",
+			})
+		}
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("%d of %d tools have the permissions they need as %s", len(results)-degraded, len(results), identity),
+	}
+	if degraded > 0 {
+		summary.Severity = types.SeverityWarning
+		summary.Detail = "Rules to add:\n" + MissingRules(results)
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+func permissionList(perms []Permission) string {
+	out := make([]string, 0, len(perms))
+	for _, p := range perms {
+		out = append(out, p.String())
+	}
+	return strings.Join(out, ", ")
+}
+
+// DegradedNote describes the permissions a degraded tool is missing, for its
+// MCP description and error responses.
+func DegradedNote(missing []Permission) string {
+	return "Degraded: missing RBAC permissions: " + permissionList(missing) + " (run check_permissions for the rules to add)"
+}
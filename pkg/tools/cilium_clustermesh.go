@@ -0,0 +1,361 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	// ciliumAgentMetricsPort is the agent Prometheus port (prometheus.enabled).
+	ciliumAgentMetricsPort = "9962"
+	// kvstoremeshMetricsPort is the kvstoremesh container's Prometheus port
+	// in the clustermesh-apiserver pods.
+	kvstoremeshMetricsPort = "9964"
+	clustermeshAPIServer   = "clustermesh-apiserver"
+	// ciliumDefaultMaxClusters is the default max-connected-clusters.
+	ciliumDefaultMaxClusters = 255
+)
+
+// remoteClusterStatus is the connection state of one remote cluster as the
+// agents (or kvstoremesh replicas) report it.
+type remoteClusterStatus struct {
+	ready    []string
+	notReady []string
+	failures float64
+}
+
+// parseRemoteClusters adds the remote cluster readiness one agent or
+// kvstoremesh replica reports to status. prefix is cilium_clustermesh or
+// cilium_kvstoremesh.
+func parseRemoteClusters(text, prefix, reporter string, status map[string]*remoteClusterStatus) {
+	get := func(cluster string) *remoteClusterStatus {
+		s := status[cluster]
+		if s == nil {
+			s = &remoteClusterStatus{}
+			status[cluster] = s
+		}
+		return s
+	}
+	for _, sample := range promSamples(text, prefix+"_remote_cluster_readiness_status") {
+		cluster := sample.Labels["target_cluster"]
+		if cluster == "" {
+			continue
+		}
+		if sample.Value >= 1 {
+			get(cluster).ready = append(get(cluster).ready, reporter)
+		} else {
+			get(cluster).notReady = append(get(cluster).notReady, reporter)
+		}
+	}
+	for _, sample := range promSamples(text, prefix+"_remote_cluster_failures") {
+		if cluster := sample.Labels["target_cluster"]; cluster != "" {
+			get(cluster).failures += sample.Value
+		}
+	}
+}
+
+// clusterMeshIdentityFindings checks the cluster name and ID every cluster
+// of a mesh needs to be unique: the defaults are shared by every cluster
+// that did not set them.
+func clusterMeshIdentityFindings(config map[string]string, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(severity, summary, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIClusterIdentityInvalid,
+			Resource:   ref,
+			Summary:    summary,
+			Suggestion: suggestion,
+		})
+	}
+	name := orDefault(config["cluster-name"], "default")
+	if name == "default" {
+		add(types.SeverityWarning, "cluster-name is \"default\": every cluster that does not set a name uses it",
+			"Set a cluster name unique across the mesh (cluster.name in the Helm values)")
+	}
+	maxClusters := ciliumDefaultMaxClusters
+	if v, err := strconv.Atoi(config["max-connected-clusters"]); err == nil && v > 0 {
+		maxClusters = v
+	}
+	id, err := strconv.Atoi(orDefault(config["cluster-id"], "0"))
+	switch {
+	case err != nil:
+		add(types.SeverityCritical, fmt.Sprintf("cluster-id %q is not a number", config["cluster-id"]),
+			fmt.Sprintf("Set a cluster ID between 1 and %d", maxClusters))
+	case id == 0:
+		add(types.SeverityWarning, "cluster-id is 0, which cannot join a cluster mesh",
+			fmt.Sprintf("Set a cluster ID between 1 and %d, unique across the mesh (cluster.id in the Helm values)", maxClusters))
+	case id > maxClusters:
+		add(types.SeverityCritical, fmt.Sprintf("cluster-id %d is above max-connected-clusters %d", id, maxClusters),
+			fmt.Sprintf("Use a cluster ID between 1 and %d", maxClusters))
+	}
+	return findings
+}
+
+// clusterMeshServiceFindings checks that remote clusters can reach the
+// clustermesh-apiserver Service.
+func clusterMeshServiceFindings(svc *corev1.Service) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name}
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Resource: ref,
+	}
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		var addrs []string
+		for _, in := range svc.Status.LoadBalancer.Ingress {
+			addrs = append(addrs, orDefault(in.IP, in.Hostname))
+		}
+		if len(addrs) == 0 {
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeCNIClusterMeshAPIServerUnhealthy
+			f.Summary = fmt.Sprintf("Service %s/%s is a LoadBalancer without an address: remote clusters cannot connect", svc.Namespace, svc.Name)
+			f.Suggestion = "Check the load balancer controller events on the Service"
+			return []types.DiagnosticFinding{f}
+		}
+		f.Summary = fmt.Sprintf("Service %s/%s is exposed at %s", svc.Namespace, svc.Name, strings.Join(addrs, ", "))
+	case corev1.ServiceTypeNodePort:
+		f.Severity = types.SeverityInfo
+		f.Summary = fmt.Sprintf("Service %s/%s is a NodePort: remote clusters connect to node addresses, which change as nodes are replaced", svc.Namespace, svc.Name)
+	default:
+		f.Severity = types.SeverityWarning
+		f.Code = types.CodeCNIClusterMeshAPIServerUnhealthy
+		f.Summary = fmt.Sprintf("Service %s/%s is a %s Service, reachable from remote clusters only over a shared network", svc.Namespace, svc.Name, orDefault(string(svc.Spec.Type), "ClusterIP"))
+		f.Suggestion = "Expose the clustermesh-apiserver with a LoadBalancer or NodePort Service (clustermesh.apiserver.service.type)"
+	}
+	return []types.DiagnosticFinding{f}
+}
+
+// remoteClusterFindings reports each remote cluster, critical when some
+// reporters are not connected to it.
+func remoteClusterFindings(status map[string]*remoteClusterStatus, reporterKind string) []types.DiagnosticFinding {
+	clusters := make([]string, 0, len(status))
+	for c := range status {
+		clusters = append(clusters, c)
+	}
+	sort.Strings(clusters)
+	var findings []types.DiagnosticFinding
+	for _, c := range clusters {
+		s := status[c]
+		total := len(s.ready) + len(s.notReady)
+		if len(s.notReady) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryConnectivity,
+				Summary:  fmt.Sprintf("Remote cluster %s: ready on %d/%d %s", c, len(s.ready), total, reporterKind),
+				Detail:   fmt.Sprintf("%.0f connection failures since the %s started", s.failures, reporterKind),
+			})
+			continue
+		}
+		sort.Strings(s.notReady)
+		severity := types.SeverityCritical
+		if len(s.ready) > 0 {
+			severity = types.SeverityWarning
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIRemoteClusterUnreachable,
+			Summary:    fmt.Sprintf("Remote cluster %s is not ready on %d/%d %s", c, len(s.notReady), total, reporterKind),
+			Detail:     fmt.Sprintf("Not ready: %s; %.0f connection failures", truncateList(s.notReady, 10), s.failures),
+			Suggestion: "Services and identities of the remote cluster are stale there; check that its clustermesh-apiserver is reachable from these nodes, the cilium-clustermesh Secret holds its address and certificates, and run cilium-dbg status --all-clusters on an affected agent",
+		})
+	}
+	return findings
+}
+
+// --- check_cilium_clustermesh ---
+
+type CheckCiliumClusterMeshTool struct{ BaseTool }
+
+func (t *CheckCiliumClusterMeshTool) Name() string { return "check_cilium_clustermesh" }
+func (t *CheckCiliumClusterMeshTool) Description() string {
+	return "Check Cilium cluster mesh: cluster name and ID, clustermesh-apiserver health and exposure, and the remote cluster connection status each agent and kvstoremesh replica reports, across the CiliumNodes of the cluster"
+}
+func (t *CheckCiliumClusterMeshTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace where Cilium is installed (default: kube-system)",
+			},
+			"metrics_port": map[string]interface{}{
+				"type":        "string",
+				"description": "Agent Prometheus port (default: " + ciliumAgentMetricsPort + ")",
+			},
+		},
+	}
+}
+
+func (t *CheckCiliumClusterMeshTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "kube-system")
+	port := getStringArg(args, "metrics_port", ciliumAgentMetricsPort)
+	var findings []types.DiagnosticFinding
+
+	cmRef := &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: "cilium-config"}
+	cm, err := t.Clients.Clientset.CoreV1().ConfigMaps(ns).Get(ctx, "cilium-config", metav1.GetOptions{})
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get ConfigMap %s/cilium-config", ns),
+			Detail:  err.Error(),
+			Cause:   err,
+		}
+	}
+	clusterName := orDefault(cm.Data["cluster-name"], "default")
+	findings = append(findings, clusterMeshIdentityFindings(cm.Data, cmRef)...)
+
+	// clustermesh-apiserver, which serves this cluster's state to the others
+	depRef := &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: clustermeshAPIServer, APIVersion: "apps/v1"}
+	dep, err := t.Clients.Clientset.AppsV1().Deployments(ns).Get(ctx, clustermeshAPIServer, metav1.GetOptions{})
+	apiServer := err == nil
+	switch {
+	case apierrors.IsNotFound(err):
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Resource: depRef,
+			Summary:  fmt.Sprintf("No %s Deployment in %s: other clusters cannot connect to this one", clustermeshAPIServer, ns),
+			Detail:   "Enable it with clustermesh.useAPIServer=true, or cilium clustermesh enable",
+		})
+	case err != nil:
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryConnectivity,
+			Resource: depRef,
+			Summary:  fmt.Sprintf("Could not read Deployment %s/%s", ns, clustermeshAPIServer),
+			Detail:   err.Error(),
+		})
+	default:
+		var replicas int32 = 1
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Resource: depRef,
+			Summary:  fmt.Sprintf("%s: %d/%d replicas ready", clustermeshAPIServer, dep.Status.ReadyReplicas, replicas),
+		}
+		if dep.Status.ReadyReplicas < replicas {
+			f.Severity = types.SeverityWarning
+			if dep.Status.ReadyReplicas == 0 {
+				f.Severity = types.SeverityCritical
+			}
+			f.Code = types.CodeCNIClusterMeshAPIServerUnhealthy
+			f.Suggestion = fmt.Sprintf("Check kubectl -n %s logs deploy/%s -c apiserver; remote clusters lose this cluster's services and identities while no replica is ready", ns, clustermeshAPIServer)
+		}
+		findings = append(findings, f)
+
+		if svc, err := t.Clients.Clientset.CoreV1().Services(ns).Get(ctx, clustermeshAPIServer, metav1.GetOptions{}); err == nil {
+			findings = append(findings, clusterMeshServiceFindings(svc)...)
+		} else {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIClusterMeshAPIServerUnhealthy,
+				Resource:   &types.ResourceRef{Kind: "Service", Namespace: ns, Name: clustermeshAPIServer},
+				Summary:    fmt.Sprintf("Service %s/%s not found: remote clusters cannot connect", ns, clustermeshAPIServer),
+				Detail:     err.Error(),
+				Suggestion: "Re-run cilium clustermesh enable, or restore the Service from the Helm chart",
+			})
+		}
+	}
+
+	// Remote clusters as each agent sees them
+	agents, err := t.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=cilium"})
+	if err != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIAgentCheckFailed,
+			Summary:    "Could not list the Cilium agent pods",
+			Detail:     err.Error(),
+			Suggestion: "Verify Cilium is installed in the " + ns + " namespace",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "cilium"), nil
+	}
+	status := make(map[string]*remoteClusterStatus)
+	agentNodes := make(map[string]bool)
+	var scraped, failed []string
+	for i := range agents.Items {
+		pod := &agents.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		agentNodes[pod.Spec.NodeName] = true
+		raw, err := t.Clients.Clientset.CoreV1().Pods(ns).ProxyGet("http", pod.Name, port, "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			failed = append(failed, pod.Name)
+			continue
+		}
+		scraped = append(scraped, pod.Name)
+		parseRemoteClusters(string(raw), "cilium_clustermesh", orDefault(pod.Spec.NodeName, pod.Name), status)
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Resource: cmRef,
+		Summary:  fmt.Sprintf("Cluster %s: %d remote clusters seen by %d of %d running agents", clusterName, len(status), len(scraped), len(agentNodes)),
+	}
+	if len(failed) > 0 {
+		summary.Detail = fmt.Sprintf("No metrics on port %s from %s; enable prometheus.enabled in the Cilium Helm values", port, truncateList(failed, 5))
+	}
+	if list, err := t.listUnrecorded(ctx, ciliumNodesGVR, ""); err == nil {
+		var orphans []string
+		for _, n := range list.Items {
+			if !agentNodes[n.GetName()] {
+				orphans = append(orphans, n.GetName())
+			}
+		}
+		if len(orphans) > 0 {
+			sort.Strings(orphans)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIRemoteClusterUnreachable,
+				Summary:    fmt.Sprintf("%d of %d CiliumNodes have no running agent and are not connected to any remote cluster", len(orphans), len(list.Items)),
+				Detail:     truncateList(orphans, 10),
+				Suggestion: "Check the cilium DaemonSet pods on those nodes, or delete CiliumNodes of nodes that are gone",
+			})
+		}
+	}
+	findings = append(findings, summary)
+	findings = append(findings, remoteClusterFindings(status, "nodes")...)
+
+	// kvstoremesh caches the remote clusters for the agents when enabled
+	if apiServer {
+		pods, err := t.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=" + clustermeshAPIServer})
+		if err == nil {
+			kvstore := make(map[string]*remoteClusterStatus)
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+					continue
+				}
+				raw, err := t.Clients.Clientset.CoreV1().Pods(ns).ProxyGet("http", pod.Name, kvstoremeshMetricsPort, "/metrics", nil).DoRaw(ctx)
+				if err != nil {
+					continue
+				}
+				parseRemoteClusters(string(raw), "cilium_kvstoremesh", pod.Name, kvstore)
+			}
+			findings = append(findings, remoteClusterFindings(kvstore, "kvstoremesh replicas")...)
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "cilium"), nil
+}
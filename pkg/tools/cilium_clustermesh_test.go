@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseRemoteClusters(t *testing.T) {
+	status := make(map[string]*remoteClusterStatus)
+	parseRemoteClusters(`# HELP cilium_clustermesh_remote_cluster_readiness_status The readiness status of the remote cluster
+cilium_clustermesh_remote_cluster_readiness_status{source_cluster="east",source_node_name="node-a",target_cluster="west"} 1
+cilium_clustermesh_remote_cluster_readiness_status{source_cluster="east",source_node_name="node-a",target_cluster="north"} 0
+cilium_clustermesh_remote_cluster_failures{source_cluster="east",source_node_name="node-a",target_cluster="north"} 3
+`, "cilium_clustermesh", "node-a", status)
+	parseRemoteClusters(`cilium_clustermesh_remote_cluster_readiness_status{target_cluster="west"} 1
+cilium_clustermesh_remote_cluster_readiness_status{target_cluster="north"} 1
+`, "cilium_clustermesh", "node-b", status)
+
+	findings := remoteClusterFindings(status, "nodes")
+	if len(findings) != 2 {
+		t.Fatalf("findings = %+v", findings)
+	}
+	north, west := findings[0], findings[1]
+	if north.Severity != types.SeverityWarning || north.Code != types.CodeCNIRemoteClusterUnreachable ||
+		north.Summary != "Remote cluster north is not ready on 1/2 nodes" || !strings.Contains(north.Detail, "node-a; 3 connection failures") {
+		t.Errorf("north = %+v", north)
+	}
+	if west.Severity != types.SeverityOK || west.Summary != "Remote cluster west: ready on 2/2 nodes" {
+		t.Errorf("west = %+v", west)
+	}
+}
+
+func TestClusterMeshIdentityFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   []string
+	}{
+		{"configured", map[string]string{"cluster-name": "east", "cluster-id": "2"}, nil},
+		{"defaults", map[string]string{}, []string{types.SeverityWarning, types.SeverityWarning}},
+		{"above max", map[string]string{"cluster-name": "east", "cluster-id": "300"}, []string{types.SeverityCritical}},
+		{"raised max", map[string]string{"cluster-name": "east", "cluster-id": "300", "max-connected-clusters": "511"}, nil},
+		{"not a number", map[string]string{"cluster-name": "east", "cluster-id": "two"}, []string{types.SeverityCritical}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, f := range clusterMeshIdentityFindings(tt.config, nil) {
+			got = append(got, f.Severity)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: severities = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClusterMeshServiceFindings(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: clustermeshAPIServer},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+	if f := clusterMeshServiceFindings(svc)[0]; f.Severity != types.SeverityCritical || f.Code != types.CodeCNIClusterMeshAPIServerUnhealthy {
+		t.Errorf("pending load balancer = %+v", f)
+	}
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	if f := clusterMeshServiceFindings(svc)[0]; f.Severity != types.SeverityOK || !strings.Contains(f.Summary, "203.0.113.10") {
+		t.Errorf("load balancer = %+v", f)
+	}
+	svc.Spec.Type = corev1.ServiceTypeClusterIP
+	if f := clusterMeshServiceFindings(svc)[0]; f.Severity != types.SeverityWarning {
+		t.Errorf("cluster IP = %+v", f)
+	}
+}
@@ -0,0 +1,368 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var ciliumEgressGatewayGVR = schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumegressgatewaypolicies"}
+
+// egressGatewayEnv is the cluster state CiliumEgressGatewayPolicies are
+// validated against.
+type egressGatewayEnv struct {
+	// config is the cilium-config ConfigMap data, nil when it was not found.
+	config   map[string]string
+	podCIDRs []netip.Prefix
+	nodes    []unstructured.Unstructured
+	pods     []unstructured.Unstructured
+	nsLabels map[string]map[string]string
+}
+
+// configFindings reports agent settings that keep every egress gateway
+// policy from taking effect.
+func (e *egressGatewayEnv) configFindings() []types.DiagnosticFinding {
+	if e.config == nil {
+		return nil
+	}
+	ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: "kube-system", Name: "cilium-config"}
+	var findings []types.DiagnosticFinding
+	for _, setting := range []struct{ key, why string }{
+		{"enable-ipv4-egress-gateway", "the egress gateway is disabled"},
+		{"enable-bpf-masquerade", "the egress gateway needs BPF masquerading"},
+	} {
+		if e.config[setting.key] != "true" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIEgressGatewayDisabled,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s is not true: %s and no CiliumEgressGatewayPolicy applies", setting.key, setting.why),
+				Suggestion: "Set egressGateway.enabled=true, bpf.masquerade=true and kubeProxyReplacement=true in the Cilium Helm values, then restart the agents",
+			})
+		}
+	}
+	return findings
+}
+
+// gatewayNodes returns the nodes a policy's egressGateway nodeSelector
+// selects and the ready ones among them.
+func (e *egressGatewayEnv) gatewayNodes(gateway map[string]interface{}) (matched, ready []string, err error) {
+	sel, present, _ := unstructured.NestedMap(gateway, "nodeSelector")
+	selector, err := parseLabelSelector(normalizeCiliumSelector(sel, nil), present)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, n := range e.nodes {
+		if !selector.Matches(labels.Set(n.GetLabels())) {
+			continue
+		}
+		matched = append(matched, n.GetName())
+		conditions, _, _ := unstructured.NestedSlice(n.Object, "status", "conditions")
+		for _, c := range conditions {
+			cm, _ := c.(map[string]interface{})
+			if cm["type"] == "Ready" && cm["status"] == "True" {
+				ready = append(ready, n.GetName())
+			}
+		}
+	}
+	sort.Strings(matched)
+	sort.Strings(ready)
+	return matched, ready, nil
+}
+
+// selectedPods returns the pods, as namespace/name, a policy's selectors
+// select: a pod matches when one selector's podSelector and
+// namespaceSelector both match it.
+func (e *egressGatewayEnv) selectedPods(policy *unstructured.Unstructured) []string {
+	selectors, _, _ := unstructured.NestedSlice(policy.Object, "spec", "selectors")
+	seen := make(map[string]bool)
+	for _, s := range selectors {
+		sm, _ := s.(map[string]interface{})
+		podSel, podPresent, _ := unstructured.NestedMap(sm, "podSelector")
+		nsSel, nsPresent, _ := unstructured.NestedMap(sm, "namespaceSelector")
+		pods, err := parseLabelSelector(normalizeCiliumSelector(podSel, nil), podPresent || nsPresent)
+		if err != nil {
+			continue
+		}
+		namespaces, err := parseLabelSelector(normalizeCiliumSelector(nsSel, nil), true)
+		if err != nil {
+			continue
+		}
+		for i := range e.pods {
+			pod := &e.pods[i]
+			if pods.Matches(ciliumPodLabels(pod)) && namespaces.Matches(labels.Set(e.nsLabels[pod.GetNamespace()])) {
+				seen[qualifiedName(pod.GetNamespace(), pod.GetName())] = true
+			}
+		}
+	}
+	return sortedSet(seen)
+}
+
+// egressPrefixes parses a list of CIDRs, returning the invalid entries.
+func egressPrefixes(cidrs []string) ([]netip.Prefix, []string) {
+	var out []netip.Prefix
+	var invalid []string
+	for _, c := range cidrs {
+		p, err := netip.ParsePrefix(c)
+		if err != nil {
+			invalid = append(invalid, c)
+			continue
+		}
+		out = append(out, p.Masked())
+	}
+	return out, invalid
+}
+
+// prefixesOverlap reports whether two prefixes share an address.
+func prefixesOverlap(a, b netip.Prefix) bool {
+	return a.Addr().BitLen() == b.Addr().BitLen() && (a.Contains(b.Addr()) || b.Contains(a.Addr()))
+}
+
+// validate checks one policy's destinations, gateway and selectors.
+func (e *egressGatewayEnv) validate(policy *unstructured.Unstructured) []types.DiagnosticFinding {
+	name := policy.GetName()
+	ref := &types.ResourceRef{Kind: "CiliumEgressGatewayPolicy", Name: name, APIVersion: "cilium.io/v2"}
+	var findings []types.DiagnosticFinding
+	add := func(severity string, code types.FindingCode, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       code,
+			Resource:   ref,
+			Summary:    summary,
+			Detail:     detail,
+			Suggestion: suggestion,
+		})
+	}
+
+	destCIDRs, _, _ := unstructured.NestedStringSlice(policy.Object, "spec", "destinationCIDRs")
+	dests, invalid := egressPrefixes(destCIDRs)
+	if len(destCIDRs) == 0 {
+		add(types.SeverityCritical, types.CodeCNIEgressGatewayPolicyInvalid,
+			fmt.Sprintf("CiliumEgressGatewayPolicy %s has no destinationCIDRs and matches no traffic", name), "",
+			"List the external ranges to send through the gateway, 0.0.0.0/0 for all")
+	}
+	excludedCIDRs, _, _ := unstructured.NestedStringSlice(policy.Object, "spec", "excludedCIDRs")
+	excluded, badExcluded := egressPrefixes(excludedCIDRs)
+	if invalid = append(invalid, badExcluded...); len(invalid) > 0 {
+		add(types.SeverityCritical, types.CodeCNIEgressGatewayPolicyInvalid,
+			fmt.Sprintf("CiliumEgressGatewayPolicy %s has invalid CIDRs: %s", name, strings.Join(invalid, ", ")), "", "Fix the CIDRs; the agents reject the policy")
+	}
+	for _, d := range dests {
+		for _, pod := range e.podCIDRs {
+			if d.Bits() >= pod.Bits() && prefixesOverlap(d, pod) {
+				add(types.SeverityWarning, types.CodeCNIEgressGatewayPolicyInvalid,
+					fmt.Sprintf("CiliumEgressGatewayPolicy %s destination %s is inside the pod CIDR %s", name, d, pod),
+					"Traffic between pods is never sent through an egress gateway", "Remove in-cluster ranges from destinationCIDRs")
+			}
+		}
+	}
+	for _, x := range excluded {
+		covered := false
+		for _, d := range dests {
+			covered = covered || (x.Bits() >= d.Bits() && prefixesOverlap(x, d))
+		}
+		if !covered {
+			add(types.SeverityWarning, types.CodeCNIEgressGatewayPolicyInvalid,
+				fmt.Sprintf("CiliumEgressGatewayPolicy %s excluded CIDR %s is outside its destinationCIDRs and has no effect", name, x), "", "")
+		}
+	}
+
+	gateway, hasGateway, _ := unstructured.NestedMap(policy.Object, "spec", "egressGateway")
+	if !hasGateway {
+		add(types.SeverityCritical, types.CodeCNIEgressGatewayPolicyInvalid,
+			fmt.Sprintf("CiliumEgressGatewayPolicy %s has no egressGateway", name), "", "Set egressGateway.nodeSelector to the gateway nodes")
+	} else {
+		if ip, _ := gateway["egressIP"].(string); ip != "" {
+			if _, err := netip.ParseAddr(ip); err != nil {
+				add(types.SeverityCritical, types.CodeCNIEgressGatewayPolicyInvalid,
+					fmt.Sprintf("CiliumEgressGatewayPolicy %s egressIP %q is not an IP address", name, ip), "", "")
+			}
+		}
+		matched, ready, err := e.gatewayNodes(gateway)
+		switch {
+		case err != nil:
+			add(types.SeverityCritical, types.CodeCNIEgressGatewayPolicyInvalid,
+				fmt.Sprintf("CiliumEgressGatewayPolicy %s has an invalid nodeSelector", name), err.Error(), "")
+		case len(matched) == 0:
+			add(types.SeverityCritical, types.CodeCNIEgressGatewayNoNode,
+				fmt.Sprintf("CiliumEgressGatewayPolicy %s selects no gateway node: matching traffic is dropped", name), "",
+				"Label a node to match egressGateway.nodeSelector, or fix the selector")
+		case len(ready) == 0:
+			add(types.SeverityCritical, types.CodeCNIEgressGatewayNoNode,
+				fmt.Sprintf("CiliumEgressGatewayPolicy %s gateway nodes are not Ready: %s", name, strings.Join(matched, ", ")),
+				"Matching traffic is dropped until a gateway node is Ready", "")
+		case len(matched) > 1:
+			add(types.SeverityInfo, "",
+				fmt.Sprintf("CiliumEgressGatewayPolicy %s nodeSelector matches %d nodes; one of them carries the traffic", name, len(matched)),
+				"Ready: "+truncateList(ready, 5)+". Cilium OSS does not fail over between gateway nodes; when the chosen node goes away, the policy moves to another one", "")
+		}
+	}
+
+	if len(e.pods) > 0 && len(e.selectedPods(policy)) == 0 {
+		add(types.SeverityInfo, "", fmt.Sprintf("CiliumEgressGatewayPolicy %s selects no running pod", name), "", "")
+	}
+	return findings
+}
+
+// egressGatewayConflicts reports policies that select the same pods for
+// overlapping destinations: Cilium applies one of them per flow.
+func (e *egressGatewayEnv) egressGatewayConflicts(policies []unstructured.Unstructured) []types.DiagnosticFinding {
+	type policyScope struct {
+		name  string
+		pods  map[string]bool
+		dests []netip.Prefix
+	}
+	scopes := make([]policyScope, 0, len(policies))
+	for i := range policies {
+		cidrs, _, _ := unstructured.NestedStringSlice(policies[i].Object, "spec", "destinationCIDRs")
+		dests, _ := egressPrefixes(cidrs)
+		scopes = append(scopes, policyScope{name: policies[i].GetName(), pods: toSet(e.selectedPods(&policies[i])), dests: dests})
+	}
+	var findings []types.DiagnosticFinding
+	for i := range scopes {
+		for j := i + 1; j < len(scopes); j++ {
+			var shared []string
+			for pod := range scopes[i].pods {
+				if scopes[j].pods[pod] {
+					shared = append(shared, pod)
+				}
+			}
+			if len(shared) == 0 {
+				continue
+			}
+			var overlaps []string
+			for _, a := range scopes[i].dests {
+				for _, b := range scopes[j].dests {
+					if prefixesOverlap(a, b) {
+						overlaps = append(overlaps, a.String()+"/"+b.String())
+					}
+				}
+			}
+			if len(overlaps) == 0 {
+				continue
+			}
+			sort.Strings(shared)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIEgressGatewayConflict,
+				Resource:   &types.ResourceRef{Kind: "CiliumEgressGatewayPolicy", Name: scopes[i].name, APIVersion: "cilium.io/v2"},
+				Summary:    fmt.Sprintf("CiliumEgressGatewayPolicies %s and %s both apply to %d pods for overlapping destinations", scopes[i].name, scopes[j].name, len(shared)),
+				Detail:     fmt.Sprintf("Pods: %s; destinations: %s", truncateList(shared, 5), strings.Join(overlaps, ", ")),
+				Suggestion: "Make the selectors or destinationCIDRs disjoint; which gateway a flow uses is otherwise not defined",
+			})
+		}
+	}
+	return findings
+}
+
+// --- validate_cilium_egress_gateway ---
+
+type ValidateCiliumEgressGatewayTool struct{ BaseTool }
+
+func (t *ValidateCiliumEgressGatewayTool) Name() string { return "validate_cilium_egress_gateway" }
+func (t *ValidateCiliumEgressGatewayTool) Description() string {
+	return "Validate CiliumEgressGatewayPolicies: egress gateway and BPF masquerading enabled, gateway nodes selected and Ready, destination and excluded CIDRs, selected pods, and policies competing for the same pods and destinations"
+}
+func (t *ValidateCiliumEgressGatewayTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Only validate the CiliumEgressGatewayPolicy with this name",
+			},
+		},
+	}
+}
+
+func (t *ValidateCiliumEgressGatewayTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "name", "")
+
+	policies, err := t.listResource(ctx, ciliumEgressGatewayGVR, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list CiliumEgressGatewayPolicy",
+			Detail:  err.Error(),
+		}
+	}
+
+	env := &egressGatewayEnv{nsLabels: make(map[string]map[string]string)}
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace("kube-system").Get(ctx, "cilium-config", metav1.GetOptions{}); err == nil {
+		env.config, _, _ = unstructured.NestedStringMap(cm.Object, "data")
+		if env.config == nil {
+			env.config = map[string]string{}
+		}
+	}
+	if list, err := t.listResource(ctx, nodesGVR, ""); err == nil {
+		env.nodes = list.Items
+	}
+	var ciliumNodes []unstructured.Unstructured
+	if list, err := t.listUnrecorded(ctx, ciliumNodesGVR, ""); err == nil {
+		ciliumNodes = list.Items
+	}
+	env.podCIDRs = ciliumPodCIDRs(env.config, env.nodes, ciliumNodes)
+	if list, err := t.listResource(ctx, podsGVR, ""); err == nil {
+		for _, p := range list.Items {
+			if phase, _, _ := unstructured.NestedString(p.Object, "status", "phase"); phase == "Running" {
+				env.pods = append(env.pods, p)
+			}
+		}
+	}
+	if list, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+		for _, n := range list.Items {
+			lbls := n.GetLabels()
+			if lbls == nil {
+				lbls = map[string]string{}
+			}
+			lbls["kubernetes.io/metadata.name"] = n.GetName()
+			env.nsLabels[n.GetName()] = lbls
+		}
+	}
+
+	findings := env.configFindings()
+	validated := 0
+	for i := range policies.Items {
+		if name != "" && policies.Items[i].GetName() != name {
+			continue
+		}
+		validated++
+		findings = append(findings, env.validate(&policies.Items[i])...)
+	}
+	if name != "" && validated == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("CiliumEgressGatewayPolicy %s not found", name),
+		}
+	}
+	if name == "" {
+		findings = append(findings, env.egressGatewayConflicts(policies.Items)...)
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("%d CiliumEgressGatewayPolicies validated", validated),
+	}
+	for _, f := range findings {
+		if f.Severity == types.SeverityCritical || f.Severity == types.SeverityWarning {
+			summary.Severity = types.SeverityInfo
+		}
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", "cilium"), nil
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func egressGatewayPolicy(name string, spec map[string]interface{}) unstructured.Unstructured {
+	return *ipamObj("cilium.io/v2", "CiliumEgressGatewayPolicy", "", name, map[string]interface{}{"spec": spec})
+}
+
+func egressNode(name string, ready bool, lbls map[string]string) unstructured.Unstructured {
+	status := "False"
+	if ready {
+		status = "True"
+	}
+	n := ipamObj("v1", "Node", "", name, map[string]interface{}{"status": map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": status}},
+	}})
+	n.SetLabels(lbls)
+	return *n
+}
+
+func TestValidateCiliumEgressGateway(t *testing.T) {
+	gateway := func(role string) map[string]interface{} {
+		return map[string]interface{}{"nodeSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"egress": role}}}
+	}
+	selectors := []interface{}{map[string]interface{}{
+		"podSelector":       map[string]interface{}{"matchLabels": map[string]interface{}{"app": "billing"}},
+		"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"kubernetes.io/metadata.name": "shop"}},
+	}}
+	env := &egressGatewayEnv{
+		config:   map[string]string{"enable-ipv4-egress-gateway": "true"},
+		podCIDRs: []netip.Prefix{netip.MustParsePrefix("10.244.0.0/16")},
+		nodes: []unstructured.Unstructured{
+			egressNode("gw-1", true, map[string]string{"egress": "main"}),
+			egressNode("gw-2", false, map[string]string{"egress": "backup"}),
+		},
+		pods:     []unstructured.Unstructured{*tenantPod("shop", "billing-1", "10.244.1.2", map[string]string{"app": "billing"}, 8080)},
+		nsLabels: map[string]map[string]string{"shop": {"kubernetes.io/metadata.name": "shop"}},
+	}
+
+	good := egressGatewayPolicy("billing", map[string]interface{}{
+		"selectors": selectors, "destinationCIDRs": []interface{}{"0.0.0.0/0"}, "excludedCIDRs": []interface{}{"192.168.0.0/16"}, "egressGateway": gateway("main"),
+	})
+	if got := ciliumCodes(env.validate(&good)); got != "" {
+		t.Errorf("valid policy findings:\n%s", got)
+	}
+
+	bad := egressGatewayPolicy("partner", map[string]interface{}{
+		"selectors": selectors, "destinationCIDRs": []interface{}{"10.244.8.0/24", "198.51.100.0/24"}, "excludedCIDRs": []interface{}{"203.0.113.0/24"}, "egressGateway": gateway("backup"),
+	})
+	got := ciliumCodes(env.validate(&bad))
+	for _, want := range []string{
+		"warning CiliumEgressGatewayPolicy partner destination 10.244.8.0/24 is inside the pod CIDR 10.244.0.0/16 CNI021_EGRESS_GATEWAY_POLICY_INVALID",
+		"warning CiliumEgressGatewayPolicy partner excluded CIDR 203.0.113.0/24 is outside its destinationCIDRs and has no effect CNI021_EGRESS_GATEWAY_POLICY_INVALID",
+		"critical CiliumEgressGatewayPolicy partner gateway nodes are not Ready: gw-2 CNI020_EGRESS_GATEWAY_NO_NODE",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	conflicts := ciliumCodes(env.egressGatewayConflicts([]unstructured.Unstructured{good, bad}))
+	if !strings.Contains(conflicts, "warning CiliumEgressGatewayPolicies billing and partner both apply to 1 pods for overlapping destinations CNI023_EGRESS_GATEWAY_CONFLICT") {
+		t.Errorf("conflicts:\n%s", conflicts)
+	}
+
+	if got := ciliumCodes(env.configFindings()); !strings.Contains(got, "enable-bpf-masquerade is not true") || strings.Contains(got, "enable-ipv4-egress-gateway") {
+		t.Errorf("config findings:\n%s", got)
+	}
+}
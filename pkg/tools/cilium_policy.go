@@ -0,0 +1,634 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// ciliumNamespaceLabel is the label Cilium gives every endpoint with its
+// pod's namespace.
+const ciliumNamespaceLabel = "io.kubernetes.pod.namespace"
+
+// ciliumRuleSections are the rule lists of a Cilium policy spec, with the
+// allow section a deny section takes precedence over.
+var ciliumRuleSections = []struct {
+	name, allow, peer string
+	deny              bool
+}{
+	{name: "ingress", peer: "from"},
+	{name: "egress", peer: "to"},
+	{name: "ingressDeny", allow: "ingress", peer: "from", deny: true},
+	{name: "egressDeny", allow: "egress", peer: "to", deny: true},
+}
+
+// ciliumPolicySpec is one rule set of a policy: spec or an entry of specs.
+type ciliumPolicySpec struct {
+	path string
+	spec map[string]interface{}
+}
+
+// ciliumPolicySpecs returns the rule sets of a CiliumNetworkPolicy or
+// CiliumClusterwideNetworkPolicy, which may set spec, specs or both.
+func ciliumPolicySpecs(obj map[string]interface{}) []ciliumPolicySpec {
+	var out []ciliumPolicySpec
+	if spec, ok, _ := unstructured.NestedMap(obj, "spec"); ok {
+		out = append(out, ciliumPolicySpec{path: "spec", spec: spec})
+	}
+	specs, _, _ := unstructured.NestedSlice(obj, "specs")
+	for i, s := range specs {
+		if spec, ok := s.(map[string]interface{}); ok {
+			out = append(out, ciliumPolicySpec{path: fmt.Sprintf("specs[%d]", i), spec: spec})
+		}
+	}
+	return out
+}
+
+// ciliumLabelKey strips the source prefix Cilium selectors may carry
+// (k8s:app, any:app).
+func ciliumLabelKey(key string) string {
+	for _, source := range []string{"k8s:", "any:"} {
+		if strings.HasPrefix(key, source) {
+			return key[len(source):]
+		}
+	}
+	return key
+}
+
+// ciliumSelectorLabels returns the matchLabels of a policy's endpoint
+// selector with source prefixes stripped, and the namespace of a namespaced
+// policy as Cilium adds it.
+func ciliumSelectorLabels(policy *unstructured.Unstructured, spec map[string]interface{}) map[string]string {
+	out := make(map[string]string)
+	ml, _, _ := unstructured.NestedStringMap(spec, "endpointSelector", "matchLabels")
+	for k, v := range ml {
+		out[ciliumLabelKey(k)] = v
+	}
+	if ns := policy.GetNamespace(); ns != "" {
+		out[ciliumNamespaceLabel] = ns
+	}
+	return out
+}
+
+// normalizeCiliumSelector returns a label selector with the source prefixes
+// of its keys stripped, and extra labels required.
+func normalizeCiliumSelector(sel map[string]interface{}, extra map[string]string) map[string]interface{} {
+	ml := make(map[string]interface{})
+	matchLabels, _, _ := unstructured.NestedStringMap(sel, "matchLabels")
+	for k, v := range matchLabels {
+		ml[ciliumLabelKey(k)] = v
+	}
+	for k, v := range extra {
+		ml[k] = v
+	}
+	norm := map[string]interface{}{"matchLabels": ml}
+	exprs, _, _ := unstructured.NestedSlice(sel, "matchExpressions")
+	var normExprs []interface{}
+	for _, e := range exprs {
+		em, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := make(map[string]interface{}, len(em))
+		for k, v := range em {
+			c[k] = v
+		}
+		if key, _ := em["key"].(string); key != "" {
+			c["key"] = ciliumLabelKey(key)
+		}
+		normExprs = append(normExprs, c)
+	}
+	if normExprs != nil {
+		norm["matchExpressions"] = normExprs
+	}
+	return norm
+}
+
+// ciliumPodLabels returns a pod's labels with the namespace label Cilium
+// adds to its endpoint.
+func ciliumPodLabels(pod *unstructured.Unstructured) labels.Set {
+	set := labels.Set{ciliumNamespaceLabel: pod.GetNamespace()}
+	for k, v := range pod.GetLabels() {
+		set[k] = v
+	}
+	return set
+}
+
+// ciliumEndpointSelector converts a policy's endpoint selector for matching
+// ciliumPodLabels.
+func ciliumEndpointSelector(policy *unstructured.Unstructured, spec map[string]interface{}) (labels.Selector, error) {
+	sel, present, _ := unstructured.NestedMap(spec, "endpointSelector")
+	var extra map[string]string
+	if ns := policy.GetNamespace(); ns != "" {
+		extra = map[string]string{ciliumNamespaceLabel: ns}
+	}
+	return parseLabelSelector(normalizeCiliumSelector(sel, extra), present || policy.GetNamespace() != "")
+}
+
+// ciliumSelectorsOverlap reports whether two matchLabels sets can select the
+// same endpoint: no label is required with different values.
+func ciliumSelectorsOverlap(a, b map[string]string) bool {
+	for k, v := range a {
+		if w, ok := b[k]; ok && w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ciliumL7Kinds returns the L7 protocols of a toPorts entry's rules.
+func ciliumL7Kinds(toPort map[string]interface{}) []string {
+	rules, _, _ := unstructured.NestedMap(toPort, "rules")
+	var kinds []string
+	for k, v := range rules {
+		if list, ok := v.([]interface{}); ok && len(list) == 0 {
+			continue
+		}
+		if k == "l7proto" {
+			continue
+		}
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ciliumRuleCIDRs returns the CIDRs a rule allows or denies by address.
+func ciliumRuleCIDRs(rule map[string]interface{}, peer string) []string {
+	cidrs, _, _ := unstructured.NestedStringSlice(rule, peer+"CIDR")
+	sets, _, _ := unstructured.NestedSlice(rule, peer+"CIDRSet")
+	for _, s := range sets {
+		sm, _ := s.(map[string]interface{})
+		if cidr, _ := sm["cidr"].(string); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// ciliumPodCIDRs collects the pod CIDRs from cilium-config cluster-pool
+// settings, node podCIDRs and CiliumNode allocations.
+func ciliumPodCIDRs(config map[string]string, nodes, ciliumNodes []unstructured.Unstructured) []netip.Prefix {
+	seen := make(map[netip.Prefix]bool)
+	add := func(s string) {
+		if p, err := netip.ParsePrefix(strings.TrimSpace(s)); err == nil {
+			seen[p.Masked()] = true
+		}
+	}
+	for _, key := range []string{"cluster-pool-ipv4-cidr", "cluster-pool-ipv6-cidr"} {
+		for _, s := range strings.FieldsFunc(config[key], func(r rune) bool { return r == ',' || r == ' ' }) {
+			add(s)
+		}
+	}
+	for _, n := range nodes {
+		cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "podCIDRs")
+		if single, _, _ := unstructured.NestedString(n.Object, "spec", "podCIDR"); single != "" {
+			cidrs = append(cidrs, single)
+		}
+		for _, c := range cidrs {
+			add(c)
+		}
+	}
+	for _, n := range ciliumNodes {
+		cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "ipam", "podCIDRs")
+		for _, c := range cidrs {
+			add(c)
+		}
+	}
+	out := make([]netip.Prefix, 0, len(seen))
+	for p := range seen {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
+
+// ciliumPolicyEnv is the cluster state Cilium policies are validated against.
+type ciliumPolicyEnv struct {
+	// config is the cilium-config ConfigMap data, nil when it was not found.
+	config    map[string]string
+	podCIDRs  []netip.Prefix
+	policies  []unstructured.Unstructured // every CNP and CCNP
+	pods      []unstructured.Unstructured
+	endpoints map[string]*unstructured.Unstructured // CiliumEndpoints by namespace/name
+}
+
+// l7Proxy reports whether the agents run the L7 proxy, which also serves
+// DNS for toFQDNs rules. It is on unless enable-l7-proxy is false.
+func (e *ciliumPolicyEnv) l7Proxy() bool {
+	return e.config == nil || e.config["enable-l7-proxy"] != "false"
+}
+
+func ciliumPolicyKind(policy *unstructured.Unstructured) string {
+	if policy.GetNamespace() == "" {
+		return "CiliumClusterwideNetworkPolicy"
+	}
+	return "CiliumNetworkPolicy"
+}
+
+func ciliumPolicyLabel(policy *unstructured.Unstructured) string {
+	return ciliumPolicyKind(policy) + " " + qualifiedName(policy.GetNamespace(), policy.GetName())
+}
+
+// selectedPods returns the pods a policy spec's endpoint selector selects.
+func (e *ciliumPolicyEnv) selectedPods(policy *unstructured.Unstructured, spec map[string]interface{}) []*unstructured.Unstructured {
+	sel, err := ciliumEndpointSelector(policy, spec)
+	if err != nil {
+		return nil
+	}
+	var out []*unstructured.Unstructured
+	for i := range e.pods {
+		pod := &e.pods[i]
+		if sel.Matches(ciliumPodLabels(pod)) {
+			out = append(out, pod)
+		}
+	}
+	return out
+}
+
+// otherRules returns the policies with rules in section, other than the
+// given spec, whose endpoint selector overlaps selector.
+func (e *ciliumPolicyEnv) otherRules(policy *unstructured.Unstructured, path, section string, selector map[string]string) []string {
+	seen := make(map[string]bool)
+	for i := range e.policies {
+		other := &e.policies[i]
+		for _, s := range ciliumPolicySpecs(other.Object) {
+			if other.GetNamespace() == policy.GetNamespace() && other.GetName() == policy.GetName() && s.path == path {
+				continue
+			}
+			rules, _, _ := unstructured.NestedSlice(s.spec, section)
+			if len(rules) == 0 {
+				continue
+			}
+			if ciliumSelectorsOverlap(selector, ciliumSelectorLabels(other, s.spec)) {
+				seen[ciliumPolicyLabel(other)] = true
+			}
+		}
+	}
+	return sortedSet(seen)
+}
+
+// hasDNSVisibility reports whether some policy gives the endpoints a spec
+// selects a DNS rule, which is how the DNS proxy learns the addresses of
+// toFQDNs names. The rule may be in any policy whose selector is at most as
+// specific as the spec's.
+func (e *ciliumPolicyEnv) hasDNSVisibility(selector map[string]string) bool {
+	for i := range e.policies {
+		other := &e.policies[i]
+		for _, s := range ciliumPolicySpecs(other.Object) {
+			if !labelsMatch(ciliumSelectorLabels(other, s.spec), selector) {
+				continue
+			}
+			egress, _, _ := unstructured.NestedSlice(s.spec, "egress")
+			for _, r := range egress {
+				rm, _ := r.(map[string]interface{})
+				toPorts, _, _ := unstructured.NestedSlice(rm, "toPorts")
+				for _, tp := range toPorts {
+					tpm, _ := tp.(map[string]interface{})
+					if containsString(ciliumL7Kinds(tpm), "dns") {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// validate checks one policy and returns its findings.
+func (e *ciliumPolicyEnv) validate(policy *unstructured.Unstructured) []types.DiagnosticFinding {
+	label := ciliumPolicyLabel(policy)
+	ref := &types.ResourceRef{Kind: ciliumPolicyKind(policy), Namespace: policy.GetNamespace(), Name: policy.GetName(), APIVersion: "cilium.io/v2"}
+	var findings []types.DiagnosticFinding
+	add := func(severity string, code types.FindingCode, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryPolicy,
+			Code:       code,
+			Resource:   ref,
+			Summary:    summary,
+			Detail:     detail,
+			Suggestion: suggestion,
+		})
+	}
+
+	for _, ps := range ciliumPolicySpecs(policy.Object) {
+		selector := ciliumSelectorLabels(policy, ps.spec)
+		prefix := ""
+		if ps.path != "spec" {
+			prefix = ps.path + "."
+		}
+		var fqdnRules, l7Rules []string
+		l7Directions := make(map[string]bool)
+
+		for _, section := range ciliumRuleSections {
+			rules, _, _ := unstructured.NestedSlice(ps.spec, section.name)
+			for ri, r := range rules {
+				rm, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				at := fmt.Sprintf("%s%s[%d]", prefix, section.name, ri)
+
+				// CIDR rules never select cluster endpoints
+				for _, cidr := range ciliumRuleCIDRs(rm, section.peer) {
+					p, err := netip.ParsePrefix(cidr)
+					if err != nil {
+						continue
+					}
+					for _, pod := range e.podCIDRs {
+						if p.Addr().BitLen() == pod.Addr().BitLen() && p.Bits() >= pod.Bits() && pod.Contains(p.Addr()) {
+							add(types.SeverityWarning, types.CodeCNICIDRInPodCIDR,
+								fmt.Sprintf("%s %s CIDR %s is inside the pod CIDR %s and matches no pod", label, at, cidr, pod),
+								"Cilium identifies pods by security identity, not address: CIDR rules only match traffic outside the cluster",
+								fmt.Sprintf("Select the pods with %sEndpoints instead", section.peer))
+							break
+						}
+					}
+				}
+
+				if section.name == "egress" {
+					if fqdns, _, _ := unstructured.NestedSlice(rm, "toFQDNs"); len(fqdns) > 0 {
+						fqdnRules = append(fqdnRules, at)
+					}
+				}
+
+				toPorts, _, _ := unstructured.NestedSlice(rm, "toPorts")
+				for pi, tp := range toPorts {
+					tpm, _ := tp.(map[string]interface{})
+					kinds := ciliumL7Kinds(tpm)
+					if len(kinds) == 0 {
+						continue
+					}
+					l7Rules = append(l7Rules, fmt.Sprintf("%s.toPorts[%d] (%s)", at, pi, strings.Join(kinds, ", ")))
+					if !section.deny {
+						l7Directions[section.name] = true
+					}
+					if ports, _ := tpm["ports"].([]interface{}); len(ports) == 0 {
+						add(types.SeverityCritical, types.CodeCNIL7WithoutProxy,
+							fmt.Sprintf("%s %s.toPorts[%d] has %s rules but no ports", label, at, pi, strings.Join(kinds, ", ")),
+							"Cilium redirects traffic to the L7 proxy per port; without ports there is nothing to redirect and the policy is rejected",
+							"List the ports the L7 rules apply to")
+					}
+				}
+			}
+
+			// Deny rules win over every allow rule, whatever their order or
+			// specificity
+			if section.deny && len(rules) > 0 {
+				allows := e.otherRules(policy, ps.path, section.allow, selector)
+				if own, _, _ := unstructured.NestedSlice(ps.spec, section.allow); len(own) > 0 {
+					allows = append([]string{"its own " + section.allow + " rules"}, allows...)
+				}
+				if len(allows) > 0 {
+					add(types.SeverityWarning, types.CodeCNIDenyOverridesAllow,
+						fmt.Sprintf("%s %s%s rules take precedence over the %s rules of %s", label, prefix, section.name, section.allow, truncateList(allows, 5)),
+						"Traffic matched by a deny rule is dropped even when a more specific allow rule matches it; only endpoints both policies select are affected",
+						"Narrow the deny rule's peers or ports if the allow rules are meant as exceptions")
+				}
+			}
+		}
+
+		if len(fqdnRules) > 0 {
+			switch {
+			case !e.l7Proxy():
+				add(types.SeverityCritical, types.CodeCNIFQDNWithoutDNSProxy,
+					fmt.Sprintf("%s has toFQDNs rules but the DNS proxy is disabled (enable-l7-proxy=false)", label),
+					"Rules: "+strings.Join(fqdnRules, ", "),
+					"Set enable-l7-proxy to true in cilium-config; toFQDNs rules learn addresses from the DNS proxy")
+			case !e.hasDNSVisibility(selector):
+				add(types.SeverityWarning, types.CodeCNIFQDNWithoutDNSProxy,
+					fmt.Sprintf("%s has toFQDNs rules but no policy sends the selected endpoints' DNS through the proxy", label),
+					"Rules: "+strings.Join(fqdnRules, ", ")+". Without an egress rule with toPorts.rules.dns, the DNS proxy never sees the lookups and the names resolve to addresses the policy does not allow",
+					"Add an egress rule to kube-dns on port 53 with rules.dns matchPattern \"*\"")
+			}
+		}
+
+		if len(l7Rules) > 0 && !e.l7Proxy() {
+			add(types.SeverityCritical, types.CodeCNIL7WithoutProxy,
+				fmt.Sprintf("%s has L7 rules but the L7 proxy is disabled (enable-l7-proxy=false)", label),
+				"Rules: "+strings.Join(l7Rules, ", "),
+				"Set enable-l7-proxy to true in cilium-config, or remove the L7 rules")
+		}
+		if len(l7Directions) > 0 && len(e.pods) > 0 {
+			findings = append(findings, e.l7EndpointFindings(policy, ps.spec, ref, label, sortedSet(l7Directions))...)
+		}
+	}
+
+	findings = append(findings, ciliumPolicyStatusFindings(policy, ref, label)...)
+	return findings
+}
+
+// l7EndpointFindings reports selected endpoints that do not enforce the
+// policy direction the L7 rules are in: their traffic is not redirected to
+// the proxy.
+func (e *ciliumPolicyEnv) l7EndpointFindings(policy *unstructured.Unstructured, spec map[string]interface{}, ref *types.ResourceRef, label string, directions []string) []types.DiagnosticFinding {
+	pods := e.selectedPods(policy, spec)
+	if len(pods) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s has L7 rules but selects no pod", label),
+			Detail:   "The L7 rules take effect when matching pods start",
+		}}
+	}
+	if len(e.endpoints) == 0 {
+		return nil
+	}
+	var notEnforcing []string
+	for _, pod := range pods {
+		ep := e.endpoints[qualifiedName(pod.GetNamespace(), pod.GetName())]
+		if ep == nil {
+			continue
+		}
+		for _, dir := range directions {
+			if enforcing, found, _ := unstructured.NestedBool(ep.Object, "status", "policy", dir, "enforcing"); found && !enforcing {
+				notEnforcing = append(notEnforcing, fmt.Sprintf("%s (%s)", qualifiedName(pod.GetNamespace(), pod.GetName()), dir))
+			}
+		}
+	}
+	if len(notEnforcing) == 0 {
+		return nil
+	}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryPolicy,
+		Code:       types.CodeCNIEndpointNotEnforcing,
+		Resource:   ref,
+		Summary:    fmt.Sprintf("%s has L7 rules but %d selected endpoints do not enforce policy", label, len(notEnforcing)),
+		Detail:     truncateList(notEnforcing, 10),
+		Suggestion: "The endpoints' traffic is not redirected to the L7 proxy; check cilium-dbg endpoint list on their nodes and the agent logs for policy regeneration errors",
+	}}
+}
+
+// ciliumPolicyStatusFindings reports a policy the agents rejected, from its
+// Valid condition or per-node errors.
+func ciliumPolicyStatusFindings(policy *unstructured.Unstructured, ref *types.ResourceRef, label string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	conditions, _, _ := unstructured.NestedSlice(policy.Object, "status", "conditions")
+	for _, c := range conditions {
+		cm, _ := c.(map[string]interface{})
+		if cm["type"] == "Valid" && cm["status"] == "False" {
+			msg, _ := cm["message"].(string)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeCNIPolicyInvalid,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s is not valid", label),
+				Detail:     msg,
+				Suggestion: "Cilium does not enforce an invalid policy; fix the rule the message names",
+			})
+		}
+	}
+	nodes, _, _ := unstructured.NestedMap(policy.Object, "status", "nodes")
+	var failed []string
+	for node, s := range nodes {
+		sm, _ := s.(map[string]interface{})
+		if msg, _ := sm["error"].(string); msg != "" {
+			failed = append(failed, node+": "+msg)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeCNIPolicyInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s failed to import on %d nodes", label, len(failed)),
+			Detail:     truncateList(failed, 5),
+			Suggestion: "Check the cilium agent logs on those nodes",
+		})
+	}
+	return findings
+}
+
+// --- validate_cilium_policy ---
+
+type ValidateCiliumPolicyTool struct{ BaseTool }
+
+func (t *ValidateCiliumPolicyTool) Name() string { return "validate_cilium_policy" }
+func (t *ValidateCiliumPolicyTool) Description() string {
+	return "Validate CiliumNetworkPolicies and CiliumClusterwideNetworkPolicies: CIDR rules inside the pod CIDR that match no pod, toFQDNs rules without the DNS proxy or a DNS visibility rule, L7 rules without the L7 proxy or on endpoints that do not enforce policy, deny rules overriding allow rules, and policies the agents rejected"
+}
+func (t *ValidateCiliumPolicyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces and the cluster-wide policies)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Only validate the policy with this name",
+			},
+		},
+	}
+}
+
+func (t *ValidateCiliumPolicyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+
+	cnps, cnpErr := t.listResource(ctx, ciliumNPGVR, "")
+	ccnps, ccnpErr := t.listResource(ctx, ciliumCNPGVR, "")
+	if cnpErr != nil && ccnpErr != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list CiliumNetworkPolicy and CiliumClusterwideNetworkPolicy",
+			Detail:  cnpErr.Error(),
+		}
+	}
+	cnps, ccnps = orEmpty(cnps, cnpErr), orEmpty(ccnps, ccnpErr)
+
+	env := &ciliumPolicyEnv{endpoints: make(map[string]*unstructured.Unstructured)}
+	env.policies = append(append(env.policies, cnps.Items...), ccnps.Items...)
+	var findings []types.DiagnosticFinding
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace("kube-system").Get(ctx, "cilium-config", metav1.GetOptions{}); err == nil {
+		env.config, _, _ = unstructured.NestedStringMap(cm.Object, "data")
+		if env.config == nil {
+			env.config = map[string]string{}
+		}
+	} else {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  "ConfigMap kube-system/cilium-config not found; the L7 and DNS proxy are assumed enabled",
+		})
+	}
+	var nodes, ciliumNodes []unstructured.Unstructured
+	if list, err := t.listUnrecorded(ctx, nodesGVR, ""); err == nil {
+		nodes = list.Items
+	}
+	if list, err := t.listUnrecorded(ctx, ciliumNodesGVR, ""); err == nil {
+		ciliumNodes = list.Items
+	}
+	env.podCIDRs = ciliumPodCIDRs(env.config, nodes, ciliumNodes)
+
+	// Cluster-wide policies select pods in every namespace
+	podNs := ns
+	if ns == "" || len(ccnps.Items) > 0 {
+		podNs = ""
+	}
+	if list, err := t.listUnrecorded(ctx, podsGVR, podNs); err == nil {
+		env.pods = list.Items
+	}
+	if list, err := t.listUnrecorded(ctx, ciliumEPGVR, podNs); err == nil {
+		for i := range list.Items {
+			ep := &list.Items[i]
+			env.endpoints[qualifiedName(ep.GetNamespace(), ep.GetName())] = ep
+		}
+	}
+
+	validated := 0
+	var problems []types.DiagnosticFinding
+	for i := range env.policies {
+		p := &env.policies[i]
+		if ns != "" && p.GetNamespace() != ns && p.GetNamespace() != "" {
+			continue
+		}
+		if name != "" && p.GetName() != name {
+			continue
+		}
+		validated++
+		problems = append(problems, env.validate(p)...)
+	}
+
+	if name != "" && validated == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("no Cilium policy named %s in %s", name, orDefault(ns, "the cluster")),
+		}
+	}
+	if len(problems) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("All %d Cilium policies passed validation", validated),
+			Detail:   fmt.Sprintf("pod CIDRs: %s", orDefault(joinPrefixes(env.podCIDRs), "unknown")),
+		})
+	}
+	findings = append(findings, problems...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "cilium"), nil
+}
+
+func joinPrefixes(prefixes []netip.Prefix) string {
+	parts := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, ", ")
+}
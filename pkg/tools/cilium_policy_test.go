@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func ciliumPolicy(ns, name string, spec map[string]interface{}) unstructured.Unstructured {
+	kind := "CiliumNetworkPolicy"
+	if ns == "" {
+		kind = "CiliumClusterwideNetworkPolicy"
+	}
+	return *ipamObj("cilium.io/v2", kind, ns, name, map[string]interface{}{"spec": spec})
+}
+
+func ciliumSelector(lbls map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"matchLabels": lbls}
+}
+
+func ciliumCodes(findings []types.DiagnosticFinding) string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, string(f.Severity)+" "+f.Summary+" "+string(f.Code))
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestCiliumPodCIDRs(t *testing.T) {
+	nodes := []unstructured.Unstructured{*ipamObj("v1", "Node", "", "a", map[string]interface{}{
+		"spec": map[string]interface{}{"podCIDR": "10.244.1.0/24", "podCIDRs": []interface{}{"10.244.1.0/24"}},
+	})}
+	ciliumNodes := []unstructured.Unstructured{*ipamObj("cilium.io/v2", "CiliumNode", "", "a", map[string]interface{}{
+		"spec": map[string]interface{}{"ipam": map[string]interface{}{"podCIDRs": []interface{}{"10.0.3.0/24"}}},
+	})}
+	got := ciliumPodCIDRs(map[string]string{"cluster-pool-ipv4-cidr": "10.0.0.0/8 fd00::/104"}, nodes, ciliumNodes)
+	want := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("10.0.3.0/24"),
+		netip.MustParsePrefix("10.244.1.0/24"), netip.MustParsePrefix("fd00::/104"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pod CIDRs = %v, want %v", got, want)
+	}
+}
+
+func TestValidateCiliumPolicy(t *testing.T) {
+	web := ciliumSelector(map[string]interface{}{"app": "web"})
+	policy := ciliumPolicy("shop", "web", map[string]interface{}{
+		"endpointSelector": web,
+		"egress": []interface{}{
+			map[string]interface{}{"toCIDR": []interface{}{"10.244.3.0/24", "192.168.0.0/16"}},
+			map[string]interface{}{"toFQDNs": []interface{}{map[string]interface{}{"matchName": "api.stripe.com"}}},
+		},
+		"ingress": []interface{}{map[string]interface{}{
+			"fromEndpoints": []interface{}{ciliumSelector(map[string]interface{}{"k8s:app": "gateway"})},
+			"toPorts": []interface{}{map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"port": "8080", "protocol": "TCP"}},
+				"rules": map[string]interface{}{"http": []interface{}{map[string]interface{}{"method": "GET"}}},
+			}},
+		}},
+	})
+	deny := ciliumPolicy("", "deny-metadata", map[string]interface{}{
+		"endpointSelector": ciliumSelector(map[string]interface{}{}),
+		"egressDeny":       []interface{}{map[string]interface{}{"toCIDR": []interface{}{"169.254.169.254/32"}}},
+	})
+	pod := tenantPod("shop", "web-1", "10.244.3.4", map[string]string{"app": "web"}, 8080)
+	endpoint := ipamObj("cilium.io/v2", "CiliumEndpoint", "shop", "web-1", map[string]interface{}{
+		"status": map[string]interface{}{"policy": map[string]interface{}{"ingress": map[string]interface{}{"enforcing": false}}},
+	})
+	env := &ciliumPolicyEnv{
+		config:    map[string]string{},
+		podCIDRs:  []netip.Prefix{netip.MustParsePrefix("10.244.0.0/16")},
+		policies:  []unstructured.Unstructured{policy, deny},
+		pods:      []unstructured.Unstructured{*pod},
+		endpoints: map[string]*unstructured.Unstructured{"shop/web-1": endpoint},
+	}
+
+	got := ciliumCodes(env.validate(&policy))
+	for _, want := range []string{
+		"warning CiliumNetworkPolicy shop/web egress[0] CIDR 10.244.3.0/24 is inside the pod CIDR 10.244.0.0/16 and matches no pod CNI011_CIDR_IN_POD_CIDR",
+		"warning CiliumNetworkPolicy shop/web has toFQDNs rules but no policy sends the selected endpoints' DNS through the proxy CNI012_FQDN_WITHOUT_DNS_PROXY",
+		"warning CiliumNetworkPolicy shop/web has L7 rules but 1 selected endpoints do not enforce policy CNI015_ENDPOINT_NOT_ENFORCING",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "192.168.0.0/16") {
+		t.Errorf("CIDR outside the pod CIDR reported:\n%s", got)
+	}
+
+	got = ciliumCodes(env.validate(&deny))
+	if want := "warning CiliumClusterwideNetworkPolicy deny-metadata egressDeny rules take precedence over the egress rules of CiliumNetworkPolicy shop/web CNI014_DENY_OVERRIDES_ALLOW"; got != want {
+		t.Errorf("deny findings:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A DNS visibility rule for every endpoint of the namespace lets the proxy
+	// learn the names; with the L7 proxy disabled neither rule works.
+	env.policies = append(env.policies, ciliumPolicy("shop", "dns", map[string]interface{}{
+		"endpointSelector": ciliumSelector(map[string]interface{}{}),
+		"egress": []interface{}{map[string]interface{}{"toPorts": []interface{}{map[string]interface{}{
+			"ports": []interface{}{map[string]interface{}{"port": "53", "protocol": "ANY"}},
+			"rules": map[string]interface{}{"dns": []interface{}{map[string]interface{}{"matchPattern": "*"}}},
+		}}}},
+	}))
+	if got := ciliumCodes(env.validate(&policy)); strings.Contains(got, "CNI012") {
+		t.Errorf("DNS visibility rule ignored:\n%s", got)
+	}
+	env.config["enable-l7-proxy"] = "false"
+	got = ciliumCodes(env.validate(&policy))
+	for _, want := range []string{
+		"critical CiliumNetworkPolicy shop/web has toFQDNs rules but the DNS proxy is disabled (enable-l7-proxy=false) CNI012_FQDN_WITHOUT_DNS_PROXY",
+		"critical CiliumNetworkPolicy shop/web has L7 rules but the L7 proxy is disabled (enable-l7-proxy=false) CNI013_L7_WITHOUT_PROXY",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCiliumPolicyStatusFindings(t *testing.T) {
+	policy := ciliumPolicy("shop", "web", nil)
+	policy.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Valid", "status": "False", "message": "L7 rules need ports"}},
+		"nodes":      map[string]interface{}{"node-a": map[string]interface{}{"error": "import failed"}, "node-b": map[string]interface{}{"enforcing": true}},
+	}
+	got := ciliumCodes(ciliumPolicyStatusFindings(&policy, nil, "CiliumNetworkPolicy shop/web"))
+	want := "critical CiliumNetworkPolicy shop/web is not valid CNI016_POLICY_INVALID\n" +
+		"critical CiliumNetworkPolicy shop/web failed to import on 1 nodes CNI016_POLICY_INVALID"
+	if got != want {
+		t.Errorf("findings:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestValidateManifestsCilium(t *testing.T) {
+	manifests := `
+apiVersion: cilium.io/v2
+kind: CiliumNetworkPolicy
+metadata:
+  name: web
+spec:
+  endpointSelector:
+    matchLabels:
+      app: web
+  ingress:
+  - toPorts:
+    - rules:
+        http:
+        - method: GET
+`
+	tool := &ValidateManifestsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}}}
+	findings := runValidateManifests(t, tool, map[string]interface{}{"manifests": manifests, "namespace": "shop"})
+	if got := ciliumCodes(findings); !strings.Contains(got, "critical CiliumNetworkPolicy shop/web ingress[0].toPorts[0] has http rules but no ports CNI013_L7_WITHOUT_PROXY") {
+		t.Errorf("findings:\n%s", got)
+	}
+}
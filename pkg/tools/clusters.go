@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// --- list_clusters ---
+
+// ListClustersTool reports every configured cluster, its reachability and the
+// providers detected on it.
+type ListClustersTool struct {
+	BaseTool
+	Clusters *k8s.ClusterRegistry
+	// Providers returns the providers currently detected on a cluster.
+	Providers func(cluster string) []string
+}
+
+func (t *ListClustersTool) Name() string { return "list_clusters" }
+func (t *ListClustersTool) Description() string {
+	return "List the clusters this server can diagnose with their API server, Kubernetes version and detected providers; pass a cluster name as the 'cluster' argument of any tool to target it"
+}
+func (t *ListClustersTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *ListClustersTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	defaultName := t.Clusters.Default().Name
+	findings := make([]types.DiagnosticFinding, 0, len(t.Clusters.Names()))
+
+	for _, c := range t.Clusters.List() {
+		ref := &types.ResourceRef{Kind: "Cluster", Name: c.Name}
+		label := c.Name
+		if c.Name == defaultName {
+			label += " (default)"
+		}
+		source := "in-cluster/default kubeconfig"
+		if c.Context != "" {
+			source = "context " + c.Context
+		}
+
+		version, err := c.Clients.Discovery.ServerVersion()
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeClusterUnreachable,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Cluster %s is unreachable", label),
+				Detail:     fmt.Sprintf("server=%s %s: %v", c.Clients.Host, source, err),
+				Suggestion: "Check the kubeconfig context credentials and network access to the API server.",
+			})
+			continue
+		}
+
+		providers := "none"
+		if t.Providers != nil {
+			if p := t.Providers(c.Name); len(p) > 0 {
+				providers = strings.Join(p, ",")
+			}
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Resource: ref,
+			Summary:  fmt.Sprintf("Cluster %s reachable, Kubernetes %s", label, version.GitVersion),
+			Detail:   fmt.Sprintf("server=%s %s providers=%s", c.Clients.Host, source, providers),
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
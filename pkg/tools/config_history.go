@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// historyCategory maps a recorded kind to a finding category.
+func historyCategory(kind string) string {
+	switch {
+	case kind == "ConfigMap":
+		return types.CategoryDNS
+	case kind == "PeerAuthentication", kind == "ClientTrafficPolicy":
+		return types.CategoryTLS
+	case strings.Contains(kind, "NetworkPolicy"), kind == "AuthorizationPolicy", kind == "SecurityPolicy":
+		return types.CategoryPolicy
+	case kind == "VirtualService", kind == "DestinationRule", kind == "ServiceEntry", kind == "Sidecar", kind == "EnvoyFilter", kind == "IstioGateway":
+		return types.CategoryMesh
+	}
+	return types.CategoryRouting
+}
+
+// changeFinding describes one change between two snapshots.
+func changeFinding(c history.Change, from, to time.Time) types.DiagnosticFinding {
+	o := c.Object()
+	ref := &types.ResourceRef{Kind: o.Kind, Namespace: o.Namespace, Name: o.Name}
+	name := o.Name
+	if o.Namespace != "" {
+		name = o.Namespace + "/" + o.Name
+	}
+	detail := fmt.Sprintf("changed between %s and %s", formatHistoryTime(from), to.Format(time.RFC3339))
+	if c.Type == history.Modified {
+		root := "spec"
+		if o.Kind == "ConfigMap" {
+			root = "data"
+		}
+		if paths := diffPaths(c.Old.Content, c.New.Content, root); len(paths) > 0 {
+			detail += "; fields: " + truncateList(paths, 10)
+		}
+	}
+	sev := types.SeverityInfo
+	if c.Type == history.Removed {
+		sev = types.SeverityWarning
+	}
+	return types.DiagnosticFinding{
+		Severity: sev,
+		Category: historyCategory(o.Kind),
+		Resource: ref,
+		Summary:  fmt.Sprintf("%s %s %s", o.Kind, name, c.Type),
+		Detail:   detail,
+	}
+}
+
+func formatHistoryTime(t time.Time) string {
+	if t.IsZero() {
+		return "the start of the history"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// filterChanges keeps the changes to kind (any when empty) in namespace (any
+// when empty; cluster-scoped objects are then excluded).
+func filterChanges(changes []history.Change, kind, namespace string) []history.Change {
+	var out []history.Change
+	for _, c := range changes {
+		o := c.Object()
+		if kind != "" && !strings.EqualFold(o.Kind, kind) {
+			continue
+		}
+		if namespace != "" && o.Namespace != namespace {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// parseHistoryTime reads an RFC3339 time or a duration before now ("90m").
+func parseHistoryTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// resolveSnapshot finds the snapshot named by ref: a snapshot ID, or the
+// configuration in effect at an RFC3339 time or a duration before now.
+func resolveSnapshot(store *history.Store, ref string, now time.Time) (*history.Snapshot, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		if snap, ok := store.Get(id); ok {
+			return snap, nil
+		}
+		return nil, fmt.Errorf("snapshot %d is not in the history", id)
+	}
+	t, err := parseHistoryTime(ref, now)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a snapshot ID, RFC3339 time or duration", ref)
+	}
+	snap := store.At(t)
+	if snap == nil {
+		return nil, fmt.Errorf("the history starts after %s", t.Format(time.RFC3339))
+	}
+	return snap, nil
+}
+
+var historyFilterProperties = map[string]interface{}{
+	"kind": map[string]interface{}{
+		"type":        "string",
+		"description": "Only changes to this kind, e.g. HTTPRoute or NetworkPolicy",
+	},
+	"namespace": map[string]interface{}{
+		"type":        "string",
+		"description": "Only changes in this namespace (empty for all, including cluster-scoped resources)",
+	},
+}
+
+// --- get_config_timeline ---
+
+type GetConfigTimelineTool struct {
+	BaseTool
+	History *history.Store
+}
+
+func (t *GetConfigTimelineTool) Name() string { return "get_config_timeline" }
+func (t *GetConfigTimelineTool) Description() string {
+	return "List networking configuration changes (Services, NetworkPolicies, Ingresses, Gateway API, Istio, Envoy Gateway, Cilium and Calico resources, CoreDNS) recorded by periodic snapshots in a time window, e.g. the hour before an outage"
+}
+func (t *GetConfigTimelineTool) InputSchema() map[string]interface{} {
+	props := map[string]interface{}{
+		"since": map[string]interface{}{
+			"type":        "string",
+			"description": "Window start as a duration before now (e.g. 1h) or RFC3339 time (default 1h)",
+		},
+		"until": map[string]interface{}{
+			"type":        "string",
+			"description": "Window end as a duration before now or RFC3339 time (default now)",
+		},
+	}
+	for k, v := range historyFilterProperties {
+		props[k] = v
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (t *GetConfigTimelineTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	now := time.Now()
+	since, err := parseHistoryTime(getStringArg(args, "since", "1h"), now)
+	if err == nil {
+		var until time.Time
+		until, err = parseHistoryTime(getStringArg(args, "until", "0s"), now)
+		now = until
+	}
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid time window: %v", err),
+			Detail:  "use a duration before now (e.g. 90m) or an RFC3339 time",
+		}
+	}
+	kind := getStringArg(args, "kind", "")
+	ns := getStringArg(args, "namespace", "")
+
+	snapshots := t.History.Snapshots()
+	var findings []types.DiagnosticFinding
+	changed := 0
+	for i := 1; i < len(snapshots); i++ {
+		prev, snap := snapshots[i-1], snapshots[i]
+		if !snap.Time.After(since) || snap.Time.After(now) {
+			continue
+		}
+		changes := filterChanges(history.Diff(prev, snap), kind, ns)
+		if len(changes) > 0 {
+			changed++
+		}
+		for _, c := range changes {
+			findings = append(findings, changeFinding(c, snap.Since, snap.Time))
+		}
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: historyCategory(kind),
+		Summary:  fmt.Sprintf("%d change(s) in %d snapshot(s) between %s and %s", len(findings), changed, since.Format(time.RFC3339), now.Format(time.RFC3339)),
+		Detail:   fmt.Sprintf("%d snapshot(s) recorded; last capture %s", len(snapshots), formatHistoryTime(t.History.LastCheck())),
+	}
+	if len(snapshots) == 0 || snapshots[0].Time.After(since) {
+		summary.Severity = types.SeverityInfo
+		summary.Suggestion = "The history does not cover the whole window: it only starts when the server starts recording (CONFIG_HISTORY_INTERVAL) and keeps CONFIG_HISTORY_SIZE snapshots."
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), ""), nil
+}
+
+// --- diff_snapshots ---
+
+type DiffSnapshotsTool struct {
+	BaseTool
+	History *history.Store
+}
+
+func (t *DiffSnapshotsTool) Name() string { return "diff_snapshots" }
+func (t *DiffSnapshotsTool) Description() string {
+	return "Compare two recorded networking configuration snapshots, by ID or point in time, and list the resources added, removed or modified with their changed fields"
+}
+func (t *DiffSnapshotsTool) InputSchema() map[string]interface{} {
+	props := map[string]interface{}{
+		"from": map[string]interface{}{
+			"type":        "string",
+			"description": "Snapshot ID, RFC3339 time, or duration before now (e.g. 2h); times select the configuration in effect then",
+		},
+		"to": map[string]interface{}{
+			"type":        "string",
+			"description": "Snapshot ID, RFC3339 time or duration before now (default: latest snapshot)",
+		},
+	}
+	for k, v := range historyFilterProperties {
+		props[k] = v
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+		"required":   []string{"from"},
+	}
+}
+
+func (t *DiffSnapshotsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	now := time.Now()
+	invalid := func(err error) error {
+		return &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: err.Error(),
+			Detail:  "use get_config_timeline to see the recorded history",
+		}
+	}
+
+	fromRef := getStringArg(args, "from", "")
+	if fromRef == "" {
+		return nil, invalid(fmt.Errorf("from is required"))
+	}
+	from, err := resolveSnapshot(t.History, fromRef, now)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	to := t.History.Latest()
+	if toRef := getStringArg(args, "to", ""); toRef != "" {
+		if to, err = resolveSnapshot(t.History, toRef, now); err != nil {
+			return nil, invalid(err)
+		}
+	}
+	ns := getStringArg(args, "namespace", "")
+
+	changes := filterChanges(history.Diff(from, to), getStringArg(args, "kind", ""), ns)
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityOK,
+		Category: types.CategoryRouting,
+		Summary: fmt.Sprintf("%d change(s) between snapshot %d (%s) and snapshot %d (%s)",
+			len(changes), from.ID, from.Time.Format(time.RFC3339), to.ID, to.Time.Format(time.RFC3339)),
+	}}
+	for _, c := range changes {
+		findings = append(findings, changeFinding(c, from.Time, to.Time))
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), ""), nil
+}
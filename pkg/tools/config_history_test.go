@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func historyStore(t *testing.T, now time.Time) *history.Store {
+	t.Helper()
+	store, err := history.NewStore(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(at time.Time, objects ...*history.Object) {
+		snap := &history.Snapshot{Time: at, Objects: make(map[string]*history.Object)}
+		for _, o := range objects {
+			snap.Objects[o.Key()] = o
+		}
+		if _, err := store.Add(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	route := func(host string) *history.Object {
+		return history.NewObject("HTTPRoute", "shop", "web", map[string]interface{}{"hostnames": []interface{}{host}})
+	}
+	deny := history.NewObject("NetworkPolicy", "shop", "deny-all", map[string]interface{}{})
+	add(now.Add(-3*time.Hour), route("a.example.com"), deny)
+	add(now.Add(-30*time.Minute), route("b.example.com"), deny)
+	add(now.Add(-10*time.Minute), route("b.example.com"))
+	return store
+}
+
+func TestGetConfigTimeline(t *testing.T) {
+	store := historyStore(t, time.Now())
+	tool := &GetConfigTimelineTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}}, History: store}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"since": "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 3 {
+		t.Fatalf("expected a summary and 2 changes, got %+v", findings)
+	}
+	if !strings.Contains(findings[1].Summary, "HTTPRoute shop/web modified") || !strings.Contains(findings[1].Detail, "spec.hostnames") {
+		t.Errorf("unexpected route change: %+v", findings[1])
+	}
+	if !strings.Contains(findings[2].Summary, "NetworkPolicy shop/deny-all removed") || findings[2].Severity != types.SeverityWarning {
+		t.Errorf("unexpected policy change: %+v", findings[2])
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"since": "1h", "kind": "networkpolicy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(resp.Data.(*types.ToolResult).Findings); n != 2 {
+		t.Errorf("expected the kind filter to keep 1 change, got %d findings", n-1)
+	}
+}
+
+func TestResolveSnapshot(t *testing.T) {
+	now := time.Now()
+	store := historyStore(t, now)
+
+	for ref, want := range map[string]int{
+		"1":                      1,
+		"2h":                     1,
+		"20m":                    2,
+		now.Format(time.RFC3339): 3,
+	} {
+		snap, err := resolveSnapshot(store, ref, now)
+		if err != nil {
+			t.Errorf("resolveSnapshot(%q): %v", ref, err)
+			continue
+		}
+		if snap.ID != want {
+			t.Errorf("resolveSnapshot(%q) = %d, want %d", ref, snap.ID, want)
+		}
+	}
+	for _, ref := range []string{"7", "5h", "yesterday"} {
+		if _, err := resolveSnapshot(store, ref, now); err == nil {
+			t.Errorf("resolveSnapshot(%q): expected an error", ref)
+		}
+	}
+}
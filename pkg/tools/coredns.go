@@ -0,0 +1,645 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// corefileDirective is one plugin line of a Corefile, with its options block.
+type corefileDirective struct {
+	Name string
+	Args []string
+	Sub  []corefileDirective
+	Line int
+}
+
+// corefileServer is one server block: the zones it serves and its plugins.
+type corefileServer struct {
+	Keys       []string
+	Directives []corefileDirective
+	Line       int
+}
+
+// corefileZone is a normalized server block key.
+type corefileZone struct {
+	Zone string // fully qualified, e.g. "cluster.local."
+	Port string
+}
+
+func (s corefileServer) zones() []corefileZone {
+	out := make([]corefileZone, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		out = append(out, parseCorefileKey(k))
+	}
+	return out
+}
+
+func (s corefileServer) directive(name string) *corefileDirective {
+	for i := range s.Directives {
+		if s.Directives[i].Name == name {
+			return &s.Directives[i]
+		}
+	}
+	return nil
+}
+
+// isRoot reports whether the block serves the root zone, i.e. all names.
+func (s corefileServer) isRoot() bool {
+	for _, z := range s.zones() {
+		if z.Zone == "." {
+			return true
+		}
+	}
+	return false
+}
+
+func (s corefileServer) label() string {
+	return strings.Join(s.Keys, " ")
+}
+
+// parseCorefileKey splits a server block key such as "dns://cluster.local:53".
+func parseCorefileKey(key string) corefileZone {
+	if i := strings.Index(key, "://"); i >= 0 {
+		key = key[i+3:]
+	}
+	port := "53"
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		key, port = key[:i], key[i+1:]
+	}
+	return corefileZone{Zone: fqdn(key), Port: port}
+}
+
+func fqdn(zone string) string {
+	if zone == "" || zone == "." {
+		return "."
+	}
+	return strings.TrimSuffix(strings.ToLower(zone), ".") + "."
+}
+
+type corefileToken struct {
+	text    string
+	line    int
+	lineEnd bool // last token on its line
+}
+
+func tokenizeCorefile(text string) []corefileToken {
+	var toks []corefileToken
+	for n, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.NewReplacer("{", " { ", "}", " } ").Replace(line)
+		fields := strings.Fields(line)
+		for i, f := range fields {
+			toks = append(toks, corefileToken{text: f, line: n + 1, lineEnd: i == len(fields)-1})
+		}
+	}
+	return toks
+}
+
+// parseCorefile parses the server blocks of a Corefile. Snippets, imports and
+// environment placeholders are kept as plain directives.
+func parseCorefile(text string) ([]corefileServer, error) {
+	toks := tokenizeCorefile(text)
+	var servers []corefileServer
+	for i := 0; i < len(toks); {
+		srv := corefileServer{Line: toks[i].line}
+		for i < len(toks) && toks[i].text != "{" {
+			if toks[i].text == "}" {
+				return nil, fmt.Errorf("line %d: unexpected '}'", toks[i].line)
+			}
+			srv.Keys = append(srv.Keys, toks[i].text)
+			i++
+		}
+		if i >= len(toks) {
+			return nil, fmt.Errorf("line %d: server block %q has no '{'", srv.Line, strings.Join(srv.Keys, " "))
+		}
+		if len(srv.Keys) == 0 {
+			return nil, fmt.Errorf("line %d: server block without a zone", toks[i].line)
+		}
+		dirs, next, err := parseCorefileBlock(toks, i+1)
+		if err != nil {
+			return nil, err
+		}
+		srv.Directives, i = dirs, next
+		servers = append(servers, srv)
+	}
+	return servers, nil
+}
+
+// parseCorefileBlock parses directives from toks[i] up to the matching '}' and
+// returns the index after it.
+func parseCorefileBlock(toks []corefileToken, i int) ([]corefileDirective, int, error) {
+	var dirs []corefileDirective
+	for i < len(toks) {
+		tok := toks[i]
+		if tok.text == "}" {
+			return dirs, i + 1, nil
+		}
+		if tok.text == "{" {
+			return nil, 0, fmt.Errorf("line %d: unexpected '{'", tok.line)
+		}
+		d := corefileDirective{Name: tok.text, Line: tok.line}
+		i++
+		for !tok.lineEnd && i < len(toks) && toks[i].line == d.Line && toks[i].text != "{" && toks[i].text != "}" {
+			tok = toks[i]
+			d.Args = append(d.Args, tok.text)
+			i++
+		}
+		if i < len(toks) && toks[i].text == "{" && toks[i].line == d.Line {
+			sub, next, err := parseCorefileBlock(toks, i+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			d.Sub, i = sub, next
+		}
+		dirs = append(dirs, d)
+	}
+	return nil, 0, fmt.Errorf("unterminated block: missing '}'")
+}
+
+// forwardTargets returns the upstreams of a forward directive ("forward FROM TO...").
+func forwardTargets(d *corefileDirective) []string {
+	if d == nil || len(d.Args) < 2 {
+		return nil
+	}
+	return d.Args[1:]
+}
+
+// forwardTargetAddr parses an upstream such as "tls://1.1.1.1:853" or
+// "10.0.0.10". isFile is set for resolv.conf paths such as /etc/resolv.conf.
+func forwardTargetAddr(target string) (addr netip.Addr, isFile bool, err error) {
+	if strings.HasPrefix(target, "/") {
+		return netip.Addr{}, true, nil
+	}
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+	}
+	if ap, perr := netip.ParseAddrPort(target); perr == nil {
+		return ap.Addr(), false, nil
+	}
+	a, perr := netip.ParseAddr(strings.Trim(target, "[]"))
+	if perr != nil {
+		return netip.Addr{}, false, fmt.Errorf("%q is not an IP address, IP:port or resolv.conf path", target)
+	}
+	return a, false, nil
+}
+
+// analyzeCorefile checks parsed server blocks for configuration that breaks or
+// degrades cluster DNS. dnsServiceIP is the kube-dns ClusterIP, used to spot
+// forwarding loops; it may be empty.
+func analyzeCorefile(servers []corefileServer, clusterDomain, dnsServiceIP string, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, sev, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryDNS, Code: code, Resource: ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+	clusterZone := fqdn(clusterDomain)
+
+	// Duplicate zone/port pairs stop CoreDNS from starting.
+	seen := make(map[corefileZone]int)
+	for _, s := range servers {
+		for _, z := range s.zones() {
+			if line, dup := seen[z]; dup {
+				add(types.CodeDNSDuplicateZone, types.SeverityCritical,
+					fmt.Sprintf("Zone %s on port %s is defined by two server blocks", z.Zone, z.Port),
+					fmt.Sprintf("server blocks at lines %d and %d", line, s.Line),
+					"CoreDNS refuses to start with duplicate zone/port keys. Merge the blocks or remove the duplicate.")
+				continue
+			}
+			seen[z] = s.Line
+		}
+	}
+
+	var root *corefileServer
+	var kubeServer *corefileServer
+	var kube *corefileDirective
+	for i := range servers {
+		if root == nil && servers[i].isRoot() {
+			root = &servers[i]
+		}
+		if d := servers[i].directive("kubernetes"); d != nil && kube == nil {
+			kubeServer, kube = &servers[i], d
+		}
+	}
+
+	// kubernetes plugin and zones
+	if kube == nil {
+		add(types.CodeDNSKubernetesPluginMissing, types.SeverityCritical, "No server block enables the kubernetes plugin",
+			fmt.Sprintf("server blocks: %d", len(servers)),
+			fmt.Sprintf("Add `kubernetes %s in-addr.arpa ip6.arpa` to the root server block so Service and Pod names resolve.", strings.TrimSuffix(clusterZone, ".")))
+	} else {
+		kubeZones := make([]string, 0, len(kube.Args))
+		for _, a := range kube.Args {
+			kubeZones = append(kubeZones, fqdn(a))
+		}
+		if len(kubeZones) == 0 {
+			for _, z := range kubeServer.zones() {
+				kubeZones = append(kubeZones, z.Zone)
+			}
+		}
+		servesCluster, servesReverse := false, false
+		for _, z := range kubeZones {
+			if z == clusterZone || z == "." || strings.HasSuffix(clusterZone, "."+z) {
+				servesCluster = true
+			}
+			if strings.HasSuffix(z, "in-addr.arpa.") || strings.HasSuffix(z, "ip6.arpa.") {
+				servesReverse = true
+			}
+		}
+		if !servesCluster {
+			add(types.CodeDNSClusterDomainNotServed, types.SeverityCritical,
+				fmt.Sprintf("kubernetes plugin does not serve the cluster domain %s", strings.TrimSuffix(clusterZone, ".")),
+				fmt.Sprintf("kubernetes zones=[%s] (line %d)", strings.Join(kubeZones, " "), kube.Line),
+				fmt.Sprintf("Add %s to the kubernetes plugin zones; names like svc.%s currently fall through to the forwarders.", strings.TrimSuffix(clusterZone, "."), strings.TrimSuffix(clusterZone, ".")))
+		}
+		if !servesReverse {
+			add("", types.SeverityInfo, "kubernetes plugin does not serve reverse zones",
+				fmt.Sprintf("kubernetes zones=[%s] (line %d)", strings.Join(kubeZones, " "), kube.Line),
+				"Add in-addr.arpa and ip6.arpa to the kubernetes plugin zones so PTR lookups for Pod and Service IPs are answered.")
+		}
+	}
+
+	if root == nil {
+		add(types.CodeDNSNoRootZone, types.SeverityWarning, "No root (.) server block: names outside the configured zones are refused",
+			fmt.Sprintf("zones: %s", strings.Join(corefileAllKeys(servers), ", ")),
+			"Add a `.:53` server block with a forward plugin so external names resolve.")
+	} else {
+		if root.directive("forward") == nil && root.directive("proxy") == nil {
+			add(types.CodeDNSForwardMissing, types.SeverityWarning, "Root server block has no forward plugin: external names will not resolve",
+				fmt.Sprintf("server block %q (line %d)", root.label(), root.Line),
+				"Add `forward . /etc/resolv.conf` (or explicit upstream resolvers) to the root server block.")
+		}
+		if root.directive("proxy") != nil {
+			add(types.CodeDNSRemovedPlugin, types.SeverityCritical, "Root server block uses the removed proxy plugin",
+				fmt.Sprintf("proxy at line %d", root.directive("proxy").Line),
+				"The proxy plugin was removed in CoreDNS 1.6; CoreDNS fails to start. Replace it with forward.")
+		}
+		if c := root.directive("cache"); c == nil {
+			add(types.CodeDNSCacheDisabled, types.SeverityWarning, "Caching is disabled in the root server block",
+				fmt.Sprintf("server block %q (line %d) has no cache plugin", root.label(), root.Line),
+				"Add `cache 30`: without it every query is answered from the API watch or forwarded upstream, multiplying upstream QPS.")
+		} else if len(c.Args) > 0 && c.Args[0] == "0" {
+			add(types.CodeDNSCacheDisabled, types.SeverityWarning, "Cache TTL is 0 in the root server block",
+				fmt.Sprintf("cache %s (line %d)", strings.Join(c.Args, " "), c.Line),
+				"Use a TTL of at least 5 seconds; `cache 0` effectively disables caching.")
+		}
+		if root.directive("errors") == nil {
+			add("", types.SeverityInfo, "errors plugin is not enabled: upstream failures are not logged",
+				fmt.Sprintf("server block %q (line %d)", root.label(), root.Line),
+				"Add `errors` so forwarding and API errors appear in CoreDNS logs.")
+		}
+		for _, p := range []string{"health", "ready"} {
+			if root.directive(p) == nil {
+				add("", types.SeverityInfo, fmt.Sprintf("%s plugin is not enabled", p),
+					fmt.Sprintf("server block %q (line %d)", root.label(), root.Line),
+					fmt.Sprintf("The CoreDNS Deployment's probes usually depend on the %s endpoint; add `%s`.", p, p))
+			}
+		}
+	}
+
+	// Forwarders and stub domains
+	for i := range servers {
+		s := &servers[i]
+		fwd := s.directive("forward")
+		if fwd != nil && len(fwd.Args) < 2 {
+			add(types.CodeDNSForwardInvalid, types.SeverityCritical, fmt.Sprintf("forward in %q has no upstream", s.label()),
+				fmt.Sprintf("forward %s (line %d)", strings.Join(fwd.Args, " "), fwd.Line),
+				"forward needs a FROM zone and at least one upstream, e.g. `forward . 10.0.0.2`.")
+		}
+		usesResolvConf := false
+		for _, target := range forwardTargets(fwd) {
+			addr, isFile, err := forwardTargetAddr(target)
+			switch {
+			case err != nil:
+				add(types.CodeDNSForwardInvalid, types.SeverityCritical, fmt.Sprintf("Invalid forward upstream in %q", s.label()),
+					fmt.Sprintf("%v (line %d)", err, fwd.Line),
+					"Use IP, IP:port, tls://IP or a resolv.conf path; hostnames are not supported as upstreams.")
+			case isFile:
+				usesResolvConf = true
+			case addr.IsLoopback():
+				add(types.CodeDNSForwardingLoop, types.SeverityCritical, fmt.Sprintf("forward in %q targets loopback %s: forwarding loop", s.label(), addr),
+					fmt.Sprintf("forward %s (line %d)", strings.Join(fwd.Args, " "), fwd.Line),
+					"Inside the CoreDNS pod a loopback upstream is CoreDNS itself. Forward to the node's real resolvers instead.")
+			case dnsServiceIP != "" && addr.String() == dnsServiceIP:
+				add(types.CodeDNSForwardingLoop, types.SeverityCritical, fmt.Sprintf("forward in %q targets the kube-dns Service %s: forwarding loop", s.label(), addr),
+					fmt.Sprintf("forward %s (line %d)", strings.Join(fwd.Args, " "), fwd.Line),
+					"CoreDNS forwarding to its own Service sends queries back to itself. Forward to upstream resolvers.")
+			}
+		}
+		if usesResolvConf && s.directive("loop") == nil {
+			add(types.CodeDNSLoopDetectionDisabled, types.SeverityWarning, fmt.Sprintf("%q forwards to resolv.conf without the loop plugin", s.label()),
+				fmt.Sprintf("forward %s (line %d)", strings.Join(fwd.Args, " "), fwd.Line),
+				"On nodes running systemd-resolved, /etc/resolv.conf points at 127.0.0.53 and creates an undetected loop. Add `loop`, and set kubelet --resolv-conf to /run/systemd/resolve/resolv.conf.")
+		}
+
+		if !s.isRoot() && s != kubeServer && fwd == nil && !corefileAnswers(s) {
+			add(types.CodeDNSForwardMissing, types.SeverityWarning, fmt.Sprintf("Stub domain block %q has no forward or answering plugin", s.label()),
+				fmt.Sprintf("plugins: %s (line %d)", corefilePluginNames(s), s.Line),
+				"Queries for this zone will return SERVFAIL. Add `forward . <stub resolver IPs>`.")
+		}
+	}
+
+	if root != nil && root.directive("loop") == nil {
+		add(types.CodeDNSLoopDetectionDisabled, types.SeverityWarning, "loop plugin is not enabled: forwarding loops go undetected",
+			fmt.Sprintf("server block %q (line %d)", root.label(), root.Line),
+			"Add `loop`; an undetected loop makes CoreDNS consume CPU and memory until it is OOM-killed.")
+	}
+
+	if len(findings) == 0 {
+		add("", types.SeverityOK,
+			fmt.Sprintf("Corefile looks healthy: %d server block(s), kubernetes zone %s", len(servers), strings.TrimSuffix(clusterZone, ".")),
+			fmt.Sprintf("zones: %s", strings.Join(corefileAllKeys(servers), ", ")), "")
+	}
+	return findings
+}
+
+// corefileAnswers reports whether a block can answer queries without forwarding.
+func corefileAnswers(s *corefileServer) bool {
+	for _, p := range []string{"file", "hosts", "template", "auto", "secondary", "etcd", "k8s_external", "rewrite", "whoami", "grpc"} {
+		if s.directive(p) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func corefilePluginNames(s *corefileServer) string {
+	names := make([]string, 0, len(s.Directives))
+	for _, d := range s.Directives {
+		names = append(names, d.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func corefileAllKeys(servers []corefileServer) []string {
+	var keys []string
+	for _, s := range servers {
+		keys = append(keys, s.Keys...)
+	}
+	return keys
+}
+
+// corednsLogClass groups CoreDNS log lines that point at the same cause.
+type corednsLogClass struct {
+	Name       string
+	Severity   string
+	Match      func(line string) bool
+	Suggestion string
+}
+
+var corednsLogClasses = []corednsLogClass{
+	{
+		Name: "forwarding loop", Severity: types.SeverityCritical,
+		Match:      func(l string) bool { return strings.Contains(l, "plugin/loop") },
+		Suggestion: "CoreDNS detected queries coming back to itself. Check forward targets and the nodes' resolv.conf (systemd-resolved stub).",
+	},
+	{
+		Name: "Kubernetes API access", Severity: types.SeverityCritical,
+		Match: func(l string) bool {
+			return strings.Contains(l, "plugin/kubernetes") && (strings.Contains(l, "forbidden") || strings.Contains(l, "Failed to watch") || strings.Contains(l, "failed to list"))
+		},
+		Suggestion: "CoreDNS cannot watch Services/EndpointSlices. Check the system:coredns ClusterRole and API server reachability.",
+	},
+	{
+		Name: "upstream timeout", Severity: types.SeverityWarning,
+		Match: func(l string) bool {
+			return strings.Contains(l, "i/o timeout") || strings.Contains(l, "context deadline exceeded")
+		},
+		Suggestion: "Forward upstreams are not answering in time. Verify they are reachable from the CoreDNS pods and not rate limiting.",
+	},
+	{
+		Name: "upstream refused", Severity: types.SeverityWarning,
+		Match: func(l string) bool {
+			return strings.Contains(l, "connection refused") || strings.Contains(l, "no route to host")
+		},
+		Suggestion: "Forward upstreams refuse connections. Check the upstream IPs/ports and egress NetworkPolicies for kube-system.",
+	},
+	{
+		Name: "SERVFAIL", Severity: types.SeverityWarning,
+		Match:      func(l string) bool { return strings.Contains(l, "SERVFAIL") },
+		Suggestion: "Queries are failing upstream or in a stub domain. Correlate the failing names with the forward and stub domain configuration.",
+	},
+}
+
+// classifyCorednsLogs counts error lines per class, keeping one sample each.
+func classifyCorednsLogs(logs string) (map[string]int, map[string]string) {
+	counts := make(map[string]int)
+	samples := make(map[string]string)
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(line, "[ERROR]") && !strings.Contains(line, "[FATAL]") && !strings.Contains(line, "[WARNING]") {
+			continue
+		}
+		for _, c := range corednsLogClasses {
+			if c.Match(line) {
+				counts[c.Name]++
+				if _, ok := samples[c.Name]; !ok {
+					samples[c.Name] = strings.TrimSpace(line)
+				}
+				break
+			}
+		}
+	}
+	return counts, samples
+}
+
+// --- analyze_coredns_config ---
+
+type AnalyzeCoreDNSConfigTool struct{ BaseTool }
+
+func (t *AnalyzeCoreDNSConfigTool) Name() string { return "analyze_coredns_config" }
+func (t *AnalyzeCoreDNSConfigTool) Description() string {
+	return "Parse the CoreDNS Corefile and flag missing kubernetes zones, invalid forwarders and stub domains, disabled cache and loop risks, correlated with recent CoreDNS error logs"
+}
+func (t *AnalyzeCoreDNSConfigTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of CoreDNS (default: kube-system)",
+			},
+			"configmap": map[string]interface{}{
+				"type":        "string",
+				"description": "ConfigMap holding the Corefile (default: coredns)",
+			},
+			"cluster_domain": map[string]interface{}{
+				"type":        "string",
+				"description": "Cluster DNS domain the kubernetes plugin must serve (default: cluster.local)",
+			},
+			"since": map[string]interface{}{
+				"type":        "string",
+				"description": "How far back to read CoreDNS logs (default: 30m)",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeCoreDNSConfigTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "kube-system")
+	cmName := getStringArg(args, "configmap", "coredns")
+	clusterDomain := getStringArg(args, "cluster_domain", "cluster.local")
+	since := getStringArg(args, "since", "30m")
+
+	cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, cmName, metav1.GetOptions{})
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("CoreDNS ConfigMap %s/%s not found", ns, cmName),
+			Detail:  err.Error(),
+		}
+	}
+	ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: cmName}
+	corefile, _, _ := unstructured.NestedString(cm.Object, "data", "Corefile")
+
+	findings := make([]types.DiagnosticFinding, 0, 8)
+	if corefile == "" {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSCorefileMissing,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("ConfigMap %s/%s has no Corefile key", ns, cmName),
+			Suggestion: "CoreDNS reads its configuration from data.Corefile; check the ConfigMap mounted by the CoreDNS Deployment.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	servers, err := parseCorefile(corefile)
+	if err != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSCorefileParseError,
+			Resource:   ref,
+			Summary:    "Corefile does not parse",
+			Detail:     err.Error(),
+			Suggestion: "CoreDNS keeps the last good configuration on reload but fails to start with this one. Fix the syntax error.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	var dnsServiceIP string
+	if svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, "kube-dns", metav1.GetOptions{}); err == nil {
+		dnsServiceIP, _, _ = unstructured.NestedString(svc.Object, "spec", "clusterIP")
+	}
+	findings = append(findings, analyzeCorefile(servers, clusterDomain, dnsServiceIP, ref)...)
+	findings = append(findings, t.legacyStubDomains(ctx, ns)...)
+	findings = append(findings, t.correlateLogs(ctx, ns, since, servers)...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// legacyStubDomains flags kube-dns style stubDomains/upstreamNameservers, which
+// CoreDNS ignores unless they were migrated into the Corefile.
+func (t *AnalyzeCoreDNSConfigTool) legacyStubDomains(ctx context.Context, ns string) []types.DiagnosticFinding {
+	cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, "kube-dns", metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: "kube-dns"}
+	var findings []types.DiagnosticFinding
+	for _, key := range []string{"stubDomains", "upstreamNameservers"} {
+		raw, _, _ := unstructured.NestedString(cm.Object, "data", key)
+		if raw == "" {
+			continue
+		}
+		var parsed interface{}
+		detail := fmt.Sprintf("%s=%s", key, raw)
+		if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+			detail += fmt.Sprintf(" (invalid JSON: %v)", err)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSLegacyConfigMapKeys,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("kube-dns ConfigMap sets %s, which CoreDNS ignores", key),
+			Detail:     detail,
+			Suggestion: "Move stub domains into the Corefile as `<domain>:53 { forward . <ips> }` server blocks and upstream nameservers into the root forward plugin.",
+		})
+	}
+	return findings
+}
+
+// correlateLogs reads recent CoreDNS logs and reports error classes, naming
+// the forwarders involved when upstreams are failing.
+func (t *AnalyzeCoreDNSConfigTool) correlateLogs(ctx context.Context, ns, since string, servers []corefileServer) []types.DiagnosticFinding {
+	pods, err := t.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=kube-dns"})
+	if err != nil || len(pods.Items) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	samples := make(map[string]string)
+	podsWith := make(map[string][]string)
+	read := 0
+	for _, pod := range pods.Items {
+		if len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		lr, err := getPodLogs(ctx, t.Clients, ns, pod.Name, pod.Spec.Containers[0].Name, 1000, since)
+		if err != nil {
+			continue
+		}
+		read++
+		c, s := classifyCorednsLogs(lr.logs)
+		for name, n := range c {
+			counts[name] += n
+			podsWith[name] = append(podsWith[name], pod.Name)
+			if _, ok := samples[name]; !ok {
+				samples[name] = s[name]
+			}
+		}
+	}
+	if read == 0 {
+		return nil
+	}
+
+	var upstreams []string
+	for i := range servers {
+		upstreams = append(upstreams, forwardTargets(servers[i].directive("forward"))...)
+	}
+	sort.Strings(upstreams)
+
+	var findings []types.DiagnosticFinding
+	for _, c := range corednsLogClasses {
+		n := counts[c.Name]
+		if n == 0 {
+			continue
+		}
+		suggestion := c.Suggestion
+		if strings.HasPrefix(c.Name, "upstream") && len(upstreams) > 0 {
+			suggestion += fmt.Sprintf(" Configured upstreams: %s.", strings.Join(upstreams, ", "))
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   c.Severity,
+			Category:   types.CategoryLogs,
+			Code:       types.CodeDNSCoreDNSLogErrors,
+			Resource:   &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: "coredns", APIVersion: "apps/v1"},
+			Summary:    fmt.Sprintf("CoreDNS logged %d %s error(s) in the last %s", n, c.Name, since),
+			Detail:     fmt.Sprintf("pods=%s sample: %s", strings.Join(podsWith[c.Name], ","), samples[c.Name]),
+			Suggestion: suggestion,
+		})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryLogs,
+			Summary:  fmt.Sprintf("No CoreDNS errors in the last %s across %d pod(s)", since, read),
+		})
+	}
+	return findings
+}
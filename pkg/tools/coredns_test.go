@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const defaultCorefile = `.:53 {
+    errors
+    health {
+       lameduck 5s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+       ttl 30
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf {
+       max_concurrent 1000
+    }
+    cache 30
+    loop
+    reload
+    loadbalance
+}
+consul.local:53 {
+    errors
+    cache 30
+    forward . 10.150.0.1
+}
+`
+
+func TestParseCorefile(t *testing.T) {
+	servers, err := parseCorefile(defaultCorefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 server blocks, got %d", len(servers))
+	}
+	kube := servers[0].directive("kubernetes")
+	if kube == nil || strings.Join(kube.Args, " ") != "cluster.local in-addr.arpa ip6.arpa" || len(kube.Sub) != 3 {
+		t.Errorf("unexpected kubernetes directive %+v", kube)
+	}
+	if got := forwardTargets(servers[1].directive("forward")); len(got) != 1 || got[0] != "10.150.0.1" {
+		t.Errorf("unexpected stub forwarders %v", got)
+	}
+	if z := parseCorefileKey("dns://consul.local:5353"); z.Zone != "consul.local." || z.Port != "5353" {
+		t.Errorf("parseCorefileKey() = %+v", z)
+	}
+
+	if _, err := parseCorefile(".:53 {\n  errors\n"); err == nil {
+		t.Error("expected an error for an unterminated block")
+	}
+}
+
+func TestAnalyzeCorefile_Default(t *testing.T) {
+	servers, _ := parseCorefile(defaultCorefile)
+	findings := analyzeCorefile(servers, "cluster.local", "10.96.0.10", nil)
+	if len(findings) != 1 || findings[0].Severity != types.SeverityOK {
+		t.Errorf("expected a single ok finding for the default Corefile, got %+v", findings)
+	}
+}
+
+func TestAnalyzeCorefile_Misconfigured(t *testing.T) {
+	corefile := `.:53 {
+    kubernetes example.internal
+    forward . 10.96.0.10 8.8.8.8
+}
+.:53 {
+    forward . dns.google
+}
+corp.example:53 {
+    errors
+}
+`
+	servers, err := parseCorefile(corefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := analyzeCorefile(servers, "cluster.local", "10.96.0.10", nil)
+	want := []string{
+		"defined by two server blocks",
+		"does not serve the cluster domain cluster.local",
+		"targets the kube-dns Service",
+		"Invalid forward upstream",
+		"Caching is disabled",
+		"loop plugin is not enabled",
+		`Stub domain block "corp.example:53"`,
+	}
+	for _, w := range want {
+		found := false
+		for _, f := range findings {
+			if strings.Contains(f.Summary, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected a finding containing %q", w)
+		}
+	}
+}
+
+func TestClassifyCorednsLogs(t *testing.T) {
+	logs := `[INFO] plugin/reload: Running configuration SHA512 = abc
+[FATAL] plugin/loop: Loop (127.0.0.1:55953 -> :53) detected for zone ".", see https://coredns.io/plugins/loop#troubleshooting
+[ERROR] plugin/errors: 2 example.com. A: read udp 10.244.0.5:40312->10.150.0.1:53: i/o timeout
+[ERROR] plugin/errors: 2 example.org. A: read udp 10.244.0.5:40313->10.150.0.1:53: i/o timeout
+`
+	counts, samples := classifyCorednsLogs(logs)
+	if counts["forwarding loop"] != 1 || counts["upstream timeout"] != 2 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+	if !strings.Contains(samples["upstream timeout"], "example.com") {
+		t.Errorf("expected the first timeout line as sample, got %q", samples["upstream timeout"])
+	}
+}
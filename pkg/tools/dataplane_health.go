@@ -123,6 +123,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeMeshSidecarNotReady,
 					Resource:   podRef,
 					Summary:    fmt.Sprintf("Sidecar %q is not ready in pod %s/%s", c.Name, pod.Namespace, pod.Name),
 					Detail:     fmt.Sprintf("container=%s ready=false", c.Name),
@@ -135,6 +136,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeMeshSidecarRestarts,
 					Resource:   podRef,
 					Summary:    fmt.Sprintf("Sidecar %q has restarted %d time(s) in pod %s/%s", c.Name, cs.restartCount, pod.Namespace, pod.Name),
 					Detail:     fmt.Sprintf("container=%s restartCount=%d", c.Name, cs.restartCount),
@@ -149,6 +151,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryMesh,
+						Code:       types.CodeIstioVersionSkew,
 						Resource:   podRef,
 						Summary:    fmt.Sprintf("Istio version skew detected in pod %s/%s: proxy=%s, control-plane=%s", pod.Namespace, pod.Name, proxyTag, istiodTag),
 						Detail:     fmt.Sprintf("proxy_image=%s istiod_tag=%s", c.Image, istiodTag),
@@ -168,6 +171,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryMesh,
+							Code:       types.CodeMeshSidecarNotReady,
 							Resource:   podRef,
 							Summary:    fmt.Sprintf("Native sidecar %q (init container) is not ready in pod %s/%s", ic.Name, pod.Namespace, pod.Name),
 							Detail:     fmt.Sprintf("container=%s ready=false", ic.Name),
@@ -178,6 +182,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryMesh,
+							Code:       types.CodeMeshSidecarRestarts,
 							Resource:   podRef,
 							Summary:    fmt.Sprintf("Native sidecar %q (init container) has restarted %d time(s) in pod %s/%s", ic.Name, ics.restartCount, pod.Namespace, pod.Name),
 							Detail:     fmt.Sprintf("container=%s restartCount=%d", ic.Name, ics.restartCount),
@@ -193,6 +198,7 @@ func (t *CheckDataplaneHealthTool) Run(ctx context.Context, args map[string]inte
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryMesh,
+						Code:       types.CodeMeshInitContainerFailed,
 						Resource:   podRef,
 						Summary:    fmt.Sprintf("Init container %q exited with code %d in pod %s/%s", ic.Name, status.exitCode, pod.Namespace, pod.Name),
 						Detail:     fmt.Sprintf("container=%s exitCode=%d reason=%s", ic.Name, status.exitCode, status.reason),
@@ -0,0 +1,566 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Progressive delivery controllers a canary can be driven by.
+const (
+	canaryControllerFlagger = "flagger"
+	canaryControllerArgo    = "argo-rollouts"
+	canaryControllerNone    = "none"
+)
+
+// canaryDesign holds the inputs of design_canary after defaults are applied.
+type canaryDesign struct {
+	svcName      string
+	ns           string
+	port         int
+	mode         string // "istio" or "gateway-api"
+	controller   string
+	versionLabel string
+	stable       string
+	canary       string
+	weight       int
+	stepWeight   int
+	maxWeight    int
+	hostname     string
+	gwName       string
+	gwNamespace  string
+	promAddress  string
+	// selector is the selector of the live Service, used for the stable and
+	// canary Services and, with Argo Rollouts, the DestinationRule subsets.
+	selector map[string]string
+}
+
+func (d canaryDesign) host() string { return fmt.Sprintf("%s.%s.svc.cluster.local", d.svcName, d.ns) }
+
+// weights returns the canary weights of the rollout steps: weight, then
+// stepWeight increments up to maxWeight.
+func (d canaryDesign) weights() []int {
+	weights := []int{d.weight}
+	for w := d.weight + d.stepWeight; w <= d.maxWeight; w += d.stepWeight {
+		weights = append(weights, w)
+	}
+	return weights
+}
+
+// labelsYAML renders labels as a YAML block indented by indent spaces.
+func labelsYAML(labels map[string]string, indent int) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pad := strings.Repeat(" ", indent)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s%s: %s", pad, k, labels[k])
+	}
+	return b.String()
+}
+
+// versionSelector is the Service selector narrowed to one version.
+func (d canaryDesign) versionSelector(version string) map[string]string {
+	out := map[string]string{d.versionLabel: version}
+	for k, v := range d.selector {
+		if k != d.versionLabel {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// subsetLabels are the DestinationRule subset labels of version. Argo
+// Rollouts adds the pod template hash itself, so its subsets keep the
+// Service selector only.
+func (d canaryDesign) subsetLabels(version string) map[string]string {
+	if d.controller == canaryControllerArgo {
+		return d.selector
+	}
+	return d.versionSelector(version)
+}
+
+func (d canaryDesign) destinationRuleYAML() string {
+	return fmt.Sprintf(`# DestinationRule - Stable and canary subsets of %s
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  host: %s
+  subsets:
+  - name: stable
+    labels:%s
+  - name: canary
+    labels:%s`, d.svcName, d.svcName, d.ns, d.host(),
+		labelsYAML(d.subsetLabels(d.stable), 6), labelsYAML(d.subsetLabels(d.canary), 6))
+}
+
+func (d canaryDesign) virtualServiceYAML() string {
+	hosts := "\n  - " + d.host()
+	gateways := ""
+	if d.hostname != "" {
+		hosts += fmt.Sprintf("\n  - %q", d.hostname)
+	}
+	if d.gwName != "" {
+		gateways = fmt.Sprintf("\n  gateways:\n  - %s/%s\n  - mesh", d.gwNamespace, d.gwName)
+	}
+	return fmt.Sprintf(`# VirtualService - Splits traffic between the stable and canary subsets
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  hosts:%s%s
+  http:
+  - name: primary
+    route:
+    - destination:
+        host: %s
+        subset: stable
+        port:
+          number: %d
+      weight: %d
+    - destination:
+        host: %s
+        subset: canary
+        port:
+          number: %d
+      weight: %d`, d.svcName, d.ns, hosts, gateways,
+		d.host(), d.port, 100-d.weight, d.host(), d.port, d.weight)
+}
+
+// serviceYAML renders the stable or canary Service of the Gateway API mode.
+func (d canaryDesign) serviceYAML(role, version string) string {
+	selector := d.versionSelector(version)
+	comment := fmt.Sprintf("selects the %s pods", role)
+	if d.controller == canaryControllerArgo {
+		selector = d.selector
+		comment = "Argo Rollouts adds the pod template hash to the selector"
+	}
+	return fmt.Sprintf(`# Service - %s backend (%s)
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s-%s
+  namespace: %s
+spec:
+  selector:%s
+  ports:
+  - name: http
+    port: %d
+    targetPort: %d`, role, comment, d.svcName, role, d.ns, labelsYAML(selector, 4), d.port, d.port)
+}
+
+func (d canaryDesign) parentRefYAML() string {
+	if d.gwName == "" {
+		// GAMMA: the route attaches to the Service and splits mesh traffic.
+		return fmt.Sprintf(`  parentRefs:
+  - group: ""
+    kind: Service
+    name: %s
+    port: %d`, d.svcName, d.port)
+	}
+	ref := fmt.Sprintf(`  parentRefs:
+  - name: %s`, d.gwName)
+	if d.gwNamespace != d.ns {
+		ref += fmt.Sprintf(`
+    namespace: %s`, d.gwNamespace)
+	}
+	return ref
+}
+
+func (d canaryDesign) httpRouteYAML() string {
+	hostnames := ""
+	if d.hostname != "" && d.gwName != "" {
+		hostnames = fmt.Sprintf(`
+  hostnames:
+  - %q`, d.hostname)
+	}
+	return fmt.Sprintf(`# HTTPRoute - Splits traffic between the stable and canary Services
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: %s
+  namespace: %s
+spec:
+%s%s
+  rules:
+  - backendRefs:
+    - name: %s-stable
+      port: %d
+      weight: %d
+    - name: %s-canary
+      port: %d
+      weight: %d`, d.svcName, d.ns, d.parentRefYAML(), hostnames,
+		d.svcName, d.port, 100-d.weight, d.svcName, d.port, d.weight)
+}
+
+func (d canaryDesign) flaggerCanaryYAML() string {
+	provider := "istio"
+	service := fmt.Sprintf(`
+  service:
+    port: %d
+    targetPort: %d`, d.port, d.port)
+	if d.mode == "gateway-api" {
+		provider = "gatewayapi:v1"
+		gw, gwNs := d.gwName, d.gwNamespace
+		if gw == "" {
+			gw, gwNs = "<gateway>", d.ns
+		}
+		service += fmt.Sprintf(`
+    gatewayRefs:
+    - name: %s
+      namespace: %s`, gw, gwNs)
+	}
+	if d.hostname != "" {
+		service += fmt.Sprintf(`
+    hosts:
+    - %q`, d.hostname)
+		if d.mode == "istio" && d.gwName != "" {
+			service += fmt.Sprintf(`
+    gateways:
+    - %s/%s
+    - mesh`, d.gwNamespace, d.gwName)
+		}
+	}
+	return fmt.Sprintf(`# Canary - Flagger shifts traffic in %d%% steps up to %d%% while the metrics pass
+apiVersion: flagger.app/v1beta1
+kind: Canary
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  provider: %s
+  targetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: %s%s
+  analysis:
+    interval: 1m
+    threshold: 5
+    stepWeight: %d
+    maxWeight: %d
+    metrics:
+    - name: request-success-rate
+      interval: 1m
+      thresholdRange:
+        min: 99
+    - name: request-duration
+      interval: 1m
+      thresholdRange:
+        max: 500`, d.stepWeight, d.maxWeight, d.svcName, d.ns, provider, d.svcName, service, d.stepWeight, d.maxWeight)
+}
+
+func (d canaryDesign) analysisTemplateName() string { return d.svcName + "-success-rate" }
+
+func (d canaryDesign) analysisTemplateYAML() string {
+	query := fmt.Sprintf(`sum(irate(istio_requests_total{reporter="source",destination_service=~"%s",response_code!~"5.*"}[1m])) /
+          sum(irate(istio_requests_total{reporter="source",destination_service=~"%s"}[1m]))`, d.host(), d.host())
+	comment := ""
+	if d.mode == "gateway-api" {
+		query = fmt.Sprintf(`sum(rate(http_requests_total{service="%s-canary",code!~"5.."}[1m])) /
+          sum(rate(http_requests_total{service="%s-canary"}[1m]))`, d.svcName, d.svcName)
+		comment = "\n        # Replace with the request metrics of your Gateway API implementation"
+	}
+	return fmt.Sprintf(`# AnalysisTemplate - Fails the rollout when the success rate drops below 99%%
+apiVersion: argoproj.io/v1alpha1
+kind: AnalysisTemplate
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  metrics:
+  - name: success-rate
+    interval: 1m
+    failureLimit: 3
+    successCondition: len(result) == 0 || result[0] >= 0.99
+    provider:
+      prometheus:
+        address: %s%s
+        query: |
+          %s`, d.analysisTemplateName(), d.ns, d.promAddress, comment, query)
+}
+
+func (d canaryDesign) rolloutYAML() string {
+	routing := fmt.Sprintf(`
+      trafficRouting:
+        istio:
+          virtualService:
+            name: %s
+            routes:
+            - primary
+          destinationRule:
+            name: %s
+            canarySubsetName: canary
+            stableSubsetName: stable`, d.svcName, d.svcName)
+	if d.mode == "gateway-api" {
+		routing = fmt.Sprintf(`
+      canaryService: %s-canary
+      stableService: %s-stable
+      trafficRouting:
+        plugins:
+          argoproj-labs/gatewayAPI:
+            httpRoute: %s
+            namespace: %s`, d.svcName, d.svcName, d.svcName, d.ns)
+	}
+	var steps strings.Builder
+	for _, w := range d.weights() {
+		fmt.Fprintf(&steps, `
+      - setWeight: %d
+      - pause:
+          duration: 5m`, w)
+	}
+	return fmt.Sprintf(`# Rollout - Argo Rollouts takes over the pods of Deployment %s and shifts traffic step by step
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  workloadRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: %s
+    scaleDown: progressively
+  strategy:
+    canary:%s
+      analysis:
+        templates:
+        - templateName: %s
+        startingStep: 1
+      steps:%s`, d.svcName, d.svcName, d.ns, d.svcName, routing, d.analysisTemplateName(), steps.String())
+}
+
+// --- design_canary ---
+
+type DesignCanaryTool struct{ BaseTool }
+
+func (t *DesignCanaryTool) Name() string { return "design_canary" }
+func (t *DesignCanaryTool) Description() string {
+	return "Generate a canary rollout setup: DestinationRule subsets and a weighted VirtualService (Istio) or stable/canary Services and a weighted HTTPRoute (Gateway API), plus a Flagger Canary or an Argo Rollout with an AnalysisTemplate when those controllers are installed"
+}
+func (t *DesignCanaryTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"service_name": map[string]interface{}{
+				"type":        "string",
+				"description": "Service (and Deployment) to roll out",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Target namespace",
+			},
+			"port": map[string]interface{}{
+				"type":        "integer",
+				"description": "Service port",
+			},
+			"mode": map[string]interface{}{
+				"type":        "string",
+				"description": "Traffic splitting API: istio or gateway-api (default: istio when its CRDs are installed, else gateway-api)",
+			},
+			"controller": map[string]interface{}{
+				"type":        "string",
+				"description": "Progressive delivery controller: flagger, argo-rollouts or none (default: the installed one, Flagger first)",
+			},
+			"stable_version": map[string]interface{}{
+				"type":        "string",
+				"description": "Version label value of the stable pods (default: v1)",
+			},
+			"canary_version": map[string]interface{}{
+				"type":        "string",
+				"description": "Version label value of the canary pods (default: v2)",
+			},
+			"version_label": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod label that tells versions apart (default: version)",
+			},
+			"canary_weight": map[string]interface{}{
+				"type":        "integer",
+				"description": "Initial percentage of traffic sent to the canary (default: 10)",
+			},
+			"step_weight": map[string]interface{}{
+				"type":        "integer",
+				"description": "Weight increment of each rollout step (default: 10)",
+			},
+			"max_weight": map[string]interface{}{
+				"type":        "integer",
+				"description": "Highest canary weight before promotion (default: 50)",
+			},
+			"hostname": map[string]interface{}{
+				"type":        "string",
+				"description": "External hostname, when the service is exposed through a gateway",
+			},
+			"gateway_name": map[string]interface{}{
+				"type":        "string",
+				"description": "Gateway the route attaches to (without one, Gateway API routes attach to the Service for mesh traffic)",
+			},
+			"gateway_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the gateway (default: namespace)",
+			},
+			"prometheus_address": map[string]interface{}{
+				"type":        "string",
+				"description": "Prometheus URL of the Argo Rollouts analysis (default: http://prometheus.monitoring:9090)",
+			},
+		},
+		"required": []string{"service_name", "namespace", "port"},
+	}
+}
+
+func (t *DesignCanaryTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	d := canaryDesign{
+		svcName:      getStringArg(args, "service_name", ""),
+		ns:           getStringArg(args, "namespace", "default"),
+		port:         getIntArg(args, "port", 80),
+		mode:         strings.ToLower(getStringArg(args, "mode", "")),
+		controller:   strings.ToLower(getStringArg(args, "controller", "")),
+		versionLabel: getStringArg(args, "version_label", "version"),
+		stable:       getStringArg(args, "stable_version", "v1"),
+		canary:       getStringArg(args, "canary_version", "v2"),
+		weight:       getIntArg(args, "canary_weight", 10),
+		stepWeight:   getIntArg(args, "step_weight", 10),
+		maxWeight:    getIntArg(args, "max_weight", 50),
+		hostname:     getStringArg(args, "hostname", ""),
+		gwName:       getStringArg(args, "gateway_name", ""),
+		promAddress:  getStringArg(args, "prometheus_address", "http://prometheus.monitoring:9090"),
+	}
+	d.gwNamespace = getStringArg(args, "gateway_namespace", d.ns)
+	if d.svcName == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "service_name is required"}
+	}
+	if d.weight < 1 || d.weight > 99 || d.stepWeight < 1 || d.maxWeight < d.weight || d.maxWeight > 100 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "canary_weight must be between 1 and 99, step_weight positive and max_weight between canary_weight and 100",
+		}
+	}
+
+	_, _, hasIstio := t.servedResource(groupIstioNet, "VirtualService")
+	switch d.mode {
+	case "":
+		d.mode = "gateway-api"
+		if hasIstio {
+			d.mode = "istio"
+		}
+	case "istio", "gateway-api":
+	default:
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("unsupported mode %q, use istio or gateway-api", d.mode)}
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 8)
+	_, _, hasFlagger := t.servedResource("flagger.app", "Canary")
+	_, _, hasArgo := t.servedResource("argoproj.io", "Rollout")
+	switch d.controller {
+	case "":
+		d.controller = canaryControllerNone
+		if hasFlagger {
+			d.controller = canaryControllerFlagger
+		} else if hasArgo {
+			d.controller = canaryControllerArgo
+		}
+	case canaryControllerFlagger, canaryControllerArgo:
+		if installed := map[string]bool{canaryControllerFlagger: hasFlagger, canaryControllerArgo: hasArgo}[d.controller]; !installed {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeDesignCanaryControllerMissing,
+				Summary:    fmt.Sprintf("%s is not installed in the cluster; its resources are generated but cannot be applied yet", d.controller),
+				Suggestion: fmt.Sprintf("Install the %s CRDs and controller before applying the generated manifests.", d.controller),
+			})
+		}
+	case canaryControllerNone:
+	default:
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("unsupported controller %q, use flagger, argo-rollouts or none", d.controller)}
+	}
+
+	svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(d.ns).Get(ctx, d.svcName, metav1.GetOptions{})
+	if err != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeDesignTargetServiceMissing,
+			Resource:   &types.ResourceRef{Kind: "Service", Namespace: d.ns, Name: d.svcName},
+			Summary:    fmt.Sprintf("Target service %s/%s not found", d.ns, d.svcName),
+			Suggestion: "Ensure the service is created before applying the generated manifests.",
+		})
+		d.selector = map[string]string{"app": d.svcName}
+	} else {
+		d.selector, _, _ = unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if len(d.selector) == 0 {
+			d.selector = map[string]string{"app": d.svcName}
+		}
+	}
+	if d.controller != canaryControllerNone {
+		if _, err := t.Clients.Dynamic.Resource(deploymentsGVR).Namespace(d.ns).Get(ctx, d.svcName, metav1.GetOptions{}); err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Resource:   &types.ResourceRef{Kind: "Deployment", Namespace: d.ns, Name: d.svcName},
+				Summary:    fmt.Sprintf("Deployment %s/%s not found; %s rolls out a Deployment named after the service", d.ns, d.svcName, d.controller),
+				Suggestion: "Rename the targetRef (Flagger) or workloadRef (Argo Rollouts) in the generated manifests to your Deployment.",
+			})
+		}
+	}
+
+	var resources []string
+	add := func(summary, yaml string) {
+		resources = append(resources, yaml)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  summary,
+			Detail:   yaml,
+		})
+	}
+
+	switch {
+	case d.controller == canaryControllerFlagger:
+		// Flagger generates the primary Deployment, the Services and the
+		// weighted route from the Canary, so no routing is written by hand.
+		add(fmt.Sprintf("Generated Flagger Canary (%s provider, %d%% steps up to %d%%)", d.mode, d.stepWeight, d.maxWeight), d.flaggerCanaryYAML())
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Summary:    fmt.Sprintf("Flagger creates %s-primary and %s-canary and the weighted route itself", d.svcName, d.svcName),
+			Suggestion: fmt.Sprintf("Remove hand-written VirtualServices, DestinationRules or HTTPRoutes for %s, Flagger owns them once the Canary is initialized.", d.svcName),
+		})
+	case d.mode == "istio":
+		add(fmt.Sprintf("Generated DestinationRule with stable (%s=%s) and canary (%s=%s) subsets", d.versionLabel, d.stable, d.versionLabel, d.canary), d.destinationRuleYAML())
+		add(fmt.Sprintf("Generated VirtualService sending %d%% to stable and %d%% to canary", 100-d.weight, d.weight), d.virtualServiceYAML())
+	default:
+		add(fmt.Sprintf("Generated Service %s-stable", d.svcName), d.serviceYAML("stable", d.stable))
+		add(fmt.Sprintf("Generated Service %s-canary", d.svcName), d.serviceYAML("canary", d.canary))
+		add(fmt.Sprintf("Generated HTTPRoute sending %d%% to stable and %d%% to canary", 100-d.weight, d.weight), d.httpRouteYAML())
+	}
+	if d.controller == canaryControllerArgo {
+		add(fmt.Sprintf("Generated AnalysisTemplate %s querying %s", d.analysisTemplateName(), d.promAddress), d.analysisTemplateYAML())
+		weights := make([]string, 0, len(d.weights()))
+		for _, w := range d.weights() {
+			weights = append(weights, fmt.Sprintf("%d%%", w))
+		}
+		add(fmt.Sprintf("Generated Argo Rollout with canary steps %s", strings.Join(weights, ", ")), d.rolloutYAML())
+	}
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Summary:  fmt.Sprintf("Complete canary configuration (%s, controller %s): %d resources to apply", d.mode, d.controller, len(resources)),
+		Detail:   strings.Join(resources, "\n---\n"),
+	})
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, d.ns, d.mode), nil
+}
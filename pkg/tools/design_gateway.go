@@ -81,6 +81,7 @@ func (t *DesignGatewayAPITool) Run(ctx context.Context, args map[string]interfac
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeDesignTargetServiceMissing,
 			Resource:   &types.ResourceRef{Kind: "Service", Namespace: ns, Name: svcName},
 			Summary:    fmt.Sprintf("Target service %s/%s not found", ns, svcName),
 			Suggestion: "Ensure the service is created before applying the generated manifests.",
@@ -89,7 +90,7 @@ func (t *DesignGatewayAPITool) Run(ctx context.Context, args map[string]interfac
 
 	// Check for existing gateways
 	if gwName == "" {
-		gateways, gwErr := listWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, "")
+		gateways, gwErr := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, "")
 		if gwErr == nil && len(gateways.Items) > 0 {
 			gwNames := make([]string, 0, len(gateways.Items))
 			for _, gw := range gateways.Items {
@@ -223,6 +224,7 @@ spec:
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeDesignReferenceGrantRequired,
 			Summary:    "Cross-namespace reference requires a ReferenceGrant",
 			Detail:     refGrantYAML,
 			Suggestion: "Apply this ReferenceGrant in the target service namespace to allow cross-namespace routing.",
@@ -234,6 +236,7 @@ spec:
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryTLS,
+			Code:       types.CodeDesignTLSSecretMissing,
 			Summary:    "HTTPS requested but no TLS secret specified",
 			Suggestion: "Create a TLS secret with your certificate and key, then reference it in the Gateway listener.",
 		})
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
@@ -69,12 +68,13 @@ func (t *DesignIstioTool) Run(ctx context.Context, args map[string]interface{})
 
 	// Check for existing PeerAuthentication conflicts
 	if wantMTLS {
-		existingPA, err := t.Clients.Dynamic.Resource(paV1GVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		existingPA, err := t.listResource(ctx, paV1GVR, ns)
 		if err == nil && len(existingPA.Items) > 0 {
 			for _, pa := range existingPA.Items {
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryTLS,
+					Code:       types.CodeDesignPeerAuthenticationConflict,
 					Resource:   &types.ResourceRef{Kind: "PeerAuthentication", Namespace: pa.GetNamespace(), Name: pa.GetName()},
 					Summary:    fmt.Sprintf("Existing PeerAuthentication %s/%s may conflict", pa.GetNamespace(), pa.GetName()),
 					Suggestion: "Review and potentially update this existing policy to avoid conflicts.",
@@ -190,6 +190,7 @@ spec:
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeDesignWeightsNot100,
 				Summary:    fmt.Sprintf("Traffic weights sum to %d%%, expected 100%%", totalWeight),
 				Suggestion: "Adjust the weights to ensure they sum to exactly 100%.",
 			})
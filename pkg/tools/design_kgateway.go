@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
@@ -63,7 +62,7 @@ func (t *DesignKgatewayTool) Run(ctx context.Context, args map[string]interface{
 	resources := make([]string, 0, 3)
 
 	// Check for existing kgateway resources
-	existingRO, err := t.Clients.Dynamic.Resource(routeOptionGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+	existingRO, err := t.listResource(ctx, routeOptionGVR, ns)
 	if err == nil && len(existingRO.Items) > 0 {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityInfo,
@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	familyIPv4 = "IPv4"
+	familyIPv6 = "IPv6"
+)
+
+// ipFamilyOf returns the IP family of an address or CIDR, or "" when s is
+// neither.
+func ipFamilyOf(s string) string {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		prefix, perr := netip.ParsePrefix(s)
+		if perr != nil {
+			return ""
+		}
+		addr = prefix.Addr()
+	}
+	if addr.Is4() || addr.Is4In6() {
+		return familyIPv4
+	}
+	return familyIPv6
+}
+
+// ipFamilySet is the set of IP families seen in a group of addresses.
+type ipFamilySet map[string]bool
+
+func (s ipFamilySet) add(addrs ...string) {
+	for _, a := range addrs {
+		if f := ipFamilyOf(a); f != "" {
+			s[f] = true
+		}
+	}
+}
+
+func (s ipFamilySet) dual() bool { return s[familyIPv4] && s[familyIPv6] }
+
+func (s ipFamilySet) String() string {
+	if len(s) == 0 {
+		return "unknown"
+	}
+	return joinKeys(s)
+}
+
+// missing returns the families of want that s lacks.
+func (s ipFamilySet) missing(want ipFamilySet) []string {
+	var out []string
+	for f := range want {
+		if !s[f] {
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// clusterFamilies is what a cluster supports per address space, and where
+// it was read from.
+type clusterFamilies struct {
+	pods, services           ipFamilySet
+	podSource, serviceSource string
+}
+
+// podFamilies reads the pod network's families from node pod CIDRs, and
+// from pod IPs when the CNI allocates addresses itself.
+func podFamilies(nodes, pods []unstructured.Unstructured) (ipFamilySet, string) {
+	set := ipFamilySet{}
+	for _, n := range nodes {
+		cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "podCIDRs")
+		set.add(cidrs...)
+	}
+	if len(set) > 0 {
+		return set, "node podCIDRs"
+	}
+	for _, p := range pods {
+		if host, _, _ := unstructured.NestedBool(p.Object, "spec", "hostNetwork"); host {
+			continue
+		}
+		ips, _, _ := unstructured.NestedSlice(p.Object, "status", "podIPs")
+		for _, ip := range ips {
+			if m, ok := ip.(map[string]interface{}); ok {
+				a, _ := m["ip"].(string)
+				set.add(a)
+			}
+		}
+	}
+	return set, "pod IPs"
+}
+
+// serviceFamilies reads the Service network's families from the ServiceCIDR
+// API, and from allocated ClusterIPs on clusters without it.
+func serviceFamilies(serviceCIDRs, services []unstructured.Unstructured) (ipFamilySet, string) {
+	set := ipFamilySet{}
+	for _, sc := range serviceCIDRs {
+		cidrs, _, _ := unstructured.NestedStringSlice(sc.Object, "spec", "cidrs")
+		set.add(cidrs...)
+	}
+	if len(set) > 0 {
+		return set, "ServiceCIDRs"
+	}
+	for _, svc := range services {
+		ips, _, _ := unstructured.NestedStringSlice(svc.Object, "spec", "clusterIPs")
+		set.add(ips...)
+	}
+	return set, "ClusterIPs"
+}
+
+// dualStackServiceFindings checks each Service's families against the pod
+// network, and on dual-stack clusters flags externally exposed Services and
+// load balancer addresses that only cover one family.
+func dualStackServiceFindings(services []unstructured.Unstructured, cf clusterFamilies) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	singleStack := 0
+	for _, svc := range services {
+		key := svc.GetNamespace() + "/" + svc.GetName()
+		ref := &types.ResourceRef{Kind: "Service", Namespace: svc.GetNamespace(), Name: svc.GetName(), APIVersion: "v1"}
+		svcType, _, _ := unstructured.NestedString(svc.Object, "spec", "type")
+		if svcType == "ExternalName" {
+			continue
+		}
+		policy, _, _ := unstructured.NestedString(svc.Object, "spec", "ipFamilyPolicy")
+		policy = orDefault(policy, "SingleStack")
+		families, _, _ := unstructured.NestedStringSlice(svc.Object, "spec", "ipFamilies")
+		want := ipFamilySet{}
+		for _, f := range families {
+			want[f] = true
+		}
+
+		selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if missing := cf.pods.missing(want); len(cf.pods) > 0 && len(selector) > 0 && len(missing) > 0 {
+			fams := strings.Join(missing, ",")
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeDualStackFamilyUnsupported,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Service %s has %s ClusterIPs, but the pod network has no %s addresses", key, fams, fams),
+				Detail:     fmt.Sprintf("ipFamilyPolicy=%s ipFamilies=%s; pod network: %s (from %s)", policy, strings.Join(families, ","), cf.pods, cf.podSource),
+				Suggestion: fmt.Sprintf("Clients resolving the %s address get no endpoints. Set ipFamilies to the families the CNI assigns to pods, or enable %s in the CNI and the node pod CIDRs.", fams, fams),
+			})
+		}
+
+		if !cf.services.dual() || !cf.pods.dual() {
+			continue
+		}
+		if len(want) < 2 {
+			singleStack++
+			if svcType == "LoadBalancer" || svcType == "NodePort" {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryConnectivity,
+					Code:       types.CodeDualStackServiceSingle,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("%s Service %s is %s (%s) on a dual-stack cluster", svcType, key, policy, strings.Join(families, ",")),
+					Detail:     fmt.Sprintf("clients on %s cannot reach it", strings.Join(want.missing(cf.services), ",")),
+					Suggestion: "Set ipFamilyPolicy: PreferDualStack so the Service gets an address of each family; ipFamilies can only be extended, not reordered.",
+				})
+			}
+			continue
+		}
+		if svcType != "LoadBalancer" {
+			continue
+		}
+		lb := ipFamilySet{}
+		ingress, _, _ := unstructured.NestedSlice(svc.Object, "status", "loadBalancer", "ingress")
+		for _, in := range ingress {
+			if m, ok := in.(map[string]interface{}); ok {
+				ip, _ := m["ip"].(string)
+				lb.add(ip)
+			}
+		}
+		if missing := lb.missing(want); len(lb) > 0 && len(missing) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeDualStackAddressSingle,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Dual-stack LoadBalancer Service %s has no %s load balancer address", key, strings.Join(missing, ",")),
+				Detail:     fmt.Sprintf("ipFamilies=%s; load balancer addresses: %s", strings.Join(families, ","), lb),
+				Suggestion: "The load balancer controller assigned one family only. Add addresses of the other family to its pool (MetalLB IPAddressPool, cloud dual-stack annotations).",
+			})
+		}
+	}
+	if singleStack > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("%d Service(s) are single-stack on a dual-stack cluster; in-cluster clients of the other family cannot reach them", singleStack),
+		})
+	}
+	return findings
+}
+
+// dualStackPolicyFindings flags NetworkPolicy rules that open every address
+// of one family (0.0.0.0/0 or ::/0) but not of the other: on a dual-stack
+// cluster traffic of the other family to the same peers stays denied.
+func dualStackPolicyFindings(policies []unstructured.Unstructured) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, np := range policies {
+		ref := &types.ResourceRef{Kind: "NetworkPolicy", Namespace: np.GetNamespace(), Name: np.GetName(), APIVersion: "networking.k8s.io/v1"}
+		for _, dir := range []struct{ field, peers string }{{"ingress", "from"}, {"egress", "to"}} {
+			rules, _, _ := unstructured.NestedSlice(np.Object, "spec", dir.field)
+			for i, r := range rules {
+				rm, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				peers, _, _ := unstructured.NestedSlice(rm, dir.peers)
+				wildcards := ipFamilySet{}
+				for _, p := range peers {
+					pm, _ := p.(map[string]interface{})
+					if cidr, _, _ := unstructured.NestedString(pm, "ipBlock", "cidr"); isWildcardCIDR(cidr) {
+						wildcards.add(cidr)
+					}
+				}
+				if len(wildcards) != 1 {
+					continue
+				}
+				missing := wildcards.missing(ipFamilySet{familyIPv4: true, familyIPv6: true})
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryPolicy,
+					Code:       types.CodeDualStackPolicySingle,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("NetworkPolicy %s/%s %s rule[%d] allows all %s addresses but no %s", np.GetNamespace(), np.GetName(), dir.field, i, wildcards, strings.Join(missing, ",")),
+					Detail:     fmt.Sprintf("%s traffic of the selected pods matching this rule is denied", strings.Join(missing, ",")),
+					Suggestion: "Add an ipBlock for the other family (0.0.0.0/0 and ::/0) with the same except ranges, or drop the ipBlock if the rule should not depend on the family.",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// dualStackGatewayFindings flags Gateways whose assigned IP addresses cover
+// one family only.
+func dualStackGatewayFindings(gateways []unstructured.Unstructured) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, gw := range gateways {
+		addrs, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+		set := ipFamilySet{}
+		for _, a := range addrs {
+			am, _ := a.(map[string]interface{})
+			if t, _ := am["type"].(string); t != "" && t != "IPAddress" {
+				continue
+			}
+			v, _ := am["value"].(string)
+			set.add(v)
+		}
+		if len(set) != 1 {
+			continue
+		}
+		missing := set.missing(ipFamilySet{familyIPv4: true, familyIPv6: true})
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeDualStackAddressSingle,
+			Resource:   &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()},
+			Summary:    fmt.Sprintf("Gateway %s/%s has only %s addresses on a dual-stack cluster", gw.GetNamespace(), gw.GetName(), set),
+			Detail:     fmt.Sprintf("routes attached to it are unreachable over %s", strings.Join(missing, ",")),
+			Suggestion: "Make the Gateway's Service dual-stack (ipFamilyPolicy: PreferDualStack, through the implementation's infrastructure parameters) or request an address of each family in spec.addresses.",
+		})
+	}
+	return findings
+}
+
+// --- analyze_dual_stack ---
+
+type AnalyzeDualStackTool struct{ BaseTool }
+
+func (t *AnalyzeDualStackTool) Name() string { return "analyze_dual_stack" }
+func (t *AnalyzeDualStackTool) Description() string {
+	return "Detect the IP families of the pod and Service networks, then flag Services using a family the CNI does not provide and, on dual-stack clusters, single-stack LoadBalancer and NodePort Services, load balancers and Gateways with addresses of one family only, and NetworkPolicy ipBlocks that open 0.0.0.0/0 without ::/0 (or the reverse)"
+}
+func (t *AnalyzeDualStackTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Services, NetworkPolicies and Gateways to check (default: all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeDualStackTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	var nodes, pods, serviceCIDRs []unstructured.Unstructured
+	if list, err := t.listResource(ctx, nodesGVR, ""); err == nil {
+		nodes = list.Items
+	}
+	if !hasPodCIDRs(nodes) {
+		if list, err := t.listResource(ctx, podsGVR, ""); err == nil {
+			pods = list.Items
+		}
+	}
+	if list, err := t.listResourceWithFallback(ctx, serviceCIDRsV1GVR, serviceCIDRsV1B1GVR, ""); err == nil {
+		serviceCIDRs = list.Items
+	}
+	svcList, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	services := svcList.Items
+	if ns != "" && len(serviceCIDRs) == 0 {
+		// ClusterIPs of one namespace may not show every family.
+		if all, err := t.listResource(ctx, servicesGVR, ""); err == nil {
+			services = all.Items
+		}
+	}
+
+	var cf clusterFamilies
+	cf.pods, cf.podSource = podFamilies(nodes, pods)
+	cf.services, cf.serviceSource = serviceFamilies(serviceCIDRs, services)
+	dual := cf.pods.dual() && cf.services.dual()
+
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("IP families: pods %s, Services %s; dual-stack: %v", cf.pods, cf.services, dual),
+		Detail:   fmt.Sprintf("pod families from %s, Service families from %s", cf.podSource, cf.serviceSource),
+	}}
+	if cf.pods.dual() != cf.services.dual() && len(cf.pods) > 0 && len(cf.services) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeDualStackFamilyMismatch,
+			Summary:    fmt.Sprintf("The pod network (%s) and the Service network (%s) do not have the same IP families", cf.pods, cf.services),
+			Suggestion: "Dual-stack needs both families in the cluster CIDR (kube-controller-manager or the CNI IPAM) and in --service-cluster-ip-range; configure the missing one.",
+		})
+	}
+
+	findings = append(findings, dualStackServiceFindings(svcList.Items, cf)...)
+	if dual {
+		if list, err := t.listResource(ctx, networkPoliciesGVR, ns); err == nil {
+			findings = append(findings, dualStackPolicyFindings(list.Items)...)
+		}
+		if list, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns); err == nil {
+			findings = append(findings, dualStackGatewayFindings(list.Items)...)
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+func hasPodCIDRs(nodes []unstructured.Unstructured) bool {
+	for _, n := range nodes {
+		if cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "podCIDRs"); len(cidrs) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func dualStackService(name, svcType, policy string, families ...interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{"type": svcType, "ipFamilies": families, "selector": map[string]interface{}{"app": name}}
+	if policy != "" {
+		spec["ipFamilyPolicy"] = policy
+	}
+	return ipamObj("v1", "Service", "shop", name, map[string]interface{}{"spec": spec})
+}
+
+func TestIPFamilyOf(t *testing.T) {
+	for in, want := range map[string]string{"10.0.0.1": "IPv4", "fd00::1": "IPv6", "0.0.0.0/0": "IPv4", "::/0": "IPv6", "web": ""} {
+		if got := ipFamilyOf(in); got != want {
+			t.Errorf("ipFamilyOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAnalyzeDualStack(t *testing.T) {
+	lb := dualStackService("web", "LoadBalancer", "PreferDualStack", "IPv4", "IPv6")
+	_ = unstructured.SetNestedSlice(lb.Object, []interface{}{map[string]interface{}{"ip": "203.0.113.10"}}, "status", "loadBalancer", "ingress")
+	objs := []runtime.Object{
+		ipamObj("v1", "Node", "", "node-a", map[string]interface{}{
+			"spec": map[string]interface{}{"podCIDRs": []interface{}{"10.244.0.0/24", "fd00:10:244::/64"}},
+		}),
+		lb,
+		dualStackService("api", "NodePort", "", "IPv4"),
+		dualStackService("db", "ClusterIP", "SingleStack", "IPv6"),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "egress-internet", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{},
+				"egress": []interface{}{map[string]interface{}{"to": []interface{}{
+					map[string]interface{}{"ipBlock": map[string]interface{}{"cidr": "0.0.0.0/0"}},
+				}}},
+			},
+		}),
+	}
+	gw := ipamObj("gateway.networking.k8s.io/v1", "Gateway", "shop", "public", map[string]interface{}{
+		"status": map[string]interface{}{"addresses": []interface{}{map[string]interface{}{"type": "IPAddress", "value": "203.0.113.20"}}},
+	})
+	sc := ipamObj("networking.k8s.io/v1", "ServiceCIDR", "", "kubernetes", map[string]interface{}{
+		"spec": map[string]interface{}{"cidrs": []interface{}{"10.96.0.0/16", "fd00:10:96::/112"}},
+	})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		nodesGVR: "NodeList", podsGVR: "PodList", servicesGVR: "ServiceList", networkPoliciesGVR: "NetworkPolicyList",
+		serviceCIDRsV1GVR: "ServiceCIDRList", serviceCIDRsV1B1GVR: "ServiceCIDRList",
+		gatewaysV1GVR: "GatewayList", gatewaysV1B1GVR: "GatewayList",
+	}, objs...)
+	for gvr, obj := range map[schema.GroupVersionResource]*unstructured.Unstructured{serviceCIDRsV1GVR: sc, gatewaysV1GVR: gw} {
+		if err := client.Tracker().Create(gvr, obj, obj.GetNamespace()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tool := &AnalyzeDualStackTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info IP families: pods IPv4, IPv6, Services IPv4, IPv6; dual-stack: true",
+		"warning Dual-stack LoadBalancer Service shop/web has no IPv6 load balancer address DUAL004_ADDRESS_SINGLE_FAMILY",
+		"warning NodePort Service shop/api is SingleStack (IPv4) on a dual-stack cluster DUAL002_SERVICE_SINGLE_STACK",
+		"info 2 Service(s) are single-stack on a dual-stack cluster",
+		"warning NetworkPolicy shop/egress-internet egress rule[0] allows all IPv4 addresses but no IPv6 DUAL003_POLICY_SINGLE_FAMILY",
+		"warning Gateway shop/public has only IPv4 addresses on a dual-stack cluster DUAL004_ADDRESS_SINGLE_FAMILY",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "DUAL001") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+}
+
+func TestDualStackServiceFindingsUnsupportedFamily(t *testing.T) {
+	cf := clusterFamilies{pods: ipFamilySet{familyIPv4: true}, services: ipFamilySet{familyIPv4: true, familyIPv6: true}, podSource: "node podCIDRs"}
+	svc := dualStackService("api", "ClusterIP", "RequireDualStack", "IPv4", "IPv6")
+	findings := dualStackServiceFindings([]unstructured.Unstructured{*svc}, cf)
+	if len(findings) != 1 || findings[0].Summary != "Service shop/api has IPv6 ClusterIPs, but the pod network has no IPv6 addresses" {
+		t.Errorf("dualStackServiceFindings() = %+v", findings)
+	}
+}
+
+func TestProbeIPFamily(t *testing.T) {
+	if flag, err := ipFamilyFlag("probe_http", map[string]interface{}{"ip_family": "IPv6"}); err != nil || flag != "-6" {
+		t.Errorf("ipFamilyFlag(IPv6) = %q, %v", flag, err)
+	}
+	if _, err := ipFamilyFlag("probe_http", map[string]interface{}{"ip_family": "ipv5"}); err == nil {
+		t.Error("expected ip_family ipv5 to be rejected")
+	}
+	if containsShellMeta(stripIPv6Literal("http://[fd00::1]:8080/health")) || !containsShellMeta(stripIPv6Literal("http://web/[x]")) {
+		t.Error("expected only the brackets of an IPv6 literal host to be accepted")
+	}
+	if !validHostname.MatchString("fd00::1") {
+		t.Error("expected an IPv6 address to be a valid probe target")
+	}
+}
+
+func TestAnalyzeDualStackFamilyMismatch(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		nodesGVR: "NodeList", podsGVR: "PodList", servicesGVR: "ServiceList", networkPoliciesGVR: "NetworkPolicyList",
+		serviceCIDRsV1GVR: "ServiceCIDRList", serviceCIDRsV1B1GVR: "ServiceCIDRList",
+		gatewaysV1GVR: "GatewayList", gatewaysV1B1GVR: "GatewayList",
+	}, ipamObj("v1", "Node", "", "node-a", map[string]interface{}{
+		"spec": map[string]interface{}{"podCIDRs": []interface{}{"10.244.0.0/24", "fd00:10:244::/64"}},
+	}))
+	sc := ipamObj("networking.k8s.io/v1", "ServiceCIDR", "", "kubernetes", map[string]interface{}{
+		"spec": map[string]interface{}{"cidrs": []interface{}{"10.96.0.0/16"}},
+	})
+	if err := client.Tracker().Create(serviceCIDRsV1GVR, sc, ""); err != nil {
+		t.Fatal(err)
+	}
+	tool := &AnalyzeDualStackTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "warning The pod network (IPv4, IPv6) and the Service network (IPv4) do not have the same IP families DUAL005_FAMILY_MISMATCH"
+	if all := managedFindingsOf(resp); !strings.Contains(all, want) {
+		t.Errorf("missing finding %q in:\n%s", want, all)
+	}
+}
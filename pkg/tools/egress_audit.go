@@ -0,0 +1,521 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	seV1GVR   = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1", Resource: "serviceentries"}
+	seV1B1GVR = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "serviceentries"}
+)
+
+// sensitivePlaintextPorts are ports of protocols that carry credentials or
+// data in clear text unless the client negotiates TLS.
+var sensitivePlaintextPorts = map[int]string{
+	21:    "FTP",
+	23:    "Telnet",
+	25:    "SMTP",
+	110:   "POP3",
+	143:   "IMAP",
+	389:   "LDAP",
+	1433:  "SQL Server",
+	3306:  "MySQL",
+	5432:  "PostgreSQL",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	11211: "memcached",
+	27017: "MongoDB",
+}
+
+// egressPort is a destination port of an egress path; tls is set when the
+// path is known to encrypt the traffic.
+type egressPort struct {
+	port     int
+	protocol string
+	tls      bool
+}
+
+func (p egressPort) String() string {
+	if p.protocol == "" {
+		return fmt.Sprint(p.port)
+	}
+	return fmt.Sprintf("%d/%s", p.port, p.protocol)
+}
+
+// egressPath is one way workloads reach destinations outside the cluster.
+// No ports means every port.
+type egressPath struct {
+	via      string
+	ref      *types.ResourceRef
+	from     string
+	hosts    []string
+	ports    []egressPort
+	wildcard bool
+}
+
+func (e egressPath) portList() string {
+	if len(e.ports) == 0 {
+		return "all ports"
+	}
+	parts := make([]string, 0, len(e.ports))
+	for _, p := range e.ports {
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluateEgressPath returns the inventory finding of a path, plus warnings
+// for wildcard destinations and sensitive ports that may be plaintext.
+func evaluateEgressPath(e egressPath) []types.DiagnosticFinding {
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Resource: e.ref,
+		Summary:  fmt.Sprintf("%s: %s -> %s (%s)", e.via, e.from, truncateList(e.hosts, 5), e.portList()),
+	}}
+	if e.wildcard {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeEgressWildcard,
+			Resource:   e.ref,
+			Summary:    fmt.Sprintf("%s allows %s to reach any external destination matching %s", e.via, e.from, truncateList(e.hosts, 5)),
+			Detail:     "ports: " + e.portList(),
+			Suggestion: "List the external hosts or CIDRs the workloads need instead of a wildcard, so a compromised pod cannot exfiltrate data to arbitrary destinations.",
+		})
+	}
+	for _, p := range e.ports {
+		name, sensitive := sensitivePlaintextPorts[p.port]
+		if !sensitive || p.tls {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeEgressPlaintextSensitivePort,
+			Resource:   e.ref,
+			Summary:    fmt.Sprintf("%s allows %s egress on port %d (%s) to %s", e.via, e.from, p.port, name, truncateList(e.hosts, 3)),
+			Detail:     fmt.Sprintf("%s sends credentials and data in clear text unless the client negotiates TLS", name),
+			Suggestion: "Use the TLS port of the service, or originate TLS in the mesh with a DestinationRule (tls.mode SIMPLE) for the host.",
+		})
+	}
+	return findings
+}
+
+// isWildcardCIDR reports whether cidr covers every IPv4 or IPv6 address.
+func isWildcardCIDR(cidr string) bool {
+	return cidr == "0.0.0.0/0" || cidr == "::/0"
+}
+
+// isExternalCIDR reports whether cidr reaches outside private address space.
+func isExternalCIDR(cidr string) bool {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return true
+	}
+	return !prefix.Addr().IsPrivate() && !prefix.Addr().IsLoopback()
+}
+
+// externalNamePaths returns the egress paths of ExternalName Services: any
+// pod resolving the Service name is sent to the external host.
+func externalNamePaths(services []unstructured.Unstructured) []egressPath {
+	var out []egressPath
+	for _, svc := range services {
+		if t, _, _ := unstructured.NestedString(svc.Object, "spec", "type"); t != "ExternalName" {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(svc.Object, "spec", "externalName")
+		e := egressPath{
+			via:   "ExternalName Service " + svc.GetNamespace() + "/" + svc.GetName(),
+			ref:   &types.ResourceRef{Kind: "Service", Namespace: svc.GetNamespace(), Name: svc.GetName(), APIVersion: "v1"},
+			from:  "pods resolving " + svc.GetName() + "." + svc.GetNamespace(),
+			hosts: []string{host},
+		}
+		ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+		for _, p := range ports {
+			if pm, ok := p.(map[string]interface{}); ok {
+				proto, _ := pm["protocol"].(string)
+				e.ports = append(e.ports, egressPort{port: toInt(pm["port"]), protocol: proto})
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// tlsOriginatingHosts returns the hosts for which a DestinationRule
+// originates TLS, on all ports ("host") or on one port ("host:port").
+func tlsOriginatingHosts(rules []unstructured.Unstructured) map[string]bool {
+	out := make(map[string]bool)
+	originates := func(tls map[string]interface{}) bool {
+		mode, _ := tls["mode"].(string)
+		return mode == "SIMPLE" || mode == "MUTUAL"
+	}
+	for _, dr := range rules {
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		if tls, ok, _ := unstructured.NestedMap(dr.Object, "spec", "trafficPolicy", "tls"); ok && originates(tls) {
+			out[host] = true
+		}
+		settings, _, _ := unstructured.NestedSlice(dr.Object, "spec", "trafficPolicy", "portLevelSettings")
+		for _, s := range settings {
+			sm, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if tls, ok, _ := unstructured.NestedMap(sm, "tls"); ok && originates(tls) {
+				out[fmt.Sprintf("%s:%d", host, toInt(nestedValue(sm, "port", "number")))] = true
+			}
+		}
+	}
+	return out
+}
+
+// serviceEntryPaths returns the egress paths of ServiceEntries for hosts
+// outside the mesh. A port is encrypted when its protocol is TLS or HTTPS or
+// a DestinationRule originates TLS for the host.
+func serviceEntryPaths(entries []unstructured.Unstructured, tlsHosts map[string]bool) []egressPath {
+	var out []egressPath
+	for _, se := range entries {
+		if loc, _, _ := unstructured.NestedString(se.Object, "spec", "location"); loc == "MESH_INTERNAL" {
+			continue
+		}
+		hosts, _, _ := unstructured.NestedStringSlice(se.Object, "spec", "hosts")
+		exportTo, _, _ := unstructured.NestedStringSlice(se.Object, "spec", "exportTo")
+		from := "meshed pods in all namespaces"
+		if len(exportTo) > 0 && !containsString(exportTo, "*") {
+			from = "meshed pods in " + strings.ReplaceAll(strings.Join(exportTo, ","), ".", se.GetNamespace())
+		}
+		e := egressPath{
+			via:   "ServiceEntry " + se.GetNamespace() + "/" + se.GetName(),
+			ref:   &types.ResourceRef{Kind: "ServiceEntry", Namespace: se.GetNamespace(), Name: se.GetName(), APIVersion: se.GetAPIVersion()},
+			from:  from,
+			hosts: hosts,
+		}
+		for _, h := range hosts {
+			e.wildcard = e.wildcard || strings.HasPrefix(h, "*")
+		}
+		ports, _, _ := unstructured.NestedSlice(se.Object, "spec", "ports")
+		for _, p := range ports {
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			number := toInt(pm["number"])
+			proto, _ := pm["protocol"].(string)
+			encrypted := proto == "TLS" || proto == "HTTPS"
+			for _, h := range hosts {
+				encrypted = encrypted || tlsHosts[h] || tlsHosts[fmt.Sprintf("%s:%d", h, number)]
+			}
+			e.ports = append(e.ports, egressPort{port: number, protocol: proto, tls: encrypted})
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// networkPolicyPorts reads the ports of a NetworkPolicy rule, expanding
+// endPort ranges only for the sensitive ports they include.
+func networkPolicyPorts(rule map[string]interface{}) []egressPort {
+	var out []egressPort
+	ports, _, _ := unstructured.NestedSlice(rule, "ports")
+	for _, p := range ports {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		proto, _ := pm["protocol"].(string)
+		port := toInt(pm["port"])
+		if port == 0 {
+			// Named or missing ports cannot be matched to a protocol.
+			continue
+		}
+		out = append(out, egressPort{port: port, protocol: orDefault(proto, "TCP")})
+		if end := toInt(pm["endPort"]); end > port {
+			for sp := range sensitivePlaintextPorts {
+				if sp > port && sp <= end {
+					out = append(out, egressPort{port: sp, protocol: orDefault(proto, "TCP")})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// networkPolicyPaths returns the external egress paths of NetworkPolicies:
+// rules without peers, which allow every destination, and ipBlock peers
+// outside private address space.
+func networkPolicyPaths(policies []unstructured.Unstructured) []egressPath {
+	var out []egressPath
+	for _, np := range policies {
+		policyTypes, _, _ := unstructured.NestedStringSlice(np.Object, "spec", "policyTypes")
+		if !containsString(policyTypes, "Egress") {
+			continue
+		}
+		podSel, _, _ := unstructured.NestedStringMap(np.Object, "spec", "podSelector", "matchLabels")
+		from := "all pods in " + np.GetNamespace()
+		if len(podSel) > 0 {
+			from = fmt.Sprintf("pods %s in %s", formatSelector(podSel), np.GetNamespace())
+		}
+		rules, _, _ := unstructured.NestedSlice(np.Object, "spec", "egress")
+		for i, r := range rules {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			e := egressPath{
+				via:   fmt.Sprintf("NetworkPolicy %s/%s egress[%d]", np.GetNamespace(), np.GetName(), i),
+				ref:   &types.ResourceRef{Kind: "NetworkPolicy", Namespace: np.GetNamespace(), Name: np.GetName(), APIVersion: "networking.k8s.io/v1"},
+				from:  from,
+				ports: networkPolicyPorts(rm),
+			}
+			peers, _, _ := unstructured.NestedSlice(rm, "to")
+			if len(peers) == 0 {
+				e.hosts, e.wildcard = []string{"any destination"}, true
+			}
+			for _, peer := range peers {
+				cidr, _, _ := unstructured.NestedString(peer.(map[string]interface{}), "ipBlock", "cidr")
+				if cidr == "" || !isExternalCIDR(cidr) {
+					continue
+				}
+				e.wildcard = e.wildcard || isWildcardCIDR(cidr)
+				except, _, _ := unstructured.NestedStringSlice(peer.(map[string]interface{}), "ipBlock", "except")
+				if len(except) > 0 {
+					cidr += " except " + strings.Join(except, ",")
+				}
+				e.hosts = append(e.hosts, cidr)
+			}
+			if len(e.hosts) > 0 {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// ciliumEgressPaths returns the external egress paths of Cilium policies:
+// toFQDNs, the world and all entities, and toCIDR/toCIDRSet outside private
+// address space.
+func ciliumEgressPaths(policies []unstructured.Unstructured) []egressPath {
+	var out []egressPath
+	for _, p := range policies {
+		specs, _, _ := unstructured.NestedSlice(p.Object, "specs")
+		if spec, ok, _ := unstructured.NestedMap(p.Object, "spec"); ok {
+			specs = append([]interface{}{spec}, specs...)
+		}
+		kind := p.GetKind()
+		name := qualifiedName(p.GetNamespace(), p.GetName())
+		for _, s := range specs {
+			spec, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			sel, _, _ := unstructured.NestedStringMap(spec, "endpointSelector", "matchLabels")
+			from := "all endpoints"
+			if p.GetNamespace() != "" {
+				from += " in " + p.GetNamespace()
+			}
+			if len(sel) > 0 {
+				from = "endpoints " + formatSelector(sel)
+				if p.GetNamespace() != "" {
+					from += " in " + p.GetNamespace()
+				}
+			}
+			rules, _, _ := unstructured.NestedSlice(spec, "egress")
+			for i, r := range rules {
+				rm, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				e := egressPath{
+					via:  fmt.Sprintf("%s %s egress[%d]", kind, name, i),
+					ref:  &types.ResourceRef{Kind: kind, Namespace: p.GetNamespace(), Name: p.GetName(), APIVersion: p.GetAPIVersion()},
+					from: from,
+				}
+				fqdns, _, _ := unstructured.NestedSlice(rm, "toFQDNs")
+				for _, f := range fqdns {
+					fm, _ := f.(map[string]interface{})
+					if n, _ := fm["matchName"].(string); n != "" {
+						e.hosts = append(e.hosts, n)
+					}
+					if pat, _ := fm["matchPattern"].(string); pat != "" {
+						e.hosts = append(e.hosts, pat)
+						e.wildcard = e.wildcard || strings.HasPrefix(pat, "*")
+					}
+				}
+				entities, _, _ := unstructured.NestedStringSlice(rm, "toEntities")
+				for _, ent := range entities {
+					if ent == "world" || ent == "all" {
+						e.hosts = append(e.hosts, "entity:"+ent)
+						e.wildcard = true
+					}
+				}
+				cidrs, _, _ := unstructured.NestedStringSlice(rm, "toCIDR")
+				cidrSets, _, _ := unstructured.NestedSlice(rm, "toCIDRSet")
+				for _, cs := range cidrSets {
+					if c, _ := cs.(map[string]interface{})["cidr"].(string); c != "" {
+						cidrs = append(cidrs, c)
+					}
+				}
+				for _, c := range cidrs {
+					if isExternalCIDR(c) {
+						e.hosts = append(e.hosts, c)
+						e.wildcard = e.wildcard || isWildcardCIDR(c)
+					}
+				}
+				if len(e.hosts) == 0 {
+					continue
+				}
+				toPorts, _, _ := unstructured.NestedSlice(rm, "toPorts")
+				for _, tp := range toPorts {
+					ports, _, _ := unstructured.NestedSlice(tp.(map[string]interface{}), "ports")
+					for _, port := range ports {
+						pm, _ := port.(map[string]interface{})
+						proto, _ := pm["protocol"].(string)
+						if n := toInt(pm["port"]); n > 0 {
+							e.ports = append(e.ports, egressPort{port: n, protocol: proto})
+						}
+					}
+				}
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// istioOutboundAllowAny reports whether the mesh config lets sidecars reach
+// hosts without a ServiceEntry; ALLOW_ANY is the Istio default.
+func istioOutboundAllowAny(mesh string) bool {
+	var cfg struct {
+		OutboundTrafficPolicy struct {
+			Mode string `json:"mode"`
+		} `json:"outboundTrafficPolicy"`
+	}
+	if err := yaml.Unmarshal([]byte(mesh), &cfg); err != nil {
+		return false
+	}
+	return cfg.OutboundTrafficPolicy.Mode != "REGISTRY_ONLY"
+}
+
+// --- audit_egress ---
+
+type AuditEgressTool struct{ BaseTool }
+
+func (t *AuditEgressTool) Name() string { return "audit_egress" }
+func (t *AuditEgressTool) Description() string {
+	return "Map what external hosts workloads can reach through ExternalName Services, Istio ServiceEntries, egress NetworkPolicies and CiliumNetworkPolicies; flag wildcard egress and plaintext egress to sensitive ports (databases, caches, mail, LDAP)"
+}
+func (t *AuditEgressTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *AuditEgressTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	services, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	paths := externalNamePaths(services.Items)
+
+	if entries, err := t.listResourceWithFallback(ctx, seV1GVR, seV1B1GVR, ns); err == nil {
+		var rules []unstructured.Unstructured
+		if list, err := t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, ""); err == nil {
+			rules = list.Items
+		}
+		paths = append(paths, serviceEntryPaths(entries.Items, tlsOriginatingHosts(rules))...)
+	}
+
+	egressNamespaces := make(map[string]bool)
+	if policies, err := t.listResource(ctx, networkPoliciesGVR, ns); err == nil {
+		for _, np := range policies.Items {
+			if pt, _, _ := unstructured.NestedStringSlice(np.Object, "spec", "policyTypes"); containsString(pt, "Egress") {
+				egressNamespaces[np.GetNamespace()] = true
+			}
+		}
+		paths = append(paths, networkPolicyPaths(policies.Items)...)
+	}
+	var cilium []unstructured.Unstructured
+	if list, err := t.listResource(ctx, ciliumNPGVR, ns); err == nil {
+		cilium = append(cilium, list.Items...)
+	}
+	if list, err := t.listResource(ctx, ciliumCNPGVR, ""); err == nil {
+		cilium = append(cilium, list.Items...)
+	}
+	for _, p := range cilium {
+		if p.GetNamespace() != "" {
+			egressNamespaces[p.GetNamespace()] = true
+		}
+	}
+	paths = append(paths, ciliumEgressPaths(cilium)...)
+
+	var findings []types.DiagnosticFinding
+	for _, e := range paths {
+		findings = append(findings, evaluateEgressPath(e)...)
+	}
+
+	if cms, err := t.listResource(ctx, configmapsGVR, istioRootNamespace); err == nil {
+		for _, cm := range cms.Items {
+			mesh, _, _ := unstructured.NestedString(cm.Object, "data", "mesh")
+			if !strings.HasPrefix(cm.GetName(), "istio") || mesh == "" || !istioOutboundAllowAny(mesh) {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeEgressMeshAllowAny,
+				Resource:   &types.ResourceRef{Kind: "ConfigMap", Namespace: istioRootNamespace, Name: cm.GetName(), APIVersion: "v1"},
+				Summary:    fmt.Sprintf("Istio outboundTrafficPolicy in %s is ALLOW_ANY: meshed pods can reach any external host, with or without a ServiceEntry", cm.GetName()),
+				Suggestion: "Set meshConfig.outboundTrafficPolicy.mode to REGISTRY_ONLY so only hosts declared in ServiceEntries are reachable.",
+			})
+		}
+	}
+
+	if nsList, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+		var open []string
+		for _, n := range nsList.Items {
+			if (ns == "" || n.GetName() == ns) && !egressNamespaces[n.GetName()] {
+				open = append(open, n.GetName())
+			}
+		}
+		sort.Strings(open)
+		if len(open) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryPolicy,
+				Summary:  fmt.Sprintf("%d namespace(s) have no egress NetworkPolicy or CiliumNetworkPolicy, so their pods can reach any destination", len(open)),
+				Detail:   truncateList(open, 20),
+			})
+		}
+	}
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("%d egress path(s) to external destinations found", len(paths)),
+	})
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, ""), nil
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestIsExternalCIDR(t *testing.T) {
+	cases := map[string]bool{
+		"0.0.0.0/0":      true,
+		"::/0":           true,
+		"52.94.0.0/16":   true,
+		"10.0.0.0/8":     false,
+		"192.168.1.0/24": false,
+		"fd00::/8":       false,
+	}
+	for cidr, want := range cases {
+		if got := isExternalCIDR(cidr); got != want {
+			t.Errorf("isExternalCIDR(%q) = %v, want %v", cidr, got, want)
+		}
+	}
+}
+
+func TestServiceEntryPaths(t *testing.T) {
+	entries := []unstructured.Unstructured{
+		*ipamObj("networking.istio.io/v1", "ServiceEntry", "shop", "db", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"hosts":    []interface{}{"db.example.com"},
+				"location": "MESH_EXTERNAL",
+				"ports": []interface{}{
+					map[string]interface{}{"number": int64(5432), "protocol": "TCP"},
+					map[string]interface{}{"number": int64(6379), "protocol": "TCP"},
+				},
+			},
+		}),
+		*ipamObj("networking.istio.io/v1", "ServiceEntry", "shop", "internal", map[string]interface{}{
+			"spec": map[string]interface{}{"hosts": []interface{}{"legacy.shop.svc"}, "location": "MESH_INTERNAL"},
+		}),
+	}
+	rules := []unstructured.Unstructured{
+		*ipamObj("networking.istio.io/v1", "DestinationRule", "shop", "db-tls", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"host": "db.example.com",
+				"trafficPolicy": map[string]interface{}{"portLevelSettings": []interface{}{
+					map[string]interface{}{"port": map[string]interface{}{"number": int64(5432)}, "tls": map[string]interface{}{"mode": "SIMPLE"}},
+				}},
+			},
+		}),
+	}
+
+	paths := serviceEntryPaths(entries, tlsOriginatingHosts(rules))
+	if len(paths) != 1 {
+		t.Fatalf("expected only the MESH_EXTERNAL entry, got %d paths", len(paths))
+	}
+	var codes []types.FindingCode
+	var summaries []string
+	for _, f := range evaluateEgressPath(paths[0]) {
+		if f.Code != "" {
+			codes = append(codes, f.Code)
+			summaries = append(summaries, f.Summary)
+		}
+	}
+	if len(codes) != 1 || codes[0] != types.CodeEgressPlaintextSensitivePort || !strings.Contains(summaries[0], "port 6379 (Redis)") {
+		t.Errorf("expected only the Redis port to be flagged, got %v %v", codes, summaries)
+	}
+}
+
+func TestAuditEgress(t *testing.T) {
+	objs := []runtime.Object{
+		ipamObj("v1", "Namespace", "", "shop", nil),
+		ipamObj("v1", "Namespace", "", "billing", nil),
+		ipamObj("v1", "Service", "shop", "payments", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"type":         "ExternalName",
+				"externalName": "api.payments.example.com",
+				"ports":        []interface{}{map[string]interface{}{"port": int64(443), "protocol": "TCP"}},
+			},
+		}),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "egress", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"policyTypes": []interface{}{"Egress"},
+				"egress": []interface{}{
+					map[string]interface{}{
+						"to":    []interface{}{map[string]interface{}{"ipBlock": map[string]interface{}{"cidr": "0.0.0.0/0", "except": []interface{}{"10.0.0.0/8"}}}},
+						"ports": []interface{}{map[string]interface{}{"port": int64(3306), "protocol": "TCP"}},
+					},
+					map[string]interface{}{
+						"to": []interface{}{map[string]interface{}{"namespaceSelector": map[string]interface{}{}}},
+					},
+				},
+			},
+		}),
+		ipamObj("v1", "ConfigMap", istioRootNamespace, "istio", map[string]interface{}{
+			"data": map[string]interface{}{"mesh": "outboundTrafficPolicy:\n  mode: ALLOW_ANY\n"},
+		}),
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		servicesGVR: "ServiceList", namespacesGVR: "NamespaceList", networkPoliciesGVR: "NetworkPolicyList",
+		configmapsGVR: "ConfigMapList", seV1GVR: "ServiceEntryList", seV1B1GVR: "ServiceEntryList",
+		drV1GVR: "DestinationRuleList", drV1B1GVR: "DestinationRuleList",
+		ciliumNPGVR: "CiliumNetworkPolicyList", ciliumCNPGVR: "CiliumClusterwideNetworkPolicyList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	cnp := ipamObj("cilium.io/v2", "CiliumNetworkPolicy", "billing", "fqdn", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"endpointSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "invoicer"}},
+			"egress": []interface{}{map[string]interface{}{
+				"toFQDNs": []interface{}{map[string]interface{}{"matchPattern": "*.amazonaws.com"}},
+				"toPorts": []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": "443", "protocol": "TCP"}}}},
+			}},
+		},
+	})
+	if err := client.Tracker().Create(ciliumNPGVR, cnp, "billing"); err != nil {
+		t.Fatal(err)
+	}
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+
+	resp, err := (&AuditEgressTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[types.FindingCode]int)
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		codes[f.Code]++
+		summaries = append(summaries, f.Summary)
+	}
+	joined := strings.Join(summaries, "\n")
+	if codes[types.CodeEgressWildcard] != 2 || codes[types.CodeEgressPlaintextSensitivePort] != 1 || codes[types.CodeEgressMeshAllowAny] != 1 {
+		t.Errorf("expected 2 wildcard, 1 plaintext and 1 ALLOW_ANY findings, got %v:\n%s", codes, joined)
+	}
+	for _, want := range []string{
+		"ExternalName Service shop/payments: pods resolving payments.shop -> api.payments.example.com (443/TCP)",
+		"pods app=web in shop -> 0.0.0.0/0 except 10.0.0.0/8",
+		"*.amazonaws.com (443/TCP)",
+		"3 egress path(s) to external destinations found",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "have no egress NetworkPolicy") {
+		t.Errorf("both namespaces have egress policies:\n%s", joined)
+	}
+}
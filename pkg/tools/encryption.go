@@ -0,0 +1,400 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	ciliumWireGuardKeyAnnotation = "network.cilium.io/wg-pub-key"
+	calicoWireGuardKeyAnnotation = "projectcalico.org/WireguardPublicKey"
+	// defaultNodeEncryptionOptOut is the Cilium default of node-encryption-opt-out-labels.
+	defaultNodeEncryptionOptOut = "node-role.kubernetes.io/control-plane"
+)
+
+// encryptionEnv is the cluster state transparent encryption is checked against.
+type encryptionEnv struct {
+	// ciliumConfig is the cilium-config ConfigMap data, nil when it was not found.
+	ciliumConfig map[string]string
+	// calico is set when the FelixConfiguration CRD is installed.
+	hasCalico    bool
+	felixConfigs []unstructured.Unstructured
+	nodes        []unstructured.Unstructured
+	ciliumNodes  map[string]*unstructured.Unstructured
+}
+
+// nodeKey is the encryption key a node advertises to its peers: a
+// WireGuard public key or an IPsec key index.
+type nodeKey struct {
+	node, key string
+}
+
+// cilium checks Cilium WireGuard or IPsec encryption.
+func (e *encryptionEnv) cilium() []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: "kube-system", Name: "cilium-config"}
+	mode := ""
+	switch {
+	case e.ciliumConfig["enable-wireguard"] == "true":
+		mode = "WireGuard"
+	case e.ciliumConfig["enable-ipsec"] == "true":
+		mode = "IPsec"
+	}
+	if mode == "" {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIEncryptionDisabled,
+			Resource:   ref,
+			Summary:    "Cilium transparent encryption is disabled: pod traffic between nodes is not encrypted",
+			Detail:     "Neither enable-wireguard nor enable-ipsec is true",
+			Suggestion: "Set encryption.enabled=true and encryption.type=wireguard in the Cilium Helm values, or rely on mesh mTLS for east-west traffic",
+		}}
+	}
+
+	var keys []nodeKey
+	var missing []string
+	for _, n := range e.nodes {
+		key := ""
+		cn := e.ciliumNodes[n.GetName()]
+		if mode == "WireGuard" {
+			key = n.GetAnnotations()[ciliumWireGuardKeyAnnotation]
+			if key == "" && cn != nil {
+				key = cn.GetAnnotations()[ciliumWireGuardKeyAnnotation]
+			}
+		} else if cn != nil {
+			if idx, _, _ := unstructured.NestedInt64(cn.Object, "spec", "encryption", "key"); idx > 0 {
+				key = strconv.FormatInt(idx, 10)
+			}
+		}
+		if key == "" {
+			missing = append(missing, n.GetName())
+			continue
+		}
+		keys = append(keys, nodeKey{node: n.GetName(), key: key})
+	}
+
+	findings := []types.DiagnosticFinding{encryptionSummary("Cilium", mode, len(keys), len(e.nodes))}
+	if len(missing) > 0 {
+		consequence := "traffic to and from them is sent unencrypted"
+		if e.ciliumConfig["enable-encryption-strict-mode"] == "true" {
+			consequence = "strict mode drops pod traffic to and from them"
+		}
+		what := "no WireGuard public key (" + ciliumWireGuardKeyAnnotation + ")"
+		if mode == "IPsec" {
+			what = "no IPsec key index in their CiliumNode"
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNINodeNotEncrypted,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d nodes have %s: %s", len(missing), what, consequence),
+			Detail:     "Nodes: " + truncateList(missing, 10),
+			Suggestion: "Check the cilium agent on these nodes; it publishes the key once encryption is set up",
+		})
+	}
+	if mode == "IPsec" {
+		findings = append(findings, ipsecRotationFindings(keys, ref)...)
+	} else {
+		findings = append(findings, duplicateKeyFindings("Cilium", keys, ref)...)
+	}
+	findings = append(findings, peerCountFinding(mode, keys, mode == "IPsec"))
+
+	if e.ciliumConfig["encrypt-node"] != "true" {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Resource:   ref,
+			Summary:    "Node-to-node encryption is off: only pod traffic is encrypted, host-network pods and node traffic are not",
+			Suggestion: "Set encryption.nodeEncryption=true in the Cilium Helm values to also encrypt host traffic",
+		})
+		return findings
+	}
+	optOut, ok := e.ciliumConfig["node-encryption-opt-out-labels"]
+	if !ok {
+		optOut = defaultNodeEncryptionOptOut
+	}
+	selector, err := labels.Parse(optOut)
+	if err != nil || optOut == "" {
+		return findings
+	}
+	var excluded []string
+	for _, n := range e.nodes {
+		if selector.Matches(labels.Set(n.GetLabels())) {
+			excluded = append(excluded, n.GetName())
+		}
+	}
+	if len(excluded) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeCNINodeNotEncrypted,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%d nodes are excluded from node-to-node encryption by node-encryption-opt-out-labels %q", len(excluded), optOut),
+			Detail:   "Nodes: " + truncateList(excluded, 10) + ". Host traffic of these nodes is not encrypted; their pod traffic still is",
+		})
+	}
+	return findings
+}
+
+// calico checks Calico WireGuard encryption, honouring per-node
+// FelixConfiguration overrides (node.<name>).
+func (e *encryptionEnv) calico() []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "FelixConfiguration", Name: "default", APIVersion: "crd.projectcalico.org/v1"}
+	enabled, hostEncryption := false, false
+	overrides := make(map[string]bool)
+	for _, fc := range e.felixConfigs {
+		wg, found, _ := unstructured.NestedBool(fc.Object, "spec", "wireguardEnabled")
+		switch {
+		case fc.GetName() == "default":
+			enabled = wg
+			hostEncryption, _, _ = unstructured.NestedBool(fc.Object, "spec", "wireguardHostEncryptionEnabled")
+		case found && strings.HasPrefix(fc.GetName(), "node."):
+			overrides[strings.TrimPrefix(fc.GetName(), "node.")] = wg
+		}
+	}
+	anyEnabled := enabled
+	for _, wg := range overrides {
+		anyEnabled = anyEnabled || wg
+	}
+	if !anyEnabled {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIEncryptionDisabled,
+			Resource:   ref,
+			Summary:    "Calico WireGuard encryption is disabled: pod traffic between nodes is not encrypted",
+			Detail:     "wireguardEnabled is not true in the default FelixConfiguration",
+			Suggestion: "Set spec.wireguardEnabled=true in the default FelixConfiguration, or rely on mesh mTLS for east-west traffic",
+		}}
+	}
+
+	var keys []nodeKey
+	var missing, excluded []string
+	for _, n := range e.nodes {
+		nodeEnabled, overridden := overrides[n.GetName()]
+		if !overridden {
+			nodeEnabled = enabled
+		}
+		if !nodeEnabled {
+			excluded = append(excluded, n.GetName())
+			continue
+		}
+		if key := n.GetAnnotations()[calicoWireGuardKeyAnnotation]; key != "" {
+			keys = append(keys, nodeKey{node: n.GetName(), key: key})
+		} else {
+			missing = append(missing, n.GetName())
+		}
+	}
+
+	findings := []types.DiagnosticFinding{encryptionSummary("Calico", "WireGuard", len(keys), len(e.nodes))}
+	if len(missing) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNINodeNotEncrypted,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d nodes have no WireGuard public key (%s): traffic to and from them is sent unencrypted", len(missing), calicoWireGuardKeyAnnotation),
+			Detail:     "Nodes: " + truncateList(missing, 10),
+			Suggestion: "Check calico-node on these nodes: the WireGuard kernel module must be available and Felix publishes the key once the interface is up",
+		})
+	}
+	if len(excluded) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNINodeNotEncrypted,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d nodes have WireGuard disabled by their FelixConfiguration", len(excluded)),
+			Detail:     "Nodes: " + truncateList(excluded, 10),
+			Suggestion: "Remove wireguardEnabled=false from the node.<name> FelixConfigurations unless these nodes are meant to send plaintext",
+		})
+	}
+	findings = append(findings, duplicateKeyFindings("Calico", keys, ref)...)
+	findings = append(findings, peerCountFinding("WireGuard", keys, false))
+	if !hostEncryption {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Resource:   ref,
+			Summary:    "Host traffic encryption is off: only pod traffic is encrypted, host-network pods and node traffic are not",
+			Suggestion: "Set spec.wireguardHostEncryptionEnabled=true in the default FelixConfiguration to also encrypt host traffic",
+		})
+	}
+	return findings
+}
+
+// encryptionSummary reports how many nodes take part in encryption.
+func encryptionSummary(provider, mode string, keyed, total int) types.DiagnosticFinding {
+	severity := types.SeverityOK
+	if keyed < total {
+		severity = types.SeverityInfo
+	}
+	return types.DiagnosticFinding{
+		Severity: severity,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("%s %s encryption is enabled on %d of %d nodes", provider, mode, keyed, total),
+	}
+}
+
+// duplicateKeyFindings reports WireGuard public keys advertised by more than
+// one node, e.g. nodes cloned from an image with a baked-in private key:
+// peers cannot tell them apart and drop their traffic.
+func duplicateKeyFindings(provider string, keys []nodeKey, ref *types.ResourceRef) []types.DiagnosticFinding {
+	byKey, sorted := nodesByKey(keys)
+	var findings []types.DiagnosticFinding
+	for _, key := range sorted {
+		if nodes := byKey[key]; len(nodes) > 1 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIEncryptionKeyMismatch,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%d nodes advertise the same %s WireGuard public key", len(nodes), provider),
+				Detail:     "Nodes: " + truncateList(nodes, 10),
+				Suggestion: "Delete the WireGuard interface on these nodes and restart the CNI agent so each generates its own key",
+			})
+		}
+	}
+	return findings
+}
+
+// ipsecRotationFindings reports nodes on different IPsec key indices. During
+// a rotation agents move to the new key one by one; nodes left on an old
+// index drop traffic from peers that no longer hold it.
+func ipsecRotationFindings(keys []nodeKey, ref *types.ResourceRef) []types.DiagnosticFinding {
+	byKey, indices := nodesByKey(keys)
+	if len(indices) < 2 {
+		return nil
+	}
+	sort.Slice(indices, func(i, j int) bool { return len(byKey[indices[i]]) > len(byKey[indices[j]]) })
+	var parts []string
+	for _, idx := range indices {
+		parts = append(parts, fmt.Sprintf("key %s on %d nodes (%s)", idx, len(byKey[idx]), truncateList(byKey[idx], 3)))
+	}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryConnectivity,
+		Code:       types.CodeCNIEncryptionKeyMismatch,
+		Resource:   ref,
+		Summary:    fmt.Sprintf("IPsec key rotation in progress or stuck: nodes use %d key indices", len(byKey)),
+		Detail:     strings.Join(parts, "; "),
+		Suggestion: "If this persists after the rotation window, restart the cilium agents still on the old key index; XfrmInNoStates drops in cilium-dbg metrics confirm it",
+	}}
+}
+
+// peerCountFinding reports how many encrypted peers each node has: every
+// other node with a key, or for IPsec every other node on the same key index.
+func peerCountFinding(mode string, keys []nodeKey, byIndex bool) types.DiagnosticFinding {
+	groups := make(map[string]int)
+	for _, k := range keys {
+		if byIndex {
+			groups[k.key]++
+		} else {
+			groups[""]++
+		}
+	}
+	var parts []string
+	for _, k := range keys {
+		group := ""
+		if byIndex {
+			group = k.key
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", k.node, groups[group]-1))
+	}
+	sort.Strings(parts)
+	return types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("%s peers per node", mode),
+		Detail:   truncateList(parts, 20),
+	}
+}
+
+// nodesByKey groups nodes by the key they advertise and returns the sorted keys.
+func nodesByKey(keys []nodeKey) (map[string][]string, []string) {
+	byKey := make(map[string][]string)
+	for _, k := range keys {
+		byKey[k.key] = append(byKey[k.key], k.node)
+	}
+	sorted := make([]string, 0, len(byKey))
+	for k := range byKey {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	return byKey, sorted
+}
+
+// --- check_pod_encryption ---
+
+type CheckPodEncryptionTool struct{ BaseTool }
+
+func (t *CheckPodEncryptionTool) Name() string { return "check_pod_encryption" }
+func (t *CheckPodEncryptionTool) Description() string {
+	return "Check Cilium (WireGuard, IPsec) and Calico (WireGuard) transparent encryption: whether node-to-node traffic is encrypted, nodes without a key or excluded from encryption, duplicate WireGuard keys, IPsec key rotation status and per-node peer counts"
+}
+func (t *CheckPodEncryptionTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *CheckPodEncryptionTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	env := &encryptionEnv{ciliumNodes: make(map[string]*unstructured.Unstructured)}
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace("kube-system").Get(ctx, "cilium-config", metav1.GetOptions{}); err == nil {
+		env.ciliumConfig, _, _ = unstructured.NestedStringMap(cm.Object, "data")
+		if env.ciliumConfig == nil {
+			env.ciliumConfig = map[string]string{}
+		}
+	}
+	if list, err := t.listUnrecorded(ctx, calicoFelixConfigGVR, ""); err == nil {
+		env.hasCalico, env.felixConfigs = true, list.Items
+	}
+	if env.ciliumConfig == nil && !env.hasCalico {
+		findings := []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  "no Cilium or Calico installation found; transparent encryption cannot be checked",
+			Detail:   "Pod traffic between nodes is only encrypted when the workloads use TLS or mesh mTLS",
+		}}
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+	}
+
+	nodes, err := t.listResource(ctx, nodesGVR, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to list nodes",
+			Detail:  err.Error(),
+			Cause:   err,
+		}
+	}
+	env.nodes = nodes.Items
+	if env.ciliumConfig != nil {
+		if list, err := t.listUnrecorded(ctx, ciliumNodesGVR, ""); err == nil {
+			for i := range list.Items {
+				env.ciliumNodes[list.Items[i].GetName()] = &list.Items[i]
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	if env.ciliumConfig != nil {
+		findings = append(findings, env.cilium()...)
+	}
+	if env.hasCalico {
+		findings = append(findings, env.calico()...)
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func encryptionNode(name string, lbls, annotations map[string]string) unstructured.Unstructured {
+	n := ipamObj("v1", "Node", "", name, map[string]interface{}{})
+	n.SetLabels(lbls)
+	n.SetAnnotations(annotations)
+	return *n
+}
+
+func TestCiliumWireGuardEncryption(t *testing.T) {
+	env := &encryptionEnv{
+		ciliumConfig: map[string]string{"enable-wireguard": "true", "encrypt-node": "true"},
+		nodes: []unstructured.Unstructured{
+			encryptionNode("cp", map[string]string{"node-role.kubernetes.io/control-plane": ""}, map[string]string{ciliumWireGuardKeyAnnotation: "a"}),
+			encryptionNode("w1", nil, map[string]string{ciliumWireGuardKeyAnnotation: "b"}),
+			encryptionNode("w2", nil, map[string]string{ciliumWireGuardKeyAnnotation: "b"}),
+			encryptionNode("w3", nil, nil),
+		},
+	}
+	got := ciliumCodes(env.cilium())
+	for _, want := range []string{
+		"info Cilium WireGuard encryption is enabled on 3 of 4 nodes ",
+		"critical 1 nodes have no WireGuard public key (network.cilium.io/wg-pub-key): traffic to and from them is sent unencrypted CNI025_NODE_NOT_ENCRYPTED",
+		"critical 2 nodes advertise the same Cilium WireGuard public key CNI026_ENCRYPTION_KEY_MISMATCH",
+		"info WireGuard peers per node ",
+		`info 1 nodes are excluded from node-to-node encryption by node-encryption-opt-out-labels "node-role.kubernetes.io/control-plane" CNI025_NODE_NOT_ENCRYPTED`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	env.ciliumConfig = map[string]string{"enable-wireguard": "false"}
+	if got := ciliumCodes(env.cilium()); !strings.Contains(got, "warning Cilium transparent encryption is disabled") {
+		t.Errorf("disabled encryption not reported:\n%s", got)
+	}
+}
+
+func TestCiliumIPsecRotation(t *testing.T) {
+	ciliumNode := func(name string, key int64) *unstructured.Unstructured {
+		return ipamObj("cilium.io/v2", "CiliumNode", "", name, map[string]interface{}{
+			"spec": map[string]interface{}{"encryption": map[string]interface{}{"key": key}},
+		})
+	}
+	env := &encryptionEnv{
+		ciliumConfig: map[string]string{"enable-ipsec": "true"},
+		nodes:        []unstructured.Unstructured{encryptionNode("a", nil, nil), encryptionNode("b", nil, nil), encryptionNode("c", nil, nil)},
+		ciliumNodes:  map[string]*unstructured.Unstructured{"a": ciliumNode("a", 4), "b": ciliumNode("b", 4), "c": ciliumNode("c", 3)},
+	}
+	findings := env.cilium()
+	got := ciliumCodes(findings)
+	if want := "warning IPsec key rotation in progress or stuck: nodes use 2 key indices CNI026_ENCRYPTION_KEY_MISMATCH"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in:\n%s", want, got)
+	}
+	for _, f := range findings {
+		if f.Summary == "IPsec peers per node" && f.Detail != "a=1, b=1, c=0" {
+			t.Errorf("peers = %q, want a=1, b=1, c=0", f.Detail)
+		}
+		if strings.HasPrefix(f.Summary, "IPsec key rotation") && f.Detail != "key 4 on 2 nodes (a, b); key 3 on 1 nodes (c)" {
+			t.Errorf("rotation detail = %q", f.Detail)
+		}
+	}
+	if !strings.Contains(got, "info Node-to-node encryption is off") {
+		t.Errorf("node encryption not reported:\n%s", got)
+	}
+}
+
+func TestCalicoWireGuardEncryption(t *testing.T) {
+	felix := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return *ipamObj("crd.projectcalico.org/v1", "FelixConfiguration", "", name, map[string]interface{}{"spec": spec})
+	}
+	env := &encryptionEnv{
+		hasCalico: true,
+		felixConfigs: []unstructured.Unstructured{
+			felix("default", map[string]interface{}{"wireguardEnabled": true, "wireguardHostEncryptionEnabled": true}),
+			felix("node.legacy", map[string]interface{}{"wireguardEnabled": false}),
+		},
+		nodes: []unstructured.Unstructured{
+			encryptionNode("a", nil, map[string]string{calicoWireGuardKeyAnnotation: "ka"}),
+			encryptionNode("b", nil, nil),
+			encryptionNode("legacy", nil, nil),
+		},
+	}
+	got := ciliumCodes(env.calico())
+	for _, want := range []string{
+		"info Calico WireGuard encryption is enabled on 1 of 3 nodes ",
+		"critical 1 nodes have no WireGuard public key (projectcalico.org/WireguardPublicKey): traffic to and from them is sent unencrypted CNI025_NODE_NOT_ENCRYPTED",
+		"warning 1 nodes have WireGuard disabled by their FelixConfiguration CNI025_NODE_NOT_ENCRYPTED",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Host traffic encryption is off") {
+		t.Errorf("host encryption reported off:\n%s", got)
+	}
+
+	env.felixConfigs = nil
+	if got := ciliumCodes(env.calico()); !strings.Contains(got, "warning Calico WireGuard encryption is disabled") {
+		t.Errorf("disabled encryption not reported:\n%s", got)
+	}
+}
@@ -0,0 +1,287 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// accessLogEntry is the part of an Envoy access log entry that explains a
+// failed request.
+type accessLogEntry struct {
+	code     int
+	flags    string // "-" when none
+	upstream string // upstream cluster, else host; "-" when none was selected
+	duration int    // milliseconds, -1 when not logged
+}
+
+// splitAccessLog splits a text access log line into fields: [bracketed],
+// "quoted" and bare tokens, without the brackets and quotes. quoted reports
+// which fields were quoted.
+func splitAccessLog(line string) (fields []string, quoted []bool) {
+	for i := 0; i < len(line); {
+		switch line[i] {
+		case ' ', '\t':
+			i++
+			continue
+		case '"', '[':
+			closing := byte('"')
+			if line[i] == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(line[i+1:], closing)
+			if end < 0 {
+				return nil, nil
+			}
+			fields = append(fields, line[i+1:i+1+end])
+			quoted = append(quoted, line[i] == '"')
+			i += end + 2
+		default:
+			end := strings.IndexAny(line[i:], " \t")
+			if end < 0 {
+				end = len(line) - i
+			}
+			fields = append(fields, line[i:i+end])
+			quoted = append(quoted, false)
+			i += end
+		}
+	}
+	return fields, quoted
+}
+
+// parseEnvoyAccessLog parses a line of Envoy's default access log format,
+// Istio's default format (which adds response code details, termination
+// details and the upstream cluster) or a JSON access log. ok is false for
+// any other line.
+func parseEnvoyAccessLog(line string) (accessLogEntry, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		return parseJSONAccessLog(line)
+	}
+	if !strings.HasPrefix(line, "[") {
+		return accessLogEntry{}, false
+	}
+	fields, quoted := splitAccessLog(line)
+	// [start time] "METHOD PATH PROTOCOL" code flags ...
+	if len(fields) < 6 || !quoted[1] || len(strings.Fields(fields[1])) != 3 {
+		return accessLogEntry{}, false
+	}
+	code, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return accessLogEntry{}, false
+	}
+	e := accessLogEntry{code: code, flags: fields[3], upstream: "-", duration: -1}
+
+	// Bytes received, bytes sent and duration are the first three bare
+	// integers after the flags; Istio logs code details before them.
+	i := 4
+	for ; i+2 < len(fields); i++ {
+		if isAccessLogInt(fields[i], quoted[i]) && isAccessLogInt(fields[i+1], quoted[i+1]) && isAccessLogInt(fields[i+2], quoted[i+2]) {
+			break
+		}
+	}
+	if i+2 >= len(fields) {
+		return accessLogEntry{}, false
+	}
+	e.duration, _ = strconv.Atoi(fields[i+2])
+
+	// Then come the upstream service time and the quoted forwarded-for,
+	// user agent, request ID, authority and upstream host; Istio follows
+	// with the bare upstream cluster.
+	var after []int
+	for j := i + 3; j < len(fields); j++ {
+		if quoted[j] {
+			after = append(after, j)
+		}
+	}
+	if len(after) >= 5 {
+		host := after[4]
+		e.upstream = fields[host]
+		if host+1 < len(fields) && !quoted[host+1] && fields[host+1] != "-" {
+			e.upstream = fields[host+1]
+		}
+	}
+	return e, true
+}
+
+func isAccessLogInt(field string, quoted bool) bool {
+	if quoted {
+		return false
+	}
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
+
+// parseJSONAccessLog parses a JSON access log line with Envoy's or Istio's
+// field names.
+func parseJSONAccessLog(line string) (accessLogEntry, bool) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return accessLogEntry{}, false
+	}
+	code, ok := jsonAccessLogInt(raw["response_code"])
+	if !ok {
+		return accessLogEntry{}, false
+	}
+	e := accessLogEntry{code: code, flags: "-", upstream: "-", duration: -1}
+	if flags, _ := raw["response_flags"].(string); flags != "" {
+		e.flags = flags
+	}
+	for _, key := range []string{"upstream_cluster", "upstream_host"} {
+		if v, _ := raw[key].(string); v != "" && v != "-" {
+			e.upstream = v
+			break
+		}
+	}
+	if d, ok := jsonAccessLogInt(raw["duration"]); ok {
+		e.duration = d
+	}
+	return e, true
+}
+
+// jsonAccessLogInt reads a number that JSON access logs may write as a
+// number or a string.
+func jsonAccessLogInt(v interface{}) (int, bool) {
+	switch x := v.(type) {
+	case float64:
+		return int(x), true
+	case string:
+		n, err := strconv.Atoi(x)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// accessLogStats aggregates the access log entries of one container.
+type accessLogStats struct {
+	total  int
+	failed map[int][]accessLogEntry // by response code
+}
+
+func (s *accessLogStats) add(e accessLogEntry) {
+	s.total++
+	// Requests that failed, or that Envoy flagged, e.g. 404 NR or 0 DC
+	if e.code < 500 && e.flags == "-" {
+		return
+	}
+	if s.failed == nil {
+		s.failed = make(map[int][]accessLogEntry)
+	}
+	s.failed[e.code] = append(s.failed[e.code], e)
+}
+
+// accessLogCause groups failed entries by response flags and upstream.
+type accessLogCause struct {
+	flags, upstream string
+	count           int
+	durations       []int
+}
+
+func (c accessLogCause) describe() string {
+	flags := "no response flag"
+	if c.flags != "-" {
+		flags = "flag " + c.flags
+	}
+	if c.upstream == "-" {
+		return flags + " with no upstream selected"
+	}
+	return fmt.Sprintf("%s to backend %s", flags, c.upstream)
+}
+
+// p95 returns the 95th percentile duration in milliseconds, or -1.
+func (c accessLogCause) p95() int {
+	if len(c.durations) == 0 {
+		return -1
+	}
+	d := append([]int(nil), c.durations...)
+	sort.Ints(d)
+	return d[(len(d)*95+99)/100-1]
+}
+
+// findings reports, per response code, the share of requests carrying the
+// most common response flags and upstream, with what the flags usually mean.
+func (s *accessLogStats) findings(ref *types.ResourceRef, source string) []types.DiagnosticFinding {
+	if s.total == 0 || len(s.failed) == 0 {
+		return nil
+	}
+	codes := make([]int, 0, len(s.failed))
+	for code := range s.failed {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if len(s.failed[codes[i]]) != len(s.failed[codes[j]]) {
+			return len(s.failed[codes[i]]) > len(s.failed[codes[j]])
+		}
+		return codes[i] < codes[j]
+	})
+
+	var findings []types.DiagnosticFinding
+	for _, code := range codes {
+		entries := s.failed[code]
+		byCause := make(map[[2]string]*accessLogCause)
+		var causes []*accessLogCause
+		for _, e := range entries {
+			key := [2]string{e.flags, e.upstream}
+			c := byCause[key]
+			if c == nil {
+				c = &accessLogCause{flags: e.flags, upstream: e.upstream}
+				byCause[key] = c
+				causes = append(causes, c)
+			}
+			c.count++
+			if e.duration >= 0 {
+				c.durations = append(c.durations, e.duration)
+			}
+		}
+		sort.SliceStable(causes, func(i, j int) bool { return causes[i].count > causes[j].count })
+
+		top := causes[0]
+		summary := fmt.Sprintf("%d%% of %ds (%d of %d) carry %s", top.count*100/len(entries), code, top.count, len(entries), top.describe())
+		if len(causes) == 1 {
+			summary = fmt.Sprintf("All %d %ds carry %s", len(entries), code, top.describe())
+		}
+		summary += fmt.Sprintf(" in %s (%d access log entries)", source, s.total)
+
+		var detail []string
+		for _, c := range causes {
+			line := fmt.Sprintf("%d with %s", c.count, c.describe())
+			if p := c.p95(); p >= 0 {
+				line += fmt.Sprintf(", p95 duration %dms", p)
+			}
+			detail = append(detail, line)
+		}
+
+		severity := types.SeverityInfo
+		if code >= 500 {
+			severity = types.SeverityWarning
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsAccessLogFailures,
+			Resource:   ref,
+			Summary:    summary,
+			Detail:     strings.Join(detail, "\n"),
+			Suggestion: accessLogSuggestion(top.flags),
+		})
+	}
+	return findings
+}
+
+// accessLogSuggestion explains the response flags of the most common cause.
+func accessLogSuggestion(flags string) string {
+	var causes []string
+	for _, f := range strings.Split(flags, ",") {
+		if c, ok := responseFlagCauses[f]; ok {
+			causes = append(causes, f+" = "+c)
+		}
+	}
+	if len(causes) == 0 {
+		return "Without a response flag the status comes from the upstream application: check its logs."
+	}
+	return strings.Join(causes, "; ")
+}
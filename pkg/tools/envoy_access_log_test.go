@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseEnvoyAccessLog(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want accessLogEntry
+		ok   bool
+	}{
+		{
+			name: "envoy default",
+			line: `[2024-05-01T10:00:00.000Z] "GET /cart HTTP/1.1" 503 UF 0 91 3 - "-" "curl/8.0" "a1b2" "shop.example.com" "10.0.1.7:8080"`,
+			want: accessLogEntry{code: 503, flags: "UF", upstream: "10.0.1.7:8080", duration: 3},
+			ok:   true,
+		},
+		{
+			name: "istio default",
+			line: `[2024-05-01T10:00:00.000Z] "GET /reviews/0 HTTP/1.1" 503 UF upstream_reset_before_response_started{connection_failure} - "-" 0 91 2 - "-" "curl/8.0" "a1b2" "reviews:9080" "10.0.1.9:9080" outbound|9080||reviews.default.svc.cluster.local - 10.96.0.5:9080 10.0.1.4:50932 - default`,
+			want: accessLogEntry{code: 503, flags: "UF", upstream: "outbound|9080||reviews.default.svc.cluster.local", duration: 2},
+			ok:   true,
+		},
+		{
+			name: "no route",
+			line: `[2024-05-01T10:00:00.000Z] "GET /missing HTTP/1.1" 404 NR route_not_found - "-" 0 0 0 - "-" "curl/8.0" "a1b2" "shop.example.com" "-" - - 10.0.1.4:8080 10.0.1.2:40000 - -`,
+			want: accessLogEntry{code: 404, flags: "NR", upstream: "-", duration: 0},
+			ok:   true,
+		},
+		{
+			name: "json",
+			line: `{"response_code":504,"response_flags":"UT","upstream_cluster":"outbound|80||api.shop.svc.cluster.local","duration":15001}`,
+			want: accessLogEntry{code: 504, flags: "UT", upstream: "outbound|80||api.shop.svc.cluster.local", duration: 15001},
+			ok:   true,
+		},
+		{name: "application log", line: `[info] 503 upstream unavailable`},
+		{name: "json application log", line: `{"level":"error","msg":"503"}`},
+	}
+	for _, tt := range tests {
+		got, ok := parseEnvoyAccessLog(tt.line)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s: got %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAccessLogFindings(t *testing.T) {
+	var s accessLogStats
+	for i := 0; i < 10; i++ {
+		s.add(accessLogEntry{code: 200, flags: "-", upstream: "backend-a", duration: 5})
+	}
+	for i := 0; i < 9; i++ {
+		s.add(accessLogEntry{code: 503, flags: "UF", upstream: "backend-a", duration: 2})
+	}
+	s.add(accessLogEntry{code: 503, flags: "UH", upstream: "-", duration: 0})
+	s.add(accessLogEntry{code: 404, flags: "NR", upstream: "-", duration: 0})
+
+	findings := s.findings(&types.ResourceRef{Kind: "Pod", Namespace: "shop", Name: "web-1"}, "shop/web-1 container istio-proxy")
+	if len(findings) != 2 {
+		t.Fatalf("findings = %+v", findings)
+	}
+	f := findings[0]
+	if want := "90% of 503s (9 of 10) carry flag UF to backend backend-a in shop/web-1 container istio-proxy (21 access log entries)"; f.Summary != want {
+		t.Errorf("summary:\ngot  %s\nwant %s", f.Summary, want)
+	}
+	if f.Severity != types.SeverityWarning || f.Code != types.CodeLogsAccessLogFailures || !strings.Contains(f.Suggestion, "mTLS") {
+		t.Errorf("finding = %+v", f)
+	}
+	if !strings.Contains(f.Detail, "1 with flag UH with no upstream selected") {
+		t.Errorf("detail = %q", f.Detail)
+	}
+	if f := findings[1]; f.Severity != types.SeverityInfo || !strings.HasPrefix(f.Summary, "All 1 404s carry flag NR") {
+		t.Errorf("404 finding = %+v", f)
+	}
+}
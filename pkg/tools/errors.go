@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// forbiddenRe matches the RBAC denial in a Forbidden message, e.g.
+// `cannot list resource "pods" in API group "" in the namespace "default"`.
+var forbiddenRe = regexp.MustCompile(`cannot (\S+) resource "([^"]+)" in API group "([^"]*)"(?: in the namespace "([^"]+)")?`)
+
+// ClassifyError wraps err, a plain error returned by a tool, in an MCPError
+// whose code tells the agent whether to retry, fix permissions or change
+// approach. MCPErrors are returned unchanged, except an INTERNAL_ERROR whose
+// cause classifies as something else, such as a Forbidden API error.
+func ClassifyError(tool string, err error) *types.MCPError {
+	var mcpErr *types.MCPError
+	if errors.As(err, &mcpErr) {
+		if mcpErr.Code != types.ErrCodeInternalError || mcpErr.Cause == nil {
+			return mcpErr
+		}
+		cause := classifyPlain(mcpErr.Tool, mcpErr.Cause)
+		if cause.Code == types.ErrCodeInternalError {
+			return mcpErr
+		}
+		e := *mcpErr
+		e.Code, e.RequiredPermission = cause.Code, cause.RequiredPermission
+		if cause.Detail != "" {
+			e.Detail = strings.TrimSpace(e.Detail + "\n" + cause.Detail)
+		}
+		return &e
+	}
+	return classifyPlain(tool, err)
+}
+
+// classifyPlain classifies err, which is not an MCPError.
+func classifyPlain(tool string, err error) *types.MCPError {
+	e := &types.MCPError{Code: types.ErrCodeInternalError, Tool: tool, Message: err.Error()}
+	var netErr net.Error
+	switch {
+	case apierrors.IsForbidden(err):
+		e.Code = types.ErrCodeForbidden
+		if p, ok := forbiddenPermission(err); ok {
+			e.RequiredPermission = p.String()
+		}
+		e.Detail = "Run check_permissions for the ClusterRole rules to add."
+	case apierrors.IsUnauthorized(err):
+		e.Code = types.ErrCodeAuthFailed
+	case apierrors.IsNotFound(err):
+		// A resource type the API server does not serve has no name in
+		// the status details, unlike a missing object.
+		var status apierrors.APIStatus
+		if errors.As(err, &status) && (status.Status().Details == nil || status.Status().Details.Name == "") {
+			e.Code = types.ErrCodeCRDNotAvailable
+		} else {
+			e.Code = types.ErrCodeNotFound
+		}
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err), apierrors.IsResourceExpired(err):
+		e.Code = types.ErrCodeInvalidInput
+	case apierrors.IsTooManyRequests(err), apierrors.IsServerTimeout(err), apierrors.IsTimeout(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsUnexpectedServerError(err):
+		e.Code = types.ErrCodeAPIUnavailable
+	case errors.As(err, &netErr):
+		e.Code = types.ErrCodeAPIUnavailable
+	}
+	return e
+}
+
+// forbiddenPermission returns the permission a Forbidden error was denied.
+func forbiddenPermission(err error) (Permission, bool) {
+	m := forbiddenRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return Permission{}, false
+	}
+	resource, subresource, _ := strings.Cut(m[2], "/")
+	return Permission{Verb: m[1], Group: m[3], Resource: resource, Subresource: subresource, Namespace: m[4]}, true
+}
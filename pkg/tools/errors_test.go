@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestClassifyError(t *testing.T) {
+	netpols := schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}
+	forbidden := apierrors.NewForbidden(netpols, "", errors.New(`User "system:serviceaccount:mcp:mcp" cannot list resource "networkpolicies" in API group "networking.k8s.io" in the namespace "default"`))
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	for _, tc := range []struct {
+		err        error
+		code       string
+		permission string
+		retryable  bool
+	}{
+		{fmt.Errorf("failed to list networkpolicies: %w", forbidden), types.ErrCodeForbidden, "list networkpolicies.networking.k8s.io in default", false},
+		{apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "web", errors.New(`User "alice" cannot get resource "pods/log" in API group "" in the namespace "shop"`)), types.ErrCodeForbidden, "get pods/log in shop", false},
+		{apierrors.NewNotFound(schema.GroupResource{Resource: "services"}, "web"), types.ErrCodeNotFound, "", false},
+		{apierrors.NewGenericServerResponse(404, "list", schema.GroupResource{Group: "gateway.networking.k8s.io", Resource: "httproutes"}, "", "", 0, false), types.ErrCodeCRDNotAvailable, "", false},
+		{apierrors.NewTooManyRequests("throttled", 1), types.ErrCodeAPIUnavailable, "", true},
+		{apierrors.NewInternalError(errors.New("etcd unavailable")), types.ErrCodeAPIUnavailable, "", true},
+		{fmt.Errorf("failed to list pods: %w", refused), types.ErrCodeAPIUnavailable, "", true},
+		{apierrors.NewBadRequest("bad selector"), types.ErrCodeInvalidInput, "", false},
+		{errors.New("unexpected"), types.ErrCodeInternalError, "", false},
+		{&types.MCPError{Code: types.ErrCodeProbeRateLimited}, types.ErrCodeProbeRateLimited, "", true},
+	} {
+		got := ClassifyError("list_networkpolicies", tc.err)
+		if got.Code != tc.code || got.RequiredPermission != tc.permission {
+			t.Errorf("%v: code %s, permission %q, want %s, %q", tc.err, got.Code, got.RequiredPermission, tc.code, tc.permission)
+		}
+		data, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf(`"retryable":%t`, tc.retryable); !strings.Contains(string(data), want) {
+			t.Errorf("%v: %s lacks %s", tc.err, data, want)
+		}
+	}
+}
+
+func TestClassifyErrorReclassifiesInternalErrorCause(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", errors.New(`User "system:serviceaccount:mcp:mcp" cannot list resource "deployments" in API group "apps" at the cluster scope`))
+	wrapped := &types.MCPError{Code: types.ErrCodeInternalError, Tool: "check_istiod_health", Message: "failed to list deployments", Detail: forbidden.Error(), Cause: forbidden}
+
+	got := ClassifyError("check_istiod_health", wrapped)
+	if got.Code != types.ErrCodeForbidden || got.RequiredPermission != "list deployments.apps" || got.Message != wrapped.Message {
+		t.Errorf("got %s %q %q, want FORBIDDEN with the permission and the tool's message", got.Code, got.RequiredPermission, got.Message)
+	}
+	if wrapped.Code != types.ErrCodeInternalError {
+		t.Error("the tool's error was modified")
+	}
+
+	unavailable := &types.MCPError{Code: types.ErrCodeInternalError, Message: "failed to list nodes", Cause: apierrors.NewServiceUnavailable("etcd leader changed")}
+	if got := ClassifyError("check_encryption", unavailable); got.Code != types.ErrCodeAPIUnavailable {
+		t.Errorf("unavailable API: code %s, want %s", got.Code, types.ErrCodeAPIUnavailable)
+	}
+	notJSON := &types.MCPError{Code: types.ErrCodeInternalError, Message: "the xDS snapshot is not JSON", Cause: errors.New("invalid character")}
+	if got := ClassifyError("get_kgateway_xds", notJSON); got != notJSON {
+		t.Errorf("internal cause reclassified as %s", got.Code)
+	}
+}
@@ -0,0 +1,711 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var dnsEndpointsGVR = schema.GroupVersionResource{Group: "externaldns.k8s.io", Version: "v1alpha1", Resource: "dnsendpoints"}
+
+const (
+	// externalDNSSelector matches the pods of the external-dns helm chart.
+	externalDNSSelector         = "app.kubernetes.io/name=external-dns"
+	externalDNSHostnameKey      = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSInternalHostname = "external-dns.alpha.kubernetes.io/internal-hostname"
+	externalDNSTargetKey        = "external-dns.alpha.kubernetes.io/target"
+)
+
+// externalDNSInstance is one external-dns Deployment, read from the flags of
+// its pods.
+type externalDNSInstance struct {
+	namespace, name  string
+	pods             []string
+	ready            int
+	container        string
+	sources          []string
+	domainFilters    []string
+	excludeDomains   []string
+	provider         string
+	registry         string
+	policy           string
+	ownerID          string
+	annotationFilter string
+	watchNamespace   string
+}
+
+// externalDNSFlags returns the flag values of an external-dns container,
+// accepting both --flag=value and --flag value.
+func externalDNSFlags(args []string) map[string][]string {
+	out := make(map[string][]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !hasValue && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			value = args[i+1]
+			i++
+		}
+		out[name] = append(out[name], value)
+	}
+	return out
+}
+
+// workloadName returns the Deployment of a pod from its ReplicaSet owner,
+// or the pod name for unmanaged pods.
+func workloadName(pod unstructured.Unstructured) string {
+	for _, ref := range pod.GetOwnerReferences() {
+		if ref.Kind == "ReplicaSet" {
+			if i := strings.LastIndex(ref.Name, "-"); i > 0 {
+				return ref.Name[:i]
+			}
+			return ref.Name
+		}
+	}
+	return pod.GetName()
+}
+
+// externalDNSInstances groups the external-dns pods by Deployment.
+func externalDNSInstances(pods []unstructured.Unstructured) []*externalDNSInstance {
+	byName := make(map[string]*externalDNSInstance)
+	var out []*externalDNSInstance
+	for _, pod := range pods {
+		key := pod.GetNamespace() + "/" + workloadName(pod)
+		inst, ok := byName[key]
+		if !ok {
+			inst = &externalDNSInstance{namespace: pod.GetNamespace(), name: workloadName(pod)}
+			containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+			for _, c := range containers {
+				cm, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				command, _, _ := unstructured.NestedStringSlice(cm, "command")
+				args, _, _ := unstructured.NestedStringSlice(cm, "args")
+				flags := externalDNSFlags(append(command, args...))
+				if len(flags["source"]) == 0 && len(flags["provider"]) == 0 {
+					continue
+				}
+				inst.container, _ = cm["name"].(string)
+				inst.sources = flags["source"]
+				inst.domainFilters = flags["domain-filter"]
+				inst.excludeDomains = flags["exclude-domains"]
+				inst.provider = strings.Join(flags["provider"], ",")
+				inst.registry = orDefault(strings.Join(flags["registry"], ","), "txt")
+				inst.policy = orDefault(strings.Join(flags["policy"], ","), "sync")
+				inst.ownerID = orDefault(strings.Join(flags["txt-owner-id"], ","), "default")
+				inst.annotationFilter = strings.Join(flags["annotation-filter"], ",")
+				inst.watchNamespace = strings.Join(flags["namespace"], ",")
+				break
+			}
+			byName[key] = inst
+			out = append(out, inst)
+		}
+		inst.pods = append(inst.pods, pod.GetName())
+		if podReady(pod) {
+			inst.ready++
+		}
+	}
+	return out
+}
+
+func podReady(pod unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(pod.Object, "status", "conditions")
+	for _, c := range conditions {
+		if cm, ok := c.(map[string]interface{}); ok && cm["type"] == "Ready" {
+			return cm["status"] == "True"
+		}
+	}
+	return false
+}
+
+// domainMatches reports whether host is inside one of the domain filters;
+// no filter matches every host.
+func domainMatches(host string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, f := range filters {
+		for _, d := range strings.Split(f, ",") {
+			d = strings.Trim(strings.ToLower(d), ".")
+			if d != "" && (host == d || strings.HasSuffix(host, "."+d)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// domainsOverlap reports whether two sets of domain filters can manage the
+// same name.
+func domainsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		if domainMatches(x, b) {
+			return true
+		}
+	}
+	for _, y := range b {
+		if domainMatches(y, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalDNSRecord is a DNS name a resource asks external-dns to publish.
+type externalDNSRecord struct {
+	hostname    string
+	source      string
+	ref         *types.ResourceRef
+	targets     []string
+	annotations map[string]string
+	// pending is set for DNSEndpoints external-dns has not processed since
+	// their last change.
+	pending bool
+}
+
+func (r externalDNSRecord) owner() string {
+	return fmt.Sprintf("%s %s/%s", r.ref.Kind, r.ref.Namespace, r.ref.Name)
+}
+
+// splitHostnames reads a comma-separated hostname annotation.
+func splitHostnames(v string) []string {
+	var out []string
+	for _, h := range strings.Split(v, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// loadBalancerTargets returns the addresses of status.loadBalancer.ingress,
+// or the target annotation that overrides them.
+func loadBalancerTargets(obj unstructured.Unstructured) []string {
+	if t := obj.GetAnnotations()[externalDNSTargetKey]; t != "" {
+		return splitHostnames(t)
+	}
+	var out []string
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	for _, in := range ingress {
+		m, _ := in.(map[string]interface{})
+		if ip, _ := m["ip"].(string); ip != "" {
+			out = append(out, ip)
+		} else if host, _ := m["hostname"].(string); host != "" {
+			out = append(out, host)
+		}
+	}
+	return out
+}
+
+// serviceRecords returns the records of Services annotated with a hostname.
+// Internal hostnames point at the ClusterIP.
+func serviceRecords(services []unstructured.Unstructured) []externalDNSRecord {
+	var out []externalDNSRecord
+	for _, svc := range services {
+		ref := &types.ResourceRef{Kind: "Service", Namespace: svc.GetNamespace(), Name: svc.GetName(), APIVersion: "v1"}
+		ann := svc.GetAnnotations()
+		svcType, _, _ := unstructured.NestedString(svc.Object, "spec", "type")
+		var targets []string
+		if svcType == "LoadBalancer" {
+			targets = loadBalancerTargets(svc)
+		} else if t := ann[externalDNSTargetKey]; t != "" {
+			targets = splitHostnames(t)
+		}
+		for _, h := range splitHostnames(ann[externalDNSHostnameKey]) {
+			out = append(out, externalDNSRecord{hostname: h, source: "service", ref: ref, targets: targets, annotations: ann})
+		}
+		clusterIP, _, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP")
+		for _, h := range splitHostnames(ann[externalDNSInternalHostname]) {
+			r := externalDNSRecord{hostname: h, source: "service", ref: ref, annotations: ann}
+			if clusterIP != "" && clusterIP != "None" {
+				r.targets = []string{clusterIP}
+			}
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// ingressRecords returns the records of the rule and TLS hosts of Ingresses
+// and their hostname annotation.
+func ingressRecords(ingresses []unstructured.Unstructured) []externalDNSRecord {
+	var out []externalDNSRecord
+	for _, ing := range ingresses {
+		ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+		hosts, _, _ := summarizeIngressRules(&ing)
+		sort.Strings(hosts)
+		tls, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
+		for _, t := range tls {
+			tm, _ := t.(map[string]interface{})
+			th, _, _ := unstructured.NestedStringSlice(tm, "hosts")
+			hosts = append(hosts, th...)
+		}
+		hosts = append(hosts, splitHostnames(ing.GetAnnotations()[externalDNSHostnameKey])...)
+		targets := loadBalancerTargets(ing)
+		seen := make(map[string]bool)
+		for _, h := range hosts {
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			out = append(out, externalDNSRecord{hostname: h, source: "ingress", ref: ref, targets: targets, annotations: ing.GetAnnotations()})
+		}
+	}
+	return out
+}
+
+// routeRecords returns the records of route hostnames, pointing at the
+// addresses of their parent Gateways.
+func routeRecords(kind, source string, routes, gateways []unstructured.Unstructured) []externalDNSRecord {
+	gwAddrs := make(map[string][]string)
+	for _, gw := range gateways {
+		key := gw.GetNamespace() + "/" + gw.GetName()
+		if t := gw.GetAnnotations()[externalDNSTargetKey]; t != "" {
+			gwAddrs[key] = splitHostnames(t)
+			continue
+		}
+		addrs, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+		for _, a := range addrs {
+			am, _ := a.(map[string]interface{})
+			if v, _ := am["value"].(string); v != "" {
+				gwAddrs[key] = append(gwAddrs[key], v)
+			}
+		}
+	}
+	var out []externalDNSRecord
+	for _, route := range routes {
+		ref := &types.ResourceRef{Kind: kind, Namespace: route.GetNamespace(), Name: route.GetName(), APIVersion: route.GetAPIVersion()}
+		var targets []string
+		parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		for _, p := range parents {
+			pm, _ := p.(map[string]interface{})
+			if k, _ := pm["kind"].(string); k != "" && k != "Gateway" {
+				continue
+			}
+			name, _ := pm["name"].(string)
+			ns, _ := pm["namespace"].(string)
+			targets = append(targets, gwAddrs[orDefault(ns, route.GetNamespace())+"/"+name]...)
+		}
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		for _, h := range hostnames {
+			out = append(out, externalDNSRecord{hostname: h, source: source, ref: ref, targets: targets, annotations: route.GetAnnotations()})
+		}
+	}
+	return out
+}
+
+// dnsEndpointRecords returns the endpoints of DNSEndpoint resources (the crd
+// source).
+func dnsEndpointRecords(endpoints []unstructured.Unstructured) []externalDNSRecord {
+	var out []externalDNSRecord
+	for _, de := range endpoints {
+		ref := &types.ResourceRef{Kind: "DNSEndpoint", Namespace: de.GetNamespace(), Name: de.GetName(), APIVersion: de.GetAPIVersion()}
+		observed, _, _ := unstructured.NestedInt64(de.Object, "status", "observedGeneration")
+		pending := observed < de.GetGeneration()
+		list, _, _ := unstructured.NestedSlice(de.Object, "spec", "endpoints")
+		for _, e := range list {
+			em, _ := e.(map[string]interface{})
+			name, _ := em["dnsName"].(string)
+			targets, _, _ := unstructured.NestedStringSlice(em, "targets")
+			if name != "" {
+				out = append(out, externalDNSRecord{hostname: name, source: "crd", ref: ref, targets: targets, annotations: de.GetAnnotations(), pending: pending})
+			}
+		}
+	}
+	return out
+}
+
+// managedBy returns the instances that read the record's source and whose
+// namespace, domain and annotation filters admit it. rejected explains why
+// the instances that read the source skip it.
+func managedBy(r externalDNSRecord, instances []*externalDNSInstance) (managing []*externalDNSInstance, readsSource bool, rejected []string) {
+	for _, inst := range instances {
+		if !containsString(inst.sources, r.source) {
+			continue
+		}
+		readsSource = true
+		switch {
+		case inst.watchNamespace != "" && inst.watchNamespace != r.ref.Namespace:
+			rejected = append(rejected, fmt.Sprintf("%s watches namespace %s only", inst.name, inst.watchNamespace))
+		case !domainMatches(r.hostname, inst.domainFilters):
+			rejected = append(rejected, fmt.Sprintf("%s --domain-filter=%s", inst.name, strings.Join(inst.domainFilters, ",")))
+		case len(inst.excludeDomains) > 0 && domainMatches(r.hostname, inst.excludeDomains):
+			rejected = append(rejected, fmt.Sprintf("%s --exclude-domains=%s", inst.name, strings.Join(inst.excludeDomains, ",")))
+		case inst.annotationFilter != "" && !annotationFilterMatches(inst.annotationFilter, r.annotations):
+			rejected = append(rejected, fmt.Sprintf("%s --annotation-filter=%s", inst.name, inst.annotationFilter))
+		default:
+			managing = append(managing, inst)
+		}
+	}
+	return managing, readsSource, rejected
+}
+
+// annotationFilterMatches applies an --annotation-filter, a label selector
+// over annotations. An unparsable filter matches nothing, as in external-dns.
+func annotationFilterMatches(filter string, annotations map[string]string) bool {
+	sel, err := labels.Parse(filter)
+	return err == nil && sel.Matches(labels.Set(annotations))
+}
+
+// externalDNSRecordFindings reports records no external-dns instance
+// publishes, records without targets, and names several resources claim
+// with different targets.
+func externalDNSRecordFindings(records []externalDNSRecord, instances []*externalDNSInstance) ([]types.DiagnosticFinding, int) {
+	var findings []types.DiagnosticFinding
+	published := 0
+	byHost := make(map[string][]externalDNSRecord)
+	for _, r := range records {
+		managing, readsSource, rejected := managedBy(r, instances)
+		switch {
+		case !readsSource:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSSourceDisabled,
+				Resource:   r.ref,
+				Summary:    fmt.Sprintf("%s requests %s, but no external-dns instance reads the %s source", r.owner(), r.hostname, r.source),
+				Suggestion: fmt.Sprintf("Add --source=%s to the external-dns Deployment, or publish the name from a source it reads.", r.source),
+			})
+			continue
+		case len(managing) == 0:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSHostnameFiltered,
+				Resource:   r.ref,
+				Summary:    fmt.Sprintf("%s requests %s, but every external-dns instance filters it out", r.owner(), r.hostname),
+				Detail:     strings.Join(rejected, "; "),
+				Suggestion: "Add the zone to --domain-filter, or fix the hostname if it is outside the managed zones.",
+			})
+			continue
+		case len(r.targets) == 0:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSNoTarget,
+				Resource:   r.ref,
+				Summary:    fmt.Sprintf("%s requests %s, but has no address to publish", r.owner(), r.hostname),
+				Detail:     "external-dns skips endpoints without targets",
+				Suggestion: "Wait for the load balancer or Gateway to get an address (check_metallb_status, get_gateway), or set the external-dns.alpha.kubernetes.io/target annotation.",
+			})
+			continue
+		case r.pending:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSSyncErrors,
+				Resource:   r.ref,
+				Summary:    fmt.Sprintf("%s has changed since external-dns last processed it; %s may be stale", r.owner(), r.hostname),
+				Detail:     "status.observedGeneration is behind metadata.generation",
+				Suggestion: "Check the external-dns logs for errors; it records observedGeneration after each successful sync.",
+			})
+		}
+		published++
+		byHost[strings.ToLower(r.hostname)] = append(byHost[strings.ToLower(r.hostname)], r)
+	}
+
+	hosts := make([]string, 0, len(byHost))
+	for h := range byHost {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	for _, h := range hosts {
+		rs := byHost[h]
+		targets := make(map[string]bool)
+		owners := make([]string, 0, len(rs))
+		for _, r := range rs {
+			targets[strings.Join(r.targets, ",")] = true
+			owners = append(owners, r.owner())
+		}
+		if len(targets) < 2 {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeExternalDNSHostnameConflict,
+			Resource:   rs[0].ref,
+			Summary:    fmt.Sprintf("%s is requested by %d resources with different targets", h, len(rs)),
+			Detail:     fmt.Sprintf("resources: %s; targets: %s", truncateList(owners, 5), joinKeys(targets)),
+			Suggestion: "external-dns publishes one of them and the record can flip between syncs. Keep the name on one resource.",
+		})
+	}
+	return findings, published
+}
+
+// externalDNSOwnershipFindings reports instances that fight over the same
+// records: the same TXT owner ID on overlapping domains, or the noop
+// registry, which cannot tell its records from anyone else's.
+func externalDNSOwnershipFindings(instances []*externalDNSInstance) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for i, a := range instances {
+		ref := &types.ResourceRef{Kind: "Deployment", Namespace: a.namespace, Name: a.name, APIVersion: "apps/v1"}
+		if a.registry == "noop" && a.policy == "sync" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSOwnershipConflict,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("external-dns %s/%s uses the noop registry with policy sync", a.namespace, a.name),
+				Detail:     "without TXT ownership records every record in its zones counts as its own",
+				Suggestion: "Use --registry=txt with a unique --txt-owner-id, or --policy=upsert-only so records created elsewhere are not deleted.",
+			})
+		}
+		for _, b := range instances[i+1:] {
+			if a.registry != "txt" || b.registry != "txt" || a.ownerID != b.ownerID || a.provider != b.provider || !domainsOverlap(a.domainFilters, b.domainFilters) {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSOwnershipConflict,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("external-dns %s/%s and %s/%s share TXT owner ID %q on overlapping domains", a.namespace, a.name, b.namespace, b.name, a.ownerID),
+				Detail:     fmt.Sprintf("provider %s; domain filters: [%s] and [%s]", a.provider, strings.Join(a.domainFilters, ","), strings.Join(b.domainFilters, ",")),
+				Suggestion: "Each instance deletes the records the other creates, as they are owned by the same ID but missing from its own sources. Give every instance a unique --txt-owner-id.",
+			})
+		}
+	}
+	return findings
+}
+
+var (
+	// externalDNSOwnerSkip is logged when a record exists but is owned by
+	// another TXT owner ID.
+	externalDNSOwnerSkip = regexp.MustCompile(`Skipping endpoint (\S+).*owner id does not match.*found: \W*([\w.-]*)\W*, required: \W*([\w.-]*)`)
+	externalDNSErrorLine = regexp.MustCompile(`level=(error|fatal)|"level":"(error|fatal)"`)
+)
+
+// externalDNSLogFindings reports ownership mismatches and errors in the log
+// lines of one instance, plus the last line mentioning hostname.
+func externalDNSLogFindings(inst *externalDNSInstance, logs, hostname string) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Deployment", Namespace: inst.namespace, Name: inst.name, APIVersion: "apps/v1"}
+	skipped := make(map[string]string)
+	var errorLines []string
+	lastMention := ""
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := externalDNSOwnerSkip.FindStringSubmatch(line); m != nil {
+			skipped[m[1]] = fmt.Sprintf("owned by %q, this instance is %q", m[2], m[3])
+		} else if externalDNSErrorLine.MatchString(line) {
+			errorLines = append(errorLines, line)
+		}
+		if hostname != "" && strings.Contains(line, hostname) {
+			lastMention = line
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	names := make([]string, 0, len(skipped))
+	for n := range skipped {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeExternalDNSOwnershipConflict,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("external-dns %s/%s does not update %s: the record belongs to another owner", inst.namespace, inst.name, n),
+			Detail:     skipped[n],
+			Suggestion: "Another external-dns instance or cluster owns the name. Remove the name from one of them, or delete the stale TXT ownership record if its owner is gone.",
+		})
+	}
+	if len(errorLines) > 0 {
+		total := len(errorLines)
+		if total > maxErrorLines {
+			errorLines = errorLines[total-maxErrorLines:]
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeExternalDNSSyncErrors,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("external-dns %s/%s logged %d error(s)", inst.namespace, inst.name, total),
+			Detail:     strings.Join(errorLines, "\n"),
+			Suggestion: "Provider errors (credentials, rate limits, zone not found) stop the whole sync, so no record of the batch is written.",
+		})
+	}
+	if lastMention != "" {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryDNS,
+			Resource: ref,
+			Summary:  fmt.Sprintf("Last log line of external-dns %s/%s about %s", inst.namespace, inst.name, hostname),
+			Detail:   lastMention,
+		})
+	}
+	return findings
+}
+
+// --- check_external_dns ---
+
+type CheckExternalDNSTool struct{ BaseTool }
+
+func (t *CheckExternalDNSTool) Name() string { return "check_external_dns" }
+func (t *CheckExternalDNSTool) Description() string {
+	return "Check that external-dns publishes the DNS names requested by Services, Ingresses, Gateway routes and DNSEndpoints: sources and domain filters that skip them, names without an address, names claimed with different targets, instances sharing a TXT owner ID and ownership mismatches in the logs. Pass hostname to explain why one name does not resolve publicly"
+}
+func (t *CheckExternalDNSTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"hostname": map[string]interface{}{
+				"type":        "string",
+				"description": "Only report on this DNS name, e.g. one that does not resolve publicly",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check resources in this namespace (default: all namespaces)",
+			},
+			"include_logs": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Scan the external-dns logs for ownership mismatches and errors (default true)",
+			},
+			"tail": map[string]interface{}{
+				"type":        "integer",
+				"description": "Log lines to scan per external-dns pod (default 1000)",
+			},
+		},
+	}
+}
+
+func (t *CheckExternalDNSTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	hostname := strings.TrimSuffix(strings.ToLower(getStringArg(args, "hostname", "")), ".")
+	ns := getStringArg(args, "namespace", "")
+	includeLogs := true
+	if v, ok := args["include_logs"].(bool); ok {
+		includeLogs = v
+	}
+
+	pods, err := t.listResourceSelected(ctx, podsGVR, "", listSelector{Label: externalDNSSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external-dns pods: %w", err)
+	}
+	instances := externalDNSInstances(pods.Items)
+	var findings []types.DiagnosticFinding
+	if len(instances) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeExternalDNSNotReady,
+			Summary:    "No external-dns pod found",
+			Detail:     "no pod matches " + externalDNSSelector,
+			Suggestion: "No DNS record is created or updated; check the external-dns Deployment.",
+		})
+	}
+	sources := make(map[string]bool)
+	for _, inst := range instances {
+		for _, s := range inst.sources {
+			sources[s] = true
+		}
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryDNS,
+			Resource: &types.ResourceRef{Kind: "Deployment", Namespace: inst.namespace, Name: inst.name, APIVersion: "apps/v1"},
+			Summary:  fmt.Sprintf("external-dns %s/%s: %d/%d pods ready, provider %s, sources %s", inst.namespace, inst.name, inst.ready, len(inst.pods), orDefault(inst.provider, "unknown"), strings.Join(inst.sources, ",")),
+			Detail: fmt.Sprintf("domain filters: [%s]; registry %s, owner ID %q, policy %s", strings.Join(inst.domainFilters, ","),
+				inst.registry, inst.ownerID, inst.policy),
+		}
+		if inst.ready == 0 {
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeExternalDNSNotReady
+			f.Suggestion = "Records are not created or updated while no pod runs; check the pod events and logs."
+		}
+		findings = append(findings, f)
+	}
+	findings = append(findings, externalDNSOwnershipFindings(instances)...)
+
+	// Records of every source, so that a source nothing reads is reported
+	// for the resources that rely on it.
+	var records []externalDNSRecord
+	if list, err := t.listResource(ctx, servicesGVR, ns); err == nil {
+		records = append(records, serviceRecords(list.Items)...)
+	}
+	if list, err := t.listResource(ctx, ingressGVR, ns); err == nil {
+		records = append(records, ingressRecords(list.Items)...)
+	}
+	if sources["gateway-httproute"] || sources["gateway-grpcroute"] {
+		var gateways []unstructured.Unstructured
+		if list, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ""); err == nil {
+			gateways = list.Items
+		}
+		if list, err := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ns); err == nil {
+			records = append(records, routeRecords("HTTPRoute", "gateway-httproute", list.Items, gateways)...)
+		}
+		if list, err := t.listResourceWithFallback(ctx, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ns); err == nil {
+			records = append(records, routeRecords("GRPCRoute", "gateway-grpcroute", list.Items, gateways)...)
+		}
+	}
+	if list, err := t.listResource(ctx, dnsEndpointsGVR, ns); err == nil {
+		records = append(records, dnsEndpointRecords(list.Items)...)
+	}
+	if hostname != "" {
+		var matching []externalDNSRecord
+		for _, r := range records {
+			if strings.TrimSuffix(strings.ToLower(r.hostname), ".") == hostname {
+				matching = append(matching, r)
+			}
+		}
+		records = matching
+		if len(records) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeExternalDNSHostnameUnrequested,
+				Summary:    fmt.Sprintf("No Service, Ingress, route or DNSEndpoint requests %s; external-dns never publishes it", hostname),
+				Suggestion: fmt.Sprintf("Add the %s annotation to the LoadBalancer Service, the host to an Ingress rule or route hostnames, or a DNSEndpoint for the name.", externalDNSHostnameKey),
+			})
+		}
+	}
+	recordFindings, published := externalDNSRecordFindings(records, instances)
+	findings = append(findings, recordFindings...)
+	if len(records) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryDNS,
+			Summary:  fmt.Sprintf("%d of %d requested DNS names are published by external-dns", published, len(records)),
+			Detail:   "published: read by an instance, inside its filters and with a target; the provider may still reject them (see the log findings)",
+		})
+	}
+
+	if includeLogs {
+		tail := int64(getIntArg(args, "tail", 1000))
+		for _, inst := range instances {
+			for _, pod := range inst.pods {
+				lr, err := getPodLogs(ctx, t.Clients, inst.namespace, pod, inst.container, tail, "")
+				if err != nil {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity: types.SeverityInfo,
+						Category: types.CategoryLogs,
+						Resource: &types.ResourceRef{Kind: "Pod", Namespace: inst.namespace, Name: pod},
+						Summary:  fmt.Sprintf("Could not read the logs of %s/%s", inst.namespace, pod),
+						Detail:   err.Error(),
+					})
+					continue
+				}
+				findings = append(findings, externalDNSLogFindings(inst, lr.logs, hostname)...)
+			}
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func externalDNSPod(name, replicaSet string, args ...interface{}) *unstructured.Unstructured {
+	pod := ipamObj("v1", "Pod", "external-dns", name, map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "external-dns", "args": args},
+		}},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}},
+	})
+	pod.SetLabels(map[string]string{"app.kubernetes.io/name": "external-dns"})
+	pod.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: replicaSet, UID: "1"}})
+	return pod
+}
+
+func TestExternalDNSFlags(t *testing.T) {
+	got := externalDNSFlags([]string{"/external-dns", "--source=service", "--source", "ingress", "--domain-filter=example.com", "--once"})
+	want := map[string][]string{"source": {"service", "ingress"}, "domain-filter": {"example.com"}, "once": {""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("externalDNSFlags() = %v, want %v", got, want)
+	}
+	if !domainMatches("shop.example.com.", []string{"example.com"}) || domainMatches("badexample.com", []string{"example.com"}) {
+		t.Error("domainMatches() should match subdomains only")
+	}
+}
+
+func TestExternalDNSLogFindings(t *testing.T) {
+	inst := &externalDNSInstance{namespace: "external-dns", name: "external-dns"}
+	logs := strings.Join([]string{
+		`time="2026-10-15T10:00:00Z" level=info msg="All records are already up to date"`,
+		`time="2026-10-15T10:01:00Z" level=debug msg="Skipping endpoint shop.example.com 300 IN A  203.0.113.10 [] because owner id does not match, found: \"prod\", required: \"staging\""`,
+		`time="2026-10-15T10:02:00Z" level=error msg="Failed to do run once: AccessDenied: not authorized to perform route53:ChangeResourceRecordSets"`,
+	}, "\n")
+	findings := externalDNSLogFindings(inst, logs, "shop.example.com")
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Summary+" "+string(f.Code)+" "+f.Detail)
+	}
+	all := strings.Join(got, "\n")
+	for _, want := range []string{
+		`the record belongs to another owner EDNS005_OWNERSHIP_CONFLICT owned by "prod", this instance is "staging"`,
+		"logged 1 error(s) EDNS007_SYNC_ERRORS",
+		"Last log line of external-dns external-dns/external-dns about shop.example.com",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+}
+
+func TestCheckExternalDNS(t *testing.T) {
+	lb := ipamObj("v1", "Service", "shop", "web", map[string]interface{}{
+		"spec":   map[string]interface{}{"type": "LoadBalancer"},
+		"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{"ip": "203.0.113.10"}}}},
+	})
+	lb.SetAnnotations(map[string]string{externalDNSHostnameKey: "shop.example.com,shop.other.org"})
+	pending := ipamObj("v1", "Service", "shop", "api", map[string]interface{}{"spec": map[string]interface{}{"type": "LoadBalancer"}})
+	pending.SetAnnotations(map[string]string{externalDNSHostnameKey: "api.example.com"})
+	ing := ipamObj("networking.k8s.io/v1", "Ingress", "shop", "web", map[string]interface{}{
+		"spec":   map[string]interface{}{"rules": []interface{}{map[string]interface{}{"host": "shop.example.com"}}},
+		"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{"ip": "203.0.113.20"}}}},
+	})
+	objs := []runtime.Object{
+		externalDNSPod("external-dns-7d9f-abcde", "external-dns-7d9f", "--source=service", "--source=ingress", "--domain-filter=example.com", "--provider=aws"),
+		externalDNSPod("external-dns-b-5c6d-fghij", "external-dns-b-5c6d", "--source=service", "--provider=aws"),
+		lb, pending, ing,
+	}
+	route := ipamObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{"hostnames": []interface{}{"www.example.com"}},
+	})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList", servicesGVR: "ServiceList", ingressGVR: "IngressList", dnsEndpointsGVR: "DNSEndpointList",
+		gatewaysV1GVR: "GatewayList", httpRoutesV1GVR: "HTTPRouteList", grpcRoutesV1GVR: "GRPCRouteList",
+	}, objs...)
+	if err := client.Tracker().Create(httpRoutesV1GVR, route, "shop"); err != nil {
+		t.Fatal(err)
+	}
+	tool := &CheckExternalDNSTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"include_logs": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok external-dns external-dns/external-dns: 1/1 pods ready, provider aws, sources service,ingress",
+		`critical external-dns external-dns/external-dns and external-dns/external-dns-b share TXT owner ID "default" on overlapping domains EDNS005_OWNERSHIP_CONFLICT`,
+		"warning Service shop/api requests api.example.com, but has no address to publish EDNS004_NO_TARGET",
+		"warning shop.example.com is requested by 2 resources with different targets EDNS006_HOSTNAME_CONFLICT",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	// The HTTPRoute is not read, as no instance runs the gateway-httproute
+	// source; shop.other.org is published by the unfiltered instance.
+	if strings.Contains(all, "www.example.com") || strings.Contains(all, "EDNS003") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"include_logs": false, "hostname": "missing.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "EDNS008_HOSTNAME_NOT_REQUESTED") {
+		t.Errorf("expected EDNS008 for an unrequested hostname in:\n%s", all)
+	}
+}
+
+func TestExternalDNSRecordFindingsFiltered(t *testing.T) {
+	inst := &externalDNSInstance{name: "external-dns", sources: []string{"ingress"}, domainFilters: []string{"example.com"}}
+	ing := ipamObj("networking.k8s.io/v1", "Ingress", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"host": "shop.example.org"}}},
+	})
+	svc := ipamObj("v1", "Service", "shop", "web", nil)
+	svc.SetAnnotations(map[string]string{externalDNSHostnameKey: "web.example.com"})
+	records := append(ingressRecords([]unstructured.Unstructured{*ing}), serviceRecords([]unstructured.Unstructured{*svc})...)
+	findings, published := externalDNSRecordFindings(records, []*externalDNSInstance{inst})
+	if published != 0 || len(findings) != 2 {
+		t.Fatalf("externalDNSRecordFindings() = %+v, %d", findings, published)
+	}
+	if findings[0].Code != "EDNS003_HOSTNAME_FILTERED" || findings[0].Detail != "external-dns --domain-filter=example.com" {
+		t.Errorf("findings[0] = %+v", findings[0])
+	}
+	if findings[1].Code != "EDNS002_SOURCE_DISABLED" {
+		t.Errorf("findings[1] = %+v", findings[1])
+	}
+}
@@ -0,0 +1,549 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// istioRootNamespace holds mesh-wide AuthorizationPolicies.
+const istioRootNamespace = "istio-system"
+
+// entryPoint is one way into the cluster and the Services it sends traffic to.
+type entryPoint struct {
+	Ref       *types.ResourceRef
+	Kind      string
+	Addresses []string
+	Backends  []string // "namespace/name" of backend Services
+	// Pending describes the missing address when Addresses is empty and one
+	// is expected; PendingHint suggests where to look.
+	Pending     string
+	PendingHint string
+}
+
+func (e entryPoint) label() string {
+	return fmt.Sprintf("%s %s/%s", e.Kind, e.Ref.Namespace, e.Ref.Name)
+}
+
+// loadBalancerAddresses reads status.loadBalancer.ingress of a Service or Ingress.
+func loadBalancerAddresses(obj map[string]interface{}) []string {
+	ingress, _, _ := unstructured.NestedSlice(obj, "status", "loadBalancer", "ingress")
+	var out []string
+	for _, i := range ingress {
+		im, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ip, _ := im["ip"].(string); ip != "" {
+			out = append(out, ip)
+		} else if host, _ := im["hostname"].(string); host != "" {
+			out = append(out, host)
+		}
+	}
+	return out
+}
+
+// gatewayAddresses reads status.addresses of a Gateway.
+func gatewayAddresses(gw map[string]interface{}) []string {
+	addrs, _, _ := unstructured.NestedSlice(gw, "status", "addresses")
+	var out []string
+	for _, a := range addrs {
+		if am, ok := a.(map[string]interface{}); ok {
+			if v, _ := am["value"].(string); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// isPublicAddress reports whether an address is reachable from outside the
+// network. Hostnames are assumed public.
+func isPublicAddress(a string) bool {
+	ip, err := netip.ParseAddr(a)
+	if err != nil {
+		return true
+	}
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified())
+}
+
+// describeAddresses labels each address as public or internal.
+func describeAddresses(addrs []string) string {
+	parts := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		scope := "internal"
+		if isPublicAddress(a) {
+			scope = "public"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", a, scope))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// gatewayProxySelector reports whether a Service selects gateway or ingress
+// controller proxies, whose own exposure is expected; their routes' backends
+// are checked instead.
+func gatewayProxySelector(sel map[string]string) bool {
+	if _, ok := sel["gateway.networking.k8s.io/gateway-name"]; ok {
+		return true
+	}
+	if strings.Contains(sel["istio"], "gateway") {
+		return true
+	}
+	switch sel["app.kubernetes.io/name"] {
+	case "ingress-nginx", "traefik", "envoy", "kgateway", "gateway-proxy", "contour":
+		return true
+	}
+	return false
+}
+
+// ingressBackends returns the backend Services of an Ingress.
+func ingressBackends(ing *unstructured.Unstructured) []string {
+	seen := make(map[string]bool)
+	add := func(backend map[string]interface{}) {
+		if name, _, _ := unstructured.NestedString(backend, "service", "name"); name != "" {
+			seen[ing.GetNamespace()+"/"+name] = true
+		}
+	}
+	if def, ok, _ := unstructured.NestedMap(ing.Object, "spec", "defaultBackend"); ok {
+		add(def)
+	}
+	rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paths, _, _ := unstructured.NestedSlice(rm, "http", "paths")
+		for _, p := range paths {
+			if pm, ok := p.(map[string]interface{}); ok {
+				if b, ok := pm["backend"].(map[string]interface{}); ok {
+					add(b)
+				}
+			}
+		}
+	}
+	return sortedSet(seen)
+}
+
+// routeAttachedToGateway reports whether a route has a parentRef to the Gateway.
+func routeAttachedToGateway(route routeInfo, gwNs, gwName string) bool {
+	parentRefs, _, _ := unstructured.NestedSlice(route.obj, "spec", "parentRefs")
+	for _, pr := range parentRefs {
+		prm, ok := pr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := prm["kind"].(string)
+		name, _ := prm["name"].(string)
+		ns, _ := prm["namespace"].(string)
+		if (kind == "" || kind == "Gateway") && name == gwName && orDefault(ns, route.namespace) == gwNs {
+			return true
+		}
+	}
+	return false
+}
+
+// routeBackends returns the "namespace/name" of the Services a route sends
+// traffic to.
+func routeBackends(route routeInfo) []string {
+	seen := make(map[string]bool)
+	rules, _, _ := unstructured.NestedSlice(route.obj, "spec", "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		brs, _ := rm["backendRefs"].([]interface{})
+		for _, br := range brs {
+			brm, ok := br.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := brm["kind"].(string); kind != "" && kind != "Service" {
+				continue
+			}
+			name, _ := brm["name"].(string)
+			ns, _ := brm["namespace"].(string)
+			seen[orDefault(ns, route.namespace)+"/"+name] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+// gatewayRouteBackends returns the backend Services of routes attached to a Gateway.
+func gatewayRouteBackends(routes []routeInfo, gwNs, gwName string) []string {
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		if !routeAttachedToGateway(route, gwNs, gwName) {
+			continue
+		}
+		for _, b := range routeBackends(route) {
+			seen[b] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// restrictingNetworkPolicies returns the NetworkPolicies that select any of
+// pods and restrict their ingress on the Service ports. A rule without "from"
+// admits every source, so a policy with such a rule does not count unless its
+// ports, named ones resolved through the pods, exclude every Service target
+// port.
+func restrictingNetworkPolicies(pods []podPorts, ports []serviceTargetPort, policies []unstructured.Unstructured) []string {
+	var out []string
+	for i := range policies {
+		p := &policies[i]
+		policyTypes, hasTypes, _ := unstructured.NestedStringSlice(p.Object, "spec", "policyTypes")
+		if hasTypes && !containsString(policyTypes, "Ingress") {
+			continue
+		}
+		selObj, ok, _ := unstructured.NestedMap(p.Object, "spec", "podSelector")
+		if !ok {
+			selObj = map[string]interface{}{}
+		}
+		sel, err := parseLabelSelector(selObj, true)
+		if err != nil || len(selectPods(pods, sel, func(ns string) bool { return ns == p.GetNamespace() })) == 0 {
+			continue
+		}
+		restricts := true
+		rules, _, _ := unstructured.NestedSlice(p.Object, "spec", "ingress")
+		for _, r := range rules {
+			rm, _ := r.(map[string]interface{})
+			if from, _, _ := unstructured.NestedSlice(rm, "from"); len(from) == 0 && ruleAdmitsService(rm, pods, ports) {
+				restricts = false
+			}
+		}
+		if restricts {
+			out = append(out, p.GetNamespace()+"/"+p.GetName())
+		}
+	}
+	return out
+}
+
+// ruleAdmitsService reports whether an ingress rule admits a target port of
+// ports on any of pods. Without Service ports to compare, every rule does.
+func ruleAdmitsService(rule map[string]interface{}, pods []podPorts, ports []serviceTargetPort) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, pod := range pods {
+		for _, sp := range ports {
+			if port, ok := targetContainerPort(sp, pod); ok && rulePortsMatch(rule, port, pod) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyingAuthorizationPolicies returns the Istio AuthorizationPolicies that
+// apply to pods, by selector, targetRef(s) to the Service, or as a
+// namespace-wide (or root namespace mesh-wide) policy.
+func applyingAuthorizationPolicies(pods []podPorts, svcNs, svcName string, policies []unstructured.Unstructured) []string {
+	var out []string
+	for i := range policies {
+		p := &policies[i]
+		if p.GetNamespace() != svcNs && p.GetNamespace() != istioRootNamespace {
+			continue
+		}
+		refs, _, _ := unstructured.NestedSlice(p.Object, "spec", "targetRefs")
+		if ref, ok, _ := unstructured.NestedMap(p.Object, "spec", "targetRef"); ok {
+			refs = append(refs, ref)
+		}
+		applies := false
+		if len(refs) > 0 {
+			for _, r := range refs {
+				rm, _ := r.(map[string]interface{})
+				kind, _ := rm["kind"].(string)
+				name, _ := rm["name"].(string)
+				applies = applies || (kind == "Service" && name == svcName && p.GetNamespace() == svcNs)
+			}
+		} else if matchLabels, ok, _ := unstructured.NestedStringMap(p.Object, "spec", "selector", "matchLabels"); ok && len(matchLabels) > 0 {
+			sel := labels.SelectorFromSet(matchLabels)
+			applies = len(selectPods(pods, sel, func(string) bool { return true })) > 0
+		} else {
+			applies = true
+		}
+		if applies {
+			out = append(out, p.GetNamespace()+"/"+p.GetName())
+		}
+	}
+	return out
+}
+
+func containsString(items []string, s string) bool {
+	for _, i := range items {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
+
+// listRoutes returns the HTTPRoutes and GRPCRoutes of all namespaces, or nil
+// when Gateway API is not installed.
+func (b *BaseTool) listRoutes(ctx context.Context) []routeInfo {
+	var routes []routeInfo
+	if list, err := b.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ""); err == nil {
+		for _, r := range list.Items {
+			routes = append(routes, routeInfo{kind: "HTTPRoute", name: r.GetName(), namespace: r.GetNamespace(), obj: r.Object})
+		}
+	}
+	if list, err := b.listResourceWithFallback(ctx, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ""); err == nil {
+		for _, r := range list.Items {
+			routes = append(routes, routeInfo{kind: "GRPCRoute", name: r.GetName(), namespace: r.GetNamespace(), obj: r.Object})
+		}
+	}
+	return routes
+}
+
+// collectEntryPoints finds the LoadBalancer and NodePort Services among
+// services, and the Gateways and Ingresses of ns, with their backends.
+func (b *BaseTool) collectEntryPoints(ctx context.Context, ns string, services []unstructured.Unstructured, routes []routeInfo) []entryPoint {
+	var entries []entryPoint
+
+	for i := range services {
+		s := &services[i]
+		svcType, _, _ := unstructured.NestedString(s.Object, "spec", "type")
+		if svcType != "LoadBalancer" && svcType != "NodePort" {
+			continue
+		}
+		e := entryPoint{
+			Ref:  &types.ResourceRef{Kind: "Service", Namespace: s.GetNamespace(), Name: s.GetName(), APIVersion: "v1"},
+			Kind: svcType + " Service",
+		}
+		if sel, _, _ := unstructured.NestedStringMap(s.Object, "spec", "selector"); !gatewayProxySelector(sel) {
+			e.Backends = []string{s.GetNamespace() + "/" + s.GetName()}
+		}
+		if svcType == "LoadBalancer" {
+			e.Addresses = loadBalancerAddresses(s.Object)
+			e.Pending = "external address: status.loadBalancer.ingress is empty"
+			e.PendingHint = "Check the Service events and the cloud controller or load balancer implementation (e.g. MetalLB) for allocation errors."
+		}
+		entries = append(entries, e)
+	}
+
+	if gwList, err := b.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns); err == nil {
+		for _, gw := range gwList.Items {
+			entries = append(entries, entryPoint{
+				Ref:         &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: "gateway.networking.k8s.io/v1"},
+				Kind:        "Gateway",
+				Addresses:   gatewayAddresses(gw.Object),
+				Backends:    gatewayRouteBackends(routes, gw.GetNamespace(), gw.GetName()),
+				Pending:     "address in status.addresses",
+				PendingHint: "Check the Gateway's Programmed condition and the Service the controller created for it.",
+			})
+		}
+	}
+
+	if ingList, err := b.listResource(ctx, ingressGVR, ns); err == nil {
+		for i := range ingList.Items {
+			ing := &ingList.Items[i]
+			entries = append(entries, entryPoint{
+				Ref:         &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"},
+				Kind:        "Ingress",
+				Addresses:   loadBalancerAddresses(ing.Object),
+				Backends:    ingressBackends(ing),
+				Pending:     "address in status.loadBalancer",
+				PendingHint: "Check that an ingress controller serves its ingressClassName and publishes its status.",
+			})
+		}
+	}
+	return entries
+}
+
+// --- analyze_external_exposure ---
+
+type AnalyzeExternalExposureTool struct{ BaseTool }
+
+func (t *AnalyzeExternalExposureTool) Name() string { return "analyze_external_exposure" }
+func (t *AnalyzeExternalExposureTool) Description() string {
+	return "Map cluster entry points (LoadBalancer and NodePort Services, Gateways, Ingresses), check that load balancer addresses are assigned, and flag externally exposed backends without a NetworkPolicy or AuthorizationPolicy"
+}
+func (t *AnalyzeExternalExposureTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeExternalExposureTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	svcList, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	services := make(map[string]*unstructured.Unstructured, len(svcList.Items))
+	for i := range svcList.Items {
+		s := &svcList.Items[i]
+		services[s.GetNamespace()+"/"+s.GetName()] = s
+	}
+
+	entries := t.collectEntryPoints(ctx, ns, svcList.Items, t.listRoutes(ctx))
+
+	var findings []types.DiagnosticFinding
+	for _, e := range entries {
+		if len(e.Addresses) > 0 || e.Pending == "" {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeServiceExposurePending,
+			Resource:   e.Ref,
+			Summary:    fmt.Sprintf("%s has no %s", e.label(), e.Pending),
+			Suggestion: e.PendingHint,
+		})
+	}
+
+	// Entry point inventory
+	for _, e := range entries {
+		if len(e.Addresses) == 0 {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: e.Ref,
+			Summary:  fmt.Sprintf("%s is reachable at %s", e.label(), describeAddresses(e.Addresses)),
+			Detail:   fmt.Sprintf("backends: %s", orDefault(truncateList(e.Backends, 5), "none")),
+		})
+	}
+
+	// Protection of exposed backends
+	exposedVia := make(map[string][]string)
+	for _, e := range entries {
+		for _, b := range e.Backends {
+			exposedVia[b] = append(exposedVia[b], e.label())
+		}
+	}
+	findings = append(findings, t.checkProtection(ctx, exposedVia, services)...)
+
+	if len(entries) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Summary:  "No external entry points (LoadBalancer/NodePort Services, Gateways, Ingresses) found",
+		})
+	}
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, ""), nil
+}
+
+// checkProtection flags exposed backend Services whose pods are selected by
+// neither an ingress-restricting NetworkPolicy nor an AuthorizationPolicy.
+func (t *AnalyzeExternalExposureTool) checkProtection(ctx context.Context, exposedVia map[string][]string, services map[string]*unstructured.Unstructured) []types.DiagnosticFinding {
+	type nsData struct {
+		pods     []podPorts
+		netpols  []unstructured.Unstructured
+		authzPol []unstructured.Unstructured
+	}
+	cache := make(map[string]*nsData)
+	var rootAuthz []unstructured.Unstructured
+	if list, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, istioRootNamespace); err == nil {
+		rootAuthz = list.Items
+	}
+	load := func(ns string) *nsData {
+		if d, ok := cache[ns]; ok {
+			return d
+		}
+		d := &nsData{}
+		if list, err := t.listResource(ctx, podsGVR, ns); err == nil {
+			for i := range list.Items {
+				d.pods = append(d.pods, podPortsFrom(&list.Items[i]))
+			}
+		}
+		if list, err := t.listResource(ctx, networkPoliciesGVR, ns); err == nil {
+			d.netpols = list.Items
+		}
+		if list, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ns); err == nil {
+			d.authzPol = list.Items
+		}
+		if ns != istioRootNamespace {
+			d.authzPol = append(d.authzPol, rootAuthz...)
+		}
+		cache[ns] = d
+		return d
+	}
+
+	var findings []types.DiagnosticFinding
+	protected := 0
+	keys := make([]string, 0, len(exposedVia))
+	for k := range exposedVia {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		svcNs, svcName, _ := strings.Cut(key, "/")
+		ref := &types.ResourceRef{Kind: "Service", Namespace: svcNs, Name: svcName, APIVersion: "v1"}
+		svc, ok := services[key]
+		if !ok {
+			// Backend outside the namespace filter
+			s, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(svcNs).Get(ctx, svcName, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			svc = s
+		}
+		sel, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if len(sel) == 0 {
+			continue
+		}
+		d := load(svcNs)
+		pods := selectPods(d.pods, labels.SelectorFromSet(sel), func(ns string) bool { return ns == svcNs })
+		if len(pods) == 0 {
+			continue
+		}
+		netpols := restrictingNetworkPolicies(pods, serviceTargetPorts(svc), d.netpols)
+		authz := applyingAuthorizationPolicies(pods, svcNs, svcName, d.authzPol)
+		if len(netpols) > 0 || len(authz) > 0 {
+			protected++
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeServiceExposedUnprotected,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Service %s is exposed externally without a NetworkPolicy or AuthorizationPolicy", key),
+			Detail:     fmt.Sprintf("exposed via: %s; %d pod(s) selected", truncateList(exposedVia[key], 5), len(pods)),
+			Suggestion: "Add a NetworkPolicy that only admits traffic from the gateway or ingress controller namespace, or an AuthorizationPolicy for the workload.",
+		})
+	}
+	if protected > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("%d externally exposed Service(s) are covered by a NetworkPolicy or AuthorizationPolicy", protected),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGatewayRouteBackends(t *testing.T) {
+	route := routeInfo{kind: "HTTPRoute", name: "shop", namespace: "shop", obj: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra"}},
+			"rules": []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+				map[string]interface{}{"name": "web", "port": int64(80)},
+				map[string]interface{}{"name": "api", "namespace": "backend", "port": int64(8080)},
+				map[string]interface{}{"name": "bucket", "kind": "Backend", "group": "gateway.kgateway.dev"},
+			}}},
+		},
+	}}
+	if got := gatewayRouteBackends([]routeInfo{route}, "infra", "public"); !reflect.DeepEqual(got, []string{"backend/api", "shop/web"}) {
+		t.Errorf("gatewayRouteBackends() = %v", got)
+	}
+	if got := gatewayRouteBackends([]routeInfo{route}, "infra", "internal"); len(got) != 0 {
+		t.Errorf("expected no backends for an unrelated gateway, got %v", got)
+	}
+}
+
+func TestRestrictingNetworkPolicies(t *testing.T) {
+	pods := []podPorts{{Namespace: "shop", Name: "web-1", Labels: map[string]string{"app": "web"}}}
+	policy := func(name string, ingress []interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "shop"},
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"ingress":     ingress,
+			},
+		}}
+	}
+	allowAll := policy("allow-all", []interface{}{map[string]interface{}{}})
+	fromGateway := policy("from-gateway", []interface{}{map[string]interface{}{"from": []interface{}{
+		map[string]interface{}{"namespaceSelector": map[string]interface{}{}},
+	}}})
+
+	if got := restrictingNetworkPolicies(pods, nil, []unstructured.Unstructured{allowAll}); len(got) != 0 {
+		t.Errorf("a rule without from should not restrict, got %v", got)
+	}
+	if got := restrictingNetworkPolicies(pods, nil, []unstructured.Unstructured{allowAll, fromGateway}); !reflect.DeepEqual(got, []string{"shop/from-gateway"}) {
+		t.Errorf("restrictingNetworkPolicies() = %v", got)
+	}
+
+	// A rule without from only opens the ports it lists, named ones resolved
+	// through the pods.
+	pods[0].Ports = []containerPort{{Name: "http", Port: 8080, Protocol: "TCP"}, {Name: "metrics", Port: 9090, Protocol: "TCP"}}
+	web := []serviceTargetPort{{Port: 80, Protocol: "TCP", TargetName: "http"}}
+	metricsOnly := policy("metrics", []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": "metrics"}}}})
+	if got := restrictingNetworkPolicies(pods, web, []unstructured.Unstructured{metricsOnly}); !reflect.DeepEqual(got, []string{"shop/metrics"}) {
+		t.Errorf("a rule opening only the metrics port should restrict the http port, got %v", got)
+	}
+	httpByNumber := policy("http", []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(8080)}}}})
+	if got := restrictingNetworkPolicies(pods, web, []unstructured.Unstructured{httpByNumber}); len(got) != 0 {
+		t.Errorf("a rule opening the resolved target port should not restrict, got %v", got)
+	}
+}
+
+func TestApplyingAuthorizationPolicies(t *testing.T) {
+	pods := []podPorts{{Namespace: "shop", Name: "web-1", Labels: map[string]string{"app": "web"}}}
+	ap := func(ns, name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": ns},
+			"spec":     spec,
+		}}
+	}
+	policies := []unstructured.Unstructured{
+		ap("shop", "other-app", map[string]interface{}{"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "db"}}}),
+		ap("shop", "web-svc", map[string]interface{}{"targetRefs": []interface{}{map[string]interface{}{"kind": "Service", "name": "web"}}}),
+		ap("payments", "ns-wide", map[string]interface{}{}),
+	}
+	if got := applyingAuthorizationPolicies(pods, "shop", "web", policies); !reflect.DeepEqual(got, []string{"shop/web-svc"}) {
+		t.Errorf("applyingAuthorizationPolicies() = %v", got)
+	}
+
+	policies = append(policies, ap(istioRootNamespace, "mesh-wide", map[string]interface{}{}))
+	if got := applyingAuthorizationPolicies(pods, "shop", "web", policies); len(got) != 2 {
+		t.Errorf("expected the root namespace policy to apply, got %v", got)
+	}
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	for addr, want := range map[string]bool{"10.0.0.5": false, "192.168.1.10": false, "34.117.59.81": true, "lb.example.com": true} {
+		if got := isPublicAddress(addr); got != want {
+			t.Errorf("isPublicAddress(%q) = %t, want %t", addr, got, want)
+		}
+	}
+}
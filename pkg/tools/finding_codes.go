@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// --- list_finding_codes ---
+
+// ListFindingCodesTool lists the catalog of machine-readable finding codes so
+// automation can key remediation off codes instead of summaries.
+type ListFindingCodesTool struct{ BaseTool }
+
+func (t *ListFindingCodesTool) Name() string { return "list_finding_codes" }
+func (t *ListFindingCodesTool) Description() string {
+	return "List the stable finding codes (e.g. GW001_LISTENER_CONFLICT) that tools attach to warning and critical findings, with their category and meaning"
+}
+func (t *ListFindingCodesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"domain": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list codes of this domain prefix, e.g. GW, IST, DNS (default all)",
+			},
+			"category": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list codes of this category: routing, dns, tls, policy, mesh, connectivity or logs (default all)",
+			},
+		},
+	}
+}
+
+func (t *ListFindingCodesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	domain := getStringArg(args, "domain", "")
+	category := getStringArg(args, "category", "")
+
+	var findings []types.DiagnosticFinding
+	domains := make(map[string]bool)
+	for _, info := range types.FindingCodes() {
+		domains[info.Code.Domain()] = true
+		if domain != "" && !strings.EqualFold(domain, info.Code.Domain()) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(category, info.Category) {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: info.Category,
+			Code:     info.Code,
+			Summary:  info.Description,
+		})
+	}
+	if len(findings) == 0 && domain != "" && !domains[strings.ToUpper(domain)] {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unknown finding code domain %q", domain),
+			Detail:  "expected one of " + joinKeys(domains),
+		}
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
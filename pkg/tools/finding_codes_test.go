@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestFindingCodeCatalog(t *testing.T) {
+	format := regexp.MustCompile(`^[A-Z]+[0-9]{3}_[A-Z0-9_]+$`)
+	categories := map[string]bool{
+		types.CategoryRouting: true, types.CategoryDNS: true, types.CategoryTLS: true, types.CategoryPolicy: true,
+		types.CategoryMesh: true, types.CategoryConnectivity: true, types.CategoryLogs: true,
+	}
+	seen := make(map[types.FindingCode]bool)
+	numbers := make(map[string]bool)
+	for _, info := range types.FindingCodes() {
+		if !format.MatchString(string(info.Code)) {
+			t.Errorf("code %s does not match <DOMAIN><NNN>_<NAME>", info.Code)
+		}
+		if seen[info.Code] {
+			t.Errorf("code %s listed twice", info.Code)
+		}
+		seen[info.Code] = true
+		number := strings.SplitN(string(info.Code), "_", 2)[0]
+		if numbers[number] {
+			t.Errorf("number %s used by two codes", number)
+		}
+		numbers[number] = true
+		if !categories[info.Category] {
+			t.Errorf("code %s has unknown category %q", info.Code, info.Category)
+		}
+		if info.Description == "" {
+			t.Errorf("code %s has no description", info.Code)
+		}
+		if got, ok := types.LookupFindingCode(info.Code); !ok || got != info {
+			t.Errorf("LookupFindingCode(%s) = %+v, %t", info.Code, got, ok)
+		}
+	}
+	if got := types.CodeIstioSubsetMissing.Domain(); got != "IST" {
+		t.Errorf("Domain() = %q, want IST", got)
+	}
+}
+
+func TestListFindingCodes(t *testing.T) {
+	tool := &ListFindingCodesTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"domain": "gw", "category": types.CategoryTLS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) == 0 {
+		t.Fatal("expected TLS codes of the GW domain")
+	}
+	for _, f := range findings {
+		if f.Code.Domain() != "GW" || f.Category != types.CategoryTLS {
+			t.Errorf("unexpected code %s (%s)", f.Code, f.Category)
+		}
+	}
+	if text := types.FindingsToText(findings[:1]); !strings.Contains(text, "[GW002_CERT_SECRET_MISSING] ") {
+		t.Errorf("text output lacks the code:\n%s", text)
+	}
+
+	_, err = tool.Run(context.Background(), map[string]interface{}{"domain": "XYZ"})
+	var mcpErr *types.MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeInvalidInput {
+		t.Errorf("expected invalid input error for unknown domain, got %v", err)
+	}
+}
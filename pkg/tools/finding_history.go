@@ -0,0 +1,425 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// RecordsFindingHistory reports whether the findings of t belong in the
+// finding history; those of the tools reading it do not.
+func RecordsFindingHistory(t Tool) bool {
+	switch t.(type) {
+	case *GetFindingHistoryTool, *GetFindingTrendsTool:
+		return false
+	}
+	return true
+}
+
+// FindingRun converts the findings of one tool call with args for the
+// finding history. ok findings, and findings with neither code nor resource,
+// are left out.
+func FindingRun(tool string, args map[string]interface{}, at time.Time, findings []types.DiagnosticFinding) *history.FindingRun {
+	run := &history.FindingRun{Time: at, Tool: tool, Scope: findingScope(args)}
+	for _, f := range findings {
+		if f.Severity == types.SeverityOK || (f.Code == "" && f.Resource == nil) {
+			continue
+		}
+		rec := history.FindingRecord{Code: string(f.Code), Severity: f.Severity, Category: f.Category, Summary: f.Summary}
+		if f.Resource != nil {
+			rec.Kind, rec.Namespace, rec.Name = f.Resource.Kind, f.Resource.Namespace, f.Resource.Name
+		}
+		run.Findings = append(run.Findings, rec)
+	}
+	return run
+}
+
+// findingScopeIgnoredArgs change how a call reports, not what it inspects.
+var findingScopeIgnoredArgs = map[string]bool{
+	"cluster":        true,
+	"output_format":  true,
+	"detail":         true,
+	"limit":          true,
+	"continue_token": true,
+}
+
+// findingScope returns the scope of a call with args, so that a later call
+// only resolves the findings it inspected again.
+func findingScope(args map[string]interface{}) history.FindingScope {
+	scope := history.FindingScope{Namespace: getStringArg(args, "namespace", "")}
+	var parts []string
+	for key, value := range args {
+		if key == "namespace" || findingScopeIgnoredArgs[key] || value == nil || value == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(parts)
+	scope.Args = strings.Join(parts, ",")
+	return scope
+}
+
+// filterFindingRun keeps the records of run matching match; the run stays
+// even when empty, as it still tells when findings were resolved.
+func filterFindingRun(run *history.FindingRun, match func(history.FindingRecord) bool) *history.FindingRun {
+	filtered := &history.FindingRun{Time: run.Time, Tool: run.Tool, Scope: run.Scope}
+	for _, rec := range run.Findings {
+		if match(rec) {
+			filtered.Findings = append(filtered.Findings, rec)
+		}
+	}
+	return filtered
+}
+
+// findingFilter builds the record filter of the code, tool and resource
+// arguments.
+func findingFilter(args map[string]interface{}) func(history.FindingRecord) bool {
+	code := strings.ToUpper(getStringArg(args, "code", ""))
+	kind := getStringArg(args, "kind", "")
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+	return func(rec history.FindingRecord) bool {
+		return (code == "" || strings.HasPrefix(rec.Code, code)) && rec.MatchesResource(kind, ns, name)
+	}
+}
+
+var findingFilterProperties = map[string]interface{}{
+	"code": map[string]interface{}{
+		"type":        "string",
+		"description": "Only findings with this code or code prefix, e.g. GW003 or DNS",
+	},
+	"kind": map[string]interface{}{
+		"type":        "string",
+		"description": "Only findings about this resource kind, e.g. HTTPRoute",
+	},
+	"namespace": map[string]interface{}{
+		"type":        "string",
+		"description": "Only findings about resources in this namespace",
+	},
+	"name": map[string]interface{}{
+		"type":        "string",
+		"description": "Only findings about resources with this name",
+	},
+}
+
+// formatSpan renders d in days, hours or minutes.
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// --- get_finding_history ---
+
+type GetFindingHistoryTool struct {
+	BaseTool
+	Findings *history.FindingStore
+}
+
+func (t *GetFindingHistoryTool) Name() string { return "get_finding_history" }
+func (t *GetFindingHistoryTool) Description() string {
+	return "Look up the recorded history of findings emitted by earlier tool calls: when each finding was first and last seen, how often, and whether it is still open or was resolved, to tell a new misconfiguration from one that has existed for weeks"
+}
+func (t *GetFindingHistoryTool) InputSchema() map[string]interface{} {
+	props := map[string]interface{}{
+		"since": map[string]interface{}{
+			"type":        "string",
+			"description": "Only findings last seen after this duration before now (e.g. 24h) or RFC3339 time (default: the whole history)",
+		},
+		"status": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"all", "open", "resolved"},
+			"description": "Only open or resolved findings (default all)",
+		},
+	}
+	for k, v := range findingFilterProperties {
+		props[k] = v
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (t *GetFindingHistoryTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	now := time.Now()
+	var since time.Time
+	if v := getStringArg(args, "since", ""); v != "" {
+		var err error
+		if since, err = parseHistoryTime(v, now); err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("invalid since: %v", err),
+				Detail:  "use a duration before now (e.g. 24h) or an RFC3339 time",
+			}
+		}
+	}
+	status := getStringArg(args, "status", "all")
+	if status != "all" && status != "open" && status != "resolved" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid status %q: expected all, open or resolved", status),
+		}
+	}
+
+	summarizer := history.NewSummarizer()
+	match := findingFilter(args)
+	var calls int
+	var start time.Time
+	err := t.Findings.Scan(time.Time{}, func(run *history.FindingRun) bool {
+		if calls == 0 {
+			start = run.Time
+		}
+		calls++
+		summarizer.Add(filterFindingRun(run, match))
+		return ctx.Err() == nil
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read finding history: %w", err)
+	}
+	summaries := summarizer.Summaries()
+	var findings []types.DiagnosticFinding
+	open, resolved := 0, 0
+	for _, fs := range summaries {
+		if fs.LastSeen.Before(since) || (status == "open" && !fs.Open) || (status == "resolved" && fs.Open) {
+			continue
+		}
+		f := types.DiagnosticFinding{
+			Severity: fs.Severity,
+			Category: fs.Category,
+			Code:     types.FindingCode(fs.Code),
+			Summary:  fmt.Sprintf("%s (first seen %s ago, %d observation(s))", fs.Summary, formatSpan(now.Sub(fs.FirstSeen)), fs.Observations),
+			Detail: fmt.Sprintf("first seen %s; last seen %s; reported by %s", fs.FirstSeen.Format(time.RFC3339),
+				fs.LastSeen.Format(time.RFC3339), strings.Join(fs.Tools, ", ")),
+		}
+		if fs.Kind != "" {
+			f.Resource = &types.ResourceRef{Kind: fs.Kind, Namespace: fs.Namespace, Name: fs.Name}
+		}
+		if fs.Open {
+			open++
+		} else {
+			resolved++
+			f.Severity = types.SeverityOK
+			f.Summary = fmt.Sprintf("Resolved %s ago: %s (seen for %s, %d observation(s))", formatSpan(now.Sub(fs.ResolvedAt)), fs.Summary,
+				formatSpan(fs.LastSeen.Sub(fs.FirstSeen)), fs.Observations)
+		}
+		findings = append(findings, f)
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Summary:  fmt.Sprintf("%d finding(s): %d open, %d resolved, over %d recorded tool call(s)", open+resolved, open, resolved, calls),
+	}
+	if calls > 0 {
+		summary.Detail = "history starts " + start.Format(time.RFC3339)
+	} else {
+		summary.Suggestion = "The history is empty: it records the findings of tool calls made since the server started, or since FINDING_HISTORY_DIR was set."
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(getStringArg(args, "namespace", ""), "all"), ""), nil
+}
+
+// --- get_finding_trends ---
+
+type GetFindingTrendsTool struct {
+	BaseTool
+	Findings *history.FindingStore
+}
+
+func (t *GetFindingTrendsTool) Name() string { return "get_finding_trends" }
+func (t *GetFindingTrendsTool) Description() string {
+	return "Count the distinct findings recorded per hour or day by severity, with the new findings of each interval and the codes that changed most, to see whether the cluster's configuration is getting better or worse"
+}
+func (t *GetFindingTrendsTool) InputSchema() map[string]interface{} {
+	props := map[string]interface{}{
+		"since": map[string]interface{}{
+			"type":        "string",
+			"description": "Window start as a duration before now (e.g. 168h) or RFC3339 time (default 168h)",
+		},
+		"interval": map[string]interface{}{
+			"type":        "string",
+			"description": "Bucket size, e.g. 1h or 24h (default 24h; at most 500 buckets)",
+		},
+	}
+	for k, v := range findingFilterProperties {
+		props[k] = v
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (t *GetFindingTrendsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	now := time.Now()
+	since, err := parseHistoryTime(getStringArg(args, "since", "168h"), now)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid since: %v", err),
+			Detail:  "use a duration before now (e.g. 168h) or an RFC3339 time",
+		}
+	}
+	interval, err := time.ParseDuration(getStringArg(args, "interval", "24h"))
+	if err != nil || interval < time.Minute || now.Sub(since)/interval > 500 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "invalid interval: expected a duration of at least 1m giving at most 500 buckets",
+		}
+	}
+
+	trend := history.NewTrendCounter(since, now, interval)
+	movements := newCodeMovements(since, now)
+	match := findingFilter(args)
+	var start time.Time
+	err = t.Findings.Scan(time.Time{}, func(run *history.FindingRun) bool {
+		if start.IsZero() {
+			start = run.Time
+		}
+		run = filterFindingRun(run, match)
+		trend.Add(run)
+		movements.add(run)
+		return ctx.Err() == nil
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read finding history: %w", err)
+	}
+	buckets := trend.Buckets()
+	var findings []types.DiagnosticFinding
+	for _, b := range buckets {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary: fmt.Sprintf("%s: %d critical, %d warning, %d info (%d new)", b.Start.Format("2006-01-02 15:04"),
+				b.BySeverity[types.SeverityCritical], b.BySeverity[types.SeverityWarning], b.BySeverity[types.SeverityInfo], b.New),
+		})
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Summary:  fmt.Sprintf("Finding trend over %d interval(s) of %s since %s", len(buckets), interval, since.Format(time.RFC3339)),
+	}
+	if first, last := firstActiveBucket(buckets), lastActiveBucket(buckets); first >= 0 && first != last {
+		a, b := buckets[first], buckets[last]
+		summary.Summary += fmt.Sprintf(": critical %d → %d, warning %d → %d", a.BySeverity[types.SeverityCritical], b.BySeverity[types.SeverityCritical],
+			a.BySeverity[types.SeverityWarning], b.BySeverity[types.SeverityWarning])
+		if b.BySeverity[types.SeverityCritical] > a.BySeverity[types.SeverityCritical] {
+			summary.Severity = types.SeverityWarning
+		}
+	}
+	if moves := movements.String(); moves != "" {
+		summary.Detail = "codes with the largest change: " + moves
+	}
+	if start.IsZero() || start.After(since) {
+		summary.Suggestion = "The history does not cover the whole window: it only holds tool calls made while the server recorded them (FINDING_HISTORY_RETENTION)."
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(getStringArg(args, "namespace", ""), "all"), ""), nil
+}
+
+// firstActiveBucket returns the index of the first bucket with findings, or -1.
+func firstActiveBucket(buckets []history.TrendBucket) int {
+	for i, b := range buckets {
+		if b.Total() > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastActiveBucket returns the index of the last bucket with findings, or -1.
+func lastActiveBucket(buckets []history.TrendBucket) int {
+	for i := len(buckets) - 1; i >= 0; i-- {
+		if buckets[i].Total() > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// codeMovements compares the distinct resources per code in the first and
+// second half of a window.
+type codeMovements struct {
+	since, mid time.Time
+	halves     [2]map[string]map[string]bool
+}
+
+func newCodeMovements(since, now time.Time) *codeMovements {
+	return &codeMovements{since: since, mid: since.Add(now.Sub(since) / 2), halves: [2]map[string]map[string]bool{{}, {}}}
+}
+
+func (cm *codeMovements) add(run *history.FindingRun) {
+	if run.Time.Before(cm.since) {
+		return
+	}
+	h := 0
+	if !run.Time.Before(cm.mid) {
+		h = 1
+	}
+	for _, rec := range run.Findings {
+		if rec.Code == "" {
+			continue
+		}
+		if cm.halves[h][rec.Code] == nil {
+			cm.halves[h][rec.Code] = make(map[string]bool)
+		}
+		cm.halves[h][rec.Code][rec.Key()] = true
+	}
+}
+
+// String renders the five largest changes.
+func (cm *codeMovements) String() string {
+	halves := cm.halves
+	type move struct {
+		code          string
+		before, after int
+	}
+	codes := make(map[string]bool)
+	for _, h := range halves {
+		for code := range h {
+			codes[code] = true
+		}
+	}
+	var moves []move
+	for code := range codes {
+		if m := (move{code, len(halves[0][code]), len(halves[1][code])}); m.before != m.after {
+			moves = append(moves, m)
+		}
+	}
+	change := func(m move) int {
+		if m.after > m.before {
+			return m.after - m.before
+		}
+		return m.before - m.after
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if change(moves[i]) != change(moves[j]) {
+			return change(moves[i]) > change(moves[j])
+		}
+		return moves[i].code < moves[j].code
+	})
+	parts := make([]string, 0, 5)
+	for i, m := range moves {
+		if i == 5 {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d → %d", m.code, m.before, m.after))
+	}
+	return strings.Join(parts, ", ")
+}
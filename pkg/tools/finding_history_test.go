@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/history"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestFindingRun(t *testing.T) {
+	run := FindingRun("check_dns_resolution", nil, time.Now(), []types.DiagnosticFinding{
+		{Severity: types.SeverityOK, Summary: "CoreDNS healthy"},
+		{Severity: types.SeverityInfo, Summary: "3 Services checked"},
+		{Severity: types.SeverityCritical, Code: "DNS001", Resource: &types.ResourceRef{Kind: "Service", Namespace: "kube-system", Name: "kube-dns"}, Summary: "no endpoints"},
+	})
+	if len(run.Findings) != 1 || run.Findings[0].Key() != "DNS001|Service/kube-system/kube-dns" {
+		t.Errorf("FindingRun() = %+v, want only the DNS001 finding", run.Findings)
+	}
+	scope := FindingRun("diagnose_route", map[string]interface{}{
+		"namespace": "shop", "route_name": "web", "output_format": "sarif", "detail": true, "kind": "",
+	}, time.Now(), nil).Scope
+	if scope != (history.FindingScope{Namespace: "shop", Args: "route_name=web"}) {
+		t.Errorf("FindingRun() scope = %+v, want namespace shop and route_name only", scope)
+	}
+	if RecordsFindingHistory(&GetFindingHistoryTool{}) || !RecordsFindingHistory(&QuickScanTool{}) {
+		t.Error("RecordsFindingHistory() should exclude the finding history tools only")
+	}
+}
+
+func TestGetFindingHistory(t *testing.T) {
+	store, _ := history.NewFindingStore(168*time.Hour, "")
+	now := time.Now()
+	route := &types.ResourceRef{Kind: "HTTPRoute", Namespace: "shop", Name: "web"}
+	svc := &types.ResourceRef{Kind: "Service", Namespace: "kube-system", Name: "kube-dns"}
+	for _, run := range []*history.FindingRun{
+		FindingRun("scan_gateway_misconfigs", nil, now.Add(-72*time.Hour), []types.DiagnosticFinding{{Severity: types.SeverityWarning, Code: "GW003", Resource: route, Summary: "backend missing"}}),
+		FindingRun("check_dns_resolution", nil, now.Add(-5*time.Hour), []types.DiagnosticFinding{{Severity: types.SeverityCritical, Code: "DNS001", Resource: svc, Summary: "no endpoints"}}),
+		FindingRun("check_dns_resolution", nil, now.Add(-3*time.Hour), nil),
+		FindingRun("scan_gateway_misconfigs", nil, now.Add(-time.Hour), []types.DiagnosticFinding{{Severity: types.SeverityWarning, Code: "GW003", Resource: route, Summary: "backend missing"}}),
+	} {
+		_ = store.Add(run)
+	}
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}}
+	tool := &GetFindingHistoryTool{BaseTool: base, Findings: store}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info 2 finding(s): 1 open, 1 resolved, over 4 recorded tool call(s)",
+		"warning backend missing (first seen 3d ago, 2 observation(s)) GW003",
+		"ok Resolved 3h ago: no endpoints (seen for 0m, 1 observation(s)) DNS001",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"status": "open", "code": "dns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "0 finding(s)") {
+		t.Errorf("expected no open DNS finding in:\n%s", all)
+	}
+
+	trends := &GetFindingTrendsTool{BaseTool: base, Findings: store}
+	resp, err = trends.Run(context.Background(), map[string]interface{}{"since": "96h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "Finding trend over 4 interval(s) of 24h0m0s") || !strings.Contains(all, "1 critical, 1 warning, 0 info (1 new)") {
+		t.Errorf("unexpected trends:\n%s", all)
+	}
+	if _, err := trends.Run(context.Background(), map[string]interface{}{"interval": "1m", "since": "720h"}); err == nil {
+		t.Error("expected an error for more than 500 buckets")
+	}
+}
@@ -6,13 +6,13 @@ import (
 	"strings"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
@@ -315,7 +315,7 @@ func (t *ListGatewaysTool) Description() string {
 	return "List Gateway API gateways with listeners, status conditions, and attached route count"
 }
 func (t *ListGatewaysTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -323,14 +323,18 @@ func (t *ListGatewaysTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListGatewaysTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	list, err := listWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	list, err := t.listResourcePageWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -419,7 +423,7 @@ func (t *ListGatewaysTool) Run(ctx context.Context, args map[string]interface{})
 		findings = append(findings, finding)
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_gateway ---
@@ -563,6 +567,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 									findings = append(findings, types.DiagnosticFinding{
 										Severity:   types.SeverityWarning,
 										Category:   types.CategoryTLS,
+										Code:       types.CodeGatewayCertSecretMissing,
 										Resource:   gwRef,
 										Summary:    fmt.Sprintf("Listener %s: certificateRef Secret %s/%s not found", lName, certNs, certName),
 										Suggestion: fmt.Sprintf("Create Secret %s/%s with TLS certificate data", certNs, certName),
@@ -576,6 +581,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryTLS,
+						Code:       types.CodeGatewayTLSCertRefsMissing,
 						Resource:   gwRef,
 						Summary:    fmt.Sprintf("Listener %s: HTTPS/TLS with mode=%s but no certificateRefs", lName, tlsMode),
 						Suggestion: "Add certificateRefs pointing to a TLS Secret",
@@ -587,6 +593,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryTLS,
+						Code:       types.CodeGatewayPassthroughWithCerts,
 						Resource:   gwRef,
 						Summary:    fmt.Sprintf("Listener %s: TLS mode=Passthrough but certificateRefs are set (contradiction)", lName),
 						Suggestion: "Remove certificateRefs for Passthrough mode, or change mode to Terminate",
@@ -605,9 +612,11 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 		}
 
 		severity := types.SeverityInfo
+		var code types.FindingCode
 		suggestion := ""
 		if hasUnhealthyCondition(listenerConditions) {
 			severity = types.SeverityWarning
+			code = types.CodeGatewayListenerUnhealthy
 			// Add specific condition problems to summary
 			for _, c := range listenerConditions {
 				if cm, ok := c.(map[string]interface{}); ok {
@@ -634,6 +643,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   severity,
 			Category:   types.CategoryRouting,
+			Code:       code,
 			Resource:   gwRef,
 			Summary:    lSummary,
 			Detail:     lDetail,
@@ -656,6 +666,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayConditionFalse,
 				Resource:   gwRef,
 				Summary:    fmt.Sprintf("Gateway condition %s=%s reason=%s", condType, status, reason),
 				Detail:     message,
@@ -665,7 +676,7 @@ func (t *GetGatewayTool) Run(ctx context.Context, args map[string]interface{}) (
 	}
 
 	// Find attached HTTPRoutes
-	routeList, _ := listWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
+	routeList, _ := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
 	if routeList != nil {
 		for _, route := range routeList.Items {
 			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
@@ -703,7 +714,7 @@ func (t *ListHTTPRoutesTool) Description() string {
 	return "List HTTPRoutes with parent refs, backend refs, and rule count"
 }
 func (t *ListHTTPRoutesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -711,14 +722,18 @@ func (t *ListHTTPRoutesTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListHTTPRoutesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	list, err := listWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
+	list, err := t.listResourcePageWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -791,8 +806,10 @@ func (t *ListHTTPRoutesTool) Run(ctx context.Context, args map[string]interface{
 		}
 
 		severity := types.SeverityInfo
+		var code types.FindingCode
 		if hasStatusProblem {
 			severity = types.SeverityWarning
+			code = types.CodeGatewayRouteConditionFalse
 		}
 
 		detail := ""
@@ -803,6 +820,7 @@ func (t *ListHTTPRoutesTool) Run(ctx context.Context, args map[string]interface{
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryRouting,
+			Code:     code,
 			Resource: &types.ResourceRef{
 				Kind:       "HTTPRoute",
 				Namespace:  item.GetNamespace(),
@@ -814,7 +832,7 @@ func (t *ListHTTPRoutesTool) Run(ctx context.Context, args map[string]interface{
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_httproute ---
@@ -994,6 +1012,7 @@ func (t *GetHTTPRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendServiceMissing,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Backend service %s/%s not found", refNs, refName),
 						Detail:     svcErr.Error(),
@@ -1020,6 +1039,7 @@ func (t *GetHTTPRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendNoEndpoints,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Backend service %s/%s has 0 ready endpoints", refNs, refName),
 						Suggestion: "Check that pods backing this service are running and passing readiness probes",
@@ -1072,6 +1092,7 @@ func (t *GetHTTPRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayRouteConditionFalse,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Route condition %s=%s for parent %s reason=%s", condType, status, pName, reason),
 						Detail:     message,
@@ -1094,7 +1115,7 @@ func (t *ListGRPCRoutesTool) Description() string {
 	return "List GRPCRoutes with parent refs, backend refs, and rule counts"
 }
 func (t *ListGRPCRoutesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -1102,14 +1123,18 @@ func (t *ListGRPCRoutesTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListGRPCRoutesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	list, err := listWithFallback(ctx, t.Clients.Dynamic, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ns)
+	list, err := t.listResourcePageWithFallback(ctx, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -1177,8 +1202,10 @@ func (t *ListGRPCRoutesTool) Run(ctx context.Context, args map[string]interface{
 		}
 
 		severity := types.SeverityInfo
+		var code types.FindingCode
 		if hasStatusProblem {
 			severity = types.SeverityWarning
+			code = types.CodeGatewayRouteConditionFalse
 		}
 
 		detail := ""
@@ -1189,6 +1216,7 @@ func (t *ListGRPCRoutesTool) Run(ctx context.Context, args map[string]interface{
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryRouting,
+			Code:     code,
 			Resource: &types.ResourceRef{
 				Kind:       "GRPCRoute",
 				Namespace:  item.GetNamespace(),
@@ -1200,7 +1228,7 @@ func (t *ListGRPCRoutesTool) Run(ctx context.Context, args map[string]interface{
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_grpcroute ---
@@ -1379,6 +1407,7 @@ func (t *GetGRPCRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendServiceMissing,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Backend service %s/%s not found", refNs, refName),
 						Detail:     svcErr.Error(),
@@ -1405,6 +1434,7 @@ func (t *GetGRPCRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendNoEndpoints,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Backend service %s/%s has 0 ready endpoints", refNs, refName),
 						Suggestion: "Check that pods backing this service are running and passing readiness probes",
@@ -1456,6 +1486,7 @@ func (t *GetGRPCRouteTool) Run(ctx context.Context, args map[string]interface{})
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayRouteConditionFalse,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("Route condition %s=%s for parent %s reason=%s", condType, status, pName, reason),
 						Detail:     message,
@@ -1478,7 +1509,7 @@ func (t *ListReferenceGrantsTool) Description() string {
 	return "List ReferenceGrants with from/to resource specifications for cross-namespace reference validation"
 }
 func (t *ListReferenceGrantsTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -1486,14 +1517,18 @@ func (t *ListReferenceGrantsTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListReferenceGrantsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	list, err := listWithFallback(ctx, t.Clients.Dynamic, refGrantsV1GVR, refGrantsV1B1GVR, ns)
+	list, err := t.listResourcePageWithFallback(ctx, refGrantsV1GVR, refGrantsV1B1GVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -1549,7 +1584,7 @@ func (t *ListReferenceGrantsTool) Run(ctx context.Context, args map[string]inter
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_referencegrant ---
@@ -1652,7 +1687,7 @@ func (t *GetReferenceGrantTool) Run(ctx context.Context, args map[string]interfa
 	}
 
 	// Find HTTPRoutes that reference backends in this grant's namespace
-	routeList, _ := listWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, "")
+	routeList, _ := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, "")
 	if routeList != nil {
 		for _, route := range routeList.Items {
 			routeNs := route.GetNamespace()
@@ -1695,6 +1730,7 @@ func (t *GetReferenceGrantTool) Run(ctx context.Context, args map[string]interfa
 						findings = append(findings, types.DiagnosticFinding{
 							Severity: types.SeverityWarning,
 							Category: types.CategoryPolicy,
+							Code:     types.CodeGatewayReferenceGrantMissing,
 							Resource: &types.ResourceRef{
 								Kind:       "HTTPRoute",
 								Namespace:  routeNs,
@@ -1719,7 +1755,7 @@ type ScanGatewayMisconfigsTool struct{ BaseTool }
 
 func (t *ScanGatewayMisconfigsTool) Name() string { return "scan_gateway_misconfigs" }
 func (t *ScanGatewayMisconfigsTool) Description() string {
-	return "Scan for Gateway API misconfigurations: missing backends, orphaned routes, missing ReferenceGrants, listener conflicts"
+	return "Scan for Gateway API misconfigurations: missing backends, backendRef port chains that do not reach a container port, HTTPS or h2c backends without a BackendTLSPolicy or appProtocol, invalid BackendTLSPolicies, orphaned routes, missing ReferenceGrants, listener conflicts, missing or unaccepted GatewayClasses, controllers that are not running, dangling parametersRefs"
 }
 func (t *ScanGatewayMisconfigsTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
@@ -1737,10 +1773,11 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 	ns := getStringArg(args, "namespace", "")
 
 	// Fetch all resources
-	gwList, _ := listWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns)
-	httpRouteList, _ := listWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
-	grpcRouteList, _ := listWithFallback(ctx, t.Clients.Dynamic, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ns)
-	refGrantList, _ := listWithFallback(ctx, t.Clients.Dynamic, refGrantsV1GVR, refGrantsV1B1GVR, ns)
+	gwList, _ := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	httpRouteList, _ := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
+	grpcRouteList, _ := t.listResourceWithFallback(ctx, grpcRoutesV1GVR, grpcRoutesV1B1GVR, ns)
+	refGrantList, _ := t.listResourceWithFallback(ctx, refGrantsV1GVR, refGrantsV1B1GVR, ns)
+	backendTLSList, _ := t.listBackendTLSPolicies(ctx, ns)
 
 	// Build lookup maps
 	// gatewaysByKey: "namespace/name" -> gateway listeners
@@ -1761,7 +1798,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 			for _, l := range listeners {
 				if lm, ok := l.(map[string]interface{}); ok {
 					lName, _ := lm["name"].(string)
-					port, _ := lm["port"].(float64)
+					port := float64(toInt(lm["port"]))
 					protocol, _ := lm["protocol"].(string)
 					info.listeners = append(info.listeners, listenerInfo{name: lName, port: port, protocol: protocol})
 				}
@@ -1772,6 +1809,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 
 	// refGrants: build set of allowed cross-namespace refs
 	refGrants := buildRefGrants(refGrantList)
+	backendTLS := newBackendTLSIndex(backendTLSList)
 
 	var findings []types.DiagnosticFinding
 
@@ -1787,6 +1825,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 					findings = append(findings, types.DiagnosticFinding{
 						Severity: types.SeverityWarning,
 						Category: types.CategoryRouting,
+						Code:     types.CodeGatewayListenerConflict,
 						Resource: &types.ResourceRef{
 							Kind:       "Gateway",
 							Namespace:  gw.GetNamespace(),
@@ -1816,6 +1855,27 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 		}
 	}
 
+	// backendPods lists the pods of a backend namespace once, for resolving
+	// Service targetPorts against container ports.
+	podsByNs := make(map[string][]podPorts)
+	backendPods := func(ns string) []podPorts {
+		if pods, ok := podsByNs[ns]; ok {
+			return pods
+		}
+		var pods []podPorts
+		if list, err := t.listResource(ctx, podsGVR, ns); err == nil {
+			for i := range list.Items {
+				if phase, _, _ := unstructured.NestedString(list.Items[i].Object, "status", "phase"); phase == "Succeeded" || phase == "Failed" {
+					continue
+				}
+				pods = append(pods, podPortsFrom(&list.Items[i]))
+			}
+		}
+		podsByNs[ns] = pods
+		return pods
+	}
+
+	validated := progress.NewCounter(ctx, "validated", len(allRoutes), "routes")
 	for _, route := range allRoutes {
 		routeRef := &types.ResourceRef{
 			Kind:       route.kind,
@@ -1848,11 +1908,12 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 				if refKind == "Service" {
 					// Validate the Service exists
 					svcKey := refNs + "/" + refName
-					_, err := t.Clients.Clientset.CoreV1().Services(refNs).Get(ctx, refName, metav1.GetOptions{})
+					_, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(refNs).Get(ctx, refName, metav1.GetOptions{})
 					if err != nil {
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewayMeshServiceMissing,
 							Resource:   routeRef,
 							Summary:    fmt.Sprintf("%s %s/%s references non-existent Service %s (GAMMA mesh route)", route.kind, route.namespace, route.name, svcKey),
 							Suggestion: fmt.Sprintf("Create Service %s or update the parentRef", svcKey),
@@ -1867,6 +1928,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeGatewayParentMissing,
 					Resource:   routeRef,
 					Summary:    fmt.Sprintf("%s %s/%s references non-existent gateway %s", route.kind, route.namespace, route.name, gwKey),
 					Suggestion: fmt.Sprintf("Create gateway %s or update the parentRef to an existing gateway", gwKey),
@@ -1884,6 +1946,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayParentListenerMissing,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("%s %s/%s references non-existent listener %q on gateway %s", route.kind, route.namespace, route.name, sectionName, gwKey),
 						Suggestion: fmt.Sprintf("Check listener names on gateway %s", gwKey),
@@ -1915,15 +1978,24 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 				}
 
 				// Check 3: Non-existent backend services
-				_, svcErr := t.Clients.Dynamic.Resource(servicesGVR).Namespace(refNs).Get(ctx, refName, metav1.GetOptions{})
+				svc, svcErr := t.Clients.Dynamic.Resource(servicesGVR).Namespace(refNs).Get(ctx, refName, metav1.GetOptions{})
 				if svcErr != nil {
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendServiceMissing,
 						Resource:   routeRef,
 						Summary:    fmt.Sprintf("%s %s/%s references non-existent backend service %s/%s", route.kind, route.namespace, route.name, refNs, refName),
 						Suggestion: "Create the backend service or update the backendRef",
 					})
+				} else if kind, _ := brm["kind"].(string); kind == "" || kind == "Service" {
+					// Check 3b: backendRef port -> Service targetPort -> container port
+					if port := int64(toInt(brm["port"])); port > 0 {
+						findings = append(findings, checkBackendPortChain(routeRef, route.kind, port, svc, backendPods(refNs))...)
+						if route.kind == "HTTPRoute" {
+							findings = append(findings, checkBackendProtocol(routeRef, port, svc, backendTLS)...)
+						}
+					}
 				}
 
 				// Check 4: Cross-namespace references missing ReferenceGrants
@@ -1946,6 +2018,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has RequestRedirect filter with missing requestRedirect config", route.kind, route.namespace, route.name),
 								Suggestion: "Add requestRedirect configuration to the filter",
@@ -1956,6 +2029,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has URLRewrite filter with missing urlRewrite config", route.kind, route.namespace, route.name),
 								Suggestion: "Add urlRewrite configuration to the filter",
@@ -1966,6 +2040,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has RequestHeaderModifier filter with missing requestHeaderModifier config", route.kind, route.namespace, route.name),
 								Suggestion: "Add requestHeaderModifier configuration to the filter",
@@ -1976,6 +2051,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has ResponseHeaderModifier filter with missing responseHeaderModifier config", route.kind, route.namespace, route.name),
 								Suggestion: "Add responseHeaderModifier configuration to the filter",
@@ -1986,6 +2062,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has RequestMirror filter with missing requestMirror config", route.kind, route.namespace, route.name),
 								Suggestion: "Add requestMirror configuration to the filter",
@@ -1996,6 +2073,7 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewayFilterConfigMissing,
 								Resource:   routeRef,
 								Summary:    fmt.Sprintf("%s %s/%s has ExtensionRef filter with missing extensionRef config", route.kind, route.namespace, route.name),
 								Suggestion: "Add extensionRef configuration to the filter",
@@ -2005,11 +2083,23 @@ func (t *ScanGatewayMisconfigsTool) Run(ctx context.Context, args map[string]int
 				}
 			}
 		}
+		validated.Add(1)
 	}
 
 	// --- Check 6: Waypoint proxy health for GAMMA mesh routes ---
 	findings = append(findings, t.checkWaypointProxies(ctx, allRoutes, gwList)...)
 
+	// --- Check 7: BackendTLSPolicy targets and validation ---
+	if backendTLSList != nil && len(backendTLSList.Items) > 0 {
+		services := t.servicesByKey(ctx, ns)
+		for i := range backendTLSList.Items {
+			findings = append(findings, checkBackendTLSPolicy(&backendTLSList.Items[i], services)...)
+		}
+	}
+
+	// --- Check 8: GatewayClasses, their controllers and parametersRefs ---
+	findings = append(findings, t.checkGatewayInfrastructure(ctx, gwList)...)
+
 	if len(findings) == 0 {
 		responseNs := ns
 		if responseNs == "" {
@@ -2082,18 +2172,18 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 	checkedNs := make(map[string]bool)
 	for ref := range seen {
 		// Check service-level waypoint label
-		svc, svcErr := t.Clients.Clientset.CoreV1().Services(ref.ns).Get(ctx, ref.name, metav1.GetOptions{})
+		svc, svcErr := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ref.ns).Get(ctx, ref.name, metav1.GetOptions{})
 		var waypointName string
 		if svcErr == nil {
-			waypointName = svc.Labels["istio.io/use-waypoint"]
+			waypointName = svc.GetLabels()["istio.io/use-waypoint"]
 		}
 
 		// If no service-level label, check namespace-level
 		if waypointName == "" && !checkedNs[ref.ns] {
 			checkedNs[ref.ns] = true
-			nsObj, nsErr := t.Clients.Clientset.CoreV1().Namespaces().Get(ctx, ref.ns, metav1.GetOptions{})
+			nsObj, nsErr := t.Clients.Dynamic.Resource(namespacesGVR).Get(ctx, ref.ns, metav1.GetOptions{})
 			if nsErr == nil {
-				waypointName = nsObj.Labels["istio.io/use-waypoint"]
+				waypointName = nsObj.GetLabels()["istio.io/use-waypoint"]
 			}
 		}
 
@@ -2115,6 +2205,7 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayWaypointMissing,
 				Resource:   svcRef,
 				Summary:    fmt.Sprintf("Service %s/%s has istio.io/use-waypoint=%s but waypoint Gateway %s not found", ref.ns, ref.name, waypointName, waypointKey),
 				Suggestion: "Create a waypoint Gateway with GatewayClass istio-waypoint, or remove the label",
@@ -2140,6 +2231,7 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayWaypointNotProgrammed,
 				Resource:   svcRef,
 				Summary:    fmt.Sprintf("Waypoint Gateway %s is not Programmed for service %s/%s", waypointKey, ref.ns, ref.name),
 				Suggestion: "Check waypoint Gateway status and istio-waypoint GatewayClass controller",
@@ -2147,7 +2239,7 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 		}
 
 		// Check waypoint pod readiness
-		wpPods, podErr := t.Clients.Clientset.CoreV1().Pods(ref.ns).List(ctx, metav1.ListOptions{
+		wpPods, podErr := t.Clients.Dynamic.Resource(podsGVR).Namespace(ref.ns).List(ctx, metav1.ListOptions{
 			LabelSelector: fmt.Sprintf("gateway.networking.k8s.io/gateway-name=%s", waypointName),
 			Limit:         10,
 		})
@@ -2156,12 +2248,16 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 			ready := 0
 			for _, pod := range wpPods.Items {
 				podReady := true
-				for _, cs := range pod.Status.ContainerStatuses {
-					if !cs.Ready {
-						podReady = false
+				statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+				for _, cs := range statuses {
+					if csMap, ok := cs.(map[string]interface{}); ok {
+						if ready, _, _ := unstructured.NestedBool(csMap, "ready"); !ready {
+							podReady = false
+						}
 					}
 				}
-				if podReady && pod.Status.Phase == corev1.PodRunning {
+				phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+				if podReady && phase == "Running" {
 					ready++
 				}
 			}
@@ -2169,6 +2265,7 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeGatewayWaypointPodsUnready,
 					Resource:   svcRef,
 					Summary:    fmt.Sprintf("Waypoint proxy pods for %s: %d/%d ready", waypointKey, ready, total),
 					Suggestion: "Check waypoint proxy pod logs and events",
@@ -2178,6 +2275,7 @@ func (t *ScanGatewayMisconfigsTool) checkWaypointProxies(ctx context.Context, al
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayWaypointPodsUnready,
 				Resource:   svcRef,
 				Summary:    fmt.Sprintf("Waypoint Gateway %s has no proxy pods", waypointKey),
 				Suggestion: "Verify the waypoint Gateway controller is deploying proxy pods",
@@ -2230,12 +2328,14 @@ var (
 	validGRPCMethodTypes   = map[string]bool{"Exact": true, "RegularExpression": true}
 	validGRPCFilterTypes   = map[string]bool{"RequestHeaderModifier": true, "ResponseHeaderModifier": true, "RequestMirror": true, "ExtensionRef": true}
 
-	// Extended conformance features (not in core profile).
-	extendedProtocols      = map[string]bool{"TLS": true, "TCP": true, "UDP": true}
-	extendedTLSModes       = map[string]bool{"Passthrough": true}
-	extendedPathMatchTypes = map[string]bool{"RegularExpression": true}
-	extendedHTTPFilters    = map[string]bool{"URLRewrite": true, "ResponseHeaderModifier": true, "RequestMirror": true}
-	extendedGRPCMethods    = map[string]bool{"RegularExpression": true}
+	// Extended conformance features (not in core profile), mapped to the
+	// GatewayClass supportedFeatures name advertising them. "" marks features
+	// the specification leaves implementation-specific.
+	extendedProtocols      = map[string]string{"TLS": "TLSRoute", "TCP": "TCPRoute", "UDP": "UDPRoute"}
+	extendedTLSModes       = map[string]string{"Passthrough": "TLSRoute"}
+	extendedPathMatchTypes = map[string]string{"RegularExpression": ""}
+	extendedHTTPFilters    = map[string]string{"URLRewrite": "HTTPRoutePathRewrite", "ResponseHeaderModifier": "HTTPRouteResponseHeaderModification", "RequestMirror": "HTTPRouteRequestMirror"}
+	extendedGRPCMethods    = map[string]string{"RegularExpression": ""}
 )
 
 func (t *CheckGatewayConformanceTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
@@ -2291,6 +2391,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 		return []types.DiagnosticFinding{{
 			Severity: types.SeverityWarning,
 			Category: types.CategoryRouting,
+			Code:     types.CodeGatewayResourceNotFound,
 			Resource: &types.ResourceRef{Kind: "Gateway", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"},
 			Summary:  fmt.Sprintf("Gateway %s/%s not found: %v", ns, name, err),
 		}}
@@ -2298,7 +2399,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 
 	ref := &types.ResourceRef{Kind: "Gateway", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"}
 	var findings []types.DiagnosticFinding
-	extendedFeatures := make(map[string]bool)
+	extendedFeatures := make(map[string]string)
 
 	// Validate gatewayClassName (required)
 	gatewayClass := getNestedString(gw.Object, "spec", "gatewayClassName")
@@ -2306,6 +2407,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewaySpecFieldMissing,
 			Resource:   ref,
 			Summary:    "spec.gatewayClassName is required but missing",
 			Suggestion: "Set spec.gatewayClassName to a valid GatewayClass name",
@@ -2318,6 +2420,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewaySpecFieldMissing,
 			Resource:   ref,
 			Summary:    "spec.listeners is required but empty or missing",
 			Suggestion: "Add at least one listener to the Gateway",
@@ -2341,6 +2444,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecFieldMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("%s: name is required but missing", prefix),
 				Suggestion: "Set a unique name for each listener",
@@ -2353,6 +2457,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecFieldMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("%s: protocol is required but missing", prefix),
 				Suggestion: "Set protocol to one of: HTTP, HTTPS, TLS, TCP, UDP",
@@ -2361,21 +2466,29 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecInvalidValue,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("%s: invalid protocol %q", prefix, protocol),
 				Detail:     "Valid protocols: HTTP, HTTPS, TLS, TCP, UDP",
 				Suggestion: "Use a valid Gateway API protocol value",
 			})
-		} else if extendedProtocols[protocol] {
-			extendedFeatures["protocol "+protocol] = true
+		} else if feature, ok := extendedProtocols[protocol]; ok {
+			extendedFeatures["protocol "+protocol] = feature
 		}
 
 		// port validation (1-65535)
-		port, portOk := lm["port"].(float64)
+		// Live objects decode integers as int64, manifests as float64.
+		var portOk bool
+		switch lm["port"].(type) {
+		case int64, float64:
+			portOk = true
+		}
+		port := float64(toInt(lm["port"]))
 		if !portOk {
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecFieldMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("%s: port is required but missing or invalid", prefix),
 				Suggestion: "Set port to a value between 1 and 65535",
@@ -2384,6 +2497,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecInvalidValue,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("%s: port %d is out of range (1-65535)", prefix, int(port)),
 				Suggestion: "Set port to a value between 1 and 65535",
@@ -2397,6 +2511,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeGatewaySpecFieldMissing,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("%s: tls configuration is required for %s protocol", prefix, protocol),
 					Suggestion: "Add tls configuration with certificateRefs",
@@ -2409,13 +2524,14 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s: invalid TLS mode %q", prefix, mode),
 							Detail:     "Valid TLS modes: Terminate, Passthrough",
 							Suggestion: "Use a valid TLS mode",
 						})
-					} else if extendedTLSModes[mode] {
-						extendedFeatures["TLS mode "+mode] = true
+					} else if feature, ok := extendedTLSModes[mode]; ok {
+						extendedFeatures["TLS mode "+mode] = feature
 					}
 				}
 
@@ -2426,6 +2542,7 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewayTLSCertRefsMissing,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s: tls.certificateRefs is required for TLS Terminate mode", prefix),
 							Suggestion: "Add at least one certificateRef pointing to a TLS Secret",
@@ -2436,20 +2553,12 @@ func (t *CheckGatewayConformanceTool) validateGateway(ctx context.Context, ns, n
 		}
 	}
 
-	// Report extended profile if any extended features detected
-	if len(extendedFeatures) > 0 {
-		featureList := make([]string, 0, len(extendedFeatures))
-		for f := range extendedFeatures {
-			featureList = append(featureList, f)
-		}
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityInfo,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  fmt.Sprintf("Gateway uses extended conformance features: %s", strings.Join(featureList, ", ")),
-			Detail:   "These features require the implementation to support the extended conformance profile",
-		})
+	// Report extended profile against what the GatewayClass supports
+	var classNames []string
+	if gatewayClass != "" {
+		classNames = []string{gatewayClass}
 	}
+	findings = append(findings, t.extendedFeatureFindings(ctx, "Gateway", ref, classNames, extendedFeatures)...)
 
 	return findings
 }
@@ -2460,6 +2569,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 		return []types.DiagnosticFinding{{
 			Severity: types.SeverityWarning,
 			Category: types.CategoryRouting,
+			Code:     types.CodeGatewayResourceNotFound,
 			Resource: &types.ResourceRef{Kind: "HTTPRoute", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"},
 			Summary:  fmt.Sprintf("HTTPRoute %s/%s not found: %v", ns, name, err),
 		}}
@@ -2467,7 +2577,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 
 	ref := &types.ResourceRef{Kind: "HTTPRoute", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"}
 	var findings []types.DiagnosticFinding
-	extendedFeatures := make(map[string]bool)
+	extendedFeatures := make(map[string]string)
 
 	// parentRefs required
 	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
@@ -2475,6 +2585,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewaySpecFieldMissing,
 			Resource:   ref,
 			Summary:    "spec.parentRefs is required but empty or missing",
 			Suggestion: "Add at least one parentRef pointing to a Gateway",
@@ -2506,13 +2617,14 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.path.type %q is not a valid PathMatchType", mPrefix, matchType),
 							Detail:     "Valid values: Exact, PathPrefix, RegularExpression",
 							Suggestion: "Use a valid PathMatchType",
 						})
-					} else if extendedPathMatchTypes[matchType] {
-						extendedFeatures["RegularExpression path match"] = true
+					} else if feature, ok := extendedPathMatchTypes[matchType]; ok {
+						extendedFeatures["RegularExpression path match"] = feature
 					}
 					// PathPrefix must start with /
 					if matchType == "PathPrefix" || matchType == "" {
@@ -2521,6 +2633,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewaySpecInvalidValue,
 								Resource:   ref,
 								Summary:    fmt.Sprintf("%s.path.value %q must start with '/' for PathPrefix match", mPrefix, value),
 								Suggestion: "Prefix the path value with /",
@@ -2538,6 +2651,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 								findings = append(findings, types.DiagnosticFinding{
 									Severity:   types.SeverityWarning,
 									Category:   types.CategoryRouting,
+									Code:       types.CodeGatewaySpecInvalidValue,
 									Resource:   ref,
 									Summary:    fmt.Sprintf("%s.headers[%d].type %q is not a valid HeaderMatchType", mPrefix, k, hType),
 									Detail:     "Valid values: Exact, RegularExpression",
@@ -2550,6 +2664,9 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 
 				// Query param match types
 				if queryParams, ok := mm["queryParams"].([]interface{}); ok {
+					if len(queryParams) > 0 {
+						extendedFeatures["query parameter match"] = "HTTPRouteQueryParamMatching"
+					}
 					for k, q := range queryParams {
 						if qm, ok := q.(map[string]interface{}); ok {
 							qType, _ := qm["type"].(string)
@@ -2557,6 +2674,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 								findings = append(findings, types.DiagnosticFinding{
 									Severity:   types.SeverityWarning,
 									Category:   types.CategoryRouting,
+									Code:       types.CodeGatewaySpecInvalidValue,
 									Resource:   ref,
 									Summary:    fmt.Sprintf("%s.queryParams[%d].type %q is not a valid QueryParamMatchType", mPrefix, k, qType),
 									Detail:     "Valid values: Exact, RegularExpression",
@@ -2569,10 +2687,12 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 
 				// HTTP method
 				if method, ok := mm["method"].(string); ok {
+					extendedFeatures["method match"] = "HTTPRouteMethodMatching"
 					if !validHTTPMethods[method] {
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.method %q is not a valid HTTP method", mPrefix, method),
 							Detail:     "Valid values: GET, HEAD, POST, PUT, DELETE, CONNECT, OPTIONS, TRACE, PATCH",
@@ -2593,13 +2713,22 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.type %q is not a valid HTTPRouteFilterType", fPrefix, fType),
 							Detail:     "Valid values: RequestHeaderModifier, ResponseHeaderModifier, RequestMirror, RequestRedirect, URLRewrite, ExtensionRef",
 							Suggestion: "Use a valid HTTPRouteFilterType",
 						})
-					} else if extendedHTTPFilters[fType] {
-						extendedFeatures["filter "+fType] = true
+					} else if feature, ok := extendedHTTPFilters[fType]; ok {
+						// A URLRewrite of the hostname only is a different feature.
+						if fType == "URLRewrite" {
+							_, hasPath, _ := unstructured.NestedFieldNoCopy(fm, "urlRewrite", "path")
+							_, hasHost, _ := unstructured.NestedFieldNoCopy(fm, "urlRewrite", "hostname")
+							if hasHost && !hasPath {
+								feature = "HTTPRouteHostRewrite"
+							}
+						}
+						extendedFeatures["filter "+fType] = feature
 					}
 				}
 			}
@@ -2616,6 +2745,7 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewaySpecFieldMissing,
 								Resource:   ref,
 								Summary:    fmt.Sprintf("%s.backendRefs[%d]: port is required for Service backend %q", prefix, j, brName),
 								Suggestion: "Add a port field to the backendRef",
@@ -2627,20 +2757,8 @@ func (t *CheckGatewayConformanceTool) validateHTTPRoute(ctx context.Context, ns,
 		}
 	}
 
-	// Report extended profile
-	if len(extendedFeatures) > 0 {
-		featureList := make([]string, 0, len(extendedFeatures))
-		for f := range extendedFeatures {
-			featureList = append(featureList, f)
-		}
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityInfo,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  fmt.Sprintf("HTTPRoute uses extended conformance features: %s", strings.Join(featureList, ", ")),
-			Detail:   "These features require the implementation to support the extended conformance profile",
-		})
-	}
+	// Report extended profile against what the parent GatewayClasses support
+	findings = append(findings, t.extendedFeatureFindings(ctx, "HTTPRoute", ref, t.routeGatewayClasses(ctx, route), extendedFeatures)...)
 
 	return findings
 }
@@ -2651,6 +2769,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 		return []types.DiagnosticFinding{{
 			Severity: types.SeverityWarning,
 			Category: types.CategoryRouting,
+			Code:     types.CodeGatewayResourceNotFound,
 			Resource: &types.ResourceRef{Kind: "GRPCRoute", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"},
 			Summary:  fmt.Sprintf("GRPCRoute %s/%s not found: %v", ns, name, err),
 		}}
@@ -2658,7 +2777,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 
 	ref := &types.ResourceRef{Kind: "GRPCRoute", Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"}
 	var findings []types.DiagnosticFinding
-	extendedFeatures := make(map[string]bool)
+	extendedFeatures := make(map[string]string)
 
 	// parentRefs required
 	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
@@ -2666,6 +2785,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewaySpecFieldMissing,
 			Resource:   ref,
 			Summary:    "spec.parentRefs is required but empty or missing",
 			Suggestion: "Add at least one parentRef pointing to a Gateway",
@@ -2700,13 +2820,14 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.method.type %q is not a valid GRPCMethodMatchType", mPrefix, matchType),
 							Detail:     "Valid values: Exact, RegularExpression",
 							Suggestion: "Use a valid GRPCMethodMatchType",
 						})
-					} else if extendedGRPCMethods[matchType] {
-						extendedFeatures["RegularExpression gRPC method match"] = true
+					} else if feature, ok := extendedGRPCMethods[matchType]; ok {
+						extendedFeatures["RegularExpression gRPC method match"] = feature
 					}
 
 					// At least service or method must be specified
@@ -2714,6 +2835,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecFieldMissing,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.method: at least one of service or method must be specified", mPrefix),
 							Suggestion: "Set service, method, or both in the gRPC method match",
@@ -2730,6 +2852,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 								findings = append(findings, types.DiagnosticFinding{
 									Severity:   types.SeverityWarning,
 									Category:   types.CategoryRouting,
+									Code:       types.CodeGatewaySpecInvalidValue,
 									Resource:   ref,
 									Summary:    fmt.Sprintf("%s.headers[%d].type %q is not a valid HeaderMatchType", mPrefix, k, hType),
 									Detail:     "Valid values: Exact, RegularExpression",
@@ -2752,6 +2875,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityWarning,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeGatewaySpecInvalidValue,
 							Resource:   ref,
 							Summary:    fmt.Sprintf("%s.type %q is not a valid GRPCRouteFilterType", fPrefix, fType),
 							Detail:     "Valid values: RequestHeaderModifier, ResponseHeaderModifier, RequestMirror, ExtensionRef",
@@ -2773,6 +2897,7 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryRouting,
+								Code:       types.CodeGatewaySpecFieldMissing,
 								Resource:   ref,
 								Summary:    fmt.Sprintf("%s.backendRefs[%d]: port is required for Service backend %q", prefix, j, brName),
 								Suggestion: "Add a port field to the backendRef",
@@ -2784,20 +2909,8 @@ func (t *CheckGatewayConformanceTool) validateGRPCRoute(ctx context.Context, ns,
 		}
 	}
 
-	// Report extended profile
-	if len(extendedFeatures) > 0 {
-		featureList := make([]string, 0, len(extendedFeatures))
-		for f := range extendedFeatures {
-			featureList = append(featureList, f)
-		}
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityInfo,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  fmt.Sprintf("GRPCRoute uses extended conformance features: %s", strings.Join(featureList, ", ")),
-			Detail:   "These features require the implementation to support the extended conformance profile",
-		})
-	}
+	// Report extended profile against what the parent GatewayClasses support
+	findings = append(findings, t.extendedFeatureFindings(ctx, "GRPCRoute", ref, t.routeGatewayClasses(ctx, route), extendedFeatures)...)
 
 	return findings
 }
@@ -2890,6 +3003,7 @@ func checkRuleWeights(rm map[string]interface{}, ns string, ruleIdx int, routeRe
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayWeightsNot100,
 			Resource:   routeRef,
 			Summary:    fmt.Sprintf("Rule %d: backendRef weights sum to %d, not 100 — %d%% traffic unaccounted", ruleIdx, totalWeight, 100-totalWeight),
 			Suggestion: "Adjust backend weights to sum to 100",
@@ -2906,6 +3020,7 @@ func checkRuleWeights(rm map[string]interface{}, ns string, ruleIdx int, routeRe
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayWeightedBackendNoEndpoints,
 				Resource:   routeRef,
 				Summary:    fmt.Sprintf("Rule %d: backend %s has weight %d%% but 0 ready endpoints — this traffic will fail", ruleIdx, wb.name, wb.weight),
 				Suggestion: "Check that pods backing this service are running, or redistribute weights",
@@ -2942,6 +3057,7 @@ func checkRuleTimeouts(rm map[string]interface{}, ruleIdx int, routeRef *types.R
 	}
 
 	severity := types.SeverityInfo
+	var code types.FindingCode
 	suggestion := ""
 
 	// Check misconfiguration: backendRequest > request
@@ -2950,6 +3066,7 @@ func checkRuleTimeouts(rm map[string]interface{}, ruleIdx int, routeRef *types.R
 		backDur, backErr := time.ParseDuration(backendTimeout)
 		if reqErr == nil && backErr == nil && backDur > reqDur {
 			severity = types.SeverityWarning
+			code = types.CodeGatewayBackendTimeoutExceedsRequest
 			suggestion = "backendRequest timeout exceeds request timeout — backend timeout will never trigger"
 		}
 	}
@@ -2957,6 +3074,7 @@ func checkRuleTimeouts(rm map[string]interface{}, ruleIdx int, routeRef *types.R
 	finding := types.DiagnosticFinding{
 		Severity: severity,
 		Category: types.CategoryRouting,
+		Code:     code,
 		Resource: routeRef,
 		Summary:  fmt.Sprintf("Rule %d: %s", ruleIdx, strings.Join(parts, ", ")),
 	}
@@ -3052,6 +3170,7 @@ func validateCrossNamespaceRef(routeNs, routeKind, backendNs, backendName string
 	return &types.DiagnosticFinding{
 		Severity:   types.SeverityWarning,
 		Category:   types.CategoryPolicy,
+		Code:       types.CodeGatewayReferenceGrantMissing,
 		Resource:   routeRef,
 		Summary:    fmt.Sprintf("%s %s/%s references backend %s/%s across namespaces but no ReferenceGrant allows this", routeKind, routeNs, routeRef.Name, backendNs, backendName),
 		Suggestion: fmt.Sprintf("Create a ReferenceGrant in namespace %s allowing %s from namespace %s", backendNs, routeKind, routeNs),
@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// serviceTargetPort is one entry of a Service's spec.ports. TargetName is set when
+// targetPort is a named container port; otherwise TargetPort holds the number.
+type serviceTargetPort struct {
+	Name        string
+	Port        int64
+	Protocol    string
+	AppProtocol string
+	TargetPort  int64
+	TargetName  string
+}
+
+func (p serviceTargetPort) target() string {
+	if p.TargetName != "" {
+		return fmt.Sprintf("%q", p.TargetName)
+	}
+	return fmt.Sprintf("%d", p.TargetPort)
+}
+
+// targetRef returns the target port as a resolvePort reference.
+func (p serviceTargetPort) targetRef() interface{} {
+	if p.TargetName != "" {
+		return p.TargetName
+	}
+	return p.TargetPort
+}
+
+func serviceTargetPorts(svc *unstructured.Unstructured) []serviceTargetPort {
+	ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+	out := make([]serviceTargetPort, 0, len(ports))
+	for _, p := range ports {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sp := serviceTargetPort{Port: int64(toInt(pm["port"]))}
+		sp.Name, _ = pm["name"].(string)
+		sp.AppProtocol, _ = pm["appProtocol"].(string)
+		proto, _ := pm["protocol"].(string)
+		sp.Protocol = orDefault(proto, "TCP")
+		switch tp := pm["targetPort"]; {
+		case isPortName(tp):
+			sp.TargetName = tp.(string)
+		case tp == nil:
+			sp.TargetPort = sp.Port
+		default:
+			sp.TargetPort = int64(toInt(tp))
+		}
+		out = append(out, sp)
+	}
+	return out
+}
+
+// checkBackendPortChain follows a route backendRef to the Service port it
+// names and on to the container ports of the pods the Service selects,
+// reporting where the port number, name or protocol stops matching. Services
+// without a selector (ExternalName, manual Endpoints) are not checked past
+// the Service port.
+func checkBackendPortChain(routeRef *types.ResourceRef, routeKind string, backendPort int64, svc *unstructured.Unstructured, pods []podPorts) []types.DiagnosticFinding {
+	svcKey := svc.GetNamespace() + "/" + svc.GetName()
+	prefix := fmt.Sprintf("%s %s/%s backend %s:%d", routeKind, routeRef.Namespace, routeRef.Name, svcKey, backendPort)
+	finding := func(code types.FindingCode, sev, summary, detail, suggestion string) []types.DiagnosticFinding {
+		return []types.DiagnosticFinding{{
+			Severity:   sev,
+			Category:   types.CategoryRouting,
+			Code:       code,
+			Resource:   routeRef,
+			Summary:    summary,
+			Detail:     detail,
+			Suggestion: suggestion,
+		}}
+	}
+
+	ports := serviceTargetPorts(svc)
+	var sp *serviceTargetPort
+	exposed := make([]string, 0, len(ports))
+	for i := range ports {
+		exposed = append(exposed, fmt.Sprintf("%d/%s", ports[i].Port, ports[i].Protocol))
+		if ports[i].Port == backendPort {
+			sp = &ports[i]
+		}
+	}
+	if sp == nil {
+		return finding(types.CodeGatewayBackendPortNotExposed, types.SeverityWarning,
+			fmt.Sprintf("%s: Service does not expose port %d", prefix, backendPort),
+			fmt.Sprintf("service ports: %s", strings.Join(exposed, ", ")),
+			"Set the backendRef port to one of the Service ports.")
+	}
+	if sp.Protocol != "TCP" {
+		return finding(types.CodeGatewayBackendPortProtocol, types.SeverityWarning,
+			fmt.Sprintf("%s: Service port uses protocol %s, but %s traffic is TCP", prefix, sp.Protocol, routeKind),
+			fmt.Sprintf("port %d/%s -> targetPort %s", sp.Port, sp.Protocol, sp.target()),
+			"Point the route at a TCP Service port.")
+	}
+
+	selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+	if len(selector) == 0 {
+		return nil
+	}
+	selected := selectPods(pods, labels.SelectorFromSet(selector), func(ns string) bool { return ns == svc.GetNamespace() })
+	if len(selected) == 0 {
+		return finding("", types.SeverityInfo,
+			fmt.Sprintf("%s: no pods match the Service selector, container ports cannot be verified", prefix),
+			fmt.Sprintf("selector=%s", labels.SelectorFromSet(selector).String()), "")
+	}
+
+	chain := fmt.Sprintf("port %d/%s -> targetPort %s", sp.Port, sp.Protocol, sp.target())
+	matched := 0
+	var missing, undeclared []string
+	otherProtos := make(map[string]bool)
+	for _, p := range selected {
+		_, found := resolvePort(sp.targetRef(), sp.Protocol, p)
+		if !found {
+			for _, proto := range declaredProtocols(sp.targetRef(), p) {
+				otherProtos[proto] = true
+			}
+		}
+		switch {
+		case found:
+			matched++
+		case sp.TargetName == "" && len(p.Ports) == 0:
+			// Numeric targetPorts do not need to be declared; nothing to compare against.
+			undeclared = append(undeclared, p.Namespace+"/"+p.Name)
+		default:
+			missing = append(missing, p.Namespace+"/"+p.Name)
+		}
+	}
+
+	switch {
+	case len(missing) == 0 && len(undeclared) > 0 && matched == 0:
+		return finding("", types.SeverityInfo,
+			fmt.Sprintf("%s: selected pods declare no container ports, targetPort %s cannot be verified", prefix, sp.target()),
+			chain, "Declare the containerPort so the chain can be checked.")
+	case matched == 0 && len(undeclared) == 0 && len(otherProtos) > 0:
+		return finding(types.CodeGatewayBackendPortProtocol, types.SeverityCritical,
+			fmt.Sprintf("%s: targetPort %s is declared by the pods only with protocol %s", prefix, sp.target(), joinKeys(otherProtos)),
+			chain,
+			fmt.Sprintf("Align the Service port protocol and the containerPort protocol (%s).", sp.Protocol))
+	case matched == 0 && len(undeclared) == 0 && sp.TargetName != "":
+		return finding(types.CodeGatewayTargetPortUnresolved, types.SeverityCritical,
+			fmt.Sprintf("%s: targetPort %s is not a named port of any of %d selected pods; the Service has no endpoints for it", prefix, sp.target(), len(selected)),
+			fmt.Sprintf("%s; pods: %s", chain, truncateList(missing, 5)),
+			"Name the containerPort in the pod spec to match targetPort, or use the numeric container port.")
+	case matched == 0 && len(undeclared) == 0:
+		return finding(types.CodeGatewayTargetPortUnresolved, types.SeverityCritical,
+			fmt.Sprintf("%s: targetPort %s matches no declared container port of %d selected pods", prefix, sp.target(), len(selected)),
+			fmt.Sprintf("%s; declared: %s", chain, declaredPorts(selected)),
+			"Set targetPort to the port the container listens on.")
+	case len(missing) > 0:
+		return finding(types.CodeGatewayTargetPortUnresolved, types.SeverityWarning,
+			fmt.Sprintf("%s: targetPort %s resolves on %d of %d selected pods", prefix, sp.target(), matched, len(selected)),
+			fmt.Sprintf("%s; pods without it: %s", chain, truncateList(missing, 5)),
+			"Pods without the port receive traffic they cannot serve; check for mixed Deployment versions behind the Service.")
+	}
+	return nil
+}
+
+// declaredPorts lists the distinct container ports of pods for a detail line.
+func declaredPorts(pods []podPorts) string {
+	seen := make(map[string]bool)
+	for _, p := range pods {
+		for _, cp := range p.Ports {
+			s := fmt.Sprintf("%d/%s", cp.Port, cp.Protocol)
+			if cp.Name != "" {
+				s = cp.Name + "=" + s
+			}
+			seen[s] = true
+		}
+	}
+	return joinKeys(seen)
+}
+
+// joinKeys returns the sorted keys of a set, comma-separated.
+func joinKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testBackendService(ports ...map[string]interface{}) *unstructured.Unstructured {
+	raw := make([]interface{}, len(ports))
+	for i, p := range ports {
+		raw[i] = p
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "shop"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"app": "web"},
+			"ports":    raw,
+		},
+	}}
+}
+
+func testWebPod(name string, ports ...containerPort) podPorts {
+	return podPorts{Namespace: "shop", Name: name, Labels: map[string]string{"app": "web"}, Ports: ports}
+}
+
+func TestCheckBackendPortChain(t *testing.T) {
+	routeRef := &types.ResourceRef{Kind: "HTTPRoute", Namespace: "shop", Name: "web"}
+	named := testBackendService(map[string]interface{}{"port": int64(80), "targetPort": "http"})
+	numeric := testBackendService(map[string]interface{}{"port": int64(80), "targetPort": int64(8080)})
+	good := testWebPod("web-1", containerPort{Name: "http", Port: 8080, Protocol: "TCP"})
+
+	tests := []struct {
+		name     string
+		svc      *unstructured.Unstructured
+		port     int64
+		pods     []podPorts
+		severity string
+		code     types.FindingCode
+		summary  string
+	}{
+		{"chain resolves", named, 80, []podPorts{good}, "", "", ""},
+		{"port not on service", named, 8080, []podPorts{good}, types.SeverityWarning, types.CodeGatewayBackendPortNotExposed, "does not expose port 8080"},
+		{"named port missing", named, 80, []podPorts{testWebPod("web-1", containerPort{Name: "web", Port: 8080, Protocol: "TCP"})}, types.SeverityCritical, types.CodeGatewayTargetPortUnresolved, `"http" is not a named port`},
+		{"protocol mismatch", named, 80, []podPorts{testWebPod("web-1", containerPort{Name: "http", Port: 8080, Protocol: "UDP"})}, types.SeverityCritical, types.CodeGatewayBackendPortProtocol, "only with protocol UDP"},
+		{"numeric port missing", numeric, 80, []podPorts{testWebPod("web-1", containerPort{Port: 9090, Protocol: "TCP"})}, types.SeverityCritical, types.CodeGatewayTargetPortUnresolved, "matches no declared container port"},
+		{"numeric port undeclared", numeric, 80, []podPorts{testWebPod("web-1")}, types.SeverityInfo, "", "cannot be verified"},
+		{"partial rollout", named, 80, []podPorts{good, testWebPod("web-2")}, types.SeverityWarning, types.CodeGatewayTargetPortUnresolved, "resolves on 1 of 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkBackendPortChain(routeRef, "HTTPRoute", tt.port, tt.svc, tt.pods)
+			if tt.severity == "" {
+				if len(findings) != 0 {
+					t.Errorf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Severity != tt.severity || findings[0].Code != tt.code || !strings.Contains(findings[0].Summary, tt.summary) {
+				t.Errorf("expected one %s %s finding containing %q, got %+v", tt.severity, tt.code, tt.summary, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// BackendTLSPolicy is v1 from Gateway API v1.4 and v1alpha3 in the
+// experimental channel before it.
+var (
+	backendTLSPoliciesV1GVR   = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "backendtlspolicies"}
+	backendTLSPoliciesV1A3GVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha3", Resource: "backendtlspolicies"}
+)
+
+// backendTLSTarget is one Service (port) a BackendTLSPolicy applies to. An
+// empty SectionName covers every port of the Service.
+type backendTLSTarget struct {
+	Namespace   string
+	Name        string
+	SectionName string
+	Policy      string
+}
+
+// backendTLSTargets returns the Service targets of a BackendTLSPolicy,
+// reading spec.targetRefs and the single spec.targetRef of v1alpha2.
+func backendTLSTargets(policy *unstructured.Unstructured) []backendTLSTarget {
+	refs, _, _ := unstructured.NestedSlice(policy.Object, "spec", "targetRefs")
+	if ref, ok, _ := unstructured.NestedMap(policy.Object, "spec", "targetRef"); ok {
+		refs = append(refs, ref)
+	}
+	var out []backendTLSTarget
+	for _, r := range refs {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if group, _ := rm["group"].(string); group != "" && group != "core" {
+			continue
+		}
+		if kind, _ := rm["kind"].(string); kind != "" && kind != "Service" {
+			continue
+		}
+		name, _ := rm["name"].(string)
+		section, _ := rm["sectionName"].(string)
+		out = append(out, backendTLSTarget{Namespace: policy.GetNamespace(), Name: name, SectionName: section, Policy: policy.GetName()})
+	}
+	return out
+}
+
+// backendTLSIndex maps "namespace/service" to the BackendTLSPolicy targets
+// of that Service.
+type backendTLSIndex map[string][]backendTLSTarget
+
+func newBackendTLSIndex(policies *unstructured.UnstructuredList) backendTLSIndex {
+	idx := make(backendTLSIndex)
+	if policies == nil {
+		return idx
+	}
+	for i := range policies.Items {
+		for _, target := range backendTLSTargets(&policies.Items[i]) {
+			key := target.Namespace + "/" + target.Name
+			idx[key] = append(idx[key], target)
+		}
+	}
+	return idx
+}
+
+// policyFor returns the name of the BackendTLSPolicy covering a Service port,
+// or "" when none does.
+func (idx backendTLSIndex) policyFor(ns, svc, portName string) string {
+	for _, target := range idx[ns+"/"+svc] {
+		if target.SectionName == "" || target.SectionName == portName {
+			return target.Policy
+		}
+	}
+	return ""
+}
+
+// tlsPortHint returns why a Service port looks like it serves TLS, or "".
+func tlsPortHint(sp *serviceTargetPort) string {
+	name := strings.ToLower(sp.Name)
+	switch {
+	case name == "https" || name == "tls" || name == "grpcs" ||
+		strings.HasPrefix(name, "https-") || strings.HasPrefix(name, "tls-") || strings.HasPrefix(name, "grpcs-"):
+		return fmt.Sprintf("port name %q", sp.Name)
+	case strings.EqualFold(sp.TargetName, "https") || strings.EqualFold(sp.TargetName, "tls"):
+		return fmt.Sprintf("targetPort %q", sp.TargetName)
+	case sp.Port == 443 || sp.Port == 8443:
+		return fmt.Sprintf("port %d", sp.Port)
+	case sp.TargetName == "" && (sp.TargetPort == 443 || sp.TargetPort == 8443):
+		return fmt.Sprintf("targetPort %d", sp.TargetPort)
+	}
+	return ""
+}
+
+// h2PortHint returns why a Service port looks like it serves cleartext
+// HTTP/2 (h2c, typically gRPC), or "".
+func h2PortHint(sp *serviceTargetPort) string {
+	name := strings.ToLower(sp.Name)
+	for _, prefix := range []string{"grpc", "h2c", "http2", "h2"} {
+		if name == prefix || strings.HasPrefix(name, prefix+"-") {
+			return fmt.Sprintf("port name %q", sp.Name)
+		}
+	}
+	return ""
+}
+
+// tlsAppProtocol reports whether an appProtocol value tells the gateway the
+// backend expects TLS.
+func tlsAppProtocol(appProtocol string) bool {
+	switch strings.ToLower(appProtocol) {
+	case "https", "tls", "kubernetes.io/wss", "grpcs":
+		return true
+	}
+	return false
+}
+
+// checkBackendProtocol flags HTTPRoute backends whose Service port serves TLS
+// or HTTP/2 cleartext while nothing tells the gateway to originate TLS or
+// speak HTTP/2. The gateway then sends HTTP/1.1 plaintext, which surfaces as
+// opaque 502s, connection resets or "upstream protocol error" responses.
+func checkBackendProtocol(routeRef *types.ResourceRef, backendPort int64, svc *unstructured.Unstructured, policies backendTLSIndex) []types.DiagnosticFinding {
+	var sp *serviceTargetPort
+	ports := serviceTargetPorts(svc)
+	for i := range ports {
+		if ports[i].Port == backendPort {
+			sp = &ports[i]
+		}
+	}
+	if sp == nil {
+		return nil
+	}
+	svcKey := svc.GetNamespace() + "/" + svc.GetName()
+	prefix := fmt.Sprintf("HTTPRoute %s/%s backend %s:%d", routeRef.Namespace, routeRef.Name, svcKey, backendPort)
+
+	if policies.policyFor(svc.GetNamespace(), svc.GetName(), sp.Name) != "" {
+		return nil
+	}
+
+	if tlsAppProtocol(sp.AppProtocol) {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryTLS,
+			Resource:   routeRef,
+			Summary:    fmt.Sprintf("%s: TLS to the backend relies on appProtocol %q, no BackendTLSPolicy targets the Service", prefix, sp.AppProtocol),
+			Detail:     "not every implementation originates TLS from appProtocol alone, and without a policy the backend certificate is not validated",
+			Suggestion: fmt.Sprintf("Create a BackendTLSPolicy targeting Service %s with a validation hostname and CA certificate refs.", svcKey),
+		}}
+	}
+	if hint := tlsPortHint(sp); hint != "" && sp.AppProtocol == "" {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeGatewayBackendTLSPolicyMissing,
+			Resource:   routeRef,
+			Summary:    fmt.Sprintf("%s: Service port looks like HTTPS (%s) but no BackendTLSPolicy or appProtocol tells the gateway to originate TLS", prefix, hint),
+			Detail:     "the gateway sends plaintext HTTP to a TLS server, which typically shows as 502 Bad Gateway or connection reset",
+			Suggestion: fmt.Sprintf("Create a BackendTLSPolicy targeting Service %s, or point the route at a plaintext port if the backend has one.", svcKey),
+		}}
+	}
+
+	if hint := h2PortHint(sp); hint != "" && sp.AppProtocol == "" {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayBackendAppProtocolMissing,
+			Resource:   routeRef,
+			Summary:    fmt.Sprintf("%s: Service port looks like HTTP/2 cleartext (%s) but has no appProtocol", prefix, hint),
+			Detail:     "without appProtocol the gateway speaks HTTP/1.1 to the backend; an h2-only server rejects it and the client sees a 502",
+			Suggestion: "Set appProtocol: kubernetes.io/h2c on the Service port (or use a GRPCRoute for gRPC backends).",
+		}}
+	}
+	return nil
+}
+
+// checkBackendTLSPolicy validates a BackendTLSPolicy: its target Services and
+// ports must exist, it must name the hostname to verify and a CA to verify
+// it with, and its ancestors must have accepted it. services maps
+// "namespace/name" to the Service, or is nil when Services were not listed.
+func checkBackendTLSPolicy(policy *unstructured.Unstructured, services map[string]*unstructured.Unstructured) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "BackendTLSPolicy", Namespace: policy.GetNamespace(), Name: policy.GetName(), APIVersion: "gateway.networking.k8s.io"}
+	key := policy.GetNamespace() + "/" + policy.GetName()
+	invalid := func(summary, suggestion string) types.DiagnosticFinding {
+		return types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeGatewayBackendTLSPolicyInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("BackendTLSPolicy %s %s", key, summary),
+			Suggestion: suggestion,
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	targets := backendTLSTargets(policy)
+	if len(targets) == 0 {
+		findings = append(findings, invalid("has no Service targetRefs", "Add a targetRef to the Service the gateway connects to."))
+	}
+	for _, target := range targets {
+		if services == nil {
+			break
+		}
+		svc, ok := services[target.Namespace+"/"+target.Name]
+		if !ok {
+			findings = append(findings, invalid(fmt.Sprintf("targets Service %s/%s, which does not exist", target.Namespace, target.Name),
+				"Fix the targetRef name or create the Service."))
+			continue
+		}
+		if target.SectionName == "" {
+			continue
+		}
+		found := false
+		for _, sp := range serviceTargetPorts(svc) {
+			if sp.Name == target.SectionName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			findings = append(findings, invalid(fmt.Sprintf("targets port %q of Service %s/%s, which has no port of that name", target.SectionName, target.Namespace, target.Name),
+				"Set sectionName to the name of a Service port, or remove it to cover every port."))
+		}
+	}
+
+	if hostname, _, _ := unstructured.NestedString(policy.Object, "spec", "validation", "hostname"); hostname == "" {
+		findings = append(findings, invalid("has no spec.validation.hostname",
+			"Set the hostname the backend certificate is issued for; it is also sent as SNI."))
+	}
+	caRefs, _, _ := unstructured.NestedSlice(policy.Object, "spec", "validation", "caCertificateRefs")
+	wellKnown, _, _ := unstructured.NestedString(policy.Object, "spec", "validation", "wellKnownCACertificates")
+	if len(caRefs) == 0 && wellKnown == "" {
+		findings = append(findings, invalid("sets neither caCertificateRefs nor wellKnownCACertificates",
+			"Reference a ConfigMap holding the backend CA bundle, or set wellKnownCACertificates: System."))
+	}
+
+	ancestors, _, _ := unstructured.NestedSlice(policy.Object, "status", "ancestors")
+	for _, a := range ancestors {
+		am, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ancestor := ""
+		if ar, ok := am["ancestorRef"].(map[string]interface{}); ok {
+			ancestor = formatParentRef(ar)
+		}
+		conds, _ := am["conditions"].([]interface{})
+		for _, c := range conds {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if status, _ := cm["status"].(string); status != "False" {
+				continue
+			}
+			condType, _ := cm["type"].(string)
+			reason, _ := cm["reason"].(string)
+			f := invalid(fmt.Sprintf("is not %s by %s: %s", strings.ToLower(condType), ancestor, reason), "Check the condition message on the policy status.")
+			f.Detail, _ = cm["message"].(string)
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// listBackendTLSPolicies lists BackendTLSPolicies from v1, then v1alpha3.
+func (b *BaseTool) listBackendTLSPolicies(ctx context.Context, ns string) (*unstructured.UnstructuredList, error) {
+	return b.listResourceWithFallback(ctx, backendTLSPoliciesV1GVR, backendTLSPoliciesV1A3GVR, ns)
+}
+
+// servicesByKey lists the Services of ns by "namespace/name", or returns nil
+// when they cannot be listed.
+func (b *BaseTool) servicesByKey(ctx context.Context, ns string) map[string]*unstructured.Unstructured {
+	list, err := b.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil
+	}
+	services := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		services[list.Items[i].GetNamespace()+"/"+list.Items[i].GetName()] = &list.Items[i]
+	}
+	return services
+}
+
+// --- list_backendtlspolicies ---
+
+type ListBackendTLSPoliciesTool struct{ BaseTool }
+
+func (t *ListBackendTLSPoliciesTool) Name() string { return "list_backendtlspolicies" }
+func (t *ListBackendTLSPoliciesTool) Description() string {
+	return "List Gateway API BackendTLSPolicies with their target Services and validation hostname, and check that targets exist, a CA is configured and the policy is accepted"
+}
+func (t *ListBackendTLSPoliciesTool) InputSchema() map[string]interface{} {
+	return withSelectors(withPagination(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+		},
+	}))
+}
+
+func (t *ListBackendTLSPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
+
+	list, err := t.listResourcePageWithFallback(ctx, backendTLSPoliciesV1GVR, backendTLSPoliciesV1A3GVR, ns, page)
+	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list backendtlspolicies",
+			Detail:  fmt.Sprintf("tried gateway.networking.k8s.io/v1 and v1alpha3: %v", err),
+		}
+	}
+
+	services := t.servicesByKey(ctx, ns)
+
+	findings := make([]types.DiagnosticFinding, 0, len(list.Items))
+	for i := range list.Items {
+		policy := &list.Items[i]
+		var targets []string
+		for _, target := range backendTLSTargets(policy) {
+			s := target.Name
+			if target.SectionName != "" {
+				s += ":" + target.SectionName
+			}
+			targets = append(targets, s)
+		}
+		hostname, _, _ := unstructured.NestedString(policy.Object, "spec", "validation", "hostname")
+		problems := checkBackendTLSPolicy(policy, services)
+
+		severity := types.SeverityOK
+		if len(problems) > 0 {
+			severity = types.SeverityWarning
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: severity,
+			Category: types.CategoryTLS,
+			Resource: &types.ResourceRef{Kind: "BackendTLSPolicy", Namespace: policy.GetNamespace(), Name: policy.GetName(), APIVersion: "gateway.networking.k8s.io"},
+			Summary:  fmt.Sprintf("%s/%s targets=[%s] hostname=%s", policy.GetNamespace(), policy.GetName(), strings.Join(targets, ", "), orDefault(hostname, "<none>")),
+		})
+		findings = append(findings, problems...)
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
+}
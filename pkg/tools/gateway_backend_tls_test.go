@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testBackendTLSPolicy(name string, targetRef map[string]interface{}, validation map[string]interface{}) *unstructured.Unstructured {
+	return managedObj("gateway.networking.k8s.io/v1", "BackendTLSPolicy", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{targetRef},
+			"validation": validation,
+		},
+	})
+}
+
+func TestCheckBackendProtocol(t *testing.T) {
+	routeRef := &types.ResourceRef{Kind: "HTTPRoute", Namespace: "shop", Name: "web"}
+	validation := map[string]interface{}{"hostname": "web.shop.svc", "wellKnownCACertificates": "System"}
+	policies := newBackendTLSIndex(&unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		*testBackendTLSPolicy("web-tls", map[string]interface{}{"group": "", "kind": "Service", "name": "web", "sectionName": "https"}, validation),
+	}})
+
+	tests := []struct {
+		name     string
+		port     map[string]interface{}
+		severity string
+		code     types.FindingCode
+	}{
+		{"plaintext", map[string]interface{}{"name": "http", "port": int64(80)}, "", ""},
+		{"https without policy", map[string]interface{}{"name": "web", "port": int64(443)}, types.SeverityWarning, types.CodeGatewayBackendTLSPolicyMissing},
+		{"https covered by policy", map[string]interface{}{"name": "https", "port": int64(443)}, "", ""},
+		{"https appProtocol only", map[string]interface{}{"name": "secure", "port": int64(9443), "appProtocol": "https"}, types.SeverityInfo, ""},
+		{"h2c without appProtocol", map[string]interface{}{"name": "grpc-api", "port": int64(9000)}, types.SeverityWarning, types.CodeGatewayBackendAppProtocolMissing},
+		{"h2c with appProtocol", map[string]interface{}{"name": "grpc-api", "port": int64(9000), "appProtocol": "kubernetes.io/h2c"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkBackendProtocol(routeRef, int64(toInt(tt.port["port"])), testBackendService(tt.port), policies)
+			if tt.severity == "" {
+				if len(findings) != 0 {
+					t.Errorf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Severity != tt.severity || findings[0].Code != tt.code {
+				t.Errorf("expected one %s %s finding, got %+v", tt.severity, tt.code, findings)
+			}
+		})
+	}
+}
+
+func TestListBackendTLSPolicies(t *testing.T) {
+	svc := testBackendService(map[string]interface{}{"name": "https", "port": int64(443)})
+	good := testBackendTLSPolicy("web-tls", map[string]interface{}{"kind": "Service", "name": "web"},
+		map[string]interface{}{"hostname": "web.shop.svc", "caCertificateRefs": []interface{}{map[string]interface{}{"kind": "ConfigMap", "name": "ca"}}})
+	bad := testBackendTLSPolicy("api-tls", map[string]interface{}{"kind": "Service", "name": "web", "sectionName": "grpc"}, map[string]interface{}{})
+	bad.Object["status"] = map[string]interface{}{"ancestors": []interface{}{map[string]interface{}{
+		"ancestorRef": map[string]interface{}{"name": "public", "namespace": "infra"},
+		"conditions":  []interface{}{map[string]interface{}{"type": "Accepted", "status": "False", "reason": "NoValidCACertificate"}},
+	}}}
+	client := newManagedClient(t, nil, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		backendTLSPoliciesV1GVR: {good, bad},
+		servicesGVR:             {svc},
+	})
+	tool := &ListBackendTLSPoliciesTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok shop/web-tls targets=[web] hostname=web.shop.svc",
+		"warning shop/api-tls targets=[web:grpc] hostname=<none>",
+		`warning BackendTLSPolicy shop/api-tls targets port "grpc" of Service shop/web, which has no port of that name GW036_BACKEND_TLS_POLICY_INVALID`,
+		"warning BackendTLSPolicy shop/api-tls has no spec.validation.hostname GW036_BACKEND_TLS_POLICY_INVALID",
+		"warning BackendTLSPolicy shop/api-tls sets neither caCertificateRefs nor wellKnownCACertificates GW036_BACKEND_TLS_POLICY_INVALID",
+		"warning BackendTLSPolicy shop/api-tls is not accepted by infra/public: NoValidCACertificate GW036_BACKEND_TLS_POLICY_INVALID",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "shop/web-tls has") || strings.Contains(all, "shop/web-tls targets port") {
+		t.Errorf("unexpected finding for a valid policy:\n%s", all)
+	}
+}
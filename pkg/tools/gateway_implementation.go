@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// gatewayImplementation is a Gateway API implementation recognised from the
+// controllerName of its GatewayClasses.
+type gatewayImplementation struct {
+	ID       string
+	Name     string
+	prefixes []string
+	// Guidance points at the tools that diagnose the implementation.
+	Guidance string
+}
+
+var gatewayImplementations = []gatewayImplementation{
+	{
+		ID: "istio", Name: "Istio", prefixes: []string{"istio.io/"},
+		Guidance: "istiod translates Gateways and routes into Envoy configuration: use analyze_istio_routing and check_istio_mtls for mesh-side issues, and generate_route_telemetry for per-route tracing.",
+	},
+	{
+		ID: "envoy-gateway", Name: "Envoy Gateway", prefixes: []string{"gateway.envoyproxy.io/"},
+		Guidance: "Envoy Gateway provisions one Envoy deployment per Gateway in envoy-gateway-system: check its pods with check_dataplane_health and attach policies (BackendTrafficPolicy, SecurityPolicy) for features outside the Gateway API.",
+	},
+	{
+		ID: "kgateway", Name: "kgateway", prefixes: []string{"kgateway.dev/", "solo.io/gloo-gateway"},
+		Guidance: "Use check_kgateway_health for the controller and data plane and validate_kgateway_resource for RouteOption, VirtualHostOption and GatewayParameters.",
+	},
+	{
+		ID: "nginx-gw", Name: "NGINX Gateway Fabric", prefixes: []string{"gateway.nginx.org/"},
+		Guidance: "NGINX Gateway Fabric runs the control and data plane in one pod: check its logs for configuration reload errors and use ClientSettingsPolicy or NginxProxy for features outside the Gateway API.",
+	},
+	{
+		ID: "cilium", Name: "Cilium", prefixes: []string{"io.cilium/"},
+		Guidance: "Cilium programs Gateways into its per-node Envoy: use check_cilium_status for the agents and make sure gatewayAPI.enabled and kube-proxy replacement are set in the Cilium configuration.",
+	},
+	{
+		ID: "gke", Name: "GKE Gateway", prefixes: []string{gkeControllerPrefix},
+		Guidance: "Use check_gke_gateway_status and list_gke_gateway_policies.",
+	},
+	{
+		ID: "vpc-lattice", Name: "AWS VPC Lattice", prefixes: []string{latticeControllerName},
+		Guidance: "Use check_vpc_lattice_status and list_vpc_lattice_policies.",
+	},
+}
+
+// implementationFor returns the implementation of a GatewayClass
+// controllerName, or nil when it is not recognised.
+func implementationFor(controllerName string) *gatewayImplementation {
+	for i := range gatewayImplementations {
+		for _, prefix := range gatewayImplementations[i].prefixes {
+			if strings.HasPrefix(controllerName, prefix) {
+				return &gatewayImplementations[i]
+			}
+		}
+	}
+	return nil
+}
+
+// gatewayClassInfo is what the conformance checks need from a GatewayClass.
+type gatewayClassInfo struct {
+	Name           string
+	Controller     string
+	Implementation *gatewayImplementation
+	Accepted       bool
+	AcceptedDetail string
+	// Pending is set when no controller has written the class status yet.
+	Pending bool
+	// Features holds status.supportedFeatures. Published is false when the
+	// controller does not report them, in which case nothing is known.
+	Features  map[string]bool
+	Published bool
+}
+
+func newGatewayClassInfo(gc *unstructured.Unstructured) *gatewayClassInfo {
+	info := &gatewayClassInfo{Name: gc.GetName(), Features: make(map[string]bool)}
+	info.Controller, _, _ = unstructured.NestedString(gc.Object, "spec", "controllerName")
+	info.Implementation = implementationFor(info.Controller)
+	conds, _, _ := unstructured.NestedSlice(gc.Object, "status", "conditions")
+	info.Accepted = classifyResourceStatus(conds) != "rejected"
+	info.Pending = conditionsPending(conds)
+	if !info.Accepted {
+		info.AcceptedDetail = extractConditionMessage(conds, "Accepted")
+	}
+	// Gateway API v1.1 publishes plain strings, v1.2 and later {name: ...}.
+	features, found, _ := unstructured.NestedSlice(gc.Object, "status", "supportedFeatures")
+	info.Published = found && len(features) > 0
+	for _, f := range features {
+		switch v := f.(type) {
+		case string:
+			info.Features[v] = true
+		case map[string]interface{}:
+			if name, _ := v["name"].(string); name != "" {
+				info.Features[name] = true
+			}
+		}
+	}
+	return info
+}
+
+// implementationName returns the implementation name of a class, or its
+// controllerName when the implementation is not recognised.
+func (c *gatewayClassInfo) implementationName() string {
+	if c.Implementation != nil {
+		return c.Implementation.Name
+	}
+	return c.Controller
+}
+
+func (c *gatewayClassInfo) sortedFeatures() []string {
+	out := make([]string, 0, len(c.Features))
+	for f := range c.Features {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// getGatewayClass fetches a GatewayClass by name.
+func (b *BaseTool) getGatewayClass(ctx context.Context, name string) (*gatewayClassInfo, error) {
+	gc, err := b.Clients.Dynamic.Resource(gatewayClassesGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return newGatewayClassInfo(gc), nil
+}
+
+// routeGatewayClasses returns the GatewayClass names of the Gateways a route
+// attaches to. Parents that are not Gateways or cannot be read are skipped.
+func (b *BaseTool) routeGatewayClasses(ctx context.Context, route *unstructured.Unstructured) []string {
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	seen := make(map[string]bool)
+	var classes []string
+	for _, p := range parentRefs {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := pm["kind"].(string)
+		group, _ := pm["group"].(string)
+		if (kind != "" && kind != "Gateway") || (group != "" && group != "gateway.networking.k8s.io") {
+			continue
+		}
+		name, _ := pm["name"].(string)
+		ns, _ := pm["namespace"].(string)
+		if ns == "" {
+			ns = route.GetNamespace()
+		}
+		gw, err := getWithFallback(ctx, b.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns, name)
+		if err != nil {
+			continue
+		}
+		class := getNestedString(gw.Object, "spec", "gatewayClassName")
+		if class != "" && !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
+// extendedFeatureFindings reports the extended conformance features a
+// resource uses against the supportedFeatures of its GatewayClasses. features
+// maps a description of each use to its supported feature name, "" when the
+// specification leaves it implementation-specific. Without a class that
+// publishes supportedFeatures, the uses are reported as a generic note.
+func (b *BaseTool) extendedFeatureFindings(ctx context.Context, kind string, ref *types.ResourceRef, classNames []string, features map[string]string) []types.DiagnosticFinding {
+	if len(features) == 0 {
+		return nil
+	}
+	uses := make([]string, 0, len(features))
+	for use := range features {
+		uses = append(uses, use)
+	}
+	sort.Strings(uses)
+
+	var findings []types.DiagnosticFinding
+	checked := false
+	for _, className := range classNames {
+		class, err := b.getGatewayClass(ctx, className)
+		if err != nil || !class.Published {
+			continue
+		}
+		checked = true
+		var unsupported, supported, specific []string
+		for _, use := range uses {
+			feature := features[use]
+			switch {
+			case feature == "":
+				specific = append(specific, use)
+			case class.Features[feature]:
+				supported = append(supported, use)
+			default:
+				unsupported = append(unsupported, fmt.Sprintf("%s (%s)", use, feature))
+			}
+		}
+		if len(unsupported) > 0 {
+			f := types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayFeatureUnsupported,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s uses features GatewayClass %s (%s) does not support: %s", kind, class.Name, class.implementationName(), strings.Join(unsupported, ", ")),
+				Detail:     "The features are missing from the GatewayClass status.supportedFeatures; the implementation may reject the resource or ignore the fields",
+				Suggestion: "Remove the unsupported fields or move the resource to a GatewayClass whose implementation supports them",
+			}
+			if class.Implementation != nil {
+				f.Suggestion += ". " + class.Implementation.Guidance
+			}
+			findings = append(findings, f)
+		}
+		if len(supported) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryRouting,
+				Resource: ref,
+				Summary:  fmt.Sprintf("%s uses extended features supported by GatewayClass %s (%s): %s", kind, class.Name, class.implementationName(), strings.Join(supported, ", ")),
+			})
+		}
+		if len(specific) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryRouting,
+				Resource: ref,
+				Summary:  fmt.Sprintf("%s uses implementation-specific features: %s", kind, strings.Join(specific, ", ")),
+				Detail:   fmt.Sprintf("Support is not advertised in supportedFeatures; check the %s documentation", class.implementationName()),
+			})
+		}
+	}
+	if checked {
+		return findings
+	}
+
+	return []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Resource: ref,
+		Summary:  fmt.Sprintf("%s uses extended conformance features: %s", kind, strings.Join(uses, ", ")),
+		Detail:   "These features require the implementation to support the extended conformance profile; its GatewayClass does not publish status.supportedFeatures",
+	}}
+}
+
+// --- detect_gateway_implementation ---
+
+type DetectGatewayImplementationTool struct{ BaseTool }
+
+func (t *DetectGatewayImplementationTool) Name() string { return "detect_gateway_implementation" }
+func (t *DetectGatewayImplementationTool) Description() string {
+	return "Identify the Gateway API implementation behind each GatewayClass (Istio, Envoy Gateway, kgateway, NGINX Gateway Fabric, Cilium, ...), list the extended features it reports in status.supportedFeatures and give implementation-specific guidance"
+}
+func (t *DetectGatewayImplementationTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"gateway_class": map[string]interface{}{
+				"type":        "string",
+				"description": "GatewayClass name (default: all GatewayClasses)",
+			},
+		},
+	}
+}
+
+func (t *DetectGatewayImplementationTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	className := getStringArg(args, "gateway_class", "")
+
+	var classes []*gatewayClassInfo
+	if className != "" {
+		class, err := t.getGatewayClass(ctx, className)
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("GatewayClass %s not found: %v", className, err),
+			}
+		}
+		classes = append(classes, class)
+	} else {
+		list, err := t.listResource(ctx, gatewayClassesGVR, "")
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeCRDNotAvailable,
+				Tool:    t.Name(),
+				Message: "failed to list GatewayClasses",
+				Detail:  err.Error(),
+			}
+		}
+		for i := range list.Items {
+			classes = append(classes, newGatewayClassInfo(&list.Items[i]))
+		}
+		sort.Slice(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	}
+
+	// Gateways per class, for context.
+	gatewaysByClass := make(map[string][]string)
+	if gateways, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ""); err == nil {
+		for _, gw := range gateways.Items {
+			class := getNestedString(gw.Object, "spec", "gatewayClassName")
+			gatewaysByClass[class] = append(gatewaysByClass[class], gw.GetNamespace()+"/"+gw.GetName())
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, class := range classes {
+		ref := &types.ResourceRef{Kind: "GatewayClass", Name: class.Name, APIVersion: "gateway.networking.k8s.io/v1"}
+		gateways := gatewaysByClass[class.Name]
+		sort.Strings(gateways)
+
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("GatewayClass %s: %s (%s), %d supported features, %d Gateways", class.Name, class.implementationName(), class.Controller, len(class.Features), len(gateways)),
+		}
+		var detail []string
+		if class.Published {
+			detail = append(detail, "Supported features: "+strings.Join(class.sortedFeatures(), ", "))
+		}
+		if len(gateways) > 0 {
+			detail = append(detail, "Gateways: "+strings.Join(gateways, ", "))
+		}
+		f.Detail = strings.Join(detail, "\n")
+		if class.Implementation != nil {
+			f.Suggestion = class.Implementation.Guidance
+		} else {
+			f.Severity = types.SeverityInfo
+			f.Summary = fmt.Sprintf("GatewayClass %s: unrecognised implementation %s, %d supported features, %d Gateways", class.Name, class.Controller, len(class.Features), len(gateways))
+		}
+		findings = append(findings, f)
+
+		if class.Pending {
+			findings = append(findings, controllerNotRunningFinding(ref, class, len(gateways)))
+		} else if !class.Accepted {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayClassNotAccepted,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("GatewayClass %s is not accepted by %s", class.Name, class.Controller),
+				Detail:     class.AcceptedDetail,
+				Suggestion: "Check that the controller is running and that spec.parametersRef points to a valid object",
+			})
+		}
+		if !class.Published {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayFeaturesUnpublished,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("GatewayClass %s does not publish status.supportedFeatures", class.Name),
+				Detail:     "check_gateway_conformance cannot tell which extended features the implementation supports and reports them as generic notes",
+				Suggestion: "Upgrade the controller to a release implementing Gateway API v1.1 or later",
+			})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Summary:    "No GatewayClasses found",
+			Suggestion: "Install a Gateway API implementation; it creates its GatewayClass",
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", "gateway-api"), nil
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestImplementationFor(t *testing.T) {
+	for controller, want := range map[string]string{
+		"istio.io/gateway-controller":                   "istio",
+		"gateway.envoyproxy.io/gatewayclass-controller": "envoy-gateway",
+		"kgateway.dev/kgateway":                         "kgateway",
+		"gateway.nginx.org/nginx-gateway-controller":    "nginx-gw",
+		"io.cilium/gateway-controller":                  "cilium",
+	} {
+		if impl := implementationFor(controller); impl == nil || impl.ID != want {
+			t.Errorf("implementationFor(%q) = %v, want %s", controller, impl, want)
+		}
+	}
+	if impl := implementationFor("example.com/other"); impl != nil {
+		t.Errorf("expected no implementation, got %s", impl.ID)
+	}
+}
+
+func gatewayImplementationClient(t *testing.T) BaseTool {
+	t.Helper()
+	istio := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "istio", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": "istio.io/gateway-controller"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Accepted", "status": "True"}},
+			"supportedFeatures": []interface{}{
+				map[string]interface{}{"name": "HTTPRoute"},
+				map[string]interface{}{"name": "HTTPRouteRequestMirror"},
+			},
+		},
+	})
+	nginx := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "nginx", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": "gateway.nginx.org/nginx-gateway-controller"},
+		"status": map[string]interface{}{
+			"conditions":        []interface{}{map[string]interface{}{"type": "Accepted", "status": "False", "message": "invalid parametersRef"}},
+			"supportedFeatures": []interface{}{"HTTPRoute", "HTTPRouteMethodMatching"},
+		},
+	})
+	legacy := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "legacy", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": "example.com/legacy"},
+	})
+	mesh := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "mesh", map[string]interface{}{
+		"spec": map[string]interface{}{"gatewayClassName": "istio"},
+	})
+	old := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "old", map[string]interface{}{
+		"spec": map[string]interface{}{"gatewayClassName": "legacy"},
+	})
+	rules := []interface{}{map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{"method": "GET"}},
+		"filters": []interface{}{map[string]interface{}{"type": "RequestMirror", "requestMirror": map[string]interface{}{}}},
+	}}
+	shop := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "store", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "mesh", "namespace": "infra"}},
+			"rules":      rules,
+		},
+	})
+	legacyRoute := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "legacy", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "old", "namespace": "infra"}},
+			"rules":      rules,
+		},
+	})
+	client := newManagedClient(t, nil, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewayClassesGVR: {istio, nginx, legacy},
+		gatewaysV1GVR:     {mesh, old},
+		httpRoutesV1GVR:   {shop, legacyRoute},
+	})
+	return BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+}
+
+func TestDetectGatewayImplementation(t *testing.T) {
+	all := managedFindings(t, &DetectGatewayImplementationTool{BaseTool: gatewayImplementationClient(t)})
+	for _, want := range []string{
+		"ok GatewayClass istio: Istio (istio.io/gateway-controller), 2 supported features, 1 Gateways",
+		"critical GatewayClass nginx is not accepted by gateway.nginx.org/nginx-gateway-controller",
+		"info GatewayClass legacy: unrecognised implementation example.com/legacy, 0 supported features, 1 Gateways",
+		"info GatewayClass legacy does not publish status.supportedFeatures",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+}
+
+func TestCheckGatewayConformanceSupportedFeatures(t *testing.T) {
+	tool := &CheckGatewayConformanceTool{BaseTool: gatewayImplementationClient(t)}
+	run := func(name string) []types.DiagnosticFinding {
+		resp, err := tool.Run(context.Background(), map[string]interface{}{"kind": "HTTPRoute", "name": name, "namespace": "shop"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.(*types.ToolResult).Findings
+	}
+
+	var unsupported, supported bool
+	for _, f := range run("store") {
+		switch {
+		case f.Code == types.CodeGatewayFeatureUnsupported:
+			unsupported = strings.Contains(f.Summary, "method match (HTTPRouteMethodMatching)") && !strings.Contains(f.Summary, "RequestMirror")
+		case f.Severity == types.SeverityInfo:
+			supported = strings.Contains(f.Summary, "supported by GatewayClass istio (Istio): filter RequestMirror")
+		}
+	}
+	if !unsupported || !supported {
+		t.Errorf("expected method matching unsupported and RequestMirror supported, got %+v", run("store"))
+	}
+
+	// A class without supportedFeatures falls back to a generic note.
+	findings := run("legacy")
+	if len(findings) != 1 || findings[0].Severity != types.SeverityInfo || !strings.Contains(findings[0].Summary, "uses extended conformance features: filter RequestMirror, method match") {
+		t.Errorf("expected a generic extended features note, got %+v", findings)
+	}
+}
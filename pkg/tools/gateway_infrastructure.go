@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// conditionsPending reports whether a GatewayClass or Gateway status has not
+// been written by a controller: no conditions at all, or the Accepted
+// condition the API server defaults to Unknown/Pending.
+func conditionsPending(conds []interface{}) bool {
+	if len(conds) == 0 {
+		return true
+	}
+	for _, c := range conds {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condType, _ := cm["type"].(string); condType == "Accepted" {
+			status, _ := cm["status"].(string)
+			return status == "Unknown"
+		}
+	}
+	return false
+}
+
+// controllerNotRunningFinding reports a GatewayClass whose controller never
+// reconciled it, so none of its Gateways can be programmed.
+func controllerNotRunningFinding(ref *types.ResourceRef, class *gatewayClassInfo, gateways int) types.DiagnosticFinding {
+	return types.DiagnosticFinding{
+		Severity:   types.SeverityCritical,
+		Category:   types.CategoryRouting,
+		Code:       types.CodeGatewayControllerNotRunning,
+		Resource:   ref,
+		Summary:    fmt.Sprintf("GatewayClass %s is still Pending: no controller %s has reconciled it, %d Gateways cannot be programmed", class.Name, class.Controller, gateways),
+		Detail:     "the API server sets Accepted=Unknown (Pending) on creation and only the controller named in spec.controllerName replaces it",
+		Suggestion: "Install or start the Gateway API implementation, and check that spec.controllerName matches the name it registers exactly",
+	}
+}
+
+// servedResource resolves the resource of a group and kind. found
+// is false when the group and kind are not served by the cluster.
+func (b *BaseTool) servedResource(group, kind string) (gvr schema.GroupVersionResource, namespaced, found bool) {
+	if b.Clients.Discovery == nil {
+		return gvr, false, false
+	}
+	groupVersion := "v1"
+	if group != "" && group != "core" {
+		groups, err := b.Clients.Discovery.ServerGroups()
+		if err != nil {
+			return gvr, false, false
+		}
+		groupVersion = ""
+		for _, g := range groups.Groups {
+			if g.Name == group {
+				groupVersion = g.PreferredVersion.GroupVersion
+			}
+		}
+		if groupVersion == "" {
+			return gvr, false, false
+		}
+	}
+	resources, err := b.Clients.Discovery.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return gvr, false, false
+	}
+	gv, _ := schema.ParseGroupVersion(groupVersion)
+	for _, r := range resources.APIResources {
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			return gv.WithResource(r.Name), r.Namespaced, true
+		}
+	}
+	return gvr, false, false
+}
+
+// checkParametersRef verifies that the object a GatewayClass parametersRef or
+// Gateway infrastructure.parametersRef names exists. ns is the namespace of a
+// namespaced target.
+func (b *BaseTool) checkParametersRef(ctx context.Context, owner *types.ResourceRef, field string, ref map[string]interface{}, ns string) []types.DiagnosticFinding {
+	group, _ := ref["group"].(string)
+	kind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+	if refNs, _ := ref["namespace"].(string); refNs != "" {
+		ns = refNs
+	}
+	target := kind + " " + name
+	if group != "" {
+		target = fmt.Sprintf("%s.%s %s", kind, group, name)
+	}
+	ownerKey := owner.Name
+	if owner.Namespace != "" {
+		ownerKey = owner.Namespace + "/" + owner.Name
+	}
+
+	gvr, namespaced, found := b.servedResource(group, kind)
+	if !found {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayParametersRefMissing,
+			Resource:   owner,
+			Summary:    fmt.Sprintf("%s %s %s points to %s, a kind the cluster does not serve", owner.Kind, ownerKey, field, target),
+			Suggestion: "Install the CRD of the implementation's parameters kind or fix the group and kind of the reference",
+		}}
+	}
+	var err error
+	if namespaced {
+		_, err = b.Clients.Dynamic.Resource(gvr).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+		target += " in namespace " + ns
+	} else {
+		_, err = b.Clients.Dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayParametersRefMissing,
+			Resource:   owner,
+			Summary:    fmt.Sprintf("%s %s %s points to %s, which does not exist", owner.Kind, ownerKey, field, target),
+			Detail:     "most implementations refuse to accept or program the resource until the parameters object exists",
+			Suggestion: fmt.Sprintf("Create %s or remove the parametersRef", target),
+		}}
+	}
+	return nil
+}
+
+// checkGatewayInfrastructure validates what Gateways depend on outside the
+// route graph: the GatewayClass must exist and be accepted by a running
+// controller, and the parametersRefs of the class and of the Gateway
+// infrastructure must resolve. A Gateway whose controller never picked it up
+// is reported as such rather than as merely not Programmed.
+func (t *ScanGatewayMisconfigsTool) checkGatewayInfrastructure(ctx context.Context, gwList *unstructured.UnstructuredList) []types.DiagnosticFinding {
+	if gwList == nil || len(gwList.Items) == 0 {
+		return nil
+	}
+	gatewaysByClass := make(map[string][]string)
+	for _, gw := range gwList.Items {
+		class := getNestedString(gw.Object, "spec", "gatewayClassName")
+		gatewaysByClass[class] = append(gatewaysByClass[class], gw.GetNamespace()+"/"+gw.GetName())
+	}
+	classNames := make([]string, 0, len(gatewaysByClass))
+	for name := range gatewaysByClass {
+		classNames = append(classNames, name)
+	}
+	sort.Strings(classNames)
+
+	var findings []types.DiagnosticFinding
+	classes := make(map[string]*gatewayClassInfo)
+	for _, name := range classNames {
+		gateways := gatewaysByClass[name]
+		if name == "" {
+			continue
+		}
+		gc, err := t.Clients.Dynamic.Resource(gatewayClassesGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayClassMissing,
+				Resource:   &types.ResourceRef{Kind: "GatewayClass", Name: name, APIVersion: "gateway.networking.k8s.io/v1"},
+				Summary:    fmt.Sprintf("GatewayClass %s does not exist; %d Gateways reference it", name, len(gateways)),
+				Detail:     "Gateways: " + truncateList(gateways, 5),
+				Suggestion: "Install the Gateway API implementation that provides the class, or set gatewayClassName to an existing GatewayClass (see detect_gateway_implementation)",
+			})
+			continue
+		}
+		class := newGatewayClassInfo(gc)
+		classes[name] = class
+		ref := &types.ResourceRef{Kind: "GatewayClass", Name: name, APIVersion: "gateway.networking.k8s.io/v1"}
+		switch {
+		case class.Pending:
+			f := controllerNotRunningFinding(ref, class, len(gateways))
+			f.Detail += "\nGateways: " + truncateList(gateways, 5)
+			findings = append(findings, f)
+		case !class.Accepted:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayClassNotAccepted,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("GatewayClass %s is not accepted by %s; %d Gateways reference it", name, class.Controller, len(gateways)),
+				Detail:     class.AcceptedDetail,
+				Suggestion: "Fix the GatewayClass as the condition message describes; spec.parametersRef is the usual cause",
+			})
+		}
+		if params, ok, _ := unstructured.NestedMap(gc.Object, "spec", "parametersRef"); ok {
+			findings = append(findings, t.checkParametersRef(ctx, ref, "spec.parametersRef", params, "")...)
+		}
+	}
+
+	for _, gw := range gwList.Items {
+		ref := &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: "gateway.networking.k8s.io/v1"}
+		className := getNestedString(gw.Object, "spec", "gatewayClassName")
+		if className == "" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayClassMissing,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Gateway %s/%s has no spec.gatewayClassName", gw.GetNamespace(), gw.GetName()),
+				Suggestion: "Set gatewayClassName to the GatewayClass of the implementation that should program the Gateway",
+			})
+			continue
+		}
+		if params, ok, _ := unstructured.NestedMap(gw.Object, "spec", "infrastructure", "parametersRef"); ok {
+			findings = append(findings, t.checkParametersRef(ctx, ref, "spec.infrastructure.parametersRef", params, gw.GetNamespace())...)
+		}
+
+		// The class controller runs but has not picked this Gateway up.
+		class := classes[className]
+		if class == nil || class.Pending || !class.Accepted {
+			continue
+		}
+		conds, _, _ := unstructured.NestedSlice(gw.Object, "status", "conditions")
+		if conditionsPending(conds) {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayControllerNotRunning,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Gateway %s/%s is still Pending: controller %s has not reconciled it", gw.GetNamespace(), gw.GetName(), class.Controller),
+				Detail:     fmt.Sprintf("GatewayClass %s is accepted, so the controller ran at some point; it is now down, crash-looping, or does not watch namespace %s", className, gw.GetNamespace()),
+				Suggestion: "Check the controller pods and logs, and any namespace or label filters in its configuration",
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestConditionsPending(t *testing.T) {
+	pending := []interface{}{map[string]interface{}{"type": "Accepted", "status": "Unknown", "reason": "Pending"}}
+	accepted := []interface{}{map[string]interface{}{"type": "Accepted", "status": "True"}}
+	if !conditionsPending(nil) || !conditionsPending(pending) || conditionsPending(accepted) {
+		t.Error("expected no conditions and Accepted=Unknown to be pending, Accepted=True not")
+	}
+}
+
+func TestScanGatewayInfrastructure(t *testing.T) {
+	accepted := map[string]interface{}{"conditions": []interface{}{map[string]interface{}{"type": "Accepted", "status": "True"}}}
+	envoy := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "eg", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"controllerName": "gateway.envoyproxy.io/gatewayclass-controller",
+			"parametersRef":  map[string]interface{}{"group": "gateway.envoyproxy.io", "kind": "EnvoyProxy", "name": "default", "namespace": "envoy-gateway-system"},
+		},
+		"status": accepted,
+	})
+	idle := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "idle", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": "example.com/idle"},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Accepted", "status": "Unknown", "reason": "Pending", "message": "Waiting for controller"},
+		}},
+	})
+	gateway := func(name, class string, infra map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{"gatewayClassName": class}
+		if infra != nil {
+			spec["infrastructure"] = map[string]interface{}{"parametersRef": infra}
+		}
+		return managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", name, map[string]interface{}{"spec": spec, "status": status})
+	}
+	params := managedObj("v1", "ConfigMap", "infra", "edge-params", map[string]interface{}{})
+
+	client := newManagedClient(t, nil, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewayClassesGVR: {envoy, idle},
+		gatewaysV1GVR: {
+			gateway("edge", "eg", map[string]interface{}{"group": "", "kind": "ConfigMap", "name": "edge-params"}, accepted),
+			gateway("stale", "eg", map[string]interface{}{"group": "", "kind": "ConfigMap", "name": "gone"}, nil),
+			gateway("waiting", "idle", nil, nil),
+			gateway("typo", "envoy", nil, nil),
+		},
+		{Version: "v1", Resource: "configmaps"}: {params},
+	})
+	disco := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}}},
+		{GroupVersion: "gateway.envoyproxy.io/v1alpha1", APIResources: []metav1.APIResource{{Name: "envoyproxies", Kind: "EnvoyProxy", Namespaced: true}}},
+	}}}
+	tool := &ScanGatewayMisconfigsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client, Discovery: disco}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		if strings.HasPrefix(string(f.Code), "GW03") {
+			got = append(got, f.Summary+" "+string(f.Code))
+		}
+	}
+	want := []string{
+		"GatewayClass eg spec.parametersRef points to EnvoyProxy.gateway.envoyproxy.io default in namespace envoy-gateway-system, which does not exist GW039_PARAMETERS_REF_MISSING",
+		"GatewayClass envoy does not exist; 1 Gateways reference it GW037_GATEWAY_CLASS_MISSING",
+		"GatewayClass idle is still Pending: no controller example.com/idle has reconciled it, 1 Gateways cannot be programmed GW038_GATEWAY_CONTROLLER_NOT_RUNNING",
+		"Gateway infra/stale spec.infrastructure.parametersRef points to ConfigMap gone in namespace infra, which does not exist GW039_PARAMETERS_REF_MISSING",
+		"Gateway infra/stale is still Pending: controller gateway.envoyproxy.io/gatewayclass-controller has not reconciled it GW038_GATEWAY_CONTROLLER_NOT_RUNNING",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
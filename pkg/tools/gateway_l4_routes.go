@@ -0,0 +1,527 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// TCPRoute, TLSRoute and UDPRoute are only in the experimental channel.
+var (
+	tcpRoutesV1A2GVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tcproutes"}
+	tlsRoutesV1A2GVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tlsroutes"}
+	tlsRoutesV1A3GVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha3", Resource: "tlsroutes"}
+	udpRoutesV1A2GVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "udproutes"}
+)
+
+// l4RouteKind describes a route kind without HTTP matching: its rules only
+// forward to backends, and it attaches to listeners of given protocols.
+type l4RouteKind struct {
+	Kind string
+	gvr  schema.GroupVersionResource
+	// fallback is tried when gvr is not served; zero when there is none.
+	fallback  schema.GroupVersionResource
+	protocols map[string]bool
+}
+
+var (
+	tcpRouteKind = l4RouteKind{Kind: "TCPRoute", gvr: tcpRoutesV1A2GVR, protocols: map[string]bool{"TCP": true}}
+	tlsRouteKind = l4RouteKind{Kind: "TLSRoute", gvr: tlsRoutesV1A2GVR, fallback: tlsRoutesV1A3GVR, protocols: map[string]bool{"TLS": true}}
+	udpRouteKind = l4RouteKind{Kind: "UDPRoute", gvr: udpRoutesV1A2GVR, protocols: map[string]bool{"UDP": true}}
+)
+
+func (k l4RouteKind) protocolList() string {
+	out := make([]string, 0, len(k.protocols))
+	for p := range k.protocols {
+		out = append(out, p)
+	}
+	return strings.Join(out, "/")
+}
+
+func (b *BaseTool) listL4Routes(ctx context.Context, kind l4RouteKind, ns string, page pageRequest) (*unstructured.UnstructuredList, error) {
+	if kind.fallback.Resource == "" {
+		return b.listResourcePage(ctx, kind.gvr, ns, page)
+	}
+	return b.listResourcePageWithFallback(ctx, kind.gvr, kind.fallback, ns, page)
+}
+
+func (b *BaseTool) getL4Route(ctx context.Context, kind l4RouteKind, ns, name string) (*unstructured.Unstructured, error) {
+	if kind.fallback.Resource == "" {
+		return b.Clients.Dynamic.Resource(kind.gvr).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	}
+	return getWithFallback(ctx, b.Clients.Dynamic, kind.gvr, kind.fallback, ns, name)
+}
+
+// runListL4Routes lists the routes of a kind with their parents, hostnames
+// and backends.
+func (b *BaseTool) runListL4Routes(ctx context.Context, tool string, kind l4RouteKind, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
+
+	list, err := b.listL4Routes(ctx, kind, ns, page)
+	if err != nil {
+		if perr := listArgError(tool, err); perr != nil {
+			return nil, perr
+		}
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    tool,
+			Message: fmt.Sprintf("failed to list %ss", strings.ToLower(kind.Kind)),
+			Detail:  fmt.Sprintf("%s is in the Gateway API experimental channel: %v", kind.Kind, err),
+		}
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, len(list.Items))
+	for _, item := range list.Items {
+		parentRefs, _, _ := unstructured.NestedSlice(item.Object, "spec", "parentRefs")
+		rules, _, _ := unstructured.NestedSlice(item.Object, "spec", "rules")
+		hostnames, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "hostnames")
+
+		parentRefParts := make([]string, 0, len(parentRefs))
+		for _, pr := range parentRefs {
+			if prm, ok := pr.(map[string]interface{}); ok {
+				parentRefParts = append(parentRefParts, formatParentRef(prm))
+			}
+		}
+		var backends []string
+		for _, r := range rules {
+			if rm, ok := r.(map[string]interface{}); ok {
+				backends = append(backends, extractBackendRefs(rm)...)
+			}
+		}
+
+		summary := fmt.Sprintf("%s/%s parents=[%s] rules=%d backends=[%s]",
+			item.GetNamespace(), item.GetName(), strings.Join(parentRefParts, ", "), len(rules), strings.Join(backends, ", "))
+		if len(hostnames) > 0 {
+			summary += fmt.Sprintf(" hostnames=[%s]", strings.Join(hostnames, ","))
+		}
+
+		severity := types.SeverityInfo
+		var code types.FindingCode
+		statusSuffix, hasStatusProblem := extractRouteStatusSuffix(item.Object)
+		if statusSuffix != "" {
+			summary += " " + statusSuffix
+		}
+		if hasStatusProblem {
+			severity = types.SeverityWarning
+			code = types.CodeGatewayRouteConditionFalse
+		}
+
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: severity,
+			Category: types.CategoryRouting,
+			Code:     code,
+			Resource: &types.ResourceRef{Kind: kind.Kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: "gateway.networking.k8s.io"},
+			Summary:  summary,
+		})
+	}
+
+	return NewToolResultResponse(b.Cfg, tool, findings, ns, "gateway-api").WithContinue(list.GetContinue()), nil
+}
+
+// runGetL4Route shows a route and validates it: parent Gateways and the
+// protocol of the listeners it attaches to, for TLSRoutes the listener TLS
+// mode and SNI hostnames, its backends and its status conditions.
+func (b *BaseTool) runGetL4Route(ctx context.Context, tool string, kind l4RouteKind, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "name", "")
+	ns := getStringArg(args, "namespace", "default")
+
+	route, err := b.getL4Route(ctx, kind, ns, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", strings.ToLower(kind.Kind), ns, name, err)
+	}
+	routeRef := &types.ResourceRef{Kind: kind.Kind, Namespace: ns, Name: name, APIVersion: "gateway.networking.k8s.io"}
+
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+
+	parentRefParts := make([]string, 0, len(parentRefs))
+	for _, pr := range parentRefs {
+		if prm, ok := pr.(map[string]interface{}); ok {
+			parentRefParts = append(parentRefParts, formatParentRef(prm))
+		}
+	}
+	summary := fmt.Sprintf("%s %s/%s parents=[%s] rules=%d", kind.Kind, ns, name, strings.Join(parentRefParts, ", "), len(rules))
+	if len(hostnames) > 0 {
+		summary += fmt.Sprintf(" hostnames=[%s]", strings.Join(hostnames, ","))
+	}
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Resource: routeRef,
+		Summary:  summary,
+	}}
+
+	if kind.Kind == "TLSRoute" && len(hostnames) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: routeRef,
+			Summary:  "TLSRoute has no hostnames: it matches every SNI the listener accepts",
+		})
+	}
+
+	// Parents: the Gateway must exist and offer a listener of the route's protocol.
+	for _, pr := range parentRefs {
+		prm, ok := pr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if k, _ := prm["kind"].(string); k != "" && k != "Gateway" {
+			continue
+		}
+		gwName, _ := prm["name"].(string)
+		gwNs, _ := prm["namespace"].(string)
+		if gwNs == "" {
+			gwNs = ns
+		}
+		section, _ := prm["sectionName"].(string)
+		gw, err := getWithFallback(ctx, b.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, gwNs, gwName)
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayParentMissing,
+				Resource:   routeRef,
+				Summary:    fmt.Sprintf("Parent Gateway %s/%s not found", gwNs, gwName),
+				Suggestion: "Fix the parentRef or create the Gateway",
+			})
+			continue
+		}
+		findings = append(findings, l4ListenerFindings(kind, routeRef, gw, section, hostnames)...)
+	}
+
+	// Backends: rules have no matches, every backendRef receives traffic.
+	refGrants := fetchScopedRefGrants(ctx, b.Clients.Dynamic, ns, rules)
+	endpointHealth := make(map[string]backendEndpointHealth)
+	for i, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		brs, _ := rm["backendRefs"].([]interface{})
+		if len(brs) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewaySpecFieldMissing,
+				Resource:   routeRef,
+				Summary:    fmt.Sprintf("spec.rules[%d] has no backendRefs: connections are rejected", i),
+				Suggestion: "Add at least one backendRef",
+			})
+			continue
+		}
+		for j, br := range brs {
+			brm, ok := br.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			refName, _ := brm["name"].(string)
+			refNs := ns
+			if rns, ok := brm["namespace"].(string); ok && rns != "" {
+				refNs = rns
+			}
+			if brKind, _ := brm["kind"].(string); brKind != "" && brKind != "Service" {
+				continue
+			}
+			if _, hasPort := brm["port"]; !hasPort {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryRouting,
+					Code:       types.CodeGatewaySpecFieldMissing,
+					Resource:   routeRef,
+					Summary:    fmt.Sprintf("spec.rules[%d].backendRefs[%d]: port is required for Service backend %q", i, j, refName),
+					Suggestion: "Add a port field to the backendRef",
+				})
+			}
+			key := refNs + "/" + refName
+			if _, checked := endpointHealth[key]; !checked {
+				endpointHealth[key] = b.l4BackendHealth(ctx, refNs, refName)
+				if !endpointHealth[key].found {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityWarning,
+						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendServiceMissing,
+						Resource:   routeRef,
+						Summary:    fmt.Sprintf("Backend service %s/%s not found", refNs, refName),
+						Suggestion: "Verify the backend service name and namespace are correct",
+					})
+				} else if endpointHealth[key].readyCount == 0 {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityWarning,
+						Category:   types.CategoryRouting,
+						Code:       types.CodeGatewayBackendNoEndpoints,
+						Resource:   routeRef,
+						Summary:    fmt.Sprintf("Backend service %s/%s has 0 ready endpoints", refNs, refName),
+						Suggestion: "Check that pods backing this service are running and passing readiness probes",
+					})
+				} else {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity: types.SeverityOK,
+						Category: types.CategoryRouting,
+						Resource: routeRef,
+						Summary:  fmt.Sprintf("Backend service %s/%s has %d ready endpoints", refNs, refName, endpointHealth[key].readyCount),
+					})
+				}
+			}
+			if finding := validateCrossNamespaceRef(ns, kind.Kind, refNs, refName, refGrants, routeRef); finding != nil {
+				findings = append(findings, *finding)
+			}
+		}
+		findings = append(findings, checkRuleWeights(rm, ns, i, routeRef, endpointHealth)...)
+	}
+
+	// Route status conditions (from parent statuses)
+	parentStatuses, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, ps := range parentStatuses {
+		psm, ok := ps.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pName := getNestedString(psm, "parentRef", "name")
+		conds, _ := psm["conditions"].([]interface{})
+		for _, c := range conds {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if status, _ := cm["status"].(string); status != "False" {
+				continue
+			}
+			condType, _ := cm["type"].(string)
+			reason, _ := cm["reason"].(string)
+			message, _ := cm["message"].(string)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayRouteConditionFalse,
+				Resource:   routeRef,
+				Summary:    fmt.Sprintf("Route condition %s=False for parent %s reason=%s", condType, pName, reason),
+				Detail:     message,
+				Suggestion: fmt.Sprintf("Check that the parent gateway and listener accept this %s", kind.Kind),
+			})
+		}
+	}
+
+	return NewToolResultResponse(b.Cfg, tool, findings, ns, "gateway-api"), nil
+}
+
+// l4BackendHealth looks up a backend Service and counts its ready endpoints.
+func (b *BaseTool) l4BackendHealth(ctx context.Context, ns, name string) backendEndpointHealth {
+	if _, err := b.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return backendEndpointHealth{}
+	}
+	health := backendEndpointHealth{found: true}
+	ep, err := b.Clients.Dynamic.Resource(endpointsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return health
+	}
+	subsets, _, _ := unstructured.NestedSlice(ep.Object, "subsets")
+	for _, s := range subsets {
+		if sm, ok := s.(map[string]interface{}); ok {
+			if addrs, ok := sm["addresses"].([]interface{}); ok {
+				health.readyCount += len(addrs)
+			}
+		}
+	}
+	return health
+}
+
+// l4ListenerFindings checks the listeners a route attaches to through a
+// parentRef: one must use the route's protocol and, for a TLSRoute, pass TLS
+// through and accept one of the route's SNI hostnames.
+func l4ListenerFindings(kind l4RouteKind, routeRef *types.ResourceRef, gw *unstructured.Unstructured, section string, hostnames []string) []types.DiagnosticFinding {
+	gwKey := gw.GetNamespace() + "/" + gw.GetName()
+	listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	var candidates []map[string]interface{}
+	for _, l := range listeners {
+		lm, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if lName, _ := lm["name"].(string); section == "" || lName == section {
+			candidates = append(candidates, lm)
+		}
+	}
+	if section != "" && len(candidates) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayParentListenerMissing,
+			Resource:   routeRef,
+			Summary:    fmt.Sprintf("Gateway %s has no listener %q", gwKey, section),
+			Suggestion: "Fix the parentRef sectionName",
+		}}
+	}
+
+	var compatible []map[string]interface{}
+	for _, lm := range candidates {
+		if protocol, _ := lm["protocol"].(string); kind.protocols[protocol] {
+			compatible = append(compatible, lm)
+		}
+	}
+	target := "any listener"
+	if section != "" {
+		target = "listener " + section
+	}
+	if len(compatible) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayListenerProtocolMismatch,
+			Resource:   routeRef,
+			Summary:    fmt.Sprintf("%s cannot attach to %s of Gateway %s: it needs protocol %s", kind.Kind, target, gwKey, kind.protocolList()),
+			Suggestion: fmt.Sprintf("Add a %s listener to the Gateway or point sectionName at one", kind.protocolList()),
+		}}
+	}
+	if kind.Kind != "TLSRoute" {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, lm := range compatible {
+		lName, _ := lm["name"].(string)
+		mode := getNestedString(lm, "tls", "mode")
+		if mode == "" {
+			mode = "Terminate"
+		}
+		if mode != "Passthrough" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryTLS,
+				Code:       types.CodeGatewayTLSRouteNotPassthrough,
+				Resource:   routeRef,
+				Summary:    fmt.Sprintf("TLSRoute attaches to listener %s of Gateway %s with TLS mode %s", lName, gwKey, mode),
+				Detail:     "TLSRoute routes on SNI and forwards the TLS stream as-is; with Terminate the Gateway decrypts it, which only some implementations support for TLSRoute",
+				Suggestion: "Set tls.mode: Passthrough on the listener (and remove its certificateRefs), or use an HTTPS listener with an HTTPRoute",
+			})
+		}
+		listenerHost, _ := lm["hostname"].(string)
+		if listenerHost != "" && len(hostnames) > 0 && len(listenerRouteHosts(listenerHost, hostnames)) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryTLS,
+				Code:       types.CodeGatewaySNIHostnameMismatch,
+				Resource:   routeRef,
+				Summary:    fmt.Sprintf("No TLSRoute hostname matches listener %s hostname %s of Gateway %s", lName, listenerHost, gwKey),
+				Detail:     fmt.Sprintf("Route hostnames: %s. Connections reach the route only when their SNI matches both", strings.Join(hostnames, ", ")),
+				Suggestion: "Align the TLSRoute hostnames with the listener hostname",
+			})
+		}
+	}
+	return findings
+}
+
+// --- list_tcproutes ---
+
+type ListTCPRoutesTool struct{ BaseTool }
+
+func (t *ListTCPRoutesTool) Name() string { return "list_tcproutes" }
+func (t *ListTCPRoutesTool) Description() string {
+	return "List TCPRoutes (Gateway API experimental channel) with parent refs, rule counts and backend refs"
+}
+func (t *ListTCPRoutesTool) InputSchema() map[string]interface{} { return l4ListSchema() }
+func (t *ListTCPRoutesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runListL4Routes(ctx, t.Name(), tcpRouteKind, args)
+}
+
+// --- get_tcproute ---
+
+type GetTCPRouteTool struct{ BaseTool }
+
+func (t *GetTCPRouteTool) Name() string { return "get_tcproute" }
+func (t *GetTCPRouteTool) Description() string {
+	return "Get a TCPRoute and validate it: parent Gateways with a TCP listener, backend Services with ready endpoints, ReferenceGrants and status conditions"
+}
+func (t *GetTCPRouteTool) InputSchema() map[string]interface{} { return l4GetSchema("TCPRoute") }
+func (t *GetTCPRouteTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runGetL4Route(ctx, t.Name(), tcpRouteKind, args)
+}
+
+// --- list_tlsroutes ---
+
+type ListTLSRoutesTool struct{ BaseTool }
+
+func (t *ListTLSRoutesTool) Name() string { return "list_tlsroutes" }
+func (t *ListTLSRoutesTool) Description() string {
+	return "List TLSRoutes (Gateway API experimental channel) with parent refs, SNI hostnames and backend refs"
+}
+func (t *ListTLSRoutesTool) InputSchema() map[string]interface{} { return l4ListSchema() }
+func (t *ListTLSRoutesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runListL4Routes(ctx, t.Name(), tlsRouteKind, args)
+}
+
+// --- get_tlsroute ---
+
+type GetTLSRouteTool struct{ BaseTool }
+
+func (t *GetTLSRouteTool) Name() string { return "get_tlsroute" }
+func (t *GetTLSRouteTool) Description() string {
+	return "Get a TLSRoute and validate SNI passthrough: parent listeners must use protocol TLS with mode Passthrough and accept the route hostnames; also checks backends, ReferenceGrants and status conditions"
+}
+func (t *GetTLSRouteTool) InputSchema() map[string]interface{} { return l4GetSchema("TLSRoute") }
+func (t *GetTLSRouteTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runGetL4Route(ctx, t.Name(), tlsRouteKind, args)
+}
+
+// --- list_udproutes ---
+
+type ListUDPRoutesTool struct{ BaseTool }
+
+func (t *ListUDPRoutesTool) Name() string { return "list_udproutes" }
+func (t *ListUDPRoutesTool) Description() string {
+	return "List UDPRoutes (Gateway API experimental channel) with parent refs, rule counts and backend refs"
+}
+func (t *ListUDPRoutesTool) InputSchema() map[string]interface{} { return l4ListSchema() }
+func (t *ListUDPRoutesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runListL4Routes(ctx, t.Name(), udpRouteKind, args)
+}
+
+// --- get_udproute ---
+
+type GetUDPRouteTool struct{ BaseTool }
+
+func (t *GetUDPRouteTool) Name() string { return "get_udproute" }
+func (t *GetUDPRouteTool) Description() string {
+	return "Get a UDPRoute and validate it: parent Gateways with a UDP listener, backend Services with ready endpoints, ReferenceGrants and status conditions"
+}
+func (t *GetUDPRouteTool) InputSchema() map[string]interface{} { return l4GetSchema("UDPRoute") }
+func (t *GetUDPRouteTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	return t.runGetL4Route(ctx, t.Name(), udpRouteKind, args)
+}
+
+func l4ListSchema() map[string]interface{} {
+	return withSelectors(withPagination(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+		},
+	}))
+}
+
+func l4GetSchema(kind string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": kind + " name",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace",
+			},
+		},
+		"required": []string{"name", "namespace"},
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestL4ListenerFindings(t *testing.T) {
+	gw := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "edge", map[string]interface{}{
+		"spec": map[string]interface{}{"listeners": []interface{}{
+			map[string]interface{}{"name": "http", "protocol": "HTTP", "port": int64(80)},
+			map[string]interface{}{"name": "tls-terminate", "protocol": "TLS", "port": int64(443), "hostname": "*.example.com"},
+			map[string]interface{}{"name": "tls-passthrough", "protocol": "TLS", "port": int64(8443), "hostname": "db.example.com", "tls": map[string]interface{}{"mode": "Passthrough"}},
+		}},
+	})
+	ref := &types.ResourceRef{Kind: "TLSRoute", Namespace: "shop", Name: "db"}
+	codes := func(findings []types.DiagnosticFinding) string {
+		var out []string
+		for _, f := range findings {
+			out = append(out, string(f.Code))
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := codes(l4ListenerFindings(tlsRouteKind, ref, gw, "tls-passthrough", []string{"db.example.com"})); got != "" {
+		t.Errorf("passthrough listener with a matching hostname: got %s", got)
+	}
+	if got := codes(l4ListenerFindings(tlsRouteKind, ref, gw, "tls-terminate", []string{"db.other.com"})); got != "GW032_TLSROUTE_NOT_PASSTHROUGH,GW033_SNI_HOSTNAME_MISMATCH" {
+		t.Errorf("terminate listener with another hostname: got %s", got)
+	}
+	if got := codes(l4ListenerFindings(tcpRouteKind, ref, gw, "", nil)); got != "GW031_LISTENER_PROTOCOL_MISMATCH" {
+		t.Errorf("TCPRoute without a TCP listener: got %s", got)
+	}
+	if got := codes(l4ListenerFindings(udpRouteKind, ref, gw, "dns", nil)); got != "GW013_PARENT_LISTENER_MISSING" {
+		t.Errorf("unknown sectionName: got %s", got)
+	}
+}
+
+func TestGetTLSRoute(t *testing.T) {
+	gw := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "edge", map[string]interface{}{
+		"spec": map[string]interface{}{"listeners": []interface{}{
+			map[string]interface{}{"name": "tls", "protocol": "TLS", "port": int64(443), "tls": map[string]interface{}{"mode": "Passthrough"}},
+		}},
+	})
+	route := managedObj("gateway.networking.k8s.io/v1alpha2", "TLSRoute", "shop", "db", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "edge", "namespace": "infra"}},
+			"hostnames":  []interface{}{"db.example.com"},
+			"rules": []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+				map[string]interface{}{"name": "postgres", "port": int64(5432)},
+				map[string]interface{}{"name": "replica", "namespace": "data", "port": int64(5432)},
+			}}},
+		},
+	})
+	svc := managedObj("v1", "Service", "shop", "postgres", map[string]interface{}{})
+	endpoints := managedObj("v1", "Endpoints", "shop", "postgres", map[string]interface{}{
+		"subsets": []interface{}{map[string]interface{}{"addresses": []interface{}{map[string]interface{}{"ip": "10.0.0.5"}}}},
+	})
+	client := newManagedClient(t, nil, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewaysV1GVR:    {gw},
+		tlsRoutesV1A2GVR: {route},
+		servicesGVR:      {svc},
+		endpointsGVR:     {endpoints},
+	})
+	tool := &GetTLSRouteTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"name": "db", "namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info TLSRoute shop/db parents=[infra/edge] rules=1 hostnames=[db.example.com]",
+		"ok Backend service shop/postgres has 1 ready endpoints",
+		"warning Backend service data/replica not found",
+		"warning TLSRoute shop/db references backend data/replica across namespaces",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "GW03") {
+		t.Errorf("unexpected listener finding:\n%s", all)
+	}
+}
+
+func managedFindingsOf(resp *StandardResponse) string {
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		summaries = append(summaries, string(f.Severity)+" "+f.Summary+" "+string(f.Code))
+	}
+	return strings.Join(summaries, "\n")
+}
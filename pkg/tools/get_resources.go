@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// maxGetResources caps the objects one get_resources call fetches.
+const maxGetResources = 50
+
+// apiResource is a served resource that a kind argument may name.
+type apiResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+	names      []string // lowercase kind, plural, singular and short names
+}
+
+// decodeResourceRefs accepts resource references as decoded JSON or as a
+// JSON string.
+func decodeResourceRefs(raw interface{}) ([]types.ResourceRef, error) {
+	var data []byte
+	if s, ok := raw.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+	var refs []types.ResourceRef
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// servedResources lists the preferred version of every resource the cluster
+// serves. Groups whose discovery failed are left out.
+func (b *BaseTool) servedResources() ([]apiResource, error) {
+	lists, err := b.Clients.Discovery.ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return nil, err
+	}
+	var out []apiResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			names := append([]string{strings.ToLower(r.Kind), r.Name, r.SingularName}, r.ShortNames...)
+			out = append(out, apiResource{gvr: gv.WithResource(r.Name), kind: r.Kind, namespaced: r.Namespaced, names: names})
+		}
+	}
+	return out, nil
+}
+
+// resolveRef finds the resource a reference names. The kind may be a kind,
+// a plural or short resource name, or either qualified by its group, as in
+// HTTPRoute.gateway.networking.k8s.io; apiVersion narrows it to one group.
+// A kind served by several groups must be qualified.
+func resolveRef(served []apiResource, ref types.ResourceRef) (apiResource, error) {
+	kind, group := strings.ToLower(ref.Kind), ""
+	if i := strings.Index(kind, "."); i > 0 {
+		kind, group = kind[:i], kind[i+1:]
+	}
+	version := ""
+	if ref.APIVersion != "" {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return apiResource{}, fmt.Errorf("invalid apiVersion %q", ref.APIVersion)
+		}
+		group, version = gv.Group, gv.Version
+	}
+
+	qualified := group != "" || ref.APIVersion != ""
+	var matches []apiResource
+	for _, r := range served {
+		if qualified && r.gvr.Group != group {
+			continue
+		}
+		for _, name := range r.names {
+			if name == kind {
+				matches = append(matches, r)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return apiResource{}, fmt.Errorf("kind %q is not served by the cluster", ref.Kind)
+	case 1:
+		r := matches[0]
+		if version != "" {
+			r.gvr.Version = version
+		}
+		return r, nil
+	}
+	groups := make([]string, 0, len(matches))
+	for _, r := range matches {
+		groups = append(groups, r.kind+"."+r.gvr.Group)
+	}
+	sort.Strings(groups)
+	return apiResource{}, fmt.Errorf("kind %q is served by several API groups; qualify it as one of %s", ref.Kind, strings.Join(groups, ", "))
+}
+
+// --- get_resources ---
+
+// GetResourcesTool returns the full manifests of several resources in one
+// call, such as the resources of a previous response's findings.
+type GetResourcesTool struct {
+	BaseTool
+}
+
+func (t *GetResourcesTool) Name() string { return "get_resources" }
+func (t *GetResourcesTool) Description() string {
+	return "Get the full manifests (spec and status) of up to 50 resources of any kind in one call, e.g. the resources of a previous scan's findings, instead of one get call per resource; kinds are resolved through API discovery"
+}
+func (t *GetResourcesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"kind":       map[string]interface{}{"type": "string", "description": "Kind or resource name, e.g. HTTPRoute, svc or Gateway.gateway.networking.k8s.io"},
+						"namespace":  map[string]interface{}{"type": "string", "description": "Namespace of a namespaced resource"},
+						"name":       map[string]interface{}{"type": "string"},
+						"apiVersion": map[string]interface{}{"type": "string", "description": "Optional, e.g. networking.istio.io/v1, to pick the API group"},
+					},
+					"required": []string{"kind", "name"},
+				},
+				"description": "Resources to get, in the form of the resource field of findings",
+			},
+			"findings": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "object"},
+				"description": "Findings of a previous tool response; the resources they reference are fetched",
+			},
+		},
+	}
+}
+
+func (t *GetResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	var refs []types.ResourceRef
+	if raw, ok := args["resources"]; ok && raw != nil {
+		r, err := decodeResourceRefs(raw)
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "resources must be an array of {kind, namespace, name} objects",
+				Detail:  err.Error(),
+			}
+		}
+		refs = append(refs, r...)
+	}
+	if raw, ok := args["findings"]; ok && raw != nil {
+		findings, err := decodeFindings(raw)
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "findings must be the findings array of a tool response",
+				Detail:  err.Error(),
+			}
+		}
+		for _, f := range findings {
+			if f.Resource != nil {
+				refs = append(refs, *f.Resource)
+			}
+		}
+	}
+
+	seen := make(map[types.ResourceRef]bool)
+	unique := refs[:0]
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+	refs = unique
+	if len(refs) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "one of resources or findings is required",
+		}
+	}
+	if len(refs) > maxGetResources {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("%d resources requested; get_resources returns at most %d per call", len(refs), maxGetResources),
+		}
+	}
+
+	served, err := t.servedResources()
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to discover the API resources of the cluster",
+			Detail:  err.Error(),
+			Cause:   err,
+		}
+	}
+
+	var docs, failed []string
+	for _, ref := range refs {
+		label := ref.Kind + " " + ref.Name
+		if ref.Namespace != "" {
+			label = ref.Kind + " " + ref.Namespace + "/" + ref.Name
+		}
+		r, err := resolveRef(served, ref)
+		if err == nil && r.kind == "Secret" && r.gvr.Group == "" {
+			err = fmt.Errorf("secrets are not returned")
+		}
+		if err == nil && r.namespaced && ref.Namespace == "" {
+			err = fmt.Errorf("namespace is required for %s", r.kind)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("# - %s: %v", label, err))
+			continue
+		}
+
+		client := t.Clients.Dynamic.Resource(r.gvr)
+		get := client.Get
+		if r.namespaced {
+			get = client.Namespace(ref.Namespace).Get
+		}
+		obj, err := get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("# - %s: %v", label, err))
+			continue
+		}
+		// Server-side bookkeeping only costs tokens
+		obj.SetManagedFields(nil)
+		annotations := obj.GetAnnotations()
+		if _, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			obj.SetAnnotations(annotations)
+		}
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("# - %s: %v", label, err))
+			continue
+		}
+		docs = append(docs, strings.TrimSpace(string(b)))
+	}
+
+	// Resources that could not be returned head the document as comments
+	out := strings.Join(docs, "\n---\n")
+	if len(failed) > 0 {
+		out = fmt.Sprintf("# %d of %d resources could not be returned:\n%s\n---\n%s", len(failed), len(refs), strings.Join(failed, "\n"), out)
+	}
+	return NewResponse(t.Cfg, t.Name(), strings.TrimSuffix(out, "\n---\n")), nil
+}
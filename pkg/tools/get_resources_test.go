@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestResolveRef(t *testing.T) {
+	served := []apiResource{
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "services"}, kind: "Service", namespaced: true, names: []string{"service", "services", "service", "svc"}},
+		{gvr: schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}, kind: "Gateway", namespaced: true, names: []string{"gateway", "gateways", "gateway"}},
+		{gvr: schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1", Resource: "gateways"}, kind: "Gateway", namespaced: true, names: []string{"gateway", "gateways", "gateway", "gw"}},
+	}
+	tests := []struct {
+		ref  types.ResourceRef
+		want string
+		err  string
+	}{
+		{ref: types.ResourceRef{Kind: "Service"}, want: "/v1, Resource=services"},
+		{ref: types.ResourceRef{Kind: "svc"}, want: "/v1, Resource=services"},
+		{ref: types.ResourceRef{Kind: "Gateway.gateway.networking.k8s.io"}, want: "gateway.networking.k8s.io/v1, Resource=gateways"},
+		{ref: types.ResourceRef{Kind: "Gateway", APIVersion: "networking.istio.io/v1beta1"}, want: "networking.istio.io/v1beta1, Resource=gateways"},
+		{ref: types.ResourceRef{Kind: "gw"}, want: "networking.istio.io/v1, Resource=gateways"},
+		{ref: types.ResourceRef{Kind: "Gateway"}, err: "qualify it as one of Gateway.gateway.networking.k8s.io, Gateway.networking.istio.io"},
+		{ref: types.ResourceRef{Kind: "Service", APIVersion: "gateway.networking.k8s.io/v1"}, err: "not served"},
+		{ref: types.ResourceRef{Kind: "HTTPRoute"}, err: "not served"},
+	}
+	for _, tt := range tests {
+		r, err := resolveRef(served, tt.ref)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%+v: err = %v, want %q", tt.ref, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || r.gvr.String() != tt.want {
+			t.Errorf("%+v: got %s, %v; want %s", tt.ref, r.gvr.String(), err, tt.want)
+		}
+	}
+}
+
+func TestDecodeResourceRefs(t *testing.T) {
+	refs, err := decodeResourceRefs(`[{"kind":"HTTPRoute","namespace":"shop","name":"web"}]`)
+	if err != nil || len(refs) != 1 || refs[0].Namespace != "shop" {
+		t.Fatalf("refs = %+v, err = %v", refs, err)
+	}
+	if _, err := decodeResourceRefs(map[string]interface{}{"kind": "Service"}); err == nil {
+		t.Error("a single object was accepted")
+	}
+}
@@ -0,0 +1,552 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	argoApplicationsGVR     = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	fluxKustomizationsGVR   = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+	fluxHelmReleasesV2GVR   = schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}
+	fluxHelmReleasesV2B2GVR = schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2beta2", Resource: "helmreleases"}
+)
+
+// Labels and annotations GitOps controllers set on the resources they apply.
+const (
+	argoTrackingAnnotation   = "argocd.argoproj.io/tracking-id"
+	argoInstanceLabel        = "app.kubernetes.io/instance"
+	fluxKustomizationLabel   = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNsLabel = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmReleaseLabel     = "helm.toolkit.fluxcd.io/name"
+	fluxHelmReleaseNsLabel   = "helm.toolkit.fluxcd.io/namespace"
+)
+
+// argoInClusterServer is the destination server of Applications that deploy
+// to the cluster Argo CD runs in.
+const argoInClusterServer = "https://kubernetes.default.svc"
+
+// gitopsHealthProblems are the Argo CD health states reported as warnings.
+var gitopsHealthProblems = map[string]bool{"Degraded": true, "Missing": true}
+
+// gitopsNetworkingKinds are the kinds the GitOps tools report on.
+var gitopsNetworkingKinds = map[string]bool{
+	"Service": true, "Ingress": true, "IngressClass": true, "NetworkPolicy": true,
+	"GatewayClass": true, "Gateway": true, "HTTPRoute": true, "GRPCRoute": true, "TCPRoute": true, "TLSRoute": true, "UDPRoute": true,
+	"ReferenceGrant": true, "BackendTLSPolicy": true,
+	"VirtualService": true, "DestinationRule": true, "ServiceEntry": true, "Sidecar": true, "EnvoyFilter": true,
+	"AuthorizationPolicy": true, "PeerAuthentication": true, "RequestAuthentication": true,
+	"CiliumNetworkPolicy": true, "CiliumClusterwideNetworkPolicy": true, "GlobalNetworkPolicy": true,
+	"RouteOption": true, "VirtualHostOption": true, "GatewayParameters": true,
+}
+
+// gitopsApp is an Argo CD Application, Flux Kustomization or Flux HelmRelease:
+// the object that applies resources from Git and reports whether they match.
+type gitopsApp struct {
+	kind      string
+	namespace string
+	name      string
+	// source is where the manifests come from: repository, path and revision.
+	source string
+	// sync is the Argo CD sync status or the status of the Flux Ready condition.
+	sync   string
+	health string
+	// failure is the message of a failed sync or reconciliation.
+	failure   string
+	suspended bool
+	selfHeal  bool
+	// remote is set when the app deploys to another cluster.
+	remote    bool
+	resources []gitopsResource
+}
+
+// gitopsResource is a resource an app applies, with its Argo CD sync and
+// health status (empty for Flux, which reports per app only).
+type gitopsResource struct {
+	kind      string
+	namespace string
+	name      string
+	sync      string
+	health    string
+}
+
+func (a *gitopsApp) String() string {
+	tool := "Flux"
+	if a.kind == "Application" {
+		tool = "Argo CD"
+	}
+	return fmt.Sprintf("%s %s %s/%s", tool, a.kind, a.namespace, a.name)
+}
+
+func (a *gitopsApp) ref() *types.ResourceRef {
+	return &types.ResourceRef{Kind: a.kind, Namespace: a.namespace, Name: a.name}
+}
+
+// fixInGit tells the reader to change a managed resource in its source
+// rather than with kubectl.
+func (a *gitopsApp) fixInGit() string {
+	msg := "Fix this in Git: it is managed by " + a.String()
+	if a.source != "" {
+		msg += " (" + a.source + ")"
+	}
+	switch {
+	case a.kind == "Application" && a.selfHeal:
+		return msg + ". Direct kubectl edits are reverted by self-heal."
+	case a.kind == "Application":
+		return msg + ". Direct kubectl edits show up as drift and are overwritten by the next sync."
+	}
+	return msg + ". Direct kubectl edits are overwritten by the next reconciliation."
+}
+
+// diffCommand is the command that shows how the cluster differs from Git.
+func (a *gitopsApp) diffCommand() string {
+	switch a.kind {
+	case "Application":
+		return fmt.Sprintf("argocd app diff %s", a.name)
+	case "HelmRelease":
+		return fmt.Sprintf("flux diff helmrelease %s -n %s", a.name, a.namespace)
+	}
+	return fmt.Sprintf("flux diff kustomization %s -n %s --path <local checkout of %s>", a.name, a.namespace, orDefault(a.source, "the source"))
+}
+
+// syncCommand is the command that applies Git to the cluster again.
+func (a *gitopsApp) syncCommand() string {
+	if a.kind == "Application" {
+		return fmt.Sprintf("argocd app sync %s", a.name)
+	}
+	return fmt.Sprintf("flux reconcile %s %s -n %s --with-source", strings.ToLower(a.kind), a.name, a.namespace)
+}
+
+// manages reports whether the app applies kind ns/name.
+func (a *gitopsApp) manages(kind, ns, name string) bool {
+	for _, r := range a.resources {
+		if r.kind == kind && r.namespace == ns && r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// networkingResources returns the networking resources of the app, in ns
+// when set.
+func (a *gitopsApp) networkingResources(ns string) []gitopsResource {
+	var out []gitopsResource
+	for _, r := range a.resources {
+		if gitopsNetworkingKinds[r.kind] && (ns == "" || r.namespace == ns) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// argoApplication reads an Argo CD Application.
+func argoApplication(obj *unstructured.Unstructured) gitopsApp {
+	app := gitopsApp{kind: "Application", namespace: obj.GetNamespace(), name: obj.GetName()}
+
+	var sources []interface{}
+	if src, ok, _ := unstructured.NestedMap(obj.Object, "spec", "source"); ok {
+		sources = append(sources, src)
+	}
+	multi, _, _ := unstructured.NestedSlice(obj.Object, "spec", "sources")
+	sources = append(sources, multi...)
+	var parts []string
+	for _, s := range sources {
+		sm, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		repo, _ := sm["repoURL"].(string)
+		path, _ := sm["path"].(string)
+		if chart, _ := sm["chart"].(string); path == "" && chart != "" {
+			path = "chart " + chart
+		}
+		part := repo
+		if path != "" {
+			part += " " + path
+		}
+		if rev, _ := sm["targetRevision"].(string); rev != "" {
+			part += "@" + rev
+		}
+		parts = append(parts, part)
+	}
+	app.source = strings.Join(parts, ", ")
+
+	server, _, _ := unstructured.NestedString(obj.Object, "spec", "destination", "server")
+	destName, _, _ := unstructured.NestedString(obj.Object, "spec", "destination", "name")
+	app.remote = (server != "" && server != argoInClusterServer) || (destName != "" && destName != "in-cluster")
+	app.selfHeal, _, _ = unstructured.NestedBool(obj.Object, "spec", "syncPolicy", "automated", "selfHeal")
+
+	app.sync, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "status")
+	app.health, _, _ = unstructured.NestedString(obj.Object, "status", "health", "status")
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "phase"); phase == "Failed" || phase == "Error" {
+		msg, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "message")
+		app.failure = orDefault(msg, "sync operation "+strings.ToLower(phase))
+	}
+
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+	for _, r := range resources {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := gitopsResource{}
+		res.kind, _ = rm["kind"].(string)
+		res.namespace, _ = rm["namespace"].(string)
+		res.name, _ = rm["name"].(string)
+		res.sync, _ = rm["status"].(string)
+		res.health, _, _ = unstructured.NestedString(rm, "health", "status")
+		app.resources = append(app.resources, res)
+	}
+	return app
+}
+
+// fluxApplication reads a Flux Kustomization or HelmRelease. Resources come
+// from status.inventory, whose entry IDs are <namespace>_<name>_<group>_<kind>.
+func fluxApplication(kind string, obj *unstructured.Unstructured) gitopsApp {
+	app := gitopsApp{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+
+	if kind == "Kustomization" {
+		srcKind, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "kind")
+		srcName, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "name")
+		path, _, _ := unstructured.NestedString(obj.Object, "spec", "path")
+		app.source = strings.TrimSpace(fmt.Sprintf("%s/%s %s", srcKind, srcName, path))
+	} else {
+		chart, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "chart")
+		srcKind, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "sourceRef", "kind")
+		srcName, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "sourceRef", "name")
+		if chart == "" {
+			srcKind, _, _ = unstructured.NestedString(obj.Object, "spec", "chartRef", "kind")
+			srcName, _, _ = unstructured.NestedString(obj.Object, "spec", "chartRef", "name")
+		}
+		app.source = strings.TrimSpace(fmt.Sprintf("%s/%s %s", srcKind, srcName, chart))
+	}
+	if rev, _, _ := unstructured.NestedString(obj.Object, "status", "lastAppliedRevision"); rev != "" {
+		app.source += "@" + rev
+	}
+
+	_, app.remote, _ = unstructured.NestedMap(obj.Object, "spec", "kubeConfig")
+	app.suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok || cm["type"] != "Ready" {
+			continue
+		}
+		app.sync, _ = cm["status"].(string)
+		if app.sync == "False" {
+			reason, _ := cm["reason"].(string)
+			msg, _ := cm["message"].(string)
+			app.failure = strings.TrimSpace(reason + ": " + msg)
+		}
+	}
+
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "status", "inventory", "entries")
+	for _, e := range entries {
+		em, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := em["id"].(string)
+		parts := strings.Split(id, "_")
+		if len(parts) != 4 {
+			continue
+		}
+		app.resources = append(app.resources, gitopsResource{namespace: parts[0], name: parts[1], kind: parts[3]})
+	}
+	return app
+}
+
+// listGitopsApps lists Argo CD Applications and Flux Kustomizations and
+// HelmReleases; kinds whose CRD is not installed are skipped.
+func (b *BaseTool) listGitopsApps(ctx context.Context) []gitopsApp {
+	var apps []gitopsApp
+	if list, err := b.listResource(ctx, argoApplicationsGVR, ""); err == nil {
+		for i := range list.Items {
+			apps = append(apps, argoApplication(&list.Items[i]))
+		}
+	}
+	if list, err := b.listResource(ctx, fluxKustomizationsGVR, ""); err == nil {
+		for i := range list.Items {
+			apps = append(apps, fluxApplication("Kustomization", &list.Items[i]))
+		}
+	}
+	if list, err := b.listResourceWithFallback(ctx, fluxHelmReleasesV2GVR, fluxHelmReleasesV2B2GVR, ""); err == nil {
+		for i := range list.Items {
+			apps = append(apps, fluxApplication("HelmRelease", &list.Items[i]))
+		}
+	}
+	return apps
+}
+
+// gitopsOwnerOf returns the app that applies obj, or nil when it is not
+// managed by GitOps. Flux labels and the Argo CD tracking annotation name the
+// owner; the legacy app.kubernetes.io/instance label is only trusted when an
+// Application of that name lists obj among its resources, since Helm charts
+// set it too.
+func (b *BaseTool) gitopsOwnerOf(ctx context.Context, obj *unstructured.Unstructured) *gitopsApp {
+	if obj == nil || b.Clients == nil || b.Clients.Dynamic == nil {
+		return nil
+	}
+	labels := obj.GetLabels()
+	if name := labels[fluxKustomizationLabel]; name != "" {
+		ns := labels[fluxKustomizationNsLabel]
+		if k, err := b.Clients.Dynamic.Resource(fluxKustomizationsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			app := fluxApplication("Kustomization", k)
+			return &app
+		}
+		return &gitopsApp{kind: "Kustomization", namespace: ns, name: name}
+	}
+	if name := labels[fluxHelmReleaseLabel]; name != "" {
+		ns := labels[fluxHelmReleaseNsLabel]
+		if hr, err := getWithFallback(ctx, b.Clients.Dynamic, fluxHelmReleasesV2GVR, fluxHelmReleasesV2B2GVR, ns, name); err == nil {
+			app := fluxApplication("HelmRelease", hr)
+			return &app
+		}
+		return &gitopsApp{kind: "HelmRelease", namespace: ns, name: name}
+	}
+
+	appName, tracked := labels[argoInstanceLabel], false
+	if id := obj.GetAnnotations()[argoTrackingAnnotation]; id != "" {
+		appName, tracked = strings.SplitN(id, ":", 2)[0], true
+	}
+	if appName == "" {
+		return nil
+	}
+	// Applications outside the control plane namespace are tracked as <namespace>_<name>.
+	appNs := ""
+	if i := strings.Index(appName, "_"); i > 0 {
+		appNs, appName = appName[:i], appName[i+1:]
+	}
+	list, err := b.listResource(ctx, argoApplicationsGVR, appNs)
+	if err != nil {
+		return nil
+	}
+	for i := range list.Items {
+		if list.Items[i].GetName() != appName {
+			continue
+		}
+		app := argoApplication(&list.Items[i])
+		if tracked || app.manages(obj.GetKind(), obj.GetNamespace(), obj.GetName()) {
+			return &app
+		}
+	}
+	return nil
+}
+
+// --- list_gitops_resources ---
+
+type ListGitopsResourcesTool struct{ BaseTool }
+
+func (t *ListGitopsResourcesTool) Name() string { return "list_gitops_resources" }
+func (t *ListGitopsResourcesTool) Description() string {
+	return "Map networking resources (Services, Ingresses, NetworkPolicies, Gateway API, Istio) to the Argo CD Application or Flux Kustomization that applies them from Git, with their sync and health status; out-of-sync resources are flagged with where to fix them in Git"
+}
+func (t *ListGitopsResourcesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the resources (empty for all namespaces)",
+			},
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list this kind, e.g. HTTPRoute (default all networking kinds)",
+			},
+		},
+	}
+}
+
+func (t *ListGitopsResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	kind := getStringArg(args, "kind", "")
+
+	apps := t.listGitopsApps(ctx)
+	var findings []types.DiagnosticFinding
+	managed, owners, remote := 0, 0, 0
+	for i := range apps {
+		app := &apps[i]
+		if app.remote {
+			remote++
+			continue
+		}
+		resources := app.networkingResources(ns)
+		counted := false
+		for _, r := range resources {
+			if kind != "" && r.kind != kind {
+				continue
+			}
+			if !counted {
+				owners++
+				counted = true
+			}
+			managed++
+			state := strings.Join(nonEmpty(r.sync, r.health), ", ")
+			if state == "" {
+				state = "Ready=" + orDefault(app.sync, "Unknown")
+			}
+			f := types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryRouting,
+				Resource: &types.ResourceRef{Kind: r.kind, Namespace: r.namespace, Name: r.name},
+				Summary:  fmt.Sprintf("%s %s: managed by %s, %s", r.kind, qualifiedName(r.namespace, r.name), app, state),
+				Detail:   app.source,
+			}
+			if r.sync == "OutOfSync" {
+				f.Severity = types.SeverityWarning
+				f.Code = types.CodeGitOpsResourceOutOfSync
+				f.Suggestion = fmt.Sprintf("%s Compare with: %s", app.fixInGit(), app.diffCommand())
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	summary := fmt.Sprintf("%d networking resources managed by %d GitOps applications", managed, owners)
+	if managed == 0 {
+		summary = "No networking resources managed by Argo CD or Flux found"
+	}
+	if remote > 0 {
+		summary += fmt.Sprintf("; %d applications deploying to other clusters were skipped", remote)
+	}
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Summary:  summary,
+	})
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gitops"), nil
+}
+
+// --- check_gitops_sync ---
+
+type CheckGitopsSyncTool struct{ BaseTool }
+
+func (t *CheckGitopsSyncTool) Name() string { return "check_gitops_sync" }
+func (t *CheckGitopsSyncTool) Description() string {
+	return "Check the sync and drift status of the Argo CD Applications and Flux Kustomizations and HelmReleases that manage networking resources: out-of-sync resources, failed syncs, degraded health and suspended reconciliation"
+}
+func (t *CheckGitopsSyncTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check applications that manage networking resources in this namespace (empty for all)",
+			},
+		},
+	}
+}
+
+func (t *CheckGitopsSyncTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	var findings []types.DiagnosticFinding
+	checked := 0
+	for _, app := range t.listGitopsApps(ctx) {
+		resources := app.networkingResources(ns)
+		// HelmReleases without an inventory may manage anything in their namespace.
+		unknown := len(app.resources) == 0 && app.kind == "HelmRelease" && (ns == "" || app.namespace == ns)
+		if app.remote || (len(resources) == 0 && !unknown) {
+			continue
+		}
+		checked++
+		findings = append(findings, gitopsAppFindings(&app, resources)...)
+	}
+
+	if checked == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  "No Argo CD or Flux applications manage networking resources",
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gitops"), nil
+}
+
+// gitopsAppFindings reports the sync state of app, which manages resources.
+func gitopsAppFindings(app *gitopsApp, resources []gitopsResource) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(severity string, code types.FindingCode, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryRouting,
+			Code:       code,
+			Resource:   app.ref(),
+			Summary:    summary,
+			Detail:     detail,
+			Suggestion: suggestion,
+		})
+	}
+
+	if app.failure != "" {
+		add(types.SeverityCritical, types.CodeGitOpsSyncFailed,
+			fmt.Sprintf("%s failed to apply Git: %s", app, app.failure), app.source,
+			fmt.Sprintf("Fix the error in %s, then run: %s", orDefault(app.source, "the source"), app.syncCommand()))
+	}
+	if app.suspended {
+		add(types.SeverityWarning, types.CodeGitOpsSuspended,
+			fmt.Sprintf("%s is suspended: changes in Git are not applied", app), app.source,
+			fmt.Sprintf("Resume it when the freeze is over: flux resume %s %s -n %s", strings.ToLower(app.kind), app.name, app.namespace))
+	}
+	if app.sync == "OutOfSync" {
+		var drifted []string
+		for _, r := range resources {
+			if r.sync == "OutOfSync" {
+				drifted = append(drifted, fmt.Sprintf("%s %s", r.kind, qualifiedName(r.namespace, r.name)))
+			}
+		}
+		sort.Strings(drifted)
+		detail := "Out-of-sync networking resources: " + orDefault(strings.Join(drifted, ", "), "none")
+		suggestion := fmt.Sprintf("Review the drift with %s. If the cluster is right, commit the change to Git; otherwise run %s.", app.diffCommand(), app.syncCommand())
+		if app.selfHeal {
+			suggestion += " Self-heal is on, so manual edits are reverted."
+		}
+		add(types.SeverityWarning, types.CodeGitOpsAppOutOfSync,
+			fmt.Sprintf("%s is OutOfSync (%d networking resources drifted)", app, len(drifted)), detail, suggestion)
+	}
+	if gitopsHealthProblems[app.health] {
+		add(types.SeverityWarning, types.CodeGitOpsAppUnhealthy,
+			fmt.Sprintf("%s health is %s", app, app.health), app.source,
+			fmt.Sprintf("List unhealthy resources with: argocd app get %s --show-operation", app.name))
+	}
+
+	if len(findings) == 0 {
+		state := strings.Join(nonEmpty(app.sync, app.health), ", ")
+		if app.kind != "Application" {
+			state = "Ready=" + orDefault(app.sync, "Unknown")
+		}
+		severity := types.SeverityOK
+		if app.sync == "" || app.sync == "Unknown" {
+			severity = types.SeverityInfo
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: severity,
+			Category: types.CategoryRouting,
+			Resource: app.ref(),
+			Summary:  fmt.Sprintf("%s: %s, %d networking resources", app, orDefault(state, "no status"), len(resources)),
+			Detail:   app.source,
+		})
+	}
+	return findings
+}
+
+// nonEmpty returns the non-empty values.
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
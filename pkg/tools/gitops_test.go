@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func newGitopsClient(t *testing.T) *k8s.Clients {
+	t.Helper()
+	obj := func(apiVersion, kind, ns, name string, fields map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: fields}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	shop := obj("argoproj.io/v1alpha1", "Application", "argocd", "shop", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source":      map[string]interface{}{"repoURL": "https://git.example.com/shop.git", "path": "deploy", "targetRevision": "main"},
+			"destination": map[string]interface{}{"server": argoInClusterServer, "namespace": "shop"},
+			"syncPolicy":  map[string]interface{}{"automated": map[string]interface{}{"selfHeal": true}},
+		},
+		"status": map[string]interface{}{
+			"sync":   map[string]interface{}{"status": "OutOfSync"},
+			"health": map[string]interface{}{"status": "Healthy"},
+			"resources": []interface{}{
+				map[string]interface{}{"kind": "HTTPRoute", "namespace": "shop", "name": "web", "status": "OutOfSync"},
+				map[string]interface{}{"kind": "Service", "namespace": "shop", "name": "web", "status": "Synced", "health": map[string]interface{}{"status": "Healthy"}},
+				map[string]interface{}{"kind": "Deployment", "namespace": "shop", "name": "web", "status": "Synced"},
+			},
+		},
+	})
+	edge := obj("argoproj.io/v1alpha1", "Application", "argocd", "edge", map[string]interface{}{
+		"spec": map[string]interface{}{"destination": map[string]interface{}{"server": "https://edge.example.com"}},
+		"status": map[string]interface{}{
+			"sync":      map[string]interface{}{"status": "OutOfSync"},
+			"resources": []interface{}{map[string]interface{}{"kind": "Service", "namespace": "shop", "name": "edge", "status": "OutOfSync"}},
+		},
+	})
+	policies := obj("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "policies", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"suspend":   true,
+			"path":      "./policies",
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "platform"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			"inventory": map[string]interface{}{"entries": []interface{}{
+				map[string]interface{}{"id": "shop_deny-all_networking.k8s.io_NetworkPolicy", "v": "v1"},
+			}},
+		},
+	})
+	web := obj("v1", "Service", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+	})
+	web.SetAnnotations(map[string]string{argoTrackingAnnotation: "shop:/Service:shop/web"})
+	// Helm sets the instance label too; it must not be mistaken for Argo CD tracking.
+	api := obj("v1", "Service", "shop", "api", map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "api"}},
+	})
+	api.SetLabels(map[string]string{argoInstanceLabel: "shop"})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		argoApplicationsGVR: "ApplicationList", fluxKustomizationsGVR: "KustomizationList",
+		fluxHelmReleasesV2GVR: "HelmReleaseList", fluxHelmReleasesV2B2GVR: "HelmReleaseList",
+		servicesGVR: "ServiceList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, web, api)
+	for gvr, item := range map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		argoApplicationsGVR:   {shop, edge},
+		fluxKustomizationsGVR: {policies},
+	} {
+		for _, i := range item {
+			if err := client.Tracker().Create(gvr, i, i.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return &k8s.Clients{Dynamic: client}
+}
+
+func runGitopsTool(t *testing.T, tool Tool, args map[string]interface{}) []types.DiagnosticFinding {
+	t.Helper()
+	resp, err := tool.Run(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.(*types.ToolResult).Findings
+}
+
+func TestListGitopsResources(t *testing.T) {
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: newGitopsClient(t)}
+	findings := runGitopsTool(t, &ListGitopsResourcesTool{BaseTool: base}, map[string]interface{}{"namespace": "shop"})
+
+	var outOfSync []string
+	for _, f := range findings {
+		if f.Resource != nil && f.Resource.Kind == "Deployment" {
+			t.Errorf("non-networking resource reported: %+v", f)
+		}
+		if f.Code == types.CodeGitOpsResourceOutOfSync {
+			outOfSync = append(outOfSync, f.Resource.Kind+"/"+f.Resource.Name)
+			if !strings.HasPrefix(f.Suggestion, "Fix this in Git: it is managed by Argo CD Application argocd/shop") {
+				t.Errorf("unexpected suggestion %q", f.Suggestion)
+			}
+		}
+	}
+	if len(outOfSync) != 1 || outOfSync[0] != "HTTPRoute/web" {
+		t.Errorf("expected only HTTPRoute/web to be out of sync, got %v", outOfSync)
+	}
+	summary := findings[len(findings)-1].Summary
+	if !strings.HasPrefix(summary, "3 networking resources managed by 2 GitOps applications") || !strings.Contains(summary, "1 applications deploying to other clusters") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+}
+
+func TestCheckGitopsSync(t *testing.T) {
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: newGitopsClient(t)}
+	findings := runGitopsTool(t, &CheckGitopsSyncTool{BaseTool: base}, map[string]interface{}{})
+
+	codes := make(map[types.FindingCode]string)
+	for _, f := range findings {
+		codes[f.Code] = f.Resource.Name
+		if f.Code == types.CodeGitOpsAppOutOfSync && f.Detail != "Out-of-sync networking resources: HTTPRoute shop/web" {
+			t.Errorf("unexpected detail %q", f.Detail)
+		}
+	}
+	if codes[types.CodeGitOpsAppOutOfSync] != "shop" || codes[types.CodeGitOpsSuspended] != "policies" {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+	if len(findings) != 2 {
+		t.Errorf("expected the remote application to be skipped, got %d findings", len(findings))
+	}
+}
+
+func TestRemediationNamesGitopsOwner(t *testing.T) {
+	tool := &SuggestRemediationTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: newGitopsClient(t)}}
+	for name, managed := range map[string]bool{"web": true, "api": false} {
+		findings := runGitopsTool(t, tool, map[string]interface{}{
+			"code": string(types.CodeServiceNoEndpoints), "resource_kind": "Service", "resource_name": name, "namespace": "shop",
+		})
+		got := strings.HasPrefix(findings[0].Detail, "Fix this in Git: it is managed by Argo CD Application argocd/shop")
+		if got != managed {
+			t.Errorf("Service %s: fix in Git = %v, want %v (detail %q)", name, got, managed, findings[0].Detail)
+		}
+	}
+}
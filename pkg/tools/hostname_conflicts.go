@@ -0,0 +1,580 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// hostClaim is a resource claiming a hostname on an entry point: an
+// ingress class, a Gateway API Gateway or the proxies an Istio Gateway
+// selects.
+type hostClaim struct {
+	host     string
+	ref      types.ResourceRef
+	frontend string
+	// backends maps a path to the sorted "namespace/service:port" backends
+	// it routes to. Gateway listeners and Istio Gateway servers claim hosts
+	// without routing them.
+	backends map[string]string
+}
+
+func (c hostClaim) String() string {
+	s := fmt.Sprintf("%s %s/%s via %s", c.ref.Kind, c.ref.Namespace, c.ref.Name, c.frontend)
+	paths := make([]string, 0, len(c.backends))
+	for p := range c.backends {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		s += fmt.Sprintf("; %s -> %s", p, orDefault(c.backends[p], "no backend"))
+	}
+	return s
+}
+
+// --- detect_hostname_conflicts ---
+
+type DetectHostnameConflictsTool struct{ BaseTool }
+
+func (t *DetectHostnameConflictsTool) Name() string { return "detect_hostname_conflicts" }
+func (t *DetectHostnameConflictsTool) Description() string {
+	return "Index the hostnames claimed by Ingresses, Gateway listeners, HTTPRoutes, GRPCRoutes, Istio Gateways and VirtualServices, and flag hosts served by several entry points or routed to different backends"
+}
+func (t *DetectHostnameConflictsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"hostname": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check this hostname",
+			},
+		},
+	}
+}
+
+func (t *DetectHostnameConflictsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	hostname := strings.TrimSuffix(strings.ToLower(getStringArg(args, "hostname", "")), ".")
+
+	var claims []hostClaim
+	if list, err := t.listResource(ctx, ingressGVR, ""); err == nil {
+		claims = append(claims, ingressHostClaims(list.Items)...)
+	}
+	var gateways []unstructured.Unstructured
+	if list, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ""); err == nil {
+		gateways = list.Items
+		claims = append(claims, gatewayListenerClaims(gateways)...)
+	}
+	claims = append(claims, routeHostClaims(t.listRoutes(ctx), gateways)...)
+	var istioGateways []unstructured.Unstructured
+	if list, err := t.listResourceWithFallback(ctx, istioGatewayV1GVR, istioGatewayV1B1GVR, ""); err == nil {
+		istioGateways = list.Items
+		claims = append(claims, istioGatewayClaims(istioGateways)...)
+	}
+	if list, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ""); err == nil {
+		claims = append(claims, virtualServiceClaims(list.Items, istioGateways)...)
+	}
+
+	byHost := make(map[string][]hostClaim)
+	for _, c := range claims {
+		if hostname == "" || c.host == hostname {
+			byHost[c.host] = append(byHost[c.host], c)
+		}
+	}
+	hosts := make([]string, 0, len(byHost))
+	for h := range byHost {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+
+	var findings []types.DiagnosticFinding
+	for _, h := range hosts {
+		findings = append(findings, hostConflictFindings(h, byHost[h])...)
+	}
+	if len(findings) == 0 {
+		summary := fmt.Sprintf("No conflicts among %d hostnames claimed by Ingresses, Gateways, routes and VirtualServices", len(hosts))
+		if hostname != "" {
+			summary = fmt.Sprintf("%s is claimed by %d resource(s) without conflict", hostname, len(byHost[hostname]))
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Summary:  summary,
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "all", "multi"), nil
+}
+
+// hostConflictFindings reports a host served by several entry points, and
+// paths of one host routed to different backends.
+func hostConflictFindings(host string, claims []hostClaim) []types.DiagnosticFinding {
+	sort.Slice(claims, func(i, j int) bool { return claims[i].String() < claims[j].String() })
+	frontends := make(map[string]bool)
+	for _, c := range claims {
+		frontends[c.frontend] = true
+	}
+	// backends[path][backends] lists the claims routing path to backends.
+	backends := make(map[string]map[string][]hostClaim)
+	for _, c := range claims {
+		for p, b := range c.backends {
+			if backends[p] == nil {
+				backends[p] = make(map[string][]hostClaim)
+			}
+			backends[p][b] = append(backends[p][b], c)
+		}
+	}
+	paths := make([]string, 0, len(backends))
+	for p := range backends {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	lines := make([]string, 0, len(claims))
+	for _, c := range claims {
+		lines = append(lines, c.String())
+	}
+
+	var findings []types.DiagnosticFinding
+	if len(frontends) > 1 {
+		names := make([]string, 0, len(frontends))
+		for f := range frontends {
+			names = append(names, f)
+		}
+		sort.Strings(names)
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeHostnameMultipleEntryPoints,
+			Resource:   &claims[0].ref,
+			Summary:    fmt.Sprintf("%s is claimed on %d entry points: %s", host, len(names), strings.Join(names, ", ")),
+			Detail:     strings.Join(lines, "\n"),
+			Suggestion: "DNS sends each client to one entry point, so which configuration serves a request depends on the resolver. Serve the host from one ingress stack and remove it from the others, unless split-horizon DNS sends each network to its own entry point.",
+		}
+		for _, p := range paths {
+			if len(backends[p]) > 1 {
+				f.Severity = types.SeverityCritical
+				f.Summary += fmt.Sprintf(", and %s%s is routed to different backends", host, p)
+				break
+			}
+		}
+		findings = append(findings, f)
+	}
+
+	for _, p := range paths {
+		if len(backends[p]) < 2 {
+			continue
+		}
+		byFrontend := make(map[string]map[string]bool)
+		for b, cs := range backends[p] {
+			for _, c := range cs {
+				if byFrontend[c.frontend] == nil {
+					byFrontend[c.frontend] = make(map[string]bool)
+				}
+				byFrontend[c.frontend][b] = true
+			}
+		}
+		frontendNames := make([]string, 0, len(byFrontend))
+		for fe := range byFrontend {
+			frontendNames = append(frontendNames, fe)
+		}
+		sort.Strings(frontendNames)
+		for _, fe := range frontendNames {
+			if len(byFrontend[fe]) < 2 {
+				continue
+			}
+			var detail []string
+			var ref *types.ResourceRef
+			for b, cs := range backends[p] {
+				for _, c := range cs {
+					if c.frontend == fe {
+						detail = append(detail, fmt.Sprintf("%s %s/%s -> %s", c.ref.Kind, c.ref.Namespace, c.ref.Name, orDefault(b, "no backend")))
+						if ref == nil || c.ref.Namespace+"/"+c.ref.Name < ref.Namespace+"/"+ref.Name {
+							cr := c.ref
+							ref = &cr
+						}
+					}
+				}
+			}
+			sort.Strings(detail)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeHostnameConflictingBackends,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s%s is routed to %d different backends on %s", host, p, len(byFrontend[fe]), fe),
+				Detail:     strings.Join(detail, "\n"),
+				Suggestion: "Only one of these rules receives the traffic, chosen by the controller's merge order. Keep the path in one resource or give the rules distinct matches.",
+			})
+		}
+	}
+	return findings
+}
+
+// headerMatchKey renders header matches, a list of HTTPRoute matches or a
+// map of VirtualService matches, so that rules selecting different requests
+// on the same path are not reported as conflicting.
+func headerMatchKey(headers interface{}) string {
+	var parts []string
+	switch h := headers.(type) {
+	case []interface{}:
+		for _, m := range h {
+			mm, _ := m.(map[string]interface{})
+			name, _ := mm["name"].(string)
+			value, _ := mm["value"].(string)
+			parts = append(parts, strings.ToLower(name)+"="+value)
+		}
+	case map[string]interface{}:
+		for name, m := range h {
+			mm, _ := m.(map[string]interface{})
+			for _, v := range mm {
+				parts = append(parts, fmt.Sprintf("%s=%v", strings.ToLower(name), v))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	sort.Strings(parts)
+	return " headers " + strings.Join(parts, ",")
+}
+
+func normalizeHost(h string) string {
+	return strings.TrimSuffix(strings.ToLower(h), ".")
+}
+
+// serviceBackend renders a backend as "namespace/name:port".
+func serviceBackend(ns, name string, port interface{}) string {
+	if p := toInt(port); p > 0 {
+		return fmt.Sprintf("%s/%s:%d", ns, name, p)
+	}
+	if s, _ := port.(string); s != "" {
+		return fmt.Sprintf("%s/%s:%s", ns, name, s)
+	}
+	return ns + "/" + name
+}
+
+func joinBackends(backends []string) string {
+	sort.Strings(backends)
+	out := backends[:0]
+	for i, b := range backends {
+		if i == 0 || b != backends[i-1] {
+			out = append(out, b)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+// ingressHostClaims returns the rule hosts of Ingresses, on the entry point
+// of their ingress class.
+func ingressHostClaims(ingresses []unstructured.Unstructured) []hostClaim {
+	var out []hostClaim
+	for _, ing := range ingresses {
+		class, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+		if class == "" {
+			class = ing.GetAnnotations()["kubernetes.io/ingress.class"]
+		}
+		frontend := "IngressClass " + orDefault(class, "(default)")
+		ref := types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+		byHost := make(map[string]map[string][]string)
+		var order []string
+		rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+		for _, r := range rules {
+			rm, _ := r.(map[string]interface{})
+			host, _ := rm["host"].(string)
+			if host == "" {
+				continue
+			}
+			host = normalizeHost(host)
+			if byHost[host] == nil {
+				byHost[host] = make(map[string][]string)
+				order = append(order, host)
+			}
+			paths, _, _ := unstructured.NestedSlice(rm, "http", "paths")
+			for _, p := range paths {
+				pm, _ := p.(map[string]interface{})
+				path, _ := pm["path"].(string)
+				name, _, _ := unstructured.NestedString(pm, "backend", "service", "name")
+				if name == "" {
+					continue
+				}
+				port := nestedValue(pm, "backend", "service", "port", "number")
+				if port == nil {
+					port = nestedValue(pm, "backend", "service", "port", "name")
+				}
+				key := orDefault(path, "/")
+				byHost[host][key] = append(byHost[host][key], serviceBackend(ing.GetNamespace(), name, port))
+			}
+		}
+		for _, h := range order {
+			c := hostClaim{host: h, ref: ref, frontend: frontend, backends: make(map[string]string)}
+			for p, b := range byHost[h] {
+				c.backends[p] = joinBackends(b)
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func gatewayFrontend(gw unstructured.Unstructured) string {
+	class, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+	return fmt.Sprintf("Gateway %s/%s (class %s)", gw.GetNamespace(), gw.GetName(), class)
+}
+
+// gatewayListenerClaims returns the listener hostnames of Gateways.
+func gatewayListenerClaims(gateways []unstructured.Unstructured) []hostClaim {
+	var out []hostClaim
+	for _, gw := range gateways {
+		ref := types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+		seen := make(map[string]bool)
+		listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+		for _, l := range listeners {
+			lm, _ := l.(map[string]interface{})
+			host, _ := lm["hostname"].(string)
+			if host = normalizeHost(host); host == "" || seen[host] {
+				continue
+			}
+			seen[host] = true
+			out = append(out, hostClaim{host: host, ref: ref, frontend: gatewayFrontend(gw)})
+		}
+	}
+	return out
+}
+
+// routeHostClaims returns the hostnames HTTPRoutes and GRPCRoutes serve on
+// each parent Gateway: their hostnames intersected with the listener
+// hostnames.
+func routeHostClaims(routes []routeInfo, gateways []unstructured.Unstructured) []hostClaim {
+	gwByKey := make(map[string]unstructured.Unstructured, len(gateways))
+	for _, gw := range gateways {
+		gwByKey[gw.GetNamespace()+"/"+gw.GetName()] = gw
+	}
+	var out []hostClaim
+	for _, r := range routes {
+		ref := types.ResourceRef{Kind: r.kind, Namespace: r.namespace, Name: r.name}
+		routeHosts, _, _ := unstructured.NestedStringSlice(r.obj, "spec", "hostnames")
+		backends := routeRuleBackends(r)
+		parentRefs, _, _ := unstructured.NestedSlice(r.obj, "spec", "parentRefs")
+		for _, parent := range routeParentGateways(r) {
+			gw, ok := gwByKey[parent.Namespace+"/"+parent.Name]
+			if !ok {
+				continue
+			}
+			sections := make(map[string]bool)
+			for _, p := range parentRefs {
+				pm, _ := p.(map[string]interface{})
+				name, _ := pm["name"].(string)
+				ns, _ := pm["namespace"].(string)
+				section, _ := pm["sectionName"].(string)
+				if name == parent.Name && orDefault(ns, r.namespace) == parent.Namespace {
+					sections[section] = true
+				}
+			}
+			hosts := make(map[string]bool)
+			listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+			for _, l := range listeners {
+				lm, _ := l.(map[string]interface{})
+				name, _ := lm["name"].(string)
+				if !sections[""] && !sections[name] {
+					continue
+				}
+				listenerHost, _ := lm["hostname"].(string)
+				for _, h := range listenerRouteHosts(listenerHost, routeHosts) {
+					hosts[normalizeHost(h)] = true
+				}
+			}
+			for h := range hosts {
+				out = append(out, hostClaim{host: h, ref: ref, frontend: gatewayFrontend(gw), backends: backends})
+			}
+		}
+	}
+	return out
+}
+
+// routeRuleBackends maps the paths a route matches to its backends. GRPCRoute
+// matches are rendered as /service/method paths.
+func routeRuleBackends(r routeInfo) map[string]string {
+	byPath := make(map[string][]string)
+	rules, _, _ := unstructured.NestedSlice(r.obj, "spec", "rules")
+	for _, rule := range rules {
+		rm, _ := rule.(map[string]interface{})
+		var backends []string
+		refs, _ := rm["backendRefs"].([]interface{})
+		for _, b := range refs {
+			bm, _ := b.(map[string]interface{})
+			name, _ := bm["name"].(string)
+			ns, _ := bm["namespace"].(string)
+			if name != "" {
+				backends = append(backends, serviceBackend(orDefault(ns, r.namespace), name, bm["port"]))
+			}
+		}
+		paths := []string{"/"}
+		if matches, _ := rm["matches"].([]interface{}); len(matches) > 0 {
+			paths = paths[:0]
+			for _, m := range matches {
+				mm, _ := m.(map[string]interface{})
+				path := "/"
+				if r.kind == "GRPCRoute" {
+					service, _, _ := unstructured.NestedString(mm, "method", "service")
+					method, _, _ := unstructured.NestedString(mm, "method", "method")
+					path = orDefault(strings.TrimRight("/"+service+"/"+method, "/"), "/")
+				} else {
+					if v, _, _ := unstructured.NestedString(mm, "path", "value"); v != "" {
+						path = v
+					}
+					if method, _ := mm["method"].(string); method != "" {
+						path += " method=" + method
+					}
+				}
+				paths = append(paths, path+headerMatchKey(mm["headers"]))
+			}
+		}
+		for _, p := range paths {
+			byPath[p] = append(byPath[p], backends...)
+		}
+	}
+	out := make(map[string]string, len(byPath))
+	for p, b := range byPath {
+		out[p] = joinBackends(b)
+	}
+	return out
+}
+
+func istioGatewayFrontend(gw unstructured.Unstructured) string {
+	selector, _, _ := unstructured.NestedStringMap(gw.Object, "spec", "selector")
+	parts := make([]string, 0, len(selector))
+	for k, v := range selector {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("Istio gateway proxies {%s}", strings.Join(parts, ","))
+}
+
+// istioHostname returns the DNS name of an Istio host, without the
+// namespace part of a Gateway server host; "*" is not a hostname.
+func istioHostname(h string) string {
+	if _, dnsName := istioServerHost(h); dnsName != "*" {
+		return normalizeHost(dnsName)
+	}
+	return ""
+}
+
+// istioGatewayClaims returns the server hosts of Istio Gateways, on the
+// proxies their selector picks.
+func istioGatewayClaims(gateways []unstructured.Unstructured) []hostClaim {
+	var out []hostClaim
+	for _, gw := range gateways {
+		ref := types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+		seen := make(map[string]bool)
+		servers, _, _ := unstructured.NestedSlice(gw.Object, "spec", "servers")
+		for _, s := range servers {
+			sm, _ := s.(map[string]interface{})
+			hosts, _, _ := unstructured.NestedStringSlice(sm, "hosts")
+			for _, h := range hosts {
+				if h = istioHostname(h); h == "" || seen[h] {
+					continue
+				}
+				seen[h] = true
+				out = append(out, hostClaim{host: h, ref: ref, frontend: istioGatewayFrontend(gw)})
+			}
+		}
+	}
+	return out
+}
+
+// virtualServiceClaims returns the hosts of VirtualServices bound to Istio
+// Gateways. VirtualServices that only apply to the mesh are skipped.
+func virtualServiceClaims(services []unstructured.Unstructured, gateways []unstructured.Unstructured) []hostClaim {
+	gwByKey := make(map[string]unstructured.Unstructured, len(gateways))
+	for _, gw := range gateways {
+		gwByKey[gw.GetNamespace()+"/"+gw.GetName()] = gw
+	}
+	var out []hostClaim
+	for _, vs := range services {
+		ref := types.ResourceRef{Kind: "VirtualService", Namespace: vs.GetNamespace(), Name: vs.GetName(), APIVersion: vs.GetAPIVersion()}
+		hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+		bound, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+		backends := virtualServiceBackends(vs)
+		frontends := make(map[string]bool)
+		for _, g := range bound {
+			if g == "mesh" {
+				continue
+			}
+			ns, name, ok := strings.Cut(g, "/")
+			if !ok {
+				ns, name = vs.GetNamespace(), g
+			}
+			if gw, ok := gwByKey[ns+"/"+name]; ok {
+				frontends[istioGatewayFrontend(gw)] = true
+			}
+		}
+		for fe := range frontends {
+			for _, h := range hosts {
+				if h = istioHostname(h); h != "" {
+					out = append(out, hostClaim{host: h, ref: ref, frontend: fe, backends: backends})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// virtualServiceBackends maps the URI matches of a VirtualService's HTTP
+// routes to their destinations.
+func virtualServiceBackends(vs unstructured.Unstructured) map[string]string {
+	byPath := make(map[string][]string)
+	routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+	for _, r := range routes {
+		rm, _ := r.(map[string]interface{})
+		var backends []string
+		dests, _ := rm["route"].([]interface{})
+		for _, d := range dests {
+			dm, _ := d.(map[string]interface{})
+			host, _, _ := unstructured.NestedString(dm, "destination", "host")
+			if host == "" {
+				continue
+			}
+			ns, name := vs.GetNamespace(), host
+			if parts := strings.Split(host, "."); len(parts) >= 2 && (len(parts) == 2 || parts[2] == "svc") {
+				ns, name = parts[1], parts[0]
+			} else if len(parts) > 2 {
+				ns, name = "", host
+			}
+			backend := serviceBackend(ns, name, nestedValue(dm, "destination", "port", "number"))
+			backends = append(backends, strings.TrimPrefix(backend, "/"))
+		}
+		paths := []string{"/"}
+		if matches, _ := rm["match"].([]interface{}); len(matches) > 0 {
+			paths = paths[:0]
+			for _, m := range matches {
+				mm, _ := m.(map[string]interface{})
+				path := "/"
+				for _, kind := range []string{"prefix", "exact", "regex"} {
+					if v, _, _ := unstructured.NestedString(mm, "uri", kind); v != "" {
+						path = v
+						break
+					}
+				}
+				if method, _, _ := unstructured.NestedString(mm, "method", "exact"); method != "" {
+					path += " method=" + method
+				}
+				paths = append(paths, path+headerMatchKey(mm["headers"]))
+			}
+		}
+		for _, p := range paths {
+			// The first matching HTTP route wins; later ones never see the path.
+			if _, ok := byPath[p]; !ok {
+				byPath[p] = backends
+			}
+		}
+	}
+	out := make(map[string]string, len(byPath))
+	for p, b := range byPath {
+		out[p] = joinBackends(b)
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func hostIngress(name, class, host, path, service string, port int64) *unstructured.Unstructured {
+	return managedObj("networking.k8s.io/v1", "Ingress", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ingressClassName": class,
+			"rules": []interface{}{map[string]interface{}{
+				"host": host,
+				"http": map[string]interface{}{"paths": []interface{}{map[string]interface{}{
+					"path":    path,
+					"backend": map[string]interface{}{"service": map[string]interface{}{"name": service, "port": map[string]interface{}{"number": port}}},
+				}}},
+			}},
+		},
+	})
+}
+
+func TestDetectHostnameConflicts(t *testing.T) {
+	gateway := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "public", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"gatewayClassName": "istio",
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "https", "hostname": "*.example.com", "port": int64(443), "protocol": "HTTPS"},
+			},
+		},
+	})
+	route := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra"}},
+			"hostnames":  []interface{}{"shop.example.com"},
+			"rules": []interface{}{map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "web-v2", "port": int64(8080)}},
+			}},
+		},
+	})
+	istioGateway := managedObj("networking.istio.io/v1", "Gateway", "istio-system", "ingress", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"istio": "ingressgateway"},
+			"servers":  []interface{}{map[string]interface{}{"hosts": []interface{}{"*/api.example.com"}, "port": map[string]interface{}{"number": int64(80)}}},
+		},
+	})
+	virtualService := managedObj("networking.istio.io/v1", "VirtualService", "shop", "api", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hosts":    []interface{}{"api.example.com"},
+			"gateways": []interface{}{"istio-system/ingress"},
+			"http": []interface{}{map[string]interface{}{
+				"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "api.shop.svc.cluster.local", "port": map[string]interface{}{"number": int64(80)}}}},
+			}},
+		},
+	})
+	meshOnly := managedObj("networking.istio.io/v1", "VirtualService", "shop", "internal", map[string]interface{}{
+		"spec": map[string]interface{}{"hosts": []interface{}{"api.example.com"}, "gateways": []interface{}{"mesh"}},
+	})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		ingressGVR:          "IngressList",
+		istioGatewayV1GVR:   "GatewayList",
+		istioGatewayV1B1GVR: "GatewayList",
+		vsV1GVR:             "VirtualServiceList",
+		vsV1B1GVR:           "VirtualServiceList",
+	}
+	for gvr, kind := range managedListKinds {
+		listKinds[gvr] = kind
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for gvr, items := range map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		ingressGVR: {
+			hostIngress("web", "nginx", "shop.example.com", "/", "web", 80),
+			hostIngress("cart", "nginx", "shop.example.com", "/cart", "cart", 80),
+			hostIngress("cart-legacy", "nginx", "shop.example.com", "/cart", "cart-legacy", 80),
+			hostIngress("api", "traefik", "api.example.com", "/", "api", 80),
+			hostIngress("docs", "nginx", "docs.example.com", "/", "docs", 80),
+		},
+		gatewaysV1GVR:     {gateway},
+		httpRoutesV1GVR:   {route},
+		istioGatewayV1GVR: {istioGateway},
+		vsV1GVR:           {virtualService, meshOnly},
+	} {
+		for _, item := range items {
+			if err := client.Tracker().Create(gvr, item, item.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tool := &DetectHostnameConflictsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := managedFindingsOf(resp)
+	want := []string{
+		"warning api.example.com is claimed on 2 entry points: IngressClass traefik, Istio gateway proxies {istio=ingressgateway} HOST001_MULTIPLE_ENTRY_POINTS",
+		"critical shop.example.com is claimed on 2 entry points: Gateway infra/public (class istio), IngressClass nginx, and shop.example.com/ is routed to different backends HOST001_MULTIPLE_ENTRY_POINTS",
+		"warning shop.example.com/cart is routed to 2 different backends on IngressClass nginx HOST002_CONFLICTING_BACKENDS",
+	}
+	if got != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"hostname": "Docs.Example.com."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := managedFindingsOf(resp), "ok docs.example.com is claimed by 1 resource(s) without conflict "; got != want {
+		t.Errorf("hostname filter: got %q, want %q", got, want)
+	}
+}
+
+func TestHeaderMatchKey(t *testing.T) {
+	route := []interface{}{
+		map[string]interface{}{"name": "X-Canary", "value": "true"},
+		map[string]interface{}{"name": "env", "value": "beta"},
+	}
+	if got, want := headerMatchKey(route), " headers env=beta,x-canary=true"; got != want {
+		t.Errorf("HTTPRoute headers: got %q, want %q", got, want)
+	}
+	vs := map[string]interface{}{"end-user": map[string]interface{}{"exact": "jason"}}
+	if got, want := headerMatchKey(vs), " headers end-user=jason"; got != want {
+		t.Errorf("VirtualService headers: got %q, want %q", got, want)
+	}
+	if got := headerMatchKey(nil); got != "" {
+		t.Errorf("no headers: got %q", got)
+	}
+}
@@ -0,0 +1,385 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// ingressAnnotationSet describes the annotations one Ingress controller reads.
+type ingressAnnotationSet struct {
+	name   string
+	prefix string
+	// legacyClass is the kubernetes.io/ingress.class value the controller
+	// answers to without an IngressClass.
+	legacyClass string
+	// known annotations, without the prefix; a trailing "." matches any
+	// suffix, e.g. ALB actions.<name>.
+	known []string
+	// deprecated maps removed or renamed annotations, without the prefix,
+	// to what to do instead.
+	deprecated map[string]string
+	// legacyPrefixes are prefixes of older releases, mapped to what to do
+	// instead.
+	legacyPrefixes map[string]string
+	// conflicts reports annotation combinations the controller resolves by
+	// ignoring one of them.
+	conflicts func(ing unstructured.Unstructured, annotations map[string]string) []string
+}
+
+// ingressAnnotationSets are keyed by IngressClass spec.controller.
+var ingressAnnotationSets = map[string]*ingressAnnotationSet{
+	ingressNginxController: {
+		name:        "ingress-nginx",
+		prefix:      nginxAnnotationPrefix,
+		legacyClass: "nginx",
+		known: []string{
+			"affinity", "affinity-canary-behavior", "affinity-mode", "allowlist-source-range", "app-root",
+			"auth-always-set-cookie", "auth-cache-duration", "auth-cache-key", "auth-keepalive", "auth-keepalive-requests",
+			"auth-keepalive-share-vars", "auth-keepalive-timeout", "auth-method", "auth-proxy-set-headers", "auth-realm",
+			"auth-request-redirect", "auth-response-headers", "auth-secret", "auth-secret-type", "auth-signin",
+			"auth-signin-redirect-param", "auth-snippet", "auth-tls-error-page", "auth-tls-match-cn",
+			"auth-tls-pass-certificate-to-upstream", "auth-tls-secret", "auth-tls-verify-client", "auth-tls-verify-depth",
+			"auth-type", "auth-url", "backend-protocol", "canary", "canary-by-cookie", "canary-by-header",
+			"canary-by-header-pattern", "canary-by-header-value", "canary-weight", "canary-weight-total",
+			"client-body-buffer-size", "configuration-snippet", "connection-proxy-header", "cors-allow-credentials",
+			"cors-allow-headers", "cors-allow-methods", "cors-allow-origin", "cors-expose-headers", "cors-max-age",
+			"custom-headers", "custom-http-errors", "default-backend", "denylist-source-range", "disable-proxy-intercept-errors",
+			"enable-access-log", "enable-cors", "enable-global-auth", "enable-modsecurity", "enable-opentelemetry",
+			"enable-owasp-core-rules", "enable-rewrite-log", "force-ssl-redirect", "from-to-www-redirect",
+			"http2-push-preload", "limit-allowlist", "limit-burst-multiplier", "limit-connections", "limit-rate",
+			"limit-rate-after", "limit-rpm", "limit-rps", "limit-whitelist", "load-balance", "mirror-host",
+			"mirror-request-body", "mirror-target", "modsecurity-snippet", "modsecurity-transaction-id",
+			"opentelemetry-trust-incoming-span", "permanent-redirect", "permanent-redirect-code", "preserve-trailing-slash",
+			"proxy-body-size", "proxy-buffer-size", "proxy-buffering", "proxy-buffers-number", "proxy-connect-timeout",
+			"proxy-cookie-domain", "proxy-cookie-path", "proxy-http-version", "proxy-max-temp-file-size",
+			"proxy-next-upstream", "proxy-next-upstream-timeout", "proxy-next-upstream-tries", "proxy-read-timeout",
+			"proxy-redirect-from", "proxy-redirect-to", "proxy-request-buffering", "proxy-send-timeout",
+			"proxy-ssl-ciphers", "proxy-ssl-name", "proxy-ssl-protocols", "proxy-ssl-secret", "proxy-ssl-server-name",
+			"proxy-ssl-verify", "proxy-ssl-verify-depth", "rewrite-target", "satisfy", "server-alias", "server-snippet",
+			"service-upstream", "session-cookie-change-on-failure", "session-cookie-conditional-samesite-none",
+			"session-cookie-domain", "session-cookie-expires", "session-cookie-max-age", "session-cookie-name",
+			"session-cookie-path", "session-cookie-samesite", "session-cookie-secure", "ssl-ciphers", "ssl-passthrough",
+			"ssl-prefer-server-ciphers", "ssl-redirect", "stream-snippet", "temporal-redirect", "temporal-redirect-code",
+			"upstream-hash-by", "upstream-hash-by-subset", "upstream-hash-by-subset-size", "upstream-vhost", "use-regex",
+			"whitelist-source-range", "x-forwarded-prefix",
+		},
+		deprecated: map[string]string{
+			"secure-backends":                 "Use backend-protocol: HTTPS instead.",
+			"grpc-backend":                    "Use backend-protocol: GRPC instead.",
+			"add-base-url":                    "Remove it; support was dropped in 0.22; rewrite links in the application.",
+			"base-url-scheme":                 "Remove it; support was dropped in 0.22; rewrite links in the application.",
+			"session-cookie-hash":             "Remove it; support was dropped in 0.24.",
+			"upstream-max-fails":              "Remove it; support was dropped in 0.26.",
+			"upstream-fail-timeout":           "Remove it; support was dropped in 0.26.",
+			"enable-opentracing":              "Use enable-opentelemetry instead.",
+			"opentracing-trust-incoming-span": "Use opentelemetry-trust-incoming-span instead.",
+			"enable-influxdb":                 "Remove it; InfluxDB support was dropped in 1.10.",
+		},
+		legacyPrefixes: map[string]string{"ingress.kubernetes.io/": "Use the " + nginxAnnotationPrefix + " prefix instead."},
+		conflicts:      nginxAnnotationConflicts,
+	},
+	"traefik.io/ingress-controller": {
+		name:        "Traefik",
+		prefix:      "traefik.ingress.kubernetes.io/",
+		legacyClass: "traefik",
+		known: []string{
+			"router.entrypoints", "router.middlewares", "router.pathmatcher", "router.priority", "router.tls",
+			"router.tls.certresolver", "router.tls.domains.", "router.tls.options", "router.observability.accesslogs",
+			"router.observability.metrics", "router.observability.tracing", "service.nativelb", "service.nodeportlb",
+			"service.passhostheader", "service.serversscheme", "service.serverstransport", "service.sticky.cookie",
+			"service.sticky.cookie.httponly", "service.sticky.cookie.maxage", "service.sticky.cookie.name",
+			"service.sticky.cookie.path", "service.sticky.cookie.samesite", "service.sticky.cookie.secure",
+		},
+		// Traefik 2 replaced the 1.x annotations with router and service
+		// settings and middlewares.
+		deprecated: map[string]string{
+			"frontend-entry-points":  "Use router.entrypoints instead.",
+			"priority":               "Use router.priority instead.",
+			"rule-type":              "Use router.pathmatcher instead.",
+			"rewrite-target":         "Use a ReplacePath or ReplacePathRegex Middleware in router.middlewares instead.",
+			"redirect-entry-point":   "Use a RedirectScheme Middleware in router.middlewares instead.",
+			"redirect-permanent":     "Use a RedirectScheme or RedirectRegex Middleware in router.middlewares instead.",
+			"redirect-regex":         "Use a RedirectRegex Middleware in router.middlewares instead.",
+			"redirect-replacement":   "Use a RedirectRegex Middleware in router.middlewares instead.",
+			"request-modifier":       "Use a ReplacePath, AddPrefix or StripPrefix Middleware in router.middlewares instead.",
+			"whitelist-source-range": "Use an IPAllowList Middleware in router.middlewares instead.",
+			"preserve-host":          "Use service.passhostheader instead.",
+			"protocol":               "Use service.serversscheme instead.",
+			"affinity":               "Use service.sticky.cookie instead.",
+			"session-cookie-name":    "Use service.sticky.cookie.name instead.",
+		},
+		legacyPrefixes: map[string]string{"ingress.kubernetes.io/": "Use Middlewares referenced in router.middlewares instead."},
+	},
+	"ingress.k8s.aws/alb": {
+		name:        "AWS Load Balancer Controller",
+		prefix:      "alb.ingress.kubernetes.io/",
+		legacyClass: "alb",
+		known: []string{
+			"actions.", "auth-idp-cognito", "auth-idp-oidc", "auth-on-unauthenticated-request", "auth-scope",
+			"auth-session-cookie", "auth-session-timeout", "auth-type", "backend-protocol", "backend-protocol-version",
+			"certificate-arn", "conditions.", "customer-owned-ipv4-pool", "group.name", "group.order",
+			"healthcheck-interval-seconds", "healthcheck-path", "healthcheck-port", "healthcheck-protocol",
+			"healthcheck-timeout-seconds", "healthy-threshold-count", "inbound-cidrs", "ip-address-type",
+			"listen-ports", "listener-attributes.", "load-balancer-attributes", "load-balancer-name",
+			"manage-backend-security-group-rules", "multi-cluster-target-group", "mutual-authentication", "scheme",
+			"security-group-prefix-lists", "security-groups", "shield-advanced-protection", "ssl-policy", "ssl-redirect",
+			"subnets", "success-codes", "tags", "target-group-attributes", "target-node-labels", "target-type",
+			"unhealthy-threshold-count", "wafv2-acl-arn",
+		},
+		deprecated: map[string]string{
+			"waf-acl-id": "Use wafv2-acl-arn instead; WAF Classic is no longer supported.",
+		},
+		conflicts: albAnnotationConflicts,
+	},
+}
+
+// traefikIndexedKey matches the list index of Traefik TLS domain settings,
+// e.g. router.tls.domains.0.main.
+var traefikIndexedKey = regexp.MustCompile(`\.\d+\.`)
+
+// knows reports whether short is an annotation the controller reads.
+func (s *ingressAnnotationSet) knows(short string) bool {
+	for _, k := range s.known {
+		if k == short || (strings.HasSuffix(k, ".") && strings.HasPrefix(short, k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns the known annotation nearest to short, if it is a likely
+// typo of it.
+func (s *ingressAnnotationSet) closest(short string) (string, bool) {
+	best, bestDist := "", 3
+	for _, k := range s.known {
+		if strings.HasSuffix(k, ".") {
+			continue
+		}
+		if d := editDistance(short, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// ingressClassIndex maps IngressClass names to their controller.
+type ingressClassIndex struct {
+	controllers  map[string]string
+	defaultClass string
+	// listed is false when the IngressClasses could not be listed, so a
+	// missing class proves nothing.
+	listed bool
+}
+
+func newIngressClassIndex(classes []unstructured.Unstructured) ingressClassIndex {
+	idx := ingressClassIndex{controllers: make(map[string]string, len(classes)), listed: true}
+	for _, c := range classes {
+		controller, _, _ := unstructured.NestedString(c.Object, "spec", "controller")
+		idx.controllers[c.GetName()] = controller
+		if c.GetAnnotations()["ingressclass.kubernetes.io/is-default-class"] == "true" {
+			idx.defaultClass = c.GetName()
+		}
+	}
+	return idx
+}
+
+// ingressClassIndex lists the IngressClasses of the cluster.
+func (b *BaseTool) ingressClassIndex(ctx context.Context) ingressClassIndex {
+	list, err := b.listResource(ctx, ingressClassesGVR, "")
+	if err != nil {
+		return ingressClassIndex{}
+	}
+	return newIngressClassIndex(list.Items)
+}
+
+// controllerOf returns the IngressClass of ing, the controller serving it
+// (empty when unknown) and the findings about the class itself.
+func (idx ingressClassIndex) controllerOf(ing unstructured.Unstructured) (string, string, []types.DiagnosticFinding) {
+	ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+	name := ing.GetNamespace() + "/" + ing.GetName()
+	class, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+	legacy := ing.GetAnnotations()[legacyIngressClassAnnotation]
+
+	var findings []types.DiagnosticFinding
+	if legacy != "" {
+		suggestion := fmt.Sprintf("Set spec.ingressClassName: %s and remove the annotation.", legacy)
+		if class != "" {
+			suggestion = "Remove the annotation; spec.ingressClassName already selects the class."
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeIngressDeprecatedAnnotation,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Ingress %s uses the deprecated %s annotation", name, legacyIngressClassAnnotation),
+			Suggestion: suggestion,
+		})
+	}
+
+	switch {
+	case class != "":
+		controller, ok := idx.controllers[class]
+		if !ok && idx.listed {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeIngressClassNotFound,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Ingress %s uses IngressClass %s, which does not exist: no controller serves it", name, class),
+				Suggestion: "Fix spec.ingressClassName, or create the IngressClass of the controller that should serve it.",
+			})
+		}
+		return class, controller, findings
+	case legacy != "":
+		// Controllers answer to their legacy class name without an
+		// IngressClass.
+		if controller, ok := idx.controllers[legacy]; ok {
+			return legacy, controller, findings
+		}
+		for controller, set := range ingressAnnotationSets {
+			if set.legacyClass == legacy {
+				return legacy, controller, findings
+			}
+		}
+		return legacy, "", findings
+	default:
+		return idx.defaultClass, idx.controllers[idx.defaultClass], findings
+	}
+}
+
+// ingressAnnotationFindings validates the annotations of ing against what
+// its controller reads: unknown annotations under the controller's prefix,
+// deprecated spellings, annotations of other controllers, and conflicting
+// combinations.
+func ingressAnnotationFindings(ing unstructured.Unstructured, controller string) []types.DiagnosticFinding {
+	set, ok := ingressAnnotationSets[controller]
+	if !ok {
+		return nil
+	}
+	ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+	name := ing.GetNamespace() + "/" + ing.GetName()
+	annotations := ing.GetAnnotations()
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, summary, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       code,
+			Resource:   ref,
+			Summary:    summary,
+			Suggestion: suggestion,
+		})
+	}
+	for _, k := range keys {
+		if short, ok := strings.CutPrefix(k, set.prefix); ok {
+			short = traefikIndexedKey.ReplaceAllString(short, ".")
+			if instead, ok := set.deprecated[short]; ok {
+				add(types.CodeIngressDeprecatedAnnotation, fmt.Sprintf("Ingress %s uses %s, which %s no longer reads", name, k, set.name), instead)
+			} else if !set.knows(short) {
+				suggestion := fmt.Sprintf("%s ignores the annotation; check its spelling against the %s documentation.", set.name, set.name)
+				if known, ok := set.closest(short); ok {
+					suggestion = fmt.Sprintf("Did you mean %s%s? %s ignores the annotation as written.", set.prefix, known, set.name)
+				}
+				add(types.CodeIngressUnknownAnnotation, fmt.Sprintf("Ingress %s annotation %s is not a %s annotation", name, k, set.name), suggestion)
+			}
+			continue
+		}
+		for prefix, instead := range set.legacyPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				add(types.CodeIngressDeprecatedAnnotation, fmt.Sprintf("Ingress %s uses %s, a prefix %s no longer reads", name, k, set.name), instead)
+			}
+		}
+		for other, otherSet := range ingressAnnotationSets {
+			if other != controller && strings.HasPrefix(k, otherSet.prefix) {
+				add(types.CodeIngressForeignAnnotation,
+					fmt.Sprintf("Ingress %s annotation %s is read by %s, but the Ingress is served by %s", name, k, otherSet.name, set.name),
+					fmt.Sprintf("%s ignores it; translate it to the %s equivalent, or change the IngressClass.", set.name, set.name))
+			}
+		}
+	}
+	if set.conflicts != nil {
+		for _, summary := range set.conflicts(ing, annotations) {
+			add(types.CodeIngressConflictingAnnotations, fmt.Sprintf("Ingress %s %s", name, summary),
+				"Keep one of the annotations so the Ingress behaves as written.")
+		}
+	}
+	return findings
+}
+
+// nginxAnnotationConflicts reports ingress-nginx annotations that cancel
+// each other out.
+func nginxAnnotationConflicts(_ unstructured.Unstructured, annotations map[string]string) []string {
+	get := func(short string) string { return strings.TrimSpace(annotations[nginxAnnotationPrefix+short]) }
+	var out []string
+	for _, redirect := range []string{"permanent-redirect", "temporal-redirect"} {
+		if get(redirect) == "" {
+			continue
+		}
+		if get("rewrite-target") != "" {
+			out = append(out, fmt.Sprintf("sets both %s and rewrite-target: requests are redirected before any rewrite", redirect))
+		}
+		if get("app-root") != "" {
+			out = append(out, fmt.Sprintf("sets both %s and app-root: the redirect applies to every path, including /", redirect))
+		}
+	}
+	if get("permanent-redirect") != "" && get("temporal-redirect") != "" {
+		out = append(out, "sets both permanent-redirect and temporal-redirect: only one redirect is returned")
+	}
+	if get("app-root") != "" && get("rewrite-target") != "" {
+		out = append(out, "sets both app-root and rewrite-target: the app-root redirect and the rewrite both act on /")
+	}
+	if get("ssl-redirect") == "false" && get("force-ssl-redirect") == "true" {
+		out = append(out, "sets ssl-redirect=false with force-ssl-redirect=true: HTTP is still redirected to HTTPS")
+	}
+	if get("from-to-www-redirect") == "true" && (get("permanent-redirect") != "" || get("temporal-redirect") != "") {
+		out = append(out, "sets from-to-www-redirect with another redirect: only one redirect is returned")
+	}
+	return out
+}
+
+// albAnnotationConflicts reports AWS Load Balancer Controller annotations
+// that cannot apply together.
+func albAnnotationConflicts(_ unstructured.Unstructured, annotations map[string]string) []string {
+	const prefix = "alb.ingress.kubernetes.io/"
+	redirect, ports := annotations[prefix+"ssl-redirect"], annotations[prefix+"listen-ports"]
+	if redirect != "" && ports != "" && !strings.Contains(strings.ToUpper(ports), "HTTPS") {
+		return []string{fmt.Sprintf("sets ssl-redirect=%s but listen-ports %s has no HTTPS listener to redirect to", redirect, ports)}
+	}
+	return nil
+}
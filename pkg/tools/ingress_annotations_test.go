@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func annotatedIngress(name, class string, annotations map[string]string) unstructured.Unstructured {
+	spec := map[string]interface{}{}
+	if class != "" {
+		spec["ingressClassName"] = class
+	}
+	ing := ipamObj("networking.k8s.io/v1", "Ingress", "shop", name, map[string]interface{}{"spec": spec})
+	ing.SetAnnotations(annotations)
+	return *ing
+}
+
+func TestIngressControllerOf(t *testing.T) {
+	class := func(name, controller string, isDefault bool) unstructured.Unstructured {
+		c := ipamObj("networking.k8s.io/v1", "IngressClass", "", name, map[string]interface{}{
+			"spec": map[string]interface{}{"controller": controller},
+		})
+		if isDefault {
+			c.SetAnnotations(map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"})
+		}
+		return *c
+	}
+	idx := newIngressClassIndex([]unstructured.Unstructured{
+		class("nginx", ingressNginxController, true),
+		class("public", "traefik.io/ingress-controller", false),
+	})
+
+	for _, tc := range []struct {
+		ing               unstructured.Unstructured
+		class, controller string
+		want              string
+	}{
+		{annotatedIngress("a", "public", nil), "public", "traefik.io/ingress-controller", ""},
+		{annotatedIngress("b", "", nil), "nginx", ingressNginxController, ""},
+		{annotatedIngress("c", "", map[string]string{legacyIngressClassAnnotation: "alb"}), "alb", "ingress.k8s.aws/alb",
+			"warning Ingress shop/c uses the deprecated kubernetes.io/ingress.class annotation ING002_DEPRECATED_ANNOTATION"},
+		{annotatedIngress("d", "internal", nil), "internal", "",
+			"warning Ingress shop/d uses IngressClass internal, which does not exist: no controller serves it ING005_CLASS_NOT_FOUND"},
+	} {
+		class, controller, findings := idx.controllerOf(tc.ing)
+		if class != tc.class || controller != tc.controller || ciliumCodes(findings) != tc.want {
+			t.Errorf("%s: got %q, %q, %q; want %q, %q, %q", tc.ing.GetName(), class, controller, ciliumCodes(findings), tc.class, tc.controller, tc.want)
+		}
+	}
+
+	// Without the IngressClasses, a missing class is not reported.
+	if _, _, findings := (ingressClassIndex{}).controllerOf(annotatedIngress("d", "internal", nil)); len(findings) != 0 {
+		t.Errorf("unlisted classes reported: %s", ciliumCodes(findings))
+	}
+}
+
+func TestIngressAnnotationFindings(t *testing.T) {
+	ing := annotatedIngress("web", "nginx", map[string]string{
+		nginxAnnotationPrefix + "rewrite-taget":      "/$2",
+		nginxAnnotationPrefix + "secure-backends":    "true",
+		nginxAnnotationPrefix + "permanent-redirect": "https://example.com",
+		nginxAnnotationPrefix + "rewrite-target":     "/",
+		nginxAnnotationPrefix + "proxy-body-size":    "8m",
+		"ingress.kubernetes.io/ssl-redirect":         "true",
+		"traefik.ingress.kubernetes.io/router.tls":   "true",
+		"meta.helm.sh/release-name":                  "web",
+	})
+	got := ciliumCodes(ingressAnnotationFindings(ing, ingressNginxController))
+	for _, want := range []string{
+		"warning Ingress shop/web annotation nginx.ingress.kubernetes.io/rewrite-taget is not a ingress-nginx annotation ING001_UNKNOWN_ANNOTATION",
+		"warning Ingress shop/web uses nginx.ingress.kubernetes.io/secure-backends, which ingress-nginx no longer reads ING002_DEPRECATED_ANNOTATION",
+		"warning Ingress shop/web uses ingress.kubernetes.io/ssl-redirect, a prefix ingress-nginx no longer reads ING002_DEPRECATED_ANNOTATION",
+		"warning Ingress shop/web annotation traefik.ingress.kubernetes.io/router.tls is read by Traefik, but the Ingress is served by ingress-nginx ING004_FOREIGN_ANNOTATION",
+		"warning Ingress shop/web sets both permanent-redirect and rewrite-target: requests are redirected before any rewrite ING003_CONFLICTING_ANNOTATIONS",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "proxy-body-size") || strings.Contains(got, "helm.sh") {
+		t.Errorf("valid annotations reported:\n%s", got)
+	}
+	for _, f := range ingressAnnotationFindings(ing, ingressNginxController) {
+		if strings.Contains(f.Summary, "rewrite-taget") && !strings.Contains(f.Suggestion, "Did you mean nginx.ingress.kubernetes.io/rewrite-target?") {
+			t.Errorf("no spelling suggestion: %q", f.Suggestion)
+		}
+	}
+
+	traefik := annotatedIngress("api", "public", map[string]string{
+		"traefik.ingress.kubernetes.io/router.tls.domains.0.main": "example.com",
+		"traefik.ingress.kubernetes.io/frontend-entry-points":     "https",
+	})
+	if got := ciliumCodes(ingressAnnotationFindings(traefik, "traefik.io/ingress-controller")); got != "warning Ingress shop/api uses traefik.ingress.kubernetes.io/frontend-entry-points, which Traefik no longer reads ING002_DEPRECATED_ANNOTATION" {
+		t.Errorf("traefik findings:\n%s", got)
+	}
+
+	alb := annotatedIngress("lb", "alb", map[string]string{
+		"alb.ingress.kubernetes.io/ssl-redirect":         "443",
+		"alb.ingress.kubernetes.io/listen-ports":         `[{"HTTP": 80}]`,
+		"alb.ingress.kubernetes.io/actions.response-503": `{"type":"fixed-response"}`,
+	})
+	if got := ciliumCodes(ingressAnnotationFindings(alb, "ingress.k8s.aws/alb")); !strings.Contains(got, "has no HTTPS listener to redirect to ING003_CONFLICTING_ANNOTATIONS") || strings.Contains(got, "ING001") {
+		t.Errorf("alb findings:\n%s", got)
+	}
+
+	if findings := ingressAnnotationFindings(ing, "example.com/unknown"); findings != nil {
+		t.Errorf("unknown controller validated: %s", ciliumCodes(findings))
+	}
+}
@@ -0,0 +1,724 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var ingressClassesGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingressclasses"}
+
+const (
+	ingressNginxController = "k8s.io/ingress-nginx"
+	ingressNginxNamespace  = "ingress-nginx"
+	// ingressNginxSelector matches the controller pods of the upstream helm
+	// chart and static manifests.
+	ingressNginxSelector  = "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller"
+	nginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+)
+
+// nginxConfigTypes are the value types of the controller ConfigMap keys that
+// are validated; other keys are passed through unchecked.
+var nginxConfigTypes = map[string]string{
+	"allow-snippet-annotations":      "bool",
+	"use-forwarded-headers":          "bool",
+	"compute-full-forwarded-for":     "bool",
+	"enable-real-ip":                 "bool",
+	"use-proxy-protocol":             "bool",
+	"use-http2":                      "bool",
+	"ssl-redirect":                   "bool",
+	"force-ssl-redirect":             "bool",
+	"hsts":                           "bool",
+	"enable-opentelemetry":           "bool",
+	"proxy-connect-timeout":          "int",
+	"proxy-read-timeout":             "int",
+	"proxy-send-timeout":             "int",
+	"keep-alive":                     "int",
+	"keep-alive-requests":            "int",
+	"upstream-keepalive-connections": "int",
+	"max-worker-connections":         "int",
+	"worker-processes":               "workers",
+	"proxy-body-size":                "size",
+	"client-body-buffer-size":        "size",
+	"proxy-buffer-size":              "size",
+	"annotations-risk-level":         "risk",
+}
+
+// nginxAnnotationTypes are the value types of the Ingress annotations that
+// are validated, without the nginx.ingress.kubernetes.io/ prefix.
+var nginxAnnotationTypes = map[string]string{
+	"ssl-redirect":          "bool",
+	"force-ssl-redirect":    "bool",
+	"use-regex":             "bool",
+	"enable-cors":           "bool",
+	"ssl-passthrough":       "bool",
+	"proxy-connect-timeout": "int",
+	"proxy-read-timeout":    "int",
+	"proxy-send-timeout":    "int",
+	"limit-rps":             "int",
+	"limit-connections":     "int",
+	"proxy-body-size":       "size",
+	"backend-protocol":      "protocol",
+	"auth-url":              "url",
+	"auth-signin":           "url",
+}
+
+// nginxServerAnnotations configure the server block shared by every Ingress
+// of a host, so only one Ingress can set them.
+var nginxServerAnnotations = []string{"server-snippet", "server-alias", "ssl-passthrough", "auth-tls-secret", "auth-tls-verify-client", "ssl-ciphers"}
+
+var (
+	nginxSizeValue  = regexp.MustCompile(`^[0-9]+[kKmMgG]?$`)
+	nginxCaptureRef = regexp.MustCompile(`\$([1-9])`)
+	// nginxErrorLine matches nginx error log levels and klog error lines of
+	// the controller.
+	nginxErrorLine = regexp.MustCompile(`\[(error|crit|alert|emerg)\]|^E\d{4} `)
+)
+
+// validNginxValue reports whether v is a valid value of the given type.
+func validNginxValue(kind, v string) bool {
+	switch kind {
+	case "bool":
+		_, err := strconv.ParseBool(v)
+		return err == nil
+	case "int":
+		_, err := strconv.Atoi(v)
+		return err == nil
+	case "workers":
+		_, err := strconv.Atoi(v)
+		return v == "auto" || err == nil
+	case "size":
+		return nginxSizeValue.MatchString(v)
+	case "risk":
+		return v == "Critical" || v == "High" || v == "Medium" || v == "Low"
+	case "protocol":
+		switch strings.ToUpper(v) {
+		case "HTTP", "HTTPS", "GRPC", "GRPCS", "AUTO_HTTP", "FCGI":
+			return true
+		}
+		return false
+	case "url":
+		u, err := url.Parse(v)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	}
+	return true
+}
+
+// captureGroups counts the capturing groups of a regex path: unescaped
+// parentheses not followed by ?.
+func captureGroups(path string) int {
+	n := 0
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\':
+			i++
+		case path[i] == '(' && (i+1 == len(path) || path[i+1] != '?'):
+			n++
+		}
+	}
+	return n
+}
+
+// nginxController is an ingress-nginx controller pod and the flags that
+// change how Ingresses are read.
+type nginxController struct {
+	namespace, name   string
+	container         string
+	ready             bool
+	configMap         string
+	sslPassthrough    bool
+	watchWithoutClass bool
+}
+
+// newNginxController reads the controller flags from the pod spec. The
+// upstream manifests pass --configmap=$(POD_NAMESPACE)/<name>.
+func newNginxController(pod unstructured.Unstructured) nginxController {
+	c := nginxController{namespace: pod.GetNamespace(), name: pod.GetName(), ready: true}
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	for _, ctr := range containers {
+		cm, ok := ctr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		args, _, _ := unstructured.NestedStringSlice(cm, "args")
+		isController := false
+		for _, arg := range args {
+			arg = strings.ReplaceAll(arg, "$(POD_NAMESPACE)", pod.GetNamespace())
+			switch {
+			case strings.HasPrefix(arg, "--configmap="):
+				c.configMap = strings.TrimPrefix(arg, "--configmap=")
+				isController = true
+			case arg == "--enable-ssl-passthrough" || arg == "--enable-ssl-passthrough=true":
+				c.sslPassthrough = true
+			case arg == "--watch-ingress-without-class" || arg == "--watch-ingress-without-class=true":
+				c.watchWithoutClass = true
+			case strings.HasPrefix(arg, "--controller-class=") || strings.HasPrefix(arg, "--ingress-class="):
+				isController = true
+			}
+		}
+		if isController || c.container == "" {
+			c.container, _ = cm["name"].(string)
+		}
+	}
+	statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+	if len(statuses) == 0 {
+		c.ready = false
+	}
+	for _, cs := range statuses {
+		if csMap, ok := cs.(map[string]interface{}); ok {
+			if ready, _, _ := unstructured.NestedBool(csMap, "ready"); !ready {
+				c.ready = false
+			}
+		}
+	}
+	return c
+}
+
+// nginxClasses returns the names of the IngressClasses served by
+// ingress-nginx and whether one of them is the cluster default.
+func nginxClasses(classes []unstructured.Unstructured) (map[string]bool, bool) {
+	names := make(map[string]bool)
+	isDefault := false
+	for _, c := range classes {
+		if controller, _, _ := unstructured.NestedString(c.Object, "spec", "controller"); controller != ingressNginxController {
+			continue
+		}
+		names[c.GetName()] = true
+		if c.GetAnnotations()["ingressclass.kubernetes.io/is-default-class"] == "true" {
+			isDefault = true
+		}
+	}
+	return names, isDefault
+}
+
+// servedByNginx reports whether ingress-nginx serves ing: through
+// spec.ingressClassName, the legacy kubernetes.io/ingress.class annotation,
+// or, for Ingresses without a class, a default nginx IngressClass or
+// --watch-ingress-without-class.
+func servedByNginx(ing unstructured.Unstructured, classes map[string]bool, classless bool) bool {
+	class, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+	if class == "" {
+		class = ing.GetAnnotations()["kubernetes.io/ingress.class"]
+	}
+	if class == "" {
+		return classless
+	}
+	return classes[class]
+}
+
+// nginxConfigFindings validates the controller ConfigMap settings.
+func nginxConfigFindings(cm *unstructured.Unstructured) []types.DiagnosticFinding {
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: cm.GetNamespace(), Name: cm.GetName(), APIVersion: "v1"}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	settings := make([]string, 0, len(keys))
+	var findings []types.DiagnosticFinding
+	for _, k := range keys {
+		v := strings.TrimSpace(data[k])
+		settings = append(settings, k+"="+v)
+		if kind, ok := nginxConfigTypes[k]; ok && !validNginxValue(kind, v) {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeNginxInvalidConfig,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("ingress-nginx setting %s=%q is not a valid %s", k, v, kind),
+				Suggestion: "The controller logs a decode error and keeps its default for this setting; fix the value in the ConfigMap.",
+			})
+		}
+	}
+	if allowed, err := strconv.ParseBool(data["allow-snippet-annotations"]); err == nil && allowed {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeNginxSnippetsAllowed,
+			Resource:   ref,
+			Summary:    "ingress-nginx allows configuration snippet annotations",
+			Detail:     "any user who can create an Ingress can inject nginx configuration and read the Secrets the controller can access (CVE-2021-25742, CVE-2025-1974)",
+			Suggestion: "Set allow-snippet-annotations to false unless snippets are required, and keep annotations-risk-level at its default.",
+		})
+	}
+
+	findings = append([]types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Resource: ref,
+		Summary:  fmt.Sprintf("ingress-nginx ConfigMap %s/%s: %d setting(s)", cm.GetNamespace(), cm.GetName(), len(keys)),
+		Detail:   strings.Join(settings, "\n"),
+	}}, findings...)
+	return findings
+}
+
+// ingressPaths returns the host and path of every rule of ing.
+func ingressPaths(ing unstructured.Unstructured) [][2]string {
+	var out [][2]string
+	rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		host, _ := rm["host"].(string)
+		paths, _, _ := unstructured.NestedSlice(rm, "http", "paths")
+		for _, p := range paths {
+			if pm, ok := p.(map[string]interface{}); ok {
+				path, _ := pm["path"].(string)
+				out = append(out, [2]string{host, orDefault(path, "/")})
+			}
+		}
+	}
+	return out
+}
+
+// nginxAnnotationFindings validates the nginx annotations of an Ingress:
+// value types, rewrite-target capture groups, ssl-redirect without TLS,
+// ssl-passthrough without the controller flag and snippets the controller
+// does not allow.
+func nginxAnnotationFindings(ing unstructured.Unstructured, snippetsAllowed, sslPassthrough bool) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+	name := ing.GetNamespace() + "/" + ing.GetName()
+	annotations := ing.GetAnnotations()
+
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		if strings.HasPrefix(k, nginxAnnotationPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var findings []types.DiagnosticFinding
+	invalid := func(summary, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeNginxInvalidAnnotation,
+			Resource:   ref,
+			Summary:    summary,
+			Suggestion: suggestion,
+		})
+	}
+	for _, k := range keys {
+		short := strings.TrimPrefix(k, nginxAnnotationPrefix)
+		v := strings.TrimSpace(annotations[k])
+		if kind, ok := nginxAnnotationTypes[short]; ok && !validNginxValue(kind, v) {
+			invalid(fmt.Sprintf("Ingress %s annotation %s=%q is not a valid %s", name, short, v, kind),
+				"The controller ignores the annotation or rejects the Ingress; fix the value.")
+		}
+		if strings.HasSuffix(short, "-snippet") && !snippetsAllowed {
+			invalid(fmt.Sprintf("Ingress %s uses %s but the controller does not allow snippet annotations", name, short),
+				"The admission webhook rejects the Ingress, or the controller ignores the snippet; use a dedicated annotation or enable allow-snippet-annotations.")
+		}
+	}
+
+	if rewrite := annotations[nginxAnnotationPrefix+"rewrite-target"]; rewrite != "" {
+		highest := 0
+		for _, m := range nginxCaptureRef.FindAllStringSubmatch(rewrite, -1) {
+			if n, _ := strconv.Atoi(m[1]); n > highest {
+				highest = n
+			}
+		}
+		for _, hp := range ingressPaths(ing) {
+			if groups := captureGroups(hp[1]); groups < highest {
+				invalid(fmt.Sprintf("Ingress %s rewrite-target %q uses $%d but path %s has %d capture group(s)", name, rewrite, highest, hp[1], groups),
+					"nginx substitutes an empty string for the missing group; add the capture group to the path, e.g. /api(/|$)(.*).")
+			}
+		}
+	}
+
+	tls, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
+	if annotations[nginxAnnotationPrefix+"ssl-redirect"] == "true" && len(tls) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Ingress %s sets ssl-redirect but has no TLS section, so no redirect happens", name),
+			Suggestion: "Use force-ssl-redirect when TLS terminates before the controller, or add a tls section.",
+		})
+	}
+	if annotations[nginxAnnotationPrefix+"ssl-passthrough"] == "true" && !sslPassthrough {
+		invalid(fmt.Sprintf("Ingress %s sets ssl-passthrough but the controller runs without --enable-ssl-passthrough", name),
+			"Start the controller with --enable-ssl-passthrough (controller.extraArgs.enable-ssl-passthrough in the helm chart); until then TLS is terminated by nginx.")
+	}
+	return findings
+}
+
+// nginxConflictFindings reports Ingresses that nginx merges into the same
+// server block in conflicting ways: the same host and path defined twice,
+// server-level annotations set differently, and different TLS Secrets for
+// one host. ingress-nginx processes Ingresses oldest first, so the oldest
+// definition wins.
+func nginxConflictFindings(ingresses []unstructured.Unstructured) []types.DiagnosticFinding {
+	sorted := append([]unstructured.Unstructured(nil), ingresses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetCreationTimestamp().Time.Before(sorted[j].GetCreationTimestamp().Time)
+	})
+
+	locations := make(map[[2]string][]string)
+	var locationKeys [][2]string
+	serverValues := make(map[string]map[string][]string)
+	secrets := make(map[string]map[string][]string)
+	for _, ing := range sorted {
+		name := ing.GetNamespace() + "/" + ing.GetName()
+		hosts := make(map[string]bool)
+		for _, hp := range ingressPaths(ing) {
+			if len(locations[hp]) == 0 {
+				locationKeys = append(locationKeys, hp)
+			}
+			if n := len(locations[hp]); n == 0 || locations[hp][n-1] != name {
+				locations[hp] = append(locations[hp], name)
+			}
+			hosts[hp[0]] = true
+		}
+		for host := range hosts {
+			for _, a := range nginxServerAnnotations {
+				v, ok := ing.GetAnnotations()[nginxAnnotationPrefix+a]
+				if !ok {
+					continue
+				}
+				key := host + "\x00" + a
+				if serverValues[key] == nil {
+					serverValues[key] = make(map[string][]string)
+				}
+				serverValues[key][v] = append(serverValues[key][v], name)
+			}
+		}
+		tls, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
+		for _, entry := range tls {
+			em, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			secret, _ := em["secretName"].(string)
+			tlsHosts, _, _ := unstructured.NestedStringSlice(em, "hosts")
+			for _, h := range tlsHosts {
+				if secrets[h] == nil {
+					secrets[h] = make(map[string][]string)
+				}
+				secrets[h][ing.GetNamespace()+"/"+secret] = append(secrets[h][ing.GetNamespace()+"/"+secret], name)
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, hp := range locationKeys {
+		owners := locations[hp]
+		if len(owners) < 2 {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeNginxServerBlockConflict,
+			Summary:    fmt.Sprintf("Host %s path %s is defined by %d Ingresses: %s", orDefault(hp[0], "*"), hp[1], len(owners), strings.Join(owners, ", ")),
+			Detail:     fmt.Sprintf("nginx keeps the location of the oldest Ingress, %s; the others are ignored for this path", owners[0]),
+			Suggestion: "Remove the duplicate path or give it a different host so each location has one owner.",
+		})
+	}
+
+	conflictKeys := make([]string, 0)
+	for key, values := range serverValues {
+		if len(values) > 1 {
+			conflictKeys = append(conflictKeys, key)
+		}
+	}
+	sort.Strings(conflictKeys)
+	for _, key := range conflictKeys {
+		parts := strings.SplitN(key, "\x00", 2)
+		var detail []string
+		for v, owners := range serverValues[key] {
+			detail = append(detail, fmt.Sprintf("%q set by %s", v, strings.Join(owners, ", ")))
+		}
+		sort.Strings(detail)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeNginxServerBlockConflict,
+			Summary:    fmt.Sprintf("Ingresses for host %s set the server-level annotation %s differently", orDefault(parts[0], "*"), parts[1]),
+			Detail:     strings.Join(detail, "; "),
+			Suggestion: "Server-level annotations apply to the whole host and are taken from one Ingress; set them on a single Ingress per host.",
+		})
+	}
+
+	hosts := make([]string, 0, len(secrets))
+	for h, s := range secrets {
+		if len(s) > 1 {
+			hosts = append(hosts, h)
+		}
+	}
+	sort.Strings(hosts)
+	for _, h := range hosts {
+		names := make([]string, 0, len(secrets[h]))
+		for s := range secrets[h] {
+			names = append(names, s)
+		}
+		sort.Strings(names)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryTLS,
+			Code:       types.CodeNginxServerBlockConflict,
+			Summary:    fmt.Sprintf("Host %s has %d different TLS Secrets: %s", h, len(names), strings.Join(names, ", ")),
+			Suggestion: "nginx serves one certificate per host; reference the same Secret from every Ingress of the host.",
+		})
+	}
+	return findings
+}
+
+// nginxControllers returns the ingress-nginx controller pods.
+func (b *BaseTool) nginxControllers(ctx context.Context) ([]nginxController, error) {
+	pods, err := b.listResourceSelected(ctx, podsGVR, "", listSelector{Label: ingressNginxSelector})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]nginxController, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		out = append(out, newNginxController(p))
+	}
+	return out, nil
+}
+
+// nginxConfigMap returns the ConfigMap named by the controller, or nil.
+func (b *BaseTool) nginxConfigMap(ctx context.Context, controllers []nginxController) *unstructured.Unstructured {
+	for _, c := range controllers {
+		ns, name, ok := strings.Cut(c.configMap, "/")
+		if !ok {
+			continue
+		}
+		if cm, err := b.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			return cm
+		}
+	}
+	return nil
+}
+
+// --- get_ingress_nginx_config ---
+
+type GetIngressNginxConfigTool struct{ BaseTool }
+
+func (t *GetIngressNginxConfigTool) Name() string { return "get_ingress_nginx_config" }
+func (t *GetIngressNginxConfigTool) Description() string {
+	return "Read the ingress-nginx controller ConfigMap (found from the --configmap flag of the controller), list its settings and flag invalid values and risky options such as allow-snippet-annotations"
+}
+func (t *GetIngressNginxConfigTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the ConfigMap (default: from the controller flags, else ingress-nginx)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "ConfigMap name (default: from the controller flags, else ingress-nginx-controller)",
+			},
+		},
+	}
+}
+
+func (t *GetIngressNginxConfigTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+
+	var cm *unstructured.Unstructured
+	if ns == "" && name == "" {
+		controllers, _ := t.nginxControllers(ctx)
+		cm = t.nginxConfigMap(ctx, controllers)
+	}
+	if cm == nil {
+		ns = orDefault(ns, ingressNginxNamespace)
+		name = orDefault(name, "ingress-nginx-controller")
+		var err error
+		cm, err = t.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeProviderNotFound,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("ingress-nginx ConfigMap %s/%s not found", ns, name),
+				Detail:  err.Error(),
+			}
+		}
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), nginxConfigFindings(cm), cm.GetNamespace(), "ingress-nginx"), nil
+}
+
+// --- check_ingress_nginx ---
+
+type CheckIngressNginxTool struct{ BaseTool }
+
+func (t *CheckIngressNginxTool) Name() string { return "check_ingress_nginx" }
+func (t *CheckIngressNginxTool) Description() string {
+	return "Check ingress-nginx: controller readiness, invalid nginx annotations (rewrite-target capture groups, ssl-redirect, auth-url, snippets, ssl-passthrough), Ingresses that conflict in the same server block, and optionally error lines from the controller logs"
+}
+func (t *CheckIngressNginxTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only validate the annotations of Ingresses in this namespace (conflicts are always checked cluster-wide)",
+			},
+			"include_logs": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also scan the controller logs for nginx and controller errors (default false)",
+			},
+			"tail": map[string]interface{}{
+				"type":        "integer",
+				"description": "Log lines to scan per controller pod (default 500)",
+			},
+			"since": map[string]interface{}{
+				"type":        "string",
+				"description": "Duration of logs to scan (e.g., 5m, 1h)",
+			},
+		},
+	}
+}
+
+func (t *CheckIngressNginxTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	includeLogs, _ := args["include_logs"].(bool)
+
+	controllers, err := t.nginxControllers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingress-nginx controller pods: %w", err)
+	}
+	var findings []types.DiagnosticFinding
+	if len(controllers) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeNginxControllerNotReady,
+			Summary:    "No ingress-nginx controller pod found",
+			Detail:     "no pod matches " + ingressNginxSelector,
+			Suggestion: "Ingresses of the nginx class are not served; check the controller Deployment.",
+		})
+	}
+	ready := 0
+	sslPassthrough, watchWithoutClass := false, false
+	for _, c := range controllers {
+		sslPassthrough = sslPassthrough || c.sslPassthrough
+		watchWithoutClass = watchWithoutClass || c.watchWithoutClass
+		if c.ready {
+			ready++
+		}
+	}
+	if len(controllers) > 0 {
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("ingress-nginx controller: %d/%d pods ready", ready, len(controllers)),
+		}
+		if ready < len(controllers) {
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeNginxControllerNotReady
+			f.Suggestion = "Run check_ingress_nginx with include_logs=true or get_infra_logs with component=ingress-nginx to see why."
+		}
+		if ready == 0 {
+			f.Severity = types.SeverityCritical
+		}
+		findings = append(findings, f)
+	}
+
+	snippetsAllowed := false
+	if cm := t.nginxConfigMap(ctx, controllers); cm != nil {
+		allowed, _, _ := unstructured.NestedString(cm.Object, "data", "allow-snippet-annotations")
+		snippetsAllowed, _ = strconv.ParseBool(allowed)
+		for _, f := range nginxConfigFindings(cm) {
+			if f.Code != "" {
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	var classList []unstructured.Unstructured
+	if list, err := t.listResource(ctx, ingressClassesGVR, ""); err == nil {
+		classList = list.Items
+	}
+	classes, defaultClass := nginxClasses(classList)
+	ingresses, err := t.listResource(ctx, ingressGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	var served []unstructured.Unstructured
+	for _, ing := range ingresses.Items {
+		if !servedByNginx(ing, classes, defaultClass || watchWithoutClass) {
+			continue
+		}
+		served = append(served, ing)
+		if ns == "" || ing.GetNamespace() == ns {
+			findings = append(findings, nginxAnnotationFindings(ing, snippetsAllowed, sslPassthrough)...)
+		}
+	}
+	findings = append(findings, nginxConflictFindings(served)...)
+
+	if includeLogs {
+		findings = append(findings, t.logFindings(ctx, controllers, getIntArg(args, "tail", 500), getStringArg(args, "since", ""))...)
+	}
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Summary:  fmt.Sprintf("%d Ingress(es) served by ingress-nginx across %d IngressClass(es)", len(served), len(classes)),
+	})
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "ingress-nginx"), nil
+}
+
+// logFindings reports the nginx and controller error lines of each
+// controller pod, fetched with the same log helper as get_infra_logs.
+func (t *CheckIngressNginxTool) logFindings(ctx context.Context, controllers []nginxController, tail int, since string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, c := range controllers {
+		ref := &types.ResourceRef{Kind: "Pod", Namespace: c.namespace, Name: c.name}
+		lr, err := getPodLogs(ctx, t.Clients, c.namespace, c.name, c.container, int64(tail), since)
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryLogs,
+				Resource: ref,
+				Summary:  fmt.Sprintf("Could not read the logs of %s/%s", c.namespace, c.name),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		var errorLines []string
+		scanner := bufio.NewScanner(strings.NewReader(lr.logs))
+		for scanner.Scan() {
+			if line := scanner.Text(); nginxErrorLine.MatchString(line) {
+				errorLines = append(errorLines, line)
+			}
+		}
+		if len(errorLines) == 0 {
+			continue
+		}
+		total := len(errorLines)
+		if total > maxErrorLines {
+			errorLines = errorLines[total-maxErrorLines:]
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsErrorsFound,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d error line(s) in the last %d log lines of %s/%s", total, lr.returnedLines, c.namespace, c.name),
+			Detail:     strings.Join(errorLines, "\n"),
+			Suggestion: fmt.Sprintf("Run analyze_log_errors with pod=%s namespace=%s to categorize them.", c.name, c.namespace),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestCaptureGroups(t *testing.T) {
+	cases := map[string]int{
+		"/":                      0,
+		"/api(/|$)(.*)":          2,
+		"/app(?:/v1)?/(.*)":      1,
+		`/literal\(paren\)/(.*)`: 1,
+	}
+	for path, want := range cases {
+		if got := captureGroups(path); got != want {
+			t.Errorf("captureGroups(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
+
+// nginxIngress builds an Ingress with one path per entry of paths on host.
+func nginxIngress(name, host string, created time.Time, annotations map[string]string, paths ...string) *unstructured.Unstructured {
+	var httpPaths []interface{}
+	for _, p := range paths {
+		httpPaths = append(httpPaths, map[string]interface{}{"path": p, "pathType": "ImplementationSpecific"})
+	}
+	ing := ipamObj("networking.k8s.io/v1", "Ingress", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ingressClassName": "nginx",
+			"rules":            []interface{}{map[string]interface{}{"host": host, "http": map[string]interface{}{"paths": httpPaths}}},
+		},
+	})
+	ing.SetAnnotations(annotations)
+	ing.SetCreationTimestamp(metav1.NewTime(created))
+	return ing
+}
+
+func TestNginxAnnotationFindings(t *testing.T) {
+	ing := nginxIngress("api", "shop.example.com", time.Now(), map[string]string{
+		"nginx.ingress.kubernetes.io/rewrite-target":        "/$2",
+		"nginx.ingress.kubernetes.io/auth-url":              "auth-svc/validate",
+		"nginx.ingress.kubernetes.io/ssl-redirect":          "true",
+		"nginx.ingress.kubernetes.io/ssl-passthrough":       "true",
+		"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X: y\";",
+		"nginx.ingress.kubernetes.io/proxy-body-size":       "8m",
+		"nginx.ingress.kubernetes.io/force-ssl-redirect":    "yes",
+	}, "/api(/|$)(.*)", "/v1/(.*)")
+
+	var summaries []string
+	for _, f := range nginxAnnotationFindings(*ing, false, false) {
+		summaries = append(summaries, f.Summary)
+	}
+	joined := strings.Join(summaries, "\n")
+	for _, want := range []string{
+		`auth-url="auth-svc/validate" is not a valid url`,
+		`force-ssl-redirect="yes" is not a valid bool`,
+		"uses configuration-snippet but the controller does not allow snippet annotations",
+		"uses $2 but path /v1/(.*) has 1 capture group(s)",
+		"sets ssl-redirect but has no TLS section",
+		"runs without --enable-ssl-passthrough",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "proxy-body-size") || strings.Contains(joined, "path /api(/|$)(.*)") {
+		t.Errorf("valid annotations reported:\n%s", joined)
+	}
+}
+
+func TestNginxConflictFindings(t *testing.T) {
+	now := time.Now()
+	old := nginxIngress("old", "shop.example.com", now.Add(-time.Hour), map[string]string{"nginx.ingress.kubernetes.io/server-snippet": "a"}, "/", "/cart")
+	old.Object["spec"].(map[string]interface{})["tls"] = []interface{}{map[string]interface{}{"hosts": []interface{}{"shop.example.com"}, "secretName": "shop-tls"}}
+	newer := nginxIngress("new", "shop.example.com", now, map[string]string{"nginx.ingress.kubernetes.io/server-snippet": "b"}, "/cart")
+	newer.Object["spec"].(map[string]interface{})["tls"] = []interface{}{map[string]interface{}{"hosts": []interface{}{"shop.example.com"}, "secretName": "other-tls"}}
+	other := nginxIngress("other", "docs.example.com", now, nil, "/cart")
+
+	findings := nginxConflictFindings([]unstructured.Unstructured{*newer, *other, *old})
+	if len(findings) != 3 {
+		for _, f := range findings {
+			t.Log(f.Summary)
+		}
+		t.Fatalf("expected path, annotation and TLS conflicts, got %d findings", len(findings))
+	}
+	if !strings.Contains(findings[0].Summary, "path /cart is defined by 2 Ingresses: shop/old, shop/new") || !strings.Contains(findings[0].Detail, "oldest Ingress, shop/old") {
+		t.Errorf("unexpected path conflict %+v", findings[0])
+	}
+	if !strings.Contains(findings[1].Summary, "server-snippet") {
+		t.Errorf("unexpected annotation conflict %+v", findings[1])
+	}
+	if !strings.Contains(findings[2].Summary, "shop/other-tls, shop/shop-tls") {
+		t.Errorf("unexpected TLS conflict %+v", findings[2])
+	}
+}
+
+func TestCheckIngressNginx(t *testing.T) {
+	controller := ipamObj("v1", "Pod", ingressNginxNamespace, "ingress-nginx-controller-abc", map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{
+			"name": "controller",
+			"args": []interface{}{"/nginx-ingress-controller", "--configmap=$(POD_NAMESPACE)/ingress-nginx-controller", "--controller-class=k8s.io/ingress-nginx"},
+		}}},
+		"status": map[string]interface{}{"containerStatuses": []interface{}{map[string]interface{}{"name": "controller", "ready": true}}},
+	})
+	controller.SetLabels(map[string]string{"app.kubernetes.io/name": "ingress-nginx", "app.kubernetes.io/component": "controller"})
+	class := ipamObj("networking.k8s.io/v1", "IngressClass", "", "nginx", map[string]interface{}{
+		"spec": map[string]interface{}{"controller": ingressNginxController},
+	})
+	cm := ipamObj("v1", "ConfigMap", ingressNginxNamespace, "ingress-nginx-controller", map[string]interface{}{
+		"data": map[string]interface{}{"allow-snippet-annotations": "true", "proxy-read-timeout": "60s"},
+	})
+	ing := nginxIngress("api", "shop.example.com", time.Now(), map[string]string{"nginx.ingress.kubernetes.io/configuration-snippet": "x"}, "/")
+	traefik := nginxIngress("traefik", "shop.example.com", time.Now(), nil, "/")
+	traefik.Object["spec"].(map[string]interface{})["ingressClassName"] = "traefik"
+
+	listKinds := map[schema.GroupVersionResource]string{
+		podsGVR: "PodList", ingressClassesGVR: "IngressClassList", ingressGVR: "IngressList", configmapsGVR: "ConfigMapList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, controller, class, cm, ing, traefik)
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+
+	resp, err := (&CheckIngressNginxTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[types.FindingCode]int)
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		codes[f.Code]++
+		summaries = append(summaries, f.Summary)
+	}
+	joined := strings.Join(summaries, "\n")
+	if codes[types.CodeNginxInvalidConfig] != 1 || codes[types.CodeNginxSnippetsAllowed] != 1 {
+		t.Errorf("expected the proxy-read-timeout and snippet config findings, got %v", codes)
+	}
+	if codes[types.CodeNginxInvalidAnnotation] != 0 || codes[types.CodeNginxServerBlockConflict] != 0 {
+		t.Errorf("snippets are allowed and the traefik Ingress is not served by nginx, got %v:\n%s", codes, joined)
+	}
+	if !strings.Contains(joined, "1/1 pods ready") || !strings.Contains(joined, "1 Ingress(es) served by ingress-nginx") {
+		t.Errorf("unexpected summaries:\n%s", joined)
+	}
+
+	cfg, err := (&GetIngressNginxConfigTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := cfg.Data.(*types.ToolResult).Findings[0]
+	if !strings.Contains(first.Detail, "proxy-read-timeout=60s") {
+		t.Errorf("expected the settings in the detail, got %q", first.Detail)
+	}
+}
@@ -0,0 +1,643 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	// connectionsMaxLines caps the conntrack entries and sockets each
+	// returned by the probe.
+	connectionsMaxLines = 5000
+	// synSentPileup is the number of connections stuck in SYN_SENT that
+	// is reported as a pileup.
+	synSentPileup = 10
+	// defaultEphemeralPorts is the size of Linux's default
+	// ip_local_port_range, 32768-60999.
+	defaultEphemeralPorts = 28232
+)
+
+// connectionsScript prints the node's conntrack usage and ephemeral port
+// range, then the conntrack entries ("CT|") and TCP sockets ("SS|") whose
+// lines contain one of the newline-separated fixed strings in $1 and $2.
+func connectionsScript() string {
+	return fmt.Sprintf(`echo "CTCOUNT|$(cat /proc/sys/net/netfilter/nf_conntrack_count 2>/dev/null)|$(cat /proc/sys/net/netfilter/nf_conntrack_max 2>/dev/null)"
+echo "PORTRANGE|$(cat /proc/sys/net/ipv4/ip_local_port_range 2>/dev/null)"
+if command -v conntrack >/dev/null 2>&1; then ct="conntrack -L -o extended"; elif [ -r /proc/net/nf_conntrack ]; then ct="cat /proc/net/nf_conntrack"; else echo "CTERR|neither the conntrack tool nor /proc/net/nf_conntrack is available"; ct=true; fi
+$ct 2>/dev/null | grep -F -e "$1" | head -n %d | sed 's/^/CT|/'
+if command -v ss >/dev/null 2>&1; then ss -Htan 2>/dev/null | grep -F -e "$2" | head -n %d | sed 's/^/SS|/'; else echo "SSERR|ss is not available"; fi`,
+		connectionsMaxLines, connectionsMaxLines)
+}
+
+// conntrackEntry is one connection tracked by netfilter, with its original
+// and reply tuples; a DNATed connection has a reply source that differs from
+// the original destination.
+type conntrackEntry struct {
+	proto     string
+	state     string // TCP only
+	orig      [2]netip.AddrPort
+	reply     [2]netip.AddrPort
+	unreplied bool
+}
+
+// parseConntrackLine reads a line of `conntrack -L -o extended` or
+// /proc/net/nf_conntrack.
+func parseConntrackLine(line string) (conntrackEntry, bool) {
+	var e conntrackEntry
+	var addrs [4]netip.Addr
+	var ports [4]uint16
+	srcs, dsts, sports, dports := 0, 0, 0, 0
+	for _, tok := range strings.Fields(line) {
+		key, value, ok := strings.Cut(tok, "=")
+		if !ok {
+			switch {
+			case tok == "[UNREPLIED]":
+				e.unreplied = true
+			case e.proto == "" && (tok == "tcp" || tok == "udp" || tok == "sctp" || tok == "dccp"):
+				e.proto = tok
+			case e.proto == "tcp" && e.state == "" && tok == strings.ToUpper(tok) && strings.Trim(tok, "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") == "":
+				e.state = tok
+			}
+			continue
+		}
+		switch key {
+		case "src", "dst":
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return e, false
+			}
+			i := &srcs
+			slot := 0
+			if key == "dst" {
+				i, slot = &dsts, 1
+			}
+			if *i < 2 {
+				addrs[*i*2+slot] = addr.Unmap()
+				*i++
+			}
+		case "sport", "dport":
+			port, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return e, false
+			}
+			i := &sports
+			slot := 0
+			if key == "dport" {
+				i, slot = &dports, 1
+			}
+			if *i < 2 {
+				ports[*i*2+slot] = uint16(port)
+				*i++
+			}
+		}
+	}
+	if e.proto == "" || srcs < 2 || dsts < 2 || sports < 2 || dports < 2 {
+		return e, false
+	}
+	for i := 0; i < 2; i++ {
+		e.orig[i] = netip.AddrPortFrom(addrs[i], ports[i])
+		e.reply[i] = netip.AddrPortFrom(addrs[2+i], ports[2+i])
+	}
+	return e, true
+}
+
+// socket is one TCP socket listed by ss.
+type socket struct {
+	state       string
+	local, peer netip.AddrPort
+}
+
+// parseSocketLine reads a line of `ss -Htan`.
+func parseSocketLine(line string) (socket, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 {
+		return socket{}, false
+	}
+	local, err1 := netip.ParseAddrPort(fields[3])
+	peer, err2 := netip.ParseAddrPort(fields[4])
+	if err1 != nil || err2 != nil {
+		return socket{}, false
+	}
+	return socket{
+		state: fields[0],
+		local: netip.AddrPortFrom(local.Addr().Unmap(), local.Port()),
+		peer:  netip.AddrPortFrom(peer.Addr().Unmap(), peer.Port()),
+	}, true
+}
+
+// nodeConnections is what the probe reported about a node.
+type nodeConnections struct {
+	conntrackCount, conntrackMax int
+	ephemeralPorts               int
+	entries                      []conntrackEntry
+	sockets                      []socket
+	truncated                    bool
+	errors                       []string
+}
+
+// parseConnectionsOutput reads the output of connectionsScript.
+func parseConnectionsOutput(output string) nodeConnections {
+	nc := nodeConnections{ephemeralPorts: defaultEphemeralPorts}
+	ctLines, ssLines := 0, 0
+	for _, line := range strings.Split(output, "\n") {
+		kind, rest, ok := strings.Cut(strings.TrimSpace(line), "|")
+		if !ok {
+			continue
+		}
+		switch kind {
+		case "CTCOUNT":
+			count, limit, _ := strings.Cut(rest, "|")
+			nc.conntrackCount, _ = strconv.Atoi(strings.TrimSpace(count))
+			nc.conntrackMax, _ = strconv.Atoi(strings.TrimSpace(limit))
+		case "PORTRANGE":
+			if f := strings.Fields(rest); len(f) == 2 {
+				lo, err1 := strconv.Atoi(f[0])
+				hi, err2 := strconv.Atoi(f[1])
+				if err1 == nil && err2 == nil && hi >= lo {
+					nc.ephemeralPorts = hi - lo + 1
+				}
+			}
+		case "CT":
+			ctLines++
+			if e, ok := parseConntrackLine(rest); ok {
+				nc.entries = append(nc.entries, e)
+			}
+		case "SS":
+			ssLines++
+			if s, ok := parseSocketLine(rest); ok {
+				nc.sockets = append(nc.sockets, s)
+			}
+		case "CTERR", "SSERR":
+			nc.errors = append(nc.errors, rest)
+		}
+	}
+	nc.truncated = ctLines >= connectionsMaxLines || ssLines >= connectionsMaxLines
+	return nc
+}
+
+// connectionTarget is what inspect_connections looks for: connections to
+// or from IPs, optionally on one port. For a Service, IPs are its cluster
+// IPs and endpoints the addresses it currently routes to.
+type connectionTarget struct {
+	label     string
+	ref       *types.ResourceRef
+	ips       map[netip.Addr]bool
+	port      uint16
+	service   bool
+	endpoints map[netip.Addr]bool // nil when unknown
+}
+
+func (c *connectionTarget) matches(ap netip.AddrPort) bool {
+	return c.ips[ap.Addr()] && (c.port == 0 || ap.Port() == c.port)
+}
+
+// grepPatterns returns the fixed strings the probe filters conntrack
+// entries and ss lines by.
+func (c *connectionTarget) grepPatterns() (string, string) {
+	var ct, ss []string
+	for ip := range c.ips {
+		ct = append(ct, fmt.Sprintf("=%s ", ip))
+		if ip.Is6() {
+			ss = append(ss, fmt.Sprintf("[%s]:", ip))
+		} else {
+			// IPv4 sockets of dual-stack listeners show as [::ffff:a.b.c.d]:port.
+			ss = append(ss, ip.String()+":", ip.String()+"]:")
+		}
+	}
+	sort.Strings(ct)
+	sort.Strings(ss)
+	return strings.Join(ct, "\n"), strings.Join(ss, "\n")
+}
+
+// stateCounts renders counts per state, most frequent first.
+func stateCounts(counts map[string]int) string {
+	states := make([]string, 0, len(counts))
+	for s := range counts {
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if counts[states[i]] != counts[states[j]] {
+			return counts[states[i]] > counts[states[j]]
+		}
+		return states[i] < states[j]
+	})
+	parts := make([]string, 0, len(states))
+	for _, s := range states {
+		parts = append(parts, fmt.Sprintf("%s=%d", s, counts[s]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// topCounts renders the n largest counts of keys.
+func topCounts(counts map[string]int, n int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	var lines []string
+	for i, k := range keys {
+		if i == n {
+			lines = append(lines, fmt.Sprintf("... %d more", len(keys)-n))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d", k, counts[k]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// connectionFindings evaluates what the probe saw on node for target.
+func connectionFindings(node string, target *connectionTarget, nc nodeConnections) []types.DiagnosticFinding {
+	ctStates := map[string]int{}
+	ssStates := map[string]int{}
+	synSent := map[string]int{}
+	timeWait := map[string]int{}
+	stale := map[string]int{}
+	staleUDP := 0
+	entries, sockets := 0, 0
+
+	for _, e := range nc.entries {
+		if target.service {
+			if !target.matches(e.orig[1]) {
+				continue
+			}
+		} else if !target.matches(e.orig[0]) && !target.matches(e.orig[1]) && !target.matches(e.reply[0]) && !target.matches(e.reply[1]) {
+			continue
+		}
+		entries++
+		state := e.state
+		if state == "" {
+			state = strings.ToUpper(e.proto)
+			if e.unreplied {
+				state += "_UNREPLIED"
+			}
+		}
+		ctStates[state]++
+		switch e.state {
+		case "SYN_SENT":
+			synSent[fmt.Sprintf("%s -> %s", e.orig[0].Addr(), e.orig[1])]++
+		case "TIME_WAIT":
+			timeWait[fmt.Sprintf("%s -> %s", e.orig[0].Addr(), e.orig[1])]++
+		}
+		if target.endpoints != nil && e.reply[0].Addr() != e.orig[1].Addr() && !target.endpoints[e.reply[0].Addr()] {
+			stale[fmt.Sprintf("%s %s -> %s", e.proto, e.orig[1], e.reply[0])]++
+			if e.proto == "udp" {
+				staleUDP++
+			}
+		}
+	}
+	for _, s := range nc.sockets {
+		if !target.matches(s.local) && !target.matches(s.peer) {
+			continue
+		}
+		sockets++
+		ssStates[s.state]++
+		switch s.state {
+		case "SYN-SENT":
+			synSent[fmt.Sprintf("%s -> %s (node socket)", s.local.Addr(), s.peer)]++
+		case "TIME-WAIT":
+			timeWait[fmt.Sprintf("%s -> %s (node socket)", s.local.Addr(), s.peer)]++
+		}
+	}
+
+	detail := []string{fmt.Sprintf("conntrack: %s", orDefault(stateCounts(ctStates), "none")), fmt.Sprintf("node sockets: %s", orDefault(stateCounts(ssStates), "none"))}
+	if nc.conntrackMax > 0 {
+		detail = append(detail, fmt.Sprintf("conntrack table: %d/%d entries", nc.conntrackCount, nc.conntrackMax))
+	}
+	if nc.truncated {
+		detail = append(detail, fmt.Sprintf("only the first %d conntrack entries and sockets were read", connectionsMaxLines))
+	}
+	detail = append(detail, nc.errors...)
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Resource: target.ref,
+		Summary:  fmt.Sprintf("%d conntrack entries and %d node sockets on node %s match %s", entries, sockets, node, target.label),
+		Detail:   strings.Join(detail, "\n"),
+	}}
+
+	if n := sumCounts(synSent); n >= synSentPileup {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeConnectionsSYNSentPileup,
+			Resource:   target.ref,
+			Summary:    fmt.Sprintf("%d connections to %s are stuck in SYN_SENT on node %s", n, target.label, node),
+			Detail:     topCounts(synSent, 10),
+			Suggestion: "SYNs leave the node but no SYN-ACK comes back: check NetworkPolicies and firewalls between the node and the destination, that the destination listens on the port, and with capture_traffic whether SYNs reach it.",
+		})
+	}
+
+	worst, worstKey := 0, ""
+	for k, v := range timeWait {
+		if v > worst || (v == worst && k < worstKey) {
+			worst, worstKey = v, k
+		}
+	}
+	if nc.ephemeralPorts > 0 && worst*2 >= nc.ephemeralPorts {
+		severity := types.SeverityWarning
+		if worst*10 >= nc.ephemeralPorts*9 {
+			severity = types.SeverityCritical
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeConnectionsTimeWaitExhaustion,
+			Resource:   target.ref,
+			Summary:    fmt.Sprintf("%d TIME_WAIT connections %s use %d%% of the %d ephemeral ports on node %s", worst, worstKey, worst*100/nc.ephemeralPorts, nc.ephemeralPorts, node),
+			Detail:     topCounts(timeWait, 10),
+			Suggestion: "Each new connection to the same destination needs a free source port until TIME_WAIT expires. Reuse connections with keep-alive or pooling, spread load over more destination IPs, or widen net.ipv4.ip_local_port_range.",
+		})
+	}
+
+	if n := sumCounts(stale); n > 0 {
+		severity := types.SeverityWarning
+		suggestion := "These entries keep sending traffic to addresses that are no longer endpoints until they expire."
+		if staleUDP > 0 {
+			severity = types.SeverityCritical
+			suggestion = "UDP entries are refreshed by every packet, so clients such as DNS resolvers keep sending to the deleted endpoint indefinitely. kube-proxy normally clears them; delete them with 'conntrack -D -p udp --orig-dst <service IP> --reply-src <endpoint IP>' and check kube-proxy's logs."
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeConnectionsStaleNAT,
+			Resource:   target.ref,
+			Summary:    fmt.Sprintf("%d conntrack NAT entries for %s on node %s point at endpoints that no longer exist", n, target.label, node),
+			Detail:     topCounts(stale, 10),
+			Suggestion: suggestion,
+		})
+	}
+
+	if nc.conntrackMax > 0 && nc.conntrackCount*10 >= nc.conntrackMax*9 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeConnectionsConntrackFull,
+			Summary:    fmt.Sprintf("The conntrack table of node %s holds %d of %d entries", node, nc.conntrackCount, nc.conntrackMax),
+			Suggestion: "When the table is full the kernel drops new connections ('nf_conntrack: table full, dropping packet' in dmesg). Raise net.netfilter.nf_conntrack_max, for example through kube-proxy's conntrack settings, or shorten conntrack timeouts.",
+		})
+	}
+	return findings
+}
+
+func sumCounts(counts map[string]int) int {
+	n := 0
+	for _, v := range counts {
+		n += v
+	}
+	return n
+}
+
+// --- inspect_connections ---
+
+type InspectConnectionsTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *InspectConnectionsTool) Name() string { return "inspect_connections" }
+func (t *InspectConnectionsTool) Description() string {
+	return "Inspect a node's conntrack entries and TCP sockets for a Service, pod or IP from a short-lived privileged pod, highlighting connections stuck in SYN_SENT, TIME_WAIT exhaustion of the ephemeral port range, NAT entries pointing at deleted endpoints and a full conntrack table"
+}
+func (t *InspectConnectionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"service": map[string]interface{}{
+				"type":        "string",
+				"description": "Service whose connections to inspect, as namespace/name",
+			},
+			"pod": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod whose connections to inspect, as namespace/name",
+			},
+			"ip": map[string]interface{}{
+				"type":        "string",
+				"description": "IP whose connections to inspect",
+			},
+			"port": map[string]interface{}{
+				"type":        "integer",
+				"description": "Only inspect connections on this port (the Service port for a Service)",
+			},
+			"node": map[string]interface{}{
+				"type":        "string",
+				"description": "Node to inspect, typically where the clients run (default for a pod: the pod's node; required otherwise)",
+			},
+		},
+	}
+}
+
+func (t *InspectConnectionsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	node := getStringArg(args, "node", "")
+	port := getIntArg(args, "port", 0)
+	if port < 0 || port > 65535 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("port %d out of range (1-65535)", port),
+		}
+	}
+	if node != "" && !validHostname.MatchString(node) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "node contains invalid characters",
+		}
+	}
+
+	target, podNode, err := t.resolveTarget(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	target.port = uint16(port)
+	if node == "" {
+		node = podNode
+	}
+	if node == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "node is required for a Service or IP: conntrack entries live on the nodes of the clients",
+		}
+	}
+
+	ctPattern, ssPattern := target.grepPatterns()
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:           probes.ProbeTypeConnections,
+		Namespace:      t.Cfg.ProbeNamespace,
+		NodeName:       node,
+		Command:        []string{"sh", "-c", connectionsScript(), "inspect", ctPattern, ssPattern},
+		Timeout:        60 * time.Second,
+		Privileged:     true,
+		MaxOutputBytes: 2 << 20,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 4)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+	if !strings.Contains(result.Output, "PORTRANGE|") {
+		detail := strings.TrimSpace(result.Output)
+		if result.Error != "" {
+			detail = strings.TrimSpace(result.Error + "\n" + detail)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Resource:   target.ref,
+			Summary:    fmt.Sprintf("Connections on node %s could not be inspected", node),
+			Detail:     detail,
+			Suggestion: fmt.Sprintf("Namespace %s must admit privileged pods (pod-security.kubernetes.io/enforce: privileged), and the probe image %s needs conntrack and ss.", t.Cfg.ProbeNamespace, t.Cfg.ProbeImage),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, target.ref.Namespace, ""), nil
+	}
+
+	findings = append(findings, connectionFindings(node, target, parseConnectionsOutput(result.Output))...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, target.ref.Namespace, ""), nil
+}
+
+// resolveTarget reads exactly one of service, pod and ip, and returns the
+// pod's node for a pod.
+func (t *InspectConnectionsTool) resolveTarget(ctx context.Context, args map[string]interface{}) (*connectionTarget, string, error) {
+	svc := getStringArg(args, "service", "")
+	pod := getStringArg(args, "pod", "")
+	ip := getStringArg(args, "ip", "")
+	set := 0
+	for _, v := range []string{svc, pod, ip} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "exactly one of service, pod and ip is required",
+		}
+	}
+
+	if ip != "" {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return nil, "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("ip %q is not an IP address", ip),
+			}
+		}
+		return &connectionTarget{label: ip, ref: &types.ResourceRef{}, ips: map[netip.Addr]bool{addr.Unmap(): true}}, "", nil
+	}
+
+	ref := pod
+	if svc != "" {
+		ref = svc
+	}
+	ns, name, ok := strings.Cut(ref, "/")
+	if !ok || ns == "" || name == "" {
+		return nil, "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "service and pod must be namespace/name",
+		}
+	}
+
+	if pod != "" {
+		p, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("failed to get pod %s", pod),
+				Detail:  err.Error(),
+			}
+		}
+		target := &connectionTarget{
+			label: "pod " + pod,
+			ref:   &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: name},
+			ips:   map[netip.Addr]bool{},
+		}
+		podIPs := []string{p.Status.PodIP}
+		for _, podIP := range p.Status.PodIPs {
+			podIPs = append(podIPs, podIP.IP)
+		}
+		for _, podIP := range podIPs {
+			if addr, err := netip.ParseAddr(podIP); err == nil {
+				target.ips[addr.Unmap()] = true
+			}
+		}
+		if len(target.ips) == 0 {
+			return nil, "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("pod %s has no IP yet", pod),
+			}
+		}
+		return target, p.Spec.NodeName, nil
+	}
+
+	s, err := t.Clients.Clientset.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get service %s", svc),
+			Detail:  err.Error(),
+		}
+	}
+	target := &connectionTarget{
+		label:   "service " + svc,
+		ref:     &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name},
+		ips:     map[netip.Addr]bool{},
+		service: true,
+	}
+	for _, clusterIP := range append([]string{s.Spec.ClusterIP}, s.Spec.ClusterIPs...) {
+		if addr, err := netip.ParseAddr(clusterIP); err == nil {
+			target.ips[addr.Unmap()] = true
+		}
+	}
+	if len(target.ips) == 0 {
+		return nil, "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("service %s has no cluster IP", svc),
+		}
+	}
+	// Not-ready addresses still exist: only entries pointing elsewhere are
+	// stale. Without the Endpoints, stale entries are not reported.
+	if ep, err := t.Clients.Dynamic.Resource(endpointsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		target.endpoints = map[netip.Addr]bool{}
+		subsets, _, _ := unstructured.NestedSlice(ep.Object, "subsets")
+		for _, sub := range subsets {
+			sm, _ := sub.(map[string]interface{})
+			for _, field := range []string{"addresses", "notReadyAddresses"} {
+				addrs, _ := sm[field].([]interface{})
+				for _, a := range addrs {
+					am, _ := a.(map[string]interface{})
+					if addr, err := netip.ParseAddr(fmt.Sprint(am["ip"])); err == nil {
+						target.endpoints[addr.Unmap()] = true
+					}
+				}
+			}
+		}
+	}
+	return target, "", nil
+}
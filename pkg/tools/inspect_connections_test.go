@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseConntrackLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		want  string
+		valid bool
+	}{
+		{
+			line:  "ipv4     2 tcp      6 431999 ESTABLISHED src=10.244.1.7 dst=10.96.0.20 sport=51000 dport=80 src=10.244.2.9 dst=10.244.1.7 sport=8080 dport=51000 [ASSURED] mark=0 zone=0 use=2",
+			want:  "tcp ESTABLISHED 10.244.1.7:51000 -> 10.96.0.20:80 reply 10.244.2.9:8080 -> 10.244.1.7:51000 false",
+			valid: true,
+		},
+		{
+			line:  "udp      17 28 src=10.244.1.7 dst=10.96.0.10 sport=40000 dport=53 [UNREPLIED] src=10.244.3.3 dst=10.244.1.7 sport=53 dport=40000 mark=0 use=1",
+			want:  "udp  10.244.1.7:40000 -> 10.96.0.10:53 reply 10.244.3.3:53 -> 10.244.1.7:40000 true",
+			valid: true,
+		},
+		{line: "icmp     1 29 src=10.0.0.1 dst=10.0.0.2 type=8 code=0 id=1 src=10.0.0.2 dst=10.0.0.1 type=0 code=0 id=1 mark=0 use=1"},
+		{line: "conntrack v1.4.7 (conntrack-tools): 12 flow entries have been shown."},
+	}
+	for _, tt := range tests {
+		e, ok := parseConntrackLine(tt.line)
+		if ok != tt.valid {
+			t.Errorf("parseConntrackLine(%q) ok = %v", tt.line, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		got := fmt.Sprintf("%s %s %s -> %s reply %s -> %s %v", e.proto, e.state, e.orig[0], e.orig[1], e.reply[0], e.reply[1], e.unreplied)
+		if got != tt.want {
+			t.Errorf("got  %s\nwant %s", got, tt.want)
+		}
+	}
+}
+
+func TestConnectionFindings(t *testing.T) {
+	lines := []string{
+		"CTCOUNT|950|1000",
+		"PORTRANGE|32768\t32867",
+		"CT|ipv4 2 tcp 6 431999 ESTABLISHED src=10.244.1.7 dst=10.96.0.20 sport=51000 dport=80 src=10.244.2.9 dst=10.244.1.7 sport=8080 dport=51000 [ASSURED] mark=0 use=2",
+		// An endpoint that was deleted: its UDP entry keeps traffic flowing to it.
+		"CT|ipv4 2 udp 17 28 src=10.244.1.7 dst=10.96.0.20 sport=40000 dport=80 src=10.244.9.9 dst=10.244.1.7 sport=8080 dport=40000 mark=0 use=1",
+		// Another Service port is filtered out.
+		"CT|ipv4 2 tcp 6 100 SYN_SENT src=10.244.1.7 dst=10.96.0.20 sport=50000 dport=443 [UNREPLIED] src=10.244.2.9 dst=10.244.1.7 sport=8443 dport=50000 mark=0 use=1",
+		"SSERR|ss is not available",
+	}
+	for i := 0; i < 12; i++ {
+		lines = append(lines, fmt.Sprintf("CT|ipv4 2 tcp 6 100 SYN_SENT src=10.244.1.8 dst=10.96.0.20 sport=%d dport=80 [UNREPLIED] src=10.244.2.9 dst=10.244.1.8 sport=8080 dport=%d mark=0 use=1", 41000+i, 41000+i))
+	}
+	for i := 0; i < 60; i++ {
+		lines = append(lines, fmt.Sprintf("CT|ipv4 2 tcp 6 100 TIME_WAIT src=10.244.1.9 dst=10.96.0.20 sport=%d dport=80 src=10.244.2.9 dst=10.244.1.9 sport=8080 dport=%d [ASSURED] mark=0 use=1", 32768+i, 32768+i))
+	}
+	nc := parseConnectionsOutput(strings.Join(lines, "\n"))
+	if nc.ephemeralPorts != 100 || nc.conntrackMax != 1000 || len(nc.entries) != 75 {
+		t.Fatalf("parsed ports %d, max %d, entries %d", nc.ephemeralPorts, nc.conntrackMax, len(nc.entries))
+	}
+
+	target := &connectionTarget{
+		label:     "service shop/web",
+		ref:       &types.ResourceRef{Kind: "Service", Namespace: "shop", Name: "web"},
+		ips:       map[netip.Addr]bool{netip.MustParseAddr("10.96.0.20"): true},
+		port:      80,
+		service:   true,
+		endpoints: map[netip.Addr]bool{netip.MustParseAddr("10.244.2.9"): true},
+	}
+	findings := connectionFindings("node-1", target, nc)
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Summary+" "+string(f.Code))
+	}
+	want := []string{
+		"info 74 conntrack entries and 0 node sockets on node node-1 match service shop/web ",
+		"critical 12 connections to service shop/web are stuck in SYN_SENT on node node-1 PRB012_SYN_SENT_PILEUP",
+		"warning 60 TIME_WAIT connections 10.244.1.9 -> 10.96.0.20:80 use 60% of the 100 ephemeral ports on node node-1 PRB013_TIME_WAIT_EXHAUSTION",
+		"critical 1 conntrack NAT entries for service shop/web on node node-1 point at endpoints that no longer exist PRB014_STALE_NAT_ENTRIES",
+		"critical The conntrack table of node node-1 holds 950 of 1000 entries PRB015_CONNTRACK_TABLE_FULL",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if want := "conntrack: TIME_WAIT=60 SYN_SENT=12 ESTABLISHED=1 UDP=1"; !strings.Contains(findings[0].Detail, want) {
+		t.Errorf("overview detail %q lacks %q", findings[0].Detail, want)
+	}
+	if want := "udp 10.96.0.20:80 -> 10.244.9.9:8080: 1"; findings[3].Detail != want {
+		t.Errorf("stale detail = %q, want %q", findings[3].Detail, want)
+	}
+}
+
+func TestParseSocketLine(t *testing.T) {
+	s, ok := parseSocketLine("SYN-SENT 0      1      [::ffff:10.0.0.5]:43210 10.96.0.20:80")
+	if !ok || s.state != "SYN-SENT" || s.local.String() != "10.0.0.5:43210" || s.peer.String() != "10.96.0.20:80" {
+		t.Errorf("got %+v, %v", s, ok)
+	}
+	if _, ok := parseSocketLine("LISTEN 0 4096 *:10250 *:*"); ok {
+		t.Error("wildcard listener parsed")
+	}
+}
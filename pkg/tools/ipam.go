@@ -0,0 +1,588 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	nodesGVR                 = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	serviceCIDRsV1GVR        = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "servicecidrs"}
+	serviceCIDRsV1B1GVR      = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "servicecidrs"}
+	ciliumNodesGVR           = schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumnodes"}
+	calicoIPAMBlocksGVR      = schema.GroupVersionResource{Group: "crd.projectcalico.org", Version: "v1", Resource: "ipamblocks"}
+	metallbIPAddressPoolsGVR = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "ipaddresspools"}
+)
+
+// calicoNamespacePoolsAnnotation lists the IPPools pods of a namespace get
+// addresses from.
+const calicoNamespacePoolsAnnotation = "cni.projectcalico.org/ipv4pools"
+
+// ipamCriticalPercent is the utilization reported as critical regardless of
+// the warning threshold.
+const ipamCriticalPercent = 95
+
+// ipamUsage is the allocation state of one address range.
+type ipamUsage struct {
+	label    string
+	ref      *types.ResourceRef
+	used     float64
+	capacity float64
+	detail   string
+}
+
+func (u ipamUsage) percent() float64 {
+	if u.capacity <= 0 {
+		return 0
+	}
+	return u.used / u.capacity * 100
+}
+
+// prefixSize returns the number of addresses in p.
+func prefixSize(p netip.Prefix) float64 {
+	return math.Pow(2, float64(p.Addr().BitLen()-p.Bits()))
+}
+
+// addressRange is an inclusive range of IPv4 or IPv6 addresses.
+type addressRange struct{ from, to netip.Addr }
+
+func (r addressRange) contains(a netip.Addr) bool {
+	return a.BitLen() == r.from.BitLen() && r.from.Compare(a) <= 0 && a.Compare(r.to) <= 0
+}
+
+// size returns the number of addresses in r, exact for IPv4.
+func (r addressRange) size() float64 {
+	if r.from.Is4() {
+		f, t := r.from.As4(), r.to.As4()
+		from := uint32(f[0])<<24 | uint32(f[1])<<16 | uint32(f[2])<<8 | uint32(f[3])
+		to := uint32(t[0])<<24 | uint32(t[1])<<16 | uint32(t[2])<<8 | uint32(t[3])
+		return float64(to-from) + 1
+	}
+	f, t := r.from.As16(), r.to.As16()
+	var diff float64
+	for i := 0; i < 16; i++ {
+		diff = diff*256 + float64(t[i]) - float64(f[i])
+	}
+	return diff + 1
+}
+
+// parseAddressRange reads a CIDR, an "a-b" range or a single address.
+func parseAddressRange(s string) (addressRange, bool) {
+	s = strings.TrimSpace(s)
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		a, err1 := netip.ParseAddr(strings.TrimSpace(from))
+		b, err2 := netip.ParseAddr(strings.TrimSpace(to))
+		if err1 != nil || err2 != nil || a.BitLen() != b.BitLen() || b.Less(a) {
+			return addressRange{}, false
+		}
+		return addressRange{a, b}, true
+	}
+	if p, err := netip.ParsePrefix(s); err == nil {
+		p = p.Masked()
+		last := p.Addr()
+		for i := 0; i < p.Addr().BitLen()-p.Bits(); i++ {
+			last = setHostBit(last, i)
+		}
+		return addressRange{p.Addr(), last}, true
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return addressRange{a, a}, true
+	}
+	return addressRange{}, false
+}
+
+// setHostBit sets bit i, counted from the least significant bit, of a.
+func setHostBit(a netip.Addr, i int) netip.Addr {
+	b := a.As16()
+	b[15-i/8] |= 1 << (i % 8)
+	out := netip.AddrFrom16(b)
+	if a.Is4() {
+		out = out.Unmap()
+	}
+	return out
+}
+
+// ipamSeverity maps a utilization to a severity.
+func ipamSeverity(pct float64, warnPercent int) string {
+	switch {
+	case pct >= ipamCriticalPercent:
+		return types.SeverityCritical
+	case pct >= float64(warnPercent):
+		return types.SeverityWarning
+	}
+	return types.SeverityOK
+}
+
+// ipamFinding reports u, with code and suggestion when it crosses warnPercent.
+func ipamFinding(u ipamUsage, warnPercent int, code types.FindingCode, suggestion string) types.DiagnosticFinding {
+	f := types.DiagnosticFinding{
+		Severity: ipamSeverity(u.percent(), warnPercent),
+		Category: types.CategoryConnectivity,
+		Resource: u.ref,
+		Summary:  fmt.Sprintf("%s: %.0f of %.0f addresses allocated (%.0f%%)", u.label, u.used, u.capacity, u.percent()),
+		Detail:   u.detail,
+	}
+	if f.Severity != types.SeverityOK {
+		f.Code = code
+		f.Suggestion = suggestion
+	}
+	return f
+}
+
+// podIPs returns the IPs of the running, non-host-network pods, by node.
+func podIPs(pods []unstructured.Unstructured) map[string][]netip.Addr {
+	out := make(map[string][]netip.Addr)
+	for _, p := range pods {
+		if hostNet, _, _ := unstructured.NestedBool(p.Object, "spec", "hostNetwork"); hostNet {
+			continue
+		}
+		if phase, _, _ := unstructured.NestedString(p.Object, "status", "phase"); phase == "Succeeded" || phase == "Failed" {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(p.Object, "spec", "nodeName")
+		ips, _, _ := unstructured.NestedSlice(p.Object, "status", "podIPs")
+		for _, ip := range ips {
+			im, _ := ip.(map[string]interface{})
+			s, _ := im["ip"].(string)
+			if a, err := netip.ParseAddr(s); err == nil {
+				out[node] = append(out[node], a)
+			}
+		}
+		if len(ips) == 0 {
+			s, _, _ := unstructured.NestedString(p.Object, "status", "podIP")
+			if a, err := netip.ParseAddr(s); err == nil {
+				out[node] = append(out[node], a)
+			}
+		}
+	}
+	return out
+}
+
+// nodeCIDRUsage computes the utilization of each node's spec.podCIDRs by the
+// pods running on it. The network and gateway addresses of IPv4 ranges are
+// not assignable. Nodes without a pod CIDR are left out.
+func nodeCIDRUsage(nodes []unstructured.Unstructured, ips map[string][]netip.Addr) []ipamUsage {
+	var out []ipamUsage
+	for _, n := range nodes {
+		cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "podCIDRs")
+		if len(cidrs) == 0 {
+			if c, _, _ := unstructured.NestedString(n.Object, "spec", "podCIDR"); c != "" {
+				cidrs = []string{c}
+			}
+		}
+		for _, c := range cidrs {
+			p, err := netip.ParsePrefix(c)
+			if err != nil || !p.Addr().Is4() {
+				continue // IPv6 pod ranges do not run out
+			}
+			u := ipamUsage{
+				label:    fmt.Sprintf("Node %s pod CIDR %s", n.GetName(), c),
+				ref:      &types.ResourceRef{Kind: "Node", Name: n.GetName()},
+				capacity: math.Max(prefixSize(p)-2, 1),
+			}
+			for _, ip := range ips[n.GetName()] {
+				if p.Contains(ip) {
+					u.used++
+				}
+			}
+			if maxPods := toInt(nestedValue(n.Object, "status", "allocatable", "pods")); maxPods > 0 {
+				u.detail = fmt.Sprintf("maxPods=%d", maxPods)
+				if float64(maxPods) > u.capacity {
+					u.detail += fmt.Sprintf(" exceeds the %.0f assignable addresses: the CIDR runs out before the pod limit", u.capacity)
+				}
+			}
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// nestedValue returns the value at fields, or nil.
+func nestedValue(obj map[string]interface{}, fields ...string) interface{} {
+	v, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	return v
+}
+
+// ciliumNodeUsage reads the addresses Cilium allocated on each node: from its
+// pre-allocated pool in ENI and Azure modes, from its pod CIDRs otherwise.
+func ciliumNodeUsage(nodes []unstructured.Unstructured) []ipamUsage {
+	var out []ipamUsage
+	for _, n := range nodes {
+		used, _, _ := unstructured.NestedMap(n.Object, "status", "ipam", "used")
+		u := ipamUsage{
+			label: fmt.Sprintf("CiliumNode %s", n.GetName()),
+			ref:   &types.ResourceRef{Kind: "CiliumNode", Name: n.GetName(), APIVersion: "cilium.io/v2"},
+			used:  float64(len(used)),
+		}
+		if pool, _, _ := unstructured.NestedMap(n.Object, "spec", "ipam", "pool"); len(pool) > 0 {
+			u.capacity = float64(len(pool))
+			u.detail = "source=pre-allocated pool"
+		} else {
+			cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "ipam", "podCIDRs")
+			for _, c := range cidrs {
+				if p, err := netip.ParsePrefix(c); err == nil && p.Addr().Is4() {
+					u.capacity += prefixSize(p)
+				}
+			}
+			u.detail = "podCIDRs=" + strings.Join(cidrs, ",")
+		}
+		if u.capacity > 0 {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// calicoBlock is a Calico IPAM block: a slice of a pool claimed by one node.
+type calicoBlock struct {
+	prefix    netip.Prefix
+	node      string
+	allocated int
+}
+
+func parseCalicoBlocks(blocks []unstructured.Unstructured) []calicoBlock {
+	var out []calicoBlock
+	for _, b := range blocks {
+		cidr, _, _ := unstructured.NestedString(b.Object, "spec", "cidr")
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		affinity, _, _ := unstructured.NestedString(b.Object, "spec", "affinity")
+		block := calicoBlock{prefix: p, node: strings.TrimPrefix(affinity, "host:")}
+		allocations, _, _ := unstructured.NestedSlice(b.Object, "spec", "allocations")
+		for _, a := range allocations {
+			if a != nil {
+				block.allocated++
+			}
+		}
+		out = append(out, block)
+	}
+	return out
+}
+
+// calicoPoolUsage is the address and block utilization of a Calico IPPool.
+type calicoPoolUsage struct {
+	ipamUsage
+	blocksClaimed int
+	blocks        int
+}
+
+// calicoPoolUsages computes each IPPool's address and block utilization and
+// the namespaces pinned to it.
+func calicoPoolUsages(pools, namespaces []unstructured.Unstructured, blocks []calicoBlock) []calicoPoolUsage {
+	pinned := make(map[string][]string)
+	for _, ns := range namespaces {
+		raw := ns.GetAnnotations()[calicoNamespacePoolsAnnotation]
+		var names []string
+		if raw == "" || json.Unmarshal([]byte(raw), &names) != nil {
+			continue
+		}
+		for _, name := range names {
+			pinned[name] = append(pinned[name], ns.GetName())
+		}
+	}
+
+	var out []calicoPoolUsage
+	for _, pool := range pools {
+		cidr, _, _ := unstructured.NestedString(pool.Object, "spec", "cidr")
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		if disabled, _, _ := unstructured.NestedBool(pool.Object, "spec", "disabled"); disabled {
+			continue
+		}
+		blockSize := toInt(nestedValue(pool.Object, "spec", "blockSize"))
+		if blockSize == 0 {
+			blockSize = 26
+			if p.Addr().Is6() {
+				blockSize = 122
+			}
+		}
+		u := calicoPoolUsage{ipamUsage: ipamUsage{
+			label:    fmt.Sprintf("Calico IPPool %s (%s)", pool.GetName(), cidr),
+			ref:      &types.ResourceRef{Kind: "IPPool", Name: pool.GetName(), APIVersion: "crd.projectcalico.org/v1"},
+			capacity: prefixSize(p),
+		}}
+		if blockSize >= p.Bits() && blockSize-p.Bits() < 31 {
+			u.blocks = 1 << (blockSize - p.Bits())
+		}
+		for _, b := range blocks {
+			if p.Contains(b.prefix.Addr()) {
+				u.blocksClaimed++
+				u.used += float64(b.allocated)
+			}
+		}
+		parts := []string{fmt.Sprintf("blocks=%d/%d blockSize=/%d", u.blocksClaimed, u.blocks, blockSize)}
+		if ns := pinned[pool.GetName()]; len(ns) > 0 {
+			sort.Strings(ns)
+			parts = append(parts, "namespaces="+strings.Join(ns, ","))
+		}
+		u.detail = strings.Join(parts, " ")
+		out = append(out, u)
+	}
+	return out
+}
+
+// serviceCIDRUsage computes the utilization of the Service CIDRs by the
+// ClusterIPs of services. The network and broadcast addresses of IPv4
+// ranges are never allocated.
+func serviceCIDRUsage(cidrs []string, services []unstructured.Unstructured) []ipamUsage {
+	var out []ipamUsage
+	for _, c := range cidrs {
+		p, err := netip.ParsePrefix(c)
+		if err != nil {
+			continue
+		}
+		u := ipamUsage{label: fmt.Sprintf("Service CIDR %s", c), capacity: prefixSize(p)}
+		if p.Addr().Is4() {
+			u.capacity = math.Max(u.capacity-2, 1)
+		}
+		for _, svc := range services {
+			ips, _, _ := unstructured.NestedStringSlice(svc.Object, "spec", "clusterIPs")
+			if len(ips) == 0 {
+				if ip, _, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP"); ip != "" {
+					ips = []string{ip}
+				}
+			}
+			for _, ip := range ips {
+				if a, err := netip.ParseAddr(ip); err == nil && p.Contains(a) {
+					u.used++
+				}
+			}
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// metallbPoolUsage computes how many addresses of each MetalLB IPAddressPool
+// are assigned to LoadBalancer Services.
+func metallbPoolUsage(pools, services []unstructured.Unstructured) []ipamUsage {
+	var assigned []netip.Addr
+	for _, svc := range services {
+		if t, _, _ := unstructured.NestedString(svc.Object, "spec", "type"); t != "LoadBalancer" {
+			continue
+		}
+		for _, a := range loadBalancerAddresses(svc.Object) {
+			if ip, err := netip.ParseAddr(a); err == nil {
+				assigned = append(assigned, ip)
+			}
+		}
+	}
+
+	var out []ipamUsage
+	for _, pool := range pools {
+		addrs, _, _ := unstructured.NestedStringSlice(pool.Object, "spec", "addresses")
+		u := ipamUsage{
+			label: fmt.Sprintf("MetalLB IPAddressPool %s/%s", pool.GetNamespace(), pool.GetName()),
+			ref:   &types.ResourceRef{Kind: "IPAddressPool", Namespace: pool.GetNamespace(), Name: pool.GetName(), APIVersion: "metallb.io/v1beta1"},
+		}
+		var ranges []addressRange
+		for _, a := range addrs {
+			if r, ok := parseAddressRange(a); ok {
+				ranges = append(ranges, r)
+				u.capacity += r.size()
+			}
+		}
+		for _, ip := range assigned {
+			for _, r := range ranges {
+				if r.contains(ip) {
+					u.used++
+					break
+				}
+			}
+		}
+		u.detail = "addresses=" + strings.Join(addrs, ",")
+		if auto, found, _ := unstructured.NestedBool(pool.Object, "spec", "autoAssign"); found && !auto {
+			u.detail += " autoAssign=false"
+		}
+		if u.capacity > 0 {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// --- analyze_ipam ---
+
+type AnalyzeIPAMTool struct{ BaseTool }
+
+func (t *AnalyzeIPAMTool) Name() string { return "analyze_ipam" }
+func (t *AnalyzeIPAMTool) Description() string {
+	return "Report IP address utilization and flag ranges approaching exhaustion: pod CIDR per node, Cilium per-node allocations, Calico IP pools and blocks (with the namespaces pinned to them), the Service CIDR and MetalLB LoadBalancer address pools"
+}
+func (t *AnalyzeIPAMTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"warn_percent": map[string]interface{}{
+				"type":        "integer",
+				"description": "Utilization reported as a warning (default: 80); 95% and above is critical",
+			},
+			"service_cidr": map[string]interface{}{
+				"type":        "string",
+				"description": "Service CIDR (--service-cluster-ip-range), for clusters without the ServiceCIDR API",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeIPAMTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	warnPercent := getIntArg(args, "warn_percent", 80)
+	serviceCIDR := getStringArg(args, "service_cidr", "")
+	if warnPercent < 1 || warnPercent > ipamCriticalPercent {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("warn_percent %d out of range (1-%d)", warnPercent, ipamCriticalPercent),
+		}
+	}
+	if serviceCIDR != "" {
+		if _, err := netip.ParsePrefix(serviceCIDR); err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("service_cidr %q is not a CIDR", serviceCIDR),
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	findings = append(findings, t.nodeFindings(ctx, warnPercent)...)
+
+	if pools, err := t.listResource(ctx, calicoIPPoolGVR, ""); err == nil && len(pools.Items) > 0 {
+		var blocks []calicoBlock
+		if list, err := t.listResource(ctx, calicoIPAMBlocksGVR, ""); err == nil {
+			blocks = parseCalicoBlocks(list.Items)
+		}
+		var namespaces []unstructured.Unstructured
+		if list, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+			namespaces = list.Items
+		}
+		for _, u := range calicoPoolUsages(pools.Items, namespaces, blocks) {
+			f := ipamFinding(u.ipamUsage, warnPercent, types.CodeIPAMPoolExhaustion,
+				"Add another IPPool (pods keep their addresses; new pods use the new pool), or free addresses held by leaked allocations with calicoctl ipam check.")
+			if f.Severity == types.SeverityOK && u.blocks > 0 && u.blocksClaimed >= u.blocks {
+				f.Severity = types.SeverityWarning
+				f.Code = types.CodeIPAMPoolExhaustion
+				f.Summary += "; every block is claimed"
+				f.Suggestion = "New nodes borrow single addresses from other nodes' blocks, which adds routes and breaks with strictAffinity. Add an IPPool or reduce the blockSize of a new pool."
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	var services []unstructured.Unstructured
+	if list, err := t.listResource(ctx, servicesGVR, ""); err == nil {
+		services = list.Items
+	}
+	findings = append(findings, t.serviceCIDRFindings(ctx, serviceCIDR, services, warnPercent)...)
+
+	if pools, err := t.listResource(ctx, metallbIPAddressPoolsGVR, ""); err == nil {
+		for _, u := range metallbPoolUsage(pools.Items, services) {
+			findings = append(findings, ipamFinding(u, warnPercent, types.CodeIPAMLoadBalancerPoolExhaustion,
+				"Add addresses to the pool or another IPAddressPool: new LoadBalancer Services stay <pending> once it is full. Check for Services of type LoadBalancer that are no longer needed."))
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+// nodeFindings reports per-node pod address utilization from CiliumNodes
+// when Cilium manages IPAM, from node pod CIDRs otherwise. Only nodes at or
+// above the threshold get their own finding.
+func (t *AnalyzeIPAMTool) nodeFindings(ctx context.Context, warnPercent int) []types.DiagnosticFinding {
+	var usages []ipamUsage
+	if list, err := t.listResource(ctx, ciliumNodesGVR, ""); err == nil {
+		usages = ciliumNodeUsage(list.Items)
+	}
+	if len(usages) == 0 {
+		nodes, err := t.listResource(ctx, nodesGVR, "")
+		if err != nil {
+			return []types.DiagnosticFinding{{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryConnectivity,
+				Summary:  "Nodes could not be listed; per-node pod CIDR utilization skipped",
+				Detail:   err.Error(),
+			}}
+		}
+		var pods []unstructured.Unstructured
+		if list, err := t.listResource(ctx, podsGVR, ""); err == nil {
+			pods = list.Items
+		}
+		usages = nodeCIDRUsage(nodes.Items, podIPs(pods))
+	}
+	if len(usages) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  "Nodes have no pod CIDR: the CNI allocates pod addresses itself (see the IP pool findings)",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	busiest := usages[0]
+	for _, u := range usages {
+		if u.percent() > busiest.percent() {
+			busiest = u
+		}
+		f := ipamFinding(u, warnPercent, types.CodeIPAMNodeCIDRExhaustion,
+			"Pods scheduled to this node will fail with 'failed to allocate for range' once it is full. Cordon the node or spread pods; for a lasting fix use a smaller --node-cidr-mask-size per node or a larger cluster CIDR, or lower maxPods to what the CIDR can hold.")
+		if f.Severity != types.SeverityOK {
+			findings = append(findings, f)
+		}
+	}
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("%d node pod ranges checked, %d at or above %d%%; highest: %s at %.0f%%", len(usages), len(findings), warnPercent, busiest.label, busiest.percent()),
+	})
+	return findings
+}
+
+// serviceCIDRFindings reports Service CIDR utilization from the ServiceCIDR
+// API, or from the service_cidr argument on clusters without it.
+func (t *AnalyzeIPAMTool) serviceCIDRFindings(ctx context.Context, serviceCIDR string, services []unstructured.Unstructured, warnPercent int) []types.DiagnosticFinding {
+	var cidrs []string
+	if serviceCIDR != "" {
+		cidrs = []string{serviceCIDR}
+	} else if list, err := t.listResourceWithFallback(ctx, serviceCIDRsV1GVR, serviceCIDRsV1B1GVR, ""); err == nil {
+		for _, sc := range list.Items {
+			c, _, _ := unstructured.NestedStringSlice(sc.Object, "spec", "cidrs")
+			cidrs = append(cidrs, c...)
+		}
+	}
+	if len(cidrs) == 0 {
+		allocated := 0
+		for _, svc := range services {
+			if ip, _, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP"); ip != "" && ip != "None" {
+				allocated++
+			}
+		}
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    fmt.Sprintf("%d ClusterIPs allocated; the Service CIDR is unknown", allocated),
+			Suggestion: "Pass service_cidr (the API server --service-cluster-ip-range) to compute its utilization; Kubernetes 1.33+ exposes it as ServiceCIDR objects.",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, u := range serviceCIDRUsage(cidrs, services) {
+		findings = append(findings, ipamFinding(u, warnPercent, types.CodeIPAMServiceCIDRExhaustion,
+			"New Services fail with 'failed to allocate a serviceIP' once the range is full. Add a ServiceCIDR (Kubernetes 1.33+) or delete unused Services; headless Services (clusterIP: None) do not use an address."))
+	}
+	return findings
+}
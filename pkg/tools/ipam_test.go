@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseAddressRange(t *testing.T) {
+	cases := map[string]float64{
+		"10.0.0.0/24":                 256,
+		"192.168.1.240-192.168.1.250": 11,
+		"192.168.1.0/30":              4,
+		"10.0.0.7":                    1,
+		"fd00::/120":                  256,
+		"fd00::10-fd00::1f":           16,
+	}
+	for in, want := range cases {
+		r, ok := parseAddressRange(in)
+		if !ok || r.size() != want {
+			t.Errorf("parseAddressRange(%q) size = %v (ok=%v), want %v", in, r.size(), ok, want)
+		}
+	}
+	r, _ := parseAddressRange("10.0.0.0/30")
+	if got := r.to.String(); got != "10.0.0.3" {
+		t.Errorf("last address of 10.0.0.0/30 = %s", got)
+	}
+	for _, bad := range []string{"10.0.0.9-10.0.0.1", "10.0.0.1-fd00::1", "pool"} {
+		if _, ok := parseAddressRange(bad); ok {
+			t.Errorf("parseAddressRange(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCiliumNodeUsage(t *testing.T) {
+	clusterPool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"ipam": map[string]interface{}{"podCIDRs": []interface{}{"10.0.1.0/27"}}},
+		"status": map[string]interface{}{"ipam": map[string]interface{}{"used": map[string]interface{}{"10.0.1.2": map[string]interface{}{}, "10.0.1.3": map[string]interface{}{}}}},
+	}}
+	clusterPool.SetName("a")
+	eni := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"ipam": map[string]interface{}{"pool": map[string]interface{}{"10.1.0.5": map[string]interface{}{}, "10.1.0.6": map[string]interface{}{}}}},
+		"status": map[string]interface{}{"ipam": map[string]interface{}{"used": map[string]interface{}{"10.1.0.5": map[string]interface{}{}}}},
+	}}
+	eni.SetName("b")
+	usages := ciliumNodeUsage([]unstructured.Unstructured{*clusterPool, *eni})
+	if len(usages) != 2 || usages[0].capacity != 32 || usages[0].used != 2 || usages[1].percent() != 50 {
+		t.Errorf("unexpected usages %+v", usages)
+	}
+}
+
+func ipamObj(apiVersion, kind, ns, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: fields}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(ns)
+	u.SetName(name)
+	return u
+}
+
+func TestAnalyzeIPAM(t *testing.T) {
+	objs := []runtime.Object{
+		ipamObj("v1", "Node", "", "node-a", map[string]interface{}{
+			"spec":   map[string]interface{}{"podCIDR": "10.244.0.0/28"},
+			"status": map[string]interface{}{"allocatable": map[string]interface{}{"pods": "110"}},
+		}),
+		ipamObj("v1", "Node", "", "node-b", map[string]interface{}{
+			"spec": map[string]interface{}{"podCIDRs": []interface{}{"10.244.1.0/24", "fd00:1::/64"}},
+		}),
+		ipamObj("v1", "Pod", "shop", "host", map[string]interface{}{
+			"spec":   map[string]interface{}{"nodeName": "node-b", "hostNetwork": true},
+			"status": map[string]interface{}{"podIP": "172.18.0.3"},
+		}),
+		ipamObj("v1", "Namespace", "", "shop", map[string]interface{}{}),
+		ipamObj("v1", "Service", "shop", "lb", map[string]interface{}{
+			"spec":   map[string]interface{}{"type": "LoadBalancer", "clusterIP": "10.96.0.1"},
+			"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{"ip": "192.168.1.240"}}}},
+		}),
+		ipamObj("v1", "Service", "shop", "headless", map[string]interface{}{
+			"spec": map[string]interface{}{"clusterIP": "None"},
+		}),
+	}
+	for i := 0; i < 13; i++ {
+		objs = append(objs, ipamObj("v1", "Pod", "shop", fmt.Sprintf("web-%d", i), map[string]interface{}{
+			"spec":   map[string]interface{}{"nodeName": "node-a"},
+			"status": map[string]interface{}{"phase": "Running", "podIP": fmt.Sprintf("10.244.0.%d", i+2)},
+		}))
+	}
+	for i := 2; i <= 6; i++ {
+		objs = append(objs, ipamObj("v1", "Service", "shop", fmt.Sprintf("svc-%d", i), map[string]interface{}{
+			"spec": map[string]interface{}{"clusterIPs": []interface{}{fmt.Sprintf("10.96.0.%d", i)}},
+		}))
+	}
+	objs[3].(*unstructured.Unstructured).SetAnnotations(map[string]string{calicoNamespacePoolsAnnotation: `["pods"]`})
+
+	tracked := map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		serviceCIDRsV1GVR: {ipamObj("networking.k8s.io/v1", "ServiceCIDR", "", "kubernetes", map[string]interface{}{
+			"spec": map[string]interface{}{"cidrs": []interface{}{"10.96.0.0/29"}},
+		})},
+		metallbIPAddressPoolsGVR: {ipamObj("metallb.io/v1beta1", "IPAddressPool", "metallb-system", "public", map[string]interface{}{
+			"spec": map[string]interface{}{"addresses": []interface{}{"192.168.1.240-192.168.1.243"}},
+		})},
+		calicoIPPoolGVR: {ipamObj("crd.projectcalico.org/v1", "IPPool", "", "pods", map[string]interface{}{
+			"spec": map[string]interface{}{"cidr": "10.10.0.0/24", "blockSize": int64(26)},
+		})},
+	}
+	for i := 0; i < 4; i++ {
+		tracked[calicoIPAMBlocksGVR] = append(tracked[calicoIPAMBlocksGVR], ipamObj("crd.projectcalico.org/v1", "IPAMBlock", "", fmt.Sprintf("block-%d", i), map[string]interface{}{
+			"spec": map[string]interface{}{
+				"cidr":        fmt.Sprintf("10.10.0.%d/26", i*64),
+				"affinity":    fmt.Sprintf("host:node-%d", i),
+				"allocations": []interface{}{int64(0), nil, int64(1), nil},
+			},
+		}))
+	}
+
+	listKinds := map[schema.GroupVersionResource]string{
+		nodesGVR: "NodeList", podsGVR: "PodList", servicesGVR: "ServiceList", namespacesGVR: "NamespaceList",
+		ciliumNodesGVR: "CiliumNodeList", calicoIPPoolGVR: "IPPoolList", calicoIPAMBlocksGVR: "IPAMBlockList",
+		serviceCIDRsV1GVR: "ServiceCIDRList", serviceCIDRsV1B1GVR: "ServiceCIDRList", metallbIPAddressPoolsGVR: "IPAddressPoolList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	for gvr, items := range tracked {
+		for _, item := range items {
+			if err := client.Tracker().Create(gvr, item, item.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tool := &AnalyzeIPAMTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byCode := make(map[types.FindingCode]types.DiagnosticFinding)
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		byCode[f.Code] = f
+		summaries = append(summaries, f.Summary)
+	}
+
+	node := byCode[types.CodeIPAMNodeCIDRExhaustion]
+	if node.Severity != types.SeverityWarning || !strings.Contains(node.Summary, "13 of 14 addresses") || !strings.Contains(node.Detail, "maxPods=110 exceeds") {
+		t.Errorf("unexpected node finding %+v", node)
+	}
+	if svc := byCode[types.CodeIPAMServiceCIDRExhaustion]; svc.Severity != types.SeverityCritical || !strings.Contains(svc.Summary, "6 of 6") {
+		t.Errorf("unexpected Service CIDR finding %+v", svc)
+	}
+	if pool := byCode[types.CodeIPAMPoolExhaustion]; !strings.Contains(pool.Summary, "every block is claimed") || !strings.Contains(pool.Detail, "namespaces=shop") {
+		t.Errorf("unexpected IPPool finding %+v", pool)
+	}
+	if _, ok := byCode[types.CodeIPAMLoadBalancerPoolExhaustion]; ok {
+		t.Error("the MetalLB pool is only 25% used")
+	}
+	joined := strings.Join(summaries, "\n")
+	if !strings.Contains(joined, "MetalLB IPAddressPool metallb-system/public: 1 of 4 addresses allocated (25%)") {
+		t.Errorf("MetalLB pool usage missing:\n%s", joined)
+	}
+	if !strings.Contains(joined, "2 node pod ranges checked, 1 at or above 80%") {
+		t.Errorf("node summary missing:\n%s", joined)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
@@ -50,7 +51,7 @@ func (t *ListIstioResourcesTool) Description() string {
 	return "List Istio resources (VirtualService, DestinationRule, AuthorizationPolicy, PeerAuthentication) with key summary fields"
 }
 func (t *ListIstioResourcesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"kind": map[string]interface{}{
@@ -64,7 +65,7 @@ func (t *ListIstioResourcesTool) InputSchema() map[string]interface{} {
 			},
 		},
 		"required": []string{"kind"},
-	}
+	})
 }
 
 func (t *ListIstioResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
@@ -80,8 +81,11 @@ func (t *ListIstioResourcesTool) Run(ctx context.Context, args map[string]interf
 		}
 	}
 
-	list, err := listWithFallback(ctx, t.Clients.Dynamic, pair.v1, pair.v1beta1, ns)
+	list, err := t.listResourceSelectedWithFallback(ctx, pair.v1, pair.v1beta1, ns, getSelectorArgs(args))
 	if err != nil {
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -268,6 +272,7 @@ func (t *GetIstioResourceTool) Run(ctx context.Context, args map[string]interfac
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioWeightsNot100,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("http[%d]: destination weights sum to %d, not 100", ri, totalWeight),
 					Suggestion: "Adjust route weights to sum to 100",
@@ -288,6 +293,7 @@ func (t *GetIstioResourceTool) Run(ctx context.Context, args map[string]interfac
 							findings = append(findings, types.DiagnosticFinding{
 								Severity:   types.SeverityWarning,
 								Category:   types.CategoryMesh,
+								Code:       types.CodeIstioRetryExceedsTimeout,
 								Resource:   ref,
 								Summary:    fmt.Sprintf("http[%d]: perTryTimeout(%s) × attempts(%d) = %s exceeds route timeout %s", ri, perTryTimeout, int(attempts), totalRetryDur, routeTimeout),
 								Suggestion: "Reduce retry attempts or perTryTimeout, or increase the route timeout",
@@ -350,16 +356,20 @@ func (t *GetIstioResourceTool) Run(ctx context.Context, args map[string]interfac
 					description, _ := vmm["description"].(string)
 
 					severity := types.SeverityInfo
+					var findingCode types.FindingCode
 					switch level {
 					case "ERROR":
 						severity = types.SeverityCritical
+						findingCode = types.CodeIstioAnalyzerMessage
 					case "WARNING":
 						severity = types.SeverityWarning
+						findingCode = types.CodeIstioAnalyzerMessage
 					}
 
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   severity,
 						Category:   types.CategoryMesh,
+						Code:       findingCode,
 						Resource:   ref,
 						Summary:    fmt.Sprintf("Validation %s: %s", level, code),
 						Detail:     description,
@@ -385,6 +395,7 @@ func (t *GetIstioResourceTool) Run(ctx context.Context, args map[string]interfac
 					findings = append(findings, types.DiagnosticFinding{
 						Severity: types.SeverityWarning,
 						Category: types.CategoryMesh,
+						Code:     types.CodeIstioConditionFalse,
 						Resource: ref,
 						Summary:  fmt.Sprintf("Condition %s=%s reason=%s", condType, condStatus, reason),
 						Detail:   message,
@@ -516,6 +527,7 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 			Tool:    t.Name(),
 			Message: fmt.Sprintf("failed to get namespace %s", ns),
 			Detail:  err.Error(),
+			Cause:   err,
 		}
 	}
 
@@ -537,6 +549,7 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioInjectionDisabled,
 			Resource:   &types.ResourceRef{Kind: "Namespace", Name: ns},
 			Summary:    fmt.Sprintf("Namespace %s does not have Istio injection enabled (label=%q)", ns, nsInjectionLabel),
 			Suggestion: "Add label istio-injection=enabled or istio.io/rev=<tag> to enable sidecar injection",
@@ -544,13 +557,14 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 	}
 
 	// List deployments
-	depList, err := t.Clients.Dynamic.Resource(deploymentsGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+	depList, err := t.listResource(ctx, deploymentsGVR, ns)
 	if err != nil {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeInternalError,
 			Tool:    t.Name(),
 			Message: fmt.Sprintf("failed to list deployments in %s", ns),
 			Detail:  err.Error(),
+			Cause:   err,
 		}
 	}
 
@@ -579,6 +593,7 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 
 		var status string
 		var severity string
+		var code types.FindingCode
 		var suggestion string
 
 		switch {
@@ -588,6 +603,7 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 		case injectionExpected && !hasSidecar:
 			status = "pending"
 			severity = types.SeverityWarning
+			code = types.CodeIstioSidecarPending
 			suggestion = "Sidecar injection is expected but not present; try restarting the deployment to trigger injection"
 		default:
 			status = "missing"
@@ -605,6 +621,7 @@ func (t *CheckSidecarInjectionTool) Run(ctx context.Context, args map[string]int
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   severity,
 			Category:   types.CategoryMesh,
+			Code:       code,
 			Resource:   depRef,
 			Summary:    fmt.Sprintf("Deployment %s/%s injection=%s", ns, depName, status),
 			Detail:     detail,
@@ -679,7 +696,7 @@ func (t *CheckIstioMTLSTool) Run(ctx context.Context, args map[string]interface{
 	ns := getStringArg(args, "namespace", "")
 
 	// Get PeerAuthentication policies (v1/v1beta1 fallback)
-	paList, err := listWithFallback(ctx, t.Clients.Dynamic, paV1GVR, paV1B1GVR, ns)
+	paList, err := t.listResourceWithFallback(ctx, paV1GVR, paV1B1GVR, ns)
 	if err != nil {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
@@ -689,16 +706,9 @@ func (t *CheckIstioMTLSTool) Run(ctx context.Context, args map[string]interface{
 		}
 	}
 
-	// Get DestinationRule TLS settings (v1/v1beta1 fallback)
-	drList, err := listWithFallback(ctx, t.Clients.Dynamic, drV1GVR, drV1B1GVR, ns)
-	if err != nil {
-		return nil, &types.MCPError{
-			Code:    types.ErrCodeCRDNotAvailable,
-			Tool:    t.Name(),
-			Message: "failed to list DestinationRule",
-			Detail:  fmt.Sprintf("tried networking.istio.io v1 and v1beta1: %v", err),
-		}
-	}
+	// Get DestinationRule TLS settings (v1/v1beta1 fallback); without them
+	// the PeerAuthentication checks still run
+	drList := orEmpty(t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, ns))
 
 	var findings []types.DiagnosticFinding
 
@@ -796,6 +806,7 @@ func (t *CheckIstioMTLSTool) Run(ctx context.Context, args map[string]interface{
 				findings = append(findings, types.DiagnosticFinding{
 					Severity: types.SeverityCritical,
 					Category: types.CategoryTLS,
+					Code:     types.CodeIstioMTLSConflict,
 					Resource: ref,
 					Summary: fmt.Sprintf("CONFLICT: DestinationRule %s/%s disables TLS for host %s but PeerAuthentication enforces STRICT mTLS",
 						drNs, drName, host),
@@ -842,38 +853,34 @@ func (t *ValidateIstioConfigTool) InputSchema() map[string]interface{} {
 func (t *ValidateIstioConfigTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
 
-	// Fetch VirtualServices
-	vsList, vsErr := listWithFallback(ctx, t.Clients.Dynamic, vsV1GVR, vsV1B1GVR, ns)
-	if vsErr != nil {
+	// Fetch VirtualServices and DestinationRules; one failing still leaves
+	// the other to validate, with the failure reported in the response errors
+	vsList, vsErr := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ns)
+	drList, drErr := t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, ns)
+	if vsErr != nil && drErr != nil {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
-			Message: "failed to list VirtualService",
+			Message: "failed to list VirtualService and DestinationRule",
 			Detail:  fmt.Sprintf("tried networking.istio.io v1 and v1beta1: %v", vsErr),
 		}
 	}
-
-	// Fetch DestinationRules
-	drList, drErr := listWithFallback(ctx, t.Clients.Dynamic, drV1GVR, drV1B1GVR, ns)
-	if drErr != nil {
-		return nil, &types.MCPError{
-			Code:    types.ErrCodeCRDNotAvailable,
-			Tool:    t.Name(),
-			Message: "failed to list DestinationRule",
-			Detail:  fmt.Sprintf("tried networking.istio.io v1 and v1beta1: %v", drErr),
-		}
-	}
+	vsList, drList = orEmpty(vsList, vsErr), orEmpty(drList, drErr)
 
 	var findings []types.DiagnosticFinding
 
+	validated := progress.NewCounter(ctx, "validated", len(vsList.Items)+len(drList.Items), "VirtualServices and DestinationRules")
+
 	// Validate each VirtualService
 	for i := range vsList.Items {
 		findings = append(findings, t.validateVirtualService(ctx, &vsList.Items[i], drList)...)
+		validated.Add(1)
 	}
 
 	// Validate each DestinationRule
 	for i := range drList.Items {
 		findings = append(findings, t.validateDestinationRule(ctx, &drList.Items[i])...)
+		validated.Add(1)
 	}
 
 	if len(findings) == 0 {
@@ -906,6 +913,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioVirtualServiceNoHosts,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("VirtualService %s/%s has no hosts defined", vsNs, vsName),
 			Suggestion: "Add at least one host in spec.hosts",
@@ -926,6 +934,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityWarning,
 				Category: types.CategoryMesh,
+				Code:     types.CodeIstioRouteShadowed,
 				Resource: ref,
 				Summary:  fmt.Sprintf("VirtualService %s/%s http route[%d] is a catch-all but not the last route", vsNs, vsName, ri),
 				Detail:   "Routes without match conditions match all requests. When placed before other routes, subsequent routes become unreachable.",
@@ -957,6 +966,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityCritical,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioDestinationUnset,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("VirtualService %s/%s http route[%d].route[%d] has no destination host", vsNs, vsName, ri, di),
 					Suggestion: "Set destination.host to a valid service name",
@@ -971,6 +981,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioDestinationServiceMissing,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("VirtualService %s/%s route destination host %q may not exist as a Service in %s", vsNs, vsName, destHost, svcNs),
 					Detail:     fmt.Sprintf("Service lookup failed: %v", svcErr),
@@ -983,6 +994,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityCritical,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioSubsetMissing,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("VirtualService %s/%s references subset %q for host %q but no matching DestinationRule subset found", vsNs, vsName, destSubset, destHost),
 					Suggestion: "Create a DestinationRule with a matching subset definition, or remove the subset reference",
@@ -995,6 +1007,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioWeightsNot100,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("VirtualService %s/%s http route[%d] weight sum is %d (expected 100)", vsNs, vsName, ri, totalWeight),
 				Suggestion: "Adjust route weights to sum to 100",
@@ -1020,6 +1033,7 @@ func (t *ValidateIstioConfigTool) validateVirtualService(ctx context.Context, vs
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityCritical,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioDestinationUnset,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("VirtualService %s/%s tcp route[%d].route[%d] has no destination host", vsNs, vsName, ri, di),
 					Suggestion: "Set destination.host to a valid service name",
@@ -1053,6 +1067,7 @@ func (t *ValidateIstioConfigTool) validateDestinationRule(ctx context.Context, d
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioDestinationServiceMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("DestinationRule %s/%s host %q may not exist as a Service in %s", drNs, drName, host, svcNs),
 				Detail:     fmt.Sprintf("Service lookup failed: %v", svcErr),
@@ -1092,6 +1107,7 @@ func (t *ValidateIstioConfigTool) validateDestinationRule(ctx context.Context, d
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioSubsetNoPods,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("DestinationRule %s/%s subset %q labels {%s} match no pods in %s", drNs, drName, subsetName, labelSelector, svcNs),
 				Suggestion: "Verify subset labels match the pod template labels of the target deployment",
@@ -1109,6 +1125,7 @@ func (t *ValidateIstioConfigTool) validateDestinationRule(ctx context.Context, d
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityCritical,
 				Category:   types.CategoryTLS,
+				Code:       types.CodeIstioClientCertMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("DestinationRule %s/%s TLS mode is MUTUAL but missing client certificate or private key", drNs, drName),
 				Detail:     fmt.Sprintf("clientCertificate=%q privateKey=%q", clientCert, privateKey),
@@ -1123,6 +1140,7 @@ func (t *ValidateIstioConfigTool) validateDestinationRule(ctx context.Context, d
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioConnectionPoolInvalid,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("DestinationRule %s/%s has tcp.maxConnections=%d (non-positive)", drNs, drName, int(maxConnections)),
 			Suggestion: "Set a positive value for connectionPool.tcp.maxConnections",
@@ -1193,7 +1211,7 @@ func (t *AnalyzeIstioAuthPolicyTool) InputSchema() map[string]interface{} {
 func (t *AnalyzeIstioAuthPolicyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
 
-	apList, err := listWithFallback(ctx, t.Clients.Dynamic, apV1GVR, apV1B1GVR, ns)
+	apList, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ns)
 	if err != nil {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
@@ -1274,6 +1292,7 @@ func (t *AnalyzeIstioAuthPolicyTool) Run(ctx context.Context, args map[string]in
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityCritical,
 				Category:   types.CategoryPolicy,
+				Code:       types.CodeIstioAuthzDenyAll,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("AuthorizationPolicy %s/%s is a blanket DENY with no rules — blocks ALL traffic (%s)", apNs, apName, scope),
 				Detail:     "A DENY policy with no rules matches all requests. This will block all traffic to the targeted workloads.",
@@ -1292,6 +1311,7 @@ func (t *AnalyzeIstioAuthPolicyTool) Run(ctx context.Context, args map[string]in
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryPolicy,
+						Code:       types.CodeIstioAuthzDenyAll,
 						Resource:   ref,
 						Summary:    fmt.Sprintf("AuthorizationPolicy %s/%s DENY rule[%d] has no from/to/when constraints — matches all traffic", apNs, apName, ri),
 						Suggestion: "Add from, to, or when conditions to narrow the deny rule scope",
@@ -1305,6 +1325,7 @@ func (t *AnalyzeIstioAuthPolicyTool) Run(ctx context.Context, args map[string]in
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryPolicy,
+				Code:       types.CodeIstioAuthzAllowNothing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("AuthorizationPolicy %s/%s is ALLOW with no rules — effectively denies all traffic (%s)", apNs, apName, scope),
 				Detail:     "An ALLOW policy with no rules means no requests are explicitly allowed. Combined with Istio's deny-by-default when any ALLOW policy exists, this blocks all traffic.",
@@ -1341,6 +1362,7 @@ func (t *AnalyzeIstioAuthPolicyTool) Run(ctx context.Context, args map[string]in
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityWarning,
 				Category: types.CategoryPolicy,
+				Code:     types.CodeIstioAuthzAllowDenyConflict,
 				Summary:  fmt.Sprintf("Conflicting ALLOW and DENY policies target the same workloads (%s)", selectorDesc),
 				Detail: fmt.Sprintf("ALLOW policies: %s\nDENY policies: %s\n"+
 					"When both ALLOW and DENY policies apply, DENY takes precedence. Ensure the ALLOW rules do not overlap with DENY rules, or traffic may be unexpectedly blocked.",
@@ -1517,6 +1539,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryRouting,
+			Code:       types.CodeServiceNoEndpoints,
 			Resource:   svcRef,
 			Summary:    fmt.Sprintf("Service %s/%s has 0 ready endpoints", ns, svcName),
 			Suggestion: "Check that pods matching the service selector are running and ready",
@@ -1531,7 +1554,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 	}
 
 	// Fetch VirtualServices in namespace
-	vsList, vsErr := listWithFallback(ctx, t.Clients.Dynamic, vsV1GVR, vsV1B1GVR, ns)
+	vsList, vsErr := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ns)
 	if vsErr != nil {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
@@ -1541,16 +1564,9 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 		}
 	}
 
-	// Fetch DestinationRules in namespace
-	drList, drErr := listWithFallback(ctx, t.Clients.Dynamic, drV1GVR, drV1B1GVR, ns)
-	if drErr != nil {
-		return nil, &types.MCPError{
-			Code:    types.ErrCodeCRDNotAvailable,
-			Tool:    t.Name(),
-			Message: "failed to list DestinationRule",
-			Detail:  fmt.Sprintf("tried networking.istio.io v1 and v1beta1: %v", drErr),
-		}
-	}
+	// Fetch DestinationRules in namespace; the VirtualService checks still
+	// run without them
+	drList := orEmpty(t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, ns))
 
 	// Find VirtualServices that reference this service
 	matchingVS := filterVSForService(vsList, svcName, ns)
@@ -1627,6 +1643,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeIstioRouteShadowed,
 					Resource:   vsRef,
 					Summary:    fmt.Sprintf("VirtualService %s/%s http route[%d] is unreachable — shadowed by a catch-all route above it", vs.GetNamespace(), vs.GetName(), ri),
 					Detail:     "A previous route has no match conditions and matches all requests. This route will never be evaluated.",
@@ -1671,6 +1688,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 						findings = append(findings, types.DiagnosticFinding{
 							Severity: types.SeverityCritical,
 							Category: types.CategoryRouting,
+							Code:     types.CodeIstioSubsetMissing,
 							Resource: vsRef,
 							Summary:  fmt.Sprintf("VirtualService %s/%s route[%d].route[%d] references non-existent subset %q for %s", vs.GetNamespace(), vs.GetName(), ri, di, destSubset, svcName),
 							Detail: func() string {
@@ -1693,6 +1711,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 						findings = append(findings, types.DiagnosticFinding{
 							Severity:   types.SeverityCritical,
 							Category:   types.CategoryRouting,
+							Code:       types.CodeIstioSubsetMissing,
 							Resource:   vsRef,
 							Summary:    fmt.Sprintf("VirtualService %s/%s route[%d].route[%d] references subset %q but no DestinationRule exists for %s", vs.GetNamespace(), vs.GetName(), ri, di, destSubset, svcName),
 							Suggestion: "Create a DestinationRule with subset definitions for this service",
@@ -1706,6 +1725,7 @@ func (t *AnalyzeIstioRoutingTool) Run(ctx context.Context, args map[string]inter
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityCritical,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeIstioWeightsNot100,
 					Resource:   vsRef,
 					Summary:    fmt.Sprintf("VirtualService %s/%s http route[%d] weight sum is %d (must be 100)", vs.GetNamespace(), vs.GetName(), ri, totalWeight),
 					Suggestion: "Adjust route destination weights to sum to exactly 100",
@@ -1820,6 +1840,7 @@ func (t *AnalyzeIstioRoutingTool) detectShadowedMatches(vs *unstructured.Unstruc
 					findings = append(findings, types.DiagnosticFinding{
 						Severity: types.SeverityWarning,
 						Category: types.CategoryRouting,
+						Code:     types.CodeIstioRouteShadowed,
 						Resource: vsRef,
 						Summary:  fmt.Sprintf("VirtualService %s/%s http route[%d] prefix %q may be shadowed by route[%d] prefix %q", vs.GetNamespace(), vs.GetName(), ri, curPrefix, pi, prevPrefix),
 						Detail:   fmt.Sprintf("Route[%d] matches prefix %q which is a superset of route[%d] prefix %q. The broader route will match first.", pi, prevPrefix, ri, curPrefix),
@@ -1847,7 +1868,7 @@ func extractMatchPrefix(match map[string]interface{}) string {
 
 // checkAuthPolicyConflicts checks if any DENY AuthorizationPolicy targets this service's workloads.
 func (t *AnalyzeIstioRoutingTool) checkAuthPolicyConflicts(ctx context.Context, svc *unstructured.Unstructured, svcName, ns string) []types.DiagnosticFinding {
-	apList, err := listWithFallback(ctx, t.Clients.Dynamic, apV1GVR, apV1B1GVR, ns)
+	apList, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ns)
 	if err != nil {
 		// Non-fatal — just skip AuthorizationPolicy analysis
 		slog.Debug("analyze_istio_routing: skipping AuthorizationPolicy check", "error", err)
@@ -1875,6 +1896,7 @@ func (t *AnalyzeIstioRoutingTool) checkAuthPolicyConflicts(ctx context.Context,
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityWarning,
 				Category: types.CategoryRouting,
+				Code:     types.CodeIstioAuthzDenyAffectsService,
 				Resource: &types.ResourceRef{
 					Kind:       "AuthorizationPolicy",
 					Namespace:  ap.GetNamespace(),
@@ -1893,6 +1915,7 @@ func (t *AnalyzeIstioRoutingTool) checkAuthPolicyConflicts(ctx context.Context,
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityWarning,
 				Category: types.CategoryRouting,
+				Code:     types.CodeIstioAuthzDenyAffectsService,
 				Resource: &types.ResourceRef{
 					Kind:       "AuthorizationPolicy",
 					Namespace:  ap.GetNamespace(),
@@ -2062,6 +2085,7 @@ func extractCBFromMap(parent map[string]interface{}, ref *types.ResourceRef) []t
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioOutlierDetectionAggressive,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("outlierDetection.consecutiveErrors=%d is aggressive (< 3)", int(consecutiveErrors)),
 				Suggestion: "Consider increasing consecutiveErrors to reduce false ejections",
@@ -2071,6 +2095,7 @@ func extractCBFromMap(parent map[string]interface{}, ref *types.ResourceRef) []t
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioOutlierDetectionAggressive,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("outlierDetection.consecutive5xxErrors=%d is aggressive (< 3)", int(consecutive5xx)),
 				Suggestion: "Consider increasing consecutive5xxErrors to reduce false ejections",
@@ -2081,6 +2106,7 @@ func extractCBFromMap(parent map[string]interface{}, ref *types.ResourceRef) []t
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioOutlierDetectionAggressive,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("outlierDetection.baseEjectionTime=%s is long (> 5m)", baseEjection),
 					Suggestion: "Long ejection times can cause extended service unavailability; consider reducing",
@@ -0,0 +1,391 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// envoyFilterRiskOrder ranks patch risk levels for aggregation.
+var envoyFilterRiskOrder = map[string]int{"low": 0, "medium": 1, "high": 2}
+
+// envoyFilterRisk classifies a configPatch. Listener-level patches sit in
+// front of every connection, so a bad one drops all traffic of the proxies
+// it applies to; REMOVE and REPLACE discard what Istio generated.
+func envoyFilterRisk(applyTo, operation string) string {
+	if operation == "REMOVE" || operation == "REPLACE" {
+		return "high"
+	}
+	switch applyTo {
+	case "LISTENER", "FILTER_CHAIN", "NETWORK_FILTER", "LISTENER_FILTER", "BOOTSTRAP":
+		return "high"
+	case "HTTP_FILTER", "CLUSTER", "ROUTE_CONFIGURATION", "EXTENSION_CONFIG":
+		return "medium"
+	}
+	return "low"
+}
+
+// envoyFilterPatch is one configPatch of an EnvoyFilter.
+type envoyFilterPatch struct {
+	Index        int
+	ApplyTo      string
+	Context      string
+	Operation    string
+	ProxyVersion string
+	Path         string
+}
+
+// envoyFilterInfo is an EnvoyFilter with the proxies it selects.
+type envoyFilterInfo struct {
+	Namespace string
+	Name      string
+	// Root is true for filters in the Istio root namespace, which apply to
+	// proxies of every namespace.
+	Root     bool
+	Selector map[string]string
+	Targets  []string
+	Priority int64
+	Patches  []envoyFilterPatch
+}
+
+func (f *envoyFilterInfo) key() string { return f.Namespace + "/" + f.Name }
+
+// scope describes the proxies an EnvoyFilter applies to.
+func (f *envoyFilterInfo) scope() string {
+	switch {
+	case len(f.Targets) > 0:
+		return "targets " + strings.Join(f.Targets, ", ")
+	case len(f.Selector) > 0 && f.Root:
+		return "workloads matching {" + joinLabels(f.Selector) + "} in all namespaces"
+	case len(f.Selector) > 0:
+		return fmt.Sprintf("workloads matching {%s} in %s", joinLabels(f.Selector), f.Namespace)
+	case f.Root:
+		return "all proxies in the mesh"
+	}
+	return "all proxies in namespace " + f.Namespace
+}
+
+// overlaps reports whether two EnvoyFilters can select the same proxy:
+// their namespaces must intersect and their selectors must not require
+// different values for the same label.
+func (f *envoyFilterInfo) overlaps(o *envoyFilterInfo) bool {
+	if !f.Root && !o.Root && f.Namespace != o.Namespace {
+		return false
+	}
+	if len(f.Targets) > 0 && len(o.Targets) > 0 && strings.Join(f.Targets, ",") != strings.Join(o.Targets, ",") {
+		return false
+	}
+	for k, v := range f.Selector {
+		if ov, ok := o.Selector[k]; ok && ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+func joinLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for k, v := range labels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// envoyFilterMatchPath renders the part of the generated configuration a
+// patch match selects, e.g. "port=8080 filter=envoy.filters.network.http_connection_manager".
+// It is empty when the patch matches every object of its applyTo type.
+func envoyFilterMatchPath(match map[string]interface{}) string {
+	var parts []string
+	add := func(label string, fields ...string) {
+		v, ok, _ := unstructured.NestedFieldNoCopy(match, fields...)
+		if !ok || v == nil {
+			return
+		}
+		if s := fmt.Sprint(v); s != "" {
+			parts = append(parts, label+"="+s)
+		}
+	}
+	add("listener", "listener", "name")
+	add("port", "listener", "portNumber")
+	add("chain", "listener", "filterChain", "name")
+	add("sni", "listener", "filterChain", "sni")
+	add("filter", "listener", "filterChain", "filter", "name")
+	add("subFilter", "listener", "filterChain", "filter", "subFilter", "name")
+	add("listenerFilter", "listener", "listenerFilter")
+	add("route", "routeConfiguration", "name")
+	add("port", "routeConfiguration", "portNumber")
+	add("portName", "routeConfiguration", "portName")
+	add("gateway", "routeConfiguration", "gateway")
+	add("vhost", "routeConfiguration", "vhost", "name")
+	add("httpRoute", "routeConfiguration", "vhost", "route", "name")
+	add("cluster", "cluster", "name")
+	add("service", "cluster", "service")
+	add("port", "cluster", "portNumber")
+	add("subset", "cluster", "subset")
+	return strings.Join(parts, " ")
+}
+
+// newEnvoyFilterInfo reads the scope and configPatches of an EnvoyFilter.
+func newEnvoyFilterInfo(ef *unstructured.Unstructured, rootNamespaces map[string]bool) *envoyFilterInfo {
+	info := &envoyFilterInfo{Namespace: ef.GetNamespace(), Name: ef.GetName(), Root: rootNamespaces[ef.GetNamespace()]}
+	info.Selector, _, _ = unstructured.NestedStringMap(ef.Object, "spec", "workloadSelector", "labels")
+	info.Priority, _, _ = unstructured.NestedInt64(ef.Object, "spec", "priority")
+	refs, _, _ := unstructured.NestedSlice(ef.Object, "spec", "targetRefs")
+	for _, r := range refs {
+		if rm, ok := r.(map[string]interface{}); ok {
+			kind, _ := rm["kind"].(string)
+			name, _ := rm["name"].(string)
+			info.Targets = append(info.Targets, kind+"/"+name)
+		}
+	}
+	sort.Strings(info.Targets)
+
+	patches, _, _ := unstructured.NestedSlice(ef.Object, "spec", "configPatches")
+	for i, p := range patches {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		patch := envoyFilterPatch{Index: i}
+		patch.ApplyTo, _ = pm["applyTo"].(string)
+		patch.Operation = getNestedString(pm, "patch", "operation")
+		match, _, _ := unstructured.NestedMap(pm, "match")
+		patch.Context = orDefault(getNestedString(match, "context"), "ANY")
+		patch.ProxyVersion = getNestedString(match, "proxy", "proxyVersion")
+		patch.Path = envoyFilterMatchPath(match)
+		info.Patches = append(info.Patches, patch)
+	}
+	return info
+}
+
+// contextsOverlap reports whether two patch contexts can select the same
+// part of a proxy's configuration.
+func contextsOverlap(a, b string) bool {
+	return a == b || a == "ANY" || b == "ANY"
+}
+
+// mutatingPatch reports whether a patch changes or removes what is already
+// there, so that the order of two patches on the same path matters.
+func mutatingPatch(operation string) bool {
+	return operation == "MERGE" || operation == "REPLACE" || operation == "REMOVE"
+}
+
+// envoyFilterVersionFindings checks the proxyVersion matches of a filter
+// against the installed Istio versions; a patch whose regex matches none of
+// them is silently skipped by every proxy.
+func envoyFilterVersionFindings(f *envoyFilterInfo, ref *types.ResourceRef, versions []string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, p := range f.Patches {
+		if p.ProxyVersion == "" {
+			continue
+		}
+		re, err := regexp.Compile(p.ProxyVersion)
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioEnvoyFilterVersionMismatch,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("EnvoyFilter %s configPatches[%d] has an invalid proxyVersion regex %q", f.key(), p.Index, p.ProxyVersion),
+				Detail:     err.Error(),
+				Suggestion: "Fix the regular expression; proxyVersion is matched against the proxy's Istio version, e.g. ^1\\.22.*",
+			})
+			continue
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		var matched []string
+		for _, v := range versions {
+			if re.MatchString(v) {
+				matched = append(matched, v)
+			}
+		}
+		switch {
+		case len(matched) == 0:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioEnvoyFilterVersionMismatch,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("EnvoyFilter %s configPatches[%d] proxyVersion %q matches none of the installed Istio versions (%s); the patch is not applied", f.key(), p.Index, p.ProxyVersion, strings.Join(versions, ", ")),
+				Detail:     "proxyVersion pins are usually left behind by an upgrade; the behaviour the filter added silently disappeared with it",
+				Suggestion: "Check that the patch is still valid for the new Envoy version, then widen or remove the proxyVersion match — or delete the filter if it is obsolete",
+			})
+		case len(matched) < len(versions):
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryMesh,
+				Code:     types.CodeIstioEnvoyFilterVersionMismatch,
+				Resource: ref,
+				Summary:  fmt.Sprintf("EnvoyFilter %s configPatches[%d] proxyVersion %q only matches Istio %s of the installed %s", f.key(), p.Index, p.ProxyVersion, strings.Join(matched, ", "), strings.Join(versions, ", ")),
+				Detail:   "proxies of the other revisions do not get the patch; expected during a canary upgrade if a version-specific filter exists for them",
+			})
+		}
+	}
+	return findings
+}
+
+// envoyFilterOverlapFindings reports pairs of filters that patch the same
+// configuration path of proxies both select, where at least one patch
+// modifies or removes what is there.
+func envoyFilterOverlapFindings(filters []*envoyFilterInfo) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	seen := make(map[string]bool)
+	for i, a := range filters {
+		for _, b := range filters[i+1:] {
+			if !a.overlaps(b) {
+				continue
+			}
+			for _, pa := range a.Patches {
+				for _, pb := range b.Patches {
+					if pa.ApplyTo != pb.ApplyTo || !contextsOverlap(pa.Context, pb.Context) {
+						continue
+					}
+					if pa.Path != pb.Path && pa.Path != "" && pb.Path != "" {
+						continue
+					}
+					if !mutatingPatch(pa.Operation) && !mutatingPatch(pb.Operation) {
+						continue
+					}
+					path := orDefault(pa.Path, pb.Path)
+					key := a.key() + "|" + b.key() + "|" + pa.ApplyTo + "|" + path
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					detail := fmt.Sprintf("%s: %s %s (priority %d); %s: %s %s (priority %d). Patches apply in priority order, then by creation time, so the later MERGE or REPLACE wins",
+						a.key(), pa.Operation, pa.Context, a.Priority, b.key(), pb.Operation, pb.Context, b.Priority)
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityWarning,
+						Category:   types.CategoryMesh,
+						Code:       types.CodeIstioEnvoyFilterOverlap,
+						Resource:   &types.ResourceRef{Kind: "EnvoyFilter", Namespace: a.Namespace, Name: a.Name, APIVersion: "networking.istio.io/v1alpha3"},
+						Summary:    fmt.Sprintf("EnvoyFilters %s and %s both modify %s %s", a.key(), b.key(), pa.ApplyTo, orDefault(path, "(all)")),
+						Detail:     detail,
+						Suggestion: "Merge the patches into one EnvoyFilter, or narrow their workloadSelectors or matches so each configuration path has a single owner; set spec.priority if the order matters",
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// --- analyze_envoyfilters ---
+
+type AnalyzeEnvoyFiltersTool struct{ BaseTool }
+
+func (t *AnalyzeEnvoyFiltersTool) Name() string { return "analyze_envoyfilters" }
+func (t *AnalyzeEnvoyFiltersTool) Description() string {
+	return "List Istio EnvoyFilters and classify each by patch context, scope and risk (listener and network filter patches that can break all traffic), flag proxyVersion matches that no installed Istio version satisfies, and detect filters that modify the same configuration path of the same proxies"
+}
+func (t *AnalyzeEnvoyFiltersTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only analyze EnvoyFilters in this namespace and the Istio root namespace (default: all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeEnvoyFiltersTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	list, err := t.listResource(ctx, envoyFilterV1A1, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list EnvoyFilters",
+			Detail:  err.Error(),
+		}
+	}
+
+	// The root namespace is where istiod runs (istio-system by default);
+	// its EnvoyFilters apply mesh-wide.
+	rootNamespaces := map[string]bool{"istio-system": true}
+	var versions []string
+	if deployments, err := t.listResource(ctx, deploymentsGVR, ""); err == nil {
+		seen := make(map[string]bool)
+		for _, r := range istioRevisions(deployments) {
+			rootNamespaces[r.Namespace] = true
+			if r.Version != "" && !seen[r.Version] {
+				seen[r.Version] = true
+				versions = append(versions, r.Version)
+			}
+		}
+	}
+	sort.Strings(versions)
+
+	var filters []*envoyFilterInfo
+	for i := range list.Items {
+		info := newEnvoyFilterInfo(&list.Items[i], rootNamespaces)
+		if ns != "" && info.Namespace != ns && !info.Root {
+			continue
+		}
+		filters = append(filters, info)
+	}
+	sort.Slice(filters, func(i, j int) bool { return filters[i].key() < filters[j].key() })
+
+	counts := make(map[string]int)
+	var findings []types.DiagnosticFinding
+	for _, f := range filters {
+		ref := &types.ResourceRef{Kind: "EnvoyFilter", Namespace: f.Namespace, Name: f.Name, APIVersion: "networking.istio.io/v1alpha3"}
+		risk := "low"
+		var patches, risky []string
+		for _, p := range f.Patches {
+			r := envoyFilterRisk(p.ApplyTo, p.Operation)
+			if envoyFilterRiskOrder[r] > envoyFilterRiskOrder[risk] {
+				risk = r
+			}
+			desc := fmt.Sprintf("[%d] %s %s in %s", p.Index, p.Operation, p.ApplyTo, p.Context)
+			if p.Path != "" {
+				desc += " (" + p.Path + ")"
+			}
+			patches = append(patches, desc)
+			if r == "high" {
+				risky = append(risky, fmt.Sprintf("%s %s", p.Operation, p.ApplyTo))
+			}
+		}
+		counts[risk]++
+
+		finding := types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Resource: ref,
+			Summary:  fmt.Sprintf("EnvoyFilter %s: %s risk, %d patches, applies to %s", f.key(), risk, len(f.Patches), f.scope()),
+			Detail:   strings.Join(patches, "\n"),
+		}
+		if risk == "high" {
+			finding.Severity = types.SeverityWarning
+			finding.Code = types.CodeIstioEnvoyFilterHighRisk
+			finding.Suggestion = fmt.Sprintf("%s patches can break every connection of the proxies they apply to, and are not validated against the Envoy version; narrow the scope with workloadSelector, pin match.context, and re-test after every Istio upgrade", strings.Join(risky, ", "))
+		}
+		findings = append(findings, finding)
+		findings = append(findings, envoyFilterVersionFindings(f, ref, versions)...)
+	}
+	findings = append(findings, envoyFilterOverlapFindings(filters)...)
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryMesh,
+		Summary:  fmt.Sprintf("%d EnvoyFilters: %d high risk, %d medium risk, %d low risk", len(filters), counts["high"], counts["medium"], counts["low"]),
+	}
+	if len(versions) > 0 {
+		summary.Detail = "Installed Istio versions: " + strings.Join(versions, ", ")
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), "istio"), nil
+}
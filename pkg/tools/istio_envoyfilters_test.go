@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func TestEnvoyFilterRisk(t *testing.T) {
+	for _, tc := range []struct{ applyTo, op, want string }{
+		{"NETWORK_FILTER", "MERGE", "high"},
+		{"HTTP_FILTER", "INSERT_BEFORE", "medium"},
+		{"HTTP_ROUTE", "MERGE", "low"},
+		{"HTTP_ROUTE", "REMOVE", "high"},
+	} {
+		if got := envoyFilterRisk(tc.applyTo, tc.op); got != tc.want {
+			t.Errorf("envoyFilterRisk(%s, %s) = %s, want %s", tc.applyTo, tc.op, got, tc.want)
+		}
+	}
+}
+
+func envoyFilter(ns, name string, selector map[string]interface{}, patches ...interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{"configPatches": patches}
+	if selector != nil {
+		spec["workloadSelector"] = map[string]interface{}{"labels": selector}
+	}
+	return managedObj("networking.istio.io/v1alpha3", "EnvoyFilter", ns, name, map[string]interface{}{"spec": spec})
+}
+
+func envoyPatch(applyTo, context, op, proxyVersion string) map[string]interface{} {
+	match := map[string]interface{}{
+		"context":  context,
+		"listener": map[string]interface{}{"filterChain": map[string]interface{}{"filter": map[string]interface{}{"name": "envoy.filters.network.http_connection_manager"}}},
+	}
+	if proxyVersion != "" {
+		match["proxy"] = map[string]interface{}{"proxyVersion": proxyVersion}
+	}
+	return map[string]interface{}{"applyTo": applyTo, "match": match, "patch": map[string]interface{}{"operation": op}}
+}
+
+func TestAnalyzeEnvoyFilters(t *testing.T) {
+	objs := []runtime.Object{
+		istiodDeployment("", "1.22.3"),
+		envoyFilter("istio-system", "hcm-tweaks", nil, envoyPatch("NETWORK_FILTER", "ANY", "MERGE", "")),
+		envoyFilter("shop", "web-lua", map[string]interface{}{"app": "web"}, envoyPatch("HTTP_FILTER", "SIDECAR_INBOUND", "INSERT_BEFORE", `^1\.18.*`)),
+		envoyFilter("shop", "web-hcm", map[string]interface{}{"app": "web"}, envoyPatch("NETWORK_FILTER", "SIDECAR_INBOUND", "MERGE", "")),
+		envoyFilter("shop", "api-hcm", map[string]interface{}{"app": "api"}, envoyPatch("NETWORK_FILTER", "SIDECAR_INBOUND", "MERGE", "")),
+		envoyFilter("other", "broken", nil, envoyPatch("HTTP_ROUTE", "GATEWAY", "MERGE", `^1\.(22`)),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		envoyFilterV1A1: "EnvoyFilterList", deploymentsGVR: "DeploymentList",
+	}, objs...)
+	tool := &AnalyzeEnvoyFiltersTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok 4 EnvoyFilters: 3 high risk, 1 medium risk, 0 low risk",
+		"warning EnvoyFilter istio-system/hcm-tweaks: high risk, 1 patches, applies to all proxies in the mesh IST028_ENVOYFILTER_HIGH_RISK",
+		"info EnvoyFilter shop/web-lua: medium risk, 1 patches, applies to workloads matching {app=web} in shop",
+		`warning EnvoyFilter shop/web-lua configPatches[0] proxyVersion "^1\\.18.*" matches none of the installed Istio versions (1.22.3); the patch is not applied IST029_ENVOYFILTER_VERSION_MISMATCH`,
+		"warning EnvoyFilters istio-system/hcm-tweaks and shop/web-hcm both modify NETWORK_FILTER filter=envoy.filters.network.http_connection_manager IST030_ENVOYFILTER_OVERLAP",
+		"warning EnvoyFilters istio-system/hcm-tweaks and shop/api-hcm both modify NETWORK_FILTER",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	// Different app labels never select the same workload.
+	if strings.Contains(all, "shop/api-hcm and shop/web-hcm") || strings.Contains(all, "other/broken") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"namespace": "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, `critical EnvoyFilter other/broken configPatches[0] has an invalid proxyVersion regex`) {
+		t.Errorf("expected the invalid regex to be reported in:\n%s", all)
+	}
+}
@@ -0,0 +1,465 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Istio's outlier detection defaults.
+const (
+	defaultMaxEjectionPercent = 10
+	defaultRetryAttempts      = 2
+)
+
+// retryAmplificationLimit is the worst-case load factor on the endpoints left
+// from which retries and ejection together are reported.
+const retryAmplificationLimit = 5
+
+// outlierPolicy is the effective outlierDetection and connectionPool of a
+// DestinationRule host or subset: a subset's trafficPolicy replaces each of
+// them when it sets it.
+type outlierPolicy struct {
+	Subset string            // "" for the host-level policy
+	Labels map[string]string // subset labels
+	// Own is true when the subset sets its own trafficPolicy fields.
+	Own     bool
+	Outlier map[string]interface{}
+	Pool    map[string]interface{}
+}
+
+func (p outlierPolicy) where(host string) string {
+	if p.Subset == "" {
+		return host
+	}
+	return fmt.Sprintf("%s subset %s", host, p.Subset)
+}
+
+// destinationRuleOutlierPolicies returns the host-level policy of a
+// DestinationRule, then one per subset.
+func destinationRuleOutlierPolicies(dr *unstructured.Unstructured) []outlierPolicy {
+	top, _, _ := unstructured.NestedMap(dr.Object, "spec", "trafficPolicy")
+	host := outlierPolicy{Own: true}
+	host.Outlier, _, _ = unstructured.NestedMap(top, "outlierDetection")
+	host.Pool, _, _ = unstructured.NestedMap(top, "connectionPool")
+	policies := []outlierPolicy{host}
+
+	subsets, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+	for _, s := range subsets {
+		sm, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := outlierPolicy{Outlier: host.Outlier, Pool: host.Pool}
+		p.Subset, _ = sm["name"].(string)
+		p.Labels, _, _ = unstructured.NestedStringMap(sm, "labels")
+		if od, ok, _ := unstructured.NestedMap(sm, "trafficPolicy", "outlierDetection"); ok {
+			p.Outlier, p.Own = od, true
+		}
+		if pool, ok, _ := unstructured.NestedMap(sm, "trafficPolicy", "connectionPool"); ok {
+			p.Pool, p.Own = pool, true
+		}
+		policies = append(policies, p)
+	}
+	return policies
+}
+
+// routeRetries is the retry policy of the VirtualService routes to a host or
+// subset with the most tries per request.
+type routeRetries struct {
+	Tries  int    // attempts + 1
+	Source string // e.g. "VirtualService shop/web http[0]"
+	// Default is true when the route sets no retries and Istio's default
+	// applies.
+	Default bool
+}
+
+// virtualServiceRetries returns the retries of every route destination,
+// keyed by "ns/service|subset", keeping the most tries per key.
+func virtualServiceRetries(vss []unstructured.Unstructured) map[string]routeRetries {
+	out := make(map[string]routeRetries)
+	for i := range vss {
+		vs := &vss[i]
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		for ri, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rr := routeRetries{Tries: defaultRetryAttempts + 1, Default: true}
+			if retries, ok, _ := unstructured.NestedMap(route, "retries"); ok {
+				rr = routeRetries{Tries: toInt(retries["attempts"]) + 1}
+			}
+			rr.Source = fmt.Sprintf("VirtualService %s/%s http[%d]", vs.GetNamespace(), vs.GetName(), ri)
+			dests, _, _ := unstructured.NestedSlice(route, "route")
+			for _, d := range dests {
+				dest, _, _ := unstructured.NestedMap(d.(map[string]interface{}), "destination")
+				host, _ := dest["host"].(string)
+				if host == "" {
+					continue
+				}
+				ns, name := resolveIstioHost(host, vs.GetNamespace())
+				subset, _ := dest["subset"].(string)
+				key := ns + "/" + name + "|" + subset
+				if rr.Tries > out[key].Tries {
+					out[key] = rr
+				}
+			}
+		}
+	}
+	return out
+}
+
+// outlierRiskFindings flags outlier detection that can eject every endpoint,
+// and retries that, with ejection, multiply the load on the endpoints left.
+// endpoints is -1 when unknown; retries.Tries is 0 when no route is known.
+func outlierRiskFindings(ref *types.ResourceRef, where string, od map[string]interface{}, endpoints int, retries routeRetries) []types.DiagnosticFinding {
+	if od == nil {
+		return nil
+	}
+	maxEjection := defaultMaxEjectionPercent
+	if v, ok := od["maxEjectionPercent"]; ok {
+		maxEjection = toInt(v)
+	}
+	minHealth := toInt(od["minHealthPercent"])
+
+	// Envoy ejects at least one host whatever maxEjectionPercent; below
+	// minHealthPercent healthy hosts it stops ejecting (panic mode).
+	ejectable := -1
+	if endpoints > 0 {
+		ejectable = max(1, endpoints*maxEjection/100)
+		if minHealth > 0 {
+			ejectable = min(ejectable, endpoints-int(math.Ceil(float64(endpoints*minHealth)/100)))
+		}
+		ejectable = min(ejectable, endpoints)
+	}
+
+	var findings []types.DiagnosticFinding
+	allEjected := minHealth == 0 && (ejectable == endpoints && endpoints > 0 || endpoints < 0 && maxEjection >= 100)
+	if allEjected {
+		summary := fmt.Sprintf("%s: outlier detection can eject all %d endpoints (maxEjectionPercent=%d%%, minHealthPercent=0)", where, endpoints, maxEjection)
+		switch {
+		case endpoints == 1:
+			summary = fmt.Sprintf("%s: outlier detection can eject the only endpoint", where)
+		case endpoints < 0:
+			summary = fmt.Sprintf("%s: outlier detection can eject every endpoint (maxEjectionPercent=%d%%, minHealthPercent=0)", where, maxEjection)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioOutlierEjectsAllHosts,
+			Resource:   ref,
+			Summary:    summary,
+			Detail:     "Envoy ejects at least one host whatever maxEjectionPercent; once every endpoint is ejected, requests fail with 503 UH until baseEjectionTime ends",
+			Suggestion: "Lower maxEjectionPercent, run more replicas, or set minHealthPercent (e.g. 50) so that outlier detection stops ejecting when too few endpoints are healthy",
+		})
+	}
+
+	if retries.Tries > 1 && endpoints > 0 && !allEjected {
+		factor := float64(retries.Tries*endpoints) / float64(endpoints-ejectable)
+		if factor >= retryAmplificationLimit {
+			retry := fmt.Sprintf("%d tries per request", retries.Tries)
+			if retries.Default {
+				retry += " (Istio's default retries)"
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioRetryEjectionAmplification,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: retries and ejection can put %.1fx the normal load on the endpoints left", where, factor),
+				Detail:     fmt.Sprintf("%s retries with %s; outlier detection can eject %d of %d endpoints", retries.Source, retry, ejectable, endpoints),
+				Suggestion: "During an outage, every failing request is retried against fewer endpoints, which can overload them in turn. Lower the route's retries.attempts, retry only on connect-failure/refused-stream, or lower maxEjectionPercent",
+			})
+		}
+	}
+	return findings
+}
+
+// outlierEjections is the ejection activity of an Envoy cluster.
+type outlierEjections struct {
+	Enforced float64 // over the window
+	Active   float64 // now, on the proxy with the most
+}
+
+// istioClusterKey turns an Istio outbound cluster name such as
+// "outbound|9080|v1|reviews.default.svc.cluster.local" into
+// "default/reviews|v1".
+func istioClusterKey(cluster string) (string, bool) {
+	parts := strings.Split(cluster, "|")
+	if len(parts) != 4 || parts[0] != "outbound" || !strings.HasSuffix(parts[3], ".svc.cluster.local") {
+		return "", false
+	}
+	ns, name := resolveIstioHost(parts[3], "")
+	return ns + "/" + name + "|" + parts[2], true
+}
+
+// queryOutlierEjections returns the ejections of every Istio outbound
+// cluster, keyed like istioClusterKey. Istio labels the cluster cluster_name;
+// plain Envoy stats use envoy_cluster_name.
+func queryOutlierEjections(ctx context.Context, p *prometheus.Client, window string) (map[string]*outlierEjections, error) {
+	byName := p.Selector(`cluster_name=~"outbound\\|.*"`)
+	byEnvoyName := p.Selector(`envoy_cluster_name=~"outbound\\|.*"`)
+	out := make(map[string]*outlierEjections)
+	add := func(query string, set func(*outlierEjections, float64)) error {
+		samples, err := p.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, s := range samples {
+			name := s.Labels["cluster_name"]
+			if name == "" {
+				name = s.Labels["envoy_cluster_name"]
+			}
+			key, ok := istioClusterKey(name)
+			if !ok {
+				continue
+			}
+			if out[key] == nil {
+				out[key] = &outlierEjections{}
+			}
+			set(out[key], s.Value)
+		}
+		return nil
+	}
+	const enforced = "envoy_cluster_outlier_detection_ejections_enforced_total"
+	if err := add(fmt.Sprintf(`sum by (cluster_name, envoy_cluster_name) (increase(%s%s[%s]) or increase(%s%s[%s]))`, enforced, byName, window, enforced, byEnvoyName, window),
+		func(e *outlierEjections, v float64) { e.Enforced += v }); err != nil {
+		return nil, err
+	}
+	const active = "envoy_cluster_outlier_detection_ejections_active"
+	if err := add(fmt.Sprintf(`max by (cluster_name, envoy_cluster_name) (%s%s or %s%s)`, active, byName, active, byEnvoyName),
+		func(e *outlierEjections, v float64) { e.Active = math.Max(e.Active, v) }); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// --- check_outlier_detection ---
+
+type CheckOutlierDetectionTool struct{ BaseTool }
+
+func (t *CheckOutlierDetectionTool) Name() string { return "check_outlier_detection" }
+func (t *CheckOutlierDetectionTool) Description() string {
+	return "Review the outlierDetection and connectionPool settings of Istio DestinationRules and their subsets: flag ejection that can remove every endpoint and route retries that, with ejection, multiply the load on the endpoints left, and, when Prometheus is configured, report the ejections proxies actually enforced per subset"
+}
+func (t *CheckOutlierDetectionTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check DestinationRules in this namespace (default: all namespaces)",
+			},
+			"window": map[string]interface{}{
+				"type":        "string",
+				"description": "How far back to count ejections in Prometheus, e.g. 1h or 24h (default 1h)",
+			},
+		},
+	}
+}
+
+func (t *CheckOutlierDetectionTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	windowArg := getStringArg(args, "window", "1h")
+	window, err := promWindow(windowArg)
+	if err != nil {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: err.Error()}
+	}
+
+	drs, err := t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, ns)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list DestinationRules",
+			Detail:  fmt.Sprintf("tried networking.istio.io/v1 and v1beta1: %v", err),
+		}
+	}
+	var retries map[string]routeRetries
+	if vss, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ""); err == nil {
+		retries = virtualServiceRetries(vss.Items)
+	}
+
+	// Ejection activity, when Prometheus is configured
+	var ejections map[string]*outlierEjections
+	var metricsNote *types.DiagnosticFinding
+	if t.Cfg.PrometheusURL == "" {
+		metricsNote = &types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Summary:  "Ejection events not checked: PROMETHEUS_URL is not set",
+		}
+	} else if p, err := prometheus.NewClient(t.Cfg.PrometheusURL, t.Cfg.PrometheusTokenFile, t.Cfg.PrometheusClusterLabel, t.Cfg.ClusterName); err != nil {
+		return nil, metricsUnavailable(t.Name(), err)
+	} else if ejections, err = queryOutlierEjections(ctx, p, window); err != nil {
+		metricsNote = &types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Summary:    "Ejection events not checked: the Prometheus query failed",
+			Detail:     err.Error(),
+			Suggestion: "Check PROMETHEUS_URL and that the server can reach it",
+		}
+	}
+
+	items := drs.Items
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+
+	pods := make(map[string][]unstructured.Unstructured)
+	var findings []types.DiagnosticFinding
+	checked, observed := 0, 0
+	for i := range items {
+		dr := &items[i]
+		policies := destinationRuleOutlierPolicies(dr)
+		configured := false
+		for _, p := range policies {
+			configured = configured || p.Outlier != nil || p.Pool != nil
+		}
+		if !configured {
+			continue
+		}
+		checked++
+		ref := &types.ResourceRef{Kind: "DestinationRule", Namespace: dr.GetNamespace(), Name: dr.GetName(), APIVersion: "networking.istio.io"}
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+
+		// Endpoints and routes are only known for a single-Service host
+		var svcNs, svcName string
+		var selector map[string]string
+		if host != "" && !strings.Contains(host, "*") {
+			svcNs, svcName = resolveIstioHost(host, dr.GetNamespace())
+			if svcs, err := t.listResource(ctx, servicesGVR, svcNs); err == nil {
+				for _, s := range svcs.Items {
+					if s.GetName() == svcName {
+						selector, _, _ = unstructured.NestedStringMap(s.Object, "spec", "selector")
+					}
+				}
+			}
+			if _, ok := pods[svcNs]; !ok && len(selector) > 0 {
+				if list, err := t.listResource(ctx, podsGVR, svcNs); err == nil {
+					pods[svcNs] = list.Items
+				}
+			}
+		}
+
+		for _, p := range policies {
+			if p.Own {
+				tp := map[string]interface{}{}
+				if p.Outlier != nil {
+					tp["outlierDetection"] = p.Outlier
+				}
+				if p.Pool != nil {
+					tp["connectionPool"] = p.Pool
+				}
+				for _, f := range extractCBFromMap(tp, ref) {
+					if p.Subset != "" {
+						f.Summary = fmt.Sprintf("subset %s %s", p.Subset, f.Summary)
+					}
+					findings = append(findings, f)
+				}
+			}
+			endpoints := -1
+			if len(selector) > 0 {
+				endpoints = countRunningPods(pods[svcNs], selector, p.Labels)
+			}
+			rr := retries[svcNs+"/"+svcName+"|"+p.Subset]
+			findings = append(findings, outlierRiskFindings(ref, p.where(host), p.Outlier, endpoints, rr)...)
+		}
+
+		// Ejections enforced by the proxies, per subset of the host
+		if ejections == nil {
+			continue
+		}
+		var services []string
+		if svcName != "" {
+			services = []string{svcNs + "/" + svcName}
+		} else if svcs, err := t.listResource(ctx, servicesGVR, ""); err == nil {
+			services = destinationRuleHostServices(host, dr.GetNamespace(), svcs.Items)
+		}
+		keys := make([]string, 0)
+		for key, e := range ejections {
+			svc, _, _ := strings.Cut(key, "|")
+			if containsString(services, svc) && (e.Enforced >= 0.5 || e.Active > 0) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			e := ejections[key]
+			observed++
+			svc, subset, _ := strings.Cut(key, "|")
+			where := svc
+			if subset != "" {
+				where += " subset " + subset
+			}
+			f := types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeIstioOutlierEjections,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: %.0f outlier ejections in the last %s", where, e.Enforced, windowArg),
+				Suggestion: "Ejected endpoints returned consecutive errors: check their logs and readiness (analyze_log_errors), or loosen the thresholds if the errors are expected",
+			}
+			if e.Active > 0 {
+				f.Severity = types.SeverityWarning
+				f.Summary += fmt.Sprintf(", %.0f endpoints ejected now", e.Active)
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	if checked == 0 {
+		scope := "any namespace"
+		if ns != "" {
+			scope = "namespace " + ns
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryMesh,
+			Summary:  fmt.Sprintf("No DestinationRule in %s sets outlierDetection or connectionPool", scope),
+		})
+	} else if ejections != nil && observed == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryMesh,
+			Summary:  fmt.Sprintf("No outlier ejections in the last %s for the %d DestinationRules checked", windowArg, checked),
+		})
+	}
+	if metricsNote != nil && checked > 0 {
+		findings = append(findings, *metricsNote)
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// countRunningPods counts the running pods matching a Service selector and
+// subset labels.
+func countRunningPods(pods []unstructured.Unstructured, selector, subset map[string]string) int {
+	set := labels.Set{}
+	for k, v := range selector {
+		set[k] = v
+	}
+	for k, v := range subset {
+		set[k] = v
+	}
+	sel := labels.SelectorFromSet(set)
+	n := 0
+	for i := range pods {
+		phase, _, _ := unstructured.NestedString(pods[i].Object, "status", "phase")
+		if phase == "Running" && sel.Matches(labels.Set(pods[i].GetLabels())) {
+			n++
+		}
+	}
+	return n
+}
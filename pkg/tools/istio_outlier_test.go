@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestDestinationRuleOutlierPolicies(t *testing.T) {
+	dr := managedObj("networking.istio.io/v1", "DestinationRule", "shop", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"host": "web",
+			"trafficPolicy": map[string]interface{}{
+				"outlierDetection": map[string]interface{}{"maxEjectionPercent": int64(100)},
+				"connectionPool":   map[string]interface{}{"tcp": map[string]interface{}{"maxConnections": int64(10)}},
+			},
+			"subsets": []interface{}{
+				map[string]interface{}{"name": "v1", "labels": map[string]interface{}{"version": "v1"}},
+				map[string]interface{}{"name": "v2", "labels": map[string]interface{}{"version": "v2"},
+					"trafficPolicy": map[string]interface{}{"outlierDetection": map[string]interface{}{"maxEjectionPercent": int64(50)}}},
+			},
+		},
+	})
+	policies := destinationRuleOutlierPolicies(dr)
+	if len(policies) != 3 {
+		t.Fatalf("policies = %+v", policies)
+	}
+	if v1 := policies[1]; v1.Own || v1.Labels["version"] != "v1" || toInt(v1.Outlier["maxEjectionPercent"]) != 100 {
+		t.Errorf("subset v1 = %+v, want the host-level policy", v1)
+	}
+	// A subset's outlierDetection replaces the host's; its connectionPool is inherited.
+	if v2 := policies[2]; !v2.Own || toInt(v2.Outlier["maxEjectionPercent"]) != 50 || v2.Pool == nil {
+		t.Errorf("subset v2 = %+v", v2)
+	}
+}
+
+func TestVirtualServiceRetries(t *testing.T) {
+	def := weightedVS("shop", "web", vsDest("web", "v1", 100))
+	explicit := managedObj("networking.istio.io/v1", "VirtualService", "shop", "web-retries", map[string]interface{}{
+		"spec": map[string]interface{}{"http": []interface{}{map[string]interface{}{
+			"retries": map[string]interface{}{"attempts": int64(5)},
+			"route":   []interface{}{vsDest("web.shop.svc.cluster.local", "v1", 100)},
+		}, map[string]interface{}{
+			"retries": map[string]interface{}{"attempts": int64(0)},
+			"route":   []interface{}{vsDest("web", "v2", 100)},
+		}}},
+	})
+	got := virtualServiceRetries([]unstructured.Unstructured{*def, *explicit})
+	if rr := got["shop/web|v1"]; rr.Tries != 6 || rr.Default || rr.Source != "VirtualService shop/web-retries http[0]" {
+		t.Errorf("v1 retries = %+v, want the 6 tries of web-retries", rr)
+	}
+	if rr := got["shop/web|v2"]; rr.Tries != 1 {
+		t.Errorf("v2 retries = %+v, want retries disabled", rr)
+	}
+}
+
+func TestOutlierRiskFindings(t *testing.T) {
+	ref := &types.ResourceRef{Kind: "DestinationRule", Namespace: "shop", Name: "web"}
+	codes := func(fs []types.DiagnosticFinding) []types.FindingCode {
+		var out []types.FindingCode
+		for _, f := range fs {
+			out = append(out, f.Code)
+		}
+		return out
+	}
+	tests := []struct {
+		name      string
+		od        map[string]interface{}
+		endpoints int
+		retries   routeRetries
+		want      []types.FindingCode
+	}{
+		{"default percent, one endpoint", map[string]interface{}{}, 1, routeRetries{}, []types.FindingCode{types.CodeIstioOutlierEjectsAllHosts}},
+		{"default percent, many endpoints", map[string]interface{}{}, 10, routeRetries{Tries: 3}, nil},
+		{"100% ejection", map[string]interface{}{"maxEjectionPercent": int64(100)}, 4, routeRetries{}, []types.FindingCode{types.CodeIstioOutlierEjectsAllHosts}},
+		{"100% ejection, unknown endpoints", map[string]interface{}{"maxEjectionPercent": int64(100)}, -1, routeRetries{}, []types.FindingCode{types.CodeIstioOutlierEjectsAllHosts}},
+		{"min health percent", map[string]interface{}{"maxEjectionPercent": int64(100), "minHealthPercent": int64(50)}, 4, routeRetries{Tries: 2}, nil},
+		{"retry amplification", map[string]interface{}{"maxEjectionPercent": int64(50)}, 4, routeRetries{Tries: 3, Default: true}, []types.FindingCode{types.CodeIstioRetryEjectionAmplification}},
+		{"no outlier detection", nil, 1, routeRetries{Tries: 3}, nil},
+	}
+	for _, tt := range tests {
+		got := codes(outlierRiskFindings(ref, "web", tt.od, tt.endpoints, tt.retries))
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("%s: codes = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIstioClusterKey(t *testing.T) {
+	tests := map[string]string{
+		"outbound|9080|v1|reviews.default.svc.cluster.local": "default/reviews|v1",
+		"outbound|80||web.shop.svc.cluster.local":            "shop/web|",
+		"inbound|8080||":                "",
+		"outbound|443||www.example.com": "",
+	}
+	for cluster, want := range tests {
+		if got, _ := istioClusterKey(cluster); got != want {
+			t.Errorf("istioClusterKey(%q) = %q, want %q", cluster, got, want)
+		}
+	}
+}
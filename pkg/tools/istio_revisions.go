@@ -0,0 +1,389 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var mutatingWebhooksGVR = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+
+// istioRevision is one installed istiod control plane revision.
+type istioRevision struct {
+	Name       string
+	Namespace  string
+	Deployment string
+	Version    string
+	Ready      int64
+	Replicas   int64
+	Tags       []string
+}
+
+// istioMinorRe extracts major.minor from an Istio image tag such as
+// "1.22.3" or "1.22.3-distroless".
+var istioMinorRe = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+
+// istioMinor returns the minor version of an Istio image tag, or -1 when the
+// tag is not a release version (dev builds, digests).
+func istioMinor(tag string) int {
+	m := istioMinorRe.FindStringSubmatch(tag)
+	if m == nil || m[1] != "1" {
+		return -1
+	}
+	minor, _ := strconv.Atoi(m[2])
+	return minor
+}
+
+// istioRevisions returns the istiod Deployments by revision; an istiod
+// without an istio.io/rev label is the "default" revision.
+func istioRevisions(deployments *unstructured.UnstructuredList) map[string]*istioRevision {
+	revisions := make(map[string]*istioRevision)
+	for i := range deployments.Items {
+		dep := &deployments.Items[i]
+		labels := dep.GetLabels()
+		if labels["app"] != "istiod" {
+			continue
+		}
+		rev := orDefault(labels["istio.io/rev"], "default")
+		r := &istioRevision{Name: rev, Namespace: dep.GetNamespace(), Deployment: dep.GetName()}
+		r.Replicas, _, _ = unstructured.NestedInt64(dep.Object, "spec", "replicas")
+		r.Ready, _, _ = unstructured.NestedInt64(dep.Object, "status", "readyReplicas")
+		containers, _, _ := unstructured.NestedSlice(dep.Object, "spec", "template", "spec", "containers")
+		for _, c := range containers {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, _ := cm["image"].(string)
+			if name, _ := cm["name"].(string); name == "discovery" || r.Version == "" {
+				r.Version = imageTag(image)
+			}
+		}
+		revisions[rev] = r
+	}
+	return revisions
+}
+
+// istioRevisionTags maps revision tags to the revision they point at, read
+// from the tag MutatingWebhookConfigurations istioctl creates.
+func istioRevisionTags(webhooks *unstructured.UnstructuredList) map[string]string {
+	tags := make(map[string]string)
+	if webhooks == nil {
+		return tags
+	}
+	for _, wh := range webhooks.Items {
+		labels := wh.GetLabels()
+		if tag := labels["istio.io/tag"]; tag != "" {
+			tags[tag] = orDefault(labels["istio.io/rev"], "default")
+		}
+	}
+	return tags
+}
+
+// resolveIstioRevision returns the revision a revision or tag name selects,
+// or "" when neither exists.
+func resolveIstioRevision(name string, revisions map[string]*istioRevision, tags map[string]string) string {
+	if rev, ok := tags[name]; ok {
+		if _, ok := revisions[rev]; ok {
+			return rev
+		}
+		return ""
+	}
+	if _, ok := revisions[name]; ok {
+		return name
+	}
+	return ""
+}
+
+// namespaceIstioRevision returns the revision or tag name a namespace asks
+// for: istio-injection=enabled selects the default tag and takes precedence
+// over istio.io/rev, as it does for the injector.
+func namespaceIstioRevision(labels map[string]string) string {
+	switch labels["istio-injection"] {
+	case "enabled":
+		return "default"
+	case "disabled":
+		return ""
+	}
+	return labels["istio.io/rev"]
+}
+
+// injectedIstioProxy returns the revision that injected a pod and the image
+// of its istio-proxy container; ok is false for pods without a sidecar.
+func injectedIstioProxy(pod *unstructured.Unstructured) (revision, image string, ok bool) {
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", field)
+		for _, c := range containers {
+			cm, isMap := c.(map[string]interface{})
+			if !isMap {
+				continue
+			}
+			if name, _ := cm["name"].(string); name == "istio-proxy" {
+				image, _ = cm["image"].(string)
+				ok = true
+			}
+		}
+	}
+	if !ok {
+		return "", "", false
+	}
+	var status struct {
+		Revision string `json:"revision"`
+	}
+	if raw := pod.GetAnnotations()["sidecar.istio.io/status"]; raw != "" && json.Unmarshal([]byte(raw), &status) == nil && status.Revision != "" {
+		return status.Revision, image, true
+	}
+	return orDefault(pod.GetLabels()["istio.io/rev"], "default"), image, true
+}
+
+// --- check_istio_revisions ---
+
+type CheckIstioRevisionsTool struct{ BaseTool }
+
+func (t *CheckIstioRevisionsTool) Name() string { return "check_istio_revisions" }
+func (t *CheckIstioRevisionsTool) Description() string {
+	return "List installed istiod revisions and revision tags, map namespaces and sidecar proxies to their revision, and detect namespaces or workloads pinned to revisions that no longer exist, proxies still on an old revision after a canary upgrade, and proxy/control plane version skew"
+}
+func (t *CheckIstioRevisionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only map this namespace and its workloads (default: all namespaces)",
+			},
+		},
+	}
+}
+
+// proxyGroup aggregates the sidecars of a namespace that share a revision
+// and proxy version, so large namespaces yield one finding per group.
+type proxyGroup struct {
+	Namespace string
+	Revision  string
+	Version   string
+	Pods      []string
+}
+
+func (t *CheckIstioRevisionsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	deployments, err := t.listResource(ctx, deploymentsGVR, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to list deployments",
+			Detail:  err.Error(),
+			Cause:   err,
+		}
+	}
+	revisions := istioRevisions(deployments)
+	webhooks, _ := t.listResource(ctx, mutatingWebhooksGVR, "")
+	tags := istioRevisionTags(webhooks)
+	for tag, rev := range tags {
+		if r, ok := revisions[rev]; ok {
+			r.Tags = append(r.Tags, tag)
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	if len(revisions) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioRevisionMissing,
+			Summary:    "No istiod Deployment (label app=istiod) found",
+			Suggestion: "Install the Istio control plane, or check that you can list Deployments cluster-wide",
+		})
+	}
+
+	// Namespaces: which revision each asks for.
+	nsRevision := make(map[string]string)
+	nsList, err := t.listResource(ctx, namespacesGVR, "")
+	if err == nil {
+		for _, item := range nsList.Items {
+			if ns != "" && item.GetName() != ns {
+				continue
+			}
+			requested := namespaceIstioRevision(item.GetLabels())
+			if requested == "" {
+				continue
+			}
+			resolved := resolveIstioRevision(requested, revisions, tags)
+			nsRevision[item.GetName()] = resolved
+			if resolved == "" {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryMesh,
+					Code:       types.CodeIstioRevisionMissing,
+					Resource:   &types.ResourceRef{Kind: "Namespace", Name: item.GetName()},
+					Summary:    fmt.Sprintf("Namespace %s selects Istio revision %q, which is neither an installed revision nor a revision tag", item.GetName(), requested),
+					Detail:     "no injector webhook matches the namespace, so new pods start without a sidecar",
+					Suggestion: "Relabel the namespace with istio.io/rev=<existing revision or tag>, or recreate the revision tag with istioctl tag set",
+				})
+			}
+		}
+	}
+
+	// Sidecars: the revision that injected each proxy and its version.
+	groups := make(map[string]*proxyGroup)
+	proxies := make(map[string]int)
+	namespaces := make(map[string]map[string]bool)
+	if pods, err := t.listResource(ctx, podsGVR, ns); err == nil {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			rev, image, ok := injectedIstioProxy(pod)
+			if !ok {
+				continue
+			}
+			version := imageTag(image)
+			key := pod.GetNamespace() + "|" + rev + "|" + version
+			g, ok := groups[key]
+			if !ok {
+				g = &proxyGroup{Namespace: pod.GetNamespace(), Revision: rev, Version: version}
+				groups[key] = g
+			}
+			g.Pods = append(g.Pods, pod.GetName())
+			proxies[rev]++
+			if namespaces[rev] == nil {
+				namespaces[rev] = make(map[string]bool)
+			}
+			namespaces[rev][pod.GetNamespace()] = true
+		}
+	}
+	for nsName, rev := range nsRevision {
+		if rev != "" {
+			if namespaces[rev] == nil {
+				namespaces[rev] = make(map[string]bool)
+			}
+			namespaces[rev][nsName] = true
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		findings = append(findings, proxyGroupFindings(groups[key], revisions, nsRevision)...)
+	}
+
+	// One summary per revision, first.
+	revNames := make([]string, 0, len(revisions))
+	for name := range revisions {
+		revNames = append(revNames, name)
+	}
+	sort.Strings(revNames)
+	summaries := make([]types.DiagnosticFinding, 0, len(revNames))
+	for _, name := range revNames {
+		r := revisions[name]
+		sort.Strings(r.Tags)
+		nsNames := make([]string, 0, len(namespaces[name]))
+		for n := range namespaces[name] {
+			nsNames = append(nsNames, n)
+		}
+		sort.Strings(nsNames)
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryMesh,
+			Resource: &types.ResourceRef{Kind: "Deployment", Namespace: r.Namespace, Name: r.Deployment, APIVersion: "apps/v1"},
+			Summary: fmt.Sprintf("Istio revision %s (%s/%s): version %s, %d/%d ready, tags [%s], %d namespaces, %d proxies",
+				name, r.Namespace, r.Deployment, orDefault(r.Version, "unknown"), r.Ready, r.Replicas, strings.Join(r.Tags, ","), len(nsNames), proxies[name]),
+		}
+		if len(nsNames) > 0 {
+			f.Detail = "Namespaces: " + truncateList(nsNames, 10)
+		}
+		if r.Ready == 0 {
+			f.Severity = types.SeverityCritical
+			f.Summary += " — no istiod replica is ready"
+		}
+		if len(nsNames) == 0 && proxies[name] == 0 {
+			f.Severity = types.SeverityInfo
+			f.Suggestion = "No namespace or proxy uses this revision; remove it once the upgrade is complete"
+		}
+		summaries = append(summaries, f)
+	}
+	findings = append(summaries, findings...)
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, "istio"), nil
+}
+
+// proxyGroupFindings checks a group of sidecars against the installed
+// revisions: the revision that injected them must still exist, should be the
+// one their namespace now selects, and should run the same minor version.
+func proxyGroupFindings(g *proxyGroup, revisions map[string]*istioRevision, nsRevision map[string]string) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Namespace", Name: g.Namespace}
+	pods := fmt.Sprintf("%d pods in %s", len(g.Pods), g.Namespace)
+	detail := "Pods: " + truncateList(g.Pods, 5)
+
+	r, ok := revisions[g.Revision]
+	if !ok {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioRevisionMissing,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s run sidecars injected by Istio revision %s, which is no longer installed", pods, g.Revision),
+			Detail:     detail + "\nthe proxies connect to istiod-" + g.Revision + " and stop receiving configuration and certificates",
+			Suggestion: "Restart the workloads so they are re-injected by an installed revision",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	if want, labelled := nsRevision[g.Namespace]; labelled && want != "" && want != g.Revision {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioRevisionMismatch,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s still run revision %s, but the namespace now selects revision %s", pods, g.Revision, want),
+			Detail:     detail,
+			Suggestion: fmt.Sprintf("Restart the workloads in %s to move them to revision %s before removing %s", g.Namespace, want, g.Revision),
+		})
+	}
+
+	proxyMinor, cpMinor := istioMinor(g.Version), istioMinor(r.Version)
+	if g.Version != "" && r.Version != "" && g.Version != r.Version {
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s run proxy %s against istiod revision %s %s", pods, g.Version, g.Revision, r.Version),
+			Detail:   detail,
+		}
+		switch {
+		case proxyMinor < 0 || cpMinor < 0 || proxyMinor == cpMinor:
+			f.Suggestion = "Restart the workloads to pick up the proxy of the current patch release"
+		case proxyMinor > cpMinor:
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeIstioVersionSkew
+			f.Summary = fmt.Sprintf("%s run proxy %s, newer than istiod revision %s %s", pods, g.Version, g.Revision, r.Version)
+			f.Suggestion = "A proxy newer than its control plane is unsupported: upgrade the revision or re-inject from the matching one"
+		default:
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeIstioVersionSkew
+			f.Summary = fmt.Sprintf("%s run proxy %s against istiod revision %s %s: %d minor versions behind", pods, g.Version, g.Revision, r.Version, cpMinor-proxyMinor)
+			f.Suggestion = "Restart the workloads to inject the current proxy; Istio supports a data plane at most two minor versions behind the control plane"
+			if cpMinor-proxyMinor > 2 {
+				f.Severity = types.SeverityCritical
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
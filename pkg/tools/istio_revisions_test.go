@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func TestIstioMinor(t *testing.T) {
+	for tag, want := range map[string]int{"1.22.3": 22, "1.21.0-distroless": 21, "latest": -1, "": -1} {
+		if got := istioMinor(tag); got != want {
+			t.Errorf("istioMinor(%q) = %d, want %d", tag, got, want)
+		}
+	}
+}
+
+func istiodDeployment(rev, version string) *unstructured.Unstructured {
+	name, labels := "istiod", map[string]string{"app": "istiod"}
+	if rev != "" {
+		name += "-" + rev
+		labels["istio.io/rev"] = rev
+	}
+	dep := managedObj("apps/v1", "Deployment", "istio-system", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "discovery", "image": "docker.io/istio/pilot:" + version},
+			}}},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(1)},
+	})
+	dep.SetLabels(labels)
+	return dep
+}
+
+func istioPod(ns, name, rev, version string) *unstructured.Unstructured {
+	pod := managedObj("v1", "Pod", ns, name, map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "shop:1"},
+			map[string]interface{}{"name": "istio-proxy", "image": "docker.io/istio/proxyv2:" + version},
+		}},
+	})
+	pod.SetAnnotations(map[string]string{"sidecar.istio.io/status": `{"containers":["istio-proxy"],"revision":"` + rev + `"}`})
+	return pod
+}
+
+func TestCheckIstioRevisions(t *testing.T) {
+	tag := managedObj("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "", "istio-revision-tag-prod", map[string]interface{}{})
+	tag.SetLabels(map[string]string{"istio.io/tag": "prod", "istio.io/rev": "1-22"})
+	shop := managedObj("v1", "Namespace", "", "shop", map[string]interface{}{})
+	shop.SetLabels(map[string]string{"istio.io/rev": "prod"})
+	legacy := managedObj("v1", "Namespace", "", "legacy", map[string]interface{}{})
+	legacy.SetLabels(map[string]string{"istio.io/rev": "1-19"})
+
+	objs := []runtime.Object{
+		istiodDeployment("1-21", "1.21.2"), istiodDeployment("1-22", "1.22.3"), tag, shop, legacy,
+		istioPod("shop", "cart-1", "1-21", "1.21.2"),
+		istioPod("shop", "web-1", "1-22", "1.20.0"),
+		istioPod("legacy", "old-1", "1-19", "1.19.9"),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList", namespacesGVR: "NamespaceList", podsGVR: "PodList", mutatingWebhooksGVR: "MutatingWebhookConfigurationList",
+	}, objs...)
+	tool := &CheckIstioRevisionsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok Istio revision 1-21 (istio-system/istiod-1-21): version 1.21.2, 1/1 ready, tags [], 1 namespaces, 1 proxies",
+		"ok Istio revision 1-22 (istio-system/istiod-1-22): version 1.22.3, 1/1 ready, tags [prod], 1 namespaces, 1 proxies",
+		`critical Namespace legacy selects Istio revision "1-19", which is neither an installed revision nor a revision tag IST022_REVISION_MISSING`,
+		"critical 1 pods in legacy run sidecars injected by Istio revision 1-19, which is no longer installed IST022_REVISION_MISSING",
+		"warning 1 pods in shop still run revision 1-21, but the namespace now selects revision 1-22 IST023_REVISION_MISMATCH",
+		"warning 1 pods in shop run proxy 1.20.0 against istiod revision 1-22 1.22.3: 2 minor versions behind IST020_VERSION_SKEW",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// istiodMonitoringPort is the istiod port serving Prometheus metrics.
+const istiodMonitoringPort = "15014"
+
+// istiodRejectMetrics are the per-proxy gauges istiod keeps while a proxy
+// NACKs a push, labelled with the proxy (node) and the Envoy error (err).
+var istiodRejectMetrics = []struct{ Metric, Type string }{
+	{"pilot_xds_cds_reject", "CDS"},
+	{"pilot_xds_eds_reject", "EDS"},
+	{"pilot_xds_lds_reject", "LDS"},
+	{"pilot_xds_rds_reject", "RDS"},
+}
+
+// istiodConflictMetrics count listeners istiod dropped because two services
+// claim the same port with incompatible protocols.
+var istiodConflictMetrics = []string{
+	"pilot_conflict_inbound_listener",
+	"pilot_conflict_outbound_listener_tcp_over_current_tcp",
+	"pilot_conflict_outbound_listener_tcp_over_current_http",
+	"pilot_conflict_outbound_listener_http_over_current_tcp",
+}
+
+// xdsRejection is one configuration a proxy currently rejects.
+type xdsRejection struct {
+	Proxy string
+	Type  string
+	Error string
+}
+
+// istiodPushStats is what one istiod replica reports about its xDS pushes.
+type istiodPushStats struct {
+	Connected      float64
+	Pushes         float64
+	Rejects        float64
+	InternalErrors float64
+	ContextErrors  float64
+	Conflicts      float64
+	ConvergenceSum float64
+	ConvergenceN   float64
+	Rejections     []xdsRejection
+}
+
+func (s *istiodPushStats) add(o istiodPushStats) {
+	s.Connected += o.Connected
+	s.Pushes += o.Pushes
+	s.Rejects += o.Rejects
+	s.InternalErrors += o.InternalErrors
+	s.ContextErrors += o.ContextErrors
+	s.Conflicts += o.Conflicts
+	s.ConvergenceSum += o.ConvergenceSum
+	s.ConvergenceN += o.ConvergenceN
+	s.Rejections = append(s.Rejections, o.Rejections...)
+}
+
+// parseIstiodMetrics extracts push, rejection and error counters from the
+// istiod /metrics output.
+func parseIstiodMetrics(text string) istiodPushStats {
+	var s istiodPushStats
+	s.Connected, _ = sumPromMetric(text, "pilot_xds")
+	s.Pushes, _ = sumPromMetric(text, "pilot_xds_pushes")
+	s.Rejects, _ = sumPromMetric(text, "pilot_total_xds_rejects")
+	s.InternalErrors, _ = sumPromMetric(text, "pilot_total_xds_internal_errors")
+	s.ContextErrors, _ = sumPromMetric(text, "pilot_xds_push_context_errors")
+	for _, m := range istiodConflictMetrics {
+		v, _ := sumPromMetric(text, m)
+		s.Conflicts += v
+	}
+	s.ConvergenceSum, _ = sumPromMetric(text, "pilot_proxy_convergence_time_sum")
+	s.ConvergenceN, _ = sumPromMetric(text, "pilot_proxy_convergence_time_count")
+	for _, m := range istiodRejectMetrics {
+		for _, sample := range promSamples(text, m.Metric) {
+			if sample.Value <= 0 {
+				continue
+			}
+			s.Rejections = append(s.Rejections, xdsRejection{Proxy: xdsProxyName(sample.Labels["node"]), Type: m.Type, Error: sample.Labels["err"]})
+		}
+	}
+	return s
+}
+
+// xdsProxyName returns the pod.namespace form of an xDS node ID; older
+// istiod versions label rejects with the full sidecar~ip~pod.ns~domain ID.
+func xdsProxyName(node string) string {
+	if parts := strings.Split(node, "~"); len(parts) == 4 {
+		return parts[2]
+	}
+	return node
+}
+
+// xdsProxyPod splits a pod.namespace proxy name.
+func xdsProxyPod(proxy string) (ns, name string) {
+	i := strings.LastIndex(proxy, ".")
+	if i < 0 {
+		return "", proxy
+	}
+	return proxy[i+1:], proxy[:i]
+}
+
+// rejectionFindings groups current NACKs by type and error, since one bad
+// resource usually makes every proxy it applies to reject the same push.
+// Only proxies in ns are reported when ns is set.
+func rejectionFindings(rejections []xdsRejection, ns string) []types.DiagnosticFinding {
+	type group struct {
+		Type, Error string
+		Proxies     []string
+	}
+	groups := make(map[string]*group)
+	seen := make(map[string]bool)
+	for _, r := range rejections {
+		proxyNs, _ := xdsProxyPod(r.Proxy)
+		if ns != "" && proxyNs != ns {
+			continue
+		}
+		key := r.Type + "|" + r.Error
+		if seen[key+"|"+r.Proxy] {
+			continue
+		}
+		seen[key+"|"+r.Proxy] = true
+		g, ok := groups[key]
+		if !ok {
+			g = &group{Type: r.Type, Error: r.Error}
+			groups[key] = g
+		}
+		g.Proxies = append(g.Proxies, r.Proxy)
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	findings := make([]types.DiagnosticFinding, 0, len(keys))
+	for _, k := range keys {
+		g := groups[k]
+		sort.Strings(g.Proxies)
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioXDSRejected,
+			Summary:    fmt.Sprintf("%d proxies reject the %s configuration istiod pushes", len(g.Proxies), g.Type),
+			Detail:     fmt.Sprintf("Envoy error: %s\nProxies: %s", orDefault(g.Error, "(not reported)"), truncateList(g.Proxies, 10)),
+			Suggestion: "These proxies keep serving their last accepted configuration, so recent VirtualService, DestinationRule, Sidecar or EnvoyFilter changes do not take effect; the error names the listener, route or cluster — fix or revert the resource that generates it",
+		}
+		if len(g.Proxies) == 1 {
+			proxyNs, pod := xdsProxyPod(g.Proxies[0])
+			f.Resource = &types.ResourceRef{Kind: "Pod", Namespace: proxyNs, Name: pod}
+			f.Summary = fmt.Sprintf("Proxy %s rejects the %s configuration istiod pushes", g.Proxies[0], g.Type)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// --- check_istiod_health ---
+
+type CheckIstiodHealthTool struct{ BaseTool }
+
+func (t *CheckIstiodHealthTool) Name() string { return "check_istiod_health" }
+func (t *CheckIstiodHealthTool) Description() string {
+	return "Check istiod control plane health: replica readiness and restarts, xDS push, rejection and error counters from the istiod metrics endpoint, listener conflicts, and the configurations each proxy currently rejects (NACKs), to explain why a VirtualService or DestinationRule change does not take effect"
+}
+func (t *CheckIstiodHealthTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"revision": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check this istiod revision (default: all revisions)",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only report rejected configuration for proxies in this namespace (default: all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *CheckIstiodHealthTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	revision := getStringArg(args, "revision", "")
+	ns := getStringArg(args, "namespace", "")
+
+	deployments, err := t.listResource(ctx, deploymentsGVR, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to list deployments",
+			Detail:  err.Error(),
+			Cause:   err,
+		}
+	}
+	revisions := istioRevisions(deployments)
+	names := make([]string, 0, len(revisions))
+	for name := range revisions {
+		if revision == "" || name == revision {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var findings []types.DiagnosticFinding
+	if len(names) == 0 {
+		summary := "No istiod Deployment (label app=istiod) found"
+		if revision != "" {
+			summary = fmt.Sprintf("No istiod Deployment for revision %q found", revision)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstiodUnhealthy,
+			Summary:    summary,
+			Suggestion: "Install the Istio control plane, or use check_istio_revisions to list the installed revisions",
+		})
+	}
+
+	for _, name := range names {
+		findings = append(findings, t.checkRevision(ctx, revisions[name], ns)...)
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), "istio"), nil
+}
+
+// checkRevision reports the pods of one istiod revision and the push
+// statistics scraped from each running replica.
+func (t *CheckIstiodHealthTool) checkRevision(ctx context.Context, r *istioRevision, ns string) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Deployment", Namespace: r.Namespace, Name: r.Deployment, APIVersion: "apps/v1"}
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryMesh,
+		Resource: ref,
+		Summary:  fmt.Sprintf("istiod revision %s (%s/%s): version %s, %d/%d ready", r.Name, r.Namespace, r.Deployment, orDefault(r.Version, "unknown"), r.Ready, r.Replicas),
+	}
+	var findings []types.DiagnosticFinding
+	if r.Ready < r.Replicas || r.Replicas == 0 {
+		severity := types.SeverityWarning
+		if r.Ready == 0 {
+			severity = types.SeverityCritical
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstiodUnhealthy,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("istiod revision %s has %d/%d replicas ready", r.Name, r.Ready, r.Replicas),
+			Detail:     "without a ready istiod, proxies keep their last configuration and new pods of this revision cannot get certificates or be injected",
+			Suggestion: fmt.Sprintf("Check the istiod pods and logs: kubectl -n %s logs deploy/%s", r.Namespace, r.Deployment),
+		})
+	}
+
+	dep, err := t.Clients.Clientset.AppsV1().Deployments(r.Namespace).Get(ctx, r.Deployment, metav1.GetOptions{})
+	if err != nil {
+		summary.Detail = "could not read the istiod Deployment: " + err.Error()
+		return append([]types.DiagnosticFinding{summary}, findings...)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+	if err != nil {
+		summary.Detail = "invalid istiod Deployment selector: " + err.Error()
+		return append([]types.DiagnosticFinding{summary}, findings...)
+	}
+	pods, err := t.Clients.Clientset.CoreV1().Pods(r.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		summary.Detail = "could not list istiod pods: " + err.Error()
+		return append([]types.DiagnosticFinding{summary}, findings...)
+	}
+
+	var stats istiodPushStats
+	var scraped, failed []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if f, ok := istiodPodFinding(pod); ok {
+			findings = append(findings, f)
+		}
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		raw, err := t.Clients.Clientset.CoreV1().Pods(r.Namespace).ProxyGet("http", pod.Name, istiodMonitoringPort, "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			failed = append(failed, pod.Name)
+			continue
+		}
+		scraped = append(scraped, pod.Name)
+		stats.add(parseIstiodMetrics(string(raw)))
+	}
+
+	if len(scraped) == 0 {
+		summary.Detail = "no istiod replica served metrics on port " + istiodMonitoringPort
+		if len(failed) > 0 {
+			summary.Detail += " (tried " + truncateList(failed, 5) + ")"
+		}
+		return append([]types.DiagnosticFinding{summary}, findings...)
+	}
+	summary.Summary += fmt.Sprintf(", %.0f proxies connected, %.0f pushes, %.0f rejected", stats.Connected, stats.Pushes, stats.Rejects)
+	if stats.ConvergenceN > 0 {
+		summary.Summary += fmt.Sprintf(", average convergence %.3fs", stats.ConvergenceSum/stats.ConvergenceN)
+	}
+	summary.Detail = "Metrics scraped from " + truncateList(scraped, 5)
+
+	rejected := rejectionFindings(stats.Rejections, ns)
+	findings = append(findings, rejected...)
+	if len(stats.Rejections) == 0 && stats.Rejects > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioXDSRejected,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Proxies rejected %.0f xDS pushes from istiod revision %s since it started; none is rejecting now", stats.Rejects, r.Name),
+			Detail:     "pilot_total_xds_rejects counts every NACK since istiod started; the per-proxy reject gauges are clear, so the rejected configuration was fixed or superseded",
+			Suggestion: "If the counter keeps growing, run this check again while the problem occurs, or watch the istiod logs for \"ADS:... NACK\"",
+		})
+	}
+	if stats.InternalErrors > 0 || stats.ContextErrors > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioXDSPushErrors,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("istiod revision %s reported %.0f internal xDS errors and %.0f push context errors", r.Name, stats.InternalErrors, stats.ContextErrors),
+			Detail:     "istiod could not generate or send configuration for some proxies, which then miss updates",
+			Suggestion: fmt.Sprintf("Look for errors in kubectl -n %s logs deploy/%s; invalid or conflicting Istio resources are the usual cause (see validate_istio_config)", r.Namespace, r.Deployment),
+		})
+	}
+	if stats.Conflicts > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstioListenerConflict,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("istiod revision %s dropped %.0f listeners because of port protocol conflicts", r.Name, stats.Conflicts),
+			Detail:     "two Services or ServiceEntries expose the same port with different protocols (HTTP vs TCP); istiod keeps one and drops the other",
+			Suggestion: "Name Service ports with their protocol (http-, grpc-, tcp-) or set appProtocol consistently for Services that share a port",
+		})
+	}
+
+	for _, f := range findings {
+		if f.Severity == types.SeverityCritical {
+			summary.Severity = types.SeverityCritical
+			break
+		}
+		if f.Severity == types.SeverityWarning {
+			summary.Severity = types.SeverityWarning
+		}
+	}
+	return append([]types.DiagnosticFinding{summary}, findings...)
+}
+
+// istiodPodFinding reports an istiod pod that is not ready or keeps
+// restarting.
+func istiodPodFinding(pod *corev1.Pod) (types.DiagnosticFinding, bool) {
+	ref := &types.ResourceRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+	ready := false
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			ready = c.Status == corev1.ConditionTrue
+		}
+	}
+	var restarts int32
+	lastReason := ""
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+		if cs.LastTerminationState.Terminated != nil {
+			lastReason = cs.LastTerminationState.Terminated.Reason
+		}
+	}
+	switch {
+	case !ready:
+		return types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstiodUnhealthy,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("istiod pod %s/%s is not ready (phase %s, %d restarts)", pod.Namespace, pod.Name, pod.Status.Phase, restarts),
+			Suggestion: fmt.Sprintf("kubectl -n %s describe pod %s; istiod only turns ready after its first full push", pod.Namespace, pod.Name),
+		}, true
+	case restarts >= 3:
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeIstiodUnhealthy,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("istiod pod %s/%s restarted %d times", pod.Namespace, pod.Name, restarts),
+			Suggestion: fmt.Sprintf("kubectl -n %s logs %s --previous; OOMKilled istiod usually needs more memory or a Sidecar resource to limit config scope", pod.Namespace, pod.Name),
+		}
+		if lastReason != "" {
+			f.Detail = "last termination reason: " + lastReason
+		}
+		return f, true
+	}
+	return types.DiagnosticFinding{}, false
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+const istiodTestMetrics = `# HELP pilot_xds Number of endpoints connected to this pilot using XDS.
+# TYPE pilot_xds gauge
+pilot_xds{version="1.22.3"} 42
+pilot_xds_pushes{type="cds"} 120
+pilot_xds_pushes{type="lds"} 80
+pilot_total_xds_rejects{type="lds"} 3
+pilot_total_xds_internal_errors 0
+pilot_conflict_outbound_listener_tcp_over_current_http 2
+pilot_proxy_convergence_time_sum 6
+pilot_proxy_convergence_time_count 60
+pilot_xds_lds_reject{err="Error adding/updating listener(s) 0.0.0.0_8080: \"bad\" filter",node="web-1.shop"} 1
+pilot_xds_lds_reject{err="Error adding/updating listener(s) 0.0.0.0_8080: \"bad\" filter",node="sidecar~10.0.0.7~web-2.shop~shop.svc.cluster.local"} 1
+pilot_xds_rds_reject{err="duplicate domain",node="api-1.payments"} 1
+pilot_xds_cds_reject{err="gone",node="old-1.shop"} 0
+`
+
+func TestPromSamples(t *testing.T) {
+	samples := promSamples(istiodTestMetrics, "pilot_xds_lds_reject")
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	if got := samples[0].Labels["err"]; got != `Error adding/updating listener(s) 0.0.0.0_8080: "bad" filter` {
+		t.Errorf("err label = %q", got)
+	}
+	if samples[1].Labels["node"] != "sidecar~10.0.0.7~web-2.shop~shop.svc.cluster.local" || samples[1].Value != 1 {
+		t.Errorf("unexpected sample %+v", samples[1])
+	}
+	if len(promSamples(istiodTestMetrics, "pilot_xds_push")) != 0 {
+		t.Error("expected a metric name prefix not to match")
+	}
+}
+
+func TestParseIstiodMetrics(t *testing.T) {
+	s := parseIstiodMetrics(istiodTestMetrics)
+	if s.Connected != 42 || s.Pushes != 200 || s.Rejects != 3 || s.Conflicts != 2 || s.ConvergenceN != 60 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if len(s.Rejections) != 3 || s.Rejections[1].Proxy != "web-2.shop" {
+		t.Errorf("unexpected rejections %+v", s.Rejections)
+	}
+	if got := rejectionFindings(s.Rejections, "payments"); len(got) != 1 || got[0].Resource.Name != "api-1" {
+		t.Errorf("expected only the payments proxy, got %+v", got)
+	}
+}
+
+// metricsResponse serves a fixed body for pod proxy requests.
+type metricsResponse struct{ body string }
+
+func (r metricsResponse) DoRaw(context.Context) ([]byte, error) { return []byte(r.body), nil }
+func (r metricsResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewBufferString(r.body)), nil
+}
+
+func TestCheckIstiodHealth(t *testing.T) {
+	labels := map[string]string{"app": "istiod"}
+	dep := istiodDeployment("", "1.22.3")
+	dep.SetName("istiod")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{deploymentsGVR: "DeploymentList"}, dep)
+
+	pod := func(name string, ready corev1.ConditionStatus, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "discovery", RestartCount: restarts}},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		pod("istiod-a", corev1.ConditionTrue, 0),
+		pod("istiod-b", corev1.ConditionTrue, 5),
+	)
+	clientset.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		return true, metricsResponse{istiodTestMetrics}, nil
+	})
+	tool := &CheckIstiodHealthTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client, Clientset: clientset}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"critical istiod revision default (istio-system/istiod): version 1.22.3, 1/1 ready, 84 proxies connected, 400 pushes, 6 rejected, average convergence 0.100s",
+		"warning istiod pod istio-system/istiod-b restarted 5 times IST024_ISTIOD_UNHEALTHY",
+		"critical 2 proxies reject the LDS configuration istiod pushes IST025_XDS_REJECTED",
+		"warning istiod revision default dropped 4 listeners because of port protocol conflicts IST027_LISTENER_CONFLICT",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "api-1.payments") || strings.Contains(all, "IST026") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+}
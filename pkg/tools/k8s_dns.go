@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
@@ -51,6 +55,7 @@ func (t *CheckDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSLookupFailed,
 			Summary:    fmt.Sprintf("DNS lookup failed for %s: %v", hostname, lookupErr),
 			Detail:     fmt.Sprintf("hostname=%s error=%v", hostname, lookupErr),
 			Suggestion: "Verify the hostname is correct and kube-dns is healthy. For cluster services, use FQDN format: <service>.<namespace>.svc.cluster.local",
@@ -94,6 +99,7 @@ func (t *CheckDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityCritical,
 				Category:   types.CategoryDNS,
+				Code:       types.CodeDNSKubeDNSNoEndpoints,
 				Resource:   &types.ResourceRef{Kind: "Service", Namespace: "kube-system", Name: "kube-dns"},
 				Summary:    "kube-dns has 0 ready endpoints",
 				Detail:     fmt.Sprintf("clusterIP=%s readyEndpoints=0", clusterIP),
@@ -104,6 +110,7 @@ func (t *CheckDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSKubeDNSMissing,
 			Summary:    fmt.Sprintf("kube-dns service not found: %v", err),
 			Suggestion: "Verify CoreDNS is deployed in the cluster",
 		})
@@ -117,28 +124,54 @@ func (t *CheckDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 type CheckKubeProxyHealthTool struct{ BaseTool }
 
 func (t *CheckKubeProxyHealthTool) Name() string        { return "check_kube_proxy_health" }
-func (t *CheckKubeProxyHealthTool) Description() string  { return "Check kube-proxy DaemonSet health: pod status across nodes, configuration mode (iptables/IPVS), unhealthy pods" }
+func (t *CheckKubeProxyHealthTool) Description() string  { return "Check kube-proxy health: DaemonSet status, proxy mode, per-node coverage (or eBPF replacement), crash-looping pods and version skew" }
 func (t *CheckKubeProxyHealthTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
-		"type":       "object",
-		"properties": map[string]interface{}{},
+		"type": "object",
+		"properties": map[string]interface{}{
+			"restart_window": map[string]interface{}{
+				"type":        "string",
+				"description": "Report kube-proxy pods that restarted within this duration (default: 1h)",
+			},
+		},
 	}
 }
 
 func (t *CheckKubeProxyHealthTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	findings := make([]types.DiagnosticFinding, 0, 4)
 
+	restartWindow, err := time.ParseDuration(getStringArg(args, "restart_window", "1h"))
+	if err != nil || restartWindow <= 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid restart_window %q", getStringArg(args, "restart_window", "")),
+		}
+	}
+
+	replacement, replacementSelector := t.kubeProxyReplacement(ctx)
+
 	// Check kube-proxy DaemonSet
 	ds, err := t.Clients.Dynamic.Resource(daemonsetsGVR).Namespace("kube-system").Get(ctx, "kube-proxy", metav1.GetOptions{})
-	if err != nil {
+	if err != nil && replacement == "" {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryConnectivity,
+			Code:       types.CodeKubeProxyDaemonSetMissing,
 			Summary:    fmt.Sprintf("kube-proxy DaemonSet not found: %v", err),
 			Suggestion: "kube-proxy may not be deployed as a DaemonSet (e.g., running as a static pod or replaced by a CNI like Cilium).",
 		})
 		return NewToolResultResponse(t.Cfg, t.Name(), findings, "kube-system", ""), nil
 	}
+	if err != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("kube-proxy is not deployed; Service routing is provided by %s", replacement),
+		})
+		findings = append(findings, t.nodeCoverage(ctx, replacement, replacementSelector, restartWindow)...)
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, "kube-system", ""), nil
+	}
 
 	desired, _, _ := unstructured.NestedInt64(ds.Object, "status", "desiredNumberScheduled")
 	ready, _, _ := unstructured.NestedInt64(ds.Object, "status", "numberReady")
@@ -146,16 +179,20 @@ func (t *CheckKubeProxyHealthTool) Run(ctx context.Context, args map[string]inte
 	unavailable, _, _ := unstructured.NestedInt64(ds.Object, "status", "numberUnavailable")
 
 	severity := types.SeverityOK
+	var code types.FindingCode
 	if unavailable > 0 {
 		severity = types.SeverityWarning
+		code = types.CodeKubeProxyPodsUnavailable
 	}
 	if ready == 0 && desired > 0 {
 		severity = types.SeverityCritical
+		code = types.CodeKubeProxyPodsUnavailable
 	}
 
 	findings = append(findings, types.DiagnosticFinding{
 		Severity: severity,
 		Category: types.CategoryConnectivity,
+		Code:     code,
 		Resource: &types.ResourceRef{Kind: "DaemonSet", Namespace: "kube-system", Name: "kube-proxy", APIVersion: "apps/v1"},
 		Summary:  fmt.Sprintf("kube-proxy: desired=%d ready=%d available=%d unavailable=%d", desired, ready, available, unavailable),
 		Detail:   fmt.Sprintf("desiredNumberScheduled=%d numberReady=%d numberAvailable=%d numberUnavailable=%d", desired, ready, available, unavailable),
@@ -169,12 +206,7 @@ func (t *CheckKubeProxyHealthTool) Run(ctx context.Context, args map[string]inte
 			configData, _, _ = unstructured.NestedString(cm.Object, "data", "kubeconfig.conf")
 		}
 
-		mode := "iptables"
-		if strings.Contains(configData, "mode: ipvs") || strings.Contains(configData, "mode: \"ipvs\"") {
-			mode = "ipvs"
-		} else if strings.Contains(configData, "mode: nftables") || strings.Contains(configData, "mode: \"nftables\"") {
-			mode = "nftables"
-		}
+		mode := kubeProxyMode(configData)
 
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityInfo,
@@ -185,27 +217,323 @@ func (t *CheckKubeProxyHealthTool) Run(ctx context.Context, args map[string]inte
 		})
 	}
 
-	// List kube-proxy pods to find unhealthy ones
-	podList, err := t.Clients.Dynamic.Resource(podsGVR).Namespace("kube-system").List(ctx, metav1.ListOptions{
-		LabelSelector: "k8s-app=kube-proxy",
-	})
-	if err == nil {
-		for _, pod := range podList.Items {
-			phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
-			node, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName")
+	findings = append(findings, t.nodeCoverage(ctx, replacement, replacementSelector, restartWindow)...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "kube-system", ""), nil
+}
+
+// kubeProxyMode returns the proxy mode set in a kube-proxy config.conf,
+// defaulting to iptables.
+func kubeProxyMode(configData string) string {
+	if strings.Contains(configData, "mode: ipvs") || strings.Contains(configData, "mode: \"ipvs\"") {
+		return "ipvs"
+	} else if strings.Contains(configData, "mode: nftables") || strings.Contains(configData, "mode: \"nftables\"") {
+		return "nftables"
+	}
+	return "iptables"
+}
+
+// kubeProxyReplacement detects an eBPF dataplane that replaces kube-proxy and
+// returns its name and the label selector of its agent pods.
+func (t *CheckKubeProxyHealthTool) kubeProxyReplacement(ctx context.Context) (string, string) {
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace("kube-system").Get(ctx, "cilium-config", metav1.GetOptions{}); err == nil {
+		kpr, _, _ := unstructured.NestedString(cm.Object, "data", "kube-proxy-replacement")
+		if kpr == "true" || kpr == "strict" {
+			return "cilium", "k8s-app=cilium"
+		}
+	}
+	if fc, err := t.Clients.Dynamic.Resource(calicoFelixConfigGVR).Get(ctx, "default", metav1.GetOptions{}); err == nil {
+		if bpf, _, _ := unstructured.NestedBool(fc.Object, "spec", "bpfEnabled"); bpf {
+			return "calico-ebpf", "k8s-app=calico-node"
+		}
+	}
+	return "", ""
+}
+
+// nodeCoverage checks that every node has a healthy kube-proxy (or replacement
+// agent) and reports crash-looping pods and version skew.
+func (t *CheckKubeProxyHealthTool) nodeCoverage(ctx context.Context, replacement, replacementSelector string, restartWindow time.Duration) []types.DiagnosticFinding {
+	nodes, err := t.Clients.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    "Cannot list nodes to verify per-node kube-proxy coverage",
+			Detail:     err.Error(),
+			Suggestion: "Grant the server list on nodes to enable coverage and version skew checks.",
+		}}
+	}
+
+	var proxyPods, replacementPods []corev1.Pod
+	if list, err := t.Clients.Clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=kube-proxy"}); err == nil {
+		proxyPods = list.Items
+	}
+	if replacementSelector != "" {
+		if list, err := t.Clients.Clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: replacementSelector}); err == nil {
+			replacementPods = list.Items
+		}
+	}
+
+	findings := kubeProxyCoverage(nodes.Items, proxyPods, replacementPods, replacement, restartWindow, time.Now())
+
+	apiServer := ""
+	if v, err := t.Clients.Discovery.ServerVersion(); err == nil {
+		apiServer = v.GitVersion
+	}
+	return append(findings, kubeProxyVersionSkew(apiServer, nodes.Items, proxyPods)...)
+}
+
+// kubeProxyCoverage reports nodes without a ready kube-proxy or replacement
+// agent, and kube-proxy pods that are crash-looping or restarted within window.
+func kubeProxyCoverage(nodes []corev1.Node, proxyPods, replacementPods []corev1.Pod, replacement string, window time.Duration, now time.Time) []types.DiagnosticFinding {
+	proxyByNode := podsByNode(proxyPods)
+	replacementByNode := podsByNode(replacementPods)
+
+	var findings []types.DiagnosticFinding
+	byProxy, byReplacement, uncovered := 0, 0, 0
+	for _, node := range nodes {
+		nodeRef := &types.ResourceRef{Kind: "Node", Name: node.Name}
+		switch {
+		case anyPodReady(proxyByNode[node.Name]):
+			byProxy++
+		case anyPodReady(replacementByNode[node.Name]):
+			byReplacement++
+		default:
+			uncovered++
+			severity := types.SeverityCritical
+			if !nodeReady(node) {
+				severity = types.SeverityWarning
+			}
+			detail := "no kube-proxy pod scheduled"
+			if pods := proxyByNode[node.Name]; len(pods) > 0 {
+				detail = fmt.Sprintf("kube-proxy pod %s phase=%s ready=false", pods[0].Name, pods[0].Status.Phase)
+			}
+			if replacement != "" {
+				detail += fmt.Sprintf("; no ready %s agent", replacement)
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeKubeProxyNodeWithoutProxy,
+				Resource:   nodeRef,
+				Summary:    fmt.Sprintf("Node %s has no healthy kube-proxy: Service ClusterIPs and NodePorts do not work from its pods", node.Name),
+				Detail:     fmt.Sprintf("%s nodeReady=%t", detail, nodeReady(node)),
+				Suggestion: "Check DaemonSet tolerations and nodeSelector against the node's taints and labels, then the pod's events and logs.",
+			})
+		}
+
+		for _, pod := range proxyByNode[node.Name] {
+			restarts, last, crashLooping := recentRestarts(pod, window, now)
+			if !crashLooping && last == nil {
+				continue
+			}
+			severity := types.SeverityWarning
+			summary := fmt.Sprintf("kube-proxy on node %s restarted %d time(s), last within %s", node.Name, restarts, window)
+			if crashLooping {
+				severity = types.SeverityCritical
+				summary = fmt.Sprintf("kube-proxy on node %s is crash-looping (%d restarts)", node.Name, restarts)
+			}
+			detail := fmt.Sprintf("pod=%s restarts=%d", pod.Name, restarts)
+			if last != nil {
+				detail += fmt.Sprintf(" lastTermination=%s exitCode=%d at=%s", last.Reason, last.ExitCode, last.FinishedAt.UTC().Format(time.RFC3339))
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeKubeProxyRestarts,
+				Resource:   &types.ResourceRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+				Summary:    summary,
+				Detail:     detail,
+				Suggestion: fmt.Sprintf("Inspect the previous container logs: kubectl logs -n %s %s --previous", pod.Namespace, pod.Name),
+			})
+		}
+	}
+
+	if uncovered == 0 && len(nodes) > 0 {
+		summary := fmt.Sprintf("All %d nodes have a healthy kube-proxy", len(nodes))
+		if byReplacement > 0 {
+			summary = fmt.Sprintf("All %d nodes covered: %d by kube-proxy, %d by %s", len(nodes), byProxy, byReplacement, replacement)
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  summary,
+		})
+	}
+	return findings
+}
+
+// kubeProxyVersionSkew compares kube-proxy versions across nodes, against the
+// API server (kube-proxy may not be newer, nor more than three minors older)
+// and against each node's kubelet (at most three minors apart).
+func kubeProxyVersionSkew(apiServer string, nodes []corev1.Node, proxyPods []corev1.Pod) []types.DiagnosticFinding {
+	kubelets := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		kubelets[n.Name] = n.Status.NodeInfo.KubeletVersion
+	}
+
+	nodesByVersion := make(map[string][]string)
+	for _, pod := range proxyPods {
+		if v := kubeProxyPodVersion(pod); v != "" && pod.Spec.NodeName != "" {
+			nodesByVersion[v] = append(nodesByVersion[v], pod.Spec.NodeName)
+		}
+	}
+	if len(nodesByVersion) == 0 {
+		return nil
+	}
+	versions := make([]string, 0, len(nodesByVersion))
+	for v := range nodesByVersion {
+		versions = append(versions, v)
+		sort.Strings(nodesByVersion[v])
+	}
+	sort.Strings(versions)
+
+	var findings []types.DiagnosticFinding
+	if len(versions) > 1 {
+		parts := make([]string, 0, len(versions))
+		for _, v := range versions {
+			parts = append(parts, fmt.Sprintf("%s on %d node(s)", v, len(nodesByVersion[v])))
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeKubeProxyVersionDrift,
+			Resource:   &types.ResourceRef{Kind: "DaemonSet", Namespace: "kube-system", Name: "kube-proxy", APIVersion: "apps/v1"},
+			Summary:    fmt.Sprintf("kube-proxy versions differ across nodes: %s", strings.Join(parts, ", ")),
+			Detail:     kubeProxyNodesDetail(versions, nodesByVersion),
+			Suggestion: "Finish the rollout or upgrade so every node runs the same kube-proxy; mixed versions can program Services differently.",
+		})
+	}
 
-			if phase != "Running" {
+	api, apiErr := version.ParseGeneric(apiServer)
+	for _, v := range versions {
+		pv, err := version.ParseGeneric(v)
+		if err != nil {
+			continue
+		}
+		if apiErr == nil && pv.Major() == api.Major() {
+			switch {
+			case pv.Minor() > api.Minor():
 				findings = append(findings, types.DiagnosticFinding{
-					Severity:   types.SeverityWarning,
+					Severity:   types.SeverityCritical,
 					Category:   types.CategoryConnectivity,
-					Resource:   &types.ResourceRef{Kind: "Pod", Namespace: "kube-system", Name: pod.GetName()},
-					Summary:    fmt.Sprintf("kube-proxy pod %s on node %s is %s", pod.GetName(), node, phase),
-					Detail:     fmt.Sprintf("phase=%s node=%s", phase, node),
-					Suggestion: "Check pod events and logs for this kube-proxy instance.",
+					Code:       types.CodeKubeProxyVersionSkew,
+					Summary:    fmt.Sprintf("kube-proxy %s is newer than the API server %s", v, apiServer),
+					Detail:     "nodes=" + strings.Join(nodesByVersion[v], ","),
+					Suggestion: "kube-proxy must not be newer than kube-apiserver. Upgrade the control plane first.",
 				})
+			case api.Minor()-pv.Minor() > 3:
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryConnectivity,
+					Code:       types.CodeKubeProxyVersionSkew,
+					Summary:    fmt.Sprintf("kube-proxy %s is more than three minor versions older than the API server %s", v, apiServer),
+					Detail:     "nodes=" + strings.Join(nodesByVersion[v], ","),
+					Suggestion: "Upgrade kube-proxy; this skew is outside the Kubernetes version skew policy.",
+				})
+			}
+		}
+
+		var skewed []string
+		for _, node := range nodesByVersion[v] {
+			kv, err := version.ParseGeneric(kubelets[node])
+			if err != nil || kv.Major() != pv.Major() {
+				continue
 			}
+			if diff := int(kv.Minor()) - int(pv.Minor()); diff > 3 || diff < -3 {
+				skewed = append(skewed, fmt.Sprintf("%s(kubelet %s)", node, kubelets[node]))
+			}
+		}
+		if len(skewed) > 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeKubeProxyVersionSkew,
+				Summary:    fmt.Sprintf("kube-proxy %s is more than three minor versions from the kubelet on %d node(s)", v, len(skewed)),
+				Detail:     strings.Join(skewed, ", "),
+				Suggestion: "Upgrade kube-proxy and the kubelet together on these nodes.",
+			})
 		}
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, "kube-system", ""), nil
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("kube-proxy %s on all nodes is within the supported skew of API server %s", versions[0], orDefault(apiServer, "unknown")),
+		})
+	}
+	return findings
+}
+
+func kubeProxyNodesDetail(versions []string, nodesByVersion map[string][]string) string {
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		parts = append(parts, fmt.Sprintf("%s=[%s]", v, strings.Join(nodesByVersion[v], ",")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// kubeProxyPodVersion returns the image tag of the kube-proxy container.
+func kubeProxyPodVersion(pod corev1.Pod) string {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "kube-proxy" || len(pod.Spec.Containers) == 1 {
+			return imageTag(c.Image)
+		}
+	}
+	return ""
+}
+
+// recentRestarts returns a pod's total restarts, the last termination when it
+// happened within window, and whether a container is in CrashLoopBackOff.
+func recentRestarts(pod corev1.Pod, window time.Duration, now time.Time) (int32, *corev1.ContainerStateTerminated, bool) {
+	var restarts int32
+	var last *corev1.ContainerStateTerminated
+	crashLooping := false
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		restarts += cs.RestartCount
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			crashLooping = true
+		}
+		if term := cs.LastTerminationState.Terminated; term != nil && now.Sub(term.FinishedAt.Time) <= window {
+			if last == nil || term.FinishedAt.After(last.FinishedAt.Time) {
+				last = term
+			}
+		}
+	}
+	return restarts, last, crashLooping
+}
+
+func podsByNode(pods []corev1.Pod) map[string][]corev1.Pod {
+	out := make(map[string][]corev1.Pod)
+	for _, p := range pods {
+		if p.Spec.NodeName != "" {
+			out[p.Spec.NodeName] = append(out[p.Spec.NodeName], p)
+		}
+	}
+	return out
+}
+
+func anyPodReady(pods []corev1.Pod) bool {
+	for _, p := range pods {
+		if p.Status.Phase != corev1.PodRunning || p.DeletionTimestamp != nil {
+			continue
+		}
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeReady(node corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
 }
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testNode(name, kubelet string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: kubelet},
+		},
+	}
+}
+
+func testProxyPod(node, image string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy-" + node, Namespace: "kube-system"},
+		Spec: corev1.PodSpec{
+			NodeName:   node,
+			Containers: []corev1.Container{{Name: "kube-proxy", Image: image}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestKubeProxyCoverage(t *testing.T) {
+	now := time.Now()
+	nodes := []corev1.Node{testNode("a", "v1.30.2"), testNode("b", "v1.30.2"), testNode("c", "v1.30.2")}
+
+	crashing := testProxyPod("b", "registry.k8s.io/kube-proxy:v1.30.2", false)
+	crashing.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         "kube-proxy",
+		RestartCount: 7,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-2 * time.Minute)),
+		}},
+	}}
+	proxies := []corev1.Pod{testProxyPod("a", "registry.k8s.io/kube-proxy:v1.30.2", true), crashing}
+	cilium := []corev1.Pod{testProxyPod("c", "quay.io/cilium/cilium:v1.16.0", true)}
+
+	findings := kubeProxyCoverage(nodes, proxies, cilium, "cilium", time.Hour, now)
+	if countSeverity(findings, types.SeverityCritical) != 2 {
+		t.Fatalf("expected node b uncovered and its pod crash-looping, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Resource != nil && f.Resource.Name == "c" {
+			t.Errorf("node c is covered by cilium, got %+v", f)
+		}
+	}
+
+	proxies[1] = testProxyPod("b", "registry.k8s.io/kube-proxy:v1.30.2", true)
+	findings = kubeProxyCoverage(nodes, proxies, cilium, "cilium", time.Hour, now)
+	if len(findings) != 1 || !strings.Contains(findings[0].Summary, "2 by kube-proxy, 1 by cilium") {
+		t.Errorf("expected full coverage, got %+v", findings)
+	}
+}
+
+func TestKubeProxyVersionSkew(t *testing.T) {
+	nodes := []corev1.Node{testNode("a", "v1.30.2"), testNode("b", "v1.26.1")}
+	proxies := []corev1.Pod{
+		testProxyPod("a", "registry.k8s.io/kube-proxy:v1.31.0", true),
+		testProxyPod("b", "registry.k8s.io/kube-proxy:v1.26.1", true),
+	}
+	findings := kubeProxyVersionSkew("v1.30.4", nodes, proxies)
+
+	want := []string{"versions differ across nodes", "v1.31.0 is newer than the API server", "v1.26.1 is more than three minor versions older"}
+	for _, w := range want {
+		found := false
+		for _, f := range findings {
+			found = found || strings.Contains(f.Summary, w)
+		}
+		if !found {
+			t.Errorf("expected a finding containing %q, got %+v", w, findings)
+		}
+	}
+
+	ok := kubeProxyVersionSkew("v1.30.4", nodes[:1], []corev1.Pod{testProxyPod("a", "registry.k8s.io/kube-proxy:v1.30.2", true)})
+	if len(ok) != 1 || ok[0].Severity != types.SeverityOK {
+		t.Errorf("expected a single ok finding, got %+v", ok)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
@@ -17,7 +16,7 @@ type ListEndpointsTool struct{ BaseTool }
 func (t *ListEndpointsTool) Name() string        { return "list_endpoints" }
 func (t *ListEndpointsTool) Description() string  { return "List endpoints with ready/not-ready address counts" }
 func (t *ListEndpointsTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -25,20 +24,18 @@ func (t *ListEndpointsTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListEndpointsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(endpointsGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(endpointsGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResourcePage(ctx, endpointsGVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, fmt.Errorf("failed to list endpoints: %w", err)
 	}
 
@@ -59,8 +56,10 @@ func (t *ListEndpointsTool) Run(ctx context.Context, args map[string]interface{}
 		}
 
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if readyCount == 0 && notReadyCount > 0 {
 			severity = types.SeverityWarning
+			code = types.CodeServiceNoEndpoints
 		} else if readyCount == 0 && notReadyCount == 0 {
 			severity = types.SeverityInfo
 		}
@@ -68,6 +67,7 @@ func (t *ListEndpointsTool) Run(ctx context.Context, args map[string]interface{}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryRouting,
+			Code:     code,
 			Resource: &types.ResourceRef{
 				Kind:      "Endpoints",
 				Namespace: item.GetNamespace(),
@@ -78,5 +78,5 @@ func (t *ListEndpointsTool) Run(ctx context.Context, args map[string]interface{}
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "").WithContinue(list.GetContinue()), nil
 }
@@ -19,9 +19,9 @@ var ingressGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version
 type ListIngressesTool struct{ BaseTool }
 
 func (t *ListIngressesTool) Name() string        { return "list_ingresses" }
-func (t *ListIngressesTool) Description() string  { return "List Ingress resources with hosts, paths, backends, and TLS configuration" }
+func (t *ListIngressesTool) Description() string  { return "List Ingress resources with hosts, paths, backends, and TLS configuration, and validate their annotations against the controller of their IngressClass" }
 func (t *ListIngressesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -29,27 +29,27 @@ func (t *ListIngressesTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListIngressesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(ingressGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(ingressGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResourcePage(ctx, ingressGVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, fmt.Errorf("failed to list ingresses: %w", err)
 	}
 
+	classes := t.ingressClassIndex(ctx)
 	findings := make([]types.DiagnosticFinding, 0, len(list.Items))
 	for _, item := range list.Items {
 		hosts, paths, hasTLS := summarizeIngressRules(&item)
 		ingressClass, _, _ := unstructured.NestedString(item.Object, "spec", "ingressClassName")
+		_, controller, classFindings := classes.controllerOf(item)
 
 		tlsStr := "none"
 		if hasTLS {
@@ -69,9 +69,11 @@ func (t *ListIngressesTool) Run(ctx context.Context, args map[string]interface{}
 				item.GetNamespace(), item.GetName(), strings.Join(hosts, ","), len(paths), tlsStr, ingressClass),
 			Detail: fmt.Sprintf("hosts=%v paths=%v ingressClassName=%s tls=%v", hosts, paths, ingressClass, hasTLS),
 		})
+		findings = append(findings, classFindings...)
+		findings = append(findings, ingressAnnotationFindings(item, controller)...)
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_ingress ---
@@ -79,7 +81,7 @@ func (t *ListIngressesTool) Run(ctx context.Context, args map[string]interface{}
 type GetIngressTool struct{ BaseTool }
 
 func (t *GetIngressTool) Name() string        { return "get_ingress" }
-func (t *GetIngressTool) Description() string  { return "Get full Ingress spec with rules, TLS settings, status, and backend validation" }
+func (t *GetIngressTool) Description() string  { return "Get full Ingress spec with rules, TLS settings, status, backend validation, and annotation validation for the controller of its IngressClass" }
 func (t *GetIngressTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
@@ -121,6 +123,24 @@ func (t *GetIngressTool) Run(ctx context.Context, args map[string]interface{}) (
 		Detail:   fmt.Sprintf("ingressClassName=%s hosts=%v", ingressClass, hosts),
 	})
 
+	// Controller and annotation validation
+	class, controller, classFindings := t.ingressClassIndex(ctx).controllerOf(*ing)
+	if controller != "" {
+		validated := "annotations are not validated for this controller"
+		if set, ok := ingressAnnotationSets[controller]; ok {
+			validated = "annotations validated against " + set.name
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("controller: %s (IngressClass %s)", controller, class),
+			Detail:   validated,
+		})
+	}
+	findings = append(findings, classFindings...)
+	findings = append(findings, ingressAnnotationFindings(*ing, controller)...)
+
 	// TLS info
 	tlsSlice, _, _ := unstructured.NestedSlice(ing.Object, "spec", "tls")
 	for _, tls := range tlsSlice {
@@ -183,6 +203,7 @@ func (t *GetIngressTool) Run(ctx context.Context, args map[string]interface{}) (
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryRouting,
+						Code:       types.CodeServiceIngressBackendMissing,
 						Resource:   ref,
 						Summary:    fmt.Sprintf("backend service %s/%s not found", ns, svcName),
 						Detail:     fmt.Sprintf("referencedService=%s error=%v", svcName, svcErr),
@@ -3,16 +3,22 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
-var networkPoliciesGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+var (
+	networkPoliciesGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+	namespacesGVR      = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
 
 // --- list_networkpolicies ---
 
@@ -21,7 +27,7 @@ type ListNetworkPoliciesTool struct{ BaseTool }
 func (t *ListNetworkPoliciesTool) Name() string        { return "list_networkpolicies" }
 func (t *ListNetworkPoliciesTool) Description() string  { return "List NetworkPolicies with podSelector and rule counts" }
 func (t *ListNetworkPoliciesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -29,20 +35,18 @@ func (t *ListNetworkPoliciesTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListNetworkPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(networkPoliciesGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(networkPoliciesGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResourcePage(ctx, networkPoliciesGVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, fmt.Errorf("failed to list network policies: %w", err)
 	}
 
@@ -54,6 +58,7 @@ func (t *ListNetworkPoliciesTool) Run(ctx context.Context, args map[string]inter
 		policyTypes, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "policyTypes")
 
 		severity := types.SeverityInfo
+		var code types.FindingCode
 		suggestion := ""
 
 		// Detect block-all-ingress: has Ingress policyType but 0 ingress rules
@@ -65,6 +70,7 @@ func (t *ListNetworkPoliciesTool) Run(ctx context.Context, args map[string]inter
 		}
 		if hasIngressType && len(ingress) == 0 {
 			severity = types.SeverityWarning
+			code = types.CodeNetworkPolicyDenyAllIngress
 			suggestion = "This policy blocks ALL ingress traffic for selected pods. Verify this is intentional."
 		}
 
@@ -74,6 +80,7 @@ func (t *ListNetworkPoliciesTool) Run(ctx context.Context, args map[string]inter
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryPolicy,
+			Code:     code,
 			Resource: &types.ResourceRef{
 				Kind:       "NetworkPolicy",
 				Namespace:  item.GetNamespace(),
@@ -86,7 +93,7 @@ func (t *ListNetworkPoliciesTool) Run(ctx context.Context, args map[string]inter
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_networkpolicy ---
@@ -184,6 +191,7 @@ func (t *GetNetworkPolicyTool) Run(ctx context.Context, args map[string]interfac
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryPolicy,
+			Code:       types.CodeNetworkPolicyDenyAllIngress,
 			Resource:   ref,
 			Summary:    "policy blocks ALL ingress traffic for selected pods",
 			Suggestion: "This policy has policyType=Ingress but no ingress rules, which blocks all incoming traffic. Add ingress rules to allow specific traffic.",
@@ -201,6 +209,7 @@ func formatSelector(labels map[string]string) string {
 	for k, v := range labels {
 		parts = append(parts, k+"="+v)
 	}
+	sort.Strings(parts)
 	return strings.Join(parts, ",")
 }
 
@@ -243,3 +252,346 @@ func describeNetpolRule(rule map[string]interface{}, direction string, index int
 
 	return strings.Join(parts, " ")
 }
+
+// --- check_networkpolicy_ports ---
+
+type CheckNetworkPolicyPortsTool struct{ BaseTool }
+
+func (t *CheckNetworkPolicyPortsTool) Name() string { return "check_networkpolicy_ports" }
+func (t *CheckNetworkPolicyPortsTool) Description() string {
+	return "Resolve named ports in NetworkPolicy rules against the container ports of the pods they apply to, flagging names no selected pod defines (the rule silently matches nothing) and TCP/UDP protocol mismatches"
+}
+func (t *CheckNetworkPolicyPortsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the NetworkPolicies to check (empty for all namespaces)",
+			},
+		},
+	}
+}
+
+func (t *CheckNetworkPolicyPortsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	policies, err := t.listResource(ctx, networkPoliciesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+	// Egress peers may select pods in any namespace, so resolve against all pods.
+	podList, err := t.listResource(ctx, podsGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	nsList, err := t.listResource(ctx, namespacesGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	pods := make([]podPorts, 0, len(podList.Items))
+	for i := range podList.Items {
+		phase, _, _ := unstructured.NestedString(podList.Items[i].Object, "status", "phase")
+		if phase == "Succeeded" || phase == "Failed" {
+			continue
+		}
+		pods = append(pods, podPortsFrom(&podList.Items[i]))
+	}
+	nsLabels := make(map[string]map[string]string, len(nsList.Items))
+	for _, item := range nsList.Items {
+		nsLabels[item.GetName()] = item.GetLabels()
+	}
+
+	var findings []types.DiagnosticFinding
+	checked := 0
+	for i := range policies.Items {
+		f, named := checkPolicyNamedPorts(&policies.Items[i], pods, nsLabels)
+		if named > 0 {
+			checked++
+		}
+		findings = append(findings, f...)
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("All named ports resolve (%d of %d NetworkPolicies use named ports)", checked, len(policies.Items)),
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// containerPort is a port declared by a pod container.
+type containerPort struct {
+	Name     string
+	Port     int64
+	Protocol string
+}
+
+// podPorts is the subset of a pod needed to evaluate NetworkPolicies and
+// resolve their named ports.
+type podPorts struct {
+	Namespace string
+	Name      string
+	Labels    map[string]string
+	Ports     []containerPort
+	IP        string
+}
+
+func podPortsFrom(pod *unstructured.Unstructured) podPorts {
+	p := podPorts{Namespace: pod.GetNamespace(), Name: pod.GetName(), Labels: pod.GetLabels()}
+	p.IP, _, _ = unstructured.NestedString(pod.Object, "status", "podIP")
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	for _, c := range containers {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ports, _, _ := unstructured.NestedSlice(cm, "ports")
+		for _, port := range ports {
+			pm, ok := port.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(pm, "name")
+			number, _, _ := unstructured.NestedInt64(pm, "containerPort")
+			proto, _, _ := unstructured.NestedString(pm, "protocol")
+			p.Ports = append(p.Ports, containerPort{Name: name, Port: number, Protocol: orDefault(proto, "TCP")})
+		}
+	}
+	return p
+}
+
+// parseLabelSelector converts an unstructured metav1.LabelSelector. A missing
+// selector selects nothing; an empty one selects everything.
+func parseLabelSelector(obj map[string]interface{}, present bool) (labels.Selector, error) {
+	if !present {
+		return labels.Nothing(), nil
+	}
+	var sel metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &sel); err != nil {
+		return nil, err
+	}
+	return metav1.LabelSelectorAsSelector(&sel)
+}
+
+// selectPods returns pods in namespaces matching nsSel whose labels match podSel.
+func selectPods(pods []podPorts, podSel labels.Selector, nsMatch func(string) bool) []podPorts {
+	var out []podPorts
+	for _, p := range pods {
+		if nsMatch(p.Namespace) && podSel.Matches(labels.Set(p.Labels)) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// egressDestinations returns the pods an egress rule's "to" peers select and
+// whether any peer is an ipBlock. An empty "to" allows every destination.
+func egressDestinations(rule map[string]interface{}, policyNs string, pods []podPorts, nsLabels map[string]map[string]string) ([]podPorts, bool, error) {
+	peers, _, _ := unstructured.NestedSlice(rule, "to")
+	if len(peers) == 0 {
+		return pods, false, nil
+	}
+
+	seen := make(map[string]bool)
+	var out []podPorts
+	ipBlock := false
+	for _, peer := range peers {
+		pm, ok := peer.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := pm["ipBlock"]; ok {
+			ipBlock = true
+			continue
+		}
+		podSelObj, hasPodSel, _ := unstructured.NestedMap(pm, "podSelector")
+		nsSelObj, hasNsSel, _ := unstructured.NestedMap(pm, "namespaceSelector")
+
+		podSel := labels.Everything()
+		if hasPodSel {
+			sel, err := parseLabelSelector(podSelObj, true)
+			if err != nil {
+				return nil, false, err
+			}
+			podSel = sel
+		}
+		nsMatch := func(ns string) bool { return ns == policyNs }
+		if hasNsSel {
+			nsSel, err := parseLabelSelector(nsSelObj, true)
+			if err != nil {
+				return nil, false, err
+			}
+			nsMatch = func(ns string) bool {
+				l, ok := nsLabels[ns]
+				return ok && nsSel.Matches(labels.Set(l))
+			}
+		}
+		for _, p := range selectPods(pods, podSel, nsMatch) {
+			if key := p.Namespace + "/" + p.Name; !seen[key] {
+				seen[key] = true
+				out = append(out, p)
+			}
+		}
+	}
+	return out, ipBlock, nil
+}
+
+// checkPolicyNamedPorts validates the named ports of one NetworkPolicy. Ingress
+// named ports resolve against the policy's own pods; egress named ports against
+// the destination pods. It returns the findings and how many named ports the
+// policy uses.
+func checkPolicyNamedPorts(policy *unstructured.Unstructured, pods []podPorts, nsLabels map[string]map[string]string) ([]types.DiagnosticFinding, int) {
+	policyNs := policy.GetNamespace()
+	ref := &types.ResourceRef{Kind: "NetworkPolicy", Namespace: policyNs, Name: policy.GetName(), APIVersion: "networking.k8s.io/v1"}
+
+	var findings []types.DiagnosticFinding
+	invalid := func(err error) []types.DiagnosticFinding {
+		return append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryPolicy,
+			Code:     types.CodeNetworkPolicyInvalidSelector,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s/%s has an invalid label selector", policyNs, policy.GetName()),
+			Detail:   err.Error(),
+		})
+	}
+
+	podSelObj, hasPodSel, _ := unstructured.NestedMap(policy.Object, "spec", "podSelector")
+	if !hasPodSel {
+		podSelObj, hasPodSel = map[string]interface{}{}, true
+	}
+	targetSel, err := parseLabelSelector(podSelObj, hasPodSel)
+	if err != nil {
+		return invalid(err), 0
+	}
+	targets := selectPods(pods, targetSel, func(ns string) bool { return ns == policyNs })
+
+	named := 0
+	for _, direction := range []string{"ingress", "egress"} {
+		rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", direction)
+		for i, r := range rules {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ports, _, _ := unstructured.NestedSlice(rm, "ports")
+			var names []containerPort
+			for _, port := range ports {
+				pm, ok := port.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if isPortName(pm["port"]) {
+					name := pm["port"].(string)
+					proto, _ := pm["protocol"].(string)
+					names = append(names, containerPort{Name: name, Protocol: orDefault(proto, "TCP")})
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+			named += len(names)
+
+			candidates, resolvedAgainst := targets, "selected pods"
+			if direction == "egress" {
+				dests, ipBlock, err := egressDestinations(rm, policyNs, pods, nsLabels)
+				if err != nil {
+					return invalid(err), named
+				}
+				candidates, resolvedAgainst = dests, "destination pods"
+				if ipBlock {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityInfo,
+						Category:   types.CategoryPolicy,
+						Resource:   ref,
+						Summary:    fmt.Sprintf("%s/%s egress rule[%d] combines named ports with an ipBlock peer", policyNs, policy.GetName(), i),
+						Detail:     "Named ports only resolve against pods, so they never match traffic to addresses outside the cluster.",
+						Suggestion: "Use numeric ports for ipBlock destinations.",
+					})
+				}
+			}
+
+			for _, np := range names {
+				findings = append(findings, resolveNamedPort(ref, direction, i, np, candidates, resolvedAgainst)...)
+			}
+		}
+	}
+	return findings, named
+}
+
+// resolveNamedPort reports when a named port matches no candidate pod.
+func resolveNamedPort(ref *types.ResourceRef, direction string, index int, np containerPort, candidates []podPorts, resolvedAgainst string) []types.DiagnosticFinding {
+	prefix := fmt.Sprintf("%s/%s %s rule[%d] port %q/%s", ref.Namespace, ref.Name, direction, index, np.Name, np.Protocol)
+	if len(candidates) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s cannot be resolved: no running %s", prefix, resolvedAgainst),
+		}}
+	}
+
+	matched := 0
+	otherProtos := make(map[string]bool)
+	var missing []string
+	for _, p := range candidates {
+		if _, found := resolvePort(np.Name, np.Protocol, p); found {
+			matched++
+			continue
+		}
+		missing = append(missing, p.Namespace+"/"+p.Name)
+		for _, proto := range declaredProtocols(np.Name, p) {
+			otherProtos[proto] = true
+		}
+	}
+
+	switch {
+	case matched == 0 && len(otherProtos) > 0:
+		protos := make([]string, 0, len(otherProtos))
+		for p := range otherProtos {
+			protos = append(protos, p)
+		}
+		sort.Strings(protos)
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeNetworkPolicyPortProtocolMismatch,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s: %s declare %q only with protocol %s", prefix, resolvedAgainst, np.Name, strings.Join(protos, ",")),
+			Detail:     fmt.Sprintf("A named port matches only when name and protocol both match, so this rule allows no traffic on %q.", np.Name),
+			Suggestion: fmt.Sprintf("Set protocol: %s on the policy port, or fix the containerPort protocol.", protos[0]),
+		}}
+	case matched == 0:
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeNetworkPolicyNamedPortUndefined,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s is not defined by any of %d %s; the rule silently matches nothing", prefix, len(candidates), resolvedAgainst),
+			Detail:     fmt.Sprintf("pods without the port: %s", truncateList(missing, 5)),
+			Suggestion: "Name the containerPort in the pod spec or use the numeric port in the policy.",
+		}}
+	case len(missing) > 0:
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s is defined by %d of %d %s", prefix, matched, len(candidates), resolvedAgainst),
+			Detail:   fmt.Sprintf("pods without the port: %s", truncateList(missing, 5)),
+		}}
+	}
+	return nil
+}
+
+// truncateList joins up to limit items, noting how many were omitted.
+func truncateList(items []string, limit int) string {
+	if len(items) <= limit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:limit], ", "), len(items)-limit)
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testPolicy(ns string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	u.SetNamespace(ns)
+	u.SetName("np")
+	return u
+}
+
+func TestCheckPolicyNamedPorts(t *testing.T) {
+	pods := []podPorts{
+		{Namespace: "shop", Name: "web-1", Labels: map[string]string{"app": "web"},
+			Ports: []containerPort{{Name: "http", Port: 8080, Protocol: "TCP"}}},
+		{Namespace: "shop", Name: "dns-1", Labels: map[string]string{"app": "dns"},
+			Ports: []containerPort{{Name: "dns", Port: 53, Protocol: "UDP"}}},
+	}
+	ingress := func(port string, proto string) map[string]interface{} {
+		p := map[string]interface{}{"port": port}
+		if proto != "" {
+			p["protocol"] = proto
+		}
+		return map[string]interface{}{"ports": []interface{}{p}}
+	}
+	selector := func(app string) map[string]interface{} {
+		return map[string]interface{}{"matchLabels": map[string]interface{}{"app": app}}
+	}
+
+	tests := []struct {
+		name        string
+		spec        map[string]interface{}
+		wantSummary string // empty = no findings
+	}{
+		{"resolves", map[string]interface{}{"podSelector": selector("web"), "ingress": []interface{}{ingress("http", "")}}, ""},
+		{"missing name", map[string]interface{}{"podSelector": selector("web"), "ingress": []interface{}{ingress("metrics", "")}}, "silently matches nothing"},
+		{"protocol mismatch", map[string]interface{}{"podSelector": selector("dns"), "ingress": []interface{}{ingress("dns", "")}}, "only with protocol UDP"},
+		{"egress to selected pods", map[string]interface{}{
+			"podSelector": map[string]interface{}{},
+			"egress": []interface{}{map[string]interface{}{
+				"to":    []interface{}{map[string]interface{}{"podSelector": selector("dns")}},
+				"ports": []interface{}{map[string]interface{}{"port": "dns", "protocol": "UDP"}},
+			}},
+		}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, named := checkPolicyNamedPorts(testPolicy("shop", tc.spec), pods, map[string]map[string]string{"shop": {}})
+			if named != 1 {
+				t.Errorf("named = %d, want 1", named)
+			}
+			if tc.wantSummary == "" {
+				if len(findings) != 0 {
+					t.Errorf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Severity != types.SeverityWarning || !strings.Contains(findings[0].Summary, tc.wantSummary) {
+				t.Errorf("expected one warning containing %q, got %+v", tc.wantSummary, findings)
+			}
+		})
+	}
+}
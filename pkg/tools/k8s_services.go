@@ -23,7 +23,7 @@ type ListServicesTool struct{ BaseTool }
 func (t *ListServicesTool) Name() string        { return "list_services" }
 func (t *ListServicesTool) Description() string  { return "List Kubernetes services with type, clusterIP, ports, and selector" }
 func (t *ListServicesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -31,20 +31,18 @@ func (t *ListServicesTool) InputSchema() map[string]interface{} {
 				"description": "Kubernetes namespace (empty for all namespaces)",
 			},
 		},
-	}
+	}))
 }
 
 func (t *ListServicesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	page := getPageArgs(args)
 
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(servicesGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResourcePage(ctx, servicesGVR, ns, page)
 	if err != nil {
+		if perr := listArgError(t.Name(), err); perr != nil {
+			return nil, perr
+		}
 		return nil, fmt.Errorf("failed to list services: %w", err)
 	}
 
@@ -82,7 +80,7 @@ func (t *ListServicesTool) Run(ctx context.Context, args map[string]interface{})
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "").WithContinue(list.GetContinue()), nil
 }
 
 // --- get_service ---
@@ -159,12 +157,15 @@ func (t *GetServiceTool) Run(ctx context.Context, args map[string]interface{}) (
 		}
 
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if readyCount == 0 {
 			severity = types.SeverityWarning
+			code = types.CodeServiceNoEndpoints
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryRouting,
+			Code:     code,
 			Resource: &types.ResourceRef{Kind: "Endpoints", Namespace: ns, Name: name},
 			Summary:  fmt.Sprintf("endpoints: %d ready, %d not-ready", readyCount, notReadyCount),
 			Detail:   fmt.Sprintf("readyAddresses=%d notReadyAddresses=%d", readyCount, notReadyCount),
@@ -188,6 +189,7 @@ func (t *GetServiceTool) Run(ctx context.Context, args map[string]interface{}) (
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryRouting,
+					Code:       types.CodeServiceSelectorNoPods,
 					Resource:   ref,
 					Summary:    fmt.Sprintf("service %s/%s selector matches no pods", ns, name),
 					Detail:     fmt.Sprintf("selector=%v matched 0 pods", selector),
@@ -0,0 +1,358 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// excludeFromLBLabel removes a node from cloud load balancer target pools.
+const excludeFromLBLabel = "node.kubernetes.io/exclude-from-external-load-balancers"
+
+// AWS load balancer annotations that override the health check the
+// controller derives from healthCheckNodePort.
+const (
+	awsHealthCheckPortAnnotation     = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-port"
+	awsHealthCheckProtocolAnnotation = "service.beta.kubernetes.io/aws-load-balancer-healthcheck-protocol"
+)
+
+// namespaceMesh returns the mesh that enrolls a namespace's pods, or "".
+func namespaceMesh(ns corev1.Namespace) string {
+	l := ns.Labels
+	switch {
+	case l["istio.io/dataplane-mode"] == "ambient":
+		return "istio-ambient"
+	case l["istio-injection"] == "enabled", l["istio.io/rev"] != "" && l["istio-injection"] != "disabled":
+		return "istio"
+	case ns.Annotations["linkerd.io/inject"] == "enabled":
+		return "linkerd"
+	}
+	return ""
+}
+
+// readyEndpointNodes counts ready endpoint addresses per node. Addresses
+// without a nodeName are counted under "".
+func readyEndpointNodes(ep *corev1.Endpoints) map[string]int {
+	out := make(map[string]int)
+	if ep == nil {
+		return out
+	}
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			node := ""
+			if a.NodeName != nil {
+				node = *a.NodeName
+			}
+			out[node]++
+		}
+	}
+	return out
+}
+
+// nodesWithoutEndpoint splits candidate nodes into those with and without a
+// local ready endpoint.
+func nodesWithoutEndpoint(nodes []corev1.Node, endpoints map[string]int, include func(corev1.Node) bool) (covered int, missing []string) {
+	for _, n := range nodes {
+		if !nodeReady(n) || !include(n) {
+			continue
+		}
+		if endpoints[n.Name] > 0 {
+			covered++
+		} else {
+			missing = append(missing, n.Name)
+		}
+	}
+	sort.Strings(missing)
+	return covered, missing
+}
+
+// localTrafficPolicy reports whether a Service keeps in-cluster or external
+// traffic on the receiving node. externalTrafficPolicy only applies to
+// NodePort and LoadBalancer Services.
+func localTrafficPolicy(svc *corev1.Service) (internal, external bool) {
+	internal = svc.Spec.InternalTrafficPolicy != nil && *svc.Spec.InternalTrafficPolicy == corev1.ServiceInternalTrafficPolicyLocal
+	external = svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal &&
+		(svc.Spec.Type == corev1.ServiceTypeLoadBalancer || svc.Spec.Type == corev1.ServiceTypeNodePort)
+	return internal, external
+}
+
+// analyzeTrafficPolicy checks a Service with internalTrafficPolicy or
+// externalTrafficPolicy set to Local: every node that can receive its traffic
+// needs a local ready endpoint, since kube-proxy drops rather than falls back
+// to a remote one. meshes lists the meshes enrolling client namespaces.
+func analyzeTrafficPolicy(svc *corev1.Service, endpoints map[string]int, nodes []corev1.Node, meshes []string) []types.DiagnosticFinding {
+	internalLocal, externalLocal := localTrafficPolicy(svc)
+	if !internalLocal && !externalLocal {
+		return nil
+	}
+
+	key := svc.Namespace + "/" + svc.Name
+	ref := &types.ResourceRef{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name, APIVersion: "v1"}
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, sev, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryConnectivity, Code: code, Resource: ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+
+	var endpointNodes []string
+	total := 0
+	for n, c := range endpoints {
+		total += c
+		if n != "" {
+			endpointNodes = append(endpointNodes, n)
+		}
+	}
+	sort.Strings(endpointNodes)
+	if total == 0 {
+		add(types.CodeServiceLocalPolicyNoEndpoints, types.SeverityCritical, fmt.Sprintf("Service %s uses a Local traffic policy and has no ready endpoints", key),
+			fmt.Sprintf("internalTrafficPolicy=Local:%t externalTrafficPolicy=Local:%t", internalLocal, externalLocal),
+			"Check the Service selector and pod readiness.")
+		return findings
+	}
+	onNodes := fmt.Sprintf("ready endpoints on: %s", truncateList(endpointNodes, 5))
+
+	if internalLocal {
+		covered, missing := nodesWithoutEndpoint(nodes, endpoints, func(corev1.Node) bool { return true })
+		if len(missing) == 0 {
+			add("", types.SeverityOK, fmt.Sprintf("Service %s internalTrafficPolicy=Local: all %d ready nodes have a local endpoint", key, covered), onNodes, "")
+		} else {
+			add(types.CodeServiceLocalPolicyNodesWithoutEndpoint, types.SeverityWarning,
+				fmt.Sprintf("Service %s internalTrafficPolicy=Local: in-cluster clients on %d of %d nodes have no local endpoint and are dropped", key, len(missing), covered+len(missing)),
+				fmt.Sprintf("nodes without endpoint: %s; %s", truncateList(missing, 5), onNodes),
+				"Local is meant for per-node agents: run the backend as a DaemonSet, or set internalTrafficPolicy: Cluster.")
+			if len(meshes) > 0 {
+				add(types.CodeServiceLocalPolicyMeshMismatch, types.SeverityWarning,
+					fmt.Sprintf("Service %s internalTrafficPolicy=Local behaves differently for meshed clients (%s)", key, strings.Join(meshes, ", ")),
+					"Mesh proxies (sidecar or ztunnel) capture the connection and pick an endpoint themselves, so meshed clients may still reach remote endpoints while un-meshed clients on the same nodes are silently dropped.",
+					"Test from an un-meshed pod on a node without an endpoint, and use one policy that holds for both paths.")
+			}
+		}
+	}
+
+	if externalLocal {
+		include := func(corev1.Node) bool { return true }
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			include = func(n corev1.Node) bool { _, excluded := n.Labels[excludeFromLBLabel]; return !excluded }
+		}
+		covered, missing := nodesWithoutEndpoint(nodes, endpoints, include)
+		detail := fmt.Sprintf("nodes without endpoint: %s; %s", truncateList(missing, 5), onNodes)
+		switch {
+		case svc.Spec.Type == corev1.ServiceTypeNodePort && len(missing) > 0:
+			add(types.CodeServiceLocalPolicyNodesWithoutEndpoint, types.SeverityWarning,
+				fmt.Sprintf("Service %s externalTrafficPolicy=Local: its NodePorts drop traffic on %d of %d nodes", key, len(missing), covered+len(missing)),
+				detail,
+				"Send external traffic only to nodes running the pods, or use externalTrafficPolicy: Cluster (losing the client source IP).")
+		case svc.Spec.Type == corev1.ServiceTypeLoadBalancer && svc.Spec.HealthCheckNodePort == 0:
+			add(types.CodeServiceLocalPolicyNoHealthCheck, types.SeverityWarning,
+				fmt.Sprintf("Service %s externalTrafficPolicy=Local has no healthCheckNodePort", key),
+				detail,
+				"The load balancer cannot tell which nodes have endpoints and sends traffic to nodes that drop it; recreate the Service or check the cloud controller.")
+		case svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(missing) > 0:
+			add("", types.SeverityInfo,
+				fmt.Sprintf("Service %s externalTrafficPolicy=Local: load balancer health checks on port %d keep %d of %d nodes in rotation", key, svc.Spec.HealthCheckNodePort, covered, covered+len(missing)),
+				detail, "")
+		default:
+			add("", types.SeverityOK, fmt.Sprintf("Service %s externalTrafficPolicy=Local: all %d candidate nodes have a local endpoint", key, covered), onNodes, "")
+		}
+		if len(endpointNodes) == 1 && covered+len(missing) > 1 {
+			add(types.CodeServiceLocalPolicySingleNode, types.SeverityWarning,
+				fmt.Sprintf("Service %s externalTrafficPolicy=Local: all endpoints run on node %s", key, endpointNodes[0]),
+				onNodes,
+				"A single node failure drops all external traffic; spread the pods with topologySpreadConstraints or pod anti-affinity.")
+		}
+	}
+	return findings
+}
+
+// analyzeSessionAffinity checks a Service with sessionAffinity: ClientIP for
+// clients that are not kept on one endpoint or that all look like the same
+// client. meshes lists the meshes enrolling client namespaces.
+func analyzeSessionAffinity(svc *corev1.Service, meshes []string) []types.DiagnosticFinding {
+	if svc.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return nil
+	}
+	key := svc.Namespace + "/" + svc.Name
+	ref := &types.ResourceRef{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name, APIVersion: "v1"}
+	timeout := int32(10800)
+	if cfg := svc.Spec.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil && cfg.ClientIP.TimeoutSeconds != nil {
+		timeout = *cfg.ClientIP.TimeoutSeconds
+	}
+
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning, Category: types.CategoryConnectivity, Code: code, Resource: ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+	if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		add(types.CodeServiceSessionAffinityIneffective,
+			fmt.Sprintf("Service %s is headless, so sessionAffinity=ClientIP has no effect", key),
+			"Clients resolve the pod IPs through DNS and connect to them directly; kube-proxy never sees the connection, so requests are not sticky.",
+			"Give the Service a ClusterIP, or implement stickiness in the client.")
+		return findings
+	}
+	if len(meshes) > 0 {
+		add(types.CodeServiceSessionAffinityIneffective,
+			fmt.Sprintf("Service %s sessionAffinity=ClientIP is ignored for meshed clients (%s)", key, strings.Join(meshes, ", ")),
+			"Mesh proxies pick the endpoint themselves, so requests from meshed pods are balanced across all endpoints while un-meshed clients stay on one.",
+			"For Istio, configure consistentHash load balancing in a DestinationRule; otherwise make the backend stateless.")
+	}
+	external := svc.Spec.Type == corev1.ServiceTypeLoadBalancer || svc.Spec.Type == corev1.ServiceTypeNodePort
+	if external && svc.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+		add(types.CodeServiceSessionAffinityUneven,
+			fmt.Sprintf("Service %s sessionAffinity=ClientIP sees external clients as the node they arrive on", key),
+			"With externalTrafficPolicy=Cluster the client source IP is SNATed to the node IP: all clients entering through one node stick to the same pod, load is uneven, and a client moves to another pod when the load balancer picks another node.",
+			"Use externalTrafficPolicy: Local to keep the client IP, or configure stickiness on the load balancer or ingress.")
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK, Category: types.CategoryConnectivity, Resource: ref,
+			Summary: fmt.Sprintf("Service %s sessionAffinity=ClientIP keeps a client on one endpoint for %ds", key, timeout),
+			Detail:  "Clients behind a shared NAT or proxy count as one client and all land on the same endpoint.",
+		})
+	}
+	return findings
+}
+
+// analyzeHealthCheckPort checks that the load balancer of a Service with
+// externalTrafficPolicy=Local probes the healthCheckNodePort over HTTP:
+// kube-proxy answers there on every node, with 503 on nodes without a local
+// endpoint, so any other port or a TCP check keeps those nodes in rotation.
+func analyzeHealthCheckPort(svc *corev1.Service) []types.DiagnosticFinding {
+	if _, externalLocal := localTrafficPolicy(svc); !externalLocal || svc.Spec.Type != corev1.ServiceTypeLoadBalancer || svc.Spec.HealthCheckNodePort == 0 {
+		return nil
+	}
+	key := svc.Namespace + "/" + svc.Name
+	hc := svc.Spec.HealthCheckNodePort
+	var problems []string
+	if port := svc.Annotations[awsHealthCheckPortAnnotation]; port != "" && port != fmt.Sprint(hc) {
+		problems = append(problems, fmt.Sprintf("%s=%s (healthCheckNodePort is %d)", awsHealthCheckPortAnnotation, port, hc))
+	}
+	if proto := svc.Annotations[awsHealthCheckProtocolAnnotation]; proto != "" && !strings.HasPrefix(strings.ToUpper(proto), "HTTP") {
+		problems = append(problems, fmt.Sprintf("%s=%s", awsHealthCheckProtocolAnnotation, proto))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryConnectivity,
+		Code:       types.CodeServiceHealthCheckPortMismatch,
+		Resource:   &types.ResourceRef{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name, APIVersion: "v1"},
+		Summary:    fmt.Sprintf("Service %s load balancer health check does not use HTTP on healthCheckNodePort %d", key, hc),
+		Detail:     strings.Join(problems, "; ") + ". Nodes without a local endpoint may pass the check and stay in rotation, so a share of new connections times out.",
+		Suggestion: "Remove the health check overrides so the load balancer probes /healthz over HTTP on the healthCheckNodePort.",
+	}}
+}
+
+// --- check_traffic_policy ---
+
+type CheckTrafficPolicyTool struct{ BaseTool }
+
+func (t *CheckTrafficPolicyTool) Name() string { return "check_traffic_policy" }
+func (t *CheckTrafficPolicyTool) Description() string {
+	return "Check Service traffic settings: internalTrafficPolicy or externalTrafficPolicy Local with nodes that have no local ready endpoint, load balancer health checks on the healthCheckNodePort, sessionAffinity that is ignored or pins clients unevenly, and mesh clients that bypass the policy"
+}
+func (t *CheckTrafficPolicyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Service name (requires namespace; empty checks every Service)",
+			},
+		},
+	}
+}
+
+func (t *CheckTrafficPolicyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+
+	var services []corev1.Service
+	if name != "" {
+		if ns == "" {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "namespace is required when name is set",
+			}
+		}
+		svc, err := t.Clients.Clientset.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service %s/%s: %w", ns, name, err)
+		}
+		services = append(services, *svc)
+	} else {
+		list, err := t.Clients.Clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		services = list.Items
+	}
+
+	nodes, err := t.Clients.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var meshes []string
+	if nsList, err := t.Clients.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		seen := make(map[string]bool)
+		for _, n := range nsList.Items {
+			if m := namespaceMesh(n); m != "" && !seen[m] {
+				seen[m] = true
+				meshes = append(meshes, m)
+			}
+		}
+		sort.Strings(meshes)
+	}
+
+	var findings []types.DiagnosticFinding
+	checked := 0
+	for i := range services {
+		svc := &services[i]
+		internal, external := localTrafficPolicy(svc)
+		if !internal && !external && svc.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+			continue
+		}
+		checked++
+		findings = append(findings, analyzeSessionAffinity(svc, meshes)...)
+		if !internal && !external {
+			continue
+		}
+		ep, err := t.Clients.Clientset.CoreV1().Endpoints(svc.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if err != nil {
+			ep = nil
+		}
+		findings = append(findings, analyzeTrafficPolicy(svc, readyEndpointNodes(ep), nodes.Items, meshes)...)
+		findings = append(findings, analyzeHealthCheckPort(svc)...)
+	}
+
+	if checked == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("No Services with a Local traffic policy or session affinity (%d checked)", len(services)),
+		})
+	}
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, ""), nil
+}
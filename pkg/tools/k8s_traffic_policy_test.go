@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestAnalyzeTrafficPolicy_InternalLocal(t *testing.T) {
+	local := corev1.ServiceInternalTrafficPolicyLocal
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "monitoring"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, InternalTrafficPolicy: &local},
+	}
+	nodes := []corev1.Node{testNode("a", "v1.30.2"), testNode("b", "v1.30.2"), testNode("c", "v1.30.2")}
+
+	findings := analyzeTrafficPolicy(svc, map[string]int{"a": 1, "b": 1, "c": 1}, nodes, nil)
+	if len(findings) != 1 || findings[0].Severity != types.SeverityOK {
+		t.Errorf("expected a single ok finding with an endpoint on every node, got %+v", findings)
+	}
+
+	findings = analyzeTrafficPolicy(svc, map[string]int{"a": 2}, nodes, []string{"istio"})
+	if countSeverity(findings, types.SeverityWarning) != 2 || !strings.Contains(findings[0].Summary, "2 of 3 nodes") {
+		t.Errorf("expected missing-node and mesh warnings, got %+v", findings)
+	}
+
+	if f := analyzeTrafficPolicy(svc, map[string]int{}, nodes, nil); len(f) != 1 || f[0].Severity != types.SeverityCritical {
+		t.Errorf("expected critical with no endpoints, got %+v", f)
+	}
+
+	svc.Spec.InternalTrafficPolicy = nil
+	if f := analyzeTrafficPolicy(svc, map[string]int{"a": 1}, nodes, nil); f != nil {
+		t.Errorf("expected no findings for Cluster policy, got %+v", f)
+	}
+}
+
+func TestAnalyzeTrafficPolicy_ExternalLocal(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "edge"},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			HealthCheckNodePort:   31234,
+		},
+	}
+	excluded := testNode("c", "v1.30.2")
+	excluded.Labels = map[string]string{excludeFromLBLabel: ""}
+	nodes := []corev1.Node{testNode("a", "v1.30.2"), testNode("b", "v1.30.2"), excluded}
+
+	findings := analyzeTrafficPolicy(svc, map[string]int{"a": 1}, nodes, nil)
+	if len(findings) != 2 || !strings.Contains(findings[0].Summary, "keep 1 of 2 nodes") || !strings.Contains(findings[1].Summary, "all endpoints run on node a") {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+
+	svc.Spec.Type = corev1.ServiceTypeNodePort
+	findings = analyzeTrafficPolicy(svc, map[string]int{"a": 1, "b": 1}, nodes, nil)
+	if len(findings) != 1 || !strings.Contains(findings[0].Summary, "drop traffic on 1 of 3 nodes") {
+		t.Errorf("expected NodePort drop warning, got %+v", findings)
+	}
+}
+
+func TestAnalyzeSessionAffinity(t *testing.T) {
+	timeout := int32(600)
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeClusterIP,
+			ClusterIP:             "10.96.0.10",
+			SessionAffinity:       corev1.ServiceAffinityClientIP,
+			SessionAffinityConfig: &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout}},
+		},
+	}
+	if f := analyzeSessionAffinity(svc, nil); len(f) != 1 || f[0].Severity != types.SeverityOK || !strings.Contains(f[0].Summary, "600s") {
+		t.Errorf("expected an ok finding with the timeout, got %+v", f)
+	}
+	if f := analyzeSessionAffinity(svc, []string{"istio"}); len(f) != 1 || f[0].Code != types.CodeServiceSessionAffinityIneffective {
+		t.Errorf("expected meshed clients to be reported, got %+v", f)
+	}
+
+	svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	if f := analyzeSessionAffinity(svc, nil); len(f) != 1 || f[0].Code != types.CodeServiceSessionAffinityUneven {
+		t.Errorf("expected SNAT to be reported with externalTrafficPolicy=Cluster, got %+v", f)
+	}
+	svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	if f := analyzeSessionAffinity(svc, nil); len(f) != 1 || f[0].Severity != types.SeverityOK {
+		t.Errorf("expected no warning with externalTrafficPolicy=Local, got %+v", f)
+	}
+
+	svc.Spec.Type = corev1.ServiceTypeClusterIP
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
+	if f := analyzeSessionAffinity(svc, []string{"istio"}); len(f) != 1 || !strings.Contains(f[0].Summary, "headless") {
+		t.Errorf("expected a single headless finding, got %+v", f)
+	}
+
+	svc.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	if f := analyzeSessionAffinity(svc, nil); f != nil {
+		t.Errorf("expected no findings without affinity, got %+v", f)
+	}
+}
+
+func TestAnalyzeHealthCheckPort(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "edge"},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			HealthCheckNodePort:   31234,
+		},
+	}
+	if f := analyzeHealthCheckPort(svc); f != nil {
+		t.Errorf("expected no findings without overrides, got %+v", f)
+	}
+
+	svc.Annotations = map[string]string{awsHealthCheckPortAnnotation: "31234", awsHealthCheckProtocolAnnotation: "HTTP"}
+	if f := analyzeHealthCheckPort(svc); f != nil {
+		t.Errorf("expected no findings when the overrides match, got %+v", f)
+	}
+
+	svc.Annotations = map[string]string{awsHealthCheckPortAnnotation: "traffic-port", awsHealthCheckProtocolAnnotation: "TCP"}
+	f := analyzeHealthCheckPort(svc)
+	if len(f) != 1 || f[0].Code != types.CodeServiceHealthCheckPortMismatch || !strings.Contains(f[0].Detail, "traffic-port") || !strings.Contains(f[0].Detail, "=TCP") {
+		t.Errorf("expected the port and protocol overrides to be reported, got %+v", f)
+	}
+}
@@ -18,6 +18,9 @@ var (
 	gatewayParamsGVR = schema.GroupVersionResource{Group: "kgateway.dev", Version: "v1alpha1", Resource: "gatewayparameters"}
 	routeOptionGVR   = schema.GroupVersionResource{Group: "gateway.kgateway.dev", Version: "v1alpha1", Resource: "routeoptions"}
 	vhostOptionGVR   = schema.GroupVersionResource{Group: "gateway.kgateway.dev", Version: "v1alpha1", Resource: "virtualhostoptions"}
+
+	backendGVR            = schema.GroupVersionResource{Group: "gateway.kgateway.dev", Version: "v1alpha1", Resource: "backends"}
+	httpListenerPolicyGVR = schema.GroupVersionResource{Group: "gateway.kgateway.dev", Version: "v1alpha1", Resource: "httplistenerpolicies"}
 )
 
 type kgatewayKindInfo struct {
@@ -29,24 +32,30 @@ var kgatewayKindGVRs = map[string]kgatewayKindInfo{
 	"GatewayParameters":  {gvr: gatewayParamsGVR, apiGroup: "kgateway.dev"},
 	"RouteOption":        {gvr: routeOptionGVR, apiGroup: "gateway.kgateway.dev"},
 	"VirtualHostOption":  {gvr: vhostOptionGVR, apiGroup: "gateway.kgateway.dev"},
+	"Backend":            {gvr: backendGVR, apiGroup: "gateway.kgateway.dev"},
+	"TrafficPolicy":      {gvr: trafficPolicyGVR, apiGroup: "gateway.kgateway.dev"},
+	"HTTPListenerPolicy": {gvr: httpListenerPolicyGVR, apiGroup: "gateway.kgateway.dev"},
 }
 
+// kgatewayKinds lists the kinds of kgatewayKindGVRs in a stable order.
+var kgatewayKinds = []string{"GatewayParameters", "RouteOption", "VirtualHostOption", "Backend", "TrafficPolicy", "HTTPListenerPolicy"}
+
 // --- list_kgateway_resources ---
 
 type ListKgatewayResourcesTool struct{ BaseTool }
 
 func (t *ListKgatewayResourcesTool) Name() string { return "list_kgateway_resources" }
 func (t *ListKgatewayResourcesTool) Description() string {
-	return "List kgateway resources (GatewayParameters, RouteOption, VirtualHostOption) with key summary fields"
+	return "List kgateway resources (GatewayParameters, RouteOption, VirtualHostOption, Backend, TrafficPolicy, HTTPListenerPolicy) with key summary fields"
 }
 func (t *ListKgatewayResourcesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"kind": map[string]interface{}{
 				"type":        "string",
-				"description": "Resource kind: GatewayParameters, RouteOption, VirtualHostOption",
-				"enum":        []string{"GatewayParameters", "RouteOption", "VirtualHostOption"},
+				"description": "Resource kind: GatewayParameters, RouteOption, VirtualHostOption, Backend, TrafficPolicy, HTTPListenerPolicy",
+				"enum":        kgatewayKinds,
 			},
 			"namespace": map[string]interface{}{
 				"type":        "string",
@@ -54,7 +63,7 @@ func (t *ListKgatewayResourcesTool) InputSchema() map[string]interface{} {
 			},
 		},
 		"required": []string{"kind"},
-	}
+	})
 }
 
 func (t *ListKgatewayResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
@@ -70,14 +79,11 @@ func (t *ListKgatewayResourcesTool) Run(ctx context.Context, args map[string]int
 		}
 	}
 
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(info.gvr).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(info.gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResourceSelected(ctx, info.gvr, ns, getSelectorArgs(args))
 	if err != nil {
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeCRDNotAvailable,
 			Tool:    t.Name(),
@@ -171,6 +177,37 @@ func kgatewayResourceSummary(kind string, item *unstructured.Unstructured) (stri
 			summary += fmt.Sprintf(" options=[%s]", strings.Join(optionKeys, ", "))
 		}
 		return summary, ""
+
+	case "Backend":
+		// Backend defines a destination outside the cluster's Services
+		summary := fmt.Sprintf("%s/%s type=%s", ns, name, orDefault(kgatewayBackendType(item), "unset"))
+		hosts, _, _ := unstructured.NestedSlice(item.Object, "spec", "static", "hosts")
+		if len(hosts) > 0 {
+			hostPorts := make([]string, 0, len(hosts))
+			for _, h := range hosts {
+				hm, _ := h.(map[string]interface{})
+				host, _ := hm["host"].(string)
+				hostPorts = append(hostPorts, fmt.Sprintf("%s:%d", host, toInt(hm["port"])))
+			}
+			summary += fmt.Sprintf(" hosts=[%s]", strings.Join(hostPorts, ", "))
+		}
+		if fn, _, _ := unstructured.NestedString(item.Object, "spec", "aws", "lambda", "functionName"); fn != "" {
+			summary += " function=" + fn
+		}
+		return summary, ""
+
+	case "TrafficPolicy", "HTTPListenerPolicy":
+		// Policies attach to Gateways, listeners or routes through targetRefs or targetSelectors
+		summary := fmt.Sprintf("%s/%s", ns, name)
+		if targetRef := kgatewayTargetRefSummary(item); targetRef != "" {
+			summary += " " + targetRef
+		} else if _, ok, _ := unstructured.NestedFieldNoCopy(item.Object, "spec", "targetSelectors"); ok {
+			summary += " targetSelectors"
+		}
+		if fields := kgatewayPolicyFields(item); len(fields) > 0 {
+			summary += fmt.Sprintf(" policies=[%s]", strings.Join(fields, ", "))
+		}
+		return summary, ""
 	}
 
 	return fmt.Sprintf("%s/%s", ns, name), ""
@@ -210,7 +247,7 @@ type ValidateKgatewayResourceTool struct{ BaseTool }
 
 func (t *ValidateKgatewayResourceTool) Name() string { return "validate_kgateway_resource" }
 func (t *ValidateKgatewayResourceTool) Description() string {
-	return "Validate kgateway resources: upstream references, route option conflicts, GatewayParameters references, and status conditions"
+	return "Validate kgateway resources: upstream and backend references, Backend hosts, policy targets and attachment conflicts, GatewayParameters references, and status conditions"
 }
 func (t *ValidateKgatewayResourceTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
@@ -218,8 +255,8 @@ func (t *ValidateKgatewayResourceTool) InputSchema() map[string]interface{} {
 		"properties": map[string]interface{}{
 			"kind": map[string]interface{}{
 				"type":        "string",
-				"description": "Resource kind: GatewayParameters, RouteOption, VirtualHostOption",
-				"enum":        []string{"GatewayParameters", "RouteOption", "VirtualHostOption"},
+				"description": "Resource kind: GatewayParameters, RouteOption, VirtualHostOption, Backend, TrafficPolicy, HTTPListenerPolicy",
+				"enum":        kgatewayKinds,
 			},
 			"name": map[string]interface{}{
 				"type":        "string",
@@ -289,46 +326,114 @@ func (t *ValidateKgatewayResourceTool) Run(ctx context.Context, args map[string]
 		findings = append(findings, t.validateRouteOption(ctx, resource, ref, ns)...)
 	case "VirtualHostOption":
 		findings = append(findings, t.validateVirtualHostOption(ctx, resource, ref, ns)...)
+	case "Backend":
+		findings = append(findings, t.validateBackend(ctx, resource, ref)...)
+	case "TrafficPolicy", "HTTPListenerPolicy":
+		findings = append(findings, t.validateKgatewayPolicy(ctx, resource, ref, ns, info.gvr)...)
 	}
 
 	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "kgateway"), nil
 }
 
-// kgatewayStatusFindings extracts findings from status.conditions on a kgateway resource.
-func kgatewayStatusFindings(resource *unstructured.Unstructured, ref *types.ResourceRef) []types.DiagnosticFinding {
-	var findings []types.DiagnosticFinding
+// kgatewayConditionSet is a list of status conditions, with the ancestor it
+// applies to for the per-ancestor status of policies.
+type kgatewayConditionSet struct {
+	ancestor   string
+	conditions []interface{}
+}
 
-	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
-	for _, c := range conditions {
-		cm, ok := c.(map[string]interface{})
+// kgatewayConditionSets returns status.conditions of a kgateway resource and
+// the conditions kgateway policies report per Gateway or route in
+// status.ancestors.
+func kgatewayConditionSets(resource *unstructured.Unstructured) []kgatewayConditionSet {
+	var sets []kgatewayConditionSet
+	if conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions"); len(conditions) > 0 {
+		sets = append(sets, kgatewayConditionSet{conditions: conditions})
+	}
+	ancestors, _, _ := unstructured.NestedSlice(resource.Object, "status", "ancestors")
+	for _, a := range ancestors {
+		am, ok := a.(map[string]interface{})
 		if !ok {
 			continue
 		}
-		condType, _ := cm["type"].(string)
-		condStatus, _ := cm["status"].(string)
-		reason, _ := cm["reason"].(string)
-		message, _ := cm["message"].(string)
+		conditions, _, _ := unstructured.NestedSlice(am, "conditions")
+		kind, _, _ := unstructured.NestedString(am, "ancestorRef", "kind")
+		name, _, _ := unstructured.NestedString(am, "ancestorRef", "name")
+		ns, _, _ := unstructured.NestedString(am, "ancestorRef", "namespace")
+		sets = append(sets, kgatewayConditionSet{
+			ancestor:   fmt.Sprintf("%s %s/%s", orDefault(kind, "Gateway"), orDefault(ns, resource.GetNamespace()), name),
+			conditions: conditions,
+		})
+	}
+	return sets
+}
 
-		if condStatus == "False" {
-			findings = append(findings, types.DiagnosticFinding{
-				Severity: types.SeverityWarning,
-				Category: types.CategoryMesh,
-				Resource: ref,
-				Summary:  fmt.Sprintf("Condition %s=%s reason=%s", condType, condStatus, reason),
-				Detail:   message,
-			})
+// kgatewayStatusFindings extracts findings from status.conditions and status.ancestors on a kgateway resource.
+func kgatewayStatusFindings(resource *unstructured.Unstructured, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+
+	for _, set := range kgatewayConditionSets(resource) {
+		on := ""
+		if set.ancestor != "" {
+			on = " on " + set.ancestor
 		}
+		for _, c := range set.conditions {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			condType, _ := cm["type"].(string)
+			condStatus, _ := cm["status"].(string)
+			reason, _ := cm["reason"].(string)
+			message, _ := cm["message"].(string)
 
-		// Check for rejected/errored status
-		if condType == "Accepted" && condStatus == "False" {
-			findings = append(findings, types.DiagnosticFinding{
-				Severity:   types.SeverityCritical,
-				Category:   types.CategoryMesh,
-				Resource:   ref,
-				Summary:    fmt.Sprintf("Resource not accepted: reason=%s", reason),
-				Detail:     message,
-				Suggestion: "Review the resource configuration and check kgateway controller logs for details",
-			})
+			if condStatus == "False" {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity: types.SeverityWarning,
+					Category: types.CategoryMesh,
+					Code:     types.CodeKgatewayConditionFalse,
+					Resource: ref,
+					Summary:  fmt.Sprintf("Condition %s=%s reason=%s%s", condType, condStatus, reason, on),
+					Detail:   message,
+				})
+			}
+
+			// Check for rejected/errored status
+			if condType == "Accepted" && condStatus == "False" {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryMesh,
+					Code:       types.CodeKgatewayNotAccepted,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("Resource not accepted%s: reason=%s", on, reason),
+					Detail:     message,
+					Suggestion: "Review the resource configuration and check kgateway controller logs for details",
+				})
+			}
+
+			// Policies that are only partly applied
+			switch {
+			case condType == "Accepted" && condStatus == "True" && reason == "PartiallyValid":
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryPolicy,
+					Code:       types.CodeKgatewayPolicyOverridden,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("Policy is only partly valid%s: its invalid settings are not applied", on),
+					Detail:     message,
+					Suggestion: "Fix the settings named in the message; the rest of the policy is applied",
+				})
+			case condType == "Attached" && reason == "Overridden":
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryPolicy,
+					Code:       types.CodeKgatewayPolicyOverridden,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("Policy is overridden by another policy%s", on),
+					Detail:     message,
+					Suggestion: "Another policy on the same target sets the same fields and takes precedence; consolidate them",
+				})
+			}
 		}
 	}
 
@@ -341,7 +446,7 @@ func (t *ValidateKgatewayResourceTool) validateGatewayParameters(ctx context.Con
 
 	// Check if referenced by any Gateway
 	gatewayAPIGVR := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
-	gateways, err := t.Clients.Dynamic.Resource(gatewayAPIGVR).List(ctx, metav1.ListOptions{})
+	gateways, err := t.listResource(ctx, gatewayAPIGVR, "")
 	if err != nil {
 		slog.Debug("kgateway: skipping Gateway reference check", "error", err)
 	} else {
@@ -368,6 +473,7 @@ func (t *ValidateKgatewayResourceTool) validateGatewayParameters(ctx context.Con
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeKgatewayParametersUnused,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("GatewayParameters %s/%s is not referenced by any Gateway", resource.GetNamespace(), resource.GetName()),
 				Suggestion: "Reference this GatewayParameters from a Gateway's infrastructure.parametersRef or via annotation",
@@ -381,6 +487,7 @@ func (t *ValidateKgatewayResourceTool) validateGatewayParameters(ctx context.Con
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayParametersInvalid,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("GatewayParameters %s/%s has deployment.replicas=%d (non-positive)", resource.GetNamespace(), resource.GetName(), int(replicas)),
 			Suggestion: "Set a positive replica count for the Gateway deployment",
@@ -393,6 +500,7 @@ func (t *ValidateKgatewayResourceTool) validateGatewayParameters(ctx context.Con
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayParametersInvalid,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("GatewayParameters %s/%s envoy image %q has no tag or digest", resource.GetNamespace(), resource.GetName(), envoyImage),
 			Suggestion: "Pin the envoy image to a specific tag or digest for reproducibility",
@@ -408,6 +516,7 @@ func (t *ValidateKgatewayResourceTool) validateGatewayParameters(ctx context.Con
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeKgatewayServiceAccountMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("GatewayParameters %s/%s references ServiceAccount %q which may not exist", resource.GetNamespace(), resource.GetName(), saName),
 				Detail:     fmt.Sprintf("ServiceAccount lookup failed: %v", saErr),
@@ -457,6 +566,13 @@ func (t *ValidateKgatewayResourceTool) validateKgatewayTargetRef(ctx context.Con
 		return findings
 	}
 
+	return append(findings, t.checkKgatewayTargetRef(ctx, targetRef, resource, ref, ns)...)
+}
+
+// checkKgatewayTargetRef verifies that one targetRef of a kgateway resource names an existing resource.
+func (t *ValidateKgatewayResourceTool) checkKgatewayTargetRef(ctx context.Context, targetRef map[string]interface{}, resource *unstructured.Unstructured, ref *types.ResourceRef, ns string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+
 	group, _ := targetRef["group"].(string)
 	kind, _ := targetRef["kind"].(string)
 	name, _ := targetRef["name"].(string)
@@ -469,6 +585,7 @@ func (t *ValidateKgatewayResourceTool) validateKgatewayTargetRef(ctx context.Con
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayTargetRefInvalid,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("%s %s/%s targetRef has no name", ref.Kind, ns, resource.GetName()),
 			Suggestion: "Set targetRef.name to the target resource name",
@@ -488,6 +605,7 @@ func (t *ValidateKgatewayResourceTool) validateKgatewayTargetRef(ctx context.Con
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayTargetRefInvalid,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("%s %s/%s targetRef %s/%s not found in %s", ref.Kind, ns, resource.GetName(), kind, name, targetNs),
 			Detail:     fmt.Sprintf("Lookup failed: %v", err),
@@ -505,6 +623,10 @@ func resolveTargetRefGVR(group, kind string) (schema.GroupVersionResource, bool)
 		return schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}, true
 	case group == "gateway.networking.k8s.io" && kind == "HTTPRoute":
 		return schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}, true
+	case group == "gateway.networking.k8s.io" && kind == "GRPCRoute":
+		return grpcRoutesV1GVR, true
+	case group == "gateway.networking.x-k8s.io" && kind == "XListenerSet":
+		return schema.GroupVersionResource{Group: "gateway.networking.x-k8s.io", Version: "v1alpha1", Resource: "xlistenersets"}, true
 	case group == "" && kind == "Service":
 		return servicesGVR, true
 	}
@@ -550,6 +672,7 @@ func (t *ValidateKgatewayResourceTool) checkNestedUpstreamRef(ctx context.Contex
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeKgatewayUpstreamMissing,
 				Resource:   ref,
 				Summary:    fmt.Sprintf("Upstream reference %s/%s in %s may not exist", upNs, ur.name, ur.path),
 				Detail:     fmt.Sprintf("Service lookup failed: %v", svcErr),
@@ -611,7 +734,7 @@ func (t *ValidateKgatewayResourceTool) detectVHostOptionConflicts(ctx context.Co
 	}
 
 	// List all VirtualHostOptions in the namespace
-	vhoList, err := t.Clients.Dynamic.Resource(vhostOptionGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+	vhoList, err := t.listResource(ctx, vhostOptionGVR, ns)
 	if err != nil {
 		return findings
 	}
@@ -635,6 +758,7 @@ func (t *ValidateKgatewayResourceTool) detectVHostOptionConflicts(ctx context.Co
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityWarning,
 			Category: types.CategoryMesh,
+			Code:     types.CodeKgatewayPolicyConflict,
 			Resource: ref,
 			Summary:  fmt.Sprintf("VirtualHostOption %s/%s targets the same resource as: %s", ns, resource.GetName(), strings.Join(conflictNames, ", ")),
 			Detail:   "Multiple VirtualHostOptions targeting the same Gateway/listener may have conflicting options. kgateway merges them by priority, which can produce unexpected behavior.",
@@ -702,6 +826,9 @@ func (t *CheckKgatewayHealthTool) Run(ctx context.Context, args map[string]inter
 	// 4. TrafficPolicy circuit breaker and rate limit settings
 	findings = append(findings, t.checkTrafficPolicies(ctx)...)
 
+	// 5. Route references to Backends that do not exist
+	findings = append(findings, t.checkBackendReferences(ctx)...)
+
 	if len(findings) == 0 {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityOK,
@@ -737,7 +864,7 @@ func (t *CheckKgatewayHealthTool) checkControlPlanePods(ctx context.Context, ns
 
 	// Also try deployment-based discovery
 	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
-	depList, err := t.Clients.Dynamic.Resource(deploymentsGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+	depList, err := t.listResource(ctx, deploymentsGVR, ns)
 	if err == nil {
 		for _, dep := range depList.Items {
 			depName := dep.GetName()
@@ -760,6 +887,7 @@ func (t *CheckKgatewayHealthTool) checkControlPlanePods(ctx context.Context, ns
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityCritical,
 						Category:   types.CategoryMesh,
+						Code:       types.CodeKgatewayControlPlaneDown,
 						Resource:   &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: depName, APIVersion: "apps/v1"},
 						Summary:    fmt.Sprintf("kgateway Deployment %s/%s has no running pods", ns, depName),
 						Suggestion: "Check deployment status and events for scheduling or image pull issues",
@@ -777,6 +905,7 @@ func (t *CheckKgatewayHealthTool) checkControlPlanePods(ctx context.Context, ns
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayControlPlaneDown,
 			Summary:    fmt.Sprintf("No kgateway control plane pods found in namespace %s", ns),
 			Suggestion: "Verify kgateway is installed and the correct namespace is specified",
 		})
@@ -824,6 +953,7 @@ func evaluatePodHealth(pod *unstructured.Unstructured, role string) []types.Diag
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityCritical,
 						Category:   types.CategoryMesh,
+						Code:       types.CodeKgatewayPodUnhealthy,
 						Resource:   ref,
 						Summary:    fmt.Sprintf("Pod %s/%s container %s is %s", podNs, podName, cName, reason),
 						Detail:     message,
@@ -836,6 +966,7 @@ func evaluatePodHealth(pod *unstructured.Unstructured, role string) []types.Diag
 
 	if phase == "Running" && allReady {
 		severity := types.SeverityOK
+		var code types.FindingCode
 		summary := fmt.Sprintf("Pod %s/%s (%s) is Running and ready", podNs, podName, role)
 		detail := ""
 		if restartCount > 0 {
@@ -843,11 +974,13 @@ func evaluatePodHealth(pod *unstructured.Unstructured, role string) []types.Diag
 		}
 		if restartCount > 5 {
 			severity = types.SeverityWarning
+			code = types.CodeKgatewayPodUnhealthy
 			summary = fmt.Sprintf("Pod %s/%s (%s) is Running but has %d restarts", podNs, podName, role, restartCount)
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryMesh,
+			Code:     code,
 			Resource: ref,
 			Summary:  summary,
 			Detail:   detail,
@@ -856,6 +989,7 @@ func evaluatePodHealth(pod *unstructured.Unstructured, role string) []types.Diag
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayPodUnhealthy,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("Pod %s/%s (%s) is Running but not all containers are ready", podNs, podName, role),
 			Detail:     fmt.Sprintf("Not ready containers: %s", strings.Join(notReadyContainers, ", ")),
@@ -865,6 +999,7 @@ func evaluatePodHealth(pod *unstructured.Unstructured, role string) []types.Diag
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeKgatewayPodUnhealthy,
 			Resource:   ref,
 			Summary:    fmt.Sprintf("Pod %s/%s (%s) phase=%s", podNs, podName, role, phase),
 			Suggestion: "Check pod events and logs for scheduling or startup issues",
@@ -879,8 +1014,9 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 	var findings []types.DiagnosticFinding
 
 	// Check each kgateway resource type
-	for kind, info := range kgatewayKindGVRs {
-		list, err := t.Clients.Dynamic.Resource(info.gvr).List(ctx, metav1.ListOptions{})
+	for _, kind := range kgatewayKinds {
+		info := kgatewayKindGVRs[kind]
+		list, err := t.listResource(ctx, info.gvr, "")
 		if err != nil {
 			slog.Debug("kgateway health: skipping resource type", "kind", kind, "error", err)
 			continue
@@ -889,10 +1025,10 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 		accepted := 0
 		rejected := 0
 		errored := 0
+		overridden := 0
 
 		for _, item := range list.Items {
-			conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
-			state := classifyResourceStatus(conditions)
+			state, conditions := classifyKgatewayStatus(&item)
 			switch state {
 			case "accepted":
 				accepted++
@@ -901,6 +1037,7 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 				findings = append(findings, types.DiagnosticFinding{
 					Severity: types.SeverityCritical,
 					Category: types.CategoryMesh,
+					Code:     types.CodeKgatewayNotAccepted,
 					Resource: &types.ResourceRef{
 						Kind:       kind,
 						Namespace:  item.GetNamespace(),
@@ -916,6 +1053,7 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 				findings = append(findings, types.DiagnosticFinding{
 					Severity: types.SeverityWarning,
 					Category: types.CategoryMesh,
+					Code:     types.CodeKgatewayConditionFalse,
 					Resource: &types.ResourceRef{
 						Kind:       kind,
 						Namespace:  item.GetNamespace(),
@@ -925,19 +1063,44 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 					Summary: fmt.Sprintf("%s %s/%s has error conditions", kind, item.GetNamespace(), item.GetName()),
 					Detail:  extractConditionMessage(conditions, ""),
 				})
+			case "partial", "overridden":
+				overridden++
+				summary := fmt.Sprintf("%s %s/%s is only partly valid", kind, item.GetNamespace(), item.GetName())
+				detail := extractConditionMessage(conditions, "Accepted")
+				if state == "overridden" {
+					summary = fmt.Sprintf("%s %s/%s is overridden by another policy on the same target", kind, item.GetNamespace(), item.GetName())
+					detail = extractConditionMessage(conditions, "Attached")
+				}
+				findings = append(findings, types.DiagnosticFinding{
+					Severity: types.SeverityWarning,
+					Category: types.CategoryPolicy,
+					Code:     types.CodeKgatewayPolicyOverridden,
+					Resource: &types.ResourceRef{
+						Kind:       kind,
+						Namespace:  item.GetNamespace(),
+						Name:       item.GetName(),
+						APIVersion: info.apiGroup,
+					},
+					Summary: summary,
+					Detail:  detail,
+				})
 			}
 		}
 
 		total := len(list.Items)
 		if total > 0 {
 			severity := types.SeverityInfo
-			if rejected > 0 || errored > 0 {
+			if rejected > 0 || errored > 0 || overridden > 0 {
 				severity = types.SeverityWarning
 			}
+			summary := fmt.Sprintf("%s resources: %d total, %d accepted, %d rejected, %d errored", kind, total, accepted, rejected, errored)
+			if overridden > 0 {
+				summary += fmt.Sprintf(", %d partly applied", overridden)
+			}
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: severity,
 				Category: types.CategoryMesh,
-				Summary:  fmt.Sprintf("%s resources: %d total, %d accepted, %d rejected, %d errored", kind, total, accepted, rejected, errored),
+				Summary:  summary,
 			})
 		}
 	}
@@ -945,8 +1108,11 @@ func (t *CheckKgatewayHealthTool) checkResourceTranslationStatus(ctx context.Con
 	return findings
 }
 
-// classifyResourceStatus determines the translation state from status conditions.
+// classifyResourceStatus determines the translation state from status conditions:
+// rejected, errored, overridden (replaced by another policy on the same target),
+// partial (only part of a policy is valid), accepted or unknown.
 func classifyResourceStatus(conditions []interface{}) string {
+	accepted, partial, overridden, errored := false, false, false, false
 	for _, c := range conditions {
 		cm, ok := c.(map[string]interface{})
 		if !ok {
@@ -956,20 +1122,48 @@ func classifyResourceStatus(conditions []interface{}) string {
 		condStatus, _ := cm["status"].(string)
 		reason, _ := cm["reason"].(string)
 
-		if condType == "Accepted" {
-			if condStatus == "True" {
-				return "accepted"
-			}
+		switch {
+		case condType == "Accepted" && condStatus != "True":
 			return "rejected"
-		}
+		case condType == "Accepted":
+			accepted = true
+			partial = reason == "PartiallyValid"
+		case condType == "Attached" && reason == "Overridden":
+			overridden = true
 		// Check for error-related conditions
-		if condStatus == "False" && (strings.Contains(reason, "Error") || strings.Contains(reason, "Invalid")) {
-			return "errored"
+		case condStatus == "False" && (strings.Contains(reason, "Error") || strings.Contains(reason, "Invalid")):
+			errored = true
 		}
 	}
+	switch {
+	case errored:
+		return "errored"
+	case overridden:
+		return "overridden"
+	case partial:
+		return "partial"
+	case accepted:
+		return "accepted"
+	}
 	return "unknown"
 }
 
+// kgatewayStateRank orders translation states from the best to the worst.
+var kgatewayStateRank = map[string]int{"unknown": 0, "accepted": 1, "partial": 2, "overridden": 3, "errored": 4, "rejected": 5}
+
+// classifyKgatewayStatus returns the worst translation state of a kgateway
+// resource over its status.conditions and per-ancestor conditions, with the
+// conditions it was found in.
+func classifyKgatewayStatus(resource *unstructured.Unstructured) (string, []interface{}) {
+	state, conditions := "unknown", []interface{}(nil)
+	for _, set := range kgatewayConditionSets(resource) {
+		if s := classifyResourceStatus(set.conditions); kgatewayStateRank[s] > kgatewayStateRank[state] {
+			state, conditions = s, set.conditions
+		}
+	}
+	return state, conditions
+}
+
 // extractConditionMessage returns the message from a specific condition type, or all False conditions.
 func extractConditionMessage(conditions []interface{}, condType string) string {
 	var messages []string
@@ -998,7 +1192,7 @@ func (t *CheckKgatewayHealthTool) checkDataPlaneHealth(ctx context.Context) []ty
 	var findings []types.DiagnosticFinding
 
 	gatewayAPIGVR := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
-	gateways, err := t.Clients.Dynamic.Resource(gatewayAPIGVR).List(ctx, metav1.ListOptions{})
+	gateways, err := t.listResource(ctx, gatewayAPIGVR, "")
 	if err != nil {
 		slog.Debug("kgateway health: skipping Gateway data plane check", "error", err)
 		return findings
@@ -1045,6 +1239,7 @@ func (t *CheckKgatewayHealthTool) checkDataPlaneHealth(ctx context.Context) []ty
 					findings = append(findings, types.DiagnosticFinding{
 						Severity:   types.SeverityCritical,
 						Category:   types.CategoryMesh,
+						Code:       types.CodeKgatewayGatewayNotProgrammed,
 						Resource:   gwRef,
 						Summary:    fmt.Sprintf("Gateway %s/%s (kgateway) is NOT Programmed: reason=%s", gwNs, gwName, reason),
 						Detail:     message,
@@ -1057,6 +1252,7 @@ func (t *CheckKgatewayHealthTool) checkDataPlaneHealth(ctx context.Context) []ty
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityCritical,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeKgatewayGatewayNotProgrammed,
 					Resource:   gwRef,
 					Summary:    fmt.Sprintf("Gateway %s/%s (kgateway) is NOT Accepted: reason=%s", gwNs, gwName, reason),
 					Detail:     message,
@@ -1069,6 +1265,7 @@ func (t *CheckKgatewayHealthTool) checkDataPlaneHealth(ctx context.Context) []ty
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryMesh,
+				Code:       types.CodeKgatewayGatewayNotProgrammed,
 				Resource:   gwRef,
 				Summary:    fmt.Sprintf("Gateway %s/%s (kgateway) has no Programmed condition", gwNs, gwName),
 				Suggestion: "The Gateway may still be provisioning or the kgateway controller may not be processing it",
@@ -1085,6 +1282,7 @@ func (t *CheckKgatewayHealthTool) checkDataPlaneHealth(ctx context.Context) []ty
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryMesh,
+					Code:       types.CodeKgatewayNoDataPlane,
 					Resource:   gwRef,
 					Summary:    fmt.Sprintf("Gateway %s/%s (kgateway) has no data plane proxy pods", gwNs, gwName),
 					Suggestion: "Check if kgateway has provisioned the proxy deployment for this Gateway",
@@ -1131,7 +1329,7 @@ func isKgatewayManaged(gw *unstructured.Unstructured) bool {
 // checkTrafficPolicies surfaces circuit breaker and rate limit settings from kgateway TrafficPolicy resources.
 // Uses extractCBFromMap (shared with Istio) for connectionPool/outlierDetection extraction.
 func (t *CheckKgatewayHealthTool) checkTrafficPolicies(ctx context.Context) []types.DiagnosticFinding {
-	list, err := t.Clients.Dynamic.Resource(trafficPolicyGVR).List(ctx, metav1.ListOptions{})
+	list, err := t.listResource(ctx, trafficPolicyGVR, "")
 	if err != nil {
 		return nil // CRD not installed or no access
 	}
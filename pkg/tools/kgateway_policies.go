@@ -0,0 +1,380 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// kgatewayPolicyTargetKinds are the kinds each kgateway policy can attach to.
+var kgatewayPolicyTargetKinds = map[string][]string{
+	"TrafficPolicy":      {"Gateway", "HTTPRoute", "XListenerSet"},
+	"HTTPListenerPolicy": {"Gateway", "XListenerSet"},
+}
+
+// kgatewayBackendBlocks are the spec fields configuring each Backend type.
+var kgatewayBackendBlocks = []struct{ typ, field string }{
+	{"Static", "static"}, {"AWS", "aws"}, {"DynamicForwardProxy", "dynamicForwardProxy"}, {"AI", "ai"}, {"MCP", "mcp"}, {"GCP", "gcp"},
+}
+
+// kgatewayBackendType returns the type of a Backend: spec.type, or the
+// type-specific block it sets.
+func kgatewayBackendType(backend *unstructured.Unstructured) string {
+	if typ, _, _ := unstructured.NestedString(backend.Object, "spec", "type"); typ != "" {
+		return typ
+	}
+	for _, block := range kgatewayBackendBlocks {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(backend.Object, "spec", block.field); ok {
+			return block.typ
+		}
+	}
+	return ""
+}
+
+// kgatewayBackendProblems lists what keeps a Backend from sending traffic
+// anywhere.
+func kgatewayBackendProblems(backend *unstructured.Unstructured) []string {
+	var problems []string
+	typ := kgatewayBackendType(backend)
+	for _, block := range kgatewayBackendBlocks {
+		if block.typ != typ || typ == "DynamicForwardProxy" {
+			continue
+		}
+		if _, ok, _ := unstructured.NestedFieldNoCopy(backend.Object, "spec", block.field); !ok {
+			return append(problems, fmt.Sprintf("type is %s but spec.%s is not set", typ, block.field))
+		}
+	}
+	switch typ {
+	case "":
+		problems = append(problems, "spec.type is not set and no backend block is configured")
+	case "Static":
+		hosts, _, _ := unstructured.NestedSlice(backend.Object, "spec", "static", "hosts")
+		if len(hosts) == 0 {
+			problems = append(problems, "static backend has no hosts")
+		}
+		for i, h := range hosts {
+			hm, _ := h.(map[string]interface{})
+			if host, _ := hm["host"].(string); host == "" {
+				problems = append(problems, fmt.Sprintf("spec.static.hosts[%d] has no host", i))
+			}
+			if toInt(hm["port"]) <= 0 {
+				problems = append(problems, fmt.Sprintf("spec.static.hosts[%d] has no port", i))
+			}
+		}
+	case "AWS":
+		if account, _, _ := unstructured.NestedString(backend.Object, "spec", "aws", "accountId"); account == "" {
+			problems = append(problems, "spec.aws.accountId is not set")
+		}
+		if fn, _, _ := unstructured.NestedString(backend.Object, "spec", "aws", "lambda", "functionName"); fn == "" {
+			problems = append(problems, "spec.aws.lambda.functionName is not set")
+		}
+	}
+	return problems
+}
+
+// kgatewayBackendRef is a backendRef of a route or policy.
+type kgatewayBackendRef struct {
+	kind      string // Service or Backend
+	name      string
+	namespace string
+	path      string
+}
+
+// kgatewayBackendRefs walks a policy spec for backendRef and backendRefs
+// fields, as set by extAuth, extProc, rate limit, tracing and access log
+// services.
+func kgatewayBackendRefs(obj interface{}, path, defaultNs string) []kgatewayBackendRef {
+	var refs []kgatewayBackendRef
+	add := func(v interface{}, p string) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		name, _ := m["name"].(string)
+		if name == "" {
+			return
+		}
+		kind, _ := m["kind"].(string)
+		ns, _ := m["namespace"].(string)
+		refs = append(refs, kgatewayBackendRef{kind: orDefault(kind, "Service"), name: name, namespace: orDefault(ns, defaultNs), path: p})
+	}
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch k {
+			case "backendRef":
+				add(v[k], path+"."+k)
+			case "backendRefs":
+				items, _ := v[k].([]interface{})
+				for i, item := range items {
+					add(item, fmt.Sprintf("%s.%s[%d]", path, k, i))
+				}
+			default:
+				refs = append(refs, kgatewayBackendRefs(v[k], path+"."+k, defaultNs)...)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			refs = append(refs, kgatewayBackendRefs(item, fmt.Sprintf("%s[%d]", path, i), defaultNs)...)
+		}
+	}
+	return refs
+}
+
+// routeKgatewayBackends returns the Backends a route sends traffic to, as
+// "namespace/name".
+func routeKgatewayBackends(route routeInfo) []string {
+	seen := make(map[string]bool)
+	rules, _, _ := unstructured.NestedSlice(route.obj, "spec", "rules")
+	for _, r := range rules {
+		rm, _ := r.(map[string]interface{})
+		brs, _ := rm["backendRefs"].([]interface{})
+		for _, br := range brs {
+			brm, _ := br.(map[string]interface{})
+			if kind, _ := brm["kind"].(string); kind != "Backend" {
+				continue
+			}
+			if group, _ := brm["group"].(string); group != backendGVR.Group {
+				continue
+			}
+			name, _ := brm["name"].(string)
+			ns, _ := brm["namespace"].(string)
+			seen[orDefault(ns, route.namespace)+"/"+name] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+// kgatewayPolicyFields returns the policy settings of a kgateway policy: the
+// spec fields other than its targets.
+func kgatewayPolicyFields(policy *unstructured.Unstructured) []string {
+	spec, _, _ := unstructured.NestedMap(policy.Object, "spec")
+	var fields []string
+	for k := range spec {
+		if k != "targetRefs" && k != "targetSelectors" {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// kgatewayPolicyTargetKeys returns the target keys of a policy's targetRefs.
+// kgateway policies only target resources in their own namespace.
+func kgatewayPolicyTargetKeys(policy *unstructured.Unstructured) []string {
+	refs, _, _ := unstructured.NestedSlice(policy.Object, "spec", "targetRefs")
+	var keys []string
+	for _, r := range refs {
+		rm, _ := r.(map[string]interface{})
+		if key := kgatewayTargetKey(rm, policy.GetNamespace()); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// kgatewayPolicyConflicts returns, for each other policy sharing a target
+// with policy, the settings both set. kgateway merges the policies of a
+// target field by field, and for a field set twice the oldest policy wins.
+func kgatewayPolicyConflicts(policy *unstructured.Unstructured, others []unstructured.Unstructured) map[string][]string {
+	keys := kgatewayPolicyTargetKeys(policy)
+	fields := kgatewayPolicyFields(policy)
+	conflicts := make(map[string][]string)
+	for i := range others {
+		other := &others[i]
+		if other.GetName() == policy.GetName() && other.GetNamespace() == policy.GetNamespace() {
+			continue
+		}
+		shared := false
+		for _, k := range kgatewayPolicyTargetKeys(other) {
+			shared = shared || containsString(keys, k)
+		}
+		if !shared {
+			continue
+		}
+		var overlap []string
+		for _, f := range kgatewayPolicyFields(other) {
+			if containsString(fields, f) {
+				overlap = append(overlap, f)
+			}
+		}
+		if len(overlap) > 0 {
+			conflicts[other.GetName()] = overlap
+		}
+	}
+	return conflicts
+}
+
+// validateBackend checks that a Backend can send traffic and is used.
+func (t *ValidateKgatewayResourceTool) validateBackend(ctx context.Context, resource *unstructured.Unstructured, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+
+	if problems := kgatewayBackendProblems(resource); len(problems) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeKgatewayBackendInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Backend %s/%s cannot send traffic: %s", resource.GetNamespace(), resource.GetName(), problems[0]),
+			Detail:     strings.Join(problems, "; "),
+			Suggestion: "Set the backend type and its hosts, ports or function",
+		})
+	}
+
+	key := resource.GetNamespace() + "/" + resource.GetName()
+	var users []string
+	for _, route := range t.listRoutes(ctx) {
+		if containsString(routeKgatewayBackends(route), key) {
+			users = append(users, fmt.Sprintf("%s %s/%s", route.kind, route.namespace, route.name))
+		}
+	}
+	for _, gvr := range []schema.GroupVersionResource{trafficPolicyGVR, httpListenerPolicyGVR} {
+		list, err := t.listResource(ctx, gvr, "")
+		if err != nil {
+			continue
+		}
+		for _, p := range list.Items {
+			for _, br := range kgatewayBackendRefs(p.Object["spec"], "spec", p.GetNamespace()) {
+				if br.kind == "Backend" && br.namespace+"/"+br.name == key {
+					users = append(users, fmt.Sprintf("%s %s/%s", p.GetKind(), p.GetNamespace(), p.GetName()))
+				}
+			}
+		}
+	}
+	if len(users) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeKgatewayBackendUnused,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Backend %s/%s is not referenced by any route or policy", resource.GetNamespace(), resource.GetName()),
+			Suggestion: "Reference it from an HTTPRoute backendRef with group gateway.kgateway.dev and kind Backend, or remove it",
+		})
+	}
+
+	return findings
+}
+
+// validateKgatewayPolicy checks the targets, backend references and
+// attachment conflicts of a TrafficPolicy or HTTPListenerPolicy.
+func (t *ValidateKgatewayResourceTool) validateKgatewayPolicy(ctx context.Context, resource *unstructured.Unstructured, ref *types.ResourceRef, ns string, gvr schema.GroupVersionResource) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	kind := ref.Kind
+
+	targetRefs, _, _ := unstructured.NestedSlice(resource.Object, "spec", "targetRefs")
+	_, hasSelectors, _ := unstructured.NestedFieldNoCopy(resource.Object, "spec", "targetSelectors")
+	if len(targetRefs) == 0 && !hasSelectors {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeKgatewayTargetRefInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s %s/%s has no targetRefs or targetSelectors and applies to nothing", kind, ns, resource.GetName()),
+			Suggestion: "Attach the policy with spec.targetRefs or spec.targetSelectors",
+		})
+	}
+	for _, tr := range targetRefs {
+		trm, _ := tr.(map[string]interface{})
+		targetKind, _ := trm["kind"].(string)
+		if allowed := kgatewayPolicyTargetKinds[kind]; !containsString(allowed, targetKind) {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeKgatewayTargetRefInvalid,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s %s/%s targets a %s, which it cannot attach to", kind, ns, resource.GetName(), targetKind),
+				Detail:     fmt.Sprintf("%s attaches to: %s", kind, strings.Join(allowed, ", ")),
+				Suggestion: "Target one of the supported kinds",
+			})
+			continue
+		}
+		findings = append(findings, t.checkKgatewayTargetRef(ctx, trm, resource, ref, ns)...)
+	}
+
+	// Backend references of extAuth, extProc, rate limit, tracing and access log services
+	for _, br := range kgatewayBackendRefs(resource.Object["spec"], "spec", ns) {
+		gvr := servicesGVR
+		if br.kind == "Backend" {
+			gvr = backendGVR
+		} else if br.kind != "Service" {
+			continue
+		}
+		if _, err := t.Clients.Dynamic.Resource(gvr).Namespace(br.namespace).Get(ctx, br.name, metav1.GetOptions{}); err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeKgatewayUpstreamMissing,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s %s/%s in %s may not exist", br.kind, br.namespace, br.name, br.path),
+				Detail:     fmt.Sprintf("%s lookup failed: %v", br.kind, err),
+				Suggestion: fmt.Sprintf("Verify the backendRef points to an existing %s", br.kind),
+			})
+		}
+	}
+
+	// Attachment conflicts with other policies of the same kind on the same target
+	if list, err := t.listResource(ctx, gvr, ns); err == nil {
+		conflicts := kgatewayPolicyConflicts(resource, list.Items)
+		names := make([]string, 0, len(conflicts))
+		for name := range conflicts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeKgatewayPolicyConflict,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s %s/%s and %s set %s on the same target", kind, ns, resource.GetName(), name, strings.Join(conflicts[name], ", ")),
+				Detail:     "kgateway merges the policies of a target field by field; for a field set by several policies, the oldest one wins and the others are overridden",
+				Suggestion: "Consolidate the settings into one policy, or target different resources",
+			})
+		}
+	}
+
+	return findings
+}
+
+// checkBackendReferences reports HTTPRoute and GRPCRoute backendRefs to
+// Backends that do not exist: kgateway returns 500 for those rules.
+func (t *CheckKgatewayHealthTool) checkBackendReferences(ctx context.Context) []types.DiagnosticFinding {
+	list, err := t.listResource(ctx, backendGVR, "")
+	if err != nil {
+		return nil // CRD not installed or no access
+	}
+	backends := make(map[string]bool, len(list.Items))
+	for _, b := range list.Items {
+		backends[b.GetNamespace()+"/"+b.GetName()] = true
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, route := range t.listRoutes(ctx) {
+		for _, key := range routeKgatewayBackends(route) {
+			if backends[key] {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeKgatewayUpstreamMissing,
+				Resource:   &types.ResourceRef{Kind: route.kind, Namespace: route.namespace, Name: route.name, APIVersion: "gateway.networking.k8s.io/v1"},
+				Summary:    fmt.Sprintf("%s %s/%s references Backend %s, which does not exist", route.kind, route.namespace, route.name, key),
+				Suggestion: "Create the Backend or fix the backendRef; requests to the rule fail until it resolves",
+			})
+		}
+	}
+	return findings
+}
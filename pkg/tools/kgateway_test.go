@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func kgatewayBackend(name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return managedObj("gateway.kgateway.dev/v1alpha1", "Backend", "shop", name, map[string]interface{}{"spec": spec})
+}
+
+func kgatewayPolicy(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return managedObj("gateway.kgateway.dev/v1alpha1", kind, "shop", name, map[string]interface{}{"spec": spec})
+}
+
+func TestKgatewayBackendProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		spec map[string]interface{}
+		want int
+	}{
+		{"static", map[string]interface{}{"type": "Static", "static": map[string]interface{}{"hosts": []interface{}{
+			map[string]interface{}{"host": "api.example.com", "port": int64(443)},
+		}}}, 0},
+		{"static without port", map[string]interface{}{"type": "Static", "static": map[string]interface{}{"hosts": []interface{}{
+			map[string]interface{}{"host": "api.example.com"},
+		}}}, 1},
+		{"static without hosts", map[string]interface{}{"static": map[string]interface{}{}}, 1},
+		{"type without block", map[string]interface{}{"type": "AI"}, 1},
+		{"lambda without function", map[string]interface{}{"type": "AWS", "aws": map[string]interface{}{"accountId": "123"}}, 1},
+		{"dynamic forward proxy", map[string]interface{}{"type": "DynamicForwardProxy"}, 0},
+		{"empty", map[string]interface{}{}, 1},
+	}
+	for _, tt := range tests {
+		if got := kgatewayBackendProblems(kgatewayBackend("b", tt.spec)); len(got) != tt.want {
+			t.Errorf("%s: problems = %v, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKgatewayBackendRefs(t *testing.T) {
+	spec := map[string]interface{}{
+		"extAuth": map[string]interface{}{"backendRef": map[string]interface{}{"name": "authz", "port": int64(9000)}},
+		"tracing": map[string]interface{}{"provider": map[string]interface{}{"openTelemetry": map[string]interface{}{
+			"grpcService": map[string]interface{}{"backendRef": map[string]interface{}{"name": "otel", "namespace": "observability"}},
+		}}},
+		"accessLog": []interface{}{map[string]interface{}{"grpcService": map[string]interface{}{
+			"backendRefs": []interface{}{map[string]interface{}{"name": "logs", "kind": "Backend", "group": "gateway.kgateway.dev"}},
+		}}},
+	}
+	got := kgatewayBackendRefs(spec, "spec", "shop")
+	want := []kgatewayBackendRef{
+		{kind: "Backend", name: "logs", namespace: "shop", path: "spec.accessLog[0].grpcService.backendRefs[0]"},
+		{kind: "Service", name: "authz", namespace: "shop", path: "spec.extAuth.backendRef"},
+		{kind: "Service", name: "otel", namespace: "observability", path: "spec.tracing.provider.openTelemetry.grpcService.backendRef"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("refs = %+v, want %+v", got, want)
+	}
+}
+
+func TestKgatewayPolicyConflicts(t *testing.T) {
+	target := func(kind, name string) []interface{} {
+		return []interface{}{map[string]interface{}{"group": groupGateway, "kind": kind, "name": name}}
+	}
+	policy := kgatewayPolicy("TrafficPolicy", "timeouts", map[string]interface{}{"targetRefs": target("HTTPRoute", "web"), "timeouts": map[string]interface{}{}, "retry": map[string]interface{}{}})
+	others := []unstructured.Unstructured{
+		*policy,
+		*kgatewayPolicy("TrafficPolicy", "retries", map[string]interface{}{"targetRefs": target("HTTPRoute", "web"), "retry": map[string]interface{}{}}),
+		*kgatewayPolicy("TrafficPolicy", "cors", map[string]interface{}{"targetRefs": target("HTTPRoute", "web"), "cors": map[string]interface{}{}}),
+		*kgatewayPolicy("TrafficPolicy", "gateway", map[string]interface{}{"targetRefs": target("Gateway", "public"), "retry": map[string]interface{}{}}),
+	}
+	want := map[string][]string{"retries": {"retry"}}
+	if got := kgatewayPolicyConflicts(policy, others); !reflect.DeepEqual(got, want) {
+		t.Errorf("conflicts = %v, want %v", got, want)
+	}
+}
+
+func TestClassifyKgatewayStatus(t *testing.T) {
+	ancestor := func(name string, conditions ...interface{}) interface{} {
+		return map[string]interface{}{
+			"ancestorRef": map[string]interface{}{"kind": "Gateway", "name": name},
+			"conditions":  conditions,
+		}
+	}
+	cond := func(typ, status, reason string) interface{} {
+		return map[string]interface{}{"type": typ, "status": status, "reason": reason}
+	}
+	policy := kgatewayPolicy("TrafficPolicy", "p", nil)
+	policy.Object["status"] = map[string]interface{}{"ancestors": []interface{}{
+		ancestor("public", cond("Accepted", "True", "Valid"), cond("Attached", "True", "Attached")),
+		ancestor("internal", cond("Accepted", "True", "Valid"), cond("Attached", "True", "Overridden")),
+	}}
+	if state, _ := classifyKgatewayStatus(policy); state != "overridden" {
+		t.Errorf("state = %s, want overridden", state)
+	}
+	findings := kgatewayStatusFindings(policy, nil)
+	if len(findings) != 1 || !strings.Contains(findings[0].Summary, "on Gateway shop/internal") {
+		t.Errorf("findings = %+v", findings)
+	}
+
+	policy.Object["status"] = map[string]interface{}{"ancestors": []interface{}{
+		ancestor("public", cond("Accepted", "False", "Invalid")),
+	}}
+	if state, _ := classifyKgatewayStatus(policy); state != "rejected" {
+		t.Errorf("state = %s, want rejected", state)
+	}
+	if state := classifyResourceStatus([]interface{}{cond("Accepted", "True", "PartiallyValid")}); state != "partial" {
+		t.Errorf("state = %s, want partial", state)
+	}
+}
+
+func TestValidateKgatewayPolicy(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{
+		trafficPolicyGVR:      "TrafficPolicyList",
+		httpListenerPolicyGVR: "HTTPListenerPolicyList",
+		backendGVR:            "BackendList",
+	}
+	for gvr, kind := range managedListKinds {
+		listKinds[gvr] = kind
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	route := rateLimitRoute("web", "infra/public")
+	route.Object["spec"].(map[string]interface{})["rules"] = []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+		map[string]interface{}{"group": "gateway.kgateway.dev", "kind": "Backend", "name": "payments"},
+	}}}
+	for gvr, items := range map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		httpRoutesV1GVR: {route},
+		trafficPolicyGVR: {
+			kgatewayPolicy("TrafficPolicy", "auth", map[string]interface{}{
+				"targetRefs": []interface{}{map[string]interface{}{"group": groupGateway, "kind": "HTTPRoute", "name": "web"}},
+				"extAuth":    map[string]interface{}{"backendRef": map[string]interface{}{"name": "authz"}},
+			}),
+			kgatewayPolicy("TrafficPolicy", "auth-v2", map[string]interface{}{
+				"targetRefs": []interface{}{map[string]interface{}{"group": groupGateway, "kind": "HTTPRoute", "name": "web"}},
+				"extAuth":    map[string]interface{}{"backendRef": map[string]interface{}{"name": "authz-v2"}},
+			}),
+		},
+		backendGVR: {kgatewayBackend("unused", map[string]interface{}{"type": "Static", "static": map[string]interface{}{"hosts": []interface{}{
+			map[string]interface{}{"host": "api.example.com", "port": int64(443)},
+		}}})},
+	} {
+		for _, item := range items {
+			if err := client.Tracker().Create(gvr, item, item.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+
+	resp, err := (&ValidateKgatewayResourceTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{"kind": "TrafficPolicy", "name": "auth", "namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := managedFindingsOf(resp)
+	for _, want := range []string{
+		"warning Service shop/authz in spec.extAuth.backendRef may not exist KGW007_UPSTREAM_MISSING",
+		"warning TrafficPolicy shop/auth and auth-v2 set extAuth on the same target KGW008_POLICY_CONFLICT",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	resp, err = (&ValidateKgatewayResourceTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{"kind": "Backend", "name": "unused", "namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := managedFindingsOf(resp); !strings.Contains(got, "info Backend shop/unused is not referenced by any route or policy KGW015_BACKEND_UNUSED") {
+		t.Errorf("findings:\n%s", got)
+	}
+
+	missing := (&CheckKgatewayHealthTool{BaseTool: base}).checkBackendReferences(context.Background())
+	if len(missing) != 1 || missing[0].Summary != "HTTPRoute shop/web references Backend shop/payments, which does not exist" {
+		t.Errorf("missing backends = %+v", missing)
+	}
+}
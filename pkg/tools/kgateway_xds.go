@@ -0,0 +1,635 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	// kgatewayAdminPort is the kgateway controller port serving its admin
+	// endpoints, among them the xDS snapshot it serves to the proxies.
+	kgatewayAdminPort = "9097"
+	kgatewayXDSPath   = "/snapshots/xds"
+)
+
+// kgatewayControllerSelectors select the kgateway controller pods.
+var kgatewayControllerSelectors = []string{"app.kubernetes.io/name=kgateway", "app=kgateway"}
+
+// xdsRoute is a route of an Envoy virtual host.
+type xdsRoute struct {
+	name     string
+	match    string // "prefix:/api", "exact:/login" or "regex:..."
+	clusters []string
+	direct   int // status of a direct response
+	redirect bool
+}
+
+type xdsVirtualHost struct {
+	name    string
+	domains []string
+	routes  []xdsRoute
+}
+
+// xdsConfig is the routing part of an Envoy configuration.
+type xdsConfig struct {
+	vhosts   []xdsVirtualHost
+	clusters map[string]bool
+}
+
+// xdsKey normalizes a field name so that proto names (virtual_hosts), JSON
+// names (virtualHosts) and Go field names (VirtualHosts) compare equal.
+func xdsKey(k string) string {
+	return strings.ToLower(strings.ReplaceAll(k, "_", ""))
+}
+
+// xdsGet returns the field of m with the normalized name key.
+func xdsGet(m map[string]interface{}, key string) interface{} {
+	for k, v := range m {
+		if xdsKey(k) == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// xdsFind returns the first field named key in v, depth first. Oneof fields
+// are nested in wrappers (Action, PathSpecifier) in some encodings.
+func xdsFind(v interface{}, key string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if found := xdsGet(t, key); found != nil {
+			return found
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if found := xdsFind(t[k], key); found != nil {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			if found := xdsFind(item, key); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// xdsRouteMatch returns the path match of an Envoy route as a key comparable
+// with gatewayRuleMatches.
+func xdsRouteMatch(route map[string]interface{}) string {
+	full, _ := xdsFind(route, "match").(map[string]interface{})
+	if full == nil {
+		return ""
+	}
+	// Header and query matchers have prefix fields of their own.
+	match := make(map[string]interface{}, len(full))
+	for k, v := range full {
+		if key := xdsKey(k); key != "headers" && key != "queryparameters" {
+			match[k] = v
+		}
+	}
+	for _, m := range []struct{ field, kind string }{
+		{"pathseparatedprefix", "prefix"}, {"prefix", "prefix"}, {"path", "exact"},
+	} {
+		if v, ok := xdsFind(match, m.field).(string); ok {
+			if m.kind == "prefix" {
+				v = strings.TrimSuffix(v, "/")
+			}
+			return m.kind + ":" + v
+		}
+	}
+	if regex, ok := xdsFind(xdsFind(match, "saferegex"), "regex").(string); ok {
+		return "regex:" + regex
+	}
+	return ""
+}
+
+func parseXDSRoute(m map[string]interface{}) xdsRoute {
+	r := xdsRoute{match: xdsRouteMatch(m)}
+	r.name, _ = xdsGet(m, "name").(string)
+	if direct, ok := xdsFind(m, "directresponse").(map[string]interface{}); ok {
+		r.direct = toInt(xdsGet(direct, "status"))
+	}
+	r.redirect = xdsFind(m, "redirect") != nil
+	if action, ok := xdsFind(m, "route").(map[string]interface{}); ok {
+		if c, ok := xdsFind(action, "cluster").(string); ok {
+			r.clusters = append(r.clusters, c)
+		}
+		if weighted := xdsFind(action, "weightedclusters"); weighted != nil {
+			clusters, _ := xdsFind(weighted, "clusters").([]interface{})
+			for _, c := range clusters {
+				cm, _ := c.(map[string]interface{})
+				if name, ok := xdsGet(cm, "name").(string); ok {
+					r.clusters = append(r.clusters, name)
+				}
+			}
+		}
+	}
+	return r
+}
+
+// isXDSCluster recognizes an Envoy Cluster by its type URL or its fields.
+func isXDSCluster(m map[string]interface{}) bool {
+	if typ, _ := m["@type"].(string); strings.HasSuffix(typ, ".Cluster") {
+		return true
+	}
+	if _, ok := xdsGet(m, "name").(string); !ok {
+		return false
+	}
+	for _, k := range []string{"connecttimeout", "edsclusterconfig", "loadassignment", "clusterdiscoverytype", "lbpolicy"} {
+		if xdsGet(m, k) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parseXDSSnapshot collects the virtual hosts and clusters of an xDS
+// snapshot or config dump. It does not depend on the layout of the
+// document: route configurations are recognized by their virtual hosts.
+func parseXDSSnapshot(doc interface{}) *xdsConfig {
+	cfg := &xdsConfig{clusters: make(map[string]bool)}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if vhosts, ok := xdsGet(t, "virtualhosts").([]interface{}); ok {
+				for _, vh := range vhosts {
+					vm, _ := vh.(map[string]interface{})
+					if vm == nil {
+						continue
+					}
+					host := xdsVirtualHost{}
+					host.name, _ = xdsGet(vm, "name").(string)
+					domains, _ := xdsGet(vm, "domains").([]interface{})
+					for _, d := range domains {
+						if s, ok := d.(string); ok {
+							host.domains = append(host.domains, s)
+						}
+					}
+					routes, _ := xdsGet(vm, "routes").([]interface{})
+					for _, r := range routes {
+						if rm, ok := r.(map[string]interface{}); ok {
+							host.routes = append(host.routes, parseXDSRoute(rm))
+						}
+					}
+					cfg.vhosts = append(cfg.vhosts, host)
+				}
+				return
+			}
+			if isXDSCluster(t) {
+				name, _ := xdsGet(t, "name").(string)
+				cfg.clusters[name] = true
+				return
+			}
+			for _, child := range t {
+				walk(child)
+			}
+		case []interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		}
+	}
+	walk(doc)
+	return cfg
+}
+
+// gatewaySnapshot returns the part of a snapshot for one Gateway's proxies:
+// the entries whose key names the Gateway when the snapshot is keyed by
+// proxy, and the whole snapshot otherwise.
+func gatewaySnapshot(doc interface{}, gwNs, gwName string) (interface{}, bool) {
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return doc, false
+	}
+	var parts []interface{}
+	for k, v := range m {
+		if strings.Contains(k, gwNs) && strings.Contains(k, gwName) {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return doc, false
+	}
+	return parts, true
+}
+
+// xdsDomainMatches applies Envoy virtual host domain matching to a host.
+func xdsDomainMatches(domain, host string) bool {
+	domain, host = strings.ToLower(domain), strings.ToLower(host)
+	domain = strings.TrimSuffix(domain, ":*")
+	switch {
+	case domain == "*" || domain == host:
+		return true
+	case strings.HasPrefix(domain, "*"):
+		return strings.HasSuffix(host, domain[1:]) && len(host) > len(domain)-1
+	}
+	return false
+}
+
+// virtualHostsFor returns the virtual hosts serving a host: the most
+// specific domain wins in Envoy, so an exact domain shadows wildcards.
+func (c *xdsConfig) virtualHostsFor(host string) []xdsVirtualHost {
+	var exact, wildcard []xdsVirtualHost
+	for _, vh := range c.vhosts {
+		for _, d := range vh.domains {
+			if strings.EqualFold(strings.TrimSuffix(d, ":*"), host) {
+				exact = append(exact, vh)
+				break
+			}
+			if xdsDomainMatches(d, host) {
+				wildcard = append(wildcard, vh)
+				break
+			}
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return wildcard
+}
+
+// gatewayRuleMatches returns the path matches of an HTTPRoute rule as keys
+// comparable with xdsRouteMatch. A rule without matches matches every path.
+func gatewayRuleMatches(rule map[string]interface{}) []string {
+	matches, _ := rule["matches"].([]interface{})
+	if len(matches) == 0 {
+		return []string{"prefix:"}
+	}
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		mm, _ := m.(map[string]interface{})
+		pathType, value := "PathPrefix", "/"
+		if pm, ok := mm["path"].(map[string]interface{}); ok {
+			if t, _ := pm["type"].(string); t != "" {
+				pathType = t
+			}
+			if v, _ := pm["value"].(string); v != "" {
+				value = v
+			}
+		}
+		switch pathType {
+		case "Exact":
+			seen["exact:"+value] = true
+		case "RegularExpression":
+			seen["regex:"+value] = true
+		default:
+			seen["prefix:"+strings.TrimSuffix(value, "/")] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+// describeXDSMatch renders a match key the way a route declares it.
+func describeXDSMatch(key string) string {
+	kind, value, _ := strings.Cut(key, ":")
+	switch kind {
+	case "exact":
+		return "Exact " + value
+	case "regex":
+		return "RegularExpression " + value
+	}
+	return "PathPrefix " + orDefault(value, "/")
+}
+
+// clusterForService reports whether an Envoy cluster name designates a
+// Service, as kgateway names them (kube_<namespace>_<name>_<port>).
+func clusterForService(cluster, svc string) bool {
+	ns, name, _ := strings.Cut(svc, "/")
+	return strings.Contains(cluster, ns) && strings.Contains(cluster, name)
+}
+
+// ruleBackends returns the Service backends of an HTTPRoute rule.
+func ruleBackends(route routeInfo, rule map[string]interface{}) []string {
+	var out []string
+	refs, _ := rule["backendRefs"].([]interface{})
+	for _, b := range refs {
+		bm, _ := b.(map[string]interface{})
+		kind, _ := bm["kind"].(string)
+		name, _ := bm["name"].(string)
+		ns, _ := bm["namespace"].(string)
+		if name != "" && orDefault(kind, "Service") == "Service" {
+			out = append(out, orDefault(ns, route.namespace)+"/"+name)
+		}
+	}
+	return out
+}
+
+// diffRouteXDS compares the rules of an accepted HTTPRoute with the virtual
+// hosts of its Gateway's xDS configuration, for the hosts it is served on.
+func diffRouteXDS(route routeInfo, hosts []string, cfg *xdsConfig, gwLabel string) (findings []types.DiagnosticFinding, present int) {
+	ref := &types.ResourceRef{Kind: "HTTPRoute", Namespace: route.namespace, Name: route.name, APIVersion: "gateway.networking.k8s.io/v1"}
+	rules, _, _ := unstructured.NestedSlice(route.obj, "spec", "rules")
+	for _, host := range hosts {
+		vhosts := cfg.virtualHostsFor(host)
+		if len(vhosts) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeKgatewayRouteMissingFromXDS,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("HTTPRoute %s/%s is accepted but no virtual host of %s serves host %s", route.namespace, route.name, gwLabel, host),
+				Detail:     fmt.Sprintf("%d virtual hosts in the snapshot", len(cfg.vhosts)),
+				Suggestion: "Requests for this host get 404 from the proxy. Check the kgateway controller logs for translation errors on the route",
+			})
+			continue
+		}
+		byMatch := make(map[string][]xdsRoute)
+		var available []string
+		for _, vh := range vhosts {
+			for _, r := range vh.routes {
+				byMatch[r.match] = append(byMatch[r.match], r)
+				available = append(available, describeXDSMatch(r.match))
+			}
+		}
+		for i, r := range rules {
+			rm, _ := r.(map[string]interface{})
+			backends := ruleBackends(route, rm)
+			for _, match := range gatewayRuleMatches(rm) {
+				xr, ok := byMatch[match]
+				if !ok {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityCritical,
+						Category:   types.CategoryRouting,
+						Code:       types.CodeKgatewayRouteMissingFromXDS,
+						Resource:   ref,
+						Summary:    fmt.Sprintf("HTTPRoute %s/%s rule %d (%s on %s) is accepted but absent from the xDS of %s", route.namespace, route.name, i, describeXDSMatch(match), host, gwLabel),
+						Detail:     "Envoy routes for the host: " + truncateList(sortedSet(toSet(available)), 10),
+						Suggestion: "kgateway accepted the route but did not translate this rule; check the controller logs, and the filters and policies of the rule it may have failed to apply",
+					})
+					continue
+				}
+				present++
+				if problem := xdsBackendProblem(xr, backends, cfg.clusters); problem != "" {
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   types.SeverityWarning,
+						Category:   types.CategoryRouting,
+						Code:       types.CodeKgatewayXDSBackendMismatch,
+						Resource:   ref,
+						Summary:    fmt.Sprintf("HTTPRoute %s/%s rule %d (%s on %s) is in the xDS of %s, but %s", route.namespace, route.name, i, describeXDSMatch(match), host, gwLabel, problem),
+						Detail:     "Declared backends: " + orDefault(strings.Join(backends, ", "), "none"),
+						Suggestion: "Check that the backend Services and ports exist and are allowed by a ReferenceGrant; kgateway answers 500 for rules whose backends it cannot resolve",
+					})
+				}
+			}
+		}
+	}
+	return findings, present
+}
+
+// xdsBackendProblem compares the Envoy routes of a rule match with the
+// rule's backends. Redirects have no backend to compare.
+func xdsBackendProblem(routes []xdsRoute, backends []string, clusters map[string]bool) string {
+	var names []string
+	for _, r := range routes {
+		if r.redirect {
+			return ""
+		}
+		if r.direct > 0 && len(r.clusters) == 0 && len(backends) > 0 {
+			return fmt.Sprintf("Envoy answers %d directly instead of forwarding", r.direct)
+		}
+		names = append(names, r.clusters...)
+	}
+	for _, svc := range backends {
+		found := false
+		for _, c := range names {
+			found = found || clusterForService(c, svc)
+		}
+		if !found {
+			return fmt.Sprintf("no Envoy cluster of the route designates Service %s", svc)
+		}
+	}
+	if len(clusters) > 0 {
+		for _, c := range names {
+			if !clusters[c] {
+				return fmt.Sprintf("its cluster %s is not in the snapshot", c)
+			}
+		}
+	}
+	return ""
+}
+
+// --- diff_kgateway_xds ---
+
+type DiffKgatewayXDSTool struct{ BaseTool }
+
+func (t *DiffKgatewayXDSTool) Name() string { return "diff_kgateway_xds" }
+func (t *DiffKgatewayXDSTool) Description() string {
+	return "Compare the xDS snapshot the kgateway controller serves to a Gateway's proxies with the HTTPRoutes accepted on that Gateway, reporting hosts and rules present in the Gateway API resources but absent from the Envoy configuration, and rules forwarded to the wrong cluster: the question behind \"it's Accepted but traffic 404s\""
+}
+func (t *DiffKgatewayXDSTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"gateway": map[string]interface{}{
+				"type":        "string",
+				"description": "Gateway name",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Gateway namespace",
+			},
+			"route": map[string]interface{}{
+				"type":        "string",
+				"description": "Only compare this HTTPRoute, as namespace/name",
+			},
+			"control_plane_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the kgateway controller (default: kgateway-system)",
+			},
+			"admin_port": map[string]interface{}{
+				"type":        "string",
+				"description": "Controller admin port serving " + kgatewayXDSPath + " (default: " + kgatewayAdminPort + ")",
+			},
+		},
+		"required": []string{"gateway", "namespace"},
+	}
+}
+
+func (t *DiffKgatewayXDSTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	gwName := getStringArg(args, "gateway", "")
+	ns := getStringArg(args, "namespace", "")
+	routeFilter := getStringArg(args, "route", "")
+	cpNs := getStringArg(args, "control_plane_namespace", "kgateway-system")
+	port := getStringArg(args, "admin_port", kgatewayAdminPort)
+	if gwName == "" || ns == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "gateway and namespace are required",
+		}
+	}
+
+	gw, err := getWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns, gwName)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get Gateway %s/%s", ns, gwName),
+			Detail:  err.Error(),
+		}
+	}
+	gwLabel := fmt.Sprintf("Gateway %s/%s", ns, gwName)
+	gwRef := &types.ResourceRef{Kind: "Gateway", Namespace: ns, Name: gwName, APIVersion: gw.GetAPIVersion()}
+	var findings []types.DiagnosticFinding
+	if !isKgatewayManaged(gw) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("%s does not look managed by kgateway; comparing with the kgateway snapshot anyway", gwLabel),
+		})
+	}
+
+	// Snapshot from the first controller replica that serves it
+	raw, source, fetchErr := t.fetchSnapshot(ctx, cpNs, port)
+	if fetchErr != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Resource:   gwRef,
+			Summary:    "Could not read the xDS snapshot from the kgateway controller",
+			Detail:     fetchErr.Error(),
+			Suggestion: fmt.Sprintf("Check control_plane_namespace and admin_port; the snapshot is served on %s of the controller's admin port", kgatewayXDSPath),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "kgateway"), nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "the xDS snapshot is not JSON",
+			Detail:  err.Error(),
+		}
+	}
+	part, scoped := gatewaySnapshot(doc, ns, gwName)
+	cfg := parseXDSSnapshot(part)
+	if !scoped {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("The snapshot has no entry named after %s; comparing with the configuration of every proxy", gwLabel),
+			Detail:   "A rule served by another Gateway's proxies is then counted as present",
+		})
+	}
+
+	var listenerHosts []string
+	specs, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	listenerByName := make(map[string]string)
+	for _, s := range specs {
+		lm, _ := s.(map[string]interface{})
+		if protocol, _ := lm["protocol"].(string); protocol != "HTTP" && protocol != "HTTPS" {
+			continue
+		}
+		name, _ := lm["name"].(string)
+		host, _ := lm["hostname"].(string)
+		listenerByName[name] = host
+		listenerHosts = append(listenerHosts, host)
+	}
+
+	routes, rules, present := 0, 0, 0
+	var skipped []string
+	for _, r := range t.listRoutes(ctx) {
+		if r.kind != "HTTPRoute" || !routeAttachedToGateway(r, ns, gwName) {
+			continue
+		}
+		if routeFilter != "" && routeFilter != r.namespace+"/"+r.name {
+			continue
+		}
+		if !routeParentAccepted(r, ns, gwName) {
+			skipped = append(skipped, fmt.Sprintf("HTTPRoute %s/%s", r.namespace, r.name))
+			continue
+		}
+		routeHosts, _, _ := unstructured.NestedStringSlice(r.obj, "spec", "hostnames")
+		names, all := routeListenerNames(r, ns, gwName)
+		hostSet := make(map[string]bool)
+		for name, lh := range listenerByName {
+			if !all && !names[name] {
+				continue
+			}
+			hosts := listenerRouteHosts(lh, routeHosts)
+			if len(hosts) == 0 && len(routeHosts) == 0 {
+				hosts = []string{"*"}
+			}
+			for _, h := range hosts {
+				hostSet[h] = true
+			}
+		}
+		routes++
+		rs, _, _ := unstructured.NestedSlice(r.obj, "spec", "rules")
+		rules += len(rs)
+		f, n := diffRouteXDS(r, sortedSet(hostSet), cfg, gwLabel)
+		findings = append(findings, f...)
+		present += n
+	}
+
+	if len(skipped) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("%d HTTPRoutes are not accepted by %s and were not compared", len(skipped), gwLabel),
+			Detail:   truncateList(skipped, 10),
+		})
+	}
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryRouting,
+		Resource: gwRef,
+		Summary:  fmt.Sprintf("%d accepted HTTPRoutes of %s compared with the xDS snapshot: %d rule matches found", routes, gwLabel, present),
+		Detail:   fmt.Sprintf("Snapshot from %s: %d virtual hosts, %d clusters; %d rules declared", source, len(cfg.vhosts), len(cfg.clusters), rules),
+	}
+	for _, f := range findings {
+		if f.Severity == types.SeverityCritical || f.Severity == types.SeverityWarning {
+			summary.Severity = types.SeverityInfo
+		}
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "kgateway"), nil
+}
+
+// fetchSnapshot reads the xDS snapshot through the API server's pod proxy
+// from the first running controller replica that serves it.
+func (t *DiffKgatewayXDSTool) fetchSnapshot(ctx context.Context, ns, port string) ([]byte, string, error) {
+	var failed []string
+	for _, selector := range kgatewayControllerSelectors {
+		pods, err := t.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, "", fmt.Errorf("could not list kgateway controller pods in %s: %w", ns, err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+			raw, err := t.Clients.Clientset.CoreV1().Pods(ns).ProxyGet("http", pod.Name, port, kgatewayXDSPath, nil).DoRaw(ctx)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", pod.Name, err))
+				continue
+			}
+			return raw, "pod " + ns + "/" + pod.Name, nil
+		}
+	}
+	if len(failed) > 0 {
+		return nil, "", fmt.Errorf("no controller replica served %s on port %s: %s", kgatewayXDSPath, port, strings.Join(failed, "; "))
+	}
+	return nil, "", fmt.Errorf("no running kgateway controller pod in %s (selectors %s)", ns, strings.Join(kgatewayControllerSelectors, ", "))
+}
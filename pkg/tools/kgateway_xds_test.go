@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const kgatewayXDSSnapshot = `{
+  "infra~public": {
+    "Routes": {"Items": {"listener~80": {"Resource": {
+      "name": "listener~80",
+      "virtual_hosts": [{
+        "name": "shop.example.com",
+        "domains": ["shop.example.com"],
+        "routes": [
+          {"match": {"prefix": "/api/", "headers": [{"name": "x-v", "string_match": {"prefix": "2"}}]},
+           "route": {"cluster": "kube_shop_api_8080"}},
+          {"match": {"path": "/login"}, "direct_response": {"status": 500}},
+          {"match": {"prefix": "/"},
+           "route": {"weighted_clusters": {"clusters": [{"name": "kube_shop_web_80"}, {"name": "kube_shop_web-v2_80"}]}}}
+        ]
+      }]
+    }}}},
+    "Clusters": {"Items": {
+      "kube_shop_api_8080": {"Resource": {"name": "kube_shop_api_8080", "connect_timeout": "5s"}},
+      "kube_shop_web_80": {"Resource": {"name": "kube_shop_web_80", "eds_cluster_config": {}}}
+    }}
+  },
+  "infra~internal": {
+    "Routes": {"Items": {"listener~80": {"Resource": {"virtual_hosts": [{"domains": ["*"], "routes": []}]}}}}
+  }
+}`
+
+func TestParseXDSSnapshot(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(kgatewayXDSSnapshot), &doc); err != nil {
+		t.Fatal(err)
+	}
+	part, scoped := gatewaySnapshot(doc, "infra", "public")
+	if !scoped {
+		t.Fatal("snapshot not scoped to infra/public")
+	}
+	cfg := parseXDSSnapshot(part)
+	if len(cfg.vhosts) != 1 || !reflect.DeepEqual(cfg.clusters, map[string]bool{"kube_shop_api_8080": true, "kube_shop_web_80": true}) {
+		t.Fatalf("config = %+v", cfg)
+	}
+	want := []xdsRoute{
+		{match: "prefix:/api", clusters: []string{"kube_shop_api_8080"}},
+		{match: "exact:/login", direct: 500},
+		{match: "prefix:", clusters: []string{"kube_shop_web_80", "kube_shop_web-v2_80"}},
+	}
+	if got := cfg.vhosts[0].routes; !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %+v, want %+v", got, want)
+	}
+
+	if _, scoped := gatewaySnapshot(doc, "infra", "edge"); scoped {
+		t.Error("infra/edge has no entry in the snapshot")
+	}
+}
+
+func TestDiffRouteXDS(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(kgatewayXDSSnapshot), &doc); err != nil {
+		t.Fatal(err)
+	}
+	part, _ := gatewaySnapshot(doc, "infra", "public")
+	cfg := parseXDSSnapshot(part)
+
+	pathMatch := func(typ, value string) interface{} {
+		return map[string]interface{}{"path": map[string]interface{}{"type": typ, "value": value}}
+	}
+	backend := func(name string) interface{} { return map[string]interface{}{"name": name, "port": int64(80)} }
+	route := rateLimitRoute("web", "infra/public")
+	route.Object["spec"].(map[string]interface{})["rules"] = []interface{}{
+		map[string]interface{}{"matches": []interface{}{pathMatch("PathPrefix", "/api")}, "backendRefs": []interface{}{backend("api")}},
+		map[string]interface{}{"matches": []interface{}{pathMatch("Exact", "/login")}, "backendRefs": []interface{}{backend("auth")}},
+		map[string]interface{}{"backendRefs": []interface{}{backend("web"), backend("web-v2")}},
+		map[string]interface{}{"matches": []interface{}{pathMatch("PathPrefix", "/admin")}, "backendRefs": []interface{}{backend("admin")}},
+	}
+	ri := routeInfo{kind: "HTTPRoute", namespace: "shop", name: "web", obj: route.Object}
+
+	findings, present := diffRouteXDS(ri, []string{"shop.example.com", "admin.example.com"}, cfg, "Gateway infra/public")
+	if present != 3 {
+		t.Errorf("present = %d, want 3", present)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Summary+" "+string(f.Code))
+	}
+	want := []string{
+		"warning HTTPRoute shop/web rule 1 (Exact /login on shop.example.com) is in the xDS of Gateway infra/public, but Envoy answers 500 directly instead of forwarding " + string(types.CodeKgatewayXDSBackendMismatch),
+		"warning HTTPRoute shop/web rule 2 (PathPrefix / on shop.example.com) is in the xDS of Gateway infra/public, but its cluster kube_shop_web-v2_80 is not in the snapshot " + string(types.CodeKgatewayXDSBackendMismatch),
+		"critical HTTPRoute shop/web rule 3 (PathPrefix /admin on shop.example.com) is accepted but absent from the xDS of Gateway infra/public " + string(types.CodeKgatewayRouteMissingFromXDS),
+		"critical HTTPRoute shop/web is accepted but no virtual host of Gateway infra/public serves host admin.example.com " + string(types.CodeKgatewayRouteMissingFromXDS),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestXDSDomainMatches(t *testing.T) {
+	tests := []struct {
+		domain, host string
+		want         bool
+	}{
+		{"shop.example.com", "shop.example.com", true},
+		{"shop.example.com:*", "shop.example.com", true},
+		{"*.example.com", "shop.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*", "anything", true},
+		{"shop.example.com", "api.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := xdsDomainMatches(tt.domain, tt.host); got != tt.want {
+			t.Errorf("xdsDomainMatches(%q, %q) = %v, want %v", tt.domain, tt.host, got, tt.want)
+		}
+	}
+}
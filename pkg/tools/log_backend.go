@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/logbackend"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Log sources of get_proxy_logs and analyze_log_errors.
+const (
+	logSourceNode    = "node"
+	logSourceBackend = "backend"
+)
+
+// defaultBackendSince is how far back log backend queries look by default.
+const defaultBackendSince = time.Hour
+
+var logSourceProperty = map[string]interface{}{
+	"type":        "string",
+	"enum":        []string{logSourceNode, logSourceBackend},
+	"description": "Read the logs kept on the node (default) or the configured log backend (Loki or Elasticsearch), which keeps older logs and those of deleted pods",
+}
+
+// useLogBackend reads the source argument: whether to read the log backend
+// rather than the logs kept on the node.
+func useLogBackend(tool string, args map[string]interface{}, backend *logbackend.Client) (bool, error) {
+	switch source := getStringArg(args, "source", logSourceNode); source {
+	case logSourceNode:
+		return false, nil
+	case logSourceBackend:
+		if backend == nil {
+			return false, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    tool,
+				Message: "no log backend is configured",
+				Detail:  "set LOG_BACKEND_URL (and LOG_BACKEND) to read logs from Loki or Elasticsearch",
+			}
+		}
+		return true, nil
+	default:
+		return false, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    tool,
+			Message: fmt.Sprintf("invalid source %q: expected node or backend", source),
+		}
+	}
+}
+
+// queryLogBackend runs q for tool, defaulting since to defaultBackendSince.
+func queryLogBackend(ctx context.Context, tool string, backend *logbackend.Client, q logbackend.Query, since string) ([]logbackend.Line, error) {
+	q.Since = defaultBackendSince
+	if since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    tool,
+				Message: fmt.Sprintf("invalid since %q", since),
+				Detail:  "use a duration such as 30m or 24h",
+			}
+		}
+		q.Since = d
+	}
+	lines, err := backend.Logs(ctx, q)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    tool,
+			Message: fmt.Sprintf("failed to query %s: %v", backend.Backend(), err),
+			Detail:  "check LOG_BACKEND_URL and LOG_BACKEND and that the server can reach the backend",
+		}
+	}
+	return lines, nil
+}
+
+// runBackend serves get_proxy_logs from the log backend. A deployment
+// matches its pods by name prefix, so that replicas deleted since, e.g. by a
+// rollout, are included; a label selector only matches current pods.
+func (t *GetProxyLogsTool) runBackend(ctx context.Context, args map[string]interface{}, ns, podName, container string, tail int64, since string) (*StandardResponse, error) {
+	deployment := getStringArg(args, "deployment", "")
+	selector := getStringArg(args, "label_selector", "")
+	q := logbackend.Query{Namespace: ns, Limit: int(tail)}
+	if container != "" {
+		q.Containers = []string{container}
+	} else {
+		q.Containers = proxyContainerNames
+	}
+
+	var source string
+	var ref *types.ResourceRef
+	switch {
+	case podName != "":
+		q.Pods = []string{podName}
+		source = fmt.Sprintf("pod %s/%s", ns, podName)
+		ref = &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: podName}
+	case deployment != "":
+		q.PodPrefix = deployment + "-"
+		source = fmt.Sprintf("deployment %s/%s", ns, deployment)
+		ref = &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: deployment, APIVersion: "apps/v1"}
+	case selector != "":
+		pods, _, err := t.selectedPods(ctx, ns, "", selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			q.Pods = append(q.Pods, pod.Name)
+		}
+		source = fmt.Sprintf("pods matching %s in %s", selector, ns)
+		ref = &types.ResourceRef{Kind: "Pod", Namespace: ns}
+		if len(q.Pods) == 0 {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("no running pods found for %s", source),
+				Detail:  "the log backend is searched by pod name; use deployment to include deleted replicas",
+			}
+		}
+	default:
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "one of pod, deployment or label_selector is required",
+		}
+	}
+
+	lines, err := queryLogBackend(ctx, t.Name(), t.LogBackend, q, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryLogs,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("No %s log lines for %s in %s", strings.Join(q.Containers, "/"), source, t.LogBackend.Backend()),
+			Suggestion: "Widen since, and check that the backend labels logs with namespace, pod and container",
+		}}, ns, ""), nil
+	}
+
+	merged := make([]replicaLogLine, len(lines))
+	pods := make(map[string]bool)
+	for i, l := range lines {
+		merged[i] = replicaLogLine{ts: l.Time, pod: l.Pod, text: l.Text}
+		pods[l.Pod] = true
+	}
+	logs, kept := mergeReplicaLogs(merged, maxLogBytes)
+
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryLogs,
+		Resource: ref,
+		Summary:  fmt.Sprintf("Retrieved %d log lines from %d pods of %s in %s", kept, len(pods), source, t.LogBackend.Backend()),
+		Detail:   logs,
+	}}
+	if kept < len(lines) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsOutputTruncated,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Log output truncated at 100KB limit for %s; the oldest lines were dropped", source),
+			Suggestion: "Use a smaller --tail value or narrower --since window to avoid truncation",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// backendLogs reads the logs analyze_log_errors analyzes from the log
+// backend: those of container, or of every container of the pod.
+func (t *AnalyzeLogErrorsTool) backendLogs(ctx context.Context, ns, podName, container string, tail int64, since string) (*logResult, error) {
+	q := logbackend.Query{Namespace: ns, Pods: []string{podName}, Limit: int(tail)}
+	if container != "" {
+		q.Containers = []string{container}
+	}
+	lines, err := queryLogBackend(ctx, t.Name(), t.LogBackend, q, since)
+	if err != nil {
+		return nil, err
+	}
+	return backendLogResult(lines, maxLogBytes), nil
+}
+
+// backendLogResult joins the newest of lines, oldest first, that fit in
+// maxBytes.
+func backendLogResult(lines []logbackend.Line, maxBytes int) *logResult {
+	size, first := 0, len(lines)
+	for first > 0 && size+len(lines[first-1].Text)+1 <= maxBytes {
+		size += len(lines[first-1].Text) + 1
+		first--
+	}
+	var b strings.Builder
+	b.Grow(size)
+	for _, l := range lines[first:] {
+		b.WriteString(l.Text)
+		b.WriteByte('\n')
+	}
+	return &logResult{logs: b.String(), truncated: first > 0, returnedLines: len(lines) - first}
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/logbackend"
+)
+
+func TestUseLogBackend(t *testing.T) {
+	backend, err := logbackend.NewClient("loki", "http://loki:3100", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if use, err := useLogBackend("get_proxy_logs", map[string]interface{}{}, backend); use || err != nil {
+		t.Errorf("default source: use = %v, err = %v", use, err)
+	}
+	if use, err := useLogBackend("get_proxy_logs", map[string]interface{}{"source": "backend"}, backend); !use || err != nil {
+		t.Errorf("backend source: use = %v, err = %v", use, err)
+	}
+	if _, err := useLogBackend("get_proxy_logs", map[string]interface{}{"source": "backend"}, nil); err == nil {
+		t.Error("backend source without a backend was accepted")
+	}
+	if _, err := useLogBackend("get_proxy_logs", map[string]interface{}{"source": "loki"}, backend); err == nil {
+		t.Error("invalid source was accepted")
+	}
+}
+
+func TestBackendLogResult(t *testing.T) {
+	now := time.Now()
+	lines := []logbackend.Line{
+		{Time: now, Text: "first"},
+		{Time: now.Add(time.Second), Text: "second"},
+		{Time: now.Add(2 * time.Second), Text: "third"},
+	}
+	if lr := backendLogResult(lines, maxLogBytes); lr.logs != "first\nsecond\nthird\n" || lr.truncated || lr.returnedLines != 3 {
+		t.Errorf("result = %+v", lr)
+	}
+	if lr := backendLogResult(lines, len("second\nthird\n")); lr.logs != "second\nthird\n" || !lr.truncated || lr.returnedLines != 2 {
+		t.Errorf("truncated result = %+v", lr)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/logbackend"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
@@ -32,21 +33,27 @@ type logResult struct {
 
 // --- get_proxy_logs ---
 
-type GetProxyLogsTool struct{ BaseTool }
+type GetProxyLogsTool struct {
+	BaseTool
+	LogBackend *logbackend.Client
+}
 
 func (t *GetProxyLogsTool) Name() string        { return "get_proxy_logs" }
-func (t *GetProxyLogsTool) Description() string  { return "Get logs from Envoy/proxy sidecars (auto-detects istio-proxy, envoy, linkerd-proxy containers)" }
+func (t *GetProxyLogsTool) Description() string  { return "Get logs from Envoy/proxy sidecars (auto-detects istio-proxy, envoy, linkerd-proxy containers) of a pod, or of all replicas of a deployment or label selector merged by timestamp" }
 func (t *GetProxyLogsTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
-			"pod":       map[string]interface{}{"type": "string", "description": "Pod name"},
-			"namespace": map[string]interface{}{"type": "string", "description": "Kubernetes namespace"},
-			"container": map[string]interface{}{"type": "string", "description": "Container name (auto-detects proxy container if not specified)"},
-			"tail":      map[string]interface{}{"type": "number", "description": "Number of lines from the end (default 100)"},
-			"since":     map[string]interface{}{"type": "string", "description": "Duration to look back (e.g., 5m, 1h)"},
+			"pod":            map[string]interface{}{"type": "string", "description": "Pod name (or use deployment or label_selector)"},
+			"deployment":     map[string]interface{}{"type": "string", "description": "Deployment whose replicas to read logs from"},
+			"label_selector": map[string]interface{}{"type": "string", "description": "Label selector of the replicas to read logs from (e.g., app=web)"},
+			"namespace":      map[string]interface{}{"type": "string", "description": "Kubernetes namespace"},
+			"container":      map[string]interface{}{"type": "string", "description": "Container name (auto-detects proxy container if not specified)"},
+			"tail":           map[string]interface{}{"type": "number", "description": "Number of lines from the end, per replica (default 100)"},
+			"since":          map[string]interface{}{"type": "string", "description": "Duration to look back (e.g., 5m, 1h; default 1h with source=backend)"},
+			"source":         logSourceProperty,
 		},
-		"required": []string{"pod", "namespace"},
+		"required": []string{"namespace"},
 	}
 }
 
@@ -57,6 +64,17 @@ func (t *GetProxyLogsTool) Run(ctx context.Context, args map[string]interface{})
 	tail := getIntArg(args, "tail", 100)
 	since := getStringArg(args, "since", "")
 
+	useBackend, err := useLogBackend(t.Name(), args, t.LogBackend)
+	if err != nil {
+		return nil, err
+	}
+	if useBackend {
+		return t.runBackend(ctx, args, ns, podName, container, int64(tail), since)
+	}
+	if podName == "" {
+		return t.runReplicas(ctx, args, ns, container, int64(tail), since)
+	}
+
 	if container == "" {
 		pod, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
 		if err != nil {
@@ -96,6 +114,7 @@ func (t *GetProxyLogsTool) Run(ctx context.Context, args map[string]interface{})
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsOutputTruncated,
 			Summary:    fmt.Sprintf("Log output truncated at 100KB limit for %s/%s container %s", ns, podName, container),
 			Suggestion: "Use a smaller --tail value or narrower --since window to avoid truncation",
 		})
@@ -174,6 +193,7 @@ func (t *GetGatewayLogsTool) Run(ctx context.Context, args map[string]interface{
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   types.SeverityWarning,
 					Category:   types.CategoryLogs,
+					Code:       types.CodeLogsOutputTruncated,
 					Summary:    fmt.Sprintf("Log output truncated at 100KB limit for %s/%s container %s", pod.Namespace, pod.Name, container),
 					Suggestion: "Use a smaller --tail value or narrower --since window to avoid truncation",
 				})
@@ -202,17 +222,17 @@ func (t *GetGatewayLogsTool) Run(ctx context.Context, args map[string]interface{
 type GetInfraLogsTool struct{ BaseTool }
 
 func (t *GetInfraLogsTool) Name() string        { return "get_infra_logs" }
-func (t *GetInfraLogsTool) Description() string  { return "Get logs from kube-proxy, CoreDNS, or CNI pods" }
+func (t *GetInfraLogsTool) Description() string  { return "Get logs from kube-proxy, CoreDNS, CNI or ingress-nginx controller pods" }
 func (t *GetInfraLogsTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"component": map[string]interface{}{
 				"type":        "string",
-				"description": "Infrastructure component: kube-proxy, coredns, or cni",
-				"enum":        []string{"kube-proxy", "coredns", "cni"},
+				"description": "Infrastructure component: kube-proxy, coredns, cni, or ingress-nginx",
+				"enum":        []string{"kube-proxy", "coredns", "cni", "ingress-nginx"},
 			},
-			"namespace": map[string]interface{}{"type": "string", "description": "Namespace override (default: kube-system)"},
+			"namespace": map[string]interface{}{"type": "string", "description": "Namespace override (default: kube-system, ingress-nginx for ingress-nginx)"},
 			"tail":      map[string]interface{}{"type": "number", "description": "Number of lines from the end (default 100)"},
 			"since":     map[string]interface{}{"type": "string", "description": "Duration to look back (e.g., 5m, 1h)"},
 		},
@@ -222,12 +242,18 @@ func (t *GetInfraLogsTool) InputSchema() map[string]interface{} {
 
 func (t *GetInfraLogsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	component := getStringArg(args, "component", "")
-	ns := getStringArg(args, "namespace", "kube-system")
+	defaultNs := "kube-system"
+	if component == "ingress-nginx" {
+		defaultNs = ingressNginxNamespace
+	}
+	ns := getStringArg(args, "namespace", defaultNs)
 	tail := getIntArg(args, "tail", 100)
 	since := getStringArg(args, "since", "")
 
 	var labelSelector string
 	switch component {
+	case "ingress-nginx":
+		labelSelector = ingressNginxSelector
 	case "kube-proxy":
 		labelSelector = "k8s-app=kube-proxy"
 	case "coredns":
@@ -263,7 +289,7 @@ func (t *GetInfraLogsTool) Run(ctx context.Context, args map[string]interface{})
 			Code:    types.ErrCodeInvalidInput,
 			Tool:    t.Name(),
 			Message: fmt.Sprintf("unsupported component: %s", component),
-			Detail:  "supported components: kube-proxy, coredns, cni",
+			Detail:  "supported components: kube-proxy, coredns, cni, ingress-nginx",
 		}
 	}
 
@@ -303,6 +329,7 @@ func (t *GetInfraLogsTool) Run(ctx context.Context, args map[string]interface{})
 			findings = append(findings, types.DiagnosticFinding{
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryLogs,
+				Code:       types.CodeLogsOutputTruncated,
 				Summary:    fmt.Sprintf("Log output truncated at 100KB limit for %s/%s container %s", ns, pod.Name, container),
 				Suggestion: "Use a smaller --tail value or narrower --since window to avoid truncation",
 			})
@@ -314,22 +341,26 @@ func (t *GetInfraLogsTool) Run(ctx context.Context, args map[string]interface{})
 
 // --- analyze_log_errors ---
 
-type AnalyzeLogErrorsTool struct{ BaseTool }
+type AnalyzeLogErrorsTool struct {
+	BaseTool
+	LogBackend *logbackend.Client
+}
 
 func (t *AnalyzeLogErrorsTool) Name() string        { return "analyze_log_errors" }
-func (t *AnalyzeLogErrorsTool) Description() string  { return "Read logs and extract error/warning lines related to misconfig, rate limiting, connection issues, TLS errors" }
+func (t *AnalyzeLogErrorsTool) Description() string  { return "Read logs and extract error/warning lines related to misconfig, rate limiting, connection issues, TLS errors; Envoy access logs are aggregated by response code, response flags and upstream" }
 func (t *AnalyzeLogErrorsTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withPagination(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"pod":       map[string]interface{}{"type": "string", "description": "Pod name"},
 			"namespace": map[string]interface{}{"type": "string", "description": "Kubernetes namespace"},
 			"container": map[string]interface{}{"type": "string", "description": "Container name (optional, uses first container)"},
 			"tail":      map[string]interface{}{"type": "number", "description": "Number of lines to analyze (default 500)"},
-			"since":     map[string]interface{}{"type": "string", "description": "Duration to look back (e.g., 5m, 1h)"},
+			"since":     map[string]interface{}{"type": "string", "description": "Duration to look back (e.g., 5m, 1h; default 1h with source=backend)"},
+			"source":    logSourceProperty,
 		},
 		"required": []string{"pod", "namespace"},
-	}
+	})
 }
 
 const maxErrorLines = 50
@@ -340,22 +371,40 @@ func (t *AnalyzeLogErrorsTool) Run(ctx context.Context, args map[string]interfac
 	container := getStringArg(args, "container", "")
 	tail := getIntArg(args, "tail", 500)
 	since := getStringArg(args, "since", "")
+	page := getPageArgs(args)
+	offset, err := decodeOffsetToken(t.Name(), page.Continue)
+	if err != nil {
+		return nil, err
+	}
 
-	if container == "" {
-		pod, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get pod %s/%s: %w", ns, podName, err)
+	useBackend, err := useLogBackend(t.Name(), args, t.LogBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	var lr *logResult
+	if useBackend {
+		// The pod may be gone: read all its containers unless one is named
+		lr, err = t.backendLogs(ctx, ns, podName, container, int64(tail), since)
+		if container == "" {
+			container = "*"
 		}
-		if len(pod.Spec.Containers) > 0 {
-			// Prefer proxy container if found, otherwise first container
-			container = findProxyContainer(pod)
-			if container == "" {
-				container = pod.Spec.Containers[0].Name
+	} else {
+		if container == "" {
+			pod, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pod %s/%s: %w", ns, podName, err)
+			}
+			if len(pod.Spec.Containers) > 0 {
+				// Prefer proxy container if found, otherwise first container
+				container = findProxyContainer(pod)
+				if container == "" {
+					container = pod.Spec.Containers[0].Name
+				}
 			}
 		}
+		lr, err = getPodLogs(ctx, t.Clients, ns, podName, container, int64(tail), since)
 	}
-
-	lr, err := getPodLogs(ctx, t.Clients, ns, podName, container, int64(tail), since)
 	if err != nil {
 		return nil, err
 	}
@@ -377,34 +426,26 @@ func (t *AnalyzeLogErrorsTool) Run(ctx context.Context, args map[string]interfac
 	}
 
 	totalErrorLines := 0
+	var accessLog accessLogStats
 	scanner := bufio.NewScanner(strings.NewReader(lr.logs))
 	for scanner.Scan() {
 		line := scanner.Text()
+		// Envoy access logs are aggregated by response code and flags
+		// rather than matched as free text
+		if entry, ok := parseEnvoyAccessLog(line); ok {
+			accessLog.add(entry)
+			continue
+		}
 		if !errorPatterns.MatchString(line) {
 			continue
 		}
 		totalErrorLines++
-
-		lower := strings.ToLower(line)
-		var cat string
-		switch {
-		case strings.Contains(lower, "connection refused") || strings.Contains(lower, "no healthy"):
-			cat = "connection_errors"
-		case strings.Contains(lower, "tls"):
-			cat = "tls_errors"
-		case strings.Contains(lower, "rate") || strings.Contains(lower, "429") || strings.Contains(lower, "overflow"):
-			cat = "rate_limiting"
-		case strings.Contains(lower, "misconfigur") || strings.Contains(lower, "invalid"):
-			cat = "misconfig"
-		case strings.Contains(lower, "rbac") || strings.Contains(lower, "denied") || strings.Contains(lower, "403"):
-			cat = "rbac_denied"
-		case strings.Contains(lower, "upstream") || strings.Contains(lower, "503") || strings.Contains(lower, "circuit"):
-			cat = "upstream_issues"
-		case strings.Contains(lower, "timeout"):
-			cat = "timeout"
-		default:
-			cat = "other_errors"
+		// With pagination, only categorize error lines within the requested page
+		if index := totalErrorLines - 1; index < offset || (page.Limit > 0 && index >= offset+int(page.Limit)) {
+			continue
 		}
+
+		cat := logErrorCategory(line)
 		categoryMap[cat].lines = append(categoryMap[cat].lines, line)
 	}
 
@@ -414,15 +455,20 @@ func (t *AnalyzeLogErrorsTool) Run(ctx context.Context, args map[string]interfac
 		Name:      podName,
 	}
 
+	accessFindings := accessLog.findings(podRef, fmt.Sprintf("%s/%s container %s", ns, podName, container))
+
 	// No errors found — return ok finding
 	if totalErrorLines == 0 {
-		findings := []types.DiagnosticFinding{
-			{
-				Severity: types.SeverityOK,
-				Category: types.CategoryLogs,
-				Resource: podRef,
-				Summary:  fmt.Sprintf("No error patterns found in %d log lines from %s/%s container %s", lr.returnedLines, ns, podName, container),
-			},
+		findings := accessFindings
+		if len(findings) == 0 {
+			findings = []types.DiagnosticFinding{
+				{
+					Severity: types.SeverityOK,
+					Category: types.CategoryLogs,
+					Resource: podRef,
+					Summary:  fmt.Sprintf("No error patterns found in %d log lines from %s/%s container %s", lr.returnedLines, ns, podName, container),
+				},
+			}
 		}
 		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
 	}
@@ -450,13 +496,16 @@ func (t *AnalyzeLogErrorsTool) Run(ctx context.Context, args map[string]interfac
 		}
 
 		severity := types.SeverityWarning
+		code := types.CodeLogsErrorsFound
 		if catName == "other_errors" {
 			severity = types.SeverityInfo
+			code = ""
 		}
 
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryLogs,
+			Code:     code,
 			Resource: podRef,
 			Summary:  fmt.Sprintf("%d %s lines in %s/%s container %s", len(cl.lines), catName, ns, podName, container),
 			Detail:   strings.Join(detail, "\n"),
@@ -467,25 +516,64 @@ func (t *AnalyzeLogErrorsTool) Run(ctx context.Context, args map[string]interfac
 	summaryFinding := types.DiagnosticFinding{
 		Severity: types.SeverityWarning,
 		Category: types.CategoryLogs,
+		Code:     types.CodeLogsErrorsFound,
 		Resource: podRef,
 		Summary:  fmt.Sprintf("Found %d error lines in %d log lines from %s/%s container %s: %s", totalErrorLines, lr.returnedLines, ns, podName, container, strings.Join(countParts, ", ")),
 	}
 	findings = append([]types.DiagnosticFinding{summaryFinding}, findings...)
+	findings = append(findings, accessFindings...)
+
+	next := ""
+	if page.paged() {
+		end := offset + int(page.Limit)
+		if page.Limit == 0 || end > totalErrorLines {
+			end = totalErrorLines
+		}
+		summaryFinding.Summary += fmt.Sprintf(" (categorized lines %d-%d of %d)", min(offset+1, end), end, totalErrorLines)
+		findings[0] = summaryFinding
+		if end < totalErrorLines {
+			next = encodeOffsetToken(end)
+		}
+	}
 
 	if lr.truncated {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsOutputTruncated,
 			Summary:    fmt.Sprintf("Log input was truncated at 100KB limit for %s/%s container %s — error counts may be incomplete", ns, podName, container),
 			Suggestion: "Use a smaller --tail value or narrower --since window to get complete analysis",
 		})
 	}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "").WithContinue(next), nil
 }
 
 // Helper functions
 
+// logErrorCategory sorts an error line into one of analyze_log_errors'
+// categories.
+func logErrorCategory(line string) string {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "connection refused") || strings.Contains(lower, "no healthy"):
+		return "connection_errors"
+	case strings.Contains(lower, "tls"):
+		return "tls_errors"
+	case strings.Contains(lower, "rate") || strings.Contains(lower, "429") || strings.Contains(lower, "overflow"):
+		return "rate_limiting"
+	case strings.Contains(lower, "misconfigur") || strings.Contains(lower, "invalid"):
+		return "misconfig"
+	case strings.Contains(lower, "rbac") || strings.Contains(lower, "denied") || strings.Contains(lower, "403"):
+		return "rbac_denied"
+	case strings.Contains(lower, "upstream") || strings.Contains(lower, "503") || strings.Contains(lower, "circuit"):
+		return "upstream_issues"
+	case strings.Contains(lower, "timeout"):
+		return "timeout"
+	}
+	return "other_errors"
+}
+
 func findProxyContainer(pod *corev1.Pod) string {
 	// Check regular containers first
 	for _, c := range pod.Spec.Containers {
@@ -508,6 +596,12 @@ func findProxyContainer(pod *corev1.Pod) string {
 }
 
 func getPodLogs(ctx context.Context, clients *k8s.Clients, namespace, podName, container string, tailLines int64, since string) (*logResult, error) {
+	return readPodLogs(ctx, clients, namespace, podName, podLogOptions(container, tailLines, since))
+}
+
+// podLogOptions returns the options of a log request for the last tailLines
+// lines within the since duration, if set.
+func podLogOptions(container string, tailLines int64, since string) *corev1.PodLogOptions {
 	opts := &corev1.PodLogOptions{
 		Container: container,
 		TailLines: &tailLines,
@@ -520,11 +614,15 @@ func getPodLogs(ctx context.Context, clients *k8s.Clients, namespace, podName, c
 			opts.SinceTime = &sinceTime
 		}
 	}
+	return opts
+}
 
+// readPodLogs reads up to maxLogBytes of a container's logs.
+func readPodLogs(ctx context.Context, clients *k8s.Clients, namespace, podName string, opts *corev1.PodLogOptions) (*logResult, error) {
 	req := clients.Clientset.CoreV1().Pods(namespace).GetLogs(podName, opts)
 	stream, err := req.Stream(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get logs for %s/%s/%s: %w", namespace, podName, container, err)
+		return nil, fmt.Errorf("failed to get logs for %s/%s/%s: %w", namespace, podName, opts.Container, err)
 	}
 	defer func() { _ = stream.Close() }()
 
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+var errManifestClientReadOnly = errors.New("manifest client is read-only")
+
+// manifestClient is a read-only dynamic.Interface that serves objects parsed
+// from manifests, layered over an optional live client. Validators written
+// against the dynamic client run unchanged on it: manifest objects replace
+// live objects of the same name, and without a base client the cluster looks
+// empty. Objects are keyed by group and resource so a v1beta1 manifest is
+// also seen by validators that list v1 first.
+type manifestClient struct {
+	base    dynamic.Interface
+	objects map[schema.GroupResource]map[string]*unstructured.Unstructured
+}
+
+func newManifestClient(base dynamic.Interface) *manifestClient {
+	return &manifestClient{base: base, objects: make(map[schema.GroupResource]map[string]*unstructured.Unstructured)}
+}
+
+// add stores obj as a gvr object, replacing an earlier one of the same name.
+func (c *manifestClient) add(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	gr := gvr.GroupResource()
+	if c.objects[gr] == nil {
+		c.objects[gr] = make(map[string]*unstructured.Unstructured)
+	}
+	c.objects[gr][qualifiedName(obj.GetNamespace(), obj.GetName())] = obj
+}
+
+func (c *manifestClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &manifestResource{client: c, gvr: gvr}
+}
+
+type manifestResource struct {
+	client *manifestClient
+	gvr    schema.GroupVersionResource
+	ns     string
+}
+
+func (r *manifestResource) Namespace(ns string) dynamic.ResourceInterface {
+	return &manifestResource{client: r.client, gvr: r.gvr, ns: ns}
+}
+
+func (r *manifestResource) baseResource() dynamic.ResourceInterface {
+	if r.ns == "" {
+		return r.client.base.Resource(r.gvr)
+	}
+	return r.client.base.Resource(r.gvr).Namespace(r.ns)
+}
+
+func (r *manifestResource) Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if obj, ok := r.client.objects[r.gvr.GroupResource()][qualifiedName(r.ns, name)]; ok && len(subresources) == 0 {
+		return obj.DeepCopy(), nil
+	}
+	if r.client.base == nil {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	return r.baseResource().Get(ctx, name, opts, subresources...)
+}
+
+// List merges the live objects, if any, with the manifest objects matching
+// the namespace and label selector. A failing live List, e.g. for a CRD the
+// cluster does not serve, leaves only the manifest objects.
+func (r *manifestResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector := labels.Everything()
+	if opts.LabelSelector != "" {
+		sel, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		selector = sel
+	}
+
+	out := &unstructured.UnstructuredList{}
+	own := r.client.objects[r.gvr.GroupResource()]
+	if r.client.base != nil {
+		if live, err := r.baseResource().List(ctx, opts); err == nil {
+			for _, item := range live.Items {
+				if _, replaced := own[qualifiedName(item.GetNamespace(), item.GetName())]; !replaced {
+					out.Items = append(out.Items, item)
+				}
+			}
+		}
+	}
+	keys := make([]string, 0, len(own))
+	for key := range own {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		obj := own[key]
+		if r.ns != "" && obj.GetNamespace() != r.ns {
+			continue
+		}
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			out.Items = append(out.Items, *obj.DeepCopy())
+		}
+	}
+	return out, nil
+}
+
+func (r *manifestResource) Create(context.Context, *unstructured.Unstructured, metav1.CreateOptions, ...string) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) Update(context.Context, *unstructured.Unstructured, metav1.UpdateOptions, ...string) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) UpdateStatus(context.Context, *unstructured.Unstructured, metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) Delete(context.Context, string, metav1.DeleteOptions, ...string) error {
+	return errManifestClientReadOnly
+}
+
+func (r *manifestResource) DeleteCollection(context.Context, metav1.DeleteOptions, metav1.ListOptions) error {
+	return errManifestClientReadOnly
+}
+
+func (r *manifestResource) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) Patch(context.Context, string, k8stypes.PatchType, []byte, metav1.PatchOptions, ...string) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) Apply(context.Context, string, *unstructured.Unstructured, metav1.ApplyOptions, ...string) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
+
+func (r *manifestResource) ApplyStatus(context.Context, string, *unstructured.Unstructured, metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+	return nil, errManifestClientReadOnly
+}
@@ -0,0 +1,410 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	calicoFelixConfigGVR = schema.GroupVersionResource{Group: "crd.projectcalico.org", Version: "v1", Resource: "felixconfigurations"}
+	calicoIPPoolGVR      = schema.GroupVersionResource{Group: "crd.projectcalico.org", Version: "v1", Resource: "ippools"}
+)
+
+// Per-packet encapsulation overhead in bytes (IPv4 outer headers).
+var encapOverheads = map[string]int{
+	"vxlan":     50,
+	"geneve":    50,
+	"ipip":      20,
+	"wireguard": 60,
+	"ipsec":     73,
+	"udp":       28,
+	"none":      0,
+}
+
+// tcpIPv4HeaderBytes is the IPv4 + TCP header size added on top of the MSS.
+const tcpIPv4HeaderBytes = 40
+
+// cniMTUConfig is the MTU-relevant configuration of one CNI datapath.
+type cniMTUConfig struct {
+	Provider string
+	Resource *types.ResourceRef
+	MTU      int      // explicitly configured pod MTU, 0 when auto-detected
+	Encaps   []string // encapsulation layers stacked on the pod packet
+}
+
+// overhead returns the total encapsulation overhead for the datapath.
+func (c cniMTUConfig) overhead() int {
+	total := 0
+	for _, e := range c.Encaps {
+		total += encapOverheads[e]
+	}
+	return total
+}
+
+// effectiveMTU returns the pod MTU in use: the configured value, or the node MTU minus overhead when auto.
+func (c cniMTUConfig) effectiveMTU(nodeMTU int) int {
+	if c.MTU > 0 {
+		return c.MTU
+	}
+	return nodeMTU - c.overhead()
+}
+
+// meshMSSSetting is a TCP_MAXSEG socket option applied by a mesh or gateway proxy.
+type meshMSSSetting struct {
+	Resource *types.ResourceRef
+	MSS      int
+}
+
+// --- check_mtu_consistency ---
+
+type CheckMTUConsistencyTool struct{ BaseTool }
+
+func (t *CheckMTUConsistencyTool) Name() string { return "check_mtu_consistency" }
+func (t *CheckMTUConsistencyTool) Description() string {
+	return "Cross-check CNI MTU settings (Cilium, Calico, Flannel) against encapsulation overhead (VXLAN, Geneve, IPIP, WireGuard) and mesh/gateway advertised TCP MSS, flagging combinations that stall large payloads while small requests succeed"
+}
+func (t *CheckMTUConsistencyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"node_mtu": map[string]interface{}{
+				"type":        "integer",
+				"description": "MTU of the node/underlay network interface (default: 1500; use 9001 for AWS jumbo frames, 1460 for GCP)",
+			},
+		},
+	}
+}
+
+func (t *CheckMTUConsistencyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	nodeMTU := getIntArg(args, "node_mtu", 1500)
+	if nodeMTU < 576 || nodeMTU > 9216 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("node_mtu %d out of range (576-9216)", nodeMTU),
+		}
+	}
+
+	cnis := t.cniMTUConfigs(ctx)
+	mss := t.meshMSSSettings(ctx)
+
+	findings := evaluateMTUConsistency(nodeMTU, cnis, mss)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+// evaluateMTUConsistency compares CNI MTU, encapsulation overhead, and proxy MSS settings.
+func evaluateMTUConsistency(nodeMTU int, cnis []cniMTUConfig, mss []meshMSSSetting) []types.DiagnosticFinding {
+	findings := make([]types.DiagnosticFinding, 0, len(cnis)+len(mss)+1)
+
+	if len(cnis) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  "no Cilium, Calico, or Flannel MTU configuration found; assuming pod MTU equals node MTU",
+			Detail:   fmt.Sprintf("nodeMTU=%d", nodeMTU),
+		})
+	}
+
+	podMTU := nodeMTU
+	for i, c := range cnis {
+		eff := c.effectiveMTU(nodeMTU)
+		if i == 0 || eff < podMTU {
+			podMTU = eff
+		}
+		encap := strings.Join(c.Encaps, "+")
+		if encap == "" {
+			encap = "none"
+		}
+		mtuStr := "auto"
+		if c.MTU > 0 {
+			mtuStr = strconv.Itoa(c.MTU)
+		}
+		detail := fmt.Sprintf("provider=%s configuredMTU=%s encapsulation=%s overhead=%d nodeMTU=%d effectivePodMTU=%d", c.Provider, mtuStr, encap, c.overhead(), nodeMTU, eff)
+
+		switch {
+		case c.MTU > 0 && c.MTU+c.overhead() > nodeMTU:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityCritical,
+				Category: types.CategoryConnectivity,
+				Code:     types.CodeCNIMTUExceedsNode,
+				Resource: c.Resource,
+				Summary:  fmt.Sprintf("%s pod MTU %d + %s overhead %d exceeds node MTU %d", c.Provider, c.MTU, encap, c.overhead(), nodeMTU),
+				Detail:   detail,
+				Suggestion: fmt.Sprintf("Set the %s MTU to at most %d. Oversized encapsulated packets are dropped or fragmented: small requests succeed while large payloads (TLS handshakes with big certificate chains, uploads) stall.",
+					c.Provider, nodeMTU-c.overhead()),
+			})
+		case c.MTU > 0 && c.MTU+c.overhead() < nodeMTU-100:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryConnectivity,
+				Resource:   c.Resource,
+				Summary:    fmt.Sprintf("%s pod MTU %d leaves %d bytes of the node MTU unused", c.Provider, c.MTU, nodeMTU-c.MTU-c.overhead()),
+				Detail:     detail,
+				Suggestion: fmt.Sprintf("Raising the MTU to %d would reduce per-packet overhead.", nodeMTU-c.overhead()),
+			})
+		default:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryConnectivity,
+				Resource: c.Resource,
+				Summary:  fmt.Sprintf("%s MTU %s fits node MTU %d with %s encapsulation", c.Provider, mtuStr, nodeMTU, encap),
+				Detail:   detail,
+			})
+		}
+
+		if len(c.Encaps) > 1 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIStackedEncapsulation,
+				Resource:   c.Resource,
+				Summary:    fmt.Sprintf("%s stacks %s encapsulation (%d bytes overhead)", c.Provider, encap, c.overhead()),
+				Detail:     detail,
+				Suggestion: "Stacked tunnels are easy to under-budget: verify the pod MTU accounts for every layer, or use native routing underneath the encryption layer.",
+			})
+		}
+	}
+
+	// Multiple CNIs disagreeing on pod MTU (e.g. Canal, or chained Cilium)
+	if len(cnis) > 1 {
+		var parts []string
+		distinct := make(map[int]struct{})
+		for _, c := range cnis {
+			eff := c.effectiveMTU(nodeMTU)
+			distinct[eff] = struct{}{}
+			parts = append(parts, fmt.Sprintf("%s=%d", c.Provider, eff))
+		}
+		if len(distinct) > 1 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIMTUMismatch,
+				Summary:    "CNI components disagree on pod MTU",
+				Detail:     strings.Join(parts, " "),
+				Suggestion: "Align the MTU of all chained CNI components to the smallest effective value.",
+			})
+		}
+	}
+
+	for _, m := range mss {
+		packet := m.MSS + tcpIPv4HeaderBytes
+		if packet > podMTU {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeCNIMSSAboveMTU,
+				Resource:   m.Resource,
+				Summary:    fmt.Sprintf("proxy advertises TCP MSS %d (%d-byte packets) above the effective pod MTU %d", m.MSS, packet, podMTU),
+				Detail:     fmt.Sprintf("mss=%d packetSize=%d podMTU=%d nodeMTU=%d", m.MSS, packet, podMTU, nodeMTU),
+				Suggestion: fmt.Sprintf("Lower the TCP_MAXSEG socket option to at most %d, or remove it and let the kernel derive MSS from the interface MTU.", podMTU-tcpIPv4HeaderBytes),
+			})
+		} else {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryMesh,
+				Resource: m.Resource,
+				Summary:  fmt.Sprintf("proxy TCP MSS %d fits the effective pod MTU %d", m.MSS, podMTU),
+			})
+		}
+	}
+
+	return findings
+}
+
+// cniMTUConfigs reads the MTU configuration of every CNI datapath found.
+func (t *BaseTool) cniMTUConfigs(ctx context.Context) []cniMTUConfig {
+	var cnis []cniMTUConfig
+	if c, ok := t.ciliumMTUConfig(ctx); ok {
+		cnis = append(cnis, c)
+	}
+	if c, ok := t.calicoMTUConfig(ctx); ok {
+		cnis = append(cnis, c)
+	}
+	if c, ok := t.flannelMTUConfig(ctx); ok {
+		cnis = append(cnis, c)
+	}
+	return cnis
+}
+
+// ciliumMTUConfig reads MTU and tunnel settings from the cilium-config ConfigMap.
+func (t *BaseTool) ciliumMTUConfig(ctx context.Context) (cniMTUConfig, bool) {
+	cm, err := t.Clients.Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "cilium-config", metav1.GetOptions{})
+	if err != nil {
+		return cniMTUConfig{}, false
+	}
+	c := cniMTUConfig{
+		Provider: "cilium",
+		Resource: &types.ResourceRef{Kind: "ConfigMap", Namespace: "kube-system", Name: "cilium-config"},
+	}
+	c.MTU, _ = strconv.Atoi(cm.Data["mtu"])
+
+	tunnel := cm.Data["tunnel-protocol"]
+	if tunnel == "" {
+		tunnel = cm.Data["tunnel"] // pre-1.14 key
+	}
+	routingMode := cm.Data["routing-mode"]
+	if routingMode == "native" || tunnel == "disabled" {
+		tunnel = ""
+	} else if tunnel == "" {
+		tunnel = "vxlan" // Cilium default
+	}
+	if tunnel != "" {
+		c.Encaps = append(c.Encaps, tunnel)
+	}
+	if cm.Data["enable-wireguard"] == "true" {
+		c.Encaps = append(c.Encaps, "wireguard")
+	}
+	if cm.Data["enable-ipsec"] == "true" {
+		c.Encaps = append(c.Encaps, "ipsec")
+	}
+	return c, true
+}
+
+// calicoMTUConfig reads MTU settings from the default FelixConfiguration and IPPool encapsulation modes.
+func (t *BaseTool) calicoMTUConfig(ctx context.Context) (cniMTUConfig, bool) {
+	felix, err := t.Clients.Dynamic.Resource(calicoFelixConfigGVR).Get(ctx, "default", metav1.GetOptions{})
+	cm, cmErr := t.Clients.Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "calico-config", metav1.GetOptions{})
+	if err != nil && cmErr != nil {
+		return cniMTUConfig{}, false
+	}
+
+	c := cniMTUConfig{Provider: "calico"}
+	encaps := make(map[string]bool)
+
+	if pools, err := t.listResource(ctx, calicoIPPoolGVR, ""); err == nil {
+		for _, p := range pools.Items {
+			if mode, _, _ := unstructured.NestedString(p.Object, "spec", "vxlanMode"); mode != "" && mode != "Never" {
+				encaps["vxlan"] = true
+			}
+			if mode, _, _ := unstructured.NestedString(p.Object, "spec", "ipipMode"); mode != "" && mode != "Never" {
+				encaps["ipip"] = true
+			}
+		}
+	}
+
+	if err == nil {
+		c.Resource = &types.ResourceRef{Kind: "FelixConfiguration", Name: "default", APIVersion: "crd.projectcalico.org/v1"}
+		if wg, _, _ := unstructured.NestedBool(felix.Object, "spec", "wireguardEnabled"); wg {
+			encaps["wireguard"] = true
+		}
+		// Pick the MTU field matching the active encapsulation
+		for _, f := range []struct{ encap, field string }{{"wireguard", "wireguardMTU"}, {"vxlan", "vxlanMTU"}, {"ipip", "ipipMTU"}} {
+			if encaps[f.encap] {
+				if v, found, _ := unstructured.NestedInt64(felix.Object, "spec", f.field); found && v > 0 {
+					c.MTU = int(v)
+					break
+				}
+			}
+		}
+	}
+	if c.MTU == 0 && cmErr == nil {
+		if v, err := strconv.Atoi(strings.TrimSpace(cm.Data["veth_mtu"])); err == nil && v > 0 {
+			c.MTU = v
+			c.Resource = &types.ResourceRef{Kind: "ConfigMap", Namespace: "kube-system", Name: "calico-config"}
+		}
+	}
+
+	for _, e := range []string{"vxlan", "ipip", "wireguard"} {
+		if encaps[e] {
+			c.Encaps = append(c.Encaps, e)
+		}
+	}
+	return c, true
+}
+
+// flannelMTUConfig reads the backend type from kube-flannel-cfg. Flannel derives the pod MTU automatically.
+func (t *BaseTool) flannelMTUConfig(ctx context.Context) (cniMTUConfig, bool) {
+	for _, ns := range []string{"kube-flannel", "kube-system"} {
+		cm, err := t.Clients.Clientset.CoreV1().ConfigMaps(ns).Get(ctx, "kube-flannel-cfg", metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		c := cniMTUConfig{
+			Provider: "flannel",
+			Resource: &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: "kube-flannel-cfg"},
+		}
+		if backend := parseFlannelBackend(cm.Data["net-conf.json"]); backend != "" && backend != "host-gw" {
+			c.Encaps = []string{backend}
+		}
+		return c, true
+	}
+	return cniMTUConfig{}, false
+}
+
+// parseFlannelBackend extracts the lower-cased Backend.Type from a flannel
+// net-conf.json document, "" when it does not parse.
+func parseFlannelBackend(netConf string) string {
+	conf, err := parseFlannelNetConf(netConf)
+	if err != nil {
+		return ""
+	}
+	return conf.Backend.Type
+}
+
+// meshMSSSettings finds EnvoyFilters that set the TCP_MAXSEG socket option on listeners or clusters.
+func (t *CheckMTUConsistencyTool) meshMSSSettings(ctx context.Context) []meshMSSSetting {
+	list, err := t.listResource(ctx, envoyFilterV1A1, "")
+	if err != nil {
+		return nil
+	}
+	var out []meshMSSSetting
+	for _, ef := range list.Items {
+		for _, mss := range findTCPMaxSegOptions(ef.Object["spec"]) {
+			out = append(out, meshMSSSetting{
+				Resource: &types.ResourceRef{Kind: "EnvoyFilter", Namespace: ef.GetNamespace(), Name: ef.GetName(), APIVersion: "networking.istio.io/v1alpha3"},
+				MSS:      mss,
+			})
+		}
+	}
+	return out
+}
+
+// findTCPMaxSegOptions walks an arbitrary object looking for Envoy socket options
+// with level IPPROTO_TCP (6) and name TCP_MAXSEG (2), returning their int values.
+func findTCPMaxSegOptions(obj interface{}) []int {
+	var out []int
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if toInt(v["level"]) == 6 && toInt(v["name"]) == 2 {
+			iv := v["int_value"]
+			if iv == nil {
+				iv = v["intValue"]
+			}
+			if n := toInt(iv); n > 0 {
+				out = append(out, n)
+			}
+		}
+		for _, child := range v {
+			out = append(out, findTCPMaxSegOptions(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			out = append(out, findTCPMaxSegOptions(child)...)
+		}
+	}
+	return out
+}
+
+// toInt converts JSON numbers and numeric strings to int, returning 0 otherwise.
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,331 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// pathMTUResult is what an MTU probe measured.
+type pathMTUResult struct {
+	ifMTU   int    // MTU of the probe pod's eth0, 0 when unreadable
+	status  string // ok, fail (no reply at the minimum size) or denied (ICMP not permitted)
+	pathMTU int
+	message string
+}
+
+// parsePathMTU reads the IFMTU and PMTU lines the MTU probe prints.
+func parsePathMTU(output string) pathMTUResult {
+	var res pathMTUResult
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "|", 3)
+		switch {
+		case fields[0] == "IFMTU" && len(fields) >= 2:
+			res.ifMTU, _ = strconv.Atoi(fields[1])
+		case fields[0] == "PMTU" && len(fields) == 3:
+			res.status = fields[1]
+			if res.status == "ok" {
+				res.pathMTU, _ = strconv.Atoi(fields[2])
+			} else {
+				res.message = strings.TrimSpace(fields[2])
+			}
+		}
+	}
+	return res
+}
+
+// pathMTUScript binary-searches the largest packet that reaches target with
+// the Don't Fragment bit set, between minMTU and the probe pod's interface
+// MTU (fallbackMTU when it cannot be read).
+func pathMTUScript(target string, headerBytes, minMTU, fallbackMTU int) string {
+	return fmt.Sprintf(`ifmtu=$(cat /sys/class/net/eth0/mtu 2>/dev/null); echo "IFMTU|$ifmtu"
+hi=${ifmtu:-%d}
+p() { ping -M do -c 2 -i 0.2 -W 2 -s $(($1-%d)) %s 2>&1 >/dev/null; }
+if ! err=$(p %d); then case "$err" in *ermitted*|*ermission*) s=denied;; *) s=fail;; esac; echo "PMTU|$s|$(echo $err)"; exit 0; fi
+if p $hi >/dev/null; then echo "PMTU|ok|$hi"; exit 0; fi
+lo=%d; while [ $((hi-lo)) -gt 1 ]; do m=$(((lo+hi)/2)); if p $m >/dev/null; then lo=$m; else hi=$m; fi; done
+echo "PMTU|ok|$lo"`, fallbackMTU, headerBytes, target, minMTU, minMTU)
+}
+
+// evaluatePathMTU compares a measured path MTU with the probe pod's
+// interface MTU and the CNI configuration.
+func evaluatePathMTU(path string, res pathMTUResult, nodeMTU int, cnis []cniMTUConfig) []types.DiagnosticFinding {
+	var cniParts []string
+	configured := 0
+	var encapCNI *cniMTUConfig
+	for i, c := range cnis {
+		mtu := "auto"
+		if c.MTU > 0 {
+			mtu = strconv.Itoa(c.MTU)
+			if configured == 0 || c.MTU < configured {
+				configured = c.MTU
+			}
+		}
+		cniParts = append(cniParts, fmt.Sprintf("provider=%s configuredMTU=%s encapsulation=%s overhead=%d", c.Provider, mtu, orDefault(strings.Join(c.Encaps, "+"), "none"), c.overhead()))
+		if encapCNI == nil && c.overhead() > 0 {
+			encapCNI = &cnis[i]
+		}
+	}
+	detail := fmt.Sprintf("podInterfaceMTU=%d pathMTU=%d nodeMTU=%d", res.ifMTU, res.pathMTU, nodeMTU)
+	if len(cniParts) > 0 {
+		detail += "\n" + strings.Join(cniParts, "\n")
+	}
+
+	switch res.status {
+	case "denied":
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    fmt.Sprintf("Path MTU %s could not be measured: the probe pod may not send ICMP", path),
+			Detail:     res.message,
+			Suggestion: "Unprivileged ping needs the net.ipv4.ping_group_range sysctl to include the probe user (containerd enable_unprivileged_icmp). Use check_mtu_consistency for a configuration-only check.",
+		}}
+	case "ok":
+	default:
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    fmt.Sprintf("Path MTU %s could not be measured: the target did not answer ICMP echo at the minimum size", path),
+			Detail:     res.message,
+			Suggestion: "Target a pod or node IP: Service ClusterIPs do not answer ping, and NetworkPolicies or cloud firewalls may drop ICMP. Use probe_connectivity to test TCP reachability.",
+		}}
+	}
+
+	if res.ifMTU > 0 && res.pathMTU < res.ifMTU {
+		suggestion := fmt.Sprintf("Lower the pod MTU to %d or raise the MTU of the network between the nodes.", res.pathMTU)
+		if encapCNI != nil {
+			underlay := res.pathMTU + encapCNI.overhead()
+			suggestion = fmt.Sprintf("Set the %s MTU to at most %d, or raise the underlay MTU.", encapCNI.Provider, res.pathMTU)
+			if underlay < nodeMTU {
+				suggestion += fmt.Sprintf(" With %d bytes of %s overhead the underlay carries only about %d bytes, less than the node MTU %d: a VPN, cloud overlay or network device with a smaller MTU sits on the path.",
+					encapCNI.overhead(), strings.Join(encapCNI.Encaps, "+"), underlay, nodeMTU)
+			}
+		}
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIPathMTUBelowPodMTU,
+			Summary:    fmt.Sprintf("Path MTU %s is %d but the pod interface MTU is %d: packets of %d-%d bytes are dropped, so large responses hang while small requests succeed", path, res.pathMTU, res.ifMTU, res.pathMTU+1, res.ifMTU),
+			Detail:     detail,
+			Suggestion: suggestion + " Until then, clamping the TCP MSS avoids stalls for TCP traffic.",
+		}}
+	}
+
+	if configured > 0 && res.ifMTU > 0 && res.ifMTU != configured {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIPodMTUDrift,
+			Summary:    fmt.Sprintf("Probe pod interface MTU %d differs from the configured CNI MTU %d", res.ifMTU, configured),
+			Detail:     detail,
+			Suggestion: "Pods keep the MTU they were created with. After changing the CNI MTU, restart the CNI agents and then the workloads; pods with different MTUs stall on large packets in one direction only.",
+		}}
+	}
+
+	summary := fmt.Sprintf("Path MTU %s is %d", path, res.pathMTU)
+	if res.ifMTU > 0 {
+		summary += fmt.Sprintf(", matching the pod interface MTU %d", res.ifMTU)
+	}
+	return []types.DiagnosticFinding{{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Summary:  summary,
+		Detail:   detail,
+	}}
+}
+
+// --- check_mtu ---
+
+type CheckMTUTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *CheckMTUTool) Name() string { return "check_mtu" }
+func (t *CheckMTUTool) Description() string {
+	return "Measure the path MTU from an ephemeral pod to a pod, node or host with Don't Fragment ping sweeps and compare it with the pod interface MTU and the CNI configuration (Cilium, Calico, Flannel), flagging encapsulation overhead that the path cannot carry: the classic cause of large responses hanging while small requests succeed"
+}
+func (t *CheckMTUTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"target_pod": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod to measure the path to, as namespace/name",
+			},
+			"target_node": map[string]interface{}{
+				"type":        "string",
+				"description": "Node to measure the path to (its InternalIP is used)",
+			},
+			"target_host": map[string]interface{}{
+				"type":        "string",
+				"description": "IP or hostname to measure the path to (Service ClusterIPs do not answer ping)",
+			},
+			"source_node": map[string]interface{}{
+				"type":        "string",
+				"description": "Node to run the probe pod on, e.g. to measure a specific node-to-node path",
+			},
+			"source_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to deploy the probe pod in",
+			},
+			"node_mtu": map[string]interface{}{
+				"type":        "integer",
+				"description": "MTU of the node/underlay network interface (default: 1500)",
+			},
+		},
+	}
+}
+
+func (t *CheckMTUTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
+	sourceNode := getStringArg(args, "source_node", "")
+	nodeMTU := getIntArg(args, "node_mtu", 1500)
+
+	if nodeMTU < 576 || nodeMTU > 9216 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("node_mtu %d out of range (576-9216)", nodeMTU),
+		}
+	}
+	if sourceNode != "" && !validHostname.MatchString(sourceNode) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "source_node contains invalid characters",
+		}
+	}
+
+	target, label, err := t.resolveTarget(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	headerBytes, minMTU := 28, 576
+	if ip := net.ParseIP(target); ip != nil && ip.To4() == nil {
+		headerBytes, minMTU = 48, 1280
+	}
+	req := probes.ProbeRequest{
+		Type:      probes.ProbeTypeMTU,
+		Namespace: sourceNS,
+		NodeName:  sourceNode,
+		Command:   []string{"sh", "-c", pathMTUScript(target, headerBytes, minMTU, nodeMTU)},
+		Timeout:   90 * time.Second,
+	}
+
+	result, err := t.ProbeManager.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 2)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+
+	res := parsePathMTU(result.Output)
+	if res.status == "" {
+		detail := strings.TrimSpace(result.Output)
+		if result.Error != "" {
+			detail = result.Error + "; " + detail
+		}
+		res.message = detail
+	}
+
+	from := sourceNS
+	if sourceNode != "" {
+		from = fmt.Sprintf("%s on node %s", sourceNS, sourceNode)
+	}
+	path := fmt.Sprintf("from %s to %s", from, label)
+	findings = append(findings, evaluatePathMTU(path, res, nodeMTU, t.cniMTUConfigs(ctx))...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, sourceNS, ""), nil
+}
+
+// resolveTarget returns the address to ping and how to name it, from exactly
+// one of target_pod, target_node and target_host.
+func (t *CheckMTUTool) resolveTarget(ctx context.Context, args map[string]interface{}) (string, string, error) {
+	pod := getStringArg(args, "target_pod", "")
+	node := getStringArg(args, "target_node", "")
+	host := getStringArg(args, "target_host", "")
+
+	set := 0
+	for _, v := range []string{pod, node, host} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "exactly one of target_pod, target_node and target_host is required",
+		}
+	}
+
+	switch {
+	case pod != "":
+		ns, name, ok := strings.Cut(pod, "/")
+		if !ok || ns == "" || name == "" {
+			return "", "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "target_pod must be namespace/name",
+			}
+		}
+		p, err := t.Clients.Clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("failed to get pod %s", pod),
+				Detail:  err.Error(),
+			}
+		}
+		if p.Status.PodIP == "" {
+			return "", "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("pod %s has no IP yet", pod),
+			}
+		}
+		return p.Status.PodIP, fmt.Sprintf("pod %s (%s)", pod, p.Status.PodIP), nil
+	case node != "":
+		n, err := t.Clients.Clientset.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+		if err != nil {
+			return "", "", &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("failed to get node %s", node),
+				Detail:  err.Error(),
+			}
+		}
+		for _, a := range n.Status.Addresses {
+			if a.Type == corev1.NodeInternalIP {
+				return a.Address, fmt.Sprintf("node %s (%s)", node, a.Address), nil
+			}
+		}
+		return "", "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("node %s has no InternalIP", node),
+		}
+	}
+	if !validHostname.MatchString(host) {
+		return "", "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "target_host contains invalid characters",
+		}
+	}
+	return host, host, nil
+}
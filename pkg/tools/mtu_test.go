@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func countSeverity(findings []types.DiagnosticFinding, severity string) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEvaluateMTUConsistency_VXLANOverNodeMTU(t *testing.T) {
+	cnis := []cniMTUConfig{{Provider: "cilium", MTU: 1500, Encaps: []string{"vxlan"}}}
+	findings := evaluateMTUConsistency(1500, cnis, nil)
+	if countSeverity(findings, types.SeverityCritical) != 1 {
+		t.Errorf("expected 1 critical finding for 1500+50 > 1500, got %+v", findings)
+	}
+}
+
+func TestEvaluateMTUConsistency_AutoMTUFits(t *testing.T) {
+	cnis := []cniMTUConfig{{Provider: "flannel", Encaps: []string{"vxlan"}}}
+	findings := evaluateMTUConsistency(1500, cnis, nil)
+	if countSeverity(findings, types.SeverityCritical) != 0 || countSeverity(findings, types.SeverityOK) != 1 {
+		t.Errorf("expected a single ok finding, got %+v", findings)
+	}
+}
+
+func TestEvaluateMTUConsistency_MSSAbovePodMTU(t *testing.T) {
+	cnis := []cniMTUConfig{{Provider: "calico", MTU: 1440, Encaps: []string{"wireguard"}}}
+	mss := []meshMSSSetting{{MSS: 1460}}
+	findings := evaluateMTUConsistency(1500, cnis, mss)
+	if countSeverity(findings, types.SeverityCritical) != 1 {
+		t.Errorf("expected MSS 1460 (+40) > 1440 to be critical, got %+v", findings)
+	}
+}
+
+func TestEvaluateMTUConsistency_StackedEncapWarning(t *testing.T) {
+	cnis := []cniMTUConfig{{Provider: "cilium", MTU: 1390, Encaps: []string{"vxlan", "wireguard"}}}
+	findings := evaluateMTUConsistency(1500, cnis, nil)
+	if countSeverity(findings, types.SeverityWarning) != 1 {
+		t.Errorf("expected stacked encapsulation warning, got %+v", findings)
+	}
+}
+
+func TestParseFlannelBackend(t *testing.T) {
+	if got := parseFlannelBackend(`{"Network":"10.244.0.0/16","Backend":{"Type":"VXLAN"}}`); got != "vxlan" {
+		t.Errorf("parseFlannelBackend = %q, want vxlan", got)
+	}
+	if got := parseFlannelBackend(`not json`); got != "" {
+		t.Errorf("parseFlannelBackend(invalid) = %q, want empty", got)
+	}
+}
+
+func TestFindTCPMaxSegOptions(t *testing.T) {
+	spec := map[string]interface{}{
+		"configPatches": []interface{}{
+			map[string]interface{}{
+				"patch": map[string]interface{}{
+					"value": map[string]interface{}{
+						"socket_options": []interface{}{
+							map[string]interface{}{"level": float64(6), "name": float64(2), "int_value": float64(1400)},
+							map[string]interface{}{"level": float64(1), "name": float64(9), "int_value": float64(1)},
+						},
+					},
+				},
+			},
+		},
+	}
+	got := findTCPMaxSegOptions(spec)
+	if len(got) != 1 || got[0] != 1400 {
+		t.Errorf("findTCPMaxSegOptions = %v, want [1400]", got)
+	}
+}
+
+func TestParsePathMTU(t *testing.T) {
+	res := parsePathMTU("IFMTU|1450\nPMTU|ok|1400\n")
+	if res.ifMTU != 1450 || res.status != "ok" || res.pathMTU != 1400 {
+		t.Errorf("unexpected result %+v", res)
+	}
+	res = parsePathMTU("IFMTU|\nPMTU|denied|ping: socket: Operation not permitted\n")
+	if res.ifMTU != 0 || res.status != "denied" || res.message != "ping: socket: Operation not permitted" {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestEvaluatePathMTU(t *testing.T) {
+	vxlan := []cniMTUConfig{{Provider: "flannel", Encaps: []string{"vxlan"}}}
+	cases := []struct {
+		name string
+		res  pathMTUResult
+		cnis []cniMTUConfig
+		want types.FindingCode
+		sev  string
+	}{
+		{"matching", pathMTUResult{ifMTU: 1450, status: "ok", pathMTU: 1450}, vxlan, "", types.SeverityOK},
+		{"black hole", pathMTUResult{ifMTU: 1450, status: "ok", pathMTU: 1400}, vxlan, types.CodeCNIPathMTUBelowPodMTU, types.SeverityCritical},
+		{"drift", pathMTUResult{ifMTU: 1450, status: "ok", pathMTU: 1450}, []cniMTUConfig{{Provider: "cilium", MTU: 1400, Encaps: []string{"vxlan"}}}, types.CodeCNIPodMTUDrift, types.SeverityWarning},
+		{"no reply", pathMTUResult{status: "fail", message: "100% packet loss"}, vxlan, "", types.SeverityInfo},
+		{"denied", pathMTUResult{status: "denied"}, nil, "", types.SeverityInfo},
+	}
+	for _, c := range cases {
+		findings := evaluatePathMTU("from default to pod shop/web", c.res, 1500, c.cnis)
+		if len(findings) != 1 || findings[0].Code != c.want || findings[0].Severity != c.sev {
+			t.Errorf("%s: unexpected findings %+v", c.name, findings)
+		}
+	}
+
+	findings := evaluatePathMTU("from default to node b", pathMTUResult{ifMTU: 1450, status: "ok", pathMTU: 1400}, 1500, vxlan)
+	if want := "the underlay carries only about 1450 bytes"; !strings.Contains(findings[0].Suggestion, want) {
+		t.Errorf("suggestion %q does not contain %q", findings[0].Suggestion, want)
+	}
+}
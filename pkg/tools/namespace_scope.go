@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// namespaceScoped is implemented by every tool through BaseTool.
+type namespaceScoped interface {
+	namespaceScope() *k8s.NamespaceScope
+}
+
+// namespaceScope returns the namespaces the tool may inspect, or nil when
+// ALLOWED_NAMESPACES and DENIED_NAMESPACES are unset.
+func (b *BaseTool) namespaceScope() *k8s.NamespaceScope {
+	if b.Cfg == nil {
+		return nil
+	}
+	return k8s.NewNamespaceScope(b.Cfg.AllowedNamespaces, b.Cfg.DeniedNamespaces)
+}
+
+// ApplyNamespaceScope enforces ALLOWED_NAMESPACES and DENIED_NAMESPACES on
+// one call of t. Namespace arguments outside the scope are rejected; an
+// empty namespace argument is set to the only allowed namespace, if there is
+// one. The returned context limits the call's API requests to the scope:
+// requests in other namespaces fail as Forbidden and cluster-wide lists only
+// return the objects of allowed namespaces.
+func ApplyNamespaceScope(ctx context.Context, t Tool, args map[string]interface{}) (context.Context, error) {
+	st, ok := t.(namespaceScoped)
+	if !ok {
+		return ctx, nil
+	}
+	scope := st.namespaceScope()
+	if scope == nil {
+		return ctx, nil
+	}
+
+	var keys []string
+	for key := range args {
+		if strings.HasSuffix(key, "namespace") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if ns, _ := args[key].(string); ns != "" && !scope.Allows(ns) {
+			return ctx, &types.MCPError{
+				Code:    types.ErrCodeNamespaceForbidden,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("%s %q is outside the namespaces this server may inspect", key, ns),
+				Detail:  scope.String(),
+			}
+		}
+	}
+
+	props, _ := t.InputSchema()["properties"].(map[string]interface{})
+	if _, ok := props["namespace"]; ok && len(scope.Allowed) == 1 && getStringArg(args, "namespace", "") == "" {
+		args["namespace"] = scope.Allowed[0]
+	}
+	return k8s.WithNamespaceScope(ctx, scope), nil
+}
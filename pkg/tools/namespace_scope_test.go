@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestApplyNamespaceScope(t *testing.T) {
+	tool := &ListServicesTool{BaseTool: BaseTool{Cfg: &config.Config{AllowedNamespaces: []string{"team-a"}}}}
+
+	args := map[string]interface{}{}
+	ctx, err := ApplyNamespaceScope(context.Background(), tool, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args["namespace"] != "team-a" || k8s.NamespaceScopeFrom(ctx) == nil {
+		t.Errorf("args = %v, want namespace set to the only allowed namespace and a scoped context", args)
+	}
+
+	_, err = ApplyNamespaceScope(context.Background(), tool, map[string]interface{}{"namespace": "team-b"})
+	if mcpErr, ok := err.(*types.MCPError); !ok || mcpErr.Code != types.ErrCodeNamespaceForbidden {
+		t.Errorf("ApplyNamespaceScope(team-b) error = %v, want %s", err, types.ErrCodeNamespaceForbidden)
+	}
+
+	unscoped := &ListServicesTool{BaseTool: BaseTool{Cfg: &config.Config{}}}
+	args = map[string]interface{}{}
+	if ctx, err := ApplyNamespaceScope(context.Background(), unscoped, args); err != nil || len(args) != 0 || k8s.NamespaceScopeFrom(ctx) != nil {
+		t.Errorf("unscoped call changed: args %v, err %v", args, err)
+	}
+}
+
+func TestSkillToolRunnerAppliesNamespaceScope(t *testing.T) {
+	client, _ := newSnapshotTestClient(t)
+	snapshot := NewClusterSnapshot(client, time.Minute)
+	if _, err := snapshot.List(context.Background(), servicesGVR, ""); err != nil {
+		t.Fatal(err)
+	}
+	reg := NewRegistry()
+	reg.Register(&ListServicesTool{BaseTool: BaseTool{
+		Cfg:      &config.Config{ClusterName: "test", DeniedNamespaces: []string{"b"}},
+		Clients:  &k8s.Clients{Dynamic: client},
+		Snapshot: snapshot,
+	}})
+	runner := SkillToolRunner{Registry: reg}
+
+	_, _, err := runner.RunTool(context.Background(), "list_services", map[string]interface{}{"namespace": "b"})
+	if mcpErr, ok := err.(*types.MCPError); !ok || mcpErr.Code != types.ErrCodeNamespaceForbidden {
+		t.Errorf("step in a denied namespace: error = %v, want %s", err, types.ErrCodeNamespaceForbidden)
+	}
+
+	findings, _, err := runner.RunTool(context.Background(), "list_services", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		if f.Resource != nil && f.Resource.Namespace == "b" {
+			t.Errorf("step saw %s from the denied namespace", f.Summary)
+		}
+	}
+	if len(findings) == 0 {
+		t.Error("step saw no services")
+	}
+}
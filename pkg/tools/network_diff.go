@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// namespacePlaceholder replaces the compared namespace in specs so that
+// references to the object's own namespace do not show up as drift.
+const namespacePlaceholder = "<namespace>"
+
+// diffKinds are the resources compared by diff_network_config.
+var diffKinds = []struct {
+	Kind        string
+	V1, V1Beta1 schema.GroupVersionResource
+	Category    string
+}{
+	{"NetworkPolicy", networkPoliciesGVR, networkPoliciesGVR, types.CategoryPolicy},
+	{"AuthorizationPolicy", apV1GVR, apV1B1GVR, types.CategoryPolicy},
+	{"PeerAuthentication", paV1GVR, paV1B1GVR, types.CategoryTLS},
+	{"DestinationRule", drV1GVR, drV1B1GVR, types.CategoryMesh},
+	{"Gateway", gatewaysV1GVR, gatewaysV1B1GVR, types.CategoryRouting},
+	{"HTTPRoute", httpRoutesV1GVR, httpRoutesV1B1GVR, types.CategoryRouting},
+	{"GRPCRoute", grpcRoutesV1GVR, grpcRoutesV1B1GVR, types.CategoryRouting},
+}
+
+// normalizeSpec deep-copies v with every string equal to ns, and every
+// SPIFFE "/ns/<ns>/" segment, replaced by namespacePlaceholder.
+func normalizeSpec(v interface{}, ns string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = normalizeSpec(val, ns)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = normalizeSpec(val, ns)
+		}
+		return out
+	case string:
+		if t == ns {
+			return namespacePlaceholder
+		}
+		return strings.ReplaceAll(t, "/ns/"+ns+"/", "/ns/"+namespacePlaceholder+"/")
+	}
+	return v
+}
+
+// diffPaths returns the field paths where a and b differ. Lists of different
+// length are reported as a whole.
+func diffPaths(a, b interface{}, path string) []string {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		keys := make(map[string]bool)
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		var out []string
+		for _, k := range sortedSet(keys) {
+			sub := path + "." + k
+			x, inA := av[k]
+			y, inB := bv[k]
+			if !inA || !inB {
+				out = append(out, sub)
+				continue
+			}
+			out = append(out, diffPaths(x, y, sub)...)
+		}
+		return out
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return []string{path}
+		}
+		var out []string
+		for i := range av {
+			out = append(out, diffPaths(av[i], bv[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return out
+	}
+	if !reflect.DeepEqual(a, b) {
+		return []string{path}
+	}
+	return nil
+}
+
+// diffSide is one side of a comparison: a namespace in a cluster.
+type diffSide struct {
+	Cluster   string
+	Namespace string
+}
+
+func (s diffSide) String() string { return s.Cluster + "/" + s.Namespace }
+
+// diffObjects compares the objects of one kind by name and reports those
+// missing on either side and those whose spec differs.
+func diffObjects(kind, category string, src, dst []unstructured.Unstructured, from, to diffSide) []types.DiagnosticFinding {
+	index := func(items []unstructured.Unstructured) map[string]*unstructured.Unstructured {
+		m := make(map[string]*unstructured.Unstructured, len(items))
+		for i := range items {
+			m[items[i].GetName()] = &items[i]
+		}
+		return m
+	}
+	srcByName, dstByName := index(src), index(dst)
+	names := make(map[string]bool)
+	for n := range srcByName {
+		names[n] = true
+	}
+	for n := range dstByName {
+		names[n] = true
+	}
+
+	var findings []types.DiagnosticFinding
+	identical := 0
+	for _, name := range sortedSet(names) {
+		s, d := srcByName[name], dstByName[name]
+		switch {
+		case d == nil:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   category,
+				Code:       types.CodeDiffOnlyInOneCluster,
+				Resource:   &types.ResourceRef{Kind: kind, Namespace: from.Namespace, Name: name},
+				Summary:    fmt.Sprintf("%s %s exists in %s but not in %s", kind, name, from, to),
+				Suggestion: fmt.Sprintf("Check whether %s is missing from %s's manifests or overlay.", name, to),
+			})
+		case s == nil:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   category,
+				Code:       types.CodeDiffOnlyInOneCluster,
+				Resource:   &types.ResourceRef{Kind: kind, Namespace: to.Namespace, Name: name},
+				Summary:    fmt.Sprintf("%s %s exists in %s but not in %s", kind, name, to, from),
+				Suggestion: fmt.Sprintf("Check whether %s was applied by hand in %s.", name, to),
+			})
+		default:
+			paths := diffPaths(normalizeSpec(s.Object["spec"], from.Namespace), normalizeSpec(d.Object["spec"], to.Namespace), "spec")
+			if len(paths) == 0 {
+				identical++
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   category,
+				Code:       types.CodeDiffDiffers,
+				Resource:   &types.ResourceRef{Kind: kind, Namespace: to.Namespace, Name: name},
+				Summary:    fmt.Sprintf("%s %s differs between %s and %s in %d field(s)", kind, name, from, to, len(paths)),
+				Detail:     fmt.Sprintf("fields: %s", truncateList(paths, 10)),
+				Suggestion: "Compare the two objects field by field; environment-specific values belong in overlays, anything else is drift.",
+			})
+		}
+	}
+	if identical > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: category,
+			Summary:  fmt.Sprintf("%d %s(s) identical between %s and %s", identical, kind, from, to),
+		})
+	}
+	return findings
+}
+
+// --- diff_network_config ---
+
+// DiffNetworkConfigTool compares networking configuration between two
+// namespaces, optionally in another configured cluster.
+type DiffNetworkConfigTool struct {
+	BaseTool
+	Clusters *k8s.ClusterRegistry
+}
+
+func (t *DiffNetworkConfigTool) Name() string { return "diff_network_config" }
+func (t *DiffNetworkConfigTool) Description() string {
+	return "Compare networking posture (NetworkPolicies, Istio AuthorizationPolicies, PeerAuthentications and DestinationRules, Gateways and routes, mesh enrollment) between two namespaces or two clusters and report drift"
+}
+func (t *DiffNetworkConfigTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to compare from (e.g. staging)",
+			},
+			"compare_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to compare to (default: same as namespace)",
+			},
+			"compare_cluster": map[string]interface{}{
+				"type":        "string",
+				"description": "Configured cluster holding compare_namespace (default: this cluster); see list_clusters",
+			},
+		},
+		"required": []string{"namespace"},
+	}
+}
+
+func (t *DiffNetworkConfigTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	otherNs := getStringArg(args, "compare_namespace", ns)
+	otherCluster := getStringArg(args, "compare_cluster", t.Cfg.ClusterName)
+
+	if ns == "" || (otherNs == ns && otherCluster == t.Cfg.ClusterName) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "namespace is required, and compare_namespace or compare_cluster must name a different target",
+		}
+	}
+
+	other := t.Clients
+	if otherCluster != t.Cfg.ClusterName {
+		var c *k8s.Cluster
+		var ok bool
+		if t.Clusters != nil {
+			c, ok = t.Clusters.Get(otherCluster)
+		}
+		if !ok {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("unknown cluster %q", otherCluster),
+				Detail:  "use list_clusters to see the configured clusters",
+			}
+		}
+		other = c.Clients
+	}
+
+	from := diffSide{Cluster: t.Cfg.ClusterName, Namespace: ns}
+	to := diffSide{Cluster: otherCluster, Namespace: otherNs}
+	var findings []types.DiagnosticFinding
+
+	// Mesh enrollment decides whether the Istio policies apply at all.
+	srcNs, srcErr := t.Clients.Clientset.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	dstNs, dstErr := other.Clientset.CoreV1().Namespaces().Get(ctx, otherNs, metav1.GetOptions{})
+	switch {
+	case srcErr != nil:
+		return nil, fmt.Errorf("failed to get namespace %s: %w", from, srcErr)
+	case dstErr != nil:
+		return nil, fmt.Errorf("failed to get namespace %s: %w", to, dstErr)
+	}
+	if a, b := namespaceMesh(*srcNs), namespaceMesh(*dstNs); a != b {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeDiffMeshEnrollmentDiffers,
+			Resource:   &types.ResourceRef{Kind: "Namespace", Name: otherNs, APIVersion: "v1"},
+			Summary:    fmt.Sprintf("Mesh enrollment differs: %s is %q, %s is %q", from, orDefault(a, "none"), to, orDefault(b, "none")),
+			Suggestion: "Align the namespace injection or dataplane-mode labels; mTLS and AuthorizationPolicies behave differently without the mesh.",
+		})
+	}
+
+	for _, k := range diffKinds {
+		src, srcErr := t.listResourceWithFallback(ctx, k.V1, k.V1Beta1, ns)
+		dst, dstErr := listWithFallback(ctx, other.Dynamic, k.V1, k.V1Beta1, otherNs)
+		switch {
+		case srcErr != nil && dstErr != nil:
+			continue
+		case srcErr != nil || dstErr != nil:
+			missing, err := from, srcErr
+			if dstErr != nil {
+				missing, err = to, dstErr
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityWarning,
+				Category: k.Category,
+				Code:     types.CodeDiffKindNotCompared,
+				Summary:  fmt.Sprintf("%s cannot be listed in %s; it is not compared", k.Kind, missing),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		findings = append(findings, diffObjects(k.Kind, k.Category, src.Items, dst.Items, from, to)...)
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("No networking configuration found in %s or %s", from, to),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestDiffPaths(t *testing.T) {
+	a := map[string]interface{}{
+		"mtls":  map[string]interface{}{"mode": "STRICT"},
+		"ports": []interface{}{int64(80)},
+		"extra": "x",
+	}
+	b := map[string]interface{}{
+		"mtls":  map[string]interface{}{"mode": "PERMISSIVE"},
+		"ports": []interface{}{int64(80), int64(443)},
+	}
+	want := []string{"spec.extra", "spec.mtls.mode", "spec.ports"}
+	if got := diffPaths(a, b, "spec"); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffPaths() = %v, want %v", got, want)
+	}
+	if got := diffPaths(a, a, "spec"); len(got) != 0 {
+		t.Errorf("expected no difference, got %v", got)
+	}
+}
+
+func TestNormalizeSpec(t *testing.T) {
+	staging := map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+				"namespaces": []interface{}{"staging"},
+				"principals": []interface{}{"cluster.local/ns/staging/sa/web"},
+			}}},
+		}},
+	}
+	prod := map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+				"namespaces": []interface{}{"prod"},
+				"principals": []interface{}{"cluster.local/ns/prod/sa/web"},
+			}}},
+		}},
+	}
+	if got := diffPaths(normalizeSpec(staging, "staging"), normalizeSpec(prod, "prod"), "spec"); len(got) != 0 {
+		t.Errorf("own-namespace references should not count as drift, got %v", got)
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	obj := func(ns, name, mode string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": ns},
+			"spec":     map[string]interface{}{"mtls": map[string]interface{}{"mode": mode}},
+		}}
+	}
+	src := []unstructured.Unstructured{obj("staging", "default", "STRICT"), obj("staging", "same", "STRICT"), obj("staging", "only-staging", "STRICT")}
+	dst := []unstructured.Unstructured{obj("prod", "default", "PERMISSIVE"), obj("prod", "same", "STRICT"), obj("prod", "only-prod", "STRICT")}
+	from, to := diffSide{Cluster: "c1", Namespace: "staging"}, diffSide{Cluster: "c1", Namespace: "prod"}
+
+	findings := diffObjects("PeerAuthentication", types.CategoryTLS, src, dst, from, to)
+	if countSeverity(findings, types.SeverityWarning) != 3 || countSeverity(findings, types.SeverityOK) != 1 {
+		t.Fatalf("expected 3 drift findings and 1 identical summary, got %+v", findings)
+	}
+	if findings[0].Detail != "fields: spec.mtls.mode" {
+		t.Errorf("unexpected detail %q", findings[0].Detail)
+	}
+}
@@ -0,0 +1,438 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// nodeLocalDNSSelector matches the DaemonSet and pods of the upstream NodeLocal DNSCache addon.
+const nodeLocalDNSSelector = "k8s-app=node-local-dns"
+
+// nodeLocalDNSPlaceholders must be replaced when the addon manifest is
+// installed. __PILLAR__CLUSTER__DNS__ and __PILLAR__UPSTREAM__SERVERS__ are
+// filled in by node-cache at startup and are expected to remain.
+var nodeLocalDNSPlaceholders = []string{"__PILLAR__LOCAL__DNS__", "__PILLAR__DNS__SERVER__", "__PILLAR__DNS__DOMAIN__"}
+
+// parseFlagArgs reads Go-style command-line flags ("-k v", "-k=v", "--k=v",
+// and bare boolean "-k") into a map. Positional arguments are ignored.
+func parseFlagArgs(args []string) map[string]string {
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			continue
+		}
+		a = strings.TrimLeft(a, "-")
+		if k, v, ok := strings.Cut(a, "="); ok {
+			flags[k] = v
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			flags[a] = args[i+1]
+			i++
+			continue
+		}
+		flags[a] = "true"
+	}
+	return flags
+}
+
+// nodeLocalDNSSetup is the node-cache container configuration that decides
+// which addresses it intercepts and whether it manages iptables.
+type nodeLocalDNSSetup struct {
+	LocalIPs      []string
+	SetupIptables bool
+	UpstreamSvc   string
+	HostNetwork   bool
+	NetAdmin      bool
+}
+
+func newNodeLocalDNSSetup(ds *appsv1.DaemonSet) nodeLocalDNSSetup {
+	spec := ds.Spec.Template.Spec
+	setup := nodeLocalDNSSetup{HostNetwork: spec.HostNetwork, UpstreamSvc: "kube-dns-upstream", SetupIptables: true}
+	c := nodeCacheContainer(ds)
+	if c == nil {
+		return setup
+	}
+	flags := parseFlagArgs(append(append([]string{}, c.Command...), c.Args...))
+	for _, ip := range strings.Split(flags["localip"], ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			setup.LocalIPs = append(setup.LocalIPs, ip)
+		}
+	}
+	if v, ok := flags["setupiptables"]; ok {
+		setup.SetupIptables = v != "false"
+	}
+	if v := flags["upstreamsvc"]; v != "" {
+		setup.UpstreamSvc = v
+	}
+	if sc := c.SecurityContext; sc != nil {
+		if sc.Privileged != nil && *sc.Privileged {
+			setup.NetAdmin = true
+		}
+		if sc.Capabilities != nil {
+			for _, cap := range sc.Capabilities.Add {
+				if cap == "NET_ADMIN" || cap == "CAP_NET_ADMIN" {
+					setup.NetAdmin = true
+				}
+			}
+		}
+	}
+	return setup
+}
+
+func nodeCacheContainer(ds *appsv1.DaemonSet) *corev1.Container {
+	cs := ds.Spec.Template.Spec.Containers
+	for i := range cs {
+		if cs[i].Name == "node-cache" {
+			return &cs[i]
+		}
+	}
+	if len(cs) == 0 {
+		return nil
+	}
+	return &cs[0]
+}
+
+func (s nodeLocalDNSSetup) binds(ip string) bool {
+	for _, l := range s.LocalIPs {
+		if l == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateNodeLocalDNSSetup checks that the addresses node-cache intercepts,
+// and the NOTRACK rules it installs for them, fit the kube-proxy mode.
+func evaluateNodeLocalDNSSetup(setup nodeLocalDNSSetup, dnsServiceIP, proxyMode string, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, sev, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryDNS, Code: code, Resource: ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+	detail := fmt.Sprintf("localip=%s setupiptables=%t kubeProxyMode=%s kubeDNS=%s", strings.Join(setup.LocalIPs, ","), setup.SetupIptables, orDefault(proxyMode, "unknown"), orDefault(dnsServiceIP, "unknown"))
+
+	if len(setup.LocalIPs) == 0 {
+		add(types.CodeDNSNodeLocalSetupInvalid, types.SeverityCritical, "node-cache has no -localip: it does not know which addresses to serve", detail,
+			"Pass -localip with the link-local address (169.254.20.10), plus the kube-dns ClusterIP in iptables mode.")
+		return findings
+	}
+	if !setup.HostNetwork {
+		add(types.CodeDNSNodeLocalSetupInvalid, types.SeverityCritical, "node-local-dns pods do not use hostNetwork", detail,
+			"node-cache must run with hostNetwork: true to bind the node-local address and install iptables rules.")
+	}
+
+	if setup.SetupIptables {
+		if !setup.NetAdmin {
+			add(types.CodeDNSNodeLocalSetupInvalid, types.SeverityCritical, "node-cache cannot install its NOTRACK rules: container lacks NET_ADMIN", detail,
+				"Add NET_ADMIN to securityContext.capabilities.add; without it DNS traffic stays conntracked and is not intercepted.")
+		} else {
+			add("", types.SeverityOK, fmt.Sprintf("node-cache installs NOTRACK rules for %s:53 (raw table)", strings.Join(setup.LocalIPs, ", ")), detail, "")
+		}
+	} else {
+		add(types.CodeDNSNodeLocalSetupInvalid, types.SeverityWarning, "node-cache runs with -setupiptables=false: NOTRACK rules are not managed", detail,
+			"DNS to the cache stays conntracked, bringing back conntrack races and 5s timeouts. Only disable this when another dataplane (e.g. a Cilium Local Redirect Policy) redirects DNS to the cache.")
+	}
+
+	bindsService := dnsServiceIP != "" && setup.binds(dnsServiceIP)
+	switch {
+	case proxyMode == "ipvs" && bindsService:
+		add(types.CodeDNSNodeLocalSetupInvalid, types.SeverityWarning, "node-cache binds the kube-dns ClusterIP in IPVS mode", detail,
+			"kube-ipvs0 already owns the ClusterIP, so the cache cannot intercept it. Bind only the link-local address and set kubelet --cluster-dns to it.")
+	case proxyMode != "ipvs" && dnsServiceIP != "" && !bindsService:
+		add("", types.SeverityInfo, "node-cache binds only the link-local address", detail,
+			fmt.Sprintf("Pods use the cache only if kubelet --cluster-dns points at %s; otherwise queries still go to %s.", setup.LocalIPs[0], dnsServiceIP))
+	}
+	return findings
+}
+
+// analyzeNodeLocalCorefile checks the node-local-dns Corefile for unreplaced
+// install placeholders, bind addresses missing from -localip, and forwarding
+// cluster queries back into the cache.
+func analyzeNodeLocalCorefile(corefile string, setup nodeLocalDNSSetup, dnsServiceIP string, ref *types.ResourceRef) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(code types.FindingCode, sev, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryDNS, Code: code, Resource: ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+
+	var left []string
+	for _, p := range nodeLocalDNSPlaceholders {
+		if strings.Contains(corefile, p) {
+			left = append(left, p)
+		}
+	}
+	if len(left) > 0 {
+		add(types.CodeDNSNodeLocalPlaceholders, types.SeverityCritical, "node-local-dns Corefile still contains install placeholders",
+			strings.Join(left, ", "),
+			"Substitute the placeholders with the link-local IP, kube-dns ClusterIP and cluster domain before applying the manifest.")
+	}
+
+	servers, err := parseCorefile(corefile)
+	if err != nil {
+		add(types.CodeDNSCorefileParseError, types.SeverityCritical, "node-local-dns Corefile does not parse", err.Error(), "Fix the Corefile syntax.")
+		return findings
+	}
+
+	bindsService := dnsServiceIP != "" && setup.binds(dnsServiceIP)
+	for _, s := range servers {
+		if bind := s.directive("bind"); bind != nil {
+			for _, ip := range bind.Args {
+				if !strings.HasPrefix(ip, "__PILLAR__") && !setup.binds(ip) {
+					add(types.CodeDNSNodeLocalBindMismatch, types.SeverityWarning, fmt.Sprintf("Server block %q binds %s, which is not in -localip", s.label(), ip),
+						fmt.Sprintf("bind %s (line %d) localip=%s", strings.Join(bind.Args, " "), bind.Line, strings.Join(setup.LocalIPs, ",")),
+						"node-cache adds only -localip addresses to its interface and NOTRACK rules; keep bind and -localip in sync.")
+				}
+			}
+		}
+		for _, target := range forwardTargets(s.directive("forward")) {
+			addr, _, err := forwardTargetAddr(target)
+			if err == nil && bindsService && addr.String() == dnsServiceIP {
+				add(types.CodeDNSForwardingLoop, types.SeverityCritical, fmt.Sprintf("%q forwards to the kube-dns ClusterIP that node-cache itself binds", s.label()),
+					fmt.Sprintf("forward %s (line %d)", strings.Join(s.directive("forward").Args, " "), s.directive("forward").Line),
+					fmt.Sprintf("Queries loop back into the cache. Forward to __PILLAR__CLUSTER__DNS__ (the %s Service) instead.", setup.UpstreamSvc))
+			}
+		}
+		if s.directive("cache") == nil && !s.isRoot() {
+			add(types.CodeDNSCacheDisabled, types.SeverityWarning, fmt.Sprintf("Server block %q has no cache plugin", s.label()),
+				fmt.Sprintf("line %d", s.Line),
+				"Without cache the node-local cache only proxies; add `cache 30`.")
+		}
+	}
+	return findings
+}
+
+// --- check_nodelocal_dns ---
+
+type CheckNodeLocalDNSTool struct{ BaseTool }
+
+func (t *CheckNodeLocalDNSTool) Name() string { return "check_nodelocal_dns" }
+func (t *CheckNodeLocalDNSTool) Description() string {
+	return "Check NodeLocal DNSCache: healthy pod on every node, iptables NOTRACK setup against the kube-proxy mode, Corefile placeholders and bind addresses, and the kube-dns upstream Service"
+}
+func (t *CheckNodeLocalDNSTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *CheckNodeLocalDNSTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	dsList, err := t.Clients.Clientset.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: nodeLocalDNSSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node-local-dns DaemonSets: %w", err)
+	}
+	if len(dsList.Items) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeProviderNotFound,
+			Tool:    t.Name(),
+			Message: "NodeLocal DNSCache is not installed",
+			Detail:  fmt.Sprintf("no DaemonSet matches %s", nodeLocalDNSSelector),
+		}
+	}
+	ds := &dsList.Items[0]
+	ns := ds.Namespace
+	ref := &types.ResourceRef{Kind: "DaemonSet", Namespace: ns, Name: ds.Name, APIVersion: "apps/v1"}
+	setup := newNodeLocalDNSSetup(ds)
+
+	findings := make([]types.DiagnosticFinding, 0, 8)
+
+	var dnsServiceIP string
+	if svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace("kube-system").Get(ctx, "kube-dns", metav1.GetOptions{}); err == nil {
+		dnsServiceIP, _, _ = unstructured.NestedString(svc.Object, "spec", "clusterIP")
+	}
+	var proxyMode string
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace("kube-system").Get(ctx, "kube-proxy", metav1.GetOptions{}); err == nil {
+		conf, _, _ := unstructured.NestedString(cm.Object, "data", "config.conf")
+		proxyMode = kubeProxyMode(conf)
+	}
+
+	findings = append(findings, evaluateNodeLocalDNSSetup(setup, dnsServiceIP, proxyMode, ref)...)
+
+	cmName := nodeLocalDNSConfigMap(ds)
+	if cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, cmName, metav1.GetOptions{}); err == nil {
+		corefile, _, _ := unstructured.NestedString(cm.Object, "data", "Corefile")
+		cmRef := &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: cmName}
+		findings = append(findings, analyzeNodeLocalCorefile(corefile, setup, dnsServiceIP, cmRef)...)
+	} else {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryDNS,
+			Code:     types.CodeDNSNodeLocalConfigMapMissing,
+			Resource: &types.ResourceRef{Kind: "ConfigMap", Namespace: ns, Name: cmName},
+			Summary:  fmt.Sprintf("node-local-dns ConfigMap %s/%s not found", ns, cmName),
+			Detail:   err.Error(),
+		})
+	}
+
+	findings = append(findings, t.checkUpstream(ctx, ns, setup.UpstreamSvc)...)
+	findings = append(findings, t.checkNodes(ctx, ds)...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// nodeLocalDNSConfigMap returns the ConfigMap mounted by the DaemonSet, or
+// the addon's default name.
+func nodeLocalDNSConfigMap(ds *appsv1.DaemonSet) string {
+	for _, v := range ds.Spec.Template.Spec.Volumes {
+		if v.ConfigMap != nil && v.ConfigMap.Name != "" {
+			return v.ConfigMap.Name
+		}
+	}
+	return "node-local-dns"
+}
+
+// checkUpstream verifies the Service node-cache forwards cluster queries to
+// exists, serves DNS over UDP and TCP, and has ready endpoints.
+func (t *CheckNodeLocalDNSTool) checkUpstream(ctx context.Context, ns, name string) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name}
+	svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSNodeLocalUpstreamInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Upstream Service %s/%s not found: cluster-domain queries cannot be forwarded", ns, name),
+			Detail:     err.Error(),
+			Suggestion: "Create the kube-dns-upstream Service from the NodeLocal DNSCache manifest; it selects the CoreDNS pods (k8s-app=kube-dns).",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	clusterIP, _, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP")
+	ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+	protocols := make(map[string]bool)
+	for _, p := range ports {
+		if pm, ok := p.(map[string]interface{}); ok {
+			if port, _, _ := unstructured.NestedInt64(pm, "port"); port == 53 {
+				proto, _, _ := unstructured.NestedString(pm, "protocol")
+				protocols[orDefault(proto, "TCP")] = true
+			}
+		}
+	}
+	if !protocols["UDP"] || !protocols["TCP"] {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSNodeLocalUpstreamInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Upstream Service %s/%s does not expose port 53 on both UDP and TCP", ns, name),
+			Detail:     fmt.Sprintf("udp=%t tcp=%t", protocols["UDP"], protocols["TCP"]),
+			Suggestion: "node-cache forwards over TCP by default (force_tcp); expose 53/TCP and 53/UDP.",
+		})
+	}
+
+	ready := 0
+	if ep, err := t.Clients.Dynamic.Resource(endpointsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		subsets, _, _ := unstructured.NestedSlice(ep.Object, "subsets")
+		for _, s := range subsets {
+			if sm, ok := s.(map[string]interface{}); ok {
+				addrs, _, _ := unstructured.NestedSlice(sm, "addresses")
+				ready += len(addrs)
+			}
+		}
+	}
+	if ready == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryDNS,
+			Code:       types.CodeDNSNodeLocalUpstreamInvalid,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Upstream Service %s/%s has no ready endpoints: cluster-domain lookups through the cache fail", ns, name),
+			Detail:     fmt.Sprintf("clusterIP=%s", clusterIP),
+			Suggestion: "Check that its selector matches the CoreDNS pods and that they are ready.",
+		})
+	} else {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryDNS,
+			Resource: ref,
+			Summary:  fmt.Sprintf("Upstream Service %s/%s has %d ready endpoint(s)", ns, name, ready),
+			Detail:   fmt.Sprintf("clusterIP=%s", clusterIP),
+		})
+	}
+	return findings
+}
+
+// checkNodes reports nodes without a ready node-local-dns pod and pods that
+// restarted recently.
+func (t *CheckNodeLocalDNSTool) checkNodes(ctx context.Context, ds *appsv1.DaemonSet) []types.DiagnosticFinding {
+	nodes, err := t.Clients.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	pods, err := t.Clients.Clientset.CoreV1().Pods(ds.Namespace).List(ctx, metav1.ListOptions{LabelSelector: nodeLocalDNSSelector})
+	if err != nil {
+		return nil
+	}
+	byNode := podsByNode(pods.Items)
+	now := time.Now()
+
+	var findings []types.DiagnosticFinding
+	missing := 0
+	for _, node := range nodes.Items {
+		if !anyPodReady(byNode[node.Name]) {
+			missing++
+			severity := types.SeverityCritical
+			if !nodeReady(node) || node.Spec.Unschedulable {
+				severity = types.SeverityWarning
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeDNSNodeLocalPodMissing,
+				Resource:   &types.ResourceRef{Kind: "Node", Name: node.Name},
+				Summary:    fmt.Sprintf("Node %s has no ready node-local-dns pod: DNS from its pods fails or bypasses the cache", node.Name),
+				Detail:     fmt.Sprintf("pods on node=%d nodeReady=%t", len(byNode[node.Name]), nodeReady(node)),
+				Suggestion: "Check the DaemonSet tolerations against the node's taints and the pod's events; with kubelet --cluster-dns pointing at the cache, pods on this node have no resolver.",
+			})
+		}
+		for _, pod := range byNode[node.Name] {
+			restarts, last, crashLooping := recentRestarts(pod, time.Hour, now)
+			if !crashLooping && last == nil {
+				continue
+			}
+			severity := types.SeverityWarning
+			if crashLooping {
+				severity = types.SeverityCritical
+			}
+			detail := fmt.Sprintf("restarts=%d crashLoopBackOff=%t", restarts, crashLooping)
+			if last != nil {
+				detail += fmt.Sprintf(" lastTermination=%s exitCode=%d", last.Reason, last.ExitCode)
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeDNSNodeLocalRestarts,
+				Resource:   &types.ResourceRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+				Summary:    fmt.Sprintf("node-local-dns on node %s restarted within the last hour", node.Name),
+				Detail:     detail,
+				Suggestion: fmt.Sprintf("Check previous logs: kubectl logs -n %s %s --previous", pod.Namespace, pod.Name),
+			})
+		}
+	}
+	if missing == 0 && len(nodes.Items) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryDNS,
+			Resource: &types.ResourceRef{Kind: "DaemonSet", Namespace: ds.Namespace, Name: ds.Name, APIVersion: "apps/v1"},
+			Summary:  fmt.Sprintf("node-local-dns is ready on all %d nodes", len(nodes.Items)),
+		})
+	}
+	return findings
+}
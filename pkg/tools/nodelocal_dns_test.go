@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseFlagArgs(t *testing.T) {
+	flags := parseFlagArgs([]string{"/node-cache", "-localip", "169.254.20.10,10.96.0.10", "-conf=/etc/Corefile", "--setupiptables=false", "-skipteardown"})
+	want := map[string]string{"localip": "169.254.20.10,10.96.0.10", "conf": "/etc/Corefile", "setupiptables": "false", "skipteardown": "true"}
+	for k, v := range want {
+		if flags[k] != v {
+			t.Errorf("flags[%q] = %q, want %q", k, flags[k], v)
+		}
+	}
+}
+
+func TestEvaluateNodeLocalDNSSetup(t *testing.T) {
+	setup := nodeLocalDNSSetup{LocalIPs: []string{"169.254.20.10", "10.96.0.10"}, SetupIptables: true, HostNetwork: true, NetAdmin: true}
+	findings := evaluateNodeLocalDNSSetup(setup, "10.96.0.10", "iptables", nil)
+	if len(findings) != 1 || findings[0].Severity != types.SeverityOK {
+		t.Errorf("expected a single ok finding in iptables mode, got %+v", findings)
+	}
+
+	findings = evaluateNodeLocalDNSSetup(setup, "10.96.0.10", "ipvs", nil)
+	if countSeverity(findings, types.SeverityWarning) != 1 || !strings.Contains(findings[1].Summary, "IPVS mode") {
+		t.Errorf("expected an IPVS warning, got %+v", findings)
+	}
+
+	setup.NetAdmin = false
+	if countSeverity(evaluateNodeLocalDNSSetup(setup, "10.96.0.10", "iptables", nil), types.SeverityCritical) != 1 {
+		t.Error("expected missing NET_ADMIN to be critical")
+	}
+}
+
+func TestAnalyzeNodeLocalCorefile(t *testing.T) {
+	setup := nodeLocalDNSSetup{LocalIPs: []string{"169.254.20.10", "10.96.0.10"}, UpstreamSvc: "kube-dns-upstream"}
+	corefile := `cluster.local:53 {
+    errors
+    cache 30
+    bind 169.254.20.10 10.96.0.10
+    forward . __PILLAR__CLUSTER__DNS__ {
+        force_tcp
+    }
+}
+.:53 {
+    errors
+    cache 30
+    bind 169.254.20.10 10.96.0.10
+    forward . __PILLAR__UPSTREAM__SERVERS__
+}
+`
+	if findings := analyzeNodeLocalCorefile(corefile, setup, "10.96.0.10", nil); len(findings) != 0 {
+		t.Errorf("expected no findings for the upstream Corefile, got %+v", findings)
+	}
+
+	broken := `__PILLAR__DNS__DOMAIN__:53 {
+    bind 169.254.20.10 10.0.0.53
+    forward . 10.96.0.10
+}
+`
+	findings := analyzeNodeLocalCorefile(broken, setup, "10.96.0.10", nil)
+	for _, w := range []string{"install placeholders", "binds 10.0.0.53", "forwards to the kube-dns ClusterIP", "no cache plugin"} {
+		found := false
+		for _, f := range findings {
+			found = found || strings.Contains(f.Summary, w)
+		}
+		if !found {
+			t.Errorf("expected a finding containing %q, got %+v", w, findings)
+		}
+	}
+}
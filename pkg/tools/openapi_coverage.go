@@ -0,0 +1,614 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// openAPIMethods lists the operation keys recognized under an OpenAPI path item.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// maxSpecSize bounds how much of a remote OpenAPI document is read.
+const maxSpecSize = 4 * 1024 * 1024
+
+// specHTTPClient fetches spec_url documents. Redirects must stay on the
+// Service host, so a spec URL cannot bounce the server out of the cluster.
+var specHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("stopped after 5 redirects")
+		}
+		if req.URL.Host != via[0].URL.Host {
+			return fmt.Errorf("redirect to %s leaves the Service", req.URL.Host)
+		}
+		return nil
+	},
+}
+
+// openAPIOperation is a single method + path template declared in an OpenAPI spec.
+type openAPIOperation struct {
+	Method string
+	Path   string
+}
+
+// routePathMatch is a path/method matcher extracted from an HTTPRoute or Ingress that targets the service.
+type routePathMatch struct {
+	Source   string // e.g. "HTTPRoute ns/name r0"
+	PathType string // Exact, PathPrefix, RegularExpression
+	Value    string
+	Method   string // empty means any method
+}
+
+// --- check_openapi_route_coverage ---
+
+type CheckOpenAPICoverageTool struct{ BaseTool }
+
+func (t *CheckOpenAPICoverageTool) Name() string { return "check_openapi_route_coverage" }
+func (t *CheckOpenAPICoverageTool) Description() string {
+	return "Compare a service's OpenAPI spec (URL or ConfigMap) against the HTTPRoute/Ingress rules routing to it: flags spec operations not routable through the gateway and exposed paths absent from the spec"
+}
+func (t *CheckOpenAPICoverageTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"service": map[string]interface{}{
+				"type":        "string",
+				"description": "Backend service name the spec describes",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the service",
+			},
+			"spec_url": map[string]interface{}{
+				"type":        "string",
+				"description": "HTTP(S) URL of the OpenAPI document (JSON or YAML) served by an in-cluster Service, e.g. http://api.shop.svc:8080/openapi.json",
+			},
+			"spec_configmap": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of a ConfigMap in the service namespace holding the OpenAPI document (alternative to spec_url)",
+			},
+			"spec_key": map[string]interface{}{
+				"type":        "string",
+				"description": "ConfigMap data key containing the spec (default: first key ending in .json, .yaml or .yml)",
+			},
+		},
+		"required": []string{"service", "namespace"},
+	}
+}
+
+func (t *CheckOpenAPICoverageTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	svcName := getStringArg(args, "service", "")
+	ns := getStringArg(args, "namespace", "default")
+	specURL := getStringArg(args, "spec_url", "")
+	specCM := getStringArg(args, "spec_configmap", "")
+	specKey := getStringArg(args, "spec_key", "")
+
+	if svcName == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "service is required"}
+	}
+	if specURL == "" && specCM == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "one of spec_url or spec_configmap is required"}
+	}
+
+	var raw []byte
+	var source string
+	var err error
+	if specURL != "" {
+		raw, err = t.fetchSpecFromURL(ctx, specURL)
+		source = specURL
+	} else {
+		raw, source, err = t.fetchSpecFromConfigMap(ctx, ns, specCM, specKey)
+	}
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "failed to load OpenAPI spec",
+			Detail:  err.Error(),
+		}
+	}
+
+	ops, basePath, err := parseOpenAPIOperations(raw)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "failed to parse OpenAPI spec",
+			Detail:  err.Error(),
+		}
+	}
+
+	matches := t.collectRouteMatches(ctx, ns, svcName)
+
+	svcRef := &types.ResourceRef{Kind: "Service", Namespace: ns, Name: svcName}
+	findings := make([]types.DiagnosticFinding, 0, 8)
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryRouting,
+		Resource: svcRef,
+		Summary:  fmt.Sprintf("spec declares %d operations, %d route matchers target %s/%s", len(ops), len(matches), ns, svcName),
+		Detail:   fmt.Sprintf("source=%s basePath=%s", source, orDefault(basePath, "/")),
+	})
+
+	if len(matches) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeOpenAPIServiceNotRouted,
+			Resource:   svcRef,
+			Summary:    fmt.Sprintf("no HTTPRoute or Ingress routes traffic to %s/%s; none of the spec is reachable through a gateway", ns, svcName),
+			Suggestion: "Create an HTTPRoute (or Ingress) with backendRefs pointing at this service for the paths in the spec.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	// Spec operations that no route can reach
+	var unroutable []string
+	for _, op := range ops {
+		full := joinURLPath(basePath, op.Path)
+		if !operationRoutable(op.Method, full, matches) {
+			unroutable = append(unroutable, strings.ToUpper(op.Method)+" "+full)
+		}
+	}
+	if len(unroutable) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeOpenAPIOperationsNotRoutable,
+			Resource:   svcRef,
+			Summary:    fmt.Sprintf("%d of %d spec operations are not routable through the gateway", len(unroutable), len(ops)),
+			Detail:     strings.Join(unroutable, ", "),
+			Suggestion: "Add HTTPRoute matches (PathPrefix or Exact) covering these paths, or remove them from the published spec if they are internal-only.",
+		})
+	} else {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Resource: svcRef,
+			Summary:  fmt.Sprintf("all %d spec operations are routable", len(ops)),
+		})
+	}
+
+	// Route matchers that expose paths/methods outside the spec
+	fullPaths := make([]string, 0, len(ops))
+	for _, op := range ops {
+		fullPaths = append(fullPaths, joinURLPath(basePath, op.Path))
+	}
+	for _, m := range matches {
+		if reason := matchExceedsSpec(m, ops, basePath, fullPaths); reason != "" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeOpenAPIPathsNotInSpec,
+				Resource:   svcRef,
+				Summary:    fmt.Sprintf("%s exposes paths absent from the spec: %s", m.Source, describeRouteMatch(m)),
+				Detail:     reason,
+				Suggestion: "Narrow the route match to the documented paths, or document the additional endpoints in the OpenAPI spec.",
+			})
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// fetchSpecFromURL downloads an OpenAPI document from an in-cluster Service.
+// Other hosts are refused: the server would fetch them with its own network
+// identity, e.g. from a cloud metadata endpoint.
+func (t *CheckOpenAPICoverageTool) fetchSpecFromURL(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("spec_url must be an http(s) URL: %s", rawURL)
+	}
+	svcName, ns, ok := specURLService(u)
+	if !ok {
+		return nil, fmt.Errorf("spec_url must point at an in-cluster Service, e.g. http://<service>.<namespace>.svc:8080/openapi.json, not %s", u.Host)
+	}
+	svc, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", ns, svcName, err)
+	}
+	if svcType, _, _ := unstructured.NestedString(svc.Object, "spec", "type"); svcType == "ExternalName" {
+		return nil, fmt.Errorf("service %s/%s is of type ExternalName, which resolves outside the cluster", ns, svcName)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := specHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned HTTP %d", u.String(), resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxSpecSize {
+		return nil, fmt.Errorf("spec at %s is larger than %d MiB", u.String(), maxSpecSize>>20)
+	}
+	return raw, nil
+}
+
+// specURLService returns the Service and namespace of a URL whose host is a
+// Service DNS name: <service>.<namespace>.svc, which pods resolve in any
+// cluster domain, or <service>.<namespace>.svc.cluster.local.
+func specURLService(u *url.URL) (svc, ns string, ok bool) {
+	host := strings.TrimSuffix(strings.TrimSuffix(u.Hostname(), "."), ".cluster.local")
+	labels := strings.Split(host, ".")
+	if len(labels) != 3 || labels[2] != "svc" {
+		return "", "", false
+	}
+	for _, l := range labels {
+		if len(validation.IsDNS1123Label(l)) > 0 {
+			return "", "", false
+		}
+	}
+	return labels[0], labels[1], true
+}
+
+// fetchSpecFromConfigMap reads the spec document out of a ConfigMap data key.
+func (t *CheckOpenAPICoverageTool) fetchSpecFromConfigMap(ctx context.Context, ns, name, key string) ([]byte, string, error) {
+	cm, err := t.Clients.Dynamic.Resource(configmapsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get configmap %s/%s: %w", ns, name, err)
+	}
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	if key == "" {
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if strings.HasSuffix(k, ".json") || strings.HasSuffix(k, ".yaml") || strings.HasSuffix(k, ".yml") {
+				key = k
+				break
+			}
+		}
+	}
+	content, ok := data[key]
+	if key == "" || !ok {
+		return nil, "", fmt.Errorf("configmap %s/%s has no spec key (tried %q)", ns, name, key)
+	}
+	return []byte(content), fmt.Sprintf("configmap:%s/%s[%s]", ns, name, key), nil
+}
+
+// parseOpenAPIOperations decodes a JSON or YAML OpenAPI 2/3 document and returns its operations
+// along with the base path (Swagger basePath or the path of the first OpenAPI 3 server URL).
+func parseOpenAPIOperations(raw []byte) ([]openAPIOperation, string, error) {
+	var doc map[string]interface{}
+	if err := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(raw), 4096).Decode(&doc); err != nil {
+		return nil, "", err
+	}
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("document has no paths object")
+	}
+
+	basePath, _ := doc["basePath"].(string)
+	if servers, ok := doc["servers"].([]interface{}); ok && len(servers) > 0 {
+		if sm, ok := servers[0].(map[string]interface{}); ok {
+			if su, ok := sm["url"].(string); ok {
+				if u, err := url.Parse(su); err == nil {
+					basePath = u.Path
+				}
+			}
+		}
+	}
+
+	pathKeys := make([]string, 0, len(paths))
+	for p := range paths {
+		pathKeys = append(pathKeys, p)
+	}
+	sort.Strings(pathKeys)
+
+	var ops []openAPIOperation
+	for _, p := range pathKeys {
+		item, ok := paths[p].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, m := range openAPIMethods {
+			if _, ok := item[m]; ok {
+				ops = append(ops, openAPIOperation{Method: m, Path: p})
+			}
+		}
+	}
+	return ops, strings.TrimSuffix(basePath, "/"), nil
+}
+
+// collectRouteMatches gathers path matchers from HTTPRoutes and Ingresses whose backends include the service.
+func (t *CheckOpenAPICoverageTool) collectRouteMatches(ctx context.Context, ns, svcName string) []routePathMatch {
+	var matches []routePathMatch
+
+	if routes, err := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ""); err == nil {
+		for _, route := range routes.Items {
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			for i, r := range rules {
+				rm, ok := r.(map[string]interface{})
+				if !ok || !ruleTargetsService(rm, route.GetNamespace(), ns, svcName) {
+					continue
+				}
+				source := fmt.Sprintf("HTTPRoute %s/%s r%d", route.GetNamespace(), route.GetName(), i)
+				matches = append(matches, httpRuleMatches(rm, source)...)
+			}
+		}
+	}
+
+	if ingresses, err := t.listResource(ctx, ingressGVR, ns); err == nil {
+		for _, ing := range ingresses.Items {
+			rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+			for _, r := range rules {
+				rm, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				httpPaths, _, _ := unstructured.NestedSlice(rm, "http", "paths")
+				for _, p := range httpPaths {
+					pm, ok := p.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					backend, _, _ := unstructured.NestedString(pm, "backend", "service", "name")
+					if backend != svcName {
+						continue
+					}
+					path, _ := pm["path"].(string)
+					pathType := "PathPrefix"
+					if pt, _ := pm["pathType"].(string); pt == "Exact" {
+						pathType = "Exact"
+					}
+					matches = append(matches, routePathMatch{
+						Source:   fmt.Sprintf("Ingress %s/%s", ing.GetNamespace(), ing.GetName()),
+						PathType: pathType,
+						Value:    orDefault(path, "/"),
+					})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// ruleTargetsService reports whether any backendRef of the rule points at ns/svcName.
+func ruleTargetsService(rm map[string]interface{}, routeNs, ns, svcName string) bool {
+	brs, _ := rm["backendRefs"].([]interface{})
+	for _, br := range brs {
+		brm, ok := br.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := brm["kind"].(string)
+		if kind != "" && kind != "Service" {
+			continue
+		}
+		name, _ := brm["name"].(string)
+		brNs, _ := brm["namespace"].(string)
+		if brNs == "" {
+			brNs = routeNs
+		}
+		if name == svcName && brNs == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// httpRuleMatches converts the matches of an HTTPRoute rule to routePathMatch values.
+// A rule without matches behaves as PathPrefix "/".
+func httpRuleMatches(rm map[string]interface{}, source string) []routePathMatch {
+	ms, _ := rm["matches"].([]interface{})
+	if len(ms) == 0 {
+		return []routePathMatch{{Source: source, PathType: "PathPrefix", Value: "/"}}
+	}
+	out := make([]routePathMatch, 0, len(ms))
+	for _, m := range ms {
+		mm, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rpm := routePathMatch{Source: source, PathType: "PathPrefix", Value: "/"}
+		if path, ok := mm["path"].(map[string]interface{}); ok {
+			if pt, _ := path["type"].(string); pt != "" {
+				rpm.PathType = pt
+			}
+			if v, _ := path["value"].(string); v != "" {
+				rpm.Value = v
+			}
+		}
+		rpm.Method, _ = mm["method"].(string)
+		out = append(out, rpm)
+	}
+	return out
+}
+
+// templateParam matches OpenAPI path template parameters such as {id}.
+var templateParam = regexp.MustCompile(`\{[^}/]+\}`)
+
+// pathTemplateRegex compiles an OpenAPI path template into an anchored regex.
+func pathTemplateRegex(tmpl string) *regexp.Regexp {
+	parts := templateParam.Split(tmpl, -1)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "$")
+}
+
+// samplePath replaces template parameters with a concrete value.
+func samplePath(tmpl string) string {
+	return templateParam.ReplaceAllString(tmpl, "x")
+}
+
+// pathHasSegmentPrefix reports whether prefix is a path-element-wise prefix of path,
+// following Gateway API PathPrefix semantics ("/foo" matches "/foo/bar" but not "/foobar").
+func pathHasSegmentPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// operationRoutable reports whether some route matcher forwards the operation to the backend.
+func operationRoutable(method, path string, matches []routePathMatch) bool {
+	for _, m := range matches {
+		if m.Method != "" && !strings.EqualFold(m.Method, method) {
+			continue
+		}
+		switch m.PathType {
+		case "Exact":
+			if pathTemplateRegex(path).MatchString(m.Value) {
+				return true
+			}
+		case "RegularExpression":
+			if re, err := regexp.Compile(m.Value); err == nil && re.MatchString(samplePath(path)) {
+				return true
+			}
+		default:
+			if pathHasSegmentPrefix(path, m.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchExceedsSpec explains how a route matcher reaches beyond the documented surface, or returns "".
+func matchExceedsSpec(m routePathMatch, ops []openAPIOperation, basePath string, fullPaths []string) string {
+	// Method restriction: the matched method must appear on some covered spec operation
+	methodOK := func(path string) bool {
+		if m.Method == "" {
+			return true
+		}
+		for _, op := range ops {
+			if joinURLPath(basePath, op.Path) == path && strings.EqualFold(op.Method, m.Method) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch m.PathType {
+	case "Exact":
+		for _, p := range fullPaths {
+			if pathTemplateRegex(p).MatchString(m.Value) && methodOK(p) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("exact path %s%s matches no documented operation", methodPrefix(m.Method), m.Value)
+	case "RegularExpression":
+		// Regex coverage cannot be enumerated; only flag when it matches nothing documented.
+		re, err := regexp.Compile(m.Value)
+		if err != nil {
+			return fmt.Sprintf("invalid regular expression %q", m.Value)
+		}
+		for _, p := range fullPaths {
+			if re.MatchString(samplePath(p)) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("regex %q matches no documented path", m.Value)
+	default:
+		covered := 0
+		for _, p := range fullPaths {
+			if pathHasSegmentPrefix(p, m.Value) && methodOK(p) {
+				covered++
+			}
+		}
+		if covered == 0 {
+			return fmt.Sprintf("prefix %s%s covers no documented operation", methodPrefix(m.Method), m.Value)
+		}
+		// The prefix is broader than the spec when it is shorter than the longest common prefix of all paths.
+		common := commonPathPrefix(fullPaths)
+		if common != "" && !pathHasSegmentPrefix(strings.TrimSuffix(m.Value, "/"), common) && !pathTemplateMatchesAny(m.Value, fullPaths) {
+			return fmt.Sprintf("prefix %s is broader than the documented API root %s; any path under it is forwarded", orDefault(m.Value, "/"), common)
+		}
+		return ""
+	}
+}
+
+// pathTemplateMatchesAny reports whether the literal path equals one of the documented templates.
+func pathTemplateMatchesAny(path string, templates []string) bool {
+	path = strings.TrimSuffix(path, "/")
+	for _, t := range templates {
+		if pathTemplateRegex(t).MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// commonPathPrefix returns the longest segment-aligned literal prefix shared by all paths.
+func commonPathPrefix(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := strings.Split(strings.Trim(paths[0], "/"), "/")
+	for _, p := range paths[1:] {
+		segs := strings.Split(strings.Trim(p, "/"), "/")
+		n := 0
+		for n < len(common) && n < len(segs) && common[n] == segs[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	// Stop at the first template parameter
+	for i, s := range common {
+		if strings.Contains(s, "{") {
+			common = common[:i]
+			break
+		}
+	}
+	if len(common) == 0 || (len(common) == 1 && common[0] == "") {
+		return ""
+	}
+	return "/" + strings.Join(common, "/")
+}
+
+// joinURLPath joins a base path and an operation path with exactly one slash between them.
+func joinURLPath(base, p string) string {
+	if base == "" {
+		return p
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(p, "/")
+}
+
+func describeRouteMatch(m routePathMatch) string {
+	return fmt.Sprintf("%s%s(%s)", methodPrefix(m.Method), m.PathType, m.Value)
+}
+
+func methodPrefix(method string) string {
+	if method == "" {
+		return ""
+	}
+	return strings.ToUpper(method) + " "
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /users:
+    get: {}
+    post: {}
+  /users/{id}:
+    get: {}
+    delete: {}
+`
+
+func TestParseOpenAPIOperations_YAMLWithServers(t *testing.T) {
+	ops, basePath, err := parseOpenAPIOperations([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if basePath != "/v1" {
+		t.Errorf("basePath = %q, want /v1", basePath)
+	}
+	if len(ops) != 4 {
+		t.Fatalf("expected 4 operations, got %d: %+v", len(ops), ops)
+	}
+}
+
+func TestParseOpenAPIOperations_SwaggerBasePathJSON(t *testing.T) {
+	raw := `{"swagger":"2.0","basePath":"/api/","paths":{"/health":{"get":{}}}}`
+	ops, basePath, err := parseOpenAPIOperations([]byte(raw))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if basePath != "/api" {
+		t.Errorf("basePath = %q, want /api", basePath)
+	}
+	if len(ops) != 1 || ops[0].Method != "get" || ops[0].Path != "/health" {
+		t.Errorf("unexpected ops: %+v", ops)
+	}
+}
+
+func TestParseOpenAPIOperations_NoPaths(t *testing.T) {
+	if _, _, err := parseOpenAPIOperations([]byte(`{"openapi":"3.0.0"}`)); err == nil {
+		t.Error("expected error for document without paths")
+	}
+}
+
+func TestPathHasSegmentPrefix(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		want         bool
+	}{
+		{"/v1/users", "/v1", true},
+		{"/v1/users", "/v1/", true},
+		{"/v1users", "/v1", false},
+		{"/v1", "/v1", true},
+		{"/anything", "/", true},
+	}
+	for _, tc := range tests {
+		if got := pathHasSegmentPrefix(tc.path, tc.prefix); got != tc.want {
+			t.Errorf("pathHasSegmentPrefix(%q, %q) = %v, want %v", tc.path, tc.prefix, got, tc.want)
+		}
+	}
+}
+
+func TestOperationRoutable(t *testing.T) {
+	matches := []routePathMatch{
+		{PathType: "PathPrefix", Value: "/v1/users"},
+		{PathType: "Exact", Value: "/v1/orders/42", Method: "GET"},
+	}
+	if !operationRoutable("get", "/v1/users/{id}", matches) {
+		t.Error("expected /v1/users/{id} to be routable via prefix")
+	}
+	if !operationRoutable("get", "/v1/orders/{id}", matches) {
+		t.Error("expected GET /v1/orders/{id} to be routable via exact match")
+	}
+	if operationRoutable("delete", "/v1/orders/{id}", matches) {
+		t.Error("expected DELETE /v1/orders/{id} to be unroutable (method restricted)")
+	}
+	if operationRoutable("get", "/v1/admin", matches) {
+		t.Error("expected /v1/admin to be unroutable")
+	}
+}
+
+func TestMatchExceedsSpec(t *testing.T) {
+	ops := []openAPIOperation{{Method: "get", Path: "/users"}, {Method: "get", Path: "/users/{id}"}}
+	full := []string{"/v1/users", "/v1/users/{id}"}
+
+	if r := matchExceedsSpec(routePathMatch{PathType: "PathPrefix", Value: "/v1/users"}, ops, "/v1", full); r != "" {
+		t.Errorf("expected documented prefix to pass, got %q", r)
+	}
+	if r := matchExceedsSpec(routePathMatch{PathType: "PathPrefix", Value: "/"}, ops, "/v1", full); r == "" {
+		t.Error("expected catch-all prefix to be flagged as broader than the spec")
+	}
+	if r := matchExceedsSpec(routePathMatch{PathType: "Exact", Value: "/v1/admin"}, ops, "/v1", full); r == "" {
+		t.Error("expected undocumented exact path to be flagged")
+	}
+	if r := matchExceedsSpec(routePathMatch{PathType: "PathPrefix", Value: "/v1/users", Method: "DELETE"}, ops, "/v1", full); r == "" {
+		t.Error("expected undocumented method to be flagged")
+	}
+}
+
+func TestSpecURLService(t *testing.T) {
+	for raw, want := range map[string]string{
+		"http://api.shop.svc:8080/openapi.json":           "shop/api",
+		"https://api.shop.svc.cluster.local/openapi.json": "shop/api",
+		"http://api.shop.svc.cluster.local./openapi.json": "shop/api",
+		"http://169.254.169.254/latest/meta-data":         "",
+		"http://metadata.google.internal/computeMetadata": "",
+		"http://api.shop.svc.example.com/openapi.json":    "",
+		"http://api.shop/openapi.json":                    "",
+		"http://api.shop.svc.other.local/openapi.json":    "",
+		"http://API_1.shop.svc/openapi.json":              "",
+		"http://a.b.shop.svc.cluster.local/openapi.json":  "",
+	} {
+		u, _ := url.Parse(raw)
+		svc, ns, ok := specURLService(u)
+		if got := ns + "/" + svc; (ok && got != want) || (!ok && want != "") {
+			t.Errorf("%s: got %q, %v; want %q", raw, got, ok, want)
+		}
+	}
+}
+
+func TestFetchSpecFromURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(`{"paths":{}}`)) })
+	mux.HandleFunc("/huge.json", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(make([]byte, maxSpecSize+1)) })
+	mux.HandleFunc("/away", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// Every host dials the test server.
+	prev := specHTTPClient.Transport
+	specHTTPClient.Transport = &http.Transport{DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}}
+	defer func() { specHTTPClient.Transport = prev }()
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		dualStackService("api", "ClusterIP", "SingleStack"),
+		dualStackService("external", "ExternalName", "SingleStack"))
+	tool := &CheckOpenAPICoverageTool{BaseTool{Cfg: &config.Config{}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	for raw, wantErr := range map[string]string{
+		"http://api.shop.svc/openapi.json":            "",
+		"http://api.shop.svc/huge.json":               "larger than 4 MiB",
+		"http://api.shop.svc/away":                    "leaves the Service",
+		"http://external.shop.svc/openapi.json":       "ExternalName",
+		"http://missing.shop.svc/openapi.json":        "failed to get service shop/missing",
+		"http://169.254.169.254/latest/meta-data":     "must point at an in-cluster Service",
+		"file:///var/run/secrets/kubernetes.io/token": "must be an http(s) URL",
+	} {
+		raw, err := tool.fetchSpecFromURL(context.Background(), raw)
+		switch {
+		case wantErr == "" && (err != nil || string(raw) != `{"paths":{}}`):
+			t.Errorf("got %q, %v", raw, err)
+		case wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)):
+			t.Errorf("got error %v, want %q", err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// maxPageLimit caps the page size a caller may request.
+const maxPageLimit = 500
+
+// pageRequest is the pagination contract shared by list-style tools: callers
+// pass "limit" to get at most that many items and receive a continue_token in
+// the response while more remain. Selectors narrow every page alike.
+type pageRequest struct {
+	Limit    int64
+	Continue string
+	listSelector
+}
+
+// paged reports whether the caller asked for a page rather than everything.
+func (p pageRequest) paged() bool { return p.Limit > 0 || p.Continue != "" }
+
+func getPageArgs(args map[string]interface{}) pageRequest {
+	limit := int64(getIntArg(args, "limit", 0))
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return pageRequest{Limit: limit, Continue: getStringArg(args, "continue_token", ""), listSelector: getSelectorArgs(args)}
+}
+
+// withPagination adds the limit and continue_token properties to a tool schema.
+func withPagination(schema map[string]interface{}) map[string]interface{} {
+	props, _ := schema["properties"].(map[string]interface{})
+	if props == nil {
+		props = map[string]interface{}{}
+		schema["properties"] = props
+	}
+	props["limit"] = map[string]interface{}{
+		"type":        "integer",
+		"description": fmt.Sprintf("Maximum number of items to return (max %d); the response carries a continue_token when more remain", maxPageLimit),
+	}
+	props["continue_token"] = map[string]interface{}{
+		"type":        "string",
+		"description": "continue_token from the previous response, to fetch the next page with the same arguments",
+	}
+	return schema
+}
+
+// listResourcePage lists one page of gvr straight from the API server using
+// ListOptions.Limit/Continue. Without a page request it returns the full list
+// via listResourceSelected.
+func (b *BaseTool) listResourcePage(ctx context.Context, gvr schema.GroupVersionResource, ns string, page pageRequest) (*unstructured.UnstructuredList, error) {
+	if !page.paged() {
+		return b.listResourceSelected(ctx, gvr, ns, page.listSelector)
+	}
+	opts, err := page.listOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts.Limit, opts.Continue = page.Limit, page.Continue
+	var list *unstructured.UnstructuredList
+	if ns == "" {
+		list, err = b.Clients.Dynamic.Resource(gvr).List(ctx, opts)
+	} else {
+		list, err = b.Clients.Dynamic.Resource(gvr).Namespace(ns).List(ctx, opts)
+	}
+	if !isPageError(err) && !isSelectorError(err) {
+		recordListError(ctx, gvr, ns, err)
+	}
+	return list, err
+}
+
+// listResourcePageWithFallback is listResourcePage with a v1beta1 fallback.
+func (b *BaseTool) listResourcePageWithFallback(ctx context.Context, v1, v1beta1 schema.GroupVersionResource, ns string, page pageRequest) (*unstructured.UnstructuredList, error) {
+	list, err := b.listResourcePage(ctx, v1, ns, page)
+	if err == nil || isPageError(err) || isSelectorError(err) {
+		return list, err
+	}
+	return b.listResourcePage(ctx, v1beta1, ns, page)
+}
+
+// isPageError reports whether err was caused by an expired or malformed
+// continue token rather than by the resource itself.
+func isPageError(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err) ||
+		(apierrors.IsBadRequest(err) && strings.Contains(err.Error(), "continue"))
+}
+
+// pageError converts continue-token failures into INVALID_INPUT so callers
+// know to restart the listing. It returns nil for other errors.
+func pageError(tool string, err error) error {
+	if !isPageError(err) {
+		return nil
+	}
+	return &types.MCPError{
+		Code:    types.ErrCodeInvalidInput,
+		Tool:    tool,
+		Message: "continue_token is expired or invalid; restart the listing without it",
+		Detail:  err.Error(),
+	}
+}
+
+// WithContinue records the token for the next page.
+func (r *StandardResponse) WithContinue(token string) *StandardResponse {
+	r.Continue = token
+	return r
+}
+
+// Offset tokens page through results the tool computes itself, such as log
+// lines, rather than API lists. They are opaque to callers.
+const offsetTokenPrefix = "offset:"
+
+func encodeOffsetToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offsetTokenPrefix + strconv.Itoa(offset)))
+}
+
+func decodeOffsetToken(tool, token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil && strings.HasPrefix(string(raw), offsetTokenPrefix) {
+		if n, err := strconv.Atoi(strings.TrimPrefix(string(raw), offsetTokenPrefix)); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, &types.MCPError{
+		Code:    types.ErrCodeInvalidInput,
+		Tool:    tool,
+		Message: "continue_token is invalid; restart the listing without it",
+	}
+}
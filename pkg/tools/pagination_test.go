@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestGetPageArgs(t *testing.T) {
+	if p := getPageArgs(map[string]interface{}{}); p.paged() {
+		t.Errorf("no arguments should mean no pagination, got %+v", p)
+	}
+	p := getPageArgs(map[string]interface{}{"limit": float64(10000), "continue_token": "abc"})
+	if p.Limit != maxPageLimit || p.Continue != "abc" {
+		t.Errorf("getPageArgs() = %+v, want limit capped at %d", p, maxPageLimit)
+	}
+}
+
+func TestOffsetToken(t *testing.T) {
+	n, err := decodeOffsetToken("analyze_log_errors", encodeOffsetToken(42))
+	if err != nil || n != 42 {
+		t.Fatalf("round trip = %d, %v; want 42", n, err)
+	}
+	if _, err := decodeOffsetToken("analyze_log_errors", "not-a-token"); err == nil {
+		t.Error("expected an error for a malformed token")
+	}
+}
+
+func TestPageError(t *testing.T) {
+	expired := apierrors.NewResourceExpired("continue token too old")
+	mcpErr, ok := pageError("list_services", expired).(*types.MCPError)
+	if !ok || mcpErr.Code != types.ErrCodeInvalidInput {
+		t.Errorf("expected INVALID_INPUT for an expired token, got %v", mcpErr)
+	}
+	if err := pageError("list_services", apierrors.NewNotFound(servicesGVR.GroupResource(), "x")); err != nil {
+		t.Errorf("unrelated errors should pass through, got %v", err)
+	}
+}
+
+func TestStandardResponse_ToTextContinue(t *testing.T) {
+	resp := NewToolResultResponse(&config.Config{ClusterName: "c"}, "list_services", nil, "", "").WithContinue("tok")
+	if !strings.Contains(resp.ToText(), "continue_token=tok") {
+		t.Errorf("ToText() should mention the continue token:\n%s", resp.ToText())
+	}
+}
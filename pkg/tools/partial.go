@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceError is a resource type a tool could not list, so its results
+// leave that type out.
+type ResourceError struct {
+	// Resource is resource.group/version, e.g. virtualservices.networking.istio.io/v1.
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Error     string `json:"error"`
+	// Code classifies the failure, e.g. FORBIDDEN; see ClassifyError.
+	Code string `json:"code,omitempty"`
+}
+
+// ListErrors collects the list failures of one tool call. Scans continue past
+// a resource type that times out or is forbidden instead of failing, and the
+// failures are returned as the response's errors.
+type ListErrors struct {
+	mu     sync.Mutex
+	errors map[ResourceError]bool
+}
+
+type listErrorsKey struct{}
+
+// WithListErrors returns a context whose list failures are collected in the
+// returned ListErrors.
+func WithListErrors(ctx context.Context) (context.Context, *ListErrors) {
+	le := &ListErrors{errors: make(map[ResourceError]bool)}
+	return context.WithValue(ctx, listErrorsKey{}, le), le
+}
+
+// Errors returns the collected failures sorted by resource and namespace.
+func (le *ListErrors) Errors() []ResourceError {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	out := make([]ResourceError, 0, len(le.errors))
+	for e := range le.errors {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Resource != out[j].Resource {
+			return out[i].Resource < out[j].Resource
+		}
+		return out[i].Namespace < out[j].Namespace
+	})
+	return out
+}
+
+// recordListError notes a failed list of gvr on the ListErrors of ctx.
+// NotFound means the CRD or API version is not installed, which tools
+// already handle, so it is not recorded.
+func recordListError(ctx context.Context, gvr schema.GroupVersionResource, ns string, err error) {
+	if err == nil || apierrors.IsNotFound(err) {
+		return
+	}
+	le, ok := ctx.Value(listErrorsKey{}).(*ListErrors)
+	if !ok {
+		return
+	}
+	resource := gvr.Resource
+	if gvr.Group != "" {
+		resource += "." + gvr.Group
+	}
+	e := ResourceError{Resource: resource + "/" + gvr.Version, Namespace: ns, Error: err.Error(), Code: ClassifyError("", err).Code}
+	le.mu.Lock()
+	le.errors[e] = true
+	le.mu.Unlock()
+}
+
+// orEmpty returns list, or an empty list when listing failed. The failure
+// itself is reported in the response's errors.
+func orEmpty(list *unstructured.UnstructuredList, err error) *unstructured.UnstructuredList {
+	if err != nil || list == nil {
+		return &unstructured.UnstructuredList{}
+	}
+	return list
+}
+
+// partialText renders the errors of a partial response.
+func partialText(errs []ResourceError) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PARTIAL RESULTS: %d resource type(s) could not be listed and are missing from the findings:", len(errs))
+	for _, e := range errs {
+		b.WriteString("\n- " + e.Resource)
+		if e.Namespace != "" {
+			b.WriteString(" in " + e.Namespace)
+		}
+		b.WriteString(": " + e.Error)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestValidateIstioConfigPartialResults(t *testing.T) {
+	dr := ipamObj("networking.istio.io/v1", "DestinationRule", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{
+		"host":    "web",
+		"subsets": []interface{}{map[string]interface{}{"name": "v1"}},
+	}})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		vsV1GVR: "VirtualServiceList", vsV1B1GVR: "VirtualServiceList",
+		drV1GVR: "DestinationRuleList", drV1B1GVR: "DestinationRuleList",
+	}, dr)
+	// The VirtualService API times out, as a slow aggregated API would.
+	client.PrependReactor("list", "virtualservices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTimeoutError("the server was unable to return a response in the time allotted", 0)
+	})
+
+	tool := &ValidateIstioConfigTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+	ctx, listErrs := WithListErrors(context.Background())
+	resp, err := tool.Run(ctx, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("expected partial results, got %v", err)
+	}
+	if len(resp.Data.(*types.ToolResult).Findings) == 0 {
+		t.Error("expected the DestinationRules to be validated")
+	}
+
+	errs := listErrs.Errors()
+	if len(errs) != 1 || errs[0].Resource != "virtualservices.networking.istio.io/v1" || !strings.Contains(errs[0].Error, "allotted") {
+		t.Fatalf("unexpected errors %+v", errs)
+	}
+	resp.Errors = errs
+	if text := resp.ToText(); !strings.Contains(text, "PARTIAL RESULTS: 1 resource type(s)") {
+		t.Errorf("expected a partial results note:\n%s", text)
+	}
+}
+
+func TestRecordListErrorSkipsNotFound(t *testing.T) {
+	ctx, listErrs := WithListErrors(context.Background())
+	recordListError(ctx, vsV1GVR, "", apierrors.NewNotFound(schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}, ""))
+	recordListError(ctx, servicesGVR, "shop", apierrors.NewForbidden(schema.GroupResource{Resource: "services"}, "", nil))
+	recordListError(ctx, servicesGVR, "shop", apierrors.NewForbidden(schema.GroupResource{Resource: "services"}, "", nil))
+	errs := listErrs.Errors()
+	if len(errs) != 1 || errs[0].Resource != "services/v1" || errs[0].Namespace != "shop" {
+		t.Errorf("unexpected errors %+v", errs)
+	}
+	// Without a collector, failures are ignored.
+	recordListError(context.Background(), servicesGVR, "", apierrors.NewForbidden(schema.GroupResource{Resource: "services"}, "", nil))
+}
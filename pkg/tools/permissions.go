@@ -0,0 +1,353 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Permission is one Kubernetes API access a tool needs.
+type Permission struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	// Namespace restricts the check to one namespace; empty is cluster-wide.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (p Permission) String() string {
+	s := p.Verb + " " + p.Resource
+	if p.Subresource != "" {
+		s += "/" + p.Subresource
+	}
+	if p.Group != "" {
+		s += "." + p.Group
+	}
+	if p.Namespace != "" {
+		s += " in " + p.Namespace
+	}
+	return s
+}
+
+// Rule renders the ClusterRole (or Role, for a namespace) rule granting p.
+func (p Permission) Rule() string {
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	return fmt.Sprintf("- apiGroups: [%q]\n  resources: [%s]\n  verbs: [%s]", p.Group, resource, p.Verb)
+}
+
+// PermissionedTool is implemented by tools, e.g. from third-party providers,
+// that declare the API access they need. Built-in tools are listed in
+// toolPermissions.
+type PermissionedTool interface {
+	RequiredPermissions() []Permission
+}
+
+func perm(verb, group, resource string) Permission {
+	return Permission{Verb: verb, Group: group, Resource: resource}
+}
+
+const (
+	groupApps       = "apps"
+	groupNetworking = "networking.k8s.io"
+	groupGateway    = "gateway.networking.k8s.io"
+	groupIstioNet   = "networking.istio.io"
+	groupIstioSec   = "security.istio.io"
+	groupCilium     = "cilium.io"
+	groupCalico     = "crd.projectcalico.org"
+	groupKgateway   = "gateway.kgateway.dev"
+	groupMetalLB    = "metallb.io"
+	groupAppMesh    = "appmesh.k8s.aws"
+	groupGKE        = "networking.gke.io"
+	groupVPCLattice = "application-networking.k8s.aws"
+)
+
+var (
+	permListServices           = perm("list", "", "services")
+	permListEndpoints          = perm("list", "", "endpoints")
+	permListPods               = perm("list", "", "pods")
+	permListNamespaces         = perm("list", "", "namespaces")
+	permListNodes              = perm("list", "", "nodes")
+	permListConfigMaps         = perm("list", "", "configmaps")
+	permListDeployments        = perm("list", groupApps, "deployments")
+	permListDaemonSets         = perm("list", groupApps, "daemonsets")
+	permListNetworkPolicies    = perm("list", groupNetworking, "networkpolicies")
+	permListIngresses          = perm("list", groupNetworking, "ingresses")
+	permPodLogs                = Permission{Verb: "get", Resource: "pods", Subresource: "log"}
+	permListGateways           = perm("list", groupGateway, "gateways")
+	permListHTTPRoutes         = perm("list", groupGateway, "httproutes")
+	permListGRPCRoutes         = perm("list", groupGateway, "grpcroutes")
+	permListReferenceGrants    = perm("list", groupGateway, "referencegrants")
+	permListBackendTLSPolicies = perm("list", groupGateway, "backendtlspolicies")
+	permListVirtualServices    = perm("list", groupIstioNet, "virtualservices")
+	permListDestRules          = perm("list", groupIstioNet, "destinationrules")
+	permListPeerAuths          = perm("list", groupIstioSec, "peerauthentications")
+	permListAuthzPolicies      = perm("list", groupIstioSec, "authorizationpolicies")
+)
+
+// toolPermissions lists the API access of the built-in tools. Resources a
+// tool only reads when their CRDs are installed are left out, so a cluster
+// without them does not report the tool as degraded.
+var toolPermissions = map[string][]Permission{
+	// Core Kubernetes
+	"list_services":                {permListServices, permListEndpoints},
+	"get_service":                  {perm("get", "", "services"), permListEndpoints, permListPods},
+	"list_endpoints":               {permListEndpoints},
+	"check_traffic_policy":         {permListServices, permListEndpoints, permListNodes, permListNamespaces},
+	"analyze_external_exposure":    {permListServices, permListPods, permListNetworkPolicies},
+	"audit_egress":                 {permListServices, permListNamespaces, permListNetworkPolicies},
+	"export_service_catalog":       {permListServices, permListPods, permListNamespaces, permListNetworkPolicies},
+	"estimate_blast_radius":        {permListServices, permListPods, permListEndpoints, permListIngresses},
+	"build_service_graph":          {permListServices, permListPods, permListNamespaces, permListNetworkPolicies, permListIngresses},
+	"analyze_change_impact":        {permListServices, permListPods, permListNamespaces, permListNetworkPolicies, permListIngresses, permListAuthzPolicies},
+	"diff_network_config":          {permListNamespaces, permListServices, permListNetworkPolicies, permListIngresses},
+	"audit_tls_policy":             {permListIngresses, permListConfigMaps},
+	"check_certificate_sni":        {permListIngresses},
+	"quick_scan":                   {permListServices, permListEndpoints, permListNetworkPolicies},
+	"validate_manifests":           {permListPods},
+	"get_config_timeline":          {permListServices, permListIngresses, permListNetworkPolicies},
+	"diff_snapshots":               {permListServices, permListIngresses, permListNetworkPolicies},
+	"list_networkpolicies":         {permListNetworkPolicies},
+	"get_networkpolicy":            {perm("get", groupNetworking, "networkpolicies")},
+	"check_networkpolicy_ports":    {permListNetworkPolicies, permListPods, permListNamespaces},
+	"analyze_policy_coverage":      {permListNetworkPolicies, permListPods, permListServices},
+	"check_dns_resolution":         {permListServices, permListEndpoints, permListPods},
+	"analyze_coredns_config":       {permListConfigMaps, permListServices, permListPods},
+	"check_kube_proxy_health":      {permListDaemonSets, permListPods, permListConfigMaps, permListNodes},
+	"recommend_scaling":            {permListPods, permListServices, permListDeployments, perm("list", "", "resourcequotas"), perm("list", "autoscaling", "horizontalpodautoscalers")},
+	"list_ingresses":               {permListIngresses, perm("list", groupNetworking, "ingressclasses")},
+	"get_ingress":                  {perm("get", groupNetworking, "ingresses"), permListServices, perm("list", groupNetworking, "ingressclasses")},
+	"check_dataplane_health":       {permListPods, permListDeployments},
+	"analyze_ipam":                 {permListNodes, permListPods, permListServices, permListNamespaces},
+	"analyze_dual_stack":           {permListNodes, permListPods, permListServices, permListNetworkPolicies, permListGateways},
+	"check_admission_webhooks":     {perm("list", "admissionregistration.k8s.io", "validatingwebhookconfigurations"), perm("list", "admissionregistration.k8s.io", "mutatingwebhookconfigurations"), perm("get", "", "services"), perm("get", "", "endpoints"), permListNamespaces},
+	"check_mtu_consistency":        {permListNodes, permListPods},
+	"check_pod_encryption":         {permListNodes, perm("get", "", "configmaps"), perm("list", groupCilium, "ciliumnodes"), perm("list", groupCalico, "felixconfigurations")},
+	"check_openapi_route_coverage": {permListConfigMaps, permListIngresses, permListServices},
+	"check_rate_limit_policies":    {permListServices},
+	"analyze_rate_limits":          {perm("list", groupGateway, "httproutes"), permListPods, permPodLogs},
+	"detect_hostname_conflicts":    {permListIngresses, permListGateways},
+	"suggest_remediation":          {permListServices},
+	"verify_tenant_isolation":      {permListNamespaces, permListPods, permListNetworkPolicies},
+	"verify_traffic_policies":      {perm("get", groupIstioNet, "virtualservices"), perm("get", groupGateway, "httproutes"), perm("get", "", "services")},
+	"capture_traffic":              {perm("get", "", "pods")},
+	"verify_route_programming":     {perm("get", groupGateway, "gateways"), perm("list", groupGateway, "httproutes"), perm("list", groupGateway, "grpcroutes"), perm("get", "", "services"), perm("get", "", "endpoints")},
+	"inspect_connections":          {perm("get", "", "pods"), perm("get", "", "services"), perm("get", "", "endpoints")},
+
+	// Logs
+	"get_proxy_logs":        {perm("get", "", "pods"), permListPods, perm("get", groupApps, "deployments"), permPodLogs},
+	"get_gateway_logs":      {permListPods, permPodLogs},
+	"get_infra_logs":        {permListPods, permPodLogs},
+	"analyze_log_errors":    {permListPods, permPodLogs},
+	"check_proxy_resources": {permListPods, permPodLogs, perm("list", "metrics.k8s.io", "pods")},
+
+	// Gateway API
+	"list_gateways":                 {permListGateways},
+	"get_gateway":                   {perm("get", groupGateway, "gateways"), permListHTTPRoutes},
+	"list_httproutes":               {permListHTTPRoutes},
+	"get_httproute":                 {perm("get", groupGateway, "httproutes"), permListServices, permListEndpoints, permListReferenceGrants},
+	"list_grpcroutes":               {permListGRPCRoutes},
+	"get_grpcroute":                 {perm("get", groupGateway, "grpcroutes"), permListServices, permListEndpoints, permListReferenceGrants},
+	"list_referencegrants":          {permListReferenceGrants},
+	"get_referencegrant":            {perm("get", groupGateway, "referencegrants"), permListHTTPRoutes},
+	"scan_gateway_misconfigs":       {permListGateways, permListHTTPRoutes, permListGRPCRoutes, permListReferenceGrants, permListBackendTLSPolicies, permListServices, permListPods, permListNamespaces, perm("get", groupGateway, "gatewayclasses")},
+	"check_gateway_conformance":     {permListGateways, permListHTTPRoutes, permListGRPCRoutes, perm("get", groupGateway, "gatewayclasses")},
+	"detect_gateway_implementation": {perm("list", groupGateway, "gatewayclasses"), permListGateways},
+	"analyze_route_precedence":      {perm("get", groupGateway, "gateways"), permListHTTPRoutes},
+	"list_tcproutes":                {perm("list", groupGateway, "tcproutes")},
+	"get_tcproute":                  {perm("get", groupGateway, "tcproutes"), perm("get", groupGateway, "gateways"), permListServices, permListEndpoints, permListReferenceGrants},
+	"list_tlsroutes":                {perm("list", groupGateway, "tlsroutes")},
+	"get_tlsroute":                  {perm("get", groupGateway, "tlsroutes"), perm("get", groupGateway, "gateways"), permListServices, permListEndpoints, permListReferenceGrants},
+	"list_udproutes":                {perm("list", groupGateway, "udproutes")},
+	"get_udproute":                  {perm("get", groupGateway, "udproutes"), perm("get", groupGateway, "gateways"), permListServices, permListEndpoints, permListReferenceGrants},
+	"list_backendtlspolicies":       {permListBackendTLSPolicies, permListServices},
+	"design_gateway_api":            {permListServices, permListGateways},
+	"generate_route_telemetry":      {perm("list", groupGateway, "gatewayclasses"), permListGateways, permListHTTPRoutes},
+	"triage_404":                    {permListGateways, permListServices},
+
+	// Istio
+	"list_istio_resources":     {permListVirtualServices, permListDestRules},
+	"get_istio_resource":       {perm("get", groupIstioNet, "virtualservices"), perm("get", groupIstioNet, "destinationrules")},
+	"check_sidecar_injection":  {permListPods, permListDeployments, perm("get", "", "namespaces")},
+	"check_istio_revisions":    {permListDeployments, permListNamespaces, permListPods, perm("list", "admissionregistration.k8s.io", "mutatingwebhookconfigurations")},
+	"check_istiod_health":      {permListDeployments, perm("get", groupApps, "deployments"), permListPods, {Verb: "get", Resource: "pods", Subresource: "proxy"}},
+	"check_istio_mtls":         {permListPeerAuths, permListDestRules},
+	"validate_istio_config":    {permListVirtualServices, permListDestRules, permListServices, permListPods},
+	"analyze_envoyfilters":     {perm("list", groupIstioNet, "envoyfilters"), permListDeployments},
+	"analyze_istio_authpolicy": {permListAuthzPolicies},
+	"analyze_istio_routing":    {permListVirtualServices, permListDestRules, permListServices, permListEndpoints},
+	"check_outlier_detection":  {permListDestRules, permListVirtualServices, permListServices, permListPods},
+	"design_istio":             {permListPeerAuths},
+
+	// Canary rollouts
+	"design_canary": {perm("get", "", "services")},
+	"check_canary":  {perm("get", "", "services")},
+
+	// kgateway
+	"list_kgateway_resources":    {perm("list", groupKgateway, "routeoptions"), perm("list", groupKgateway, "virtualhostoptions"), perm("list", groupKgateway, "backends"), perm("list", groupKgateway, "trafficpolicies"), perm("list", groupKgateway, "httplistenerpolicies")},
+	"validate_kgateway_resource": {permListServices},
+	"check_kgateway_health":      {permListPods, permListDeployments},
+	"diff_kgateway_xds":          {perm("get", groupGateway, "gateways"), perm("list", groupGateway, "httproutes"), permListPods, {Verb: "get", Resource: "pods", Subresource: "proxy"}},
+
+	// Tier 2 providers
+	"list_cilium_policies":           {perm("list", groupCilium, "ciliumnetworkpolicies"), perm("list", groupCilium, "ciliumclusterwidenetworkpolicies")},
+	"get_cilium_policy":              {perm("get", groupCilium, "ciliumnetworkpolicies"), permListServices},
+	"check_cilium_status":            {permListPods, perm("list", groupCilium, "ciliumendpoints")},
+	"validate_cilium_policy":         {perm("list", groupCilium, "ciliumnetworkpolicies"), perm("list", groupCilium, "ciliumclusterwidenetworkpolicies"), perm("list", groupCilium, "ciliumendpoints"), perm("list", groupCilium, "ciliumnodes"), perm("get", "", "configmaps"), permListNodes, permListPods},
+	"check_cilium_clustermesh":       {perm("get", "", "configmaps"), perm("get", groupApps, "deployments"), perm("get", "", "services"), permListPods, {Verb: "get", Resource: "pods", Subresource: "proxy"}, perm("list", groupCilium, "ciliumnodes")},
+	"validate_cilium_egress_gateway": {perm("list", groupCilium, "ciliumegressgatewaypolicies"), perm("get", "", "configmaps"), permListNodes, perm("list", groupCilium, "ciliumnodes"), permListPods, permListNamespaces},
+	"list_calico_policies":           {perm("list", groupCalico, "networkpolicies"), perm("list", groupCalico, "globalnetworkpolicies")},
+	"check_calico_status":            {permListPods},
+	"check_flannel_status":           {permListPods, permListConfigMaps, permListNodes},
+	"check_kuma_status":              {perm("list", "kuma.io", "meshes"), perm("list", "kuma.io", "dataplanes"), permListPods},
+	"check_linkerd_status":           {perm("list", "linkerd.io", "serviceprofiles"), permListPods, permListNamespaces},
+	"check_nodelocal_dns":            {permListDaemonSets, permListPods, permListServices, permListConfigMaps, permListNodes},
+	"get_ingress_nginx_config":       {permListConfigMaps},
+	"check_ingress_nginx":            {permListIngresses, perm("list", groupNetworking, "ingressclasses")},
+	"check_external_dns":             {permListPods, permPodLogs, permListServices, permListIngresses, permListGateways, permListHTTPRoutes, permListGRPCRoutes, perm("list", "externaldns.k8s.io", "dnsendpoints")},
+	"list_gke_gateway_policies":      {perm("list", groupGKE, "gcpbackendpolicies"), perm("list", groupGKE, "healthcheckpolicies")},
+	"check_gke_gateway_status":       {permListGateways, perm("list", groupGKE, "gcpbackendpolicies")},
+	"list_vpc_lattice_policies":      {perm("list", groupVPCLattice, "targetgrouppolicies"), perm("list", groupVPCLattice, "vpcassociationpolicies"), perm("list", groupVPCLattice, "iamauthpolicies"), perm("list", groupVPCLattice, "accesslogpolicies")},
+	"check_vpc_lattice_status":       {perm("list", groupGateway, "gatewayclasses"), permListGateways},
+	"list_appmesh_resources":         {perm("list", groupAppMesh, "meshes"), perm("list", groupAppMesh, "virtualnodes")},
+	"check_appmesh_status":           {perm("list", groupAppMesh, "virtualnodes"), perm("list", groupAppMesh, "virtualservices")},
+	"list_metallb_resources":         {perm("list", groupMetalLB, "ipaddresspools"), perm("list", groupMetalLB, "l2advertisements"), perm("list", groupMetalLB, "bgpadvertisements")},
+	"check_metallb_status":           {perm("list", groupMetalLB, "ipaddresspools"), permListServices, permListDeployments, permListDaemonSets},
+	"list_gitops_resources":          {perm("list", "argoproj.io", "applications")},
+	"check_gitops_sync":              {perm("list", "argoproj.io", "applications")},
+
+	// Discovery
+	"refresh_capabilities": {perm("list", "apiextensions.k8s.io", "customresourcedefinitions"), permListDaemonSets, permListDeployments, perm("list", groupNetworking, "ingressclasses")},
+}
+
+// probeTools deploy probe pods in the probe namespace.
+var probeTools = map[string]bool{
+	"probe_connectivity":       true,
+	"probe_dns":                true,
+	"probe_http":               true,
+	"probe_latency":            true,
+	"verify_traffic_policies":  true,
+	"check_mtu":                true,
+	"capture_traffic":          true,
+	"inspect_connections":      true,
+	"verify_route_programming": true,
+	"check_probe_hygiene":      true,
+	"check_admission_webhooks": true,
+	"check_flannel_status":     true,
+	"verify_tenant_isolation":  true,
+}
+
+// probePermissions is what the probe manager needs in namespace.
+func probePermissions(namespace string) []Permission {
+	return []Permission{
+		{Verb: "create", Resource: "pods", Namespace: namespace},
+		{Verb: "watch", Resource: "pods", Namespace: namespace},
+		{Verb: "delete", Resource: "pods", Namespace: namespace},
+		{Verb: "get", Resource: "pods", Subresource: "log", Namespace: namespace},
+	}
+}
+
+// RequiredPermissions returns the API access t needs; probe tools also need
+// to manage pods in probeNamespace.
+func RequiredPermissions(t Tool, probeNamespace string) []Permission {
+	if pt, ok := t.(PermissionedTool); ok {
+		return pt.RequiredPermissions()
+	}
+	perms := toolPermissions[t.Name()]
+	if probeTools[t.Name()] {
+		perms = append(append([]Permission(nil), perms...), probePermissions(probeNamespace)...)
+	}
+	return perms
+}
+
+// ToolPermissions is the outcome of the permission check of one tool.
+type ToolPermissions struct {
+	Tool     string       `json:"tool"`
+	Required []Permission `json:"required"`
+	Missing  []Permission `json:"missing,omitempty"`
+	// Unchecked are permissions whose review failed, e.g. on a timeout.
+	Unchecked []Permission `json:"unchecked,omitempty"`
+}
+
+// Degraded reports whether the tool lacks permissions.
+func (tp ToolPermissions) Degraded() bool { return len(tp.Missing) > 0 }
+
+// CheckPermissions runs one SelfSubjectAccessReview per distinct permission
+// the tools need, as the identity of ctx (see k8s.WithImpersonation), and
+// returns the result per tool sorted by name. Tools needing no API access
+// are left out.
+func CheckPermissions(ctx context.Context, clientset kubernetes.Interface, tools []Tool, probeNamespace string) []ToolPermissions {
+	type review struct {
+		allowed bool
+		err     error
+	}
+	reviews := make(map[Permission]review)
+	var out []ToolPermissions
+	for _, t := range tools {
+		required := RequiredPermissions(t, probeNamespace)
+		if len(required) == 0 {
+			continue
+		}
+		tp := ToolPermissions{Tool: t.Name(), Required: required}
+		for _, p := range required {
+			r, ok := reviews[p]
+			if !ok {
+				r.allowed, r.err = accessAllowed(ctx, clientset, p)
+				reviews[p] = r
+			}
+			switch {
+			case r.err != nil:
+				tp.Unchecked = append(tp.Unchecked, p)
+			case !r.allowed:
+				tp.Missing = append(tp.Missing, p)
+			}
+		}
+		out = append(out, tp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tool < out[j].Tool })
+	return out
+}
+
+func accessAllowed(ctx context.Context, clientset kubernetes.Interface, p Permission) (bool, error) {
+	ssar := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   p.Namespace,
+				Verb:        p.Verb,
+				Group:       p.Group,
+				Resource:    p.Resource,
+				Subresource: p.Subresource,
+			},
+		},
+	}
+	res, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return res.Status.Allowed, nil
+}
+
+// MissingRules renders the rules granting the missing permissions of the
+// tools, one per distinct permission.
+func MissingRules(results []ToolPermissions) string {
+	seen := make(map[Permission]bool)
+	var rules []string
+	for _, tp := range results {
+		for _, p := range tp.Missing {
+			if !seen[p] {
+				seen[p] = true
+				rules = append(rules, p.Rule())
+			}
+		}
+	}
+	sort.Strings(rules)
+	return strings.Join(rules, "\n")
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// rbacClientset answers SelfSubjectAccessReviews, denying the listed
+// "verb resource" pairs.
+func rbacClientset(denied ...string) (*fake.Clientset, *int) {
+	clientset := fake.NewSimpleClientset()
+	reviews := 0
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		ssar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := ssar.Spec.ResourceAttributes
+		resource := attrs.Resource
+		if attrs.Subresource != "" {
+			resource += "/" + attrs.Subresource
+		}
+		allowed := true
+		for _, d := range denied {
+			if d == attrs.Verb+" "+resource {
+				allowed = false
+			}
+		}
+		ssar.Status.Allowed = allowed
+		return true, ssar, nil
+	})
+	return clientset, &reviews
+}
+
+func TestCheckPermissions(t *testing.T) {
+	clientset, reviews := rbacClientset("list endpoints", "create pods")
+	toolList := []Tool{
+		&ListServicesTool{},
+		&ListEndpointsTool{},
+		&ProbeDNSTool{},
+		&ListFindingCodesTool{},
+	}
+	results := CheckPermissions(context.Background(), clientset, toolList, "probes")
+
+	byTool := make(map[string]ToolPermissions)
+	for _, tp := range results {
+		byTool[tp.Tool] = tp
+	}
+	if _, ok := byTool["list_finding_codes"]; ok {
+		t.Error("list_finding_codes needs no API access and should be left out")
+	}
+	if !byTool["list_services"].Degraded() || !byTool["list_endpoints"].Degraded() {
+		t.Errorf("expected list_services and list_endpoints degraded: %+v", results)
+	}
+	probe := byTool["probe_dns"]
+	if len(probe.Missing) != 1 || probe.Missing[0] != (Permission{Verb: "create", Resource: "pods", Namespace: "probes"}) {
+		t.Errorf("unexpected probe_dns missing %+v", probe.Missing)
+	}
+
+	// Permissions shared by tools are reviewed once.
+	distinct := make(map[Permission]bool)
+	for _, tp := range results {
+		for _, p := range tp.Required {
+			distinct[p] = true
+		}
+	}
+	if *reviews != len(distinct) {
+		t.Errorf("expected %d reviews, got %d", len(distinct), *reviews)
+	}
+
+	rules := MissingRules(results)
+	if strings.Count(rules, "resources: [endpoints]") != 1 || !strings.Contains(rules, "resources: [pods]") {
+		t.Errorf("unexpected rules:\n%s", rules)
+	}
+}
+
+func TestCheckPermissionsTool(t *testing.T) {
+	clientset, _ := rbacClientset("list endpoints")
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test", ProbeNamespace: "probes"}, Clients: &k8s.Clients{Clientset: clientset}}
+	registry := NewRegistry()
+	registry.Register(&ListServicesTool{BaseTool: base})
+	registry.Register(&GetServiceTool{BaseTool: base})
+	tool := &CheckPermissionsTool{BaseTool: base, Registry: registry}
+	registry.Register(tool)
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if findings[0].Severity != types.SeverityWarning || !strings.Contains(findings[0].Summary, "0 of 2") {
+		t.Errorf("unexpected summary %+v", findings[0])
+	}
+	missing := 0
+	for _, f := range findings {
+		if f.Code == types.CodeRBACPermissionMissing {
+			missing++
+		}
+	}
+	if missing != 2 {
+		t.Errorf("expected 2 degraded tools, got %+v", findings)
+	}
+	if got := registry.Degraded("get_service"); len(got) != 1 || got[0].Resource != "endpoints" {
+		t.Errorf("expected get_service marked degraded, got %+v", got)
+	}
+
+	// Checks as an impersonated user leave the server's record alone.
+	registry.SetDegraded(nil)
+	ctx := k8s.WithImpersonation(context.Background(), k8s.Impersonation{User: "alice"})
+	if _, err := tool.Run(ctx, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := registry.Degraded("get_service"); got != nil {
+		t.Errorf("impersonated check marked tools degraded: %+v", got)
+	}
+
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"tool": "nope"}); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	isolation := RequiredPermissions(&VerifyTenantIsolationTool{}, "probes")
+	if !slices.Contains(isolation, Permission{Verb: "create", Resource: "pods", Namespace: "probes"}) {
+		t.Errorf("verify_tenant_isolation deploys probe pods, got %+v", isolation)
+	}
+	lattice := RequiredPermissions(&ListVPCLatticePoliciesTool{}, "probes")
+	if len(lattice) != len(latticePolicyKinds) {
+		t.Errorf("list_vpc_lattice_policies should list every policy kind, got %+v", lattice)
+	}
+}
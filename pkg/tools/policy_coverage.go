@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// percent formats n of total as a percentage; an empty total is fully covered.
+func percent(n, total int) string {
+	if total == 0 {
+		return "100%"
+	}
+	return fmt.Sprintf("%d%%", n*100/total)
+}
+
+// isDefaultDeny reports whether a NetworkPolicy selects every pod of its
+// namespace and allows nothing for direction.
+func isDefaultDeny(np unstructured.Unstructured, direction string) bool {
+	if !policyHasType(np, direction) {
+		return false
+	}
+	if sel, _, _ := unstructured.NestedMap(np.Object, "spec", "podSelector"); len(sel) > 0 {
+		return false
+	}
+	rules, _, _ := unstructured.NestedSlice(np.Object, "spec", strings.ToLower(direction))
+	return len(rules) == 0
+}
+
+// podPolicies returns the NetworkPolicies of the pod's namespace that select
+// it for direction.
+func podPolicies(policies []unstructured.Unstructured, pod podPorts, direction string) ([]unstructured.Unstructured, error) {
+	var out []unstructured.Unstructured
+	for _, np := range policies {
+		if np.GetNamespace() != pod.Namespace || !policyHasType(np, direction) {
+			continue
+		}
+		podSelObj, _, _ := unstructured.NestedMap(np.Object, "spec", "podSelector")
+		sel, err := parseLabelSelector(podSelObj, true)
+		if err != nil {
+			return nil, fmt.Errorf("NetworkPolicy %s/%s: %w", np.GetNamespace(), np.GetName(), err)
+		}
+		if sel.Matches(labels.Set(pod.Labels)) {
+			out = append(out, np)
+		}
+	}
+	return out, nil
+}
+
+// admittingRule returns the first ingress rule of policies whose ports
+// include port on pod, from any source, or "" when none does.
+func admittingRule(policies []unstructured.Unstructured, port containerPort, pod podPorts) string {
+	for _, np := range policies {
+		rules, _, _ := unstructured.NestedSlice(np.Object, "spec", "ingress")
+		for i, r := range rules {
+			if rm, ok := r.(map[string]interface{}); ok && rulePortsMatch(rm, port, pod) {
+				return fmt.Sprintf("%s ingress[%d]", np.GetName(), i)
+			}
+		}
+	}
+	return ""
+}
+
+// targetContainerPort resolves the target port of a Service port on pod. ok
+// is false for a named target port the pod does not declare.
+func targetContainerPort(sp serviceTargetPort, pod podPorts) (containerPort, bool) {
+	port, _ := resolvePort(sp.targetRef(), sp.Protocol, pod)
+	return port, port.Port != 0
+}
+
+func namesOf(policies []unstructured.Unstructured) string {
+	names := make([]string, 0, len(policies))
+	for _, np := range policies {
+		names = append(names, np.GetName())
+	}
+	return truncateList(names, 5)
+}
+
+// --- analyze_policy_coverage ---
+
+type AnalyzePolicyCoverageTool struct{ BaseTool }
+
+func (t *AnalyzePolicyCoverageTool) Name() string { return "analyze_policy_coverage" }
+func (t *AnalyzePolicyCoverageTool) Description() string {
+	return "Report the NetworkPolicy coverage of a namespace: pods selected by no NetworkPolicy (wide open), Service ports no ingress rule admits, and whether default-deny policies exist, as coverage percentages plus per-pod findings"
+}
+func (t *AnalyzePolicyCoverageTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to analyze",
+			},
+		},
+		"required": []string{"namespace"},
+	}
+}
+
+func (t *AnalyzePolicyCoverageTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	if ns == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "namespace is required"}
+	}
+
+	policyList, err := t.listResource(ctx, networkPoliciesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+	podList, err := t.listResource(ctx, podsGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	svcList, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	policies := policyList.Items
+
+	var findings []types.DiagnosticFinding
+	var pods []podPorts
+	ingressPolicies := make(map[string][]unstructured.Unstructured)
+	ingressCovered, egressCovered := 0, 0
+	for i := range podList.Items {
+		phase, _, _ := unstructured.NestedString(podList.Items[i].Object, "status", "phase")
+		if phase == "Succeeded" || phase == "Failed" {
+			continue
+		}
+		pod := podPortsFrom(&podList.Items[i])
+		pods = append(pods, pod)
+		ingress, err := podPolicies(policies, pod, "Ingress")
+		var egress []unstructured.Unstructured
+		if err == nil {
+			egress, err = podPolicies(policies, pod, "Egress")
+		}
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityWarning,
+				Category: types.CategoryPolicy,
+				Code:     types.CodeNetworkPolicyInvalidSelector,
+				Resource: &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: pod.Name},
+				Summary:  fmt.Sprintf("Cannot evaluate the NetworkPolicies of pod %s/%s", ns, pod.Name),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		ingressPolicies[pod.Name] = ingress
+		if len(ingress) > 0 {
+			ingressCovered++
+		}
+		if len(egress) > 0 {
+			egressCovered++
+		}
+		if len(ingress) == 0 && len(egress) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeNetworkPolicyPodNotSelected,
+				Resource:   &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: pod.Name},
+				Summary:    fmt.Sprintf("Pod %s/%s is selected by no NetworkPolicy: all ingress and egress is allowed", ns, pod.Name),
+				Detail:     "labels: " + formatSelector(pod.Labels),
+				Suggestion: "Add a default-deny NetworkPolicy to the namespace and allow the traffic the pod needs explicitly.",
+			})
+		}
+	}
+
+	// A Service port is admitted when, on every backing pod the policies
+	// isolate, an ingress rule includes its target port.
+	portsTotal, portsAdmitted := 0, 0
+	for _, svc := range svcList.Items {
+		selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if len(selector) == 0 {
+			continue
+		}
+		backends := selectPods(pods, labels.SelectorFromSet(selector), func(string) bool { return true })
+		for _, sp := range serviceTargetPorts(&svc) {
+			portsTotal++
+			var blocked []string
+			var blockingPolicies []unstructured.Unstructured
+			for _, pod := range backends {
+				isolating := ingressPolicies[pod.Name]
+				if len(isolating) == 0 {
+					continue
+				}
+				port, ok := targetContainerPort(sp, pod)
+				if !ok {
+					// Unresolvable named target ports are reported by get_service.
+					continue
+				}
+				if admittingRule(isolating, port, pod) == "" {
+					blocked = append(blocked, pod.Name)
+					blockingPolicies = isolating
+				}
+			}
+			if len(blocked) == 0 {
+				portsAdmitted++
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeNetworkPolicyServicePortNotAdmitted,
+				Resource:   &types.ResourceRef{Kind: "Service", Namespace: ns, Name: svc.GetName()},
+				Summary:    fmt.Sprintf("Service %s/%s port %d (target %s/%s) is admitted by no ingress rule on %d of %d pods", ns, svc.GetName(), sp.Port, sp.target(), sp.Protocol, len(blocked), len(backends)),
+				Detail:     fmt.Sprintf("pods: %s; isolated by: %s", truncateList(blocked, 5), namesOf(blockingPolicies)),
+				Suggestion: "Add the target port to an ingress rule of a policy selecting these pods, or remove the Service port if it is unused.",
+			})
+		}
+	}
+
+	var denyIngress, denyEgress []string
+	for _, np := range policies {
+		if isDefaultDeny(np, "Ingress") {
+			denyIngress = append(denyIngress, np.GetName())
+		}
+		if isDefaultDeny(np, "Egress") {
+			denyEgress = append(denyEgress, np.GetName())
+		}
+	}
+	describe := func(names []string) string {
+		if len(names) == 0 {
+			return "no"
+		}
+		return "yes (" + strings.Join(names, ", ") + ")"
+	}
+	for _, missing := range []struct {
+		direction, policyType string
+		names                 []string
+	}{{"ingress", "Ingress", denyIngress}, {"egress", "Egress", denyEgress}} {
+		if len(missing.names) > 0 {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeNetworkPolicyNoDefaultDeny,
+			Resource:   &types.ResourceRef{Kind: "Namespace", Name: ns},
+			Summary:    fmt.Sprintf("Namespace %s has no default-deny %s NetworkPolicy: new pods are open for %s until a policy selects them", ns, missing.direction, missing.direction),
+			Suggestion: fmt.Sprintf("Add a NetworkPolicy with podSelector: {} and policyTypes: [%s] and no %s rules.", missing.policyType, missing.direction),
+		})
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryPolicy,
+		Resource: &types.ResourceRef{Kind: "Namespace", Name: ns},
+		Summary: fmt.Sprintf("Namespace %s: ingress coverage %s (%d/%d pods), egress coverage %s (%d/%d pods), Service ports admitted %s (%d/%d)",
+			ns, percent(ingressCovered, len(pods)), ingressCovered, len(pods), percent(egressCovered, len(pods)), egressCovered, len(pods),
+			percent(portsAdmitted, portsTotal), portsAdmitted, portsTotal),
+		Detail: fmt.Sprintf("%d NetworkPolicies; default-deny ingress: %s; default-deny egress: %s", len(policies), describe(denyIngress), describe(denyEgress)),
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
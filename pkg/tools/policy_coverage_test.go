@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func TestAnalyzePolicyCoverage(t *testing.T) {
+	objs := []runtime.Object{
+		tenantPod("shop", "web-1", "10.0.1.10", map[string]string{"app": "web"}, 8080),
+		tenantPod("shop", "api-1", "10.0.1.11", map[string]string{"app": "api"}, 9090),
+		tenantPod("shop", "batch-1", "10.0.1.12", map[string]string{"app": "batch"}, 8000),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "deny-egress", map[string]interface{}{
+			"spec": map[string]interface{}{"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}}, "policyTypes": []interface{}{"Egress"}},
+		}),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "web", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"ingress":     []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": "http"}}}},
+			},
+		}),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "api", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "api"}},
+				"ingress":     []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(8080)}}}},
+			},
+		}),
+		ipamObj("v1", "Service", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"app": "web"},
+			"ports":    []interface{}{map[string]interface{}{"port": int64(80), "targetPort": "http"}},
+		}}),
+		ipamObj("v1", "Service", "shop", "api", map[string]interface{}{"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"app": "api"},
+			"ports":    []interface{}{map[string]interface{}{"port": int64(80), "targetPort": int64(9090)}},
+		}}),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList", networkPoliciesGVR: "NetworkPolicyList", servicesGVR: "ServiceList",
+	}, objs...)
+	tool := &AnalyzePolicyCoverageTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"ok Namespace shop: ingress coverage 66% (2/3 pods), egress coverage 33% (1/3 pods), Service ports admitted 50% (1/2)",
+		"warning Pod shop/batch-1 is selected by no NetworkPolicy: all ingress and egress is allowed NP006_POD_NOT_SELECTED",
+		"warning Service shop/api port 80 (target 9090/TCP) is admitted by no ingress rule on 1 of 1 pods NP007_SERVICE_PORT_NOT_ADMITTED",
+		"warning Namespace shop has no default-deny ingress NetworkPolicy",
+		"warning Namespace shop has no default-deny egress NetworkPolicy",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "Pod shop/web-1") || strings.Contains(all, "Service shop/web ") {
+		t.Errorf("unexpected finding in:\n%s", all)
+	}
+}
+
+func TestIsDefaultDeny(t *testing.T) {
+	deny := *testPolicy("shop", map[string]interface{}{"podSelector": map[string]interface{}{}, "policyTypes": []interface{}{"Ingress", "Egress"}})
+	if !isDefaultDeny(deny, "Ingress") || !isDefaultDeny(deny, "Egress") {
+		t.Error("expected an empty policy with both types to deny both directions")
+	}
+	allowDNS := *testPolicy("shop", map[string]interface{}{
+		"podSelector": map[string]interface{}{},
+		"policyTypes": []interface{}{"Egress"},
+		"egress":      []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(53), "protocol": "UDP"}}}},
+	})
+	if isDefaultDeny(allowDNS, "Egress") || isDefaultDeny(allowDNS, "Ingress") {
+		t.Error("expected a policy with egress rules and no Ingress type not to be a default deny")
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import "strconv"
+
+// isPortName reports whether a port reference is an IANA_SVC_NAME rather than
+// a number. NetworkPolicy ports and Service targetPorts are IntOrString, and
+// a numeric string such as "8080" is a number.
+func isPortName(ref interface{}) bool {
+	s, ok := ref.(string)
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(s)
+	return err != nil
+}
+
+// resolvePort resolves a port reference on pod. A name resolves to the
+// container port the pod declares with that name and protocol; Port stays 0
+// when it declares none. A number resolves to itself, with Name filled in
+// when the pod declares it. declared reports whether the pod declares the
+// port with protocol.
+func resolvePort(ref interface{}, protocol string, pod podPorts) (port containerPort, declared bool) {
+	if isPortName(ref) {
+		name := ref.(string)
+		for _, cp := range pod.Ports {
+			if cp.Name == name && cp.Protocol == protocol {
+				return cp, true
+			}
+		}
+		return containerPort{Name: name, Protocol: protocol}, false
+	}
+	port = containerPort{Port: int64(toInt(ref)), Protocol: protocol}
+	for _, cp := range pod.Ports {
+		if cp.Port == port.Port && cp.Protocol == protocol {
+			port.Name = cp.Name
+			return port, true
+		}
+	}
+	return port, false
+}
+
+// declaredProtocols returns the protocols pod declares a port reference
+// with, to tell a protocol mismatch from a missing port.
+func declaredProtocols(ref interface{}, pod podPorts) []string {
+	var out []string
+	for _, cp := range pod.Ports {
+		if isPortName(ref) && cp.Name != ref.(string) {
+			continue
+		}
+		if !isPortName(ref) && cp.Port != int64(toInt(ref)) {
+			continue
+		}
+		if !containsString(out, cp.Protocol) {
+			out = append(out, cp.Protocol)
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolvePort(t *testing.T) {
+	pod := podPorts{Ports: []containerPort{
+		{Name: "http", Port: 8080, Protocol: "TCP"},
+		{Name: "dns", Port: 53, Protocol: "UDP"},
+	}}
+	for _, tc := range []struct {
+		ref      interface{}
+		protocol string
+		want     containerPort
+		declared bool
+	}{
+		{"http", "TCP", containerPort{Name: "http", Port: 8080, Protocol: "TCP"}, true},
+		{"dns", "TCP", containerPort{Name: "dns", Protocol: "TCP"}, false},
+		{int64(8080), "TCP", containerPort{Name: "http", Port: 8080, Protocol: "TCP"}, true},
+		{"8080", "TCP", containerPort{Name: "http", Port: 8080, Protocol: "TCP"}, true},
+		{float64(9090), "TCP", containerPort{Port: 9090, Protocol: "TCP"}, false},
+	} {
+		got, declared := resolvePort(tc.ref, tc.protocol, pod)
+		if got != tc.want || declared != tc.declared {
+			t.Errorf("resolvePort(%v, %s) = %+v, %v; want %+v, %v", tc.ref, tc.protocol, got, declared, tc.want, tc.declared)
+		}
+	}
+	if got := declaredProtocols("dns", pod); !reflect.DeepEqual(got, []string{"UDP"}) {
+		t.Errorf("declaredProtocols(dns) = %v", got)
+	}
+}
+
+func TestServiceTargetPortsNumericString(t *testing.T) {
+	svc := managedObj("v1", "Service", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{
+		"ports": []interface{}{map[string]interface{}{"port": int64(80), "targetPort": "8080"}},
+	}})
+	ports := serviceTargetPorts(svc)
+	if len(ports) != 1 || ports[0].TargetName != "" || ports[0].TargetPort != 8080 {
+		t.Errorf("serviceTargetPorts() = %+v, want numeric target 8080", ports)
+	}
+}
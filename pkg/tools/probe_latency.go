@@ -0,0 +1,397 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// latencyProbeBudget bounds the request loop so the probe pod finishes well
+// within the 5-minute TTL after which the reconciler deletes it.
+const latencyProbeBudget = 210 * time.Second
+
+// latencyProbeMaxTimeout is the longest per-request timeout, in seconds, of
+// a latency probe; it is also the API server's limit on webhook timeouts.
+const latencyProbeMaxTimeout = 30
+
+// latencyProbePodTimeout is how long a latency probe pod whose requests time
+// out after timeoutSec seconds may run.
+func latencyProbePodTimeout(timeoutSec int) time.Duration {
+	return latencyProbeBudget + time.Duration(timeoutSec)*time.Second + 30*time.Second
+}
+
+// curlExitReasons names the curl exit codes a failed request usually has.
+var curlExitReasons = map[int]string{
+	6:  "DNS resolution failed",
+	7:  "connection refused",
+	28: "timed out",
+	35: "TLS handshake failed",
+	52: "empty reply",
+	56: "connection reset",
+	60: "certificate not trusted",
+}
+
+// latencySample is one request of a latency probe, with curl's cumulative
+// timings in seconds.
+type latencySample struct {
+	status        int
+	exitCode      int
+	nameLookup    float64
+	connect       float64
+	appConnect    float64
+	preTransfer   float64
+	startTransfer float64
+	total         float64
+}
+
+func (s latencySample) failed() bool { return s.exitCode != 0 || s.status == 0 }
+
+// latencyPhases are the phases a request's time is split into, in order.
+var latencyPhases = []string{"dns", "connect", "tls", "server", "ttfb", "total"}
+
+// phase returns the duration of one phase in milliseconds. ttfb and total
+// are cumulative; the others are the time spent in that phase alone.
+func (s latencySample) phase(name string) float64 {
+	var sec float64
+	switch name {
+	case "dns":
+		sec = s.nameLookup
+	case "connect":
+		sec = s.connect - s.nameLookup
+	case "tls":
+		if s.appConnect > 0 {
+			sec = s.appConnect - s.connect
+		}
+	case "server":
+		sec = s.startTransfer - s.preTransfer
+	case "ttfb":
+		sec = s.startTransfer
+	case "total":
+		sec = s.total
+	}
+	return math.Max(sec, 0) * 1000
+}
+
+// parseLatencySamples reads the LAT lines the probe prints, one per request:
+// LAT|status|namelookup|connect|appconnect|pretransfer|starttransfer|total|exit.
+func parseLatencySamples(output string) []latencySample {
+	var samples []latencySample
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) != 9 || fields[0] != "LAT" {
+			continue
+		}
+		var s latencySample
+		s.status, _ = strconv.Atoi(fields[1])
+		times := []*float64{&s.nameLookup, &s.connect, &s.appConnect, &s.preTransfer, &s.startTransfer, &s.total}
+		for i, dst := range times {
+			*dst, _ = strconv.ParseFloat(fields[i+2], 64)
+		}
+		s.exitCode, _ = strconv.Atoi(fields[8])
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// percentile returns the nearest-rank percentile p of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// latencyStats holds the p50, p95 and p99 of one phase in milliseconds.
+type latencyStats struct{ p50, p95, p99 float64 }
+
+func phaseStats(samples []latencySample, name string) latencyStats {
+	values := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		values = append(values, s.phase(name))
+	}
+	sort.Float64s(values)
+	return latencyStats{percentile(values, 50), percentile(values, 95), percentile(values, 99)}
+}
+
+// latencyBottlenecks explains what a dominant phase points to.
+var latencyBottlenecks = map[string]struct{ what, suggestion string }{
+	"dns": {"DNS resolution",
+		"Use the fully qualified name with a trailing dot to skip search domains (ndots:5), check CoreDNS load and latency, or enable NodeLocal DNSCache."},
+	"connect": {"TCP connect, i.e. the network path",
+		"The backend answers quickly once connected: check the CNI and node network (check_mtu_consistency, conntrack exhaustion), cross-zone routing and sidecar or gateway hops between client and backend."},
+	"tls": {"the TLS handshake",
+		"Check the certificate chain size and OCSP settings, and whether connections are reused; a mesh adds an mTLS handshake per new connection."},
+	"server": {"the backend processing the request",
+		"Routing is fine; the backend itself is slow. Check its CPU throttling, dependencies and application metrics or traces (query_service_traffic, find_failing_traces)."},
+}
+
+// evaluateLatency builds the findings of a latency probe of target.
+func evaluateLatency(target string, requested int, samples []latencySample, thresholdMs int) []types.DiagnosticFinding {
+	var ok []latencySample
+	failures := make(map[string]int)
+	errorStatuses := 0
+	for _, s := range samples {
+		if s.failed() {
+			reason := curlExitReasons[s.exitCode]
+			if reason == "" {
+				reason = fmt.Sprintf("curl exit code %d", s.exitCode)
+			}
+			failures[reason]++
+			continue
+		}
+		if s.status >= 500 {
+			errorStatuses++
+		}
+		ok = append(ok, s)
+	}
+
+	var findings []types.DiagnosticFinding
+	if len(ok) == 0 {
+		return append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeProbeHTTPFailed,
+			Summary:    fmt.Sprintf("All %d requests to %s failed", len(samples), target),
+			Detail:     formatCounts(failures),
+			Suggestion: "Run probe_http or probe_connectivity to diagnose the failure; latency cannot be measured without successful requests.",
+		})
+	}
+
+	stats := make(map[string]latencyStats, len(latencyPhases))
+	var lines []string
+	for _, name := range latencyPhases {
+		st := phaseStats(ok, name)
+		stats[name] = st
+		lines = append(lines, fmt.Sprintf("%-7s p50=%.1fms p95=%.1fms p99=%.1fms", name, st.p50, st.p95, st.p99))
+	}
+	detail := strings.Join(lines, "\n")
+	if len(samples) < requested {
+		detail += fmt.Sprintf("\nonly %d of %d requests ran within the %s probe budget", len(samples), requested, latencyProbeBudget)
+	}
+
+	// The phase with the largest p95 is where slow requests spend their time.
+	dominant := ""
+	for _, name := range []string{"dns", "connect", "tls", "server"} {
+		if dominant == "" || stats[name].p95 > stats[dominant].p95 {
+			dominant = name
+		}
+	}
+	total := stats["total"]
+	summary := fmt.Sprintf("%s: %d/%d requests succeeded, p50=%.0fms p95=%.0fms p99=%.0fms", target, len(ok), len(samples), total.p50, total.p95, total.p99)
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Summary:  summary,
+		Detail:   detail,
+	}
+	if total.p95 > float64(thresholdMs) {
+		b := latencyBottlenecks[dominant]
+		f.Severity = types.SeverityWarning
+		f.Code = types.CodeProbeLatencyHigh
+		f.Summary = fmt.Sprintf("%s; p95 exceeds %dms, mostly spent in %s (p95 %.0fms)", summary, thresholdMs, b.what, stats[dominant].p95)
+		f.Suggestion = b.suggestion
+	}
+	findings = append(findings, f)
+
+	if len(failures) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeProbeRequestsFailed,
+			Summary:    fmt.Sprintf("%d of %d requests to %s failed", len(samples)-len(ok), len(samples), target),
+			Detail:     formatCounts(failures),
+			Suggestion: "Intermittent failures usually come from a backend pod that is not ready but still receives traffic, connection resets from a proxy, or conntrack exhaustion.",
+		})
+	}
+	if errorStatuses > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeProbeHTTPErrorStatus,
+			Summary:  fmt.Sprintf("%d of %d responses from %s had a 5xx status", errorStatuses, len(ok), target),
+		})
+	}
+	return findings
+}
+
+// formatCounts renders counts as "reason: n" pairs in a stable order.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// latencyProbeScript builds the shell loop of a latency probe: count curl
+// requests printing one LAT line each (see parseLatencySamples). headers are
+// 'Key: Value' pairs separated by semicolons, which callers check with
+// checkProbeHeaders; any left with shell metacharacters are dropped.
+// insecure skips certificate verification, for targets whose CA the probe
+// image does not trust. The loop stops at the budget deadline so a slow
+// target still returns the samples collected so far instead of timing out
+// the whole probe.
+func latencyProbeScript(method, targetURL, headers string, insecure bool, count, intervalMs, timeoutSec int) string {
+	curlCmd := fmt.Sprintf("curl -s -o /dev/null -w 'LAT|%%{http_code}|%%{time_namelookup}|%%{time_connect}|%%{time_appconnect}|%%{time_pretransfer}|%%{time_starttransfer}|%%{time_total}' -X %s --max-time %d", method, timeoutSec)
+	if insecure {
+		curlCmd += " -k"
+	}
+	for _, h := range strings.Split(headers, ";") {
+		h = strings.TrimSpace(h)
+		if h != "" && !containsShellMeta(h) {
+			curlCmd += fmt.Sprintf(" -H '%s'", h)
+		}
+	}
+	curlCmd += " " + targetURL
+
+	budget := int(latencyProbeBudget.Seconds())
+	return fmt.Sprintf(
+		"end=$(($(date +%%s)+%d)); i=0; while [ $i -lt %d ] && [ $(date +%%s) -lt $end ]; do %s 2>/dev/null; echo \"|$?\"; i=$((i+1)); [ $i -lt %d ] && sleep %.3f; done; true",
+		budget, count, curlCmd, count, float64(intervalMs)/1000)
+}
+
+// --- probe_latency ---
+
+type ProbeLatencyTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *ProbeLatencyTool) Name() string { return "probe_latency" }
+func (t *ProbeLatencyTool) Description() string {
+	return "Deploy an ephemeral pod that sends a series of HTTP/HTTPS requests and reports p50/p95/p99 latency broken down into DNS, TCP connect, TLS handshake, server time and time to first byte, to tell network or routing problems apart from slow backends"
+}
+func (t *ProbeLatencyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "Target URL (e.g., http://my-service.default.svc.cluster.local/health)",
+			},
+			"method": map[string]interface{}{
+				"type":        "string",
+				"description": "HTTP method (GET, POST, HEAD). Default: GET",
+			},
+			"headers": map[string]interface{}{
+				"type":        "string",
+				"description": "Additional headers as 'Key: Value' pairs separated by semicolons",
+			},
+			"count": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of requests (default: 20, max: 200)",
+			},
+			"interval_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "Pause between requests in milliseconds (default: 100, max: 10000)",
+			},
+			"slow_threshold_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "p95 latency above which a warning is reported (default: 500)",
+			},
+			"source_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to deploy the probe pod in",
+			},
+			"timeout_seconds": map[string]interface{}{
+				"type":        "integer",
+				"description": "Timeout of each request in seconds (default: 5, max: 30)",
+			},
+		},
+		"required": []string{"url"},
+	}
+}
+
+func (t *ProbeLatencyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	targetURL := getStringArg(args, "url", "")
+	method := strings.ToUpper(getStringArg(args, "method", "GET"))
+	headers := getStringArg(args, "headers", "")
+	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
+	count := getIntArg(args, "count", 20)
+	intervalMs := getIntArg(args, "interval_ms", 100)
+	thresholdMs := getIntArg(args, "slow_threshold_ms", 500)
+	timeoutSec := getIntArg(args, "timeout_seconds", 5)
+
+	if targetURL == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "url is required",
+		}
+	}
+	if containsShellMeta(targetURL) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "url contains invalid shell characters",
+		}
+	}
+	if err := checkProbeHeaders(t.Name(), headers); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > 200 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("count %d out of range (1-200)", count),
+		}
+	}
+	if !probeAllowedMethods[method] {
+		method = "GET"
+	}
+	intervalMs = min(max(intervalMs, 0), 10000)
+	timeoutSec = min(max(timeoutSec, 1), latencyProbeMaxTimeout)
+
+	req := probes.ProbeRequest{
+		Type:      probes.ProbeTypeLatency,
+		Namespace: sourceNS,
+		Command:   []string{"sh", "-c", latencyProbeScript(method, targetURL, headers, false, count, intervalMs, timeoutSec)},
+		Timeout:   latencyProbePodTimeout(timeoutSec),
+	}
+
+	result, err := t.ProbeManager.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 4)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+
+	samples := parseLatencySamples(result.Output)
+	if len(samples) == 0 {
+		detail := strings.TrimSpace(result.Output)
+		if result.Error != "" {
+			detail = result.Error + "; " + detail
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeProbeHTTPFailed,
+			Summary:    fmt.Sprintf("Latency probe of %s produced no measurements", targetURL),
+			Detail:     detail,
+			Suggestion: "Check that the probe image provides curl and that the probe pod can start in the source namespace.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, sourceNS, ""), nil
+	}
+
+	findings = append(findings, evaluateLatency(fmt.Sprintf("%s %s", method, targetURL), count, samples, thresholdMs)...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, sourceNS, ""), nil
+}
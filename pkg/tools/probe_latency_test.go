@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseLatencySamples(t *testing.T) {
+	output := "LAT|200|0.001|0.002|0.010|0.011|0.050|0.051|0\n" +
+		"noise\n" +
+		"LAT|000|0.000|0.000|0.000|0.000|0.000|5.001|28\n"
+	samples := parseLatencySamples(output)
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	s := samples[0]
+	for name, want := range map[string]float64{"dns": 1, "connect": 1, "tls": 8, "server": 39, "ttfb": 50, "total": 51} {
+		if got := s.phase(name); got < want-0.01 || got > want+0.01 {
+			t.Errorf("%s = %.2fms, want %.0fms", name, got, want)
+		}
+	}
+	if !samples[1].failed() || samples[1].exitCode != 28 {
+		t.Errorf("expected the second sample to be a timeout, got %+v", samples[1])
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	for p, want := range map[float64]float64{50: 50, 95: 95, 99: 99, 100: 100} {
+		if got := percentile(values, p); got != want {
+			t.Errorf("p%.0f = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile([]float64{7}, 99); got != 7 {
+		t.Errorf("single value p99 = %v", got)
+	}
+}
+
+// latencyOutput returns n successful samples whose server time is server
+// seconds, plus failed ones with the given curl exit codes.
+func latencyOutput(n int, dns, server float64, failedExits ...int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		connect := dns + 0.001
+		fmt.Fprintf(&b, "LAT|200|%.3f|%.3f|0|%.3f|%.3f|%.3f|0\n", dns, connect, connect, connect+server, connect+server+0.001)
+	}
+	for _, code := range failedExits {
+		fmt.Fprintf(&b, "LAT|000|0|0|0|0|0|1.000|%d\n", code)
+	}
+	return b.String()
+}
+
+func TestEvaluateLatency(t *testing.T) {
+	cases := []struct {
+		name        string
+		output      string
+		wantCodes   []types.FindingCode
+		wantSummary string
+	}{
+		{"fast", latencyOutput(20, 0.001, 0.010), []types.FindingCode{""}, "20/20 requests succeeded"},
+		{"slow backend", latencyOutput(20, 0.001, 0.800), []types.FindingCode{types.CodeProbeLatencyHigh}, "the backend processing the request"},
+		{"slow dns", latencyOutput(20, 0.900, 0.010), []types.FindingCode{types.CodeProbeLatencyHigh}, "DNS resolution"},
+		{"partial failures", latencyOutput(18, 0.001, 0.010, 7, 28), []types.FindingCode{"", types.CodeProbeRequestsFailed}, "18/20 requests succeeded"},
+		{"all failed", latencyOutput(0, 0, 0, 28, 28), []types.FindingCode{types.CodeProbeHTTPFailed}, "All 2 requests"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			samples := parseLatencySamples(c.output)
+			findings := evaluateLatency("GET http://web.shop", len(samples), samples, 500)
+			var codes []types.FindingCode
+			for _, f := range findings {
+				codes = append(codes, f.Code)
+			}
+			if fmt.Sprint(codes) != fmt.Sprint(c.wantCodes) {
+				t.Errorf("codes = %v, want %v", codes, c.wantCodes)
+			}
+			if !strings.Contains(findings[0].Summary, c.wantSummary) {
+				t.Errorf("summary %q does not contain %q", findings[0].Summary, c.wantSummary)
+			}
+		})
+	}
+
+	samples := parseLatencySamples(latencyOutput(5, 0.001, 0.010))
+	findings := evaluateLatency("GET http://web.shop", 20, samples, 500)
+	if !strings.Contains(findings[0].Detail, "only 5 of 20 requests ran") {
+		t.Errorf("expected a truncated run to be reported, got %q", findings[0].Detail)
+	}
+}
+
+func TestProbeHeadersWithShellMetaAreRejected(t *testing.T) {
+	if err := checkProbeHeaders("probe_latency", "X-Env: beta; Accept: */*"); err != nil {
+		t.Errorf("valid headers rejected: %v", err)
+	}
+
+	cfg := &config.Config{ClusterName: "test"}
+	args := map[string]interface{}{"url": "http://web.shop:8080/", "headers": "X-Env: beta; X-Id: $(id)"}
+	for _, tool := range []Tool{
+		&ProbeLatencyTool{BaseTool: BaseTool{Cfg: cfg}},
+		&ProbeHTTPTool{BaseTool: BaseTool{Cfg: cfg}},
+	} {
+		_, err := tool.Run(context.Background(), args)
+		var mcpErr *types.MCPError
+		if !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeInvalidInput || !strings.Contains(mcpErr.Message, `"X-Id: $(id)"`) {
+			t.Errorf("%s: got %v, want INVALID_INPUT naming the header", tool.Name(), err)
+		}
+	}
+}
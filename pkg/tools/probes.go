@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -15,8 +16,8 @@ import (
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
-// validHostname matches DNS names, IPs, and K8s service FQDNs.
-var validHostname = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+// validHostname matches DNS names, IPv4 and IPv6 addresses, and K8s service FQDNs.
+var validHostname = regexp.MustCompile(`^[a-zA-Z0-9._:-]+$`)
 
 // probeAllowedMethods whitelist for HTTP probe methods.
 var probeAllowedMethods = map[string]bool{
@@ -28,11 +29,71 @@ var validRecordTypes = map[string]bool{
 	"A": true, "AAAA": true, "SRV": true, "CNAME": true, "MX": true, "TXT": true, "NS": true, "PTR": true,
 }
 
+// probeIPFamilyFlags maps the ip_family argument of the probe tools to the
+// nc and curl flag that forces it; "" keeps the resolver's choice.
+var probeIPFamilyFlags = map[string]string{"": "", "ipv4": "-4", "ipv6": "-6"}
+
+var ipFamilyProperty = map[string]interface{}{
+	"type":        "string",
+	"enum":        []string{"ipv4", "ipv6"},
+	"description": "Force the probe over IPv4 or IPv6, e.g. to test each family of a dual-stack Service (default: whichever the resolver returns first)",
+}
+
+// ipFamilyFlag validates the ip_family argument and returns its flag.
+func ipFamilyFlag(tool string, args map[string]interface{}) (string, error) {
+	family := strings.ToLower(getStringArg(args, "ip_family", ""))
+	flag, ok := probeIPFamilyFlags[family]
+	if !ok {
+		return "", &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    tool,
+			Message: fmt.Sprintf("ip_family %q must be ipv4 or ipv6", family),
+		}
+	}
+	return flag, nil
+}
+
+// overFamily describes a forced family in a probe summary.
+func overFamily(flag string) string {
+	switch flag {
+	case "-4":
+		return " over IPv4"
+	case "-6":
+		return " over IPv6"
+	}
+	return ""
+}
+
 // containsShellMeta returns true if the string contains shell metacharacters.
 func containsShellMeta(s string) bool {
 	return strings.ContainsAny(s, "'\"`;|&$(){}[]<>!\\#~")
 }
 
+// checkProbeHeaders rejects the first of headers, 'Key: Value' pairs
+// separated by semicolons, that contains shell metacharacters.
+func checkProbeHeaders(tool, headers string) error {
+	for _, h := range strings.Split(headers, ";") {
+		if h = strings.TrimSpace(h); containsShellMeta(h) {
+			return &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    tool,
+				Message: fmt.Sprintf("header %q contains invalid shell characters", h),
+			}
+		}
+	}
+	return nil
+}
+
+// stripIPv6Literal removes the brackets around an IPv6 literal host of
+// rawURL, which are the only brackets a probe URL may contain.
+func stripIPv6Literal(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || ipFamilyOf(u.Hostname()) != familyIPv6 {
+		return rawURL
+	}
+	return strings.Replace(rawURL, "["+u.Hostname()+"]", u.Hostname(), 1)
+}
+
 // servicePort holds a resolved K8s Service port.
 type servicePort struct {
 	Name     string
@@ -83,6 +144,19 @@ func resolveServicePorts(ctx context.Context, bt *BaseTool, host, fallbackNS str
 	return ports, nil
 }
 
+// probeQueueFinding reports how long a probe waited for a concurrency slot, or nil if it ran immediately.
+func probeQueueFinding(result *probes.ProbeResult) *types.DiagnosticFinding {
+	if result == nil || result.QueuePosition == 0 {
+		return nil
+	}
+	return &types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("probe queued at position %d, waited %s for a free slot", result.QueuePosition, result.QueueWait.Round(time.Millisecond)),
+		Detail:   "All concurrent probe slots were busy; see MAX_CONCURRENT_PROBES and PROBE_QUEUE_SIZE.",
+	}
+}
+
 // --- probe_connectivity ---
 
 type ProbeConnectivityTool struct {
@@ -114,6 +188,7 @@ func (t *ProbeConnectivityTool) InputSchema() map[string]interface{} {
 				"type":        "integer",
 				"description": "Probe timeout in seconds (default: 10, max: 30)",
 			},
+			"ip_family": ipFamilyProperty,
 		},
 		"required": []string{"target_host"},
 	}
@@ -141,6 +216,10 @@ func (t *ProbeConnectivityTool) Run(ctx context.Context, args map[string]interfa
 	if timeoutSec > 30 {
 		timeoutSec = 30
 	}
+	family, err := ipFamilyFlag(t.Name(), args)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine which port(s) to test
 	targetPort := getIntArg(args, "target_port", 0)
@@ -162,7 +241,7 @@ func (t *ProbeConnectivityTool) Run(ctx context.Context, args map[string]interfa
 
 	allFindings := make([]types.DiagnosticFinding, 0, len(ports))
 	for _, port := range ports {
-		findings, err := t.probePort(ctx, sourceNS, targetHost, port, timeoutSec)
+		findings, err := t.probePort(ctx, sourceNS, targetHost, port, timeoutSec, family)
 		if err != nil {
 			return nil, err
 		}
@@ -172,13 +251,13 @@ func (t *ProbeConnectivityTool) Run(ctx context.Context, args map[string]interfa
 	return NewToolResultResponse(t.Cfg, t.Name(), allFindings, sourceNS, ""), nil
 }
 
-func (t *ProbeConnectivityTool) probePort(ctx context.Context, sourceNS, targetHost string, targetPort, timeoutSec int) ([]types.DiagnosticFinding, error) {
+func (t *ProbeConnectivityTool) probePort(ctx context.Context, sourceNS, targetHost string, targetPort, timeoutSec int, family string) ([]types.DiagnosticFinding, error) {
 	req := probes.ProbeRequest{
 		Type:      probes.ProbeTypeConnectivity,
 		Namespace: sourceNS,
 		Command: []string{
 			"sh", "-c",
-			fmt.Sprintf("nc -z -w %d %s %d && echo 'CONNECTION_SUCCESS' || echo 'CONNECTION_FAILED'", timeoutSec, targetHost, targetPort),
+			fmt.Sprintf("nc %s -z -w %d %s %d && echo 'CONNECTION_SUCCESS' || echo 'CONNECTION_FAILED'", family, timeoutSec, targetHost, targetPort),
 		},
 	}
 
@@ -187,13 +266,16 @@ func (t *ProbeConnectivityTool) probePort(ctx context.Context, sourceNS, targetH
 		return nil, err
 	}
 
-	findings := make([]types.DiagnosticFinding, 0, 1)
+	findings := make([]types.DiagnosticFinding, 0, 2)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
 
 	if result.Success && strings.Contains(result.Output, "CONNECTION_SUCCESS") {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityOK,
 			Category: types.CategoryConnectivity,
-			Summary:  fmt.Sprintf("TCP connectivity from %s to %s:%d succeeded", sourceNS, targetHost, targetPort),
+			Summary:  fmt.Sprintf("TCP connectivity from %s to %s:%d%s succeeded", sourceNS, targetHost, targetPort, overFamily(family)),
 			Detail:   fmt.Sprintf("output=%s duration=%s", strings.TrimSpace(result.Output), result.Duration),
 		})
 	} else {
@@ -204,7 +286,8 @@ func (t *ProbeConnectivityTool) probePort(ctx context.Context, sourceNS, targetH
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryConnectivity,
-			Summary:    fmt.Sprintf("TCP connectivity from %s to %s:%d failed", sourceNS, targetHost, targetPort),
+			Code:       types.CodeProbeTCPFailed,
+			Summary:    fmt.Sprintf("TCP connectivity from %s to %s:%d%s failed", sourceNS, targetHost, targetPort, overFamily(family)),
 			Detail:     detail,
 			Suggestion: "Check NetworkPolicies, service endpoints, DNS resolution, and firewall rules between the source and destination namespaces.",
 		})
@@ -238,8 +321,9 @@ func (t *ProbeDNSTool) InputSchema() map[string]interface{} {
 			},
 			"record_type": map[string]interface{}{
 				"type":        "string",
-				"description": "DNS record type to query (A, AAAA, SRV, CNAME). Default: A",
+				"description": "DNS record type to query (A, AAAA, SRV, CNAME). Default: A, or AAAA when ip_family is ipv6",
 			},
+			"ip_family": ipFamilyProperty,
 		},
 		"required": []string{"hostname"},
 	}
@@ -248,7 +332,7 @@ func (t *ProbeDNSTool) InputSchema() map[string]interface{} {
 func (t *ProbeDNSTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	hostname := getStringArg(args, "hostname", "")
 	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
-	recordType := getStringArg(args, "record_type", "A")
+	recordType := getStringArg(args, "record_type", "")
 
 	if hostname == "" {
 		return nil, &types.MCPError{
@@ -264,8 +348,15 @@ func (t *ProbeDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 			Message: "hostname contains invalid characters",
 		}
 	}
+	family, err := ipFamilyFlag(t.Name(), args)
+	if err != nil {
+		return nil, err
+	}
 	if !validRecordTypes[strings.ToUpper(recordType)] {
 		recordType = "A"
+		if family == "-6" {
+			recordType = "AAAA"
+		}
 	}
 
 	req := probes.ProbeRequest{
@@ -282,7 +373,10 @@ func (t *ProbeDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 		return nil, err
 	}
 
-	findings := make([]types.DiagnosticFinding, 0, 1)
+	findings := make([]types.DiagnosticFinding, 0, 2)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
 	output := strings.TrimSpace(result.Output)
 
 	if result.Success && !strings.Contains(output, "** server can't find") && !strings.Contains(output, "NXDOMAIN") {
@@ -296,6 +390,7 @@ func (t *ProbeDNSTool) Run(ctx context.Context, args map[string]interface{}) (*S
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryDNS,
+			Code:       types.CodeProbeDNSFailed,
 			Summary:    fmt.Sprintf("DNS resolution for %s (%s) failed", hostname, recordType),
 			Detail:     output,
 			Suggestion: "Check CoreDNS pods are running, verify the service exists in the expected namespace, and check NetworkPolicies are not blocking DNS (port 53).",
@@ -340,6 +435,7 @@ func (t *ProbeHTTPTool) InputSchema() map[string]interface{} {
 				"type":        "integer",
 				"description": "Request timeout in seconds (default: 10, max: 30)",
 			},
+			"ip_family": ipFamilyProperty,
 		},
 		"required": []string{"url"},
 	}
@@ -359,13 +455,16 @@ func (t *ProbeHTTPTool) Run(ctx context.Context, args map[string]interface{}) (*
 			Message: "url is required",
 		}
 	}
-	if containsShellMeta(rawURL) {
+	if containsShellMeta(stripIPv6Literal(rawURL)) {
 		return nil, &types.MCPError{
 			Code:    types.ErrCodeInvalidInput,
 			Tool:    t.Name(),
 			Message: "url contains invalid shell characters",
 		}
 	}
+	if err := checkProbeHeaders(t.Name(), headers); err != nil {
+		return nil, err
+	}
 	method = strings.ToUpper(method)
 	if !probeAllowedMethods[method] {
 		method = "GET"
@@ -373,6 +472,10 @@ func (t *ProbeHTTPTool) Run(ctx context.Context, args map[string]interface{}) (*
 	if timeoutSec > 30 {
 		timeoutSec = 30
 	}
+	family, err := ipFamilyFlag(t.Name(), args)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine which URL(s) to test. If the URL has no port and the hostname
 	// looks like a K8s service, resolve the port(s) from the Service spec.
@@ -394,7 +497,7 @@ func (t *ProbeHTTPTool) Run(ctx context.Context, args map[string]interface{}) (*
 
 	allFindings := make([]types.DiagnosticFinding, 0, len(urls))
 	for _, testURL := range urls {
-		findings, err := t.probeURL(ctx, sourceNS, method, headers, timeoutSec, testURL)
+		findings, err := t.probeURL(ctx, sourceNS, method, headers, timeoutSec, family, testURL)
 		if err != nil {
 			return nil, err
 		}
@@ -404,9 +507,10 @@ func (t *ProbeHTTPTool) Run(ctx context.Context, args map[string]interface{}) (*
 	return NewToolResultResponse(t.Cfg, t.Name(), allFindings, sourceNS, ""), nil
 }
 
-func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers string, timeoutSec int, targetURL string) ([]types.DiagnosticFinding, error) {
-	// Build curl command
-	curlCmd := fmt.Sprintf("curl -s -o /tmp/body -w '%%{http_code}|%%{time_total}|%%{ssl_verify_result}' -X %s --max-time %d -L", method, timeoutSec)
+func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers string, timeoutSec int, family, targetURL string) ([]types.DiagnosticFinding, error) {
+	// Build curl command; -g keeps the brackets of IPv6 literals from being
+	// read as a URL glob.
+	curlCmd := fmt.Sprintf("curl %s -g -s -o /tmp/body -w '%%{http_code}|%%{time_total}|%%{ssl_verify_result}' -X %s --max-time %d -L", family, method, timeoutSec)
 
 	if headers != "" {
 		for _, h := range strings.Split(headers, ";") {
@@ -417,7 +521,7 @@ func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers
 		}
 	}
 
-	curlCmd += fmt.Sprintf(" %s", targetURL)
+	curlCmd += fmt.Sprintf(" '%s'", targetURL)
 	curlCmd += " 2>&1; echo; echo '---BODY---'; head -c 1024 /tmp/body 2>/dev/null || true"
 
 	req := probes.ProbeRequest{
@@ -431,7 +535,10 @@ func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers
 		return nil, err
 	}
 
-	findings := make([]types.DiagnosticFinding, 0, 1)
+	findings := make([]types.DiagnosticFinding, 0, 2)
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
 	output := strings.TrimSpace(result.Output)
 
 	// Parse curl output: status_code|time_total|ssl_verify
@@ -455,18 +562,22 @@ func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers
 
 	if result.Success && statusCode != "000" {
 		severity := types.SeverityOK
+		var findingCode types.FindingCode
 		if code, err := strconv.Atoi(statusCode); err == nil {
 			if code >= 500 {
 				severity = types.SeverityCritical
+				findingCode = types.CodeProbeHTTPErrorStatus
 			} else if code >= 400 {
 				severity = types.SeverityWarning
+				findingCode = types.CodeProbeHTTPErrorStatus
 			}
 		}
 
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryConnectivity,
-			Summary:  fmt.Sprintf("HTTP %s %s returned %s in %s", method, targetURL, statusCode, responseTime),
+			Code:     findingCode,
+			Summary:  fmt.Sprintf("HTTP %s %s%s returned %s in %s", method, targetURL, overFamily(family), statusCode, responseTime),
 			Detail:   fmt.Sprintf("status=%s response_time=%s body_snippet=%s", statusCode, responseTime, bodySnippet),
 		})
 	} else {
@@ -477,7 +588,8 @@ func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityCritical,
 			Category:   types.CategoryConnectivity,
-			Summary:    fmt.Sprintf("HTTP %s %s failed (connection error or timeout)", method, targetURL),
+			Code:       types.CodeProbeHTTPFailed,
+			Summary:    fmt.Sprintf("HTTP %s %s%s failed (connection error or timeout)", method, targetURL, overFamily(family)),
 			Detail:     detail,
 			Suggestion: "Check that the target service is running, DNS resolves correctly, and there are no NetworkPolicies or mTLS requirements blocking the connection.",
 		})
@@ -485,3 +597,79 @@ func (t *ProbeHTTPTool) probeURL(ctx context.Context, sourceNS, method, headers
 
 	return findings, nil
 }
+
+// --- check_probe_hygiene ---
+
+type CheckProbeHygieneTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *CheckProbeHygieneTool) Name() string { return "check_probe_hygiene" }
+func (t *CheckProbeHygieneTool) Description() string {
+	return "Verify that ephemeral probe pods are being cleaned up: runs the probe reconciler across all namespaces, deletes probe pods past their TTL and reports each one as a leak"
+}
+func (t *CheckProbeHygieneTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *CheckProbeHygieneTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	report := t.ProbeManager.Reconcile(ctx)
+	_, removed := t.ProbeManager.LastHygiene()
+
+	if report.Error != "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: "failed to list probe pods",
+			Detail:  report.Error,
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, leak := range report.Leaked {
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeProbeLeakedPod,
+			Resource: &types.ResourceRef{Kind: "Pod", Namespace: leak.Namespace, Name: leak.Name, APIVersion: "v1"},
+			Summary:  fmt.Sprintf("Leaked %s probe pod %s/%s outlived its TTL (age %s) and was deleted", leak.ProbeType, leak.Namespace, leak.Name, leak.Age.Round(time.Second)),
+			Detail:   fmt.Sprintf("phase=%s", leak.Phase),
+		}
+		if !leak.Deleted {
+			f.Severity = types.SeverityCritical
+			f.Summary = fmt.Sprintf("Leaked %s probe pod %s/%s outlived its TTL (age %s) and could not be deleted", leak.ProbeType, leak.Namespace, leak.Name, leak.Age.Round(time.Second))
+			f.Detail = fmt.Sprintf("phase=%s error=%s", leak.Phase, leak.Error)
+			f.Suggestion = "Verify the server's RBAC allows deleting pods in this namespace, or delete the pod manually."
+		}
+		findings = append(findings, f)
+	}
+
+	if len(report.Leaked) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("No leaked probe pods found (%d probe pods currently running within TTL)", report.Active),
+		})
+	} else if report.Active > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("%d probe pods currently running within TTL", report.Active),
+		})
+	}
+
+	if removed > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    fmt.Sprintf("Probe reconciler has removed %d leaked probe pods since startup", removed),
+			Suggestion: "Frequent leaks usually mean the server is restarted or loses API access while probes are running.",
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	appMeshMeshGVR           = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "meshes"}
+	appMeshVirtualNodeGVR    = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "virtualnodes"}
+	appMeshVirtualServiceGVR = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "virtualservices"}
+	appMeshVirtualRouterGVR  = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "virtualrouters"}
+	appMeshVirtualGatewayGVR = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "virtualgateways"}
+	appMeshGatewayRouteGVR   = schema.GroupVersionResource{Group: "appmesh.k8s.aws", Version: "v1beta2", Resource: "gatewayroutes"}
+)
+
+// appMeshKind is an App Mesh CRD; each reports readiness with an <Kind>Active
+// condition.
+type appMeshKind struct {
+	kind      string
+	gvr       schema.GroupVersionResource
+	clustered bool
+}
+
+var appMeshKinds = []appMeshKind{
+	{kind: "Mesh", gvr: appMeshMeshGVR, clustered: true},
+	{kind: "VirtualNode", gvr: appMeshVirtualNodeGVR},
+	{kind: "VirtualService", gvr: appMeshVirtualServiceGVR},
+	{kind: "VirtualRouter", gvr: appMeshVirtualRouterGVR},
+	{kind: "VirtualGateway", gvr: appMeshVirtualGatewayGVR},
+	{kind: "GatewayRoute", gvr: appMeshGatewayRouteGVR},
+}
+
+const appMeshControllerNamespace = "appmesh-system"
+
+// appMeshEndOfSupport warns that AWS ends support for App Mesh on
+// 2026-09-30; the controller keeps running but the service is retired.
+var appMeshEndOfSupport = types.DiagnosticFinding{
+	Severity:   types.SeverityWarning,
+	Category:   types.CategoryMesh,
+	Code:       types.CodeMeshAppMeshEndOfSupport,
+	Summary:    "AWS App Mesh reaches end of support on 30 September 2026",
+	Suggestion: "Plan a migration to Amazon VPC Lattice (Gateway API controller) or Amazon ECS Service Connect.",
+}
+
+// appMeshActive returns the status and message of the <kind>Active condition.
+func appMeshActive(obj map[string]interface{}, kind string) (status, message string) {
+	conds, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conds {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ct, _ := cm["type"].(string); ct == kind+"Active" {
+			status, _ = cm["status"].(string)
+			reason, _ := cm["reason"].(string)
+			msg, _ := cm["message"].(string)
+			return status, strings.TrimSpace(reason + " " + msg)
+		}
+	}
+	return "Unknown", ""
+}
+
+// virtualNodeBackends returns the namespace/name of the VirtualServices a
+// VirtualNode lists as backends.
+func virtualNodeBackends(obj map[string]interface{}, nodeNs string) []string {
+	backends, _, _ := unstructured.NestedSlice(obj, "spec", "backends")
+	var out []string
+	for _, b := range backends {
+		bm, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok, _ := unstructured.NestedMap(bm, "virtualService", "virtualServiceRef")
+		if !ok {
+			continue
+		}
+		name, _ := ref["name"].(string)
+		ns, _ := ref["namespace"].(string)
+		if name != "" {
+			out = append(out, orDefault(ns, nodeNs)+"/"+name)
+		}
+	}
+	return out
+}
+
+// --- list_appmesh_resources ---
+
+type ListAppMeshResourcesTool struct{ BaseTool }
+
+func (t *ListAppMeshResourcesTool) Name() string { return "list_appmesh_resources" }
+func (t *ListAppMeshResourcesTool) Description() string {
+	return "List AWS App Mesh resources (Meshes, VirtualNodes, VirtualServices, VirtualRouters, VirtualGateways, GatewayRoutes) with their Active status"
+}
+func (t *ListAppMeshResourcesTool) InputSchema() map[string]interface{} {
+	return withSelectors(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace (empty for all namespaces)",
+			},
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list this kind, e.g. VirtualNode (default all)",
+			},
+		},
+	})
+}
+
+func (t *ListAppMeshResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	kind := getStringArg(args, "kind", "")
+	sel := getSelectorArgs(args)
+
+	var kinds []appMeshKind
+	names := make([]string, 0, len(appMeshKinds))
+	for _, k := range appMeshKinds {
+		names = append(names, k.kind)
+		if kind == "" || strings.EqualFold(kind, k.kind) {
+			kinds = append(kinds, k)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unknown App Mesh kind %q", kind),
+			Detail:  "expected one of " + strings.Join(names, ", "),
+		}
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 10)
+	for _, k := range kinds {
+		listNs := ns
+		if k.clustered {
+			listNs = ""
+		}
+		list, err := t.listResourceSelected(ctx, k.gvr, listNs, sel)
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
+		if err != nil {
+			continue
+		}
+		for _, item := range list.Items {
+			status, msg := appMeshActive(item.Object, k.kind)
+			f := types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryMesh,
+				Resource: &types.ResourceRef{Kind: k.kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: "appmesh.k8s.aws/v1beta2"},
+				Summary:  fmt.Sprintf("App Mesh %s %s (%sActive=%s)", k.kind, qualifiedName(item.GetNamespace(), item.GetName()), k.kind, status),
+				Detail:   msg,
+			}
+			if status == "False" {
+				f.Severity = types.SeverityWarning
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Summary:  "No App Mesh resources found",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "appmesh"), nil
+}
+
+// qualifiedName formats namespace/name, or name for cluster-scoped resources.
+func qualifiedName(ns, name string) string {
+	if ns == "" {
+		return name
+	}
+	return ns + "/" + name
+}
+
+// --- check_appmesh_status ---
+
+type CheckAppMeshStatusTool struct{ BaseTool }
+
+func (t *CheckAppMeshStatusTool) Name() string { return "check_appmesh_status" }
+func (t *CheckAppMeshStatusTool) Description() string {
+	return "Check AWS App Mesh: controller readiness, resources whose Active condition is False, and VirtualNode backends referencing missing VirtualServices"
+}
+func (t *CheckAppMeshStatusTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to check (empty for cluster-wide)",
+			},
+		},
+	}
+}
+
+func (t *CheckAppMeshStatusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	findings := t.controllerDeploymentFindings(ctx, appMeshControllerNamespace, "App Mesh", types.CategoryMesh,
+		"Install the appmesh-controller helm chart in "+appMeshControllerNamespace+".")
+	findings = append(findings, appMeshEndOfSupport)
+
+	counts := make([]string, 0, len(appMeshKinds))
+	for _, k := range appMeshKinds {
+		listNs := ns
+		if k.clustered {
+			listNs = ""
+		}
+		list, err := t.listResource(ctx, k.gvr, listNs)
+		if err != nil {
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%s=%d", k.kind, len(list.Items)))
+		for _, item := range list.Items {
+			status, msg := appMeshActive(item.Object, k.kind)
+			if status != "False" {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeMeshAppMeshNotActive,
+				Resource:   &types.ResourceRef{Kind: k.kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: "appmesh.k8s.aws/v1beta2"},
+				Summary:    fmt.Sprintf("App Mesh %s %s is not active", k.kind, qualifiedName(item.GetNamespace(), item.GetName())),
+				Detail:     msg,
+				Suggestion: "The controller could not create the resource in App Mesh; check the controller logs and its IAM permissions.",
+			})
+		}
+	}
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryMesh,
+		Summary:  "App Mesh resources: " + orDefault(strings.Join(counts, ", "), "none"),
+	})
+
+	findings = append(findings, t.missingBackends(ctx, ns)...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "appmesh"), nil
+}
+
+// missingBackends reports VirtualNode backends whose VirtualService does not
+// exist: Envoy gets no route for them and calls fail.
+func (t *CheckAppMeshStatusTool) missingBackends(ctx context.Context, ns string) []types.DiagnosticFinding {
+	nodes, err := t.listResource(ctx, appMeshVirtualNodeGVR, ns)
+	if err != nil {
+		return nil
+	}
+	services, err := t.listResource(ctx, appMeshVirtualServiceGVR, "")
+	if err != nil {
+		return nil
+	}
+	existing := make(map[string]bool, len(services.Items))
+	for _, vs := range services.Items {
+		existing[vs.GetNamespace()+"/"+vs.GetName()] = true
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, node := range nodes.Items {
+		for _, backend := range virtualNodeBackends(node.Object, node.GetNamespace()) {
+			if existing[backend] {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeMeshAppMeshBackendMissing,
+				Resource:   &types.ResourceRef{Kind: "VirtualNode", Namespace: node.GetNamespace(), Name: node.GetName(), APIVersion: "appmesh.k8s.aws/v1beta2"},
+				Summary:    fmt.Sprintf("VirtualNode %s/%s backend VirtualService %s does not exist", node.GetNamespace(), node.GetName(), backend),
+				Suggestion: "Create the VirtualService or remove the backend; calls from this node to it are not routed by the mesh.",
+			})
+		}
+	}
+	return findings
+}
@@ -25,7 +25,7 @@ func (t *ListCalicoPoliciesTool) Description() string {
 	return "List Calico NetworkPolicies and GlobalNetworkPolicies"
 }
 func (t *ListCalicoPoliciesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -33,16 +33,20 @@ func (t *ListCalicoPoliciesTool) InputSchema() map[string]interface{} {
 				"description": "Namespace (empty for all namespaces)",
 			},
 		},
-	}
+	})
 }
 
 func (t *ListCalicoPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	sel := getSelectorArgs(args)
 	findings := make([]types.DiagnosticFinding, 0, 10)
 
 	// Calico NetworkPolicies
 	if ns == "" {
-		list, err := t.Clients.Dynamic.Resource(calicoNPGVR).List(ctx, metav1.ListOptions{})
+		list, err := t.listResourceSelected(ctx, calicoNPGVR, "", sel)
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
 		if err == nil {
 			for _, item := range list.Items {
 				findings = append(findings, types.DiagnosticFinding{
@@ -54,7 +58,10 @@ func (t *ListCalicoPoliciesTool) Run(ctx context.Context, args map[string]interf
 			}
 		}
 	} else {
-		list, err := t.Clients.Dynamic.Resource(calicoNPGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		list, err := t.listResourceSelected(ctx, calicoNPGVR, ns, sel)
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
 		if err == nil {
 			for _, item := range list.Items {
 				findings = append(findings, types.DiagnosticFinding{
@@ -68,7 +75,10 @@ func (t *ListCalicoPoliciesTool) Run(ctx context.Context, args map[string]interf
 	}
 
 	// GlobalNetworkPolicies
-	gnpList, err := t.Clients.Dynamic.Resource(calicoGNPGVR).List(ctx, metav1.ListOptions{})
+	gnpList, err := t.listResourceSelected(ctx, calicoGNPGVR, "", sel)
+	if serr := selectorError(t.Name(), err); serr != nil {
+		return nil, serr
+	}
 	if err == nil {
 		for _, item := range gnpList.Items {
 			findings = append(findings, types.DiagnosticFinding{
@@ -124,6 +134,7 @@ func (t *CheckCalicoStatusTool) Run(ctx context.Context, args map[string]interfa
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeCNIAgentCheckFailed,
 			Summary:    "Could not check Calico node pods",
 			Detail:     err.Error(),
 			Suggestion: "Verify Calico is installed (check kube-system or calico-system namespace).",
@@ -145,15 +156,19 @@ func (t *CheckCalicoStatusTool) Run(ctx context.Context, args map[string]interfa
 			nodeNames = append(nodeNames, pod.Spec.NodeName)
 		}
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if ready < total {
 			severity = types.SeverityWarning
+			code = types.CodeCNIAgentsNotReady
 		}
 		if ready == 0 && total > 0 {
 			severity = types.SeverityCritical
+			code = types.CodeCNIAgentsNotReady
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryMesh,
+			Code:     code,
 			Summary:  fmt.Sprintf("Calico nodes: %d/%d ready", ready, total),
 			Detail:   fmt.Sprintf("nodes=%s", strings.Join(nodeNames, ", ")),
 		})
@@ -73,7 +73,7 @@ func (t *ListCiliumPoliciesTool) Description() string {
 	return "List Cilium NetworkPolicies and CiliumClusterwideNetworkPolicies with L3/L4/L7 rule counts and endpoint selector labels"
 }
 func (t *ListCiliumPoliciesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
+	return withSelectors(map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
 			"namespace": map[string]interface{}{
@@ -81,20 +81,18 @@ func (t *ListCiliumPoliciesTool) InputSchema() map[string]interface{} {
 				"description": "Namespace (empty for all namespaces)",
 			},
 		},
-	}
+	})
 }
 
 func (t *ListCiliumPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
 	ns := getStringArg(args, "namespace", "")
+	sel := getSelectorArgs(args)
 	findings := make([]types.DiagnosticFinding, 0, 10)
 
 	// CiliumNetworkPolicies
-	var cnpList *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		cnpList, err = t.Clients.Dynamic.Resource(ciliumNPGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		cnpList, err = t.Clients.Dynamic.Resource(ciliumNPGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+	cnpList, err := t.listResourceSelected(ctx, ciliumNPGVR, ns, sel)
+	if serr := selectorError(t.Name(), err); serr != nil {
+		return nil, serr
 	}
 	if err == nil {
 		for _, item := range cnpList.Items {
@@ -122,7 +120,10 @@ func (t *ListCiliumPoliciesTool) Run(ctx context.Context, args map[string]interf
 	}
 
 	// CiliumClusterwideNetworkPolicies
-	ccnpList, ccnpErr := t.Clients.Dynamic.Resource(ciliumCNPGVR).List(ctx, metav1.ListOptions{})
+	ccnpList, ccnpErr := t.listResourceSelected(ctx, ciliumCNPGVR, "", sel)
+	if serr := selectorError(t.Name(), ccnpErr); serr != nil {
+		return nil, serr
+	}
 	if ccnpErr == nil {
 		for _, item := range ccnpList.Items {
 			ingress, _, _ := unstructured.NestedSlice(item.Object, "spec", "ingress")
@@ -338,15 +339,18 @@ func describeCiliumRule(rule map[string]interface{}, direction string, index int
 					direction, index, hi, orAny(method), orAny(path))
 
 				severity := types.SeverityInfo
+				var code types.FindingCode
 				suggestion := ""
 				if method != "" || path != "" {
 					severity = types.SeverityWarning
+					code = types.CodeCNIL7RuleRestricts
 					suggestion = fmt.Sprintf("L7 HTTP rule restricts traffic to method=%s path=%s; ensure clients comply or requests will be dropped.", orAny(method), orAny(path))
 				}
 
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   severity,
 					Category:   types.CategoryPolicy,
+					Code:       code,
 					Resource:   ref,
 					Summary:    summary,
 					Suggestion: suggestion,
@@ -368,15 +372,18 @@ func describeCiliumRule(rule map[string]interface{}, direction string, index int
 					direction, index, gi, orAny(svc), orAny(method))
 
 				severity := types.SeverityInfo
+				var code types.FindingCode
 				suggestion := ""
 				if svc != "" || method != "" {
 					severity = types.SeverityWarning
+					code = types.CodeCNIL7RuleRestricts
 					suggestion = fmt.Sprintf("L7 gRPC rule restricts traffic to service=%s method=%s; ensure clients comply or requests will be dropped.", orAny(svc), orAny(method))
 				}
 
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   severity,
 					Category:   types.CategoryPolicy,
+					Code:       code,
 					Resource:   ref,
 					Summary:    summary,
 					Suggestion: suggestion,
@@ -398,15 +405,18 @@ func describeCiliumRule(rule map[string]interface{}, direction string, index int
 					direction, index, ki, orAny(topic), orAny(role))
 
 				severity := types.SeverityInfo
+				var code types.FindingCode
 				suggestion := ""
 				if topic != "" || role != "" {
 					severity = types.SeverityWarning
+					code = types.CodeCNIL7RuleRestricts
 					suggestion = fmt.Sprintf("L7 Kafka rule restricts traffic to topic=%s role=%s; ensure clients comply or requests will be dropped.", orAny(topic), orAny(role))
 				}
 
 				findings = append(findings, types.DiagnosticFinding{
 					Severity:   severity,
 					Category:   types.CategoryPolicy,
+					Code:       code,
 					Resource:   ref,
 					Summary:    summary,
 					Suggestion: suggestion,
@@ -485,6 +495,7 @@ func (t *CheckCiliumStatusTool) Run(ctx context.Context, args map[string]interfa
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeCNIAgentCheckFailed,
 			Summary:    "Could not check Cilium agent pods",
 			Detail:     err.Error(),
 			Suggestion: "Verify Cilium is installed in the kube-system namespace.",
@@ -506,15 +517,19 @@ func (t *CheckCiliumStatusTool) Run(ctx context.Context, args map[string]interfa
 			nodeNames = append(nodeNames, pod.Spec.NodeName)
 		}
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if ready < total {
 			severity = types.SeverityWarning
+			code = types.CodeCNIAgentsNotReady
 		}
 		if ready == 0 {
 			severity = types.SeverityCritical
+			code = types.CodeCNIAgentsNotReady
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryMesh,
+			Code:     code,
 			Summary:  fmt.Sprintf("Cilium agents: %d/%d ready", ready, total),
 			Detail:   fmt.Sprintf("nodes=%s", strings.Join(nodeNames, ", ")),
 		})
@@ -522,7 +537,7 @@ func (t *CheckCiliumStatusTool) Run(ctx context.Context, args map[string]interfa
 
 	// Count Cilium endpoints
 	if ns == "" {
-		epList, err := t.Clients.Dynamic.Resource(ciliumEPGVR).List(ctx, metav1.ListOptions{})
+		epList, err := t.listResource(ctx, ciliumEPGVR, "")
 		if err == nil {
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityInfo,
@@ -531,7 +546,7 @@ func (t *CheckCiliumStatusTool) Run(ctx context.Context, args map[string]interfa
 			})
 		}
 	} else {
-		epList, err := t.Clients.Dynamic.Resource(ciliumEPGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		epList, err := t.listResource(ctx, ciliumEPGVR, ns)
 		if err == nil {
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityInfo,
@@ -2,30 +2,421 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/netip"
+	"sort"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
+// flannelAnnotationPrefix is the default --kube-annotation-prefix under
+// which flanneld registers each node's backend and public IP.
+const flannelAnnotationPrefix = "flannel.alpha.coreos.com/"
+
+// flannelNetConf is the net-conf.json document of kube-flannel-cfg.
+type flannelNetConf struct {
+	Network     string `json:"Network"`
+	IPv6Network string `json:"IPv6Network"`
+	EnableIPv6  bool   `json:"EnableIPv6"`
+	Backend     struct {
+		Type string `json:"Type"`
+		VNI  int    `json:"VNI"`
+	} `json:"Backend"`
+}
+
+// parseFlannelNetConf parses net-conf.json; the backend type defaults to udp
+// as in flanneld.
+func parseFlannelNetConf(netConf string) (flannelNetConf, error) {
+	var conf flannelNetConf
+	if err := json.Unmarshal([]byte(netConf), &conf); err != nil {
+		return conf, err
+	}
+	conf.Backend.Type = strings.ToLower(conf.Backend.Type)
+	if conf.Backend.Type == "" {
+		conf.Backend.Type = "udp"
+	}
+	return conf, nil
+}
+
+// flannelRouteDevice returns the interface flanneld routes other nodes'
+// subnets through, "" for host-gw, which routes through the node IPs, and
+// ok=false for backends that program no node routes (cloud VPC routes).
+func flannelRouteDevice(conf flannelNetConf) (dev string, ok bool) {
+	switch conf.Backend.Type {
+	case "vxlan":
+		vni := conf.Backend.VNI
+		if vni == 0 {
+			vni = 1
+		}
+		return fmt.Sprintf("flannel.%d", vni), true
+	case "wireguard":
+		return "flannel-wg", true
+	case "udp":
+		return "flannel0", true
+	case "ipip":
+		return "flannel.ipip", true
+	case "host-gw":
+		return "", true
+	}
+	return "", false
+}
+
+// flannelConfigFindings reports an unusable or deprecated net-conf.json.
+func flannelConfigFindings(conf flannelNetConf, err error, ref *types.ResourceRef) []types.DiagnosticFinding {
+	invalid := func(summary, detail string) []types.DiagnosticFinding {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIFlannelConfigInvalid,
+			Resource:   ref,
+			Summary:    summary,
+			Detail:     detail,
+			Suggestion: "Fix net-conf.json in the kube-flannel-cfg ConfigMap and restart the flannel pods",
+		}}
+	}
+	if err != nil {
+		return invalid("Flannel net-conf.json is not valid JSON: flanneld cannot start", err.Error())
+	}
+	if _, perr := netip.ParsePrefix(conf.Network); perr != nil && !(conf.EnableIPv6 && conf.Network == "") {
+		return invalid(fmt.Sprintf("Flannel Network %q is not a CIDR", conf.Network), "")
+	}
+
+	var findings []types.DiagnosticFinding
+	switch conf.Backend.Type {
+	case "vxlan", "host-gw", "wireguard", "ipip", "alloc", "aws-vpc", "gce", "ali-vpc", "tencent-vpc":
+	case "udp":
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIFlannelConfigInvalid,
+			Resource:   ref,
+			Summary:    "Flannel uses the udp backend, which copies every packet through flanneld in user space",
+			Detail:     "net-conf.json sets Backend.Type udp or no backend at all",
+			Suggestion: `Set "Backend": {"Type": "vxlan"} unless the kernel lacks VXLAN support`,
+		})
+	default:
+		findings = append(findings, invalid(fmt.Sprintf("Flannel backend %q is not supported", conf.Backend.Type), "")...)
+	}
+
+	detail := "Network " + conf.Network
+	if conf.EnableIPv6 {
+		detail += ", IPv6Network " + conf.IPv6Network
+	}
+	if dev, ok := flannelRouteDevice(conf); ok && dev != "" {
+		detail += ", interface " + dev
+	}
+	findings = append([]types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Resource: ref,
+		Summary:  fmt.Sprintf("Flannel backend: %s", conf.Backend.Type),
+		Detail:   detail,
+	}}, findings...)
+	return findings
+}
+
+// flannelLease is the subnet lease of a node: with the kube subnet manager,
+// its IPv4 spec.podCIDR, and the public IP flanneld registered for it.
+type flannelLease struct {
+	node     string
+	subnet   netip.Prefix
+	publicIP string
+}
+
+// flannelLeases reads the lease of every node with an IPv4 pod CIDR.
+func flannelLeases(nodes []unstructured.Unstructured) []flannelLease {
+	var leases []flannelLease
+	for _, n := range nodes {
+		cidrs, _, _ := unstructured.NestedStringSlice(n.Object, "spec", "podCIDRs")
+		if cidr, _, _ := unstructured.NestedString(n.Object, "spec", "podCIDR"); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+		for _, c := range cidrs {
+			if p, err := netip.ParsePrefix(c); err == nil && p.Addr().Is4() {
+				leases = append(leases, flannelLease{node: n.GetName(), subnet: p.Masked(), publicIP: n.GetAnnotations()[flannelAnnotationPrefix+"public-ip"]})
+				break
+			}
+		}
+	}
+	return leases
+}
+
+// flannelNodeFindings checks the subnet leases and flannel annotations of
+// the nodes: every node needs a pod CIDR inside Network that no other node
+// holds, and flanneld registers its backend and public IP once it runs.
+func flannelNodeFindings(conf *flannelNetConf, nodes []unstructured.Unstructured) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	add := func(severity string, code types.FindingCode, summary string, names []string, suggestion string) {
+		if len(names) == 0 {
+			return
+		}
+		sort.Strings(names)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   severity,
+			Category:   types.CategoryConnectivity,
+			Code:       code,
+			Summary:    summary,
+			Detail:     "Nodes: " + truncateList(names, 10),
+			Suggestion: suggestion,
+		})
+	}
+
+	leases := flannelLeases(nodes)
+	leased := make(map[string]bool, len(leases))
+	for _, l := range leases {
+		leased[l.node] = true
+	}
+	var noLease, unregistered, wrongBackend []string
+	publicIPs := make(map[string][]string)
+	for _, n := range nodes {
+		if !leased[n.GetName()] {
+			noLease = append(noLease, n.GetName())
+		}
+		annotations := n.GetAnnotations()
+		backend := annotations[flannelAnnotationPrefix+"backend-type"]
+		publicIP := annotations[flannelAnnotationPrefix+"public-ip"]
+		if backend == "" || publicIP == "" {
+			unregistered = append(unregistered, n.GetName())
+			continue
+		}
+		publicIPs[publicIP] = append(publicIPs[publicIP], n.GetName())
+		if conf != nil && backend != conf.Backend.Type {
+			wrongBackend = append(wrongBackend, fmt.Sprintf("%s (%s)", n.GetName(), backend))
+		}
+	}
+
+	add(types.SeverityCritical, types.CodeCNIFlannelSubnetLeaseInvalid,
+		fmt.Sprintf("%d nodes have no IPv4 podCIDR: flanneld cannot start on them", len(noLease)), noLease,
+		"Run kube-controller-manager with --allocate-node-cidrs=true and a --cluster-cidr matching the flannel Network")
+	if conf != nil {
+		if network, err := netip.ParsePrefix(conf.Network); err == nil {
+			var outside []string
+			for _, l := range leases {
+				if !prefixesOverlap(l.subnet, network) || l.subnet.Bits() < network.Bits() {
+					outside = append(outside, fmt.Sprintf("%s (%s)", l.node, l.subnet))
+				}
+			}
+			add(types.SeverityCritical, types.CodeCNIFlannelSubnetLeaseInvalid,
+				fmt.Sprintf("%d nodes have a podCIDR outside the flannel Network %s", len(outside), network), outside,
+				"Make the kube-controller-manager --cluster-cidr match the flannel Network")
+		}
+	}
+	var overlaps []string
+	for i := range leases {
+		for j := i + 1; j < len(leases); j++ {
+			if prefixesOverlap(leases[i].subnet, leases[j].subnet) {
+				overlaps = append(overlaps, fmt.Sprintf("%s (%s) and %s (%s)", leases[i].node, leases[i].subnet, leases[j].node, leases[j].subnet))
+			}
+		}
+	}
+	add(types.SeverityCritical, types.CodeCNIFlannelSubnetLeaseInvalid,
+		fmt.Sprintf("%d pairs of nodes have overlapping pod subnets: pods on one node are unreachable from the other", len(overlaps)), overlaps,
+		"Delete and re-register one of the nodes so it is allocated a free podCIDR")
+
+	add(types.SeverityWarning, types.CodeCNIFlannelNodeNotRegistered,
+		fmt.Sprintf("%d nodes have no %sbackend-type or public-ip annotation: flanneld has not registered them and other nodes have no route to their pods", len(unregistered), flannelAnnotationPrefix), unregistered,
+		"Check the flannel pod on these nodes")
+	if conf != nil {
+		add(types.SeverityWarning, types.CodeCNIFlannelNodeNotRegistered,
+			fmt.Sprintf("%d nodes registered a backend other than the configured %s", len(wrongBackend), conf.Backend.Type), wrongBackend,
+			"Restart the flannel pods on these nodes so they pick up net-conf.json")
+	}
+	var shared []string
+	for ip, names := range publicIPs {
+		if len(names) > 1 {
+			sort.Strings(names)
+			shared = append(shared, fmt.Sprintf("%s: %s", ip, strings.Join(names, ", ")))
+		}
+	}
+	add(types.SeverityCritical, types.CodeCNIFlannelNodeNotRegistered,
+		fmt.Sprintf("%d public IPs are registered by more than one node: traffic for one node's pods reaches the other", len(shared)), shared,
+		"Set --iface or --public-ip on flanneld so each node registers its own address")
+	return findings
+}
+
+// flannelRouteScript prints the flannel interface named by $1, if any, and
+// the node's IPv4 routes.
+const flannelRouteScript = `[ -n "$1" ] && echo "LINK|$(ip -o link show dev "$1" 2>&1)"
+ip -4 route show 2>&1 | sed 's/^/ROUTE|/'`
+
+// ipRoute is one route printed by `ip route show`.
+type ipRoute struct {
+	prefix netip.Prefix
+	via    string
+	dev    string
+}
+
+// parseIPRoute reads a line of `ip -4 route show`.
+func parseIPRoute(line string) (ipRoute, bool) {
+	fields := strings.Fields(line)
+	if len(fields) > 0 && (fields[0] == "unicast" || fields[0] == "blackhole" || fields[0] == "unreachable" || fields[0] == "prohibit") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ipRoute{}, false
+	}
+	var r ipRoute
+	switch dst := fields[0]; {
+	case dst == "default":
+		r.prefix = netip.MustParsePrefix("0.0.0.0/0")
+	case strings.Contains(dst, "/"):
+		p, err := netip.ParsePrefix(dst)
+		if err != nil {
+			return r, false
+		}
+		r.prefix = p.Masked()
+	default:
+		a, err := netip.ParseAddr(dst)
+		if err != nil {
+			return r, false
+		}
+		r.prefix = netip.PrefixFrom(a, a.BitLen())
+	}
+	for i := 1; i+1 < len(fields); i++ {
+		switch fields[i] {
+		case "via":
+			r.via = fields[i+1]
+		case "dev":
+			r.dev = fields[i+1]
+		}
+	}
+	return r, true
+}
+
+// flannelRouteFindings checks the probe output of one node: the flannel
+// interface is up, and the subnet of every other node is routed through it,
+// or for host-gw through that node's public IP.
+func flannelRouteFindings(node, dev string, output string, leases []flannelLease) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Node", Name: node}
+	var routes []ipRoute
+	link := ""
+	for _, line := range strings.Split(output, "\n") {
+		kind, rest, ok := strings.Cut(strings.TrimSpace(line), "|")
+		switch {
+		case !ok:
+		case kind == "LINK":
+			link = rest
+		case kind == "ROUTE":
+			if r, ok := parseIPRoute(rest); ok {
+				routes = append(routes, r)
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	if dev != "" {
+		flags := ""
+		if _, after, ok := strings.Cut(link, "<"); ok {
+			flags, _, _ = strings.Cut(after, ">")
+		}
+		if flags == "" || !containsString(strings.Split(flags, ","), "UP") {
+			summary := fmt.Sprintf("Node %s has no %s interface", node, dev)
+			if flags != "" {
+				summary = fmt.Sprintf("Node %s interface %s is down", node, dev)
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeCNIFlannelRouteMissing,
+				Resource:   ref,
+				Summary:    summary,
+				Detail:     strings.TrimSpace(link),
+				Suggestion: "Check the logs of the flannel pod on this node; flanneld creates the interface at startup",
+			})
+		}
+	}
+
+	var missing []string
+	peers := 0
+	for _, l := range leases {
+		if l.node == node {
+			continue
+		}
+		peers++
+		var best *ipRoute
+		for i := range routes {
+			r := &routes[i]
+			if r.prefix.Bits() <= l.subnet.Bits() && r.prefix.Contains(l.subnet.Addr()) && (best == nil || r.prefix.Bits() > best.prefix.Bits()) {
+				best = r
+			}
+		}
+		want := "dev " + dev
+		if dev == "" {
+			want = "via " + l.publicIP
+		}
+		switch {
+		case best == nil:
+			missing = append(missing, fmt.Sprintf("%s (%s): no route", l.subnet, l.node))
+		case dev == "" && l.publicIP != "" && best.via != l.publicIP, dev != "" && best.dev != dev:
+			missing = append(missing, fmt.Sprintf("%s (%s): %s via %s dev %s, want %s", l.subnet, l.node, best.prefix, orDefault(best.via, "-"), best.dev, want))
+		}
+	}
+	if len(missing) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIFlannelRouteMissing,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Node %s does not route the pod subnets of %d nodes through flannel", node, len(missing)),
+			Detail:     truncateList(missing, 10),
+			Suggestion: "Restart the flannel pod on this node so it reprograms the routes; check that no other agent rewrites them",
+		})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Resource: ref,
+			Summary:  fmt.Sprintf("Node %s routes the pod subnets of %d nodes through flannel", node, peers),
+		})
+	}
+	return findings
+}
+
 // --- check_flannel_status ---
 
 type CheckFlannelStatusTool struct{ BaseTool }
 
 func (t *CheckFlannelStatusTool) Name() string { return "check_flannel_status" }
 func (t *CheckFlannelStatusTool) Description() string {
-	return "Check Flannel CNI installation health including DaemonSet status and pod health across nodes"
+	return "Check Flannel CNI health: DaemonSet pod status, the net-conf.json backend (vxlan, host-gw, wireguard), node subnet leases that are missing, outside the Network or overlapping, nodes flanneld has not registered, and optionally the flannel interface and routes on a node from a short-lived privileged pod"
 }
 func (t *CheckFlannelStatusTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
-		"type":       "object",
-		"properties": map[string]interface{}{},
+		"type": "object",
+		"properties": map[string]interface{}{
+			"node": map[string]interface{}{
+				"type":        "string",
+				"description": "Also check the flannel interface and routes on this node from a short-lived privileged pod (requires PRIVILEGED_PROBES)",
+			},
+		},
 	}
 }
 
 func (t *CheckFlannelStatusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	node := getStringArg(args, "node", "")
+	if node != "" && !validHostname.MatchString(node) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "node contains invalid characters",
+		}
+	}
+	if node != "" && (t.Probes == nil || !t.Cfg.PrivilegedProbes) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "checking a node's routes needs privileged probes; set PRIVILEGED_PROBES=true",
+		}
+	}
+
 	findings := make([]types.DiagnosticFinding, 0, 3)
 
 	// Check kube-flannel DaemonSet pods
@@ -55,16 +446,20 @@ func (t *CheckFlannelStatusTool) Run(ctx context.Context, args map[string]interf
 			}
 
 			severity := types.SeverityOK
+			var code types.FindingCode
 			if ready < total {
 				severity = types.SeverityWarning
+				code = types.CodeCNIAgentsNotReady
 			}
 			if ready == 0 {
 				severity = types.SeverityCritical
+				code = types.CodeCNIAgentsNotReady
 			}
 
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: severity,
 				Category: types.CategoryConnectivity,
+				Code:     code,
 				Resource: &types.ResourceRef{Kind: "DaemonSet", Namespace: nsCandidate, Name: "kube-flannel-ds"},
 				Summary:  fmt.Sprintf("Flannel pods: %d/%d ready in %s", ready, total, nsCandidate),
 				Detail:   fmt.Sprintf("nodes=%s", strings.Join(nodeNames, ", ")),
@@ -79,28 +474,89 @@ func (t *CheckFlannelStatusTool) Run(ctx context.Context, args map[string]interf
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryConnectivity,
+			Code:       types.CodeCNIDaemonSetMissing,
 			Summary:    "Flannel DaemonSet not found",
 			Suggestion: "Check if Flannel is installed (look for kube-flannel-ds DaemonSet in kube-flannel or kube-system namespace).",
 		})
 	}
 
-	// Check for Flannel ConfigMap
+	// Check the Flannel ConfigMap
+	var conf *flannelNetConf
 	for _, nsCandidate := range []string{"kube-flannel", "kube-system"} {
 		cm, err := t.Clients.Clientset.CoreV1().ConfigMaps(nsCandidate).Get(ctx, "kube-flannel-cfg", metav1.GetOptions{})
 		if err == nil {
-			netConf := cm.Data["net-conf.json"]
-			if netConf != "" {
-				findings = append(findings, types.DiagnosticFinding{
-					Severity: types.SeverityInfo,
-					Category: types.CategoryConnectivity,
-					Resource: &types.ResourceRef{Kind: "ConfigMap", Namespace: nsCandidate, Name: "kube-flannel-cfg"},
-					Summary:  "Flannel configuration found",
-					Detail:   fmt.Sprintf("net-conf.json=%s", netConf),
-				})
+			parsed, perr := parseFlannelNetConf(cm.Data["net-conf.json"])
+			ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: nsCandidate, Name: "kube-flannel-cfg"}
+			findings = append(findings, flannelConfigFindings(parsed, perr, ref)...)
+			if perr == nil {
+				conf = &parsed
 			}
 			break
 		}
 	}
 
+	nodes, err := t.listResource(ctx, nodesGVR, "")
+	if err != nil {
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, "", "flannel"), nil
+	}
+	findings = append(findings, flannelNodeFindings(conf, nodes.Items)...)
+
+	if node != "" {
+		nf, err := t.checkNodeRoutes(ctx, node, conf, flannelLeases(nodes.Items))
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, nf...)
+	}
 	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", "flannel"), nil
 }
+
+// checkNodeRoutes runs flannelRouteScript on the node's host network.
+func (t *CheckFlannelStatusTool) checkNodeRoutes(ctx context.Context, node string, conf *flannelNetConf, leases []flannelLease) ([]types.DiagnosticFinding, error) {
+	if conf == nil {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("Routes on node %s not checked: the flannel backend is unknown", node),
+		}}, nil
+	}
+	dev, ok := flannelRouteDevice(*conf)
+	if !ok {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("Routes on node %s not checked: the %s backend programs cloud routes, not node routes", node, conf.Backend.Type),
+		}}, nil
+	}
+
+	result, err := t.Probes.Execute(ctx, probes.ProbeRequest{
+		Type:       probes.ProbeTypeRoutes,
+		Namespace:  t.Cfg.ProbeNamespace,
+		NodeName:   node,
+		Command:    []string{"sh", "-c", flannelRouteScript, "routes", dev},
+		Timeout:    30 * time.Second,
+		Privileged: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var findings []types.DiagnosticFinding
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+	if !strings.Contains(result.Output, "ROUTE|") {
+		detail := strings.TrimSpace(result.Output)
+		if result.Error != "" {
+			detail = strings.TrimSpace(result.Error + "\n" + detail)
+		}
+		return append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Resource:   &types.ResourceRef{Kind: "Node", Name: node},
+			Summary:    fmt.Sprintf("Routes on node %s could not be read", node),
+			Detail:     detail,
+			Suggestion: fmt.Sprintf("Namespace %s must admit privileged pods (pod-security.kubernetes.io/enforce: privileged), and the probe image %s needs ip from iproute2.", t.Cfg.ProbeNamespace, t.Cfg.ProbeImage),
+		}), nil
+	}
+	return append(findings, flannelRouteFindings(node, dev, result.Output, leases)...), nil
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func flannelNode(name, podCIDR, backend, publicIP string) unstructured.Unstructured {
+	n := ipamObj("v1", "Node", "", name, map[string]interface{}{
+		"spec": map[string]interface{}{"podCIDR": podCIDR},
+	})
+	if backend != "" {
+		n.SetAnnotations(map[string]string{
+			flannelAnnotationPrefix + "backend-type": backend,
+			flannelAnnotationPrefix + "public-ip":    publicIP,
+		})
+	}
+	return *n
+}
+
+func TestFlannelConfigFindings(t *testing.T) {
+	for _, tc := range []struct {
+		netConf, want string
+	}{
+		{`{"Network":"10.244.0.0/16","Backend":{"Type":"VXLAN","VNI":4}}`, "info Flannel backend: vxlan "},
+		{`{"Network":"10.244.0.0/16"}`, "warning Flannel uses the udp backend, which copies every packet through flanneld in user space CNI027_FLANNEL_CONFIG_INVALID"},
+		{`{"Network":"10.244.0.0/16","Backend":{"Type":"vpn"}}`, `critical Flannel backend "vpn" is not supported CNI027_FLANNEL_CONFIG_INVALID`},
+		{`{"Network":"10.244.0.0","Backend":{"Type":"vxlan"}}`, `critical Flannel Network "10.244.0.0" is not a CIDR CNI027_FLANNEL_CONFIG_INVALID`},
+		{`{"Network":`, "critical Flannel net-conf.json is not valid JSON: flanneld cannot start CNI027_FLANNEL_CONFIG_INVALID"},
+	} {
+		conf, err := parseFlannelNetConf(tc.netConf)
+		if got := ciliumCodes(flannelConfigFindings(conf, err, nil)); !strings.Contains(got, tc.want) {
+			t.Errorf("%s: missing %q in:\n%s", tc.netConf, tc.want, got)
+		}
+	}
+
+	conf, _ := parseFlannelNetConf(`{"Network":"10.244.0.0/16","Backend":{"Type":"vxlan","VNI":4}}`)
+	if dev, ok := flannelRouteDevice(conf); !ok || dev != "flannel.4" {
+		t.Errorf("route device = %q, %v, want flannel.4", dev, ok)
+	}
+}
+
+func TestFlannelNodeFindings(t *testing.T) {
+	conf, _ := parseFlannelNetConf(`{"Network":"10.244.0.0/16","Backend":{"Type":"vxlan"}}`)
+	nodes := []unstructured.Unstructured{
+		flannelNode("a", "10.244.0.0/24", "vxlan", "172.18.0.2"),
+		flannelNode("b", "10.244.0.0/25", "vxlan", "172.18.0.3"),
+		flannelNode("c", "10.10.0.0/24", "host-gw", "172.18.0.3"),
+		flannelNode("d", "", "", ""),
+	}
+	got := ciliumCodes(flannelNodeFindings(&conf, nodes))
+	for _, want := range []string{
+		"critical 1 nodes have no IPv4 podCIDR: flanneld cannot start on them CNI029_FLANNEL_SUBNET_LEASE_INVALID",
+		"critical 1 nodes have a podCIDR outside the flannel Network 10.244.0.0/16 CNI029_FLANNEL_SUBNET_LEASE_INVALID",
+		"critical 1 pairs of nodes have overlapping pod subnets: pods on one node are unreachable from the other CNI029_FLANNEL_SUBNET_LEASE_INVALID",
+		"warning 1 nodes have no flannel.alpha.coreos.com/backend-type or public-ip annotation",
+		"warning 1 nodes registered a backend other than the configured vxlan CNI028_FLANNEL_NODE_NOT_REGISTERED",
+		"critical 1 public IPs are registered by more than one node: traffic for one node's pods reaches the other CNI028_FLANNEL_NODE_NOT_REGISTERED",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseIPRoute(t *testing.T) {
+	for line, want := range map[string]ipRoute{
+		"default via 172.18.0.1 dev eth0":                               {prefix: netip.MustParsePrefix("0.0.0.0/0"), via: "172.18.0.1", dev: "eth0"},
+		"10.244.1.0/24 via 10.244.1.0 dev flannel.1 onlink":             {prefix: netip.MustParsePrefix("10.244.1.0/24"), via: "10.244.1.0", dev: "flannel.1"},
+		"10.244.0.0/24 dev cni0 proto kernel scope link src 10.244.0.1": {prefix: netip.MustParsePrefix("10.244.0.0/24"), dev: "cni0"},
+		"blackhole 10.244.2.0/24 proto bird":                            {prefix: netip.MustParsePrefix("10.244.2.0/24")},
+		"169.254.169.254 via 172.18.0.1 dev eth0":                       {prefix: netip.MustParsePrefix("169.254.169.254/32"), via: "172.18.0.1", dev: "eth0"},
+	} {
+		got, ok := parseIPRoute(line)
+		if !ok || got != want {
+			t.Errorf("parseIPRoute(%q) = %+v, %v, want %+v", line, got, ok, want)
+		}
+	}
+}
+
+func TestFlannelRouteFindings(t *testing.T) {
+	leases := []flannelLease{
+		{node: "a", subnet: netip.MustParsePrefix("10.244.0.0/24"), publicIP: "172.18.0.2"},
+		{node: "b", subnet: netip.MustParsePrefix("10.244.1.0/24"), publicIP: "172.18.0.3"},
+		{node: "c", subnet: netip.MustParsePrefix("10.244.2.0/24"), publicIP: "172.18.0.4"},
+	}
+	output := strings.Join([]string{
+		"LINK|5: flannel.1: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1450 qdisc noqueue state UNKNOWN",
+		"ROUTE|default via 172.18.0.1 dev eth0",
+		"ROUTE|10.244.0.0/24 dev cni0 proto kernel scope link src 10.244.0.1",
+		"ROUTE|10.244.1.0/24 via 10.244.1.0 dev flannel.1 onlink",
+	}, "\n")
+	got := ciliumCodes(flannelRouteFindings("a", "flannel.1", output, leases))
+	want := "critical Node a does not route the pod subnets of 1 nodes through flannel CNI030_FLANNEL_ROUTE_MISSING"
+	if got != want {
+		t.Errorf("findings:\n%s\nwant:\n%s", got, want)
+	}
+
+	output += "\nROUTE|10.244.2.0/24 via 10.244.2.0 dev flannel.1 onlink"
+	if got := ciliumCodes(flannelRouteFindings("a", "flannel.1", output, leases)); got != "ok Node a routes the pod subnets of 2 nodes through flannel " {
+		t.Errorf("findings:\n%s", got)
+	}
+
+	down := strings.Replace(output, "UP,LOWER_UP", "", 1)
+	if got := ciliumCodes(flannelRouteFindings("a", "flannel.1", down, leases)); !strings.Contains(got, "critical Node a interface flannel.1 is down") {
+		t.Errorf("findings:\n%s", got)
+	}
+
+	// host-gw routes each subnet through the node that holds it.
+	hostGW := "ROUTE|10.244.1.0/24 via 172.18.0.3 dev eth0\nROUTE|10.244.2.0/24 via 172.18.0.3 dev eth0"
+	got = ciliumCodes(flannelRouteFindings("a", "", hostGW, leases))
+	if !strings.Contains(got, "does not route the pod subnets of 1 nodes") {
+		t.Errorf("findings:\n%s", got)
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	gkeBackendPolicyGVR     = schema.GroupVersionResource{Group: "networking.gke.io", Version: "v1", Resource: "gcpbackendpolicies"}
+	gkeHealthCheckPolicyGVR = schema.GroupVersionResource{Group: "networking.gke.io", Version: "v1", Resource: "healthcheckpolicies"}
+	gkeGatewayPolicyGVR     = schema.GroupVersionResource{Group: "networking.gke.io", Version: "v1", Resource: "gcpgatewaypolicies"}
+)
+
+// gkePolicyKinds are the policies GKE's Gateway controller attaches to
+// Services (backend and health check settings) and Gateways.
+var gkePolicyKinds = []managedPolicyKind{
+	{kind: "GCPBackendPolicy", gvr: gkeBackendPolicyGVR},
+	{kind: "HealthCheckPolicy", gvr: gkeHealthCheckPolicyGVR},
+	{kind: "GCPGatewayPolicy", gvr: gkeGatewayPolicyGVR},
+}
+
+// gkeControllerPrefix prefixes the controllerName of GKE's GatewayClasses
+// (networking.gke.io/gateway and networking.gke.io/gateway-multi-cluster).
+const gkeControllerPrefix = "networking.gke.io/"
+
+// gkeGatewayHint suggests the usual cause of a GKE Gateway that is not
+// programmed: regional and internal load balancers need a proxy-only subnet.
+func gkeGatewayHint(class string) string {
+	if strings.Contains(class, "rilb") || strings.Contains(class, "regional") {
+		return "Regional GKE Gateways need a proxy-only subnet (purpose REGIONAL_MANAGED_PROXY) in the cluster's region; check the Gateway's events."
+	}
+	return "Check the Gateway's events: GKE reports load balancer, certificate and quota errors there."
+}
+
+// --- list_gke_gateway_policies ---
+
+type ListGKEGatewayPoliciesTool struct{ BaseTool }
+
+func (t *ListGKEGatewayPoliciesTool) Name() string { return "list_gke_gateway_policies" }
+func (t *ListGKEGatewayPoliciesTool) Description() string {
+	return "List GKE Gateway policies (GCPBackendPolicy, HealthCheckPolicy, GCPGatewayPolicy) with their targets and attachment status"
+}
+func (t *ListGKEGatewayPoliciesTool) InputSchema() map[string]interface{} {
+	return withSelectors(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace (empty for all namespaces)",
+			},
+		},
+	})
+}
+
+func (t *ListGKEGatewayPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	findings, err := t.managedPolicyFindings(ctx, t.Name(), gkePolicyKinds, ns, getSelectorArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  "No GKE Gateway policies found",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gke-gateway"), nil
+}
+
+// --- check_gke_gateway_status ---
+
+type CheckGKEGatewayStatusTool struct{ BaseTool }
+
+func (t *CheckGKEGatewayStatusTool) Name() string { return "check_gke_gateway_status" }
+func (t *CheckGKEGatewayStatusTool) Description() string {
+	return "Check the GKE Gateway controller: its GatewayClasses, whether Gateways using them are programmed with an address, and GKE policies that are not attached or target missing resources"
+}
+func (t *CheckGKEGatewayStatusTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to check (empty for cluster-wide)",
+			},
+		},
+	}
+}
+
+func (t *CheckGKEGatewayStatusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	// The controller runs in the GKE control plane: its GatewayClasses are the
+	// only in-cluster sign that it is enabled.
+	findings, classes := t.managedGatewayFindings(ctx, gkeControllerPrefix, ns, true, gkeGatewayHint)
+	if classes == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCloudControllerNotEnabled,
+			Summary:    "GKE Gateway controller not enabled: no GatewayClass with a networking.gke.io controller",
+			Suggestion: "Enable it with: gcloud container clusters update CLUSTER --gateway-api=standard",
+		})
+	}
+
+	policies, err := t.managedPolicyFindings(ctx, t.Name(), gkePolicyKinds, ns, listSelector{})
+	if err != nil {
+		return nil, err
+	}
+	unattached := 0
+	for _, p := range policies {
+		if p.Severity == types.SeverityWarning {
+			findings = append(findings, p)
+			unattached++
+		}
+	}
+	findings = append(findings, t.missingPolicyTargets(ctx, gkePolicyKinds, ns, types.CategoryPolicy)...)
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("GKE Gateway policies: %d, %d not attached", len(policies), unattached),
+	})
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gke-gateway"), nil
+}
@@ -49,6 +49,7 @@ func (t *CheckKumaStatusTool) Run(ctx context.Context, args map[string]interface
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeMeshControlPlaneCheckFailed,
 			Summary:    "Could not check Kuma control plane pods",
 			Detail:     err.Error(),
 			Suggestion: "Verify Kuma is installed in the kuma-system namespace.",
@@ -63,19 +64,22 @@ func (t *CheckKumaStatusTool) Run(ctx context.Context, args map[string]interface
 			}
 		}
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if ready == 0 {
 			severity = types.SeverityCritical
+			code = types.CodeMeshControlPlaneNotReady
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryMesh,
+			Code:     code,
 			Resource: &types.ResourceRef{Kind: "Deployment", Namespace: "kuma-system", Name: "kuma-control-plane"},
 			Summary:  fmt.Sprintf("Kuma control plane: %d/%d pods ready", ready, len(cpPods.Items)),
 		})
 	}
 
 	// Count meshes
-	meshes, err := t.Clients.Dynamic.Resource(kumaMeshGVR).List(ctx, metav1.ListOptions{})
+	meshes, err := t.listResource(ctx, kumaMeshGVR, "")
 	if err == nil {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityInfo,
@@ -87,11 +91,7 @@ func (t *CheckKumaStatusTool) Run(ctx context.Context, args map[string]interface
 
 	// Count dataplanes
 	var dataplanes *unstructured.UnstructuredList
-	if ns == "" {
-		dataplanes, err = t.Clients.Dynamic.Resource(kumaDataplaneGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		dataplanes, err = t.Clients.Dynamic.Resource(kumaDataplaneGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	dataplanes, err = t.listResource(ctx, kumaDataplaneGVR, ns)
 	if err == nil {
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: types.SeverityInfo,
@@ -46,6 +46,7 @@ func (t *CheckLinkerdStatusTool) Run(ctx context.Context, args map[string]interf
 		findings = append(findings, types.DiagnosticFinding{
 			Severity:   types.SeverityWarning,
 			Category:   types.CategoryMesh,
+			Code:       types.CodeMeshControlPlaneCheckFailed,
 			Summary:    "Could not check Linkerd control plane pods",
 			Detail:     err.Error(),
 			Suggestion: "Verify Linkerd is installed in the linkerd namespace.",
@@ -68,15 +69,19 @@ func (t *CheckLinkerdStatusTool) Run(ctx context.Context, args map[string]interf
 			components[component] = isReady
 		}
 		severity := types.SeverityOK
+		var code types.FindingCode
 		if ready < total {
 			severity = types.SeverityWarning
+			code = types.CodeMeshControlPlaneNotReady
 		}
 		if ready == 0 {
 			severity = types.SeverityCritical
+			code = types.CodeMeshControlPlaneNotReady
 		}
 		findings = append(findings, types.DiagnosticFinding{
 			Severity: severity,
 			Category: types.CategoryMesh,
+			Code:     code,
 			Resource: &types.ResourceRef{Kind: "Namespace", Name: "linkerd"},
 			Summary:  fmt.Sprintf("Linkerd control plane: %d/%d pods ready", ready, total),
 			Detail:   fmt.Sprintf("components=%v", components),
@@ -101,7 +106,7 @@ func (t *CheckLinkerdStatusTool) Run(ctx context.Context, args map[string]interf
 
 	// Count service profiles
 	if ns == "" {
-		profiles, err := t.Clients.Dynamic.Resource(linkerdSPGVR).List(ctx, metav1.ListOptions{})
+		profiles, err := t.listResource(ctx, linkerdSPGVR, "")
 		if err == nil {
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityInfo,
@@ -110,7 +115,7 @@ func (t *CheckLinkerdStatusTool) Run(ctx context.Context, args map[string]interf
 			})
 		}
 	} else {
-		profiles, err := t.Clients.Dynamic.Resource(linkerdSPGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		profiles, err := t.listResource(ctx, linkerdSPGVR, ns)
 		if err == nil {
 			findings = append(findings, types.DiagnosticFinding{
 				Severity: types.SeverityInfo,
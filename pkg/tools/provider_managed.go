@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Helpers shared by the tools of managed cloud offerings (GKE Gateway, AWS
+// VPC Lattice, AWS App Mesh): their controllers run outside the cluster or in
+// a fixed namespace, and their policies attach to Gateway API resources or
+// Services through targetRefs.
+
+// managedPolicyKind is a policy CRD of a managed offering.
+type managedPolicyKind struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}
+
+// policyTargetRefs returns the targets of a policy from spec.targetRef or
+// spec.targetRefs, defaulting their namespace to the policy's.
+func policyTargetRefs(obj map[string]interface{}, policyNs string) []types.ResourceRef {
+	var raw []interface{}
+	if ref, ok, _ := unstructured.NestedMap(obj, "spec", "targetRef"); ok {
+		raw = append(raw, ref)
+	}
+	if refs, ok, _ := unstructured.NestedSlice(obj, "spec", "targetRefs"); ok {
+		raw = append(raw, refs...)
+	}
+	var out []types.ResourceRef
+	for _, r := range raw {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := rm["kind"].(string)
+		name, _ := rm["name"].(string)
+		ns, _ := rm["namespace"].(string)
+		if kind == "" || name == "" {
+			continue
+		}
+		out = append(out, types.ResourceRef{Kind: kind, Namespace: orDefault(ns, policyNs), Name: name})
+	}
+	return out
+}
+
+func formatTargetRefs(refs []types.ResourceRef) string {
+	if len(refs) == 0 {
+		return "no target"
+	}
+	parts := make([]string, 0, len(refs))
+	for _, r := range refs {
+		parts = append(parts, fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// managedPolicyFindings lists the policies of each kind, one finding per
+// policy with its targets; policies with a False condition are warnings.
+// Kinds whose CRD is not installed are skipped.
+func (b *BaseTool) managedPolicyFindings(ctx context.Context, toolName string, kinds []managedPolicyKind, ns string, sel listSelector) ([]types.DiagnosticFinding, error) {
+	var findings []types.DiagnosticFinding
+	for _, k := range kinds {
+		list, err := b.listResourceSelected(ctx, k.gvr, ns, sel)
+		if serr := selectorError(toolName, err); serr != nil {
+			return nil, serr
+		}
+		if err != nil {
+			continue
+		}
+		for _, item := range list.Items {
+			conds, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+			f := types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryPolicy,
+				Resource: &types.ResourceRef{Kind: k.kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: k.gvr.Group + "/" + k.gvr.Version},
+				Summary:  fmt.Sprintf("%s %s/%s -> %s", k.kind, item.GetNamespace(), item.GetName(), formatTargetRefs(policyTargetRefs(item.Object, item.GetNamespace()))),
+				Detail:   formatConditions(conds),
+			}
+			if msg := extractConditionMessage(conds, ""); msg != "" {
+				f.Severity = types.SeverityWarning
+				f.Code = types.CodeCloudPolicyNotAttached
+				f.Summary += " (not attached)"
+				f.Detail = msg
+				f.Suggestion = "Check that the target exists and is served by the managed controller; the condition message gives the controller's reason."
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// missingPolicyTargets reports policies whose Service, Gateway or HTTPRoute
+// target does not exist. Targets of other kinds are not checked.
+func (b *BaseTool) missingPolicyTargets(ctx context.Context, kinds []managedPolicyKind, ns, category string) []types.DiagnosticFinding {
+	existing := map[string]map[string]bool{}
+	load := func(kind string, list *unstructured.UnstructuredList, err error) {
+		if err != nil {
+			return
+		}
+		set := make(map[string]bool, len(list.Items))
+		for _, item := range list.Items {
+			set[item.GetNamespace()+"/"+item.GetName()] = true
+		}
+		existing[kind] = set
+	}
+	svcs, err := b.listResource(ctx, servicesGVR, ns)
+	load("Service", svcs, err)
+	gws, err := b.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	load("Gateway", gws, err)
+	routes, err := b.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, ns)
+	load("HTTPRoute", routes, err)
+
+	var findings []types.DiagnosticFinding
+	for _, k := range kinds {
+		list, err := b.listResource(ctx, k.gvr, ns)
+		if err != nil {
+			continue
+		}
+		for _, item := range list.Items {
+			for _, ref := range policyTargetRefs(item.Object, item.GetNamespace()) {
+				set, checked := existing[ref.Kind]
+				if !checked || set[ref.Namespace+"/"+ref.Name] {
+					continue
+				}
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   category,
+					Code:       types.CodeCloudPolicyTargetMissing,
+					Resource:   &types.ResourceRef{Kind: k.kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: k.gvr.Group + "/" + k.gvr.Version},
+					Summary:    fmt.Sprintf("%s %s/%s targets missing %s %s/%s", k.kind, item.GetNamespace(), item.GetName(), ref.Kind, ref.Namespace, ref.Name),
+					Suggestion: fmt.Sprintf("Create the %s or fix spec.targetRef; the policy has no effect until its target exists.", ref.Kind),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// managedGatewayFindings reports the GatewayClasses whose controllerName has
+// controllerPrefix and the Gateways using them. When wantAddress is set, a
+// programmed Gateway without an address is a warning. It returns the number
+// of matching GatewayClasses. hint, when set, returns a suggestion for a
+// Gateway of the given class that is not programmed.
+func (b *BaseTool) managedGatewayFindings(ctx context.Context, controllerPrefix, ns string, wantAddress bool, hint func(class string) string) ([]types.DiagnosticFinding, int) {
+	var findings []types.DiagnosticFinding
+	classes, err := b.listResource(ctx, gatewayClassesGVR, "")
+	if err != nil {
+		return nil, 0
+	}
+	managed := make(map[string]bool)
+	for _, gc := range classes.Items {
+		controller, _, _ := unstructured.NestedString(gc.Object, "spec", "controllerName")
+		if !strings.HasPrefix(controller, controllerPrefix) {
+			continue
+		}
+		managed[gc.GetName()] = true
+		conds, _, _ := unstructured.NestedSlice(gc.Object, "status", "conditions")
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Resource: &types.ResourceRef{Kind: "GatewayClass", Name: gc.GetName(), APIVersion: "gateway.networking.k8s.io/v1"},
+			Summary:  fmt.Sprintf("GatewayClass %s (%s) accepted", gc.GetName(), controller),
+		}
+		if classifyResourceStatus(conds) == "rejected" {
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeCloudGatewayClassNotAccepted
+			f.Summary = fmt.Sprintf("GatewayClass %s (%s) not accepted", gc.GetName(), controller)
+			f.Detail = extractConditionMessage(conds, "Accepted")
+		}
+		findings = append(findings, f)
+	}
+	if len(managed) == 0 {
+		return nil, 0
+	}
+
+	gateways, err := b.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	if err != nil {
+		return findings, len(managed)
+	}
+	for _, gw := range gateways.Items {
+		class, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+		if !managed[class] {
+			continue
+		}
+		conds, _, _ := unstructured.NestedSlice(gw.Object, "status", "conditions")
+		addrs, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+		var values []string
+		for _, a := range addrs {
+			if am, ok := a.(map[string]interface{}); ok {
+				if v, _ := am["value"].(string); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Resource: &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: "gateway.networking.k8s.io/v1"},
+			Summary:  fmt.Sprintf("Gateway %s/%s (class %s) programmed", gw.GetNamespace(), gw.GetName(), class),
+			Detail:   "addresses: " + orDefault(strings.Join(values, ", "), "none"),
+		}
+		switch {
+		case hasUnhealthyCondition(conds):
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeCloudGatewayNotProgrammed
+			f.Summary = fmt.Sprintf("Gateway %s/%s (class %s) not programmed", gw.GetNamespace(), gw.GetName(), class)
+			f.Detail = extractConditionMessage(conds, "")
+			if hint != nil {
+				f.Suggestion = hint(class)
+			}
+		case wantAddress && len(values) == 0:
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeCloudGatewayNoAddress
+			f.Summary = fmt.Sprintf("Gateway %s/%s (class %s) has no address yet", gw.GetNamespace(), gw.GetName(), class)
+			f.Suggestion = "The cloud load balancer is still being provisioned or failed; check the Gateway's events."
+		}
+		findings = append(findings, f)
+	}
+	return findings, len(managed)
+}
+
+// controllerDeploymentFindings reports the readiness of the Deployments of a
+// controller installed in ns.
+func (b *BaseTool) controllerDeploymentFindings(ctx context.Context, ns, product, category, suggestion string) []types.DiagnosticFinding {
+	deployments, err := b.listResource(ctx, deploymentsGVR, ns)
+	if err != nil || len(deployments.Items) == 0 {
+		detail := fmt.Sprintf("no Deployments in namespace %s", ns)
+		if err != nil {
+			detail = err.Error()
+		}
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   category,
+			Code:       types.CodeCloudControllerNotReady,
+			Summary:    fmt.Sprintf("%s controller not found", product),
+			Detail:     detail,
+			Suggestion: suggestion,
+		}}
+	}
+	var findings []types.DiagnosticFinding
+	for _, d := range deployments.Items {
+		desired, found, _ := unstructured.NestedInt64(d.Object, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+		ready, _, _ := unstructured.NestedInt64(d.Object, "status", "readyReplicas")
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: category,
+			Resource: &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: d.GetName(), APIVersion: "apps/v1"},
+			Summary:  fmt.Sprintf("%s controller %s: %d/%d replicas ready", product, d.GetName(), ready, desired),
+		}
+		if ready == 0 {
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeCloudControllerNotReady
+			f.Suggestion = fmt.Sprintf("Check the pods and logs of %s/%s.", ns, d.GetName())
+		} else if ready < desired {
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeCloudControllerNotReady
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
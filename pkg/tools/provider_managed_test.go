@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func managedObj(apiVersion, kind, ns, name string, content map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(ns)
+	u.SetName(name)
+	return u
+}
+
+func managedFindings(t *testing.T, tool Tool) string {
+	t.Helper()
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		summaries = append(summaries, string(f.Severity)+" "+f.Summary)
+	}
+	return strings.Join(summaries, "\n")
+}
+
+func TestPolicyTargetRefs(t *testing.T) {
+	obj := map[string]interface{}{"spec": map[string]interface{}{
+		"targetRef": map[string]interface{}{"kind": "Service", "name": "store"},
+		"targetRefs": []interface{}{
+			map[string]interface{}{"kind": "Gateway", "name": "external", "namespace": "infra"},
+			map[string]interface{}{"kind": "Service"},
+		},
+	}}
+	want := []types.ResourceRef{
+		{Kind: "Service", Namespace: "shop", Name: "store"},
+		{Kind: "Gateway", Namespace: "infra", Name: "external"},
+	}
+	if got := policyTargetRefs(obj, "shop"); !reflect.DeepEqual(got, want) {
+		t.Errorf("policyTargetRefs = %v, want %v", got, want)
+	}
+}
+
+func TestVirtualNodeBackends(t *testing.T) {
+	obj := map[string]interface{}{"spec": map[string]interface{}{"backends": []interface{}{
+		map[string]interface{}{"virtualService": map[string]interface{}{"virtualServiceRef": map[string]interface{}{"name": "reviews"}}},
+		map[string]interface{}{"virtualService": map[string]interface{}{"virtualServiceRef": map[string]interface{}{"name": "ratings", "namespace": "data"}}},
+		map[string]interface{}{"virtualService": map[string]interface{}{"virtualServiceARN": "arn:aws:appmesh:..."}},
+	}}}
+	want := []string{"shop/reviews", "data/ratings"}
+	if got := virtualNodeBackends(obj, "shop"); !reflect.DeepEqual(got, want) {
+		t.Errorf("virtualNodeBackends = %v, want %v", got, want)
+	}
+}
+
+var managedListKinds = map[schema.GroupVersionResource]string{
+	gatewayClassesGVR: "GatewayClassList",
+	gatewaysV1GVR:     "GatewayList", gatewaysV1B1GVR: "GatewayList",
+	httpRoutesV1GVR: "HTTPRouteList", httpRoutesV1B1GVR: "HTTPRouteList",
+	grpcRoutesV1GVR: "GRPCRouteList", grpcRoutesV1B1GVR: "GRPCRouteList",
+	refGrantsV1GVR: "ReferenceGrantList", refGrantsV1B1GVR: "ReferenceGrantList",
+	backendTLSPoliciesV1GVR: "BackendTLSPolicyList", backendTLSPoliciesV1A3GVR: "BackendTLSPolicyList",
+	servicesGVR: "ServiceList", deploymentsGVR: "DeploymentList",
+	gkeBackendPolicyGVR: "GCPBackendPolicyList", gkeHealthCheckPolicyGVR: "HealthCheckPolicyList", gkeGatewayPolicyGVR: "GCPGatewayPolicyList",
+	latticeTargetGroupPolicyGVR: "TargetGroupPolicyList", latticeVpcAssociationPolicyGVR: "VpcAssociationPolicyList",
+	latticeIAMAuthPolicyGVR: "IAMAuthPolicyList", latticeAccessLogPolicyGVR: "AccessLogPolicyList",
+	appMeshMeshGVR: "MeshList", appMeshVirtualNodeGVR: "VirtualNodeList", appMeshVirtualServiceGVR: "VirtualServiceList",
+	appMeshVirtualRouterGVR: "VirtualRouterList", appMeshVirtualGatewayGVR: "VirtualGatewayList", appMeshGatewayRouteGVR: "GatewayRouteList",
+}
+
+// newManagedClient creates a fake client. The tracker guesses resources such
+// as "gatewaies" or "meshs" from the kind, so those objects are created with
+// an explicit GVR.
+func newManagedClient(t *testing.T, objs []runtime.Object, tracked map[schema.GroupVersionResource][]*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), managedListKinds, objs...)
+	for gvr, items := range tracked {
+		for _, item := range items {
+			if err := client.Tracker().Create(gvr, item, item.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return client
+}
+
+func TestCheckGKEGatewayStatus(t *testing.T) {
+	class := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "gke-l7-rilb", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": "networking.gke.io/gateway"},
+	})
+	gw := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "internal", map[string]interface{}{
+		"spec": map[string]interface{}{"gatewayClassName": "gke-l7-rilb"},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Programmed", "status": "False", "reason": "Invalid", "message": "no proxy-only subnet"},
+		}},
+	})
+	backend := managedObj("networking.gke.io/v1", "GCPBackendPolicy", "shop", "store", map[string]interface{}{
+		"spec": map[string]interface{}{"targetRef": map[string]interface{}{"kind": "Service", "name": "store"}},
+	})
+	healthCheck := managedObj("networking.gke.io/v1", "HealthCheckPolicy", "shop", "store", map[string]interface{}{
+		"spec": map[string]interface{}{"targetRef": map[string]interface{}{"kind": "Service", "name": "gone"}},
+	})
+	svc := managedObj("v1", "Service", "shop", "store", map[string]interface{}{})
+
+	client := newManagedClient(t, []runtime.Object{svc}, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewayClassesGVR:       {class},
+		gatewaysV1GVR:           {gw},
+		gkeBackendPolicyGVR:     {backend},
+		gkeHealthCheckPolicyGVR: {healthCheck},
+	})
+	tool := &CheckGKEGatewayStatusTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	all := managedFindings(t, tool)
+	for _, want := range []string{
+		"ok GatewayClass gke-l7-rilb (networking.gke.io/gateway) accepted",
+		"critical Gateway infra/internal (class gke-l7-rilb) not programmed",
+		"warning HealthCheckPolicy shop/store targets missing Service shop/gone",
+		"info GKE Gateway policies: 2, 0 not attached",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "GCPBackendPolicy shop/store targets missing") {
+		t.Errorf("policy on an existing Service reported as missing:\n%s", all)
+	}
+}
+
+func TestCheckGKEGatewayStatusNotEnabled(t *testing.T) {
+	client := newManagedClient(t, nil, nil)
+	tool := &CheckGKEGatewayStatusTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+	if all := managedFindings(t, tool); !strings.Contains(all, "warning GKE Gateway controller not enabled") {
+		t.Errorf("expected not-enabled warning, got:\n%s", all)
+	}
+}
+
+func TestCheckVPCLatticeStatus(t *testing.T) {
+	controller := managedObj("apps/v1", "Deployment", latticeControllerNamespace, "gateway-api-controller", map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{"readyReplicas": int64(2)},
+	})
+	class := managedObj("gateway.networking.k8s.io/v1", "GatewayClass", "", "amazon-vpc-lattice", map[string]interface{}{
+		"spec": map[string]interface{}{"controllerName": latticeControllerName},
+	})
+	gw := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "my-network", map[string]interface{}{
+		"spec": map[string]interface{}{"gatewayClassName": "amazon-vpc-lattice"},
+	})
+	pending := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "pending", map[string]interface{}{
+		"spec": map[string]interface{}{"parentRefs": []interface{}{map[string]interface{}{"name": "my-network", "namespace": "infra"}}},
+	})
+	ready := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "ready", map[string]interface{}{
+		"spec": map[string]interface{}{"parentRefs": []interface{}{map[string]interface{}{"name": "my-network", "namespace": "infra"}}},
+	})
+	ready.SetAnnotations(map[string]string{latticeDomainAnnotation: "ready-shop.7d67968.vpc-lattice-svcs.us-west-2.on.aws"})
+	tgp := managedObj("application-networking.k8s.aws/v1alpha1", "TargetGroupPolicy", "shop", "store", map[string]interface{}{
+		"spec": map[string]interface{}{"targetRef": map[string]interface{}{"kind": "Service", "name": "store"}},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Accepted", "status": "False", "reason": "TargetNotFound", "message": "Service not found"},
+		}},
+	})
+
+	client := newManagedClient(t, []runtime.Object{controller, pending, ready}, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewayClassesGVR:           {class},
+		gatewaysV1GVR:               {gw},
+		latticeTargetGroupPolicyGVR: {tgp},
+	})
+	tool := &CheckVPCLatticeStatusTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	all := managedFindings(t, tool)
+	for _, want := range []string{
+		"ok VPC Lattice controller gateway-api-controller: 2/2 replicas ready",
+		"ok Gateway infra/my-network (class amazon-vpc-lattice) programmed",
+		"warning HTTPRoute shop/pending on Lattice Gateway infra/my-network has no Lattice domain",
+		"warning TargetGroupPolicy shop/store -> Service shop/store (not attached)",
+		"warning TargetGroupPolicy shop/store targets missing Service shop/store",
+		"info VPC Lattice policies: 1, 1 not attached",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "shop/ready") {
+		t.Errorf("route with a Lattice domain reported:\n%s", all)
+	}
+}
+
+func TestCheckAppMeshStatus(t *testing.T) {
+	mesh := managedObj("appmesh.k8s.aws/v1beta2", "Mesh", "", "global", map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "MeshActive", "status": "True"},
+		}},
+	})
+	node := managedObj("appmesh.k8s.aws/v1beta2", "VirtualNode", "shop", "frontend", map[string]interface{}{
+		"spec": map[string]interface{}{"backends": []interface{}{
+			map[string]interface{}{"virtualService": map[string]interface{}{"virtualServiceRef": map[string]interface{}{"name": "reviews"}}},
+			map[string]interface{}{"virtualService": map[string]interface{}{"virtualServiceRef": map[string]interface{}{"name": "ratings"}}},
+		}},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "VirtualNodeActive", "status": "False", "reason": "VirtualNodeError", "message": "AccessDeniedException"},
+		}},
+	})
+	vs := managedObj("appmesh.k8s.aws/v1beta2", "VirtualService", "shop", "reviews", map[string]interface{}{})
+
+	client := newManagedClient(t, []runtime.Object{node, vs}, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		appMeshMeshGVR: {mesh},
+	})
+	tool := &CheckAppMeshStatusTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	all := managedFindings(t, tool)
+	for _, want := range []string{
+		"warning App Mesh controller not found",
+		"warning AWS App Mesh reaches end of support on 30 September 2026",
+		"critical App Mesh VirtualNode shop/frontend is not active",
+		"critical VirtualNode shop/frontend backend VirtualService shop/ratings does not exist",
+		"info App Mesh resources: Mesh=1, VirtualNode=1, VirtualService=1, VirtualRouter=0, VirtualGateway=0, GatewayRoute=0",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+	if strings.Contains(all, "shop/reviews does not exist") {
+		t.Errorf("existing VirtualService reported missing:\n%s", all)
+	}
+}
+
+func TestListAppMeshResourcesKind(t *testing.T) {
+	client := newManagedClient(t, nil, nil)
+	tool := &ListAppMeshResourcesTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+	_, err := tool.Run(context.Background(), map[string]interface{}{"kind": "VirtualThing"})
+	if mcpErr, ok := err.(*types.MCPError); !ok || mcpErr.Code != types.ErrCodeInvalidInput {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+}
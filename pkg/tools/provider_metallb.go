@@ -0,0 +1,553 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	metallbL2AdvertisementsGVR  = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "l2advertisements"}
+	metallbBGPAdvertisementsGVR = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "bgpadvertisements"}
+	metallbBGPPeersGVR          = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta2", Resource: "bgppeers"}
+	metallbBGPPeersV1B1GVR      = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "bgppeers"}
+	metallbBFDProfilesGVR       = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "bfdprofiles"}
+	metallbBGPSessionStatesGVR  = schema.GroupVersionResource{Group: "metallb.io", Version: "v1beta1", Resource: "bgpsessionstates"}
+)
+
+const metallbNamespace = "metallb-system"
+
+// metallbPoolAnnotations and metallbIPsAnnotations are read in order; the
+// metallb.universe.tf prefix predates MetalLB 0.14 and is still honoured.
+var (
+	metallbPoolAnnotations = []string{"metallb.io/address-pool", "metallb.universe.tf/address-pool"}
+	metallbIPsAnnotations  = []string{"metallb.io/loadBalancerIPs", "metallb.universe.tf/loadBalancerIPs"}
+)
+
+// metallbKind is a MetalLB CRD; fallback is tried when gvr is not served.
+type metallbKind struct {
+	kind     string
+	gvr      schema.GroupVersionResource
+	fallback schema.GroupVersionResource
+}
+
+var metallbKinds = []metallbKind{
+	{kind: "IPAddressPool", gvr: metallbIPAddressPoolsGVR},
+	{kind: "L2Advertisement", gvr: metallbL2AdvertisementsGVR},
+	{kind: "BGPAdvertisement", gvr: metallbBGPAdvertisementsGVR},
+	{kind: "BGPPeer", gvr: metallbBGPPeersGVR, fallback: metallbBGPPeersV1B1GVR},
+	{kind: "BFDProfile", gvr: metallbBFDProfilesGVR},
+}
+
+// metallbDescribe summarizes the spec of a MetalLB resource.
+func metallbDescribe(kind string, obj map[string]interface{}) string {
+	switch kind {
+	case "IPAddressPool":
+		addrs, _, _ := unstructured.NestedStringSlice(obj, "spec", "addresses")
+		desc := "addresses=" + orDefault(strings.Join(addrs, ","), "none")
+		if auto, found, _ := unstructured.NestedBool(obj, "spec", "autoAssign"); found && !auto {
+			desc += " autoAssign=false"
+		}
+		return desc
+	case "L2Advertisement", "BGPAdvertisement":
+		pools, _, _ := unstructured.NestedStringSlice(obj, "spec", "ipAddressPools")
+		selectors, _, _ := unstructured.NestedSlice(obj, "spec", "ipAddressPoolSelectors")
+		desc := "pools=" + strings.Join(pools, ",")
+		switch {
+		case len(pools) == 0 && len(selectors) == 0:
+			desc = "pools=all"
+		case len(selectors) > 0:
+			desc += fmt.Sprintf(" poolSelectors=%d", len(selectors))
+		}
+		if ifaces, _, _ := unstructured.NestedStringSlice(obj, "spec", "interfaces"); len(ifaces) > 0 {
+			desc += " interfaces=" + strings.Join(ifaces, ",")
+		}
+		return desc
+	case "BGPPeer":
+		addr, _, _ := unstructured.NestedString(obj, "spec", "peerAddress")
+		return fmt.Sprintf("peer=%s peerASN=%v myASN=%v", addr, nestedValue(obj, "spec", "peerASN"), nestedValue(obj, "spec", "myASN"))
+	}
+	return ""
+}
+
+// advertisesPool reports whether an L2 or BGP advertisement announces pool.
+// An advertisement without ipAddressPools or ipAddressPoolSelectors
+// announces every pool.
+func advertisesPool(adv, pool unstructured.Unstructured) bool {
+	names, _, _ := unstructured.NestedStringSlice(adv.Object, "spec", "ipAddressPools")
+	selectors, _, _ := unstructured.NestedSlice(adv.Object, "spec", "ipAddressPoolSelectors")
+	if len(names) == 0 && len(selectors) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name == pool.GetName() {
+			return true
+		}
+	}
+	for _, s := range selectors {
+		sm, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if sel, err := parseLabelSelector(sm, true); err == nil && sel.Matches(labels.Set(pool.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// poolAllowsService reports whether the serviceAllocation of pool lets svc
+// use it. namespaceSelectors are not evaluated and count as a match.
+func poolAllowsService(pool, svc unstructured.Unstructured) bool {
+	alloc, found, _ := unstructured.NestedMap(pool.Object, "spec", "serviceAllocation")
+	if !found {
+		return true
+	}
+	namespaces, _, _ := unstructured.NestedStringSlice(alloc, "namespaces")
+	nsSelectors, _, _ := unstructured.NestedSlice(alloc, "namespaceSelectors")
+	if len(namespaces) > 0 && len(nsSelectors) == 0 {
+		allowed := false
+		for _, ns := range namespaces {
+			allowed = allowed || ns == svc.GetNamespace()
+		}
+		if !allowed {
+			return false
+		}
+	}
+	svcSelectors, _, _ := unstructured.NestedSlice(alloc, "serviceSelectors")
+	if len(svcSelectors) == 0 {
+		return true
+	}
+	for _, s := range svcSelectors {
+		sm, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if sel, err := parseLabelSelector(sm, true); err == nil && sel.Matches(labels.Set(svc.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstAnnotation returns the value of the first of keys set on obj.
+func firstAnnotation(obj unstructured.Unstructured, keys []string) string {
+	annotations := obj.GetAnnotations()
+	for _, k := range keys {
+		if v := strings.TrimSpace(annotations[k]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// metallbHandles reports whether MetalLB allocates addresses for svc: a
+// LoadBalancer Service without a loadBalancerClass, or with a MetalLB one.
+func metallbHandles(svc unstructured.Unstructured) bool {
+	if t, _, _ := unstructured.NestedString(svc.Object, "spec", "type"); t != "LoadBalancer" {
+		return false
+	}
+	class, _, _ := unstructured.NestedString(svc.Object, "spec", "loadBalancerClass")
+	return class == "" || strings.Contains(class, "metallb")
+}
+
+// requestedIPs returns the addresses svc asks for through the MetalLB
+// annotation or the deprecated spec.loadBalancerIP.
+func requestedIPs(svc unstructured.Unstructured) []string {
+	var out []string
+	if v := firstAnnotation(svc, metallbIPsAnnotations); v != "" {
+		for _, ip := range strings.Split(v, ",") {
+			out = append(out, strings.TrimSpace(ip))
+		}
+	}
+	if ip, _, _ := unstructured.NestedString(svc.Object, "spec", "loadBalancerIP"); ip != "" {
+		out = append(out, ip)
+	}
+	return out
+}
+
+// poolContains reports whether ip falls in one of the address ranges of pool.
+func poolContains(pool unstructured.Unstructured, ip netip.Addr) bool {
+	addrs, _, _ := unstructured.NestedStringSlice(pool.Object, "spec", "addresses")
+	for _, a := range addrs {
+		if r, ok := parseAddressRange(a); ok && r.contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingServiceFinding explains why MetalLB has not assigned svc an
+// address: the pool it names is missing, no pool may serve it, the address
+// it requests is outside its pools, or its pools are full.
+func pendingServiceFinding(svc unstructured.Unstructured, pools []unstructured.Unstructured, full map[string]bool) types.DiagnosticFinding {
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityCritical,
+		Category: types.CategoryConnectivity,
+		Code:     types.CodeLBServicePending,
+		Resource: &types.ResourceRef{Kind: "Service", Namespace: svc.GetNamespace(), Name: svc.GetName(), APIVersion: "v1"},
+		Summary:  fmt.Sprintf("LoadBalancer Service %s/%s has no external address", svc.GetNamespace(), svc.GetName()),
+	}
+
+	var candidates []unstructured.Unstructured
+	if name := firstAnnotation(svc, metallbPoolAnnotations); name != "" {
+		for _, p := range pools {
+			if p.GetName() == name {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) == 0 {
+			f.Code = types.CodeLBPoolMissing
+			f.Detail = fmt.Sprintf("the Service requests IPAddressPool %q, which does not exist", name)
+			f.Suggestion = "Create the IPAddressPool in the MetalLB namespace or fix the address-pool annotation."
+			return f
+		}
+	} else {
+		for _, p := range pools {
+			if auto, found, _ := unstructured.NestedBool(p.Object, "spec", "autoAssign"); found && !auto {
+				continue
+			}
+			if poolAllowsService(p, svc) {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) == 0 {
+			f.Detail = fmt.Sprintf("none of the %d IPAddressPools auto-assigns addresses to this Service (autoAssign=false or serviceAllocation excludes it)", len(pools))
+			f.Suggestion = "Name a pool with the metallb.io/address-pool annotation, or add an IPAddressPool with autoAssign enabled whose serviceAllocation matches the Service."
+			return f
+		}
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, p := range candidates {
+		names = append(names, p.GetName())
+	}
+	for _, req := range requestedIPs(svc) {
+		ip, err := netip.ParseAddr(req)
+		if err != nil {
+			f.Detail = fmt.Sprintf("the requested address %q is not a valid IP", req)
+			f.Suggestion = "Fix the metallb.io/loadBalancerIPs annotation or spec.loadBalancerIP."
+			return f
+		}
+		inPool := false
+		for _, p := range candidates {
+			inPool = inPool || poolContains(p, ip)
+		}
+		if !inPool {
+			f.Detail = fmt.Sprintf("the requested address %s is not in IPAddressPool %s", req, strings.Join(names, ", "))
+			f.Suggestion = "Request an address inside the pool, or add the address to an IPAddressPool."
+			return f
+		}
+	}
+
+	exhausted := true
+	for _, p := range candidates {
+		exhausted = exhausted && full[p.GetNamespace()+"/"+p.GetName()]
+	}
+	if exhausted {
+		f.Detail = fmt.Sprintf("IPAddressPool %s has no free address", strings.Join(names, ", "))
+		f.Suggestion = "Add addresses to the pool, or delete LoadBalancer Services that are no longer needed."
+		return f
+	}
+	f.Detail = fmt.Sprintf("candidate IPAddressPools %s have free addresses", strings.Join(names, ", "))
+	f.Suggestion = fmt.Sprintf("Check the events of the Service (kubectl describe svc -n %s %s) and the MetalLB controller logs.", svc.GetNamespace(), svc.GetName())
+	return f
+}
+
+// --- list_metallb_resources ---
+
+type ListMetalLBResourcesTool struct{ BaseTool }
+
+func (t *ListMetalLBResourcesTool) Name() string { return "list_metallb_resources" }
+func (t *ListMetalLBResourcesTool) Description() string {
+	return "List MetalLB resources (IPAddressPools, L2Advertisements, BGPAdvertisements, BGPPeers, BFDProfiles) with their addresses, advertised pools and peers"
+}
+func (t *ListMetalLBResourcesTool) InputSchema() map[string]interface{} {
+	return withSelectors(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace (empty for all namespaces; MetalLB usually runs in metallb-system)",
+			},
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "Only list this kind, e.g. IPAddressPool (default all)",
+			},
+		},
+	})
+}
+
+func (t *ListMetalLBResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	kind := getStringArg(args, "kind", "")
+	sel := getSelectorArgs(args)
+
+	var kinds []metallbKind
+	names := make([]string, 0, len(metallbKinds))
+	for _, k := range metallbKinds {
+		names = append(names, k.kind)
+		if kind == "" || strings.EqualFold(kind, k.kind) {
+			kinds = append(kinds, k)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unknown MetalLB kind %q", kind),
+			Detail:  "expected one of " + strings.Join(names, ", "),
+		}
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 10)
+	for _, k := range kinds {
+		var list *unstructured.UnstructuredList
+		var err error
+		if k.fallback.Resource != "" {
+			list, err = t.listResourceSelectedWithFallback(ctx, k.gvr, k.fallback, ns, sel)
+		} else {
+			list, err = t.listResourceSelected(ctx, k.gvr, ns, sel)
+		}
+		if serr := selectorError(t.Name(), err); serr != nil {
+			return nil, serr
+		}
+		if err != nil {
+			continue
+		}
+		for _, item := range list.Items {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryConnectivity,
+				Resource: &types.ResourceRef{Kind: k.kind, Namespace: item.GetNamespace(), Name: item.GetName(), APIVersion: item.GetAPIVersion()},
+				Summary:  fmt.Sprintf("MetalLB %s %s", k.kind, qualifiedName(item.GetNamespace(), item.GetName())),
+				Detail:   metallbDescribe(k.kind, item.Object),
+			})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  "No MetalLB resources found",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "metallb"), nil
+}
+
+// --- check_metallb_status ---
+
+type CheckMetalLBStatusTool struct{ BaseTool }
+
+func (t *CheckMetalLBStatusTool) Name() string { return "check_metallb_status" }
+func (t *CheckMetalLBStatusTool) Description() string {
+	return "Check MetalLB: controller and speaker readiness, IPAddressPools no L2/BGP advertisement announces, BGP sessions that are down, and LoadBalancer Services stuck with a pending EXTERNAL-IP with the reason (missing pool, exhausted pool, requested IP outside the pools)"
+}
+func (t *CheckMetalLBStatusTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Services to check (empty for cluster-wide)",
+			},
+			"metallb_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace MetalLB is installed in (default metallb-system)",
+			},
+		},
+	}
+}
+
+func (t *CheckMetalLBStatusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	metallbNs := getStringArg(args, "metallb_namespace", metallbNamespace)
+
+	findings := t.componentFindings(ctx, metallbNs)
+
+	var pools []unstructured.Unstructured
+	if list, err := t.listResource(ctx, metallbIPAddressPoolsGVR, ""); err == nil {
+		pools = list.Items
+	}
+	var advertisements []unstructured.Unstructured
+	for _, gvr := range []schema.GroupVersionResource{metallbL2AdvertisementsGVR, metallbBGPAdvertisementsGVR} {
+		if list, err := t.listResource(ctx, gvr, ""); err == nil {
+			advertisements = append(advertisements, list.Items...)
+		}
+	}
+	var services []unstructured.Unstructured
+	if list, err := t.listResource(ctx, servicesGVR, ""); err == nil {
+		services = list.Items
+	}
+
+	if len(pools) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeLBPoolMissing,
+			Summary:    "No MetalLB IPAddressPool exists",
+			Suggestion: "Create an IPAddressPool and an L2Advertisement or BGPAdvertisement; MetalLB assigns no address without one.",
+		})
+	}
+
+	full := make(map[string]bool)
+	used := make(map[string]float64)
+	for _, u := range metallbPoolUsage(pools, services) {
+		key := u.ref.Namespace + "/" + u.ref.Name
+		used[key] = u.used
+		full[key] = u.used >= u.capacity
+	}
+	for _, pool := range pools {
+		key := pool.GetNamespace() + "/" + pool.GetName()
+		advertised := false
+		for _, adv := range advertisements {
+			advertised = advertised || advertisesPool(adv, pool)
+		}
+		if advertised {
+			continue
+		}
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeLBPoolNotAdvertised,
+			Resource:   &types.ResourceRef{Kind: "IPAddressPool", Namespace: pool.GetNamespace(), Name: pool.GetName(), APIVersion: "metallb.io/v1beta1"},
+			Summary:    fmt.Sprintf("IPAddressPool %s is not announced by any L2Advertisement or BGPAdvertisement", key),
+			Suggestion: "Create an L2Advertisement or BGPAdvertisement that lists the pool; addresses from it are assigned but unreachable from outside the cluster.",
+		}
+		if used[key] > 0 {
+			f.Severity = types.SeverityCritical
+			f.Detail = fmt.Sprintf("%.0f Service address(es) from this pool are not reachable", used[key])
+		}
+		findings = append(findings, f)
+	}
+
+	pending := 0
+	for _, svc := range services {
+		if ns != "" && svc.GetNamespace() != ns {
+			continue
+		}
+		if !metallbHandles(svc) || len(loadBalancerAddresses(svc.Object)) > 0 {
+			continue
+		}
+		pending++
+		findings = append(findings, pendingServiceFinding(svc, pools, full))
+	}
+
+	findings = append(findings, t.bgpSessionFindings(ctx)...)
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("MetalLB: %d IPAddressPool(s), %d advertisement(s), %d pending LoadBalancer Service(s)", len(pools), len(advertisements), pending),
+	})
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "metallb"), nil
+}
+
+// componentFindings reports the readiness of the MetalLB controller
+// Deployment and speaker DaemonSet in ns, matched by name as the manifests
+// and the helm chart prefix them differently.
+func (t *CheckMetalLBStatusTool) componentFindings(ctx context.Context, ns string) []types.DiagnosticFinding {
+	type component struct {
+		kind, name     string
+		ready, desired int64
+	}
+	var found []component
+	if list, err := t.listResource(ctx, deploymentsGVR, ns); err == nil {
+		for _, d := range list.Items {
+			if !strings.Contains(d.GetName(), "controller") {
+				continue
+			}
+			desired, ok, _ := unstructured.NestedInt64(d.Object, "spec", "replicas")
+			if !ok {
+				desired = 1
+			}
+			ready, _, _ := unstructured.NestedInt64(d.Object, "status", "readyReplicas")
+			found = append(found, component{"Deployment", d.GetName(), ready, desired})
+		}
+	}
+	speakers := 0
+	if list, err := t.listResource(ctx, daemonsetsGVR, ns); err == nil {
+		for _, ds := range list.Items {
+			if !strings.Contains(ds.GetName(), "speaker") {
+				continue
+			}
+			desired, _, _ := unstructured.NestedInt64(ds.Object, "status", "desiredNumberScheduled")
+			ready, _, _ := unstructured.NestedInt64(ds.Object, "status", "numberReady")
+			found = append(found, component{"DaemonSet", ds.GetName(), ready, desired})
+			speakers++
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	if speakers == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeLBComponentsNotReady,
+			Summary:    fmt.Sprintf("MetalLB speaker DaemonSet not found in namespace %s", ns),
+			Suggestion: "Without speakers no address is announced. Pass metallb_namespace if MetalLB is installed elsewhere.",
+		})
+	}
+	for _, c := range found {
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Resource: &types.ResourceRef{Kind: c.kind, Namespace: ns, Name: c.name, APIVersion: "apps/v1"},
+			Summary:  fmt.Sprintf("MetalLB %s %s: %d/%d ready", strings.ToLower(c.kind), c.name, c.ready, c.desired),
+		}
+		switch {
+		case c.ready == 0:
+			f.Severity = types.SeverityCritical
+			f.Code = types.CodeLBComponentsNotReady
+			f.Suggestion = fmt.Sprintf("Check the pods and logs of %s/%s.", ns, c.name)
+		case c.ready < c.desired:
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeLBComponentsNotReady
+			if c.kind == "DaemonSet" {
+				f.Detail = "nodes without a ready speaker do not announce addresses in L2 mode and drop their BGP sessions"
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// bgpSessionFindings reports BGP sessions that are not established, from the
+// BGPSessionState resources MetalLB 0.15 and later publish per node and peer.
+func (t *CheckMetalLBStatusTool) bgpSessionFindings(ctx context.Context) []types.DiagnosticFinding {
+	list, err := t.listResource(ctx, metallbBGPSessionStatesGVR, "")
+	if err != nil {
+		return nil
+	}
+	var down []string
+	for _, s := range list.Items {
+		state, _, _ := unstructured.NestedString(s.Object, "status", "bgpStatus")
+		if state == "Established" {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(s.Object, "status", "node")
+		peer, _, _ := unstructured.NestedString(s.Object, "status", "peer")
+		down = append(down, fmt.Sprintf("%s->%s (%s)", node, peer, orDefault(state, "unknown")))
+	}
+	if len(down) == 0 {
+		return nil
+	}
+	sort.Strings(down)
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryConnectivity,
+		Code:       types.CodeLBBGPSessionDown,
+		Summary:    fmt.Sprintf("%d of %d MetalLB BGP sessions are not established", len(down), len(list.Items)),
+		Detail:     strings.Join(down, ", "),
+		Suggestion: "Check the BGPPeer address, ASNs and password against the router, and that the speaker nodes can reach it on TCP 179.",
+	}}
+}
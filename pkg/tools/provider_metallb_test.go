@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestAdvertisesPool(t *testing.T) {
+	pool := ipamObj("metallb.io/v1beta1", "IPAddressPool", metallbNamespace, "public", map[string]interface{}{})
+	pool.SetLabels(map[string]string{"tier": "edge"})
+	cases := map[string]struct {
+		spec map[string]interface{}
+		want bool
+	}{
+		"all pools":      {map[string]interface{}{}, true},
+		"by name":        {map[string]interface{}{"ipAddressPools": []interface{}{"public"}}, true},
+		"other name":     {map[string]interface{}{"ipAddressPools": []interface{}{"private"}}, false},
+		"by selector":    {map[string]interface{}{"ipAddressPoolSelectors": []interface{}{map[string]interface{}{"matchLabels": map[string]interface{}{"tier": "edge"}}}}, true},
+		"other selector": {map[string]interface{}{"ipAddressPoolSelectors": []interface{}{map[string]interface{}{"matchLabels": map[string]interface{}{"tier": "core"}}}}, false},
+	}
+	for name, c := range cases {
+		adv := ipamObj("metallb.io/v1beta1", "L2Advertisement", metallbNamespace, "l2", map[string]interface{}{"spec": c.spec})
+		if got := advertisesPool(*adv, *pool); got != c.want {
+			t.Errorf("%s: advertisesPool = %v, want %v", name, got, c.want)
+		}
+	}
+}
+
+func metallbService(name string, annotations map[string]string, ingressIP string) *unstructured.Unstructured {
+	svc := ipamObj("v1", "Service", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{"type": "LoadBalancer"},
+	})
+	if ingressIP != "" {
+		svc.Object["status"] = map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{map[string]interface{}{"ip": ingressIP}}}}
+	}
+	svc.SetAnnotations(annotations)
+	return svc
+}
+
+func TestCheckMetalLBStatus(t *testing.T) {
+	objs := []runtime.Object{
+		ipamObj("apps/v1", "Deployment", metallbNamespace, "metallb-controller", map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(1)},
+			"status": map[string]interface{}{"readyReplicas": int64(1)},
+		}),
+		ipamObj("apps/v1", "DaemonSet", metallbNamespace, "metallb-speaker", map[string]interface{}{
+			"status": map[string]interface{}{"desiredNumberScheduled": int64(3), "numberReady": int64(2)},
+		}),
+		metallbService("web", nil, "192.168.1.240"),
+		metallbService("api", nil, "192.168.1.241"),
+		metallbService("full", nil, ""),
+		metallbService("typo", map[string]string{"metallb.universe.tf/address-pool": "pubilc"}, ""),
+		metallbService("outside", map[string]string{"metallb.io/address-pool": "private", "metallb.io/loadBalancerIPs": "10.0.0.9"}, ""),
+	}
+	tracked := []struct {
+		gvr schema.GroupVersionResource
+		obj *unstructured.Unstructured
+	}{
+		{metallbIPAddressPoolsGVR, ipamObj("metallb.io/v1beta1", "IPAddressPool", metallbNamespace, "public", map[string]interface{}{
+			"spec": map[string]interface{}{"addresses": []interface{}{"192.168.1.240/31"}},
+		})},
+		{metallbIPAddressPoolsGVR, ipamObj("metallb.io/v1beta1", "IPAddressPool", metallbNamespace, "private", map[string]interface{}{
+			"spec": map[string]interface{}{"addresses": []interface{}{"10.10.0.0/24"}, "autoAssign": false},
+		})},
+		{metallbL2AdvertisementsGVR, ipamObj("metallb.io/v1beta1", "L2Advertisement", metallbNamespace, "l2", map[string]interface{}{
+			"spec": map[string]interface{}{"ipAddressPools": []interface{}{"private"}},
+		})},
+		{metallbBGPSessionStatesGVR, ipamObj("metallb.io/v1beta1", "BGPSessionState", metallbNamespace, "node-a-peer", map[string]interface{}{
+			"status": map[string]interface{}{"node": "node-a", "peer": "10.0.0.1", "bgpStatus": "Active"},
+		})},
+	}
+
+	listKinds := map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList", daemonsetsGVR: "DaemonSetList", servicesGVR: "ServiceList",
+		metallbIPAddressPoolsGVR: "IPAddressPoolList", metallbL2AdvertisementsGVR: "L2AdvertisementList",
+		metallbBGPAdvertisementsGVR: "BGPAdvertisementList", metallbBGPPeersGVR: "BGPPeerList",
+		metallbBGPPeersV1B1GVR: "BGPPeerList", metallbBFDProfilesGVR: "BFDProfileList",
+		metallbBGPSessionStatesGVR: "BGPSessionStateList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	for _, tr := range tracked {
+		if err := client.Tracker().Create(tr.gvr, tr.obj, tr.obj.GetNamespace()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+
+	resp, err := (&CheckMetalLBStatusTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byResource := make(map[string]types.DiagnosticFinding)
+	var codes []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		if f.Resource != nil {
+			byResource[f.Resource.Kind+"/"+f.Resource.Name] = f
+		}
+		codes = append(codes, string(f.Code))
+	}
+
+	if f := byResource["Service/full"]; f.Code != types.CodeLBServicePending || !strings.Contains(f.Detail, "public has no free address") {
+		t.Errorf("unexpected finding for the Service of the exhausted pool %+v", f)
+	}
+	if f := byResource["Service/typo"]; f.Code != types.CodeLBPoolMissing || !strings.Contains(f.Detail, `"pubilc"`) {
+		t.Errorf("unexpected finding for the Service naming a missing pool %+v", f)
+	}
+	if f := byResource["Service/outside"]; f.Code != types.CodeLBServicePending || !strings.Contains(f.Detail, "10.0.0.9 is not in IPAddressPool private") {
+		t.Errorf("unexpected finding for the Service requesting an address outside its pool %+v", f)
+	}
+	if _, ok := byResource["Service/web"]; ok {
+		t.Error("a Service with an address should not be reported")
+	}
+	if f := byResource["IPAddressPool/public"]; f.Code != types.CodeLBPoolNotAdvertised || f.Severity != types.SeverityCritical {
+		t.Errorf("the unannounced pool with assigned addresses should be critical, got %+v", f)
+	}
+	if _, ok := byResource["IPAddressPool/private"]; ok {
+		t.Error("the private pool is announced by the L2Advertisement")
+	}
+	if f := byResource["DaemonSet/metallb-speaker"]; f.Code != types.CodeLBComponentsNotReady || f.Severity != types.SeverityWarning {
+		t.Errorf("unexpected speaker finding %+v", f)
+	}
+	if !strings.Contains(strings.Join(codes, ","), string(types.CodeLBBGPSessionDown)) {
+		t.Errorf("expected a BGP session finding, got codes %v", codes)
+	}
+
+	list, err := (&ListMetalLBResourcesTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{"kind": "ipaddresspool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(list.Data.(*types.ToolResult).Findings); n != 2 {
+		t.Errorf("expected 2 IPAddressPools, got %d", n)
+	}
+	if _, err := (&ListMetalLBResourcesTool{BaseTool: base}).Run(context.Background(), map[string]interface{}{"kind": "Pool"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
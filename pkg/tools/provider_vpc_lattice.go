@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	latticeTargetGroupPolicyGVR    = schema.GroupVersionResource{Group: "application-networking.k8s.aws", Version: "v1alpha1", Resource: "targetgrouppolicies"}
+	latticeVpcAssociationPolicyGVR = schema.GroupVersionResource{Group: "application-networking.k8s.aws", Version: "v1alpha1", Resource: "vpcassociationpolicies"}
+	latticeIAMAuthPolicyGVR        = schema.GroupVersionResource{Group: "application-networking.k8s.aws", Version: "v1alpha1", Resource: "iamauthpolicies"}
+	latticeAccessLogPolicyGVR      = schema.GroupVersionResource{Group: "application-networking.k8s.aws", Version: "v1alpha1", Resource: "accesslogpolicies"}
+	latticePolicyKinds             = []managedPolicyKind{
+		{kind: "TargetGroupPolicy", gvr: latticeTargetGroupPolicyGVR},
+		{kind: "VpcAssociationPolicy", gvr: latticeVpcAssociationPolicyGVR},
+		{kind: "IAMAuthPolicy", gvr: latticeIAMAuthPolicyGVR},
+		{kind: "AccessLogPolicy", gvr: latticeAccessLogPolicyGVR},
+	}
+)
+
+const (
+	latticeControllerName      = "application-networking.k8s.aws/gateway-api-controller"
+	latticeControllerNamespace = "aws-application-networking-system"
+	// latticeDomainAnnotation is set on routes once VPC Lattice has created the service.
+	latticeDomainAnnotation = "application-networking.k8s.aws/lattice-assigned-domain-name"
+)
+
+// --- list_vpc_lattice_policies ---
+
+type ListVPCLatticePoliciesTool struct{ BaseTool }
+
+func (t *ListVPCLatticePoliciesTool) Name() string { return "list_vpc_lattice_policies" }
+func (t *ListVPCLatticePoliciesTool) Description() string {
+	return "List AWS VPC Lattice policies (TargetGroupPolicy, VpcAssociationPolicy, IAMAuthPolicy, AccessLogPolicy) with their targets and status"
+}
+func (t *ListVPCLatticePoliciesTool) InputSchema() map[string]interface{} {
+	return withSelectors(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace (empty for all namespaces)",
+			},
+		},
+	})
+}
+
+func (t *ListVPCLatticePoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	findings, err := t.managedPolicyFindings(ctx, t.Name(), latticePolicyKinds, ns, getSelectorArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  "No VPC Lattice policies found",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "vpc-lattice"), nil
+}
+
+// --- check_vpc_lattice_status ---
+
+type CheckVPCLatticeStatusTool struct{ BaseTool }
+
+func (t *CheckVPCLatticeStatusTool) Name() string { return "check_vpc_lattice_status" }
+func (t *CheckVPCLatticeStatusTool) Description() string {
+	return "Check the AWS Gateway API Controller for VPC Lattice: controller readiness, its GatewayClass and Gateways (service networks), routes without a Lattice domain, and policies that are not attached or target missing resources"
+}
+func (t *CheckVPCLatticeStatusTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to check (empty for cluster-wide)",
+			},
+		},
+	}
+}
+
+func (t *CheckVPCLatticeStatusTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+
+	findings := t.controllerDeploymentFindings(ctx, latticeControllerNamespace, "VPC Lattice", types.CategoryRouting,
+		"Install the AWS Gateway API Controller (helm chart aws-gateway-controller-chart) in "+latticeControllerNamespace+".")
+
+	// Lattice Gateways map to service networks and get no address.
+	gateways, classes := t.managedGatewayFindings(ctx, latticeControllerName, ns, false, func(string) string {
+		return "Check the controller logs: the service network must exist or be creatable, and the controller's IAM role needs vpc-lattice permissions."
+	})
+	findings = append(findings, gateways...)
+	if classes == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeCloudControllerNotEnabled,
+			Summary:    "No GatewayClass uses the VPC Lattice controller (" + latticeControllerName + ")",
+			Suggestion: "Create the amazon-vpc-lattice GatewayClass shipped with the controller.",
+		})
+	} else {
+		findings = append(findings, t.latticeRouteFindings(ctx, ns)...)
+	}
+
+	policies, err := t.managedPolicyFindings(ctx, t.Name(), latticePolicyKinds, ns, listSelector{})
+	if err != nil {
+		return nil, err
+	}
+	unattached := 0
+	for _, p := range policies {
+		if p.Severity == types.SeverityWarning {
+			findings = append(findings, p)
+			unattached++
+		}
+	}
+	findings = append(findings, t.missingPolicyTargets(ctx, latticePolicyKinds, ns, types.CategoryPolicy)...)
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("VPC Lattice policies: %d, %d not attached", len(policies), unattached),
+	})
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "vpc-lattice"), nil
+}
+
+// latticeRouteFindings reports the routes attached to a VPC Lattice Gateway
+// that have no Lattice-assigned domain: their Lattice service was not created.
+func (t *CheckVPCLatticeStatusTool) latticeRouteFindings(ctx context.Context, ns string) []types.DiagnosticFinding {
+	classes, err := t.listResource(ctx, gatewayClassesGVR, "")
+	if err != nil {
+		return nil
+	}
+	latticeClasses := make(map[string]bool)
+	for _, gc := range classes.Items {
+		if c, _, _ := unstructured.NestedString(gc.Object, "spec", "controllerName"); c == latticeControllerName {
+			latticeClasses[gc.GetName()] = true
+		}
+	}
+	gateways, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, "")
+	if err != nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, route := range t.listRoutes(ctx) {
+		if ns != "" && route.namespace != ns {
+			continue
+		}
+		for _, gw := range gateways.Items {
+			class, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+			if !latticeClasses[class] || !routeAttachedToGateway(route, gw.GetNamespace(), gw.GetName()) {
+				continue
+			}
+			annotations, _, _ := unstructured.NestedStringMap(route.obj, "metadata", "annotations")
+			if annotations[latticeDomainAnnotation] != "" {
+				break
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeCloudLatticeServiceMissing,
+				Resource:   &types.ResourceRef{Kind: route.kind, Namespace: route.namespace, Name: route.name, APIVersion: "gateway.networking.k8s.io/v1"},
+				Summary:    fmt.Sprintf("%s %s/%s on Lattice Gateway %s/%s has no Lattice domain", route.kind, route.namespace, route.name, gw.GetNamespace(), gw.GetName()),
+				Detail:     "annotation " + latticeDomainAnnotation + " is not set",
+				Suggestion: "The Lattice service was not created: check the route's status conditions and the controller logs; backends must be Services or ServiceImports.",
+			})
+			break
+		}
+	}
+	return findings
+}
@@ -0,0 +1,369 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// overloadPatterns match the log lines of a proxy shedding load: Envoy
+// overload manager actions and its "envoy overloaded" local reply, and
+// linkerd-proxy load shedding and fail-fast.
+var overloadPatterns = regexp.MustCompile(`(?i)(overload|load.?shed|shrink.?heap|stop.?accepting|fail-fast)`)
+
+const (
+	// Proxy memory use above these fractions of the limit is a warning and
+	// critical; CPU use at the limit means the container is throttled.
+	proxyMemoryWarning  = 0.8
+	proxyMemoryCritical = 0.9
+	proxyCPULimit       = 0.9
+	// A proxy throttled in more than this fraction of CFS periods adds
+	// latency to every request it handles.
+	proxyThrottledRatio = 0.25
+)
+
+// proxyUsage is the measured state of one proxy container.
+type proxyUsage struct {
+	pod, container       string
+	cpuLimit, memLimit   int64 // millicores, bytes; 0 = no limit
+	cpuUsage, memUsage   int64 // millicores, bytes; -1 = not measured
+	throttled            float64
+	oomKilled            bool
+	restarts             int32
+	overloadLines        []string
+	errorLines, logLines int // 503/timeout lines, lines read
+}
+
+// proxyContainerSpec returns the spec of the named container, which may be a
+// native sidecar among the init containers.
+func proxyContainerSpec(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == name {
+			return &pod.Spec.InitContainers[i]
+		}
+	}
+	return nil
+}
+
+// proxyContainerStatus returns the status of the named container.
+func proxyContainerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name == name {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// podContainerUsage reads the container usage of PodMetrics objects, keyed
+// by pod/container, in millicores and bytes.
+func podContainerUsage(items []unstructured.Unstructured) map[string][2]int64 {
+	out := make(map[string][2]int64)
+	for _, item := range items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, c := range containers {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := cm["name"].(string)
+			cpu, _, _ := unstructured.NestedString(cm, "usage", "cpu")
+			mem, _, _ := unstructured.NestedString(cm, "usage", "memory")
+			cq, err1 := resource.ParseQuantity(cpu)
+			mq, err2 := resource.ParseQuantity(mem)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			out[item.GetName()+"/"+name] = [2]int64{cq.MilliValue(), mq.Value()}
+		}
+	}
+	return out
+}
+
+// scanProxyLogs records the overload lines of a proxy's logs, and its 503
+// and timeout lines: access log entries with those statuses and the lines
+// analyze_log_errors reports as upstream issues or timeouts.
+func (u *proxyUsage) scanProxyLogs(logs string) {
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		u.logLines++
+		if overloadPatterns.MatchString(line) {
+			u.overloadLines = append(u.overloadLines, line)
+		}
+		if entry, ok := parseEnvoyAccessLog(line); ok {
+			if entry.code == 503 || entry.code == 504 {
+				u.errorLines++
+			}
+			continue
+		}
+		switch {
+		case strings.Contains(line, " 503 ") || strings.Contains(line, " 504 "):
+			u.errorLines++
+		case errorPatterns.MatchString(line):
+			if cat := logErrorCategory(line); cat == "upstream_issues" || cat == "timeout" {
+				u.errorLines++
+			}
+		}
+	}
+}
+
+func formatBytes(b int64) string {
+	return resource.NewQuantity(b, resource.BinarySI).String()
+}
+
+// proxyFindings grades one proxy container. Resource exhaustion in a proxy
+// whose logs show 503s or timeouts is critical: the proxy, not the
+// application, is the likely cause of those errors.
+func proxyFindings(ns string, u proxyUsage) []types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: "Pod", Namespace: ns, Name: u.pod}
+	name := fmt.Sprintf("%s in %s/%s", u.container, ns, u.pod)
+	correlation := ""
+	if u.errorLines > 0 {
+		correlation = fmt.Sprintf("; its last %d log lines have %d 503/timeout lines, likely caused by this", u.logLines, u.errorLines)
+	}
+	grade := func(severity string) string {
+		if u.errorLines > 0 {
+			return types.SeverityCritical
+		}
+		return severity
+	}
+
+	var findings []types.DiagnosticFinding
+	if u.oomKilled {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryMesh,
+			Code:       types.CodeMeshProxyOOMKilled,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s was OOMKilled (%d restarts)%s", name, u.restarts, correlation),
+			Detail:     fmt.Sprintf("memory limit=%s", formatBytes(u.memLimit)),
+			Suggestion: "Raise the proxy memory limit (e.g. the sidecar.istio.io/proxyMemoryLimit or config.linkerd.io/proxy-memory-limit annotation), or reduce the configuration pushed to it with a Sidecar resource.",
+		})
+	}
+	if u.memLimit > 0 && u.memUsage >= 0 {
+		ratio := float64(u.memUsage) / float64(u.memLimit)
+		severity := ""
+		switch {
+		case ratio >= proxyMemoryCritical:
+			severity = types.SeverityCritical
+		case ratio >= proxyMemoryWarning:
+			severity = grade(types.SeverityWarning)
+		}
+		if severity != "" {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   severity,
+				Category:   types.CategoryMesh,
+				Code:       types.CodeMeshProxyMemoryPressure,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s uses %.0f%% of its memory limit%s", name, ratio*100, correlation),
+				Detail:     fmt.Sprintf("usage=%s limit=%s", formatBytes(u.memUsage), formatBytes(u.memLimit)),
+				Suggestion: "Raise the proxy memory limit before it is OOMKilled, or scope the proxy configuration with a Sidecar resource so it holds fewer clusters and listeners.",
+			})
+		}
+	}
+	cpuAtLimit := u.cpuLimit > 0 && u.cpuUsage >= 0 && float64(u.cpuUsage)/float64(u.cpuLimit) >= proxyCPULimit
+	if cpuAtLimit || u.throttled >= proxyThrottledRatio {
+		var detail []string
+		if u.cpuLimit > 0 && u.cpuUsage >= 0 {
+			detail = append(detail, fmt.Sprintf("usage=%dm limit=%dm", u.cpuUsage, u.cpuLimit))
+		}
+		summary := fmt.Sprintf("%s runs at its CPU limit", name)
+		if u.throttled > 0 {
+			summary = fmt.Sprintf("%s is CPU throttled in %.0f%% of periods", name, u.throttled*100)
+			detail = append(detail, fmt.Sprintf("container_cpu_cfs_throttled_periods_total / container_cpu_cfs_periods_total = %.2f", u.throttled))
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   grade(types.SeverityWarning),
+			Category:   types.CategoryMesh,
+			Code:       types.CodeMeshProxyCPUThrottled,
+			Resource:   ref,
+			Summary:    summary + correlation,
+			Detail:     strings.Join(detail, "\n"),
+			Suggestion: "A throttled proxy adds latency and timeouts to every request: raise or remove its CPU limit (e.g. the sidecar.istio.io/proxyCPULimit annotation) or add replicas.",
+		})
+	}
+	if len(u.overloadLines) > 0 {
+		detail := u.overloadLines
+		if len(detail) > maxErrorLines {
+			detail = detail[:maxErrorLines]
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   grade(types.SeverityWarning),
+			Category:   types.CategoryMesh,
+			Code:       types.CodeMeshProxyOverloaded,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s logged %d overload or load shedding lines%s", name, len(u.overloadLines), correlation),
+			Detail:     strings.Join(detail, "\n"),
+			Suggestion: "The proxy rejects requests to protect itself: give it more memory and CPU, or spread the load over more replicas.",
+		})
+	}
+	return findings
+}
+
+// --- check_proxy_resources ---
+
+// CheckProxyResourcesTool looks for Envoy, istio-proxy and linkerd-proxy
+// containers running out of CPU or memory.
+type CheckProxyResourcesTool struct {
+	BaseTool
+	// Prometheus, when set, supplies CPU throttling from cAdvisor metrics.
+	Prometheus *prometheus.Client
+}
+
+func (t *CheckProxyResourcesTool) Name() string { return "check_proxy_resources" }
+func (t *CheckProxyResourcesTool) Description() string {
+	return "Check Envoy, istio-proxy and linkerd-proxy containers for memory near their limit, OOMKills, CPU throttling and overload manager or load shedding log lines, and correlate them with the 503 and timeout lines of the proxy's logs"
+}
+func (t *CheckProxyResourcesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace":      map[string]interface{}{"type": "string", "description": "Kubernetes namespace (empty for all namespaces)"},
+			"label_selector": map[string]interface{}{"type": "string", "description": "Label selector of the pods to check (e.g. app=checkout)"},
+			"tail":           map[string]interface{}{"type": "number", "description": "Log lines to read per proxy (default 300, 0 to skip logs)"},
+			"max_pods":       map[string]interface{}{"type": "number", "description": "Maximum proxy pods to check (default 50)"},
+		},
+	}
+}
+
+func (t *CheckProxyResourcesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	tail := getIntArg(args, "tail", 300)
+	maxPods := getIntArg(args, "max_pods", 50)
+	if maxPods <= 0 {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "max_pods must be positive"}
+	}
+
+	pods, err := t.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: getStringArg(args, "label_selector", "")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	var proxies []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && findProxyContainer(pod) != "" {
+			proxies = append(proxies, pod)
+		}
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].Namespace+"/"+proxies[i].Name < proxies[j].Namespace+"/"+proxies[j].Name
+	})
+
+	var findings []types.DiagnosticFinding
+	if len(proxies) > maxPods {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryMesh,
+			Summary:    fmt.Sprintf("Checked the first %d of %d proxy pods", maxPods, len(proxies)),
+			Suggestion: "Narrow the check with namespace or label_selector, or raise max_pods.",
+		})
+		proxies = proxies[:maxPods]
+	}
+	if len(proxies) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryMesh,
+			Summary:  "No running pods with an istio-proxy, envoy or linkerd-proxy container",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	var usage map[string][2]int64
+	if metrics, err := t.Clients.Dynamic.Resource(podMetricsGVR).Namespace(ns).List(ctx, metav1.ListOptions{}); err == nil {
+		usage = podContainerUsage(metrics.Items)
+	} else {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryMesh,
+			Summary:    "Pod metrics (metrics.k8s.io) are unavailable; CPU and memory usage were not checked",
+			Detail:     err.Error(),
+			Suggestion: "Install metrics-server, or grant the server list on pods.metrics.k8s.io.",
+		})
+	}
+	throttled := t.throttling(ctx, ns)
+
+	issues := 0
+	for _, pod := range proxies {
+		container := findProxyContainer(pod)
+		u := proxyUsage{pod: pod.Name, container: container, cpuUsage: -1, memUsage: -1}
+		if spec := proxyContainerSpec(pod, container); spec != nil {
+			u.cpuLimit = spec.Resources.Limits.Cpu().MilliValue()
+			u.memLimit = spec.Resources.Limits.Memory().Value()
+		}
+		if status := proxyContainerStatus(pod, container); status != nil {
+			u.restarts = status.RestartCount
+			if term := status.LastTerminationState.Terminated; term != nil && term.Reason == "OOMKilled" {
+				u.oomKilled = true
+			}
+		}
+		if m, ok := usage[pod.Name+"/"+container]; ok {
+			u.cpuUsage, u.memUsage = m[0], m[1]
+		}
+		u.throttled = throttled[pod.Namespace+"/"+pod.Name+"/"+container]
+		if tail > 0 {
+			if lr, err := getPodLogs(ctx, t.Clients, pod.Namespace, pod.Name, container, int64(tail), ""); err == nil {
+				u.scanProxyLogs(lr.logs)
+			}
+		}
+		podFindings := proxyFindings(pod.Namespace, u)
+		if len(podFindings) > 0 {
+			issues++
+		}
+		findings = append(findings, podFindings...)
+	}
+
+	if issues == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryMesh,
+			Summary:  fmt.Sprintf("%d proxy containers show no resource pressure or overload", len(proxies)),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// throttling returns the fraction of CFS periods each proxy container was
+// throttled in over the last 5 minutes, keyed by namespace/pod/container,
+// or nil without Prometheus.
+func (t *CheckProxyResourcesTool) throttling(ctx context.Context, ns string) map[string]float64 {
+	if t.Prometheus == nil {
+		return nil
+	}
+	matchers := []string{`container=~"` + strings.Join(proxyContainerNames, "|") + `"`}
+	if ns != "" {
+		matchers = append(matchers, prometheus.Equal("namespace", ns))
+	}
+	sel := t.Prometheus.Selector(matchers...)
+	samples, err := t.Prometheus.Query(ctx, fmt.Sprintf(
+		`sum by (namespace, pod, container) (rate(container_cpu_cfs_throttled_periods_total%s[5m])) / sum by (namespace, pod, container) (rate(container_cpu_cfs_periods_total%s[5m]))`, sel, sel))
+	if err != nil {
+		return nil
+	}
+	out := make(map[string]float64, len(samples))
+	for _, s := range samples {
+		out[s.Labels["namespace"]+"/"+s.Labels["pod"]+"/"+s.Labels["container"]] = s.Value
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestProxyFindings(t *testing.T) {
+	const mi = 1 << 20
+	base := proxyUsage{pod: "web-1", container: "istio-proxy", cpuLimit: 2000, memLimit: 1024 * mi, cpuUsage: 100, memUsage: 200 * mi}
+	tests := []struct {
+		name string
+		u    func(u *proxyUsage)
+		want []string
+	}{
+		{"healthy", func(u *proxyUsage) {}, nil},
+		{"memory warning", func(u *proxyUsage) { u.memUsage = 850 * mi }, []string{"warning MESH009_PROXY_MEMORY_PRESSURE"}},
+		{"memory with 503s", func(u *proxyUsage) { u.memUsage = 850 * mi; u.errorLines = 3 }, []string{"critical MESH009_PROXY_MEMORY_PRESSURE"}},
+		{"oom", func(u *proxyUsage) { u.oomKilled = true; u.restarts = 4 }, []string{"critical MESH011_PROXY_OOM_KILLED"}},
+		{"cpu limit", func(u *proxyUsage) { u.cpuUsage = 1950 }, []string{"warning MESH010_PROXY_CPU_THROTTLED"}},
+		{"throttled", func(u *proxyUsage) { u.throttled = 0.4 }, []string{"warning MESH010_PROXY_CPU_THROTTLED"}},
+		{"no metrics", func(u *proxyUsage) { u.cpuUsage, u.memUsage = -1, -1 }, nil},
+		{"overload", func(u *proxyUsage) { u.overloadLines = []string{"envoy overloaded"} }, []string{"warning MESH012_PROXY_OVERLOADED"}},
+	}
+	for _, tt := range tests {
+		u := base
+		tt.u(&u)
+		var got []string
+		for _, f := range proxyFindings("shop", u) {
+			got = append(got, string(f.Severity)+" "+string(f.Code))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanProxyLogs(t *testing.T) {
+	var u proxyUsage
+	u.scanProxyLogs(strings.Join([]string{
+		`[2024-05-01T10:00:00.000Z] "GET /cart HTTP/1.1" 503 UO upstream_reset_before_response_started{overflow}`,
+		`[2024-05-01T10:00:01.000Z] "GET /cart HTTP/1.1" 504 UT - upstream request timeout`,
+		`warning envoy overload: shrink heap action triggered`,
+		`[2024-05-01T10:00:02.000Z] "GET /cart HTTP/1.1" 200 - via_upstream`,
+	}, "\n"))
+	if u.logLines != 4 || len(u.overloadLines) != 1 || u.errorLines != 2 {
+		t.Errorf("lines=%d overload=%d errors=%d", u.logLines, len(u.overloadLines), u.errorLines)
+	}
+}
+
+func TestPodContainerUsage(t *testing.T) {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-1"},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "istio-proxy", "usage": map[string]interface{}{"cpu": "250m", "memory": "128Mi"}},
+		},
+	}}
+	got := podContainerUsage([]unstructured.Unstructured{item})["web-1/istio-proxy"]
+	if got != [2]int64{250, 128 << 20} {
+		t.Errorf("usage = %v", got)
+	}
+}
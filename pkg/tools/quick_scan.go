@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	defaultQuickScanBudget = 10 * time.Second
+	maxQuickScanBudget     = 60 * time.Second
+)
+
+// quickCheck is one cheap check of quick_scan. run only reads lists through
+// the shared snapshot; next names the deep tools to run when it finds issues.
+type quickCheck struct {
+	name string
+	next []string
+	run  func(t *QuickScanTool, ctx context.Context, ns string) []types.DiagnosticFinding
+}
+
+var quickChecks = []quickCheck{
+	{name: "services", next: []string{"get_service", "list_endpoints"}, run: (*QuickScanTool).scanServices},
+	{name: "gateway-api", next: []string{"scan_gateway_misconfigs", "check_gateway_conformance"}, run: (*QuickScanTool).scanGatewayAPI},
+	{name: "network-policy", next: []string{"check_networkpolicy_ports", "list_networkpolicies"}, run: (*QuickScanTool).scanNetworkPolicies},
+	{name: "istio", next: []string{"analyze_istio_routing", "analyze_istio_authpolicy", "validate_istio_config"}, run: (*QuickScanTool).scanIstio},
+}
+
+// --- quick_scan ---
+
+// QuickScanTool runs the cheapest checks of several modules in parallel
+// within a time budget, as a first pass before the deep diagnose tools.
+type QuickScanTool struct{ BaseTool }
+
+func (t *QuickScanTool) Name() string { return "quick_scan" }
+func (t *QuickScanTool) Description() string {
+	return "Fast first-pass scan within a time budget: Services without endpoints, unhealthy Gateways and routes, routes to missing Services, deny-all NetworkPolicies and Istio AuthorizationPolicies, and missing Istio subsets. Uses shared cached lists only and recommends which deep tools to run next"
+}
+func (t *QuickScanTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to scan (empty for cluster-wide)",
+			},
+			"budget_seconds": map[string]interface{}{
+				"type":        "integer",
+				"description": "Time budget for the whole scan in seconds (default 10, max 60); checks still running when it expires are reported as incomplete",
+			},
+		},
+	}
+}
+
+func (t *QuickScanTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	budget := time.Duration(getIntArg(args, "budget_seconds", 0)) * time.Second
+	if budget <= 0 {
+		budget = defaultQuickScanBudget
+	}
+	if budget > maxQuickScanBudget {
+		budget = maxQuickScanBudget
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	type checkResult struct {
+		index    int
+		findings []types.DiagnosticFinding
+	}
+	// Buffered so checks that finish after the budget do not block.
+	results := make(chan checkResult, len(quickChecks))
+	for i, c := range quickChecks {
+		go func() {
+			results <- checkResult{index: i, findings: c.run(t, scanCtx, ns)}
+		}()
+	}
+
+	done := make([][]types.DiagnosticFinding, len(quickChecks))
+	finished := make([]bool, len(quickChecks))
+collect:
+	for n := range quickChecks {
+		select {
+		case r := <-results:
+			done[r.index] = r.findings
+			finished[r.index] = true
+			progress.Report(ctx, n+1, len(quickChecks), fmt.Sprintf("finished %d/%d checks (%s)", n+1, len(quickChecks), quickChecks[r.index].name))
+		case <-scanCtx.Done():
+			break collect
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	var next []string
+	seen := make(map[string]bool)
+	recommend := func(c quickCheck) {
+		for _, tool := range c.next {
+			if !seen[tool] {
+				seen[tool] = true
+				next = append(next, tool)
+			}
+		}
+	}
+	for i, c := range quickChecks {
+		if !finished[i] {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Summary:    fmt.Sprintf("Quick scan check %q did not finish within %s", c.name, budget),
+				Suggestion: "Raise budget_seconds or run " + strings.Join(c.next, " / ") + " directly.",
+			})
+			recommend(c)
+			continue
+		}
+		issues := 0
+		for _, f := range done[i] {
+			if f.Severity == types.SeverityWarning || f.Severity == types.SeverityCritical {
+				issues++
+			}
+		}
+		findings = append(findings, done[i]...)
+		if issues > 0 {
+			recommend(c)
+		}
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("Quick scan found no issues in %d checks", len(quickChecks)),
+	}
+	if len(next) > 0 {
+		summary.Severity = types.SeverityInfo
+		summary.Summary = "Run next: " + strings.Join(next, ", ")
+		summary.Detail = "quick_scan only runs shallow checks; these tools analyse the affected areas in depth"
+	}
+	findings = append(findings, summary)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// scanServices reports selector-based Services whose Endpoints have no ready
+// address.
+func (t *QuickScanTool) scanServices(ctx context.Context, ns string) []types.DiagnosticFinding {
+	services, err := t.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil
+	}
+	endpoints, err := t.listResource(ctx, endpointsGVR, ns)
+	if err != nil {
+		return nil
+	}
+	ready := make(map[string]bool, len(endpoints.Items))
+	for _, ep := range endpoints.Items {
+		subsets, _, _ := unstructured.NestedSlice(ep.Object, "subsets")
+		for _, s := range subsets {
+			sm, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if addrs, _ := sm["addresses"].([]interface{}); len(addrs) > 0 {
+				ready[ep.GetNamespace()+"/"+ep.GetName()] = true
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, svc := range services.Items {
+		selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if len(selector) == 0 || ready[svc.GetNamespace()+"/"+svc.GetName()] {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeServiceNoEndpoints,
+			Resource:   &types.ResourceRef{Kind: "Service", Namespace: svc.GetNamespace(), Name: svc.GetName(), APIVersion: "v1"},
+			Summary:    fmt.Sprintf("Service %s/%s has no ready endpoints", svc.GetNamespace(), svc.GetName()),
+			Suggestion: "Check that pods match the selector " + formatSelector(selector) + " and pass their readiness probes.",
+		})
+	}
+	return findings
+}
+
+// scanGatewayAPI reports Gateways and routes with a False condition and routes
+// whose backend Service does not exist.
+func (t *QuickScanTool) scanGatewayAPI(ctx context.Context, ns string) []types.DiagnosticFinding {
+	gateways, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	if err != nil {
+		return nil
+	}
+	var findings []types.DiagnosticFinding
+	for _, gw := range gateways.Items {
+		conds, _, _ := unstructured.NestedSlice(gw.Object, "status", "conditions")
+		if !hasUnhealthyCondition(conds) {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeGatewayConditionFalse,
+			Resource:   &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: "gateway.networking.k8s.io/v1"},
+			Summary:    fmt.Sprintf("Gateway %s/%s is not accepted or not programmed", gw.GetNamespace(), gw.GetName()),
+			Detail:     formatConditions(conds),
+			Suggestion: "Run check_gateway_conformance for the failing conditions and their reasons.",
+		})
+	}
+
+	services, err := t.listResource(ctx, servicesGVR, "")
+	if err != nil {
+		return findings
+	}
+	existing := make(map[string]bool, len(services.Items))
+	for _, svc := range services.Items {
+		existing[svc.GetNamespace()+"/"+svc.GetName()] = true
+	}
+	for _, route := range t.listRoutes(ctx) {
+		if ns != "" && route.namespace != ns {
+			continue
+		}
+		ref := &types.ResourceRef{Kind: route.kind, Namespace: route.namespace, Name: route.name, APIVersion: "gateway.networking.k8s.io/v1"}
+		if status, bad := extractRouteStatusSuffix(route.obj); bad {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayRouteConditionFalse,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s %s/%s is not accepted by all parents%s", route.kind, route.namespace, route.name, status),
+				Suggestion: "Run scan_gateway_misconfigs to find the cause.",
+			})
+		}
+		for _, backend := range routeBackends(route) {
+			if existing[backend] {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayBackendServiceMissing,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s %s/%s references missing Service %s", route.kind, route.namespace, route.name, backend),
+				Suggestion: "Create the Service or fix the backendRef name and namespace.",
+			})
+		}
+	}
+	return findings
+}
+
+// scanNetworkPolicies reports NetworkPolicies that deny all ingress to the
+// pods they select.
+func (t *QuickScanTool) scanNetworkPolicies(ctx context.Context, ns string) []types.DiagnosticFinding {
+	policies, err := t.listResource(ctx, networkPoliciesGVR, ns)
+	if err != nil {
+		return nil
+	}
+	var findings []types.DiagnosticFinding
+	for _, np := range policies.Items {
+		policyTypes, _, _ := unstructured.NestedStringSlice(np.Object, "spec", "policyTypes")
+		ingress, _, _ := unstructured.NestedSlice(np.Object, "spec", "ingress")
+		for _, pt := range policyTypes {
+			if pt != "Ingress" || len(ingress) > 0 {
+				continue
+			}
+			selector, _, _ := unstructured.NestedStringMap(np.Object, "spec", "podSelector", "matchLabels")
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeNetworkPolicyDenyAllIngress,
+				Resource:   &types.ResourceRef{Kind: "NetworkPolicy", Namespace: np.GetNamespace(), Name: np.GetName(), APIVersion: "networking.k8s.io/v1"},
+				Summary:    fmt.Sprintf("NetworkPolicy %s/%s denies all ingress to %s", np.GetNamespace(), np.GetName(), formatSelector(selector)),
+				Suggestion: "Verify this is intentional and that allow policies exist for the expected clients.",
+			})
+		}
+	}
+	return findings
+}
+
+// scanIstio reports blanket DENY and empty ALLOW AuthorizationPolicies and
+// VirtualService subsets that no DestinationRule defines.
+func (t *QuickScanTool) scanIstio(ctx context.Context, ns string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	if aps, err := t.listResourceWithFallback(ctx, apV1GVR, apV1B1GVR, ns); err == nil {
+		for _, ap := range aps.Items {
+			action, _, _ := unstructured.NestedString(ap.Object, "spec", "action")
+			rules, _, _ := unstructured.NestedSlice(ap.Object, "spec", "rules")
+			if len(rules) > 0 {
+				continue
+			}
+			ref := &types.ResourceRef{Kind: "AuthorizationPolicy", Namespace: ap.GetNamespace(), Name: ap.GetName(), APIVersion: "security.istio.io/v1"}
+			switch orDefault(action, "ALLOW") {
+			case "DENY":
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryPolicy,
+					Code:       types.CodeIstioAuthzDenyAll,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("AuthorizationPolicy %s/%s is a DENY with no rules and blocks all traffic", ap.GetNamespace(), ap.GetName()),
+					Suggestion: "Add rules to narrow the deny scope, or remove the policy.",
+				})
+			case "ALLOW":
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryPolicy,
+					Code:       types.CodeIstioAuthzAllowNothing,
+					Resource:   ref,
+					Summary:    fmt.Sprintf("AuthorizationPolicy %s/%s is an ALLOW with no rules and allows nothing", ap.GetNamespace(), ap.GetName()),
+					Suggestion: "Add rules for the traffic to allow, or remove the policy.",
+				})
+			}
+		}
+	}
+
+	vss, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ns)
+	if err != nil {
+		return findings
+	}
+	drs, err := t.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, "")
+	if err != nil {
+		return findings
+	}
+	// subsets maps namespace/service to the subsets its DestinationRules define.
+	subsets := make(map[string]map[string]bool)
+	for _, dr := range drs.Items {
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		hostNs, svc := resolveIstioHost(host, dr.GetNamespace())
+		key := hostNs + "/" + svc
+		if subsets[key] == nil {
+			subsets[key] = make(map[string]bool)
+		}
+		defined, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+		for _, s := range defined {
+			if sm, ok := s.(map[string]interface{}); ok {
+				name, _ := sm["name"].(string)
+				subsets[key][name] = true
+			}
+		}
+	}
+	for _, vs := range vss.Items {
+		httpRoutes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		for _, r := range httpRoutes {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			dests, _ := rm["route"].([]interface{})
+			for _, d := range dests {
+				dm, ok := d.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				host, _, _ := unstructured.NestedString(dm, "destination", "host")
+				subset, _, _ := unstructured.NestedString(dm, "destination", "subset")
+				hostNs, svc := resolveIstioHost(host, vs.GetNamespace())
+				if subset == "" || subsets[hostNs+"/"+svc][subset] {
+					continue
+				}
+				findings = append(findings, types.DiagnosticFinding{
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryRouting,
+					Code:       types.CodeIstioSubsetMissing,
+					Resource:   &types.ResourceRef{Kind: "VirtualService", Namespace: vs.GetNamespace(), Name: vs.GetName(), APIVersion: "networking.istio.io/v1"},
+					Summary:    fmt.Sprintf("VirtualService %s/%s routes to subset %q of %s which no DestinationRule defines", vs.GetNamespace(), vs.GetName(), subset, host),
+					Suggestion: "Define the subset in the DestinationRule for the host, or fix the subset name.",
+				})
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestQuickScan(t *testing.T) {
+	obj := func(apiVersion, kind, ns, name string, fields map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: fields}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	selector := map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "web"}}}
+	web := obj("v1", "Service", "shop", "web", selector)
+	api := obj("v1", "Service", "shop", "api", map[string]interface{}{"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "api"}}})
+	webEndpoints := obj("v1", "Endpoints", "shop", "web", map[string]interface{}{
+		"subsets": []interface{}{map[string]interface{}{"addresses": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}}}},
+	})
+	route := obj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+			map[string]interface{}{"name": "web", "port": int64(80)},
+			map[string]interface{}{"name": "cart", "port": int64(80)},
+		}}},
+	}})
+	denyAll := obj("networking.k8s.io/v1", "NetworkPolicy", "shop", "deny-all", map[string]interface{}{"spec": map[string]interface{}{
+		"podSelector": map[string]interface{}{},
+		"policyTypes": []interface{}{"Ingress"},
+	}})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		servicesGVR: "ServiceList", endpointsGVR: "EndpointsList", networkPoliciesGVR: "NetworkPolicyList",
+		gatewaysV1GVR: "GatewayList", gatewaysV1B1GVR: "GatewayList",
+		httpRoutesV1GVR: "HTTPRouteList", httpRoutesV1B1GVR: "HTTPRouteList",
+		grpcRoutesV1GVR: "GRPCRouteList", grpcRoutesV1B1GVR: "GRPCRouteList",
+		apV1GVR: "AuthorizationPolicyList", apV1B1GVR: "AuthorizationPolicyList",
+		vsV1GVR: "VirtualServiceList", vsV1B1GVR: "VirtualServiceList",
+		drV1GVR: "DestinationRuleList", drV1B1GVR: "DestinationRuleList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, web, api, webEndpoints, route, denyAll)
+	tool := &QuickScanTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	codes := make(map[types.FindingCode]string)
+	for _, f := range findings {
+		if f.Code != "" {
+			codes[f.Code] = f.Summary
+		}
+	}
+	for code, want := range map[types.FindingCode]string{
+		types.CodeServiceNoEndpoints:           "Service shop/api has no ready endpoints",
+		types.CodeGatewayBackendServiceMissing: "HTTPRoute shop/web references missing Service shop/cart",
+		types.CodeNetworkPolicyDenyAllIngress:  "NetworkPolicy shop/deny-all denies all ingress",
+	} {
+		if !strings.HasPrefix(codes[code], want) {
+			t.Errorf("%s: got %q, want prefix %q", code, codes[code], want)
+		}
+	}
+	if len(codes) != 3 {
+		t.Errorf("expected 3 coded findings, got %v", codes)
+	}
+	last := findings[len(findings)-1]
+	want := "Run next: get_service, list_endpoints, scan_gateway_misconfigs, check_gateway_conformance, check_networkpolicy_ports, list_networkpolicies"
+	if last.Summary != want {
+		t.Errorf("recommendation = %q, want %q", last.Summary, want)
+	}
+}
+
+func TestQuickScanBudget(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	saved := quickChecks
+	defer func() { quickChecks = saved }()
+	quickChecks = []quickCheck{
+		{name: "fast", next: []string{"fast_tool"}, run: func(*QuickScanTool, context.Context, string) []types.DiagnosticFinding { return nil }},
+		{name: "slow", next: []string{"slow_tool"}, run: func(*QuickScanTool, context.Context, string) []types.DiagnosticFinding {
+			<-release
+			return nil
+		}},
+	}
+	tool := &QuickScanTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"budget_seconds": float64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 2 {
+		t.Fatalf("expected a timeout finding and the recommendation, got %+v", findings)
+	}
+	if !strings.Contains(findings[0].Summary, `"slow" did not finish within 1s`) {
+		t.Errorf("unexpected timeout finding %q", findings[0].Summary)
+	}
+	if findings[1].Summary != "Run next: slow_tool" {
+		t.Errorf("unexpected recommendation %q", findings[1].Summary)
+	}
+}
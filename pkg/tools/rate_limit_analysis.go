@@ -0,0 +1,712 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	backendTrafficPolicyGVR = schema.GroupVersionResource{Group: "gateway.envoyproxy.io", Version: "v1alpha1", Resource: "backendtrafficpolicies"}
+	rateLimitConfigGVR      = schema.GroupVersionResource{Group: "ratelimit.solo.io", Version: "v1alpha1", Resource: "ratelimitconfigs"}
+)
+
+// rateLimit is one rate limit a policy attaches to a Gateway or route.
+type rateLimit struct {
+	policy types.ResourceRef
+	// target is the Gateway, HTTPRoute or GRPCRoute the limit applies to.
+	target types.ResourceRef
+	scope  string // local or global
+	limits []string
+}
+
+func (l rateLimit) String() string {
+	limits := "limits set in the rate limit service"
+	if len(l.limits) > 0 {
+		limits = strings.Join(l.limits, ", ")
+	}
+	return fmt.Sprintf("%s %s (%s %s/%s on %s %s/%s)", l.scope, limits,
+		l.policy.Kind, l.policy.Namespace, l.policy.Name, l.target.Kind, l.target.Namespace, l.target.Name)
+}
+
+// overridable reports whether a limit of the same policy kind on a route
+// replaces this one. Envoy Gateway and kgateway apply the most specific
+// policy; an EnvoyFilter patches the gateway proxy for every route.
+func (l rateLimit) overridable() bool { return l.policy.Kind != "EnvoyFilter" }
+
+// --- analyze_rate_limits ---
+
+type AnalyzeRateLimitsTool struct{ BaseTool }
+
+func (t *AnalyzeRateLimitsTool) Name() string { return "analyze_rate_limits" }
+func (t *AnalyzeRateLimitsTool) Description() string {
+	return "Summarize the effective rate limits of each Gateway API route from Envoy Gateway BackendTrafficPolicy, kgateway TrafficPolicy and RateLimitConfig, and Istio EnvoyFilter local rate limits, and explain the 429 responses in the gateway proxy access logs"
+}
+func (t *AnalyzeRateLimitsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the routes to analyze (empty for all namespaces)",
+			},
+			"route": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional HTTPRoute or GRPCRoute name",
+			},
+			"include_logs": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Count 429 responses in the access logs of the gateway proxies (default true)",
+			},
+			"since": map[string]interface{}{
+				"type":        "string",
+				"description": "Access log window (default 15m)",
+			},
+			"tail": map[string]interface{}{
+				"type":        "number",
+				"description": "Log lines to scan per gateway proxy pod (default 1000)",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeRateLimitsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	routeName := getStringArg(args, "route", "")
+	includeLogs := true
+	if v, ok := args["include_logs"].(bool); ok {
+		includeLogs = v
+	}
+	since := getStringArg(args, "since", "15m")
+	tail := int64(getIntArg(args, "tail", 1000))
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+
+	var routes []routeInfo
+	for _, r := range t.listRoutes(ctx) {
+		if (ns == "" || r.namespace == ns) && (routeName == "" || r.name == routeName) {
+			routes = append(routes, r)
+		}
+	}
+	if len(routes) == 0 {
+		summary := fmt.Sprintf("No HTTPRoute or GRPCRoute found in namespace %s", responseNs)
+		if routeName != "" {
+			summary = fmt.Sprintf("No HTTPRoute or GRPCRoute named %s found in namespace %s", routeName, responseNs)
+		}
+		findings := []types.DiagnosticFinding{{Severity: types.SeverityInfo, Category: types.CategoryPolicy, Summary: summary}}
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, "multi"), nil
+	}
+
+	// Gateways of the routes, with their proxy pods.
+	gateways := make(map[string]*gatewayProxy)
+	var gatewayKeys []string
+	routeParents := make(map[string][]types.ResourceRef, len(routes))
+	for _, r := range routes {
+		parents := routeParentGateways(r)
+		routeParents[routeKey(r)] = parents
+		for _, p := range parents {
+			key := p.Namespace + "/" + p.Name
+			if gateways[key] == nil {
+				gateways[key] = t.gatewayProxy(ctx, p)
+				gatewayKeys = append(gatewayKeys, key)
+			}
+		}
+	}
+	sort.Strings(gatewayKeys)
+
+	limits := t.policyRateLimits(ctx)
+	if list, err := t.listResource(ctx, envoyFilterV1A1, ""); err == nil {
+		for _, key := range gatewayKeys {
+			limits = append(limits, envoyFilterRateLimits(list.Items, gateways[key])...)
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	effective := make(map[string][]rateLimit, len(routes))
+	var unlimited []string
+	for _, r := range routes {
+		key := routeKey(r)
+		eff, overridden := effectiveRateLimits(r, routeParents[key], limits)
+		effective[key] = eff
+		if len(eff) == 0 {
+			unlimited = append(unlimited, key)
+			continue
+		}
+		lines := make([]string, 0, len(eff)+len(overridden))
+		for _, l := range eff {
+			lines = append(lines, l.String())
+		}
+		for _, l := range overridden {
+			lines = append(lines, "overrides "+l.String())
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Resource: &types.ResourceRef{Kind: r.kind, Namespace: r.namespace, Name: r.name},
+			Summary:  fmt.Sprintf("%s: %d effective rate limit(s): %s", key, len(eff), eff[0]),
+			Detail:   strings.Join(lines, "\n"),
+		})
+	}
+	if len(unlimited) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Summary:  fmt.Sprintf("%d of %d routes have no rate limit", len(unlimited), len(routes)),
+			Detail:   strings.Join(unlimited, ", "),
+		})
+	}
+	findings = append(findings, t.rateLimitConfigFindings(ctx, ns)...)
+
+	if includeLogs {
+		for _, key := range gatewayKeys {
+			findings = append(findings, t.gateway429Findings(ctx, gateways[key], routes, effective, tail, since)...)
+		}
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, "multi"), nil
+}
+
+func routeKey(r routeInfo) string {
+	return fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name)
+}
+
+// routeParentGateways returns the Gateways among a route's parentRefs.
+func routeParentGateways(r routeInfo) []types.ResourceRef {
+	parentRefs, _, _ := unstructured.NestedSlice(r.obj, "spec", "parentRefs")
+	var out []types.ResourceRef
+	seen := make(map[string]bool)
+	for _, p := range parentRefs {
+		pm, _ := p.(map[string]interface{})
+		kind, _ := pm["kind"].(string)
+		group, _ := pm["group"].(string)
+		name, _ := pm["name"].(string)
+		ns, _ := pm["namespace"].(string)
+		if name == "" || orDefault(kind, "Gateway") != "Gateway" || orDefault(group, groupGateway) != groupGateway {
+			continue
+		}
+		ref := types.ResourceRef{Kind: "Gateway", Namespace: orDefault(ns, r.namespace), Name: name}
+		if key := ref.Namespace + "/" + ref.Name; !seen[key] {
+			seen[key] = true
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// effectiveRateLimits returns the limits that apply to a route, and the
+// Gateway limits that a route policy of the same kind overrides.
+func effectiveRateLimits(r routeInfo, parents []types.ResourceRef, limits []rateLimit) (effective, overridden []rateLimit) {
+	onRoute := make(map[string]bool)
+	for _, l := range limits {
+		if l.target.Kind == r.kind && l.target.Namespace == r.namespace && l.target.Name == r.name {
+			effective = append(effective, l)
+			onRoute[l.policy.Kind] = true
+		}
+	}
+	for _, l := range limits {
+		if l.target.Kind != "Gateway" {
+			continue
+		}
+		for _, p := range parents {
+			if l.target.Namespace != p.Namespace || l.target.Name != p.Name {
+				continue
+			}
+			if l.overridable() && onRoute[l.policy.Kind] {
+				overridden = append(overridden, l)
+			} else {
+				effective = append(effective, l)
+			}
+		}
+	}
+	return effective, overridden
+}
+
+// policyRateLimits reads the rate limits of Envoy Gateway
+// BackendTrafficPolicies and kgateway TrafficPolicies. Missing CRDs are
+// skipped.
+func (t *AnalyzeRateLimitsTool) policyRateLimits(ctx context.Context) []rateLimit {
+	var out []rateLimit
+	if list, err := t.listResource(ctx, backendTrafficPolicyGVR, ""); err == nil {
+		for _, p := range list.Items {
+			out = append(out, backendTrafficPolicyRateLimits(&p)...)
+		}
+	}
+	if list, err := t.listResource(ctx, trafficPolicyGVR, ""); err == nil {
+		for _, p := range list.Items {
+			out = append(out, kgatewayTrafficPolicyRateLimits(&p)...)
+		}
+	}
+	return out
+}
+
+func attachRateLimit(policy types.ResourceRef, obj map[string]interface{}, scope string, limits []string) []rateLimit {
+	var out []rateLimit
+	for _, target := range policyTargetRefs(obj, policy.Namespace) {
+		switch target.Kind {
+		case "Gateway", "HTTPRoute", "GRPCRoute":
+			out = append(out, rateLimit{policy: policy, target: target, scope: scope, limits: limits})
+		}
+	}
+	return out
+}
+
+// backendTrafficPolicyRateLimits reads spec.rateLimit of an Envoy Gateway
+// BackendTrafficPolicy: local and global rules of requests per unit.
+func backendTrafficPolicyRateLimits(p *unstructured.Unstructured) []rateLimit {
+	rl, ok, _ := unstructured.NestedMap(p.Object, "spec", "rateLimit")
+	if !ok {
+		return nil
+	}
+	ref := types.ResourceRef{Kind: "BackendTrafficPolicy", Namespace: p.GetNamespace(), Name: p.GetName(), APIVersion: "gateway.envoyproxy.io/v1alpha1"}
+	var out []rateLimit
+	for _, scope := range []string{"local", "global"} {
+		rules, ok, _ := unstructured.NestedSlice(rl, scope, "rules")
+		if !ok {
+			continue
+		}
+		var limits []string
+		for _, rule := range rules {
+			rm, _ := rule.(map[string]interface{})
+			requests := toInt(nestedValue(rm, "limit", "requests"))
+			unit, _, _ := unstructured.NestedString(rm, "limit", "unit")
+			if requests == 0 || unit == "" {
+				continue
+			}
+			limit := fmt.Sprintf("%d/%s", requests, unit)
+			if selectors, _ := rm["clientSelectors"].([]interface{}); len(selectors) > 0 {
+				limit += " per matching client"
+			}
+			limits = append(limits, limit)
+		}
+		out = append(out, attachRateLimit(ref, p.Object, scope, limits)...)
+	}
+	return out
+}
+
+// kgatewayTrafficPolicyRateLimits reads spec.rateLimit of a kgateway
+// TrafficPolicy: a local token bucket or global descriptors sent to a rate
+// limit service.
+func kgatewayTrafficPolicyRateLimits(p *unstructured.Unstructured) []rateLimit {
+	rl, ok, _ := unstructured.NestedMap(p.Object, "spec", "rateLimit")
+	if !ok {
+		return nil
+	}
+	ref := types.ResourceRef{Kind: "TrafficPolicy", Namespace: p.GetNamespace(), Name: p.GetName(), APIVersion: "gateway.kgateway.dev/v1alpha1"}
+	var out []rateLimit
+	if bucket, ok, _ := unstructured.NestedMap(rl, "local", "tokenBucket"); ok {
+		var limits []string
+		if limit := tokenBucketLimit(bucket); limit != "" {
+			limits = append(limits, limit)
+		}
+		out = append(out, attachRateLimit(ref, p.Object, "local", limits)...)
+	}
+	if global, ok, _ := unstructured.NestedMap(rl, "global"); ok {
+		var limits []string
+		if descriptors, _ := global["descriptors"].([]interface{}); len(descriptors) > 0 {
+			limits = append(limits, fmt.Sprintf("%d descriptor(s)", len(descriptors)))
+		}
+		if ext, _, _ := unstructured.NestedString(global, "extensionRef", "name"); ext != "" {
+			limits = append(limits, "service "+ext)
+		}
+		out = append(out, attachRateLimit(ref, p.Object, "global", limits)...)
+	}
+	return out
+}
+
+// tokenBucketLimit describes an Envoy token bucket, in the camelCase of
+// Kubernetes APIs or the snake_case of Envoy configuration.
+func tokenBucketLimit(bucket map[string]interface{}) string {
+	field := func(camel, snake string) interface{} {
+		if v, ok := bucket[camel]; ok {
+			return v
+		}
+		return bucket[snake]
+	}
+	maxTokens := toInt(field("maxTokens", "max_tokens"))
+	if maxTokens == 0 {
+		return ""
+	}
+	perFill := toInt(field("tokensPerFill", "tokens_per_fill"))
+	if perFill == 0 {
+		perFill = 1
+	}
+	interval, _ := field("fillInterval", "fill_interval").(string)
+	return fmt.Sprintf("%d per %s (burst %d)", perFill, orDefault(interval, "?"), maxTokens)
+}
+
+// gatewayProxy is a Gateway and the proxy pods that serve it.
+type gatewayProxy struct {
+	ref  types.ResourceRef
+	pods []unstructured.Unstructured
+}
+
+// gatewayProxy finds the proxy pods of a Gateway: in its namespace for Istio
+// and kgateway, or labelled with the owning Gateway for Envoy Gateway.
+func (t *AnalyzeRateLimitsTool) gatewayProxy(ctx context.Context, gw types.ResourceRef) *gatewayProxy {
+	g := &gatewayProxy{ref: gw}
+	sel := listSelector{Label: "gateway.networking.k8s.io/gateway-name=" + gw.Name}
+	if list, err := t.listResourceSelected(ctx, podsGVR, gw.Namespace, sel); err == nil && len(list.Items) > 0 {
+		g.pods = list.Items
+		return g
+	}
+	sel = listSelector{Label: fmt.Sprintf("gateway.envoyproxy.io/owning-gateway-name=%s,gateway.envoyproxy.io/owning-gateway-namespace=%s", gw.Name, gw.Namespace)}
+	if list, err := t.listResourceSelected(ctx, podsGVR, "", sel); err == nil {
+		g.pods = list.Items
+	}
+	return g
+}
+
+// proxyLabels returns the labels of the Gateway's proxy pods, or the label
+// Istio sets on them when none runs.
+func (g *gatewayProxy) proxyLabels() []map[string]string {
+	if len(g.pods) == 0 {
+		return []map[string]string{{"gateway.networking.k8s.io/gateway-name": g.ref.Name}}
+	}
+	out := make([]map[string]string, 0, len(g.pods))
+	for _, p := range g.pods {
+		out = append(out, p.GetLabels())
+	}
+	return out
+}
+
+// envoyFilterRateLimits returns the local rate limits that Istio EnvoyFilters
+// patch into the proxies of a Gateway.
+func envoyFilterRateLimits(filters []unstructured.Unstructured, gw *gatewayProxy) []rateLimit {
+	var out []rateLimit
+	for _, ef := range filters {
+		if ef.GetNamespace() != gw.ref.Namespace && ef.GetNamespace() != istioRootNamespace {
+			continue
+		}
+		if selector, ok, _ := unstructured.NestedStringMap(ef.Object, "spec", "workloadSelector", "labels"); ok && len(selector) > 0 {
+			matched := false
+			for _, l := range gw.proxyLabels() {
+				matched = matched || labels.SelectorFromSet(selector).Matches(labels.Set(l))
+			}
+			if !matched {
+				continue
+			}
+		}
+		var limits []string
+		found := false
+		patches, _, _ := unstructured.NestedSlice(ef.Object, "spec", "configPatches")
+		for _, cp := range patches {
+			cpm, _ := cp.(map[string]interface{})
+			switch matchContext, _, _ := unstructured.NestedString(cpm, "match", "context"); matchContext {
+			case "", "ANY", "GATEWAY":
+			default:
+				continue
+			}
+			value, _, _ := unstructured.NestedMap(cpm, "patch", "value")
+			configs := []interface{}{nestedValue(value, "typed_config")}
+			if perFilter, ok, _ := unstructured.NestedMap(value, "typed_per_filter_config"); ok {
+				configs = append(configs, perFilter["envoy.filters.http.local_ratelimit"])
+			}
+			for _, c := range configs {
+				cfg, ok := localRateLimitConfig(c)
+				if !ok {
+					continue
+				}
+				found = true
+				if bucket, ok := cfg["token_bucket"].(map[string]interface{}); ok {
+					if limit := tokenBucketLimit(bucket); limit != "" {
+						limits = append(limits, limit)
+					}
+				}
+			}
+		}
+		if found {
+			ref := types.ResourceRef{Kind: "EnvoyFilter", Namespace: ef.GetNamespace(), Name: ef.GetName(), APIVersion: "networking.istio.io/v1alpha3"}
+			out = append(out, rateLimit{policy: ref, target: gw.ref, scope: "local", limits: limits})
+		}
+	}
+	return out
+}
+
+const localRateLimitTypeURL = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+
+// localRateLimitConfig returns the LocalRateLimit configuration of a
+// typed_config, unwrapping a TypedStruct.
+func localRateLimitConfig(v interface{}) (map[string]interface{}, bool) {
+	cfg, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	switch cfg["@type"] {
+	case localRateLimitTypeURL:
+		return cfg, true
+	case "type.googleapis.com/udpa.type.v1.TypedStruct", "type.googleapis.com/xds.type.v3.TypedStruct":
+		if cfg["type_url"] == localRateLimitTypeURL {
+			value, _ := cfg["value"].(map[string]interface{})
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// rateLimitConfigFindings lists the kgateway RateLimitConfigs of ns, whose
+// descriptors set the limits of the global rate limit service.
+func (t *AnalyzeRateLimitsTool) rateLimitConfigFindings(ctx context.Context, ns string) []types.DiagnosticFinding {
+	list, err := t.listResource(ctx, rateLimitConfigGVR, ns)
+	if err != nil {
+		return nil
+	}
+	var findings []types.DiagnosticFinding
+	for _, c := range list.Items {
+		ref := &types.ResourceRef{Kind: "RateLimitConfig", Namespace: c.GetNamespace(), Name: c.GetName(), APIVersion: "ratelimit.solo.io/v1alpha1"}
+		descriptors, _, _ := unstructured.NestedSlice(c.Object, "spec", "raw", "descriptors")
+		limits := descriptorLimits(descriptors, "")
+		state, _, _ := unstructured.NestedString(c.Object, "status", "state")
+		if strings.EqualFold(state, "REJECTED") {
+			message, _, _ := unstructured.NestedString(c.Object, "status", "message")
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeKgatewayRateLimitConfigRejected,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("RateLimitConfig %s/%s is rejected; its limits are not enforced", c.GetNamespace(), c.GetName()),
+				Detail:     message,
+				Suggestion: "Fix the descriptors named in the status message; the rate limit service keeps its previous configuration.",
+			})
+			continue
+		}
+		summary := fmt.Sprintf("RateLimitConfig %s/%s: %d global limit(s)", c.GetNamespace(), c.GetName(), len(limits))
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryPolicy,
+			Resource: ref,
+			Summary:  summary,
+			Detail:   strings.Join(limits, "\n"),
+		})
+	}
+	return findings
+}
+
+// descriptorLimits flattens nested rate limit descriptors into
+// "key=value: N/unit" lines.
+func descriptorLimits(descriptors []interface{}, prefix string) []string {
+	var out []string
+	for _, d := range descriptors {
+		dm, _ := d.(map[string]interface{})
+		key, _ := dm["key"].(string)
+		name := key
+		if value, _ := dm["value"].(string); value != "" {
+			name += "=" + value
+		}
+		if prefix != "" {
+			name = prefix + ", " + name
+		}
+		if n := toInt(nestedValue(dm, "rateLimit", "requestsPerUnit")); n > 0 {
+			unit, _, _ := unstructured.NestedString(dm, "rateLimit", "unit")
+			out = append(out, fmt.Sprintf("%s: %d/%s", name, n, unit))
+		}
+		nested, _ := dm["descriptors"].([]interface{})
+		out = append(out, descriptorLimits(nested, name)...)
+	}
+	return out
+}
+
+// status429Counts counts 429 responses by origin.
+type status429Counts struct {
+	// Limited were rejected by an Envoy rate limit (response flag RL).
+	Limited int
+	// Upstream were returned by the backend.
+	Upstream int
+}
+
+var (
+	// accessLogStatus matches the response code and flags of Envoy's default
+	// text access log: "GET /cart HTTP/1.1" 429 RL ...
+	accessLogStatus = regexp.MustCompile(`"[A-Z]+ \S+ [^"]*" (\d{3}) (\S+)`)
+	// accessLogRoute matches route names of Envoy Gateway and kgateway,
+	// e.g. httproute/shop/web/rule/0/match/0/*.
+	accessLogRoute = regexp.MustCompile(`\b(httproute|grpcroute)/([a-z0-9.-]+)/([a-z0-9.-]+)/`)
+)
+
+// count429s counts the 429 responses in Envoy access logs, in JSON or the
+// default text format, by the route named in the log line. Lines without a
+// route name are counted under "".
+func count429s(logs string) map[string]*status429Counts {
+	out := make(map[string]*status429Counts)
+	for _, line := range strings.Split(logs, "\n") {
+		code, flags, route := parseAccessLogLine(line)
+		if code != "429" {
+			continue
+		}
+		key := ""
+		if m := accessLogRoute.FindStringSubmatch(route); m != nil {
+			kind := map[string]string{"httproute": "HTTPRoute", "grpcroute": "GRPCRoute"}[m[1]]
+			key = fmt.Sprintf("%s %s/%s", kind, m[2], m[3])
+		}
+		if out[key] == nil {
+			out[key] = &status429Counts{}
+		}
+		if containsString(strings.Split(flags, ","), "RL") {
+			out[key].Limited++
+		} else {
+			out[key].Upstream++
+		}
+	}
+	return out
+}
+
+// parseAccessLogLine returns the response code, response flags and route
+// name of an access log line; route is the whole line for the text format.
+func parseAccessLogLine(line string) (code, flags, route string) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		var entry map[string]interface{}
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			for _, k := range []string{"response_code", ":status", "status"} {
+				if v, ok := entry[k]; ok && v != nil {
+					code = fmt.Sprint(v)
+					break
+				}
+			}
+			flags, _ = entry["response_flags"].(string)
+			route, _ = entry["route_name"].(string)
+			return code, flags, route
+		}
+	}
+	if m := accessLogStatus.FindStringSubmatch(line); m != nil {
+		return m[1], m[2], line
+	}
+	return "", "", ""
+}
+
+// gateway429Findings reads the access logs of a Gateway's proxies and reports
+// the 429 responses of each analyzed route, with the limits that explain
+// them. 429s whose log line names no route are reported on the Gateway, or
+// on its only analyzed route.
+func (t *AnalyzeRateLimitsTool) gateway429Findings(ctx context.Context, gw *gatewayProxy, routes []routeInfo, effective map[string][]rateLimit, tail int64, since string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	counts := make(map[string]*status429Counts)
+	for _, pod := range gw.pods {
+		lr, err := getPodLogs(ctx, t.Clients, pod.GetNamespace(), pod.GetName(), podProxyContainer(&pod), tail, since)
+		if err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryLogs,
+				Resource: &types.ResourceRef{Kind: "Pod", Namespace: pod.GetNamespace(), Name: pod.GetName()},
+				Summary:  fmt.Sprintf("Could not read the access logs of %s/%s", pod.GetNamespace(), pod.GetName()),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		for key, c := range count429s(lr.logs) {
+			if counts[key] == nil {
+				counts[key] = &status429Counts{}
+			}
+			counts[key].Limited += c.Limited
+			counts[key].Upstream += c.Upstream
+		}
+	}
+
+	var attached []routeInfo
+	for _, r := range routes {
+		for _, p := range routeParentGateways(r) {
+			if p.Namespace == gw.ref.Namespace && p.Name == gw.ref.Name {
+				attached = append(attached, r)
+				break
+			}
+		}
+	}
+	if c := counts[""]; c != nil {
+		if len(attached) == 1 {
+			key := routeKey(attached[0])
+			if counts[key] == nil {
+				counts[key] = &status429Counts{}
+			}
+			counts[key].Limited += c.Limited
+			counts[key].Upstream += c.Upstream
+		} else {
+			var limits []rateLimit
+			for _, r := range attached {
+				limits = append(limits, effective[routeKey(r)]...)
+			}
+			gwRef := gw.ref
+			findings = append(findings, status429Findings(&gwRef, fmt.Sprintf("Gateway %s/%s", gwRef.Namespace, gwRef.Name), c, limits, since)...)
+		}
+	}
+	for _, r := range attached {
+		key := routeKey(r)
+		if c := counts[key]; c != nil {
+			findings = append(findings, status429Findings(&types.ResourceRef{Kind: r.kind, Namespace: r.namespace, Name: r.name}, key, c, effective[key], since)...)
+		}
+	}
+	return findings
+}
+
+func status429Findings(ref *types.ResourceRef, subject string, c *status429Counts, limits []rateLimit, since string) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	if c.Limited > 0 {
+		if len(limits) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeGatewayRateLimitSourceUnknown,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: %d request(s) rejected with 429 by a gateway rate limit in the last %s, but no rate limit policy applies to it", subject, c.Limited, since),
+				Suggestion: "The limit may come from a global rate limit service, an EnvoyProxy or bootstrap patch, or a policy in a namespace this server cannot read.",
+			})
+		} else {
+			lines := make([]string, 0, len(limits))
+			for _, l := range limits {
+				lines = append(lines, l.String())
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryPolicy,
+				Code:       types.CodeGatewayRateLimited,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: %d request(s) rejected with 429 by the gateway rate limit in the last %s", subject, c.Limited, since),
+				Detail:     strings.Join(lines, "\n"),
+				Suggestion: fmt.Sprintf("Raise the limit in %s %s/%s if this traffic is expected, or find the clients exceeding it with get_gateway_logs.", limits[0].policy.Kind, limits[0].policy.Namespace, limits[0].policy.Name),
+			})
+		}
+	}
+	if c.Upstream > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeGatewayUpstreamRateLimited,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s: %d 429 response(s) in the last %s came from the backend, not from a gateway rate limit", subject, c.Upstream, since),
+			Suggestion: "The backend enforces its own limit; gateway rate limit policies do not cause these responses.",
+		})
+	}
+	return findings
+}
+
+// podProxyContainer returns the proxy container of a gateway pod, or its
+// first container.
+func podProxyContainer(pod *unstructured.Unstructured) string {
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	var names []string
+	for _, c := range containers {
+		cm, _ := c.(map[string]interface{})
+		if name, _ := cm["name"].(string); name != "" {
+			names = append(names, name)
+		}
+	}
+	for _, proxy := range proxyContainerNames {
+		if containsString(names, proxy) {
+			return proxy
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestCount429s(t *testing.T) {
+	logs := strings.Join([]string{
+		`{"response_code":429,"response_flags":"RL","route_name":"httproute/shop/web/rule/0/match/0/*"}`,
+		`{"response_code":429,"response_flags":"-","route_name":"httproute/shop/web/rule/0/match/0/*"}`,
+		`{"response_code":200,"response_flags":"-","route_name":"httproute/shop/web/rule/0/match/0/*"}`,
+		`{":status":"429","response_flags":"RL,UAEX","route_name":"grpcroute/shop/cart/rule/0/match/0/*"}`,
+		`[2026-10-15T10:00:00.000Z] "GET /cart HTTP/1.1" 429 RL - "-" 0 18 0 - "10.0.0.1" "curl/8.0" "abc" "shop.example.com" "-" - - 10.0.0.2:8080 10.0.0.1:5000 - -`,
+		`[2026-10-15T10:00:01.000Z] "GET /cart HTTP/1.1" 429 - via_upstream "-" 0 18 3 2 "10.0.0.1" "curl/8.0" "abc" "shop.example.com" "10.0.1.4:8080" outbound|8080||cart.shop.svc.cluster.local - -`,
+		"not an access log line 429",
+	}, "\n")
+	got := count429s(logs)
+	want := map[string]*status429Counts{
+		"HTTPRoute shop/web":  {Limited: 1, Upstream: 1},
+		"GRPCRoute shop/cart": {Limited: 1},
+		"":                    {Limited: 1, Upstream: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for k, v := range got {
+			t.Logf("%q: %+v", k, *v)
+		}
+		t.Errorf("count429s mismatch")
+	}
+}
+
+func TestTokenBucketLimit(t *testing.T) {
+	tests := []struct {
+		bucket map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"maxTokens": int64(20), "tokensPerFill": int64(10), "fillInterval": "1s"}, "10 per 1s (burst 20)"},
+		{map[string]interface{}{"max_tokens": float64(100), "fill_interval": "60s"}, "1 per 60s (burst 100)"},
+		{map[string]interface{}{"fillInterval": "1s"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tokenBucketLimit(tt.bucket); got != tt.want {
+			t.Errorf("tokenBucketLimit(%v) = %q, want %q", tt.bucket, got, tt.want)
+		}
+	}
+}
+
+func rateLimitRoute(name string, parents ...string) *unstructured.Unstructured {
+	refs := make([]interface{}, 0, len(parents))
+	for _, p := range parents {
+		ns, gw, _ := strings.Cut(p, "/")
+		refs = append(refs, map[string]interface{}{"name": gw, "namespace": ns})
+	}
+	return managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{"parentRefs": refs},
+	})
+}
+
+func backendTrafficPolicy(ns, name, kind, target string, requests int64, unit string) *unstructured.Unstructured {
+	return managedObj("gateway.envoyproxy.io/v1alpha1", "BackendTrafficPolicy", ns, name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{map[string]interface{}{"group": groupGateway, "kind": kind, "name": target}},
+			"rateLimit": map[string]interface{}{
+				"type": "Local",
+				"local": map[string]interface{}{"rules": []interface{}{
+					map[string]interface{}{"limit": map[string]interface{}{"requests": requests, "unit": unit}},
+				}},
+			},
+		},
+	})
+}
+
+func TestAnalyzeRateLimits(t *testing.T) {
+	envoyFilter := managedObj("networking.istio.io/v1alpha3", "EnvoyFilter", "infra", "gw-local-rl", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"workloadSelector": map[string]interface{}{"labels": map[string]interface{}{"gateway.networking.k8s.io/gateway-name": "public"}},
+			"configPatches": []interface{}{map[string]interface{}{
+				"applyTo": "HTTP_FILTER",
+				"match":   map[string]interface{}{"context": "GATEWAY"},
+				"patch": map[string]interface{}{"value": map[string]interface{}{
+					"name": "envoy.filters.http.local_ratelimit",
+					"typed_config": map[string]interface{}{
+						"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+						"type_url": localRateLimitTypeURL,
+						"value": map[string]interface{}{"token_bucket": map[string]interface{}{
+							"max_tokens": int64(500), "tokens_per_fill": int64(500), "fill_interval": "60s",
+						}},
+					},
+				}},
+			}},
+		},
+	})
+	sidecarFilter := envoyFilter.DeepCopy()
+	sidecarFilter.SetName("sidecar-rl")
+	sidecarFilter.Object["spec"].(map[string]interface{})["workloadSelector"] = map[string]interface{}{"labels": map[string]interface{}{"app": "web"}}
+	rejected := managedObj("ratelimit.solo.io/v1alpha1", "RateLimitConfig", "shop", "per-user", map[string]interface{}{
+		"spec":   map[string]interface{}{"raw": map[string]interface{}{"descriptors": []interface{}{}}},
+		"status": map[string]interface{}{"state": "REJECTED", "message": "descriptor key missing"},
+	})
+	kgatewayPolicy := managedObj("gateway.kgateway.dev/v1alpha1", "TrafficPolicy", "shop", "cart-rl", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{map[string]interface{}{"group": groupGateway, "kind": "HTTPRoute", "name": "cart"}},
+			"rateLimit": map[string]interface{}{"local": map[string]interface{}{"tokenBucket": map[string]interface{}{
+				"maxTokens": int64(10), "tokensPerFill": int64(5), "fillInterval": "1s",
+			}}},
+		},
+	})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		podsGVR:                 "PodList",
+		backendTrafficPolicyGVR: "BackendTrafficPolicyList",
+		trafficPolicyGVR:        "TrafficPolicyList",
+		envoyFilterV1A1:         "EnvoyFilterList",
+		rateLimitConfigGVR:      "RateLimitConfigList",
+	}
+	for gvr, kind := range managedListKinds {
+		listKinds[gvr] = kind
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for gvr, items := range map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		httpRoutesV1GVR: {rateLimitRoute("web", "infra/public"), rateLimitRoute("cart", "infra/public"), rateLimitRoute("admin", "infra/internal")},
+		backendTrafficPolicyGVR: {
+			backendTrafficPolicy("infra", "gw-rl", "Gateway", "public", 1000, "Minute"),
+			backendTrafficPolicy("shop", "web-rl", "HTTPRoute", "web", 100, "Second"),
+		},
+		trafficPolicyGVR:   {kgatewayPolicy},
+		envoyFilterV1A1:    {envoyFilter, sidecarFilter},
+		rateLimitConfigGVR: {rejected},
+	} {
+		for _, item := range items {
+			if err := client.Tracker().Create(gvr, item, item.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tool := &AnalyzeRateLimitsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"namespace": "shop", "include_logs": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := managedFindingsOf(resp)
+	for _, want := range []string{
+		"info HTTPRoute shop/web: 2 effective rate limit(s): local 100/Second (BackendTrafficPolicy shop/web-rl on HTTPRoute shop/web)",
+		"info HTTPRoute shop/cart: 3 effective rate limit(s): local 5 per 1s (burst 10) (TrafficPolicy shop/cart-rl on HTTPRoute shop/cart)",
+		"info 1 of 3 routes have no rate limit",
+		"warning RateLimitConfig shop/per-user is rejected; its limits are not enforced KGW013_RATE_LIMIT_CONFIG_REJECTED",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "sidecar-rl") {
+		t.Errorf("EnvoyFilter for sidecars attributed to the gateway:\n%s", got)
+	}
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		if f.Resource != nil && f.Resource.Name == "web" {
+			if want := "overrides local 1000/Minute (BackendTrafficPolicy infra/gw-rl on Gateway infra/public)"; !strings.Contains(f.Detail, want) {
+				t.Errorf("web detail %q lacks %q", f.Detail, want)
+			}
+		}
+	}
+}
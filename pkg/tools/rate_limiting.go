@@ -85,13 +85,7 @@ func (t *CheckRateLimitPoliciesTool) Run(ctx context.Context, args map[string]in
 }
 
 func (t *CheckRateLimitPoliciesTool) checkKgatewayTrafficPolicies(ctx context.Context, ns, service, route string) []types.DiagnosticFinding {
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(trafficPolicyGVR).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(trafficPolicyGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResource(ctx, trafficPolicyGVR, ns)
 	if err != nil {
 		return nil // CRD not installed or no access — silent
 	}
@@ -199,13 +193,7 @@ var envoyRateLimitTypeURLs = map[string]string{
 }
 
 func (t *CheckRateLimitPoliciesTool) checkIstioEnvoyFilters(ctx context.Context, ns, service string) []types.DiagnosticFinding {
-	var list *unstructured.UnstructuredList
-	var err error
-	if ns == "" {
-		list, err = t.Clients.Dynamic.Resource(envoyFilterV1A1).List(ctx, metav1.ListOptions{})
-	} else {
-		list, err = t.Clients.Dynamic.Resource(envoyFilterV1A1).Namespace(ns).List(ctx, metav1.ListOptions{})
-	}
+	list, err := t.listResource(ctx, envoyFilterV1A1, ns)
 	if err != nil {
 		return nil // CRD not installed or no access — silent
 	}
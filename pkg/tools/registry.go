@@ -1,30 +1,58 @@
 package tools
 
 import (
+	"fmt"
 	"sync"
 )
 
+// AliasedTool is implemented by tools that were renamed or absorbed another
+// tool. Registering one also makes it callable by its former names.
+type AliasedTool interface {
+	Tool
+	Aliases() []string
+}
+
 type Registry struct {
 	tools map[string]Tool
-	mu    sync.RWMutex
+	// aliases maps the former name of a tool to its current one.
+	aliases map[string]string
+	// degraded holds the RBAC permissions tools are missing (see CheckPermissions).
+	degraded map[string][]Permission
+	mu       sync.RWMutex
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		tools: make(map[string]Tool),
+		tools:    make(map[string]Tool),
+		aliases:  make(map[string]string),
+		degraded: make(map[string][]Permission),
 	}
 }
 
-func (r *Registry) Register(tool Tool) {
+// Register adds tool, callable by its name and by the deprecated aliases
+// given here or returned by its Aliases method.
+func (r *Registry) Register(tool Tool, aliases ...string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.tools[tool.Name()] = tool
+	if a, ok := tool.(AliasedTool); ok {
+		aliases = append(aliases, a.Aliases()...)
+	}
+	for _, alias := range aliases {
+		r.aliases[alias] = tool.Name()
+	}
 }
 
+// Unregister removes a tool and its aliases.
 func (r *Registry) Unregister(name string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	delete(r.tools, name)
+	for alias, target := range r.aliases {
+		if target == name {
+			delete(r.aliases, alias)
+		}
+	}
 }
 
 func (r *Registry) Get(name string) (Tool, bool) {
@@ -34,6 +62,42 @@ func (r *Registry) Get(name string) (Tool, bool) {
 	return t, ok
 }
 
+// Resolve returns the tool called name, or the tool name is a deprecated
+// alias of. A tool registered under the name itself takes precedence over
+// an alias.
+func (r *Registry) Resolve(name string) (tool Tool, alias bool, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, ok := r.tools[name]; ok {
+		return t, false, true
+	}
+	if target, ok := r.aliases[name]; ok {
+		t, ok := r.tools[target]
+		return t, ok, ok
+	}
+	return nil, false, false
+}
+
+// Aliases returns the deprecated aliases of registered tools that no tool
+// uses as its name, mapped to the name of the tool they call.
+func (r *Registry) Aliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]string, len(r.aliases))
+	for alias, target := range r.aliases {
+		if _, taken := r.tools[alias]; !taken {
+			out[alias] = target
+		}
+	}
+	return out
+}
+
+// DeprecationNote tells callers of a deprecated alias which tool to call
+// instead.
+func DeprecationNote(alias, tool string) string {
+	return fmt.Sprintf("DEPRECATED: %s was renamed to %s and will be removed in a future release. Call %s instead.", alias, tool, tool)
+}
+
 func (r *Registry) List() []Tool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -43,3 +107,22 @@ func (r *Registry) List() []Tool {
 	}
 	return result
 }
+
+// SetDegraded replaces the record of tools missing RBAC permissions.
+func (r *Registry) SetDegraded(results []ToolPermissions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.degraded = make(map[string][]Permission)
+	for _, tp := range results {
+		if tp.Degraded() {
+			r.degraded[tp.Tool] = tp.Missing
+		}
+	}
+}
+
+// Degraded returns the RBAC permissions a tool is missing, or nil.
+func (r *Registry) Degraded(name string) []Permission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.degraded[name]
+}
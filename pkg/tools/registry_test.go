@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+type renamedTool struct {
+	name    string
+	aliases []string
+}
+
+func (t *renamedTool) Name() string                        { return t.name }
+func (t *renamedTool) Description() string                 { return "" }
+func (t *renamedTool) InputSchema() map[string]interface{} { return nil }
+func (t *renamedTool) Aliases() []string                   { return t.aliases }
+func (t *renamedTool) Run(context.Context, map[string]interface{}) (*StandardResponse, error) {
+	return nil, nil
+}
+
+func TestRegistryAliases(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&renamedTool{name: "new_check", aliases: []string{"old_check"}}, "older_check")
+
+	for _, name := range []string{"old_check", "older_check"} {
+		tool, alias, ok := r.Resolve(name)
+		if !ok || !alias || tool.Name() != "new_check" {
+			t.Errorf("Resolve(%s) = %v, %v, %v", name, tool, alias, ok)
+		}
+	}
+	if _, alias, ok := r.Resolve("new_check"); !ok || alias {
+		t.Errorf("Resolve(new_check) alias=%v ok=%v", alias, ok)
+	}
+	if _, ok := r.Get("old_check"); ok {
+		t.Error("Get resolved an alias")
+	}
+	if len(r.List()) != 1 {
+		t.Errorf("List() = %d tools, aliases must not be listed", len(r.List()))
+	}
+
+	// A tool named like an alias takes precedence over it
+	r.Register(&renamedTool{name: "older_check"})
+	if tool, alias, _ := r.Resolve("older_check"); alias || tool.Name() != "older_check" {
+		t.Errorf("Resolve(older_check) = %s, alias=%v", tool.Name(), alias)
+	}
+	if got := r.Aliases(); len(got) != 1 || got["old_check"] != "new_check" {
+		t.Errorf("Aliases() = %v", got)
+	}
+
+	r.Unregister("new_check")
+	if _, _, ok := r.Resolve("old_check"); ok {
+		t.Error("alias outlived its tool")
+	}
+}
@@ -2,264 +2,290 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
+// issueTypeCodes maps the issue types suggest_remediation accepted before
+// findings carried codes to the equivalent finding code.
+var issueTypeCodes = map[string]types.FindingCode{
+	"missing_endpoints":         types.CodeServiceNoEndpoints,
+	"no_matching_pods":          types.CodeServiceSelectorNoPods,
+	"network_policy_blocking":   types.CodeNetworkPolicyBlockingTraffic,
+	"dns_failure":               types.CodeDNSLookupFailed,
+	"mtls_conflict":             types.CodeIstioMTLSConflict,
+	"route_misconfigured":       types.CodeGatewayRouteConditionFalse,
+	"missing_reference_grant":   types.CodeGatewayReferenceGrantMissing,
+	"gateway_listener_conflict": types.CodeGatewayListenerConflict,
+	"sidecar_missing":           types.CodeIstioInjectionDisabled,
+	"weight_mismatch":           types.CodeGatewayWeightsNot100,
+}
+
+// remediationKinds are the kinds whose live object is read to fill in
+// remediation manifests, with the API versions tried in order.
+var remediationKinds = map[string][]schema.GroupVersionResource{
+	"Gateway":             {gatewaysV1GVR, gatewaysV1B1GVR},
+	"HTTPRoute":           {httpRoutesV1GVR, httpRoutesV1B1GVR},
+	"GRPCRoute":           {grpcRoutesV1GVR, grpcRoutesV1B1GVR},
+	"Service":             {servicesGVR},
+	"NetworkPolicy":       {networkPoliciesGVR},
+	"AuthorizationPolicy": {apV1GVR, apV1B1GVR},
+	"DestinationRule":     {drV1GVR, drV1B1GVR},
+	"VirtualService":      {vsV1GVR, vsV1B1GVR},
+}
+
 // --- suggest_remediation ---
 
+// SuggestRemediationTool turns a finding code, or the coded findings of a
+// previous tool response, into remediation steps and fix manifests that use
+// the names of the affected resources.
 type SuggestRemediationTool struct{ BaseTool }
 
 func (t *SuggestRemediationTool) Name() string { return "suggest_remediation" }
 func (t *SuggestRemediationTool) Description() string {
-	return "Suggest remediations for identified diagnostic issues with actionable YAML fixes"
+	return "Suggest remediations with actionable YAML fixes for a finding code or for the findings of a previous tool response; manifests are filled in with the real names and specs of the affected resources"
 }
 func (t *SuggestRemediationTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "Finding code to remediate, e.g. SVC001_NO_ENDPOINTS (see list_finding_codes)",
+			},
+			"findings": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "object"},
+				"description": "Findings of a previous tool response; every warning or critical finding with a code gets a remediation for its resource",
+			},
 			"issue_type": map[string]interface{}{
 				"type":        "string",
-				"description": "Type of issue: missing_endpoints, no_matching_pods, network_policy_blocking, dns_failure, mtls_conflict, route_misconfigured, missing_reference_grant, gateway_listener_conflict, sidecar_missing, weight_mismatch",
+				"description": "Deprecated, use code. One of: missing_endpoints, no_matching_pods, network_policy_blocking, dns_failure, mtls_conflict, route_misconfigured, missing_reference_grant, gateway_listener_conflict, sidecar_missing, weight_mismatch",
 			},
 			"resource_kind": map[string]interface{}{
 				"type":        "string",
-				"description": "Kubernetes resource kind (e.g., Service, HTTPRoute, VirtualService)",
+				"description": "Kubernetes resource kind (e.g., Service, HTTPRoute, VirtualService), used with code",
 			},
 			"resource_name": map[string]interface{}{
 				"type":        "string",
-				"description": "Name of the affected resource",
+				"description": "Name of the affected resource, used with code",
 			},
 			"namespace": map[string]interface{}{
 				"type":        "string",
-				"description": "Namespace of the affected resource",
+				"description": "Namespace of the affected resource, used with code",
 			},
 			"additional_context": map[string]interface{}{
 				"type":        "string",
-				"description": "Additional context about the issue (e.g., specific error, conflicting resource)",
+				"description": "Additional context about the issue (e.g., the source namespace to allow or the backend namespace)",
 			},
 		},
-		"required": []string{"issue_type"},
 	}
 }
 
 func (t *SuggestRemediationTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
-	issueType := getStringArg(args, "issue_type", "")
-	resourceKind := getStringArg(args, "resource_kind", "")
-	resourceName := getStringArg(args, "resource_name", "")
-	ns := getStringArg(args, "namespace", "default")
 	additionalCtx := getStringArg(args, "additional_context", "")
 
-	findings := make([]types.DiagnosticFinding, 0, 3)
-
-	ref := &types.ResourceRef{
-		Kind:      resourceKind,
-		Namespace: ns,
-		Name:      resourceName,
-	}
-
-	switch strings.ToLower(issueType) {
-	case "missing_endpoints", "no_matching_pods":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  fmt.Sprintf("Service %s/%s has no matching endpoints", ns, resourceName),
-			Detail: fmt.Sprintf(`Remediation steps:
-1. Verify pods exist: kubectl get pods -n %s -l app=%s
-2. Check selector labels match pod template labels
-3. Ensure pods are in Running state
-4. Verify the service port matches a container port`, ns, resourceName),
-			Suggestion: fmt.Sprintf(`# Verify selector match
-kubectl get svc %s -n %s -o jsonpath='{.spec.selector}'
-kubectl get pods -n %s --show-labels
-
-# Check endpoints
-kubectl get endpoints %s -n %s`, resourceName, ns, ns, resourceName, ns),
-		})
-
-	case "network_policy_blocking":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
-			Category: types.CategoryPolicy,
-			Resource: ref,
-			Summary:  fmt.Sprintf("NetworkPolicy may be blocking traffic to %s/%s", ns, resourceName),
-			Detail: `Remediation steps:
-1. Review existing NetworkPolicies for the namespace
-2. Ensure ingress rules allow traffic from the source
-3. Verify egress rules allow DNS (port 53) and the target port`,
-			Suggestion: fmt.Sprintf(`# Example: Allow ingress from specific namespace
-apiVersion: networking.k8s.io/v1
-kind: NetworkPolicy
-metadata:
-  name: allow-ingress-%s
-  namespace: %s
-spec:
-  podSelector:
-    matchLabels:
-      app: %s
-  policyTypes:
-  - Ingress
-  ingress:
-  - from:
-    - namespaceSelector:
-        matchLabels:
-          kubernetes.io/metadata.name: %s  # source namespace
-    ports:
-    - protocol: TCP
-      port: 80  # adjust to your service port`, resourceName, ns, resourceName, additionalCtx),
-		})
-
-	case "dns_failure":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityCritical,
-			Category: types.CategoryDNS,
-			Resource: ref,
-			Summary:  "DNS resolution failure",
-			Detail: `Remediation steps:
-1. Verify CoreDNS pods are running: kubectl get pods -n kube-system -l k8s-app=kube-dns
-2. Check CoreDNS logs for errors: kubectl logs -n kube-system -l k8s-app=kube-dns
-3. Verify the service exists in the expected namespace
-4. Check NetworkPolicies are not blocking DNS traffic (UDP/TCP port 53)`,
-			Suggestion: `# Check CoreDNS health
-kubectl get pods -n kube-system -l k8s-app=kube-dns
-kubectl logs -n kube-system -l k8s-app=kube-dns --tail=50
-
-# Verify service DNS name format: <service>.<namespace>.svc.cluster.local`,
+	var targets []remediationTarget
+	uncoded := 0
+	ns := ""
+	if raw, ok := args["findings"]; ok && raw != nil {
+		source, err := decodeFindings(raw)
+		if err != nil {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "findings must be the findings array of a tool response",
+				Detail:  err.Error(),
+			}
+		}
+		seen := make(map[string]bool)
+		for _, f := range source {
+			if f.Severity != types.SeverityWarning && f.Severity != types.SeverityCritical {
+				continue
+			}
+			if _, known := types.LookupFindingCode(f.Code); !known {
+				uncoded++
+				continue
+			}
+			r := remediationTarget{code: f.Code, severity: f.Severity, summary: f.Summary, context: additionalCtx}
+			if f.Resource != nil {
+				r.kind, r.namespace, r.name = f.Resource.Kind, f.Resource.Namespace, f.Resource.Name
+			}
+			// Tools report one finding per rule or backend; one fix per resource is enough.
+			key := string(f.Code) + "|" + r.kind + "|" + r.namespace + "|" + r.name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, r)
+		}
+	} else {
+		ns = getStringArg(args, "namespace", "default")
+		code := types.FindingCode(strings.ToUpper(getStringArg(args, "code", "")))
+		if issueType := getStringArg(args, "issue_type", ""); code == "" && issueType != "" {
+			code = issueTypeCodes[strings.ToLower(issueType)]
+			if code == "" {
+				return nil, &types.MCPError{
+					Code:    types.ErrCodeInvalidInput,
+					Tool:    t.Name(),
+					Message: fmt.Sprintf("unknown issue_type %q", issueType),
+					Detail:  "expected one of " + joinKeys(issueTypeKeys()) + ", or pass a finding code as code",
+				}
+			}
+		}
+		if code == "" {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "one of code or findings is required",
+			}
+		}
+		if _, known := types.LookupFindingCode(code); !known {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("unknown finding code %q", code),
+				Detail:  "run list_finding_codes for the valid codes",
+			}
+		}
+		targets = append(targets, remediationTarget{
+			code:      code,
+			severity:  types.SeverityWarning,
+			kind:      getStringArg(args, "resource_kind", ""),
+			namespace: ns,
+			name:      getStringArg(args, "resource_name", ""),
+			context:   additionalCtx,
 		})
+	}
 
-	case "mtls_conflict":
+	findings := make([]types.DiagnosticFinding, 0, len(targets)+1)
+	for _, r := range targets {
+		findings = append(findings, t.remediate(ctx, r))
+	}
+	if uncoded > 0 {
 		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityCritical,
-			Category: types.CategoryTLS,
-			Resource: ref,
-			Summary:  fmt.Sprintf("mTLS configuration conflict in %s", ns),
-			Detail: `Remediation steps:
-1. Review PeerAuthentication policies for the namespace
-2. Check DestinationRule TLS settings for conflicting modes
-3. Ensure STRICT mTLS has matching DestinationRule with ISTIO_MUTUAL`,
-			Suggestion: fmt.Sprintf(`# Align DestinationRule TLS with PeerAuthentication
-apiVersion: networking.istio.io/v1
-kind: DestinationRule
-metadata:
-  name: %s-mtls
-  namespace: %s
-spec:
-  host: %s
-  trafficPolicy:
-    tls:
-      mode: ISTIO_MUTUAL`, resourceName, ns, resourceName),
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Summary:    fmt.Sprintf("%d warning or critical findings have no finding code and got no remediation", uncoded),
+			Suggestion: "Follow the suggestion of those findings directly.",
 		})
-
-	case "route_misconfigured":
+	}
+	if len(findings) == 0 {
 		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
+			Severity: types.SeverityOK,
 			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  fmt.Sprintf("Route misconfiguration on %s %s/%s", resourceKind, ns, resourceName),
-			Detail: `Remediation steps:
-1. Verify backend service references exist
-2. Check port numbers match the target service
-3. Verify parentRef (Gateway) exists and has matching listeners
-4. Check for conflicting route rules`,
-			Suggestion: fmt.Sprintf(`# Verify backend service exists
-kubectl get svc -n %s
-# Check route status
-kubectl get %s %s -n %s -o yaml | grep -A 20 status`, ns, strings.ToLower(resourceKind), resourceName, ns),
+			Summary:  "No warning or critical findings to remediate",
 		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
 
-	case "missing_reference_grant":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  "Cross-namespace reference missing ReferenceGrant",
-			Detail:   "A route references a backend service in a different namespace without a ReferenceGrant allowing the cross-namespace reference.",
-			Suggestion: fmt.Sprintf(`# Create ReferenceGrant in the target service namespace
-apiVersion: gateway.networking.k8s.io/v1beta1
-kind: ReferenceGrant
-metadata:
-  name: allow-cross-ns-%s
-  namespace: %s  # namespace of the target service
-spec:
-  from:
-  - group: gateway.networking.k8s.io
-    kind: HTTPRoute
-    namespace: %s  # namespace of the route
-  to:
-  - group: ""
-    kind: Service`, resourceName, ns, additionalCtx),
-		})
+// remediate builds the remediation finding for r from the catalog, reading
+// the live resource first so manifests match its current spec and naming its
+// GitOps owner when it has one.
+func (t *SuggestRemediationTool) remediate(ctx context.Context, r remediationTarget) types.DiagnosticFinding {
+	info, _ := types.LookupFindingCode(r.code)
+	r.obj = t.liveObject(ctx, r.kind, r.namespace, r.name)
+	r.serviceExists = func(ns, name string) bool { return t.serviceExists(ctx, ns, name) }
 
-	case "gateway_listener_conflict":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  "Gateway listener conflict (port/protocol collision)",
-			Detail: `Remediation steps:
-1. Check for multiple listeners on the same port with different protocols
-2. Ensure hostnames are unique across listeners on the same port
-3. Review Gateway status conditions for conflict details`,
-			Suggestion: `# Check Gateway status for conflict details
-kubectl get gateway <gateway-name> -n <namespace> -o yaml | grep -A 30 status`,
-		})
+	build, ok := remediationCatalog[r.code]
+	if !ok {
+		build = genericRemediation
+	}
+	steps, fix := build(&r)
+	// Resources applied by Argo CD or Flux must be fixed in Git: a kubectl
+	// edit is reverted or reported as drift.
+	if owner := t.gitopsOwnerOf(ctx, r.obj); owner != nil {
+		steps = owner.fixInGit() + "\n\n" + steps
+	}
 
-	case "sidecar_missing":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityWarning,
-			Category: types.CategoryMesh,
-			Resource: ref,
-			Summary:  fmt.Sprintf("Sidecar injection missing for workloads in %s", ns),
-			Detail: `Remediation steps:
-1. Label the namespace for injection: kubectl label namespace <ns> istio-injection=enabled
-2. Restart deployments to trigger injection: kubectl rollout restart deployment -n <ns>
-3. Verify injection: kubectl get pods -n <ns> -o jsonpath='{.items[*].spec.containers[*].name}'`,
-			Suggestion: fmt.Sprintf(`# Enable sidecar injection
-kubectl label namespace %s istio-injection=enabled --overwrite
-# Restart deployments
-kubectl rollout restart deployment -n %s`, ns, ns),
-		})
+	f := types.DiagnosticFinding{
+		Severity:   r.severity,
+		Category:   info.Category,
+		Code:       r.code,
+		Summary:    r.summary,
+		Detail:     steps,
+		Suggestion: fix,
+	}
+	if f.Summary == "" {
+		f.Summary = info.Description
+		if r.name != "" {
+			f.Summary += fmt.Sprintf(" (%s %s)", orDefault(r.kind, "resource"), qualifiedName(r.namespace, r.name))
+		}
+	}
+	if r.name != "" {
+		f.Resource = &types.ResourceRef{Kind: r.kind, Namespace: r.namespace, Name: r.name}
+		if r.obj != nil {
+			f.Resource.APIVersion = r.obj.GetAPIVersion()
+		}
+	}
+	return f
+}
 
-	case "weight_mismatch":
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityCritical,
-			Category: types.CategoryRouting,
-			Resource: ref,
-			Summary:  "Traffic split weights do not sum to 100%",
-			Detail:   "VirtualService or HTTPRoute weight configuration is invalid. Weights must sum to exactly 100.",
-			Suggestion: `# Fix weights in VirtualService to sum to 100
-# Example: 80/20 split
-spec:
-  http:
-  - route:
-    - destination:
-        host: my-service
-        subset: v1
-      weight: 80
-    - destination:
-        host: my-service
-        subset: v2
-      weight: 20`,
-		})
+// liveObject fetches the affected resource, or returns nil when its kind is
+// not one remediations read or it cannot be fetched.
+func (t *SuggestRemediationTool) liveObject(ctx context.Context, kind, ns, name string) *unstructured.Unstructured {
+	gvrs, ok := remediationKinds[kind]
+	if !ok || name == "" || ns == "" || t.Clients == nil || t.Clients.Dynamic == nil {
+		return nil
+	}
+	var obj *unstructured.Unstructured
+	var err error
+	if len(gvrs) == 2 {
+		obj, err = getWithFallback(ctx, t.Clients.Dynamic, gvrs[0], gvrs[1], ns, name)
+	} else {
+		obj, err = t.Clients.Dynamic.Resource(gvrs[0]).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil
+	}
+	return obj
+}
 
-	default:
-		findings = append(findings, types.DiagnosticFinding{
-			Severity: types.SeverityInfo,
-			Category: types.CategoryRouting,
-			Summary:  fmt.Sprintf("General remediation guidance for issue: %s", issueType),
-			Detail: fmt.Sprintf(`General diagnostic steps:
-1. Check resource exists: kubectl get %s %s -n %s
-2. Review resource status: kubectl describe %s %s -n %s
-3. Check events: kubectl get events -n %s --sort-by='.lastTimestamp'
-4. Review logs of related controllers
+// serviceExists reports whether the Service ns/name exists. Without a client
+// every Service is assumed to exist.
+func (t *SuggestRemediationTool) serviceExists(ctx context.Context, ns, name string) bool {
+	if t.Clients == nil || t.Clients.Dynamic == nil {
+		return true
+	}
+	_, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	return err == nil
+}
 
-Additional context: %s`, strings.ToLower(resourceKind), resourceName, ns,
-				strings.ToLower(resourceKind), resourceName, ns, ns, additionalCtx),
-		})
+// decodeFindings accepts the findings array of a tool response, either as
+// decoded JSON or as a JSON string, or a whole ToolResult object.
+func decodeFindings(raw interface{}) ([]types.DiagnosticFinding, error) {
+	var data []byte
+	if s, ok := raw.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+	var findings []types.DiagnosticFinding
+	if err := json.Unmarshal(data, &findings); err == nil {
+		return findings, nil
+	}
+	var result types.ToolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
 	}
+	return result.Findings, nil
+}
 
-	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+func issueTypeKeys() map[string]bool {
+	keys := make(map[string]bool, len(issueTypeCodes))
+	for k := range issueTypeCodes {
+		keys[k] = true
+	}
+	return keys
 }
@@ -0,0 +1,549 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// remediationTarget is one affected resource to remediate. obj is its live
+// object when it could be read; builders fall back to placeholders without it.
+type remediationTarget struct {
+	code                  types.FindingCode
+	severity              string
+	kind, namespace, name string
+	// summary is the summary of the source finding, when there is one.
+	summary string
+	// context is the caller's additional_context.
+	context       string
+	obj           *unstructured.Unstructured
+	serviceExists func(ns, name string) bool
+}
+
+func (r *remediationTarget) ns() string { return orDefault(r.namespace, "<namespace>") }
+
+func (r *remediationTarget) nameOr(placeholder string) string {
+	return orDefault(r.name, "<"+placeholder+">")
+}
+
+// remediationFunc returns the remediation steps and the fix, as commands or
+// a manifest, for r.
+type remediationFunc func(r *remediationTarget) (steps, fix string)
+
+// remediationCatalog holds the tailored remediations by finding code. Codes
+// without an entry get genericRemediation.
+var remediationCatalog = map[types.FindingCode]remediationFunc{
+	types.CodeServiceNoEndpoints:           serviceEndpointsRemediation,
+	types.CodeServiceSelectorNoPods:        serviceEndpointsRemediation,
+	types.CodeNetworkPolicyDenyAllIngress:  networkPolicyRemediation,
+	types.CodeNetworkPolicyBlockingTraffic: networkPolicyRemediation,
+	types.CodeDNSLookupFailed:              dnsRemediation,
+	types.CodeDNSKubeDNSNoEndpoints:        dnsRemediation,
+	types.CodeIstioMTLSConflict:            mtlsRemediation,
+	types.CodeIstioInjectionDisabled:       injectionRemediation,
+	types.CodeIstioSubsetMissing:           subsetRemediation,
+	types.CodeIstioAuthzDenyAll:            authzRemediation,
+	types.CodeIstioAuthzAllowNothing:       authzRemediation,
+	types.CodeIstioWeightsNot100:           weightsRemediation,
+	types.CodeGatewayListenerConflict:      listenerConflictRemediation,
+	types.CodeGatewayRouteConditionFalse:   routeStatusRemediation,
+	types.CodeGatewayBackendServiceMissing: backendServiceRemediation,
+	types.CodeGatewayReferenceGrantMissing: referenceGrantRemediation,
+	types.CodeGatewayWeightsNot100:         weightsRemediation,
+}
+
+// selectorYAML renders labels as a matchLabels block indented by indent, or
+// {} to select all pods.
+func selectorYAML(labels map[string]string, indent string) string {
+	if len(labels) == 0 {
+		return " {}"
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("\n" + indent + "matchLabels:")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s  %s: %s", indent, k, labels[k])
+	}
+	return b.String()
+}
+
+func genericRemediation(r *remediationTarget) (string, string) {
+	info, _ := types.LookupFindingCode(r.code)
+	kind := strings.ToLower(orDefault(r.kind, "<kind>"))
+	name, ns := r.nameOr("name"), r.ns()
+	steps := fmt.Sprintf(`Remediation steps for %s (%s):
+1. Check the resource exists: kubectl get %s %s -n %s
+2. Review its status and events: kubectl describe %s %s -n %s
+3. Check recent events: kubectl get events -n %s --sort-by='.lastTimestamp'
+4. Review the logs of the controller that reconciles it`, r.code, info.Description, kind, name, ns, kind, name, ns, ns)
+	if r.context != "" {
+		steps += "\n\nAdditional context: " + r.context
+	}
+	return steps, fmt.Sprintf("kubectl describe %s %s -n %s", kind, name, ns)
+}
+
+func serviceEndpointsRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("service"), r.ns()
+	selector := "app=" + name
+	if r.obj != nil {
+		if sel, _, _ := unstructured.NestedStringMap(r.obj.Object, "spec", "selector"); len(sel) > 0 {
+			selector = formatSelector(sel)
+		}
+	}
+	steps := fmt.Sprintf(`Remediation steps:
+1. List the pods the selector matches: kubectl get pods -n %s -l %s
+2. Compare the selector with the pod template labels of the workload
+3. Ensure the pods are Running and pass their readiness probes
+4. Verify the Service targetPort matches a container port`, ns, selector)
+	fix := fmt.Sprintf(`# Pods selected by the Service and their readiness
+kubectl get pods -n %s -l %s -o wide
+
+# Endpoints published for the Service
+kubectl get endpointslices -n %s -l kubernetes.io/service-name=%s
+
+# Pod template labels of the workloads
+kubectl get deploy,statefulset -n %s -o custom-columns=NAME:.metadata.name,LABELS:.spec.template.metadata.labels`, ns, selector, ns, name, ns)
+	return steps, fix
+}
+
+func networkPolicyRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("app"), r.ns()
+	podLabels := map[string]string{"app": name}
+	port := "80  # adjust to the target port"
+	if r.obj != nil {
+		switch r.kind {
+		case "NetworkPolicy":
+			podLabels, _, _ = unstructured.NestedStringMap(r.obj.Object, "spec", "podSelector", "matchLabels")
+		case "Service":
+			podLabels, _, _ = unstructured.NestedStringMap(r.obj.Object, "spec", "selector")
+			if ports, _, _ := unstructured.NestedSlice(r.obj.Object, "spec", "ports"); len(ports) > 0 {
+				if pm, ok := ports[0].(map[string]interface{}); ok {
+					target, ok := pm["targetPort"]
+					if !ok {
+						target = pm["port"]
+					}
+					port = fmt.Sprint(target)
+				}
+			}
+		}
+	}
+	source := orDefault(r.context, "<source-namespace>")
+
+	steps := fmt.Sprintf(`Remediation steps:
+1. List the policies that select the pods: kubectl get networkpolicy -n %s
+2. Allow the expected clients with an additional policy (below), or narrow the blocking policy
+3. Verify egress policies of the clients allow DNS (port 53) and the target port`, ns)
+	fix := fmt.Sprintf(`# Allow ingress from namespace %s
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-ingress-%s
+  namespace: %s
+spec:
+  podSelector:%s
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: %s
+    ports:
+    - protocol: TCP
+      port: %s`, source, name, ns, selectorYAML(podLabels, "    "), source, port)
+	return steps, fix
+}
+
+func dnsRemediation(r *remediationTarget) (string, string) {
+	host := "<service>.<namespace>.svc.cluster.local"
+	if r.kind == "Service" && r.name != "" {
+		host = r.name + "." + r.ns() + ".svc.cluster.local"
+	}
+	steps := `Remediation steps:
+1. Verify CoreDNS pods are running: kubectl get pods -n kube-system -l k8s-app=kube-dns
+2. Check CoreDNS logs for errors: kubectl logs -n kube-system -l k8s-app=kube-dns
+3. Verify the service exists in the expected namespace
+4. Check NetworkPolicies are not blocking DNS traffic (UDP/TCP port 53)`
+	fix := fmt.Sprintf(`# Check CoreDNS health
+kubectl get pods -n kube-system -l k8s-app=kube-dns
+kubectl get endpointslices -n kube-system -l kubernetes.io/service-name=kube-dns
+kubectl logs -n kube-system -l k8s-app=kube-dns --tail=50
+
+# Resolve the name from inside the cluster
+kubectl run dns-test --rm -it --restart=Never --image=busybox:1.36 -- nslookup %s`, host)
+	return steps, fix
+}
+
+func mtlsRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("service"), r.ns()
+	host, drName := name, name+"-mtls"
+	switch r.kind {
+	case "Service":
+		host = name + "." + ns + ".svc.cluster.local"
+	case "DestinationRule":
+		drName = name
+		if r.obj != nil {
+			host, _, _ = unstructured.NestedString(r.obj.Object, "spec", "host")
+		}
+	}
+	steps := fmt.Sprintf(`Remediation steps:
+1. Review PeerAuthentication policies: kubectl get peerauthentication -n %s
+2. Check DestinationRule TLS settings for %s: kubectl get destinationrule -n %s
+3. Ensure STRICT mTLS has a matching DestinationRule with ISTIO_MUTUAL`, ns, host, ns)
+	fix := fmt.Sprintf(`# Align DestinationRule TLS with PeerAuthentication
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  host: %s
+  trafficPolicy:
+    tls:
+      mode: ISTIO_MUTUAL`, drName, ns, host)
+	return steps, fix
+}
+
+func injectionRemediation(r *remediationTarget) (string, string) {
+	ns := r.ns()
+	if r.kind == "Namespace" && r.name != "" {
+		ns = r.name
+	}
+	steps := fmt.Sprintf(`Remediation steps:
+1. Label the namespace for injection: kubectl label namespace %s istio-injection=enabled
+2. Restart deployments to trigger injection: kubectl rollout restart deployment -n %s
+3. Verify injection: kubectl get pods -n %s -o jsonpath='{.items[*].spec.containers[*].name}'`, ns, ns, ns)
+	fix := fmt.Sprintf(`# Enable sidecar injection
+kubectl label namespace %s istio-injection=enabled --overwrite
+# Restart deployments
+kubectl rollout restart deployment -n %s`, ns, ns)
+	return steps, fix
+}
+
+var (
+	subsetPattern     = regexp.MustCompile(`subset "([^"]+)"`)
+	subsetHostPattern = regexp.MustCompile(` (?:of|for) ([a-z0-9][a-z0-9.-]*)`)
+)
+
+func subsetRemediation(r *remediationTarget) (string, string) {
+	ns := r.ns()
+	subset, host := "<subset>", "<host>"
+	if m := subsetPattern.FindStringSubmatch(r.summary); m != nil {
+		subset = m[1]
+	}
+	if m := subsetHostPattern.FindStringSubmatch(r.summary); m != nil {
+		host = m[1]
+	}
+	_, svc := resolveIstioHost(host, ns)
+	steps := fmt.Sprintf(`Remediation steps:
+1. Find the DestinationRule for %s: kubectl get destinationrule -n %s
+2. Add subset %q to it (keep the existing subsets), or fix the subset name in VirtualService %s
+3. Check pods carry the subset labels: kubectl get pods -n %s -l version=%s`, host, ns, subset, qualifiedName(r.namespace, r.name), ns, subset)
+	fix := fmt.Sprintf(`# Define the subset referenced by the VirtualService
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  host: %s
+  subsets:
+  - name: %s
+    labels:
+      version: %s  # labels of the pods in this subset`, svc, ns, host, subset, subset)
+	return steps, fix
+}
+
+func authzRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("policy"), r.ns()
+	selector := ""
+	if r.obj != nil {
+		if labels, _, _ := unstructured.NestedStringMap(r.obj.Object, "spec", "selector", "matchLabels"); len(labels) > 0 {
+			selector = "\n  selector:" + selectorYAML(labels, "    ")
+		}
+	}
+	source := orDefault(r.context, "<client-namespace>")
+
+	if r.code == types.CodeIstioAuthzDenyAll {
+		steps := fmt.Sprintf(`Remediation steps:
+1. Confirm the policy is not intended as a lockdown: kubectl get authorizationpolicy %s -n %s -o yaml
+2. Scope the DENY to the traffic to block with rules (below), or delete it: kubectl delete authorizationpolicy %s -n %s`, name, ns, name, ns)
+		fix := fmt.Sprintf(`# Deny only traffic from outside namespace %s
+apiVersion: security.istio.io/v1
+kind: AuthorizationPolicy
+metadata:
+  name: %s
+  namespace: %s
+spec:%s
+  action: DENY
+  rules:
+  - from:
+    - source:
+        notNamespaces:
+        - %s`, source, name, ns, selector, source)
+		return steps, fix
+	}
+
+	steps := fmt.Sprintf(`Remediation steps:
+1. An ALLOW policy without rules allows nothing: list the clients of the selected workloads
+2. Add a rule per client (below), or delete the policy: kubectl delete authorizationpolicy %s -n %s`, name, ns)
+	fix := fmt.Sprintf(`# Allow traffic from namespace %s
+apiVersion: security.istio.io/v1
+kind: AuthorizationPolicy
+metadata:
+  name: %s
+  namespace: %s
+spec:%s
+  action: ALLOW
+  rules:
+  - from:
+    - source:
+        namespaces:
+        - %s`, source, name, ns, selector, source)
+	return steps, fix
+}
+
+func listenerConflictRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("gateway"), r.ns()
+	steps := fmt.Sprintf(`Remediation steps:
+1. Check for multiple listeners on the same port with different protocols
+2. Ensure hostnames are unique across listeners on the same port
+3. Review the Conflicted condition in the status of Gateway %s/%s`, ns, name)
+	if r.obj != nil {
+		byPort := make(map[int64][]string)
+		listeners, _, _ := unstructured.NestedSlice(r.obj.Object, "spec", "listeners")
+		for _, l := range listeners {
+			lm, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			port, _, _ := unstructured.NestedInt64(lm, "port")
+			lName, _ := lm["name"].(string)
+			protocol, _ := lm["protocol"].(string)
+			hostname, _ := lm["hostname"].(string)
+			byPort[port] = append(byPort[port], fmt.Sprintf("%s (%s, %s)", lName, protocol, orDefault(hostname, "any host")))
+		}
+		ports := make([]int64, 0, len(byPort))
+		for p, ls := range byPort {
+			if len(ls) > 1 {
+				ports = append(ports, p)
+			}
+		}
+		sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+		for _, p := range ports {
+			steps += fmt.Sprintf("\nListeners on port %d: %s", p, strings.Join(byPort[p], ", "))
+		}
+	}
+	fix := fmt.Sprintf(`# Listener status with conflict details
+kubectl get gateway %s -n %s -o jsonpath='{range .status.listeners[*]}{.name}{": "}{range .conditions[*]}{.type}={.status} {.reason} {end}{"\n"}{end}'
+# Move conflicting listeners to distinct ports or hostnames
+kubectl edit gateway %s -n %s`, name, ns, name, ns)
+	return steps, fix
+}
+
+func routeStatusRemediation(r *remediationTarget) (string, string) {
+	kind := strings.ToLower(orDefault(r.kind, "httproute"))
+	name, ns := r.nameOr("route"), r.ns()
+	steps := `Remediation steps:
+1. Read the reason of the False condition for each parent
+2. NotAllowedByListeners or NoMatchingListenerHostname: check the listener allowedRoutes and hostnames
+3. NoMatchingParent: check the parentRef name, namespace and sectionName
+4. BackendNotFound or RefNotPermitted: create the backend Service or a ReferenceGrant`
+	if r.obj != nil {
+		if status, bad := extractRouteStatusSuffix(r.obj.Object); bad {
+			steps += "\n\nCurrent status:" + status
+		}
+	}
+	fix := fmt.Sprintf(`# Route status per parent
+kubectl get %s %s -n %s -o jsonpath='{range .status.parents[*]}{.parentRef.name}{": "}{range .conditions[*]}{.type}={.status} {.reason} {end}{"\n"}{end}'
+kubectl describe %s %s -n %s`, kind, name, ns, kind, name, ns)
+	return steps, fix
+}
+
+func backendServiceRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("route"), r.ns()
+	var missing []string
+	if r.obj != nil {
+		for _, backend := range routeBackends(routeInfo{kind: r.kind, name: r.name, namespace: r.namespace, obj: r.obj.Object}) {
+			parts := strings.SplitN(backend, "/", 2)
+			if !r.serviceExists(parts[0], parts[1]) {
+				missing = append(missing, backend)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		missing = []string{ns + "/<backend-service>"}
+	}
+
+	steps := fmt.Sprintf(`Remediation steps:
+1. Check whether the backend was renamed or lives in another namespace: kubectl get svc -A
+2. Fix the backendRef name and namespace in %s %s/%s, or create the Service (below)
+3. Cross-namespace backends also need a ReferenceGrant in the backend namespace`, orDefault(r.kind, "route"), ns, name)
+	manifests := make([]string, 0, len(missing))
+	for _, backend := range missing {
+		parts := strings.SplitN(backend, "/", 2)
+		manifests = append(manifests, fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  selector:
+    app: %s  # labels of the backend pods
+  ports:
+  - port: 80  # port of the backendRef
+    targetPort: 8080  # container port`, parts[1], parts[0], parts[1]))
+	}
+	return steps, strings.Join(manifests, "\n---\n")
+}
+
+func referenceGrantRemediation(r *remediationTarget) (string, string) {
+	kind := orDefault(r.kind, "HTTPRoute")
+	ns := r.ns()
+	// targets maps each backend namespace to the Services referenced there.
+	targets := make(map[string][]string)
+	if r.obj != nil {
+		for _, backend := range routeBackends(routeInfo{kind: kind, name: r.name, namespace: r.namespace, obj: r.obj.Object}) {
+			parts := strings.SplitN(backend, "/", 2)
+			if parts[0] != r.namespace {
+				targets[parts[0]] = append(targets[parts[0]], parts[1])
+			}
+		}
+	}
+	if len(targets) == 0 {
+		targets[orDefault(r.context, "<backend-namespace>")] = nil
+	}
+	namespaces := make([]string, 0, len(targets))
+	for n := range targets {
+		namespaces = append(namespaces, n)
+	}
+	sort.Strings(namespaces)
+
+	steps := fmt.Sprintf(`Remediation steps:
+1. A ReferenceGrant in the backend namespace must allow %s from namespace %s
+2. Apply one ReferenceGrant per backend namespace (below): %s
+3. Check the route's ResolvedRefs condition turns True`, kind, ns, strings.Join(namespaces, ", "))
+	manifests := make([]string, 0, len(namespaces))
+	for _, target := range namespaces {
+		to := `
+  - group: ""
+    kind: Service`
+		if names := targets[target]; len(names) > 0 {
+			to = ""
+			for _, svc := range names {
+				to += fmt.Sprintf(`
+  - group: ""
+    kind: Service
+    name: %s`, svc)
+			}
+		}
+		manifests = append(manifests, fmt.Sprintf(`apiVersion: gateway.networking.k8s.io/v1beta1
+kind: ReferenceGrant
+metadata:
+  name: allow-%s-from-%s
+  namespace: %s
+spec:
+  from:
+  - group: gateway.networking.k8s.io
+    kind: %s
+    namespace: %s
+  to:%s`, strings.ToLower(kind), ns, target, kind, ns, to))
+	}
+	return steps, strings.Join(manifests, "\n---\n")
+}
+
+// rescaleWeights scales weights to sum to 100, giving the rounding remainder
+// to the heaviest backend. All-zero weights are split evenly.
+func rescaleWeights(weights []int) []int {
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	out := make([]int, len(weights))
+	heaviest, total := 0, 0
+	for i, w := range weights {
+		if sum == 0 {
+			out[i] = 100 / len(weights)
+		} else {
+			out[i] = w * 100 / sum
+		}
+		total += out[i]
+		if w > weights[heaviest] {
+			heaviest = i
+		}
+	}
+	out[heaviest] += 100 - total
+	return out
+}
+
+func weightsRemediation(r *remediationTarget) (string, string) {
+	name, ns := r.nameOr("name"), r.ns()
+	steps := `Remediation steps:
+1. Decide the intended split between the backends of each rule
+2. Set weights that sum to exactly 100 per rule (the patch below keeps the current ratios)`
+
+	// rulesPath and backendsKey locate the weighted backends of each rule.
+	rulesPath, backendsKey := []string{"spec", "rules"}, "backendRefs"
+	if r.kind == "VirtualService" {
+		rulesPath, backendsKey = []string{"spec", "http"}, "route"
+	}
+	var ops []string
+	if r.obj != nil {
+		rules, _, _ := unstructured.NestedSlice(r.obj.Object, rulesPath...)
+		for ri, rule := range rules {
+			rm, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			backends, _ := rm[backendsKey].([]interface{})
+			if len(backends) < 2 {
+				continue
+			}
+			weights := make([]int, len(backends))
+			sum := 0
+			for bi, b := range backends {
+				bm, _ := b.(map[string]interface{})
+				if w, ok := bm["weight"]; ok {
+					weights[bi] = toInt(w)
+				} else if r.kind != "VirtualService" {
+					// Gateway API backends default to weight 1.
+					weights[bi] = 1
+				}
+				sum += weights[bi]
+			}
+			if sum == 100 {
+				continue
+			}
+			for bi, w := range rescaleWeights(weights) {
+				ops = append(ops, fmt.Sprintf(`{"op":"add","path":"/%s/%d/%s/%d/weight","value":%d}`, strings.Join(rulesPath, "/"), ri, backendsKey, bi, w))
+			}
+		}
+	}
+	if len(ops) > 0 {
+		return steps, fmt.Sprintf(`# Rescale the weights of %s %s/%s to sum to 100
+kubectl patch %s %s -n %s --type=json -p '[%s]'`, r.kind, ns, name, strings.ToLower(r.kind), name, ns, strings.Join(ops, ","))
+	}
+
+	host := orDefault(r.name, "my-service")
+	return steps, fmt.Sprintf(`# Example: 80/20 split that sums to 100
+spec:
+  http:
+  - route:
+    - destination:
+        host: %s
+        subset: v1
+      weight: 80
+    - destination:
+        host: %s
+        subset: v2
+      weight: 20`, host, host)
+}
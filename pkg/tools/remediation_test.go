@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestRescaleWeights(t *testing.T) {
+	cases := []struct {
+		in, want []int
+	}{
+		{[]int{30, 30}, []int{50, 50}},
+		{[]int{1, 1, 1}, []int{34, 33, 33}},
+		{[]int{90, 30}, []int{75, 25}},
+		{[]int{0, 0}, []int{50, 50}},
+	}
+	for _, c := range cases {
+		if got := rescaleWeights(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("rescaleWeights(%v) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func newRemediationTool(t *testing.T) *SuggestRemediationTool {
+	t.Helper()
+	obj := func(apiVersion, kind, ns, name string, spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	web := obj("v1", "Service", "shop", "web", map[string]interface{}{
+		"selector": map[string]interface{}{"tier": "front", "app": "web"},
+		"ports":    []interface{}{map[string]interface{}{"port": int64(80), "targetPort": int64(8080)}},
+	})
+	route := obj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+			map[string]interface{}{"name": "web", "weight": int64(30)},
+			map[string]interface{}{"name": "api", "namespace": "payments", "weight": int64(30)},
+			map[string]interface{}{"name": "cart", "namespace": "payments", "weight": int64(0)},
+		}}},
+	})
+	api := obj("v1", "Service", "payments", "api", map[string]interface{}{})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		servicesGVR: "ServiceList", httpRoutesV1GVR: "HTTPRouteList", httpRoutesV1B1GVR: "HTTPRouteList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, web, route, api)
+	return &SuggestRemediationTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+}
+
+func TestSuggestRemediationByCode(t *testing.T) {
+	tool := newRemediationTool(t)
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{
+		"code": "svc001_no_endpoints", "resource_kind": "Service", "resource_name": "web", "namespace": "shop",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 1 || findings[0].Code != types.CodeServiceNoEndpoints {
+		t.Fatalf("unexpected findings %+v", findings)
+	}
+	if !strings.Contains(findings[0].Suggestion, "kubectl get pods -n shop -l app=web,tier=front") {
+		t.Errorf("fix does not use the live selector:\n%s", findings[0].Suggestion)
+	}
+
+	// Legacy issue types map to codes.
+	resp, err = tool.Run(context.Background(), map[string]interface{}{
+		"issue_type": "network_policy_blocking", "resource_kind": "Service", "resource_name": "web", "namespace": "shop", "additional_context": "frontend",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := resp.Data.(*types.ToolResult).Findings[0]
+	if f.Code != types.CodeNetworkPolicyBlockingTraffic {
+		t.Errorf("issue_type mapped to %s", f.Code)
+	}
+	for _, want := range []string{"matchLabels:\n      app: web\n      tier: front", "kubernetes.io/metadata.name: frontend", "port: 8080"} {
+		if !strings.Contains(f.Suggestion, want) {
+			t.Errorf("NetworkPolicy manifest lacks %q:\n%s", want, f.Suggestion)
+		}
+	}
+
+	var mcpErr *types.MCPError
+	for _, args := range []map[string]interface{}{{"code": "XYZ001_NOPE"}, {"issue_type": "nope"}, {}} {
+		if _, err := tool.Run(context.Background(), args); !errors.As(err, &mcpErr) || mcpErr.Code != types.ErrCodeInvalidInput {
+			t.Errorf("%v: expected invalid input error, got %v", args, err)
+		}
+	}
+}
+
+func TestSuggestRemediationFromFindings(t *testing.T) {
+	tool := newRemediationTool(t)
+	routeRef := &types.ResourceRef{Kind: "HTTPRoute", Namespace: "shop", Name: "web"}
+	source := []types.DiagnosticFinding{
+		{Severity: types.SeverityWarning, Code: types.CodeGatewayReferenceGrantMissing, Resource: routeRef, Summary: "HTTPRoute shop/web references backend payments/api across namespaces"},
+		{Severity: types.SeverityWarning, Code: types.CodeGatewayReferenceGrantMissing, Resource: routeRef, Summary: "HTTPRoute shop/web references backend payments/cart across namespaces"},
+		{Severity: types.SeverityCritical, Code: types.CodeGatewayBackendServiceMissing, Resource: routeRef},
+		{Severity: types.SeverityWarning, Code: types.CodeGatewayWeightsNot100, Resource: routeRef},
+		{Severity: types.SeverityWarning, Summary: "something without a code"},
+		{Severity: types.SeverityInfo, Code: types.CodeServiceNoEndpoints, Summary: "informational"},
+	}
+	// Findings arrive as decoded JSON, like in an MCP call.
+	data, _ := json.Marshal(source)
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"findings": raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 4 {
+		t.Fatalf("expected 3 remediations and the uncoded note, got %+v", findings)
+	}
+	grant := findings[0].Suggestion
+	for _, want := range []string{"kind: ReferenceGrant", "namespace: payments", "kind: HTTPRoute\n    namespace: shop", "name: api", "name: cart"} {
+		if !strings.Contains(grant, want) {
+			t.Errorf("ReferenceGrant lacks %q:\n%s", want, grant)
+		}
+	}
+	if strings.Count(grant, "kind: ReferenceGrant") != 1 {
+		t.Errorf("expected one ReferenceGrant for the payments namespace:\n%s", grant)
+	}
+	if backend := findings[1].Suggestion; !strings.Contains(backend, "name: cart\n  namespace: payments") || strings.Contains(backend, "name: api") {
+		t.Errorf("expected a Service manifest for payments/cart only:\n%s", backend)
+	}
+	if patch := findings[2].Suggestion; !strings.Contains(patch, "kubectl patch httproute web -n shop") ||
+		!strings.Contains(patch, `"path":"/spec/rules/0/backendRefs/0/weight","value":50`) {
+		t.Errorf("unexpected weight patch:\n%s", patch)
+	}
+	if !strings.HasPrefix(findings[3].Summary, "1 warning or critical findings have no finding code") {
+		t.Errorf("unexpected note %q", findings[3].Summary)
+	}
+
+	// A whole tool response encoded as a string is accepted too.
+	result, _ := json.Marshal(types.ToolResult{Findings: source[:1]})
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"findings": string(result)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Data.(*types.ToolResult).Findings; len(got) != 1 || got[0].Code != types.CodeGatewayReferenceGrantMissing {
+		t.Errorf("unexpected findings from a ToolResult string: %+v", got)
+	}
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// maxLogReplicas caps the pods one selector-based log request reads.
+const maxLogReplicas = 20
+
+// replicaLogLine is a log line of one replica, with the timestamp the
+// kubelet recorded for it.
+type replicaLogLine struct {
+	ts   time.Time
+	pod  string
+	text string
+}
+
+// replicaLogs is the merged log of several replicas.
+type replicaLogs struct {
+	logResult
+	pods   []string          // replicas whose logs were read
+	failed map[string]string // pod -> error
+}
+
+// runReplicas serves get_proxy_logs for a deployment or label selector.
+func (t *GetProxyLogsTool) runReplicas(ctx context.Context, args map[string]interface{}, ns, container string, tail int64, since string) (*StandardResponse, error) {
+	deployment := getStringArg(args, "deployment", "")
+	selector := getStringArg(args, "label_selector", "")
+	if deployment == "" && selector == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "one of pod, deployment or label_selector is required",
+		}
+	}
+
+	pods, selector, err := t.selectedPods(ctx, ns, deployment, selector)
+	if err != nil {
+		return nil, err
+	}
+	source := fmt.Sprintf("pods matching %s in %s", selector, ns)
+	ref := &types.ResourceRef{Kind: "Pod", Namespace: ns}
+	if deployment != "" {
+		source = fmt.Sprintf("deployment %s/%s", ns, deployment)
+		ref = &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: deployment, APIVersion: "apps/v1"}
+	}
+	if len(pods) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("no running pods found for %s", source),
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	if len(pods) > maxLogReplicas {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryLogs,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%d running pods match %s; reading the first %d", len(pods), source, maxLogReplicas),
+			Suggestion: "Use a narrower label_selector to read the logs of other replicas",
+		})
+		pods = pods[:maxLogReplicas]
+	}
+
+	var skipped []string
+	result := getReplicaLogs(ctx, t.Clients, pods, func(pod *corev1.Pod) string {
+		if container != "" {
+			return container
+		}
+		name := findProxyContainer(pod)
+		if name == "" {
+			skipped = append(skipped, pod.Name)
+		}
+		return name
+	}, tail, since)
+
+	if len(result.pods) == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("no proxy sidecar container found in %s", source),
+			Detail:  fmt.Sprintf("looked for containers named: %s", strings.Join(proxyContainerNames, ", ")),
+		}
+	}
+
+	read := len(result.pods) - len(result.failed)
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryLogs,
+		Resource: ref,
+		Summary:  fmt.Sprintf("Retrieved %d log lines from %d replicas of %s, merged by timestamp", result.returnedLines, read, source),
+		Detail:   result.logs,
+	})
+
+	if len(skipped) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryLogs,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%d pods of %s have no proxy sidecar container: %s", len(skipped), source, strings.Join(skipped, ", ")),
+			Detail:   fmt.Sprintf("looked for containers named: %s", strings.Join(proxyContainerNames, ", ")),
+		})
+	}
+
+	if len(result.failed) > 0 {
+		failed := make([]string, 0, len(result.failed))
+		for pod, msg := range result.failed {
+			failed = append(failed, pod+": "+msg)
+		}
+		sort.Strings(failed)
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryLogs,
+			Resource: ref,
+			Summary:  fmt.Sprintf("Failed to read logs from %d of %d replicas of %s", len(result.failed), len(result.pods), source),
+			Detail:   strings.Join(failed, "\n"),
+		})
+	}
+
+	if result.truncated {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryLogs,
+			Code:       types.CodeLogsOutputTruncated,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Merged log output truncated at 100KB limit for %s; the oldest lines were dropped", source),
+			Suggestion: "Use a smaller --tail value or narrower --since window to avoid truncation",
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// selectedPods returns the running pods of a Deployment, or matching a label
+// selector, sorted by name, and the selector used.
+func (b *BaseTool) selectedPods(ctx context.Context, ns, deployment, selector string) ([]corev1.Pod, string, error) {
+	if deployment != "" {
+		dep, err := b.Clients.Clientset.AppsV1().Deployments(ns).Get(ctx, deployment, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get deployment %s/%s: %w", ns, deployment, err)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+		if err != nil {
+			return nil, "", fmt.Errorf("deployment %s/%s has an invalid selector: %w", ns, deployment, err)
+		}
+		selector = sel.String()
+	} else if _, err := labels.Parse(selector); err != nil {
+		return nil, "", fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+	list, err := b.Clients.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, selector, fmt.Errorf("failed to list pods in %s: %w", ns, err)
+	}
+	var pods []corev1.Pod
+	for _, pod := range list.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, selector, nil
+}
+
+// getReplicaLogs reads the logs of a container of each pod in parallel and
+// merges them by timestamp, prefixing each line with its pod name. The
+// maxLogBytes budget applies to the merged output, keeping the newest lines.
+// container returns the container to read in a pod, or "" to skip the pod.
+func getReplicaLogs(ctx context.Context, clients *k8s.Clients, pods []corev1.Pod, container func(*corev1.Pod) string, tailLines int64, since string) *replicaLogs {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		lines  []replicaLogLine
+		result = &replicaLogs{failed: make(map[string]string)}
+	)
+	for i := range pods {
+		pod := &pods[i]
+		name := container(pod)
+		if name == "" {
+			continue
+		}
+		result.pods = append(result.pods, pod.Name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := podLogOptions(name, tailLines, since)
+			opts.Timestamps = true
+			lr, err := readPodLogs(ctx, clients, pod.Namespace, pod.Name, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.failed[pod.Name] = err.Error()
+				return
+			}
+			lines = append(lines, parseTimestampedLogs(pod.Name, lr.logs)...)
+			if lr.truncated {
+				result.truncated = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].ts.Equal(lines[j].ts) {
+			return lines[i].ts.Before(lines[j].ts)
+		}
+		return lines[i].pod < lines[j].pod
+	})
+	result.logs, result.returnedLines = mergeReplicaLogs(lines, maxLogBytes)
+	if result.returnedLines < len(lines) {
+		result.truncated = true
+	}
+	return result
+}
+
+// parseTimestampedLogs splits logs read with timestamps into lines. Lines
+// without a timestamp keep the one of the line before them.
+func parseTimestampedLogs(pod, logs string) []replicaLogLine {
+	var out []replicaLogLine
+	var last time.Time
+	for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		ts, text, ok := strings.Cut(line, " ")
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil && ok {
+			last = t
+		} else {
+			text = line
+		}
+		out = append(out, replicaLogLine{ts: last, pod: pod, text: text})
+	}
+	return out
+}
+
+// mergeReplicaLogs renders sorted lines as "[pod] text", keeping the newest
+// lines that fit in maxBytes. It returns the text and the lines kept.
+func mergeReplicaLogs(lines []replicaLogLine, maxBytes int) (string, int) {
+	size, first := 0, len(lines)
+	for first > 0 {
+		n := len(lines[first-1].pod) + len(lines[first-1].text) + 4
+		if size+n > maxBytes {
+			break
+		}
+		size += n
+		first--
+	}
+	var b strings.Builder
+	b.Grow(size)
+	for _, l := range lines[first:] {
+		fmt.Fprintf(&b, "[%s] %s\n", l.pod, l.text)
+	}
+	return b.String(), len(lines) - first
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseTimestampedLogs(t *testing.T) {
+	lines := parseTimestampedLogs("web-1", "2024-05-01T10:00:00.5Z first\n  continued\n2024-05-01T10:00:01Z second\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %+v", lines)
+	}
+	if lines[0].text != "first" || lines[1].text != "  continued" || !lines[1].ts.Equal(lines[0].ts) {
+		t.Errorf("lines = %+v", lines)
+	}
+}
+
+func TestMergeReplicaLogs(t *testing.T) {
+	lines := append(
+		parseTimestampedLogs("web-1", "2024-05-01T10:00:00Z a1\n2024-05-01T10:00:02Z a2\n"),
+		parseTimestampedLogs("web-2", "2024-05-01T10:00:01Z b1\n2024-05-01T10:00:03Z b2\n")...)
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].ts.Before(lines[j].ts) })
+
+	got, n := mergeReplicaLogs(lines, maxLogBytes)
+	want := "[web-1] a1\n[web-2] b1\n[web-1] a2\n[web-2] b2\n"
+	if got != want || n != 4 {
+		t.Errorf("merged = %q (%d lines), want %q", got, n, want)
+	}
+
+	// The budget keeps the newest lines.
+	got, n = mergeReplicaLogs(lines, 2*len("[web-1] a2\n"))
+	if got != "[web-1] a2\n[web-2] b2\n" || n != 2 {
+		t.Errorf("truncated = %q (%d lines)", got, n)
+	}
+	if len(got) > 2*len("[web-1] a2\n") || strings.Contains(got, "a1") {
+		t.Errorf("budget exceeded: %q", got)
+	}
+}
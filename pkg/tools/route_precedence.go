@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// routeMatch is one match of an HTTPRoute rule with the attributes the
+// Gateway API uses to order matches across routes.
+type routeMatch struct {
+	Route    string // namespace/name
+	Created  time.Time
+	Rule     int
+	Match    int
+	PathType string
+	Path     string
+	Method   string
+	Headers  []string
+	Query    []string
+}
+
+// pathRank orders path match types: Exact before PathPrefix, and the
+// implementation-specific RegularExpression last.
+func (m *routeMatch) pathRank() int {
+	switch m.PathType {
+	case "Exact":
+		return 0
+	case "PathPrefix":
+		return 1
+	}
+	return 2
+}
+
+// signature identifies the requests a match selects: two matches with the
+// same signature on the same hostname select exactly the same requests.
+func (m *routeMatch) signature() string {
+	return strings.Join([]string{m.PathType, m.Path, m.Method, strings.Join(m.Headers, "&"), strings.Join(m.Query, "&")}, "|")
+}
+
+func (m *routeMatch) String() string {
+	parts := []string{m.PathType + " " + m.Path}
+	if m.Method != "" {
+		parts = append(parts, m.Method)
+	}
+	if len(m.Headers) > 0 {
+		parts = append(parts, "headers "+strings.Join(m.Headers, ","))
+	}
+	if len(m.Query) > 0 {
+		parts = append(parts, "query "+strings.Join(m.Query, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// matchPrecedes reports whether a takes precedence over b following the
+// Gateway API HTTPRoute rules: Exact path, longest prefix, method, most header
+// matches, most query matches, oldest route, route name, then rule order.
+func matchPrecedes(a, b *routeMatch) bool {
+	if a.pathRank() != b.pathRank() {
+		return a.pathRank() < b.pathRank()
+	}
+	if a.PathType == "PathPrefix" && len(a.Path) != len(b.Path) {
+		return len(a.Path) > len(b.Path)
+	}
+	if (a.Method != "") != (b.Method != "") {
+		return a.Method != ""
+	}
+	if len(a.Headers) != len(b.Headers) {
+		return len(a.Headers) > len(b.Headers)
+	}
+	if len(a.Query) != len(b.Query) {
+		return len(a.Query) > len(b.Query)
+	}
+	if !a.Created.Equal(b.Created) {
+		return a.Created.Before(b.Created)
+	}
+	if a.Route != b.Route {
+		return a.Route < b.Route
+	}
+	if a.Rule != b.Rule {
+		return a.Rule < b.Rule
+	}
+	return a.Match < b.Match
+}
+
+// sortedMatchConditions returns the sorted name/type/value conditions of a
+// header or query parameter match list.
+func sortedMatchConditions(list []interface{}) []string {
+	var out []string
+	for _, item := range list {
+		im, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := im["name"].(string)
+		value, _ := im["value"].(string)
+		matchType, _ := im["type"].(string)
+		op := "="
+		if matchType == "RegularExpression" {
+			op = "~"
+		}
+		out = append(out, strings.ToLower(name)+op+value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// httpRouteMatches flattens the rules of an HTTPRoute into matches. A rule
+// without matches, or a match without a path, matches PathPrefix /.
+func httpRouteMatches(route *unstructured.Unstructured) []*routeMatch {
+	key := route.GetNamespace() + "/" + route.GetName()
+	created := route.GetCreationTimestamp().Time
+	var out []*routeMatch
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for ri, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, _ := rm["matches"].([]interface{})
+		if len(matches) == 0 {
+			matches = []interface{}{map[string]interface{}{}}
+		}
+		for mi, m := range matches {
+			mm, _ := m.(map[string]interface{})
+			entry := &routeMatch{Route: key, Created: created, Rule: ri, Match: mi, PathType: "PathPrefix", Path: "/"}
+			if path, ok := mm["path"].(map[string]interface{}); ok {
+				if pt, _ := path["type"].(string); pt != "" {
+					entry.PathType = pt
+				}
+				if v, _ := path["value"].(string); v != "" {
+					entry.Path = v
+				}
+			}
+			entry.Method, _ = mm["method"].(string)
+			headers, _ := mm["headers"].([]interface{})
+			entry.Headers = sortedMatchConditions(headers)
+			query, _ := mm["queryParams"].([]interface{})
+			entry.Query = sortedMatchConditions(query)
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// --- analyze_route_precedence ---
+
+type AnalyzeRoutePrecedenceTool struct{ BaseTool }
+
+func (t *AnalyzeRoutePrecedenceTool) Name() string { return "analyze_route_precedence" }
+func (t *AnalyzeRoutePrecedenceTool) Description() string {
+	return "Compute the Gateway API precedence order of the HTTPRoute rules attached to a Gateway or serving a hostname (path type and length, method, header and query matches, route age) and flag rules that are shadowed and never receive traffic"
+}
+func (t *AnalyzeRoutePrecedenceTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"gateway": map[string]interface{}{
+				"type":        "string",
+				"description": "Gateway name: analyze the HTTPRoutes attached to it",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Gateway (default: default)",
+			},
+			"hostname": map[string]interface{}{
+				"type":        "string",
+				"description": "Request hostname: analyze the HTTPRoutes serving it, alone or within the Gateway",
+			},
+		},
+	}
+}
+
+func (t *AnalyzeRoutePrecedenceTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	gateway := getStringArg(args, "gateway", "")
+	ns := getStringArg(args, "namespace", "default")
+	hostname := strings.ToLower(getStringArg(args, "hostname", ""))
+	if gateway == "" && hostname == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "gateway or hostname is required",
+		}
+	}
+
+	if gateway != "" {
+		if _, err := getWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns, gateway); err != nil {
+			return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{{
+				Severity: types.SeverityWarning,
+				Category: types.CategoryRouting,
+				Code:     types.CodeGatewayResourceNotFound,
+				Resource: &types.ResourceRef{Kind: "Gateway", Namespace: ns, Name: gateway, APIVersion: "gateway.networking.k8s.io"},
+				Summary:  fmt.Sprintf("Gateway %s/%s not found: %v", ns, gateway, err),
+			}}, ns, "gateway-api"), nil
+		}
+	}
+
+	routes, err := t.listResourceWithFallback(ctx, httpRoutesV1GVR, httpRoutesV1B1GVR, "")
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: "failed to list HTTPRoutes",
+			Detail:  fmt.Sprintf("tried gateway.networking.k8s.io/v1 and v1beta1: %v", err),
+		}
+	}
+
+	// Group the matches by route hostname: matches compete only on the same
+	// hostname, a more specific hostname always wins over a wildcard.
+	groups := make(map[string][]*routeMatch)
+	routeCount := 0
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if gateway != "" && !routeAttachedTo(route, ns, gateway) {
+			continue
+		}
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		if len(hostnames) == 0 {
+			hostnames = []string{""}
+		}
+		matches := httpRouteMatches(route)
+		attached := false
+		for _, h := range hostnames {
+			h = strings.ToLower(h)
+			if hostname != "" && !hostnameMatches(h, hostname) {
+				continue
+			}
+			groups[h] = append(groups[h], matches...)
+			attached = true
+		}
+		if attached {
+			routeCount++
+		}
+	}
+
+	scope := "Gateway " + ns + "/" + gateway
+	if gateway == "" {
+		scope = "hostname " + hostname
+	}
+	if len(groups) == 0 {
+		return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("No HTTPRoutes for %s", scope),
+		}}, ns, "gateway-api"), nil
+	}
+
+	hosts := make([]string, 0, len(groups))
+	for h := range groups {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+
+	var findings []types.DiagnosticFinding
+	shadowed := 0
+	for _, host := range hosts {
+		matches := groups[host]
+		sort.SliceStable(matches, func(i, j int) bool { return matchPrecedes(matches[i], matches[j]) })
+		label := host
+		if label == "" {
+			label = "(any hostname)"
+		}
+
+		lines := make([]string, 0, len(matches))
+		winners := make(map[string]*routeMatch)
+		for i, m := range matches {
+			lines = append(lines, fmt.Sprintf("%d. HTTPRoute %s rule[%d] match[%d]: %s", i+1, m.Route, m.Rule, m.Match, m.String()))
+			sig := m.signature()
+			winner, ok := winners[sig]
+			if !ok {
+				winners[sig] = m
+				continue
+			}
+			shadowed++
+			rns, rname, _ := strings.Cut(m.Route, "/")
+			reason := "an earlier rule of the same route wins"
+			if winner.Route != m.Route {
+				reason = "both routes match identically, so the older route (then the one first by namespace/name) wins"
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeGatewayRouteShadowed,
+				Resource:   &types.ResourceRef{Kind: "HTTPRoute", Namespace: rns, Name: rname, APIVersion: "gateway.networking.k8s.io"},
+				Summary:    fmt.Sprintf("HTTPRoute %s rule[%d] match[%d] (%s) never receives traffic for %s: shadowed by HTTPRoute %s rule[%d]", m.Route, m.Rule, m.Match, m.String(), label, winner.Route, winner.Rule),
+				Detail:     reason,
+				Suggestion: "Remove the duplicate match or make it more specific (longer path, method, header or query parameter match)",
+			})
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("Precedence for %s: %d matches", label, len(matches)),
+			Detail:   strings.Join(lines, "\n"),
+		})
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryRouting,
+		Summary:  fmt.Sprintf("%s: %d HTTPRoutes, %d hostnames, no shadowed rules", scope, routeCount, len(hosts)),
+	}
+	if shadowed > 0 {
+		summary.Severity = types.SeverityWarning
+		summary.Summary = fmt.Sprintf("%s: %d HTTPRoutes, %d hostnames, %d shadowed matches", scope, routeCount, len(hosts), shadowed)
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+}
+
+// routeAttachedTo reports whether a route has a parentRef to Gateway ns/name.
+func routeAttachedTo(route *unstructured.Unstructured, ns, name string) bool {
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	for _, p := range parentRefs {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, _ := pm["kind"].(string); kind != "" && kind != "Gateway" {
+			continue
+		}
+		pns, _ := pm["namespace"].(string)
+		if pns == "" {
+			pns = route.GetNamespace()
+		}
+		if pname, _ := pm["name"].(string); pname == name && pns == ns {
+			return true
+		}
+	}
+	return false
+}
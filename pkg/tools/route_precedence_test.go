@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestMatchPrecedes(t *testing.T) {
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	matches := []*routeMatch{
+		{Route: "a/catchall", Created: old, PathType: "PathPrefix", Path: "/"},
+		{Route: "a/new-api", Created: old.Add(time.Hour), PathType: "PathPrefix", Path: "/api"},
+		{Route: "a/regex", Created: old, PathType: "RegularExpression", Path: "/api/.*"},
+		{Route: "a/get-api", Created: old.Add(time.Hour), PathType: "PathPrefix", Path: "/api", Method: "GET"},
+		{Route: "a/old-api", Created: old, PathType: "PathPrefix", Path: "/api"},
+		{Route: "a/exact", Created: old.Add(time.Hour), PathType: "Exact", Path: "/"},
+		{Route: "a/header-api", Created: old.Add(time.Hour), PathType: "PathPrefix", Path: "/api", Headers: []string{"x-canary=true"}},
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matchPrecedes(matches[i], matches[j]) })
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Route)
+	}
+	want := "a/exact a/get-api a/header-api a/old-api a/new-api a/catchall a/regex"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+func precedenceRoute(name string, created time.Time, hostnames []interface{}, rules []interface{}) *unstructured.Unstructured {
+	route := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra"}},
+			"hostnames":  hostnames,
+			"rules":      rules,
+		},
+	})
+	route.SetCreationTimestamp(metav1.NewTime(created))
+	return route
+}
+
+func TestAnalyzeRoutePrecedence(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiRule := map[string]interface{}{"matches": []interface{}{
+		map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}},
+	}}
+	gw := managedObj("gateway.networking.k8s.io/v1", "Gateway", "infra", "public", map[string]interface{}{})
+	old := precedenceRoute("api-v1", created, []interface{}{"shop.example.com"}, []interface{}{apiRule})
+	// Identical to api-v1 on shop.example.com but newer; the second rule
+	// repeats its first one.
+	dup := precedenceRoute("api-v2", created.Add(time.Hour), []interface{}{"shop.example.com", "beta.example.com"}, []interface{}{apiRule, apiRule})
+	other := precedenceRoute("web", created, nil, []interface{}{map[string]interface{}{}})
+
+	client := newManagedClient(t, nil, map[schema.GroupVersionResource][]*unstructured.Unstructured{
+		gatewaysV1GVR:   {gw},
+		httpRoutesV1GVR: {old, dup, other},
+	})
+	tool := &AnalyzeRoutePrecedenceTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"gateway": "public", "namespace": "infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shadowed []string
+	for _, f := range resp.Data.(*types.ToolResult).Findings {
+		if f.Code == types.CodeGatewayRouteShadowed {
+			shadowed = append(shadowed, f.Summary)
+		}
+	}
+	sort.Strings(shadowed)
+	want := []string{
+		"HTTPRoute shop/api-v2 rule[0] match[0] (PathPrefix /api) never receives traffic for shop.example.com: shadowed by HTTPRoute shop/api-v1 rule[0]",
+		"HTTPRoute shop/api-v2 rule[1] match[0] (PathPrefix /api) never receives traffic for beta.example.com: shadowed by HTTPRoute shop/api-v2 rule[0]",
+		"HTTPRoute shop/api-v2 rule[1] match[0] (PathPrefix /api) never receives traffic for shop.example.com: shadowed by HTTPRoute shop/api-v1 rule[0]",
+	}
+	if strings.Join(shadowed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("shadowed =\n%s\nwant\n%s", strings.Join(shadowed, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A hostname scope keeps the routes serving it: the catch-all route has
+	// no hostnames and serves every host.
+	resp, err = tool.Run(context.Background(), map[string]interface{}{"hostname": "beta.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := resp.Data.(*types.ToolResult).Findings[0].Summary; summary != "hostname beta.example.com: 2 HTTPRoutes, 2 hostnames, 1 shadowed matches" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+}
@@ -0,0 +1,657 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	// annotationEchoHeader on a backend Service names the response header
+	// its pods identify themselves with; annotationEchoValue is the value
+	// that identifies the Service (default: its name).
+	annotationEchoHeader = "mcp-k8s-networking/echo-header"
+	annotationEchoValue  = "mcp-k8s-networking/echo-value"
+
+	// routeProbeHeader marks the requests verify_route_programming sends.
+	routeProbeHeader = "X-MCP-Route-Probe"
+	// routeProbeBodyBytes is how much of each response the probe returns.
+	routeProbeBodyBytes = 2048
+	// routeProbeMaxRules bounds the rules one probe pod requests.
+	routeProbeMaxRules = 50
+	// routeProbeRequestTimeout is the timeout of each request, in seconds.
+	routeProbeRequestTimeout = 5
+)
+
+// routeProbePodTimeout is how long a probe pod requesting rules rules may run.
+func routeProbePodTimeout(rules int) time.Duration {
+	return time.Duration(rules*(routeProbeRequestTimeout+1)+30) * time.Second
+}
+
+var (
+	validProbePath        = regexp.MustCompile(`^/[A-Za-z0-9._~%/:@+,=-]*$`)
+	validProbeHeaderName  = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	validProbeHeaderValue = regexp.MustCompile(`^[A-Za-z0-9 ._~%/:@+,=-]*$`)
+	validProbeMethod      = regexp.MustCompile(`^[A-Z]+$`)
+)
+
+// routeProbe is a request crafted to match one HTTPRoute rule through a
+// Gateway listener, and the backends expected to answer it.
+type routeProbe struct {
+	route    routeInfo
+	rule     int
+	scheme   string
+	host     string
+	port     int64
+	method   string
+	path     string // including the query string
+	headers  []string
+	backends []string // Services as namespace/name
+	redirect bool     // the rule redirects instead of forwarding
+	skip     string   // why the rule cannot be probed
+}
+
+func (p routeProbe) label() string {
+	return fmt.Sprintf("HTTPRoute %s/%s rule %d", p.route.namespace, p.route.name, p.rule)
+}
+
+func (p routeProbe) request() string {
+	return fmt.Sprintf("%s %s://%s:%d%s", p.method, p.scheme, p.host, p.port, p.path)
+}
+
+// probeHostname picks a concrete host a listener and route both accept.
+func probeHostname(listenerHost string, routeHosts []string) string {
+	hosts := listenerRouteHosts(listenerHost, routeHosts)
+	if len(hosts) == 0 {
+		if len(routeHosts) > 0 {
+			return ""
+		}
+		return "route-probe.mcp.invalid"
+	}
+	if suffix, ok := strings.CutPrefix(hosts[0], "*"); ok {
+		return "route-probe" + suffix
+	}
+	return hosts[0]
+}
+
+// ruleProbe crafts the request for rule i of a route, from its first match.
+// Matches that only a regular expression describes cannot be crafted.
+func ruleProbe(r routeInfo, i int, rule map[string]interface{}, l gatewayListener) routeProbe {
+	p := routeProbe{route: r, rule: i, scheme: "http", port: l.port, method: "GET", path: "/"}
+	if protocol, _ := l.spec["protocol"].(string); protocol == "HTTPS" {
+		p.scheme = "https"
+	}
+	listenerHost, _ := l.spec["hostname"].(string)
+	routeHosts, _, _ := unstructured.NestedStringSlice(r.obj, "spec", "hostnames")
+	if p.host = probeHostname(listenerHost, routeHosts); p.host == "" {
+		p.skip = fmt.Sprintf("no hostname of the route is accepted by listener %s", l.name)
+		return p
+	}
+	p.headers = append(p.headers, fmt.Sprintf("%s: %s/%s/%d", routeProbeHeader, r.namespace, r.name, i))
+
+	matches, _ := rule["matches"].([]interface{})
+	if len(matches) > 0 {
+		m, _ := matches[0].(map[string]interface{})
+		if pm, ok := m["path"].(map[string]interface{}); ok {
+			pathType, _ := pm["type"].(string)
+			value, _ := pm["value"].(string)
+			if orDefault(pathType, "PathPrefix") == "RegularExpression" {
+				p.skip = "its path match is a regular expression"
+				return p
+			}
+			p.path = orDefault(value, "/")
+		}
+		if method, _ := m["method"].(string); method != "" {
+			p.method = method
+		}
+		headers, _ := m["headers"].([]interface{})
+		for _, h := range headers {
+			hm, _ := h.(map[string]interface{})
+			if t, _ := hm["type"].(string); orDefault(t, "Exact") != "Exact" {
+				p.skip = "its header match is a regular expression"
+				return p
+			}
+			p.headers = append(p.headers, fmt.Sprintf("%s: %s", hm["name"], hm["value"]))
+		}
+		params, _ := m["queryParams"].([]interface{})
+		var query []string
+		for _, q := range params {
+			qm, _ := q.(map[string]interface{})
+			if t, _ := qm["type"].(string); orDefault(t, "Exact") != "Exact" {
+				p.skip = "its query parameter match is a regular expression"
+				return p
+			}
+			query = append(query, fmt.Sprintf("%s=%s", qm["name"], qm["value"]))
+		}
+		if len(query) > 0 {
+			p.path += "?" + strings.Join(query, "&")
+		}
+	}
+
+	filters, _ := rule["filters"].([]interface{})
+	for _, f := range filters {
+		fm, _ := f.(map[string]interface{})
+		if fm["type"] == "RequestRedirect" {
+			p.redirect = true
+		}
+	}
+	refs, _ := rule["backendRefs"].([]interface{})
+	for _, b := range refs {
+		bm, _ := b.(map[string]interface{})
+		kind, _ := bm["kind"].(string)
+		name, _ := bm["name"].(string)
+		ns, _ := bm["namespace"].(string)
+		if name != "" && orDefault(kind, "Service") == "Service" {
+			p.backends = append(p.backends, orDefault(ns, r.namespace)+"/"+name)
+		}
+	}
+	if !p.redirect && len(p.backends) == 0 {
+		p.skip = "it has no Service backend"
+		return p
+	}
+
+	path, query, _ := strings.Cut(p.path, "?")
+	valid := validHostname.MatchString(p.host) && validProbeMethod.MatchString(p.method) && validProbePath.MatchString(path) && validProbeHeaderValue.MatchString(query)
+	for _, h := range p.headers {
+		name, value, _ := strings.Cut(h, ": ")
+		valid = valid && validProbeHeaderName.MatchString(name) && validProbeHeaderValue.MatchString(value)
+	}
+	if !valid {
+		p.skip = "its match contains characters the probe does not send"
+	}
+	return p
+}
+
+// routeProbeScript sends every probe to address with curl, pinning the
+// probe's host to address so that TLS uses the right SNI, and prints each
+// response between BEGIN|i|exit and END|i lines. Every value was validated
+// against a character set without quotes.
+func routeProbeScript(address string, probes []routeProbe, timeoutSec int) string {
+	pinned := address
+	if ip, err := netip.ParseAddr(address); err == nil && ip.Is6() {
+		pinned = "[" + address + "]"
+	}
+	var b strings.Builder
+	for i, p := range probes {
+		fmt.Fprintf(&b, "out=$(curl -sk -i --max-time %d --resolve '%s:%d:%s' -X '%s'", timeoutSec, p.host, p.port, pinned, p.method)
+		for _, h := range p.headers {
+			fmt.Fprintf(&b, " -H '%s'", h)
+		}
+		fmt.Fprintf(&b, " '%s://%s:%d%s' 2>/dev/null); rc=$?\n", p.scheme, p.host, p.port, p.path)
+		fmt.Fprintf(&b, "echo \"BEGIN|%d|$rc\"; printf '%%s\\n' \"$out\" | head -c %d; echo; echo \"END|%d\"\n", i, routeProbeBodyBytes, i)
+	}
+	return b.String()
+}
+
+// probeResponse is what one probe request got back.
+type probeResponse struct {
+	exitCode int
+	status   int
+	headers  map[string]string // lower-case names
+	body     string
+}
+
+// parseRouteProbeOutput reads the output of routeProbeScript, keyed by
+// probe index.
+func parseRouteProbeOutput(output string) map[int]*probeResponse {
+	out := make(map[int]*probeResponse)
+	var cur *probeResponse
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if rest, ok := strings.CutPrefix(line, "BEGIN|"); ok {
+			idx, rc, _ := strings.Cut(rest, "|")
+			i, err := strconv.Atoi(idx)
+			if err != nil {
+				continue
+			}
+			cur = &probeResponse{headers: map[string]string{}}
+			cur.exitCode, _ = strconv.Atoi(rc)
+			out[i] = cur
+			lines = lines[:0]
+			continue
+		}
+		if strings.HasPrefix(line, "END|") && cur != nil {
+			cur.parse(lines)
+			cur = nil
+			continue
+		}
+		if cur != nil {
+			lines = append(lines, line)
+		}
+	}
+	return out
+}
+
+// parse reads the status, headers and body of a curl -i response. Interim
+// responses such as 100 Continue precede the final one.
+func (r *probeResponse) parse(lines []string) {
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "HTTP/") {
+			start = i
+			r.headers = map[string]string{}
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				r.status, _ = strconv.Atoi(fields[1])
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		if line == "" {
+			if r.status >= 200 {
+				r.body = strings.Join(lines[i+1:], "\n")
+				return
+			}
+			start = -1
+			continue
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			r.headers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// backendIdentities tells which Service answered a request: from the echo
+// header a Service is annotated with, or from the name of one of its pods in
+// the response, as echo servers print.
+type backendIdentities struct {
+	echo map[string][2]string // Service -> header name, expected value
+	pods map[string]string    // pod name -> Service
+}
+
+func (b backendIdentities) identify(r *probeResponse) string {
+	services := make([]string, 0, len(b.echo))
+	for svc := range b.echo {
+		services = append(services, svc)
+	}
+	sort.Strings(services)
+	for _, svc := range services {
+		e := b.echo[svc]
+		if v := r.headers[strings.ToLower(e[0])]; v != "" && strings.EqualFold(v, e[1]) {
+			return svc
+		}
+	}
+	pods := make([]string, 0, len(b.pods))
+	for pod := range b.pods {
+		pods = append(pods, pod)
+	}
+	// Longest first, so that web-2 does not claim web-23.
+	sort.Slice(pods, func(i, j int) bool {
+		if len(pods[i]) != len(pods[j]) {
+			return len(pods[i]) > len(pods[j])
+		}
+		return pods[i] < pods[j]
+	})
+	for _, pod := range pods {
+		for _, v := range r.headers {
+			if strings.Contains(v, pod) {
+				return b.pods[pod]
+			}
+		}
+		if strings.Contains(r.body, pod) {
+			return b.pods[pod]
+		}
+	}
+	return ""
+}
+
+// evaluateRouteProbe compares the response to a probe with what the rule
+// should do.
+func evaluateRouteProbe(p routeProbe, r *probeResponse, ids backendIdentities) types.DiagnosticFinding {
+	ref := &types.ResourceRef{Kind: p.route.kind, Namespace: p.route.namespace, Name: p.route.name}
+	f := types.DiagnosticFinding{Category: types.CategoryRouting, Resource: ref, Detail: p.request()}
+	expected := strings.Join(p.backends, ", ")
+	switch {
+	case r == nil || r.status == 0:
+		reason := "no response"
+		if r != nil && r.exitCode != 0 {
+			reason = orDefault(curlExitReasons[r.exitCode], fmt.Sprintf("curl exit code %d", r.exitCode))
+		}
+		f.Severity = types.SeverityWarning
+		f.Code = types.CodeGatewayRouteNotServing
+		f.Summary = fmt.Sprintf("%s is accepted but the probe request got no response: %s", p.label(), reason)
+		f.Suggestion = "Check that the Gateway address is reachable from the probe namespace with probe_http."
+		return f
+	case p.redirect:
+		if r.status >= 300 && r.status < 400 {
+			f.Severity = types.SeverityOK
+			f.Summary = fmt.Sprintf("%s redirects as configured (%d to %s)", p.label(), r.status, orDefault(r.headers["location"], "no Location"))
+			return f
+		}
+		f.Severity = types.SeverityCritical
+		f.Code = types.CodeGatewayRouteNotServing
+		f.Summary = fmt.Sprintf("%s is accepted but answered %d instead of its redirect", p.label(), r.status)
+		f.Suggestion = "The gateway has not programmed the rule: check the controller logs and the Programmed condition of the Gateway."
+		return f
+	}
+
+	served := ids.identify(r)
+	switch {
+	case served != "" && containsString(p.backends, served):
+		f.Severity = types.SeverityOK
+		f.Summary = fmt.Sprintf("%s is served by %s (status %d)", p.label(), served, r.status)
+	case served != "":
+		f.Severity = types.SeverityCritical
+		f.Code = types.CodeGatewayRouteWrongBackend
+		f.Summary = fmt.Sprintf("%s is accepted but %s answered instead of %s", p.label(), served, expected)
+		f.Suggestion = "Another rule or route with higher precedence matches the request, or the gateway still serves a previous version of the route. Run triage_404 with the request's host and path to see the match order."
+	case r.status == 404:
+		f.Severity = types.SeverityCritical
+		f.Code = types.CodeGatewayRouteNotServing
+		f.Summary = fmt.Sprintf("%s is accepted but the gateway answered 404", p.label())
+		f.Suggestion = "The route is accepted in status but not programmed in the data plane: check the controller logs, the Programmed condition of the Gateway and, for Envoy-based gateways, the proxy's route table."
+	case r.status >= 500:
+		f.Severity = types.SeverityWarning
+		f.Code = types.CodeGatewayRouteNotServing
+		f.Summary = fmt.Sprintf("%s is accepted but answered %d", p.label(), r.status)
+		f.Detail += "\n" + strings.TrimSpace(truncateString(r.body, 200))
+		f.Suggestion = fmt.Sprintf("The rule matches but %s cannot serve it: check its endpoints with list_endpoints and the gateway logs.", expected)
+	default:
+		f.Severity = types.SeverityInfo
+		f.Summary = fmt.Sprintf("%s answered %d, but the backend could not be identified", p.label(), r.status)
+		f.Suggestion = fmt.Sprintf("Annotate the backend Services with %s (and optionally %s) naming a response header that identifies them, or use a backend that echoes its pod name.", annotationEchoHeader, annotationEchoValue)
+	}
+	return f
+}
+
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// routeParentAccepted reports whether a route's status has an Accepted=True
+// condition for a Gateway.
+func routeParentAccepted(route routeInfo, gwNs, gwName string) bool {
+	parents, _, _ := unstructured.NestedSlice(route.obj, "status", "parents")
+	for _, p := range parents {
+		pm, _ := p.(map[string]interface{})
+		name, _, _ := unstructured.NestedString(pm, "parentRef", "name")
+		ns, _, _ := unstructured.NestedString(pm, "parentRef", "namespace")
+		if name != gwName || orDefault(ns, route.namespace) != gwNs {
+			continue
+		}
+		conds, _ := pm["conditions"].([]interface{})
+		for _, c := range conds {
+			cm, _ := c.(map[string]interface{})
+			if cm["type"] == "Accepted" && cm["status"] == "True" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// --- verify_route_programming ---
+
+type VerifyRouteProgrammingTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *VerifyRouteProgrammingTool) Name() string { return "verify_route_programming" }
+func (t *VerifyRouteProgrammingTool) Description() string {
+	return "Verify that a Gateway serves what its HTTPRoutes declare: for each rule accepted in status, send a request crafted to match it (host, path, method, headers, query) through the gateway address from a probe pod, confirm the expected backend answered via an echo header annotation or the pod name in the response, and report rules that are accepted but not programmed or served by the wrong backend"
+}
+func (t *VerifyRouteProgrammingTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"gateway": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the Gateway",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Gateway",
+			},
+			"route": map[string]interface{}{
+				"type":        "string",
+				"description": "Only verify this HTTPRoute, as namespace/name",
+			},
+			"address": map[string]interface{}{
+				"type":        "string",
+				"description": "Address to send the requests to (default: the first address in the Gateway status)",
+			},
+			"max_rules": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of rules to probe (default: 20, max: 50)",
+			},
+			"source_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to deploy the probe pod in",
+			},
+		},
+		"required": []string{"gateway", "namespace"},
+	}
+}
+
+func (t *VerifyRouteProgrammingTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	gwName := getStringArg(args, "gateway", "")
+	ns := getStringArg(args, "namespace", "")
+	routeFilter := getStringArg(args, "route", "")
+	address := getStringArg(args, "address", "")
+	maxRules := getIntArg(args, "max_rules", 20)
+	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
+
+	if gwName == "" || ns == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "gateway and namespace are required",
+		}
+	}
+	if maxRules < 1 || maxRules > routeProbeMaxRules {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("max_rules %d out of range (1-%d)", maxRules, routeProbeMaxRules),
+		}
+	}
+
+	gw, err := getWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns, gwName)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get Gateway %s/%s", ns, gwName),
+			Detail:  err.Error(),
+		}
+	}
+	gwRef := &types.ResourceRef{Kind: "Gateway", Namespace: ns, Name: gwName, APIVersion: gw.GetAPIVersion()}
+	if address == "" {
+		if addrs := gatewayAddresses(gw.Object); len(addrs) > 0 {
+			address = addrs[0]
+		}
+	}
+	if address == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("Gateway %s/%s has no address in status; pass address", ns, gwName),
+		}
+	}
+	if !validHostname.MatchString(address) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "address contains invalid characters",
+		}
+	}
+
+	var listeners []gatewayListener
+	specs, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	for _, s := range specs {
+		lm, _ := s.(map[string]interface{})
+		if protocol, _ := lm["protocol"].(string); protocol != "HTTP" && protocol != "HTTPS" {
+			continue
+		}
+		l := gatewayListener{gw: gw, spec: lm}
+		l.name, _ = lm["name"].(string)
+		l.port, _, _ = unstructured.NestedInt64(lm, "port")
+		listeners = append(listeners, l)
+	}
+
+	var findings []types.DiagnosticFinding
+	var probesToRun []routeProbe
+	var skipped []string
+	truncated := 0
+	for _, r := range t.listRoutes(ctx) {
+		if r.kind != "HTTPRoute" || !routeAttachedToGateway(r, ns, gwName) {
+			continue
+		}
+		if routeFilter != "" && routeFilter != r.namespace+"/"+r.name {
+			continue
+		}
+		key := fmt.Sprintf("HTTPRoute %s/%s", r.namespace, r.name)
+		if !routeParentAccepted(r, ns, gwName) {
+			reason := orDefault(routeParentRejection(r, ns, gwName), "no Accepted condition for the Gateway yet")
+			skipped = append(skipped, fmt.Sprintf("%s: not accepted (%s)", key, reason))
+			continue
+		}
+		names, all := routeListenerNames(r, ns, gwName)
+		var listener *gatewayListener
+		for i, l := range listeners {
+			if ok, _ := listenerAllowsRoute(l.spec, ns, r.namespace); ok && (all || names[l.name]) {
+				listener = &listeners[i]
+				break
+			}
+		}
+		if listener == nil {
+			skipped = append(skipped, fmt.Sprintf("%s: no HTTP or HTTPS listener of the Gateway admits it", key))
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(r.obj, "spec", "rules")
+		for i, rule := range rules {
+			rm, _ := rule.(map[string]interface{})
+			p := ruleProbe(r, i, rm, *listener)
+			if p.skip != "" {
+				skipped = append(skipped, fmt.Sprintf("%s: %s", p.label(), p.skip))
+				continue
+			}
+			if len(probesToRun) == maxRules {
+				truncated++
+				continue
+			}
+			probesToRun = append(probesToRun, p)
+		}
+	}
+	if len(skipped) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("%d rule(s) or route(s) of Gateway %s/%s were not probed", len(skipped), ns, gwName),
+			Detail:   strings.Join(skipped, "\n"),
+		})
+	}
+	if truncated > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("%d more rule(s) were not probed; raise max_rules or pass route", truncated),
+		})
+	}
+	if len(probesToRun) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: gwRef,
+			Summary:  fmt.Sprintf("Gateway %s/%s has no accepted HTTPRoute rule to probe", ns, gwName),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:           probes.ProbeTypeRouteProgramming,
+		Namespace:      sourceNS,
+		Command:        []string{"sh", "-c", routeProbeScript(address, probesToRun, routeProbeRequestTimeout)},
+		Timeout:        routeProbePodTimeout(len(probesToRun)),
+		MaxOutputBytes: len(probesToRun)*(routeProbeBodyBytes+64) + 4096,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+
+	responses := parseRouteProbeOutput(result.Output)
+	answered := 0
+	for _, r := range responses {
+		if r.status != 0 {
+			answered++
+		}
+	}
+	if answered == 0 && len(probesToRun) > 1 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeGatewayUnreachable,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("None of %d requests to Gateway %s/%s at %s got a response", len(probesToRun), ns, gwName, address),
+			Detail:     strings.TrimSpace(truncateString(result.Output, 500)),
+			Suggestion: "Check the Gateway Programmed condition and its Service, and that the address is reachable from the probe namespace with probe_http.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+	ids := t.backendIdentities(ctx, probesToRun)
+	for i, p := range probesToRun {
+		findings = append(findings, evaluateRouteProbe(p, responses[i], ids))
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// backendIdentities reads the echo annotations and the pod names of the
+// backends of probes.
+func (t *VerifyRouteProgrammingTool) backendIdentities(ctx context.Context, probes []routeProbe) backendIdentities {
+	ids := backendIdentities{echo: map[string][2]string{}, pods: map[string]string{}}
+	seen := map[string]bool{}
+	for _, p := range probes {
+		for _, svc := range p.backends {
+			if seen[svc] {
+				continue
+			}
+			seen[svc] = true
+			ns, name, _ := strings.Cut(svc, "/")
+			if s, err := t.Clients.Clientset.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{}); err == nil {
+				if header := s.Annotations[annotationEchoHeader]; header != "" {
+					ids.echo[svc] = [2]string{header, orDefault(s.Annotations[annotationEchoValue], name)}
+				}
+			}
+			ep, err := t.Clients.Dynamic.Resource(endpointsGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			subsets, _, _ := unstructured.NestedSlice(ep.Object, "subsets")
+			for _, sub := range subsets {
+				sm, _ := sub.(map[string]interface{})
+				addrs, _ := sm["addresses"].([]interface{})
+				for _, a := range addrs {
+					am, _ := a.(map[string]interface{})
+					if kind, _, _ := unstructured.NestedString(am, "targetRef", "kind"); kind == "Pod" {
+						pod, _, _ := unstructured.NestedString(am, "targetRef", "name")
+						ids.pods[pod] = svc
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestRuleProbe(t *testing.T) {
+	route := routeInfo{kind: "HTTPRoute", namespace: "shop", name: "web", obj: map[string]interface{}{
+		"spec": map[string]interface{}{"hostnames": []interface{}{"*.example.com"}},
+	}}
+	listener := gatewayListener{name: "https", port: 443, spec: map[string]interface{}{"protocol": "HTTPS", "hostname": "*.example.com"}}
+	rule := map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{
+			"path":        map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+			"method":      "POST",
+			"headers":     []interface{}{map[string]interface{}{"name": "x-version", "value": "v2"}},
+			"queryParams": []interface{}{map[string]interface{}{"name": "debug", "value": "1"}},
+		}},
+		"backendRefs": []interface{}{map[string]interface{}{"name": "api", "port": int64(80)}},
+	}
+	p := ruleProbe(route, 1, rule, listener)
+	if p.skip != "" {
+		t.Fatalf("skipped: %s", p.skip)
+	}
+	if got, want := p.request(), "POST https://route-probe.example.com:443/api?debug=1"; got != want {
+		t.Errorf("request = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(p.headers, "|"), "X-MCP-Route-Probe: shop/web/1|x-version: v2"; got != want {
+		t.Errorf("headers = %q, want %q", got, want)
+	}
+	if len(p.backends) != 1 || p.backends[0] != "shop/api" {
+		t.Errorf("backends = %v", p.backends)
+	}
+
+	regex := map[string]interface{}{
+		"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "RegularExpression", "value": "/v[0-9]+"}}},
+		"backendRefs": []interface{}{map[string]interface{}{"name": "api"}},
+	}
+	if p := ruleProbe(route, 0, regex, listener); p.skip == "" {
+		t.Error("regular expression match was not skipped")
+	}
+	quoted := map[string]interface{}{
+		"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"value": "/it's"}}},
+		"backendRefs": []interface{}{map[string]interface{}{"name": "api"}},
+	}
+	if p := ruleProbe(route, 0, quoted, listener); p.skip == "" {
+		t.Error("path with a quote was not skipped")
+	}
+	other := gatewayListener{name: "http", port: 80, spec: map[string]interface{}{"protocol": "HTTP", "hostname": "shop.internal"}}
+	if p := ruleProbe(route, 0, rule, other); p.skip == "" {
+		t.Error("route hostname outside the listener was not skipped")
+	}
+}
+
+func TestParseRouteProbeOutput(t *testing.T) {
+	output := strings.Join([]string{
+		"BEGIN|0|0",
+		"HTTP/1.1 100 Continue",
+		"",
+		"HTTP/1.1 200 OK",
+		"Content-Type: text/plain\r",
+		"X-Served-By: api",
+		"",
+		"Hostname: api-7d9f-abcde",
+		"END|0",
+		"BEGIN|1|7",
+		"",
+		"END|1",
+	}, "\n")
+	responses := parseRouteProbeOutput(output)
+	r := responses[0]
+	if r == nil || r.status != 200 || r.headers["x-served-by"] != "api" || r.headers["content-type"] != "text/plain" {
+		t.Fatalf("response 0 = %+v", r)
+	}
+	if !strings.Contains(r.body, "api-7d9f-abcde") {
+		t.Errorf("body = %q", r.body)
+	}
+	if r := responses[1]; r == nil || r.exitCode != 7 || r.status != 0 {
+		t.Errorf("response 1 = %+v", r)
+	}
+}
+
+func TestEvaluateRouteProbe(t *testing.T) {
+	route := routeInfo{kind: "HTTPRoute", namespace: "shop", name: "web"}
+	p := routeProbe{route: route, rule: 0, scheme: "http", host: "shop.example.com", port: 80, method: "GET", path: "/", backends: []string{"shop/web"}}
+	ids := backendIdentities{
+		echo: map[string][2]string{"shop/web": {"X-Served-By", "web"}},
+		pods: map[string]string{"legacy-2": "shop/legacy", "legacy-23": "shop/legacy-canary"},
+	}
+	tests := []struct {
+		name string
+		p    routeProbe
+		r    *probeResponse
+		want string
+	}{
+		{"echo header", p, &probeResponse{status: 200, headers: map[string]string{"x-served-by": "web"}}, "ok  HTTPRoute shop/web rule 0 is served by shop/web (status 200)"},
+		{"pod name", p, &probeResponse{status: 200, headers: map[string]string{}, body: "Hostname: legacy-23"}, "critical GW044_ROUTE_WRONG_BACKEND HTTPRoute shop/web rule 0 is accepted but shop/legacy-canary answered instead of shop/web"},
+		{"not found", p, &probeResponse{status: 404, headers: map[string]string{}}, "critical GW043_ROUTE_NOT_SERVING HTTPRoute shop/web rule 0 is accepted but the gateway answered 404"},
+		{"unavailable", p, &probeResponse{status: 503, headers: map[string]string{}, body: "no healthy upstream"}, "warning GW043_ROUTE_NOT_SERVING HTTPRoute shop/web rule 0 is accepted but answered 503"},
+		{"unknown", p, &probeResponse{status: 200, headers: map[string]string{}}, "info  HTTPRoute shop/web rule 0 answered 200, but the backend could not be identified"},
+		{"unreachable", p, &probeResponse{exitCode: 28}, "warning GW043_ROUTE_NOT_SERVING HTTPRoute shop/web rule 0 is accepted but the probe request got no response: " + curlExitReasons[28]},
+		{"redirect", routeProbe{route: route, redirect: true}, &probeResponse{status: 301, headers: map[string]string{"location": "https://shop.example.com/"}}, "ok  HTTPRoute shop/web rule 0 redirects as configured (301 to https://shop.example.com/)"},
+	}
+	for _, tt := range tests {
+		f := evaluateRouteProbe(tt.p, tt.r, ids)
+		if got := string(f.Severity) + " " + string(f.Code) + " " + f.Summary; got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.name, got, tt.want)
+		}
+		if f.Category != types.CategoryRouting {
+			t.Errorf("%s: category %s", tt.name, f.Category)
+		}
+	}
+}
+
+func TestRouteProbeScript(t *testing.T) {
+	p := routeProbe{scheme: "https", host: "shop.example.com", port: 443, method: "GET", path: "/", headers: []string{"X-MCP-Route-Probe: shop/web/0"}}
+	script := routeProbeScript("fd00::1", []routeProbe{p}, 5)
+	want := "curl -sk -i --max-time 5 --resolve 'shop.example.com:443:[fd00::1]' -X 'GET' -H 'X-MCP-Route-Probe: shop/web/0' 'https://shop.example.com:443/'"
+	if !strings.Contains(script, want) {
+		t.Errorf("script:\n%s\nlacks %s", script, want)
+	}
+}
@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var gatewayClassesGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gatewayclasses"}
+
+// telemetryProviders maps GatewayClass controllerName prefixes to the
+// telemetry flavour generated for them.
+var telemetryProviders = map[string]string{
+	"istio.io/":              "istio",
+	"gateway.envoyproxy.io/": "envoy-gateway",
+}
+
+func telemetryProviderFor(controllerName string) string {
+	for prefix, p := range telemetryProviders {
+		if strings.HasPrefix(controllerName, prefix) {
+			return p
+		}
+	}
+	return ""
+}
+
+// celQuote quotes s as a CEL string literal.
+func celQuote(s string) string {
+	return strconv.Quote(s)
+}
+
+// routeCELFilter builds a CEL expression over Envoy request attributes that
+// matches the traffic of an HTTPRoute: any of its hostnames and any of its
+// path matches. It returns "" when the route matches all traffic.
+func routeCELFilter(route map[string]interface{}) string {
+	var clauses []string
+
+	hostnames, _, _ := unstructured.NestedStringSlice(route, "spec", "hostnames")
+	var hosts []string
+	for _, h := range hostnames {
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			hosts = append(hosts, fmt.Sprintf("request.host.endsWith(%s)", celQuote(suffix)))
+		} else {
+			hosts = append(hosts, fmt.Sprintf("request.host == %s", celQuote(h)))
+		}
+	}
+	if len(hosts) > 0 {
+		clauses = append(clauses, "("+strings.Join(hosts, " || ")+")")
+	}
+
+	var paths []string
+	catchAll := false
+	rules, _, _ := unstructured.NestedSlice(route, "spec", "rules")
+	for _, r := range rules {
+		rm, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, _, _ := unstructured.NestedSlice(rm, "matches")
+		if len(matches) == 0 {
+			catchAll = true
+		}
+		for _, m := range matches {
+			mm, ok := m.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pathType, _, _ := unstructured.NestedString(mm, "path", "type")
+			value, _, _ := unstructured.NestedString(mm, "path", "value")
+			switch {
+			case value == "" || (value == "/" && pathType != "Exact"):
+				catchAll = true
+			case pathType == "Exact":
+				paths = append(paths, fmt.Sprintf("request.url_path == %s", celQuote(value)))
+			case pathType == "RegularExpression":
+				paths = append(paths, fmt.Sprintf("request.url_path.matches(%s)", celQuote(value)))
+			default:
+				paths = append(paths, fmt.Sprintf("request.url_path.startsWith(%s)", celQuote(value)))
+			}
+		}
+	}
+	if !catchAll && len(paths) > 0 {
+		clauses = append(clauses, "("+strings.Join(paths, " || ")+")")
+	}
+	return strings.Join(clauses, " && ")
+}
+
+// telemetryScope is the Gateway the generated telemetry attaches to and,
+// for a route, the filter narrowing it to that route's traffic.
+type telemetryScope struct {
+	Namespace string
+	Gateway   string
+	Route     string
+	Filter    string
+}
+
+func (s telemetryScope) name() string {
+	if s.Route != "" {
+		return s.Route + "-telemetry"
+	}
+	return s.Gateway + "-telemetry"
+}
+
+// istioTelemetryYAML renders a telemetry.istio.io/v1 Telemetry targeting the Gateway.
+func istioTelemetryYAML(s telemetryScope, accessLog bool, samplingPercent float64, tracingProvider string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `# Telemetry - detailed telemetry for %s
+apiVersion: telemetry.istio.io/v1
+kind: Telemetry
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  targetRefs:
+  - group: gateway.networking.k8s.io
+    kind: Gateway
+    name: %s
+`, s.describe(), s.name(), s.Namespace, s.Gateway)
+	if accessLog {
+		b.WriteString("  accessLogging:\n  - providers:\n    - name: envoy\n")
+		if s.Filter != "" {
+			fmt.Fprintf(&b, "    filter:\n      expression: %s\n", yamlQuote(s.Filter))
+		}
+	}
+	if samplingPercent > 0 {
+		fmt.Fprintf(&b, "  tracing:\n  - providers:\n    - name: %s\n    randomSamplingPercentage: %s\n", tracingProvider, formatPercent(samplingPercent))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// envoyGatewayTelemetryYAML renders an EnvoyProxy with access logging and
+// tracing, plus the Gateway patch that attaches it.
+func envoyGatewayTelemetryYAML(s telemetryScope, accessLog bool, samplingPercent float64, collector string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `# EnvoyProxy - detailed telemetry for %s
+apiVersion: gateway.envoyproxy.io/v1alpha1
+kind: EnvoyProxy
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  telemetry:
+`, s.describe(), s.name(), s.Namespace)
+	if accessLog {
+		b.WriteString("    accessLog:\n      settings:\n      - format:\n          type: JSON\n")
+		b.WriteString("          json:\n            start_time: \"%START_TIME%\"\n            method: \"%REQ(:METHOD)%\"\n            path: \"%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%\"\n            authority: \"%REQ(:AUTHORITY)%\"\n            response_code: \"%RESPONSE_CODE%\"\n            response_flags: \"%RESPONSE_FLAGS%\"\n            duration: \"%DURATION%\"\n            upstream_host: \"%UPSTREAM_HOST%\"\n            trace_id: \"%REQ(TRACEPARENT)%\"\n")
+		if s.Filter != "" {
+			fmt.Fprintf(&b, "        matches:\n        - %s\n", yamlQuote(s.Filter))
+		}
+		b.WriteString("        sinks:\n        - type: File\n          file:\n            path: /dev/stdout\n")
+	}
+	if samplingPercent > 0 {
+		host, port := splitCollector(collector)
+		svc, ns, _ := strings.Cut(host, ".")
+		// samplingRate is an integer percentage.
+		fmt.Fprintf(&b, "    tracing:\n      samplingRate: %d\n      provider:\n        type: OpenTelemetry\n        backendRefs:\n        - name: %s\n          namespace: %s\n          port: %d\n",
+			int(math.Ceil(samplingPercent)), svc, orDefault(ns, s.Namespace), port)
+	}
+	fmt.Fprintf(&b, `---
+# Gateway patch - attach the EnvoyProxy to Gateway %s/%s
+# kubectl patch gateway %s -n %s --type merge -p '{"spec":{"infrastructure":{"parametersRef":{"group":"gateway.envoyproxy.io","kind":"EnvoyProxy","name":"%s"}}}}'`,
+		s.Namespace, s.Gateway, s.Gateway, s.Namespace, s.name())
+	return b.String()
+}
+
+func (s telemetryScope) describe() string {
+	if s.Route != "" {
+		return fmt.Sprintf("HTTPRoute %s/%s via Gateway %s", s.Namespace, s.Route, s.Gateway)
+	}
+	return fmt.Sprintf("Gateway %s/%s", s.Namespace, s.Gateway)
+}
+
+// yamlQuote renders s as a single-quoted YAML scalar.
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// splitCollector parses "service.namespace:port", defaulting to the OTLP gRPC port.
+func splitCollector(endpoint string) (string, int) {
+	host, portStr, ok := strings.Cut(endpoint, ":")
+	port := 4317
+	if ok {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			port = p
+		}
+	}
+	return host, port
+}
+
+// --- generate_route_telemetry ---
+
+type GenerateRouteTelemetryTool struct{ BaseTool }
+
+func (t *GenerateRouteTelemetryTool) Name() string { return "generate_route_telemetry" }
+func (t *GenerateRouteTelemetryTool) Description() string {
+	return "Generate telemetry config (Istio Telemetry or Envoy Gateway EnvoyProxy) that turns on access logs and tracing for a single HTTPRoute or Gateway under investigation"
+}
+func (t *GenerateRouteTelemetryTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the route or gateway",
+			},
+			"route": map[string]interface{}{
+				"type":        "string",
+				"description": "HTTPRoute name; telemetry is scoped to its hostnames and paths on each parent Gateway",
+			},
+			"gateway": map[string]interface{}{
+				"type":        "string",
+				"description": "Gateway name, when no route is given",
+			},
+			"provider": map[string]interface{}{
+				"type":        "string",
+				"description": "istio or envoy-gateway (default: detected from the GatewayClass controller)",
+			},
+			"access_log": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Enable access logs (default true)",
+			},
+			"sampling_percent": map[string]interface{}{
+				"type":        "number",
+				"description": "Trace sampling percentage, 0 disables tracing (default 100)",
+			},
+			"tracing_provider": map[string]interface{}{
+				"type":        "string",
+				"description": "Istio meshConfig extensionProvider name for tracing (default otel)",
+			},
+			"otlp_collector": map[string]interface{}{
+				"type":        "string",
+				"description": "Envoy Gateway OTLP collector as service.namespace:port (default otel-collector.observability:4317)",
+			},
+		},
+		"required": []string{"namespace"},
+	}
+}
+
+func (t *GenerateRouteTelemetryTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	routeName := getStringArg(args, "route", "")
+	gwName := getStringArg(args, "gateway", "")
+	providerArg := getStringArg(args, "provider", "")
+	accessLog := true
+	if v, ok := args["access_log"].(bool); ok {
+		accessLog = v
+	}
+	sampling := 100.0
+	if v, ok := args["sampling_percent"].(float64); ok {
+		sampling = v
+	}
+	tracingProvider := getStringArg(args, "tracing_provider", "otel")
+	collector := getStringArg(args, "otlp_collector", "otel-collector.observability:4317")
+
+	if ns == "" || (routeName == "" && gwName == "") {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "namespace and one of route or gateway are required",
+		}
+	}
+	if sampling < 0 || sampling > 100 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("sampling_percent must be between 0 and 100, got %v", sampling),
+		}
+	}
+	if !accessLog && sampling == 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "nothing to generate: access_log is false and sampling_percent is 0",
+		}
+	}
+	if providerArg != "" && providerArg != "istio" && providerArg != "envoy-gateway" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unsupported provider %q: use istio or envoy-gateway", providerArg),
+		}
+	}
+
+	var scopes []telemetryScope
+	if routeName != "" {
+		route, err := getWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns, routeName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HTTPRoute %s/%s: %w", ns, routeName, err)
+		}
+		filter := routeCELFilter(route.Object)
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		for _, pr := range parentRefs {
+			prm, ok := pr.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := prm["kind"].(string); kind != "" && kind != "Gateway" {
+				continue
+			}
+			name, _ := prm["name"].(string)
+			prNs, _ := prm["namespace"].(string)
+			scopes = append(scopes, telemetryScope{Namespace: orDefault(prNs, ns), Gateway: name, Route: routeName, Filter: filter})
+		}
+		if len(scopes) == 0 {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("HTTPRoute %s/%s has no Gateway parentRefs", ns, routeName),
+				Detail:  "Mesh (GAMMA) routes attach to Services; configure telemetry on the workloads instead.",
+			}
+		}
+	} else {
+		scopes = append(scopes, telemetryScope{Namespace: ns, Gateway: gwName})
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, len(scopes)+1)
+	resources := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		ref := &types.ResourceRef{Kind: "Gateway", Namespace: s.Namespace, Name: s.Gateway, APIVersion: "gateway.networking.k8s.io/v1"}
+		provider := providerArg
+		controller := ""
+		if provider == "" {
+			controller = t.gatewayController(ctx, s.Namespace, s.Gateway)
+			provider = telemetryProviderFor(controller)
+		}
+
+		var yaml string
+		switch provider {
+		case "istio":
+			yaml = istioTelemetryYAML(s, accessLog, sampling, tracingProvider)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryRouting,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Generated Istio Telemetry for %s", s.describe()),
+				Detail:     yaml,
+				Suggestion: fmt.Sprintf("Tracing uses the %q extensionProvider, which must be defined in meshConfig. Delete the Telemetry when the investigation is done.", tracingProvider),
+			})
+		case "envoy-gateway":
+			yaml = envoyGatewayTelemetryYAML(s, accessLog, sampling, collector)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryRouting,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("Generated Envoy Gateway EnvoyProxy telemetry for %s", s.describe()),
+				Detail:     yaml,
+				Suggestion: "Access logs are filtered to the route; the trace sampling rate applies to the whole Gateway. The parametersRef patch redeploys the Gateway's Envoy; remove it when the investigation is done.",
+			})
+		default:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeObservabilityNoTelemetryTemplate,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("No telemetry template for Gateway %s/%s", s.Namespace, s.Gateway),
+				Detail:     fmt.Sprintf("controllerName=%s", orDefault(controller, "unknown")),
+				Suggestion: "Pass provider=istio or provider=envoy-gateway to generate config anyway.",
+			})
+			continue
+		}
+		resources = append(resources, yaml)
+	}
+
+	if len(resources) > 1 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("Complete telemetry configuration: %d Gateways", len(resources)),
+			Detail:   strings.Join(resources, "\n---\n"),
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, "gateway-api"), nil
+}
+
+// gatewayController returns the controllerName of a Gateway's GatewayClass, or "".
+func (t *GenerateRouteTelemetryTool) gatewayController(ctx context.Context, ns, name string) string {
+	gw, err := getWithFallback(ctx, t.Clients.Dynamic, gatewaysV1GVR, gatewaysV1B1GVR, ns, name)
+	if err != nil {
+		return ""
+	}
+	className, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+	gc, err := t.Clients.Dynamic.Resource(gatewayClassesGVR).Get(ctx, className, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	controller, _, _ := unstructured.NestedString(gc.Object, "spec", "controllerName")
+	return controller
+}
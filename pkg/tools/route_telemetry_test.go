@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouteCELFilter(t *testing.T) {
+	route := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"shop.example.com", "*.shop.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{"matches": []interface{}{
+					map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}},
+					map[string]interface{}{"path": map[string]interface{}{"type": "Exact", "value": "/healthz"}},
+				}},
+			},
+		},
+	}
+	want := `(request.host == "shop.example.com" || request.host.endsWith(".shop.example.com")) && (request.url_path.startsWith("/api") || request.url_path == "/healthz")`
+	if got := routeCELFilter(route); got != want {
+		t.Errorf("routeCELFilter() =\n%s\nwant\n%s", got, want)
+	}
+
+	catchAll := map[string]interface{}{"spec": map[string]interface{}{"rules": []interface{}{map[string]interface{}{}}}}
+	if got := routeCELFilter(catchAll); got != "" {
+		t.Errorf("expected no filter for a catch-all route, got %q", got)
+	}
+}
+
+func TestTelemetryYAML(t *testing.T) {
+	s := telemetryScope{Namespace: "infra", Gateway: "public", Route: "shop", Filter: `request.host == "shop.example.com"`}
+
+	istio := istioTelemetryYAML(s, true, 12.5, "otel")
+	for _, want := range []string{"kind: Telemetry", "name: shop-telemetry", "kind: Gateway\n    name: public", `expression: 'request.host == "shop.example.com"'`, "randomSamplingPercentage: 12.5"} {
+		if !strings.Contains(istio, want) {
+			t.Errorf("Istio Telemetry missing %q:\n%s", want, istio)
+		}
+	}
+
+	eg := envoyGatewayTelemetryYAML(s, false, 12.5, "tempo.tracing:4317")
+	for _, want := range []string{"kind: EnvoyProxy", "samplingRate: 13", "name: tempo\n          namespace: tracing", `"kind":"EnvoyProxy","name":"shop-telemetry"`} {
+		if !strings.Contains(eg, want) {
+			t.Errorf("EnvoyProxy missing %q:\n%s", want, eg)
+		}
+	}
+	if strings.Contains(eg, "accessLog") {
+		t.Errorf("access_log=false should omit access logging:\n%s", eg)
+	}
+}
+
+func TestTelemetryProviderFor(t *testing.T) {
+	if p := telemetryProviderFor("istio.io/gateway-controller"); p != "istio" {
+		t.Errorf("got %q", p)
+	}
+	if p := telemetryProviderFor("gateway.envoyproxy.io/gatewayclass-controller"); p != "envoy-gateway" {
+		t.Errorf("got %q", p)
+	}
+	if p := telemetryProviderFor("example.com/other"); p != "" {
+		t.Errorf("got %q", p)
+	}
+}
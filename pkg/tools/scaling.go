@@ -0,0 +1,737 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// minRecommendedReplicas keeps CoreDNS and gateways highly available regardless of load.
+const minRecommendedReplicas = 2
+
+// scalingProfile describes how to measure load on a component and how much
+// one replica can serve. Capacities are conservative per-replica figures for
+// 1 vCPU, the usual upper bound before adding replicas beats adding CPU.
+type scalingProfile struct {
+	Component   string
+	Port        string
+	Path        string
+	RateMetrics []string // counters summed for requests/s; first one present wins
+	ConnMetrics []string // gauges summed for active connections
+	Containers  []string // container to size; first match wins, else the first container
+
+	RPSPerReplica   float64
+	ConnsPerReplica float64
+	// MilliCPUPerKRPS is the CPU request per 1000 requests/s on one replica.
+	MilliCPUPerKRPS float64
+	MinMilliCPU     int64
+	BaseMemoryMi    int64
+	// MemoryKiPerConn is added per active connection on one replica.
+	MemoryKiPerConn int64
+}
+
+var (
+	coreDNSProfile = scalingProfile{
+		Component:       "coredns",
+		Port:            "9153",
+		Path:            "/metrics",
+		RateMetrics:     []string{"coredns_dns_requests_total", "coredns_dns_request_count_total"},
+		Containers:      []string{"coredns"},
+		RPSPerReplica:   5000,
+		MilliCPUPerKRPS: 100,
+		MinMilliCPU:     100,
+		BaseMemoryMi:    70,
+	}
+	gatewayProfile = scalingProfile{
+		Component:       "gateway",
+		Port:            "15090",
+		Path:            "/stats/prometheus",
+		RateMetrics:     []string{"envoy_http_downstream_rq_total"},
+		ConnMetrics:     []string{"envoy_http_downstream_cx_active"},
+		Containers:      proxyContainerNames,
+		RPSPerReplica:   2500,
+		ConnsPerReplica: 10000,
+		MilliCPUPerKRPS: 400,
+		MinMilliCPU:     100,
+		BaseMemoryMi:    128,
+		MemoryKiPerConn: 32,
+	}
+)
+
+// loadSample is the load measured across all pods of a component.
+type loadSample struct {
+	Pods        int
+	RPS         float64
+	Connections float64
+}
+
+// scalingPlan is the recommended size of a component.
+type scalingPlan struct {
+	Replicas    int32
+	MilliCPU    int64 // CPU request per replica
+	MemoryMi    int64 // memory request per replica
+	DemandRPS   float64
+	DemandConns float64
+}
+
+// planScaling sizes a component for the sampled load plus headroom.
+// coreDNSMemoryMi, when non-zero, replaces the profile's memory model with
+// the CoreDNS cache estimate, which depends on cluster size rather than load.
+func planScaling(p scalingProfile, load loadSample, headroomPercent int, coreDNSMemoryMi int64) scalingPlan {
+	factor := 1 + float64(headroomPercent)/100
+	plan := scalingPlan{DemandRPS: load.RPS * factor, DemandConns: load.Connections * factor}
+
+	replicas := minRecommendedReplicas
+	if p.RPSPerReplica > 0 {
+		replicas = max(replicas, int(math.Ceil(plan.DemandRPS/p.RPSPerReplica)))
+	}
+	if p.ConnsPerReplica > 0 {
+		replicas = max(replicas, int(math.Ceil(plan.DemandConns/p.ConnsPerReplica)))
+	}
+	plan.Replicas = int32(replicas)
+
+	perReplicaRPS := plan.DemandRPS / float64(replicas)
+	cpu := int64(math.Ceil(perReplicaRPS / 1000 * p.MilliCPUPerKRPS))
+	plan.MilliCPU = roundUp(max(cpu, p.MinMilliCPU), 10)
+
+	if coreDNSMemoryMi > 0 {
+		plan.MemoryMi = coreDNSMemoryMi
+	} else {
+		perReplicaConns := plan.DemandConns / float64(replicas)
+		plan.MemoryMi = p.BaseMemoryMi + int64(math.Ceil(perReplicaConns*float64(p.MemoryKiPerConn)/1024))
+	}
+	plan.MemoryMi = roundUp(plan.MemoryMi, 8)
+	return plan
+}
+
+// coreDNSMemoryEstimate is the CoreDNS memory guidance for default settings:
+// (pods + services) / 1000 + 54 MiB, rounded up to the profile's floor.
+func coreDNSMemoryEstimate(pods, services int) int64 {
+	return max(int64(math.Ceil(float64(pods+services)/1000))+54, coreDNSProfile.BaseMemoryMi)
+}
+
+func roundUp(v, step int64) int64 {
+	return (v + step - 1) / step * step
+}
+
+// quotaHeadroom is the room left in a namespace's ResourceQuotas once the
+// component's own current usage is given back. A negative value means unlimited.
+type quotaHeadroom struct {
+	MilliCPU int64
+	MemoryMi int64
+	Pods     int64
+	Quotas   []string
+}
+
+// newQuotaHeadroom derives the room available to the component from every
+// quota in its namespace, taking the tightest limit per resource.
+func newQuotaHeadroom(quotas []corev1.ResourceQuota, currentReplicas int32, currentCPU, currentMem resource.Quantity) quotaHeadroom {
+	h := quotaHeadroom{MilliCPU: -1, MemoryMi: -1, Pods: -1}
+	for _, q := range quotas {
+		constrained := false
+		if free, ok := quotaFree(q, corev1.ResourceRequestsCPU, corev1.ResourceCPU); ok {
+			free.Add(multiplyQuantity(currentCPU, currentReplicas))
+			h.MilliCPU = tighter(h.MilliCPU, free.MilliValue())
+			constrained = true
+		}
+		if free, ok := quotaFree(q, corev1.ResourceRequestsMemory, corev1.ResourceMemory); ok {
+			free.Add(multiplyQuantity(currentMem, currentReplicas))
+			h.MemoryMi = tighter(h.MemoryMi, free.Value()/(1024*1024))
+			constrained = true
+		}
+		if free, ok := quotaFree(q, corev1.ResourcePods); ok {
+			h.Pods = tighter(h.Pods, free.Value()+int64(currentReplicas))
+			constrained = true
+		}
+		if constrained {
+			h.Quotas = append(h.Quotas, q.Name)
+		}
+	}
+	return h
+}
+
+// quotaFree returns hard minus used for the first of names the quota sets.
+func quotaFree(q corev1.ResourceQuota, names ...corev1.ResourceName) (resource.Quantity, bool) {
+	for _, name := range names {
+		hard, ok := q.Status.Hard[name]
+		if !ok {
+			hard, ok = q.Spec.Hard[name]
+		}
+		if !ok {
+			continue
+		}
+		free := hard.DeepCopy()
+		if used, ok := q.Status.Used[name]; ok {
+			free.Sub(used)
+		}
+		return free, true
+	}
+	return resource.Quantity{}, false
+}
+
+func multiplyQuantity(q resource.Quantity, n int32) resource.Quantity {
+	out := resource.Quantity{Format: q.Format}
+	for i := int32(0); i < n; i++ {
+		out.Add(q)
+	}
+	return out
+}
+
+func tighter(current, v int64) int64 {
+	if v < 0 {
+		v = 0
+	}
+	if current < 0 || v < current {
+		return v
+	}
+	return current
+}
+
+// maxReplicas returns how many replicas of the plan fit, or -1 when unlimited.
+func (h quotaHeadroom) maxReplicas(plan scalingPlan) int32 {
+	limit := int64(-1)
+	if h.MilliCPU >= 0 && plan.MilliCPU > 0 {
+		limit = tighter(limit, h.MilliCPU/plan.MilliCPU)
+	}
+	if h.MemoryMi >= 0 && plan.MemoryMi > 0 {
+		limit = tighter(limit, h.MemoryMi/plan.MemoryMi)
+	}
+	if h.Pods >= 0 {
+		limit = tighter(limit, h.Pods)
+	}
+	return int32(limit)
+}
+
+// sumPromMetric sums every sample of a metric in Prometheus text exposition
+// format, across all label sets. It reports whether the metric was present.
+func sumPromMetric(text, name string) (float64, bool) {
+	var total float64
+	found := false
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || !strings.HasPrefix(line, name) {
+			continue
+		}
+		rest := line[len(name):]
+		switch {
+		case strings.HasPrefix(rest, "{"):
+			end := strings.LastIndex(rest, "}")
+			if end < 0 {
+				continue
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, " "), strings.HasPrefix(rest, "\t"):
+		default:
+			continue // a longer metric name sharing the prefix
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || math.IsNaN(v) {
+			continue
+		}
+		total += v
+		found = true
+	}
+	return total, found
+}
+
+// firstPromMetric sums the first of names present in text.
+func firstPromMetric(text string, names []string) (float64, string, bool) {
+	for _, n := range names {
+		if v, ok := sumPromMetric(text, n); ok {
+			return v, n, true
+		}
+	}
+	return 0, "", false
+}
+
+// promSample is one sample of a metric with its labels.
+type promSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// promSamples returns every sample of a metric in Prometheus text exposition
+// format with its labels, for metrics whose labels matter (per-proxy errors).
+func promSamples(text, name string) []promSample {
+	var out []promSample
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || !strings.HasPrefix(line, name) {
+			continue
+		}
+		rest := line[len(name):]
+		sample := promSample{Labels: make(map[string]string)}
+		switch {
+		case strings.HasPrefix(rest, "{"):
+			var ok bool
+			rest, ok = parsePromLabels(rest[1:], sample.Labels)
+			if !ok {
+				continue
+			}
+		case strings.HasPrefix(rest, " "), strings.HasPrefix(rest, "\t"):
+		default:
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || math.IsNaN(v) {
+			continue
+		}
+		sample.Value = v
+		out = append(out, sample)
+	}
+	return out
+}
+
+// parsePromLabels reads name="value" pairs up to the closing brace into
+// labels and returns the rest of the line.
+func parsePromLabels(s string, labels map[string]string) (string, bool) {
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return s[1:], true
+		}
+		eq := strings.Index(s, "=\"")
+		if eq < 0 {
+			return "", false
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+		var value strings.Builder
+		i := 0
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return "", false
+		}
+		labels[name] = value.String()
+		s = s[i+1:]
+	}
+}
+
+// --- recommend_scaling ---
+
+type RecommendScalingTool struct{ BaseTool }
+
+func (t *RecommendScalingTool) Name() string { return "recommend_scaling" }
+func (t *RecommendScalingTool) Description() string {
+	return "Recommend replica counts and resource requests for CoreDNS or a gateway data plane from measured QPS and connections, capped by the namespace's ResourceQuotas, with the patch YAML to apply"
+}
+func (t *RecommendScalingTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"target": map[string]interface{}{
+				"type":        "string",
+				"description": "Component to size: coredns or gateway",
+				"enum":        []string{"coredns", "gateway"},
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the Deployment (default: kube-system for coredns)",
+			},
+			"deployment": map[string]interface{}{
+				"type":        "string",
+				"description": "Deployment name (default: coredns for coredns; required for gateway)",
+			},
+			"metrics_port": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod port serving Prometheus metrics (default: 9153 for coredns, 15090 for gateway)",
+			},
+			"metrics_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Metrics path (default: /metrics for coredns, /stats/prometheus for gateway)",
+			},
+			"sample_seconds": map[string]interface{}{
+				"type":        "integer",
+				"description": "Seconds between the two metric scrapes used to compute rates (default: 5, max: 30)",
+			},
+			"headroom_percent": map[string]interface{}{
+				"type":        "integer",
+				"description": "Extra capacity on top of the measured load, in percent (default: 50)",
+			},
+		},
+		"required": []string{"target"},
+	}
+}
+
+func (t *RecommendScalingTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	target := getStringArg(args, "target", "")
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "deployment", "")
+
+	var profile scalingProfile
+	switch target {
+	case "coredns":
+		profile = coreDNSProfile
+		ns = orDefault(ns, "kube-system")
+		name = orDefault(name, "coredns")
+	case "gateway":
+		profile = gatewayProfile
+		if ns == "" || name == "" {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: "namespace and deployment are required for target=gateway",
+			}
+		}
+	default:
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("unknown target %q; expected coredns or gateway", target),
+		}
+	}
+	profile.Port = getStringArg(args, "metrics_port", profile.Port)
+	profile.Path = getStringArg(args, "metrics_path", profile.Path)
+
+	sampleSeconds := getIntArg(args, "sample_seconds", 5)
+	if sampleSeconds < 1 || sampleSeconds > 30 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("sample_seconds %d out of range (1-30)", sampleSeconds),
+		}
+	}
+	headroom := getIntArg(args, "headroom_percent", 50)
+	if headroom < 0 || headroom > 500 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("headroom_percent %d out of range (0-500)", headroom),
+		}
+	}
+
+	deploy, err := t.Clients.Clientset.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("deployment %s/%s not found", ns, name),
+			Detail:  err.Error(),
+		}
+	}
+	ref := &types.ResourceRef{Kind: "Deployment", Namespace: ns, Name: name, APIVersion: "apps/v1"}
+	container := scalingContainer(deploy, profile.Containers)
+	if container == nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("deployment %s/%s has no containers", ns, name),
+		}
+	}
+
+	findings := make([]types.DiagnosticFinding, 0, 4)
+
+	load, metricNote, err := t.sampleLoad(ctx, deploy, profile, time.Duration(sampleSeconds)*time.Second)
+	if err != nil {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   categoryFor(profile),
+			Code:       types.CodeScalingLoadUnmeasured,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("Could not measure load on %s/%s", ns, name),
+			Detail:     err.Error(),
+			Suggestion: fmt.Sprintf("Check that pods expose metrics on port %s%s and that the server may get pods/proxy in %s", profile.Port, profile.Path, ns),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	var dnsMemory int64
+	if target == "coredns" {
+		pods, perr := t.listResource(ctx, podsGVR, "")
+		svcs, serr := t.listResource(ctx, servicesGVR, "")
+		if perr == nil && serr == nil {
+			dnsMemory = coreDNSMemoryEstimate(len(pods.Items), len(svcs.Items))
+		}
+	}
+	plan := planScaling(profile, load, headroom, dnsMemory)
+
+	currentReplicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		currentReplicas = *deploy.Spec.Replicas
+	}
+	curCPU := container.Resources.Requests[corev1.ResourceCPU]
+	curMem := container.Resources.Requests[corev1.ResourceMemory]
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: categoryFor(profile),
+		Resource: ref,
+		Summary: fmt.Sprintf("Measured %.0f req/s and %.0f active connections across %d pod(s) of %s/%s",
+			load.RPS, load.Connections, load.Pods, ns, name),
+		Detail: fmt.Sprintf("sample=%ds %s headroom=%d%% demand_rps=%.0f demand_connections=%.0f current: replicas=%d cpu=%s memory=%s",
+			sampleSeconds, metricNote, headroom, plan.DemandRPS, plan.DemandConns, currentReplicas, quantityOrNone(curCPU), quantityOrNone(curMem)),
+	})
+
+	// ResourceQuota caps what the namespace can actually schedule.
+	recommended := plan.Replicas
+	quotas, err := t.Clients.Clientset.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		h := newQuotaHeadroom(quotas.Items, currentReplicas, curCPU, curMem)
+		if fit := h.maxReplicas(plan); fit >= 0 && fit < plan.Replicas {
+			recommended = max(fit, 1)
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityWarning,
+				Category: categoryFor(profile),
+				Code:     types.CodeScalingQuotaLimitsReplicas,
+				Resource: ref,
+				Summary: fmt.Sprintf("ResourceQuota in %s allows only %d of the %d recommended replicas of %s",
+					ns, fit, plan.Replicas, name),
+				Detail: fmt.Sprintf("quotas=%s available: cpu=%s memory=%s pods=%s per replica: cpu=%dm memory=%dMi",
+					strings.Join(h.Quotas, ","), quotaValue(h.MilliCPU, "m"), quotaValue(h.MemoryMi, "Mi"), quotaValue(h.Pods, ""), plan.MilliCPU, plan.MemoryMi),
+				Suggestion: fmt.Sprintf("Raise the quota in %s by at least cpu=%dm memory=%dMi, or accept %d replicas with less headroom",
+					ns, int64(plan.Replicas-fit)*plan.MilliCPU, int64(plan.Replicas-fit)*plan.MemoryMi, recommended),
+			})
+		}
+	}
+
+	hpa := t.findHPA(ctx, deploy)
+	severity := types.SeverityOK
+	var code types.FindingCode
+	summary := fmt.Sprintf("%s/%s is sized for the measured load", ns, name)
+	if recommended > currentReplicas || requestBelow(curCPU, plan.MilliCPU, true) || requestBelow(curMem, plan.MemoryMi, false) {
+		severity = types.SeverityWarning
+		code = types.CodeScalingUndersized
+		summary = fmt.Sprintf("%s/%s is undersized: recommend %d replica(s) with cpu=%dm memory=%dMi", ns, name, recommended, plan.MilliCPU, plan.MemoryMi)
+	} else if recommended < currentReplicas {
+		severity = types.SeverityInfo
+		summary = fmt.Sprintf("%s/%s can scale down to %d replica(s) with cpu=%dm memory=%dMi", ns, name, recommended, plan.MilliCPU, plan.MemoryMi)
+	}
+
+	detail := fmt.Sprintf("recommended: replicas=%d cpu=%dm memory=%dMi (capacity per replica: %.0f req/s", recommended, plan.MilliCPU, plan.MemoryMi, profile.RPSPerReplica)
+	if profile.ConnsPerReplica > 0 {
+		detail += fmt.Sprintf(", %.0f connections", profile.ConnsPerReplica)
+	}
+	detail += ")"
+	if hpa != nil {
+		detail += fmt.Sprintf(" hpa=%s min=%d max=%d", hpa.Name, hpaMin(hpa), hpa.Spec.MaxReplicas)
+	}
+
+	findings = append(findings, types.DiagnosticFinding{
+		Severity:   severity,
+		Category:   categoryFor(profile),
+		Code:       code,
+		Resource:   ref,
+		Summary:    summary,
+		Detail:     detail,
+		Suggestion: scalingPatchYAML(deploy, container.Name, hpa, recommended, plan),
+	})
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// sampleLoad scrapes every ready pod of the deployment twice, sampleFor apart,
+// and returns the request rate and current connections summed across pods.
+func (t *RecommendScalingTool) sampleLoad(ctx context.Context, deploy *appsv1.Deployment, p scalingProfile, sampleFor time.Duration) (loadSample, string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return loadSample{}, "", fmt.Errorf("invalid deployment selector: %w", err)
+	}
+	pods, err := t.Clients.Clientset.CoreV1().Pods(deploy.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return loadSample{}, "", fmt.Errorf("listing pods: %w", err)
+	}
+	var names []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			names = append(names, pod.Name)
+		}
+	}
+	if len(names) == 0 {
+		return loadSample{}, "", fmt.Errorf("no running pods match %s", selector.String())
+	}
+
+	scrape := func() (map[string]string, error) {
+		out := make(map[string]string, len(names))
+		for _, n := range names {
+			raw, err := t.Clients.Clientset.CoreV1().Pods(deploy.Namespace).ProxyGet("http", n, p.Port, p.Path, nil).DoRaw(ctx)
+			if err != nil {
+				continue // pod restarting or not exposing metrics; measure the rest
+			}
+			out[n] = string(raw)
+		}
+		if len(out) == 0 {
+			return nil, fmt.Errorf("no pod served metrics on port %s path %s", p.Port, p.Path)
+		}
+		return out, nil
+	}
+
+	first, err := scrape()
+	if err != nil {
+		return loadSample{}, "", err
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		return loadSample{}, "", ctx.Err()
+	case <-time.After(sampleFor):
+	}
+	second, err := scrape()
+	if err != nil {
+		return loadSample{}, "", err
+	}
+	elapsed := time.Since(start).Seconds()
+
+	var load loadSample
+	var rateMetric string
+	for n, after := range second {
+		before, ok := first[n]
+		if !ok {
+			continue
+		}
+		v1, metric, ok1 := firstPromMetric(before, p.RateMetrics)
+		v2, _, ok2 := firstPromMetric(after, []string{metric})
+		if !ok1 || !ok2 {
+			continue
+		}
+		rateMetric = metric
+		load.Pods++
+		if v2 >= v1 { // a decrease means the counter reset
+			load.RPS += (v2 - v1) / elapsed
+		}
+		if conns, _, ok := firstPromMetric(after, p.ConnMetrics); ok {
+			load.Connections += conns
+		}
+	}
+	if load.Pods == 0 {
+		return loadSample{}, "", fmt.Errorf("metrics did not include any of: %s", strings.Join(p.RateMetrics, ", "))
+	}
+	return load, "metric=" + rateMetric, nil
+}
+
+// findHPA returns the HorizontalPodAutoscaler targeting the deployment, if any.
+func (t *RecommendScalingTool) findHPA(ctx context.Context, deploy *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	hpas, err := t.Clients.Clientset.AutoscalingV2().HorizontalPodAutoscalers(deploy.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	for i := range hpas.Items {
+		ref := hpas.Items[i].Spec.ScaleTargetRef
+		if ref.Kind == "Deployment" && ref.Name == deploy.Name {
+			return &hpas.Items[i]
+		}
+	}
+	return nil
+}
+
+// scalingContainer picks the container to size: the first whose name is in
+// preferred, otherwise the first container.
+func scalingContainer(deploy *appsv1.Deployment, preferred []string) *corev1.Container {
+	cs := deploy.Spec.Template.Spec.Containers
+	for _, want := range preferred {
+		for i := range cs {
+			if cs[i].Name == want {
+				return &cs[i]
+			}
+		}
+	}
+	if len(cs) == 0 {
+		return nil
+	}
+	return &cs[0]
+}
+
+// scalingPatchYAML renders the strategic-merge patch for the deployment. When
+// an HPA owns the replica count, replicas are set through it instead.
+func scalingPatchYAML(deploy *appsv1.Deployment, container string, hpa *autoscalingv2.HorizontalPodAutoscaler, replicas int32, plan scalingPlan) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `# Apply with: kubectl patch deployment %s -n %s --patch-file <file>
+spec:
+`, deploy.Name, deploy.Namespace)
+	if hpa == nil {
+		fmt.Fprintf(&sb, "  replicas: %d\n", replicas)
+	}
+	fmt.Fprintf(&sb, `  template:
+    spec:
+      containers:
+      - name: %s
+        resources:
+          requests:
+            cpu: %dm
+            memory: %dMi`, container, plan.MilliCPU, plan.MemoryMi)
+	if hpa != nil {
+		maxReplicas := max(hpa.Spec.MaxReplicas, replicas)
+		fmt.Fprintf(&sb, `
+---
+# Apply with: kubectl patch hpa %s -n %s --patch-file <file>
+spec:
+  minReplicas: %d
+  maxReplicas: %d`, hpa.Name, hpa.Namespace, replicas, maxReplicas)
+	}
+	return sb.String()
+}
+
+func categoryFor(p scalingProfile) string {
+	if p.Component == "coredns" {
+		return types.CategoryDNS
+	}
+	return types.CategoryRouting
+}
+
+func hpaMin(hpa *autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if hpa.Spec.MinReplicas == nil {
+		return 1
+	}
+	return *hpa.Spec.MinReplicas
+}
+
+// requestBelow reports whether the current request is unset or below the
+// recommendation, given in millicores (cpu) or MiB.
+func requestBelow(cur resource.Quantity, want int64, cpu bool) bool {
+	if cur.IsZero() {
+		return true
+	}
+	if cpu {
+		return cur.MilliValue() < want
+	}
+	return cur.Value() < want*1024*1024
+}
+
+func quantityOrNone(q resource.Quantity) string {
+	if q.IsZero() {
+		return "none"
+	}
+	return q.String()
+}
+
+func quotaValue(v int64, unit string) string {
+	if v < 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(v, 10) + unit
+}
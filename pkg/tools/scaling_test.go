@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSumPromMetric(t *testing.T) {
+	text := `# HELP coredns_dns_requests_total Counter of DNS requests made per zone, protocol and family.
+# TYPE coredns_dns_requests_total counter
+coredns_dns_requests_total{family="1",proto="udp",server="dns://:53",type="A",zone="."} 1200
+coredns_dns_requests_total{family="1",proto="tcp",server="dns://:53",type="AAAA",zone="."} 300 1700000000000
+coredns_dns_requests_total_extra 99
+coredns_dns_request_duration_seconds_count 5
+`
+	v, ok := sumPromMetric(text, "coredns_dns_requests_total")
+	if !ok || v != 1500 {
+		t.Errorf("expected 1500, got %v (found=%v)", v, ok)
+	}
+	if _, ok := sumPromMetric(text, "envoy_http_downstream_rq_total"); ok {
+		t.Error("expected missing metric to be reported as absent")
+	}
+	if v, name, ok := firstPromMetric("coredns_dns_request_count_total 42\n", coreDNSProfile.RateMetrics); !ok || v != 42 || name != "coredns_dns_request_count_total" {
+		t.Errorf("expected fallback metric, got %v %q %v", v, name, ok)
+	}
+}
+
+func TestPlanScaling_GatewayConnections(t *testing.T) {
+	// 4000 rps needs 3 replicas with 50% headroom; 40000 connections need 6.
+	plan := planScaling(gatewayProfile, loadSample{RPS: 4000, Connections: 40000}, 50, 0)
+	if plan.Replicas != 6 {
+		t.Errorf("expected connections to drive 6 replicas, got %d", plan.Replicas)
+	}
+	if plan.MilliCPU != 400 {
+		t.Errorf("expected 6000rps/6 * 400m/krps = 400m, got %dm", plan.MilliCPU)
+	}
+	if plan.MemoryMi != 448 {
+		t.Errorf("expected 128Mi + 10000 conns * 32Ki = 440Mi rounded to 448Mi, got %dMi", plan.MemoryMi)
+	}
+}
+
+func TestPlanScaling_IdleKeepsMinimum(t *testing.T) {
+	plan := planScaling(coreDNSProfile, loadSample{}, 50, coreDNSMemoryEstimate(30000, 2000))
+	if plan.Replicas != minRecommendedReplicas {
+		t.Errorf("expected %d replicas when idle, got %d", minRecommendedReplicas, plan.Replicas)
+	}
+	if plan.MilliCPU != coreDNSProfile.MinMilliCPU {
+		t.Errorf("expected minimum CPU, got %dm", plan.MilliCPU)
+	}
+	if plan.MemoryMi != 88 {
+		t.Errorf("expected 32000/1000 + 54 = 86Mi rounded to 88Mi, got %dMi", plan.MemoryMi)
+	}
+}
+
+func TestQuotaHeadroom_CapsReplicas(t *testing.T) {
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-quota"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("2"),
+				corev1.ResourcePods:        resource.MustParse("20"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("1500m"),
+				corev1.ResourcePods:        resource.MustParse("10"),
+			},
+		},
+	}
+	// The gateway itself uses 2 x 200m of the 1500m in use, so 900m is available.
+	h := newQuotaHeadroom([]corev1.ResourceQuota{quota}, 2, resource.MustParse("200m"), resource.Quantity{})
+	if h.MilliCPU != 900 || h.Pods != 12 || h.MemoryMi != -1 {
+		t.Fatalf("unexpected headroom %+v", h)
+	}
+	if fit := h.maxReplicas(scalingPlan{Replicas: 6, MilliCPU: 400, MemoryMi: 448}); fit != 2 {
+		t.Errorf("expected 900m / 400m = 2 replicas to fit, got %d", fit)
+	}
+	if fit := newQuotaHeadroom(nil, 2, resource.Quantity{}, resource.Quantity{}).maxReplicas(scalingPlan{MilliCPU: 100}); fit != -1 {
+		t.Errorf("expected no quota to be unlimited, got %d", fit)
+	}
+}
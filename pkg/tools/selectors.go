@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// listSelector narrows a listing by label and field selector, in the same
+// syntax as kubectl -l and --field-selector.
+type listSelector struct {
+	Label string
+	Field string
+}
+
+// selected reports whether the caller narrowed the listing.
+func (s listSelector) selected() bool { return s.Label != "" || s.Field != "" }
+
+func getSelectorArgs(args map[string]interface{}) listSelector {
+	return listSelector{
+		Label: strings.TrimSpace(getStringArg(args, "label_selector", "")),
+		Field: strings.TrimSpace(getStringArg(args, "field_selector", "")),
+	}
+}
+
+// withSelectors adds the label_selector and field_selector properties to a tool schema.
+func withSelectors(schema map[string]interface{}) map[string]interface{} {
+	props, _ := schema["properties"].(map[string]interface{})
+	if props == nil {
+		props = map[string]interface{}{}
+		schema["properties"] = props
+	}
+	props["label_selector"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Label selector to filter results (e.g., team=payments,tier!=canary)",
+	}
+	props["field_selector"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Field selector to filter results (e.g., metadata.name=frontend); custom resources only support metadata.name and metadata.namespace",
+	}
+	return schema
+}
+
+// listOptions validates the selectors and returns them as ListOptions. Parse
+// failures are returned as BadRequest, as the API server would.
+func (s listSelector) listOptions() (metav1.ListOptions, error) {
+	if _, err := labels.Parse(s.Label); err != nil {
+		return metav1.ListOptions{}, apierrors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %v", s.Label, err))
+	}
+	if _, err := fields.ParseSelector(s.Field); err != nil {
+		return metav1.ListOptions{}, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector %q: %v", s.Field, err))
+	}
+	return metav1.ListOptions{LabelSelector: s.Label, FieldSelector: s.Field}, nil
+}
+
+// listResourceSelected lists gvr narrowed by sel. Selected lists go straight
+// to the API server; unselected ones are served from the shared snapshot.
+func (b *BaseTool) listResourceSelected(ctx context.Context, gvr schema.GroupVersionResource, ns string, sel listSelector) (*unstructured.UnstructuredList, error) {
+	if !sel.selected() {
+		return b.listResource(ctx, gvr, ns)
+	}
+	opts, err := sel.listOptions()
+	if err != nil {
+		return nil, err
+	}
+	var list *unstructured.UnstructuredList
+	if ns == "" {
+		list, err = b.Clients.Dynamic.Resource(gvr).List(ctx, opts)
+	} else {
+		list, err = b.Clients.Dynamic.Resource(gvr).Namespace(ns).List(ctx, opts)
+	}
+	if !isSelectorError(err) {
+		recordListError(ctx, gvr, ns, err)
+	}
+	return list, err
+}
+
+// listResourceSelectedWithFallback is listResourceSelected with a v1beta1 fallback.
+func (b *BaseTool) listResourceSelectedWithFallback(ctx context.Context, v1, v1beta1 schema.GroupVersionResource, ns string, sel listSelector) (*unstructured.UnstructuredList, error) {
+	list, err := b.listResourceSelected(ctx, v1, ns, sel)
+	if err == nil || isSelectorError(err) {
+		return list, err
+	}
+	return b.listResourceSelected(ctx, v1beta1, ns, sel)
+}
+
+// isSelectorError reports whether err was caused by a malformed or
+// unsupported selector rather than by the resource itself.
+func isSelectorError(err error) bool {
+	if !apierrors.IsBadRequest(err) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "selector") || strings.Contains(msg, "field label")
+}
+
+// selectorError converts selector failures into INVALID_INPUT. It returns nil
+// for other errors.
+func selectorError(tool string, err error) error {
+	if !isSelectorError(err) {
+		return nil
+	}
+	return &types.MCPError{
+		Code:    types.ErrCodeInvalidInput,
+		Tool:    tool,
+		Message: "label_selector or field_selector is invalid or not supported for this resource",
+		Detail:  err.Error(),
+	}
+}
+
+// listArgError converts failures caused by list arguments (continue_token,
+// label_selector, field_selector) into INVALID_INPUT. It returns nil for other errors.
+func listArgError(tool string, err error) error {
+	if perr := pageError(tool, err); perr != nil {
+		return perr
+	}
+	return selectorError(tool, err)
+}
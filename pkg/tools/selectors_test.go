@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestGetPageArgs_Selectors(t *testing.T) {
+	p := getPageArgs(map[string]interface{}{"label_selector": " team=payments ", "field_selector": "metadata.name=web"})
+	if p.paged() || !p.selected() {
+		t.Errorf("selectors alone should not page, got %+v", p)
+	}
+	if p.Label != "team=payments" || p.Field != "metadata.name=web" {
+		t.Errorf("getPageArgs() = %+v", p)
+	}
+}
+
+func TestListResourceSelected_FiltersByLabel(t *testing.T) {
+	svc := func(name, team string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("Service")
+		u.SetNamespace("shop")
+		u.SetName(name)
+		u.SetLabels(map[string]string{"team": team})
+		return u
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{servicesGVR: "ServiceList"},
+		svc("checkout", "payments"), svc("catalog", "search"))
+	b := &BaseTool{Clients: &k8s.Clients{Dynamic: client}, Snapshot: NewClusterSnapshot(client, time.Minute)}
+
+	list, err := b.listResourceSelected(context.Background(), servicesGVR, "shop", listSelector{Label: "team=payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "checkout" {
+		t.Errorf("expected only checkout, got %d items", len(list.Items))
+	}
+
+	if _, err := b.listResourceSelected(context.Background(), servicesGVR, "shop", listSelector{Label: "team in (payments"}); selectorError("list_services", err) == nil {
+		t.Errorf("expected a malformed label selector to be INVALID_INPUT, got %v", err)
+	}
+}
+
+func TestListArgError(t *testing.T) {
+	unsupported := apierrors.NewBadRequest(`Unable to find "gateway.networking.k8s.io/v1, Resource=httproutes" that match label selector "", field selector "spec.hostnames=x": field label not supported: spec.hostnames`)
+	mcpErr, ok := listArgError("list_httproutes", unsupported).(*types.MCPError)
+	if !ok || mcpErr.Code != types.ErrCodeInvalidInput {
+		t.Errorf("expected INVALID_INPUT for an unsupported field selector, got %v", mcpErr)
+	}
+	if err := listArgError("list_httproutes", apierrors.NewNotFound(servicesGVR.GroupResource(), "x")); err != nil {
+		t.Errorf("unrelated errors should pass through, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/telemetry"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const (
+	// errorRateThreshold is the share of failed calls above which a tool is
+	// reported, once it has been called minErrorRateCalls times.
+	errorRateThreshold = 0.2
+	minErrorRateCalls  = 5
+	// nearTimeoutRatio is how close to its timeout a tool's p95 latency gets
+	// before it is reported.
+	nearTimeoutRatio = 0.8
+)
+
+// --- get_server_stats ---
+
+// GetServerStatsTool reports how the server itself is used: per-tool calls,
+// errors and latency, and the Kubernetes API requests behind them.
+type GetServerStatsTool struct {
+	BaseTool
+	Registry *Registry
+	Stats    *telemetry.Stats
+}
+
+func (t *GetServerStatsTool) Name() string { return "get_server_stats" }
+func (t *GetServerStatsTool) Description() string {
+	return "Report per-tool call counts, error rates and p95 latency since the server started, plus failed and slowest recent Kubernetes API requests, to tune RBAC, caching and tool timeouts"
+}
+func (t *GetServerStatsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of tools to list, most called first (default: 20)",
+			},
+			"slowest": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of slowest recent Kubernetes API requests to list (default: 10)",
+			},
+		},
+	}
+}
+
+func (t *GetServerStatsTool) Run(_ context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	limit := getIntArg(args, "limit", 20)
+	slowest := getIntArg(args, "slowest", 10)
+	if limit < 1 || slowest < 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "limit must be at least 1 and slowest at least 0",
+		}
+	}
+
+	snap := t.Stats.Snapshot(slowest)
+	policy := TimeoutPolicy{
+		Read:      t.Cfg.ToolTimeout,
+		Scan:      t.Cfg.ScanToolTimeout,
+		Probe:     t.Cfg.ProbeToolTimeout,
+		Overrides: t.Cfg.ToolTimeouts,
+	}
+	findings := serverStatsFindings(snap, time.Now(), limit, func(name string) time.Duration {
+		tool, ok := t.Registry.Get(name)
+		if !ok {
+			return 0
+		}
+		d, _ := policy.For(tool)
+		return d
+	})
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
+
+// serverStatsFindings reports snap as of now. timeoutOf returns the timeout
+// of a tool, 0 when it is unknown.
+func serverStatsFindings(snap telemetry.StatsSnapshot, now time.Time, limit int, timeoutOf func(string) time.Duration) []types.DiagnosticFinding {
+	calls, errors := 0, 0
+	for _, ts := range snap.Tools {
+		calls += ts.Calls
+		errors += ts.Errors
+	}
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Summary: fmt.Sprintf("%d tool calls (%d failed) and %d Kubernetes API requests in %s",
+			calls, errors, snap.APICalls, now.Sub(snap.Since).Round(time.Second)),
+		Detail: "Since " + snap.Since.UTC().Format(time.RFC3339) + "; latency percentiles cover the last 1000 calls of each tool.",
+	}}
+
+	for i, ts := range snap.Tools {
+		if i < limit {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryConnectivity,
+				Summary: fmt.Sprintf("%s: %d calls, %d errors (%.0f%%), p95 %s, max %s", ts.Tool, ts.Calls, ts.Errors,
+					100*ts.ErrorRate(), formatSeconds(ts.P95Seconds), formatSeconds(ts.MaxSeconds)),
+				Detail: errorTypesDetail(ts.ErrorTypes),
+			})
+		}
+		if ts.Calls >= minErrorRateCalls && ts.ErrorRate() >= errorRateThreshold {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConnectivity,
+				Code:       types.CodeServerToolErrorRate,
+				Summary:    fmt.Sprintf("%s failed %d of %d calls (%.0f%%)", ts.Tool, ts.Errors, ts.Calls, 100*ts.ErrorRate()),
+				Detail:     errorTypesDetail(ts.ErrorTypes),
+				Suggestion: "FORBIDDEN errors call for check_permissions, TOOL_TIMEOUT for a longer TOOL_TIMEOUTS entry, and INVALID_INPUT for a look at how clients call the tool.",
+			})
+		}
+		if timeout := timeoutOf(ts.Tool); timeout > 0 && ts.P95Seconds >= nearTimeoutRatio*timeout.Seconds() {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityWarning,
+				Category: types.CategoryConnectivity,
+				Code:     types.CodeServerToolNearTimeout,
+				Summary:  fmt.Sprintf("%s p95 latency %s is close to its %s timeout", ts.Tool, formatSeconds(ts.P95Seconds), timeout),
+				Suggestion: fmt.Sprintf("Raise the timeout of this tool, e.g. TOOL_TIMEOUTS=%s=%s, or narrow its calls to a namespace.",
+					ts.Tool, 2*timeout),
+			})
+		}
+	}
+	if len(snap.Tools) > limit {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("%d less used tools not listed", len(snap.Tools)-limit),
+		})
+	}
+
+	findings = append(findings, apiFailureFindings(snap.APIFailures)...)
+
+	if len(snap.SlowestAPICalls) > 0 {
+		lines := make([]string, 0, len(snap.SlowestAPICalls))
+		for _, c := range snap.SlowestAPICalls {
+			line := fmt.Sprintf("%s %s", c.Verb, c.Resource)
+			if c.Namespace != "" {
+				line += " in " + c.Namespace
+			}
+			status := "no response"
+			if c.Status != 0 {
+				status = fmt.Sprint(c.Status)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s (%s) at %s", line, c.Duration.Round(time.Millisecond), status, c.At.UTC().Format(time.RFC3339)))
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Summary:    fmt.Sprintf("Slowest recent Kubernetes API requests: %s", lines[0]),
+			Detail:     strings.Join(lines, "\n"),
+			Suggestion: "Slow list requests of large resources are served from cache for CACHE_TTL; a longer TTL makes repeated calls cheaper.",
+		})
+	}
+	return findings
+}
+
+// apiFailureFindings reports the Kubernetes API requests refused as Forbidden
+// or throttled. Not found and conflict responses are expected and ignored.
+func apiFailureFindings(failures []telemetry.APIFailures) []types.DiagnosticFinding {
+	var forbidden, throttled []string
+	for _, f := range failures {
+		op := f.Verb + " " + f.Resource
+		if n := f.Statuses[http.StatusForbidden]; n > 0 {
+			forbidden = append(forbidden, fmt.Sprintf("%s (%d)", op, n))
+		}
+		if n := f.Statuses[http.StatusTooManyRequests]; n > 0 {
+			throttled = append(throttled, fmt.Sprintf("%s (%d)", op, n))
+		}
+	}
+	var findings []types.DiagnosticFinding
+	if len(forbidden) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeServerAPIForbidden,
+			Summary:    fmt.Sprintf("The API server refused %d operations as Forbidden", len(forbidden)),
+			Detail:     strings.Join(forbidden, ", "),
+			Suggestion: "Run check_permissions for the ClusterRole rules to add.",
+		})
+	}
+	if len(throttled) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeServerAPIThrottled,
+			Summary:    fmt.Sprintf("The API server throttled %d operations", len(throttled)),
+			Detail:     strings.Join(throttled, ", "),
+			Suggestion: "Raise CACHE_TTL so repeated tool calls are served from cache, or check the API Priority and Fairness level of the server's service account.",
+		})
+	}
+	return findings
+}
+
+// errorTypesDetail lists error counts by type, most frequent first.
+func errorTypesDetail(errorTypes map[string]int) string {
+	if len(errorTypes) == 0 {
+		return ""
+	}
+	kinds := make([]string, 0, len(errorTypes))
+	for k := range errorTypes {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if errorTypes[kinds[i]] != errorTypes[kinds[j]] {
+			return errorTypes[kinds[i]] > errorTypes[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	parts := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, errorTypes[k]))
+	}
+	return "Errors: " + strings.Join(parts, ", ")
+}
+
+func formatSeconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/telemetry"
+)
+
+func TestServerStatsFindings(t *testing.T) {
+	stats := telemetry.NewStats()
+	for i := 0; i < 10; i++ {
+		stats.RecordToolCall("list_services", "", 0.1)
+	}
+	for i := 0; i < 5; i++ {
+		errType := ""
+		if i < 2 {
+			errType = "TIMEOUT"
+		}
+		stats.RecordToolCall("scan_cluster", errType, 55)
+	}
+	stats.RecordToolCall("get_service", "INVALID_INPUT", 0.01)
+
+	list := telemetry.APIOperation{Verb: "list", Resource: "pods"}
+	stats.RecordAPICall(telemetry.APICall{APIOperation: list, Status: 200, Duration: 2 * time.Second})
+	stats.RecordAPICall(telemetry.APICall{APIOperation: list, Status: 429, Duration: time.Millisecond})
+	stats.RecordAPICall(telemetry.APICall{APIOperation: telemetry.APIOperation{Verb: "list", Resource: "secrets"}, Namespace: "default", Status: 403})
+	stats.RecordAPICall(telemetry.APICall{APIOperation: telemetry.APIOperation{Verb: "get", Resource: "services"}, Status: 404})
+
+	snap := stats.Snapshot(2)
+	timeouts := map[string]time.Duration{"list_services": 10 * time.Second, "scan_cluster": time.Minute}
+	findings := serverStatsFindings(snap, snap.Since.Add(time.Hour), 2, func(name string) time.Duration { return timeouts[name] })
+	got := ciliumCodes(findings)
+	for _, want := range []string{
+		"info 16 tool calls (3 failed) and 4 Kubernetes API requests in 1h0m0s ",
+		"info list_services: 10 calls, 0 errors (0%), p95 100ms, max 100ms ",
+		"info scan_cluster: 5 calls, 2 errors (40%), p95 55s, max 55s ",
+		"warning scan_cluster failed 2 of 5 calls (40%) SRV001_TOOL_ERROR_RATE",
+		"warning scan_cluster p95 latency 55s is close to its 1m0s timeout SRV002_TOOL_NEAR_TIMEOUT",
+		"info 1 less used tools not listed ",
+		"warning The API server refused 1 operations as Forbidden SRV003_API_FORBIDDEN",
+		"warning The API server throttled 1 operations SRV004_API_THROTTLED",
+		"info Slowest recent Kubernetes API requests: list pods: 2s (200) at ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "get_service") {
+		t.Errorf("tool beyond the limit listed:\n%s", got)
+	}
+	for _, f := range findings {
+		if f.Code == "SRV001_TOOL_ERROR_RATE" && f.Detail != "Errors: TIMEOUT=2" {
+			t.Errorf("error detail = %q", f.Detail)
+		}
+	}
+}
@@ -0,0 +1,695 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Edge sources of build_service_graph.
+const (
+	graphSourceRoutes          = "routes"
+	graphSourceNetworkPolicies = "networkpolicies"
+	graphSourceTelemetry       = "telemetry"
+)
+
+var graphSources = []string{graphSourceRoutes, graphSourceNetworkPolicies, graphSourceTelemetry}
+
+// graphNode is a Service, an entry point (Gateway or Ingress) or, for
+// traffic from pods no Service selects, a Workload.
+type graphNode struct {
+	ID        string `json:"id"` // "Kind namespace/name"
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func newGraphNode(kind, ns, name string) graphNode {
+	return graphNode{ID: fmt.Sprintf("%s %s/%s", kind, ns, name), Kind: kind, Namespace: ns, Name: name}
+}
+
+// graphEdge is a dependency of From on To: From sends, or may send, traffic
+// to To.
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Sources are what the edge was found from: route, networkpolicy, istio
+	// or hubble.
+	Sources []string `json:"sources"`
+	// Via lists the resources the edge was read from, e.g. "HTTPRoute shop/web".
+	Via []string `json:"via,omitempty"`
+	// Rate is the observed requests (Istio) or flows (Hubble) per second.
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// graphImpact answers what breaks when a Service goes down.
+type graphImpact struct {
+	Service string `json:"service"`
+	// DirectDependents send traffic to the Service; TransitiveDependents
+	// reach it through other nodes.
+	DirectDependents     []string `json:"direct_dependents"`
+	TransitiveDependents []string `json:"transitive_dependents"`
+	// EntryPoints are the Gateways and Ingresses among its dependents.
+	EntryPoints  []string `json:"entry_points"`
+	Dependencies []string `json:"dependencies"`
+}
+
+// serviceGraph is the structured result of build_service_graph.
+type serviceGraph struct {
+	Nodes  []graphNode  `json:"nodes"`
+	Edges  []graphEdge  `json:"edges"`
+	Impact *graphImpact `json:"impact,omitempty"`
+	Notes  []string     `json:"notes,omitempty"`
+}
+
+// graphBuilder collects nodes and edges, merging the edges found between the
+// same two nodes.
+type graphBuilder struct {
+	nodes map[string]graphNode
+	edges map[[2]string]*graphEdge
+	// services are the existing Services, "namespace/name"; routes to other
+	// hosts are left out.
+	services map[string]bool
+}
+
+func newGraphBuilder(services []unstructured.Unstructured) *graphBuilder {
+	g := &graphBuilder{nodes: make(map[string]graphNode), edges: make(map[[2]string]*graphEdge), services: make(map[string]bool)}
+	for i := range services {
+		g.services[services[i].GetNamespace()+"/"+services[i].GetName()] = true
+	}
+	return g
+}
+
+func (g *graphBuilder) addEdge(from, to graphNode, source, via string, rate float64) {
+	if from.ID == to.ID {
+		return
+	}
+	g.nodes[from.ID], g.nodes[to.ID] = from, to
+	key := [2]string{from.ID, to.ID}
+	e, ok := g.edges[key]
+	if !ok {
+		e = &graphEdge{From: from.ID, To: to.ID}
+		g.edges[key] = e
+	}
+	if !containsString(e.Sources, source) {
+		e.Sources = append(e.Sources, source)
+	}
+	if via != "" && !containsString(e.Via, via) {
+		e.Via = append(e.Via, via)
+	}
+	e.Rate += rate
+}
+
+// serviceNode returns the node of an existing Service, "namespace/name".
+func (g *graphBuilder) serviceNode(key string) (graphNode, bool) {
+	ns, name, _ := strings.Cut(key, "/")
+	return newGraphNode("Service", ns, name), g.services[key]
+}
+
+// graph returns the nodes and edges sorted by ID.
+func (g *graphBuilder) graph() *serviceGraph {
+	out := &serviceGraph{Nodes: make([]graphNode, 0, len(g.nodes)), Edges: make([]graphEdge, 0, len(g.edges))}
+	for _, n := range g.nodes {
+		out.Nodes = append(out.Nodes, n)
+	}
+	sort.Slice(out.Nodes, func(i, j int) bool { return out.Nodes[i].ID < out.Nodes[j].ID })
+	for _, e := range g.edges {
+		sort.Strings(e.Sources)
+		sort.Strings(e.Via)
+		out.Edges = append(out.Edges, *e)
+	}
+	sort.Slice(out.Edges, func(i, j int) bool {
+		if out.Edges[i].From != out.Edges[j].From {
+			return out.Edges[i].From < out.Edges[j].From
+		}
+		return out.Edges[i].To < out.Edges[j].To
+	})
+	return out
+}
+
+// addRouteEdges adds the edges of Gateway API routes, from their parent
+// Gateways, or parent Services for mesh (GAMMA) routes, to their backends.
+func (g *graphBuilder) addRouteEdges(routes []routeInfo) {
+	for _, r := range routes {
+		via := fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name)
+		parentRefs, _, _ := unstructured.NestedSlice(r.obj, "spec", "parentRefs")
+		for _, pr := range parentRefs {
+			prm, ok := pr.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			kind, _ := prm["kind"].(string)
+			name, _ := prm["name"].(string)
+			ns, _ := prm["namespace"].(string)
+			parent := newGraphNode(orDefault(kind, "Gateway"), orDefault(ns, r.namespace), name)
+			if parent.Kind == "Service" && !g.services[parent.Namespace+"/"+name] {
+				continue
+			}
+			if parent.Kind != "Gateway" && parent.Kind != "Service" {
+				continue
+			}
+			for _, b := range routeBackends(r) {
+				if to, ok := g.serviceNode(b); ok {
+					g.addEdge(parent, to, "route", via, 0)
+				}
+			}
+		}
+	}
+}
+
+// addIngressEdges adds the edges of Ingresses to their backends.
+func (g *graphBuilder) addIngressEdges(ingresses []unstructured.Unstructured) {
+	for i := range ingresses {
+		ing := &ingresses[i]
+		from := newGraphNode("Ingress", ing.GetNamespace(), ing.GetName())
+		for _, b := range ingressBackends(ing) {
+			if to, ok := g.serviceNode(b); ok {
+				g.addEdge(from, to, "route", "", 0)
+			}
+		}
+	}
+}
+
+// istioServiceKey resolves a VirtualService host to an existing Service,
+// "namespace/name".
+func (g *graphBuilder) istioServiceKey(host, defaultNs string) (string, bool) {
+	if strings.Contains(host, "*") {
+		return "", false
+	}
+	ns, name := resolveIstioHost(strings.TrimSuffix(host, ".svc"), defaultNs)
+	key := ns + "/" + name
+	return key, g.services[key]
+}
+
+// addVirtualServiceEdges adds the edges of Istio VirtualServices: from the
+// Gateways they are bound to, and for the mesh gateway from the Services of
+// their hosts, to the Services of their destinations.
+func (g *graphBuilder) addVirtualServiceEdges(vss []unstructured.Unstructured) {
+	for i := range vss {
+		vs := &vss[i]
+		ns := vs.GetNamespace()
+		via := fmt.Sprintf("VirtualService %s/%s", ns, vs.GetName())
+
+		var dests []string
+		for _, section := range []string{"http", "tcp", "tls"} {
+			routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", section)
+			for _, r := range routes {
+				rm, _ := r.(map[string]interface{})
+				targets, _, _ := unstructured.NestedSlice(rm, "route")
+				if mirror, ok, _ := unstructured.NestedMap(rm, "mirror"); ok {
+					targets = append(targets, map[string]interface{}{"destination": mirror})
+				}
+				for _, d := range targets {
+					dm, _ := d.(map[string]interface{})
+					host, _, _ := unstructured.NestedString(dm, "destination", "host")
+					if key, ok := g.istioServiceKey(host, ns); ok && !containsString(dests, key) {
+						dests = append(dests, key)
+					}
+				}
+			}
+		}
+
+		var from []graphNode
+		gateways, found, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+		if !found {
+			gateways = []string{"mesh"}
+		}
+		for _, gw := range gateways {
+			if gw != "mesh" {
+				gwNs, gwName, ok := strings.Cut(gw, "/")
+				if !ok {
+					gwNs, gwName = ns, gw
+				}
+				from = append(from, newGraphNode("Gateway", gwNs, gwName))
+				continue
+			}
+			hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+			for _, h := range hosts {
+				if key, ok := g.istioServiceKey(h, ns); ok {
+					node, _ := g.serviceNode(key)
+					from = append(from, node)
+				}
+			}
+		}
+		for _, f := range from {
+			for _, d := range dests {
+				to, _ := g.serviceNode(d)
+				g.addEdge(f, to, "route", via, 0)
+			}
+		}
+	}
+}
+
+// graphPod is a pod with the graph nodes that stand for it: the Services
+// selecting it, or its Workload.
+type graphPod struct {
+	podPorts
+	nodes          []graphNode
+	serviceAccount string
+	proxy          bool // runs a mesh sidecar
+}
+
+// graphPods returns the running, non-host-network pods with their nodes.
+func graphPods(pods, services []unstructured.Unstructured) []graphPod {
+	type selector struct {
+		ns   string
+		sel  labels.Selector
+		node graphNode
+	}
+	var selectors []selector
+	for i := range services {
+		s := &services[i]
+		if sel, _, _ := unstructured.NestedStringMap(s.Object, "spec", "selector"); len(sel) > 0 {
+			selectors = append(selectors, selector{s.GetNamespace(), labels.SelectorFromSet(sel), newGraphNode("Service", s.GetNamespace(), s.GetName())})
+		}
+	}
+	var out []graphPod
+	for i := range pods {
+		pod := &pods[i]
+		phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+		hostNetwork, _, _ := unstructured.NestedBool(pod.Object, "spec", "hostNetwork")
+		if phase == "Succeeded" || phase == "Failed" || hostNetwork {
+			continue
+		}
+		gp := graphPod{podPorts: podPortsFrom(pod), proxy: podHasProxy(pod)}
+		gp.serviceAccount, _, _ = unstructured.NestedString(pod.Object, "spec", "serviceAccountName")
+		for _, s := range selectors {
+			if s.ns == pod.GetNamespace() && s.sel.Matches(labels.Set(pod.GetLabels())) {
+				gp.nodes = append(gp.nodes, s.node)
+			}
+		}
+		if len(gp.nodes) == 0 {
+			gp.nodes = []graphNode{newGraphNode("Workload", pod.GetNamespace(), workloadName(*pod))}
+		}
+		out = append(out, gp)
+	}
+	return out
+}
+
+// addNetworkPolicyEdges adds the connections NetworkPolicies allow: from
+// the pods an ingress rule's "from" peers select to the pods of the policy,
+// and from the pods of the policy to those an egress rule's "to" peers
+// select. Rules without peers allow everything and say nothing about
+// dependencies; they are counted in the returned number and left out.
+func (g *graphBuilder) addNetworkPolicyEdges(policies []unstructured.Unstructured, pods []graphPod, nsLabels map[string]map[string]string) int {
+	openRules := 0
+	for i := range policies {
+		np := &policies[i]
+		via := fmt.Sprintf("NetworkPolicy %s/%s", np.GetNamespace(), np.GetName())
+		selObj, ok, _ := unstructured.NestedMap(np.Object, "spec", "podSelector")
+		if !ok {
+			selObj = map[string]interface{}{}
+		}
+		sel, err := parseLabelSelector(selObj, true)
+		if err != nil {
+			continue
+		}
+		subjectNodes := make(map[string]graphNode)
+		for _, p := range pods {
+			if p.Namespace == np.GetNamespace() && sel.Matches(labels.Set(p.Labels)) {
+				for _, n := range p.nodes {
+					subjectNodes[n.ID] = n
+				}
+			}
+		}
+		if len(subjectNodes) == 0 {
+			continue
+		}
+
+		for _, direction := range []string{"Ingress", "Egress"} {
+			if !policyHasType(*np, direction) {
+				continue
+			}
+			field, peerField := "ingress", "from"
+			if direction == "Egress" {
+				field, peerField = "egress", "to"
+			}
+			rules, _, _ := unstructured.NestedSlice(np.Object, "spec", field)
+			for _, r := range rules {
+				rm, _ := r.(map[string]interface{})
+				peers, _, _ := unstructured.NestedSlice(rm, peerField)
+				if len(peers) == 0 {
+					openRules++
+					continue
+				}
+				peerNodes := make(map[string]graphNode)
+				for _, peer := range peers {
+					pm, _ := peer.(map[string]interface{})
+					if _, ok := pm["ipBlock"]; ok {
+						continue
+					}
+					for _, p := range pods {
+						if match, err := peerMatches(pm, np.GetNamespace(), p.podPorts, nsLabels); err == nil && match {
+							for _, n := range p.nodes {
+								peerNodes[n.ID] = n
+							}
+						}
+					}
+				}
+				for _, peer := range peerNodes {
+					for _, subject := range subjectNodes {
+						from, to := peer, subject
+						if direction == "Egress" {
+							from, to = subject, peer
+						}
+						if to.Kind == "Service" {
+							g.addEdge(from, to, "networkpolicy", via, 0)
+						}
+					}
+				}
+			}
+		}
+	}
+	return openRules
+}
+
+// workloadNodes maps "namespace/workload" to the nodes of its pods, as
+// telemetry names workloads.
+func workloadNodes(pods []unstructured.Unstructured, gpods []graphPod) map[string][]graphNode {
+	byPod := make(map[string][]graphNode, len(gpods))
+	for _, p := range gpods {
+		byPod[p.Namespace+"/"+p.Name] = p.nodes
+	}
+	out := make(map[string][]graphNode)
+	for i := range pods {
+		pod := &pods[i]
+		key := pod.GetNamespace() + "/" + workloadName(*pod)
+		for _, n := range byPod[pod.GetNamespace()+"/"+pod.GetName()] {
+			if !containsNode(out[key], n) {
+				out[key] = append(out[key], n)
+			}
+		}
+	}
+	return out
+}
+
+func containsNode(nodes []graphNode, n graphNode) bool {
+	for _, m := range nodes {
+		if m.ID == n.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// telemetryNodes returns the nodes of a workload seen in telemetry, or a
+// Workload node when no running pod is known for it.
+func telemetryNodes(workloads map[string][]graphNode, ns, workload string) []graphNode {
+	if ns == "" || workload == "" || workload == "unknown" {
+		return nil
+	}
+	if nodes := workloads[ns+"/"+workload]; len(nodes) > 0 {
+		return nodes
+	}
+	return []graphNode{newGraphNode("Workload", ns, workload)}
+}
+
+// addTelemetryEdges adds the traffic observed by Istio (istio_requests_total
+// and istio_tcp_connections_opened_total) and Hubble
+// (hubble_flows_processed_total with workload labels) over window. It
+// returns which of them had data.
+func (g *graphBuilder) addTelemetryEdges(ctx context.Context, p *prometheus.Client, window string, workloads map[string][]graphNode) ([]string, error) {
+	var found []string
+	istio := func(metric string) error {
+		samples, err := p.Query(ctx, fmt.Sprintf(
+			`sum by (source_workload_namespace, source_workload, destination_service_namespace, destination_service_name) (rate(%s%s[%s])) > 0`,
+			metric, p.Selector(`reporter="source"`), window))
+		if err != nil {
+			return err
+		}
+		for _, s := range samples {
+			to, ok := g.serviceNode(s.Labels["destination_service_namespace"] + "/" + s.Labels["destination_service_name"])
+			if !ok {
+				continue
+			}
+			for _, from := range telemetryNodes(workloads, s.Labels["source_workload_namespace"], s.Labels["source_workload"]) {
+				g.addEdge(from, to, "istio", "", s.Value)
+			}
+		}
+		if len(samples) > 0 && !containsString(found, "istio") {
+			found = append(found, "istio")
+		}
+		return nil
+	}
+	if err := istio("istio_requests_total"); err != nil {
+		return nil, err
+	}
+	if err := istio("istio_tcp_connections_opened_total"); err != nil {
+		return nil, err
+	}
+
+	samples, err := p.Query(ctx, fmt.Sprintf(
+		`sum by (source_namespace, source_workload, destination_namespace, destination_workload) (rate(hubble_flows_processed_total%s[%s])) > 0`,
+		p.Selector(`verdict="FORWARDED"`, `destination_workload!=""`), window))
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range samples {
+		for _, to := range telemetryNodes(workloads, s.Labels["destination_namespace"], s.Labels["destination_workload"]) {
+			if to.Kind != "Service" {
+				continue
+			}
+			for _, from := range telemetryNodes(workloads, s.Labels["source_namespace"], s.Labels["source_workload"]) {
+				g.addEdge(from, to, "hubble", "", s.Value)
+			}
+		}
+	}
+	if len(samples) > 0 {
+		found = append(found, "hubble")
+	}
+	return found, nil
+}
+
+// scope keeps the edges with an end in namespace ns.
+func (sg *serviceGraph) scope(ns string) {
+	keep := make(map[string]bool)
+	var edges []graphEdge
+	for _, e := range sg.Edges {
+		from, to := sg.node(e.From), sg.node(e.To)
+		if from.Namespace == ns || to.Namespace == ns {
+			edges = append(edges, e)
+			keep[e.From], keep[e.To] = true, true
+		}
+	}
+	sg.Edges = edges
+	sg.keepNodes(keep)
+}
+
+func (sg *serviceGraph) node(id string) graphNode {
+	i := sort.Search(len(sg.Nodes), func(i int) bool { return sg.Nodes[i].ID >= id })
+	if i < len(sg.Nodes) && sg.Nodes[i].ID == id {
+		return sg.Nodes[i]
+	}
+	return graphNode{}
+}
+
+func (sg *serviceGraph) keepNodes(keep map[string]bool) {
+	nodes := sg.Nodes[:0]
+	for _, n := range sg.Nodes {
+		if keep[n.ID] {
+			nodes = append(nodes, n)
+		}
+	}
+	sg.Nodes = nodes
+}
+
+// impact computes what depends on the node id, and reduces the graph to
+// the edges between it, its dependents and its direct dependencies.
+func (sg *serviceGraph) impact(id string) {
+	callers := make(map[string][]string)
+	imp := &graphImpact{Service: id, DirectDependents: []string{}, TransitiveDependents: []string{}, EntryPoints: []string{}, Dependencies: []string{}}
+	for _, e := range sg.Edges {
+		callers[e.To] = append(callers[e.To], e.From)
+		if e.From == id {
+			imp.Dependencies = append(imp.Dependencies, e.To)
+		}
+	}
+
+	// Walk the dependents breadth first.
+	depth := map[string]int{id: 0}
+	queue := []string{id}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, c := range callers[n] {
+			if _, seen := depth[c]; seen {
+				continue
+			}
+			depth[c] = depth[n] + 1
+			queue = append(queue, c)
+			if depth[c] == 1 {
+				imp.DirectDependents = append(imp.DirectDependents, c)
+			} else {
+				imp.TransitiveDependents = append(imp.TransitiveDependents, c)
+			}
+			if k := sg.node(c).Kind; k == "Gateway" || k == "Ingress" {
+				imp.EntryPoints = append(imp.EntryPoints, c)
+			}
+		}
+	}
+	for _, l := range [][]string{imp.DirectDependents, imp.TransitiveDependents, imp.EntryPoints, imp.Dependencies} {
+		sort.Strings(l)
+	}
+
+	keep := map[string]bool{id: true}
+	for n := range depth {
+		keep[n] = true
+	}
+	var edges []graphEdge
+	for _, e := range sg.Edges {
+		_, fromDependent := depth[e.From]
+		_, toDependent := depth[e.To]
+		if fromDependent && toDependent || e.From == id {
+			edges = append(edges, e)
+			keep[e.To] = true
+		}
+	}
+	sg.Edges = edges
+	sg.keepNodes(keep)
+	sg.Impact = imp
+}
+
+// --- build_service_graph ---
+
+type BuildServiceGraphTool struct {
+	BaseTool
+	// Prometheus, when set, supplies the traffic observed by Istio and Hubble.
+	Prometheus *prometheus.Client
+}
+
+func (t *BuildServiceGraphTool) Name() string { return "build_service_graph" }
+func (t *BuildServiceGraphTool) Description() string {
+	return "Build the directed graph of service-to-service dependencies from Gateway API routes, Istio VirtualServices and Ingresses, the connections NetworkPolicies allow and, when Prometheus is configured, the traffic Istio and Hubble observed; returns nodes and edges, and with service, what depends on it (what breaks if it goes down)"
+}
+func (t *BuildServiceGraphTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only keep edges from or to this namespace (default: all namespaces); with service, the namespace of the service",
+			},
+			"service": map[string]interface{}{
+				"type":        "string",
+				"description": "Service to compute the impact of, as name (with namespace) or namespace/name: returns its direct and transitive dependents, the entry points reaching it and its dependencies",
+			},
+			"sources": map[string]interface{}{
+				"type":        "string",
+				"description": "Comma-separated list of where to read edges from: routes, networkpolicies, telemetry (default: all; telemetry needs PROMETHEUS_URL)",
+			},
+			"window": map[string]interface{}{
+				"type":        "string",
+				"description": "Telemetry window, e.g. 1h or 24h (default 1h)",
+			},
+		},
+	}
+}
+
+func (t *BuildServiceGraphTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	service := getStringArg(args, "service", "")
+	window, err := promWindow(getStringArg(args, "window", "1h"))
+	if err != nil {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: err.Error()}
+	}
+	sources := graphSources
+	if arg := getStringArg(args, "sources", ""); arg != "" {
+		sources = strings.Split(strings.ReplaceAll(arg, " ", ""), ",")
+	}
+	for _, s := range sources {
+		if !containsString(graphSources, s) {
+			return nil, &types.MCPError{
+				Code:    types.ErrCodeInvalidInput,
+				Tool:    t.Name(),
+				Message: fmt.Sprintf("invalid source %q: expected %s", s, strings.Join(graphSources, ", ")),
+			}
+		}
+	}
+	var focus graphNode
+	if service != "" {
+		svcNs, svcName, ok := strings.Cut(service, "/")
+		if !ok {
+			svcNs, svcName = ns, service
+		}
+		if svcNs == "" {
+			return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "service needs a namespace: pass namespace or namespace/name"}
+		}
+		focus = newGraphNode("Service", svcNs, svcName)
+	}
+
+	svcList, err := t.listResource(ctx, servicesGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	g := newGraphBuilder(svcList.Items)
+	var notes []string
+
+	if containsString(sources, graphSourceRoutes) {
+		g.addRouteEdges(t.listRoutes(ctx))
+		if list, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, ""); err == nil {
+			g.addVirtualServiceEdges(list.Items)
+		}
+		if list, err := t.listResource(ctx, ingressGVR, ""); err == nil {
+			g.addIngressEdges(list.Items)
+		}
+	}
+
+	var pods []unstructured.Unstructured
+	var gpods []graphPod
+	if containsString(sources, graphSourceNetworkPolicies) || containsString(sources, graphSourceTelemetry) {
+		podList, err := t.listResource(ctx, podsGVR, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = podList.Items
+		gpods = graphPods(pods, svcList.Items)
+	}
+
+	if containsString(sources, graphSourceNetworkPolicies) {
+		nsLabels := make(map[string]map[string]string)
+		if nsList, err := t.listResource(ctx, namespacesGVR, ""); err == nil {
+			for _, item := range nsList.Items {
+				nsLabels[item.GetName()] = item.GetLabels()
+			}
+		}
+		if list, err := t.listResource(ctx, networkPoliciesGVR, ""); err == nil {
+			if open := g.addNetworkPolicyEdges(list.Items, gpods, nsLabels); open > 0 {
+				notes = append(notes, fmt.Sprintf("%d NetworkPolicy rules allow any peer and add no edges", open))
+			}
+		} else {
+			notes = append(notes, fmt.Sprintf("NetworkPolicies not read: %v", err))
+		}
+	}
+
+	if containsString(sources, graphSourceTelemetry) {
+		if t.Prometheus == nil {
+			notes = append(notes, "Telemetry not read: PROMETHEUS_URL is not set")
+		} else if found, err := g.addTelemetryEdges(ctx, t.Prometheus, window, workloadNodes(pods, gpods)); err != nil {
+			notes = append(notes, fmt.Sprintf("Telemetry not read: the Prometheus query failed: %v", err))
+		} else if len(found) == 0 {
+			notes = append(notes, "No Istio or Hubble traffic metrics in Prometheus for the window")
+		}
+	}
+
+	if focus.ID != "" {
+		if !g.services[focus.Namespace+"/"+focus.Name] {
+			return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("service %s/%s not found", focus.Namespace, focus.Name)}
+		}
+		g.nodes[focus.ID] = focus
+	}
+	graph := g.graph()
+	if focus.ID != "" {
+		graph.impact(focus.ID)
+	} else if ns != "" {
+		graph.scope(ns)
+	}
+	graph.Notes = notes
+	return NewResponse(t.Cfg, t.Name(), graph), nil
+}
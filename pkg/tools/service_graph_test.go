@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func graphService(ns, name string, selector map[string]interface{}) unstructured.Unstructured {
+	return *ipamObj("v1", "Service", ns, name, map[string]interface{}{"spec": map[string]interface{}{"selector": selector}})
+}
+
+func graphEdges(sg *serviceGraph) map[string][]string {
+	out := make(map[string][]string)
+	for _, e := range sg.Edges {
+		out[e.From+" -> "+e.To] = e.Sources
+	}
+	return out
+}
+
+func TestBuildServiceGraph(t *testing.T) {
+	services := []unstructured.Unstructured{
+		graphService("shop", "web", map[string]interface{}{"app": "web"}),
+		graphService("shop", "cart", map[string]interface{}{"app": "cart"}),
+		graphService("shop", "db", map[string]interface{}{"app": "db"}),
+	}
+	g := newGraphBuilder(services)
+
+	g.addRouteEdges([]routeInfo{{kind: "HTTPRoute", namespace: "shop", name: "web", obj: map[string]interface{}{"spec": map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra"}},
+		"rules":      []interface{}{map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "web"}, map[string]interface{}{"name": "missing"}}}},
+	}}}})
+	g.addVirtualServiceEdges([]unstructured.Unstructured{*weightedVS("shop", "cart", vsDest("cart.shop.svc.cluster.local", "", 100), vsDest("www.example.com", "", 0))})
+	// The VirtualService has no hosts: give it the web Service's, so that
+	// mesh traffic to web is routed to cart.
+	vs := weightedVS("shop", "web-to-cart", vsDest("cart", "", 100))
+	_ = unstructured.SetNestedStringSlice(vs.Object, []string{"web"}, "spec", "hosts")
+	g.addVirtualServiceEdges([]unstructured.Unstructured{*vs})
+
+	pods := []unstructured.Unstructured{
+		*tenantPod("shop", "web-1", "10.0.0.1", map[string]string{"app": "web"}, 8080),
+		*tenantPod("shop", "cart-1", "10.0.0.2", map[string]string{"app": "cart"}, 8080),
+		*tenantPod("shop", "db-1", "10.0.0.3", map[string]string{"app": "db"}, 5432),
+		*tenantPod("shop", "job-1", "10.0.0.4", map[string]string{"app": "job"}, 8080),
+	}
+	gpods := graphPods(pods, services)
+	policies := []unstructured.Unstructured{
+		*ipamObj("networking.k8s.io/v1", "NetworkPolicy", "shop", "db", map[string]interface{}{"spec": map[string]interface{}{
+			"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "db"}},
+			"ingress": []interface{}{
+				map[string]interface{}{"from": []interface{}{map[string]interface{}{"podSelector": map[string]interface{}{"matchExpressions": []interface{}{
+					map[string]interface{}{"key": "app", "operator": "In", "values": []interface{}{"cart", "job"}},
+				}}}}},
+				map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": int64(9187)}}},
+			},
+		}}),
+	}
+	if open := g.addNetworkPolicyEdges(policies, gpods, nil); open != 1 {
+		t.Errorf("open rules = %d, want 1", open)
+	}
+
+	sg := g.graph()
+	want := map[string][]string{
+		"Gateway infra/public -> Service shop/web": {"route"},
+		"Service shop/web -> Service shop/cart":    {"route"},
+		"Service shop/cart -> Service shop/db":     {"networkpolicy"},
+		"Workload shop/job-1 -> Service shop/db":   {"networkpolicy"},
+	}
+	if got := graphEdges(sg); !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+
+	sg.impact("Service shop/db")
+	imp := sg.Impact
+	if !reflect.DeepEqual(imp.DirectDependents, []string{"Service shop/cart", "Workload shop/job-1"}) ||
+		!reflect.DeepEqual(imp.TransitiveDependents, []string{"Gateway infra/public", "Service shop/web"}) ||
+		!reflect.DeepEqual(imp.EntryPoints, []string{"Gateway infra/public"}) || len(imp.Dependencies) != 0 {
+		t.Errorf("impact = %+v", imp)
+	}
+	if len(sg.Edges) != 4 || len(sg.Nodes) != 5 {
+		t.Errorf("impact graph = %d edges, %d nodes", len(sg.Edges), len(sg.Nodes))
+	}
+}
+
+func TestServiceGraphScope(t *testing.T) {
+	services := []unstructured.Unstructured{graphService("a", "x", nil), graphService("b", "y", nil), graphService("c", "z", nil)}
+	g := newGraphBuilder(services)
+	x, _ := g.serviceNode("a/x")
+	y, _ := g.serviceNode("b/y")
+	z, _ := g.serviceNode("c/z")
+	g.addEdge(x, y, "istio", "", 2)
+	g.addEdge(x, y, "hubble", "", 1)
+	g.addEdge(y, z, "istio", "", 1)
+
+	sg := g.graph()
+	if e := sg.Edges[0]; !reflect.DeepEqual(e.Sources, []string{"hubble", "istio"}) || e.Rate != 3 {
+		t.Errorf("merged edge = %+v", e)
+	}
+	sg.scope("a")
+	if len(sg.Edges) != 1 || len(sg.Nodes) != 2 {
+		t.Errorf("scoped graph = %+v", sg)
+	}
+}
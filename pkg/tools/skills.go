@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/isitobservable/k8s-networking-mcp/pkg/auth"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/skills"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
@@ -34,6 +35,7 @@ func (t *ListSkillsTool) Run(ctx context.Context, args map[string]interface{}) (
 		Description  string              `json:"description"`
 		RequiredCRDs []string            `json:"requiredCRDs,omitempty"`
 		Parameters   []skills.SkillParam `json:"parameters"`
+		Source       string              `json:"source,omitempty"`
 	}
 
 	items := make([]skillInfo, 0, len(defs))
@@ -43,6 +45,7 @@ func (t *ListSkillsTool) Run(ctx context.Context, args map[string]interface{}) (
 			Description:  d.Description,
 			RequiredCRDs: d.RequiredCRDs,
 			Parameters:   d.Parameters,
+			Source:       d.Source,
 		})
 	}
 
@@ -127,3 +130,127 @@ func (t *RunSkillTool) Run(ctx context.Context, args map[string]interface{}) (*S
 
 	return NewResponse(t.Cfg, "run_skill", result), nil
 }
+
+// maxSkillStepOutput bounds the raw output a custom skill step keeps from a
+// tool that does not report findings.
+const maxSkillStepOutput = 4000
+
+// SkillToolRunner runs the tools of a registry for custom skill steps,
+// applying the caller's tool allowlist and the namespace scope, for steps of
+// run_skill calls and scheduled runs alike.
+type SkillToolRunner struct {
+	Registry *Registry
+}
+
+func (r SkillToolRunner) RunTool(ctx context.Context, name string, args map[string]interface{}) ([]types.DiagnosticFinding, string, error) {
+	if !auth.ToolAccessFromContext(ctx).Allows(name) {
+		return nil, "", fmt.Errorf("%w: %s", auth.ErrToolNotAllowed, name)
+	}
+	tool, ok := r.Registry.Get(name)
+	if !ok {
+		return nil, "", fmt.Errorf("tool %q is not available", name)
+	}
+	ctx, err := ApplyNamespaceScope(ctx, tool, args)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := tool.Run(ctx, args)
+	if err != nil {
+		return nil, "", err
+	}
+	if result, ok := resp.Data.(*types.ToolResult); ok {
+		return result.Findings, "", nil
+	}
+	out, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(out) > maxSkillStepOutput {
+		out = append(out[:maxSkillStepOutput], "..."...)
+	}
+	return nil, string(out), nil
+}
+
+// --- list_scheduled_skills ---
+
+// ListScheduledSkillsTool lists the skills run on a schedule.
+type ListScheduledSkillsTool struct {
+	BaseTool
+	Scheduler *skills.Scheduler
+}
+
+func (t *ListScheduledSkillsTool) Name() string { return "list_scheduled_skills" }
+func (t *ListScheduledSkillsTool) Description() string {
+	return "List the skills run in the background on a cron schedule, with their next run and the status of their last run"
+}
+func (t *ListScheduledSkillsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *ListScheduledSkillsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	schedules := t.Scheduler.Schedules()
+	return NewResponse(t.Cfg, "list_scheduled_skills", map[string]interface{}{
+		"schedules": schedules,
+		"count":     len(schedules),
+	}), nil
+}
+
+// --- get_last_run_results ---
+
+// GetLastRunResultsTool returns the latest results of scheduled skills.
+type GetLastRunResultsTool struct {
+	BaseTool
+	Scheduler *skills.Scheduler
+}
+
+func (t *GetLastRunResultsTool) Name() string { return "get_last_run_results" }
+func (t *GetLastRunResultsTool) Description() string {
+	return "Get the latest results of scheduled skills: per-step findings, status and whether findings were sent to the alerting webhook"
+}
+func (t *GetLastRunResultsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"schedule": map[string]interface{}{
+				"type":        "string",
+				"description": "Schedule name (from list_scheduled_skills); all schedules if omitted",
+			},
+		},
+	}
+}
+
+func (t *GetLastRunResultsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "schedule", "")
+	if name == "" {
+		runs := t.Scheduler.LastRuns()
+		return NewResponse(t.Cfg, "get_last_run_results", map[string]interface{}{
+			"runs":  runs,
+			"count": len(runs),
+		}), nil
+	}
+
+	known := false
+	for _, s := range t.Scheduler.Schedules() {
+		if s.Name == name {
+			known = true
+		}
+	}
+	if !known {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("schedule %q not found", name),
+		}
+	}
+	run, ok := t.Scheduler.LastRun(name)
+	if !ok {
+		return NewResponse(t.Cfg, "get_last_run_results", map[string]interface{}{
+			"schedule": name,
+			"message":  "the schedule has not run yet",
+		}), nil
+	}
+	return NewResponse(t.Cfg, "get_last_run_results", run), nil
+}
@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+// ClusterSnapshot shares unfiltered List results between tool invocations for
+// a short window (CACHE_TTL). Scans that run back-to-back, such as the steps
+// of a skill or an agent fanning out several diagnose calls, hit the API server
+// once per resource type instead of once per tool.
+type ClusterSnapshot struct {
+	client dynamic.Interface
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[snapshotKey]*snapshotEntry
+}
+
+type snapshotKey struct {
+	gvr schema.GroupVersionResource
+	ns  string
+	// as is the impersonated identity and scope the namespace scope of the
+	// call; callers only share lists they are allowed to see.
+	as    string
+	scope string
+}
+
+// snapshotEntry is one in-flight or completed List. ready is closed once
+// list/err are populated so concurrent callers wait on a single request.
+type snapshotEntry struct {
+	ready     chan struct{}
+	list      *unstructured.UnstructuredList
+	err       error
+	fetchedAt time.Time
+}
+
+// NewClusterSnapshot creates a snapshot backed by client. A zero ttl disables
+// sharing and every List goes straight to the API server.
+func NewClusterSnapshot(client dynamic.Interface, ttl time.Duration) *ClusterSnapshot {
+	return &ClusterSnapshot{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[snapshotKey]*snapshotEntry),
+	}
+}
+
+// List returns all gvr objects in ns (all namespaces when empty). A fresh
+// cluster-wide entry also satisfies namespaced requests. Only objects in the
+// namespace scope of ctx are returned. The returned list is a copy and may be
+// modified by the caller.
+func (s *ClusterSnapshot) List(ctx context.Context, gvr schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+	if s.ttl <= 0 {
+		return listDirect(ctx, s.client, gvr, ns)
+	}
+
+	scope := k8s.NamespaceScopeFrom(ctx)
+	var as, scopeKey string
+	if imp, ok := k8s.ImpersonationFrom(ctx); ok {
+		as = imp.Key()
+	}
+	if scope != nil {
+		scopeKey = scope.String()
+	}
+
+	s.mu.Lock()
+	if ns != "" {
+		if all, ok := s.entries[snapshotKey{gvr: gvr, as: as, scope: scopeKey}]; ok && s.completedFresh(all) && all.err == nil {
+			s.mu.Unlock()
+			return filterScope(filterNamespace(all.list, ns), scope), nil
+		}
+	}
+	key := snapshotKey{gvr: gvr, ns: ns, as: as, scope: scopeKey}
+	entry, ok := s.entries[key]
+	if ok && !s.completedFresh(entry) && isDone(entry) {
+		ok = false
+	}
+	if !ok {
+		entry = &snapshotEntry{ready: make(chan struct{})}
+		s.entries[key] = entry
+		s.mu.Unlock()
+		s.fetch(ctx, key, entry)
+	} else {
+		s.mu.Unlock()
+	}
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		// The caller that started the fetch gave up; our own context is
+		// still live, so list directly rather than inherit its error.
+		if ctx.Err() == nil && (errors.Is(entry.err, context.Canceled) || errors.Is(entry.err, context.DeadlineExceeded)) {
+			return listDirect(ctx, s.client, gvr, ns)
+		}
+		return nil, entry.err
+	}
+	return filterScope(entry.list.DeepCopy(), scope), nil
+}
+
+// Reset drops every cached list, e.g. after CRDs are installed or removed.
+func (s *ClusterSnapshot) Reset() {
+	s.mu.Lock()
+	s.entries = make(map[snapshotKey]*snapshotEntry)
+	s.mu.Unlock()
+}
+
+func (s *ClusterSnapshot) fetch(ctx context.Context, key snapshotKey, entry *snapshotEntry) {
+	list, err := listDirect(ctx, s.client, key.gvr, key.ns)
+
+	s.mu.Lock()
+	entry.list, entry.err, entry.fetchedAt = list, err, time.Now()
+	// Only remember NotFound errors (missing CRD or API version); anything
+	// else, including the caller's context ending, is retried next time.
+	if err != nil && !apierrors.IsNotFound(err) && s.entries[key] == entry {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	close(entry.ready)
+}
+
+// completedFresh reports whether entry has finished and is within the TTL.
+// Callers must hold s.mu.
+func (s *ClusterSnapshot) completedFresh(entry *snapshotEntry) bool {
+	return isDone(entry) && time.Since(entry.fetchedAt) < s.ttl
+}
+
+func isDone(entry *snapshotEntry) bool {
+	select {
+	case <-entry.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func listDirect(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+	if ns == "" {
+		return client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	}
+	return client.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+}
+
+// filterNamespace copies the items of list that live in ns.
+func filterNamespace(list *unstructured.UnstructuredList, ns string) *unstructured.UnstructuredList {
+	out := &unstructured.UnstructuredList{}
+	out.SetAPIVersion(list.GetAPIVersion())
+	out.SetKind(list.GetKind())
+	for _, item := range list.Items {
+		if item.GetNamespace() == ns {
+			out.Items = append(out.Items, *item.DeepCopy())
+		}
+	}
+	return out
+}
+
+// filterScope drops the items of list outside scope. The API client already
+// narrows scoped lists; this keeps a list fetched under another scope from
+// being served anyway.
+func filterScope(list *unstructured.UnstructuredList, scope *k8s.NamespaceScope) *unstructured.UnstructuredList {
+	if scope == nil {
+		return list
+	}
+	items := list.Items[:0]
+	for _, item := range list.Items {
+		if ns := item.GetNamespace(); ns == "" || scope.Allows(ns) {
+			items = append(items, item)
+		}
+	}
+	list.Items = items
+	return list
+}
+
+// listResource lists gvr in ns (all namespaces when empty), served from the
+// shared snapshot when one is configured. Failures are recorded for the
+// response's partial-results errors.
+func (b *BaseTool) listResource(ctx context.Context, gvr schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+	list, err := b.listUnrecorded(ctx, gvr, ns)
+	recordListError(ctx, gvr, ns, err)
+	return list, err
+}
+
+func (b *BaseTool) listUnrecorded(ctx context.Context, gvr schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+	if b.Snapshot == nil {
+		return listDirect(ctx, b.Clients.Dynamic, gvr, ns)
+	}
+	return b.Snapshot.List(ctx, gvr, ns)
+}
+
+// listResourceWithFallback is listWithFallback served from the shared snapshot.
+// Only a failure of both versions is recorded, as the v1 error when v1 is
+// served but failed.
+func (b *BaseTool) listResourceWithFallback(ctx context.Context, v1, v1beta1 schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+	list, err := b.listUnrecorded(ctx, v1, ns)
+	if err == nil {
+		return list, nil
+	}
+	list, fallbackErr := b.listUnrecorded(ctx, v1beta1, ns)
+	if fallbackErr == nil {
+		return list, nil
+	}
+	if apierrors.IsNotFound(err) {
+		recordListError(ctx, v1beta1, ns, fallbackErr)
+	} else {
+		recordListError(ctx, v1, ns, err)
+	}
+	return nil, fallbackErr
+}
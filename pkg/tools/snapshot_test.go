@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+)
+
+func newSnapshotTestClient(t *testing.T) (*dynamicfake.FakeDynamicClient, *int) {
+	t.Helper()
+	svc := func(ns, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("Service")
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{servicesGVR: "ServiceList"},
+		svc("a", "one"), svc("b", "two"))
+	lists := 0
+	client.PrependReactor("list", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	return client, &lists
+}
+
+func TestClusterSnapshot_SharesListsWithinTTL(t *testing.T) {
+	client, lists := newSnapshotTestClient(t)
+	s := NewClusterSnapshot(client, time.Minute)
+	ctx := context.Background()
+
+	all, err := s.List(ctx, servicesGVR, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Items) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(all.Items))
+	}
+	// Mutating a returned list must not leak into later callers.
+	all.Items = nil
+
+	nsList, err := s.List(ctx, servicesGVR, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nsList.Items) != 1 || nsList.Items[0].GetName() != "one" {
+		t.Errorf("namespaced list should be served from the cluster-wide entry, got %+v", nsList.Items)
+	}
+	if again, _ := s.List(ctx, servicesGVR, ""); len(again.Items) != 2 {
+		t.Errorf("cached list was modified by an earlier caller")
+	}
+	if *lists != 1 {
+		t.Errorf("expected 1 API list call, got %d", *lists)
+	}
+
+	s.Reset()
+	if _, err := s.List(ctx, servicesGVR, ""); err != nil {
+		t.Fatal(err)
+	}
+	if *lists != 2 {
+		t.Errorf("expected Reset to force a new list call, got %d calls", *lists)
+	}
+}
+
+func TestClusterSnapshot_ZeroTTLDisablesSharing(t *testing.T) {
+	client, lists := newSnapshotTestClient(t)
+	s := NewClusterSnapshot(client, 0)
+	for i := 0; i < 3; i++ {
+		if _, err := s.List(context.Background(), servicesGVR, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *lists != 3 {
+		t.Errorf("expected every call to hit the API with TTL 0, got %d calls", *lists)
+	}
+}
+
+func TestClusterSnapshot_SeparatesImpersonatedCallers(t *testing.T) {
+	client, lists := newSnapshotTestClient(t)
+	s := NewClusterSnapshot(client, time.Minute)
+	alice := k8s.WithImpersonation(context.Background(), k8s.Impersonation{User: "alice"})
+	bob := k8s.WithImpersonation(context.Background(), k8s.Impersonation{User: "bob"})
+
+	for _, ctx := range []context.Context{alice, bob, alice, context.Background()} {
+		if _, err := s.List(ctx, servicesGVR, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *lists != 3 {
+		t.Errorf("expected one API list call per identity, got %d", *lists)
+	}
+}
+
+func TestClusterSnapshot_AppliesNamespaceScope(t *testing.T) {
+	client, lists := newSnapshotTestClient(t)
+	s := NewClusterSnapshot(client, time.Minute)
+
+	// An unscoped caller, such as a scheduled skill, fills the cache first.
+	if all, err := s.List(context.Background(), servicesGVR, ""); err != nil || len(all.Items) != 2 {
+		t.Fatalf("unscoped list: %v, %v", all, err)
+	}
+
+	scoped := k8s.WithNamespaceScope(context.Background(), k8s.NewNamespaceScope(nil, []string{"b"}))
+	for _, ns := range []string{"", "b"} {
+		list, err := s.List(scoped, servicesGVR, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range list.Items {
+			if item.GetNamespace() == "b" {
+				t.Errorf("list of %q served %s/%s from the denied namespace", ns, item.GetNamespace(), item.GetName())
+			}
+		}
+	}
+	if *lists != 2 {
+		t.Errorf("expected one API list call per scope, got %d", *lists)
+	}
+}
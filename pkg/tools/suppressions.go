@@ -0,0 +1,565 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// Suppression silences the findings with a code about matching resources
+// until it expires.
+type Suppression struct {
+	ID string `json:"id"`
+	// Code is a finding code or code prefix, e.g. EGR003.
+	Code string `json:"code"`
+	// Kind, Namespace and Name select the resources; empty values match any.
+	Kind      string    `json:"kind,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Reason    string    `json:"reason"`
+	Created   time.Time `json:"created"`
+	Expires   time.Time `json:"expires,omitempty"` // zero = never
+}
+
+var suppressionKeyChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// suppressionID derives the ConfigMap key of a suppression from what it
+// matches, so suppressing the same findings again replaces it.
+func suppressionID(code, kind, namespace, name string) string {
+	parts := []string{code, kind, namespace, name}
+	for i, p := range parts {
+		parts[i] = suppressionKeyChars.ReplaceAllString(strings.ToLower(p), "-")
+		if parts[i] == "" {
+			parts[i] = "any"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Expired reports whether s no longer applies at now.
+func (s Suppression) Expired(now time.Time) bool {
+	return !s.Expires.IsZero() && !now.Before(s.Expires)
+}
+
+// Matches reports whether s silences f.
+func (s Suppression) Matches(f types.DiagnosticFinding) bool {
+	if f.Code == "" || !strings.HasPrefix(string(f.Code), s.Code) {
+		return false
+	}
+	if s.Kind == "" && s.Namespace == "" && s.Name == "" {
+		return true
+	}
+	return f.Resource != nil &&
+		(s.Kind == "" || strings.EqualFold(f.Resource.Kind, s.Kind)) &&
+		(s.Namespace == "" || f.Resource.Namespace == s.Namespace) &&
+		(s.Name == "" || f.Resource.Name == s.Name)
+}
+
+func (s Suppression) target() string {
+	var parts []string
+	if s.Kind != "" {
+		parts = append(parts, s.Kind)
+	}
+	switch {
+	case s.Namespace != "" && s.Name != "":
+		parts = append(parts, s.Namespace+"/"+s.Name)
+	case s.Namespace != "":
+		parts = append(parts, "in "+s.Namespace)
+	case s.Name != "":
+		parts = append(parts, s.Name)
+	}
+	if len(parts) == 0 {
+		return "any resource"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s Suppression) expiry() string {
+	if s.Expires.IsZero() {
+		return "never expires"
+	}
+	return "expires " + s.Expires.Format(time.RFC3339)
+}
+
+// SuppressionStore keeps suppressions in a ConfigMap, one JSON entry per key,
+// so operators can also review them with kubectl. Reads are cached for ttl.
+type SuppressionStore struct {
+	client    dynamic.Interface
+	namespace string
+	name      string
+	ttl       time.Duration
+
+	mu       sync.Mutex
+	cached   []Suppression
+	loadedAt time.Time
+}
+
+// NewSuppressionStore creates a store of the suppressions in ConfigMap
+// namespace/name, which is created on the first suppression.
+func NewSuppressionStore(client dynamic.Interface, namespace, name string, ttl time.Duration) *SuppressionStore {
+	return &SuppressionStore{client: client, namespace: namespace, name: name, ttl: ttl}
+}
+
+// ConfigMap returns the namespace/name of the ConfigMap holding the suppressions.
+func (s *SuppressionStore) ConfigMap() string {
+	return s.namespace + "/" + s.name
+}
+
+// List returns every suppression, expired ones included, sorted by ID.
+func (s *SuppressionStore) List(ctx context.Context) ([]Suppression, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.ttl {
+		return s.cached, nil
+	}
+	cm, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cached = parseSuppressions(cm)
+	s.loadedAt = time.Now()
+	return s.cached, nil
+}
+
+// get returns the ConfigMap, or nil when it does not exist yet. The
+// ConfigMap is read whatever the namespace scope of the call.
+func (s *SuppressionStore) get(ctx context.Context) (*unstructured.Unstructured, error) {
+	ctx = k8s.WithNamespaceScope(ctx, nil)
+	cm, err := s.client.Resource(configmapsGVR).Namespace(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppressions ConfigMap %s: %w", s.ConfigMap(), err)
+	}
+	return cm, nil
+}
+
+func parseSuppressions(cm *unstructured.Unstructured) []Suppression {
+	if cm == nil {
+		return nil
+	}
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	var out []Suppression
+	for key, value := range data {
+		var sup Suppression
+		if err := json.Unmarshal([]byte(value), &sup); err != nil || sup.Code == "" {
+			slog.Warn("skipping unreadable suppression", "configmap", cm.GetNamespace()+"/"+cm.GetName(), "key", key)
+			continue
+		}
+		sup.ID = key
+		out = append(out, sup)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Put adds or replaces sup, and drops the suppressions that expired.
+func (s *SuppressionStore) Put(ctx context.Context, sup Suppression) error {
+	return s.update(ctx, func(data map[string]string) {
+		value, _ := json.Marshal(sup)
+		data[sup.ID] = string(value)
+	})
+}
+
+// Remove deletes the suppression with id, and reports whether it existed.
+func (s *SuppressionStore) Remove(ctx context.Context, id string) (bool, error) {
+	found := false
+	err := s.update(ctx, func(data map[string]string) {
+		_, found = data[id]
+		delete(data, id)
+	})
+	return found, err
+}
+
+// update applies change to the ConfigMap data, creating the ConfigMap if
+// needed.
+func (s *SuppressionStore) update(ctx context.Context, change func(map[string]string)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+	cm, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]string)
+	if cm != nil {
+		data, _, _ = unstructured.NestedStringMap(cm.Object, "data")
+		if data == nil {
+			data = make(map[string]string)
+		}
+	}
+	now := time.Now()
+	for _, sup := range parseSuppressions(cm) {
+		if sup.Expired(now) {
+			delete(data, sup.ID)
+		}
+	}
+	change(data)
+
+	ctx = k8s.WithNamespaceScope(ctx, nil)
+	if cm == nil {
+		cm = &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+		cm.SetNamespace(s.namespace)
+		cm.SetName(s.name)
+		cm.SetLabels(map[string]string{"app.kubernetes.io/managed-by": "mcp-k8s-networking"})
+		if err := unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+			return err
+		}
+		_, err = s.client.Resource(configmapsGVR).Namespace(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		if err := unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+			return err
+		}
+		_, err = s.client.Resource(configmapsGVR).Namespace(s.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write suppressions ConfigMap %s: %w", s.ConfigMap(), err)
+	}
+	return nil
+}
+
+// Apply removes the findings silenced by an active suppression and, when it
+// removed any, appends one info finding telling what was suppressed and why.
+// When the suppressions cannot be read, findings are returned unchanged.
+func (s *SuppressionStore) Apply(ctx context.Context, findings []types.DiagnosticFinding) []types.DiagnosticFinding {
+	sups, err := s.List(ctx)
+	if err != nil {
+		slog.Warn("findings are not filtered by suppressions", "error", err)
+		return findings
+	}
+	now := time.Now()
+	var active []Suppression
+	for _, sup := range sups {
+		if !sup.Expired(now) {
+			active = append(active, sup)
+		}
+	}
+	if len(active) == 0 {
+		return findings
+	}
+
+	kept := make([]types.DiagnosticFinding, 0, len(findings))
+	used := make(map[string]int)
+	var order []Suppression
+	suppressed := 0
+	category := ""
+	for _, f := range findings {
+		matched := -1
+		if f.Severity != types.SeverityOK {
+			for i, sup := range active {
+				if sup.Matches(f) {
+					matched = i
+					break
+				}
+			}
+		}
+		if matched < 0 {
+			kept = append(kept, f)
+			continue
+		}
+		sup := active[matched]
+		if used[sup.ID] == 0 {
+			order = append(order, sup)
+		}
+		used[sup.ID]++
+		suppressed++
+		if category == "" {
+			category = f.Category
+		} else if category != f.Category {
+			category = types.CategoryPolicy
+		}
+	}
+	if suppressed == 0 {
+		return findings
+	}
+	var details []string
+	for _, sup := range order {
+		details = append(details, fmt.Sprintf("%d by %s (%s %s): %s, %s", used[sup.ID], sup.ID, sup.Code, sup.target(), sup.Reason, sup.expiry()))
+	}
+	return append(kept, types.DiagnosticFinding{
+		Severity:   types.SeverityInfo,
+		Category:   orDefault(category, types.CategoryPolicy),
+		Summary:    fmt.Sprintf("%d finding(s) suppressed by %d suppression(s)", suppressed, len(order)),
+		Detail:     strings.Join(details, "; "),
+		Suggestion: "Run list_suppressions to review suppressions, or suppress_finding with remove to lift one.",
+	})
+}
+
+// AppliesSuppressions reports whether the findings of t are filtered by
+// suppressions; those of the tools listing codes, suppressions or the
+// finding history are not.
+func AppliesSuppressions(t Tool) bool {
+	switch t.(type) {
+	case *SuppressFindingTool, *ListSuppressionsTool, *ListFindingCodesTool, *GetFindingHistoryTool, *GetFindingTrendsTool:
+		return false
+	}
+	return true
+}
+
+// findingCodesWithPrefix returns the catalog entries whose code starts with prefix.
+func findingCodesWithPrefix(prefix string) []types.FindingCodeInfo {
+	var out []types.FindingCodeInfo
+	for _, info := range types.FindingCodes() {
+		if strings.HasPrefix(string(info.Code), prefix) {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// suppressionCategory returns the category of the findings code silences,
+// or policy when its codes span several categories or none is known.
+func suppressionCategory(code string) string {
+	category := ""
+	for _, info := range findingCodesWithPrefix(code) {
+		if category != "" && category != info.Category {
+			return types.CategoryPolicy
+		}
+		category = info.Category
+	}
+	return orDefault(category, types.CategoryPolicy)
+}
+
+// parseExpiry reads an expiry as a duration after now, an RFC3339 time or
+// "never".
+func parseExpiry(s string, now time.Time) (time.Time, error) {
+	if s == "never" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration %s must be positive", s)
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a duration (e.g. 720h), an RFC3339 time or never")
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("%s is in the past", s)
+	}
+	return t, nil
+}
+
+func suppressionFinding(sup Suppression, now time.Time) types.DiagnosticFinding {
+	f := types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: suppressionCategory(sup.Code),
+		Summary:  fmt.Sprintf("%s: %s on %s, %s", sup.ID, sup.Code, sup.target(), sup.expiry()),
+		Detail:   fmt.Sprintf("reason: %s; created %s", sup.Reason, sup.Created.Format(time.RFC3339)),
+	}
+	if infos := findingCodesWithPrefix(sup.Code); len(infos) == 1 {
+		f.Detail += "; " + infos[0].Description
+	}
+	if sup.Expired(now) {
+		f.Severity = types.SeverityWarning
+		f.Summary = fmt.Sprintf("%s: %s on %s expired on %s", sup.ID, sup.Code, sup.target(), sup.Expires.Format(time.RFC3339))
+		f.Suggestion = "The findings are reported again. Renew the suppression with suppress_finding if they are still accepted."
+	}
+	return f
+}
+
+// --- suppress_finding ---
+
+type SuppressFindingTool struct {
+	BaseTool
+	Suppressions *SuppressionStore
+}
+
+func (t *SuppressFindingTool) Name() string { return "suppress_finding" }
+func (t *SuppressFindingTool) Description() string {
+	return "Silence a known or accepted finding (e.g. an intentional wildcard egress) until an expiry, with a reason: every tool then leaves it out of its findings and reports how many were suppressed. Use remove to lift a suppression"
+}
+func (t *SuppressFindingTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "Finding code or code prefix to suppress, e.g. EGR003",
+			},
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "Only findings about this resource kind (default: any)",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only findings about resources in this namespace (default: any)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Only findings about resources with this name (default: any)",
+			},
+			"reason": map[string]interface{}{
+				"type":        "string",
+				"description": "Why the finding is accepted, shown wherever it is suppressed",
+			},
+			"expires": map[string]interface{}{
+				"type":        "string",
+				"description": "Duration from now (e.g. 720h), RFC3339 time or never (default 720h)",
+			},
+			"remove": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Lift the suppression of code, kind, namespace and name instead (default false)",
+			},
+		},
+		"required": []string{"code"},
+	}
+}
+
+func (t *SuppressFindingTool) RequiredPermissions() []Permission {
+	return []Permission{
+		{Verb: "get", Resource: "configmaps", Namespace: t.Suppressions.namespace},
+		{Verb: "create", Resource: "configmaps", Namespace: t.Suppressions.namespace},
+		{Verb: "update", Resource: "configmaps", Namespace: t.Suppressions.namespace},
+	}
+}
+
+func (t *SuppressFindingTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	code := strings.ToUpper(getStringArg(args, "code", ""))
+	kind := getStringArg(args, "kind", "")
+	ns := getStringArg(args, "namespace", "")
+	name := getStringArg(args, "name", "")
+	if code == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "code is required",
+			Detail:  "list_finding_codes lists the finding codes",
+		}
+	}
+	// A caller limited to some namespaces may not silence, or lift, the
+	// findings of the others
+	if scope := k8s.NamespaceScopeFrom(ctx); scope != nil && (ns == "" || !scope.Allows(ns)) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeNamespaceForbidden,
+			Tool:    t.Name(),
+			Message: "namespace is required and must be one this server may inspect: a suppression without one applies to every namespace",
+			Detail:  scope.String(),
+		}
+	}
+	id := suppressionID(code, kind, ns, name)
+
+	if remove, _ := args["remove"].(bool); remove {
+		found, err := t.Suppressions.Remove(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: suppressionCategory(code),
+			Summary:  fmt.Sprintf("Suppression %s removed: %s findings are reported again", id, code),
+		}
+		if !found {
+			f.Severity = types.SeverityWarning
+			f.Summary = fmt.Sprintf("No suppression %s in ConfigMap %s", id, t.Suppressions.ConfigMap())
+			f.Suggestion = "Pass the code, kind, namespace and name of the suppression as list_suppressions shows them."
+		}
+		return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{f}, orDefault(ns, "all"), ""), nil
+	}
+
+	reason := strings.TrimSpace(getStringArg(args, "reason", ""))
+	if reason == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "reason is required to suppress a finding",
+		}
+	}
+	now := time.Now()
+	expires, err := parseExpiry(getStringArg(args, "expires", "720h"), now)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid expires: %v", err),
+		}
+	}
+	sup := Suppression{ID: id, Code: code, Kind: kind, Namespace: ns, Name: name, Reason: reason, Created: now.UTC(), Expires: expires.UTC()}
+	if err := t.Suppressions.Put(ctx, sup); err != nil {
+		return nil, err
+	}
+	f := suppressionFinding(sup, now)
+	f.Severity = types.SeverityOK
+	f.Summary = "Suppressed " + f.Summary
+	if len(findingCodesWithPrefix(code)) == 0 {
+		f.Severity = types.SeverityWarning
+		f.Suggestion = fmt.Sprintf("%s is not a known finding code: check it with list_finding_codes.", code)
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{f}, orDefault(ns, "all"), ""), nil
+}
+
+// --- list_suppressions ---
+
+type ListSuppressionsTool struct {
+	BaseTool
+	Suppressions *SuppressionStore
+}
+
+func (t *ListSuppressionsTool) Name() string { return "list_suppressions" }
+func (t *ListSuppressionsTool) Description() string {
+	return "List the finding suppressions with their reason and expiry; expired suppressions are reported as warnings until they are renewed or removed"
+}
+func (t *ListSuppressionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "Only suppressions whose code starts with this prefix",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only suppressions of this namespace",
+			},
+		},
+	}
+}
+
+func (t *ListSuppressionsTool) RequiredPermissions() []Permission {
+	return []Permission{{Verb: "get", Resource: "configmaps", Namespace: t.Suppressions.namespace}}
+}
+
+func (t *ListSuppressionsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	code := strings.ToUpper(getStringArg(args, "code", ""))
+	ns := getStringArg(args, "namespace", "")
+	sups, err := t.Suppressions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var findings []types.DiagnosticFinding
+	active := 0
+	for _, sup := range sups {
+		if !strings.HasPrefix(sup.Code, code) || (ns != "" && sup.Namespace != ns) {
+			continue
+		}
+		if !sup.Expired(now) {
+			active++
+		}
+		findings = append(findings, suppressionFinding(sup, now))
+	}
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("%d suppression(s), %d active, in ConfigMap %s", len(findings), active, t.Suppressions.ConfigMap()),
+	}
+	findings = append([]types.DiagnosticFinding{summary}, findings...)
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), ""), nil
+}
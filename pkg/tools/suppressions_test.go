@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestSuppressionMatches(t *testing.T) {
+	sup := Suppression{Code: "EGR003", Namespace: "shop"}
+	egress := types.DiagnosticFinding{Code: "EGR003_WILDCARD_EGRESS", Resource: &types.ResourceRef{Kind: "NetworkPolicy", Namespace: "shop", Name: "allow-all"}}
+	if !sup.Matches(egress) {
+		t.Error("expected a code prefix and namespace to match")
+	}
+	egress.Resource.Namespace = "billing"
+	if sup.Matches(egress) || sup.Matches(types.DiagnosticFinding{Code: "EGR003_WILDCARD_EGRESS"}) {
+		t.Error("expected findings of other or no resources not to match")
+	}
+	if got := suppressionID("EGR003", "NetworkPolicy", "shop", ""); got != "egr003.networkpolicy.shop.any" {
+		t.Errorf("suppressionID() = %s", got)
+	}
+	if _, err := parseExpiry("2020-01-01T00:00:00Z", time.Now()); err == nil {
+		t.Error("expected an expiry in the past to be rejected")
+	}
+}
+
+func TestSuppressFinding(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configmapsGVR: "ConfigMapList",
+	})
+	store := NewSuppressionStore(client, "mcp", "suppressions", time.Minute)
+	base := BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}
+	suppress := &SuppressFindingTool{BaseTool: base, Suppressions: store}
+	ctx := context.Background()
+
+	if _, err := suppress.Run(ctx, map[string]interface{}{"code": "egr003"}); err == nil {
+		t.Error("expected an error without reason")
+	}
+	for _, args := range []map[string]interface{}{
+		{"code": "EGR003", "namespace": "shop", "reason": "egress proxy needs the internet"},
+		{"code": "GW001", "reason": "old rule", "expires": "1h"},
+	} {
+		if _, err := suppress.Run(ctx, args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings := []types.DiagnosticFinding{
+		{Severity: types.SeverityWarning, Code: "EGR003_WILDCARD_EGRESS", Resource: &types.ResourceRef{Kind: "NetworkPolicy", Namespace: "shop", Name: "allow-all"}, Summary: "wildcard egress"},
+		{Severity: types.SeverityWarning, Code: "EGR003_WILDCARD_EGRESS", Resource: &types.ResourceRef{Kind: "NetworkPolicy", Namespace: "billing", Name: "allow-all"}, Summary: "wildcard egress"},
+		{Severity: types.SeverityOK, Summary: "3 policies checked"},
+	}
+	got := store.Apply(ctx, findings)
+	if len(got) != 3 || got[0].Resource.Namespace != "billing" || got[2].Summary != "1 finding(s) suppressed by 1 suppression(s)" ||
+		!strings.Contains(got[2].Detail, "1 by egr003.any.shop.any (EGR003 in shop): egress proxy needs the internet") {
+		t.Errorf("Apply() = %+v", got)
+	}
+
+	list := &ListSuppressionsTool{BaseTool: base, Suppressions: store}
+	resp, err := list.Run(ctx, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "2 suppression(s), 2 active, in ConfigMap mcp/suppressions") {
+		t.Errorf("unexpected suppressions:\n%s", all)
+	}
+
+	resp, err = suppress.Run(ctx, map[string]interface{}{"code": "EGR003", "namespace": "shop", "remove": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := managedFindingsOf(resp); !strings.Contains(all, "ok Suppression egr003.any.shop.any removed") {
+		t.Errorf("unexpected removal:\n%s", all)
+	}
+	if got := store.Apply(ctx, findings); len(got) != len(findings) {
+		t.Errorf("Apply() after removal = %+v, want findings unchanged", got)
+	}
+}
+
+func TestSuppressFindingNamespaceScope(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configmapsGVR: "ConfigMapList",
+	})
+	store := NewSuppressionStore(client, "mcp", "suppressions", time.Minute)
+	suppress := &SuppressFindingTool{
+		BaseTool:     BaseTool{Cfg: &config.Config{ClusterName: "test", AllowedNamespaces: []string{"team-a", "team-a-staging"}}, Clients: &k8s.Clients{Dynamic: client}},
+		Suppressions: store,
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"code": "EGR003", "reason": "accepted"},
+		{"code": "EGR003", "remove": true},
+	} {
+		ctx, err := ApplyNamespaceScope(context.Background(), suppress, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = suppress.Run(ctx, args)
+		if mcpErr, ok := err.(*types.MCPError); !ok || mcpErr.Code != types.ErrCodeNamespaceForbidden {
+			t.Errorf("%v without a namespace: error = %v, want %s", args, err, types.ErrCodeNamespaceForbidden)
+		}
+	}
+
+	args := map[string]interface{}{"code": "EGR003", "namespace": "team-a", "reason": "accepted"}
+	ctx, err := ApplyNamespaceScope(context.Background(), suppress, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := suppress.Run(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := resp.Data.(*types.ToolResult).Findings[0]; f.Category != types.CategoryPolicy {
+		t.Errorf("suppression finding category %q, want that of EGR003", f.Category)
+	}
+
+	got := store.Apply(context.Background(), []types.DiagnosticFinding{
+		{Severity: types.SeverityWarning, Category: types.CategoryConnectivity, Code: "EGR003_WILDCARD_EGRESS", Resource: &types.ResourceRef{Kind: "NetworkPolicy", Namespace: "team-a", Name: "allow-all"}},
+	})
+	if len(got) != 1 || got[0].Category != types.CategoryConnectivity {
+		t.Errorf("Apply() = %+v, want a summary in the category of the suppressed finding", got)
+	}
+}
@@ -0,0 +1,664 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/progress"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// maxIsolationProbeTargets bounds the connections one isolation probe pod tries.
+const maxIsolationProbeTargets = 10
+
+// unlabeledPod names the synthetic source pod standing for any new pod, and
+// the probe pod, in a tenant namespace.
+const unlabeledPod = "(any unlabeled pod)"
+
+// policyHasType reports whether a NetworkPolicy isolates for direction
+// (Ingress or Egress). Without policyTypes, Ingress is implied and Egress
+// only when the policy has egress rules.
+func policyHasType(np unstructured.Unstructured, direction string) bool {
+	if pt, _, _ := unstructured.NestedStringSlice(np.Object, "spec", "policyTypes"); len(pt) > 0 {
+		return containsString(pt, direction)
+	}
+	if direction == "Ingress" {
+		return true
+	}
+	_, ok, _ := unstructured.NestedFieldNoCopy(np.Object, "spec", "egress")
+	return ok
+}
+
+// ipBlockMatches reports whether ip is in the ipBlock and none of its
+// exceptions.
+func ipBlockMatches(block map[string]interface{}, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	cidr, _ := block["cidr"].(string)
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Contains(addr) {
+		return false
+	}
+	except, _, _ := unstructured.NestedStringSlice(block, "except")
+	for _, e := range except {
+		if p, err := netip.ParsePrefix(e); err == nil && p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// peerMatches reports whether a NetworkPolicy from/to peer selects pod.
+func peerMatches(peer map[string]interface{}, policyNs string, pod podPorts, nsLabels map[string]map[string]string) (bool, error) {
+	if block, ok, _ := unstructured.NestedMap(peer, "ipBlock"); ok {
+		return ipBlockMatches(block, pod.IP), nil
+	}
+	podSelObj, hasPodSel, _ := unstructured.NestedMap(peer, "podSelector")
+	nsSelObj, hasNsSel, _ := unstructured.NestedMap(peer, "namespaceSelector")
+
+	if hasNsSel {
+		nsSel, err := parseLabelSelector(nsSelObj, true)
+		if err != nil {
+			return false, err
+		}
+		l, ok := nsLabels[pod.Namespace]
+		if !ok || !nsSel.Matches(labels.Set(l)) {
+			return false, nil
+		}
+	} else if pod.Namespace != policyNs {
+		return false, nil
+	}
+	if !hasPodSel {
+		return true, nil
+	}
+	podSel, err := parseLabelSelector(podSelObj, true)
+	if err != nil {
+		return false, err
+	}
+	return podSel.Matches(labels.Set(pod.Labels)), nil
+}
+
+// rulePortsMatch reports whether the ports of a NetworkPolicy rule include
+// port on dst. Port 0 stands for a port no rule names, which only rules
+// without a port number match.
+func rulePortsMatch(rule map[string]interface{}, port containerPort, dst podPorts) bool {
+	ports, _, _ := unstructured.NestedSlice(rule, "ports")
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		proto, _ := pm["protocol"].(string)
+		if orDefault(proto, "TCP") != port.Protocol {
+			continue
+		}
+		v, ok := pm["port"]
+		if !ok || v == nil {
+			return true
+		}
+		if port.Port == 0 {
+			continue
+		}
+		if isPortName(v) {
+			if cp, declared := resolvePort(v, port.Protocol, dst); declared && cp.Port == port.Port {
+				return true
+			}
+			continue
+		}
+		start, end := int64(toInt(v)), int64(toInt(pm["endPort"]))
+		if port.Port == start || (end > start && port.Port >= start && port.Port <= end) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyDecision is how the NetworkPolicies of one pod treat one direction
+// of a connection.
+type policyDecision struct {
+	isolatedBy []string // policies that select the pod for the direction
+	allowedBy  string   // the first rule allowing the connection
+}
+
+func (d policyDecision) allowed() bool { return len(d.isolatedBy) == 0 || d.allowedBy != "" }
+
+// evaluatePolicies applies the NetworkPolicies to the direction side of a
+// connection: subject is the pod the policies must select, peer the other
+// end and dst the destination pod whose named ports resolve port.
+func evaluatePolicies(policies []unstructured.Unstructured, direction string, subject, peer, dst podPorts, port containerPort, nsLabels map[string]map[string]string) (policyDecision, error) {
+	var d policyDecision
+	ruleKey, peerKey := "ingress", "from"
+	if direction == "Egress" {
+		ruleKey, peerKey = "egress", "to"
+	}
+	for _, np := range policies {
+		if np.GetNamespace() != subject.Namespace || !policyHasType(np, direction) {
+			continue
+		}
+		podSelObj, _, _ := unstructured.NestedMap(np.Object, "spec", "podSelector")
+		podSel, err := parseLabelSelector(podSelObj, true)
+		if err != nil {
+			return d, fmt.Errorf("NetworkPolicy %s/%s: %w", np.GetNamespace(), np.GetName(), err)
+		}
+		if !podSel.Matches(labels.Set(subject.Labels)) {
+			continue
+		}
+		d.isolatedBy = append(d.isolatedBy, np.GetNamespace()+"/"+np.GetName())
+		if d.allowedBy != "" {
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(np.Object, "spec", ruleKey)
+		for i, r := range rules {
+			rm, ok := r.(map[string]interface{})
+			if !ok || !rulePortsMatch(rm, port, dst) {
+				continue
+			}
+			peers, _, _ := unstructured.NestedSlice(rm, peerKey)
+			matched := len(peers) == 0
+			for _, pe := range peers {
+				pm, ok := pe.(map[string]interface{})
+				if !ok || matched {
+					continue
+				}
+				if matched, err = peerMatches(pm, np.GetNamespace(), peer, nsLabels); err != nil {
+					return d, fmt.Errorf("NetworkPolicy %s/%s: %w", np.GetNamespace(), np.GetName(), err)
+				}
+			}
+			if matched {
+				d.allowedBy = fmt.Sprintf("%s/%s %s[%d]", np.GetNamespace(), np.GetName(), ruleKey, i)
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+// describeDecision explains a policyDecision for the evidence of a finding.
+func describeDecision(direction string, d policyDecision, subject, peer podPorts) string {
+	side := strings.ToLower(direction)
+	switch {
+	case len(d.isolatedBy) == 0:
+		return fmt.Sprintf("%s: no NetworkPolicy isolates %s/%s", side, subject.Namespace, subject.Name)
+	case d.allowedBy != "":
+		return fmt.Sprintf("%s: allowed by %s", side, d.allowedBy)
+	default:
+		return fmt.Sprintf("%s: %s isolate %s/%s and no rule allows %s/%s", side, strings.Join(d.isolatedBy, ", "), subject.Namespace, subject.Name, peer.Namespace, peer.Name)
+	}
+}
+
+// portClass is a destination port the isolation check evaluates; port 0
+// stands for any port the destination pods do not declare.
+type portClass struct {
+	port     int64
+	protocol string
+}
+
+func (c portClass) String() string {
+	if c.port == 0 {
+		return "undeclared " + c.protocol + " ports"
+	}
+	return fmt.Sprintf("%d/%s", c.port, c.protocol)
+}
+
+// isolationTenant is one side of an isolation check.
+type isolationTenant struct {
+	label      string
+	namespaces []string
+	pods       []podPorts
+}
+
+// isolationResult is the outcome of one direction and port class.
+type isolationResult struct {
+	class    portClass
+	pairs    int
+	allowed  int
+	example  [2]podPorts // an allowed pair, or a blocked one when none is
+	evidence string
+}
+
+// representativePods keeps one pod per namespace, label set and declared
+// ports: NetworkPolicies cannot tell such pods apart.
+func representativePods(pods []podPorts) []podPorts {
+	seen := make(map[string]bool)
+	var out []podPorts
+	for _, p := range pods {
+		key := p.Namespace + "|" + labels.Set(p.Labels).String() + "|" + fmt.Sprint(p.Ports)
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// evaluateIsolation evaluates every connection from the pods of src to the
+// pods of dst, per destination port class. Each source namespace also gets
+// an unlabeled pod, standing for any new workload.
+func evaluateIsolation(policies []unstructured.Unstructured, src, dst isolationTenant, nsLabels map[string]map[string]string) ([]isolationResult, error) {
+	sources := representativePods(src.pods)
+	for _, ns := range src.namespaces {
+		sources = append(sources, podPorts{Namespace: ns, Name: unlabeledPod})
+	}
+	dests := representativePods(dst.pods)
+
+	classes := map[portClass]bool{}
+	for _, p := range dests {
+		for _, cp := range p.Ports {
+			classes[portClass{port: cp.Port, protocol: cp.Protocol}] = true
+		}
+	}
+	ordered := []portClass{{protocol: "TCP"}, {protocol: "UDP"}}
+	for c := range classes {
+		ordered = append(ordered, c)
+	}
+	declared := ordered[2:]
+	sort.Slice(declared, func(i, j int) bool {
+		a, b := declared[i], declared[j]
+		if a.port != b.port {
+			return a.port < b.port
+		}
+		return a.protocol < b.protocol
+	})
+
+	var results []isolationResult
+	for _, c := range ordered {
+		res := isolationResult{class: c}
+		for _, d := range dests {
+			if c.port != 0 && !declaresPort(d, c) {
+				continue
+			}
+			port := containerPort{Port: c.port, Protocol: c.protocol}
+			for _, s := range sources {
+				egress, err := evaluatePolicies(policies, "Egress", s, d, d, port, nsLabels)
+				if err != nil {
+					return nil, err
+				}
+				ingress, err := evaluatePolicies(policies, "Ingress", d, s, d, port, nsLabels)
+				if err != nil {
+					return nil, err
+				}
+				res.pairs++
+				allowed := egress.allowed() && ingress.allowed()
+				if allowed {
+					res.allowed++
+				}
+				if res.pairs == 1 || (allowed && res.allowed == 1) {
+					res.example = [2]podPorts{s, d}
+					res.evidence = describeDecision("Egress", egress, s, d) + "; " + describeDecision("Ingress", ingress, d, s)
+				}
+			}
+		}
+		if res.pairs > 0 {
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+func declaresPort(p podPorts, c portClass) bool {
+	for _, cp := range p.Ports {
+		if cp.Port == c.port && cp.Protocol == c.protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// isolationFindings reports the results of one direction.
+func isolationFindings(src, dst isolationTenant, results []isolationResult) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, r := range results {
+		s, d := r.example[0], r.example[1]
+		ref := &types.ResourceRef{Kind: "Pod", Namespace: d.Namespace, Name: d.Name, APIVersion: "v1"}
+		if r.allowed == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryPolicy,
+				Summary:  fmt.Sprintf("%s -> %s on %s: blocked for all %d pod pair(s)", src.label, dst.label, r.class, r.pairs),
+				Detail:   fmt.Sprintf("e.g. %s/%s -> %s/%s: %s", s.Namespace, s.Name, d.Namespace, d.Name, r.evidence),
+			})
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryPolicy,
+			Code:       types.CodeTenantPathAllowed,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s -> %s on %s: allowed for %d of %d pod pair(s), e.g. %s/%s -> %s/%s", src.label, dst.label, r.class, r.allowed, r.pairs, s.Namespace, s.Name, d.Namespace, d.Name),
+			Detail:     r.evidence,
+			Suggestion: fmt.Sprintf("Add a default-deny NetworkPolicy (empty podSelector, policyTypes Ingress and Egress) to the namespaces of %s and %s, then allow only the traffic each tenant needs within itself.", src.label, dst.label),
+		})
+	}
+	return findings
+}
+
+// isolationProbeTargets picks one pod IP per declared TCP port of dst, as
+// ip:port.
+func isolationProbeTargets(dst isolationTenant) []string {
+	seen := make(map[int64]bool)
+	var targets []string
+	for _, p := range dst.pods {
+		if net.ParseIP(p.IP) == nil {
+			continue
+		}
+		for _, cp := range p.Ports {
+			if cp.Protocol != "TCP" || seen[cp.Port] || len(targets) == maxIsolationProbeTargets {
+				continue
+			}
+			seen[cp.Port] = true
+			targets = append(targets, fmt.Sprintf("%s:%d", p.IP, cp.Port))
+		}
+	}
+	return targets
+}
+
+// isolationProbeScript tries a TCP connection to each ip:port target and
+// prints OPEN or CLOSED per target.
+func isolationProbeScript(targets []string) string {
+	return fmt.Sprintf(`for t in %s; do h=${t%%:*}; p=${t##*:}; if nc -z -w 3 "$h" "$p" 2>/dev/null; then echo "OPEN $h $p"; else echo "CLOSED $h $p"; fi; done`,
+		strings.Join(targets, " "))
+}
+
+// parseIsolationProbe returns the targets the probe reached and how many it
+// tried.
+func parseIsolationProbe(output string) (open []string, tried int) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		switch fields[0] {
+		case "OPEN":
+			open = append(open, fields[1]+":"+fields[2])
+			tried++
+		case "CLOSED":
+			tried++
+		}
+	}
+	return open, tried
+}
+
+// --- verify_tenant_isolation ---
+
+type VerifyTenantIsolationTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *VerifyTenantIsolationTool) Name() string { return "verify_tenant_isolation" }
+func (t *VerifyTenantIsolationTool) Description() string {
+	return "Verify that no traffic path exists between two tenants, each a namespace or a namespace label selector: evaluates the NetworkPolicies for every pod pair in both directions and per destination port, optionally confirms with live TCP probes, and returns a PASS/FAIL verdict with the policy evidence for each direction and port"
+}
+func (t *VerifyTenantIsolationTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"tenant_a": map[string]interface{}{
+				"type":        "string",
+				"description": "First tenant: a namespace name or a namespace label selector (e.g. tenant=acme)",
+			},
+			"tenant_b": map[string]interface{}{
+				"type":        "string",
+				"description": "Second tenant: a namespace name or a namespace label selector",
+			},
+			"probe": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also deploy an ephemeral pod in each tenant and try TCP connections to the other tenant's pods (default: false)",
+			},
+		},
+		"required": []string{"tenant_a", "tenant_b"},
+	}
+}
+
+// resolveTenant returns the namespaces a tenant argument names: one
+// namespace, or those matching a label selector.
+func (t *VerifyTenantIsolationTool) resolveTenant(arg, value string, nsLabels map[string]map[string]string) (isolationTenant, error) {
+	tenant := isolationTenant{label: value}
+	if strings.ContainsAny(value, "=!,() ") {
+		sel, err := labels.Parse(value)
+		if err != nil {
+			return tenant, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("invalid %s selector %q", arg, value), Detail: err.Error()}
+		}
+		for ns, l := range nsLabels {
+			if sel.Matches(labels.Set(l)) {
+				tenant.namespaces = append(tenant.namespaces, ns)
+			}
+		}
+		sort.Strings(tenant.namespaces)
+		if len(tenant.namespaces) == 0 {
+			return tenant, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("%s selector %q matches no namespace", arg, value)}
+		}
+		return tenant, nil
+	}
+	if _, ok := nsLabels[value]; !ok {
+		return tenant, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("%s namespace %q not found", arg, value)}
+	}
+	tenant.namespaces = []string{value}
+	return tenant, nil
+}
+
+func (t *VerifyTenantIsolationTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	argA := getStringArg(args, "tenant_a", "")
+	argB := getStringArg(args, "tenant_b", "")
+	if argA == "" || argB == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "tenant_a and tenant_b are required"}
+	}
+	probe, _ := args["probe"].(bool)
+
+	nsList, err := t.listResource(ctx, namespacesGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	nsLabels := make(map[string]map[string]string, len(nsList.Items))
+	for _, item := range nsList.Items {
+		nsLabels[item.GetName()] = item.GetLabels()
+	}
+	a, err := t.resolveTenant("tenant_a", argA, nsLabels)
+	if err != nil {
+		return nil, err
+	}
+	b, err := t.resolveTenant("tenant_b", argB, nsLabels)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range a.namespaces {
+		if containsString(b.namespaces, ns) {
+			return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: fmt.Sprintf("namespace %s belongs to both tenants", ns)}
+		}
+	}
+
+	podList, err := t.listResource(ctx, podsGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+		hostNetwork, _, _ := unstructured.NestedBool(pod.Object, "spec", "hostNetwork")
+		if phase == "Succeeded" || phase == "Failed" || hostNetwork {
+			// NetworkPolicies do not apply to host network pods.
+			continue
+		}
+		switch {
+		case containsString(a.namespaces, pod.GetNamespace()):
+			a.pods = append(a.pods, podPortsFrom(pod))
+		case containsString(b.namespaces, pod.GetNamespace()):
+			b.pods = append(b.pods, podPortsFrom(pod))
+		}
+	}
+	policyList, err := t.listResource(ctx, networkPoliciesGVR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networkpolicies: %w", err)
+	}
+
+	var findings []types.DiagnosticFinding
+	broken := 0
+	directions := [][2]isolationTenant{{a, b}, {b, a}}
+	for d, dir := range directions {
+		src, dst := dir[0], dir[1]
+		if len(dst.pods) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryPolicy,
+				Summary:  fmt.Sprintf("%s -> %s: %s has no pods to reach, so this direction cannot be evaluated", src.label, dst.label, dst.label),
+			})
+			continue
+		}
+		results, err := evaluateIsolation(policyList.Items, src, dst, nsLabels)
+		if err != nil {
+			return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "invalid NetworkPolicy selector", Detail: err.Error()}
+		}
+		for _, r := range results {
+			if r.allowed > 0 {
+				broken++
+			}
+		}
+		findings = append(findings, isolationFindings(src, dst, results)...)
+
+		if probe {
+			// Each direction is one probe; report directions, not probe phases.
+			progress.Report(ctx, d, len(directions), fmt.Sprintf("probing %s -> %s", src.label, dst.label))
+			probeFindings, connected, err := t.probeDirection(progress.Without(ctx), src, dst)
+			if err != nil {
+				return nil, err
+			}
+			broken += connected
+			findings = append(findings, probeFindings...)
+		}
+	}
+
+	findings = append(findings, t.unevaluatedPolicyFindings(ctx, append(append([]string(nil), a.namespaces...), b.namespaces...))...)
+
+	verdict := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryPolicy,
+		Summary:  fmt.Sprintf("PASS: no traffic path between %s and %s", a.label, b.label),
+		Detail:   fmt.Sprintf("%s: namespaces %s, %d pod(s); %s: namespaces %s, %d pod(s)", a.label, strings.Join(a.namespaces, ","), len(a.pods), b.label, strings.Join(b.namespaces, ","), len(b.pods)),
+	}
+	if broken > 0 {
+		verdict.Severity = types.SeverityCritical
+		verdict.Code = types.CodeTenantIsolationBroken
+		verdict.Summary = fmt.Sprintf("FAIL: %d traffic path(s) between %s and %s", broken, a.label, b.label)
+		verdict.Suggestion = "See the findings below for the direction, port and policy rule of each path."
+	}
+	findings = append([]types.DiagnosticFinding{verdict}, findings...)
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, strings.Join(append(append([]string(nil), a.namespaces...), b.namespaces...), ","), ""), nil
+}
+
+// probeDirection tries TCP connections from an unlabeled pod in the first
+// namespace of src to one pod per declared TCP port of dst. It returns the
+// findings and how many connections succeeded.
+func (t *VerifyTenantIsolationTool) probeDirection(ctx context.Context, src, dst isolationTenant) ([]types.DiagnosticFinding, int, error) {
+	targets := isolationProbeTargets(dst)
+	if len(targets) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("%s -> %s: no pod of %s declares a TCP port with an IP to probe", src.label, dst.label, dst.label),
+		}}, 0, nil
+	}
+	sourceNS := src.namespaces[0]
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:      probes.ProbeTypeIsolation,
+		Namespace: sourceNS,
+		Command:   []string{"sh", "-c", isolationProbeScript(targets)},
+		Timeout:   time.Duration(len(targets)*4+30) * time.Second,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var findings []types.DiagnosticFinding
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+	open, tried := parseIsolationProbe(result.Output)
+	switch {
+	case tried == 0:
+		detail := strings.TrimSpace(result.Output)
+		if result.Error != "" {
+			detail = result.Error + "; " + detail
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeProbeTCPFailed,
+			Summary:  fmt.Sprintf("%s -> %s: the isolation probe in %s did not run", src.label, dst.label, sourceNS),
+			Detail:   detail,
+		})
+	case len(open) > 0:
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeTenantProbeConnected,
+			Summary:    fmt.Sprintf("%s -> %s: a probe pod in %s connected to %d of %d target(s)", src.label, dst.label, sourceNS, len(open), tried),
+			Detail:     "connected: " + strings.Join(open, ", "),
+			Suggestion: "If the NetworkPolicy findings report this path as blocked, the CNI does not enforce NetworkPolicies (e.g. Flannel alone) or another policy engine allows it.",
+		})
+	default:
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("%s -> %s: a probe pod in %s could not connect to any of %d target(s)", src.label, dst.label, sourceNS, tried),
+			Detail:   "The probe pod is unlabeled, so policies allowing specific source labels are not exercised.",
+		})
+	}
+	return findings, len(open), nil
+}
+
+// unevaluatedPolicyFindings warns when policy engines other than Kubernetes
+// NetworkPolicy select pods of the tenants: they may allow traffic the
+// verdict does not account for.
+func (t *VerifyTenantIsolationTool) unevaluatedPolicyFindings(ctx context.Context, namespaces []string) []types.DiagnosticFinding {
+	var found []string
+	count := func(kind string, list *unstructured.UnstructuredList) {
+		n := 0
+		for _, item := range list.Items {
+			if item.GetNamespace() == "" || containsString(namespaces, item.GetNamespace()) {
+				n++
+			}
+		}
+		if n > 0 {
+			found = append(found, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	for _, src := range []struct {
+		kind string
+		gvr  schema.GroupVersionResource
+	}{
+		{"CiliumNetworkPolicy", ciliumNPGVR},
+		{"CiliumClusterwideNetworkPolicy", ciliumCNPGVR},
+		{"Calico NetworkPolicy", calicoNPGVR},
+		{"Calico GlobalNetworkPolicy", calicoGNPGVR},
+	} {
+		if list, err := t.listResource(ctx, src.gvr, ""); err == nil {
+			count(src.kind, list)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityWarning,
+		Category:   types.CategoryPolicy,
+		Code:       types.CodeTenantPoliciesNotEvaluated,
+		Summary:    fmt.Sprintf("%s apply to the tenants and were not evaluated", strings.Join(found, ", ")),
+		Suggestion: "These policies can allow traffic the NetworkPolicy verdict reports as blocked; rerun with probe=true to confirm.",
+	}}
+}
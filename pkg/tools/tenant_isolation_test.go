@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestRulePortsMatch(t *testing.T) {
+	dst := podPorts{Ports: []containerPort{{Name: "http", Port: 8080, Protocol: "TCP"}}}
+	rule := func(ports ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"ports": ports}
+	}
+	cases := []struct {
+		name string
+		rule map[string]interface{}
+		port containerPort
+		want bool
+	}{
+		{"no ports", map[string]interface{}{}, containerPort{Port: 0, Protocol: "TCP"}, true},
+		{"numeric", rule(map[string]interface{}{"port": int64(8080)}), containerPort{Port: 8080, Protocol: "TCP"}, true},
+		{"other protocol", rule(map[string]interface{}{"port": int64(8080), "protocol": "UDP"}), containerPort{Port: 8080, Protocol: "TCP"}, false},
+		{"named", rule(map[string]interface{}{"port": "http"}), containerPort{Port: 8080, Protocol: "TCP"}, true},
+		{"range", rule(map[string]interface{}{"port": int64(8000), "endPort": int64(9000)}), containerPort{Port: 8080, Protocol: "TCP"}, true},
+		{"undeclared port", rule(map[string]interface{}{"port": int64(8080)}), containerPort{Port: 0, Protocol: "TCP"}, false},
+		{"protocol only", rule(map[string]interface{}{"protocol": "TCP"}), containerPort{Port: 0, Protocol: "TCP"}, true},
+	}
+	for _, c := range cases {
+		if got := rulePortsMatch(c.rule, c.port, dst); got != c.want {
+			t.Errorf("%s: rulePortsMatch = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func tenantPod(ns, name, ip string, lbls map[string]string, port int64) *unstructured.Unstructured {
+	pod := ipamObj("v1", "Pod", ns, name, map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{
+			"name":  "app",
+			"ports": []interface{}{map[string]interface{}{"name": "http", "containerPort": port, "protocol": "TCP"}},
+		}}},
+		"status": map[string]interface{}{"phase": "Running", "podIP": ip},
+	})
+	pod.SetLabels(lbls)
+	return pod
+}
+
+func TestVerifyTenantIsolation(t *testing.T) {
+	acme := ipamObj("v1", "Namespace", "", "acme", nil)
+	acme.SetLabels(map[string]string{"tenant": "acme"})
+	globex := ipamObj("v1", "Namespace", "", "globex", nil)
+	globex.SetLabels(map[string]string{"tenant": "globex"})
+
+	// globex denies all ingress except from monitoring; acme only isolates
+	// ingress to its web pods, so globex can reach acme's api pods.
+	objs := []runtime.Object{
+		acme, globex,
+		tenantPod("acme", "web-1", "10.0.1.10", map[string]string{"app": "web"}, 8080),
+		tenantPod("acme", "api-1", "10.0.1.11", map[string]string{"app": "api"}, 9090),
+		tenantPod("globex", "shop-1", "10.0.2.10", map[string]string{"app": "shop"}, 8080),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "globex", "default-deny", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{},
+				"policyTypes": []interface{}{"Ingress"},
+				"ingress": []interface{}{map[string]interface{}{
+					"from": []interface{}{map[string]interface{}{"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"purpose": "monitoring"}}}},
+				}},
+			},
+		}),
+		ipamObj("networking.k8s.io/v1", "NetworkPolicy", "acme", "web", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"ingress": []interface{}{map[string]interface{}{
+					"from": []interface{}{map[string]interface{}{"podSelector": map[string]interface{}{}}},
+				}},
+			},
+		}),
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		namespacesGVR: "NamespaceList", podsGVR: "PodList", networkPoliciesGVR: "NetworkPolicyList",
+		ciliumNPGVR: "CiliumNetworkPolicyList", ciliumCNPGVR: "CiliumClusterwideNetworkPolicyList",
+		calicoNPGVR: "NetworkPolicyList", calicoGNPGVR: "GlobalNetworkPolicyList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	tool := &VerifyTenantIsolationTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"tenant_a": "tenant=acme", "tenant_b": "globex"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	var summaries []string
+	for _, f := range findings {
+		summaries = append(summaries, f.Summary)
+	}
+	joined := strings.Join(summaries, "\n")
+
+	if findings[0].Code != types.CodeTenantIsolationBroken || !strings.HasPrefix(findings[0].Summary, "FAIL") {
+		t.Fatalf("expected a FAIL verdict first, got %+v", findings[0])
+	}
+	for _, want := range []string{
+		"globex -> tenant=acme on 9090/TCP: allowed for 2 of 2 pod pair(s)",
+		"globex -> tenant=acme on 8080/TCP: blocked for all 2 pod pair(s)",
+		"tenant=acme -> globex on 8080/TCP: blocked for all 3 pod pair(s)",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+	for _, f := range findings {
+		if strings.HasPrefix(f.Summary, "globex -> tenant=acme on 9090/TCP") && !strings.Contains(f.Detail, "ingress: no NetworkPolicy isolates acme/api-1") {
+			t.Errorf("unexpected evidence %q", f.Detail)
+		}
+	}
+
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"tenant_a": "acme", "tenant_b": "tenant in (acme,globex)"}); err == nil {
+		t.Error("expected an error for overlapping tenants")
+	}
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"tenant_a": "acme", "tenant_b": "initech"}); err == nil {
+		t.Error("expected an error for a missing namespace")
+	}
+}
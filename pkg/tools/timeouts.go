@@ -0,0 +1,150 @@
+package tools
+
+import "time"
+
+// Tool classes, each with its own execution timeout.
+const (
+	ToolClassRead  = "read"
+	ToolClassScan  = "scan"
+	ToolClassProbe = "probe"
+)
+
+// ClassifiedTool is implemented by tools, e.g. from third-party providers,
+// that declare their class. Built-in tools are listed in scanTools and
+// probeClassTools; all others are read tools.
+type ClassifiedTool interface {
+	ToolClass() string
+}
+
+// scanTools analyse many resource types or the whole cluster.
+var scanTools = map[string]bool{
+	"quick_scan":                   true,
+	"scan_gateway_misconfigs":      true,
+	"check_gateway_conformance":    true,
+	"validate_istio_config":        true,
+	"validate_cilium_policy":       true,
+	"check_cilium_clustermesh":     true,
+	"validate_kgateway_resource":   true,
+	"validate_manifests":           true,
+	"analyze_istio_authpolicy":     true,
+	"analyze_istio_routing":        true,
+	"check_istio_mtls":             true,
+	"check_sidecar_injection":      true,
+	"check_dataplane_health":       true,
+	"audit_tls_policy":             true,
+	"audit_egress":                 true,
+	"analyze_external_exposure":    true,
+	"estimate_blast_radius":        true,
+	"build_service_graph":          true,
+	"analyze_change_impact":        true,
+	"check_networkpolicy_ports":    true,
+	"check_mtu_consistency":        true,
+	"analyze_ipam":                 true,
+	"diff_network_config":          true,
+	"export_service_catalog":       true,
+	"check_openapi_route_coverage": true,
+	"check_rate_limit_policies":    true,
+	"analyze_rate_limits":          true,
+	"detect_hostname_conflicts":    true,
+	"check_certificate_sni":        true,
+	"check_provider_health":        true,
+	"check_permissions":            true,
+	"recommend_scaling":            true,
+	"get_proxy_logs":               true,
+	"get_gateway_logs":             true,
+	"get_infra_logs":               true,
+	"analyze_log_errors":           true,
+	"check_proxy_resources":        true,
+	"find_failing_traces":          true,
+	"check_outlier_detection":      true,
+}
+
+// probeClassTools deploy probe pods, some of them only on request, or, like
+// run_skill, may run tools that do.
+var probeClassTools = map[string]bool{
+	"probe_connectivity":       true,
+	"probe_dns":                true,
+	"probe_http":               true,
+	"probe_latency":            true,
+	"check_mtu":                true,
+	"capture_traffic":          true,
+	"inspect_connections":      true,
+	"verify_tenant_isolation":  true,
+	"verify_traffic_policies":  true,
+	"verify_route_programming": true,
+	"check_flannel_status":     true,
+	"run_skill":                true,
+}
+
+// ClassOf returns the class of t.
+func ClassOf(t Tool) string {
+	if ct, ok := t.(ClassifiedTool); ok {
+		return ct.ToolClass()
+	}
+	switch {
+	case probeClassTools[t.Name()]:
+		return ToolClassProbe
+	case scanTools[t.Name()]:
+		return ToolClassScan
+	default:
+		return ToolClassRead
+	}
+}
+
+// mutatingTools change cluster or server state.
+var mutatingTools = map[string]bool{
+	"suppress_finding":     true,
+	"refresh_capabilities": true,
+}
+
+// SharesResults reports whether identical calls of t may be served by one
+// run: read and scan tools that neither change state nor deploy probe pods.
+func SharesResults(t Tool) bool {
+	if mutatingTools[t.Name()] || probeTools[t.Name()] {
+		return false
+	}
+	class := ClassOf(t)
+	return class == ToolClassRead || class == ToolClassScan
+}
+
+// probePodTimeouts are the longest probe pods of tools, with the largest
+// arguments they accept. A tool's timeout leaves at least probePodMargin
+// past its pod, whatever its class timeout.
+var probePodTimeouts = map[string]time.Duration{
+	"probe_latency":            latencyProbePodTimeout(latencyProbeMaxTimeout),
+	"verify_traffic_policies":  latencyProbePodTimeout(latencyProbeMaxTimeout),
+	"check_admission_webhooks": latencyProbePodTimeout(latencyProbeMaxTimeout),
+	"verify_route_programming": routeProbePodTimeout(routeProbeMaxRules),
+	"capture_traffic":          capturePodTimeout(captureMaxDuration),
+}
+
+const probePodMargin = 30 * time.Second
+
+// TimeoutPolicy is how long a tool call may run before it is cancelled.
+type TimeoutPolicy struct {
+	Read, Scan, Probe time.Duration
+	// Overrides sets the timeout of individual tools by name.
+	Overrides map[string]time.Duration
+}
+
+// For returns the timeout of t and the class (or "override") it came from.
+// A class timeout too short for the tool's probe pod is raised to fit it.
+func (p TimeoutPolicy) For(t Tool) (time.Duration, string) {
+	if d, ok := p.Overrides[t.Name()]; ok {
+		return d, "override"
+	}
+	class := ClassOf(t)
+	var d time.Duration
+	switch class {
+	case ToolClassProbe:
+		d = p.Probe
+	case ToolClassScan:
+		d = p.Scan
+	default:
+		d = p.Read
+	}
+	if pod, ok := probePodTimeouts[t.Name()]; ok && d < pod+probePodMargin {
+		d = pod + probePodMargin
+	}
+	return d, class
+}
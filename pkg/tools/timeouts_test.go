@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+type classifiedStub struct{ ListServicesTool }
+
+func (*classifiedStub) ToolClass() string { return ToolClassProbe }
+
+func TestTimeoutPolicy(t *testing.T) {
+	p := TimeoutPolicy{
+		Read:      10 * time.Second,
+		Scan:      time.Minute,
+		Probe:     2 * time.Minute,
+		Overrides: map[string]time.Duration{"get_service": 3 * time.Second},
+	}
+	cases := []struct {
+		tool  Tool
+		want  time.Duration
+		class string
+	}{
+		{&ListServicesTool{}, 10 * time.Second, ToolClassRead},
+		{&QuickScanTool{}, time.Minute, ToolClassScan},
+		{&ProbeDNSTool{}, 2 * time.Minute, ToolClassProbe},
+		{&VerifyTenantIsolationTool{}, 2 * time.Minute, ToolClassProbe},
+		{&GetServiceTool{}, 3 * time.Second, "override"},
+		{&classifiedStub{}, 2 * time.Minute, ToolClassProbe},
+	}
+	for _, c := range cases {
+		got, class := p.For(c.tool)
+		if got != c.want || class != c.class {
+			t.Errorf("%s: got %s (%s), want %s (%s)", c.tool.Name(), got, class, c.want, c.class)
+		}
+	}
+}
+
+func TestTimeoutPolicyOutlastsProbePods(t *testing.T) {
+	// The defaults of TOOL_TIMEOUT, TOOL_TIMEOUT_SCAN and TOOL_TIMEOUT_PROBE.
+	p := TimeoutPolicy{Read: 10 * time.Second, Scan: time.Minute, Probe: 2 * time.Minute}
+	// The longest probe pod of each tool, with the largest arguments.
+	cases := []struct {
+		tool Tool
+		pod  time.Duration
+	}{
+		{&ProbeConnectivityTool{}, 30 * time.Second},
+		{&ProbeDNSTool{}, 30 * time.Second},
+		{&ProbeHTTPTool{}, 30 * time.Second},
+		{&ProbeLatencyTool{}, latencyProbePodTimeout(30)},
+		{&VerifyTrafficPoliciesTool{}, latencyProbePodTimeout(30)},
+		{&CheckAdmissionWebhooksTool{}, latencyProbePodTimeout(30)},
+		{&VerifyRouteProgrammingTool{}, routeProbePodTimeout(50)},
+		{&CaptureTrafficTool{}, capturePodTimeout(captureMaxDuration)},
+		{&InspectConnectionsTool{}, time.Minute},
+		{&CheckMTUTool{}, 90 * time.Second},
+		{&VerifyTenantIsolationTool{}, time.Duration(maxIsolationProbeTargets*4+30) * time.Second},
+		{&CheckFlannelStatusTool{}, 30 * time.Second},
+	}
+	for _, c := range cases {
+		if got, _ := p.For(c.tool); got < c.pod+probePodMargin {
+			t.Errorf("%s: timeout %s does not outlast its %s probe pod", c.tool.Name(), got, c.pod)
+		}
+	}
+
+	// An override still wins.
+	p.Overrides = map[string]time.Duration{"probe_latency": time.Minute}
+	if got, class := p.For(&ProbeLatencyTool{}); got != time.Minute || class != "override" {
+		t.Errorf("override: got %s (%s)", got, class)
+	}
+}
@@ -0,0 +1,435 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+var (
+	istioGatewayV1GVR   = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1", Resource: "gateways"}
+	istioGatewayV1B1GVR = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "gateways"}
+	clientTrafficPolGVR = schema.GroupVersionResource{Group: "gateway.envoyproxy.io", Version: "v1alpha1", Resource: "clienttrafficpolicies"}
+)
+
+// TLS protocol versions, ordered.
+const (
+	tls10 = 10
+	tls11 = 11
+	tls12 = 12
+	tls13 = 13
+)
+
+// tlsProfile is a TLS policy: the lowest accepted protocol version and the
+// accepted TLS 1.2 cipher suites, in OpenSSL names. TLS 1.3 suites are not
+// configurable in Envoy or nginx and are not checked.
+type tlsProfile struct {
+	Name       string
+	MinVersion int
+	Ciphers    map[string]bool
+}
+
+func cipherSet(names ...string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
+
+// tlsProfiles follow the Mozilla server-side TLS recommendations and, for
+// fips, the NIST SP 800-52r2 approved suites without ChaCha20.
+var tlsProfiles = map[string]tlsProfile{
+	config.TLSProfileIntermediate: {
+		Name:       config.TLSProfileIntermediate,
+		MinVersion: tls12,
+		Ciphers: cipherSet(
+			"ECDHE-ECDSA-AES128-GCM-SHA256", "ECDHE-RSA-AES128-GCM-SHA256",
+			"ECDHE-ECDSA-AES256-GCM-SHA384", "ECDHE-RSA-AES256-GCM-SHA384",
+			"ECDHE-ECDSA-CHACHA20-POLY1305", "ECDHE-RSA-CHACHA20-POLY1305",
+			"DHE-RSA-AES128-GCM-SHA256", "DHE-RSA-AES256-GCM-SHA384", "DHE-RSA-CHACHA20-POLY1305",
+		),
+	},
+	config.TLSProfileModern: {
+		Name:       config.TLSProfileModern,
+		MinVersion: tls13,
+		Ciphers:    cipherSet(),
+	},
+	config.TLSProfileFIPS: {
+		Name:       config.TLSProfileFIPS,
+		MinVersion: tls12,
+		Ciphers: cipherSet(
+			"ECDHE-ECDSA-AES128-GCM-SHA256", "ECDHE-RSA-AES128-GCM-SHA256",
+			"ECDHE-ECDSA-AES256-GCM-SHA384", "ECDHE-RSA-AES256-GCM-SHA384",
+			"AES128-GCM-SHA256", "AES256-GCM-SHA384",
+		),
+	},
+}
+
+// parseTLSVersion reads the version spellings used by Istio (TLSV1_2),
+// Envoy Gateway (1.2) and nginx (TLSv1.2). ok is false for "auto" and
+// unknown values.
+func parseTLSVersion(s string) (int, bool) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	v = strings.TrimPrefix(v, "TLSV")
+	v = strings.ReplaceAll(v, "_", ".")
+	switch v {
+	case "1", "1.0":
+		return tls10, true
+	case "1.1":
+		return tls11, true
+	case "1.2":
+		return tls12, true
+	case "1.3":
+		return tls13, true
+	}
+	return 0, false
+}
+
+func tlsVersionName(v int) string {
+	return fmt.Sprintf("TLS %d.%d", v/10, v%10)
+}
+
+// normalizeCipher converts IANA names (TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+// to OpenSSL names (ECDHE-RSA-AES128-GCM-SHA256).
+func normalizeCipher(c string) string {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	kx, cipher, ok := strings.Cut(strings.TrimPrefix(c, "TLS_"), "_WITH_")
+	if !strings.HasPrefix(c, "TLS_") || !ok {
+		return c
+	}
+	cipher = strings.NewReplacer("AES_128_", "AES128_", "AES_256_", "AES256_", "CHACHA20_POLY1305_SHA256", "CHACHA20_POLY1305").Replace(cipher)
+	cipher = strings.ReplaceAll(cipher, "_", "-")
+	if kx == "RSA" {
+		return cipher
+	}
+	return strings.ReplaceAll(kx, "_", "-") + "-" + cipher
+}
+
+// splitCiphers splits an Envoy or OpenSSL cipher list. Envoy equal-preference
+// groups ("[A|B]") are flattened, and OpenSSL exclusions ("!aNULL") dropped.
+func splitCiphers(s string) []string {
+	var out []string
+	for _, c := range strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == ',' || r == ' ' || r == '|' }) {
+		c = strings.Trim(c, "[]")
+		if c == "" || strings.HasPrefix(c, "!") || strings.HasPrefix(c, "-") {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// tlsSetting is the TLS configuration of one listener, server or controller.
+type tlsSetting struct {
+	Ref        *types.ResourceRef
+	Where      string // e.g. "listener https" or "ConfigMap ssl-protocols"
+	MinVersion string // raw value; empty when unset
+	MaxVersion string
+	// Protocols lists every enabled version, for nginx-style settings.
+	Protocols []string
+	Ciphers   []string
+}
+
+// auditTLSSetting checks one setting against a profile.
+func auditTLSSetting(s tlsSetting, p tlsProfile) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	label := fmt.Sprintf("%s %s/%s %s", s.Ref.Kind, s.Ref.Namespace, s.Ref.Name, s.Where)
+	add := func(code types.FindingCode, sev, summary, detail, suggestion string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: sev, Category: types.CategoryTLS, Code: code, Resource: s.Ref,
+			Summary: summary, Detail: detail, Suggestion: suggestion,
+		})
+	}
+
+	// Lowest enabled version: min version, or the lowest listed protocol.
+	lowest, raw := 0, s.MinVersion
+	if v, ok := parseTLSVersion(s.MinVersion); ok {
+		lowest = v
+	}
+	for _, proto := range s.Protocols {
+		if v, ok := parseTLSVersion(proto); ok && (lowest == 0 || v < lowest) {
+			lowest, raw = v, proto
+		}
+	}
+	if v, ok := parseTLSVersion(s.MaxVersion); ok && v < p.MinVersion {
+		sev := types.SeverityWarning
+		if v < tls12 {
+			sev = types.SeverityCritical
+		}
+		add(types.CodeTLSBelowProfileVersion, sev, fmt.Sprintf("%s caps TLS at %s, below the %s profile minimum %s", label, tlsVersionName(v), p.Name, tlsVersionName(p.MinVersion)),
+			fmt.Sprintf("maxProtocolVersion=%s", s.MaxVersion),
+			"Raise or remove the maximum protocol version.")
+	}
+
+	switch {
+	case lowest != 0 && lowest < tls12:
+		add(types.CodeTLSDeprecatedVersion, types.SeverityCritical,
+			fmt.Sprintf("%s accepts %s", label, tlsVersionName(lowest)),
+			fmt.Sprintf("configured: %s", raw),
+			fmt.Sprintf("TLS 1.0 and 1.1 are deprecated (RFC 8996); set the minimum version to %s.", tlsVersionName(p.MinVersion)))
+	case lowest != 0 && lowest < p.MinVersion:
+		add(types.CodeTLSBelowProfileVersion, types.SeverityWarning,
+			fmt.Sprintf("%s accepts %s, below the %s profile minimum %s", label, tlsVersionName(lowest), p.Name, tlsVersionName(p.MinVersion)),
+			fmt.Sprintf("configured: %s", raw),
+			fmt.Sprintf("Set the minimum version to %s.", tlsVersionName(p.MinVersion)))
+	case lowest == 0 && s.MinVersion == "" && len(s.Protocols) == 0 && len(s.Ciphers) == 0:
+		add("", types.SeverityInfo, fmt.Sprintf("%s uses the implementation default TLS settings", label), "",
+			"Defaults change between versions; set the minimum version explicitly to make the policy auditable.")
+	}
+
+	var rejected []string
+	for _, c := range s.Ciphers {
+		n := normalizeCipher(c)
+		if strings.HasPrefix(n, "TLS_") {
+			continue // TLS 1.3 suite
+		}
+		if !p.Ciphers[n] {
+			rejected = append(rejected, c)
+		}
+	}
+	if len(rejected) > 0 && !(p.MinVersion == tls13 && lowest == tls13) {
+		add(types.CodeTLSCipherNotInProfile, types.SeverityWarning,
+			fmt.Sprintf("%s allows %d cipher suite(s) outside the %s profile", label, len(rejected), p.Name),
+			fmt.Sprintf("non-compliant: %s", truncateList(rejected, 8)),
+			"Restrict the cipher list to the profile's suites, e.g. ECDHE-ECDSA-AES128-GCM-SHA256 and ECDHE-RSA-AES128-GCM-SHA256.")
+	}
+
+	if len(findings) == 0 {
+		add("", types.SeverityOK, fmt.Sprintf("%s complies with the %s profile", label, p.Name), "", "")
+	}
+	return findings
+}
+
+// meshConfigTLSSettings reads the TLS settings of an Istio mesh config:
+// meshMTLS for workload mTLS and tlsDefaults for gateways and sidecars.
+func meshConfigTLSSettings(ref *types.ResourceRef, mesh string) []tlsSetting {
+	var cfg struct {
+		MeshMTLS struct {
+			MinProtocolVersion string `json:"minProtocolVersion"`
+		} `json:"meshMTLS"`
+		TLSDefaults struct {
+			MinProtocolVersion string   `json:"minProtocolVersion"`
+			CipherSuites       []string `json:"cipherSuites"`
+		} `json:"tlsDefaults"`
+	}
+	if err := yaml.Unmarshal([]byte(mesh), &cfg); err != nil {
+		return nil
+	}
+	var out []tlsSetting
+	if cfg.MeshMTLS.MinProtocolVersion != "" {
+		out = append(out, tlsSetting{Ref: ref, Where: "meshMTLS", MinVersion: cfg.MeshMTLS.MinProtocolVersion})
+	}
+	if cfg.TLSDefaults.MinProtocolVersion != "" || len(cfg.TLSDefaults.CipherSuites) > 0 {
+		out = append(out, tlsSetting{Ref: ref, Where: "tlsDefaults", MinVersion: cfg.TLSDefaults.MinProtocolVersion, Ciphers: cfg.TLSDefaults.CipherSuites})
+	}
+	return out
+}
+
+// istioGatewayTLSSettings reads the TLS servers of an Istio Gateway. Passthrough
+// servers do not terminate TLS and are skipped.
+func istioGatewayTLSSettings(gw *unstructured.Unstructured) []tlsSetting {
+	ref := &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+	servers, _, _ := unstructured.NestedSlice(gw.Object, "spec", "servers")
+	var out []tlsSetting
+	for i, s := range servers {
+		sm, _ := s.(map[string]interface{})
+		tls, ok := sm["tls"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mode, _ := tls["mode"].(string)
+		if mode == "PASSTHROUGH" || mode == "AUTO_PASSTHROUGH" {
+			continue
+		}
+		where := fmt.Sprintf("server[%d]", i)
+		if port, ok, _ := unstructured.NestedString(sm, "port", "name"); ok && port != "" {
+			where = "server " + port
+		}
+		st := tlsSetting{Ref: ref, Where: where}
+		st.MinVersion, _ = tls["minProtocolVersion"].(string)
+		st.MaxVersion, _ = tls["maxProtocolVersion"].(string)
+		st.Ciphers, _, _ = unstructured.NestedStringSlice(tls, "cipherSuites")
+		out = append(out, st)
+	}
+	return out
+}
+
+// gatewayListenerTLSSettings reads Gateway API listeners that terminate TLS.
+// Versions and ciphers are implementation-specific listener options; keys
+// mentioning "min" and "version", or "cipher", are read.
+func gatewayListenerTLSSettings(gw *unstructured.Unstructured) []tlsSetting {
+	ref := &types.ResourceRef{Kind: "Gateway", Namespace: gw.GetNamespace(), Name: gw.GetName(), APIVersion: gw.GetAPIVersion()}
+	listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	var out []tlsSetting
+	for _, l := range listeners {
+		lm, _ := l.(map[string]interface{})
+		tls, ok := lm["tls"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if mode, _ := tls["mode"].(string); mode == "Passthrough" {
+			continue
+		}
+		name, _ := lm["name"].(string)
+		st := tlsSetting{Ref: ref, Where: "listener " + name}
+		options, _, _ := unstructured.NestedStringMap(tls, "options")
+		for k, v := range options {
+			key := strings.ToLower(k)
+			switch {
+			case strings.Contains(key, "min") && strings.Contains(key, "version"):
+				st.MinVersion = v
+			case strings.Contains(key, "max") && strings.Contains(key, "version"):
+				st.MaxVersion = v
+			case strings.Contains(key, "cipher"):
+				st.Ciphers = splitCiphers(v)
+			}
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// clientTrafficPolicyTLSSetting reads an Envoy Gateway ClientTrafficPolicy,
+// which sets TLS for the Gateways or listeners it targets.
+func clientTrafficPolicyTLSSetting(ctp *unstructured.Unstructured) (tlsSetting, bool) {
+	tls, ok, _ := unstructured.NestedMap(ctp.Object, "spec", "tls")
+	if !ok {
+		return tlsSetting{}, false
+	}
+	st := tlsSetting{
+		Ref:   &types.ResourceRef{Kind: "ClientTrafficPolicy", Namespace: ctp.GetNamespace(), Name: ctp.GetName(), APIVersion: ctp.GetAPIVersion()},
+		Where: "spec.tls",
+	}
+	st.MinVersion, _ = tls["minVersion"].(string)
+	st.MaxVersion, _ = tls["maxVersion"].(string)
+	st.Ciphers, _, _ = unstructured.NestedStringSlice(tls, "ciphers")
+	return st, true
+}
+
+// nginxTLSSettings reads ingress-nginx ssl-protocols and ssl-ciphers from
+// the controller ConfigMap or Ingress annotations.
+func nginxTLSSettings(ref *types.ResourceRef, where, protocols, ciphers string) (tlsSetting, bool) {
+	if protocols == "" && ciphers == "" {
+		return tlsSetting{}, false
+	}
+	return tlsSetting{Ref: ref, Where: where, Protocols: strings.Fields(protocols), Ciphers: splitCiphers(ciphers)}, true
+}
+
+// --- audit_tls_policy ---
+
+type AuditTLSPolicyTool struct{ BaseTool }
+
+func (t *AuditTLSPolicyTool) Name() string { return "audit_tls_policy" }
+func (t *AuditTLSPolicyTool) Description() string {
+	return "Audit TLS minimum versions and cipher suites across Gateway API listeners, Envoy Gateway ClientTrafficPolicies, ingress-nginx, Istio Gateways and meshConfig against a policy profile (intermediate, modern or fips), flagging TLS 1.0/1.1 and non-compliant ciphers"
+}
+func (t *AuditTLSPolicyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace (empty for all namespaces)",
+			},
+			"profile": map[string]interface{}{
+				"type":        "string",
+				"description": "Policy profile: intermediate, modern or fips (default from TLS_POLICY_PROFILE)",
+			},
+		},
+	}
+}
+
+func (t *AuditTLSPolicyTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	profileName := strings.ToLower(getStringArg(args, "profile", orDefault(t.Cfg.TLSPolicyProfile, config.TLSProfileIntermediate)))
+	profile, ok := tlsProfiles[profileName]
+	if !ok {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: config.ValidateTLSProfile(profileName).Error(),
+		}
+	}
+
+	var settings []tlsSetting
+
+	if list, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns); err == nil {
+		for i := range list.Items {
+			settings = append(settings, gatewayListenerTLSSettings(&list.Items[i])...)
+		}
+	}
+	if list, err := t.listResource(ctx, clientTrafficPolGVR, ns); err == nil {
+		for i := range list.Items {
+			if st, ok := clientTrafficPolicyTLSSetting(&list.Items[i]); ok {
+				settings = append(settings, st)
+			}
+		}
+	}
+	if list, err := t.listResourceWithFallback(ctx, istioGatewayV1GVR, istioGatewayV1B1GVR, ns); err == nil {
+		for i := range list.Items {
+			settings = append(settings, istioGatewayTLSSettings(&list.Items[i])...)
+		}
+	}
+	if ingList, err := t.listResource(ctx, ingressGVR, ns); err == nil {
+		for i := range ingList.Items {
+			ing := &ingList.Items[i]
+			a := ing.GetAnnotations()
+			ref := &types.ResourceRef{Kind: "Ingress", Namespace: ing.GetNamespace(), Name: ing.GetName(), APIVersion: "networking.k8s.io/v1"}
+			if st, ok := nginxTLSSettings(ref, "annotations", "", a["nginx.ingress.kubernetes.io/ssl-ciphers"]); ok {
+				settings = append(settings, st)
+			}
+		}
+	}
+
+	// Controller-wide settings live in ConfigMaps outside the audited namespace.
+	if cmList, err := t.listResource(ctx, configmapsGVR, ""); err == nil {
+		for i := range cmList.Items {
+			cm := &cmList.Items[i]
+			data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+			ref := &types.ResourceRef{Kind: "ConfigMap", Namespace: cm.GetNamespace(), Name: cm.GetName(), APIVersion: "v1"}
+			switch {
+			case cm.GetNamespace() == istioRootNamespace && strings.HasPrefix(cm.GetName(), "istio") && data["mesh"] != "":
+				settings = append(settings, meshConfigTLSSettings(ref, data["mesh"])...)
+			case strings.Contains(cm.GetName(), "nginx"):
+				if st, ok := nginxTLSSettings(ref, "ssl-protocols/ssl-ciphers", data["ssl-protocols"], data["ssl-ciphers"]); ok {
+					settings = append(settings, st)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(settings, func(i, j int) bool {
+		a, b := settings[i].Ref, settings[j].Ref
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+
+	var findings []types.DiagnosticFinding
+	for _, s := range settings {
+		findings = append(findings, auditTLSSetting(s, profile)...)
+	}
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryTLS,
+			Summary:  "No TLS-terminating listeners or TLS settings found",
+		})
+	}
+
+	responseNs := ns
+	if responseNs == "" {
+		responseNs = "all"
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, responseNs, ""), nil
+}
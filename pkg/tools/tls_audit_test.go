@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	cases := map[string]int{"TLSV1_0": tls10, "TLSV1_2": tls12, "1.1": tls11, "1.3": tls13, "TLSv1.2": tls12, "TLSv1": tls10}
+	for in, want := range cases {
+		if got, ok := parseTLSVersion(in); !ok || got != want {
+			t.Errorf("parseTLSVersion(%q) = %d, %t", in, got, ok)
+		}
+	}
+	for _, in := range []string{"TLS_AUTO", "Auto", ""} {
+		if _, ok := parseTLSVersion(in); ok {
+			t.Errorf("parseTLSVersion(%q) should not resolve", in)
+		}
+	}
+}
+
+func TestNormalizeCipher(t *testing.T) {
+	cases := map[string]string{
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         "ECDHE-RSA-AES128-GCM-SHA256",
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": "ECDHE-ECDSA-CHACHA20-POLY1305",
+		"TLS_RSA_WITH_AES_256_GCM_SHA384":               "AES256-GCM-SHA384",
+		"ecdhe-rsa-aes256-gcm-sha384":                   "ECDHE-RSA-AES256-GCM-SHA384",
+		"TLS_AES_128_GCM_SHA256":                        "TLS_AES_128_GCM_SHA256",
+	}
+	for in, want := range cases {
+		if got := normalizeCipher(in); got != want {
+			t.Errorf("normalizeCipher(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSplitCiphers(t *testing.T) {
+	got := splitCiphers("[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]:AES128-SHA:!aNULL")
+	want := []string{"ECDHE-ECDSA-AES128-GCM-SHA256", "ECDHE-ECDSA-CHACHA20-POLY1305", "AES128-SHA"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitCiphers() = %v", got)
+	}
+}
+
+func TestAuditTLSSetting(t *testing.T) {
+	ref := &types.ResourceRef{Kind: "Gateway", Namespace: "infra", Name: "public"}
+	intermediate, fips, modern := tlsProfiles[config.TLSProfileIntermediate], tlsProfiles[config.TLSProfileFIPS], tlsProfiles[config.TLSProfileModern]
+
+	legacy := tlsSetting{Ref: ref, Where: "server https", MinVersion: "TLSV1_0", Ciphers: []string{"ECDHE-RSA-AES128-GCM-SHA256", "AES128-SHA"}}
+	findings := auditTLSSetting(legacy, intermediate)
+	if countSeverity(findings, types.SeverityCritical) != 1 || countSeverity(findings, types.SeverityWarning) != 1 {
+		t.Errorf("expected a critical version and a cipher warning, got %+v", findings)
+	}
+
+	chacha := tlsSetting{Ref: ref, Where: "listener https", MinVersion: "1.2", Ciphers: []string{"ECDHE-RSA-CHACHA20-POLY1305"}}
+	if f := auditTLSSetting(chacha, intermediate); len(f) != 1 || f[0].Severity != types.SeverityOK {
+		t.Errorf("expected compliance with intermediate, got %+v", f)
+	}
+	if f := auditTLSSetting(chacha, fips); countSeverity(f, types.SeverityWarning) != 1 {
+		t.Errorf("ChaCha20 is not FIPS-approved, got %+v", f)
+	}
+	if f := auditTLSSetting(chacha, modern); countSeverity(f, types.SeverityWarning) != 2 {
+		t.Errorf("expected version and cipher warnings for modern, got %+v", f)
+	}
+
+	nginx := tlsSetting{Ref: ref, Where: "ssl-protocols/ssl-ciphers", Protocols: []string{"TLSv1.1", "TLSv1.2"}}
+	if f := auditTLSSetting(nginx, intermediate); countSeverity(f, types.SeverityCritical) != 1 {
+		t.Errorf("expected TLS 1.1 in ssl-protocols to be critical, got %+v", f)
+	}
+
+	if f := auditTLSSetting(tlsSetting{Ref: ref, Where: "listener https"}, intermediate); f[0].Severity != types.SeverityInfo {
+		t.Errorf("expected an info finding for defaults, got %+v", f)
+	}
+}
+
+func TestMeshConfigTLSSettings(t *testing.T) {
+	mesh := "meshMTLS:\n  minProtocolVersion: TLSV1_3\ntlsDefaults:\n  minProtocolVersion: TLSV1_2\n  cipherSuites: [ECDHE-RSA-AES128-GCM-SHA256]\n"
+	got := meshConfigTLSSettings(&types.ResourceRef{Kind: "ConfigMap", Namespace: "istio-system", Name: "istio"}, mesh)
+	if len(got) != 2 || got[0].MinVersion != "TLSV1_3" || len(got[1].Ciphers) != 1 {
+		t.Errorf("meshConfigTLSSettings() = %+v", got)
+	}
+}
+
+func TestIstioGatewayTLSSettings(t *testing.T) {
+	gw := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ingress", "namespace": "istio-system"},
+		"spec": map[string]interface{}{"servers": []interface{}{
+			map[string]interface{}{"port": map[string]interface{}{"name": "https"}, "tls": map[string]interface{}{"mode": "SIMPLE", "minProtocolVersion": "TLSV1_1"}},
+			map[string]interface{}{"port": map[string]interface{}{"name": "tls"}, "tls": map[string]interface{}{"mode": "PASSTHROUGH"}},
+			map[string]interface{}{"port": map[string]interface{}{"name": "http"}},
+		}},
+	}}
+	got := istioGatewayTLSSettings(gw)
+	if len(got) != 1 || got[0].Where != "server https" || got[0].MinVersion != "TLSV1_1" {
+		t.Errorf("istioGatewayTLSSettings() = %+v", got)
+	}
+}
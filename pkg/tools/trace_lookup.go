@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/traces"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// spanStatusCode returns the HTTP status of a span, from the old or the
+// current OTel semantic conventions.
+func spanStatusCode(s traces.Span) string {
+	return orDefault(s.Attributes["http.response.status_code"], s.Attributes["http.status_code"])
+}
+
+// spanHop classifies the hop of the request path a span represents.
+func spanHop(s traces.Span) string {
+	name := strings.ToLower(s.Service + " " + s.Attributes["istio.canonical_service"])
+	switch {
+	case strings.Contains(name, "gateway"):
+		return "gateway"
+	case s.Attributes["component"] == "proxy" || s.Attributes["upstream_cluster"] != "":
+		return "sidecar"
+	}
+	return "application"
+}
+
+// upstreamService extracts the Service of an Istio upstream cluster such as
+// "outbound|80||reviews.bookinfo.svc.cluster.local".
+func upstreamService(cluster string) (ns, name string, ok bool) {
+	parts := strings.Split(cluster, "|")
+	if len(parts) != 4 || !strings.HasSuffix(parts[3], ".svc.cluster.local") {
+		return "", "", false
+	}
+	labels := strings.Split(strings.TrimSuffix(parts[3], ".svc.cluster.local"), ".")
+	if len(labels) != 2 {
+		return "", "", false
+	}
+	return labels[1], labels[0], true
+}
+
+// explainFlags explains each Envoy response flag of a span.
+func explainFlags(flags string) []string {
+	var out []string
+	for _, f := range strings.Split(flags, ",") {
+		if c, ok := responseFlagCauses[f]; ok {
+			out = append(out, f+": "+c)
+		}
+	}
+	return out
+}
+
+// failureGroup collects the traces whose failures start at the same hop with
+// the same status.
+type failureGroup struct {
+	service, name, hop string
+	status, flags      string
+	upstream           string
+	traceIDs           []string
+	path               []string
+}
+
+func (g *failureGroup) label() string {
+	var out []string
+	if g.status != "" {
+		out = append(out, "HTTP "+g.status)
+	}
+	if g.flags != "" && g.flags != "-" {
+		out = append(out, g.flags)
+	}
+	if len(out) == 0 {
+		return "error"
+	}
+	return strings.Join(out, " ")
+}
+
+// groupFailures groups the failure origins of traces, most frequent first.
+func groupFailures(trs []traces.Trace) []*failureGroup {
+	groups := make(map[string]*failureGroup)
+	for _, tr := range trs {
+		seen := make(map[string]bool)
+		for _, s := range tr.FailureOrigins() {
+			g := &failureGroup{
+				service:  s.Service,
+				name:     s.Name,
+				hop:      spanHop(s),
+				status:   spanStatusCode(s),
+				flags:    s.Attributes["response_flags"],
+				upstream: s.Attributes["upstream_cluster"],
+			}
+			key := strings.Join([]string{g.service, g.name, g.status, g.flags, g.upstream}, "\x00")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if existing, ok := groups[key]; ok {
+				g = existing
+			} else {
+				for _, p := range tr.Path(s) {
+					g.path = append(g.path, fmt.Sprintf("%s (%s)", p.Service, spanHop(p)))
+				}
+				groups[key] = g
+			}
+			g.traceIDs = append(g.traceIDs, tr.TraceID)
+		}
+	}
+	out := make([]*failureGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].traceIDs) != len(out[j].traceIDs) {
+			return len(out[i].traceIDs) > len(out[j].traceIDs)
+		}
+		return out[i].service+out[i].name < out[j].service+out[j].name
+	})
+	return out
+}
+
+// --- find_failing_traces ---
+
+type FindFailingTracesTool struct {
+	BaseTool
+	Traces *traces.Client
+}
+
+func (t *FindFailingTracesTool) Name() string { return "find_failing_traces" }
+func (t *FindFailingTracesTool) Description() string {
+	return "Fetch recent failing traces of a service from the trace backend (Tempo or Jaeger) and summarize where along the gateway, sidecar and application path the failures start, with HTTP status, Envoy response flags and the DestinationRules of the failing upstream"
+}
+func (t *FindFailingTracesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"service": map[string]interface{}{
+				"type":        "string",
+				"description": "Service name in the traces (service.name); Istio proxies report <app>.<namespace>",
+			},
+			"since": map[string]interface{}{
+				"type":        "string",
+				"description": "How far back to search, e.g. 15m or 2h (default 15m)",
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of traces to fetch (default 20, max 100)",
+			},
+		},
+		"required": []string{"service"},
+	}
+}
+
+func (t *FindFailingTracesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	service := getStringArg(args, "service", "")
+	if service == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "service is required",
+		}
+	}
+	since, err := time.ParseDuration(getStringArg(args, "since", "15m"))
+	if err != nil || since <= 0 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid since %q", getStringArg(args, "since", "")),
+			Detail:  "use a duration such as 15m or 2h",
+		}
+	}
+	limit := getIntArg(args, "limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	trs, err := t.Traces.FailingTraces(ctx, service, since, limit)
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInternalError,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to query %s: %v", t.Traces.Backend(), err),
+			Detail:  "check TRACES_URL and TRACES_BACKEND and that the server can reach the backend",
+		}
+	}
+
+	summary := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryConnectivity,
+		Summary:  fmt.Sprintf("No failing traces for %s in the last %s", service, since),
+		Detail:   fmt.Sprintf("backend=%s", t.Traces.Backend()),
+	}
+	if len(trs) == 0 {
+		summary.Suggestion = "Check the service name as the traces report it, and the sampling rate: failures in unsampled requests leave no trace."
+		return NewToolResultResponse(t.Cfg, t.Name(), []types.DiagnosticFinding{summary}, "", ""), nil
+	}
+
+	groups := groupFailures(trs)
+	summary.Severity = types.SeverityInfo
+	summary.Summary = fmt.Sprintf("%d failing trace(s) for %s in the last %s, failing at %d distinct hop(s)", len(trs), service, since, len(groups))
+	findings := []types.DiagnosticFinding{summary}
+
+	for _, g := range groups {
+		sev := types.SeverityWarning
+		if len(g.traceIDs)*2 >= len(trs) {
+			sev = types.SeverityCritical
+		}
+		f := types.DiagnosticFinding{
+			Severity: sev,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeObservabilityFailingTraces,
+			Summary:  fmt.Sprintf("%d of %d trace(s) fail at %s (%s) %q: %s", len(g.traceIDs), len(trs), g.service, g.hop, g.name, g.label()),
+			Detail:   fmt.Sprintf("path: %s; traces: %s", strings.Join(g.path, " -> "), truncateList(g.traceIDs, 5)),
+		}
+		if g.upstream != "" {
+			f.Detail += "; upstream_cluster=" + g.upstream
+		}
+
+		flags := explainFlags(g.flags)
+		var drs []string
+		if ns, name, ok := upstreamService(g.upstream); ok {
+			f.Resource = &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name, APIVersion: "v1"}
+			drs = t.serviceDestinationRules(ctx, ns, name)
+		}
+		switch {
+		case strings.Contains(g.flags, "NR"):
+			f.Suggestion = "No route matched the request at this hop; run triage_404 with the request host and path."
+		case len(flags) > 0 || len(drs) > 0:
+			f.Suggestion = correlationSuggestion(flags, drs)
+		case g.hop == "application":
+			f.Suggestion = "The failure starts in the application's own span; check its logs for the listed traces."
+		default:
+			f.Suggestion = "Check the proxy access log of this hop for the listed traces (get_proxy_logs)."
+		}
+		findings = append(findings, f)
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, "", ""), nil
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/traces"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestUpstreamService(t *testing.T) {
+	if ns, name, ok := upstreamService("outbound|8080||cart.shop.svc.cluster.local"); !ok || ns != "shop" || name != "cart" {
+		t.Errorf("upstreamService() = %s, %s, %v", ns, name, ok)
+	}
+	for _, c := range []string{"", "inbound|8080||", "outbound|443||api.example.com", "kube_shop_cart_8080"} {
+		if _, _, ok := upstreamService(c); ok {
+			t.Errorf("upstreamService(%q): expected no Service", c)
+		}
+	}
+}
+
+func TestGroupFailures(t *testing.T) {
+	gateway := traces.Span{SpanID: "1", Service: "istio-ingressgateway.istio-system", Error: true}
+	cart := func(flags string) traces.Span {
+		return traces.Span{SpanID: "2", ParentID: "1", Service: "cart.shop", Name: "cart", Error: true,
+			Attributes: map[string]string{"response_flags": flags, "upstream_cluster": "outbound|8080||cart.shop.svc.cluster.local", "http.status_code": "503"}}
+	}
+	trs := []traces.Trace{
+		{TraceID: "a", Spans: []traces.Span{gateway, cart("UF")}},
+		{TraceID: "b", Spans: []traces.Span{gateway, cart("UF")}},
+		{TraceID: "c", Spans: []traces.Span{gateway, cart("UO")}},
+	}
+	groups := groupFailures(trs)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.flags != "UF" || len(g.traceIDs) != 2 || g.hop != "sidecar" || g.label() != "HTTP 503 UF" {
+		t.Errorf("unexpected first group %+v", g)
+	}
+	if strings.Join(g.path, " -> ") != "istio-ingressgateway.istio-system (gateway) -> cart.shop (sidecar)" {
+		t.Errorf("path = %v", g.path)
+	}
+}
+
+func TestFindFailingTraces_CorrelatesDestinationRule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"traceID":"t1","spans":[
+			{"spanID":"a","operationName":"ingress","startTime":10,"duration":30,"processID":"p1",
+			 "tags":[{"key":"error","value":true},{"key":"http.status_code","value":"503"}]},
+			{"spanID":"b","operationName":"cart.shop.svc.cluster.local:8080/*","references":[{"refType":"CHILD_OF","spanID":"a"}],"startTime":20,"duration":5,"processID":"p1",
+			 "tags":[{"key":"error","value":true},{"key":"component","value":"proxy"},{"key":"response_flags","value":"UF"},{"key":"upstream_cluster","value":"outbound|8080||cart.shop.svc.cluster.local"}]}],
+			"processes":{"p1":{"serviceName":"istio-ingressgateway.istio-system"}}}]}`))
+	}))
+	defer srv.Close()
+
+	svc := &unstructured.Unstructured{}
+	svc.SetAPIVersion("v1")
+	svc.SetKind("Service")
+	svc.SetNamespace("shop")
+	svc.SetName("cart")
+	dr := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"host": "cart.shop.svc.cluster.local"}}}
+	dr.SetAPIVersion("networking.istio.io/v1")
+	dr.SetKind("DestinationRule")
+	dr.SetNamespace("shop")
+	dr.SetName("cart-mtls")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{servicesGVR: "ServiceList", drV1GVR: "DestinationRuleList", drV1B1GVR: "DestinationRuleList"},
+		svc, dr)
+
+	tc, err := traces.NewClient("jaeger", srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := &FindFailingTracesTool{
+		BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}},
+		Traces:   tc,
+	}
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"service": "istio-ingressgateway.istio-system"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 2 {
+		t.Fatalf("expected a summary and one failure group, got %+v", findings)
+	}
+	f := findings[1]
+	if f.Severity != types.SeverityCritical || !strings.Contains(f.Summary, "1 of 1 trace(s) fail at istio-ingressgateway.istio-system (gateway)") {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	if f.Resource == nil || f.Resource.Name != "cart" || !strings.Contains(f.Suggestion, "shop/cart-mtls") || !strings.Contains(f.Suggestion, "mTLS mode mismatch") {
+		t.Errorf("expected the DestinationRule and UF cause, got %+v", f)
+	}
+
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"service": "cart", "since": "soon"}); err == nil {
+		t.Error("expected an error for an invalid since")
+	}
+}
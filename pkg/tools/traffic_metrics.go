@@ -0,0 +1,413 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// responseFlagCauses explains Envoy response flags (istio_requests_total's
+// response_flags label) in terms of the configuration that usually causes them.
+var responseFlagCauses = map[string]string{
+	"UH":   "no healthy upstream: no ready endpoints, or all ejected by DestinationRule outlierDetection",
+	"UF":   "upstream connection failure: mTLS mode mismatch (PeerAuthentication vs DestinationRule tls) or wrong targetPort",
+	"UO":   "upstream overflow: DestinationRule connectionPool limits (circuit breaking) reached",
+	"URX":  "retry limit exceeded: VirtualService/HTTPRoute retries exhausted against failing endpoints",
+	"NR":   "no route: no VirtualService/HTTPRoute rule matches the request host or path",
+	"UC":   "upstream connection termination: idle or keepalive timeout shorter on the server than in the proxy",
+	"UT":   "upstream request timeout: the route timeout is shorter than the backend's response time",
+	"RL":   "rate limited by a local or global rate limit policy",
+	"UAEX": "denied by external authorization",
+	"DC":   "downstream connection termination: the client closed the connection",
+}
+
+// describeFlags explains the response flags of 5xx traffic, largest rate first.
+func describeFlags(rates map[string]float64) []string {
+	flags := make([]string, 0, len(rates))
+	for f := range rates {
+		if f != "" && f != "-" {
+			flags = append(flags, f)
+		}
+	}
+	sort.Slice(flags, func(i, j int) bool { return rates[flags[i]] > rates[flags[j]] })
+	var out []string
+	for _, f := range flags {
+		var causes []string
+		for _, part := range strings.Split(f, ",") {
+			if c, ok := responseFlagCauses[part]; ok {
+				causes = append(causes, c)
+			}
+		}
+		line := fmt.Sprintf("%s (%.2f req/s)", f, rates[f])
+		if len(causes) > 0 {
+			line += ": " + strings.Join(causes, "; ")
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// errorRateSeverity grades a 5xx ratio against a threshold: critical at the
+// threshold, warning at a fifth of it.
+func errorRateSeverity(ratio, threshold float64) string {
+	switch {
+	case ratio >= threshold:
+		return types.SeverityCritical
+	case ratio >= threshold/5:
+		return types.SeverityWarning
+	}
+	return types.SeverityOK
+}
+
+// promWindow validates a rate window and renders it as a PromQL duration.
+func promWindow(s string) (string, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Minute {
+		return "", fmt.Errorf("invalid window %q: use a duration of at least 1m, e.g. 5m", s)
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds())), nil
+}
+
+// envoyClusterRegex matches the Envoy clusters of a Service: Istio's
+// "outbound|80||svc.ns.svc.cluster.local" and kgateway's "kube_ns_svc_80".
+func envoyClusterRegex(ns, svc string) string {
+	return fmt.Sprintf(`.*\\|%s\\.%s\\.svc\\..*|kube_%s_%s_.*`, svc, ns, ns, svc)
+}
+
+// serviceDestinationRules returns the DestinationRules whose host applies to
+// the Service, as "ns/name" references.
+func (b *BaseTool) serviceDestinationRules(ctx context.Context, ns, name string) []string {
+	drs, err := b.listResourceWithFallback(ctx, drV1GVR, drV1B1GVR, "")
+	if err != nil {
+		return nil
+	}
+	svc, err := b.listResource(ctx, servicesGVR, ns)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, dr := range drs.Items {
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		if containsString(destinationRuleHostServices(host, dr.GetNamespace(), svc.Items), ns+"/"+name) {
+			out = append(out, dr.GetNamespace()+"/"+dr.GetName())
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// correlationSuggestion points from the response flags of failing traffic to
+// the configuration that likely causes them.
+func correlationSuggestion(flags []string, destinationRules []string) string {
+	var parts []string
+	if len(flags) > 0 {
+		parts = append(parts, "Response flags: "+strings.Join(flags, " | ")+".")
+	}
+	if len(destinationRules) > 0 {
+		parts = append(parts, fmt.Sprintf("DestinationRule(s) %s apply to this service; review their trafficPolicy (tls, connectionPool, outlierDetection).", strings.Join(destinationRules, ", ")))
+	}
+	if len(parts) == 0 {
+		return "Check the backend's logs and readiness; 5xx without response flags come from the application itself."
+	}
+	return strings.Join(parts, " ")
+}
+
+var trafficWindowProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Rate window, e.g. 5m or 1h (default 5m)",
+}
+
+func metricsUnavailable(tool string, err error) error {
+	return &types.MCPError{
+		Code:    types.ErrCodeInternalError,
+		Tool:    tool,
+		Message: fmt.Sprintf("failed to query Prometheus: %v", err),
+		Detail:  "check PROMETHEUS_URL and that the server can reach it",
+	}
+}
+
+// --- query_service_traffic ---
+
+type QueryServiceTrafficTool struct {
+	BaseTool
+	Prometheus *prometheus.Client
+}
+
+func (t *QueryServiceTrafficTool) Name() string { return "query_service_traffic" }
+func (t *QueryServiceTrafficTool) Description() string {
+	return "Query Prometheus for a Service's live traffic: request rate, 5xx ratio by response code and Envoy response flag, and p99 latency, from Istio or Envoy metrics, with the configuration that likely causes the errors"
+}
+func (t *QueryServiceTrafficTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"service":   map[string]interface{}{"type": "string", "description": "Service name"},
+			"namespace": map[string]interface{}{"type": "string", "description": "Service namespace"},
+			"window":    trafficWindowProperty,
+		},
+		"required": []string{"service", "namespace"},
+	}
+}
+
+func (t *QueryServiceTrafficTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	name := getStringArg(args, "service", "")
+	ns := getStringArg(args, "namespace", "")
+	if name == "" || ns == "" {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "service and namespace are required"}
+	}
+	window, err := promWindow(getStringArg(args, "window", "5m"))
+	if err != nil {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: err.Error()}
+	}
+	ref := &types.ResourceRef{Kind: "Service", Namespace: ns, Name: name}
+	p := t.Prometheus
+
+	sel := p.Selector(`reporter="destination"`, prometheus.Equal("destination_service_namespace", ns), prometheus.Equal("destination_service_name", name))
+	samples, err := p.Query(ctx, fmt.Sprintf(`sum by (response_code, response_flags) (rate(istio_requests_total%s[%s]))`, sel, window))
+	if err != nil {
+		return nil, metricsUnavailable(t.Name(), err)
+	}
+	source := "istio_requests_total"
+	var total, errors float64
+	codes := map[string]float64{}
+	flags := map[string]float64{}
+	for _, s := range samples {
+		total += s.Value
+		code := s.Labels["response_code"]
+		codes[code] += s.Value
+		if strings.HasPrefix(code, "5") {
+			errors += s.Value
+			flags[s.Labels["response_flags"]] += s.Value
+		}
+	}
+
+	if len(samples) == 0 {
+		source = "envoy_cluster_upstream_rq_xx"
+		sel = p.Selector(fmt.Sprintf(`envoy_cluster_name=~"%s"`, envoyClusterRegex(ns, name)))
+		samples, err = p.Query(ctx, fmt.Sprintf(`sum by (envoy_response_code_class) (rate(envoy_cluster_upstream_rq_xx%s[%s]))`, sel, window))
+		if err != nil {
+			return nil, metricsUnavailable(t.Name(), err)
+		}
+		for _, s := range samples {
+			class := s.Labels["envoy_response_code_class"] + "xx"
+			total += s.Value
+			codes[class] += s.Value
+			if class == "5xx" {
+				errors += s.Value
+			}
+		}
+	}
+
+	if len(samples) == 0 {
+		findings := []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryConnectivity,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("No traffic metrics for %s/%s in the last %s", ns, name, window),
+			Detail:     "queried istio_requests_total and envoy_cluster_upstream_rq_xx",
+			Suggestion: "The service may receive no traffic, or its proxies are not scraped by this Prometheus.",
+		}}
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	codeList := make([]string, 0, len(codes))
+	for c, v := range codes {
+		codeList = append(codeList, fmt.Sprintf("%s=%.2f/s", c, v))
+	}
+	sort.Strings(codeList)
+	summary := fmt.Sprintf("%s/%s: %.2f req/s", ns, name, total)
+	ratio := 0.0
+	if total > 0 {
+		ratio = errors / total
+		summary += fmt.Sprintf(", %.1f%% 5xx", ratio*100)
+	}
+	if source == "istio_requests_total" {
+		latSel := p.Selector(`reporter="destination"`, prometheus.Equal("destination_service_namespace", ns), prometheus.Equal("destination_service_name", name))
+		lat, err := p.Query(ctx, fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(istio_request_duration_milliseconds_bucket%s[%s])))`, latSel, window))
+		if err == nil && len(lat) == 1 && !math.IsNaN(lat[0].Value) {
+			summary += fmt.Sprintf(", p99 %.0f ms", lat[0].Value)
+		}
+	}
+
+	findings := []types.DiagnosticFinding{{
+		Severity: types.SeverityInfo,
+		Category: types.CategoryConnectivity,
+		Resource: ref,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("source=%s window=%s codes=[%s]", source, window, strings.Join(codeList, ", ")),
+	}}
+	if sev := errorRateSeverity(ratio, 0.05); sev != types.SeverityOK {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   sev,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeObservabilityService5xxRate,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%.1f%% of requests to %s/%s fail with 5xx", ratio*100, ns, name),
+			Detail:     fmt.Sprintf("%.2f of %.2f req/s over %s", errors, total, window),
+			Suggestion: correlationSuggestion(describeFlags(flags), t.serviceDestinationRules(ctx, ns, name)),
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
+
+// --- check_error_rate ---
+
+type CheckErrorRateTool struct {
+	BaseTool
+	Prometheus *prometheus.Client
+}
+
+func (t *CheckErrorRateTool) Name() string { return "check_error_rate" }
+func (t *CheckErrorRateTool) Description() string {
+	return "Find services, Envoy clusters and DNS with elevated error rates in Prometheus (istio_requests_total, envoy_cluster_upstream_rq_xx, CoreDNS responses) and correlate them with response flags and DestinationRules to state the traffic impact of configuration problems"
+}
+func (t *CheckErrorRateTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Only services in this namespace (empty for all)",
+			},
+			"threshold_percent": map[string]interface{}{
+				"type":        "integer",
+				"description": "5xx or SERVFAIL percentage reported as critical; a fifth of it is a warning (default 5)",
+			},
+			"window": trafficWindowProperty,
+		},
+	}
+}
+
+func (t *CheckErrorRateTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	ns := getStringArg(args, "namespace", "")
+	threshold := float64(getIntArg(args, "threshold_percent", 5)) / 100
+	if threshold <= 0 || threshold > 1 {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: "threshold_percent must be between 1 and 100"}
+	}
+	window, err := promWindow(getStringArg(args, "window", "5m"))
+	if err != nil {
+		return nil, &types.MCPError{Code: types.ErrCodeInvalidInput, Tool: t.Name(), Message: err.Error()}
+	}
+	p := t.Prometheus
+
+	matchers := []string{`reporter="destination"`}
+	if ns != "" {
+		matchers = append(matchers, prometheus.Equal("destination_service_namespace", ns))
+	}
+	sel := p.Selector(matchers...)
+	totals, err := p.Query(ctx, fmt.Sprintf(`sum by (destination_service_namespace, destination_service_name) (rate(istio_requests_total%s[%s])) > 0`, sel, window))
+	if err != nil {
+		return nil, metricsUnavailable(t.Name(), err)
+	}
+	errSel := p.Selector(append(matchers, `response_code=~"5.."`)...)
+	failures, err := p.Query(ctx, fmt.Sprintf(`sum by (destination_service_namespace, destination_service_name, response_flags) (rate(istio_requests_total%s[%s]))`, errSel, window))
+	if err != nil {
+		return nil, metricsUnavailable(t.Name(), err)
+	}
+
+	type serviceErrors struct {
+		errors float64
+		flags  map[string]float64
+	}
+	byService := map[string]*serviceErrors{}
+	for _, s := range failures {
+		key := s.Labels["destination_service_namespace"] + "/" + s.Labels["destination_service_name"]
+		se := byService[key]
+		if se == nil {
+			se = &serviceErrors{flags: map[string]float64{}}
+			byService[key] = se
+		}
+		se.errors += s.Value
+		se.flags[s.Labels["response_flags"]] += s.Value
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, s := range totals {
+		svcNs, svcName := s.Labels["destination_service_namespace"], s.Labels["destination_service_name"]
+		se := byService[svcNs+"/"+svcName]
+		if se == nil {
+			continue
+		}
+		ratio := se.errors / s.Value
+		sev := errorRateSeverity(ratio, threshold)
+		if sev == types.SeverityOK {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   sev,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeObservabilityService5xxRate,
+			Resource:   &types.ResourceRef{Kind: "Service", Namespace: svcNs, Name: svcName},
+			Summary:    fmt.Sprintf("%.1f%% of requests to %s/%s fail with 5xx", ratio*100, svcNs, svcName),
+			Detail:     fmt.Sprintf("%.2f of %.2f req/s over %s (istio_requests_total)", se.errors, s.Value, window),
+			Suggestion: correlationSuggestion(describeFlags(se.flags), t.serviceDestinationRules(ctx, svcNs, svcName)),
+		})
+	}
+	services := len(totals)
+
+	// Envoy clusters cover gateways (Envoy Gateway, kgateway) and proxies without Istio telemetry.
+	if ns == "" {
+		clusters, err := p.Query(ctx, fmt.Sprintf(`sum by (envoy_cluster_name) (rate(envoy_cluster_upstream_rq_xx%s[%s])) > 0`, p.Selector(`envoy_response_code_class="5"`), window))
+		if err == nil && len(clusters) > 0 {
+			all, err := p.Query(ctx, fmt.Sprintf(`sum by (envoy_cluster_name) (rate(envoy_cluster_upstream_rq_xx%s[%s]))`, p.Selector(), window))
+			if err == nil {
+				totalByCluster := map[string]float64{}
+				for _, s := range all {
+					totalByCluster[s.Labels["envoy_cluster_name"]] = s.Value
+				}
+				for _, s := range clusters {
+					name := s.Labels["envoy_cluster_name"]
+					if totalByCluster[name] == 0 {
+						continue
+					}
+					ratio := s.Value / totalByCluster[name]
+					sev := errorRateSeverity(ratio, threshold)
+					if sev == types.SeverityOK {
+						continue
+					}
+					findings = append(findings, types.DiagnosticFinding{
+						Severity:   sev,
+						Category:   types.CategoryRouting,
+						Code:       types.CodeObservabilityEnvoyCluster5xxRate,
+						Summary:    fmt.Sprintf("%.1f%% of requests to Envoy cluster %s fail with 5xx", ratio*100, name),
+						Detail:     fmt.Sprintf("%.2f of %.2f req/s over %s (envoy_cluster_upstream_rq_xx)", s.Value, totalByCluster[name], window),
+						Suggestion: "Check the cluster's backend endpoints and the route or policy that targets it.",
+					})
+				}
+			}
+		}
+	}
+
+	dns, err := p.Query(ctx, fmt.Sprintf(`sum(rate(coredns_dns_responses_total%s[%s])) / sum(rate(coredns_dns_responses_total%s[%s]))`,
+		p.Selector(`rcode="SERVFAIL"`), window, p.Selector(), window))
+	if err == nil && len(dns) == 1 && !math.IsNaN(dns[0].Value) {
+		if sev := errorRateSeverity(dns[0].Value, threshold); sev != types.SeverityOK {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   sev,
+				Category:   types.CategoryDNS,
+				Code:       types.CodeDNSServfailRate,
+				Summary:    fmt.Sprintf("%.1f%% of CoreDNS responses are SERVFAIL", dns[0].Value*100),
+				Detail:     fmt.Sprintf("window=%s", window),
+				Suggestion: "Run analyze_coredns_config and check the upstream resolvers in the forward plugin.",
+			})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryConnectivity,
+			Summary:  fmt.Sprintf("No error rate above %.1f%% over %s (%d services with Istio traffic)", threshold*100/5, window, services),
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), ""), nil
+}
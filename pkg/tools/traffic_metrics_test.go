@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/prometheus"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestDescribeFlags(t *testing.T) {
+	got := describeFlags(map[string]float64{"-": 5, "UF,URX": 0.5, "UO": 2})
+	if len(got) != 2 || !strings.HasPrefix(got[0], "UO (2.00 req/s): upstream overflow") {
+		t.Fatalf("describeFlags() = %v", got)
+	}
+	if !strings.Contains(got[1], "mTLS mode mismatch") || !strings.Contains(got[1], "retries exhausted") {
+		t.Errorf("expected both UF and URX causes, got %s", got[1])
+	}
+}
+
+func TestErrorRateSeverity(t *testing.T) {
+	cases := map[float64]string{0.2: types.SeverityCritical, 0.05: types.SeverityCritical, 0.02: types.SeverityWarning, 0.001: types.SeverityOK}
+	for ratio, want := range cases {
+		if got := errorRateSeverity(ratio, 0.05); got != want {
+			t.Errorf("errorRateSeverity(%v) = %s, want %s", ratio, got, want)
+		}
+	}
+}
+
+func TestPromWindow(t *testing.T) {
+	if got, err := promWindow("1h"); err != nil || got != "3600s" {
+		t.Errorf("promWindow(1h) = %s, %v", got, err)
+	}
+	for _, w := range []string{"30s", "5", "soon"} {
+		if _, err := promWindow(w); err == nil {
+			t.Errorf("promWindow(%q): expected an error", w)
+		}
+	}
+}
+
+func TestCheckErrorRate_CorrelatesDestinationRule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.FormValue("query")
+		switch {
+		case strings.Contains(q, "istio_requests_total") && strings.Contains(q, "response_flags"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"destination_service_namespace":"shop","destination_service_name":"web","response_flags":"UO"},"value":[0,"1.4"]}]}}`))
+		case strings.Contains(q, "istio_requests_total"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"destination_service_namespace":"shop","destination_service_name":"web"},"value":[0,"10"]},
+				{"metric":{"destination_service_namespace":"shop","destination_service_name":"api"},"value":[0,"5"]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}
+	}))
+	defer srv.Close()
+
+	svc := &unstructured.Unstructured{}
+	svc.SetAPIVersion("v1")
+	svc.SetKind("Service")
+	svc.SetNamespace("shop")
+	svc.SetName("web")
+	dr := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"host": "web"}}}
+	dr.SetAPIVersion("networking.istio.io/v1")
+	dr.SetKind("DestinationRule")
+	dr.SetNamespace("shop")
+	dr.SetName("web-pool")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{servicesGVR: "ServiceList", drV1GVR: "DestinationRuleList", drV1B1GVR: "DestinationRuleList"},
+		svc, dr)
+
+	prom, err := prometheus.NewClient(srv.URL, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := &CheckErrorRateTool{
+		BaseTool:   BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}},
+		Prometheus: prom,
+	}
+	resp, err := tool.Run(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := resp.Data.(*types.ToolResult).Findings
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding for shop/web, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Severity != types.SeverityCritical || !strings.Contains(f.Summary, "14.0% of requests to shop/web") {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	if !strings.Contains(f.Suggestion, "shop/web-pool") || !strings.Contains(f.Suggestion, "connectionPool") {
+		t.Errorf("expected the DestinationRule and UO cause in the suggestion, got %s", f.Suggestion)
+	}
+}
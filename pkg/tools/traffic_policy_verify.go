@@ -0,0 +1,663 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// trafficMirror is one destination a route mirrors requests to.
+type trafficMirror struct {
+	Service   string
+	Namespace string
+	Port      int
+	Percent   float64
+}
+
+// trafficPolicy is the fault injection, timeout, retry and mirror
+// configuration of one VirtualService http route or HTTPRoute rule.
+type trafficPolicy struct {
+	Rule           string
+	Delay          time.Duration
+	DelayPercent   float64
+	DelaySet       bool
+	AbortStatus    int
+	AbortPercent   float64
+	AbortSet       bool
+	Timeout        time.Duration
+	BackendTimeout time.Duration
+	Attempts       int
+	PerTryTimeout  time.Duration
+	RetryOn        string
+	Mirrors        []trafficMirror
+}
+
+// probed reports whether the policy has behaviour a client can observe.
+func (p *trafficPolicy) probed() bool {
+	return p.DelaySet || p.AbortSet || p.Timeout > 0 || p.Attempts > 0
+}
+
+func (p *trafficPolicy) String() string {
+	var parts []string
+	if p.DelaySet {
+		parts = append(parts, fmt.Sprintf("delay %s on %g%%", p.Delay, p.DelayPercent))
+	}
+	if p.AbortSet {
+		parts = append(parts, fmt.Sprintf("abort %d on %g%%", p.AbortStatus, p.AbortPercent))
+	}
+	if p.Timeout > 0 {
+		parts = append(parts, "timeout "+p.Timeout.String())
+	}
+	if p.BackendTimeout > 0 {
+		parts = append(parts, "backend timeout "+p.BackendTimeout.String())
+	}
+	if p.Attempts > 0 {
+		retry := fmt.Sprintf("%d retries", p.Attempts)
+		if p.PerTryTimeout > 0 {
+			retry += " of " + p.PerTryTimeout.String()
+		}
+		if p.RetryOn != "" {
+			retry += " on " + p.RetryOn
+		}
+		parts = append(parts, retry)
+	}
+	for _, m := range p.Mirrors {
+		parts = append(parts, fmt.Sprintf("mirror to %s.%s (%g%%)", m.Service, m.Namespace, m.Percent))
+	}
+	if len(parts) == 0 {
+		return "no fault injection, timeout, retry or mirror"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return 0
+}
+
+// policyDuration parses a VirtualService or Gateway API duration such as
+// "1.5s" or "500ms"; invalid or missing values are 0.
+func policyDuration(m map[string]interface{}, fields ...string) time.Duration {
+	d, err := time.ParseDuration(getNestedString(m, fields...))
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// istioPercent reads the percentage of an Istio fault or mirror: the
+// percentage.value field, or the deprecated integer percent.
+func istioPercent(m map[string]interface{}) (float64, bool) {
+	if v, ok, _ := unstructured.NestedFieldNoCopy(m, "percentage", "value"); ok {
+		return toFloat(v), true
+	}
+	if v, ok := m["percent"]; ok {
+		return float64(toInt(v)), true
+	}
+	return 0, false
+}
+
+// ruleName labels a route or rule by index and, if set, name.
+func ruleName(field string, i int, rule map[string]interface{}) string {
+	label := fmt.Sprintf("%s[%d]", field, i)
+	if name, _ := rule["name"].(string); name != "" {
+		label += " (" + name + ")"
+	}
+	return label
+}
+
+// virtualServicePolicies reads the traffic policy of every http route of a
+// VirtualService. An unset fault percentage injects nothing; an unset
+// mirror percentage mirrors everything.
+func virtualServicePolicies(vs *unstructured.Unstructured) []trafficPolicy {
+	routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+	policies := make([]trafficPolicy, 0, len(routes))
+	for i, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := trafficPolicy{Rule: ruleName("http", i, route)}
+		if delay, ok, _ := unstructured.NestedMap(route, "fault", "delay"); ok {
+			p.DelaySet = true
+			p.Delay = policyDuration(delay, "fixedDelay")
+			p.DelayPercent, _ = istioPercent(delay)
+		}
+		if abort, ok, _ := unstructured.NestedMap(route, "fault", "abort"); ok {
+			p.AbortSet = true
+			p.AbortStatus = toInt(abort["httpStatus"])
+			p.AbortPercent, _ = istioPercent(abort)
+		}
+		p.Timeout = policyDuration(route, "timeout")
+		if retries, ok, _ := unstructured.NestedMap(route, "retries"); ok {
+			p.Attempts = toInt(retries["attempts"])
+			p.PerTryTimeout = policyDuration(retries, "perTryTimeout")
+			p.RetryOn, _ = retries["retryOn"].(string)
+		}
+
+		addMirror := func(dest map[string]interface{}, percent float64) {
+			host, _ := dest["host"].(string)
+			name, ns, ok := parseK8sServiceHost(host, vs.GetNamespace())
+			if !ok {
+				name, ns = host, ""
+			}
+			port, _, _ := unstructured.NestedFieldNoCopy(dest, "port", "number")
+			p.Mirrors = append(p.Mirrors, trafficMirror{Service: name, Namespace: ns, Port: toInt(port), Percent: percent})
+		}
+		if mirror, ok, _ := unstructured.NestedMap(route, "mirror"); ok {
+			percent := 100.0
+			if v, ok, _ := unstructured.NestedFieldNoCopy(route, "mirrorPercentage", "value"); ok {
+				percent = toFloat(v)
+			}
+			addMirror(mirror, percent)
+		}
+		mirrors, _, _ := unstructured.NestedSlice(route, "mirrors")
+		for _, m := range mirrors {
+			mm, ok := m.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			dest, _, _ := unstructured.NestedMap(mm, "destination")
+			percent, ok := istioPercent(mm)
+			if !ok {
+				percent = 100
+			}
+			addMirror(dest, percent)
+		}
+		policies = append(policies, p)
+	}
+	return policies
+}
+
+// httpRoutePolicies reads the timeouts, retry and RequestMirror filters of
+// every rule of an HTTPRoute. Gateway API has no fault injection.
+func httpRoutePolicies(route *unstructured.Unstructured) []trafficPolicy {
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	policies := make([]trafficPolicy, 0, len(rules))
+	for i, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := trafficPolicy{Rule: ruleName("rules", i, rule)}
+		p.Timeout = policyDuration(rule, "timeouts", "request")
+		p.BackendTimeout = policyDuration(rule, "timeouts", "backendRequest")
+		if retry, ok, _ := unstructured.NestedMap(rule, "retry"); ok {
+			p.Attempts = toInt(retry["attempts"])
+			codes, _, _ := unstructured.NestedSlice(retry, "codes")
+			var onCodes []string
+			for _, c := range codes {
+				onCodes = append(onCodes, fmt.Sprint(toInt(c)))
+			}
+			p.RetryOn = strings.Join(onCodes, ",")
+		}
+		filters, _, _ := unstructured.NestedSlice(rule, "filters")
+		for _, f := range filters {
+			fm, ok := f.(map[string]interface{})
+			if !ok || fm["type"] != "RequestMirror" {
+				continue
+			}
+			ref, _, _ := unstructured.NestedMap(fm, "requestMirror", "backendRef")
+			m := trafficMirror{Namespace: route.GetNamespace(), Port: toInt(ref["port"]), Percent: 100}
+			m.Service, _ = ref["name"].(string)
+			if ns, _ := ref["namespace"].(string); ns != "" {
+				m.Namespace = ns
+			}
+			if v, ok, _ := unstructured.NestedFieldNoCopy(fm, "requestMirror", "percent"); ok {
+				m.Percent = toFloat(v)
+			} else if num, ok, _ := unstructured.NestedFieldNoCopy(fm, "requestMirror", "fraction", "numerator"); ok {
+				den := 100.0
+				if d, ok, _ := unstructured.NestedFieldNoCopy(fm, "requestMirror", "fraction", "denominator"); ok && toFloat(d) > 0 {
+					den = toFloat(d)
+				}
+				m.Percent = toFloat(num) * 100 / den
+			}
+			p.Mirrors = append(p.Mirrors, m)
+		}
+		policies = append(policies, p)
+	}
+	return policies
+}
+
+// staticPolicyFindings checks a route's policy for settings that cannot
+// behave as intended regardless of traffic.
+func (b *BaseTool) staticPolicyFindings(ctx context.Context, route string, ref *types.ResourceRef, p trafficPolicy) []types.DiagnosticFinding {
+	where := route + " " + p.Rule
+	var findings []types.DiagnosticFinding
+	if p.Attempts > 0 && p.PerTryTimeout > 0 && p.Timeout > 0 {
+		if budget := p.PerTryTimeout * time.Duration(p.Attempts+1); budget > p.Timeout {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeTrafficTimeoutBudgetExceeded,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: %d tries of %s need up to %s, but the route timeout is %s", where, p.Attempts+1, p.PerTryTimeout, budget, p.Timeout),
+				Detail:     "the route timeout covers all tries, so the last retries are cut off and the client gets a 504",
+				Suggestion: "Raise the route timeout to at least (attempts+1) x perTryTimeout, or lower perTryTimeout or attempts",
+			})
+		}
+	}
+	if p.BackendTimeout > 0 && p.Timeout > 0 && p.BackendTimeout > p.Timeout {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTrafficTimeoutBudgetExceeded,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s: timeouts.backendRequest %s exceeds timeouts.request %s", where, p.BackendTimeout, p.Timeout),
+			Detail:     "Gateway API requires backendRequest to be at most request; implementations reject the rule or ignore the backend timeout",
+			Suggestion: "Lower timeouts.backendRequest below timeouts.request",
+		})
+	}
+	for _, fault := range []struct {
+		kind    string
+		set     bool
+		percent float64
+	}{{"delay", p.DelaySet, p.DelayPercent}, {"abort", p.AbortSet, p.AbortPercent}} {
+		if fault.set && fault.percent <= 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeTrafficFaultIneffective,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: the %s fault has no percentage, so Istio injects it into no request", where, fault.kind),
+				Suggestion: fmt.Sprintf("Set fault.%s.percentage.value (0-100)", fault.kind),
+			})
+		}
+	}
+	if p.DelaySet && p.Delay <= 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTrafficFaultIneffective,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s: the delay fault has no valid fixedDelay", where),
+			Suggestion: "Set fault.delay.fixedDelay to a duration such as 5s",
+		})
+	}
+	for _, m := range p.Mirrors {
+		if m.Namespace == "" {
+			continue // external host, not a Service
+		}
+		_, err := b.Clients.Dynamic.Resource(servicesGVR).Namespace(m.Namespace).Get(ctx, m.Service, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeTrafficMirrorBackendMissing,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s mirrors %g%% of requests to Service %s/%s, which does not exist", where, m.Percent, m.Namespace, m.Service),
+				Detail:     "mirrored requests are dropped; the primary traffic is not affected",
+				Suggestion: "Deploy the shadow Service or fix the mirror destination",
+			})
+		}
+	}
+	return findings
+}
+
+// withinExpected reports whether observed out of n requests is consistent
+// with a configured percentage, allowing three standard deviations.
+func withinExpected(observed, n int, percent float64) bool {
+	q := percent / 100
+	expected := float64(n) * q
+	tolerance := 3*math.Sqrt(float64(n)*q*(1-q)) + 1
+	return math.Abs(float64(observed)-expected) <= tolerance
+}
+
+// evaluateTrafficPolicy compares what the probe requests experienced with
+// the faults, timeout and retries configured for the rule they matched.
+func evaluateTrafficPolicy(where string, ref *types.ResourceRef, p trafficPolicy, samples []latencySample) []types.DiagnosticFinding {
+	n := len(samples)
+	notApplied := "Check that the url and host header match this rule, and that the requests reach a proxy that has the route: a VirtualService applies at a gateway only when it lists the gateway, and the probe pod has no sidecar."
+	var findings []types.DiagnosticFinding
+	observed := func(kind string, count int, percent float64, what string) {
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s: %s fault observed on %d/%d requests (%.0f%%), configured %g%%", where, kind, count, n, 100*float64(count)/float64(n), percent),
+			Detail:   what,
+		}
+		if !withinExpected(count, n, percent) {
+			f.Severity = types.SeverityWarning
+			f.Code = types.CodeTrafficPolicyNotObserved
+			f.Summary = fmt.Sprintf("%s: %s fault observed on %d/%d requests (%.0f%%), but %g%% are configured", where, kind, count, n, 100*float64(count)/float64(n), percent)
+			f.Suggestion = "Another route or an EnvoyFilter may handle these requests; compare with the effective proxy configuration."
+			if count == 0 {
+				f.Severity = types.SeverityCritical
+				f.Suggestion = notApplied
+			}
+		}
+		findings = append(findings, f)
+	}
+
+	delayed := func(s latencySample) bool {
+		return p.DelaySet && p.Delay > 0 && s.total >= 0.9*p.Delay.Seconds()
+	}
+	if p.AbortSet && p.AbortPercent > 0 && p.AbortStatus > 0 {
+		count := 0
+		for _, s := range samples {
+			if s.status == p.AbortStatus {
+				count++
+			}
+		}
+		observed("abort", count, p.AbortPercent, fmt.Sprintf("requests answered with status %d", p.AbortStatus))
+	}
+	if p.DelaySet && p.DelayPercent > 0 && p.Delay > 0 {
+		count := 0
+		for _, s := range samples {
+			if delayed(s) {
+				count++
+			}
+		}
+		observed("delay", count, p.DelayPercent, fmt.Sprintf("requests that took at least %s", time.Duration(0.9*float64(p.Delay))))
+	}
+
+	if p.Timeout > 0 {
+		limit := p.Timeout.Seconds()*1.2 + 0.25
+		var over, timedOut int
+		var longest float64
+		for _, s := range samples {
+			if delayed(s) {
+				continue // the delay is injected before the route timeout starts
+			}
+			longest = math.Max(longest, s.total)
+			if s.total > limit {
+				over++
+			}
+			if s.status == 504 && s.total >= 0.9*p.Timeout.Seconds() {
+				timedOut++
+			}
+		}
+		switch {
+		case over > 0:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeTrafficPolicyNotObserved,
+				Resource:   ref,
+				Summary:    fmt.Sprintf("%s: %d/%d requests took longer than the %s route timeout (longest %.2fs)", where, over, n, p.Timeout, longest),
+				Suggestion: notApplied,
+			})
+		case timedOut > 0:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityOK,
+				Category: types.CategoryRouting,
+				Resource: ref,
+				Summary:  fmt.Sprintf("%s: the %s route timeout is enforced: %d/%d requests returned 504 after it expired", where, p.Timeout, timedOut, n),
+			})
+		default:
+			findings = append(findings, types.DiagnosticFinding{
+				Severity: types.SeverityInfo,
+				Category: types.CategoryRouting,
+				Resource: ref,
+				Summary:  fmt.Sprintf("%s: no request reached the %s route timeout (longest %.2fs), so it was not exercised", where, p.Timeout, longest),
+				Detail:   "add a delay fault longer than the timeout on a test route to verify it",
+			})
+		}
+	}
+
+	if p.Attempts > 0 {
+		failed := 0
+		for _, s := range samples {
+			if s.status >= 500 && (!p.AbortSet || s.status != p.AbortStatus) {
+				failed++
+			}
+		}
+		f := types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s: no 5xx response reached the client; %d retries are configured", where, p.Attempts),
+			Detail:   "retries happen between the proxy and the backend and are invisible to the client; compare the upstream_rq_retry counters of the proxy to confirm them",
+		}
+		if failed > 0 {
+			f.Summary = fmt.Sprintf("%s: %d/%d requests returned 5xx despite %d retries on %s", where, failed, n, p.Attempts, orDefault(p.RetryOn, "the default conditions"))
+			f.Detail = "either every try failed, or the status is not among the retry conditions; injected aborts are never retried"
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// --- verify_traffic_policies ---
+
+type VerifyTrafficPoliciesTool struct {
+	BaseTool
+	ProbeManager *probes.Manager
+}
+
+func (t *VerifyTrafficPoliciesTool) Name() string { return "verify_traffic_policies" }
+func (t *VerifyTrafficPoliciesTool) Description() string {
+	return "Cross-check the fault injection (delay/abort), timeouts, retries and request mirroring configured in a VirtualService or HTTPRoute: flag settings that cannot work as written, and with a url, send controlled probe requests through the gateway and compare the observed aborts, delays and timeouts with the configuration"
+}
+func (t *VerifyTrafficPoliciesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"kind": map[string]interface{}{
+				"type":        "string",
+				"description": "VirtualService or HTTPRoute",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the VirtualService or HTTPRoute",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the route",
+			},
+			"rule": map[string]interface{}{
+				"type":        "integer",
+				"description": "Index of the http route or rule the probe requests match (default: the first one with a fault, timeout or retry)",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "URL the probe requests, usually the gateway address and a path matching the rule. Without it only the configuration is checked",
+			},
+			"host": map[string]interface{}{
+				"type":        "string",
+				"description": "Host header to send, when the url does not carry the route's hostname",
+			},
+			"count": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of probe requests (default: 50, max: 200)",
+			},
+			"source_namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace to deploy the probe pod in",
+			},
+		},
+		"required": []string{"kind", "name", "namespace"},
+	}
+}
+
+func (t *VerifyTrafficPoliciesTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	kind := getStringArg(args, "kind", "")
+	name := getStringArg(args, "name", "")
+	ns := getStringArg(args, "namespace", "")
+	targetURL := getStringArg(args, "url", "")
+	host := getStringArg(args, "host", "")
+	count := getIntArg(args, "count", 50)
+	sourceNS := getStringArg(args, "source_namespace", t.Cfg.ProbeNamespace)
+
+	if name == "" || ns == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "name and namespace are required",
+		}
+	}
+	if containsShellMeta(targetURL) || !validHostname.MatchString(orDefault(host, "x")) {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "url or host contains invalid characters",
+		}
+	}
+	if count < 1 || count > 200 {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("count %d out of range (1-200)", count),
+		}
+	}
+
+	var (
+		obj      *unstructured.Unstructured
+		err      error
+		policies []trafficPolicy
+		ref      *types.ResourceRef
+	)
+	switch kind {
+	case "VirtualService":
+		obj, err = getWithFallback(ctx, t.Clients.Dynamic, vsV1GVR, vsV1B1GVR, ns, name)
+		if err == nil {
+			policies = virtualServicePolicies(obj)
+			ref = &types.ResourceRef{Kind: kind, Namespace: ns, Name: name, APIVersion: obj.GetAPIVersion()}
+		}
+	case "HTTPRoute":
+		obj, err = getWithFallback(ctx, t.Clients.Dynamic, httpRoutesV1GVR, httpRoutesV1B1GVR, ns, name)
+		if err == nil {
+			policies = httpRoutePolicies(obj)
+			ref = &types.ResourceRef{Kind: kind, Namespace: ns, Name: name, APIVersion: obj.GetAPIVersion()}
+		}
+	default:
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("kind %q is not supported; use VirtualService or HTTPRoute", kind),
+		}
+	}
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeCRDNotAvailable,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("failed to get %s %s/%s", kind, ns, name),
+			Detail:  err.Error(),
+		}
+	}
+
+	route := fmt.Sprintf("%s %s/%s", kind, ns, name)
+	var findings []types.DiagnosticFinding
+	for _, p := range policies {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s %s: %s", route, p.Rule, p.String()),
+		})
+		findings = append(findings, t.staticPolicyFindings(ctx, route, ref, p)...)
+	}
+	if targetURL == "" {
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+
+	// Probe the selected rule.
+	index := getIntArg(args, "rule", -1)
+	if index < 0 {
+		for i := range policies {
+			if policies[i].probed() {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 || index >= len(policies) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityInfo,
+			Category: types.CategoryRouting,
+			Resource: ref,
+			Summary:  fmt.Sprintf("%s has no rule with a fault, timeout or retry to probe", route),
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+	p := policies[index]
+
+	// curl must outlast the injected delay plus the route timeout, or the
+	// probe would time out before the proxy does.
+	timeoutSec := int(math.Ceil((p.Delay + p.Timeout + p.PerTryTimeout).Seconds())) + 5
+	timeoutSec = min(timeoutSec, latencyProbeMaxTimeout)
+	headers := ""
+	if host != "" {
+		headers = "Host: " + host
+	}
+	result, err := t.ProbeManager.Execute(ctx, probes.ProbeRequest{
+		Type:      probes.ProbeTypeTraffic,
+		Namespace: sourceNS,
+		Command:   []string{"sh", "-c", latencyProbeScript("GET", targetURL, headers, false, count, 50, timeoutSec)},
+		Timeout:   latencyProbePodTimeout(timeoutSec),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if qf := probeQueueFinding(result); qf != nil {
+		findings = append(findings, *qf)
+	}
+
+	var samples []latencySample
+	failures := make(map[string]int)
+	for _, s := range parseLatencySamples(result.Output) {
+		if s.failed() {
+			reason := curlExitReasons[s.exitCode]
+			if reason == "" {
+				reason = fmt.Sprintf("curl exit code %d", s.exitCode)
+			}
+			failures[reason]++
+			continue
+		}
+		samples = append(samples, s)
+	}
+	if len(samples) == 0 {
+		detail := formatCounts(failures)
+		if result.Error != "" {
+			detail = result.Error + "; " + detail
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConnectivity,
+			Code:       types.CodeProbeHTTPFailed,
+			Summary:    fmt.Sprintf("No probe request to %s got a response", targetURL),
+			Detail:     detail,
+			Suggestion: "Check the url with probe_http first; the gateway must be reachable from the probe namespace.",
+		})
+		return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+	}
+	if len(failures) > 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryConnectivity,
+			Code:     types.CodeProbeRequestsFailed,
+			Summary:  fmt.Sprintf("%d probe requests to %s got no response and are not counted", len(parseLatencySamples(result.Output))-len(samples), targetURL),
+			Detail:   formatCounts(failures),
+		})
+	}
+
+	findings = append(findings, evaluateTrafficPolicy(route+" "+p.Rule, ref, p, samples)...)
+	for _, m := range p.Mirrors {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Resource:   ref,
+			Summary:    fmt.Sprintf("%s %s: mirroring %g%% to %s cannot be observed from the client", route, p.Rule, m.Percent, m.Service),
+			Detail:     fmt.Sprintf("%d probe requests were sent; Envoy-based implementations send mirrored copies with a -shadow suffix on the Host header", len(samples)),
+			Suggestion: "Compare the request rate of the mirror Service before and after the probe (query_service_traffic), or check its access logs for the -shadow host",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, ns, ""), nil
+}
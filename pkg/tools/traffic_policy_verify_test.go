@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func testVirtualService() map[string]interface{} {
+	return map[string]interface{}{"spec": map[string]interface{}{"http": []interface{}{
+		map[string]interface{}{
+			"name": "faulty",
+			"fault": map[string]interface{}{
+				"delay": map[string]interface{}{"fixedDelay": "2s", "percentage": map[string]interface{}{"value": 50.0}},
+				"abort": map[string]interface{}{"httpStatus": int64(503)},
+			},
+			"timeout":          "1s",
+			"retries":          map[string]interface{}{"attempts": int64(3), "perTryTimeout": "500ms", "retryOn": "5xx"},
+			"mirror":           map[string]interface{}{"host": "reviews-shadow"},
+			"mirrorPercentage": map[string]interface{}{"value": 10.0},
+		},
+		map[string]interface{}{"route": []interface{}{}},
+	}}}
+}
+
+func TestVirtualServicePolicies(t *testing.T) {
+	vs := managedObj("networking.istio.io/v1", "VirtualService", "shop", "reviews", testVirtualService())
+	policies := virtualServicePolicies(vs)
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(policies))
+	}
+	p := policies[0]
+	if !p.DelaySet || p.Delay != 2*time.Second || p.DelayPercent != 50 || !p.AbortSet || p.AbortPercent != 0 || p.AbortStatus != 503 {
+		t.Errorf("unexpected faults %+v", p)
+	}
+	if got := p.String(); got != "delay 2s on 50%, abort 503 on 0%, timeout 1s, 3 retries of 500ms on 5xx, mirror to reviews-shadow.shop (10%)" {
+		t.Errorf("String() = %q", got)
+	}
+	if policies[1].probed() {
+		t.Error("expected the second route to have nothing to probe")
+	}
+}
+
+func TestHTTPRoutePolicies(t *testing.T) {
+	route := managedObj("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "web", map[string]interface{}{"spec": map[string]interface{}{"rules": []interface{}{
+		map[string]interface{}{
+			"timeouts": map[string]interface{}{"request": "2s", "backendRequest": "5s"},
+			"retry":    map[string]interface{}{"attempts": int64(2), "codes": []interface{}{int64(502), int64(503)}},
+			"filters": []interface{}{map[string]interface{}{
+				"type":          "RequestMirror",
+				"requestMirror": map[string]interface{}{"backendRef": map[string]interface{}{"name": "web-v2", "port": int64(80)}, "fraction": map[string]interface{}{"numerator": int64(1), "denominator": int64(4)}},
+			}},
+		},
+	}}})
+	p := httpRoutePolicies(route)[0]
+	if got := p.String(); got != "timeout 2s, backend timeout 5s, 2 retries on 502,503, mirror to web-v2.shop (25%)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestVerifyTrafficPoliciesStatic(t *testing.T) {
+	vs := managedObj("networking.istio.io/v1", "VirtualService", "shop", "reviews", testVirtualService())
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		vsV1GVR: "VirtualServiceList", servicesGVR: "ServiceList",
+	}, vs)
+	tool := &VerifyTrafficPoliciesTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	resp, err := tool.Run(context.Background(), map[string]interface{}{"kind": "VirtualService", "name": "reviews", "namespace": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := managedFindingsOf(resp)
+	for _, want := range []string{
+		"warning VirtualService shop/reviews http[0] (faulty): 4 tries of 500ms need up to 2s, but the route timeout is 1s TRP001_TIMEOUT_BUDGET_EXCEEDED",
+		"warning VirtualService shop/reviews http[0] (faulty): the abort fault has no percentage, so Istio injects it into no request TRP002_FAULT_INEFFECTIVE",
+		"warning VirtualService shop/reviews http[0] (faulty) mirrors 10% of requests to Service shop/reviews-shadow, which does not exist TRP003_MIRROR_BACKEND_MISSING",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("missing finding %q in:\n%s", want, all)
+		}
+	}
+
+	if _, err := tool.Run(context.Background(), map[string]interface{}{"kind": "Ingress", "name": "web", "namespace": "shop"}); err == nil {
+		t.Error("expected an unsupported kind to be rejected")
+	}
+}
+
+func TestEvaluateTrafficPolicy(t *testing.T) {
+	p := trafficPolicy{Rule: "http[0]", AbortSet: true, AbortStatus: 503, AbortPercent: 50, DelaySet: true, Delay: time.Second, DelayPercent: 20, Timeout: 500 * time.Millisecond}
+	var samples []latencySample
+	for i := 0; i < 40; i++ {
+		s := latencySample{status: 200, total: 0.05}
+		if i%2 == 0 {
+			s.status = 503
+		}
+		if i%5 == 1 {
+			s.total = 1.02
+		}
+		samples = append(samples, s)
+	}
+	findings := evaluateTrafficPolicy("VirtualService shop/reviews http[0]", nil, p, samples)
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Severity)+" "+f.Summary)
+	}
+	want := []string{
+		"ok VirtualService shop/reviews http[0]: abort fault observed on 20/40 requests (50%), configured 50%",
+		"ok VirtualService shop/reviews http[0]: delay fault observed on 8/40 requests (20%), configured 20%",
+		"info VirtualService shop/reviews http[0]: no request reached the 500ms route timeout (longest 0.05s), so it was not exercised",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A route that never applies: no abort seen, requests outlast the timeout.
+	for i := range samples {
+		samples[i] = latencySample{status: 200, total: 0.88}
+	}
+	findings = evaluateTrafficPolicy("route", nil, p, samples)
+	if findings[0].Severity != types.SeverityCritical || findings[0].Code != types.CodeTrafficPolicyNotObserved {
+		t.Errorf("expected an unobserved abort to be critical, got %+v", findings[0])
+	}
+	if last := findings[len(findings)-1]; last.Code != types.CodeTrafficPolicyNotObserved || !strings.Contains(last.Summary, "40/40 requests took longer than the 500ms route timeout") {
+		t.Errorf("expected the timeout not to be enforced, got %+v", last)
+	}
+}
@@ -0,0 +1,899 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// triageRequest is the request that returns 404 at the edge.
+type triageRequest struct {
+	Host string
+	Path string
+	Port int64 // 0 when unknown
+}
+
+func (r triageRequest) String() string {
+	if r.Port > 0 {
+		return fmt.Sprintf("%s:%d%s", r.Host, r.Port, r.Path)
+	}
+	return r.Host + r.Path
+}
+
+// parseTriageRequest reads host, path and port from the arguments. host may
+// also be a URL, or carry a port ("shop.example.com:8443").
+func parseTriageRequest(host, path string, port int64) (triageRequest, error) {
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return triageRequest{}, err
+		}
+		host = u.Host
+		if path == "" {
+			path = u.EscapedPath()
+		}
+		if port == 0 {
+			switch u.Scheme {
+			case "http":
+				port = 80
+			case "https":
+				port = 443
+			}
+		}
+	}
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host = h
+		if n, err := strconv.ParseInt(p, 10, 64); err == nil {
+			port = n
+		}
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return triageRequest{}, fmt.Errorf("host is empty")
+	}
+	return triageRequest{Host: host, Path: path, Port: port}, nil
+}
+
+// matchConditions lists the request attributes other than the path that a
+// match block also requires.
+func matchConditions(m map[string]interface{}, keys ...string) []string {
+	var conds []string
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			conds = append(conds, k)
+		}
+	}
+	return conds
+}
+
+// istioStringMatch evaluates an Istio StringMatch (exact, prefix or regex).
+func istioStringMatch(m map[string]interface{}, s string, ignoreCase bool) bool {
+	if ignoreCase {
+		s = strings.ToLower(s)
+	}
+	value := func(k string) (string, bool) {
+		v, ok := m[k].(string)
+		if ignoreCase {
+			v = strings.ToLower(v)
+		}
+		return v, ok
+	}
+	if v, ok := value("exact"); ok {
+		return s == v
+	}
+	if v, ok := value("prefix"); ok {
+		return strings.HasPrefix(s, v)
+	}
+	if v, ok := m["regex"].(string); ok {
+		re, err := regexp.Compile("^(?:" + v + ")$")
+		return err == nil && re.MatchString(s)
+	}
+	return true
+}
+
+func describeIstioURIMatch(m map[string]interface{}) string {
+	uri, _ := m["uri"].(map[string]interface{})
+	for _, k := range []string{"exact", "prefix", "regex"} {
+		if v, ok := uri[k].(string); ok {
+			return k + " " + v
+		}
+	}
+	return "any path"
+}
+
+// istioServerHost splits an Istio Gateway server host "[namespace/]dnsName".
+func istioServerHost(h string) (scope, dnsName string) {
+	if i := strings.Index(h, "/"); i >= 0 {
+		return h[:i], h[i+1:]
+	}
+	return "*", h
+}
+
+// istioNamespaceAllowed reports whether a VirtualService in vsNs may bind to
+// a server host whose namespace part is scope.
+func istioNamespaceAllowed(scope, gwNs, vsNs string) bool {
+	switch scope {
+	case "*":
+		return true
+	case ".":
+		return vsNs == gwNs
+	case "~":
+		return false
+	}
+	return scope == vsNs
+}
+
+// vsGatewayRefs returns the "namespace/name" of the gateways a VirtualService
+// binds to, excluding the mesh.
+func vsGatewayRefs(vs *unstructured.Unstructured) []string {
+	gateways, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+	var refs []string
+	for _, g := range gateways {
+		if g == "mesh" {
+			continue
+		}
+		if !strings.Contains(g, "/") {
+			g = vs.GetNamespace() + "/" + g
+		}
+		refs = append(refs, g)
+	}
+	return refs
+}
+
+// istioRouteMatch evaluates an http route of a VirtualService bound to gwRef.
+// It returns whether one of its match blocks accepts the request path and
+// port, and the other conditions that block requires.
+func istioRouteMatch(route map[string]interface{}, vsNs, gwRef string, req triageRequest) (bool, []string) {
+	matches, _ := route["match"].([]interface{})
+	if len(matches) == 0 {
+		return true, nil
+	}
+	var conditional []string
+	for _, m := range matches {
+		mm, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if gws, ok := mm["gateways"].([]interface{}); ok && len(gws) > 0 {
+			bound := false
+			for _, g := range gws {
+				gs, _ := g.(string)
+				if !strings.Contains(gs, "/") {
+					gs = vsNs + "/" + gs
+				}
+				bound = bound || gs == gwRef
+			}
+			if !bound {
+				continue
+			}
+		}
+		if p, ok := mm["port"].(int64); ok && req.Port > 0 && p != req.Port {
+			continue
+		}
+		ignoreCase, _ := mm["ignoreUriCase"].(bool)
+		if uri, ok := mm["uri"].(map[string]interface{}); ok && !istioStringMatch(uri, req.Path, ignoreCase) {
+			continue
+		}
+		conds := matchConditions(mm, "headers", "withoutHeaders", "method", "queryParams", "authority", "scheme", "sourceLabels", "sourceNamespace")
+		if len(conds) == 0 {
+			return true, nil
+		}
+		if conditional == nil {
+			conditional = conds
+		}
+	}
+	return conditional != nil, conditional
+}
+
+// httpRouteRuleMatch returns the match of an HTTPRoute rule that accepts
+// path, as its type and value, and the other conditions it requires. Rules
+// without matches accept every path.
+func httpRouteRuleMatch(rule map[string]interface{}, path string) (matchType, value string, conds []string, ok bool) {
+	matches, _ := rule["matches"].([]interface{})
+	if len(matches) == 0 {
+		return "PathPrefix", "/", nil, true
+	}
+	for _, m := range matches {
+		mm, _ := m.(map[string]interface{})
+		t, v := "PathPrefix", "/"
+		if pm, ok := mm["path"].(map[string]interface{}); ok {
+			if s, ok := pm["type"].(string); ok {
+				t = s
+			}
+			if s, ok := pm["value"].(string); ok {
+				v = s
+			}
+		}
+		if !gatewayPathMatches(t, v, path) {
+			continue
+		}
+		c := matchConditions(mm, "headers", "queryParams", "method")
+		// Prefer an unconditional match in the same rule.
+		if !ok || (len(conds) > 0 && len(c) == 0) {
+			matchType, value, conds, ok = t, v, c, true
+		}
+		if len(c) == 0 {
+			break
+		}
+	}
+	return matchType, value, conds, ok
+}
+
+// gatewayPathMatches evaluates a Gateway API path match. PathPrefix matches
+// whole path segments: /api matches /api and /api/v1 but not /apis.
+func gatewayPathMatches(matchType, value, path string) bool {
+	switch matchType {
+	case "Exact":
+		return path == value
+	case "RegularExpression":
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		return err == nil && re.MatchString(path)
+	}
+	prefix := strings.TrimSuffix(value, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// describeHTTPRouteMatches lists the path matches of a route's rules.
+func describeHTTPRouteMatches(route routeInfo) []string {
+	var out []string
+	rules, _, _ := unstructured.NestedSlice(route.obj, "spec", "rules")
+	for _, r := range rules {
+		rm, _ := r.(map[string]interface{})
+		matches, _ := rm["matches"].([]interface{})
+		if len(matches) == 0 {
+			out = append(out, "PathPrefix /")
+		}
+		for _, m := range matches {
+			mm, _ := m.(map[string]interface{})
+			t, _, _ := unstructured.NestedString(mm, "path", "type")
+			v, _, _ := unstructured.NestedString(mm, "path", "value")
+			out = append(out, orDefault(t, "PathPrefix")+" "+orDefault(v, "/"))
+		}
+	}
+	return out
+}
+
+// listenerAllowsRoute reports whether a listener's allowedRoutes admit an
+// HTTPRoute from routeNs. Selector-based rules cannot be evaluated here and
+// are treated as allowing the route.
+func listenerAllowsRoute(listener map[string]interface{}, gwNs, routeNs string) (bool, string) {
+	kinds, _, _ := unstructured.NestedSlice(listener, "allowedRoutes", "kinds")
+	if len(kinds) > 0 {
+		allowed := false
+		for _, k := range kinds {
+			km, _ := k.(map[string]interface{})
+			allowed = allowed || km["kind"] == "HTTPRoute"
+		}
+		if !allowed {
+			return false, "allowedRoutes.kinds does not include HTTPRoute"
+		}
+	}
+	from, _, _ := unstructured.NestedString(listener, "allowedRoutes", "namespaces", "from")
+	if orDefault(from, "Same") == "Same" && routeNs != gwNs {
+		return false, fmt.Sprintf("allowedRoutes.namespaces.from is Same, so only routes in %s attach", gwNs)
+	}
+	return true, ""
+}
+
+// routeParentRejection returns the reason a route's status reports it as not
+// accepted by a Gateway, or "".
+func routeParentRejection(route routeInfo, gwNs, gwName string) string {
+	parents, _, _ := unstructured.NestedSlice(route.obj, "status", "parents")
+	for _, p := range parents {
+		pm, _ := p.(map[string]interface{})
+		name, _, _ := unstructured.NestedString(pm, "parentRef", "name")
+		ns, _, _ := unstructured.NestedString(pm, "parentRef", "namespace")
+		if name != gwName || orDefault(ns, route.namespace) != gwNs {
+			continue
+		}
+		conds, _ := pm["conditions"].([]interface{})
+		for _, c := range conds {
+			cm, _ := c.(map[string]interface{})
+			if cm["type"] == "Accepted" && cm["status"] == "False" {
+				reason, _ := cm["reason"].(string)
+				message, _ := cm["message"].(string)
+				return strings.TrimSpace(reason + " " + message)
+			}
+		}
+	}
+	return ""
+}
+
+// routeHostMatches reports whether a route (or VirtualService) with the
+// given hostnames serves host; no hostnames means any host.
+func routeHostMatches(hostnames []string, host string) bool {
+	if len(hostnames) == 0 {
+		return true
+	}
+	for _, h := range hostnames {
+		if hostnameMatches(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClusterLocalHost reports whether an Istio destination host names a
+// Kubernetes Service: "svc", "svc.ns" or "svc.ns.svc.cluster.local".
+func isClusterLocalHost(host string) bool {
+	return strings.HasSuffix(host, ".svc.cluster.local") || strings.Count(host, ".") <= 1
+}
+
+// --- triage_404 ---
+
+type Triage404Tool struct{ BaseTool }
+
+func (t *Triage404Tool) Name() string { return "triage_404" }
+func (t *Triage404Tool) Description() string {
+	return "Find why a host and path return 404 (Envoy response flag NR, no route) at the ingress: checks Istio Gateway server hosts and ports, VirtualService gateway binding, host and path matching and server protocol, and Gateway API listener hostnames, HTTPRoute attachment and path matches in one pass, and reports the specific mismatch"
+}
+func (t *Triage404Tool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"host": map[string]interface{}{
+				"type":        "string",
+				"description": "Host the client requests (Host/:authority header), or the full URL, e.g. https://shop.example.com/api/cart",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Request path (default /, or the path of the URL)",
+			},
+			"port": map[string]interface{}{
+				"type":        "integer",
+				"description": "Gateway port the request arrives on (default: any port, or the URL scheme's port)",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace of the gateways to check (empty for all namespaces)",
+			},
+		},
+		"required": []string{"host"},
+	}
+}
+
+func (t *Triage404Tool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	req, err := parseTriageRequest(getStringArg(args, "host", ""), getStringArg(args, "path", ""), int64(getIntArg(args, "port", 0)))
+	if err != nil {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: fmt.Sprintf("invalid host: %v", err),
+			Detail:  "pass a hostname such as shop.example.com, or a URL",
+		}
+	}
+	ns := getStringArg(args, "namespace", "")
+
+	var findings []types.DiagnosticFinding
+	istioFindings, istioGateways := t.triageIstio(ctx, req, ns)
+	findings = append(findings, istioFindings...)
+	gwFindings, gatewayListeners := t.triageGatewayAPI(ctx, req, ns)
+	findings = append(findings, gwFindings...)
+
+	if istioGateways == 0 && gatewayListeners == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoListener,
+			Summary:    fmt.Sprintf("No Istio Gateway or Gateway API HTTP listener found in %s", orDefault(ns, "any namespace")),
+			Suggestion: "Check the namespace, or whether the request reaches an Ingress controller instead (list_ingresses).",
+		})
+	}
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, orDefault(ns, "all"), "istio"), nil
+}
+
+// istioServer is a server of an Istio Gateway that accepts the request host.
+type istioServer struct {
+	gw       *unstructured.Unstructured
+	ref      string // namespace/name
+	index    int
+	port     int64
+	protocol string
+	tlsMode  string
+	scopes   []string // namespace parts of the matching hosts
+}
+
+func (s istioServer) label() string {
+	return fmt.Sprintf("Gateway %s server %d (%s %d)", s.ref, s.index, s.protocol, s.port)
+}
+
+// triageIstio follows the request through Istio Gateways and VirtualServices.
+// It returns the findings and the number of Istio Gateways checked.
+func (t *Triage404Tool) triageIstio(ctx context.Context, req triageRequest, ns string) ([]types.DiagnosticFinding, int) {
+	gws, err := t.listResourceWithFallback(ctx, istioGatewayV1GVR, istioGatewayV1B1GVR, ns)
+	if err != nil || len(gws.Items) == 0 {
+		return nil, 0
+	}
+
+	var servers []istioServer
+	var otherHosts, otherPorts []string
+	for i := range gws.Items {
+		gw := &gws.Items[i]
+		ref := gw.GetNamespace() + "/" + gw.GetName()
+		list, _, _ := unstructured.NestedSlice(gw.Object, "spec", "servers")
+		for si, s := range list {
+			sm, _ := s.(map[string]interface{})
+			port, _, _ := unstructured.NestedInt64(sm, "port", "number")
+			protocol, _, _ := unstructured.NestedString(sm, "port", "protocol")
+			tlsMode, _, _ := unstructured.NestedString(sm, "tls", "mode")
+			hosts, _, _ := unstructured.NestedStringSlice(sm, "hosts")
+			var scopes []string
+			for _, h := range hosts {
+				scope, dnsName := istioServerHost(h)
+				if hostnameMatches(dnsName, req.Host) {
+					scopes = append(scopes, scope)
+				}
+			}
+			switch {
+			case len(scopes) == 0:
+				otherHosts = append(otherHosts, fmt.Sprintf("%s:%d %s", ref, port, strings.Join(hosts, ",")))
+			case req.Port > 0 && port != req.Port:
+				otherPorts = append(otherPorts, fmt.Sprintf("%s:%d", ref, port))
+			default:
+				servers = append(servers, istioServer{gw: gw, ref: ref, index: si, port: port, protocol: strings.ToUpper(protocol), tlsMode: tlsMode, scopes: scopes})
+			}
+		}
+	}
+
+	if len(servers) == 0 {
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoListener,
+			Summary:    fmt.Sprintf("No Istio Gateway server accepts %s", req),
+			Detail:     "server hosts: " + truncateList(otherHosts, 10),
+			Suggestion: fmt.Sprintf("Add %s (or a matching wildcard) to the hosts of a Gateway server on the port the client uses.", req.Host),
+		}
+		if len(otherPorts) > 0 {
+			f.Summary = fmt.Sprintf("Istio Gateway servers accept %s only on other ports", req.Host)
+			f.Detail = fmt.Sprintf("request port %d; servers: %s", req.Port, strings.Join(otherPorts, ", "))
+			f.Suggestion = "Check the port the client connects to against the Gateway service port mapping, or add a server on that port."
+		}
+		return []types.DiagnosticFinding{f}, len(gws.Items)
+	}
+
+	vsList, err := t.listResourceWithFallback(ctx, vsV1GVR, vsV1B1GVR, "")
+	if err != nil {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityWarning,
+			Category: types.CategoryRouting,
+			Summary:  "Cannot list VirtualServices",
+			Detail:   err.Error(),
+		}}, len(gws.Items)
+	}
+	// VirtualServices serving the host, oldest first: Istio merges the
+	// routes of several VirtualServices for a gateway host in that order.
+	var hostVS []*unstructured.Unstructured
+	for i := range vsList.Items {
+		hosts, _, _ := unstructured.NestedStringSlice(vsList.Items[i].Object, "spec", "hosts")
+		if len(hosts) > 0 && routeHostMatches(hosts, req.Host) {
+			hostVS = append(hostVS, &vsList.Items[i])
+		}
+	}
+	sort.SliceStable(hostVS, func(i, j int) bool {
+		return hostVS[i].GetCreationTimestamp().Time.Before(hostVS[j].GetCreationTimestamp().Time)
+	})
+
+	var findings []types.DiagnosticFinding
+	for _, s := range servers {
+		findings = append(findings, t.triageIstioServer(ctx, req, s, hostVS)...)
+	}
+	return findings, len(gws.Items)
+}
+
+func (t *Triage404Tool) triageIstioServer(ctx context.Context, req triageRequest, s istioServer, hostVS []*unstructured.Unstructured) []types.DiagnosticFinding {
+	gwRef := &types.ResourceRef{Kind: "Gateway", Namespace: s.gw.GetNamespace(), Name: s.gw.GetName(), APIVersion: s.gw.GetAPIVersion()}
+
+	switch {
+	case s.tlsMode == "PASSTHROUGH" || s.tlsMode == "AUTO_PASSTHROUGH" || s.protocol == "TLS" && s.tlsMode != "SIMPLE" && s.tlsMode != "MUTUAL":
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNotHTTPServer,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("%s passes TLS through: VirtualService http routes do not apply", s.label()),
+			Detail:     fmt.Sprintf("tls.mode=%s; the gateway routes on SNI with tls routes and the backend answers the request", orDefault(s.tlsMode, "unset")),
+			Suggestion: "If the 404 comes from the backend, check the backend; to route HTTP paths at the gateway, terminate TLS (tls.mode SIMPLE) and use http routes.",
+		}}
+	case s.protocol != "HTTP" && s.protocol != "HTTPS" && s.protocol != "HTTP2" && s.protocol != "GRPC":
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNotHTTPServer,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("%s is not an HTTP server: http routes do not apply", s.label()),
+			Suggestion: "Set port.protocol to HTTP or HTTPS for HTTP traffic; the protocol decides whether the gateway builds an HTTP route table for the port.",
+		}}
+	}
+
+	// VirtualServices bound to this gateway, and why the others are not.
+	var bound []*unstructured.Unstructured
+	var unbound []string
+	for _, vs := range hostVS {
+		refs := vsGatewayRefs(vs)
+		name := vs.GetNamespace() + "/" + vs.GetName()
+		switch {
+		case !containsString(refs, s.ref):
+			unbound = append(unbound, fmt.Sprintf("%s binds to gateways [%s], not %s", name, strings.Join(refs, ", "), s.ref))
+		case !scopeAllows(s.scopes, s.gw.GetNamespace(), vs.GetNamespace()):
+			unbound = append(unbound, fmt.Sprintf("%s is in namespace %s, which the server hosts [%s/...] do not select", name, vs.GetNamespace(), strings.Join(s.scopes, ",")))
+		default:
+			bound = append(bound, vs)
+		}
+	}
+	if len(bound) == 0 {
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoRouteForHost,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("%s accepts %s but no VirtualService bound to it serves the host", s.label(), req.Host),
+			Detail:     "no VirtualService lists the host",
+			Suggestion: fmt.Sprintf("Create a VirtualService with hosts [%s] and gateways [%s].", req.Host, s.ref),
+		}
+		if len(unbound) > 0 {
+			f.Detail = strings.Join(unbound, "; ")
+			f.Suggestion = fmt.Sprintf("Add %s to spec.gateways of the VirtualService, or fix the namespace in the Gateway server hosts.", s.ref)
+		}
+		return []types.DiagnosticFinding{f}
+	}
+
+	// First matching http route across the bound VirtualServices.
+	var tried, conditional []string
+	for _, vs := range bound {
+		vsRef := &types.ResourceRef{Kind: "VirtualService", Namespace: vs.GetNamespace(), Name: vs.GetName(), APIVersion: vs.GetAPIVersion()}
+		vsName := vs.GetNamespace() + "/" + vs.GetName()
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		for ri, r := range routes {
+			rm, _ := r.(map[string]interface{})
+			ok, conds := istioRouteMatch(rm, vs.GetNamespace(), s.ref, req)
+			label := fmt.Sprintf("%s http[%d]", vsName, ri)
+			if name, _ := rm["name"].(string); name != "" {
+				label += " (" + name + ")"
+			}
+			if !ok {
+				matches, _ := rm["match"].([]interface{})
+				for _, m := range matches {
+					mm, _ := m.(map[string]interface{})
+					tried = append(tried, describeIstioURIMatch(mm))
+				}
+				continue
+			}
+			if len(conds) > 0 {
+				conditional = append(conditional, fmt.Sprintf("%s also requires %s", label, strings.Join(conds, ", ")))
+				continue
+			}
+			return t.istioMatchedRoute(ctx, req, s, vsRef, label, rm, conditional)
+		}
+	}
+
+	f := types.DiagnosticFinding{
+		Severity:   types.SeverityCritical,
+		Category:   types.CategoryRouting,
+		Code:       types.CodeTriageNoRouteMatch,
+		Resource:   gwRef,
+		Summary:    fmt.Sprintf("No http route matches %s: the gateway returns 404 (NR)", req),
+		Detail:     fmt.Sprintf("VirtualServices %s; uri matches: %s", vsNames(bound), truncateList(sortedUnique(tried), 10)),
+		Suggestion: "Add a match for the path (prefix matches are plain string prefixes), or a final route without match as a catch-all.",
+	}
+	if len(bound) > 1 {
+		f.Detail += "; routes of several VirtualServices for one gateway host are merged oldest first"
+	}
+	if len(conditional) > 0 {
+		f.Detail += "; conditional matches: " + strings.Join(conditional, "; ")
+		f.Suggestion = "The path matches only with extra conditions; check that the request carries the headers, method or query parameters those matches require."
+	}
+	return []types.DiagnosticFinding{f}
+}
+
+// istioMatchedRoute reports the route that serves the request and checks
+// what it does with it.
+func (t *Triage404Tool) istioMatchedRoute(ctx context.Context, req triageRequest, s istioServer, vsRef *types.ResourceRef, label string, route map[string]interface{}, conditional []string) []types.DiagnosticFinding {
+	if status, ok, _ := unstructured.NestedInt64(route, "directResponse", "status"); ok {
+		sev := types.SeverityInfo
+		var code types.FindingCode
+		if status == 404 {
+			sev = types.SeverityCritical
+			code = types.CodeTriageDirectResponse
+		}
+		return []types.DiagnosticFinding{{
+			Severity:   sev,
+			Category:   types.CategoryRouting,
+			Code:       code,
+			Resource:   vsRef,
+			Summary:    fmt.Sprintf("%s matches %s and returns a direct response %d", label, req, status),
+			Suggestion: "The 404 is configured: an earlier route with directResponse catches the path. Move the intended route above it or narrow its match.",
+		}}
+	}
+	if _, ok := route["redirect"]; ok {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Resource:   vsRef,
+			Summary:    fmt.Sprintf("%s matches %s and redirects it", label, req),
+			Suggestion: "Check that the redirect target is itself routed.",
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	var dests []string
+	dsts, _ := route["route"].([]interface{})
+	for _, d := range dsts {
+		dm, _ := d.(map[string]interface{})
+		host, _, _ := unstructured.NestedString(dm, "destination", "host")
+		dests = append(dests, host)
+		if !isClusterLocalHost(host) {
+			continue
+		}
+		svcNs, svcName := resolveIstioHost(host, vsRef.Namespace)
+		if _, err := t.Clients.Dynamic.Resource(servicesGVR).Namespace(svcNs).Get(ctx, svcName, metav1.GetOptions{}); err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryRouting,
+				Code:       types.CodeTriageBackendServiceMissing,
+				Resource:   vsRef,
+				Summary:    fmt.Sprintf("%s routes to %s, but Service %s/%s does not exist", label, host, svcNs, svcName),
+				Detail:     "short hosts resolve in the VirtualService namespace",
+				Suggestion: "Use the Service FQDN (name.namespace.svc.cluster.local) or fix the name.",
+			})
+		}
+	}
+	detail := "route " + s.label()
+	if len(conditional) > 0 {
+		detail += "; earlier conditional matches: " + strings.Join(conditional, "; ")
+	}
+	ok := types.DiagnosticFinding{
+		Severity: types.SeverityOK,
+		Category: types.CategoryRouting,
+		Resource: vsRef,
+		Summary:  fmt.Sprintf("%s serves %s via %s -> %s", s.label(), req, label, strings.Join(dests, ", ")),
+		Detail:   detail,
+	}
+	if len(findings) == 0 {
+		ok.Suggestion = "Istio routes the request; a 404 then comes from the backend itself (check its access log for response flags other than NR)."
+	}
+	return append([]types.DiagnosticFinding{ok}, findings...)
+}
+
+func scopeAllows(scopes []string, gwNs, vsNs string) bool {
+	for _, s := range scopes {
+		if istioNamespaceAllowed(s, gwNs, vsNs) {
+			return true
+		}
+	}
+	return false
+}
+
+func vsNames(vss []*unstructured.Unstructured) string {
+	names := make([]string, 0, len(vss))
+	for _, vs := range vss {
+		names = append(names, vs.GetNamespace()+"/"+vs.GetName())
+	}
+	return strings.Join(names, ", ")
+}
+
+func sortedUnique(items []string) []string {
+	set := make(map[string]bool, len(items))
+	for _, s := range items {
+		set[s] = true
+	}
+	return sortedSet(set)
+}
+
+// gatewayListener is a Gateway API listener that accepts the request host.
+type gatewayListener struct {
+	gw   *unstructured.Unstructured
+	spec map[string]interface{}
+	name string
+	port int64
+}
+
+func (l gatewayListener) label() string {
+	return fmt.Sprintf("Gateway %s/%s listener %s (port %d)", l.gw.GetNamespace(), l.gw.GetName(), l.name, l.port)
+}
+
+// triageGatewayAPI follows the request through Gateway API listeners and
+// HTTPRoutes. It returns the findings and the number of HTTP listeners checked.
+func (t *Triage404Tool) triageGatewayAPI(ctx context.Context, req triageRequest, ns string) ([]types.DiagnosticFinding, int) {
+	gws, err := t.listResourceWithFallback(ctx, gatewaysV1GVR, gatewaysV1B1GVR, ns)
+	if err != nil {
+		return nil, 0
+	}
+	var listeners []gatewayListener
+	var others []string
+	total := 0
+	for i := range gws.Items {
+		gw := &gws.Items[i]
+		list, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+		for _, l := range list {
+			lm, _ := l.(map[string]interface{})
+			protocol, _ := lm["protocol"].(string)
+			if protocol != "HTTP" && protocol != "HTTPS" {
+				continue
+			}
+			total++
+			gl := gatewayListener{gw: gw, spec: lm}
+			gl.name, _ = lm["name"].(string)
+			gl.port, _, _ = unstructured.NestedInt64(lm, "port")
+			hostname, _ := lm["hostname"].(string)
+			if !hostnameMatches(hostname, req.Host) || (req.Port > 0 && gl.port != req.Port) {
+				others = append(others, fmt.Sprintf("%s/%s %s:%d", gw.GetNamespace(), gw.GetName(), orDefault(hostname, "*"), gl.port))
+				continue
+			}
+			listeners = append(listeners, gl)
+		}
+	}
+	if total == 0 {
+		return nil, 0
+	}
+	if len(listeners) == 0 {
+		return []types.DiagnosticFinding{{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoListener,
+			Summary:    fmt.Sprintf("No Gateway API HTTP listener accepts %s", req),
+			Detail:     "listeners: " + truncateList(others, 10),
+			Suggestion: "Add a listener whose hostname matches the host on the port the client uses, or leave hostname empty to accept any host.",
+		}}, total
+	}
+
+	var hostRoutes []routeInfo
+	for _, r := range t.listRoutes(ctx) {
+		hostnames, _, _ := unstructured.NestedStringSlice(r.obj, "spec", "hostnames")
+		if r.kind == "HTTPRoute" && routeHostMatches(hostnames, req.Host) {
+			hostRoutes = append(hostRoutes, r)
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, l := range listeners {
+		findings = append(findings, triageListener(req, l, hostRoutes)...)
+	}
+	return findings, total
+}
+
+// pathCandidate is an HTTPRoute rule whose match accepts the request path.
+type pathCandidate struct {
+	route     routeInfo
+	rule      int
+	matchType string
+	value     string
+}
+
+// precedes orders candidates by Gateway API match precedence: Exact, then the
+// longest PathPrefix, then regular expressions.
+func (c pathCandidate) precedes(o pathCandidate) bool {
+	rank := func(p pathCandidate) int {
+		switch p.matchType {
+		case "Exact":
+			return 0
+		case "PathPrefix":
+			return 1
+		}
+		return 2
+	}
+	if rank(c) != rank(o) {
+		return rank(c) < rank(o)
+	}
+	return len(c.value) > len(o.value)
+}
+
+func triageListener(req triageRequest, l gatewayListener, hostRoutes []routeInfo) []types.DiagnosticFinding {
+	gwNs, gwName := l.gw.GetNamespace(), l.gw.GetName()
+	gwRef := &types.ResourceRef{Kind: "Gateway", Namespace: gwNs, Name: gwName, APIVersion: l.gw.GetAPIVersion()}
+
+	var attached []routeInfo
+	var rejected []string
+	for _, r := range hostRoutes {
+		name := r.namespace + "/" + r.name
+		names, all := routeListenerNames(r, gwNs, gwName)
+		if !all && !names[l.name] {
+			if routeAttachedToGateway(r, gwNs, gwName) {
+				rejected = append(rejected, fmt.Sprintf("%s attaches to listener(s) %s", name, joinKeys(names)))
+			} else {
+				rejected = append(rejected, fmt.Sprintf("%s does not reference %s/%s in parentRefs", name, gwNs, gwName))
+			}
+			continue
+		}
+		if ok, reason := listenerAllowsRoute(l.spec, gwNs, r.namespace); !ok {
+			rejected = append(rejected, fmt.Sprintf("%s: %s", name, reason))
+			continue
+		}
+		if reason := routeParentRejection(r, gwNs, gwName); reason != "" {
+			rejected = append(rejected, fmt.Sprintf("%s is not accepted: %s", name, reason))
+			continue
+		}
+		attached = append(attached, r)
+	}
+	if len(attached) == 0 {
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoRouteForHost,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("%s accepts %s but no HTTPRoute attached to it serves the host", l.label(), req.Host),
+			Detail:     "no HTTPRoute lists the host",
+			Suggestion: fmt.Sprintf("Create an HTTPRoute with hostnames [%s] and a parentRef to %s/%s.", req.Host, gwNs, gwName),
+		}
+		if len(rejected) > 0 {
+			f.Detail = strings.Join(rejected, "; ")
+			f.Suggestion = "Fix the route's parentRefs (name, namespace, sectionName) or the listener's allowedRoutes."
+		}
+		return []types.DiagnosticFinding{f}
+	}
+
+	var best *pathCandidate
+	var conditional, tried []string
+	for _, r := range attached {
+		rules, _, _ := unstructured.NestedSlice(r.obj, "spec", "rules")
+		for ri, rule := range rules {
+			rm, _ := rule.(map[string]interface{})
+			matchType, value, conds, ok := httpRouteRuleMatch(rm, req.Path)
+			if !ok {
+				continue
+			}
+			if len(conds) > 0 {
+				conditional = append(conditional, fmt.Sprintf("%s/%s rule %d also requires %s", r.namespace, r.name, ri, strings.Join(conds, ", ")))
+				continue
+			}
+			c := pathCandidate{route: r, rule: ri, matchType: matchType, value: value}
+			if best == nil || c.precedes(*best) {
+				best = &c
+			}
+		}
+		tried = append(tried, describeHTTPRouteMatches(r)...)
+	}
+
+	if best == nil {
+		f := types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeTriageNoRouteMatch,
+			Resource:   gwRef,
+			Summary:    fmt.Sprintf("No HTTPRoute rule on %s matches %s: the gateway returns 404", l.label(), req),
+			Detail:     fmt.Sprintf("routes %s; path matches: %s", routeNames(attached), truncateList(sortedUnique(tried), 10)),
+			Suggestion: "Add a rule matching the path. PathPrefix matches whole segments: /api matches /api/v1 but not /apis.",
+		}
+		if len(conditional) > 0 {
+			f.Detail += "; conditional matches: " + strings.Join(conditional, "; ")
+			f.Suggestion = "The path matches only with extra conditions; check that the request carries the headers, method or query parameters those rules require."
+		}
+		return []types.DiagnosticFinding{f}
+	}
+
+	rules, _, _ := unstructured.NestedSlice(best.route.obj, "spec", "rules")
+	rm, _ := rules[best.rule].(map[string]interface{})
+	backends := extractBackendRefs(rm)
+	routeRef := &types.ResourceRef{Kind: "HTTPRoute", Namespace: best.route.namespace, Name: best.route.name, APIVersion: "gateway.networking.k8s.io/v1"}
+	return []types.DiagnosticFinding{{
+		Severity:   types.SeverityOK,
+		Category:   types.CategoryRouting,
+		Resource:   routeRef,
+		Summary:    fmt.Sprintf("%s serves %s via HTTPRoute %s/%s rule %d (%s %s) -> %s", l.label(), req, best.route.namespace, best.route.name, best.rule, best.matchType, best.value, orDefault(strings.Join(backends, ", "), "no backends")),
+		Suggestion: "The gateway routes the request; a 404 then comes from the backend itself, e.g. a path the application does not serve after URL rewrites.",
+	}}
+}
+
+func routeNames(routes []routeInfo) string {
+	names := make([]string, 0, len(routes))
+	for _, r := range routes {
+		names = append(names, r.namespace+"/"+r.name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+func TestParseTriageRequest(t *testing.T) {
+	cases := []struct {
+		host, path string
+		port       int64
+		want       triageRequest
+	}{
+		{"Shop.Example.com", "", 0, triageRequest{Host: "shop.example.com", Path: "/"}},
+		{"https://shop.example.com/api/cart?id=1", "", 0, triageRequest{Host: "shop.example.com", Path: "/api/cart", Port: 443}},
+		{"shop.example.com:8443", "api", 0, triageRequest{Host: "shop.example.com", Path: "/api", Port: 8443}},
+		{"http://shop.example.com:8080", "/x", 0, triageRequest{Host: "shop.example.com", Path: "/x", Port: 8080}},
+	}
+	for _, c := range cases {
+		got, err := parseTriageRequest(c.host, c.path, c.port)
+		if err != nil || got != c.want {
+			t.Errorf("parseTriageRequest(%q, %q) = %+v, %v; want %+v", c.host, c.path, got, err, c.want)
+		}
+	}
+	if _, err := parseTriageRequest("", "/", 0); err == nil {
+		t.Error("expected an error for an empty host")
+	}
+}
+
+func TestGatewayPathMatches(t *testing.T) {
+	cases := []struct {
+		matchType, value, path string
+		want                   bool
+	}{
+		{"PathPrefix", "/", "/anything", true},
+		{"PathPrefix", "/api", "/api", true},
+		{"PathPrefix", "/api/", "/api/v1", true},
+		{"PathPrefix", "/api", "/apis", false},
+		{"Exact", "/api", "/api/", false},
+		{"RegularExpression", "/v[0-9]+/.*", "/v2/users", true},
+		{"RegularExpression", "/v[0-9]+", "/v2/users", false},
+	}
+	for _, c := range cases {
+		if got := gatewayPathMatches(c.matchType, c.value, c.path); got != c.want {
+			t.Errorf("gatewayPathMatches(%s %s, %s) = %v, want %v", c.matchType, c.value, c.path, got, c.want)
+		}
+	}
+}
+
+func TestIstioRouteMatch(t *testing.T) {
+	req := triageRequest{Host: "shop.example.com", Path: "/API/cart", Port: 443}
+	route := func(match ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"match": match}
+	}
+	uri := func(kind, v string) map[string]interface{} {
+		return map[string]interface{}{"uri": map[string]interface{}{kind: v}}
+	}
+
+	if ok, _ := istioRouteMatch(map[string]interface{}{}, "shop", "infra/public", req); !ok {
+		t.Error("a route without match should match every request")
+	}
+	if ok, _ := istioRouteMatch(route(uri("prefix", "/api")), "shop", "infra/public", req); ok {
+		t.Error("prefix matches are case sensitive")
+	}
+	ci := uri("prefix", "/api")
+	ci["ignoreUriCase"] = true
+	if ok, _ := istioRouteMatch(route(ci), "shop", "infra/public", req); !ok {
+		t.Error("ignoreUriCase should match /API")
+	}
+	if ok, _ := istioRouteMatch(route(map[string]interface{}{"port": int64(80)}), "shop", "infra/public", req); ok {
+		t.Error("a match on another port should not match")
+	}
+	scoped := uri("regex", "/API/.*")
+	scoped["gateways"] = []interface{}{"other"}
+	if ok, _ := istioRouteMatch(route(scoped), "shop", "infra/public", req); ok {
+		t.Error("a match scoped to another gateway should not match")
+	}
+	withHeader := uri("exact", "/API/cart")
+	withHeader["headers"] = map[string]interface{}{"x-canary": map[string]interface{}{"exact": "1"}}
+	if ok, conds := istioRouteMatch(route(withHeader), "shop", "infra/public", req); !ok || len(conds) != 1 || conds[0] != "headers" {
+		t.Errorf("expected a conditional match on headers, got %v %v", ok, conds)
+	}
+}
+
+func TestIstioNamespaceAllowed(t *testing.T) {
+	cases := []struct {
+		scope string
+		want  bool
+	}{
+		{"*", true}, {".", false}, {"shop", true}, {"other", false}, {"~", false},
+	}
+	for _, c := range cases {
+		if got := istioNamespaceAllowed(c.scope, "infra", "shop"); got != c.want {
+			t.Errorf("istioNamespaceAllowed(%q) = %v, want %v", c.scope, got, c.want)
+		}
+	}
+}
+
+func newTriageTestTool(t *testing.T, gateways map[schema.GroupVersionResource][]*unstructured.Unstructured, objects ...runtime.Object) *Triage404Tool {
+	t.Helper()
+	listKinds := map[schema.GroupVersionResource]string{
+		gatewaysV1GVR: "GatewayList", gatewaysV1B1GVR: "GatewayList",
+		httpRoutesV1GVR: "HTTPRouteList", httpRoutesV1B1GVR: "HTTPRouteList",
+		grpcRoutesV1GVR: "GRPCRouteList", grpcRoutesV1B1GVR: "GRPCRouteList",
+		istioGatewayV1GVR: "GatewayList", istioGatewayV1B1GVR: "GatewayList",
+		vsV1GVR: "VirtualServiceList", vsV1B1GVR: "VirtualServiceList",
+		servicesGVR: "ServiceList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	// The fake tracker would guess the resource "gatewaies" from the kind.
+	for gvr, gws := range gateways {
+		for _, gw := range gws {
+			if err := client.Tracker().Create(gvr, gw, gw.GetNamespace()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return &Triage404Tool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+}
+
+func triageObject(apiVersion, kind, ns, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(ns)
+	u.SetName(name)
+	return u
+}
+
+func runTriage(t *testing.T, tool *Triage404Tool, args map[string]interface{}) []types.DiagnosticFinding {
+	t.Helper()
+	resp, err := tool.Run(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.(*types.ToolResult).Findings
+}
+
+func TestTriage404_Istio(t *testing.T) {
+	gw := triageObject("networking.istio.io/v1", "Gateway", "infra", "public", map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{
+			"port":  map[string]interface{}{"number": int64(443), "protocol": "HTTPS", "name": "https"},
+			"hosts": []interface{}{"*/*.example.com"},
+			"tls":   map[string]interface{}{"mode": "SIMPLE"},
+		}},
+	})
+	vs := triageObject("networking.istio.io/v1", "VirtualService", "shop", "shop", map[string]interface{}{
+		"hosts":    []interface{}{"shop.example.com"},
+		"gateways": []interface{}{"infra/public"},
+		"http": []interface{}{map[string]interface{}{
+			"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}},
+			"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "cart"}}},
+		}},
+	})
+	unbound := triageObject("networking.istio.io/v1", "VirtualService", "blog", "blog", map[string]interface{}{
+		"hosts":    []interface{}{"blog.example.com"},
+		"gateways": []interface{}{"public"},
+	})
+	tool := newTriageTestTool(t, map[schema.GroupVersionResource][]*unstructured.Unstructured{istioGatewayV1GVR: {gw}}, vs, unbound)
+
+	cases := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"host": "shop.example.org"}, "critical No Istio Gateway server accepts shop.example.org/"},
+		{map[string]interface{}{"host": "shop.example.com", "port": 80}, "critical Istio Gateway servers accept shop.example.com only on other ports"},
+		{map[string]interface{}{"host": "blog.example.com"}, "critical Gateway infra/public server 0 (HTTPS 443) accepts blog.example.com but no VirtualService bound to it serves the host"},
+		{map[string]interface{}{"host": "https://shop.example.com/checkout"}, "critical No http route matches shop.example.com:443/checkout"},
+		{map[string]interface{}{"host": "shop.example.com", "path": "/api/cart"}, "ok Gateway infra/public server 0 (HTTPS 443) serves shop.example.com/api/cart via shop/shop http[0] -> cart"},
+	}
+	for _, c := range cases {
+		findings := runTriage(t, tool, c.args)
+		var got []string
+		for _, f := range findings {
+			got = append(got, string(f.Severity)+" "+f.Summary)
+		}
+		if !strings.HasPrefix(strings.Join(got, "\n"), c.want) {
+			t.Errorf("%v: expected %q, got:\n%s", c.args, c.want, strings.Join(got, "\n"))
+		}
+	}
+
+	// The destination Service does not exist.
+	findings := runTriage(t, tool, map[string]interface{}{"host": "shop.example.com", "path": "/api"})
+	if len(findings) != 2 || !strings.Contains(findings[1].Summary, "Service shop/cart does not exist") {
+		t.Errorf("expected a missing destination finding, got %+v", findings)
+	}
+	// A short gateway name resolves in the VirtualService namespace.
+	findings = runTriage(t, tool, map[string]interface{}{"host": "blog.example.com"})
+	if !strings.Contains(findings[0].Detail, "blog/blog binds to gateways [blog/public], not infra/public") {
+		t.Errorf("expected the binding mismatch in detail, got %q", findings[0].Detail)
+	}
+}
+
+func TestTriage404_GatewayAPI(t *testing.T) {
+	gw := triageObject("gateway.networking.k8s.io/v1", "Gateway", "infra", "public", map[string]interface{}{
+		"listeners": []interface{}{
+			map[string]interface{}{"name": "http", "protocol": "HTTP", "port": int64(80), "hostname": "*.example.com",
+				"allowedRoutes": map[string]interface{}{"namespaces": map[string]interface{}{"from": "All"}}},
+			map[string]interface{}{"name": "internal", "protocol": "HTTP", "port": int64(8080)},
+		},
+	})
+	route := triageObject("gateway.networking.k8s.io/v1", "HTTPRoute", "shop", "shop", map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "infra", "sectionName": "http"}},
+		"hostnames":  []interface{}{"shop.example.com"},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "api", "port": int64(8080)}},
+			},
+			map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "Exact", "value": "/api/health"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "health", "port": int64(8080)}},
+			},
+		},
+	})
+	tool := newTriageTestTool(t, map[schema.GroupVersionResource][]*unstructured.Unstructured{gatewaysV1GVR: {gw}}, route)
+
+	cases := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"host": "shop.example.com", "path": "/apis"}, "critical No HTTPRoute rule on Gateway infra/public listener http (port 80) matches shop.example.com/apis"},
+		{map[string]interface{}{"host": "shop.example.com", "path": "/api/health"}, "ok Gateway infra/public listener http (port 80) serves shop.example.com/api/health via HTTPRoute shop/shop rule 1 (Exact /api/health)"},
+		{map[string]interface{}{"host": "shop.example.com", "path": "/api/v1", "port": 8080}, "critical Gateway infra/public listener internal (port 8080) accepts shop.example.com but no HTTPRoute attached to it serves the host"},
+		{map[string]interface{}{"host": "shop.example.com", "port": 443}, "critical No Gateway API HTTP listener accepts shop.example.com:443/"},
+	}
+	for _, c := range cases {
+		findings := runTriage(t, tool, c.args)
+		var got []string
+		for _, f := range findings {
+			got = append(got, string(f.Severity)+" "+f.Summary)
+		}
+		if !strings.HasPrefix(strings.Join(got, "\n"), c.want) {
+			t.Errorf("%v: expected %q, got:\n%s", c.args, c.want, strings.Join(got, "\n"))
+		}
+	}
+}
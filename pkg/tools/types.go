@@ -10,6 +10,7 @@ import (
 
 	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/probes"
 	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
 )
 
@@ -25,6 +26,15 @@ type StandardResponse struct {
 	Timestamp string      `json:"timestamp"`
 	Tool      string      `json:"tool"`
 	Data      interface{} `json:"data"`
+	// Continue is set when a paginated list has more items; pass it back as
+	// the continue_token argument to fetch the next page.
+	Continue string `json:"continue_token,omitempty"`
+	// Errors lists the resource types that could not be listed. When set,
+	// the results are partial: the tool carried on without those types.
+	Errors []ResourceError `json:"errors,omitempty"`
+	// Deprecation is set when the tool was called by a deprecated alias;
+	// see DeprecationNote.
+	Deprecation string `json:"deprecation,omitempty"`
 }
 
 func NewResponse(cfg *config.Config, toolName string, data interface{}) *StandardResponse {
@@ -39,6 +49,11 @@ func NewResponse(cfg *config.Config, toolName string, data interface{}) *Standar
 type BaseTool struct {
 	Cfg     *config.Config
 	Clients *k8s.Clients
+	// Snapshot, when set, shares List results between tools (see ClusterSnapshot).
+	Snapshot *ClusterSnapshot
+	// Probes, when set, runs probe pods for provider tools, which are built
+	// from a BaseTool alone and so cannot take a ProbeManager of their own.
+	Probes *probes.Manager
 }
 
 func getStringArg(args map[string]interface{}, key string, defaultVal string) string {
@@ -84,6 +99,20 @@ func NewToolResultResponse(cfg *config.Config, toolName string, findings []types
 // If Data is a *types.ToolResult, uses the structured text formatter.
 // Otherwise falls back to a simple key=value format.
 func (r *StandardResponse) ToText() string {
+	text := r.dataText()
+	if len(r.Errors) > 0 {
+		text += "\n" + partialText(r.Errors)
+	}
+	if r.Continue != "" {
+		text += "\nMore results available: call again with continue_token=" + r.Continue
+	}
+	if r.Deprecation != "" {
+		text += "\n" + r.Deprecation
+	}
+	return text
+}
+
+func (r *StandardResponse) dataText() string {
 	header := fmt.Sprintf("[%s] %s", r.Tool, r.Cluster)
 
 	if tr, ok := r.Data.(*types.ToolResult); ok {
@@ -99,6 +128,11 @@ func (r *StandardResponse) ToText() string {
 		return header + " | " + strings.Join(parts, " | ")
 	}
 
+	// Pre-rendered documents (e.g. YAML) are returned as-is
+	if s, ok := r.Data.(string); ok {
+		return header + "\n" + s
+	}
+
 	// Fallback: marshal to JSON but keep it compact (no indent)
 	b, err := json.Marshal(r.Data)
 	if err != nil {
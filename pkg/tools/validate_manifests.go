@@ -0,0 +1,369 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+// manifestKind is a kind validate_manifests understands. Context kinds are
+// loaded so references to them resolve but are not validated themselves.
+type manifestKind struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+	context    bool
+}
+
+var manifestKinds = map[schema.GroupKind]manifestKind{
+	{Group: "gateway.networking.k8s.io", Kind: "Gateway"}:        {gvr: gatewaysV1GVR, namespaced: true},
+	{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}:      {gvr: httpRoutesV1GVR, namespaced: true},
+	{Group: "gateway.networking.k8s.io", Kind: "GRPCRoute"}:      {gvr: grpcRoutesV1GVR, namespaced: true},
+	{Group: "gateway.networking.k8s.io", Kind: "ReferenceGrant"}: {gvr: refGrantsV1GVR, namespaced: true},
+	{Group: "gateway.networking.k8s.io", Kind: "GatewayClass"}:   {gvr: gatewayClassesGVR, context: true},
+	{Group: "networking.istio.io", Kind: "VirtualService"}:       {gvr: vsV1GVR, namespaced: true},
+	{Group: "networking.istio.io", Kind: "DestinationRule"}:      {gvr: drV1GVR, namespaced: true},
+	{Group: "networking.istio.io", Kind: "Gateway"}:              {gvr: istioGatewayV1GVR, namespaced: true, context: true},
+	{Group: "security.istio.io", Kind: "AuthorizationPolicy"}:    {gvr: apV1GVR, namespaced: true},
+	{Group: "security.istio.io", Kind: "PeerAuthentication"}:     {gvr: paV1GVR, namespaced: true, context: true},
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:          {gvr: networkPoliciesGVR, namespaced: true},
+	{Group: "gateway.kgateway.dev", Kind: "RouteOption"}:         {gvr: routeOptionGVR, namespaced: true},
+	{Group: "gateway.kgateway.dev", Kind: "VirtualHostOption"}:   {gvr: vhostOptionGVR, namespaced: true},
+	{Group: "kgateway.dev", Kind: "GatewayParameters"}:           {gvr: gatewayParamsGVR, namespaced: true},
+	{Group: "gateway.kgateway.dev", Kind: "Backend"}:             {gvr: backendGVR, namespaced: true},
+	{Group: "gateway.kgateway.dev", Kind: "TrafficPolicy"}:       {gvr: trafficPolicyGVR, namespaced: true},
+	{Group: "gateway.kgateway.dev", Kind: "HTTPListenerPolicy"}:  {gvr: httpListenerPolicyGVR, namespaced: true},
+	{Group: "cilium.io", Kind: "CiliumNetworkPolicy"}:            {gvr: ciliumNPGVR, namespaced: true},
+	{Group: "cilium.io", Kind: "CiliumClusterwideNetworkPolicy"}: {gvr: ciliumCNPGVR},
+	{Kind: "Service"}:   {gvr: servicesGVR, namespaced: true, context: true},
+	{Kind: "Pod"}:       {gvr: podsGVR, namespaced: true, context: true},
+	{Kind: "Namespace"}: {gvr: namespacesGVR, context: true},
+}
+
+// workloadKinds are expanded into a pod from their template so selectors and
+// named ports resolve against workloads that are not deployed yet.
+var workloadKinds = map[schema.GroupKind]bool{
+	{Group: "apps", Kind: "Deployment"}:  true,
+	{Group: "apps", Kind: "StatefulSet"}: true,
+	{Group: "apps", Kind: "DaemonSet"}:   true,
+}
+
+// manifestValidator is an existing validation tool run against the manifests.
+// It runs once when any of kinds is present, or once per object with kind,
+// name and namespace arguments when perObject is set.
+type manifestValidator struct {
+	kinds     []schema.GroupKind
+	perObject bool
+	tool      func(base BaseTool) Tool
+}
+
+var manifestValidators = []manifestValidator{
+	{
+		kinds: []schema.GroupKind{{Group: "gateway.networking.k8s.io", Kind: "Gateway"}, {Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}, {Group: "gateway.networking.k8s.io", Kind: "GRPCRoute"}, {Group: "gateway.networking.k8s.io", Kind: "ReferenceGrant"}},
+		tool:  func(base BaseTool) Tool { return &ScanGatewayMisconfigsTool{BaseTool: base} },
+	},
+	{
+		kinds:     []schema.GroupKind{{Group: "gateway.networking.k8s.io", Kind: "Gateway"}, {Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}, {Group: "gateway.networking.k8s.io", Kind: "GRPCRoute"}},
+		perObject: true,
+		tool:      func(base BaseTool) Tool { return &CheckGatewayConformanceTool{BaseTool: base} },
+	},
+	{
+		kinds: []schema.GroupKind{{Group: "networking.istio.io", Kind: "VirtualService"}, {Group: "networking.istio.io", Kind: "DestinationRule"}},
+		tool:  func(base BaseTool) Tool { return &ValidateIstioConfigTool{BaseTool: base} },
+	},
+	{
+		kinds: []schema.GroupKind{{Group: "security.istio.io", Kind: "AuthorizationPolicy"}},
+		tool:  func(base BaseTool) Tool { return &AnalyzeIstioAuthPolicyTool{BaseTool: base} },
+	},
+	{
+		kinds: []schema.GroupKind{{Group: "networking.k8s.io", Kind: "NetworkPolicy"}},
+		tool:  func(base BaseTool) Tool { return &CheckNetworkPolicyPortsTool{BaseTool: base} },
+	},
+	{
+		kinds:     []schema.GroupKind{{Group: "gateway.kgateway.dev", Kind: "RouteOption"}, {Group: "gateway.kgateway.dev", Kind: "VirtualHostOption"}, {Group: "kgateway.dev", Kind: "GatewayParameters"}, {Group: "gateway.kgateway.dev", Kind: "Backend"}, {Group: "gateway.kgateway.dev", Kind: "TrafficPolicy"}, {Group: "gateway.kgateway.dev", Kind: "HTTPListenerPolicy"}},
+		perObject: true,
+		tool:      func(base BaseTool) Tool { return &ValidateKgatewayResourceTool{BaseTool: base} },
+	},
+	{
+		kinds: []schema.GroupKind{{Group: "cilium.io", Kind: "CiliumNetworkPolicy"}, {Group: "cilium.io", Kind: "CiliumClusterwideNetworkPolicy"}},
+		tool:  func(base BaseTool) Tool { return &ValidateCiliumPolicyTool{BaseTool: base} },
+	},
+}
+
+// liveStateCodes are reported from endpoints, pods or status conditions. They
+// describe the cluster rather than the manifests and are dropped.
+var liveStateCodes = map[types.FindingCode]bool{
+	types.CodeGatewayListenerUnhealthy:          true,
+	types.CodeGatewayConditionFalse:             true,
+	types.CodeGatewayBackendNoEndpoints:         true,
+	types.CodeGatewayRouteConditionFalse:        true,
+	types.CodeGatewayWaypointNotProgrammed:      true,
+	types.CodeGatewayWaypointPodsUnready:        true,
+	types.CodeGatewayWeightedBackendNoEndpoints: true,
+	types.CodeIstioConditionFalse:               true,
+	types.CodeIstioSubsetNoPods:                 true,
+	types.CodeServiceNoEndpoints:                true,
+	types.CodeKgatewayNotAccepted:               true,
+	types.CodeKgatewayConditionFalse:            true,
+	types.CodeKgatewayPodUnhealthy:              true,
+	types.CodeKgatewayGatewayNotProgrammed:      true,
+	types.CodeKgatewayNoDataPlane:               true,
+	types.CodeKgatewayPolicyOverridden:          true,
+	types.CodeCNIEndpointNotEnforcing:           true,
+	types.CodeCNIPolicyInvalid:                  true,
+}
+
+// --- validate_manifests ---
+
+// ValidateManifestsTool lints Gateway API, Istio, NetworkPolicy, kgateway and
+// Cilium manifests before they are applied by running the cluster validators
+// against an in-memory client that serves the parsed objects.
+type ValidateManifestsTool struct{ BaseTool }
+
+func (t *ValidateManifestsTool) Name() string { return "validate_manifests" }
+func (t *ValidateManifestsTool) Description() string {
+	return "Lint multi-document YAML for Gateway API, Istio, NetworkPolicy, kgateway and Cilium policies before applying it, running the same misconfiguration and conformance checks as the cluster tools; references resolve against the manifests and, optionally, the live cluster"
+}
+func (t *ValidateManifestsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"manifests": map[string]interface{}{
+				"type":        "string",
+				"description": "YAML or JSON manifests, multiple documents separated by ---",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace for namespaced objects that do not set one (default: default)",
+			},
+			"include_cluster": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Resolve references (Services, Gateways, ReferenceGrants) against the live cluster as well as the manifests (default: true); false validates the manifests as a self-contained set",
+			},
+		},
+		"required": []string{"manifests"},
+	}
+}
+
+func (t *ValidateManifestsTool) Run(ctx context.Context, args map[string]interface{}) (*StandardResponse, error) {
+	data := getStringArg(args, "manifests", "")
+	defaultNs := getStringArg(args, "namespace", "default")
+	if strings.TrimSpace(data) == "" {
+		return nil, &types.MCPError{
+			Code:    types.ErrCodeInvalidInput,
+			Tool:    t.Name(),
+			Message: "manifests is required",
+		}
+	}
+
+	objs, findings := parseManifests(data)
+
+	var live dynamic.Interface
+	if includeCluster, ok := args["include_cluster"].(bool); (!ok || includeCluster) && t.Clients != nil && t.Clients.Dynamic != nil {
+		live = t.Clients.Dynamic
+	}
+	client := newManifestClient(live)
+	base := BaseTool{Cfg: t.Cfg, Clients: &k8s.Clients{Dynamic: client}}
+
+	present := make(map[schema.GroupKind][]*unstructured.Unstructured)
+	own := make(map[string]bool)
+	notValidated := make(map[string][]string)
+	for _, obj := range objs {
+		gk := obj.GroupVersionKind().GroupKind()
+		mk, known := manifestKinds[gk]
+		if obj.GetNamespace() == "" && (workloadKinds[gk] || mk.namespaced) {
+			obj.SetNamespace(defaultNs)
+		}
+		if workloadKinds[gk] {
+			if pod := templatePod(obj); pod != nil {
+				client.add(podsGVR, pod)
+			}
+			continue
+		}
+		if !known {
+			notValidated[gk.String()] = append(notValidated[gk.String()], qualifiedName(obj.GetNamespace(), obj.GetName()))
+			continue
+		}
+		client.add(mk.gvr, obj)
+		present[gk] = append(present[gk], obj)
+		own[manifestKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = true
+	}
+
+	validated := 0
+	for gk, list := range present {
+		if !manifestKinds[gk].context {
+			validated += len(list)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range manifestValidators {
+		tool := v.tool(base)
+		var runs []map[string]interface{}
+		for _, gk := range v.kinds {
+			if v.perObject {
+				for _, obj := range present[gk] {
+					runs = append(runs, map[string]interface{}{"kind": gk.Kind, "name": obj.GetName(), "namespace": obj.GetNamespace()})
+				}
+			} else if len(present[gk]) > 0 {
+				runs = append(runs, map[string]interface{}{"namespace": ""})
+				break
+			}
+		}
+		for _, runArgs := range runs {
+			resp, err := tool.Run(ctx, runArgs)
+			if err != nil {
+				findings = append(findings, types.DiagnosticFinding{
+					Severity: types.SeverityInfo,
+					Category: types.CategoryRouting,
+					Summary:  fmt.Sprintf("%s could not run: %v", tool.Name(), err),
+				})
+				continue
+			}
+			result, ok := resp.Data.(*types.ToolResult)
+			if !ok {
+				continue
+			}
+			for _, f := range result.Findings {
+				if f.Resource == nil || !own[manifestKey(f.Resource.Kind, f.Resource.Namespace, f.Resource.Name)] || liveStateCodes[f.Code] {
+					continue
+				}
+				if f.Severity != types.SeverityWarning && f.Severity != types.SeverityCritical && f.Code == "" {
+					continue
+				}
+				key := string(f.Code) + "|" + manifestKey(f.Resource.Kind, f.Resource.Namespace, f.Resource.Name) + "|" + f.Summary
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	kinds := make([]string, 0, len(notValidated))
+	for kind := range notValidated {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityInfo,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeManifestNotValidated,
+			Summary:    fmt.Sprintf("%d %s objects were not validated", len(notValidated[kind]), kind),
+			Detail:     strings.Join(notValidated[kind], ", "),
+			Suggestion: "Only Gateway API, Istio, NetworkPolicy and kgateway kinds are validated offline",
+		})
+	}
+
+	issues := 0
+	for _, f := range findings {
+		if f.Severity == types.SeverityWarning || f.Severity == types.SeverityCritical {
+			issues++
+		}
+	}
+	if issues == 0 {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity: types.SeverityOK,
+			Category: types.CategoryRouting,
+			Summary:  fmt.Sprintf("%d manifest objects validated, no issues found", validated),
+		})
+	}
+
+	return NewToolResultResponse(t.Cfg, t.Name(), findings, defaultNs, ""), nil
+}
+
+// parseManifests decodes every YAML or JSON document of data, expanding List
+// objects. Documents that do not decode are reported as findings.
+func parseManifests(data string) ([]*unstructured.Unstructured, []types.DiagnosticFinding) {
+	var objs []*unstructured.Unstructured
+	var findings []types.DiagnosticFinding
+	invalid := func(doc int, msg string) {
+		findings = append(findings, types.DiagnosticFinding{
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryRouting,
+			Code:       types.CodeManifestInvalid,
+			Summary:    fmt.Sprintf("Document %d is not a valid manifest: %s", doc, msg),
+			Suggestion: "Fix the document so it parses and sets apiVersion, kind and metadata.name",
+		})
+	}
+
+	reader := yaml.NewYAMLReader(bufio.NewReader(strings.NewReader(data)))
+	for doc := 1; ; doc++ {
+		raw, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			invalid(doc, err.Error())
+			break
+		}
+		jsonData, err := yaml.ToJSON(raw)
+		if err != nil {
+			invalid(doc, err.Error())
+			continue
+		}
+		if trimmed := strings.TrimSpace(string(jsonData)); trimmed == "null" || trimmed == "" {
+			doc--
+			continue
+		}
+		decoded, _, err := unstructured.UnstructuredJSONScheme.Decode(jsonData, nil, nil)
+		if err != nil {
+			invalid(doc, err.Error())
+			continue
+		}
+
+		var items []*unstructured.Unstructured
+		switch obj := decoded.(type) {
+		case *unstructured.Unstructured:
+			items = append(items, obj)
+		case *unstructured.UnstructuredList:
+			for i := range obj.Items {
+				items = append(items, &obj.Items[i])
+			}
+		}
+		for _, obj := range items {
+			if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+				invalid(doc, fmt.Sprintf("%s %q lacks apiVersion, kind or metadata.name", orDefault(obj.GetKind(), "object"), obj.GetName()))
+				continue
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, findings
+}
+
+// templatePod builds a stand-in pod from the pod template of a workload.
+func templatePod(workload *unstructured.Unstructured) *unstructured.Unstructured {
+	spec, found, _ := unstructured.NestedMap(workload.Object, "spec", "template", "spec")
+	if !found {
+		return nil
+	}
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   spec,
+		"status": map[string]interface{}{"phase": "Running"},
+	}}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace(workload.GetNamespace())
+	pod.SetName(workload.GetName() + "-template")
+	podLabels, _, _ := unstructured.NestedStringMap(workload.Object, "spec", "template", "metadata", "labels")
+	pod.SetLabels(podLabels)
+	return pod
+}
+
+func manifestKey(kind, ns, name string) string {
+	return kind + "|" + ns + "|" + name
+}
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/isitobservable/k8s-networking-mcp/pkg/config"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/k8s"
+	"github.com/isitobservable/k8s-networking-mcp/pkg/types"
+)
+
+const testManifests = `
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: web
+spec:
+  gatewayClassName: istio
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: web
+spec:
+  parentRefs:
+  - name: web
+  rules:
+  - backendRefs:
+    - name: web
+      port: 80
+    - name: api
+      namespace: payments
+      port: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: http
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        ports:
+        - name: http
+          containerPort: 8080
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: web
+spec:
+  podSelector:
+    matchLabels:
+      app: web
+  ingress:
+  - ports:
+    - port: http
+    - port: metrics
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+kind: HTTPRoute
+metadata:
+  name: broken
+`
+
+func runValidateManifests(t *testing.T, tool *ValidateManifestsTool, args map[string]interface{}) []types.DiagnosticFinding {
+	t.Helper()
+	resp, err := tool.Run(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.(*types.ToolResult).Findings
+}
+
+func TestValidateManifestsOffline(t *testing.T) {
+	tool := &ValidateManifestsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}}}
+	findings := runValidateManifests(t, tool, map[string]interface{}{"manifests": testManifests, "namespace": "shop"})
+
+	codes := make(map[types.FindingCode][]string)
+	for _, f := range findings {
+		codes[f.Code] = append(codes[f.Code], f.Summary)
+	}
+	if got := codes[types.CodeGatewayBackendServiceMissing]; len(got) != 1 || !strings.Contains(got[0], "payments/api") {
+		t.Errorf("expected only payments/api to be missing, got %v", got)
+	}
+	if len(codes[types.CodeGatewayReferenceGrantMissing]) != 1 {
+		t.Errorf("expected a missing ReferenceGrant, got %v", codes[types.CodeGatewayReferenceGrantMissing])
+	}
+	if got := codes[types.CodeNetworkPolicyNamedPortUndefined]; len(got) != 1 || !strings.Contains(got[0], "metrics") {
+		t.Errorf("expected only the metrics port to be undefined, got %v", got)
+	}
+	if got := codes[types.CodeManifestInvalid]; len(got) != 1 || !strings.HasPrefix(got[0], "Document 7 ") {
+		t.Errorf("expected document 7 to be invalid, got %v", got)
+	}
+	if got := codes[types.CodeManifestNotValidated]; len(got) != 1 || !strings.Contains(got[0], "ConfigMap") {
+		t.Errorf("expected the ConfigMap to be reported as not validated, got %v", got)
+	}
+	for _, f := range findings {
+		if f.Resource != nil && f.Resource.Kind == "Gateway" && f.Severity != types.SeverityInfo {
+			t.Errorf("unexpected Gateway finding %+v", f)
+		}
+	}
+}
+
+// manifestListKinds registers every kind the validators list on the fake
+// client, which panics on unregistered resources.
+func manifestListKinds() map[schema.GroupVersionResource]string {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for gk, mk := range manifestKinds {
+		listKinds[mk.gvr] = gk.Kind + "List"
+	}
+	for gvr, kind := range map[schema.GroupVersionResource]string{
+		gatewaysV1B1GVR: "GatewayList", httpRoutesV1B1GVR: "HTTPRouteList", grpcRoutesV1B1GVR: "GRPCRouteList", refGrantsV1B1GVR: "ReferenceGrantList",
+		backendTLSPoliciesV1GVR: "BackendTLSPolicyList", backendTLSPoliciesV1A3GVR: "BackendTLSPolicyList",
+		vsV1B1GVR: "VirtualServiceList", drV1B1GVR: "DestinationRuleList", apV1B1GVR: "AuthorizationPolicyList", paV1B1GVR: "PeerAuthenticationList",
+		endpointsGVR: "EndpointsList", deploymentsGVR: "DeploymentList",
+	} {
+		listKinds[gvr] = kind
+	}
+	return listKinds
+}
+
+func TestValidateManifestsIncludeCluster(t *testing.T) {
+	api := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	api.SetAPIVersion("v1")
+	api.SetKind("Service")
+	api.SetNamespace("payments")
+	api.SetName("api")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), manifestListKinds(), api)
+	tool := &ValidateManifestsTool{BaseTool: BaseTool{Cfg: &config.Config{ClusterName: "test"}, Clients: &k8s.Clients{Dynamic: client}}}
+
+	for _, include := range []bool{true, false} {
+		findings := runValidateManifests(t, tool, map[string]interface{}{"manifests": testManifests, "namespace": "shop", "include_cluster": include})
+		missing := 0
+		for _, f := range findings {
+			if f.Code == types.CodeGatewayBackendServiceMissing {
+				missing++
+			}
+		}
+		if want := map[bool]int{true: 0, false: 1}[include]; missing != want {
+			t.Errorf("include_cluster=%v: %d missing backends, want %d", include, missing, want)
+		}
+	}
+}
+
+func TestManifestClientOverlay(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	live.SetAPIVersion("v1")
+	live.SetKind("Service")
+	live.SetNamespace("shop")
+	live.SetName("web")
+	live.SetLabels(map[string]string{"source": "cluster"})
+	listKinds := map[schema.GroupVersionResource]string{servicesGVR: "ServiceList"}
+	client := newManifestClient(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, live))
+
+	own := live.DeepCopy()
+	own.SetLabels(map[string]string{"source": "manifest"})
+	client.add(servicesGVR, own)
+	extra := own.DeepCopy()
+	extra.SetName("api")
+	client.add(servicesGVR, extra)
+
+	list, err := client.Resource(servicesGVR).Namespace("shop").List(context.Background(), metav1.ListOptions{LabelSelector: "source=manifest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected the manifest objects to replace the live one, got %d items", len(list.Items))
+	}
+	if _, err := client.Resource(servicesGVR).Namespace("shop").Create(context.Background(), own, metav1.CreateOptions{}); err == nil {
+		t.Error("expected writes to fail")
+	}
+}
@@ -0,0 +1,352 @@
+// Package traces fetches traces from a trace backend's HTTP API (Grafana
+// Tempo or Jaeger) so that tools can locate where along a request path
+// failures occur.
+package traces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSize bounds the size of a search or trace response.
+const maxResponseSize = 20 << 20
+
+// Supported backends.
+const (
+	BackendTempo  = "tempo"
+	BackendJaeger = "jaeger"
+)
+
+// Span is one span of a trace, with string-valued attributes.
+type Span struct {
+	SpanID     string
+	ParentID   string
+	Service    string
+	Name       string
+	Start      time.Time
+	Duration   time.Duration
+	Error      bool
+	Attributes map[string]string
+}
+
+// Trace is a trace and its spans, ordered by start time.
+type Trace struct {
+	TraceID string
+	Spans   []Span
+}
+
+// Client searches a Tempo or Jaeger query API.
+type Client struct {
+	backend    string
+	baseURL    string
+	tokenFile  string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the backend API at baseURL. When tokenFile
+// is set, its content is sent as a bearer token; it is read on every request
+// so that rotated service account tokens are picked up.
+func NewClient(backend, baseURL, tokenFile string) (*Client, error) {
+	backend = strings.ToLower(backend)
+	if backend != BackendTempo && backend != BackendJaeger {
+		return nil, fmt.Errorf("invalid trace backend %q: expected tempo or jaeger", backend)
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid trace backend URL %q: expected http(s)://host[:port][/path]", baseURL)
+	}
+	return &Client{
+		backend:    backend,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		tokenFile:  tokenFile,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Backend returns the backend type, tempo or jaeger.
+func (c *Client) Backend() string { return c.backend }
+
+// FailingTraces returns up to limit traces from the last since that involve
+// service and contain an error span.
+func (c *Client) FailingTraces(ctx context.Context, service string, since time.Duration, limit int) ([]Trace, error) {
+	end := time.Now()
+	start := end.Add(-since)
+	if c.backend == BackendJaeger {
+		return c.jaegerSearch(ctx, service, start, end, limit)
+	}
+	return c.tempoSearch(ctx, service, start, end, limit)
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read trace backend token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.backend, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d: %s", c.backend, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s returned an unreadable body: %w", c.backend, err)
+	}
+	return nil
+}
+
+// --- Tempo ---
+
+// tempoSearch runs a TraceQL search, then fetches each trace: search results
+// only carry a summary of the matching spans.
+func (c *Client) tempoSearch(ctx context.Context, service string, start, end time.Time, limit int) ([]Trace, error) {
+	var result struct {
+		Traces []struct {
+			TraceID string `json:"traceID"`
+		} `json:"traces"`
+	}
+	query := url.Values{
+		"q":     {fmt.Sprintf(`{ resource.service.name = %s && status = error }`, strconv.Quote(service))},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"limit": {strconv.Itoa(limit)},
+	}
+	if err := c.get(ctx, "/api/search", query, &result); err != nil {
+		return nil, err
+	}
+	traces := make([]Trace, 0, len(result.Traces))
+	for _, r := range result.Traces {
+		var otlp otlpTrace
+		if err := c.get(ctx, "/api/traces/"+url.PathEscape(r.TraceID), nil, &otlp); err != nil {
+			return nil, err
+		}
+		traces = append(traces, Trace{TraceID: r.TraceID, Spans: otlp.spans()})
+	}
+	return traces, nil
+}
+
+// otlpTrace is the OTLP JSON encoding Tempo returns for a trace.
+type otlpTrace struct {
+	Batches []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+		// Tempo releases before 2.0 use the pre-1.0 OTLP field name.
+		InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	} `json:"batches"`
+}
+
+type otlpScopeSpans struct {
+	Spans []struct {
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId"`
+		Name              string          `json:"name"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes"`
+		Status            struct {
+			Code json.RawMessage `json:"code"`
+		} `json:"status"`
+	} `json:"spans"`
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string  `json:"stringValue"`
+		IntValue    *string  `json:"intValue"`
+		BoolValue   *bool    `json:"boolValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+	} `json:"value"`
+}
+
+func (a otlpAttribute) String() string {
+	v := a.Value
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.IntValue != nil:
+		return *v.IntValue
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'f', -1, 64)
+	}
+	return ""
+}
+
+func otlpAttributes(attrs []otlpAttribute) map[string]string {
+	out := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		out[a.Key] = a.String()
+	}
+	return out
+}
+
+func unixNano(s string) time.Time {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return time.Unix(0, n)
+}
+
+func (t otlpTrace) spans() []Span {
+	var spans []Span
+	for _, b := range t.Batches {
+		service := otlpAttributes(b.Resource.Attributes)["service.name"]
+		for _, ss := range append(b.ScopeSpans, b.InstrumentationLibrarySpans...) {
+			for _, s := range ss.Spans {
+				start, end := unixNano(s.StartTimeUnixNano), unixNano(s.EndTimeUnixNano)
+				// The status code is an enum name or number depending on the encoder.
+				code := strings.Trim(string(s.Status.Code), `"`)
+				attrs := otlpAttributes(s.Attributes)
+				spans = append(spans, Span{
+					SpanID:     s.SpanID,
+					ParentID:   s.ParentSpanID,
+					Service:    service,
+					Name:       s.Name,
+					Start:      start,
+					Duration:   end.Sub(start),
+					Error:      code == "STATUS_CODE_ERROR" || code == "2" || attrs["error"] == "true",
+					Attributes: attrs,
+				})
+			}
+		}
+	}
+	sortSpans(spans)
+	return spans
+}
+
+// --- Jaeger ---
+
+func (c *Client) jaegerSearch(ctx context.Context, service string, start, end time.Time, limit int) ([]Trace, error) {
+	var result struct {
+		Data []struct {
+			TraceID string `json:"traceID"`
+			Spans   []struct {
+				SpanID        string `json:"spanID"`
+				OperationName string `json:"operationName"`
+				References    []struct {
+					RefType string `json:"refType"`
+					SpanID  string `json:"spanID"`
+				} `json:"references"`
+				StartTime int64 `json:"startTime"` // microseconds
+				Duration  int64 `json:"duration"`  // microseconds
+				Tags      []struct {
+					Key   string      `json:"key"`
+					Value interface{} `json:"value"`
+				} `json:"tags"`
+				ProcessID string `json:"processID"`
+			} `json:"spans"`
+			Processes map[string]struct {
+				ServiceName string `json:"serviceName"`
+			} `json:"processes"`
+		} `json:"data"`
+	}
+	query := url.Values{
+		"service": {service},
+		"tags":    {`{"error":"true"}`},
+		"start":   {strconv.FormatInt(start.UnixMicro(), 10)},
+		"end":     {strconv.FormatInt(end.UnixMicro(), 10)},
+		"limit":   {strconv.Itoa(limit)},
+	}
+	if err := c.get(ctx, "/api/traces", query, &result); err != nil {
+		return nil, err
+	}
+
+	traces := make([]Trace, 0, len(result.Data))
+	for _, d := range result.Data {
+		tr := Trace{TraceID: d.TraceID}
+		for _, s := range d.Spans {
+			attrs := make(map[string]string, len(s.Tags))
+			for _, tag := range s.Tags {
+				attrs[tag.Key] = fmt.Sprint(tag.Value)
+			}
+			span := Span{
+				SpanID:     s.SpanID,
+				Service:    d.Processes[s.ProcessID].ServiceName,
+				Name:       s.OperationName,
+				Start:      time.UnixMicro(s.StartTime),
+				Duration:   time.Duration(s.Duration) * time.Microsecond,
+				Error:      attrs["error"] == "true" || attrs["otel.status_code"] == "ERROR",
+				Attributes: attrs,
+			}
+			for _, ref := range s.References {
+				if ref.RefType == "CHILD_OF" {
+					span.ParentID = ref.SpanID
+					break
+				}
+			}
+			tr.Spans = append(tr.Spans, span)
+		}
+		sortSpans(tr.Spans)
+		traces = append(traces, tr)
+	}
+	return traces, nil
+}
+
+func sortSpans(spans []Span) {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+}
+
+// FailureOrigins returns the error spans none of whose children failed: the
+// spans where the failures of the trace start.
+func (t Trace) FailureOrigins() []Span {
+	failedChild := make(map[string]bool)
+	for _, s := range t.Spans {
+		if s.Error && s.ParentID != "" {
+			failedChild[s.ParentID] = true
+		}
+	}
+	var out []Span
+	for _, s := range t.Spans {
+		if s.Error && !failedChild[s.SpanID] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Path returns the spans from the root of the trace down to s.
+func (t Trace) Path(s Span) []Span {
+	byID := make(map[string]Span, len(t.Spans))
+	for _, sp := range t.Spans {
+		byID[sp.SpanID] = sp
+	}
+	path := []Span{s}
+	for cur := s; cur.ParentID != ""; {
+		parent, ok := byID[cur.ParentID]
+		if !ok || len(path) > len(t.Spans) {
+			break
+		}
+		path = append([]Span{parent}, path...)
+		cur = parent
+	}
+	return path
+}
@@ -0,0 +1,130 @@
+package traces
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Validates(t *testing.T) {
+	if _, err := NewClient("zipkin", "http://tempo:3200", ""); err == nil {
+		t.Error("expected an error for an unsupported backend")
+	}
+	if _, err := NewClient("tempo", "tempo:3200", ""); err == nil {
+		t.Error("expected an error for a URL without scheme")
+	}
+	if c, err := NewClient("Jaeger", "http://jaeger:16686/", ""); err != nil || c.Backend() != BackendJaeger {
+		t.Errorf("NewClient(Jaeger) = %v, %v", c, err)
+	}
+}
+
+func TestFailingTraces_Tempo(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var gotQuery, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/search":
+			gotQuery = r.URL.Query().Get("q")
+			_, _ = w.Write([]byte(`{"traces":[{"traceID":"abc"}]}`))
+		case "/api/traces/abc":
+			_, _ = w.Write([]byte(`{"batches":[
+				{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"istio-ingressgateway.istio-system"}}]},
+				 "scopeSpans":[{"spans":[{"spanId":"1","name":"shop.example.com:443/*","startTimeUnixNano":"1000","endTimeUnixNano":"5000",
+				   "attributes":[{"key":"http.status_code","value":{"stringValue":"503"}}],"status":{"code":"STATUS_CODE_ERROR"}}]}]},
+				{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"cart.shop"}}]},
+				 "instrumentationLibrarySpans":[{"spans":[{"spanId":"2","parentSpanId":"1","name":"cart.shop.svc.cluster.local:8080/*","startTimeUnixNano":"2000","endTimeUnixNano":"4000",
+				   "attributes":[{"key":"response_flags","value":{"stringValue":"UF"}},{"key":"http.status_code","value":{"intValue":"503"}}],"status":{"code":2}}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("tempo", srv.URL, tokenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trs, err := c.FailingTraces(context.Background(), "cart.shop", time.Hour, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != `{ resource.service.name = "cart.shop" && status = error }` {
+		t.Errorf("query = %s", gotQuery)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if len(trs) != 1 || len(trs[0].Spans) != 2 {
+		t.Fatalf("unexpected traces %+v", trs)
+	}
+	spans := trs[0].Spans
+	if spans[0].Service != "istio-ingressgateway.istio-system" || !spans[0].Error || spans[0].Duration != 4*time.Microsecond {
+		t.Errorf("unexpected root span %+v", spans[0])
+	}
+	if spans[1].Attributes["response_flags"] != "UF" || spans[1].Attributes["http.status_code"] != "503" || !spans[1].Error {
+		t.Errorf("unexpected child span %+v", spans[1])
+	}
+	origins := trs[0].FailureOrigins()
+	if len(origins) != 1 || origins[0].SpanID != "2" {
+		t.Fatalf("FailureOrigins() = %+v", origins)
+	}
+	if path := trs[0].Path(origins[0]); len(path) != 2 || path[0].SpanID != "1" {
+		t.Errorf("Path() = %+v", path)
+	}
+}
+
+func TestFailingTraces_Jaeger(t *testing.T) {
+	var gotTags string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jaeger/api/traces" {
+			http.NotFound(w, r)
+			return
+		}
+		gotTags = r.URL.Query().Get("tags")
+		_, _ = w.Write([]byte(`{"data":[{"traceID":"t1","spans":[
+			{"spanID":"b","operationName":"GET /cart","references":[{"refType":"CHILD_OF","spanID":"a"}],"startTime":20,"duration":5,
+			 "tags":[{"key":"error","type":"bool","value":true}],"processID":"p2"},
+			{"spanID":"a","operationName":"ingress","startTime":10,"duration":30,"tags":[],"processID":"p1"}],
+			"processes":{"p1":{"serviceName":"gateway"},"p2":{"serviceName":"cart"}}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("jaeger", srv.URL+"/jaeger", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trs, err := c.FailingTraces(context.Background(), "cart", time.Hour, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTags != `{"error":"true"}` {
+		t.Errorf("tags = %s", gotTags)
+	}
+	if len(trs) != 1 || len(trs[0].Spans) != 2 {
+		t.Fatalf("unexpected traces %+v", trs)
+	}
+	spans := trs[0].Spans
+	if spans[0].Service != "gateway" || spans[1].ParentID != "a" || !spans[1].Error || spans[1].Duration != 5*time.Microsecond {
+		t.Errorf("unexpected spans %+v", spans)
+	}
+}
+
+func TestFailingTraces_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid TraceQL", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	c, _ := NewClient("tempo", srv.URL, "")
+	if _, err := c.FailingTraces(context.Background(), "cart", time.Hour, 5); err == nil || !strings.Contains(err.Error(), "HTTP 400: invalid TraceQL") {
+		t.Errorf("expected the HTTP error, got %v", err)
+	}
+}
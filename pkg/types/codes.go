@@ -0,0 +1,830 @@
+package types
+
+import "strings"
+
+// FindingCode is a stable, machine-readable identifier of the kind of problem
+// a finding reports. Codes are <DOMAIN><NNN>_<NAME>; a released code keeps its
+// meaning, so automation can key remediation off it instead of the summary.
+type FindingCode string
+
+// Gateway API.
+const (
+	CodeGatewayListenerConflict             FindingCode = "GW001_LISTENER_CONFLICT"
+	CodeGatewayCertSecretMissing            FindingCode = "GW002_CERT_SECRET_MISSING"
+	CodeGatewayTLSCertRefsMissing           FindingCode = "GW003_TLS_CERT_REFS_MISSING"
+	CodeGatewayPassthroughWithCerts         FindingCode = "GW004_PASSTHROUGH_WITH_CERTS"
+	CodeGatewayListenerUnhealthy            FindingCode = "GW005_LISTENER_UNHEALTHY"
+	CodeGatewayConditionFalse               FindingCode = "GW006_GATEWAY_CONDITION_FALSE"
+	CodeGatewayBackendServiceMissing        FindingCode = "GW007_BACKEND_SERVICE_MISSING"
+	CodeGatewayBackendNoEndpoints           FindingCode = "GW008_BACKEND_NO_ENDPOINTS"
+	CodeGatewayRouteConditionFalse          FindingCode = "GW009_ROUTE_CONDITION_FALSE"
+	CodeGatewayReferenceGrantMissing        FindingCode = "GW010_REFERENCE_GRANT_MISSING"
+	CodeGatewayMeshServiceMissing           FindingCode = "GW011_MESH_SERVICE_MISSING"
+	CodeGatewayParentMissing                FindingCode = "GW012_PARENT_GATEWAY_MISSING"
+	CodeGatewayParentListenerMissing        FindingCode = "GW013_PARENT_LISTENER_MISSING"
+	CodeGatewayFilterConfigMissing          FindingCode = "GW014_FILTER_CONFIG_MISSING"
+	CodeGatewayWaypointMissing              FindingCode = "GW015_WAYPOINT_MISSING"
+	CodeGatewayWaypointNotProgrammed        FindingCode = "GW016_WAYPOINT_NOT_PROGRAMMED"
+	CodeGatewayWaypointPodsUnready          FindingCode = "GW017_WAYPOINT_PODS_UNREADY"
+	CodeGatewayResourceNotFound             FindingCode = "GW018_RESOURCE_NOT_FOUND"
+	CodeGatewaySpecFieldMissing             FindingCode = "GW019_SPEC_FIELD_MISSING"
+	CodeGatewaySpecInvalidValue             FindingCode = "GW020_SPEC_INVALID_VALUE"
+	CodeGatewayWeightsNot100                FindingCode = "GW021_WEIGHTS_NOT_100"
+	CodeGatewayWeightedBackendNoEndpoints   FindingCode = "GW022_WEIGHTED_BACKEND_NO_ENDPOINTS"
+	CodeGatewayBackendTimeoutExceedsRequest FindingCode = "GW023_BACKEND_TIMEOUT_EXCEEDS_REQUEST"
+	CodeGatewayBackendPortNotExposed        FindingCode = "GW024_BACKEND_PORT_NOT_EXPOSED"
+	CodeGatewayBackendPortProtocol          FindingCode = "GW025_BACKEND_PORT_PROTOCOL"
+	CodeGatewayTargetPortUnresolved         FindingCode = "GW026_TARGET_PORT_UNRESOLVED"
+	CodeGatewayFeatureUnsupported           FindingCode = "GW027_FEATURE_UNSUPPORTED"
+	CodeGatewayClassNotAccepted             FindingCode = "GW028_GATEWAY_CLASS_NOT_ACCEPTED"
+	CodeGatewayFeaturesUnpublished          FindingCode = "GW029_FEATURES_UNPUBLISHED"
+	CodeGatewayRouteShadowed                FindingCode = "GW030_ROUTE_SHADOWED"
+	CodeGatewayListenerProtocolMismatch     FindingCode = "GW031_LISTENER_PROTOCOL_MISMATCH"
+	CodeGatewayTLSRouteNotPassthrough       FindingCode = "GW032_TLSROUTE_NOT_PASSTHROUGH"
+	CodeGatewaySNIHostnameMismatch          FindingCode = "GW033_SNI_HOSTNAME_MISMATCH"
+	CodeGatewayBackendTLSPolicyMissing      FindingCode = "GW034_BACKEND_TLS_POLICY_MISSING"
+	CodeGatewayBackendAppProtocolMissing    FindingCode = "GW035_BACKEND_APP_PROTOCOL_MISSING"
+	CodeGatewayBackendTLSPolicyInvalid      FindingCode = "GW036_BACKEND_TLS_POLICY_INVALID"
+	CodeGatewayClassMissing                 FindingCode = "GW037_GATEWAY_CLASS_MISSING"
+	CodeGatewayControllerNotRunning         FindingCode = "GW038_GATEWAY_CONTROLLER_NOT_RUNNING"
+	CodeGatewayParametersRefMissing         FindingCode = "GW039_PARAMETERS_REF_MISSING"
+	CodeGatewayRateLimited                  FindingCode = "GW040_RATE_LIMITED"
+	CodeGatewayRateLimitSourceUnknown       FindingCode = "GW041_RATE_LIMIT_SOURCE_UNKNOWN"
+	CodeGatewayUpstreamRateLimited          FindingCode = "GW042_UPSTREAM_RATE_LIMITED"
+	CodeGatewayRouteNotServing              FindingCode = "GW043_ROUTE_NOT_SERVING"
+	CodeGatewayRouteWrongBackend            FindingCode = "GW044_ROUTE_WRONG_BACKEND"
+	CodeGatewayUnreachable                  FindingCode = "GW045_GATEWAY_UNREACHABLE"
+)
+
+// Istio.
+const (
+	CodeIstioWeightsNot100              FindingCode = "IST001_WEIGHTS_NOT_100"
+	CodeIstioRetryExceedsTimeout        FindingCode = "IST002_RETRY_EXCEEDS_TIMEOUT"
+	CodeIstioAnalyzerMessage            FindingCode = "IST003_ANALYZER_MESSAGE"
+	CodeIstioConditionFalse             FindingCode = "IST004_CONDITION_FALSE"
+	CodeIstioInjectionDisabled          FindingCode = "IST005_INJECTION_DISABLED"
+	CodeIstioMTLSConflict               FindingCode = "IST006_MTLS_CONFLICT"
+	CodeIstioVirtualServiceNoHosts      FindingCode = "IST007_VS_NO_HOSTS"
+	CodeIstioRouteShadowed              FindingCode = "IST008_ROUTE_SHADOWED"
+	CodeIstioDestinationUnset           FindingCode = "IST009_DESTINATION_UNSET"
+	CodeIstioDestinationServiceMissing  FindingCode = "IST010_DESTINATION_SERVICE_MISSING"
+	CodeIstioClientCertMissing          FindingCode = "IST011_DR_CLIENT_CERT_MISSING"
+	CodeIstioConnectionPoolInvalid      FindingCode = "IST012_CONNECTION_POOL_INVALID"
+	CodeIstioSubsetNoPods               FindingCode = "IST013_SUBSET_NO_PODS"
+	CodeIstioSubsetMissing              FindingCode = "IST014_SUBSET_MISSING"
+	CodeIstioAuthzDenyAll               FindingCode = "IST015_AUTHZ_DENY_ALL"
+	CodeIstioAuthzAllowNothing          FindingCode = "IST016_AUTHZ_ALLOW_NOTHING"
+	CodeIstioAuthzAllowDenyConflict     FindingCode = "IST017_AUTHZ_ALLOW_DENY_CONFLICT"
+	CodeIstioAuthzDenyAffectsService    FindingCode = "IST018_AUTHZ_DENY_AFFECTS_SERVICE"
+	CodeIstioOutlierDetectionAggressive FindingCode = "IST019_OUTLIER_DETECTION_AGGRESSIVE"
+	CodeIstioVersionSkew                FindingCode = "IST020_VERSION_SKEW"
+	CodeIstioSidecarPending             FindingCode = "IST021_SIDECAR_PENDING"
+	CodeIstioRevisionMissing            FindingCode = "IST022_REVISION_MISSING"
+	CodeIstioRevisionMismatch           FindingCode = "IST023_REVISION_MISMATCH"
+	CodeIstiodUnhealthy                 FindingCode = "IST024_ISTIOD_UNHEALTHY"
+	CodeIstioXDSRejected                FindingCode = "IST025_XDS_REJECTED"
+	CodeIstioXDSPushErrors              FindingCode = "IST026_XDS_PUSH_ERRORS"
+	CodeIstioListenerConflict           FindingCode = "IST027_LISTENER_CONFLICT"
+	CodeIstioEnvoyFilterHighRisk        FindingCode = "IST028_ENVOYFILTER_HIGH_RISK"
+	CodeIstioEnvoyFilterVersionMismatch FindingCode = "IST029_ENVOYFILTER_VERSION_MISMATCH"
+	CodeIstioEnvoyFilterOverlap         FindingCode = "IST030_ENVOYFILTER_OVERLAP"
+	CodeIstioOutlierEjectsAllHosts      FindingCode = "IST031_OUTLIER_EJECTS_ALL_HOSTS"
+	CodeIstioRetryEjectionAmplification FindingCode = "IST032_RETRY_EJECTION_AMPLIFICATION"
+	CodeIstioOutlierEjections           FindingCode = "IST033_OUTLIER_EJECTIONS"
+)
+
+// kgateway.
+const (
+	CodeKgatewayNotAccepted             FindingCode = "KGW001_NOT_ACCEPTED"
+	CodeKgatewayConditionFalse          FindingCode = "KGW002_CONDITION_FALSE"
+	CodeKgatewayParametersUnused        FindingCode = "KGW003_PARAMETERS_UNUSED"
+	CodeKgatewayParametersInvalid       FindingCode = "KGW004_PARAMETERS_INVALID"
+	CodeKgatewayServiceAccountMissing   FindingCode = "KGW005_SERVICE_ACCOUNT_MISSING"
+	CodeKgatewayTargetRefInvalid        FindingCode = "KGW006_TARGET_REF_INVALID"
+	CodeKgatewayUpstreamMissing         FindingCode = "KGW007_UPSTREAM_MISSING"
+	CodeKgatewayPolicyConflict          FindingCode = "KGW008_POLICY_CONFLICT"
+	CodeKgatewayControlPlaneDown        FindingCode = "KGW009_CONTROL_PLANE_DOWN"
+	CodeKgatewayPodUnhealthy            FindingCode = "KGW010_POD_UNHEALTHY"
+	CodeKgatewayGatewayNotProgrammed    FindingCode = "KGW011_GATEWAY_NOT_PROGRAMMED"
+	CodeKgatewayNoDataPlane             FindingCode = "KGW012_NO_DATA_PLANE"
+	CodeKgatewayRateLimitConfigRejected FindingCode = "KGW013_RATE_LIMIT_CONFIG_REJECTED"
+	CodeKgatewayBackendInvalid          FindingCode = "KGW014_BACKEND_INVALID"
+	CodeKgatewayBackendUnused           FindingCode = "KGW015_BACKEND_UNUSED"
+	CodeKgatewayPolicyOverridden        FindingCode = "KGW016_POLICY_OVERRIDDEN"
+	CodeKgatewayRouteMissingFromXDS     FindingCode = "KGW017_ROUTE_MISSING_FROM_XDS"
+	CodeKgatewayXDSBackendMismatch      FindingCode = "KGW018_XDS_BACKEND_MISMATCH"
+)
+
+// Services and clusters.
+const (
+	CodeServiceNoEndpoints                     FindingCode = "SVC001_NO_ENDPOINTS"
+	CodeServiceSelectorNoPods                  FindingCode = "SVC002_SELECTOR_NO_PODS"
+	CodeServiceIngressBackendMissing           FindingCode = "SVC003_INGRESS_BACKEND_MISSING"
+	CodeServiceExposurePending                 FindingCode = "SVC004_EXPOSURE_PENDING"
+	CodeServiceExposedUnprotected              FindingCode = "SVC005_EXPOSED_UNPROTECTED"
+	CodeServiceLocalPolicyNoEndpoints          FindingCode = "SVC006_LOCAL_POLICY_NO_ENDPOINTS"
+	CodeServiceLocalPolicyNodesWithoutEndpoint FindingCode = "SVC007_LOCAL_POLICY_NODES_WITHOUT_ENDPOINT"
+	CodeServiceLocalPolicyMeshMismatch         FindingCode = "SVC008_LOCAL_POLICY_MESH_MISMATCH"
+	CodeServiceLocalPolicyNoHealthCheck        FindingCode = "SVC009_LOCAL_POLICY_NO_HEALTH_CHECK"
+	CodeServiceLocalPolicySingleNode           FindingCode = "SVC010_LOCAL_POLICY_SINGLE_NODE"
+	CodeServiceSessionAffinityIneffective      FindingCode = "SVC011_SESSION_AFFINITY_INEFFECTIVE"
+	CodeServiceSessionAffinityUneven           FindingCode = "SVC012_SESSION_AFFINITY_UNEVEN"
+	CodeServiceHealthCheckPortMismatch         FindingCode = "SVC013_HEALTH_CHECK_PORT_MISMATCH"
+	CodeClusterUnreachable                     FindingCode = "CLU001_UNREACHABLE"
+)
+
+// NetworkPolicy.
+const (
+	CodeNetworkPolicyDenyAllIngress         FindingCode = "NP001_DENY_ALL_INGRESS"
+	CodeNetworkPolicyInvalidSelector        FindingCode = "NP002_INVALID_SELECTOR"
+	CodeNetworkPolicyPortProtocolMismatch   FindingCode = "NP003_PORT_PROTOCOL_MISMATCH"
+	CodeNetworkPolicyNamedPortUndefined     FindingCode = "NP004_NAMED_PORT_UNDEFINED"
+	CodeNetworkPolicyBlockingTraffic        FindingCode = "NP005_BLOCKING_TRAFFIC"
+	CodeNetworkPolicyPodNotSelected         FindingCode = "NP006_POD_NOT_SELECTED"
+	CodeNetworkPolicyServicePortNotAdmitted FindingCode = "NP007_SERVICE_PORT_NOT_ADMITTED"
+	CodeNetworkPolicyNoDefaultDeny          FindingCode = "NP008_NO_DEFAULT_DENY"
+)
+
+// Egress.
+const (
+	CodeEgressWildcard               FindingCode = "EGR001_WILDCARD_EGRESS"
+	CodeEgressPlaintextSensitivePort FindingCode = "EGR002_PLAINTEXT_SENSITIVE_PORT"
+	CodeEgressMeshAllowAny           FindingCode = "EGR003_ALLOW_ANY_OUTBOUND"
+)
+
+// Tenant isolation.
+const (
+	CodeTenantIsolationBroken      FindingCode = "TEN001_ISOLATION_BROKEN"
+	CodeTenantPathAllowed          FindingCode = "TEN002_PATH_ALLOWED"
+	CodeTenantProbeConnected       FindingCode = "TEN003_PROBE_CONNECTED"
+	CodeTenantPoliciesNotEvaluated FindingCode = "TEN004_POLICIES_NOT_EVALUATED"
+)
+
+// DNS.
+const (
+	CodeDNSLookupFailed              FindingCode = "DNS001_LOOKUP_FAILED"
+	CodeDNSKubeDNSNoEndpoints        FindingCode = "DNS002_KUBE_DNS_NO_ENDPOINTS"
+	CodeDNSKubeDNSMissing            FindingCode = "DNS003_KUBE_DNS_MISSING"
+	CodeDNSCorefileMissing           FindingCode = "DNS004_COREFILE_MISSING"
+	CodeDNSCorefileParseError        FindingCode = "DNS005_COREFILE_PARSE_ERROR"
+	CodeDNSLegacyConfigMapKeys       FindingCode = "DNS006_LEGACY_CONFIGMAP_KEYS"
+	CodeDNSCoreDNSLogErrors          FindingCode = "DNS007_COREDNS_LOG_ERRORS"
+	CodeDNSDuplicateZone             FindingCode = "DNS008_DUPLICATE_ZONE"
+	CodeDNSKubernetesPluginMissing   FindingCode = "DNS009_KUBERNETES_PLUGIN_MISSING"
+	CodeDNSClusterDomainNotServed    FindingCode = "DNS010_CLUSTER_DOMAIN_NOT_SERVED"
+	CodeDNSNoRootZone                FindingCode = "DNS011_NO_ROOT_ZONE"
+	CodeDNSForwardMissing            FindingCode = "DNS012_FORWARD_MISSING"
+	CodeDNSForwardInvalid            FindingCode = "DNS013_FORWARD_INVALID"
+	CodeDNSRemovedPlugin             FindingCode = "DNS014_REMOVED_PLUGIN"
+	CodeDNSForwardingLoop            FindingCode = "DNS015_FORWARDING_LOOP"
+	CodeDNSLoopDetectionDisabled     FindingCode = "DNS016_LOOP_DETECTION_DISABLED"
+	CodeDNSCacheDisabled             FindingCode = "DNS017_CACHE_DISABLED"
+	CodeDNSNodeLocalSetupInvalid     FindingCode = "DNS018_NODELOCAL_SETUP_INVALID"
+	CodeDNSNodeLocalPlaceholders     FindingCode = "DNS019_NODELOCAL_PLACEHOLDERS"
+	CodeDNSNodeLocalBindMismatch     FindingCode = "DNS020_NODELOCAL_BIND_MISMATCH"
+	CodeDNSNodeLocalConfigMapMissing FindingCode = "DNS021_NODELOCAL_CONFIGMAP_MISSING"
+	CodeDNSNodeLocalUpstreamInvalid  FindingCode = "DNS022_NODELOCAL_UPSTREAM_INVALID"
+	CodeDNSNodeLocalPodMissing       FindingCode = "DNS023_NODELOCAL_POD_MISSING"
+	CodeDNSNodeLocalRestarts         FindingCode = "DNS024_NODELOCAL_RESTARTS"
+	CodeDNSServfailRate              FindingCode = "DNS025_SERVFAIL_RATE"
+)
+
+// kube-proxy.
+const (
+	CodeKubeProxyDaemonSetMissing FindingCode = "KPX001_DAEMONSET_MISSING"
+	CodeKubeProxyPodsUnavailable  FindingCode = "KPX002_PODS_UNAVAILABLE"
+	CodeKubeProxyNodeWithoutProxy FindingCode = "KPX003_NODE_WITHOUT_PROXY"
+	CodeKubeProxyRestarts         FindingCode = "KPX004_RESTARTS"
+	CodeKubeProxyVersionDrift     FindingCode = "KPX005_VERSION_DRIFT"
+	CodeKubeProxyVersionSkew      FindingCode = "KPX006_VERSION_SKEW"
+)
+
+// TLS.
+const (
+	CodeTLSCertHostNotCovered  FindingCode = "TLS001_CERT_HOST_NOT_COVERED"
+	CodeTLSSNIListenerMismatch FindingCode = "TLS002_SNI_LISTENER_MISMATCH"
+	CodeTLSCatchAllListener    FindingCode = "TLS003_CATCH_ALL_LISTENER"
+	CodeTLSIngressNoSecret     FindingCode = "TLS004_INGRESS_TLS_NO_SECRET"
+	CodeTLSHostMissingFromTLS  FindingCode = "TLS005_HOST_MISSING_FROM_TLS"
+	CodeTLSDeprecatedVersion   FindingCode = "TLS006_DEPRECATED_VERSION"
+	CodeTLSBelowProfileVersion FindingCode = "TLS007_BELOW_PROFILE_VERSION"
+	CodeTLSCipherNotInProfile  FindingCode = "TLS008_CIPHER_NOT_IN_PROFILE"
+)
+
+// CNI.
+const (
+	CodeCNIMTUExceedsNode                FindingCode = "CNI001_MTU_EXCEEDS_NODE"
+	CodeCNIStackedEncapsulation          FindingCode = "CNI002_STACKED_ENCAPSULATION"
+	CodeCNIMTUMismatch                   FindingCode = "CNI003_MTU_MISMATCH"
+	CodeCNIMSSAboveMTU                   FindingCode = "CNI004_MSS_ABOVE_MTU"
+	CodeCNIAgentCheckFailed              FindingCode = "CNI005_AGENT_CHECK_FAILED"
+	CodeCNIAgentsNotReady                FindingCode = "CNI006_AGENTS_NOT_READY"
+	CodeCNIDaemonSetMissing              FindingCode = "CNI007_DAEMONSET_MISSING"
+	CodeCNIL7RuleRestricts               FindingCode = "CNI008_L7_RULE_RESTRICTS"
+	CodeCNIPathMTUBelowPodMTU            FindingCode = "CNI009_PATH_MTU_BELOW_POD_MTU"
+	CodeCNIPodMTUDrift                   FindingCode = "CNI010_POD_MTU_DRIFT"
+	CodeCNICIDRInPodCIDR                 FindingCode = "CNI011_CIDR_IN_POD_CIDR"
+	CodeCNIFQDNWithoutDNSProxy           FindingCode = "CNI012_FQDN_WITHOUT_DNS_PROXY"
+	CodeCNIL7WithoutProxy                FindingCode = "CNI013_L7_WITHOUT_PROXY"
+	CodeCNIDenyOverridesAllow            FindingCode = "CNI014_DENY_OVERRIDES_ALLOW"
+	CodeCNIEndpointNotEnforcing          FindingCode = "CNI015_ENDPOINT_NOT_ENFORCING"
+	CodeCNIPolicyInvalid                 FindingCode = "CNI016_POLICY_INVALID"
+	CodeCNIClusterMeshAPIServerUnhealthy FindingCode = "CNI017_CLUSTERMESH_APISERVER_UNHEALTHY"
+	CodeCNIRemoteClusterUnreachable      FindingCode = "CNI018_REMOTE_CLUSTER_UNREACHABLE"
+	CodeCNIClusterIdentityInvalid        FindingCode = "CNI019_CLUSTER_IDENTITY_INVALID"
+	CodeCNIEgressGatewayNoNode           FindingCode = "CNI020_EGRESS_GATEWAY_NO_NODE"
+	CodeCNIEgressGatewayPolicyInvalid    FindingCode = "CNI021_EGRESS_GATEWAY_POLICY_INVALID"
+	CodeCNIEgressGatewayDisabled         FindingCode = "CNI022_EGRESS_GATEWAY_DISABLED"
+	CodeCNIEgressGatewayConflict         FindingCode = "CNI023_EGRESS_GATEWAY_CONFLICT"
+	CodeCNIEncryptionDisabled            FindingCode = "CNI024_ENCRYPTION_DISABLED"
+	CodeCNINodeNotEncrypted              FindingCode = "CNI025_NODE_NOT_ENCRYPTED"
+	CodeCNIEncryptionKeyMismatch         FindingCode = "CNI026_ENCRYPTION_KEY_MISMATCH"
+	CodeCNIFlannelConfigInvalid          FindingCode = "CNI027_FLANNEL_CONFIG_INVALID"
+	CodeCNIFlannelNodeNotRegistered      FindingCode = "CNI028_FLANNEL_NODE_NOT_REGISTERED"
+	CodeCNIFlannelSubnetLeaseInvalid     FindingCode = "CNI029_FLANNEL_SUBNET_LEASE_INVALID"
+	CodeCNIFlannelRouteMissing           FindingCode = "CNI030_FLANNEL_ROUTE_MISSING"
+)
+
+// IP address management.
+const (
+	CodeIPAMNodeCIDRExhaustion         FindingCode = "IPAM001_NODE_CIDR_EXHAUSTION"
+	CodeIPAMPoolExhaustion             FindingCode = "IPAM002_POOL_EXHAUSTION"
+	CodeIPAMServiceCIDRExhaustion      FindingCode = "IPAM003_SERVICE_CIDR_EXHAUSTION"
+	CodeIPAMLoadBalancerPoolExhaustion FindingCode = "IPAM004_LB_POOL_EXHAUSTION"
+)
+
+// IPv6 and dual-stack.
+const (
+	CodeDualStackFamilyUnsupported FindingCode = "DUAL001_FAMILY_UNSUPPORTED"
+	CodeDualStackServiceSingle     FindingCode = "DUAL002_SERVICE_SINGLE_STACK"
+	CodeDualStackPolicySingle      FindingCode = "DUAL003_POLICY_SINGLE_FAMILY"
+	CodeDualStackAddressSingle     FindingCode = "DUAL004_ADDRESS_SINGLE_FAMILY"
+	CodeDualStackFamilyMismatch    FindingCode = "DUAL005_FAMILY_MISMATCH"
+)
+
+// Admission webhooks of networking controllers.
+const (
+	CodeWebhookServiceMissing   FindingCode = "WHK001_SERVICE_MISSING"
+	CodeWebhookNoReadyEndpoints FindingCode = "WHK002_NO_READY_ENDPOINTS"
+	CodeWebhookCABundleInvalid  FindingCode = "WHK003_CA_BUNDLE_INVALID"
+	CodeWebhookFailClosedBroad  FindingCode = "WHK004_FAIL_CLOSED_BROAD"
+	CodeWebhookSelfDeadlock     FindingCode = "WHK005_SELF_DEADLOCK"
+	CodeWebhookTimeoutHigh      FindingCode = "WHK006_TIMEOUT_HIGH"
+	CodeWebhookLatencyHigh      FindingCode = "WHK007_LATENCY_HIGH"
+)
+
+// Bare-metal load balancers (MetalLB).
+const (
+	CodeLBServicePending     FindingCode = "LB001_SERVICE_PENDING"
+	CodeLBPoolNotAdvertised  FindingCode = "LB002_POOL_NOT_ADVERTISED"
+	CodeLBPoolMissing        FindingCode = "LB003_POOL_MISSING"
+	CodeLBComponentsNotReady FindingCode = "LB004_COMPONENTS_NOT_READY"
+	CodeLBBGPSessionDown     FindingCode = "LB005_BGP_SESSION_DOWN"
+)
+
+// ingress-nginx.
+const (
+	CodeNginxInvalidAnnotation   FindingCode = "NGX001_INVALID_ANNOTATION"
+	CodeNginxServerBlockConflict FindingCode = "NGX002_SERVER_BLOCK_CONFLICT"
+	CodeNginxInvalidConfig       FindingCode = "NGX003_INVALID_CONFIG"
+	CodeNginxSnippetsAllowed     FindingCode = "NGX004_SNIPPETS_ALLOWED"
+	CodeNginxControllerNotReady  FindingCode = "NGX005_CONTROLLER_NOT_READY"
+)
+
+// Ingress annotations, by controller class.
+const (
+	CodeIngressUnknownAnnotation      FindingCode = "ING001_UNKNOWN_ANNOTATION"
+	CodeIngressDeprecatedAnnotation   FindingCode = "ING002_DEPRECATED_ANNOTATION"
+	CodeIngressConflictingAnnotations FindingCode = "ING003_CONFLICTING_ANNOTATIONS"
+	CodeIngressForeignAnnotation      FindingCode = "ING004_FOREIGN_ANNOTATION"
+	CodeIngressClassNotFound          FindingCode = "ING005_CLASS_NOT_FOUND"
+)
+
+// external-dns.
+const (
+	CodeExternalDNSNotReady            FindingCode = "EDNS001_CONTROLLER_NOT_READY"
+	CodeExternalDNSSourceDisabled      FindingCode = "EDNS002_SOURCE_DISABLED"
+	CodeExternalDNSHostnameFiltered    FindingCode = "EDNS003_HOSTNAME_FILTERED"
+	CodeExternalDNSNoTarget            FindingCode = "EDNS004_NO_TARGET"
+	CodeExternalDNSOwnershipConflict   FindingCode = "EDNS005_OWNERSHIP_CONFLICT"
+	CodeExternalDNSHostnameConflict    FindingCode = "EDNS006_HOSTNAME_CONFLICT"
+	CodeExternalDNSSyncErrors          FindingCode = "EDNS007_SYNC_ERRORS"
+	CodeExternalDNSHostnameUnrequested FindingCode = "EDNS008_HOSTNAME_NOT_REQUESTED"
+)
+
+// Service mesh data planes and other meshes.
+const (
+	CodeMeshSidecarNotReady         FindingCode = "MESH001_SIDECAR_NOT_READY"
+	CodeMeshSidecarRestarts         FindingCode = "MESH002_SIDECAR_RESTARTS"
+	CodeMeshInitContainerFailed     FindingCode = "MESH003_INIT_CONTAINER_FAILED"
+	CodeMeshControlPlaneCheckFailed FindingCode = "MESH004_CONTROL_PLANE_CHECK_FAILED"
+	CodeMeshControlPlaneNotReady    FindingCode = "MESH005_CONTROL_PLANE_NOT_READY"
+	CodeMeshAppMeshEndOfSupport     FindingCode = "MESH006_APPMESH_END_OF_SUPPORT"
+	CodeMeshAppMeshNotActive        FindingCode = "MESH007_APPMESH_NOT_ACTIVE"
+	CodeMeshAppMeshBackendMissing   FindingCode = "MESH008_APPMESH_BACKEND_MISSING"
+	CodeMeshProxyMemoryPressure     FindingCode = "MESH009_PROXY_MEMORY_PRESSURE"
+	CodeMeshProxyCPUThrottled       FindingCode = "MESH010_PROXY_CPU_THROTTLED"
+	CodeMeshProxyOOMKilled          FindingCode = "MESH011_PROXY_OOM_KILLED"
+	CodeMeshProxyOverloaded         FindingCode = "MESH012_PROXY_OVERLOADED"
+)
+
+// Managed cloud offerings.
+const (
+	CodeCloudControllerNotEnabled    FindingCode = "CLD001_CONTROLLER_NOT_ENABLED"
+	CodeCloudControllerNotReady      FindingCode = "CLD002_CONTROLLER_NOT_READY"
+	CodeCloudGatewayClassNotAccepted FindingCode = "CLD003_GATEWAY_CLASS_NOT_ACCEPTED"
+	CodeCloudGatewayNotProgrammed    FindingCode = "CLD004_GATEWAY_NOT_PROGRAMMED"
+	CodeCloudGatewayNoAddress        FindingCode = "CLD005_GATEWAY_NO_ADDRESS"
+	CodeCloudPolicyNotAttached       FindingCode = "CLD006_POLICY_NOT_ATTACHED"
+	CodeCloudPolicyTargetMissing     FindingCode = "CLD007_POLICY_TARGET_MISSING"
+	CodeCloudLatticeServiceMissing   FindingCode = "CLD008_LATTICE_SERVICE_MISSING"
+)
+
+// Active probes.
+const (
+	CodeProbeTCPFailed                FindingCode = "PRB001_TCP_FAILED"
+	CodeProbeDNSFailed                FindingCode = "PRB002_DNS_FAILED"
+	CodeProbeHTTPErrorStatus          FindingCode = "PRB003_HTTP_ERROR_STATUS"
+	CodeProbeHTTPFailed               FindingCode = "PRB004_HTTP_FAILED"
+	CodeProbeLeakedPod                FindingCode = "PRB005_LEAKED_POD"
+	CodeProbeLatencyHigh              FindingCode = "PRB006_LATENCY_HIGH"
+	CodeProbeRequestsFailed           FindingCode = "PRB007_REQUESTS_FAILED"
+	CodeCaptureFailed                 FindingCode = "PRB008_CAPTURE_FAILED"
+	CodeCaptureSYNUnanswered          FindingCode = "PRB009_SYN_UNANSWERED"
+	CodeCaptureConnectionsReset       FindingCode = "PRB010_CONNECTIONS_RESET"
+	CodeCaptureRetransmissions        FindingCode = "PRB011_RETRANSMISSIONS"
+	CodeConnectionsSYNSentPileup      FindingCode = "PRB012_SYN_SENT_PILEUP"
+	CodeConnectionsTimeWaitExhaustion FindingCode = "PRB013_TIME_WAIT_EXHAUSTION"
+	CodeConnectionsStaleNAT           FindingCode = "PRB014_STALE_NAT_ENTRIES"
+	CodeConnectionsConntrackFull      FindingCode = "PRB015_CONNTRACK_TABLE_FULL"
+)
+
+// Logs.
+const (
+	CodeLogsOutputTruncated   FindingCode = "LOG001_OUTPUT_TRUNCATED"
+	CodeLogsErrorsFound       FindingCode = "LOG002_ERRORS_FOUND"
+	CodeLogsAccessLogFailures FindingCode = "LOG003_ACCESS_LOG_FAILURES"
+)
+
+// Metrics and traces.
+const (
+	CodeObservabilityService5xxRate      FindingCode = "OBS001_SERVICE_5XX_RATE"
+	CodeObservabilityEnvoyCluster5xxRate FindingCode = "OBS002_ENVOY_CLUSTER_5XX_RATE"
+	CodeObservabilityFailingTraces       FindingCode = "OBS003_FAILING_TRACES"
+	CodeObservabilityNoTelemetryTemplate FindingCode = "OBS004_NO_TELEMETRY_TEMPLATE"
+)
+
+// Request triage.
+const (
+	CodeTriageNoListener            FindingCode = "RTE001_NO_LISTENER"
+	CodeTriageNoRouteForHost        FindingCode = "RTE002_NO_ROUTE_FOR_HOST"
+	CodeTriageNoRouteMatch          FindingCode = "RTE003_NO_ROUTE_MATCH"
+	CodeTriageDirectResponse        FindingCode = "RTE004_DIRECT_RESPONSE"
+	CodeTriageBackendServiceMissing FindingCode = "RTE005_BACKEND_SERVICE_MISSING"
+	CodeTriageNotHTTPServer         FindingCode = "RTE006_NOT_HTTP_SERVER"
+)
+
+// Traffic policy verification.
+const (
+	CodeTrafficTimeoutBudgetExceeded FindingCode = "TRP001_TIMEOUT_BUDGET_EXCEEDED"
+	CodeTrafficFaultIneffective      FindingCode = "TRP002_FAULT_INEFFECTIVE"
+	CodeTrafficMirrorBackendMissing  FindingCode = "TRP003_MIRROR_BACKEND_MISSING"
+	CodeTrafficPolicyNotObserved     FindingCode = "TRP004_POLICY_NOT_OBSERVED"
+)
+
+// Cross-cluster configuration diff.
+const (
+	CodeDiffOnlyInOneCluster      FindingCode = "CFG001_ONLY_IN_ONE_CLUSTER"
+	CodeDiffDiffers               FindingCode = "CFG002_DIFFERS"
+	CodeDiffMeshEnrollmentDiffers FindingCode = "CFG003_MESH_ENROLLMENT_DIFFERS"
+	CodeDiffKindNotCompared       FindingCode = "CFG004_KIND_NOT_COMPARED"
+)
+
+// OpenAPI coverage.
+const (
+	CodeOpenAPIServiceNotRouted      FindingCode = "API001_SERVICE_NOT_ROUTED"
+	CodeOpenAPIOperationsNotRoutable FindingCode = "API002_OPERATIONS_NOT_ROUTABLE"
+	CodeOpenAPIPathsNotInSpec        FindingCode = "API003_PATHS_NOT_IN_SPEC"
+)
+
+// Design tools.
+const (
+	CodeDesignTargetServiceMissing       FindingCode = "DSN001_TARGET_SERVICE_MISSING"
+	CodeDesignReferenceGrantRequired     FindingCode = "DSN002_REFERENCE_GRANT_REQUIRED"
+	CodeDesignTLSSecretMissing           FindingCode = "DSN003_TLS_SECRET_MISSING"
+	CodeDesignPeerAuthenticationConflict FindingCode = "DSN004_PEER_AUTHENTICATION_CONFLICT"
+	CodeDesignWeightsNot100              FindingCode = "DSN005_WEIGHTS_NOT_100"
+	CodeDesignCanaryControllerMissing    FindingCode = "DSN006_CANARY_CONTROLLER_MISSING"
+)
+
+// Canary rollouts.
+const (
+	CodeCanaryFailed         FindingCode = "CNY001_CANARY_FAILED"
+	CodeCanaryAnalysisFailed FindingCode = "CNY002_ANALYSIS_FAILED"
+	CodeCanaryMetricProvider FindingCode = "CNY003_METRIC_PROVIDER_INVALID"
+	CodeCanaryWeightMismatch FindingCode = "CNY004_WEIGHT_MISMATCH"
+	CodeCanaryRouteMissing   FindingCode = "CNY005_ROUTE_MISSING"
+)
+
+// Scaling.
+const (
+	CodeScalingLoadUnmeasured      FindingCode = "SCL001_LOAD_UNMEASURED"
+	CodeScalingQuotaLimitsReplicas FindingCode = "SCL002_QUOTA_LIMITS_REPLICAS"
+	CodeScalingUndersized          FindingCode = "SCL003_UNDERSIZED"
+)
+
+// GitOps.
+const (
+	CodeGitOpsResourceOutOfSync FindingCode = "GIT001_RESOURCE_OUT_OF_SYNC"
+	CodeGitOpsAppOutOfSync      FindingCode = "GIT002_APP_OUT_OF_SYNC"
+	CodeGitOpsAppUnhealthy      FindingCode = "GIT003_APP_UNHEALTHY"
+	CodeGitOpsSyncFailed        FindingCode = "GIT004_SYNC_FAILED"
+	CodeGitOpsSuspended         FindingCode = "GIT005_SUSPENDED"
+)
+
+// Manifest validation.
+const (
+	CodeManifestInvalid      FindingCode = "MAN001_INVALID"
+	CodeManifestNotValidated FindingCode = "MAN002_NOT_VALIDATED"
+)
+
+// Change impact.
+const (
+	CodeChangeBreaksFlow    FindingCode = "CHG001_BREAKS_FLOW"
+	CodeChangeRestrictsFlow FindingCode = "CHG002_RESTRICTS_FLOW"
+	CodeChangeOpensFlow     FindingCode = "CHG003_OPENS_FLOW"
+)
+
+// RBAC self-check.
+const (
+	CodeRBACPermissionMissing FindingCode = "RBAC001_PERMISSION_MISSING"
+	CodeRBACCheckFailed       FindingCode = "RBAC002_CHECK_FAILED"
+)
+
+// Server self-check.
+const (
+	CodeServerToolErrorRate   FindingCode = "SRV001_TOOL_ERROR_RATE"
+	CodeServerToolNearTimeout FindingCode = "SRV002_TOOL_NEAR_TIMEOUT"
+	CodeServerAPIForbidden    FindingCode = "SRV003_API_FORBIDDEN"
+	CodeServerAPIThrottled    FindingCode = "SRV004_API_THROTTLED"
+)
+
+// Hostname conflicts.
+const (
+	CodeHostnameMultipleEntryPoints FindingCode = "HOST001_MULTIPLE_ENTRY_POINTS"
+	CodeHostnameConflictingBackends FindingCode = "HOST002_CONFLICTING_BACKENDS"
+)
+
+// FindingCodeInfo describes a finding code.
+type FindingCodeInfo struct {
+	Code        FindingCode `json:"code"`
+	Category    string      `json:"category"`
+	Description string      `json:"description"`
+}
+
+var findingCodes = []FindingCodeInfo{
+	{CodeGatewayListenerConflict, CategoryRouting, "Two listeners of a Gateway use the same port and protocol"},
+	{CodeGatewayCertSecretMissing, CategoryTLS, "A listener certificateRef points to a Secret that does not exist"},
+	{CodeGatewayTLSCertRefsMissing, CategoryTLS, "An HTTPS or TLS listener terminates TLS without certificateRefs"},
+	{CodeGatewayPassthroughWithCerts, CategoryTLS, "A TLS Passthrough listener also sets certificateRefs"},
+	{CodeGatewayListenerUnhealthy, CategoryRouting, "A listener reports a False condition or has no attached routes"},
+	{CodeGatewayConditionFalse, CategoryRouting, "A Gateway is not Accepted or not Programmed"},
+	{CodeGatewayBackendServiceMissing, CategoryRouting, "A route backendRef points to a Service that does not exist"},
+	{CodeGatewayBackendNoEndpoints, CategoryRouting, "A route backend Service has no ready endpoints"},
+	{CodeGatewayRouteConditionFalse, CategoryRouting, "A route reports a False condition for one of its parents"},
+	{CodeGatewayReferenceGrantMissing, CategoryPolicy, "A cross-namespace reference is not allowed by any ReferenceGrant"},
+	{CodeGatewayMeshServiceMissing, CategoryRouting, "A GAMMA mesh route has a parent Service that does not exist"},
+	{CodeGatewayParentMissing, CategoryRouting, "A route parentRef points to a Gateway that does not exist"},
+	{CodeGatewayParentListenerMissing, CategoryRouting, "A route parentRef sectionName names a listener the Gateway does not have"},
+	{CodeGatewayFilterConfigMissing, CategoryRouting, "A route filter sets its type without the matching configuration"},
+	{CodeGatewayWaypointMissing, CategoryMesh, "A Service uses a waypoint Gateway that does not exist"},
+	{CodeGatewayWaypointNotProgrammed, CategoryMesh, "A waypoint Gateway is not Programmed"},
+	{CodeGatewayWaypointPodsUnready, CategoryMesh, "A waypoint Gateway has no ready proxy pods"},
+	{CodeGatewayResourceNotFound, CategoryRouting, "The Gateway API resource to validate does not exist"},
+	{CodeGatewaySpecFieldMissing, CategoryRouting, "A required spec field of a Gateway API resource is missing"},
+	{CodeGatewaySpecInvalidValue, CategoryRouting, "A spec field of a Gateway API resource has an invalid value"},
+	{CodeGatewayWeightsNot100, CategoryRouting, "The backendRef weights of a route rule do not sum to 100"},
+	{CodeGatewayWeightedBackendNoEndpoints, CategoryRouting, "A weighted backend receives traffic but has no ready endpoints"},
+	{CodeGatewayBackendTimeoutExceedsRequest, CategoryRouting, "A route rule backendRequest timeout exceeds its request timeout"},
+	{CodeGatewayBackendPortNotExposed, CategoryRouting, "A backendRef port is not a port of the Service"},
+	{CodeGatewayBackendPortProtocol, CategoryRouting, "A backend Service port protocol or appProtocol does not fit the route"},
+	{CodeGatewayTargetPortUnresolved, CategoryConnectivity, "A backend Service targetPort does not resolve to a container port"},
+	{CodeGatewayFeatureUnsupported, CategoryRouting, "A resource uses an extended feature missing from its GatewayClass supportedFeatures"},
+	{CodeGatewayClassNotAccepted, CategoryRouting, "A GatewayClass is not accepted by its controller"},
+	{CodeGatewayFeaturesUnpublished, CategoryRouting, "A GatewayClass does not publish status.supportedFeatures"},
+	{CodeGatewayRouteShadowed, CategoryRouting, "An HTTPRoute match is identical to one with higher precedence and never receives traffic"},
+	{CodeGatewayListenerProtocolMismatch, CategoryRouting, "A TCPRoute, TLSRoute or UDPRoute parent has no listener of the route's protocol"},
+	{CodeGatewayTLSRouteNotPassthrough, CategoryTLS, "A TLSRoute attaches to a listener that terminates TLS instead of passing it through"},
+	{CodeGatewaySNIHostnameMismatch, CategoryTLS, "No TLSRoute hostname matches the hostname of the listener it attaches to"},
+	{CodeGatewayBackendTLSPolicyMissing, CategoryTLS, "An HTTPRoute backend Service port serves TLS but no BackendTLSPolicy or appProtocol tells the gateway to originate TLS"},
+	{CodeGatewayBackendAppProtocolMissing, CategoryRouting, "An HTTPRoute backend Service port serves HTTP/2 cleartext but has no appProtocol, so the gateway speaks HTTP/1.1 to it"},
+	{CodeGatewayBackendTLSPolicyInvalid, CategoryTLS, "A BackendTLSPolicy targets a missing Service or port, lacks a validation hostname or CA, or is not accepted"},
+	{CodeGatewayClassMissing, CategoryRouting, "A Gateway references a GatewayClass that does not exist"},
+	{CodeGatewayControllerNotRunning, CategoryRouting, "No controller has reconciled a GatewayClass or Gateway: its status is still Pending"},
+	{CodeGatewayParametersRefMissing, CategoryRouting, "The parametersRef of a GatewayClass or Gateway infrastructure points to an object that does not exist"},
+	{CodeGatewayRateLimited, CategoryPolicy, "A gateway rate limit rejects requests to a route with 429"},
+	{CodeGatewayRateLimitSourceUnknown, CategoryPolicy, "A gateway rejects requests with 429 but no rate limit policy applies to the route"},
+	{CodeGatewayUpstreamRateLimited, CategoryPolicy, "A route's backend, not the gateway, answers requests with 429"},
+	{CodeGatewayRouteNotServing, CategoryRouting, "A route rule accepted in status does not serve requests crafted to match it through the gateway"},
+	{CodeGatewayRouteWrongBackend, CategoryRouting, "A request crafted to match a route rule is answered by a backend the rule does not reference"},
+	{CodeGatewayUnreachable, CategoryConnectivity, "None of the requests crafted for a Gateway's routes got a response at its address"},
+	{CodeIstioWeightsNot100, CategoryRouting, "The destination weights of a VirtualService route do not sum to 100"},
+	{CodeIstioRetryExceedsTimeout, CategoryRouting, "perTryTimeout times attempts exceeds the route timeout"},
+	{CodeIstioAnalyzerMessage, CategoryMesh, "istioctl analyze reported a warning or error in the resource status"},
+	{CodeIstioConditionFalse, CategoryMesh, "An Istio resource reports a False condition"},
+	{CodeIstioInjectionDisabled, CategoryMesh, "Sidecar injection is not enabled for the namespace or workload"},
+	{CodeIstioMTLSConflict, CategoryTLS, "A DestinationRule disables TLS where PeerAuthentication enforces STRICT mTLS"},
+	{CodeIstioVirtualServiceNoHosts, CategoryRouting, "A VirtualService has no hosts"},
+	{CodeIstioRouteShadowed, CategoryRouting, "A VirtualService route is unreachable behind an earlier route"},
+	{CodeIstioDestinationUnset, CategoryRouting, "A VirtualService route destination has no host"},
+	{CodeIstioDestinationServiceMissing, CategoryRouting, "A VirtualService or DestinationRule host does not match a Service"},
+	{CodeIstioClientCertMissing, CategoryTLS, "A DestinationRule uses MUTUAL TLS without a client certificate or key"},
+	{CodeIstioConnectionPoolInvalid, CategoryMesh, "A DestinationRule connection pool limit is not positive"},
+	{CodeIstioSubsetNoPods, CategoryMesh, "The labels of a DestinationRule subset match no pods"},
+	{CodeIstioSubsetMissing, CategoryRouting, "A VirtualService references a subset no DestinationRule defines"},
+	{CodeIstioAuthzDenyAll, CategoryPolicy, "A DENY AuthorizationPolicy or rule matches all traffic"},
+	{CodeIstioAuthzAllowNothing, CategoryPolicy, "An ALLOW AuthorizationPolicy without rules denies all traffic"},
+	{CodeIstioAuthzAllowDenyConflict, CategoryPolicy, "ALLOW and DENY AuthorizationPolicies select the same workloads"},
+	{CodeIstioAuthzDenyAffectsService, CategoryRouting, "A DENY AuthorizationPolicy applies to the workloads of a routed Service"},
+	{CodeIstioOutlierDetectionAggressive, CategoryMesh, "DestinationRule outlier detection ejects hosts too eagerly or too long"},
+	{CodeIstioVersionSkew, CategoryMesh, "A sidecar proxy runs a different Istio version than the control plane"},
+	{CodeIstioSidecarPending, CategoryMesh, "Injection is enabled for a workload but its pods have no sidecar"},
+	{CodeIstioRevisionMissing, CategoryMesh, "A namespace or sidecar references an Istio revision or revision tag that is not installed"},
+	{CodeIstioRevisionMismatch, CategoryMesh, "Sidecars still run the revision that injected them after their namespace moved to another revision"},
+	{CodeIstiodUnhealthy, CategoryMesh, "istiod replicas are not ready or keep restarting"},
+	{CodeIstioXDSRejected, CategoryMesh, "A proxy rejected (NACKed) configuration pushed by istiod"},
+	{CodeIstioXDSPushErrors, CategoryMesh, "istiod failed to build or send xDS configuration"},
+	{CodeIstioListenerConflict, CategoryMesh, "istiod dropped listeners because Services or ServiceEntries claim the same port with conflicting protocols"},
+	{CodeIstioEnvoyFilterHighRisk, CategoryMesh, "An EnvoyFilter patches listeners or network filters broadly enough to break all traffic it applies to"},
+	{CodeIstioEnvoyFilterVersionMismatch, CategoryMesh, "An EnvoyFilter proxyVersion match does not select the installed Istio version, so its patch is skipped"},
+	{CodeIstioEnvoyFilterOverlap, CategoryMesh, "Several EnvoyFilters modify the same configuration path of the same proxies"},
+	{CodeIstioOutlierEjectsAllHosts, CategoryMesh, "DestinationRule outlier detection can eject every endpoint of a host, failing all requests with 503 UH"},
+	{CodeIstioRetryEjectionAmplification, CategoryMesh, "Route retries and outlier ejection together multiply the load on the endpoints left"},
+	{CodeIstioOutlierEjections, CategoryMesh, "Proxies ejected endpoints of a DestinationRule host or subset"},
+	{CodeKgatewayNotAccepted, CategoryMesh, "kgateway rejected a resource"},
+	{CodeKgatewayConditionFalse, CategoryMesh, "A kgateway resource reports a False condition"},
+	{CodeKgatewayParametersUnused, CategoryMesh, "No Gateway references a GatewayParameters"},
+	{CodeKgatewayParametersInvalid, CategoryMesh, "A GatewayParameters sets an invalid replica count or untagged image"},
+	{CodeKgatewayServiceAccountMissing, CategoryMesh, "A GatewayParameters references a ServiceAccount that does not exist"},
+	{CodeKgatewayTargetRefInvalid, CategoryPolicy, "A kgateway policy targetRef is empty or points to a missing resource"},
+	{CodeKgatewayUpstreamMissing, CategoryRouting, "A kgateway resource references an Upstream, Service or Backend that does not exist"},
+	{CodeKgatewayPolicyConflict, CategoryPolicy, "Several kgateway policies target the same resource"},
+	{CodeKgatewayControlPlaneDown, CategoryMesh, "The kgateway control plane has no running pods"},
+	{CodeKgatewayPodUnhealthy, CategoryMesh, "A kgateway pod is failing, not ready or restarting"},
+	{CodeKgatewayGatewayNotProgrammed, CategoryRouting, "A kgateway Gateway is not Accepted or not Programmed"},
+	{CodeKgatewayNoDataPlane, CategoryRouting, "A kgateway Gateway has no proxy pods"},
+	{CodeKgatewayRateLimitConfigRejected, CategoryPolicy, "A RateLimitConfig is rejected and its limits are not enforced"},
+	{CodeKgatewayBackendInvalid, CategoryRouting, "A kgateway Backend has no hosts, ports or function to send traffic to"},
+	{CodeKgatewayBackendUnused, CategoryRouting, "No route or policy references a kgateway Backend"},
+	{CodeKgatewayPolicyOverridden, CategoryPolicy, "A kgateway policy is only partly valid, or overridden by another policy on the same target"},
+	{CodeKgatewayRouteMissingFromXDS, CategoryRouting, "An accepted HTTPRoute host or rule is absent from the xDS configuration kgateway serves to the Gateway"},
+	{CodeKgatewayXDSBackendMismatch, CategoryRouting, "The xDS route of an HTTPRoute rule answers directly or forwards to clusters other than its backends"},
+	{CodeServiceNoEndpoints, CategoryConnectivity, "A Service has no ready endpoints"},
+	{CodeServiceSelectorNoPods, CategoryConnectivity, "A Service selector matches no pods"},
+	{CodeServiceIngressBackendMissing, CategoryRouting, "An Ingress backend Service does not exist"},
+	{CodeServiceExposurePending, CategoryConnectivity, "A LoadBalancer, Ingress or Gateway has no external address yet"},
+	{CodeServiceExposedUnprotected, CategoryPolicy, "A Service is exposed externally without a NetworkPolicy or AuthorizationPolicy"},
+	{CodeServiceLocalPolicyNoEndpoints, CategoryConnectivity, "A Service with a Local traffic policy has no ready endpoints"},
+	{CodeServiceLocalPolicyNodesWithoutEndpoint, CategoryConnectivity, "A Local traffic policy drops traffic reaching nodes without a ready endpoint"},
+	{CodeServiceLocalPolicyMeshMismatch, CategoryConnectivity, "A Local traffic policy conflicts with how the mesh or CNI routes the Service"},
+	{CodeServiceLocalPolicyNoHealthCheck, CategoryConnectivity, "An externalTrafficPolicy Local LoadBalancer has no healthCheckNodePort"},
+	{CodeServiceLocalPolicySingleNode, CategoryConnectivity, "All endpoints of a Local traffic policy Service run on one node"},
+	{CodeServiceSessionAffinityIneffective, CategoryConnectivity, "Session affinity has no effect for some clients of a Service"},
+	{CodeServiceSessionAffinityUneven, CategoryConnectivity, "Session affinity pins all clients arriving through a node to one endpoint"},
+	{CodeServiceHealthCheckPortMismatch, CategoryConnectivity, "The load balancer health check does not probe the healthCheckNodePort over HTTP"},
+	{CodeClusterUnreachable, CategoryConnectivity, "A configured cluster cannot be reached"},
+	{CodeNetworkPolicyDenyAllIngress, CategoryPolicy, "A NetworkPolicy blocks all ingress to the pods it selects"},
+	{CodeNetworkPolicyInvalidSelector, CategoryPolicy, "A NetworkPolicy has an invalid label selector"},
+	{CodeNetworkPolicyPortProtocolMismatch, CategoryPolicy, "A NetworkPolicy named port is declared with another protocol"},
+	{CodeNetworkPolicyNamedPortUndefined, CategoryPolicy, "A NetworkPolicy named port is not defined by any selected pod"},
+	{CodeNetworkPolicyBlockingTraffic, CategoryPolicy, "A NetworkPolicy may block the traffic being diagnosed"},
+	{CodeNetworkPolicyPodNotSelected, CategoryPolicy, "A pod is selected by no NetworkPolicy, so all its traffic is allowed"},
+	{CodeNetworkPolicyServicePortNotAdmitted, CategoryPolicy, "A Service port is admitted by no ingress rule of the policies isolating its pods"},
+	{CodeNetworkPolicyNoDefaultDeny, CategoryPolicy, "A namespace has no default-deny NetworkPolicy"},
+	{CodeEgressWildcard, CategoryPolicy, "Workloads may reach any external destination on any port"},
+	{CodeEgressPlaintextSensitivePort, CategoryTLS, "Egress to a sensitive port of a plaintext protocol is allowed without TLS"},
+	{CodeEgressMeshAllowAny, CategoryPolicy, "The mesh lets sidecars reach hosts without a ServiceEntry"},
+	{CodeTenantIsolationBroken, CategoryPolicy, "Traffic can flow between two tenants that should be isolated"},
+	{CodeTenantPathAllowed, CategoryPolicy, "NetworkPolicies allow a direction and port between two tenants"},
+	{CodeTenantProbeConnected, CategoryConnectivity, "A live probe connected from one tenant to another"},
+	{CodeTenantPoliciesNotEvaluated, CategoryPolicy, "Non-Kubernetes network policies apply to the tenants and were not evaluated"},
+	{CodeDNSLookupFailed, CategoryDNS, "A DNS lookup failed"},
+	{CodeDNSKubeDNSNoEndpoints, CategoryDNS, "The kube-dns Service has no ready endpoints"},
+	{CodeDNSKubeDNSMissing, CategoryDNS, "The kube-dns Service does not exist"},
+	{CodeDNSCorefileMissing, CategoryDNS, "The CoreDNS ConfigMap has no Corefile"},
+	{CodeDNSCorefileParseError, CategoryDNS, "A Corefile does not parse"},
+	{CodeDNSLegacyConfigMapKeys, CategoryDNS, "The kube-dns ConfigMap sets keys CoreDNS ignores"},
+	{CodeDNSCoreDNSLogErrors, CategoryDNS, "CoreDNS logged errors"},
+	{CodeDNSDuplicateZone, CategoryDNS, "Two Corefile server blocks serve the same zone and port"},
+	{CodeDNSKubernetesPluginMissing, CategoryDNS, "No Corefile server block has the kubernetes plugin"},
+	{CodeDNSClusterDomainNotServed, CategoryDNS, "The kubernetes plugin does not serve the cluster domain"},
+	{CodeDNSNoRootZone, CategoryDNS, "No Corefile server block serves the root zone"},
+	{CodeDNSForwardMissing, CategoryDNS, "A server block serving the root zone does not forward"},
+	{CodeDNSForwardInvalid, CategoryDNS, "A forward directive has no or invalid upstreams"},
+	{CodeDNSRemovedPlugin, CategoryDNS, "The Corefile uses a plugin removed from CoreDNS"},
+	{CodeDNSForwardingLoop, CategoryDNS, "DNS forwards back to itself"},
+	{CodeDNSLoopDetectionDisabled, CategoryDNS, "The loop plugin is missing"},
+	{CodeDNSCacheDisabled, CategoryDNS, "The cache plugin is missing or misconfigured"},
+	{CodeDNSNodeLocalSetupInvalid, CategoryDNS, "The node-local-dns DaemonSet or its Service is set up incorrectly"},
+	{CodeDNSNodeLocalPlaceholders, CategoryDNS, "The node-local-dns Corefile still has unreplaced placeholders"},
+	{CodeDNSNodeLocalBindMismatch, CategoryDNS, "node-local-dns binds addresses that differ from the kubelet clusterDNS"},
+	{CodeDNSNodeLocalConfigMapMissing, CategoryDNS, "The node-local-dns ConfigMap does not exist"},
+	{CodeDNSNodeLocalUpstreamInvalid, CategoryDNS, "The node-local-dns upstream Service is missing, lacks port 53 or has no ready endpoints"},
+	{CodeDNSNodeLocalPodMissing, CategoryDNS, "A node has no ready node-local-dns pod"},
+	{CodeDNSNodeLocalRestarts, CategoryDNS, "A node-local-dns pod restarted recently"},
+	{CodeDNSServfailRate, CategoryDNS, "CoreDNS answers a high share of queries with SERVFAIL"},
+	{CodeKubeProxyDaemonSetMissing, CategoryConnectivity, "The kube-proxy DaemonSet does not exist"},
+	{CodeKubeProxyPodsUnavailable, CategoryConnectivity, "Some kube-proxy pods are unavailable"},
+	{CodeKubeProxyNodeWithoutProxy, CategoryConnectivity, "A node has no healthy kube-proxy"},
+	{CodeKubeProxyRestarts, CategoryConnectivity, "kube-proxy restarted recently or is crash-looping"},
+	{CodeKubeProxyVersionDrift, CategoryConnectivity, "kube-proxy versions differ across nodes"},
+	{CodeKubeProxyVersionSkew, CategoryConnectivity, "kube-proxy is outside the supported version skew"},
+	{CodeTLSCertHostNotCovered, CategoryTLS, "A certificate does not cover a host it serves"},
+	{CodeTLSSNIListenerMismatch, CategoryTLS, "SNI selects a listener serving another certificate"},
+	{CodeTLSCatchAllListener, CategoryTLS, "A listener without hostname serves a certificate for specific hosts"},
+	{CodeTLSIngressNoSecret, CategoryTLS, "An Ingress TLS entry has no secretName"},
+	{CodeTLSHostMissingFromTLS, CategoryTLS, "An Ingress host is routed but missing from spec.tls"},
+	{CodeTLSDeprecatedVersion, CategoryTLS, "TLS 1.0 or 1.1 is allowed"},
+	{CodeTLSBelowProfileVersion, CategoryTLS, "The allowed TLS versions fall below the profile minimum"},
+	{CodeTLSCipherNotInProfile, CategoryTLS, "A cipher suite is not allowed by the profile"},
+	{CodeCNIMTUExceedsNode, CategoryConnectivity, "The pod MTU plus encapsulation overhead exceeds the node MTU"},
+	{CodeCNIStackedEncapsulation, CategoryConnectivity, "Several encapsulations are stacked"},
+	{CodeCNIMTUMismatch, CategoryConnectivity, "CNI components disagree on the pod MTU"},
+	{CodeCNIMSSAboveMTU, CategoryConnectivity, "A proxy advertises a TCP MSS above the pod MTU"},
+	{CodeCNIAgentCheckFailed, CategoryConnectivity, "The CNI agent pods could not be checked"},
+	{CodeCNIAgentsNotReady, CategoryConnectivity, "Some CNI agent pods are not ready"},
+	{CodeCNIDaemonSetMissing, CategoryConnectivity, "The CNI DaemonSet does not exist"},
+	{CodeCNIL7RuleRestricts, CategoryPolicy, "A Cilium L7 rule restricts traffic to specific requests"},
+	{CodeCNIPathMTUBelowPodMTU, CategoryConnectivity, "The measured path MTU is below the pod interface MTU"},
+	{CodeCNIPodMTUDrift, CategoryConnectivity, "A pod interface MTU differs from the MTU the CNI is configured with"},
+	{CodeCNICIDRInPodCIDR, CategoryPolicy, "A Cilium CIDR rule is inside the pod CIDR, where pods are matched by identity and never by address"},
+	{CodeCNIFQDNWithoutDNSProxy, CategoryPolicy, "A Cilium toFQDNs rule cannot learn addresses: the DNS proxy is disabled or no DNS rule covers the endpoints"},
+	{CodeCNIL7WithoutProxy, CategoryPolicy, "A Cilium L7 rule cannot be enforced: the L7 proxy is disabled or the rule has no ports to redirect"},
+	{CodeCNIDenyOverridesAllow, CategoryPolicy, "A Cilium deny rule takes precedence over allow rules selecting the same endpoints"},
+	{CodeCNIEndpointNotEnforcing, CategoryPolicy, "Endpoints selected by a Cilium L7 policy do not enforce policy"},
+	{CodeCNIPolicyInvalid, CategoryPolicy, "Cilium rejected a policy as invalid or failed to import it on some nodes"},
+	{CodeCNIClusterMeshAPIServerUnhealthy, CategoryConnectivity, "The clustermesh-apiserver is not ready or not reachable from remote clusters"},
+	{CodeCNIRemoteClusterUnreachable, CategoryConnectivity, "Some Cilium agents are not connected to a remote cluster of the mesh"},
+	{CodeCNIClusterIdentityInvalid, CategoryConnectivity, "The Cilium cluster name or ID cannot identify the cluster in a mesh"},
+	{CodeCNIEgressGatewayNoNode, CategoryConnectivity, "A CiliumEgressGatewayPolicy has no Ready gateway node, so its traffic is dropped"},
+	{CodeCNIEgressGatewayPolicyInvalid, CategoryConnectivity, "A CiliumEgressGatewayPolicy has invalid or ineffective destinations or gateway settings"},
+	{CodeCNIEgressGatewayDisabled, CategoryConnectivity, "The Cilium egress gateway or BPF masquerading it needs is disabled"},
+	{CodeCNIEgressGatewayConflict, CategoryConnectivity, "Two CiliumEgressGatewayPolicies apply to the same pods and destinations"},
+	{CodeCNIEncryptionDisabled, CategoryConnectivity, "Cilium or Calico transparent encryption is disabled, so pod traffic between nodes is not encrypted"},
+	{CodeCNINodeNotEncrypted, CategoryConnectivity, "A node has no encryption key or is excluded from transparent encryption"},
+	{CodeCNIEncryptionKeyMismatch, CategoryConnectivity, "Nodes advertise the same WireGuard key or different IPsec key indices"},
+	{CodeCNIFlannelConfigInvalid, CategoryConnectivity, "The Flannel net-conf.json is invalid or uses a deprecated backend"},
+	{CodeCNIFlannelNodeNotRegistered, CategoryConnectivity, "Flannel has not registered a node, or registered it with the wrong backend or a shared public IP"},
+	{CodeCNIFlannelSubnetLeaseInvalid, CategoryConnectivity, "A node's Flannel subnet lease is missing, outside the Network or overlaps another node's"},
+	{CodeCNIFlannelRouteMissing, CategoryConnectivity, "A node lacks the Flannel interface or routes to other nodes' pod subnets"},
+	{CodeIPAMNodeCIDRExhaustion, CategoryConnectivity, "A node is running out of pod addresses"},
+	{CodeIPAMPoolExhaustion, CategoryConnectivity, "A CNI IP pool is running out of addresses or blocks"},
+	{CodeIPAMServiceCIDRExhaustion, CategoryConnectivity, "The Service CIDR is running out of ClusterIPs"},
+	{CodeIPAMLoadBalancerPoolExhaustion, CategoryConnectivity, "A LoadBalancer address pool is running out of addresses"},
+	{CodeDualStackFamilyUnsupported, CategoryConnectivity, "A Service uses an IP family the pod network does not provide"},
+	{CodeDualStackServiceSingle, CategoryConnectivity, "An externally exposed Service is single-stack on a dual-stack cluster"},
+	{CodeDualStackPolicySingle, CategoryPolicy, "A NetworkPolicy opens all addresses of one IP family but not the other"},
+	{CodeDualStackAddressSingle, CategoryConnectivity, "A dual-stack LoadBalancer or Gateway has addresses of only one family"},
+	{CodeDualStackFamilyMismatch, CategoryConnectivity, "The pod network and the Service network do not provide the same IP families"},
+	{CodeWebhookServiceMissing, CategoryConnectivity, "An admission webhook calls a Service or port that does not exist"},
+	{CodeWebhookNoReadyEndpoints, CategoryConnectivity, "The Service of an admission webhook has no ready endpoints"},
+	{CodeWebhookCABundleInvalid, CategoryTLS, "An admission webhook caBundle is missing, unreadable, expired or about to expire"},
+	{CodeWebhookFailClosedBroad, CategoryPolicy, "A fail-closed webhook intercepts workloads in every namespace, including kube-system"},
+	{CodeWebhookSelfDeadlock, CategoryPolicy, "A fail-closed webhook intercepts the pods of its own namespace"},
+	{CodeWebhookTimeoutHigh, CategoryPolicy, "A fail-closed webhook has a timeout above 10 seconds"},
+	{CodeWebhookLatencyHigh, CategoryConnectivity, "An admission webhook is slow or unreachable from a probe pod"},
+	{CodeLBServicePending, CategoryConnectivity, "A LoadBalancer Service has no external address"},
+	{CodeLBPoolNotAdvertised, CategoryConnectivity, "No L2 or BGP advertisement announces an address pool"},
+	{CodeLBPoolMissing, CategoryConnectivity, "An address pool a Service requests does not exist"},
+	{CodeLBComponentsNotReady, CategoryConnectivity, "The load balancer controller or speakers are missing or not ready"},
+	{CodeLBBGPSessionDown, CategoryConnectivity, "A load balancer BGP session is not established"},
+	{CodeNginxInvalidAnnotation, CategoryRouting, "An ingress-nginx annotation has an invalid value or is not allowed"},
+	{CodeNginxServerBlockConflict, CategoryRouting, "Ingresses for the same host conflict in one nginx server block"},
+	{CodeNginxInvalidConfig, CategoryRouting, "An ingress-nginx ConfigMap setting has an invalid value"},
+	{CodeNginxSnippetsAllowed, CategoryPolicy, "ingress-nginx allows configuration snippet annotations"},
+	{CodeNginxControllerNotReady, CategoryRouting, "The ingress-nginx controller is missing or not ready"},
+	{CodeIngressUnknownAnnotation, CategoryRouting, "An Ingress annotation is not one its controller reads, e.g. a typo"},
+	{CodeIngressDeprecatedAnnotation, CategoryRouting, "An Ingress annotation is a deprecated or removed spelling"},
+	{CodeIngressConflictingAnnotations, CategoryRouting, "Ingress annotations combine in a way the controller resolves by ignoring one"},
+	{CodeIngressForeignAnnotation, CategoryRouting, "An Ingress annotation belongs to a controller other than the one serving it"},
+	{CodeIngressClassNotFound, CategoryRouting, "The IngressClass of an Ingress does not exist"},
+	{CodeExternalDNSNotReady, CategoryDNS, "The external-dns controller is missing or not ready"},
+	{CodeExternalDNSSourceDisabled, CategoryDNS, "A resource requests a DNS name from a source no external-dns instance reads"},
+	{CodeExternalDNSHostnameFiltered, CategoryDNS, "A requested DNS name is outside the domain, annotation or namespace filters of external-dns"},
+	{CodeExternalDNSNoTarget, CategoryDNS, "A requested DNS name has no address for external-dns to publish"},
+	{CodeExternalDNSOwnershipConflict, CategoryDNS, "external-dns instances or clusters claim the same records through TXT ownership"},
+	{CodeExternalDNSHostnameConflict, CategoryDNS, "Several resources request the same DNS name with different targets"},
+	{CodeExternalDNSSyncErrors, CategoryDNS, "external-dns failed to sync records or has not processed a DNSEndpoint"},
+	{CodeExternalDNSHostnameUnrequested, CategoryDNS, "No resource requests the DNS name from external-dns"},
+	{CodeMeshSidecarNotReady, CategoryMesh, "A sidecar proxy is not ready"},
+	{CodeMeshSidecarRestarts, CategoryMesh, "A sidecar proxy restarted"},
+	{CodeMeshInitContainerFailed, CategoryMesh, "A mesh init container failed"},
+	{CodeMeshControlPlaneCheckFailed, CategoryMesh, "The mesh control plane pods could not be checked"},
+	{CodeMeshControlPlaneNotReady, CategoryMesh, "Some mesh control plane pods are not ready"},
+	{CodeMeshAppMeshEndOfSupport, CategoryMesh, "AWS App Mesh reaches end of support"},
+	{CodeMeshAppMeshNotActive, CategoryMesh, "An App Mesh resource is not active"},
+	{CodeMeshAppMeshBackendMissing, CategoryMesh, "A VirtualNode backend VirtualService does not exist"},
+	{CodeMeshProxyMemoryPressure, CategoryMesh, "A proxy container uses most of its memory limit"},
+	{CodeMeshProxyCPUThrottled, CategoryMesh, "A proxy container is CPU throttled or runs at its CPU limit"},
+	{CodeMeshProxyOOMKilled, CategoryMesh, "A proxy container was OOMKilled"},
+	{CodeMeshProxyOverloaded, CategoryMesh, "A proxy logged overload manager actions or load shedding"},
+	{CodeCloudControllerNotEnabled, CategoryRouting, "No GatewayClass uses the managed controller"},
+	{CodeCloudControllerNotReady, CategoryRouting, "The managed controller is missing or not ready"},
+	{CodeCloudGatewayClassNotAccepted, CategoryRouting, "A managed GatewayClass is not accepted"},
+	{CodeCloudGatewayNotProgrammed, CategoryRouting, "A managed Gateway is not programmed"},
+	{CodeCloudGatewayNoAddress, CategoryRouting, "A managed Gateway has no address"},
+	{CodeCloudPolicyNotAttached, CategoryPolicy, "A managed policy is not attached to its target"},
+	{CodeCloudPolicyTargetMissing, CategoryPolicy, "A managed policy targets a resource that does not exist"},
+	{CodeCloudLatticeServiceMissing, CategoryRouting, "A route on a VPC Lattice Gateway has no Lattice service"},
+	{CodeProbeTCPFailed, CategoryConnectivity, "A TCP probe failed"},
+	{CodeProbeDNSFailed, CategoryDNS, "A DNS probe failed"},
+	{CodeProbeHTTPErrorStatus, CategoryConnectivity, "An HTTP probe got an error status"},
+	{CodeProbeHTTPFailed, CategoryConnectivity, "An HTTP probe could not connect or timed out"},
+	{CodeProbeLeakedPod, CategoryConnectivity, "A probe pod outlived its TTL and was deleted"},
+	{CodeProbeLatencyHigh, CategoryConnectivity, "The p95 latency of a latency probe exceeds its threshold"},
+	{CodeProbeRequestsFailed, CategoryConnectivity, "Some requests of a latency probe failed or timed out"},
+	{CodeCaptureFailed, CategoryConnectivity, "A packet capture could not be taken or decoded"},
+	{CodeCaptureSYNUnanswered, CategoryConnectivity, "Captured TCP connection attempts got no SYN-ACK"},
+	{CodeCaptureConnectionsReset, CategoryConnectivity, "Captured TCP connections were reset"},
+	{CodeCaptureRetransmissions, CategoryConnectivity, "Captured TCP connections retransmitted segments"},
+	{CodeConnectionsSYNSentPileup, CategoryConnectivity, "Connections to a target pile up in SYN_SENT on a node"},
+	{CodeConnectionsTimeWaitExhaustion, CategoryConnectivity, "TIME_WAIT sockets to one destination use up most of the ephemeral port range"},
+	{CodeConnectionsStaleNAT, CategoryConnectivity, "Conntrack NAT entries of a Service point at endpoints that no longer exist"},
+	{CodeConnectionsConntrackFull, CategoryConnectivity, "A node's conntrack table is nearly full"},
+	{CodeLogsOutputTruncated, CategoryLogs, "Log output was truncated"},
+	{CodeLogsErrorsFound, CategoryLogs, "Logs contain errors"},
+	{CodeLogsAccessLogFailures, CategoryLogs, "Envoy access logs show failed or flagged requests"},
+	{CodeObservabilityService5xxRate, CategoryConnectivity, "A Service answers a high share of requests with 5xx"},
+	{CodeObservabilityEnvoyCluster5xxRate, CategoryRouting, "An Envoy cluster answers a high share of requests with 5xx"},
+	{CodeObservabilityFailingTraces, CategoryConnectivity, "Traces fail at the same hop"},
+	{CodeObservabilityNoTelemetryTemplate, CategoryRouting, "No telemetry template exists for a Gateway"},
+	{CodeTriageNoListener, CategoryRouting, "No gateway listener accepts the request"},
+	{CodeTriageNoRouteForHost, CategoryRouting, "No route serves the request host"},
+	{CodeTriageNoRouteMatch, CategoryRouting, "No route rule matches the request"},
+	{CodeTriageDirectResponse, CategoryRouting, "A route answers the request with a direct 404 response"},
+	{CodeTriageBackendServiceMissing, CategoryRouting, "The matching route sends the request to a Service that does not exist"},
+	{CodeTriageNotHTTPServer, CategoryRouting, "The gateway server accepting the request does not terminate HTTP"},
+	{CodeTrafficTimeoutBudgetExceeded, CategoryRouting, "Retries or a backend timeout do not fit in the route's request timeout"},
+	{CodeTrafficFaultIneffective, CategoryRouting, "A configured fault injection cannot affect any request"},
+	{CodeTrafficMirrorBackendMissing, CategoryRouting, "The Service traffic is mirrored to does not exist"},
+	{CodeTrafficPolicyNotObserved, CategoryRouting, "Probe traffic did not show the configured fault injection, timeout or retry behaviour"},
+	{CodeDiffOnlyInOneCluster, CategoryRouting, "A resource exists in only one of the compared clusters"},
+	{CodeDiffDiffers, CategoryRouting, "A resource differs between the compared clusters"},
+	{CodeDiffMeshEnrollmentDiffers, CategoryMesh, "A namespace is enrolled in different meshes across clusters"},
+	{CodeDiffKindNotCompared, CategoryRouting, "A kind could not be listed in a cluster and was not compared"},
+	{CodeOpenAPIServiceNotRouted, CategoryRouting, "No route sends traffic to the Service of the spec"},
+	{CodeOpenAPIOperationsNotRoutable, CategoryRouting, "Spec operations are not routable through the gateway"},
+	{CodeOpenAPIPathsNotInSpec, CategoryRouting, "Routes expose paths absent from the spec"},
+	{CodeDesignTargetServiceMissing, CategoryRouting, "The Service to design for does not exist"},
+	{CodeDesignReferenceGrantRequired, CategoryPolicy, "The design needs a ReferenceGrant"},
+	{CodeDesignTLSSecretMissing, CategoryTLS, "HTTPS was requested without a TLS Secret"},
+	{CodeDesignPeerAuthenticationConflict, CategoryMesh, "An existing PeerAuthentication may conflict with the design"},
+	{CodeDesignWeightsNot100, CategoryRouting, "The requested traffic weights do not sum to 100"},
+	{CodeDesignCanaryControllerMissing, CategoryRouting, "The requested progressive delivery controller is not installed"},
+	{CodeCanaryFailed, CategoryRouting, "A canary rollout failed or was aborted"},
+	{CodeCanaryAnalysisFailed, CategoryRouting, "A canary analysis failed or has failed checks"},
+	{CodeCanaryMetricProvider, CategoryRouting, "A canary analysis references a missing template or an incomplete metric provider"},
+	{CodeCanaryWeightMismatch, CategoryRouting, "The route weights do not match the canary weight"},
+	{CodeCanaryRouteMissing, CategoryRouting, "The route or subsets a canary shifts traffic with do not exist"},
+	{CodeScalingLoadUnmeasured, CategoryConnectivity, "The load on a workload could not be measured"},
+	{CodeScalingQuotaLimitsReplicas, CategoryConnectivity, "A ResourceQuota allows fewer replicas than recommended"},
+	{CodeScalingUndersized, CategoryConnectivity, "A workload has fewer replicas than its load needs"},
+	{CodeGitOpsResourceOutOfSync, CategoryRouting, "A resource differs from its manifest in Git"},
+	{CodeGitOpsAppOutOfSync, CategoryRouting, "A GitOps application has resources that differ from Git"},
+	{CodeGitOpsAppUnhealthy, CategoryRouting, "A GitOps application reports degraded or missing resources"},
+	{CodeGitOpsSyncFailed, CategoryRouting, "A GitOps application failed to apply Git"},
+	{CodeGitOpsSuspended, CategoryRouting, "GitOps reconciliation is suspended"},
+	{CodeManifestInvalid, CategoryRouting, "A manifest document does not parse or lacks apiVersion, kind or name"},
+	{CodeManifestNotValidated, CategoryRouting, "A manifest has a kind no offline validator checks"},
+	{CodeChangeBreaksFlow, CategoryPolicy, "A proposed change stops a configured or observed traffic flow"},
+	{CodeChangeRestrictsFlow, CategoryPolicy, "A proposed change blocks some ports or requests of a traffic flow"},
+	{CodeChangeOpensFlow, CategoryPolicy, "A proposed change allows or routes traffic that was not allowed or routed before"},
+	{CodeRBACPermissionMissing, CategoryPolicy, "The server's identity lacks RBAC permissions a tool needs"},
+	{CodeRBACCheckFailed, CategoryPolicy, "A permission could not be checked with a SelfSubjectAccessReview"},
+	{CodeServerToolErrorRate, CategoryConnectivity, "A tool fails on a large share of its calls"},
+	{CodeServerToolNearTimeout, CategoryConnectivity, "A tool's p95 latency is close to its timeout"},
+	{CodeServerAPIForbidden, CategoryPolicy, "The Kubernetes API server refused requests of the server as Forbidden"},
+	{CodeServerAPIThrottled, CategoryConnectivity, "The Kubernetes API server throttled requests of the server"},
+	{CodeHostnameMultipleEntryPoints, CategoryRouting, "A hostname is claimed on several entry points: ingress classes, Gateways or Istio gateway proxies"},
+	{CodeHostnameConflictingBackends, CategoryRouting, "Rules on one entry point route the same host and path to different backends"},
+}
+
+// FindingCodes returns the catalog of finding codes, grouped by domain.
+func FindingCodes() []FindingCodeInfo {
+	return append([]FindingCodeInfo(nil), findingCodes...)
+}
+
+// LookupFindingCode returns the catalog entry of code.
+func LookupFindingCode(code FindingCode) (FindingCodeInfo, bool) {
+	for _, info := range findingCodes {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return FindingCodeInfo{}, false
+}
+
+// Domain returns the letter prefix of the code, e.g. GW for GW001_LISTENER_CONFLICT.
+func (c FindingCode) Domain() string {
+	return strings.TrimRight(strings.SplitN(string(c), "_", 2)[0], "0123456789")
+}
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // Error code constants for agent-facing errors.
 const (
@@ -10,15 +13,76 @@ const (
 	ErrCodeInternalError     = "INTERNAL_ERROR"
 	ErrCodeProbeTimeout      = "PROBE_TIMEOUT"
 	ErrCodeProbeLimitReached = "PROBE_LIMIT_REACHED"
+	ErrCodeProbeRateLimited  = "PROBE_RATE_LIMITED"
 	ErrCodeAuthFailed        = "AUTH_FAILED"
+	// ErrCodeToolTimeout: the call ran past its tool class timeout.
+	ErrCodeToolTimeout = "TOOL_TIMEOUT"
+	// ErrCodeCancelled: the client cancelled the call or disconnected.
+	ErrCodeCancelled = "CANCELLED"
+	// ErrCodeNamespaceForbidden: the call names a namespace outside
+	// ALLOWED_NAMESPACES or in DENIED_NAMESPACES.
+	ErrCodeNamespaceForbidden = "NAMESPACE_FORBIDDEN"
+	// ErrCodeForbidden: the server's identity, or the impersonated caller,
+	// lacks an RBAC permission the call needs.
+	ErrCodeForbidden = "FORBIDDEN"
+	// ErrCodeNotFound: a resource named in the call does not exist.
+	ErrCodeNotFound = "NOT_FOUND"
+	// ErrCodeAPIUnavailable: the Kubernetes API server throttled the call,
+	// timed out or could not be reached.
+	ErrCodeAPIUnavailable = "API_UNAVAILABLE"
 )
 
+// retryHints are the Retryable and Transient hints of each error code:
+// whether the same call may succeed later, and whether its cause is
+// temporary rather than in the cluster, the arguments or the permissions.
+var retryHints = map[string]struct{ retryable, transient bool }{
+	ErrCodeProbeTimeout:      {true, true},
+	ErrCodeProbeLimitReached: {true, true},
+	ErrCodeProbeRateLimited:  {true, true},
+	ErrCodeCancelled:         {true, true},
+	ErrCodeAPIUnavailable:    {true, true},
+	// A call past its timeout usually needs narrowing before a retry.
+	ErrCodeToolTimeout: {false, true},
+}
+
 // MCPError represents a structured error returned to AI agents.
 type MCPError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Tool    string `json:"tool"`
 	Detail  string `json:"detail,omitempty"`
+	// Retryable and Transient default to the hints of Code; see RetryHints.
+	Retryable bool `json:"retryable"`
+	Transient bool `json:"transient"`
+	// RequiredPermission is the RBAC permission a FORBIDDEN call lacks,
+	// e.g. "list networkpolicies.networking.k8s.io in default".
+	RequiredPermission string `json:"requiredPermission,omitempty"`
+	// Cause is the error behind an INTERNAL_ERROR, e.g. a Kubernetes API
+	// error, so that it can still be classified.
+	Cause error `json:"-"`
+}
+
+// RetryHints reports whether a call that failed with code may succeed when
+// retried unchanged, and whether the cause is temporary. Agents should fix
+// the arguments or permissions, or change approach, for other errors.
+func RetryHints(code string) (retryable, transient bool) {
+	h := retryHints[code]
+	return h.retryable, h.transient
+}
+
+// MarshalJSON adds the retry hints of the error code to e.
+func (e *MCPError) MarshalJSON() ([]byte, error) {
+	type plain MCPError
+	p := plain(*e)
+	retryable, transient := RetryHints(e.Code)
+	p.Retryable = p.Retryable || retryable
+	p.Transient = p.Transient || transient
+	return json.Marshal(p)
+}
+
+// Unwrap returns the cause of e, if any.
+func (e *MCPError) Unwrap() error {
+	return e.Cause
 }
 
 func (e *MCPError) Error() string {
@@ -0,0 +1,235 @@
+package types
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Output formats a tool response can be rendered in.
+const (
+	OutputFormatText  = "text"
+	OutputFormatSARIF = "sarif"
+	OutputFormatJUnit = "junit"
+)
+
+// OutputFormats lists the supported output formats.
+var OutputFormats = []string{OutputFormatText, OutputFormatSARIF, OutputFormatJUnit}
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifInfoURI   = "https://github.com/henrikrexed/mcp-k8s-networking"
+	exportToolName = "mcp-k8s-networking"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool              `json:"tool"`
+	Results    []sarifResult          `json:"results"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId,omitempty"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps a severity to a SARIF result level; OK findings are not
+// results.
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return ""
+}
+
+// resourcePath identifies a resource as namespace/Kind/name, or Kind/name for
+// cluster-scoped resources.
+func resourcePath(r *ResourceRef) string {
+	path := r.Kind + "/" + r.Name
+	if r.Namespace != "" {
+		path = r.Namespace + "/" + path
+	}
+	return path
+}
+
+// ToSARIF renders the findings of toolName as a SARIF 2.1.0 log for code
+// scanning dashboards. Each finding code becomes a rule; resources are
+// reported as logical locations since findings describe live objects, not
+// files.
+func (tr *ToolResult) ToSARIF(toolName string) (string, error) {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: exportToolName, InformationURI: sarifInfoURI, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+		Properties: map[string]interface{}{
+			"tool":      toolName,
+			"cluster":   tr.Metadata.ClusterName,
+			"timestamp": tr.Metadata.Timestamp,
+		},
+	}
+	rules := make(map[FindingCode]bool)
+	for _, f := range tr.Findings {
+		level := sarifLevel(f.Severity)
+		if level == "" {
+			continue
+		}
+		if f.Code != "" && !rules[f.Code] {
+			rules[f.Code] = true
+			rule := sarifRule{ID: string(f.Code), ShortDescription: sarifMessage{Text: f.Summary}}
+			if info, ok := LookupFindingCode(f.Code); ok {
+				rule.ShortDescription.Text = info.Description
+				rule.Properties = map[string]interface{}{"category": info.Category}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		text := f.Summary
+		if f.Detail != "" {
+			text += "\n\n" + f.Detail
+		}
+		result := sarifResult{
+			RuleID:     string(f.Code),
+			Level:      level,
+			Message:    sarifMessage{Text: text},
+			Properties: map[string]interface{}{"category": f.Category},
+		}
+		if f.Suggestion != "" {
+			result.Properties["suggestion"] = f.Suggestion
+		}
+		if f.Resource != nil {
+			result.Locations = []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+				Name:               f.Resource.Name,
+				FullyQualifiedName: resourcePath(f.Resource),
+				Kind:               "resource",
+			}}}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	out, err := json.MarshalIndent(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Hostname  string          `xml:"hostname,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit renders the findings of toolName as a JUnit XML report for CI
+// pipelines: every finding is a test case and warning or critical findings
+// are failures, so a scan with issues fails the build.
+func (tr *ToolResult) ToJUnit(toolName string) (string, error) {
+	suite := junitTestSuite{
+		Name:     toolName,
+		Hostname: tr.Metadata.ClusterName,
+	}
+	if !tr.Metadata.Timestamp.IsZero() {
+		suite.Timestamp = tr.Metadata.Timestamp.Format("2006-01-02T15:04:05")
+	}
+	for _, f := range tr.Findings {
+		name := f.Summary
+		if f.Resource != nil {
+			name = resourcePath(f.Resource) + ": " + name
+		}
+		tc := junitTestCase{Name: name, ClassName: toolName + "." + f.Category}
+		if f.Severity == SeverityCritical || f.Severity == SeverityWarning {
+			var text []string
+			for _, s := range []string{f.Detail, f.Suggestion} {
+				if s != "" {
+					text = append(text, s)
+				}
+			}
+			tc.Failure = &junitFailure{
+				Message: f.Summary,
+				Type:    strings.TrimSpace(fmt.Sprintf("%s %s", f.Severity, f.Code)),
+				Text:    strings.Join(text, "\n\n"),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	out, err := xml.MarshalIndent(junitTestSuites{
+		Name:     exportToolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out), nil
+}
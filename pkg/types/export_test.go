@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportFixture() *ToolResult {
+	route := &ResourceRef{Kind: "HTTPRoute", Namespace: "shop", Name: "web"}
+	return &ToolResult{
+		Metadata: ClusterMetadata{ClusterName: "prod", Timestamp: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)},
+		Findings: []DiagnosticFinding{
+			{Severity: SeverityCritical, Category: CategoryRouting, Code: CodeGatewayBackendServiceMissing, Resource: route, Summary: "backend shop/cart missing", Suggestion: "Create the Service"},
+			{Severity: SeverityCritical, Category: CategoryRouting, Code: CodeGatewayBackendServiceMissing, Resource: route, Summary: "backend shop/api missing"},
+			{Severity: SeverityWarning, Category: CategoryPolicy, Summary: "uncoded <warning> & more", Detail: "details"},
+			{Severity: SeverityInfo, Category: CategoryRouting, Summary: "2 routes scanned"},
+			{Severity: SeverityOK, Category: CategoryRouting, Summary: "all good"},
+		},
+	}
+}
+
+func TestToSARIF(t *testing.T) {
+	out, err := exportFixture().ToSARIF("scan_gateway_misconfigs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != string(CodeGatewayBackendServiceMissing) {
+		t.Errorf("expected one rule per code, got %+v", run.Tool.Driver.Rules)
+	}
+	var levels []string
+	for _, r := range run.Results {
+		levels = append(levels, r.Level)
+	}
+	if got := strings.Join(levels, ","); got != "error,error,warning,note" {
+		t.Errorf("levels = %s, want error,error,warning,note", got)
+	}
+	loc := run.Results[0].Locations[0].LogicalLocations[0]
+	if loc.FullyQualifiedName != "shop/HTTPRoute/web" {
+		t.Errorf("location = %q", loc.FullyQualifiedName)
+	}
+	if run.Results[0].Properties["suggestion"] != "Create the Service" {
+		t.Errorf("suggestion not kept: %+v", run.Results[0].Properties)
+	}
+}
+
+func TestToJUnit(t *testing.T) {
+	out, err := exportFixture().ToJUnit("validate_istio_config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if suites.Tests != 5 || suites.Failures != 3 {
+		t.Errorf("tests=%d failures=%d, want 5 and 3", suites.Tests, suites.Failures)
+	}
+	suite := suites.Suites[0]
+	if suite.Name != "validate_istio_config" || suite.Hostname != "prod" || suite.Timestamp != "2026-10-01T12:00:00" {
+		t.Errorf("unexpected suite attributes %+v", suite)
+	}
+	first := suite.Cases[0]
+	if first.Name != "shop/HTTPRoute/web: backend shop/cart missing" || first.ClassName != "validate_istio_config.routing" {
+		t.Errorf("unexpected test case %+v", first)
+	}
+	if first.Failure == nil || first.Failure.Type != "critical GW007_BACKEND_SERVICE_MISSING" || first.Failure.Text != "Create the Service" {
+		t.Errorf("unexpected failure %+v", first.Failure)
+	}
+	if suite.Cases[2].Failure.Type != "warning" || suite.Cases[3].Failure != nil {
+		t.Errorf("unexpected failures %+v / %+v", suite.Cases[2].Failure, suite.Cases[3].Failure)
+	}
+}
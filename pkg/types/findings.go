@@ -25,6 +25,7 @@ const (
 type DiagnosticFinding struct {
 	Severity   string       `json:"severity"`
 	Category   string       `json:"category"`
+	Code       FindingCode  `json:"code,omitempty"`
 	Resource   *ResourceRef `json:"resource,omitempty"`
 	Summary    string       `json:"summary"`
 	Detail     string       `json:"detail,omitempty"`
@@ -49,6 +50,7 @@ func FilterFindings(findings []DiagnosticFinding, detail bool) []DiagnosticFindi
 		filtered[i] = DiagnosticFinding{
 			Severity: f.Severity,
 			Category: f.Category,
+			Code:     f.Code,
 			Resource: f.Resource,
 			Summary:  f.Summary,
 		}
@@ -99,7 +101,11 @@ func FindingsToText(findings []DiagnosticFinding) string {
 
 		// Escape pipes in content
 		res = strings.ReplaceAll(res, "|", "\\|")
-		summary := strings.ReplaceAll(f.Summary, "|", "\\|")
+		summary := f.Summary
+		if f.Code != "" {
+			summary = "[" + string(f.Code) + "] " + summary
+		}
+		summary = strings.ReplaceAll(summary, "|", "\\|")
 		detail = strings.ReplaceAll(detail, "|", "\\|")
 		// Replace newlines
 		summary = strings.ReplaceAll(summary, "\n", " ")